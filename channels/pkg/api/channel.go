@@ -67,6 +67,22 @@ type AddonSpec struct {
 
 	// NeedsPKI determines if channels should provision a CA and a cert-manager issuer for the addon.
 	NeedsPKI bool `json:"needsPKI,omitempty"`
+
+	// Prune determines which objects channels deletes once they are removed from the manifest.
+	Prune *PruneSpec `json:"prune,omitempty"`
+}
+
+// PruneSpec configures the deletion of the objects that are no longer in the manifest of an addon.
+type PruneSpec struct {
+	// Kinds are the kinds of objects to prune.
+	// Only the objects labeled with the name of the addon are deleted.
+	Kinds []PruneKindSpec `json:"kinds,omitempty"`
+}
+
+// PruneKindSpec identifies a kind of objects to prune.
+type PruneKindSpec struct {
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind,omitempty"`
 }
 
 func (a *Addons) Verify() error {
@@ -33,6 +33,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/util/pkg/vfs"
 
 	cmv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -172,6 +173,16 @@ func (a *Addon) EnsureUpdated(ctx context.Context, k8sClient kubernetes.Interfac
 			return nil, fmt.Errorf("error applying update from %q: %v", manifestURL, err)
 		}
 
+		if a.Spec.Prune != nil {
+			manifest, err := vfs.Context.ReadFile(manifestURL.String())
+			if err != nil {
+				return nil, fmt.Errorf("error reading manifest %q: %v", manifestURL, err)
+			}
+			if err := a.prune(ctx, k8sClient, manifest); err != nil {
+				return nil, fmt.Errorf("error pruning addon %q: %v", a.Name, err)
+			}
+		}
+
 		if err := a.AddNeedsUpdateLabel(ctx, k8sClient, required); err != nil {
 			return nil, fmt.Errorf("error adding needs-update label: %v", err)
 		}
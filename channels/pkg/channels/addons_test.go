@@ -18,11 +18,13 @@ package channels
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/blang/semver/v4"
 	fakecertmanager "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	fakekubernetes "k8s.io/client-go/kubernetes/fake"
@@ -342,3 +344,75 @@ func Test_InstallPKI(t *testing.T) {
 	}
 
 }
+
+func Test_Prune(t *testing.T) {
+	labels := map[string]string{addonNameLabel: "rbac-bindings.addons.k8s.io"}
+	objects := []runtime.Object{
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "kops:operators:cluster-admin", Labels: labels}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "kops:operators:view", Labels: labels}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "kops:developers:edit", Namespace: "apps", Labels: labels}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "kops:developers:edit", Namespace: "old", Labels: labels}},
+	}
+	manifest := `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: kops:operators:cluster-admin
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: kops:developers:edit
+  namespace: apps
+`
+
+	addon := &Addon{
+		Name: "rbac-bindings.addons.k8s.io",
+		Spec: &api.AddonSpec{
+			Prune: &api.PruneSpec{
+				Kinds: []api.PruneKindSpec{
+					{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+					{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"},
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	fakek8s := fakekubernetes.NewSimpleClientset(objects...)
+	if err := addon.prune(ctx, fakek8s, []byte(manifest)); err != nil {
+		t.Fatalf("unexpected error pruning: %v", err)
+	}
+
+	clusterRoleBindings, err := fakek8s.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing ClusterRoleBindings: %v", err)
+	}
+	var names []string
+	for _, binding := range clusterRoleBindings.Items {
+		names = append(names, binding.Name)
+	}
+	expected := []string{"kops:operators:cluster-admin", "unmanaged"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("unexpected ClusterRoleBindings: expected %v, got %v", expected, names)
+	}
+
+	roleBindings, err := fakek8s.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing RoleBindings: %v", err)
+	}
+	names = nil
+	for _, binding := range roleBindings.Items {
+		names = append(names, binding.Namespace+"/"+binding.Name)
+	}
+	expected = []string{"apps/kops:developers:edit"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("unexpected RoleBindings: expected %v, got %v", expected, names)
+	}
+
+	addon.Spec.Prune.Kinds = []api.PruneKindSpec{{Kind: "ConfigMap"}}
+	if err := addon.prune(ctx, fakek8s, []byte(manifest)); err == nil {
+		t.Errorf("expected an error pruning an unsupported kind")
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/kubemanifest"
+)
+
+// addonNameLabel is set by kOps on every object of an addon.
+const addonNameLabel = "addon.kops.k8s.io/name"
+
+// prune deletes the objects of the kinds listed in the prune spec of the addon
+// that are labeled with the name of the addon, but are no longer in its manifest.
+func (a *Addon) prune(ctx context.Context, k8sClient kubernetes.Interface, manifest []byte) error {
+	if a.Spec.Prune == nil {
+		return nil
+	}
+
+	objects, err := kubemanifest.LoadObjectsFrom(manifest)
+	if err != nil {
+		return fmt.Errorf("error parsing manifest: %v", err)
+	}
+	keep := sets.NewString()
+	for _, object := range objects {
+		gv, err := schema.ParseGroupVersion(object.APIVersion())
+		if err != nil {
+			return fmt.Errorf("error parsing apiVersion of %s: %v", object.Kind(), err)
+		}
+		meta := &metav1.ObjectMeta{}
+		if err := object.Reparse(meta, "metadata"); err != nil {
+			return fmt.Errorf("error parsing metadata of %s: %v", object.Kind(), err)
+		}
+		keep.Insert(pruneKey(gv.Group, object.Kind(), meta.Namespace, meta.Name))
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: addonNameLabel + "=" + a.Name}
+	for _, kind := range a.Spec.Prune.Kinds {
+		switch {
+		case kind.Group == rbacv1.GroupName && kind.Kind == "ClusterRoleBinding":
+			bindings, err := k8sClient.RbacV1().ClusterRoleBindings().List(ctx, listOptions)
+			if err != nil {
+				return fmt.Errorf("error listing ClusterRoleBindings: %v", err)
+			}
+			for _, binding := range bindings.Items {
+				if keep.Has(pruneKey(kind.Group, kind.Kind, "", binding.Name)) {
+					continue
+				}
+				klog.Infof("pruning ClusterRoleBinding %q of addon %q", binding.Name, a.Name)
+				err := k8sClient.RbacV1().ClusterRoleBindings().Delete(ctx, binding.Name, metav1.DeleteOptions{})
+				if err != nil && !errors.IsNotFound(err) {
+					return fmt.Errorf("error deleting ClusterRoleBinding %q: %v", binding.Name, err)
+				}
+			}
+
+		case kind.Group == rbacv1.GroupName && kind.Kind == "RoleBinding":
+			bindings, err := k8sClient.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, listOptions)
+			if err != nil {
+				return fmt.Errorf("error listing RoleBindings: %v", err)
+			}
+			for _, binding := range bindings.Items {
+				if keep.Has(pruneKey(kind.Group, kind.Kind, binding.Namespace, binding.Name)) {
+					continue
+				}
+				klog.Infof("pruning RoleBinding %s/%s of addon %q", binding.Namespace, binding.Name, a.Name)
+				err := k8sClient.RbacV1().RoleBindings(binding.Namespace).Delete(ctx, binding.Name, metav1.DeleteOptions{})
+				if err != nil && !errors.IsNotFound(err) {
+					return fmt.Errorf("error deleting RoleBinding %s/%s: %v", binding.Namespace, binding.Name, err)
+				}
+			}
+
+		default:
+			return fmt.Errorf("pruning %s.%s is not supported", kind.Kind, kind.Group)
+		}
+	}
+	return nil
+}
+
+func pruneKey(group, kind, namespace, name string) string {
+	return kind + "." + group + ":" + namespace + "/" + name
+}
@@ -222,6 +222,34 @@ func (m *MockAutoscaling) SuspendProcesses(input *autoscaling.ScalingProcessQuer
 	return &autoscaling.SuspendProcessesOutput{}, nil
 }
 
+func (m *MockAutoscaling) ResumeProcesses(input *autoscaling.ScalingProcessQuery) (*autoscaling.ResumeProcessesOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Mock ResumeProcesses: %v", input)
+
+	g := m.Groups[*input.AutoScalingGroupName]
+	if g == nil {
+		return nil, fmt.Errorf("AutoScalingGroup not found")
+	}
+
+	var suspended []*autoscaling.SuspendedProcess
+	for _, asgProc := range g.SuspendedProcesses {
+		resumed := false
+		for _, p := range input.ScalingProcesses {
+			if aws.StringValue(asgProc.ProcessName) == aws.StringValue(p) {
+				resumed = true
+			}
+		}
+		if !resumed {
+			suspended = append(suspended, asgProc)
+		}
+	}
+	g.SuspendedProcesses = suspended
+
+	return &autoscaling.ResumeProcessesOutput{}, nil
+}
+
 func (m *MockAutoscaling) DescribeAutoScalingGroups(input *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
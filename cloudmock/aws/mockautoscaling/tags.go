@@ -17,6 +17,8 @@ limitations under the License.
 package mockautoscaling
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
@@ -41,6 +43,20 @@ func (m *MockAutoscaling) DescribeTags(request *autoscaling.DescribeTagsInput) (
 						}
 					}
 
+				case "key":
+					for _, v := range filter.Values {
+						if aws.StringValue(tag.Key) == aws.StringValue(v) {
+							match = true
+						}
+					}
+
+				case "auto-scaling-group":
+					for _, v := range filter.Values {
+						if aws.StringValue(tag.ResourceId) == aws.StringValue(v) {
+							match = true
+						}
+					}
+
 				default:
 					klog.Fatalf("Unsupported filter: %v", filter)
 				}
@@ -88,3 +104,33 @@ func (m *MockAutoscaling) DescribeTagsPagesWithContext(aws.Context, *autoscaling
 	klog.Fatalf("Not implemented")
 	return nil
 }
+
+func (m *MockAutoscaling) CreateOrUpdateTags(request *autoscaling.CreateOrUpdateTagsInput) (*autoscaling.CreateOrUpdateTagsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, tag := range request.Tags {
+		g := m.Groups[aws.StringValue(tag.ResourceId)]
+		if g == nil {
+			return nil, fmt.Errorf("AutoScalingGroup not found")
+		}
+		found := false
+		for _, t := range g.Tags {
+			if aws.StringValue(t.Key) == aws.StringValue(tag.Key) {
+				t.Value = tag.Value
+				t.PropagateAtLaunch = tag.PropagateAtLaunch
+				found = true
+			}
+		}
+		if !found {
+			g.Tags = append(g.Tags, &autoscaling.TagDescription{
+				Key:               tag.Key,
+				PropagateAtLaunch: tag.PropagateAtLaunch,
+				ResourceId:        tag.ResourceId,
+				ResourceType:      tag.ResourceType,
+				Value:             tag.Value,
+			})
+		}
+	}
+	return &autoscaling.CreateOrUpdateTagsOutput{}, nil
+}
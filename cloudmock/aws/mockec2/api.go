@@ -47,6 +47,11 @@ type MockEC2 struct {
 
 	Volumes map[string]*ec2.Volume
 
+	Snapshots map[string]*ec2.Snapshot
+
+	// EbsEncryptionByDefault is whether EBS encryption by default is turned on
+	EbsEncryptionByDefault bool
+
 	KeyPairs map[string]*ec2.KeyPairInfo
 
 	Tags []*ec2.TagDescription
@@ -60,6 +65,9 @@ type MockEC2 struct {
 
 	NatGateways map[string]*ec2.NatGateway
 
+	// SpotPriceHistory are the spot prices returned by DescribeSpotPriceHistory
+	SpotPriceHistory []*ec2.SpotPrice
+
 	idsMutex sync.Mutex
 	ids      map[string]*idAllocator
 }
@@ -90,6 +98,9 @@ func (m *MockEC2) All() map[string]interface{} {
 	for id, o := range m.Volumes {
 		all[id] = o
 	}
+	for id, o := range m.Snapshots {
+		all[id] = o
+	}
 	for id, o := range m.KeyPairs {
 		all[id] = o
 	}
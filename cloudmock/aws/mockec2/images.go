@@ -51,6 +51,18 @@ func (m *MockEC2) DescribeImages(request *ec2.DescribeImagesInput) (*ec2.Describ
 	var images []*ec2.Image
 
 	for _, image := range m.Images {
+		if len(request.ImageIds) != 0 {
+			found := false
+			for _, id := range request.ImageIds {
+				if aws.StringValue(id) == aws.StringValue(image.ImageId) {
+					found = true
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+
 		matches, err := m.imageMatchesFilter(image, request.Filters)
 		if err != nil {
 			return nil, err
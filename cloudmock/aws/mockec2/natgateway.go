@@ -167,8 +167,16 @@ func (m *MockEC2) DescribeNatGatewaysRequest(*ec2.DescribeNatGatewaysInput) (*re
 	panic("Not implemented")
 }
 
-func (m *MockEC2) DescribeNatGatewaysPages(*ec2.DescribeNatGatewaysInput, func(*ec2.DescribeNatGatewaysOutput, bool) bool) error {
-	panic("Not implemented")
+func (m *MockEC2) DescribeNatGatewaysPages(request *ec2.DescribeNatGatewaysInput, callback func(*ec2.DescribeNatGatewaysOutput, bool) bool) error {
+	// For the mock, we just send everything in one page
+	page, err := m.DescribeNatGateways(request)
+	if err != nil {
+		return err
+	}
+
+	callback(page, false)
+
+	return nil
 }
 func (m *MockEC2) DescribeNatGatewaysPagesWithContext(aws.Context, *ec2.DescribeNatGatewaysInput, func(*ec2.DescribeNatGatewaysOutput, bool) bool, ...request.Option) error {
 	panic("Not implemented")
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockec2
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+func (m *MockEC2) DescribeSpotPriceHistory(request *ec2.DescribeSpotPriceHistoryInput) (*ec2.DescribeSpotPriceHistoryOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeSpotPriceHistory: %v", request)
+
+	instanceTypes := sets.NewString(aws.StringValueSlice(request.InstanceTypes)...)
+	productDescriptions := sets.NewString(aws.StringValueSlice(request.ProductDescriptions)...)
+	zones := sets.NewString()
+	if request.AvailabilityZone != nil {
+		zones.Insert(aws.StringValue(request.AvailabilityZone))
+	}
+	for _, filter := range request.Filters {
+		switch aws.StringValue(filter.Name) {
+		case "availability-zone":
+			zones.Insert(aws.StringValueSlice(filter.Values)...)
+		default:
+			return nil, fmt.Errorf("unknown filter name: %q", aws.StringValue(filter.Name))
+		}
+	}
+
+	// Like EC2, include the price in effect at the start time
+	var history []*ec2.SpotPrice
+	for _, price := range m.SpotPriceHistory {
+		if instanceTypes.Len() != 0 && !instanceTypes.Has(aws.StringValue(price.InstanceType)) {
+			continue
+		}
+		if productDescriptions.Len() != 0 && !productDescriptions.Has(aws.StringValue(price.ProductDescription)) {
+			continue
+		}
+		if zones.Len() != 0 && !zones.Has(aws.StringValue(price.AvailabilityZone)) {
+			continue
+		}
+		if request.EndTime != nil && aws.TimeValue(price.Timestamp).After(aws.TimeValue(request.EndTime)) {
+			continue
+		}
+		history = append(history, price)
+	}
+	if request.StartTime != nil {
+		history = pricesSince(history, aws.TimeValue(request.StartTime))
+	}
+
+	return &ec2.DescribeSpotPriceHistoryOutput{SpotPriceHistory: history}, nil
+}
+
+// pricesSince keeps the prices changed after start, and the latest price of each instance type and zone before it.
+func pricesSince(history []*ec2.SpotPrice, start time.Time) []*ec2.SpotPrice {
+	var since []*ec2.SpotPrice
+	before := make(map[string]*ec2.SpotPrice)
+	for _, price := range history {
+		timestamp := aws.TimeValue(price.Timestamp)
+		if !timestamp.Before(start) {
+			since = append(since, price)
+			continue
+		}
+		key := aws.StringValue(price.InstanceType) + "/" + aws.StringValue(price.AvailabilityZone)
+		if previous := before[key]; previous == nil || aws.TimeValue(previous.Timestamp).Before(timestamp) {
+			before[key] = price
+		}
+	}
+	for _, price := range before {
+		since = append(since, price)
+	}
+	return since
+}
+
+func (m *MockEC2) DescribeSpotPriceHistoryPages(request *ec2.DescribeSpotPriceHistoryInput, callback func(*ec2.DescribeSpotPriceHistoryOutput, bool) bool) error {
+	// For the mock, we just send everything in one page
+	page, err := m.DescribeSpotPriceHistory(request)
+	if err != nil {
+		return err
+	}
+
+	callback(page, false)
+
+	return nil
+}
+
+func (m *MockEC2) DescribeSpotPriceHistoryWithContext(aws.Context, *ec2.DescribeSpotPriceHistoryInput, ...request.Option) (*ec2.DescribeSpotPriceHistoryOutput, error) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DescribeSpotPriceHistoryPagesWithContext(aws.Context, *ec2.DescribeSpotPriceHistoryInput, func(*ec2.DescribeSpotPriceHistoryOutput, bool) bool, ...request.Option) error {
+	panic("Not implemented")
+}
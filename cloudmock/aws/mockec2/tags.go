@@ -59,6 +59,8 @@ func (m *MockEC2) addTags(resourceId string, tags ...*ec2.Tag) {
 		resourceType = ec2.ResourceTypeSecurityGroup
 	} else if strings.HasPrefix(resourceId, "vol-") {
 		resourceType = ec2.ResourceTypeVolume
+	} else if strings.HasPrefix(resourceId, "snap-") {
+		resourceType = ec2.ResourceTypeSnapshot
 	} else if strings.HasPrefix(resourceId, "igw-") {
 		resourceType = ec2.ResourceTypeInternetGateway
 	} else if strings.HasPrefix(resourceId, "nat-") {
@@ -80,6 +80,40 @@ func (m *MockEC2) CreateVolumeRequest(*ec2.CreateVolumeInput) (*request.Request,
 	panic("Not implemented")
 }
 
+func (m *MockEC2) CreateSnapshot(request *ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("CreateSnapshot: %v", request)
+
+	volume := m.Volumes[aws.StringValue(request.VolumeId)]
+	if volume == nil {
+		return nil, fmt.Errorf("volume %q not found", aws.StringValue(request.VolumeId))
+	}
+
+	id := m.allocateId("snap")
+	snapshot := &ec2.Snapshot{
+		SnapshotId:  s(id),
+		Description: request.Description,
+		VolumeId:    volume.VolumeId,
+		VolumeSize:  volume.Size,
+		Encrypted:   volume.Encrypted,
+		KmsKeyId:    volume.KmsKeyId,
+		State:       s(ec2.SnapshotStatePending),
+	}
+
+	if m.Snapshots == nil {
+		m.Snapshots = make(map[string]*ec2.Snapshot)
+	}
+	m.Snapshots[id] = snapshot
+
+	m.addTags(id, tagSpecificationsToTags(request.TagSpecifications, ec2.ResourceTypeSnapshot)...)
+
+	copy := *snapshot
+	copy.Tags = m.getTags(ec2.ResourceTypeSnapshot, id)
+	return &copy, nil
+}
+
 func (m *MockEC2) DescribeVolumeAttributeRequest(*ec2.DescribeVolumeAttributeInput) (*request.Request, *ec2.DescribeVolumeAttributeOutput) {
 	panic("MockEC2 DescribeVolumeAttributeRequest not implemented")
 }
@@ -206,3 +240,27 @@ func (m *MockEC2) DeleteVolumeWithContext(aws.Context, *ec2.DeleteVolumeInput, .
 func (m *MockEC2) DeleteVolumeRequest(*ec2.DeleteVolumeInput) (*request.Request, *ec2.DeleteVolumeOutput) {
 	panic("Not implemented")
 }
+
+func (m *MockEC2) GetEbsEncryptionByDefault(request *ec2.GetEbsEncryptionByDefaultInput) (*ec2.GetEbsEncryptionByDefaultOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("GetEbsEncryptionByDefault: %v", request)
+
+	return &ec2.GetEbsEncryptionByDefaultOutput{
+		EbsEncryptionByDefault: aws.Bool(m.EbsEncryptionByDefault),
+	}, nil
+}
+
+func (m *MockEC2) EnableEbsEncryptionByDefault(request *ec2.EnableEbsEncryptionByDefaultInput) (*ec2.EnableEbsEncryptionByDefaultOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("EnableEbsEncryptionByDefault: %v", request)
+
+	m.EbsEncryptionByDefault = true
+
+	return &ec2.EnableEbsEncryptionByDefaultOutput{
+		EbsEncryptionByDefault: aws.Bool(m.EbsEncryptionByDefault),
+	}, nil
+}
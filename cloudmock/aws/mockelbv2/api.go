@@ -45,6 +45,7 @@ type loadBalancer struct {
 
 type targetGroup struct {
 	description elbv2.TargetGroup
+	attributes  []*elbv2.TargetGroupAttribute
 }
 
 type listener struct {
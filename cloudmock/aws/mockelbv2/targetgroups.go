@@ -98,8 +98,14 @@ func (m *MockELBV2) CreateTargetGroup(request *elbv2.CreateTargetGroupInput) (*e
 		Port:                    request.Port,
 		Protocol:                request.Protocol,
 		VpcId:                   request.VpcId,
+		TargetType:              request.TargetType,
 		HealthyThresholdCount:   request.HealthyThresholdCount,
 		UnhealthyThresholdCount: request.UnhealthyThresholdCount,
+
+		HealthCheckIntervalSeconds: request.HealthCheckIntervalSeconds,
+		HealthCheckPath:            request.HealthCheckPath,
+		HealthCheckPort:            request.HealthCheckPort,
+		HealthCheckProtocol:        request.HealthCheckProtocol,
 	}
 
 	m.tgCount++
@@ -131,3 +137,77 @@ func (m *MockELBV2) DeleteTargetGroup(request *elbv2.DeleteTargetGroupInput) (*e
 	delete(m.TargetGroups, arn)
 	return &elbv2.DeleteTargetGroupOutput{}, nil
 }
+
+func (m *MockELBV2) ModifyTargetGroup(request *elbv2.ModifyTargetGroupInput) (*elbv2.ModifyTargetGroupOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("ModifyTargetGroup %v", request)
+
+	tg, ok := m.TargetGroups[aws.StringValue(request.TargetGroupArn)]
+	if !ok {
+		return nil, awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "target group not found", nil)
+	}
+
+	if request.HealthyThresholdCount != nil {
+		tg.description.HealthyThresholdCount = request.HealthyThresholdCount
+	}
+	if request.UnhealthyThresholdCount != nil {
+		tg.description.UnhealthyThresholdCount = request.UnhealthyThresholdCount
+	}
+	if request.HealthCheckIntervalSeconds != nil {
+		tg.description.HealthCheckIntervalSeconds = request.HealthCheckIntervalSeconds
+	}
+	if request.HealthCheckPath != nil {
+		tg.description.HealthCheckPath = request.HealthCheckPath
+	}
+	if request.HealthCheckPort != nil {
+		tg.description.HealthCheckPort = request.HealthCheckPort
+	}
+	if request.HealthCheckProtocol != nil {
+		tg.description.HealthCheckProtocol = request.HealthCheckProtocol
+	}
+
+	return &elbv2.ModifyTargetGroupOutput{TargetGroups: []*elbv2.TargetGroup{&tg.description}}, nil
+}
+
+func (m *MockELBV2) DescribeTargetGroupAttributes(request *elbv2.DescribeTargetGroupAttributesInput) (*elbv2.DescribeTargetGroupAttributesOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeTargetGroupAttributes %v", request)
+
+	tg, ok := m.TargetGroups[aws.StringValue(request.TargetGroupArn)]
+	if !ok {
+		return nil, awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "target group not found", nil)
+	}
+
+	return &elbv2.DescribeTargetGroupAttributesOutput{Attributes: tg.attributes}, nil
+}
+
+func (m *MockELBV2) ModifyTargetGroupAttributes(request *elbv2.ModifyTargetGroupAttributesInput) (*elbv2.ModifyTargetGroupAttributesOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("ModifyTargetGroupAttributes %v", request)
+
+	tg, ok := m.TargetGroups[aws.StringValue(request.TargetGroupArn)]
+	if !ok {
+		return nil, awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "target group not found", nil)
+	}
+
+	for _, reqAttr := range request.Attributes {
+		found := false
+		for _, tgAttr := range tg.attributes {
+			if aws.StringValue(reqAttr.Key) == aws.StringValue(tgAttr.Key) {
+				tgAttr.Value = reqAttr.Value
+				found = true
+			}
+		}
+		if !found {
+			tg.attributes = append(tg.attributes, reqAttr)
+		}
+	}
+
+	return &elbv2.ModifyTargetGroupAttributesOutput{Attributes: tg.attributes}, nil
+}
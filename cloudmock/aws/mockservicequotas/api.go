@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockservicequotas
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+)
+
+type MockServiceQuotas struct {
+	servicequotasiface.ServiceQuotasAPI
+	mutex sync.Mutex
+
+	// Quotas are the values of the quotas, keyed by "<service code>/<quota code>"
+	Quotas map[string]float64
+
+	// Requests are the quota increases that have been requested
+	Requests []*servicequotas.RequestedServiceQuotaChange
+}
+
+var _ servicequotasiface.ServiceQuotasAPI = &MockServiceQuotas{}
+
+func quotaKey(serviceCode, quotaCode *string) string {
+	return aws.StringValue(serviceCode) + "/" + aws.StringValue(quotaCode)
+}
+
+func (m *MockServiceQuotas) GetServiceQuota(request *servicequotas.GetServiceQuotaInput) (*servicequotas.GetServiceQuotaOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	value, ok := m.Quotas[quotaKey(request.ServiceCode, request.QuotaCode)]
+	if !ok {
+		return nil, awserr.New(servicequotas.ErrCodeNoSuchResourceException, fmt.Sprintf("quota %s not found", quotaKey(request.ServiceCode, request.QuotaCode)), nil)
+	}
+
+	response := &servicequotas.GetServiceQuotaOutput{
+		Quota: &servicequotas.ServiceQuota{
+			ServiceCode: request.ServiceCode,
+			QuotaCode:   request.QuotaCode,
+			Value:       aws.Float64(value),
+		},
+	}
+	return response, nil
+}
+
+func (m *MockServiceQuotas) GetAWSDefaultServiceQuota(request *servicequotas.GetAWSDefaultServiceQuotaInput) (*servicequotas.GetAWSDefaultServiceQuotaOutput, error) {
+	return nil, awserr.New(servicequotas.ErrCodeNoSuchResourceException, fmt.Sprintf("quota %s not found", quotaKey(request.ServiceCode, request.QuotaCode)), nil)
+}
+
+func (m *MockServiceQuotas) RequestServiceQuotaIncrease(request *servicequotas.RequestServiceQuotaIncreaseInput) (*servicequotas.RequestServiceQuotaIncreaseOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, existing := range m.Requests {
+		if quotaKey(existing.ServiceCode, existing.QuotaCode) == quotaKey(request.ServiceCode, request.QuotaCode) {
+			return nil, awserr.New(servicequotas.ErrCodeResourceAlreadyExistsException, "a request for this quota is already pending", nil)
+		}
+	}
+
+	change := &servicequotas.RequestedServiceQuotaChange{
+		Id:           aws.String(fmt.Sprintf("request-%d", len(m.Requests)+1)),
+		ServiceCode:  request.ServiceCode,
+		QuotaCode:    request.QuotaCode,
+		DesiredValue: request.DesiredValue,
+		Status:       aws.String(servicequotas.RequestStatusPending),
+	}
+	m.Requests = append(m.Requests, change)
+
+	return &servicequotas.RequestServiceQuotaIncreaseOutput{RequestedQuota: change}, nil
+}
@@ -35,9 +35,10 @@ type MockClient struct {
 	firewallClient       *firewallClient
 	routerClient         *routerClient
 
-	instanceTemplateClient     *instanceTemplateClient
-	instanceGroupManagerClient *instanceGroupManagerClient
-	targetPoolClient           *targetPoolClient
+	instanceTemplateClient           *instanceTemplateClient
+	instanceGroupManagerClient       *instanceGroupManagerClient
+	regionInstanceGroupManagerClient *regionInstanceGroupManagerClient
+	targetPoolClient                 *targetPoolClient
 
 	diskClient *diskClient
 }
@@ -58,9 +59,10 @@ func NewMockClient(project string) *MockClient {
 		firewallClient:       newFirewallClient(),
 		routerClient:         newRouterClient(),
 
-		instanceTemplateClient:     newInstanceTemplateClient(),
-		instanceGroupManagerClient: newInstanceGroupManagerClient(),
-		targetPoolClient:           newTargetPoolClient(),
+		instanceTemplateClient:           newInstanceTemplateClient(),
+		instanceGroupManagerClient:       newInstanceGroupManagerClient(),
+		regionInstanceGroupManagerClient: newRegionInstanceGroupManagerClient(),
+		targetPoolClient:                 newTargetPoolClient(),
 
 		diskClient: newDiskClient(),
 	}
@@ -82,6 +84,7 @@ func (c *MockClient) AllResources() map[string]interface{} {
 		c.routerClient.All,
 		c.instanceTemplateClient.All,
 		c.instanceGroupManagerClient.All,
+		c.regionInstanceGroupManagerClient.All,
 		c.targetPoolClient.All,
 		c.diskClient.All,
 	}
@@ -149,6 +152,10 @@ func (c *MockClient) InstanceGroupManagers() gce.InstanceGroupManagerClient {
 	return c.instanceGroupManagerClient
 }
 
+func (c *MockClient) RegionInstanceGroupManagers() gce.RegionInstanceGroupManagerClient {
+	return c.regionInstanceGroupManagerClient
+}
+
 func (c *MockClient) TargetPools() gce.TargetPoolClient {
 	return c.targetPoolClient
 }
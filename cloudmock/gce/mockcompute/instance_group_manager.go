@@ -145,3 +145,19 @@ func (c *instanceGroupManagerClient) SetInstanceTemplate(project, zone, name, in
 func (c *instanceGroupManagerClient) Resize(project, zone, name string, newSize int64) (*compute.Operation, error) {
 	return doneOperation(), nil
 }
+
+func (c *instanceGroupManagerClient) Patch(project, zone, name string, igm *compute.InstanceGroupManager) (*compute.Operation, error) {
+	c.Lock()
+	defer c.Unlock()
+	existing, ok := c.instanceGroupManagers[project][zone][name]
+	if !ok {
+		return nil, notFoundError()
+	}
+	if igm.InstanceTemplate != "" {
+		existing.InstanceTemplate = igm.InstanceTemplate
+	}
+	if igm.Versions != nil {
+		existing.Versions = igm.Versions
+	}
+	return doneOperation(), nil
+}
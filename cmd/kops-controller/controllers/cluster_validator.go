@@ -0,0 +1,263 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/vfs"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	validationSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kops_cluster_validation_success",
+		Help: "Whether the last validation of the cluster passed (1) or failed (0).",
+	})
+	validationFailures = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kops_cluster_validation_failures",
+		Help: "Number of failures found by the last validation of the cluster.",
+	}, []string{"kind", "check"})
+	validationTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kops_cluster_validation_last_run_timestamp_seconds",
+		Help: "Time of the last completed validation of the cluster, in seconds since the epoch.",
+	})
+	validationDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kops_cluster_validation_duration_seconds",
+		Help: "Duration of the last completed validation of the cluster.",
+	})
+	validationErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "kops_cluster_validation_errors_total",
+		Help: "Number of times the cluster could not be validated.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(validationSuccess, validationFailures, validationTimestamp, validationDuration, validationErrors)
+}
+
+// validationEventObject is the object Events about the validation of the cluster are recorded against.
+var validationEventObject = &corev1.ObjectReference{
+	APIVersion: "apps/v1",
+	Kind:       "DaemonSet",
+	Namespace:  "kube-system",
+	Name:       "kops-controller",
+}
+
+// maxEventFailures is the maximum number of failures listed in a single Event.
+const maxEventFailures = 10
+
+// NewClusterValidator is the constructor for a ClusterValidator
+func NewClusterValidator(mgr manager.Manager, configPath string, opt *config.ValidationOptions) (*ClusterValidator, error) {
+	checks, err := validation.FindChecks(opt.Checks, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %v", err)
+	}
+
+	configBase, err := vfs.Context.BuildVfsPath(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse ConfigBase %q: %v", configPath, err)
+	}
+
+	return &ClusterValidator{
+		log:        ctrl.Log.WithName("controllers").WithName("ClusterValidator"),
+		configBase: configBase,
+		host:       mgr.GetConfig().Host,
+		k8sClient:  k8sClient,
+		recorder:   mgr.GetEventRecorderFor("kops-controller"),
+		interval:   opt.Interval.Duration,
+		checks:     checks,
+	}, nil
+}
+
+// ClusterValidator periodically validates the cluster, as `kops validate cluster` would,
+// exporting the results as Prometheus metrics and recording Events when the result changes.
+type ClusterValidator struct {
+	// log is a logr
+	log logr.Logger
+
+	// configBase is the parsed path to the base location of our configuration files
+	configBase vfs.Path
+
+	// host is the URL of the kubernetes API server
+	host string
+
+	// k8sClient is a client-go client for the cluster being validated
+	k8sClient kubernetes.Interface
+
+	// recorder records Events about the validation of the cluster
+	recorder record.EventRecorder
+
+	// interval is the time between validations
+	interval time.Duration
+
+	// checks are the additional validation checks to run
+	checks []validation.Check
+
+	// lastFailures is the number of failures found by the previous validation, or nil if there was none
+	lastFailures *int
+}
+
+var _ manager.Runnable = &ClusterValidator{}
+
+// Start validates the cluster every interval until the context is done.
+// As a manager.Runnable it only runs on the elected leader.
+func (v *ClusterValidator) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, v.validate, v.interval)
+	return nil
+}
+
+func (v *ClusterValidator) validate(ctx context.Context) {
+	start := time.Now()
+	result, err := v.runValidation()
+	if err != nil {
+		v.log.Error(err, "unable to validate cluster")
+		validationErrors.Inc()
+		return
+	}
+	validationDuration.Set(time.Since(start).Seconds())
+	validationTimestamp.Set(float64(time.Now().Unix()))
+
+	validationFailures.Reset()
+	for _, failure := range result.Failures {
+		validationFailures.WithLabelValues(failure.Kind, failure.Check).Inc()
+	}
+	if len(result.Failures) == 0 {
+		validationSuccess.Set(1)
+	} else {
+		validationSuccess.Set(0)
+	}
+
+	v.recordEvents(result)
+}
+
+func (v *ClusterValidator) runValidation() (*validation.ValidationCluster, error) {
+	cluster, err := loadCluster(v.configBase)
+	if err != nil {
+		return nil, err
+	}
+	instanceGroups, err := loadInstanceGroups(v.configBase, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error building cloud: %v", err)
+	}
+
+	validator, err := validation.NewClusterValidator(cluster, cloud, instanceGroups, v.host, v.k8sClient, v.checks...)
+	if err != nil {
+		return nil, err
+	}
+	return validator.Validate()
+}
+
+// recordEvents records an Event when validation starts failing, when the number of failures changes,
+// and when validation passes again.
+func (v *ClusterValidator) recordEvents(result *validation.ValidationCluster) {
+	failures := len(result.Failures)
+	previous := v.lastFailures
+	v.lastFailures = &failures
+
+	if failures == 0 {
+		if previous != nil && *previous != 0 {
+			v.recorder.Event(validationEventObject, corev1.EventTypeNormal, "ClusterValidationSucceeded", "Cluster passed validation")
+		}
+		return
+	}
+	if previous != nil && *previous == failures {
+		return
+	}
+
+	var messages []string
+	for i, failure := range result.Failures {
+		if i == maxEventFailures {
+			messages = append(messages, fmt.Sprintf("and %d more", failures-maxEventFailures))
+			break
+		}
+		messages = append(messages, failure.Message)
+	}
+	v.recorder.Eventf(validationEventObject, corev1.EventTypeWarning, "ClusterValidationFailed", "Cluster failed validation with %d failures: %s", failures, strings.Join(messages, "; "))
+}
+
+// loadCluster loads the completed kops.Cluster object from the vfs backing store
+func loadCluster(configBase vfs.Path) (*kops.Cluster, error) {
+	p := configBase.Join(registry.PathClusterCompleted)
+	b, err := p.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("error loading Cluster %q: %v", p, err)
+	}
+
+	o, _, err := kopscodecs.Decode(b, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Cluster %q: %v", p, err)
+	}
+	if cluster, ok := o.(*kops.Cluster); ok {
+		return cluster, nil
+	}
+	return nil, fmt.Errorf("unexpected object type for Cluster %q: %T", p, o)
+}
+
+// loadInstanceGroups loads all kops.InstanceGroup objects from the vfs backing store,
+// with the instanceGroupDefaults of the cluster applied.
+func loadInstanceGroups(configBase vfs.Path, cluster *kops.Cluster) (*kops.InstanceGroupList, error) {
+	files, err := configBase.Join("instancegroup").ReadDir()
+	if err != nil {
+		return nil, fmt.Errorf("error listing InstanceGroups: %v", err)
+	}
+
+	list := &kops.InstanceGroupList{}
+	for _, p := range files {
+		b, err := p.ReadFile()
+		if err != nil {
+			return nil, fmt.Errorf("error loading InstanceGroup %q: %v", p, err)
+		}
+		o, _, err := kopscodecs.Decode(b, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing InstanceGroup %q: %v", p, err)
+		}
+		ig, ok := o.(*kops.InstanceGroup)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object type for InstanceGroup %q: %T", p, o)
+		}
+		list.Items = append(list.Items, *cluster.InstanceGroupWithDefaults(ig))
+	}
+	return list, nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/nodeidentity"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// egressNATPolicyGVK is the kind of the Cilium policies that route the traffic of the namespaces through the gateway nodes.
+var egressNATPolicyGVK = schema.GroupVersionKind{Group: "cilium.io", Version: "v2alpha1", Kind: "CiliumEgressNATPolicy"}
+
+// NewEgressGatewayReconciler is the constructor for an EgressGatewayReconciler
+func NewEgressGatewayReconciler(mgr manager.Manager, identifier nodeidentity.Identifier, gateways []config.EgressGatewayOptions) (*EgressGatewayReconciler, error) {
+	awsConfig := aws.NewConfig().WithCredentialsChainVerboseErrors(true)
+	s, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error starting new AWS session: %v", err)
+	}
+	region, err := ec2metadata.New(s, awsConfig).Region()
+	if err != nil {
+		return nil, fmt.Errorf("error querying ec2 metadata service (for region): %v", err)
+	}
+
+	return &EgressGatewayReconciler{
+		client:     mgr.GetClient(),
+		log:        ctrl.Log.WithName("controllers").WithName("EgressGateway"),
+		ec2Client:  ec2.New(s, awsConfig.WithRegion(region)),
+		identifier: identifier,
+		gateways:   gateways,
+	}, nil
+}
+
+// EgressGatewayReconciler observes Node objects, elects a single ready node of each egress gateway,
+// associates the Elastic IP of the gateway with its instance, and points the Cilium egress NAT policy
+// of the gateway at the node, so that the traffic of the namespaces leaves the cluster from the Elastic IP.
+type EgressGatewayReconciler struct {
+	// client is the controller-runtime client
+	client client.Client
+
+	// log is a logr
+	log logr.Logger
+
+	// ec2Client is used to associate the Elastic IPs
+	ec2Client ec2iface.EC2API
+
+	// identifier is a provider that can securely map nodes to their instances
+	identifier nodeidentity.Identifier
+
+	// gateways are the egress gateways of the cluster
+	gateways []config.EgressGatewayOptions
+}
+
+// +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cilium.io,resources=ciliumegressnatpolicies,verbs=get;create;update
+// Reconcile is the main reconciler function that observes node changes.
+func (r *EgressGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// The node of the request may have been deleted, or may no longer be the holder of a gateway,
+	// so the holders of all the gateways are checked again
+	for i := range r.gateways {
+		gateway := &r.gateways[i]
+		if err := r.reconcileGateway(ctx, gateway); err != nil {
+			return ctrl.Result{}, fmt.Errorf("error configuring egress gateway %q: %v", gateway.Name, err)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager registers the egress gateway controller with the manager.
+func (r *EgressGatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("egressgateway").
+		For(&corev1.Node{}).
+		Complete(r)
+}
+
+// reconcileGateway elects the node that holds the Elastic IP of the gateway, associates the Elastic IP
+// with the instance of the node, and updates the policy of the gateway.
+func (r *EgressGatewayReconciler) reconcileGateway(ctx context.Context, gateway *config.EgressGatewayOptions) error {
+	nodes := &corev1.NodeList{}
+	if err := r.client.List(ctx, nodes, client.MatchingLabels{kops.NodeLabelInstanceGroup: gateway.InstanceGroup}); err != nil {
+		return fmt.Errorf("error listing nodes of instance group %q: %v", gateway.InstanceGroup, err)
+	}
+
+	policy := buildEgressNATPolicy(gateway, "")
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(egressNATPolicyGVK)
+	found := true
+	if err := r.client.Get(ctx, client.ObjectKey{Name: policy.GetName()}, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error getting CiliumEgressNATPolicy %q: %v", policy.GetName(), err)
+		}
+		found = false
+	}
+
+	// The policy records the node that holds the Elastic IP, as the Elastic IP is associated first
+	currentIP, _, _ := unstructured.NestedString(existing.Object, "spec", "egressSourceIP")
+	node := electGatewayNode(nodes.Items, currentIP)
+	if node == nil {
+		r.log.Info("no ready node for egress gateway", "gateway", gateway.Name, "instanceGroup", gateway.InstanceGroup)
+		return nil
+	}
+	sourceIP := nodeInternalIP(node)
+
+	policy = buildEgressNATPolicy(gateway, sourceIP)
+	if found && reflect.DeepEqual(existing.Object["spec"], policy.Object["spec"]) {
+		return nil
+	}
+
+	if sourceIP != currentIP {
+		if err := r.associateElasticIP(ctx, gateway, node); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		if err := r.client.Create(ctx, policy); err != nil {
+			return fmt.Errorf("error creating CiliumEgressNATPolicy %q: %v", policy.GetName(), err)
+		}
+		r.log.Info("created egress NAT policy", "gateway", gateway.Name, "node", node.Name, "egressSourceIP", sourceIP)
+		return nil
+	}
+
+	existing.Object["spec"] = policy.Object["spec"]
+	if err := r.client.Update(ctx, existing); err != nil {
+		return fmt.Errorf("error updating CiliumEgressNATPolicy %q: %v", policy.GetName(), err)
+	}
+	r.log.Info("updated egress NAT policy", "gateway", gateway.Name, "node", node.Name, "egressSourceIP", sourceIP)
+	return nil
+}
+
+// associateElasticIP associates the Elastic IP of the gateway with the instance of the node.
+func (r *EgressGatewayReconciler) associateElasticIP(ctx context.Context, gateway *config.EgressGatewayOptions, node *corev1.Node) error {
+	info, err := r.identifier.IdentifyNode(ctx, node)
+	if err != nil {
+		return fmt.Errorf("error identifying node %q: %v", node.Name, err)
+	}
+
+	response, err := r.ec2Client.DescribeAddressesWithContext(ctx, &ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{awsup.NewEC2Filter("tag:Name", gateway.ElasticIP)},
+	})
+	if err != nil {
+		return fmt.Errorf("error describing Elastic IP %q: %v", gateway.ElasticIP, err)
+	}
+	if len(response.Addresses) != 1 {
+		return fmt.Errorf("found %d Elastic IPs named %q", len(response.Addresses), gateway.ElasticIP)
+	}
+	address := response.Addresses[0]
+
+	if aws.StringValue(address.InstanceId) == info.InstanceID {
+		return nil
+	}
+	if _, err := r.ec2Client.AssociateAddressWithContext(ctx, &ec2.AssociateAddressInput{
+		AllocationId:       address.AllocationId,
+		InstanceId:         aws.String(info.InstanceID),
+		AllowReassociation: aws.Bool(true),
+	}); err != nil {
+		return fmt.Errorf("error associating Elastic IP %q with instance %q: %v", gateway.ElasticIP, info.InstanceID, err)
+	}
+	r.log.Info("associated Elastic IP of egress gateway", "gateway", gateway.Name, "node", node.Name, "instance", info.InstanceID, "publicIP", aws.StringValue(address.PublicIp))
+	return nil
+}
+
+// electGatewayNode returns the node that should hold the Elastic IP of a gateway, or nil if no node is ready.
+// The node with the current source IP keeps the Elastic IP while it is ready, so that it only moves when
+// that node becomes NotReady or is deleted; otherwise the ready node with the lowest name is elected.
+func electGatewayNode(nodes []corev1.Node, currentIP string) *corev1.Node {
+	var elected *corev1.Node
+	for i := range nodes {
+		node := &nodes[i]
+		sourceIP := nodeInternalIP(node)
+		if !isNodeReady(node) || sourceIP == "" {
+			continue
+		}
+		if currentIP != "" && sourceIP == currentIP {
+			return node
+		}
+		if elected == nil || node.Name < elected.Name {
+			elected = node
+		}
+	}
+	return elected
+}
+
+// buildEgressNATPolicy builds the Cilium policy that routes the traffic of the namespaces of the gateway
+// to the destination CIDRs through the node with the source IP.
+func buildEgressNATPolicy(gateway *config.EgressGatewayOptions, sourceIP string) *unstructured.Unstructured {
+	var egress []interface{}
+	for _, namespace := range gateway.Namespaces {
+		egress = append(egress, map[string]interface{}{
+			"podSelector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					"io.kubernetes.pod.namespace": namespace,
+				},
+			},
+		})
+	}
+	var destinationCIDRs []interface{}
+	for _, cidr := range gateway.DestinationCIDRs {
+		destinationCIDRs = append(destinationCIDRs, cidr)
+	}
+
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(egressNATPolicyGVK)
+	policy.SetName("kops-egress-gateway-" + gateway.Name)
+	policy.Object["spec"] = map[string]interface{}{
+		"egress":           egress,
+		"destinationCIDRs": destinationCIDRs,
+		"egressSourceIP":   sourceIP,
+	}
+	return policy
+}
+
+// isNodeReady returns true if the node reports the Ready condition.
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// nodeInternalIP returns the first internal IP address of the node.
+func nodeInternalIP(node *corev1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			return address.Address
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"sigs.k8s.io/yaml"
+)
+
+func TestBuildEgressNATPolicy(t *testing.T) {
+	gateway := &config.EgressGatewayOptions{
+		Name:             "partner",
+		InstanceGroup:    "egress",
+		ElasticIP:        "partner.egress.minimal.example.com",
+		Namespaces:       []string{"payments", "billing"},
+		DestinationCIDRs: []string{"203.0.113.0/24"},
+	}
+
+	actual, err := yaml.Marshal(buildEgressNATPolicy(gateway, "172.20.32.10").Object)
+	if err != nil {
+		t.Fatalf("error marshaling policy: %v", err)
+	}
+
+	expected := `apiVersion: cilium.io/v2alpha1
+kind: CiliumEgressNATPolicy
+metadata:
+  name: kops-egress-gateway-partner
+spec:
+  destinationCIDRs:
+  - 203.0.113.0/24
+  egress:
+  - podSelector:
+      matchLabels:
+        io.kubernetes.pod.namespace: payments
+  - podSelector:
+      matchLabels:
+        io.kubernetes.pod.namespace: billing
+  egressSourceIP: 172.20.32.10
+`
+	if string(actual) != expected {
+		t.Errorf("unexpected policy:\n%s\nexpected:\n%s", actual, expected)
+	}
+}
+
+func TestElectGatewayNode(t *testing.T) {
+	node := func(name string, ip string, ready corev1.ConditionStatus) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+				Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: ip}},
+			},
+		}
+	}
+
+	grid := []struct {
+		name      string
+		nodes     []corev1.Node
+		currentIP string
+		expected  string
+	}{
+		{
+			name: "no nodes",
+		},
+		{
+			name:  "no ready nodes",
+			nodes: []corev1.Node{node("b", "10.0.0.2", corev1.ConditionFalse)},
+		},
+		{
+			name: "lowest name without holder",
+			nodes: []corev1.Node{
+				node("c", "10.0.0.3", corev1.ConditionTrue),
+				node("a", "10.0.0.1", corev1.ConditionFalse),
+				node("b", "10.0.0.2", corev1.ConditionTrue),
+			},
+			expected: "b",
+		},
+		{
+			name: "ready holder is kept",
+			nodes: []corev1.Node{
+				node("a", "10.0.0.1", corev1.ConditionTrue),
+				node("b", "10.0.0.2", corev1.ConditionTrue),
+			},
+			currentIP: "10.0.0.2",
+			expected:  "b",
+		},
+		{
+			name: "not ready holder is replaced",
+			nodes: []corev1.Node{
+				node("a", "10.0.0.1", corev1.ConditionFalse),
+				node("c", "10.0.0.3", corev1.ConditionTrue),
+				node("b", "10.0.0.2", corev1.ConditionTrue),
+			},
+			currentIP: "10.0.0.1",
+			expected:  "b",
+		},
+		{
+			name: "deleted holder is replaced",
+			nodes: []corev1.Node{
+				node("c", "10.0.0.3", corev1.ConditionTrue),
+			},
+			currentIP: "10.0.0.1",
+			expected:  "c",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			actual := electGatewayNode(g.nodes, g.currentIP)
+			name := ""
+			if actual != nil {
+				name = actual.Name
+			}
+			if name != g.expected {
+				t.Errorf("expected node %q, got %q", g.expected, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/util/pkg/vfs"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// etcdManagerControlDir is the directory of a backup store holding the control files of etcd-manager,
+// which are updated in place rather than written once like the backups.
+const etcdManagerControlDir = "control/"
+
+// NewEtcdBackupReplicator is the constructor for an EtcdBackupReplicator
+func NewEtcdBackupReplicator(opt *config.EtcdBackupReplicationOptions) (*EtcdBackupReplicator, error) {
+	r := &EtcdBackupReplicator{
+		log:     ctrl.Log.WithName("controllers").WithName("EtcdBackupReplicator"),
+		options: opt,
+	}
+
+	for _, store := range opt.Stores {
+		source, err := vfs.Context.BuildVfsPath(store.Source)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse backup store %q: %v", store.Source, err)
+		}
+		destination, err := vfs.Context.BuildVfsPath(store.Destination)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse replica store %q: %v", store.Destination, err)
+		}
+		r.stores = append(r.stores, &etcdBackupReplica{source: source, destination: destination})
+	}
+
+	return r, nil
+}
+
+// EtcdBackupReplicator periodically copies the new etcd backups written by etcd-manager to replica stores,
+// for example buckets in another region, so that the cluster can be restored if the backup store is lost.
+type EtcdBackupReplicator struct {
+	// log is a logr
+	log logr.Logger
+
+	// options configures the replication
+	options *config.EtcdBackupReplicationOptions
+
+	// stores are the parsed backup stores and their replica stores
+	stores []*etcdBackupReplica
+}
+
+type etcdBackupReplica struct {
+	source      vfs.Path
+	destination vfs.Path
+
+	// replicated are the paths of the files of the replica store, relative to it. The replica store is listed
+	// once, when the replicator starts, and the set is kept up to date as files are copied and removed.
+	replicated sets.String
+}
+
+var _ manager.Runnable = &EtcdBackupReplicator{}
+
+// Start copies the backups until the context is done.
+// As a manager.Runnable it only runs on the elected leader.
+func (r *EtcdBackupReplicator) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, r.replicate, r.options.Interval.Duration)
+	return nil
+}
+
+func (r *EtcdBackupReplicator) replicate(ctx context.Context) {
+	for _, store := range r.stores {
+		copied, removed, err := store.replicate()
+		if err != nil {
+			r.log.Error(err, "unable to copy etcd backups", "source", store.source.Path(), "destination", store.destination.Path())
+			continue
+		}
+		if copied != 0 || removed != 0 {
+			r.log.Info("replicated etcd backup files", "source", store.source.Path(), "destination", store.destination.Path(), "copied", copied, "removed", removed)
+		}
+	}
+}
+
+// replicate copies the files of the backup store that are missing from the replica store, and the control files of
+// etcd-manager that changed. The backups that etcd-manager removed from the backup store are removed from the replica
+// store too, unless the backup store holds no backup at all, so that losing the content of the backup store doesn't
+// lose the replica.
+func (s *etcdBackupReplica) replicate() (int, int, error) {
+	if s.replicated == nil {
+		replicated, err := relativeTree(s.destination)
+		if err != nil {
+			return 0, 0, fmt.Errorf("error listing replica store: %v", err)
+		}
+		s.replicated = replicated
+	}
+	files, err := s.source.ReadTree()
+	if err != nil {
+		return 0, 0, fmt.Errorf("error listing backup store: %v", err)
+	}
+
+	copied := 0
+	backups := sets.NewString()
+	for _, file := range files {
+		relativePath := relativePath(s.source, file)
+		target := s.destination.Join(relativePath)
+		if strings.HasPrefix(relativePath, etcdManagerControlDir) {
+			// The control files are small, and updated in place
+			if s.replicated.Has(relativePath) {
+				same, err := sameContents(file, target)
+				if err != nil {
+					return copied, 0, err
+				}
+				if same {
+					continue
+				}
+			}
+		} else {
+			backups.Insert(relativePath)
+			if s.replicated.Has(relativePath) {
+				continue
+			}
+		}
+
+		if err := copyFile(file, target); err != nil {
+			return copied, 0, err
+		}
+		s.replicated.Insert(relativePath)
+		copied++
+	}
+
+	removed := 0
+	if backups.Len() == 0 {
+		return copied, removed, nil
+	}
+	for _, relativePath := range s.replicated.List() {
+		if strings.HasPrefix(relativePath, etcdManagerControlDir) || backups.Has(relativePath) {
+			continue
+		}
+		target := s.destination.Join(relativePath)
+		if err := target.Remove(); err != nil && !os.IsNotExist(err) {
+			return copied, removed, fmt.Errorf("error removing %s: %v", target, err)
+		}
+		s.replicated.Delete(relativePath)
+		removed++
+	}
+
+	return copied, removed, nil
+}
+
+// copyFile copies the file through a temporary file, rather than memory, as the etcd backups can be much bigger
+// than the memory of kops-controller
+func copyFile(file, target vfs.Path) error {
+	tmp, err := ioutil.TempFile("", "etcd-backup-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %v", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	if _, err := file.WriteTo(tmp); err != nil {
+		return fmt.Errorf("error reading %s: %v", file, err)
+	}
+	if _, err := tmp.Seek(0, 0); err != nil {
+		return fmt.Errorf("error seeking temporary file: %v", err)
+	}
+	if err := target.WriteFile(tmp, nil); err != nil {
+		return fmt.Errorf("error writing %s: %v", target, err)
+	}
+	return nil
+}
+
+// sameContents returns whether both files have the same contents, comparing their hashes when the stores provide them
+func sameContents(file, target vfs.Path) (bool, error) {
+	if fileHash, ok := file.(vfs.HasHash); ok {
+		if targetHash, ok := target.(vfs.HasHash); ok {
+			h1, err := fileHash.PreferredHash()
+			if err != nil {
+				return false, fmt.Errorf("error hashing %s: %v", file, err)
+			}
+			h2, err := targetHash.PreferredHash()
+			if err != nil && !os.IsNotExist(err) {
+				return false, fmt.Errorf("error hashing %s: %v", target, err)
+			}
+			if h1 != nil && h2 != nil {
+				return h1.Equal(h2), nil
+			}
+		}
+	}
+
+	data, err := file.ReadFile()
+	if err != nil {
+		return false, fmt.Errorf("error reading %s: %v", file, err)
+	}
+	existing, err := target.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading %s: %v", target, err)
+	}
+	return bytes.Equal(data, existing), nil
+}
+
+// relativeTree returns the paths of the files below base, relative to base
+func relativeTree(base vfs.Path) (sets.String, error) {
+	paths := sets.NewString()
+	files, err := base.ReadTree()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return paths, nil
+		}
+		return nil, err
+	}
+	for _, file := range files {
+		paths.Insert(relativePath(base, file))
+	}
+	return paths, nil
+}
+
+func relativePath(base, file vfs.Path) string {
+	return strings.TrimPrefix(strings.TrimPrefix(file.Path(), base.Path()), "/")
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestReplicateEtcdBackups(t *testing.T) {
+	memfs := vfs.NewMemFSContext()
+	source := vfs.NewMemFSPath(memfs, "backups/etcd/main")
+	destination := vfs.NewMemFSPath(memfs, "replica/etcd/main")
+
+	write := func(p vfs.Path, contents string) {
+		if err := p.WriteFile(bytes.NewReader([]byte(contents)), nil); err != nil {
+			t.Fatalf("error writing %s: %v", p, err)
+		}
+	}
+	read := func(p vfs.Path) string {
+		data, err := p.ReadFile()
+		if err != nil {
+			t.Fatalf("error reading %s: %v", p, err)
+		}
+		return string(data)
+	}
+
+	replicate := func(replica *etcdBackupReplica, expectedCopied, expectedRemoved int) {
+		copied, removed, err := replica.replicate()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if copied != expectedCopied {
+			t.Errorf("expected %d files to be copied, got %d", expectedCopied, copied)
+		}
+		if removed != expectedRemoved {
+			t.Errorf("expected %d files to be removed, got %d", expectedRemoved, removed)
+		}
+	}
+
+	write(source.Join("control/etcd-cluster-spec"), "spec-1")
+	write(source.Join("2021-08-01T00:00:00Z-000001/etcd.backup.gz"), "backup-1")
+
+	replica := &etcdBackupReplica{source: source, destination: destination}
+	replicate(replica, 2, 0)
+
+	// The control files are copied again when they change, the backups only once
+	write(source.Join("control/etcd-cluster-spec"), "spec-2")
+	write(source.Join("2021-08-01T00:15:00Z-000002/etcd.backup.gz"), "backup-2")
+	write(destination.Join("2021-08-01T00:00:00Z-000001/etcd.backup.gz"), "backup-1-replica")
+
+	replicate(replica, 2, 0)
+	if got := read(destination.Join("control/etcd-cluster-spec")); got != "spec-2" {
+		t.Errorf("unexpected control file %q", got)
+	}
+	if got := read(destination.Join("2021-08-01T00:00:00Z-000001/etcd.backup.gz")); got != "backup-1-replica" {
+		t.Errorf("existing backup was copied again: %q", got)
+	}
+	if got := read(destination.Join("2021-08-01T00:15:00Z-000002/etcd.backup.gz")); got != "backup-2" {
+		t.Errorf("unexpected backup %q", got)
+	}
+
+	// Unchanged control files are not copied again
+	replicate(replica, 0, 0)
+
+	// A replicator started later lists the replica store rather than copying everything again
+	replicate(&etcdBackupReplica{source: source, destination: destination}, 0, 0)
+
+	// The backups removed from the backup store are removed from the replica store. MemFS still lists the removed
+	// files, so the backup store is written again without them.
+	replica.source = vfs.NewMemFSPath(vfs.NewMemFSContext(), "backups/etcd/main")
+	write(replica.source.Join("control/etcd-cluster-spec"), "spec-2")
+	write(replica.source.Join("2021-08-01T00:15:00Z-000002/etcd.backup.gz"), "backup-2")
+	replicate(replica, 0, 1)
+	if _, err := destination.Join("2021-08-01T00:00:00Z-000001/etcd.backup.gz").ReadFile(); !os.IsNotExist(err) {
+		t.Errorf("expected removed backup to be removed from the replica store, got %v", err)
+	}
+
+	// but not when the backup store holds no backup at all
+	replica.source = vfs.NewMemFSPath(vfs.NewMemFSContext(), "backups/etcd/main")
+	write(replica.source.Join("control/etcd-cluster-spec"), "spec-2")
+	replicate(replica, 0, 0)
+	if got := read(destination.Join("2021-08-01T00:15:00Z-000002/etcd.backup.gz")); got != "backup-2" {
+		t.Errorf("unexpected backup %q", got)
+	}
+}
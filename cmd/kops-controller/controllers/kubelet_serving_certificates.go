@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/pkg/nodeidentity"
+	"k8s.io/kops/pkg/rbac"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// kubeletServingCertificateApprovedReason is the reason of the Approved condition set on the requests kops-controller approves.
+const kubeletServingCertificateApprovedReason = "KopsControllerApprove"
+
+// NewKubeletServingCertificateApprover is the constructor for a KubeletServingCertificateApprover
+func NewKubeletServingCertificateApprover(mgr manager.Manager, identifier nodeidentity.Identifier) (*KubeletServingCertificateApprover, error) {
+	k8sClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %v", err)
+	}
+
+	return &KubeletServingCertificateApprover{
+		client:     mgr.GetClient(),
+		log:        ctrl.Log.WithName("controllers").WithName("KubeletServingCertificateApprover"),
+		k8sClient:  k8sClient,
+		identifier: identifier,
+	}, nil
+}
+
+// KubeletServingCertificateApprover observes the CertificateSigningRequests for kubelet serving certificates,
+// and approves those whose DNS names and IP addresses are addresses of the instance of the requesting node,
+// as reported by the cloud provider. Requests that do not match are left pending.
+type KubeletServingCertificateApprover struct {
+	// client is the controller-runtime client
+	client client.Client
+
+	// log is a logr
+	log logr.Logger
+
+	// k8sClient is a client-go client for approving requests
+	k8sClient kubernetes.Interface
+
+	// identifier is a provider that can securely map nodes to the addresses of their instances
+	identifier nodeidentity.Identifier
+}
+
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/approval,verbs=update
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=signers,resourceNames=kubernetes.io/kubelet-serving,verbs=approve
+// Reconcile is the main reconciler function that observes certificate signing request changes.
+func (r *KubeletServingCertificateApprover) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("csr", req.Name)
+
+	csr := &certificatesv1.CertificateSigningRequest{}
+	if err := r.client.Get(ctx, req.NamespacedName, csr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if csr.Spec.SignerName != certificatesv1.KubeletServingSignerName || isCSRDecided(csr) {
+		return ctrl.Result{}, nil
+	}
+
+	nodeName, err := kubeletServingCSRNodeName(csr)
+	if err != nil {
+		log.Info("not approving request", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("not approving request", "reason", fmt.Sprintf("node %q not found", nodeName))
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	info, err := r.identifier.IdentifyNode(ctx, node)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error identifying node %q: %v", node.Name, err)
+	}
+
+	if err := validateKubeletServingCSR(csr, nodeName, info.Addresses); err != nil {
+		log.Info("not approving request", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:           certificatesv1.CertificateApproved,
+		Status:         corev1.ConditionTrue,
+		Reason:         kubeletServingCertificateApprovedReason,
+		Message:        "The names and addresses of the kubelet serving certificate match the instance of the node",
+		LastUpdateTime: metav1.Now(),
+	})
+	if _, err := r.k8sClient.CertificatesV1().CertificateSigningRequests().UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("error approving request %q: %v", csr.Name, err)
+	}
+	log.Info("approved kubelet serving certificate", "node", nodeName)
+
+	return ctrl.Result{}, nil
+}
+
+func (r *KubeletServingCertificateApprover) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("kubeletservingcertificateapprover").
+		For(&certificatesv1.CertificateSigningRequest{}).
+		Complete(r)
+}
+
+// isCSRDecided returns true if the request has already been approved, denied or failed.
+func isCSRDecided(csr *certificatesv1.CertificateSigningRequest) bool {
+	for _, condition := range csr.Status.Conditions {
+		switch condition.Type {
+		case certificatesv1.CertificateApproved, certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return true
+		}
+	}
+	return false
+}
+
+// kubeletServingCSRNodeName returns the name of the node that created the request.
+func kubeletServingCSRNodeName(csr *certificatesv1.CertificateSigningRequest) (string, error) {
+	if !strings.HasPrefix(csr.Spec.Username, "system:node:") {
+		return "", fmt.Errorf("requested by %q, which is not a node", csr.Spec.Username)
+	}
+	if !sets.NewString(csr.Spec.Groups...).Has(rbac.NodesGroup) {
+		return "", fmt.Errorf("requester %q is not in the %q group", csr.Spec.Username, rbac.NodesGroup)
+	}
+	return strings.TrimPrefix(csr.Spec.Username, "system:node:"), nil
+}
+
+// validateKubeletServingCSR checks that the request is for a serving certificate of the node,
+// valid only for addresses of the instance of the node.
+func validateKubeletServingCSR(csr *certificatesv1.CertificateSigningRequest, nodeName string, addresses []string) error {
+	allowedUsages := sets.NewString(
+		string(certificatesv1.UsageDigitalSignature),
+		string(certificatesv1.UsageKeyEncipherment),
+		string(certificatesv1.UsageServerAuth),
+	)
+	hasServerAuth := false
+	for _, usage := range csr.Spec.Usages {
+		if !allowedUsages.Has(string(usage)) {
+			return fmt.Errorf("usage %q is not allowed", usage)
+		}
+		if usage == certificatesv1.UsageServerAuth {
+			hasServerAuth = true
+		}
+	}
+	if !hasServerAuth {
+		return fmt.Errorf("usage %q is missing", certificatesv1.UsageServerAuth)
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return fmt.Errorf("request is not a PEM encoded certificate request")
+	}
+	x509cr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("error parsing certificate request: %v", err)
+	}
+	if err := x509cr.CheckSignature(); err != nil {
+		return fmt.Errorf("invalid certificate request signature: %v", err)
+	}
+
+	if x509cr.Subject.CommonName != "system:node:"+nodeName {
+		return fmt.Errorf("common name %q does not match node %q", x509cr.Subject.CommonName, nodeName)
+	}
+	if len(x509cr.Subject.Organization) != 1 || x509cr.Subject.Organization[0] != rbac.NodesGroup {
+		return fmt.Errorf("organization %v is not %q", x509cr.Subject.Organization, rbac.NodesGroup)
+	}
+	if len(x509cr.EmailAddresses) != 0 || len(x509cr.URIs) != 0 {
+		return fmt.Errorf("email and URI names are not allowed")
+	}
+	if len(x509cr.DNSNames) == 0 && len(x509cr.IPAddresses) == 0 {
+		return fmt.Errorf("no DNS names or IP addresses requested")
+	}
+
+	if len(addresses) == 0 {
+		return fmt.Errorf("the cloud provider did not report the addresses of node %q", nodeName)
+	}
+	instanceAddresses := sets.NewString(addresses...)
+	for _, name := range x509cr.DNSNames {
+		if !instanceAddresses.Has(name) {
+			return fmt.Errorf("DNS name %q is not a name of the instance of node %q", name, nodeName)
+		}
+	}
+	for _, ip := range x509cr.IPAddresses {
+		if !instanceAddresses.Has(ip.String()) {
+			return fmt.Errorf("IP address %q is not an address of the instance of node %q", ip, nodeName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"strings"
+	"testing"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+)
+
+func buildKubeletServingCSR(t *testing.T, subject pkix.Name, dnsNames []string, ips []string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	template := &x509.CertificateRequest{
+		Subject:  subject,
+		DNSNames: dnsNames,
+	}
+	for _, ip := range ips {
+		template.IPAddresses = append(template.IPAddresses, net.ParseIP(ip))
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatalf("error creating certificate request: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func TestKubeletServingCSRNodeName(t *testing.T) {
+	grid := []struct {
+		username string
+		groups   []string
+		expected string
+		err      string
+	}{
+		{
+			username: "system:node:ip-10-0-1-1.ec2.internal",
+			groups:   []string{"system:nodes", "system:authenticated"},
+			expected: "ip-10-0-1-1.ec2.internal",
+		},
+		{
+			username: "system:serviceaccount:kube-system:default",
+			groups:   []string{"system:nodes"},
+			err:      "which is not a node",
+		},
+		{
+			username: "system:node:ip-10-0-1-1.ec2.internal",
+			groups:   []string{"system:authenticated"},
+			err:      "is not in the \"system:nodes\" group",
+		},
+	}
+
+	for _, g := range grid {
+		csr := &certificatesv1.CertificateSigningRequest{
+			Spec: certificatesv1.CertificateSigningRequestSpec{
+				Username: g.username,
+				Groups:   g.groups,
+			},
+		}
+		actual, err := kubeletServingCSRNodeName(csr)
+		if g.err != "" {
+			if err == nil || !strings.Contains(err.Error(), g.err) {
+				t.Errorf("%s: expected error containing %q, got %v", g.username, g.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", g.username, err)
+		} else if actual != g.expected {
+			t.Errorf("%s: unexpected node name %q", g.username, actual)
+		}
+	}
+}
+
+func TestValidateKubeletServingCSR(t *testing.T) {
+	nodeName := "ip-10-0-1-1.ec2.internal"
+	addresses := []string{"ip-10-0-1-1.ec2.internal", "ec2-3-4-5-6.compute-1.amazonaws.com", "10.0.1.1", "3.4.5.6", "2600:1f18::1"}
+	subject := pkix.Name{CommonName: "system:node:" + nodeName, Organization: []string{"system:nodes"}}
+	serverUsages := []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageServerAuth}
+
+	grid := []struct {
+		name      string
+		subject   pkix.Name
+		dnsNames  []string
+		ips       []string
+		usages    []certificatesv1.KeyUsage
+		addresses []string
+		err       string
+	}{
+		{
+			name:     "valid",
+			dnsNames: []string{"ip-10-0-1-1.ec2.internal"},
+			ips:      []string{"10.0.1.1", "3.4.5.6", "2600:1f18:0:0:0:0:0:1"},
+		},
+		{
+			name:     "unknown DNS name",
+			dnsNames: []string{"kubernetes.default"},
+			err:      "DNS name \"kubernetes.default\" is not a name of the instance",
+		},
+		{
+			name:     "unknown IP address",
+			dnsNames: []string{"ip-10-0-1-1.ec2.internal"},
+			ips:      []string{"10.0.1.2"},
+			err:      "IP address \"10.0.1.2\" is not an address of the instance",
+		},
+		{
+			name:     "other node",
+			subject:  pkix.Name{CommonName: "system:node:ip-10-0-1-2.ec2.internal", Organization: []string{"system:nodes"}},
+			dnsNames: []string{"ip-10-0-1-1.ec2.internal"},
+			err:      "does not match node",
+		},
+		{
+			name:     "wrong organization",
+			subject:  pkix.Name{CommonName: "system:node:" + nodeName, Organization: []string{"system:masters"}},
+			dnsNames: []string{"ip-10-0-1-1.ec2.internal"},
+			err:      "organization",
+		},
+		{
+			name:     "client usage",
+			dnsNames: []string{"ip-10-0-1-1.ec2.internal"},
+			usages:   []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageClientAuth},
+			err:      "usage \"client auth\" is not allowed",
+		},
+		{
+			name: "no names",
+			err:  "no DNS names or IP addresses requested",
+		},
+		{
+			name:      "no addresses reported",
+			dnsNames:  []string{"ip-10-0-1-1.ec2.internal"},
+			addresses: []string{},
+			err:       "the cloud provider did not report the addresses",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			if g.subject.CommonName == "" {
+				g.subject = subject
+			}
+			if g.usages == nil {
+				g.usages = serverUsages
+			}
+			if g.addresses == nil {
+				g.addresses = addresses
+			}
+			csr := &certificatesv1.CertificateSigningRequest{
+				Spec: certificatesv1.CertificateSigningRequestSpec{
+					Request:    buildKubeletServingCSR(t, g.subject, g.dnsNames, g.ips),
+					SignerName: certificatesv1.KubeletServingSignerName,
+					Usages:     g.usages,
+				},
+			}
+			err := validateKubeletServingCSR(csr, nodeName, g.addresses)
+			if g.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), g.err) {
+				t.Errorf("expected error containing %q, got %v", g.err, err)
+			}
+		})
+	}
+}
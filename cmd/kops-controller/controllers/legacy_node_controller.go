@@ -110,6 +110,7 @@ func (r *LegacyNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("unable to load instance group object for node %s: %v", node.Name, err)
 	}
+	ig = cluster.InstanceGroupWithDefaults(ig)
 
 	labels := nodelabels.BuildNodeLabels(cluster, ig)
 
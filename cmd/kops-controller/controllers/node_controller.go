@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops-controller/pkg/bootmilestones"
 	"k8s.io/kops/pkg/nodeidentity"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,11 +38,12 @@ import (
 )
 
 // NewNodeReconciler is the constructor for a NodeReconciler
-func NewNodeReconciler(mgr manager.Manager, identifier nodeidentity.Identifier) (*NodeReconciler, error) {
+func NewNodeReconciler(mgr manager.Manager, identifier nodeidentity.Identifier, identityLabelsOnly bool) (*NodeReconciler, error) {
 	r := &NodeReconciler{
-		client:     mgr.GetClient(),
-		log:        ctrl.Log.WithName("controllers").WithName("Node"),
-		identifier: identifier,
+		client:             mgr.GetClient(),
+		log:                ctrl.Log.WithName("controllers").WithName("Node"),
+		identifier:         identifier,
+		identityLabelsOnly: identityLabelsOnly,
 	}
 
 	coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
@@ -65,6 +69,10 @@ type NodeReconciler struct {
 
 	// identifier is a provider that can securely map node ProviderIDs to labels
 	identifier nodeidentity.Identifier
+
+	// identityLabelsOnly is true if only the labels identifying the role and instance group of the node are applied,
+	// because the NodeLabelReconciler applies the other node labels of the instance group
+	identityLabelsOnly bool
 }
 
 // +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch;patch
@@ -93,25 +101,43 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 	updateLabels := make(map[string]string)
 	for k, v := range labels {
+		if r.identityLabelsOnly && !isIdentityLabel(k) {
+			continue
+		}
 		actual, found := node.Labels[k]
 		if !found || actual != v {
 			updateLabels[k] = v
 		}
 	}
 
-	if len(updateLabels) == 0 {
-		klog.V(4).Infof("no label changes needed for %s", node.Name)
+	updateAnnotations := bootmilestones.Missing(node, info.LaunchTime, time.Now())
+
+	if len(updateLabels) == 0 && len(updateAnnotations) == 0 {
+		klog.V(4).Infof("no label or annotation changes needed for %s", node.Name)
 		return ctrl.Result{}, nil
 	}
 
-	if err := patchNodeLabels(r.coreV1Client, ctx, node, updateLabels); err != nil {
-		klog.Warningf("failed to patch node labels on %s: %v", node.Name, err)
+	patched, err := patchNode(r.coreV1Client, ctx, node, updateLabels, updateAnnotations)
+	if err != nil {
+		klog.Warningf("failed to patch node %s: %v", node.Name, err)
 		return ctrl.Result{}, err
 	}
 
+	observeBootMilestones(patched, updateAnnotations)
+
 	return ctrl.Result{}, nil
 }
 
+// observeBootMilestones records the metrics for the boot milestones that were newly annotated on the node.
+func observeBootMilestones(node *corev1.Node, setAnnotations map[string]string) {
+	for k := range setAnnotations {
+		milestone := strings.TrimPrefix(k, bootmilestones.AnnotationPrefix)
+		if t, found := bootmilestones.Get(node, milestone); found {
+			bootmilestones.Observe(node, milestone, t)
+		}
+	}
+}
+
 func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Node{}).
@@ -123,28 +149,36 @@ type nodePatch struct {
 }
 
 type nodePatchMetadata struct {
-	Labels map[string]string `json:"labels,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // patchNodeLabels patches the node labels to set the specified labels
 func patchNodeLabels(client *corev1client.CoreV1Client, ctx context.Context, node *corev1.Node, setLabels map[string]string) error {
+	_, err := patchNode(client, ctx, node, setLabels, nil)
+	return err
+}
+
+// patchNode patches the node to set the specified labels and annotations, returning the patched node
+func patchNode(client *corev1client.CoreV1Client, ctx context.Context, node *corev1.Node, setLabels map[string]string, setAnnotations map[string]string) (*corev1.Node, error) {
 	nodePatchMetadata := &nodePatchMetadata{
-		Labels: setLabels,
+		Labels:      setLabels,
+		Annotations: setAnnotations,
 	}
 	nodePatch := &nodePatch{
 		Metadata: nodePatchMetadata,
 	}
 	nodePatchJson, err := json.Marshal(nodePatch)
 	if err != nil {
-		return fmt.Errorf("error building node patch: %v", err)
+		return nil, fmt.Errorf("error building node patch: %v", err)
 	}
 
 	klog.V(2).Infof("sending patch for node %q: %q", node.Name, string(nodePatchJson))
 
-	_, err = client.Nodes().Patch(ctx, node.Name, types.StrategicMergePatchType, nodePatchJson, metav1.PatchOptions{})
+	patched, err := client.Nodes().Patch(ctx, node.Name, types.StrategicMergePatchType, nodePatchJson, metav1.PatchOptions{})
 	if err != nil {
-		return fmt.Errorf("error applying patch to node: %v", err)
+		return nil, fmt.Errorf("error applying patch to node: %v", err)
 	}
 
-	return nil
+	return patched, nil
 }
@@ -0,0 +1,288 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/nodelabels"
+	"k8s.io/kops/util/pkg/vfs"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// managedLabelsAnnotation is set on nodes to the keys of the node labels applied from their instance group,
+	// so that they are removed when they are removed from the instance group.
+	managedLabelsAnnotation = "kops.k8s.io/instancegroup-labels"
+
+	// managedTaintsAnnotation is set on nodes to the keys and effects of the taints applied from their instance group,
+	// so that they are removed when they are removed from the instance group.
+	managedTaintsAnnotation = "kops.k8s.io/instancegroup-taints"
+)
+
+// NewNodeLabelReconciler is the constructor for a NodeLabelReconciler
+func NewNodeLabelReconciler(mgr manager.Manager, configPath string, opt *config.NodeLabelReconciliationOptions) (*NodeLabelReconciler, error) {
+	configBase, err := vfs.Context.BuildVfsPath(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse ConfigBase %q: %v", configPath, err)
+	}
+
+	return &NodeLabelReconciler{
+		client:     mgr.GetClient(),
+		log:        ctrl.Log.WithName("controllers").WithName("NodeLabelReconciler"),
+		configBase: configBase,
+		interval:   opt.Interval.Duration,
+	}, nil
+}
+
+// NodeLabelReconciler observes Node objects, and applies the node labels and taints of their instance group,
+// removing those that were removed from the instance group and the taint the nodes register with.
+// Changing the node labels and taints of an instance group therefore does not replace its nodes.
+type NodeLabelReconciler struct {
+	// client is the controller-runtime client
+	client client.Client
+
+	// log is a logr
+	log logr.Logger
+
+	// configBase is the parsed path to the base location of our configuration files
+	configBase vfs.Path
+
+	// interval is the time between reconciliations of each node, and how long the instance groups are cached
+	interval time.Duration
+
+	// mutex guards the cached cluster and instance groups
+	mutex sync.Mutex
+	// loaded is the time the cluster and instance groups were loaded
+	loaded time.Time
+	// cluster is the cached cluster
+	cluster *kops.Cluster
+	// instanceGroups are the cached instance groups, by name
+	instanceGroups map[string]*kops.InstanceGroup
+}
+
+// +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch;patch
+// Reconcile is the main reconciler function that observes node changes.
+func (r *NodeLabelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("node", req.Name)
+
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, req.NamespacedName, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The node controller labels the node with its instance group, which triggers another reconciliation.
+	igName := node.Labels[kops.NodeLabelInstanceGroup]
+	if igName == "" {
+		return ctrl.Result{}, nil
+	}
+
+	cluster, ig, err := r.getInstanceGroup(igName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if ig == nil {
+		log.Info("instance group of node not found", "instanceGroup", igName)
+		return ctrl.Result{RequeueAfter: r.interval}, nil
+	}
+	if !model.UseNodeLabelReconciliation(cluster, ig) {
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFromWithOptions(node.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	changed, err := applyInstanceGroupLabels(node, nodelabels.BuildNodeLabels(cluster, ig), ig.Spec.Taints)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error reconciling node %q with instance group %q: %v", node.Name, igName, err)
+	}
+	if changed {
+		if err := r.client.Patch(ctx, node, patch); err != nil {
+			if apierrors.IsNotFound(err) {
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("error patching node %q: %v", node.Name, err)
+		}
+		log.Info("applied node labels and taints of instance group", "instanceGroup", igName)
+	}
+
+	return ctrl.Result{RequeueAfter: r.interval}, nil
+}
+
+// SetupWithManager registers the node label reconciler with the manager.
+func (r *NodeLabelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("nodelabelreconciler").
+		For(&corev1.Node{}).
+		Complete(r)
+}
+
+// getInstanceGroup returns the cluster and the named instance group, or nil if it does not exist.
+// They are loaded from the state store at most once per interval.
+func (r *NodeLabelReconciler) getInstanceGroup(name string) (*kops.Cluster, *kops.InstanceGroup, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.cluster == nil || time.Since(r.loaded) >= r.interval {
+		cluster, err := loadCluster(r.configBase)
+		if err != nil {
+			return nil, nil, err
+		}
+		list, err := loadInstanceGroups(r.configBase, cluster)
+		if err != nil {
+			return nil, nil, err
+		}
+		instanceGroups := make(map[string]*kops.InstanceGroup)
+		for i := range list.Items {
+			ig := &list.Items[i]
+			instanceGroups[ig.ObjectMeta.Name] = ig
+		}
+		r.cluster = cluster
+		r.instanceGroups = instanceGroups
+		r.loaded = time.Now()
+	}
+
+	return r.cluster, r.instanceGroups[name], nil
+}
+
+// applyInstanceGroupLabels sets the labels and taints of the node to those of its instance group.
+// The labels and taints previously applied but no longer in the instance group are removed, as is the taint
+// the node registered with. It returns true if the node was changed.
+func applyInstanceGroupLabels(node *corev1.Node, labels map[string]string, taintSpecs []string) (bool, error) {
+	var taints []corev1.Taint
+	for _, spec := range taintSpecs {
+		taint, err := parseNodeTaint(spec)
+		if err != nil {
+			return false, err
+		}
+		taints = append(taints, taint)
+	}
+
+	original := node.DeepCopy()
+
+	if node.Labels == nil {
+		node.Labels = make(map[string]string)
+	}
+	for _, k := range splitAnnotationList(node.Annotations[managedLabelsAnnotation]) {
+		if _, found := labels[k]; !found && !isIdentityLabel(k) {
+			delete(node.Labels, k)
+		}
+	}
+	for k, v := range labels {
+		node.Labels[k] = v
+	}
+
+	desiredTaints := sets.NewString()
+	for _, taint := range taints {
+		desiredTaints.Insert(taintKeyEffect(taint))
+	}
+	removeTaints := sets.NewString(splitAnnotationList(node.Annotations[managedTaintsAnnotation])...).Difference(desiredTaints)
+	removeTaints.Insert(nodelabels.TaintInstanceGroupPending + ":" + string(corev1.TaintEffectNoSchedule))
+
+	var nodeTaints []corev1.Taint
+	for _, taint := range node.Spec.Taints {
+		if !removeTaints.Has(taintKeyEffect(taint)) {
+			nodeTaints = append(nodeTaints, taint)
+		}
+	}
+	for _, taint := range taints {
+		found := false
+		for i := range nodeTaints {
+			if taintKeyEffect(nodeTaints[i]) == taintKeyEffect(taint) {
+				nodeTaints[i].Value = taint.Value
+				found = true
+			}
+		}
+		if !found {
+			nodeTaints = append(nodeTaints, taint)
+		}
+	}
+	node.Spec.Taints = nodeTaints
+
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	labelKeys := make([]string, 0, len(labels))
+	for k := range labels {
+		labelKeys = append(labelKeys, k)
+	}
+	sort.Strings(labelKeys)
+	node.Annotations[managedLabelsAnnotation] = strings.Join(labelKeys, ",")
+	if desiredTaints.Len() > 0 {
+		node.Annotations[managedTaintsAnnotation] = strings.Join(desiredTaints.List(), ",")
+	} else {
+		delete(node.Annotations, managedTaintsAnnotation)
+	}
+
+	return !equality.Semantic.DeepEqual(original, node), nil
+}
+
+// parseNodeTaint parses a taint in the format of the instance group, key[=value]:effect.
+func parseNodeTaint(spec string) (corev1.Taint, error) {
+	var taint corev1.Taint
+
+	parts := strings.Split(spec, ":")
+	if len(parts) != 2 || parts[1] == "" {
+		return taint, fmt.Errorf("invalid taint %q: expected key[=value]:effect", spec)
+	}
+	taint.Effect = corev1.TaintEffect(parts[1])
+
+	kv := strings.SplitN(parts[0], "=", 2)
+	taint.Key = kv[0]
+	if len(kv) == 2 {
+		taint.Value = kv[1]
+	}
+	if taint.Key == "" {
+		return taint, fmt.Errorf("invalid taint %q: expected key[=value]:effect", spec)
+	}
+	return taint, nil
+}
+
+// taintKeyEffect returns the key and effect identifying the taint.
+func taintKeyEffect(taint corev1.Taint) string {
+	return taint.Key + ":" + string(taint.Effect)
+}
+
+// splitAnnotationList splits a comma separated annotation value.
+func splitAnnotationList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// isIdentityLabel returns true if the label identifies the role or instance group of the node,
+// which the node controller applies from the tags of its instance.
+func isIdentityLabel(key string) bool {
+	return key == kops.NodeLabelInstanceGroup || key == nodelabels.RoleLabelName15 || strings.HasPrefix(key, "node-role.kubernetes.io/")
+}
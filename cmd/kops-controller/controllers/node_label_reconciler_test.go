@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestApplyInstanceGroupLabels(t *testing.T) {
+	grid := []struct {
+		name        string
+		node        corev1.Node
+		labels      map[string]string
+		taints      []string
+		changed     bool
+		expected    corev1.Node
+		expectError bool
+	}{
+		{
+			name: "new node",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kops.k8s.io/instancegroup": "nodes"},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{{Key: "kops.k8s.io/instancegroup-pending", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+			labels:  map[string]string{"kops.k8s.io/instancegroup": "nodes", "team": "payments"},
+			taints:  []string{"dedicated=payments:NoSchedule"},
+			changed: true,
+			expected: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kops.k8s.io/instancegroup": "nodes", "team": "payments"},
+					Annotations: map[string]string{
+						"kops.k8s.io/instancegroup-labels": "kops.k8s.io/instancegroup,team",
+						"kops.k8s.io/instancegroup-taints": "dedicated:NoSchedule",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{{Key: "dedicated", Value: "payments", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+		},
+		{
+			name: "removed and changed",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kops.k8s.io/instancegroup": "nodes", "team": "payments", "other": "x"},
+					Annotations: map[string]string{
+						"kops.k8s.io/instancegroup-labels": "kops.k8s.io/instancegroup,team",
+						"kops.k8s.io/instancegroup-taints": "dedicated:NoSchedule",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "dedicated", Value: "payments", Effect: corev1.TaintEffectNoSchedule},
+						{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoExecute},
+					},
+				},
+			},
+			labels:  map[string]string{"kops.k8s.io/instancegroup": "nodes", "tier": "batch"},
+			taints:  []string{"batch:NoExecute"},
+			changed: true,
+			expected: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kops.k8s.io/instancegroup": "nodes", "tier": "batch", "other": "x"},
+					Annotations: map[string]string{
+						"kops.k8s.io/instancegroup-labels": "kops.k8s.io/instancegroup,tier",
+						"kops.k8s.io/instancegroup-taints": "batch:NoExecute",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoExecute},
+						{Key: "batch", Effect: corev1.TaintEffectNoExecute},
+					},
+				},
+			},
+		},
+		{
+			name: "unchanged",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"kops.k8s.io/instancegroup": "nodes"},
+					Annotations: map[string]string{"kops.k8s.io/instancegroup-labels": "kops.k8s.io/instancegroup"},
+				},
+			},
+			labels: map[string]string{"kops.k8s.io/instancegroup": "nodes"},
+			expected: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"kops.k8s.io/instancegroup": "nodes"},
+					Annotations: map[string]string{"kops.k8s.io/instancegroup-labels": "kops.k8s.io/instancegroup"},
+				},
+			},
+		},
+		{
+			name:        "invalid taint",
+			labels:      map[string]string{},
+			taints:      []string{"dedicated=payments"},
+			expectError: true,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			node := g.node.DeepCopy()
+			changed, err := applyInstanceGroupLabels(node, g.labels, g.taints)
+			if g.expectError {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if changed != g.changed {
+				t.Errorf("unexpected changed: %v, expected %v", changed, g.changed)
+			}
+			if !reflect.DeepEqual(*node, g.expected) {
+				t.Errorf("unexpected node:\n%+v\nexpected:\n%+v", *node, g.expected)
+			}
+		})
+	}
+}
+
+func TestLoadInstanceGroupsInheritsDefaults(t *testing.T) {
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster.example.com")
+	ig := "apiVersion: kops.k8s.io/v1alpha2\nkind: InstanceGroup\nmetadata:\n  name: nodes\nspec:\n  role: Node\n  nodeLabels:\n    team: payments\n"
+	if err := configBase.Join("instancegroup", "nodes").WriteFile(bytes.NewReader([]byte(ig)), nil); err != nil {
+		t.Fatalf("error writing instance group: %v", err)
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.InstanceGroupDefaults = &kops.InstanceGroupSpec{
+		MachineType: "m5.large",
+		NodeLabels:  map[string]string{"tier": "batch"},
+		Taints:      []string{"dedicated=batch:NoSchedule"},
+	}
+
+	list, err := loadInstanceGroups(configBase, cluster)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("expected 1 instance group, got %d", len(list.Items))
+	}
+	spec := list.Items[0].Spec
+	if spec.MachineType != "m5.large" {
+		t.Errorf("expected the inherited machine type, got %q", spec.MachineType)
+	}
+	if expected := map[string]string{"team": "payments", "tier": "batch"}; !reflect.DeepEqual(spec.NodeLabels, expected) {
+		t.Errorf("unexpected node labels %v, expected %v", spec.NodeLabels, expected)
+	}
+	if expected := []string{"dedicated=batch:NoSchedule"}; !reflect.DeepEqual(spec.Taints, expected) {
+		t.Errorf("unexpected taints %v, expected %v", spec.Taints, expected)
+	}
+}
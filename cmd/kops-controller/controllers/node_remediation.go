@@ -0,0 +1,474 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/maintenancewindow"
+	"k8s.io/kops/pkg/nodelabels"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/drain"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// remediationAnnotation is set on nodes being remediated, to the condition that triggered the remediation.
+	remediationAnnotation = "kops.k8s.io/remediation"
+
+	// recycledAnnotation is set on nodes whose instance has been deleted, to the time of the deletion.
+	recycledAnnotation = "kops.k8s.io/remediation-recycled"
+
+	// remediationFailuresAnnotation is set on nodes whose recycling has failed, to the number of failed attempts.
+	remediationFailuresAnnotation = "kops.k8s.io/remediation-failures"
+
+	// remediationFailedAtAnnotation is set on nodes whose recycling has failed, to the time of the last failure.
+	remediationFailedAtAnnotation = "kops.k8s.io/remediation-failed-at"
+
+	// remediationRetryInterval is the time to wait before retrying when too many nodes are being remediated.
+	remediationRetryInterval = time.Minute
+
+	// remediationDrainTimeout is the maximum time spent draining a node before its instance is deleted.
+	remediationDrainTimeout = 10 * time.Minute
+
+	// remediationMaxAttempts is the number of times recycling a node is attempted before giving up on the node.
+	remediationMaxAttempts = 3
+
+	// remediationBackoff is the time to wait after the first failure to recycle a node; it doubles with each failure.
+	remediationBackoff = 10 * time.Minute
+)
+
+// NewNodeRemediationReconciler is the constructor for a NodeRemediationReconciler
+func NewNodeRemediationReconciler(mgr manager.Manager, configPath string, opt *config.RemediationOptions, window *maintenancewindow.Window) (*NodeRemediationReconciler, error) {
+	k8sClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %v", err)
+	}
+
+	configBase, err := vfs.Context.BuildVfsPath(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse ConfigBase %q: %v", configPath, err)
+	}
+
+	return &NodeRemediationReconciler{
+		client:        mgr.GetClient(),
+		log:           ctrl.Log.WithName("controllers").WithName("NodeRemediation"),
+		k8sClient:     k8sClient,
+		recorder:      mgr.GetEventRecorderFor("kops-controller"),
+		configBase:    configBase,
+		conditions:    sets.NewString(opt.Conditions...),
+		threshold:     opt.Threshold.Duration,
+		maxConcurrent: opt.MaxConcurrent,
+		recycle:       opt.Recycle,
+		window:        window,
+		recycling:     sets.NewString(),
+	}, nil
+}
+
+// NodeRemediationReconciler observes Node objects, and cordons the nodes that have reported
+// one of the configured conditions, typically set by node-problem-detector, for longer than the threshold.
+// When recycling is enabled, the cordoned nodes are drained and their instances are deleted,
+// so that the cloud provider replaces them.
+type NodeRemediationReconciler struct {
+	// client is the controller-runtime client
+	client client.Client
+
+	// log is a logr
+	log logr.Logger
+
+	// k8sClient is a client-go client for cordoning and draining nodes
+	k8sClient kubernetes.Interface
+
+	// recorder records Events about the remediation of nodes
+	recorder record.EventRecorder
+
+	// configBase is the parsed path to the base location of our configuration files
+	configBase vfs.Path
+
+	// conditions are the types of node conditions that trigger remediation
+	conditions sets.String
+
+	// threshold is how long a condition must have been true before the node is remediated
+	threshold time.Duration
+
+	// maxConcurrent is the maximum number of nodes being remediated at the same time
+	maxConcurrent int
+
+	// recycle is true if the instances of cordoned nodes should be deleted
+	recycle bool
+
+	// window is the maintenance window outside which nodes are not recycled, or nil
+	window *maintenancewindow.Window
+
+	// mutex protects recycling
+	mutex sync.Mutex
+
+	// recycling is the names of the nodes being recycled in the background
+	recycling sets.String
+}
+
+// +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch;patch
+// Reconcile is the main reconciler function that observes node changes.
+func (r *NodeRemediationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("node", req.Name)
+
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, req.NamespacedName, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// We never remediate the control plane; losing a member is riskier than the problem being reported.
+	if isControlPlaneNode(node) {
+		return ctrl.Result{}, nil
+	}
+
+	// The result of the recycling is recorded on the node once it completes, which triggers another reconciliation.
+	if r.isRecycling(node.Name) {
+		return ctrl.Result{}, nil
+	}
+
+	condition, wait := r.persistentCondition(node, time.Now())
+	if condition == nil {
+		if _, remediating := node.Annotations[remediationAnnotation]; remediating && !r.recycle {
+			return ctrl.Result{}, r.clearRemediation(ctx, node)
+		}
+		if _, failed := node.Annotations[remediationFailuresAnnotation]; failed {
+			return ctrl.Result{}, r.clearFailures(ctx, node)
+		}
+		if wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if _, remediating := node.Annotations[remediationAnnotation]; !remediating {
+		if r.recycle {
+			failures, retryAfter := remediationBackoffFor(node, time.Now())
+			if failures >= remediationMaxAttempts {
+				log.Info("not remediating node: recycling has failed too many times", "condition", condition.Type, "failures", failures)
+				return ctrl.Result{}, nil
+			}
+			if retryAfter > 0 {
+				log.Info("delaying remediation of node after a failure to recycle it", "condition", condition.Type, "failures", failures, "retryAfter", retryAfter)
+				return ctrl.Result{RequeueAfter: retryAfter}, nil
+			}
+		}
+
+		nodes := &corev1.NodeList{}
+		if err := r.client.List(ctx, nodes); err != nil {
+			return ctrl.Result{}, fmt.Errorf("error listing nodes: %v", err)
+		}
+		inProgress := 0
+		for i := range nodes.Items {
+			if _, ok := nodes.Items[i].Annotations[remediationAnnotation]; ok {
+				inProgress++
+			}
+		}
+		if inProgress >= r.maxConcurrent {
+			log.Info("delaying remediation of node: too many nodes are being remediated", "condition", condition.Type, "inProgress", inProgress)
+			return ctrl.Result{RequeueAfter: remediationRetryInterval}, nil
+		}
+
+		if err := r.startRemediation(ctx, node, condition); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if _, recycled := node.Annotations[recycledAnnotation]; recycled || !r.recycle {
+		return ctrl.Result{}, nil
+	}
+
+	// The node stays cordoned until the maintenance window opens.
+	if now := time.Now(); r.window != nil && !r.window.Contains(now) {
+		next := r.window.Next(now)
+		if next.IsZero() {
+			log.Info("not recycling node: the maintenance window never opens")
+			return ctrl.Result{}, nil
+		}
+		log.Info("delaying recycling of node until the maintenance window opens", "opens", next)
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+
+	// Draining can take up to remediationDrainTimeout, so it does not run in the reconcile loop.
+	r.startRecycling(node.DeepCopy())
+	return ctrl.Result{}, nil
+}
+
+// isRecycling returns true if the node is being recycled in the background.
+func (r *NodeRemediationReconciler) isRecycling(name string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.recycling.Has(name)
+}
+
+// startRecycling recycles the node in the background, then records the result on the node.
+func (r *NodeRemediationReconciler) startRecycling(node *corev1.Node) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.recycling.Has(node.Name) {
+		return
+	}
+	r.recycling.Insert(node.Name)
+
+	go func() {
+		defer func() {
+			r.mutex.Lock()
+			defer r.mutex.Unlock()
+			r.recycling.Delete(node.Name)
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*remediationDrainTimeout)
+		defer cancel()
+
+		if err := r.recycleNode(ctx, node); err != nil {
+			r.log.Error(err, "unable to recycle node", "node", node.Name)
+			r.recorder.Eventf(node, corev1.EventTypeWarning, "NodeRecycleFailed", "Unable to replace node: %v", err)
+			if err := r.rollbackRemediation(ctx, node); err != nil {
+				r.log.Error(err, "unable to roll back remediation of node", "node", node.Name)
+			}
+			return
+		}
+		r.recorder.Event(node, corev1.EventTypeNormal, "NodeRecycled", "Deleted the instance of the node so that it is replaced")
+
+		patch := client.MergeFrom(node.DeepCopy())
+		node.Annotations[recycledAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		if err := r.client.Patch(ctx, node, patch); err != nil && !apierrors.IsNotFound(err) {
+			r.log.Error(err, "unable to annotate node", "node", node.Name)
+		}
+	}()
+}
+
+// rollbackRemediation uncordons a node that could not be recycled and records the failure,
+// so that the node no longer counts against maxConcurrent and is retried after a backoff.
+func (r *NodeRemediationReconciler) rollbackRemediation(ctx context.Context, node *corev1.Node) error {
+	current := &corev1.Node{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: node.Name}, current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting node: %v", err)
+	}
+
+	if err := drain.RunCordonOrUncordon(r.drainHelper(ctx), current, false); err != nil {
+		return fmt.Errorf("error uncordoning node: %v", err)
+	}
+
+	failures, _ := remediationBackoffFor(current, time.Now())
+	patch := client.MergeFrom(current.DeepCopy())
+	if current.Annotations == nil {
+		current.Annotations = make(map[string]string)
+	}
+	delete(current.Annotations, remediationAnnotation)
+	current.Annotations[remediationFailuresAnnotation] = strconv.Itoa(failures + 1)
+	current.Annotations[remediationFailedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	if err := r.client.Patch(ctx, current, patch); err != nil {
+		return fmt.Errorf("error annotating node: %v", err)
+	}
+	return nil
+}
+
+// clearFailures removes the record of failed recycling attempts from a node that no longer reports any configured condition.
+func (r *NodeRemediationReconciler) clearFailures(ctx context.Context, node *corev1.Node) error {
+	patch := client.MergeFrom(node.DeepCopy())
+	delete(node.Annotations, remediationFailuresAnnotation)
+	delete(node.Annotations, remediationFailedAtAnnotation)
+	if err := r.client.Patch(ctx, node, patch); err != nil {
+		return fmt.Errorf("error removing annotation from node: %v", err)
+	}
+	return nil
+}
+
+// remediationBackoffFor returns the number of failed attempts to recycle the node,
+// and the time remaining until it may be attempted again.
+func remediationBackoffFor(node *corev1.Node, now time.Time) (int, time.Duration) {
+	failures, err := strconv.Atoi(node.Annotations[remediationFailuresAnnotation])
+	if err != nil || failures <= 0 {
+		return 0, 0
+	}
+	failedAt, err := time.Parse(time.RFC3339, node.Annotations[remediationFailedAtAnnotation])
+	if err != nil {
+		return failures, 0
+	}
+	backoff := remediationBackoff << uint(failures-1)
+	if remaining := backoff - now.Sub(failedAt); remaining > 0 {
+		return failures, remaining
+	}
+	return failures, 0
+}
+
+// SetupWithManager registers the remediation controller with the manager.
+func (r *NodeRemediationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("noderemediation").
+		For(&corev1.Node{}).
+		Complete(r)
+}
+
+// persistentCondition returns the first configured condition that has been true for at least the threshold.
+// If there is none, it returns the time until the next configured condition that is true reaches the threshold.
+func (r *NodeRemediationReconciler) persistentCondition(node *corev1.Node, now time.Time) (*corev1.NodeCondition, time.Duration) {
+	var wait time.Duration
+	for i := range node.Status.Conditions {
+		condition := &node.Status.Conditions[i]
+		if condition.Status != corev1.ConditionTrue || !r.conditions.Has(string(condition.Type)) {
+			continue
+		}
+		remaining := r.threshold - now.Sub(condition.LastTransitionTime.Time)
+		if remaining <= 0 {
+			return condition, 0
+		}
+		if wait == 0 || remaining < wait {
+			wait = remaining
+		}
+	}
+	return nil, wait
+}
+
+// startRemediation marks the node as being remediated and cordons it.
+func (r *NodeRemediationReconciler) startRemediation(ctx context.Context, node *corev1.Node, condition *corev1.NodeCondition) error {
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[remediationAnnotation] = string(condition.Type)
+	if err := r.client.Patch(ctx, node, patch); err != nil {
+		return fmt.Errorf("error annotating node: %v", err)
+	}
+
+	if err := drain.RunCordonOrUncordon(r.drainHelper(ctx), node, true); err != nil {
+		return fmt.Errorf("error cordoning node: %v", err)
+	}
+
+	r.recorder.Eventf(node, corev1.EventTypeWarning, "NodeCordoned", "Cordoned node because condition %s has been true since %s: %s",
+		condition.Type, condition.LastTransitionTime.Format(time.RFC3339), condition.Message)
+	return nil
+}
+
+// clearRemediation uncordons a node that was cordoned but no longer reports any configured condition.
+func (r *NodeRemediationReconciler) clearRemediation(ctx context.Context, node *corev1.Node) error {
+	if err := drain.RunCordonOrUncordon(r.drainHelper(ctx), node, false); err != nil {
+		return fmt.Errorf("error uncordoning node: %v", err)
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	delete(node.Annotations, remediationAnnotation)
+	if err := r.client.Patch(ctx, node, patch); err != nil {
+		return fmt.Errorf("error removing annotation from node: %v", err)
+	}
+
+	r.recorder.Event(node, corev1.EventTypeNormal, "NodeUncordoned", "Uncordoned node because it no longer reports any problem")
+	return nil
+}
+
+// recycleNode drains the node and deletes its instance through the cloud provider.
+func (r *NodeRemediationReconciler) recycleNode(ctx context.Context, node *corev1.Node) error {
+	helper := r.drainHelper(ctx)
+	helper.Timeout = remediationDrainTimeout
+	if err := drain.RunNodeDrain(helper, node.Name); err != nil {
+		// We go on to delete the instance: the problem the node is reporting may well be why it cannot be drained.
+		r.log.Error(err, "unable to drain node", "node", node.Name)
+	}
+
+	cluster, err := loadCluster(r.configBase)
+	if err != nil {
+		return err
+	}
+	instanceGroupList, err := loadInstanceGroups(r.configBase, cluster)
+	if err != nil {
+		return err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range instanceGroupList.Items {
+		instanceGroups = append(instanceGroups, &instanceGroupList.Items[i])
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return fmt.Errorf("error building cloud: %v", err)
+	}
+
+	groups, err := cloud.GetCloudGroups(cluster, instanceGroups, false, []corev1.Node{*node})
+	if err != nil {
+		return fmt.Errorf("error listing instances: %v", err)
+	}
+	for _, group := range groups {
+		for _, instance := range append(group.Ready, group.NeedUpdate...) {
+			if instance.Node == nil || instance.Node.Name != node.Name {
+				continue
+			}
+			if err := cloud.DeleteInstance(instance); err != nil {
+				return fmt.Errorf("error deleting instance %q: %v", instance.ID, err)
+			}
+			r.log.Info("deleted instance of node", "node", node.Name, "instance", instance.ID, "instanceGroup", group.HumanName)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unable to find the instance of node %q", node.Name)
+}
+
+func (r *NodeRemediationReconciler) drainHelper(ctx context.Context) *drain.Helper {
+	return &drain.Helper{
+		Ctx:                 ctx,
+		Client:              r.k8sClient,
+		Force:               true,
+		GracePeriodSeconds:  -1,
+		IgnoreAllDaemonSets: true,
+		DeleteEmptyDirData:  true,
+		Out:                 &logWriter{log: r.log},
+		ErrOut:              &logWriter{log: r.log},
+	}
+}
+
+// isControlPlaneNode returns true if the node is a member of the control plane.
+func isControlPlaneNode(node *corev1.Node) bool {
+	if _, ok := node.Labels[nodelabels.RoleLabelMaster16]; ok {
+		return true
+	}
+	if _, ok := node.Labels[nodelabels.RoleLabelControlPlane20]; ok {
+		return true
+	}
+	return false
+}
+
+// logWriter writes the output of the drain helper to the log.
+type logWriter struct {
+	log logr.Logger
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.log.Info(string(p))
+	return len(p), nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRemediationBackoffFor(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	grid := []struct {
+		name        string
+		annotations map[string]string
+		failures    int
+		retryAfter  time.Duration
+	}{
+		{
+			name: "no failures",
+		},
+		{
+			name: "first failure",
+			annotations: map[string]string{
+				remediationFailuresAnnotation: "1",
+				remediationFailedAtAnnotation: now.Add(-4 * time.Minute).Format(time.RFC3339),
+			},
+			failures:   1,
+			retryAfter: 6 * time.Minute,
+		},
+		{
+			name: "second failure doubles the backoff",
+			annotations: map[string]string{
+				remediationFailuresAnnotation: "2",
+				remediationFailedAtAnnotation: now.Add(-5 * time.Minute).Format(time.RFC3339),
+			},
+			failures:   2,
+			retryAfter: 15 * time.Minute,
+		},
+		{
+			name: "backoff elapsed",
+			annotations: map[string]string{
+				remediationFailuresAnnotation: "1",
+				remediationFailedAtAnnotation: now.Add(-time.Hour).Format(time.RFC3339),
+			},
+			failures: 1,
+		},
+		{
+			name: "invalid count",
+			annotations: map[string]string{
+				remediationFailuresAnnotation: "many",
+			},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: g.annotations}}
+			failures, retryAfter := remediationBackoffFor(node, now)
+			if failures != g.failures || retryAfter != g.retryAfter {
+				t.Errorf("got (%d, %v), expected (%d, %v)", failures, retryAfter, g.failures, g.retryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/maintenancewindow"
+	"k8s.io/kops/pkg/ospatch"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/vfs"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// patchedAnnotation is set on nodes to the time their packages were last updated.
+	patchedAnnotation = "kops.k8s.io/os-patched"
+
+	// patchCheckInterval is the time between checks for nodes that are due to be patched.
+	patchCheckInterval = 10 * time.Minute
+
+	// patchTimeout is the maximum time spent updating the packages of a node.
+	patchTimeout = 30 * time.Minute
+
+	// patchDrainTimeout is the maximum time spent draining a node before it is patched.
+	patchDrainTimeout = 30 * time.Minute
+
+	// patchRebootTimeout is the maximum time for a node to be ready again after it is rebooted.
+	patchRebootTimeout = 15 * time.Minute
+)
+
+// NewOSPatcher is the constructor for an OSPatcher
+func NewOSPatcher(mgr manager.Manager, configPath string, opt *config.OSPatchingOptions, window *maintenancewindow.Window) (*OSPatcher, error) {
+	k8sClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %v", err)
+	}
+
+	configBase, err := vfs.Context.BuildVfsPath(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse ConfigBase %q: %v", configPath, err)
+	}
+
+	return &OSPatcher{
+		log:        ctrl.Log.WithName("controllers").WithName("OSPatcher"),
+		configBase: configBase,
+		k8sClient:  k8sClient,
+		recorder:   mgr.GetEventRecorderFor("kops-controller"),
+		options:    opt,
+		window:     window,
+	}, nil
+}
+
+// OSPatcher periodically updates the operating system packages of the nodes, one node at a time,
+// as `kops toolbox patch-nodes` would. Each node is patched once per interval.
+type OSPatcher struct {
+	// log is a logr
+	log logr.Logger
+
+	// configBase is the parsed path to the base location of our configuration files
+	configBase vfs.Path
+
+	// k8sClient is a client-go client for the cluster being patched
+	k8sClient kubernetes.Interface
+
+	// recorder records Events about the patching of nodes
+	recorder record.EventRecorder
+
+	// options configures the patching
+	options *config.OSPatchingOptions
+
+	// window is the maintenance window outside which nodes are not patched, or nil
+	window *maintenancewindow.Window
+}
+
+var _ manager.Runnable = &OSPatcher{}
+
+// Start patches the nodes that are due until the context is done.
+// As a manager.Runnable it only runs on the elected leader.
+func (p *OSPatcher) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, p.patchDueNodes, patchCheckInterval)
+	return nil
+}
+
+func (p *OSPatcher) patchDueNodes(ctx context.Context) {
+	nodeList, err := p.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		p.log.Error(err, "unable to list nodes")
+		return
+	}
+
+	now := time.Now()
+	var due []corev1.Node
+	for _, node := range ospatch.SelectNodes(nodeList.Items, p.options.InstanceGroups) {
+		if isControlPlaneNode(&node) {
+			// Draining the control plane would evict kops-controller itself.
+			continue
+		}
+		if p.isDue(&node, now) {
+			due = append(due, node)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	patcher, err := p.buildPatcher()
+	if err != nil {
+		p.log.Error(err, "unable to patch nodes")
+		return
+	}
+
+	for i := range due {
+		node := &due[i]
+		// The window is checked before each node, so that patching stops when it closes.
+		if p.window != nil && !p.window.Contains(time.Now()) {
+			p.log.Info("delaying patching of nodes until the maintenance window opens", "nodes", len(due)-i)
+			return
+		}
+		if err := patcher.PatchNode(ctx, node.Name); err != nil {
+			p.log.Error(err, "unable to patch node", "node", node.Name)
+			p.recorder.Eventf(node, corev1.EventTypeWarning, "OSPatchFailed", "Unable to update operating system packages: %v", err)
+			// Stop here rather than risk taking down more nodes for the same reason.
+			return
+		}
+		if err := p.annotatePatched(ctx, node.Name, time.Now()); err != nil {
+			p.log.Error(err, "unable to annotate node", "node", node.Name)
+		}
+		p.recorder.Event(node, corev1.EventTypeNormal, "OSPatched", "Updated operating system packages")
+	}
+}
+
+// isDue returns true if the node was last patched, or created, more than the interval ago.
+func (p *OSPatcher) isDue(node *corev1.Node, now time.Time) bool {
+	last := node.CreationTimestamp.Time
+	if value, ok := node.Annotations[patchedAnnotation]; ok {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			last = t
+		} else {
+			p.log.Info("ignoring invalid annotation", "node", node.Name, "annotation", patchedAnnotation, "value", value)
+		}
+	}
+	return now.Sub(last) >= p.options.Interval.Duration
+}
+
+func (p *OSPatcher) buildPatcher() (*ospatch.Patcher, error) {
+	var runner ospatch.CommandRunner
+	switch p.options.Method {
+	case kops.OSPatchingMethodPod:
+		runner = &ospatch.PodRunner{
+			K8sClient: p.k8sClient,
+			Namespace: "kube-system",
+			Image:     ospatch.DefaultPodImage,
+		}
+
+	case kops.OSPatchingMethodSSM:
+		cluster, err := loadCluster(p.configBase)
+		if err != nil {
+			return nil, err
+		}
+		cloud, err := cloudup.BuildCloud(cluster)
+		if err != nil {
+			return nil, fmt.Errorf("error building cloud: %v", err)
+		}
+		awsCloud, ok := cloud.(awsup.AWSCloud)
+		if !ok {
+			return nil, fmt.Errorf("method %q requires AWS", p.options.Method)
+		}
+		runner = &ospatch.SSMRunner{SSM: awsCloud.SSM()}
+
+	default:
+		return nil, fmt.Errorf("unknown method %q", p.options.Method)
+	}
+
+	return &ospatch.Patcher{
+		K8sClient:     p.k8sClient,
+		Runner:        runner,
+		Reboot:        p.options.Reboot,
+		DrainTimeout:  patchDrainTimeout,
+		PatchTimeout:  patchTimeout,
+		RebootTimeout: patchRebootTimeout,
+		Out:           &logWriter{log: p.log},
+	}, nil
+}
+
+func (p *OSPatcher) annotatePatched(ctx context.Context, nodeName string, t time.Time) error {
+	patch := &nodePatch{
+		Metadata: &nodePatchMetadata{
+			Annotations: map[string]string{patchedAnnotation: t.UTC().Format(time.RFC3339)},
+		},
+	}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = p.k8sClient.CoreV1().Nodes().Patch(ctx, nodeName, types.StrategicMergePatchType, b, metav1.PatchOptions{})
+	return err
+}
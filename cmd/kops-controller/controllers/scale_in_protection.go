@@ -0,0 +1,188 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/nodeidentity"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// NewScaleInProtectionReconciler is the constructor for a ScaleInProtectionReconciler
+func NewScaleInProtectionReconciler(mgr manager.Manager, identifier nodeidentity.Identifier, options *config.ScaleInProtectionOptions) (*ScaleInProtectionReconciler, error) {
+	awsConfig := aws.NewConfig().WithCredentialsChainVerboseErrors(true)
+	s, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error starting new AWS session: %v", err)
+	}
+	region, err := ec2metadata.New(s, awsConfig).Region()
+	if err != nil {
+		return nil, fmt.Errorf("error querying ec2 metadata service (for region): %v", err)
+	}
+
+	return &ScaleInProtectionReconciler{
+		client:            mgr.GetClient(),
+		log:               ctrl.Log.WithName("controllers").WithName("ScaleInProtection"),
+		autoscalingClient: autoscaling.New(s, awsConfig.WithRegion(region)),
+		identifier:        identifier,
+		instanceGroups:    sets.NewString(options.InstanceGroups...),
+	}, nil
+}
+
+// ScaleInProtectionReconciler observes Node objects, and protects the instances of the nodes of the instance groups
+// from scale in until the nodes are cordoned. As the autoscaling groups only terminate unprotected instances on
+// scale in, they terminate the cordoned, and usually drained, nodes first.
+type ScaleInProtectionReconciler struct {
+	// client is the controller-runtime client
+	client client.Client
+
+	// log is a logr
+	log logr.Logger
+
+	// autoscalingClient is used to change the scale in protection of the instances
+	autoscalingClient autoscalingiface.AutoScalingAPI
+
+	// identifier is a provider that can securely map nodes to their instances
+	identifier nodeidentity.Identifier
+
+	// instanceGroups are the names of the instance groups whose instances are protected until their node is cordoned
+	instanceGroups sets.String
+}
+
+// +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch
+// Reconcile is the main reconciler function that observes node changes.
+func (r *ScaleInProtectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, req.NamespacedName, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !r.instanceGroups.Has(node.Labels[kops.NodeLabelInstanceGroup]) || node.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	info, err := r.identifier.IdentifyNode(ctx, node)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error identifying node %q: %v", node.Name, err)
+	}
+
+	response, err := r.autoscalingClient.DescribeAutoScalingInstancesWithContext(ctx, &autoscaling.DescribeAutoScalingInstancesInput{
+		InstanceIds: aws.StringSlice([]string{info.InstanceID}),
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error describing autoscaling instance %q: %v", info.InstanceID, err)
+	}
+	if len(response.AutoScalingInstances) == 0 {
+		// The instance was detached from its autoscaling group
+		return ctrl.Result{}, nil
+	}
+	instance := response.AutoScalingInstances[0]
+
+	protect, change, retry := scaleInProtectionChange(node, instance)
+	if retry {
+		// The protection of instances that are launching cannot be changed yet
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	if !change {
+		return ctrl.Result{}, nil
+	}
+
+	if _, err := r.autoscalingClient.SetInstanceProtectionWithContext(ctx, &autoscaling.SetInstanceProtectionInput{
+		AutoScalingGroupName: instance.AutoScalingGroupName,
+		InstanceIds:          aws.StringSlice([]string{info.InstanceID}),
+		ProtectedFromScaleIn: aws.Bool(protect),
+	}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("error setting scale in protection of instance %q: %v", info.InstanceID, err)
+	}
+	r.log.Info("set scale in protection", "node", node.Name, "instance", info.InstanceID, "autoscalingGroup", aws.StringValue(instance.AutoScalingGroupName), "protected", protect)
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager registers the scale in protection controller with the manager.
+func (r *ScaleInProtectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("scaleinprotection").
+		For(&corev1.Node{}, builder.WithPredicates(scaleInProtectionPredicate)).
+		Complete(r)
+}
+
+// scaleInProtectionPredicate only passes on the events that can change the scale in protection of the instance of a node:
+// its creation, and changes to whether it is cordoned or to its instance group. Node status heartbeats are ignored, so
+// that the autoscaling API is not called for every node every few seconds.
+var scaleInProtectionPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		return oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable ||
+			oldNode.Labels[kops.NodeLabelInstanceGroup] != newNode.Labels[kops.NodeLabelInstanceGroup]
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// scaleInProtectionChange returns whether the instance of the node should be protected from scale in, which is until
+// the node is cordoned, and whether its protection must be changed. Instances that are not in service cannot be
+// changed; retry is true for the instances that are still launching.
+func scaleInProtectionChange(node *corev1.Node, instance *autoscaling.InstanceDetails) (protect bool, change bool, retry bool) {
+	protect = !node.Spec.Unschedulable
+
+	switch aws.StringValue(instance.LifecycleState) {
+	case autoscaling.LifecycleStateInService:
+	case autoscaling.LifecycleStatePending, autoscaling.LifecycleStatePendingWait, autoscaling.LifecycleStatePendingProceed:
+		return protect, false, true
+	default:
+		return protect, false, false
+	}
+
+	return protect, aws.BoolValue(instance.ProtectedFromScaleIn) != protect, false
+}
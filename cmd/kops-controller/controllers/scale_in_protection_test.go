@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestScaleInProtectionChange(t *testing.T) {
+	grid := []struct {
+		Name           string
+		Unschedulable  bool
+		LifecycleState string
+		Protected      bool
+		ExpectProtect  bool
+		ExpectChange   bool
+		ExpectRetry    bool
+	}{
+		{
+			Name:           "schedulable node keeps its protection",
+			LifecycleState: autoscaling.LifecycleStateInService,
+			Protected:      true,
+			ExpectProtect:  true,
+		},
+		{
+			Name:           "schedulable node is protected",
+			LifecycleState: autoscaling.LifecycleStateInService,
+			ExpectProtect:  true,
+			ExpectChange:   true,
+		},
+		{
+			Name:           "cordoned node loses its protection",
+			Unschedulable:  true,
+			LifecycleState: autoscaling.LifecycleStateInService,
+			Protected:      true,
+			ExpectChange:   true,
+		},
+		{
+			Name:           "cordoned node stays unprotected",
+			Unschedulable:  true,
+			LifecycleState: autoscaling.LifecycleStateInService,
+		},
+		{
+			Name:           "launching instance is retried",
+			LifecycleState: autoscaling.LifecycleStatePendingWait,
+			ExpectProtect:  true,
+			ExpectRetry:    true,
+		},
+		{
+			Name:           "terminating instance is ignored",
+			Unschedulable:  true,
+			LifecycleState: autoscaling.LifecycleStateTerminating,
+			Protected:      true,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			node := &corev1.Node{Spec: corev1.NodeSpec{Unschedulable: g.Unschedulable}}
+			instance := &autoscaling.InstanceDetails{
+				LifecycleState:       aws.String(g.LifecycleState),
+				ProtectedFromScaleIn: aws.Bool(g.Protected),
+			}
+
+			protect, change, retry := scaleInProtectionChange(node, instance)
+			if protect != g.ExpectProtect || change != g.ExpectChange || retry != g.ExpectRetry {
+				t.Errorf("expected protect=%v change=%v retry=%v, got protect=%v change=%v retry=%v",
+					g.ExpectProtect, g.ExpectChange, g.ExpectRetry, protect, change, retry)
+			}
+		})
+	}
+}
+
+func TestScaleInProtectionPredicate(t *testing.T) {
+	node := func(unschedulable bool, instanceGroup string, heartbeat int64) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node",
+				Labels: map[string]string{"kops.k8s.io/instancegroup": instanceGroup},
+			},
+			Spec: corev1.NodeSpec{Unschedulable: unschedulable},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, LastHeartbeatTime: metav1.Unix(heartbeat, 0)}},
+			},
+		}
+	}
+
+	grid := []struct {
+		Name     string
+		Old      *corev1.Node
+		New      *corev1.Node
+		Expected bool
+	}{
+		{
+			Name:     "heartbeat",
+			Old:      node(false, "nodes", 0),
+			New:      node(false, "nodes", 10),
+			Expected: false,
+		},
+		{
+			Name:     "cordoned",
+			Old:      node(false, "nodes", 0),
+			New:      node(true, "nodes", 0),
+			Expected: true,
+		},
+		{
+			Name:     "uncordoned",
+			Old:      node(true, "nodes", 0),
+			New:      node(false, "nodes", 0),
+			Expected: true,
+		},
+		{
+			Name:     "instance group label set",
+			Old:      node(false, "", 0),
+			New:      node(false, "nodes", 0),
+			Expected: true,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			if actual := scaleInProtectionPredicate.Update(event.UpdateEvent{ObjectOld: g.Old, ObjectNew: g.New}); actual != g.Expected {
+				t.Errorf("expected %v, got %v", g.Expected, actual)
+			}
+		})
+	}
+
+	if !scaleInProtectionPredicate.Create(event.CreateEvent{Object: node(false, "nodes", 0)}) {
+		t.Errorf("expected node creation to pass the predicate")
+	}
+	if scaleInProtectionPredicate.Delete(event.DeleteEvent{Object: node(false, "nodes", 0)}) {
+		t.Errorf("expected node deletion not to pass the predicate")
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/kops/pkg/pki"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// webhookCertificateCheckInterval is the time between checks for webhook certificates that are due to be renewed.
+	webhookCertificateCheckInterval = time.Hour
+
+	// restartedAtAnnotation is set on the pod template of a Deployment to restart its pods, as `kubectl rollout restart` does.
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// NewWebhookCertificateIssuer is the constructor for a WebhookCertificateIssuer
+func NewWebhookCertificateIssuer(mgr manager.Manager, keystore pki.Keystore, opt *config.WebhookCertificatesOptions) (*WebhookCertificateIssuer, error) {
+	k8sClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %v", err)
+	}
+
+	return &WebhookCertificateIssuer{
+		log:       ctrl.Log.WithName("controllers").WithName("WebhookCertificateIssuer"),
+		k8sClient: k8sClient,
+		keystore:  keystore,
+		options:   opt,
+	}, nil
+}
+
+// WebhookCertificateIssuer issues the serving certificates of admission webhooks from the cluster CA,
+// stores them in Secrets, and renews them before they expire or when the CA changes.
+type WebhookCertificateIssuer struct {
+	// log is a logr
+	log logr.Logger
+
+	// k8sClient is a client-go client for the cluster the webhooks run in
+	k8sClient kubernetes.Interface
+
+	// keystore holds the CA that signs the certificates
+	keystore pki.Keystore
+
+	// options configures the certificates
+	options *config.WebhookCertificatesOptions
+}
+
+var _ manager.Runnable = &WebhookCertificateIssuer{}
+
+// Start issues the certificates that are due until the context is done.
+// As a manager.Runnable it only runs on the elected leader.
+func (i *WebhookCertificateIssuer) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, i.issueDueCertificates, webhookCertificateCheckInterval)
+	return nil
+}
+
+func (i *WebhookCertificateIssuer) issueDueCertificates(ctx context.Context) {
+	for _, opt := range i.options.Certificates {
+		if err := i.ensureCertificate(ctx, opt); err != nil {
+			i.log.Error(err, "unable to issue webhook certificate", "namespace", opt.Namespace, "secret", opt.Secret)
+		}
+	}
+}
+
+func (i *WebhookCertificateIssuer) ensureCertificate(ctx context.Context, opt config.WebhookCertificateOptions) error {
+	caCertificate, _, err := i.keystore.FindPrimaryKeypair(i.options.Signer)
+	if err != nil {
+		return err
+	}
+
+	secrets := i.k8sClient.CoreV1().Secrets(opt.Namespace)
+	secret, err := secrets.Get(ctx, opt.Secret, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error getting secret: %v", err)
+	}
+	exists := err == nil
+	if exists && !webhookCertificateNeedsRenewal(secret, opt, caCertificate, time.Now()) {
+		return nil
+	}
+
+	data, err := i.issueCertificate(opt)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		secret.Data = data
+		if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("error updating secret: %v", err)
+		}
+		i.log.Info("renewed webhook certificate", "namespace", opt.Namespace, "secret", opt.Secret)
+	} else {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      opt.Secret,
+				Namespace: opt.Namespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "kops"},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating secret: %v", err)
+		}
+		i.log.Info("issued webhook certificate", "namespace", opt.Namespace, "secret", opt.Secret)
+	}
+
+	// Webhooks read their certificate when they start, so a renewed certificate is only served after a restart.
+	// A new Secret needs no restart: the pods can't have started without it.
+	if exists && opt.Deployment != "" {
+		if err := i.restartDeployment(ctx, opt.Namespace, opt.Deployment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *WebhookCertificateIssuer) issueCertificate(opt config.WebhookCertificateOptions) (map[string][]byte, error) {
+	dnsNames := webhookDNSNames(opt)
+	req := &pki.IssueCertRequest{
+		Signer:         i.options.Signer,
+		Type:           "server",
+		Subject:        pkix.Name{CommonName: dnsNames[len(dnsNames)-1]},
+		AlternateNames: dnsNames,
+		Validity:       opt.Validity.Duration,
+	}
+	certificate, privateKey, caCertificate, err := pki.IssueCert(req, i.keystore)
+	if err != nil {
+		return nil, fmt.Errorf("error issuing certificate: %v", err)
+	}
+
+	certificateBytes, err := certificate.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	privateKeyBytes, err := privateKey.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	caBytes, err := caCertificate.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		opt.CertificateKey: certificateBytes,
+		opt.PrivateKeyKey:  privateKeyBytes,
+		opt.CAKey:          caBytes,
+	}, nil
+}
+
+func (i *WebhookCertificateIssuer) restartDeployment(ctx context.Context, namespace, name string) error {
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = i.k8sClient.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patchJSON, metav1.PatchOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error restarting deployment %s/%s: %v", namespace, name, err)
+	}
+	return nil
+}
+
+// webhookDNSNames returns the names the API server may use to reach the webhook Service.
+func webhookDNSNames(opt config.WebhookCertificateOptions) []string {
+	return []string{
+		opt.Service,
+		opt.Service + "." + opt.Namespace,
+		opt.Service + "." + opt.Namespace + ".svc",
+	}
+}
+
+// webhookCertificateNeedsRenewal is true if the certificate in the secret is missing or invalid,
+// was not signed by the current CA, or has less than a third of its validity left.
+func webhookCertificateNeedsRenewal(secret *corev1.Secret, opt config.WebhookCertificateOptions, caCertificate *pki.Certificate, now time.Time) bool {
+	if len(secret.Data[opt.PrivateKeyKey]) == 0 {
+		return true
+	}
+
+	caBytes, err := caCertificate.AsBytes()
+	if err != nil || string(caBytes) != string(secret.Data[opt.CAKey]) {
+		return true
+	}
+
+	certificate, err := pki.ParsePEMCertificate(secret.Data[opt.CertificateKey])
+	if err != nil {
+		return true
+	}
+	cert := certificate.Certificate
+	if err := cert.CheckSignatureFrom(caCertificate.Certificate); err != nil {
+		return true
+	}
+	if !sets.NewString(cert.DNSNames...).HasAll(webhookDNSNames(opt)...) {
+		return true
+	}
+
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotBefore.Add(lifetime * 2 / 3))
+}
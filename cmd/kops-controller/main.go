@@ -22,22 +22,30 @@ import (
 	"io/ioutil"
 	"os"
 
+	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/kops/cmd/kops-controller/controllers"
 	"k8s.io/kops/cmd/kops-controller/pkg/config"
 	"k8s.io/kops/cmd/kops-controller/pkg/server"
+	"k8s.io/kops/pkg/maintenancewindow"
 	"k8s.io/kops/pkg/nodeidentity"
 	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
 	nodeidentityazure "k8s.io/kops/pkg/nodeidentity/azure"
 	nodeidentitydo "k8s.io/kops/pkg/nodeidentity/do"
 	nodeidentitygce "k8s.io/kops/pkg/nodeidentity/gce"
+	nodeidentityhybrid "k8s.io/kops/pkg/nodeidentity/hybrid"
+	nodeidentitymetal "k8s.io/kops/pkg/nodeidentity/metal"
+	nodeidentityoci "k8s.io/kops/pkg/nodeidentity/oci"
 	nodeidentityos "k8s.io/kops/pkg/nodeidentity/openstack"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hybrid"
+	"k8s.io/kops/util/pkg/vfs"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/yaml"
@@ -98,7 +106,22 @@ func main() {
 			klog.Fatalf("server cloud provider config not provided")
 		}
 
-		srv, err := server.NewServer(&opt, verifier)
+		if opt.HybridNodes != nil {
+			configBase, err := vfs.Context.BuildVfsPath(opt.ConfigBase)
+			if err != nil {
+				setupLog.Error(err, "unable to parse configBase")
+				os.Exit(1)
+			}
+			verifier = hybrid.NewVerifier(configBase, opt.HybridNodes.InstanceGroups, verifier)
+		}
+
+		coreV1Client, err := corev1client.NewForConfig(ctrl.GetConfigOrDie())
+		if err != nil {
+			setupLog.Error(err, "unable to create kubernetes client")
+			os.Exit(1)
+		}
+
+		srv, err := server.NewServer(&opt, verifier, coreV1Client)
 		if err != nil {
 			setupLog.Error(err, "unable to create server")
 			os.Exit(1)
@@ -115,6 +138,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	if opt.Validation != nil && opt.Validation.MetricsAddress != "" {
+		metricsAddress = opt.Validation.MetricsAddress
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddress,
@@ -130,6 +157,60 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "NodeController")
 		os.Exit(1)
 	}
+	if opt.Validation != nil {
+		if err := addClusterValidator(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create cluster validator")
+			os.Exit(1)
+		}
+	}
+	if opt.Remediation != nil {
+		if err := addNodeRemediationController(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NodeRemediation")
+			os.Exit(1)
+		}
+	}
+	if opt.OSPatching != nil {
+		if err := addOSPatcher(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create OS patcher")
+			os.Exit(1)
+		}
+	}
+	if opt.NodeLabelReconciliation != nil {
+		if err := addNodeLabelReconciler(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NodeLabelReconciler")
+			os.Exit(1)
+		}
+	}
+	if opt.WebhookCertificates != nil {
+		if err := addWebhookCertificateIssuer(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create webhook certificate issuer")
+			os.Exit(1)
+		}
+	}
+	if opt.ApproveKubeletServingCertificates {
+		if err := addKubeletServingCertificateApprover(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "KubeletServingCertificateApprover")
+			os.Exit(1)
+		}
+	}
+	if len(opt.EgressGateways) != 0 {
+		if err := addEgressGatewayController(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "EgressGateway")
+			os.Exit(1)
+		}
+	}
+	if opt.ScaleInProtection != nil {
+		if err := addScaleInProtectionController(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ScaleInProtection")
+			os.Exit(1)
+		}
+	}
+	if opt.EtcdBackupReplication != nil {
+		if err := addEtcdBackupReplicator(mgr, &opt); err != nil {
+			setupLog.Error(err, "unable to create etcd backup replicator")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -143,6 +224,9 @@ func buildScheme() error {
 	if err := corev1.AddToScheme(scheme); err != nil {
 		return fmt.Errorf("error registering corev1: %v", err)
 	}
+	if err := certificatesv1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("error registering certificatesv1: %v", err)
+	}
 	return nil
 }
 
@@ -181,6 +265,18 @@ func addNodeController(mgr manager.Manager, opt *config.Options) error {
 			return fmt.Errorf("error building identifier: %v", err)
 		}
 
+	case "oci":
+		legacyIdentifier, err = nodeidentityoci.New()
+		if err != nil {
+			return fmt.Errorf("error building identifier: %v", err)
+		}
+
+	case "metal":
+		legacyIdentifier, err = nodeidentitymetal.New(opt.ConfigBase)
+		if err != nil {
+			return fmt.Errorf("error building identifier: %v", err)
+		}
+
 	case "":
 		return fmt.Errorf("must specify cloud")
 
@@ -188,8 +284,18 @@ func addNodeController(mgr manager.Manager, opt *config.Options) error {
 		return fmt.Errorf("identifier for cloud %q not implemented", opt.Cloud)
 	}
 
+	if identifier != nil && opt.HybridNodes != nil {
+		identifier, err = nodeidentityhybrid.New(opt.ConfigBase, identifier)
+		if err != nil {
+			return fmt.Errorf("error building identifier: %v", err)
+		}
+	}
+
 	if identifier != nil {
-		nodeController, err := controllers.NewNodeReconciler(mgr, identifier)
+		// The instance tags hold the node labels of the instance group at launch, which are stale
+		// when the node labels are reconciled.
+		identityLabelsOnly := opt.NodeLabelReconciliation != nil
+		nodeController, err := controllers.NewNodeReconciler(mgr, identifier, identityLabelsOnly)
 		if err != nil {
 			return err
 		}
@@ -212,3 +318,145 @@ func addNodeController(mgr manager.Manager, opt *config.Options) error {
 
 	return nil
 }
+
+func addKubeletServingCertificateApprover(mgr manager.Manager, opt *config.Options) error {
+	if opt.Cloud != "aws" {
+		return fmt.Errorf("approving kubelet serving certificates is not supported on cloud %q", opt.Cloud)
+	}
+
+	identifier, err := nodeidentityaws.New(opt.CacheNodeidentityInfo)
+	if err != nil {
+		return fmt.Errorf("error building identifier: %v", err)
+	}
+
+	approver, err := controllers.NewKubeletServingCertificateApprover(mgr, identifier)
+	if err != nil {
+		return err
+	}
+	return approver.SetupWithManager(mgr)
+}
+
+func addEgressGatewayController(mgr manager.Manager, opt *config.Options) error {
+	if opt.Cloud != "aws" {
+		return fmt.Errorf("egress gateways are not supported on cloud %q", opt.Cloud)
+	}
+
+	identifier, err := nodeidentityaws.New(opt.CacheNodeidentityInfo)
+	if err != nil {
+		return fmt.Errorf("error building identifier: %v", err)
+	}
+
+	egressGatewayController, err := controllers.NewEgressGatewayReconciler(mgr, identifier, opt.EgressGateways)
+	if err != nil {
+		return err
+	}
+	return egressGatewayController.SetupWithManager(mgr)
+}
+
+func addScaleInProtectionController(mgr manager.Manager, opt *config.Options) error {
+	if opt.Cloud != "aws" {
+		return fmt.Errorf("scale in protection is not supported on cloud %q", opt.Cloud)
+	}
+
+	identifier, err := nodeidentityaws.New(opt.CacheNodeidentityInfo)
+	if err != nil {
+		return fmt.Errorf("error building identifier: %v", err)
+	}
+
+	scaleInProtectionController, err := controllers.NewScaleInProtectionReconciler(mgr, identifier, opt.ScaleInProtection)
+	if err != nil {
+		return err
+	}
+	return scaleInProtectionController.SetupWithManager(mgr)
+}
+
+func addClusterValidator(mgr manager.Manager, opt *config.Options) error {
+	if opt.ConfigBase == "" {
+		return fmt.Errorf("must specify configBase")
+	}
+
+	validator, err := controllers.NewClusterValidator(mgr, opt.ConfigBase, opt.Validation)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(validator)
+}
+
+func addNodeRemediationController(mgr manager.Manager, opt *config.Options) error {
+	if opt.ConfigBase == "" {
+		return fmt.Errorf("must specify configBase")
+	}
+
+	window, err := buildMaintenanceWindow(opt)
+	if err != nil {
+		return err
+	}
+
+	remediationController, err := controllers.NewNodeRemediationReconciler(mgr, opt.ConfigBase, opt.Remediation, window)
+	if err != nil {
+		return err
+	}
+	return remediationController.SetupWithManager(mgr)
+}
+
+func addOSPatcher(mgr manager.Manager, opt *config.Options) error {
+	if opt.ConfigBase == "" {
+		return fmt.Errorf("must specify configBase")
+	}
+
+	window, err := buildMaintenanceWindow(opt)
+	if err != nil {
+		return err
+	}
+
+	patcher, err := controllers.NewOSPatcher(mgr, opt.ConfigBase, opt.OSPatching, window)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(patcher)
+}
+
+func addEtcdBackupReplicator(mgr manager.Manager, opt *config.Options) error {
+	replicator, err := controllers.NewEtcdBackupReplicator(opt.EtcdBackupReplication)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(replicator)
+}
+
+// buildMaintenanceWindow returns the maintenance window of the cluster, or nil if disruptive operations are always allowed.
+func buildMaintenanceWindow(opt *config.Options) (*maintenancewindow.Window, error) {
+	if opt.MaintenanceWindow == nil {
+		return nil, nil
+	}
+	window, err := maintenancewindow.New(opt.MaintenanceWindow.Schedule, opt.MaintenanceWindow.Duration.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window: %v", err)
+	}
+	return window, nil
+}
+
+func addNodeLabelReconciler(mgr manager.Manager, opt *config.Options) error {
+	if opt.ConfigBase == "" {
+		return fmt.Errorf("must specify configBase")
+	}
+
+	reconciler, err := controllers.NewNodeLabelReconciler(mgr, opt.ConfigBase, opt.NodeLabelReconciliation)
+	if err != nil {
+		return err
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+func addWebhookCertificateIssuer(mgr manager.Manager, opt *config.Options) error {
+	keystore, err := server.NewKeystore(opt.WebhookCertificates.CABasePath, []string{opt.WebhookCertificates.Signer})
+	if err != nil {
+		return err
+	}
+
+	issuer, err := controllers.NewWebhookCertificateIssuer(mgr, keystore, opt.WebhookCertificates)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(issuer)
+}
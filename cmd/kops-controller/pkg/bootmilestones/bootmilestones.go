@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootmilestones
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// AnnotationPrefix prefixes the Node annotations holding the times the node reached its boot milestones.
+const AnnotationPrefix = "boot-milestones.kops.k8s.io/"
+
+// MaxNodeAge is the age after which the boot milestones of a node are no longer recorded.
+// Nodes that registered before kops-controller recorded milestones would otherwise get
+// misleading times, such as the last time they became Ready.
+const MaxNodeAge = time.Hour
+
+var bootMilestoneSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "kops_node_boot_milestone_seconds",
+	Help:    "Time from the launch of the instance of a node to the node reaching a boot milestone.",
+	Buckets: []float64{15, 30, 45, 60, 90, 120, 180, 240, 300, 450, 600, 900},
+}, []string{"milestone", "instance_group"})
+
+func init() {
+	metrics.Registry.MustRegister(bootMilestoneSeconds)
+}
+
+// Annotation returns the Node annotation holding the time of the milestone.
+func Annotation(milestone string) string {
+	return AnnotationPrefix + milestone
+}
+
+// Get returns the time the node reached the milestone, if it is recorded.
+func Get(node *corev1.Node, milestone string) (time.Time, bool) {
+	v, found := node.Annotations[Annotation(milestone)]
+	if !found {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Format formats the time of a milestone as an annotation value.
+func Format(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Missing returns the annotations for the milestones the node reached that are not recorded yet.
+// Milestones are only recorded once, so that restarts of the node do not overwrite them.
+func Missing(node *corev1.Node, launchTime time.Time, now time.Time) map[string]string {
+	if now.Sub(node.CreationTimestamp.Time) > MaxNodeAge {
+		return nil
+	}
+
+	reached := map[string]time.Time{
+		nodeup.BootMilestoneKubeletRegistered: node.CreationTimestamp.Time,
+	}
+	if !launchTime.IsZero() {
+		reached[nodeup.BootMilestoneInstanceLaunched] = launchTime
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			reached[nodeup.BootMilestoneNodeReady] = condition.LastTransitionTime.Time
+		}
+	}
+
+	annotations := make(map[string]string)
+	for milestone, t := range reached {
+		if _, found := node.Annotations[Annotation(milestone)]; !found {
+			annotations[Annotation(milestone)] = Format(t)
+		}
+	}
+	return annotations
+}
+
+// Observe records the time from the launch of the instance of the node to the milestone,
+// if the launch time of the node is recorded.
+func Observe(node *corev1.Node, milestone string, t time.Time) {
+	if milestone == nodeup.BootMilestoneInstanceLaunched {
+		return
+	}
+	launched, found := Get(node, nodeup.BootMilestoneInstanceLaunched)
+	if !found {
+		return
+	}
+	bootMilestoneSeconds.WithLabelValues(milestone, node.Labels[kops.NodeLabelInstanceGroup]).Observe(t.Sub(launched).Seconds())
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootmilestones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMissing(t *testing.T) {
+	launched := time.Date(2021, 7, 1, 10, 0, 0, 0, time.UTC)
+	registered := launched.Add(70 * time.Second)
+	ready := launched.Add(95 * time.Second)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "node1",
+			CreationTimestamp: metav1.NewTime(registered),
+		},
+	}
+
+	grid := []struct {
+		name        string
+		annotations map[string]string
+		conditions  []corev1.NodeCondition
+		launchTime  time.Time
+		now         time.Time
+		expected    map[string]string
+	}{
+		{
+			name:       "registered",
+			launchTime: launched,
+			now:        registered,
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(registered)},
+			},
+			expected: map[string]string{
+				"boot-milestones.kops.k8s.io/instance-launched":  "2021-07-01T10:00:00Z",
+				"boot-milestones.kops.k8s.io/kubelet-registered": "2021-07-01T10:01:10Z",
+			},
+		},
+		{
+			name:       "ready",
+			launchTime: launched,
+			now:        ready,
+			annotations: map[string]string{
+				"boot-milestones.kops.k8s.io/instance-launched":  "2021-07-01T10:00:00Z",
+				"boot-milestones.kops.k8s.io/kubelet-registered": "2021-07-01T10:01:10Z",
+			},
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(ready)},
+			},
+			expected: map[string]string{
+				"boot-milestones.kops.k8s.io/node-ready": "2021-07-01T10:01:35Z",
+			},
+		},
+		{
+			name: "ready again after a restart",
+			now:  ready.Add(10 * time.Minute),
+			annotations: map[string]string{
+				"boot-milestones.kops.k8s.io/instance-launched":  "2021-07-01T10:00:00Z",
+				"boot-milestones.kops.k8s.io/kubelet-registered": "2021-07-01T10:01:10Z",
+				"boot-milestones.kops.k8s.io/node-ready":         "2021-07-01T10:01:35Z",
+			},
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(ready.Add(10 * time.Minute))},
+			},
+			expected: map[string]string{},
+		},
+		{
+			name:       "unknown launch time",
+			now:        registered,
+			conditions: nil,
+			expected: map[string]string{
+				"boot-milestones.kops.k8s.io/kubelet-registered": "2021-07-01T10:01:10Z",
+			},
+		},
+		{
+			name:       "old node",
+			launchTime: launched,
+			now:        registered.Add(2 * time.Hour),
+			expected:   nil,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			n := node.DeepCopy()
+			n.Annotations = g.annotations
+			n.Status.Conditions = g.conditions
+			actual := Missing(n, g.launchTime, g.now)
+			if !reflect.DeepEqual(actual, g.expected) {
+				t.Errorf("unexpected annotations %v, expected %v", actual, g.expected)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"boot-milestones.kops.k8s.io/instance-launched": "2021-07-01T10:00:00Z",
+				"boot-milestones.kops.k8s.io/node-ready":        "not a time",
+			},
+		},
+	}
+
+	if actual, found := Get(node, "instance-launched"); !found || !actual.Equal(time.Date(2021, 7, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected launch time %v (found %v)", actual, found)
+	}
+	if _, found := Get(node, "node-ready"); found {
+		t.Errorf("expected an invalid time not to be found")
+	}
+	if _, found := Get(node, "kubelet-registered"); found {
+		t.Errorf("expected a missing milestone not to be found")
+	}
+}
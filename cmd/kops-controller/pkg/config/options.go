@@ -16,13 +16,53 @@ limitations under the License.
 
 package config
 
-import "k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
 
 type Options struct {
 	Cloud                 string         `json:"cloud,omitempty"`
 	ConfigBase            string         `json:"configBase,omitempty"`
 	Server                *ServerOptions `json:"server,omitempty"`
 	CacheNodeidentityInfo bool           `json:"cacheNodeidentityInfo,omitempty"`
+
+	// Validation enables periodic validation of the cluster.
+	Validation *ValidationOptions `json:"validation,omitempty"`
+
+	// Remediation enables the remediation of nodes reporting problem conditions.
+	Remediation *RemediationOptions `json:"remediation,omitempty"`
+
+	// OSPatching enables the periodic update of the operating system packages of the nodes.
+	OSPatching *OSPatchingOptions `json:"osPatching,omitempty"`
+
+	// MaintenanceWindow restricts the patching and recycling of nodes to a recurring window.
+	MaintenanceWindow *MaintenanceWindowOptions `json:"maintenanceWindow,omitempty"`
+
+	// NodeLabelReconciliation enables the reconciliation of the node labels and taints of the instance groups
+	// with the Node role, so that changing them does not replace the nodes.
+	NodeLabelReconciliation *NodeLabelReconciliationOptions `json:"nodeLabelReconciliation,omitempty"`
+
+	// WebhookCertificates enables the issuing of admission webhook serving certificates from the cluster CA.
+	WebhookCertificates *WebhookCertificatesOptions `json:"webhookCertificates,omitempty"`
+
+	// ApproveKubeletServingCertificates enables the approval of the kubelet serving certificate requests
+	// whose names and addresses match the instance of the requesting node.
+	ApproveKubeletServingCertificates bool `json:"approveKubeletServingCertificates,omitempty"`
+
+	// EgressGateways are the egress gateways whose Elastic IPs are associated with the gateway nodes,
+	// and whose traffic is routed by Cilium egress NAT policies.
+	EgressGateways []EgressGatewayOptions `json:"egressGateways,omitempty"`
+
+	// ScaleInProtection enables the removal of the scale in protection of the instances of cordoned nodes.
+	ScaleInProtection *ScaleInProtectionOptions `json:"scaleInProtection,omitempty"`
+
+	// EtcdBackupReplication enables the copy of the etcd backups to replica stores.
+	EtcdBackupReplication *EtcdBackupReplicationOptions `json:"etcdBackupReplication,omitempty"`
+
+	// HybridNodes enables the bootstrap and identification of the machines outside the cloud provider
+	// that are enrolled into instance groups with the Hybrid role.
+	HybridNodes *HybridNodesOptions `json:"hybridNodes,omitempty"`
 }
 
 func (o *Options) PopulateDefaults() {
@@ -51,3 +91,111 @@ type ServerOptions struct {
 type ServerProviderOptions struct {
 	AWS *awsup.AWSVerifierOptions `json:"aws,omitempty"`
 }
+
+type ValidationOptions struct {
+	// Interval is the time between validations.
+	Interval metav1.Duration `json:"interval"`
+	// Checks are the names of the additional validation checks to run, or "all".
+	Checks []string `json:"checks,omitempty"`
+	// MetricsAddress is the network endpoint (ip and port) on which the validation metrics are served.
+	MetricsAddress string `json:"metricsAddress,omitempty"`
+}
+
+type RemediationOptions struct {
+	// Conditions are the types of node conditions that trigger remediation while true.
+	Conditions []string `json:"conditions"`
+	// Threshold is how long a condition must have been true before the node is remediated.
+	Threshold metav1.Duration `json:"threshold"`
+	// MaxConcurrent is the maximum number of nodes being remediated at the same time.
+	MaxConcurrent int `json:"maxConcurrent"`
+	// Recycle drains cordoned nodes and deletes their instances through the cloud provider.
+	Recycle bool `json:"recycle,omitempty"`
+}
+
+type OSPatchingOptions struct {
+	// Interval is the time between updates of the packages of each node.
+	Interval metav1.Duration `json:"interval"`
+	// Method is how commands are run on the nodes, Pod or SSM.
+	Method string `json:"method"`
+	// Reboot reboots the nodes after their packages are updated.
+	Reboot bool `json:"reboot,omitempty"`
+	// InstanceGroups are the names of the instance groups to patch, or all but the control plane if empty.
+	InstanceGroups []string `json:"instanceGroups,omitempty"`
+}
+
+type MaintenanceWindowOptions struct {
+	// Schedule is the cron schedule, in UTC, on which the window opens.
+	Schedule string `json:"schedule"`
+	// Duration is how long the window stays open.
+	Duration metav1.Duration `json:"duration"`
+}
+
+type NodeLabelReconciliationOptions struct {
+	// Interval is the time between reconciliations of each node, and how long the instance groups are cached.
+	Interval metav1.Duration `json:"interval"`
+}
+
+type WebhookCertificatesOptions struct {
+	// CABasePath is a base of the path to the CA certificate and key files.
+	CABasePath string `json:"caBasePath"`
+	// Signer is the name of the CA that signs the certificates.
+	Signer string `json:"signer"`
+	// Certificates are the webhook serving certificates to issue.
+	Certificates []WebhookCertificateOptions `json:"certificates"`
+}
+
+type WebhookCertificateOptions struct {
+	// Namespace is the namespace of the webhook Service, Secret and Deployment.
+	Namespace string `json:"namespace"`
+	// Service is the name of the Service in front of the webhook. The certificate is valid for its DNS names.
+	Service string `json:"service"`
+	// Secret is the name of the Secret the certificate is stored in.
+	Secret string `json:"secret"`
+	// CertificateKey is the key of the certificate in the Secret.
+	CertificateKey string `json:"certificateKey"`
+	// PrivateKeyKey is the key of the private key in the Secret.
+	PrivateKeyKey string `json:"privateKeyKey"`
+	// CAKey is the key of the CA certificate in the Secret.
+	CAKey string `json:"caKey"`
+	// Validity is how long the certificate is valid. It is renewed after two thirds of its validity.
+	Validity metav1.Duration `json:"validity"`
+	// Deployment is restarted when the certificate is renewed, so that the webhook serves the new certificate.
+	Deployment string `json:"deployment,omitempty"`
+}
+
+type EgressGatewayOptions struct {
+	// Name is the name of the egress gateway.
+	Name string `json:"name"`
+	// InstanceGroup is the instance group of the gateway node.
+	InstanceGroup string `json:"instanceGroup"`
+	// ElasticIP is the Name tag of the Elastic IP of the gateway.
+	ElasticIP string `json:"elasticIP"`
+	// Namespaces are the namespaces whose pods send their traffic through the gateway.
+	Namespaces []string `json:"namespaces"`
+	// DestinationCIDRs are the destinations of the traffic sent through the gateway.
+	DestinationCIDRs []string `json:"destinationCIDRs"`
+}
+
+type ScaleInProtectionOptions struct {
+	// InstanceGroups are the names of the instance groups whose instances are protected from scale in until their node is cordoned.
+	InstanceGroups []string `json:"instanceGroups"`
+}
+
+type EtcdBackupReplicationOptions struct {
+	// Interval is the time between copies of the new backups.
+	Interval metav1.Duration `json:"interval"`
+	// Stores are the backup stores whose backups are copied.
+	Stores []EtcdBackupReplicaOptions `json:"stores"`
+}
+
+type EtcdBackupReplicaOptions struct {
+	// Source is the VFS path of the backup store of an etcd cluster.
+	Source string `json:"source"`
+	// Destination is the VFS path the backups are copied to.
+	Destination string `json:"destination"`
+}
+
+type HybridNodesOptions struct {
+	// InstanceGroups are the names of the instance groups with the Hybrid role.
+	InstanceGroups []string `json:"instanceGroups"`
+}
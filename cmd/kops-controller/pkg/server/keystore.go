@@ -43,7 +43,8 @@ func (k keystore) FindPrimaryKeypair(name string) (*pki.Certificate, *pki.Privat
 	return entry.certificate, entry.key, nil
 }
 
-func newKeystore(basePath string, cas []string) (pki.Keystore, error) {
+// NewKeystore loads the certificates and private keys of the named CAs from the files in basePath.
+func NewKeystore(basePath string, cas []string) (pki.Keystore, error) {
 	keystore := &keystore{
 		keys: map[string]keystoreEntry{},
 	}
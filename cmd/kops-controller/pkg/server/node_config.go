@@ -52,7 +52,11 @@ func (s *Server) getNodeConfig(ctx context.Context, req *nodeup.BootstrapRequest
 	}
 
 	{
-		p := s.configBase.Join("igconfig", "node", instanceGroupName, "nodeupconfig.yaml")
+		role := "node"
+		if s.isHybridInstanceGroup(instanceGroupName) {
+			role = "hybrid"
+		}
+		p := s.configBase.Join("igconfig", role, instanceGroupName, "nodeupconfig.yaml")
 
 		b, err := p.ReadFile()
 		if err != nil {
@@ -63,3 +67,16 @@ func (s *Server) getNodeConfig(ctx context.Context, req *nodeup.BootstrapRequest
 
 	return nodeConfig, nil
 }
+
+// isHybridInstanceGroup returns true if the instance group holds machines outside the cloud provider.
+func (s *Server) isHybridInstanceGroup(name string) bool {
+	if s.opt.HybridNodes == nil {
+		return false
+	}
+	for _, ig := range s.opt.HybridNodes.InstanceGroups {
+		if ig == name {
+			return true
+		}
+	}
+	return false
+}
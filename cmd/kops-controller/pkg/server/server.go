@@ -29,8 +29,13 @@ import (
 	"runtime/debug"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops-controller/pkg/bootmilestones"
 	"k8s.io/kops/cmd/kops-controller/pkg/config"
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/pki"
@@ -46,11 +51,14 @@ type Server struct {
 	verifier  fi.Verifier
 	keystore  pki.Keystore
 
+	// coreV1Client is a client-go client for annotating nodes
+	coreV1Client corev1client.CoreV1Interface
+
 	// configBase is the base of the configuration storage.
 	configBase vfs.Path
 }
 
-func NewServer(opt *config.Options, verifier fi.Verifier) (*Server, error) {
+func NewServer(opt *config.Options, verifier fi.Verifier, coreV1Client corev1client.CoreV1Interface) (*Server, error) {
 	server := &http.Server{
 		Addr: opt.Server.Listen,
 		TLSConfig: &tls.Config{
@@ -64,6 +72,8 @@ func NewServer(opt *config.Options, verifier fi.Verifier) (*Server, error) {
 		certNames: sets.NewString(opt.Server.CertNames...),
 		server:    server,
 		verifier:  verifier,
+
+		coreV1Client: coreV1Client,
 	}
 
 	configBase, err := vfs.Context.BuildVfsPath(opt.ConfigBase)
@@ -74,6 +84,7 @@ func NewServer(opt *config.Options, verifier fi.Verifier) (*Server, error) {
 
 	r := http.NewServeMux()
 	r.Handle("/bootstrap", http.HandlerFunc(s.bootstrap))
+	r.Handle("/boot-milestones", http.HandlerFunc(s.bootMilestones))
 	server.Handler = recovery(r)
 
 	return s, nil
@@ -81,7 +92,7 @@ func NewServer(opt *config.Options, verifier fi.Verifier) (*Server, error) {
 
 func (s *Server) Start() error {
 	var err error
-	s.keystore, err = newKeystore(s.opt.Server.CABasePath, s.opt.Server.SigningCAs)
+	s.keystore, err = NewKeystore(s.opt.Server.CABasePath, s.opt.Server.SigningCAs)
 	if err != nil {
 		return err
 	}
@@ -167,6 +178,99 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	klog.Infof("bootstrap %s %s success", r.RemoteAddr, id.NodeName)
 }
 
+// bootMilestones records the boot milestones reported by nodeup as annotations on the node.
+func (s *Server) bootMilestones(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		klog.Infof("boot-milestones %s no body", r.RemoteAddr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		klog.Infof("boot-milestones %s read err: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(fmt.Sprintf("boot-milestones %s failed to read body: %v", r.RemoteAddr, err)))
+		return
+	}
+
+	id, err := s.verifier.VerifyToken(r.Header.Get("Authorization"), body)
+	if err != nil {
+		klog.Infof("boot-milestones %s verify err: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(fmt.Sprintf("failed to verify token: %v", err)))
+		return
+	}
+
+	req := &nodeup.BootMilestonesRequest{}
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		klog.Infof("boot-milestones %s decode err: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(fmt.Sprintf("failed to decode: %v", err)))
+		return
+	}
+
+	if req.APIVersion != nodeup.BootstrapAPIVersion {
+		klog.Infof("boot-milestones %s wrong APIVersion", r.RemoteAddr)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("unexpected APIVersion"))
+		return
+	}
+
+	node, err := s.coreV1Client.Nodes().Get(r.Context(), id.NodeName, metav1.GetOptions{})
+	if err != nil {
+		klog.Infof("boot-milestones %s get node %q err: %v", r.RemoteAddr, id.NodeName, err)
+		if apierrors.IsNotFound(err) {
+			// The kubelet has not registered the node yet; nodeup will try again.
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("node not registered yet"))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Only the milestones that only nodeup can observe are accepted, and they are only recorded once.
+	setAnnotations := map[string]string{}
+	for milestone, t := range req.Milestones {
+		if milestone != nodeup.BootMilestoneContainerRuntimeReady {
+			klog.Infof("boot-milestones %s ignoring unexpected milestone %q", r.RemoteAddr, milestone)
+			continue
+		}
+		if _, found := node.Annotations[bootmilestones.Annotation(milestone)]; !found {
+			setAnnotations[bootmilestones.Annotation(milestone)] = bootmilestones.Format(t)
+		}
+	}
+
+	if len(setAnnotations) != 0 {
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": setAnnotations,
+			},
+		})
+		if err != nil {
+			klog.Infof("boot-milestones %s build patch err: %v", r.RemoteAddr, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		patched, err := s.coreV1Client.Nodes().Patch(r.Context(), node.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			klog.Infof("boot-milestones %s patch node %q err: %v", r.RemoteAddr, node.Name, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		for milestone, t := range req.Milestones {
+			if _, found := setAnnotations[bootmilestones.Annotation(milestone)]; found {
+				bootmilestones.Observe(patched, milestone, t)
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	klog.Infof("boot-milestones %s %s success", r.RemoteAddr, id.NodeName)
+}
+
 func (s *Server) issueCert(name string, pubKey string, id *fi.VerifyResult, validHours uint32) (string, error) {
 	block, _ := pem.Decode([]byte(pubKey))
 	if block.Type != "RSA PUBLIC KEY" {
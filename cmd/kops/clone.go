@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	cloneLong = templates.LongDesc(i18n.T(`Create a resource as a copy of an existing one.
+
+        kops clone does not update the cloud resources; to apply the changes use "kops update cluster".
+    `))
+
+	cloneExample = templates.Examples(i18n.T(`
+	# Clone the nodes-us-east-1a instance group into a new availability zone
+	kops clone instancegroup --name k8s-cluster.example.com nodes-us-east-1a nodes-us-east-1d --subnets us-east-1d
+	`))
+)
+
+func NewCmdClone(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "clone",
+		Short:   i18n.T("Create resources as copies of existing ones."),
+		Long:    cloneLong,
+		Example: cloneExample,
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdCloneInstanceGroup(f, out))
+
+	return cmd
+}
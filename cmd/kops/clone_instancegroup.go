@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops/validation"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type CloneInstanceGroupOptions struct {
+	ClusterName             string
+	SourceInstanceGroupName string
+	commands.CloneInstanceGroupOptions
+	// DryRun mode output an ig manifest of Output type.
+	DryRun bool
+	// Output type during a DryRun
+	Output string
+}
+
+var (
+	cloneInstanceGroupLong = templates.LongDesc(i18n.T(`
+		Create an instance group as a copy of an existing instance group.
+
+		The copy has the same spec and labels as the source instance group. Its subnets
+		and zones can be replaced, for example to spread a workload to another availability
+		zone. Control plane instance groups cannot be cloned, as etcd has to be configured
+		for each of them.`))
+
+	cloneInstanceGroupExample = templates.Examples(i18n.T(`
+		# Clone the nodes-us-east-1a instance group into a new availability zone
+		kops clone instancegroup --name k8s-cluster.example.com nodes-us-east-1a nodes-us-east-1d \
+		  --subnets us-east-1d
+
+		# Create a YAML manifest for the copy of an instance group
+		kops clone instancegroup --name k8s-cluster.example.com nodes nodes-batch --dry-run -oyaml
+		`))
+
+	cloneInstanceGroupShort = i18n.T(`Create an instancegroup as a copy of an existing one.`)
+)
+
+// NewCmdCloneInstanceGroup creates a new cobra command object for cloning an instancegroup.
+func NewCmdCloneInstanceGroup(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &CloneInstanceGroupOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "instancegroup SOURCE_INSTANCE_GROUP INSTANCE_GROUP",
+		Aliases: []string{"instancegroups", "ig"},
+		Short:   cloneInstanceGroupShort,
+		Long:    cloneInstanceGroupLong,
+		Example: cloneInstanceGroupExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			if len(args) != 2 {
+				return fmt.Errorf("must specify the names of the source instance group and of the instance group to create")
+			}
+
+			options.SourceInstanceGroupName = args[0]
+			options.Name = args[1]
+
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeInstanceGroup(nil, nil)(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunCloneInstanceGroup(context.TODO(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.Subnets, "subnets", options.Subnets, "Subnets of the new instance group (defaults to the subnets of the source instance group)")
+	cmd.Flags().StringSliceVar(&options.Zones, "zones", options.Zones, "Zones of the new instance group (defaults to the zones of the source instance group)")
+	cmd.Flags().BoolVar(&options.DryRun, "dry-run", options.DryRun, "Only print the object that would be created, without creating it. This flag can be used to create an instance group YAML or JSON manifest.")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of json or yaml")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func RunCloneInstanceGroup(ctx context.Context, f *util.Factory, out io.Writer, options *CloneInstanceGroupOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return fmt.Errorf("error getting cluster: %q: %v", options.ClusterName, err)
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	source, err := clientset.InstanceGroupsFor(cluster).Get(ctx, options.SourceInstanceGroupName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("instance group %q not found", options.SourceInstanceGroupName)
+		}
+		return err
+	}
+
+	existing, err := clientset.InstanceGroupsFor(cluster).Get(ctx, options.Name, metav1.GetOptions{})
+	if err != nil {
+		// We expect a NotFound error when creating the instance group
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	if existing != nil {
+		return fmt.Errorf("instance group %q already exists", options.Name)
+	}
+
+	ig, err := commands.CloneInstanceGroup(source, &options.CloneInstanceGroupOptions)
+	if err != nil {
+		return err
+	}
+
+	if options.DryRun {
+		switch options.Output {
+		case "":
+			return fmt.Errorf("must set output flag; yaml or json")
+		case OutputYaml:
+			if err := fullOutputYAML(out, ig); err != nil {
+				return fmt.Errorf("error writing instance group yaml to stdout: %v", err)
+			}
+			return nil
+		case OutputJSON:
+			if err := fullOutputJSON(out, ig); err != nil {
+				return fmt.Errorf("error writing instance group json to stdout: %v", err)
+			}
+			return nil
+		default:
+			return fmt.Errorf("unsupported output type %q", options.Output)
+		}
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+	err = validation.CrossValidateInstanceGroup(cluster.InstanceGroupWithDefaults(ig), cluster, cloud).ToAggregate()
+	if err != nil {
+		return err
+	}
+
+	_, err = clientset.InstanceGroupsFor(cluster).Create(ctx, ig, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("error storing InstanceGroup: %v", err)
+	}
+
+	fmt.Fprintf(out, "Created instance group %q as a copy of %q\n", ig.ObjectMeta.Name, source.ObjectMeta.Name)
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/featureflag"
+	"k8s.io/kops/pkg/testutils"
+)
+
+// TestCloneAndSetInstanceGroups clones an instance group, then changes both the source and the clone with a selector.
+func TestCloneAndSetInstanceGroups(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	i := newIntegrationTest("minimal.example.com", "minimal")
+	i.srcDir = updateClusterTestBase + i.srcDir
+
+	ctx := context.Background()
+	factory := i.setupCluster(t, "in-"+i.version+".yaml", ctx, bytes.Buffer{})
+
+	var stdout bytes.Buffer
+	cloneOptions := &CloneInstanceGroupOptions{
+		ClusterName:             i.clusterName,
+		SourceInstanceGroupName: "nodes",
+	}
+	cloneOptions.Name = "nodes-b"
+	cloneOptions.Subnets = []string{"us-test-1a"}
+	if err := RunCloneInstanceGroup(ctx, factory, &stdout, cloneOptions); err != nil {
+		t.Fatalf("error cloning instance group: %v", err)
+	}
+	if err := RunCloneInstanceGroup(ctx, factory, &stdout, cloneOptions); err == nil || err.Error() != `instance group "nodes-b" already exists` {
+		t.Errorf("expected an error cloning to an existing instance group, got %v", err)
+	}
+
+	featureflag.ParseFlags("+SpecOverrideFlag")
+	defer featureflag.ParseFlags("-SpecOverrideFlag")
+
+	stdout.Reset()
+	setOptions := &commands.SetInstanceGroupOptions{
+		ClusterName: i.clusterName,
+		Selector:    "role=Node",
+		Fields:      []string{"spec.machineType=m5.large"},
+	}
+	if err := commands.RunSetInstancegroup(ctx, factory, nil, &stdout, setOptions); err != nil {
+		t.Fatalf("error setting instance groups: %v", err)
+	}
+	if expected := "Updated instance group \"nodes\"\nUpdated instance group \"nodes-b\"\n"; stdout.String() != expected {
+		t.Errorf("unexpected output: expected %q, got %q", expected, stdout.String())
+	}
+
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error getting clientset: %v", err)
+	}
+	cluster, err := clientset.GetCluster(ctx, i.clusterName)
+	if err != nil {
+		t.Fatalf("error getting cluster: %v", err)
+	}
+	for name, expected := range map[string]string{
+		"master-us-test-1a": "m3.medium",
+		"nodes":             "m5.large",
+		"nodes-b":           "m5.large",
+	} {
+		ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("error getting instance group %q: %v", name, err)
+		}
+		if ig.Spec.MachineType != expected {
+			t.Errorf("expected machine type %q for instance group %q, got %q", expected, name, ig.Spec.MachineType)
+		}
+	}
+}
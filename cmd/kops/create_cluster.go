@@ -46,10 +46,12 @@ import (
 	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/featureflag"
+	"k8s.io/kops/pkg/ipam"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -75,6 +77,15 @@ type CreateClusterOptions struct {
 	MasterSecurityGroups []string
 	AssociatePublicIP    *bool
 
+	// IPAMProvider is the external IPAM the network CIDR is allocated from: AWS, Infoblox or Netbox
+	IPAMProvider string
+	// IPAMEndpoint is the URL of the Infoblox WAPI or of the Netbox API
+	IPAMEndpoint string
+	// IPAMPool is the AWS VPC IPAM pool, Infoblox network container or Netbox prefix the network CIDR is allocated from
+	IPAMPool string
+	// IPAMPrefixLength is the prefix length of the network CIDR allocated from the IPAM
+	IPAMPrefixLength int
+
 	// SSHPublicKeys is a map of the SSH public keys we should configure; required on AWS, not required on GCE
 	SSHPublicKeys map[string][]byte
 
@@ -102,6 +113,11 @@ type CreateClusterOptions struct {
 
 	// AddonPaths specify paths to additional components that we can add to a cluster
 	AddonPaths []string
+
+	// Preset is the name of a set of defaults for topology, instance groups, networking and addons
+	Preset string
+	// Interactive prompts for the cluster settings that were not set by flags
+	Interactive bool
 }
 
 func (o *CreateClusterOptions) InitDefaults() {
@@ -155,6 +171,16 @@ var (
 		--node-count 3 \
 		--yes
 
+	# Create a highly available private cluster in AWS from a preset.
+	kops create cluster k8s-cluster.example.com \
+		--state=s3://my-state-store \
+		--zones=us-east-1a,us-east-1b,us-east-1c \
+		--preset=prod-ha \
+		--yes
+
+	# Answer a few questions, and get a cluster spec to review and apply later.
+	kops create cluster --interactive > filename.yaml
+
 	# Generate a cluster spec to apply later.
 	# Run the following, then: kops create -f filename.yaml
 	kops create cluster --name=k8s-cluster.example.com \
@@ -181,13 +207,33 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		Short:   createClusterShort,
 		Long:    createClusterLong,
 		Example: createClusterExample,
-		Args:    rootCommand.clusterNameArgsNoKubeconfig(&options.ClusterName),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if options.Interactive {
+				// The wizard prompts for the cluster name if it was not given.
+				if err := rootCommand.ProcessArgs(args); err != nil {
+					return err
+				}
+				options.ClusterName = rootCommand.clusterName
+				return nil
+			}
+			return rootCommand.clusterNameArgsNoKubeconfig(&options.ClusterName)(cmd, args)
+		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 
+			if options.Interactive {
+				if err := runCreateClusterWizard(cmd.Flags(), os.Stdin, os.Stderr, options); err != nil {
+					return err
+				}
+			} else if options.Preset != "" {
+				if err := applyClusterPreset(cmd.Flags(), options.Preset); err != nil {
+					return err
+				}
+			}
+
 			if cmd.Flag("associate-public-ip").Changed {
 				options.AssociatePublicIP = &associatePublicIP
 			}
@@ -208,6 +254,12 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to immediately create the cluster")
+	cmd.Flags().StringVar(&options.Preset, "preset", options.Preset, "Preset defaults for topology, instance groups, networking and addons: "+strings.Join(clusterPresetNames(), ", ")+". Flags that are set explicitly take precedence.")
+	cmd.RegisterFlagCompletionFunc("preset", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return clusterPresetNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().BoolVar(&options.Interactive, "interactive", options.Interactive, "Prompt for the cluster settings not set by flags, then output the cluster manifest as YAML unless --yes is specified")
+
 	cmd.Flags().StringVar(&options.Target, "target", options.Target, fmt.Sprintf("Valid targets: %s, %s, %s. Set this flag to %s if you want kOps to generate terraform", cloudup.TargetDirect, cloudup.TargetTerraform, cloudup.TargetCloudformation, cloudup.TargetTerraform))
 	cmd.RegisterFlagCompletionFunc("target", completeTarget)
 
@@ -276,6 +328,13 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.RegisterFlagCompletionFunc("network-cidr", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	})
+	cmd.Flags().StringVar(&options.IPAMProvider, "ipam-provider", options.IPAMProvider, "External IPAM to allocate the network CIDR from: AWS, Infoblox or Netbox")
+	cmd.RegisterFlagCompletionFunc("ipam-provider", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{api.NetworkCIDRAllocationProviderAWS, api.NetworkCIDRAllocationProviderInfoblox, api.NetworkCIDRAllocationProviderNetbox}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.IPAMEndpoint, "ipam-endpoint", options.IPAMEndpoint, "URL of the Infoblox WAPI or of the Netbox API")
+	cmd.Flags().StringVar(&options.IPAMPool, "ipam-pool", options.IPAMPool, "AWS VPC IPAM pool ID, Infoblox network container or Netbox prefix ID to allocate the network CIDR from")
+	cmd.Flags().IntVar(&options.IPAMPrefixLength, "ipam-prefix-length", 16, "Prefix length of the network CIDR allocated from the IPAM")
 	cmd.Flags().BoolVar(&options.DisableSubnetTags, "disable-subnet-tags", options.DisableSubnetTags, "Disable automatic subnet tagging")
 
 	cmd.Flags().BoolVar(&encryptEtcdStorage, "encrypt-etcd-storage", false, "Generate key in AWS KMS and use it for encrypt etcd volumes")
@@ -333,6 +392,9 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 
 	// Bastion
 	cmd.Flags().BoolVar(&options.Bastion, "bastion", options.Bastion, "Enable a bastion instance group. Only applies to private topology.")
+	cmd.Flags().Int32Var(&options.BastionMinSize, "bastion-min-size", options.BastionMinSize, "Minimum number of bastion instances. Defaults to one bastion")
+	cmd.Flags().Int32Var(&options.BastionMaxSize, "bastion-max-size", options.BastionMaxSize, "Maximum number of bastion instances. Defaults to one bastion")
+	cmd.Flags().BoolVar(&options.SessionManager, "session-manager", options.SessionManager, "Allow access to the instances with AWS Systems Manager Session Manager instead of a bastion.")
 
 	// Allow custom tags from the CLI
 	cmd.Flags().StringVar(&options.CloudLabels, "cloud-labels", options.CloudLabels, "A list of key/value pairs used to tag all instance groups (for example \"Owner=John Doe,Team=Some Team\").")
@@ -400,6 +462,13 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		cmd.RegisterFlagCompletionFunc("azure-admin-user", completeAzureAdminUsers)
 	}
 
+	if featureflag.OCI.Enabled() {
+		cmd.Flags().StringVar(&options.OCICompartmentID, "oci-compartment-id", options.OCICompartmentID, "OCID of the OCI compartment where the cluster is created")
+		cmd.RegisterFlagCompletionFunc("oci-compartment-id", completeOCICompartmentID)
+		cmd.Flags().StringVar(&options.OCITagNamespace, "oci-tag-namespace", options.OCITagNamespace, "Name of an existing OCI defined tag namespace with a kops-cluster tag key, used to identify the instances of the cluster")
+		cmd.RegisterFlagCompletionFunc("oci-tag-namespace", completeOCITagNamespace)
+	}
+
 	if featureflag.Spotinst.Enabled() {
 		// Spotinst flags
 		cmd.Flags().StringVar(&options.SpotinstProduct, "spotinst-product", options.SpotinstProduct, "Product description (valid values: Linux/UNIX, Linux/UNIX (Amazon VPC), Windows and Windows (Amazon VPC))")
@@ -456,6 +525,18 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 		return fmt.Errorf("unable to execute --dry-run without setting --output")
 	}
 
+	if c.IPAMProvider != "" {
+		if c.IPAMPool == "" {
+			return fmt.Errorf("--ipam-pool is required with --ipam-provider")
+		}
+		if c.NetworkCIDR != "" || c.NetworkID != "" {
+			return fmt.Errorf("--ipam-provider cannot be used with --network-cidr or --vpc")
+		}
+		if c.DryRun {
+			return fmt.Errorf("--ipam-provider cannot be used with --dry-run, which would allocate a network CIDR that is never released")
+		}
+	}
+
 	// TODO: Reuse rootCommand stateStore logic?
 
 	if c.OutDir == "" {
@@ -504,6 +585,16 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 	cluster := clusterResult.Cluster
 	instanceGroups := clusterResult.InstanceGroups
 
+	if c.Preset != "" {
+		preset, err := findClusterPreset(c.Preset)
+		if err != nil {
+			return err
+		}
+		if preset.Configure != nil {
+			preset.Configure(cluster)
+		}
+	}
+
 	var masters []*api.InstanceGroup
 	var nodes []*api.InstanceGroup
 	for _, ig := range instanceGroups {
@@ -623,6 +714,35 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 		return err
 	}
 
+	clusterCreated := false
+	if c.IPAMProvider != "" {
+		region := ""
+		if c.IPAMProvider == api.NetworkCIDRAllocationProviderAWS {
+			region, err = awsup.FindRegion(cluster)
+			if err != nil {
+				return err
+			}
+		}
+		allocator, err := ipam.NewAllocator(c.IPAMProvider, c.IPAMEndpoint, region)
+		if err != nil {
+			return err
+		}
+		if err := ipam.AllocateNetworkCIDR(ctx, allocator, cluster, c.IPAMProvider, c.IPAMEndpoint, c.IPAMPool, c.IPAMPrefixLength); err != nil {
+			return err
+		}
+		klog.Infof("Allocated network CIDR %s from %s pool %q", cluster.Spec.NetworkCIDR, c.IPAMProvider, c.IPAMPool)
+
+		// Release the network CIDR if the cluster is not created
+		defer func() {
+			if clusterCreated {
+				return
+			}
+			if err := ipam.ReleaseNetworkCIDR(ctx, cluster, region); err != nil {
+				klog.Warningf("%v", err)
+			}
+		}()
+	}
+
 	err = cloudup.PerformAssignments(cluster, cloud)
 	if err != nil {
 		return fmt.Errorf("error populating configuration: %v", err)
@@ -698,6 +818,7 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 	if err != nil {
 		return fmt.Errorf("error writing updated configuration: %v", err)
 	}
+	clusterCreated = true
 
 	if len(c.SSHPublicKeys) == 0 {
 		autoloadSSHPublicKeys := true
@@ -1016,6 +1137,16 @@ func completeAzureAdminUsers(cmd *cobra.Command, args []string, toComplete strin
 	return nil, cobra.ShellCompDirectiveNoFileComp
 }
 
+func completeOCICompartmentID(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// TODO call into cloud provider to get list of compartment IDs
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeOCITagNamespace(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// TODO call into cloud provider to get list of tag namespaces
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
 func completeSpotinstProduct(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	// TODO call into cloud provider to get list of products
 	return nil, cobra.ShellCompDirectiveNoFileComp
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+)
+
+// clusterPrompt asks for the value of a create cluster flag, if the flag was not set explicitly.
+type clusterPrompt struct {
+	Flag     string
+	Question string
+	// Choices, if set, restricts the accepted answers.
+	Choices []string
+	// Required prompts again on an empty answer when the flag has no value.
+	Required bool
+}
+
+// runCreateClusterWizard prompts for the main cluster settings, applying the chosen preset before asking about
+// the settings it pre-populates. Unless --yes was given, the wizard emits the cluster manifest for review.
+func runCreateClusterWizard(flags *pflag.FlagSet, in io.Reader, out io.Writer, options *CreateClusterOptions) error {
+	reader := bufio.NewReader(in)
+
+	// Values from the preset can still be changed, so we only skip the flags the user set explicitly.
+	explicit := sets.NewString()
+	flags.Visit(func(flag *pflag.Flag) {
+		explicit.Insert(flag.Name)
+	})
+
+	fmt.Fprintf(out, "Press enter to accept the value shown in brackets.\n\n")
+
+	for options.ClusterName == "" {
+		answer, err := readAnswer(reader, out, "Cluster name", "")
+		if err != nil {
+			return err
+		}
+		options.ClusterName = answer
+	}
+
+	if err := askClusterPrompts(reader, out, flags, explicit, []clusterPrompt{
+		{Flag: "cloud", Question: "Cloud provider", Choices: cloudup.SupportedClouds()},
+		{Flag: "zones", Question: "Zones (comma separated)", Required: true},
+	}); err != nil {
+		return err
+	}
+
+	if !explicit.Has("preset") {
+		fmt.Fprintf(out, "\nPresets:\n")
+		for _, name := range clusterPresetNames() {
+			fmt.Fprintf(out, "  %-16s %s\n", name, clusterPresets[name].Description)
+		}
+		if err := askClusterPrompts(reader, out, flags, explicit, []clusterPrompt{
+			{Flag: "preset", Question: "Preset (empty for none)", Choices: append([]string{""}, clusterPresetNames()...)},
+		}); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\n")
+	}
+	if options.Preset != "" {
+		if err := applyClusterPreset(flags, options.Preset); err != nil {
+			return err
+		}
+	}
+
+	if err := askClusterPrompts(reader, out, flags, explicit, []clusterPrompt{
+		{Flag: "topology", Question: "Topology", Choices: []string{"public", "private"}},
+		{Flag: "networking", Question: "Networking"},
+		{Flag: "master-count", Question: "Number of masters (0 for one per master zone)"},
+		{Flag: "node-count", Question: "Number of nodes (0 for one per zone)"},
+		{Flag: "kubernetes-version", Question: "Kubernetes version (empty for the channel default)"},
+	}); err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		options.DryRun = true
+		if options.Output == "" {
+			options.Output = OutputYaml
+		}
+	}
+
+	return nil
+}
+
+func askClusterPrompts(reader *bufio.Reader, out io.Writer, flags *pflag.FlagSet, explicit sets.String, prompts []clusterPrompt) error {
+	for _, prompt := range prompts {
+		flag := flags.Lookup(prompt.Flag)
+		if flag == nil {
+			return fmt.Errorf("unknown flag %q", prompt.Flag)
+		}
+		if explicit.Has(prompt.Flag) {
+			continue
+		}
+
+		def := strings.Trim(flag.Value.String(), "[]")
+		question := prompt.Question
+		if len(prompt.Choices) != 0 {
+			question += " (" + strings.Trim(strings.Join(prompt.Choices, ", "), ", ") + ")"
+		}
+
+		for {
+			answer, err := readAnswer(reader, out, question, def)
+			if err != nil {
+				return err
+			}
+			if answer == "" {
+				if prompt.Required && def == "" {
+					continue
+				}
+				break
+			}
+			if len(prompt.Choices) != 0 && !containsString(prompt.Choices, answer) {
+				fmt.Fprintf(out, "%q is not one of: %s\n", answer, strings.Join(prompt.Choices, ", "))
+				continue
+			}
+			if err := flags.Set(prompt.Flag, answer); err != nil {
+				fmt.Fprintf(out, "invalid value: %v\n", err)
+				continue
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// readAnswer prints the question and reads a single line, returning it trimmed.
+func readAnswer(reader *bufio.Reader, out io.Writer, question string, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(out, "%s: ", question)
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("error reading from input: %v", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
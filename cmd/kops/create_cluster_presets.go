@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/pflag"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// clusterPreset is a named set of create cluster flag values, plus optional changes to the resulting cluster spec
+// for settings that have no flag (such as addons).
+type clusterPreset struct {
+	// Description is shown in the interactive wizard.
+	Description string
+	// Flags are applied in order, skipping any flag the user set explicitly.
+	Flags [][2]string
+	// Configure is called on the new cluster, before any --set overrides.
+	Configure func(cluster *api.Cluster)
+}
+
+var clusterPresets = map[string]clusterPreset{
+	"dev-small": {
+		Description: "a single master and node with public topology, for development and testing",
+		Flags: [][2]string{
+			{"topology", api.TopologyPublic},
+			{"networking", "kubenet"},
+			{"master-count", "1"},
+			{"node-count", "1"},
+		},
+	},
+	"prod-ha": {
+		Description: "three masters with private topology, calico, a bastion, metrics-server and cert-manager",
+		Flags: [][2]string{
+			{"topology", api.TopologyPrivate},
+			{"networking", "calico"},
+			{"master-count", "3"},
+			{"node-count", "3"},
+			{"bastion", "true"},
+		},
+		Configure: enableMetricsServer,
+	},
+	"private-gitops": {
+		Description: "like prod-ha with cilium and no bastion, emitting the manifest as YAML instead of creating the cluster",
+		Flags: [][2]string{
+			{"topology", api.TopologyPrivate},
+			{"networking", "cilium"},
+			{"master-count", "3"},
+			{"node-count", "3"},
+			{"dry-run", "true"},
+			{"output", OutputYaml},
+		},
+		Configure: enableMetricsServer,
+	},
+}
+
+// clusterPresetNames returns the names of the known presets, sorted.
+func clusterPresetNames() []string {
+	var names []string
+	for name := range clusterPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func findClusterPreset(name string) (*clusterPreset, error) {
+	preset, ok := clusterPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q; valid presets are: %v", name, clusterPresetNames())
+	}
+	return &preset, nil
+}
+
+// applyClusterPreset sets the preset's flag values, leaving alone any flag that has already been set.
+func applyClusterPreset(flags *pflag.FlagSet, name string) error {
+	preset, err := findClusterPreset(name)
+	if err != nil {
+		return err
+	}
+
+	for _, kv := range preset.Flags {
+		flag := flags.Lookup(kv[0])
+		if flag == nil {
+			return fmt.Errorf("preset %q sets unknown flag %q", name, kv[0])
+		}
+		if flag.Changed {
+			continue
+		}
+		if err := flags.Set(kv[0], kv[1]); err != nil {
+			return fmt.Errorf("preset %q: error setting --%s=%s: %v", name, kv[0], kv[1], err)
+		}
+	}
+
+	return nil
+}
+
+// enableMetricsServer enables a secure metrics-server, along with the cert-manager it depends on.
+func enableMetricsServer(cluster *api.Cluster) {
+	if cluster.Spec.CertManager == nil {
+		cluster.Spec.CertManager = &api.CertManagerConfig{}
+	}
+	cluster.Spec.CertManager.Enabled = fi.Bool(true)
+
+	if cluster.Spec.MetricsServer == nil {
+		cluster.Spec.MetricsServer = &api.MetricsServerConfig{}
+	}
+	cluster.Spec.MetricsServer.Enabled = fi.Bool(true)
+	cluster.Spec.MetricsServer.Insecure = fi.Bool(false)
+}
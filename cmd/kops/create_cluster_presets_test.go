@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// newPresetTestFlags registers the subset of create cluster flags used by the presets and the wizard.
+func newPresetTestFlags(options *CreateClusterOptions) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringVar(&options.CloudProvider, "cloud", options.CloudProvider, "")
+	flags.StringSliceVar(&options.Zones, "zones", options.Zones, "")
+	flags.StringVar(&options.Preset, "preset", options.Preset, "")
+	flags.StringVar(&options.Topology, "topology", options.Topology, "")
+	flags.StringVar(&options.Networking, "networking", options.Networking, "")
+	flags.Int32Var(&options.MasterCount, "master-count", options.MasterCount, "")
+	flags.Int32Var(&options.NodeCount, "node-count", options.NodeCount, "")
+	flags.StringVar(&options.KubernetesVersion, "kubernetes-version", options.KubernetesVersion, "")
+	flags.BoolVar(&options.Bastion, "bastion", options.Bastion, "")
+	flags.BoolVar(&options.DryRun, "dry-run", options.DryRun, "")
+	flags.StringVar(&options.Output, "output", options.Output, "")
+	return flags
+}
+
+func TestApplyClusterPreset(t *testing.T) {
+	options := &CreateClusterOptions{}
+	options.InitDefaults()
+	flags := newPresetTestFlags(options)
+
+	if err := flags.Parse([]string{"--networking=cilium"}); err != nil {
+		t.Fatalf("unexpected error parsing flags: %v", err)
+	}
+	if err := applyClusterPreset(flags, "prod-ha"); err != nil {
+		t.Fatalf("unexpected error applying preset: %v", err)
+	}
+
+	if options.Topology != api.TopologyPrivate {
+		t.Errorf("expected topology %q, got %q", api.TopologyPrivate, options.Topology)
+	}
+	if options.MasterCount != 3 {
+		t.Errorf("expected 3 masters, got %d", options.MasterCount)
+	}
+	if !options.Bastion {
+		t.Errorf("expected bastion to be enabled")
+	}
+	if options.Networking != "cilium" {
+		t.Errorf("expected explicitly set networking to be kept, got %q", options.Networking)
+	}
+
+	if err := applyClusterPreset(flags, "nonexistent"); err == nil {
+		t.Errorf("expected error for unknown preset")
+	}
+}
+
+func TestClusterPresetConfigure(t *testing.T) {
+	cluster := &api.Cluster{}
+	preset, err := findClusterPreset("prod-ha")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	preset.Configure(cluster)
+
+	if !fi.BoolValue(cluster.Spec.MetricsServer.Enabled) || fi.BoolValue(cluster.Spec.MetricsServer.Insecure) {
+		t.Errorf("expected secure metrics server to be enabled, got %+v", cluster.Spec.MetricsServer)
+	}
+	if !fi.BoolValue(cluster.Spec.CertManager.Enabled) {
+		t.Errorf("expected cert manager to be enabled")
+	}
+}
+
+func TestCreateClusterWizard(t *testing.T) {
+	options := &CreateClusterOptions{}
+	options.InitDefaults()
+	flags := newPresetTestFlags(options)
+
+	if err := flags.Parse([]string{"--cloud=aws"}); err != nil {
+		t.Fatalf("unexpected error parsing flags: %v", err)
+	}
+
+	input := strings.Join([]string{
+		"test.example.com",      // cluster name
+		"",                      // zones are required, so this is asked again
+		"us-test-1a,us-test-1b", // zones
+		"medium",                // not a preset, so this is asked again
+		"dev-small",             // preset
+		"",                      // topology, keeping the preset value
+		"",                      // networking, keeping the preset value
+		"",                      // master count, keeping the preset value
+		"2",                     // node count
+		"1.21.0",                // kubernetes version
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	if err := runCreateClusterWizard(flags, strings.NewReader(input), &out, options); err != nil {
+		t.Fatalf("unexpected error: %v\noutput: %s", err, out.String())
+	}
+
+	if options.ClusterName != "test.example.com" {
+		t.Errorf("unexpected cluster name %q", options.ClusterName)
+	}
+	if strings.Join(options.Zones, ",") != "us-test-1a,us-test-1b" {
+		t.Errorf("unexpected zones %v", options.Zones)
+	}
+	if options.Preset != "dev-small" {
+		t.Errorf("unexpected preset %q", options.Preset)
+	}
+	if options.Topology != api.TopologyPublic || options.Networking != "kubenet" || options.MasterCount != 1 {
+		t.Errorf("expected preset values, got topology=%q networking=%q masterCount=%d", options.Topology, options.Networking, options.MasterCount)
+	}
+	if options.NodeCount != 2 {
+		t.Errorf("expected 2 nodes, got %d", options.NodeCount)
+	}
+	if options.KubernetesVersion != "1.21.0" {
+		t.Errorf("unexpected kubernetes version %q", options.KubernetesVersion)
+	}
+	if !options.DryRun || options.Output != OutputYaml {
+		t.Errorf("expected the wizard to output YAML, got dryRun=%v output=%q", options.DryRun, options.Output)
+	}
+	if strings.Contains(out.String(), "Cloud provider") {
+		t.Errorf("did not expect a prompt for the explicitly set cloud provider")
+	}
+}
+
+func TestCreateClusterWizardEOF(t *testing.T) {
+	options := &CreateClusterOptions{}
+	options.InitDefaults()
+	flags := newPresetTestFlags(options)
+
+	err := runCreateClusterWizard(flags, strings.NewReader("test.example.com\n"), &bytes.Buffer{}, options)
+	if err == nil {
+		t.Errorf("expected error when the input ends before the required answers")
+	}
+}
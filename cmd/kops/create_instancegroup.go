@@ -128,6 +128,9 @@ func NewCmdCreateInstanceGroup(f *util.Factory, out io.Writer) *cobra.Command {
 		if r == kopsapi.InstanceGroupRoleAPIServer && !featureflag.APIServerNodes.Enabled() {
 			continue
 		}
+		if r == kopsapi.InstanceGroupRoleHybrid && !featureflag.HybridNodes.Enabled() {
+			continue
+		}
 		allRoles = append(allRoles, strings.ToLower(string(r)))
 	}
 
@@ -197,6 +200,7 @@ func RunCreateInstanceGroup(ctx context.Context, f *util.Factory, out io.Writer,
 	if err != nil {
 		return err
 	}
+	cluster.RemoveInheritedInstanceGroupFields(ig)
 
 	ig.AddInstanceGroupNodeLabel()
 	if kopsapi.CloudProviderID(cluster.Spec.CloudProvider) == kopsapi.CloudProviderGCE {
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	createSecretSSHCALong = templates.LongDesc(i18n.T(`
+	Add the public key of an SSH certificate authority to the state store.
+	The instances of the cluster trust user certificates signed by the certificate authority,
+	once they are updated.
+
+	More than one key can be added, for instance to rotate the certificate authority.
+	A key is removed with "kops delete secret sshpublickey sshca <id>".`))
+
+	createSecretSSHCAExample = templates.Examples(i18n.T(`
+	# Trust user certificates signed by a certificate authority
+	kops create secret sshca -i ~/.ssh/ca.pub \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
+	# Sign a short-lived certificate for a user of the instances
+	ssh-keygen -s ~/.ssh/ca -I alice -n ubuntu -V +1h ~/.ssh/id_rsa.pub
+	`))
+
+	createSecretSSHCAShort = i18n.T(`Add the public key of an SSH certificate authority.`)
+)
+
+type CreateSecretSSHCAOptions struct {
+	ClusterName   string
+	PublicKeyPath string
+}
+
+func NewCmdCreateSecretSSHCA(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &CreateSecretSSHCAOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "sshca",
+		Short:   createSecretSSHCAShort,
+		Long:    createSecretSSHCALong,
+		Example: createSecretSSHCAExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			err := rootCommand.ProcessArgs(args)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err = RunCreateSecretSSHCA(ctx, f, os.Stdout, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.PublicKeyPath, "pubkey", "i", "", "Path to the SSH public key of the certificate authority")
+
+	return cmd
+}
+
+func RunCreateSecretSSHCA(ctx context.Context, f *util.Factory, out io.Writer, options *CreateSecretSSHCAOptions) error {
+	if options.PublicKeyPath == "" {
+		return fmt.Errorf("public key path is required (use -i)")
+	}
+
+	data, err := ioutil.ReadFile(options.PublicKeyPath)
+	if err != nil {
+		return fmt.Errorf("error reading SSH public key %v: %v", options.PublicKeyPath, err)
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return fmt.Errorf("error parsing SSH public key %v: %v", options.PublicKeyPath, err)
+	}
+	if _, ok := publicKey.(*ssh.Certificate); ok {
+		return fmt.Errorf("%v is a certificate; specify the public key of the certificate authority", options.PublicKeyPath)
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	err = sshCredentialStore.AddSSHPublicKey(fi.SecretNameSSHCA, data)
+	if err != nil {
+		return fmt.Errorf("error adding SSH public key: %v", err)
+	}
+
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/ipam"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/resources"
 	resourceops "k8s.io/kops/pkg/resources/ops"
@@ -43,6 +44,7 @@ type DeleteClusterOptions struct {
 	Region      string
 	External    bool
 	Unregister  bool
+	Orphaned    bool
 	ClusterName string
 }
 
@@ -50,6 +52,12 @@ var (
 	deleteClusterLong = templates.LongDesc(i18n.T(`
 	Deletes a Kubernetes cluster and all associated resources.  Resources include instancegroups,
 	secrets, and the state store.  There is no "UNDO" for this command.
+
+	With --orphaned, only the cloud resources of the cluster that it no longer uses are deleted, and the
+	cluster is kept. These are launch template versions that are not current, unattached volumes and network
+	interfaces, and security groups tagged as owned by the cluster that nothing uses, such as those left
+	behind by the load balancers of deleted services. Volumes of etcd and of persistent volumes are never deleted. The resources are listed
+	with an estimate of what they cost per month. This is only supported on AWS.
 	`))
 
 	deleteClusterExample = templates.Examples(i18n.T(`
@@ -57,6 +65,11 @@ var (
 	# The --yes option runs the command immediately.
 	kops delete cluster --name=k8s.cluster.site --yes
 
+	# List the resources the cluster no longer uses, with their estimated cost.
+	kops delete cluster --name=k8s.cluster.site --orphaned
+
+	# Delete the resources the cluster no longer uses, keeping the cluster.
+	kops delete cluster --name=k8s.cluster.site --orphaned --yes
 	`))
 
 	deleteClusterShort = i18n.T("Delete a cluster.")
@@ -80,6 +93,7 @@ func NewCmdDeleteCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to delete the cluster")
 	cmd.Flags().BoolVar(&options.Unregister, "unregister", options.Unregister, "Don't delete cloud resources, just unregister the cluster")
 	cmd.Flags().BoolVar(&options.External, "external", options.External, "Delete an external cluster")
+	cmd.Flags().BoolVar(&options.Orphaned, "orphaned", options.Orphaned, "Only delete the cloud resources that the cluster no longer uses, keeping the cluster")
 
 	cmd.Flags().StringVar(&options.Region, "region", options.Region, "External cluster's cloud region")
 	cmd.RegisterFlagCompletionFunc("region", completeRegion)
@@ -93,6 +107,13 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 		return fmt.Errorf("--name is required (for safety)")
 	}
 
+	if options.Orphaned {
+		if options.External || options.Unregister {
+			return fmt.Errorf("--orphaned cannot be used with --external or --unregister")
+		}
+		return RunDeleteOrphanedResources(ctx, f, out, options)
+	}
+
 	var cloud fi.Cloud
 	var cluster *kopsapi.Cluster
 	var err error
@@ -186,6 +207,20 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 			}
 			return nil
 		}
+		if !options.Unregister && cluster.Spec.NetworkCIDRAllocation != nil {
+			region := ""
+			if cluster.Spec.NetworkCIDRAllocation.Provider == kopsapi.NetworkCIDRAllocationProviderAWS {
+				region, err = awsup.FindRegion(cluster)
+				if err != nil {
+					return err
+				}
+			}
+			if err := ipam.ReleaseNetworkCIDR(ctx, cluster, region); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Released network CIDR %s to %s\n", cluster.Spec.NetworkCIDR, cluster.Spec.NetworkCIDRAllocation.Provider)
+		}
+
 		clientset, err := f.Clientset()
 		if err != nil {
 			return err
@@ -207,6 +242,70 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 	return nil
 }
 
+// RunDeleteOrphanedResources deletes the cloud resources that the cluster no longer uses
+func RunDeleteOrphanedResources(ctx context.Context, f *util.Factory, out io.Writer, options *DeleteClusterOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	orphanedResources, err := resourceops.ListOrphanedResources(cloud, cluster)
+	if err != nil {
+		return err
+	}
+
+	if len(orphanedResources) == 0 {
+		fmt.Fprintf(out, "No orphaned cloud resources\n")
+		return nil
+	}
+
+	var l []*resources.Resource
+	var totalCost float64
+	for _, v := range orphanedResources {
+		l = append(l, v)
+		totalCost += v.EstimatedMonthlyCost
+	}
+
+	t := &tables.Table{}
+	t.AddColumn("TYPE", func(r *resources.Resource) string {
+		return r.Type
+	})
+	t.AddColumn("ID", func(r *resources.Resource) string {
+		return r.ID
+	})
+	t.AddColumn("NAME", func(r *resources.Resource) string {
+		return r.Name
+	})
+	t.AddColumn("MONTHLY COST", func(r *resources.Resource) string {
+		return fmt.Sprintf("$%.2f", r.EstimatedMonthlyCost)
+	})
+	if err := t.Render(l, out, "TYPE", "NAME", "ID", "MONTHLY COST"); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nEstimated monthly cost of orphaned resources: $%.2f\n", totalCost)
+	fmt.Fprintf(out, "(estimated from on-demand prices in us-east-1; actual prices vary by region)\n")
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to delete orphaned resources\n")
+		return nil
+	}
+
+	fmt.Fprintf(out, "\n")
+
+	if err := resourceops.DeleteResources(cloud, orphanedResources); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nDeleted orphaned resources of cluster: %q\n", cluster.ObjectMeta.Name)
+	return nil
+}
+
 func completeRegion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	// TODO call into cloud provider(s) to get list of valid regions
 	return nil, cobra.ShellCompDirectiveNoFileComp
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/clusterevents"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// publishClusterEvent publishes an event to the SNS topic and EventBridge bus of the cluster spec, if any.
+// Events are informational: failing to publish one is logged, and never fails the command.
+func publishClusterEvent(ctx context.Context, cluster *kopsapi.Cluster, cloud fi.Cloud, event *clusterevents.Event) {
+	publisher, err := clusterevents.NewPublisher(cluster, cloud)
+	if err == nil {
+		err = publisher.Publish(ctx, event)
+	}
+	if err != nil {
+		klog.Warningf("unable to publish %s event: %v", event.Type, err)
+	}
+}
+
+// publishValidationFailed publishes a ValidationFailed event with the failures of the last validation
+func publishValidationFailed(ctx context.Context, cluster *kopsapi.Cluster, cloud fi.Cloud, result *validation.ValidationCluster, reason error) {
+	event := clusterevents.NewEvent(cluster, clusterevents.ValidationFailed, "cluster validation failed")
+	event.Error = reason.Error()
+	if result != nil {
+		var failures []string
+		for _, failure := range result.Failures {
+			failures = append(failures, fmt.Sprintf("%s %s: %s", failure.Kind, failure.Name, failure.Message))
+		}
+		event.Details = map[string]string{
+			"failures": strings.Join(failures, "\n"),
+		}
+	}
+	publishClusterEvent(ctx, cluster, cloud, event)
+}
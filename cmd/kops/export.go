@@ -32,6 +32,9 @@ var (
 	exportExample = templates.Examples(i18n.T(`
 	# export a kubecfg file
 	kops export kubecfg k8s-cluster.example.com
+
+	# export the CA bundle of a cluster
+	kops export credentials ca --name k8s-cluster.example.com
 		`))
 
 	exportShort = i18n.T(`Export configuration.`)
@@ -47,6 +50,7 @@ func NewCmdExport(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	// create subcommands
+	cmd.AddCommand(NewCmdExportCredentials(f, out))
 	cmd.AddCommand(NewCmdExportKubecfg(f, out))
 
 	return cmd
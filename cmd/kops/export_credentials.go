@@ -0,0 +1,375 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+const (
+	// CredentialsAdmin is a cluster admin client certificate, with the URL of the API server
+	CredentialsAdmin = "admin"
+	// CredentialsCA is the CA bundle of the cluster
+	CredentialsCA = "ca"
+	// CredentialsEtcdClient is a client certificate for the etcd clusters
+	CredentialsEtcdClient = "etcd-client"
+
+	CredentialsFormatPEM    = "pem"
+	CredentialsFormatJSON   = "json"
+	CredentialsFormatPKCS12 = "pkcs12"
+)
+
+var (
+	exportCredentialsLong = templates.LongDesc(i18n.T(`
+	Export credentials of a cluster for external tools, such as Vault or External Secrets.
+
+	The credentials are one of:
+
+	* admin: a new cluster admin client certificate, with the CA bundle and the URL of the API server.
+	* ca: the CA bundle of the cluster.
+	* etcd-client: a new client certificate for the etcd clusters, with the etcd clients CA bundle.
+
+	Client certificates are issued for the duration of --lifetime and cannot be revoked, so keep it short.
+
+	The formats are:
+
+	* pem: the certificate, private key and CA certificates, concatenated.
+	* json: an object with the certificate, private_key, private_key_type, serial_number, expiration,
+	  issuing_ca, ca_chain and server fields, following the Vault PKI secrets engine.
+	* pkcs12: a PKCS#12 archive encrypted with AES-256 and the password from --password-file, which is required.`))
+
+	exportCredentialsExample = templates.Examples(i18n.T(`
+	# Export admin credentials valid for an hour as JSON
+	kops export credentials admin --name k8s-cluster.example.com --format json --lifetime 1h
+
+	# Export the CA bundle of the cluster
+	kops export credentials ca --name k8s-cluster.example.com --out ca.pem
+
+	# Export an etcd client certificate as a PKCS#12 archive
+	kops export credentials etcd-client --name k8s-cluster.example.com --format pkcs12 --password-file password.txt --out etcd-client.p12
+	`))
+
+	exportCredentialsShort = i18n.T(`Export credentials for external tools.`)
+)
+
+type ExportCredentialsOptions struct {
+	Format string
+
+	// Lifetime is the validity of the issued client certificates
+	Lifetime time.Duration
+
+	// PasswordFile holds the password protecting PKCS#12 archives
+	PasswordFile string
+
+	// Out is the file to write the credentials to, instead of stdout
+	Out string
+
+	// Internal uses the internal DNS name of the API server
+	Internal bool
+}
+
+func (o *ExportCredentialsOptions) InitDefaults() {
+	o.Format = CredentialsFormatPEM
+	o.Lifetime = time.Hour
+}
+
+func NewCmdExportCredentials(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ExportCredentialsOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:       "credentials CREDENTIALS",
+		Short:     exportCredentialsShort,
+		Long:      exportCredentialsLong,
+		Example:   exportCredentialsExample,
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: []string{CredentialsAdmin, CredentialsCA, CredentialsEtcdClient},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunExportCredentials(context.TODO(), f, out, options, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Format, "format", options.Format, "Format of the credentials: pem, json or pkcs12")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{CredentialsFormatPEM, CredentialsFormatJSON, CredentialsFormatPKCS12}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().DurationVar(&options.Lifetime, "lifetime", options.Lifetime, "Validity of the issued client certificates")
+	cmd.Flags().StringVar(&options.PasswordFile, "password-file", options.PasswordFile, "File holding the password of the PKCS#12 archive")
+	cmd.Flags().StringVar(&options.Out, "out", options.Out, "File to write the credentials to, instead of stdout")
+	cmd.Flags().BoolVar(&options.Internal, "internal", options.Internal, "Use the cluster's internal DNS name for the API server")
+
+	return cmd
+}
+
+// credentialsBundle holds exported credentials
+type credentialsBundle struct {
+	Server         string
+	Certificate    *pki.Certificate
+	PrivateKey     *pki.PrivateKey
+	IssuingCA      *pki.Certificate
+	CACertificates []*pki.Certificate
+}
+
+func RunExportCredentials(ctx context.Context, f *util.Factory, out io.Writer, options *ExportCredentialsOptions, name string) error {
+	switch options.Format {
+	case CredentialsFormatPEM, CredentialsFormatJSON, CredentialsFormatPKCS12:
+	default:
+		return fmt.Errorf("unknown format %q, supported formats: pem, json, pkcs12", options.Format)
+	}
+	if options.Lifetime <= 0 {
+		return fmt.Errorf("--lifetime must be positive")
+	}
+	if options.Format == CredentialsFormatPKCS12 && options.PasswordFile == "" {
+		return fmt.Errorf("--password-file is required for the pkcs12 format")
+	}
+
+	cluster, err := rootCommand.Cluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := rootCommand.Clientset()
+	if err != nil {
+		return err
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	bundle := &credentialsBundle{}
+	switch name {
+	case CredentialsAdmin:
+		secretStore, err := clientset.SecretStore(cluster)
+		if err != nil {
+			return err
+		}
+		cloud, err := cloudup.BuildCloud(cluster)
+		if err != nil {
+			return err
+		}
+		conf, err := kubeconfig.BuildKubecfg(cluster, keyStore, secretStore, cloud, options.Lifetime, "", options.Internal, f.KopsStateStore(), false)
+		if err != nil {
+			return err
+		}
+		bundle.Server = conf.Server
+		if bundle.Certificate, err = pki.ParsePEMCertificate(conf.ClientCert); err != nil {
+			return err
+		}
+		if bundle.PrivateKey, err = pki.ParsePEMPrivateKey(conf.ClientKey); err != nil {
+			return err
+		}
+		// The CA is omitted when the API server uses a publicly trusted certificate
+		if len(conf.CACerts) != 0 {
+			if bundle.IssuingCA, _, err = keyStore.FindPrimaryKeypair(fi.CertificateIDCA); err != nil {
+				return err
+			}
+			if bundle.CACertificates, err = trustedCertificates(keyStore, fi.CertificateIDCA); err != nil {
+				return err
+			}
+		}
+
+	case CredentialsCA:
+		if bundle.IssuingCA, _, err = keyStore.FindPrimaryKeypair(fi.CertificateIDCA); err != nil {
+			return err
+		}
+		if bundle.CACertificates, err = trustedCertificates(keyStore, fi.CertificateIDCA); err != nil {
+			return err
+		}
+
+	case CredentialsEtcdClient:
+		req := pki.IssueCertRequest{
+			Signer: "etcd-clients-ca",
+			Type:   "client",
+			Subject: pkix.Name{
+				CommonName: "kops-etcd-client",
+			},
+			Validity: options.Lifetime,
+		}
+		if bundle.Certificate, bundle.PrivateKey, bundle.IssuingCA, err = pki.IssueCert(&req, keyStore); err != nil {
+			return err
+		}
+		if bundle.CACertificates, err = trustedCertificates(keyStore, req.Signer); err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unknown credentials %q, supported credentials: admin, ca, etcd-client", name)
+	}
+
+	password := ""
+	if options.PasswordFile != "" {
+		data, err := ioutil.ReadFile(options.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("error reading password file: %v", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+		if password == "" {
+			return fmt.Errorf("password file %q is empty", options.PasswordFile)
+		}
+	}
+
+	data, err := encodeCredentials(bundle, options.Format, password)
+	if err != nil {
+		return err
+	}
+
+	if options.Out != "" {
+		return ioutil.WriteFile(options.Out, data, 0600)
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// trustedCertificates returns the certificates of a keyset that are not distrusted, oldest first
+func trustedCertificates(keyStore fi.Keystore, name string) ([]*pki.Certificate, error) {
+	keyset, err := keyStore.FindKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+	if keyset == nil {
+		return nil, fmt.Errorf("keyset %q not found", name)
+	}
+
+	var items []*fi.KeysetItem
+	for _, item := range keyset.Items {
+		if item.DistrustTimestamp == nil {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return fi.KeysetItemIdOlder(items[i].Id, items[j].Id)
+	})
+
+	var certificates []*pki.Certificate
+	for _, item := range items {
+		certificates = append(certificates, item.Certificate)
+	}
+	return certificates, nil
+}
+
+// credentialsJSON is the JSON format of exported credentials, following the Vault PKI secrets engine
+type credentialsJSON struct {
+	Server         string   `json:"server,omitempty"`
+	Certificate    string   `json:"certificate,omitempty"`
+	PrivateKey     string   `json:"private_key,omitempty"`
+	PrivateKeyType string   `json:"private_key_type,omitempty"`
+	SerialNumber   string   `json:"serial_number,omitempty"`
+	Expiration     int64    `json:"expiration,omitempty"`
+	IssuingCA      string   `json:"issuing_ca,omitempty"`
+	CAChain        []string `json:"ca_chain,omitempty"`
+}
+
+func encodeCredentials(bundle *credentialsBundle, format string, password string) ([]byte, error) {
+	switch format {
+	case CredentialsFormatPKCS12:
+		return pki.EncodePKCS12(bundle.Certificate, bundle.PrivateKey, bundle.CACertificates, password)
+
+	case CredentialsFormatPEM:
+		var buf bytes.Buffer
+		if bundle.Certificate != nil {
+			if _, err := bundle.Certificate.WriteTo(&buf); err != nil {
+				return nil, err
+			}
+		}
+		if bundle.PrivateKey != nil {
+			if _, err := bundle.PrivateKey.WriteTo(&buf); err != nil {
+				return nil, err
+			}
+		}
+		for _, ca := range bundle.CACertificates {
+			if _, err := ca.WriteTo(&buf); err != nil {
+				return nil, err
+			}
+		}
+		return buf.Bytes(), nil
+
+	case CredentialsFormatJSON:
+		credentials := &credentialsJSON{
+			Server: bundle.Server,
+		}
+		var err error
+		if bundle.Certificate != nil {
+			if credentials.Certificate, err = bundle.Certificate.AsString(); err != nil {
+				return nil, err
+			}
+			credentials.SerialNumber = formatSerialNumber(bundle.Certificate.Certificate.SerialNumber.Bytes())
+			credentials.Expiration = bundle.Certificate.Certificate.NotAfter.Unix()
+		}
+		if bundle.PrivateKey != nil {
+			if credentials.PrivateKey, err = bundle.PrivateKey.AsString(); err != nil {
+				return nil, err
+			}
+			switch bundle.PrivateKey.Key.(type) {
+			case *rsa.PrivateKey:
+				credentials.PrivateKeyType = "rsa"
+			case *ecdsa.PrivateKey:
+				credentials.PrivateKeyType = "ec"
+			}
+		}
+		if bundle.IssuingCA != nil {
+			if credentials.IssuingCA, err = bundle.IssuingCA.AsString(); err != nil {
+				return nil, err
+			}
+		}
+		for _, ca := range bundle.CACertificates {
+			s, err := ca.AsString()
+			if err != nil {
+				return nil, err
+			}
+			credentials.CAChain = append(credentials.CAChain, s)
+		}
+		data, err := json.MarshalIndent(credentials, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// formatSerialNumber formats a certificate serial number as colon-separated hex bytes, as Vault does
+func formatSerialNumber(serial []byte) string {
+	var parts []string
+	for _, b := range serial {
+		parts = append(parts, fmt.Sprintf("%02x", b))
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/pki"
+)
+
+type credentialsTestKeystore struct {
+	certificate *pki.Certificate
+	privateKey  *pki.PrivateKey
+}
+
+func (k *credentialsTestKeystore) FindPrimaryKeypair(name string) (*pki.Certificate, *pki.PrivateKey, error) {
+	return k.certificate, k.privateKey, nil
+}
+
+func TestEncodeCredentials(t *testing.T) {
+	ca, caKey, _, err := pki.IssueCert(&pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "Test CA"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("error issuing CA: %v", err)
+	}
+	certificate, privateKey, _, err := pki.IssueCert(&pki.IssueCertRequest{
+		Signer:   "ca",
+		Type:     "client",
+		Subject:  pkix.Name{CommonName: "client"},
+		Validity: time.Hour,
+	}, &credentialsTestKeystore{certificate: ca, privateKey: caKey})
+	if err != nil {
+		t.Fatalf("error issuing certificate: %v", err)
+	}
+
+	bundle := &credentialsBundle{
+		Server:         "https://api.example.com",
+		Certificate:    certificate,
+		PrivateKey:     privateKey,
+		IssuingCA:      ca,
+		CACertificates: []*pki.Certificate{ca},
+	}
+
+	data, err := encodeCredentials(bundle, CredentialsFormatPEM, "")
+	if err != nil {
+		t.Fatalf("error encoding PEM: %v", err)
+	}
+	var types []string
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		types = append(types, block.Type)
+	}
+	if len(types) != 3 || types[0] != "CERTIFICATE" || types[1] != "RSA PRIVATE KEY" || types[2] != "CERTIFICATE" {
+		t.Errorf("unexpected PEM blocks %v", types)
+	}
+
+	data, err = encodeCredentials(bundle, CredentialsFormatJSON, "")
+	if err != nil {
+		t.Fatalf("error encoding JSON: %v", err)
+	}
+	credentials := &credentialsJSON{}
+	if err := json.Unmarshal(data, credentials); err != nil {
+		t.Fatalf("error parsing JSON: %v", err)
+	}
+	if credentials.Server != "https://api.example.com" {
+		t.Errorf("unexpected server %q", credentials.Server)
+	}
+	if credentials.PrivateKeyType != "rsa" {
+		t.Errorf("unexpected private key type %q", credentials.PrivateKeyType)
+	}
+	if credentials.Expiration != certificate.Certificate.NotAfter.Unix() {
+		t.Errorf("unexpected expiration %d", credentials.Expiration)
+	}
+	if len(credentials.CAChain) != 1 || credentials.IssuingCA != credentials.CAChain[0] {
+		t.Errorf("unexpected CA chain %v, issuing CA %q", credentials.CAChain, credentials.IssuingCA)
+	}
+
+	caOnly, err := encodeCredentials(&credentialsBundle{CACertificates: []*pki.Certificate{ca}}, CredentialsFormatJSON, "")
+	if err != nil {
+		t.Fatalf("error encoding JSON: %v", err)
+	}
+	credentials = &credentialsJSON{}
+	if err := json.Unmarshal(caOnly, credentials); err != nil {
+		t.Fatalf("error parsing JSON: %v", err)
+	}
+	if credentials.Certificate != "" || credentials.PrivateKey != "" || len(credentials.CAChain) != 1 {
+		t.Errorf("unexpected CA bundle %+v", credentials)
+	}
+}
+
+func TestFormatSerialNumber(t *testing.T) {
+	if actual := formatSerialNumber([]byte{0x01, 0xab, 0x10}); actual != "01:ab:10" {
+		t.Errorf("unexpected serial number %q", actual)
+	}
+}
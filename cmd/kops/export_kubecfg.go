@@ -48,6 +48,9 @@ var (
 	# export using a user already existing in the kubeconfig file
 	kops export kubecfg k8s-cluster.example.com --user my-oidc-user
 
+	# export a kubeconfig that mints short-lived cluster admin credentials on demand
+	kops export kubecfg k8s-cluster.example.com --auth-plugin
+
 	# export using the internal DNS name, bypassing the cloud load balancer
 	kops export kubecfg k8s-cluster.example.com --internal
 		`))
@@ -89,7 +92,7 @@ func NewCmdExportKubecfg(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().Lookup("admin").NoOptDefVal = kubeconfig.DefaultKubecfgAdminLifetime.String()
 	cmd.Flags().StringVar(&options.user, "user", options.user, "re-use an existing user in kubeconfig.  Value must specify an existing user block in your kubeconfig file.")
 	cmd.Flags().BoolVar(&options.internal, "internal", options.internal, "use the cluster's internal DNS name")
-	cmd.Flags().BoolVar(&options.UseKopsAuthenticationPlugin, "auth-plugin", options.UseKopsAuthenticationPlugin, "use the kOps authentication plugin")
+	cmd.Flags().BoolVar(&options.UseKopsAuthenticationPlugin, "auth-plugin", options.UseKopsAuthenticationPlugin, "use the kOps authentication plugin to issue short-lived credentials on demand; --admin sets the credential lifetime")
 
 	return cmd
 }
@@ -107,6 +110,9 @@ func RunExportKubecfg(ctx context.Context, f *util.Factory, out io.Writer, optio
 	if options.admin != 0 && options.user != "" {
 		return fmt.Errorf("cannot use both --admin and --user")
 	}
+	if options.UseKopsAuthenticationPlugin && options.user != "" {
+		return fmt.Errorf("cannot use both --auth-plugin and --user")
+	}
 
 	var clusterList []*kopsapi.Cluster
 	if options.all {
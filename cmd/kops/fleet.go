@@ -0,0 +1,301 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kops/util/pkg/text"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	fleetLong = templates.LongDesc(i18n.T(`
+	Run operations across many clusters.
+
+	The clusters are the ones defined in a directory of manifests, in the format
+	used by kops replace. Each operation runs on several clusters at a time, reporting
+	progress as each cluster completes and a summary of all the clusters at the end.
+	`))
+
+	fleetExample = templates.Examples(i18n.T(`
+	# Write the cluster manifests to the state store, then update the clusters
+	kops fleet replace -f clusters/
+	kops fleet update -f clusters/ --yes
+
+	# Roll the clusters, five at a time
+	kops fleet rolling-update -f clusters/ --concurrency 5 --yes
+
+	# Validate two of the clusters
+	kops fleet validate -f clusters/ --clusters a.example.com,b.example.com
+	`))
+
+	fleetShort = i18n.T(`Run operations across many clusters.`)
+)
+
+// FleetOptions selects the clusters a fleet command runs on
+type FleetOptions struct {
+	// Filenames are the manifest files, or directories of manifest files, defining the clusters
+	Filenames []string
+	// Clusters restricts the command to the named clusters, if set
+	Clusters []string
+	// Concurrency is the maximum number of clusters to run on at the same time
+	Concurrency int
+}
+
+func (o *FleetOptions) InitDefaults() {
+	o.Concurrency = 4
+}
+
+func (o *FleetOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(&o.Filenames, "filename", "f", o.Filenames, "Manifest files, or directories of manifest files, defining the clusters")
+	cmd.MarkFlagRequired("filename")
+	cmd.Flags().StringSliceVar(&o.Clusters, "clusters", o.Clusters, "Names of the clusters to run on. Defaults to all the clusters in the manifests")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", o.Concurrency, "Maximum number of clusters to run on at the same time")
+}
+
+func NewCmdFleet(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "fleet",
+		Short:   fleetShort,
+		Long:    fleetLong,
+		Example: fleetExample,
+	}
+
+	cmd.AddCommand(NewCmdFleetReplace(f, out))
+	cmd.AddCommand(NewCmdFleetUpdate(f, out))
+	cmd.AddCommand(NewCmdFleetRollingUpdate(f, out))
+	cmd.AddCommand(NewCmdFleetValidate(f, out))
+
+	return cmd
+}
+
+// fleetCluster is a cluster defined in the fleet manifests
+type fleetCluster struct {
+	Name string
+	// Files are the manifest files containing the cluster's resources
+	Files []string
+}
+
+// manifestHeader is the part of a manifest needed to find the cluster a resource belongs to
+type manifestHeader struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+}
+
+// loadFleetClusters finds the clusters defined by the manifests, sorted by name
+func loadFleetClusters(options *FleetOptions) ([]*fleetCluster, error) {
+	var files []string
+	for _, filename := range options.Filenames {
+		list, err := expandFiles(utils.ExpandPath(filename))
+		if err != nil {
+			return nil, fmt.Errorf("error reading %q: %v", filename, err)
+		}
+		for _, file := range list {
+			switch filepath.Ext(file) {
+			case ".yaml", ".yml", ".json":
+				files = append(files, file)
+			}
+		}
+	}
+
+	clusters := make(map[string]*fleetCluster)
+	for _, file := range files {
+		contents, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file %q: %v", file, err)
+		}
+
+		names := sets.NewString()
+		for _, section := range text.SplitContentToSections(contents) {
+			header := &manifestHeader{}
+			if err := yaml.Unmarshal(section, header); err != nil {
+				return nil, fmt.Errorf("error parsing file %q: %v", file, err)
+			}
+			if header.Kind == "Cluster" {
+				names.Insert(header.Metadata.Name)
+			} else if name := header.Metadata.Labels[kopsapi.LabelClusterName]; name != "" {
+				names.Insert(name)
+			}
+		}
+
+		switch names.Len() {
+		case 0:
+			continue
+		case 1:
+		default:
+			return nil, fmt.Errorf("file %q contains resources for more than one cluster: %v", file, names.List())
+		}
+
+		name := names.List()[0]
+		if clusters[name] == nil {
+			clusters[name] = &fleetCluster{Name: name}
+		}
+		clusters[name].Files = append(clusters[name].Files, file)
+	}
+
+	if len(options.Clusters) != 0 {
+		selected := make(map[string]*fleetCluster)
+		for _, name := range options.Clusters {
+			if clusters[name] == nil {
+				return nil, fmt.Errorf("cluster %q not found in the manifests", name)
+			}
+			selected[name] = clusters[name]
+		}
+		clusters = selected
+	}
+
+	var list []*fleetCluster
+	for _, cluster := range clusters {
+		list = append(list, cluster)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no clusters found in %v", options.Filenames)
+	}
+
+	return list, nil
+}
+
+// fleetResult is the outcome of running an operation on one cluster
+type fleetResult struct {
+	Cluster  string
+	Err      error
+	Duration time.Duration
+}
+
+// runFleet runs fn on the clusters, at most concurrency at a time. Each cluster's output is buffered and
+// written when the cluster completes, followed by a progress line, and a summary is written at the end.
+func runFleet(ctx context.Context, out io.Writer, operation string, clusters []*fleetCluster, concurrency int, fn func(ctx context.Context, cluster *fleetCluster, out io.Writer) error) error {
+	if concurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1")
+	}
+	if concurrency > len(clusters) {
+		concurrency = len(clusters)
+	}
+
+	fmt.Fprintf(out, "Running %s on %d clusters, %d at a time\n\n", operation, len(clusters), concurrency)
+
+	results := make([]*fleetResult, len(clusters))
+
+	var mutex sync.Mutex
+	completed := 0
+
+	runCluster := func(i int, cluster *fleetCluster) {
+		var buf bytes.Buffer
+		start := time.Now()
+		err := fn(ctx, cluster, &buf)
+		result := &fleetResult{
+			Cluster:  cluster.Name,
+			Err:      err,
+			Duration: time.Since(start).Round(time.Second),
+		}
+		results[i] = result
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		completed++
+		if buf.Len() != 0 {
+			fmt.Fprintf(out, "==> %s <==\n", cluster.Name)
+			out.Write(buf.Bytes())
+			if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+				fmt.Fprintln(out)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(out, "[%d/%d] %s: failed after %v: %v\n\n", completed, len(clusters), cluster.Name, result.Duration, err)
+		} else {
+			fmt.Fprintf(out, "[%d/%d] %s: succeeded in %v\n\n", completed, len(clusters), cluster.Name, result.Duration)
+		}
+	}
+
+	// The clusters are started in order, by a fixed number of workers
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				runCluster(i, clusters[i])
+			}
+		}()
+	}
+	for i := range clusters {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	t := &tables.Table{}
+	t.AddColumn("CLUSTER", func(r *fleetResult) string {
+		return r.Cluster
+	})
+	t.AddColumn("STATUS", func(r *fleetResult) string {
+		if r.Err != nil {
+			return "Failed"
+		}
+		return "Succeeded"
+	})
+	t.AddColumn("DURATION", func(r *fleetResult) string {
+		return r.Duration.String()
+	})
+	t.AddColumn("MESSAGE", func(r *fleetResult) string {
+		if r.Err != nil {
+			return strings.ReplaceAll(r.Err.Error(), "\n", " ")
+		}
+		return ""
+	})
+	if err := t.Render(results, out, "CLUSTER", "STATUS", "DURATION", "MESSAGE"); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed != 0 {
+		return fmt.Errorf("%s failed on %d of %d clusters", operation, failed, len(clusters))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	fleetReplaceLong = templates.LongDesc(i18n.T(`
+	Replace the specs of the clusters in the state store with the ones in the manifests,
+	creating any clusters and instance groups that do not exist yet.
+
+	This writes the desired configuration only; use kops fleet update to apply it.
+	`))
+
+	fleetReplaceExample = templates.Examples(i18n.T(`
+	kops fleet replace -f clusters/
+	`))
+
+	fleetReplaceShort = i18n.T(`Replace the cluster specs from a directory of manifests.`)
+)
+
+func NewCmdFleetReplace(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &FleetOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "replace -f DIRECTORY",
+		Short:   fleetReplaceShort,
+		Long:    fleetReplaceLong,
+		Example: fleetReplaceExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunFleetReplace(context.TODO(), f, out, options)
+		},
+	}
+
+	options.AddFlags(cmd)
+
+	return cmd
+}
+
+func RunFleetReplace(ctx context.Context, f *util.Factory, out io.Writer, options *FleetOptions) error {
+	clusters, err := loadFleetClusters(options)
+	if err != nil {
+		return err
+	}
+
+	return runFleet(ctx, out, "replace", clusters, options.Concurrency, func(ctx context.Context, cluster *fleetCluster, out io.Writer) error {
+		return RunReplace(ctx, f, nil, out, &replaceOptions{
+			Filenames: cluster.Files,
+			force:     true,
+		})
+	})
+}
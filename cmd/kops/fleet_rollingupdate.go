@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	fleetRollingUpdateLong = templates.LongDesc(i18n.T(`
+	Perform a rolling update of the clusters, replacing the instances that need updating.
+	Without --yes, the instances that would be replaced are listed.
+
+	Each cluster is rolled one instance group at a time, as with kops rolling-update cluster;
+	--concurrency controls how many clusters are rolled at the same time.
+	`))
+
+	fleetRollingUpdateExample = templates.Examples(i18n.T(`
+	# Roll the nodes of all the clusters, two clusters at a time
+	kops fleet rolling-update -f clusters/ --instance-group-roles=node --concurrency 2 --yes
+	`))
+
+	fleetRollingUpdateShort = i18n.T(`Rolling update the clusters in a directory of manifests.`)
+)
+
+type FleetRollingUpdateOptions struct {
+	FleetOptions
+
+	Yes                bool
+	Force              bool
+	CloudOnly          bool
+	FailOnValidate     bool
+	ValidationTimeout  time.Duration
+	ValidateCount      int32
+	InstanceGroupRoles []string
+}
+
+func (o *FleetRollingUpdateOptions) InitDefaults() {
+	o.FleetOptions.InitDefaults()
+
+	defaults := &RollingUpdateOptions{}
+	defaults.InitDefaults()
+	o.FailOnValidate = defaults.FailOnValidate
+	o.ValidationTimeout = defaults.ValidationTimeout
+	o.ValidateCount = defaults.ValidateCount
+}
+
+func NewCmdFleetRollingUpdate(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &FleetRollingUpdateOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "rolling-update -f DIRECTORY",
+		Short:   fleetRollingUpdateShort,
+		Long:    fleetRollingUpdateLong,
+		Example: fleetRollingUpdateExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunFleetRollingUpdate(context.TODO(), f, out, options)
+		},
+	}
+
+	allRoles := make([]string, 0, len(kopsapi.AllInstanceGroupRoles))
+	for _, r := range kopsapi.AllInstanceGroupRoles {
+		allRoles = append(allRoles, strings.ToLower(string(r)))
+	}
+
+	options.AddFlags(cmd)
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Perform rolling update immediately; without --yes rolling-update executes a dry-run")
+	cmd.Flags().BoolVar(&options.Force, "force", options.Force, "Force rolling update, even if no changes")
+	cmd.Flags().BoolVar(&options.CloudOnly, "cloudonly", options.CloudOnly, "Perform rolling update without confirming progress with Kubernetes")
+	cmd.Flags().BoolVar(&options.FailOnValidate, "fail-on-validate-error", options.FailOnValidate, "Fail a cluster's rolling-update if its validation fails")
+	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for a cluster to validate")
+	cmd.Flags().Int32Var(&options.ValidateCount, "validate-count", options.ValidateCount, "Number of times that a cluster needs to be validated after single node update")
+	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "Instance group roles to update ("+strings.Join(allRoles, ",")+")")
+
+	return cmd
+}
+
+func RunFleetRollingUpdate(ctx context.Context, f *util.Factory, out io.Writer, options *FleetRollingUpdateOptions) error {
+	clusters, err := loadFleetClusters(&options.FleetOptions)
+	if err != nil {
+		return err
+	}
+
+	return runFleet(ctx, out, "rolling-update", clusters, options.Concurrency, func(ctx context.Context, cluster *fleetCluster, out io.Writer) error {
+		rollingUpdateOptions := &RollingUpdateOptions{}
+		rollingUpdateOptions.InitDefaults()
+		rollingUpdateOptions.ClusterName = cluster.Name
+		rollingUpdateOptions.Yes = options.Yes
+		rollingUpdateOptions.Force = options.Force
+		rollingUpdateOptions.CloudOnly = options.CloudOnly
+		rollingUpdateOptions.FailOnValidate = options.FailOnValidate
+		rollingUpdateOptions.ValidationTimeout = options.ValidationTimeout
+		rollingUpdateOptions.ValidateCount = options.ValidateCount
+		rollingUpdateOptions.InstanceGroupRoles = options.InstanceGroupRoles
+
+		return RunRollingUpdateCluster(ctx, f, out, rollingUpdateOptions)
+	})
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func writeFleetManifests(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("error writing %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+const fleetClusterManifest = `apiVersion: kops.k8s.io/v1alpha2
+kind: Cluster
+metadata:
+  name: %s
+---
+apiVersion: kops.k8s.io/v1alpha2
+kind: InstanceGroup
+metadata:
+  name: nodes
+  labels:
+    kops.k8s.io/cluster: %s
+`
+
+const fleetInstanceGroupManifest = `apiVersion: kops.k8s.io/v1alpha2
+kind: InstanceGroup
+metadata:
+  name: extra
+  labels:
+    kops.k8s.io/cluster: %s
+`
+
+func TestLoadFleetClusters(t *testing.T) {
+	dir := writeFleetManifests(t, map[string]string{
+		"b.yaml":       fmt.Sprintf(fleetClusterManifest, "b.example.com", "b.example.com"),
+		"a.yaml":       fmt.Sprintf(fleetClusterManifest, "a.example.com", "a.example.com"),
+		"a-extra.yml":  fmt.Sprintf(fleetInstanceGroupManifest, "a.example.com"),
+		"README.md":    "not a manifest",
+		"unowned.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n",
+	})
+
+	clusters, err := loadFleetClusters(&FleetOptions{Filenames: []string{dir}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual []string
+	for _, cluster := range clusters {
+		var files []string
+		for _, file := range cluster.Files {
+			files = append(files, filepath.Base(file))
+		}
+		actual = append(actual, cluster.Name+"="+strings.Join(files, ","))
+	}
+	expected := []string{"a.example.com=a-extra.yml,a.yaml", "b.example.com=b.yaml"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	clusters, err = loadFleetClusters(&FleetOptions{Filenames: []string{dir}, Clusters: []string{"b.example.com"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clusters) != 1 || clusters[0].Name != "b.example.com" {
+		t.Errorf("expected only b.example.com to be selected, got %v", clusters)
+	}
+
+	if _, err := loadFleetClusters(&FleetOptions{Filenames: []string{dir}, Clusters: []string{"c.example.com"}}); err == nil {
+		t.Errorf("expected error selecting a cluster not in the manifests")
+	}
+}
+
+func TestLoadFleetClustersMixedFile(t *testing.T) {
+	dir := writeFleetManifests(t, map[string]string{
+		"mixed.yaml": fmt.Sprintf(fleetClusterManifest, "a.example.com", "b.example.com"),
+	})
+
+	_, err := loadFleetClusters(&FleetOptions{Filenames: []string{dir}})
+	if err == nil || !strings.Contains(err.Error(), "more than one cluster") {
+		t.Errorf("expected error for a file with more than one cluster, got %v", err)
+	}
+}
+
+func TestRunFleet(t *testing.T) {
+	var clusters []*fleetCluster
+	for _, name := range []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com"} {
+		clusters = append(clusters, &fleetCluster{Name: name})
+	}
+
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+
+	var out bytes.Buffer
+	err := runFleet(context.Background(), &out, "test", clusters, 2, func(ctx context.Context, cluster *fleetCluster, out io.Writer) error {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		defer func() {
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		}()
+
+		fmt.Fprintf(out, "output from %s\n", cluster.Name)
+		if cluster.Name == "c.example.com" {
+			return fmt.Errorf("broken")
+		}
+		return nil
+	})
+
+	if err == nil || err.Error() != "test failed on 1 of 4 clusters" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if maxRunning > 2 {
+		t.Errorf("expected at most 2 clusters at a time, got %d", maxRunning)
+	}
+
+	output := out.String()
+	for _, expected := range []string{
+		"Running test on 4 clusters, 2 at a time",
+		"==> a.example.com <==\noutput from a.example.com\n",
+		"c.example.com: failed after 0s: broken",
+		"[4/4]",
+		"CLUSTER",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	fleetUpdateLong = templates.LongDesc(i18n.T(`
+	Update the cloud resources of the clusters to match their specs in the state store.
+	Without --yes, the changes are previewed.
+
+	The kubeconfig is not written, so that concurrent updates do not conflict; use
+	kops export kubecfg for any new clusters.
+	`))
+
+	fleetUpdateExample = templates.Examples(i18n.T(`
+	# Preview the changes
+	kops fleet update -f clusters/
+
+	# Apply the changes
+	kops fleet update -f clusters/ --yes
+	`))
+
+	fleetUpdateShort = i18n.T(`Update the clusters in a directory of manifests.`)
+)
+
+type FleetUpdateOptions struct {
+	FleetOptions
+
+	Yes                bool
+	AllowKopsDowngrade bool
+}
+
+func NewCmdFleetUpdate(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &FleetUpdateOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "update -f DIRECTORY",
+		Short:   fleetUpdateShort,
+		Long:    fleetUpdateLong,
+		Example: fleetUpdateExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunFleetUpdate(context.TODO(), f, out, options)
+		},
+	}
+
+	options.AddFlags(cmd)
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Update cloud resources, without --yes update is in dry run mode")
+	cmd.Flags().BoolVar(&options.AllowKopsDowngrade, "allow-kops-downgrade", options.AllowKopsDowngrade, "Allow an older version of kOps to update the clusters than last used")
+
+	return cmd
+}
+
+func RunFleetUpdate(ctx context.Context, f *util.Factory, out io.Writer, options *FleetUpdateOptions) error {
+	clusters, err := loadFleetClusters(&options.FleetOptions)
+	if err != nil {
+		return err
+	}
+
+	return runFleet(ctx, out, "update", clusters, options.Concurrency, func(ctx context.Context, cluster *fleetCluster, out io.Writer) error {
+		updateOptions := &UpdateClusterOptions{}
+		updateOptions.InitDefaults()
+		updateOptions.ClusterName = cluster.Name
+		updateOptions.Yes = options.Yes
+		updateOptions.AllowKopsDowngrade = options.AllowKopsDowngrade
+		updateOptions.CreateKubecfg = false
+
+		_, err := RunUpdateCluster(ctx, f, out, updateOptions)
+		return err
+	})
+}
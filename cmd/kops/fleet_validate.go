@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	fleetValidateLong = templates.LongDesc(i18n.T(`
+	Validate the clusters, using the kubeconfig context named after each cluster.
+	`))
+
+	fleetValidateExample = templates.Examples(i18n.T(`
+	# Validate all the clusters, waiting up to 10 minutes for each to become ready
+	kops fleet validate -f clusters/ --wait 10m
+	`))
+
+	fleetValidateShort = i18n.T(`Validate the clusters in a directory of manifests.`)
+)
+
+type FleetValidateOptions struct {
+	FleetOptions
+
+	Wait       time.Duration
+	Count      int
+	Kubeconfig string
+}
+
+func NewCmdFleetValidate(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &FleetValidateOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "validate -f DIRECTORY",
+		Short:   fleetValidateShort,
+		Long:    fleetValidateLong,
+		Example: fleetValidateExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunFleetValidate(context.TODO(), f, out, options)
+		},
+	}
+
+	options.AddFlags(cmd)
+	cmd.Flags().DurationVar(&options.Wait, "wait", options.Wait, "Amount of time to wait for each cluster to become ready")
+	cmd.Flags().IntVar(&options.Count, "count", options.Count, "Number of consecutive successful validations required")
+	cmd.Flags().StringVar(&options.Kubeconfig, "kubeconfig", options.Kubeconfig, "Path to the kubeconfig file")
+
+	return cmd
+}
+
+func RunFleetValidate(ctx context.Context, f *util.Factory, out io.Writer, options *FleetValidateOptions) error {
+	clusters, err := loadFleetClusters(&options.FleetOptions)
+	if err != nil {
+		return err
+	}
+
+	return runFleet(ctx, out, "validate", clusters, options.Concurrency, func(ctx context.Context, cluster *fleetCluster, out io.Writer) error {
+		validateOptions := &ValidateClusterOptions{}
+		validateOptions.InitDefaults()
+		validateOptions.ClusterName = cluster.Name
+		validateOptions.wait = options.Wait
+		validateOptions.count = options.Count
+		validateOptions.kubeconfig = options.Kubeconfig
+
+		result, err := RunValidateCluster(ctx, f, out, validateOptions)
+		if err != nil {
+			return err
+		}
+		if len(result.Failures) != 0 {
+			return fmt.Errorf("%d validation failures", len(result.Failures))
+		}
+		return nil
+	})
+}
@@ -176,7 +176,7 @@ func RunGet(ctx context.Context, f commandutils.Factory, out io.Writer, options
 			return err
 		}
 		fmt.Fprintf(os.Stdout, "\nInstance Groups\n")
-		err = igOutputTable(cluster, instancegroups, out)
+		err = igOutputTable(cluster, instancegroups, nil, out)
 		if err != nil {
 			return err
 		}
@@ -22,7 +22,12 @@ import (
 	"fmt"
 	"io"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 	"sigs.k8s.io/yaml"
@@ -37,6 +42,10 @@ import (
 type GetAssetsOptions struct {
 	*GetOptions
 	Copy bool
+	// ContainerRegistry is the container registry to copy images to, which the cluster is then configured to use.
+	ContainerRegistry string
+	// FileRepository is the file repository to copy files to, which the cluster is then configured to use.
+	FileRepository string
 }
 
 type Image struct {
@@ -65,11 +74,20 @@ func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *co
 	getAssetsShort := i18n.T(`Display assets for cluster.`)
 
 	getAssetsLong := templates.LongDesc(i18n.T(`
-	Display assets for cluster.`))
+	Display assets for cluster.
+
+	With --copy, the assets are copied to the container registry and file repository
+	configured in the cluster's assets. A different container registry or file repository
+	can be given with --container-registry and --file-repository; once all assets have been
+	copied, the cluster spec is updated to use them. Apply the change with kops update cluster.`))
 
 	getAssetsExample := templates.Examples(i18n.T(`
 	# Display all assets.
 	kops get assets
+
+	# Copy all assets to a private registry and bucket, and configure the cluster to use them.
+	kops get assets --copy --container-registry registry.example.com/kops \
+	  --file-repository https://kops-assets.s3.amazonaws.com/
 	`))
 
 	cmd := &cobra.Command{
@@ -92,6 +110,8 @@ func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *co
 	}
 
 	cmd.Flags().BoolVar(&options.Copy, "copy", options.Copy, "copy assets to local repository")
+	cmd.Flags().StringVar(&options.ContainerRegistry, "container-registry", options.ContainerRegistry, "container registry to copy images to and configure the cluster to use; requires --copy")
+	cmd.Flags().StringVar(&options.FileRepository, "file-repository", options.FileRepository, "file repository to copy files to and configure the cluster to use; requires --copy")
 
 	return cmd
 }
@@ -104,10 +124,25 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 		return fmt.Errorf("--name is required")
 	}
 
+	var assetLocations *kops.Assets
+	if options.ContainerRegistry != "" || options.FileRepository != "" {
+		if !options.Copy {
+			return fmt.Errorf("--container-registry and --file-repository require --copy")
+		}
+		assetLocations = &kops.Assets{}
+		if options.ContainerRegistry != "" {
+			assetLocations.ContainerRegistry = fi.String(options.ContainerRegistry)
+		}
+		if options.FileRepository != "" {
+			assetLocations.FileRepository = fi.String(options.FileRepository)
+		}
+	}
+
 	updateClusterResults, err := RunUpdateCluster(ctx, f, out, &UpdateClusterOptions{
 		Target:      cloudup.TargetDryRun,
 		GetAssets:   true,
 		ClusterName: clusterName,
+		Assets:      assetLocations,
 	})
 	if err != nil {
 		return err
@@ -148,6 +183,13 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 		if err != nil {
 			return err
 		}
+
+		if assetLocations != nil {
+			if err := updateAssetLocations(ctx, f, clusterName, assetLocations); err != nil {
+				return err
+			}
+			klog.Infof("Cluster %q updated to use the copied assets; run kops update cluster to apply the change", clusterName)
+		}
 	}
 
 	switch options.output {
@@ -209,3 +251,29 @@ func fileOutputTable(files []*File, out io.Writer) error {
 	columns := []string{"CANONICAL", "DOWNLOAD", "SHA"}
 	return t.Render(files, out, columns...)
 }
+
+// updateAssetLocations writes the asset locations to the cluster spec in the state store.
+func updateAssetLocations(ctx context.Context, f *util.Factory, clusterName string, assetLocations *kops.Assets) error {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, clusterName)
+	if err != nil {
+		return err
+	}
+
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+
+	overrideAssetLocations(cluster, assetLocations)
+
+	return commands.UpdateCluster(ctx, clientset, cluster, instanceGroups)
+}
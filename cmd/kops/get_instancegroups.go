@@ -20,15 +20,21 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/formatter"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -47,13 +53,47 @@ var (
 
 	# Save a cluster's instancegroups desired configuration to YAML file
 	kops get ig --name k8s-cluster.example.com -o yaml > instancegroups-desired-config.yaml
+
+	# Show an instancegroup with the defaults kOps applies to it, such as the image, volumes and kubelet configuration
+	kops get ig --name k8s-cluster.example.com nodes --full -o yaml
+
+	# Show whether the instancegroups have changes pending that require a rolling update
+	kops get ig --name k8s-cluster.example.com --pending
 	`))
 
 	getInstancegroupsShort = i18n.T(`Get one or many instancegroups`)
+
+	// Warning for --full.  Since we are not using the template from kubectl
+	// we have to have zero white space before the comment characters otherwise
+	// output to stdout is going to be off.
+	get_instancegroups_full_warning = i18n.T(`
+//
+//   WARNING: Do not use a '--full' instance group specification to define an instance group.
+//   The defaults would no longer follow the cluster and future kOps versions.
+//   Use only the required elements and any modifications that you require.
+//
+//   Use the following command to retrieve only the required elements:
+//   $ kops get instancegroups -o yaml
+//
+
+`)
+)
+
+const (
+	// pendingRollingUpdate is the pending status of an instance group whose instances must be replaced by a rolling update
+	pendingRollingUpdate = "RollingUpdate"
+	// pendingInPlace is the pending status of an instance group whose changes are applied to the existing instances
+	pendingInPlace = "InPlace"
 )
 
 type GetInstanceGroupsOptions struct {
 	*GetOptions
+
+	// FullSpec determines if we should output the instance groups with the defaults kOps applies to them
+	FullSpec bool
+
+	// Pending determines if we should show the changes to the instances pending to be applied by kops update cluster
+	Pending bool
 }
 
 func NewCmdGetInstanceGroups(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
@@ -69,19 +109,26 @@ func NewCmdGetInstanceGroups(f *util.Factory, out io.Writer, getOptions *GetOpti
 		Example: getInstancegroupsExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.TODO()
-			err := RunGetInstanceGroups(ctx, &options, args)
+			err := RunGetInstanceGroups(ctx, f, &options, args)
 			if err != nil {
 				exitWithError(err)
 			}
 		},
 	}
 
+	cmd.Flags().BoolVar(&options.FullSpec, "full", options.FullSpec, "Show fully populated configuration, as kOps applies it")
+	cmd.Flags().BoolVar(&options.Pending, "pending", options.Pending, "Show whether the changes pending to be applied by kops update cluster require a rolling update of the instances or are applied in place")
+
 	return cmd
 }
 
-func RunGetInstanceGroups(ctx context.Context, options *GetInstanceGroupsOptions, args []string) error {
+func RunGetInstanceGroups(ctx context.Context, f *util.Factory, options *GetInstanceGroupsOptions, args []string) error {
 	out := os.Stdout
 
+	if options.Pending && options.output != OutputTable {
+		return fmt.Errorf("--pending is only supported with the table output")
+	}
+
 	clusterName := rootCommand.ClusterName(true)
 	if clusterName == "" {
 		return fmt.Errorf("--name is required")
@@ -115,6 +162,15 @@ func RunGetInstanceGroups(ctx context.Context, options *GetInstanceGroupsOptions
 		return fmt.Errorf("No InstanceGroup objects found")
 	}
 
+	if options.FullSpec {
+		instancegroups, err = fullInstanceGroupSpecs(cluster, instancegroups)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(out, get_instancegroups_full_warning)
+	}
+
 	var obj []runtime.Object
 	if options.output != OutputTable {
 		for _, c := range instancegroups {
@@ -124,7 +180,14 @@ func RunGetInstanceGroups(ctx context.Context, options *GetInstanceGroupsOptions
 
 	switch options.output {
 	case OutputTable:
-		return igOutputTable(cluster, instancegroups, out)
+		var pending map[string]string
+		if options.Pending {
+			pending, err = pendingInstanceGroupChanges(ctx, f, cluster, instancegroups)
+			if err != nil {
+				return err
+			}
+		}
+		return igOutputTable(cluster, instancegroups, pending, out)
 	case OutputYaml:
 		return fullOutputYAML(out, obj...)
 	case OutputJSON:
@@ -134,6 +197,77 @@ func RunGetInstanceGroups(ctx context.Context, options *GetInstanceGroupsOptions
 	}
 }
 
+// fullInstanceGroupSpecs returns the instance groups with the defaults kOps applies to them,
+// based on the fully populated cluster spec written by the last update of the cluster
+func fullInstanceGroupSpecs(cluster *api.Cluster, instancegroups []*api.InstanceGroup) ([]*api.InstanceGroup, error) {
+	fullClusters, err := fullClusterSpecs([]*api.Cluster{cluster})
+	if err != nil {
+		return nil, err
+	}
+	fullCluster := fullClusters[0]
+
+	cloud, err := cloudup.BuildCloud(fullCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := cloudup.ChannelForCluster(fullCluster)
+	if err != nil {
+		klog.Warningf("%v", err)
+	}
+
+	var fullSpecs []*api.InstanceGroup
+	for _, ig := range instancegroups {
+		fullSpec, err := cloudup.FullInstanceGroupSpec(fullCluster, ig, cloud, channel)
+		if err != nil {
+			return nil, fmt.Errorf("error populating instance group %q: %v", ig.ObjectMeta.Name, err)
+		}
+		fullSpecs = append(fullSpecs, fullSpec)
+	}
+	return fullSpecs, nil
+}
+
+// pendingInstanceGroupChanges returns, by instance group name, whether the changes to its instances pending to be
+// applied by kops update cluster require a rolling update or are applied in place, by running a dry run of the update
+func pendingInstanceGroupChanges(ctx context.Context, f *util.Factory, cluster *api.Cluster, instancegroups []*api.InstanceGroup) (map[string]string, error) {
+	updateOptions := &UpdateClusterOptions{}
+	updateOptions.InitDefaults()
+	updateOptions.ClusterName = cluster.ObjectMeta.Name
+	updateOptions.Target = cloudup.TargetDryRun
+	results, err := RunUpdateCluster(ctx, f, ioutil.Discard, updateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	target, ok := results.Target.(*fi.DryRunTarget)
+	if !ok {
+		return nil, fmt.Errorf("unexpected target type %T", results.Target)
+	}
+	changes, err := target.InstanceChanges(results.TaskMap)
+	if err != nil {
+		return nil, err
+	}
+
+	modelContext := &model.KopsModelContext{
+		IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+	}
+	pending := make(map[string]string)
+	for _, ig := range instancegroups {
+		name := modelContext.AutoscalingGroupName(ig)
+		for _, change := range changes {
+			if change.Name != name {
+				continue
+			}
+			if change.RequiresReplacement() {
+				pending[ig.ObjectMeta.Name] = pendingRollingUpdate
+			} else if pending[ig.ObjectMeta.Name] == "" {
+				pending[ig.ObjectMeta.Name] = pendingInPlace
+			}
+		}
+	}
+	return pending, nil
+}
+
 func filterInstanceGroupsByName(instanceGroupNames []string, list []api.InstanceGroup) ([]*api.InstanceGroup, error) {
 	var instancegroups []*api.InstanceGroup
 	if len(instanceGroupNames) != 0 {
@@ -161,7 +295,8 @@ func filterInstanceGroupsByName(instanceGroupNames []string, list []api.Instance
 	return instancegroups, nil
 }
 
-func igOutputTable(cluster *api.Cluster, instancegroups []*api.InstanceGroup, out io.Writer) error {
+// igOutputTable renders the instance groups as a table, with their pending changes if not nil
+func igOutputTable(cluster *api.Cluster, instancegroups []*api.InstanceGroup, pending map[string]string, out io.Writer) error {
 	t := &tables.Table{}
 	t.AddColumn("NAME", func(c *api.InstanceGroup) string {
 		return c.ObjectMeta.Name
@@ -180,8 +315,24 @@ func igOutputTable(cluster *api.Cluster, instancegroups []*api.InstanceGroup, ou
 	t.AddColumn("MAX", func(c *api.InstanceGroup) string {
 		return int32PointerToString(c.Spec.MaxSize)
 	})
+	t.AddColumn("PENDING", func(c *api.InstanceGroup) string {
+		if s := pending[c.ObjectMeta.Name]; s != "" {
+			return s
+		}
+		return "-"
+	})
 	// SUBNETS is not selected by default - not as useful as ZONES
-	return t.Render(instancegroups, os.Stdout, "NAME", "ROLE", "MACHINETYPE", "MIN", "MAX", "ZONES")
+	columns := []string{"NAME", "ROLE", "MACHINETYPE", "MIN", "MAX", "ZONES"}
+	if pending != nil {
+		columns = append(columns, "PENDING")
+	}
+
+	// Render the fields the instance groups inherit from the instanceGroupDefaults of the cluster
+	var withDefaults []*api.InstanceGroup
+	for _, ig := range instancegroups {
+		withDefaults = append(withDefaults, cluster.InstanceGroupWithDefaults(ig))
+	}
+	return t.Render(withDefaults, os.Stdout, columns...)
 }
 
 func int32PointerToString(v *int32) string {
@@ -0,0 +1,254 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	hibernateClusterLong = templates.LongDesc(i18n.T(`
+	Hibernate a cluster, for development clusters only used during working hours.
+
+	All the instance groups are scaled to zero, including the control plane, whose etcd volumes are kept.
+	With --nat-gateways, the NAT gateways created by kOps are deleted as well, keeping their Elastic IPs.
+	The hibernation is recorded in the cluster spec, so that kops update cluster keeps the instance groups
+	scaled to zero until the cluster is resumed with kops resume cluster.
+
+	Only clusters on AWS, updated without the terraform or cloudformation targets, can be hibernated.`))
+
+	hibernateClusterExample = templates.Examples(i18n.T(`
+	# Hibernate a cluster, deleting its NAT gateways
+	kops hibernate cluster k8s-cluster.example.com --nat-gateways --yes
+
+	# Resume the cluster
+	kops resume cluster k8s-cluster.example.com --yes
+	`))
+
+	hibernateClusterShort = i18n.T(`Scale a cluster to zero instances.`)
+)
+
+type HibernateClusterOptions struct {
+	ClusterName string
+
+	// NATGateways deletes the NAT gateways created by kOps while the cluster is hibernated
+	NATGateways bool
+	Yes         bool
+}
+
+func NewCmdHibernateCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &HibernateClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             hibernateClusterShort,
+		Long:              hibernateClusterLong,
+		Example:           hibernateClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(&rootCommand, true),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunHibernateCluster(context.TODO(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.NATGateways, "nat-gateways", options.NATGateways, "Delete the NAT gateways created by kOps, keeping their Elastic IPs")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Hibernate the cluster; without --yes the changes are only listed")
+
+	return cmd
+}
+
+func RunHibernateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *HibernateClusterOptions) error {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if kopsapi.CloudProviderID(cluster.Spec.CloudProvider) != kopsapi.CloudProviderAWS {
+		return fmt.Errorf("hibernation is only supported on AWS")
+	}
+	if err := checkClusterRunning(clientset, cluster); err != nil {
+		return err
+	}
+
+	instanceGroups, err := listInstanceGroups(ctx, clientset, cluster)
+	if err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		printInstanceGroups(out, "Will scale the instance groups to zero:", instanceGroups)
+		if options.NATGateways {
+			fmt.Fprintf(out, "Will delete the NAT gateways created by kOps\n")
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to hibernate the cluster\n")
+		return nil
+	}
+
+	cluster.Spec.Hibernation = &kopsapi.HibernationSpec{
+		NATGateways: options.NATGateways,
+	}
+	if err := commands.UpdateCluster(ctx, clientset, cluster, instanceGroups); err != nil {
+		return err
+	}
+
+	updateOptions := &UpdateClusterOptions{}
+	updateOptions.InitDefaults()
+	updateOptions.ClusterName = cluster.ObjectMeta.Name
+	updateOptions.Yes = true
+	if _, err := RunUpdateCluster(ctx, f, out, updateOptions); err != nil {
+		return err
+	}
+
+	if options.NATGateways {
+		cloud, err := cloudup.BuildCloud(cluster)
+		if err != nil {
+			return err
+		}
+		// The NAT gateways and the routes to them are no longer part of the cluster once it is updated
+		if err := deleteNATGateways(cloud.(awsup.AWSCloud), cluster.ObjectMeta.Name, out); err != nil {
+			return err
+		}
+	}
+
+	printResumeInstructions(out, cluster, "hibernated")
+	return nil
+}
+
+// listInstanceGroups returns the instance groups of the cluster
+func listInstanceGroups(ctx context.Context, clientset simple.Clientset, cluster *kopsapi.Cluster) ([]*kopsapi.InstanceGroup, error) {
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var instanceGroups []*kopsapi.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+	return instanceGroups, nil
+}
+
+// checkClusterRunning returns an error if the cluster is hibernated or shut down, as its instance groups
+// are already scaled to zero and their sizes would be lost
+func checkClusterRunning(clientset simple.Clientset, cluster *kopsapi.Cluster) error {
+	if cluster.Spec.Hibernation != nil {
+		return fmt.Errorf("cluster %q is hibernated; resume it with kops resume cluster first", cluster.ObjectMeta.Name)
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+	shutdown, err := instancegroups.ReadResumeManifest(configBase)
+	if err != nil {
+		return err
+	}
+	if shutdown != nil {
+		return fmt.Errorf("cluster %q is shut down; resume it with kops resume cluster first", cluster.ObjectMeta.Name)
+	}
+	return nil
+}
+
+// printInstanceGroups lists the instance groups affected by a command run without --yes
+func printInstanceGroups(out io.Writer, header string, instanceGroups []*kopsapi.InstanceGroup) {
+	fmt.Fprintf(out, "%s\n", header)
+	for _, ig := range instanceGroups {
+		fmt.Fprintf(out, "  %s\n", ig.ObjectMeta.Name)
+	}
+}
+
+// printResumeInstructions tells how to resume a cluster that was hibernated or shut down
+func printResumeInstructions(out io.Writer, cluster *kopsapi.Cluster, state string) {
+	fmt.Fprintf(out, "\nCluster %q is %s. Resume it with:\n", cluster.ObjectMeta.Name, state)
+	fmt.Fprintf(out, " * kops resume cluster %s --yes\n", cluster.ObjectMeta.Name)
+}
+
+// deleteNATGateways deletes the NAT gateways owned by the cluster, and the routes of the cluster to them
+func deleteNATGateways(cloud awsup.AWSCloud, clusterName string, out io.Writer) error {
+	response, err := cloud.EC2().DescribeNatGateways(&ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{
+			awsup.NewEC2Filter("tag:"+awsup.TagNameClusterOwnershipPrefix+clusterName, "owned"),
+			awsup.NewEC2Filter("state", ec2.NatGatewayStatePending, ec2.NatGatewayStateAvailable),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error listing NAT gateways: %v", err)
+	}
+
+	deleted := sets.NewString()
+	for _, ngw := range response.NatGateways {
+		id := aws.StringValue(ngw.NatGatewayId)
+		klog.V(2).Infof("deleting NAT gateway %q", id)
+		if _, err := cloud.EC2().DeleteNatGateway(&ec2.DeleteNatGatewayInput{NatGatewayId: ngw.NatGatewayId}); err != nil {
+			return fmt.Errorf("error deleting NAT gateway %q: %v", id, err)
+		}
+		fmt.Fprintf(out, "Deleted NAT gateway %s\n", id)
+		deleted.Insert(id)
+	}
+	if deleted.Len() == 0 {
+		return nil
+	}
+
+	routeTables, err := cloud.EC2().DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			awsup.NewEC2Filter("tag:"+awsup.TagClusterName, clusterName),
+			awsup.NewEC2Filter("route.nat-gateway-id", deleted.List()...),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error listing route tables: %v", err)
+	}
+	for _, rt := range routeTables.RouteTables {
+		for _, route := range rt.Routes {
+			if !deleted.Has(aws.StringValue(route.NatGatewayId)) {
+				continue
+			}
+			request := &ec2.DeleteRouteInput{
+				RouteTableId:             rt.RouteTableId,
+				DestinationCidrBlock:     route.DestinationCidrBlock,
+				DestinationIpv6CidrBlock: route.DestinationIpv6CidrBlock,
+			}
+			if _, err := cloud.EC2().DeleteRoute(request); err != nil {
+				return fmt.Errorf("error deleting route to NAT gateway %q from route table %q: %v", aws.StringValue(route.NatGatewayId), aws.StringValue(rt.RouteTableId), err)
+			}
+		}
+	}
+
+	return nil
+}
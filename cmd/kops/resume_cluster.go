@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	resumeClusterLong = templates.LongDesc(i18n.T(`
+	Resume a cluster hibernated with kops hibernate cluster or shut down with kops shutdown cluster.
+
+	The instance groups are scaled back to their sizes, the control plane reattaches its etcd volumes
+	and the NAT gateways deleted during the hibernation are recreated with their Elastic IPs.
+	A shut down cluster has its autoscaling groups restored as recorded in the state store, starting
+	with the control plane.`))
+
+	resumeClusterExample = templates.Examples(i18n.T(`
+	# Resume a cluster
+	kops resume cluster k8s-cluster.example.com --yes
+
+	# Wait for the cluster to become ready
+	kops validate cluster k8s-cluster.example.com --wait 10m
+	`))
+
+	resumeClusterShort = i18n.T(`Resume a hibernated cluster.`)
+)
+
+type ResumeClusterOptions struct {
+	ClusterName string
+	Yes         bool
+}
+
+func NewCmdResumeCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ResumeClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             resumeClusterShort,
+		Long:              resumeClusterLong,
+		Example:           resumeClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(&rootCommand, true),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunResumeCluster(context.TODO(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Resume the cluster; without --yes the changes are only listed")
+
+	return cmd
+}
+
+func RunResumeCluster(ctx context.Context, f *util.Factory, out io.Writer, options *ResumeClusterOptions) error {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+	shutdown, err := instancegroups.ReadResumeManifest(configBase)
+	if err != nil {
+		return err
+	}
+	if shutdown != nil {
+		return resumeShutdownCluster(cluster, configBase, shutdown, out, options)
+	}
+
+	if cluster.Spec.Hibernation == nil {
+		return fmt.Errorf("cluster %q is not hibernated or shut down", cluster.ObjectMeta.Name)
+	}
+
+	instanceGroups, err := listInstanceGroups(ctx, clientset, cluster)
+	if err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		printInstanceGroups(out, "Will scale the instance groups back to their sizes:", instanceGroups)
+		if cluster.Spec.Hibernation.NATGateways {
+			fmt.Fprintf(out, "Will recreate the NAT gateways\n")
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to resume the cluster\n")
+		return nil
+	}
+
+	cluster.Spec.Hibernation = nil
+	if err := commands.UpdateCluster(ctx, clientset, cluster, instanceGroups); err != nil {
+		return err
+	}
+
+	updateOptions := &UpdateClusterOptions{}
+	updateOptions.InitDefaults()
+	updateOptions.ClusterName = cluster.ObjectMeta.Name
+	updateOptions.Yes = true
+	if _, err := RunUpdateCluster(ctx, f, out, updateOptions); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nCluster %q is resuming. Wait for it to become ready with:\n", cluster.ObjectMeta.Name)
+	fmt.Fprintf(out, " * kops validate cluster %s --wait 10m\n", cluster.ObjectMeta.Name)
+	return nil
+}
+
+// resumeShutdownCluster restores the autoscaling groups of a cluster shut down with kops shutdown cluster
+func resumeShutdownCluster(cluster *kopsapi.Cluster, configBase vfs.Path, manifest *instancegroups.ResumeManifest, out io.Writer, options *ResumeClusterOptions) error {
+	if !options.Yes {
+		fmt.Fprintf(out, "Will scale the instance groups back to their sizes:\n")
+		for _, g := range manifest.Groups {
+			fmt.Fprintf(out, "  %s: %d instances\n", g.InstanceGroup, g.DesiredCapacity)
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to resume the cluster\n")
+		return nil
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+	if err := instancegroups.ResumeCluster(cloud.(awsup.AWSCloud), configBase, manifest); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nCluster %q is resuming. Wait for it to become ready with:\n", cluster.ObjectMeta.Name)
+	fmt.Fprintf(out, " * kops validate cluster %s --wait 10m\n", cluster.ObjectMeta.Name)
+	return nil
+}
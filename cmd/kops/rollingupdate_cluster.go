@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -36,8 +37,10 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/clusterevents"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/pkg/maintenancewindow"
 	"k8s.io/kops/pkg/pretty"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
@@ -58,9 +61,17 @@ var (
 	updated with the --force flag.  Rolling update drains and validates the cluster by default.  A cluster is
 	deemed validated when all required nodes are running and all pods with a critical priority are operational.
 
+	If the cluster has a maintenance window, rolling update refuses to start outside of it, and stops before
+	replacing another instance once it closes. Use --ignore-maintenance-window to update the cluster anyway.
+
 	Note: terraform users will need to run all of the following commands from the same directory
 	` + pretty.Bash("kops update cluster --target=terraform") + ` then ` + pretty.Bash("terraform plan") + ` then
-	` + pretty.Bash("terraform apply") + ` prior to running ` + pretty.Bash("kops rolling-update cluster") + `.`))
+	` + pretty.Bash("terraform apply") + ` prior to running ` + pretty.Bash("kops rolling-update cluster") + `.
+
+	The progress of a rolling update is recorded in the state store. If a rolling update is interrupted,
+	running it again resumes where it left off: instances that were already replaced are not replaced
+	again, even with --force, and the initial validation of partially updated instance groups is skipped.
+	Use --resume=false to discard the recorded progress.`))
 
 	rollingupdateExample = templates.Examples(i18n.T(`
 		# Preview a rolling update.
@@ -86,6 +97,11 @@ var (
 		# Update only the "nodes-1a" instance group of the k8s-cluster.example.com kOps cluster.
 		kops rolling-update cluster k8s-cluster.example.com --yes \
 		  --instance-group nodes-1a
+
+		# Update the k8s-cluster.example.com kOps cluster.
+		# Update up to three node instance groups at the same time.
+		kops rolling-update cluster k8s-cluster.example.com --yes \
+		  --parallelism 3
 		`))
 
 	rollingupdateShort = i18n.T(`Rolling update a cluster.`)
@@ -97,6 +113,9 @@ type RollingUpdateOptions struct {
 	Force     bool
 	CloudOnly bool
 
+	// IgnoreMaintenanceWindow updates the cluster outside of its maintenance window
+	IgnoreMaintenanceWindow bool
+
 	// The following two variables are when kOps is validating a cluster
 	// during a rolling update.
 
@@ -128,6 +147,12 @@ type RollingUpdateOptions struct {
 	// Interactive rolling-update prompts user to continue after each instances is updated.
 	Interactive bool
 
+	// Parallelism is the maximum number of node instance groups to update concurrently.
+	Parallelism int
+
+	// Resume continues an interrupted rolling-update from the progress recorded in the state store.
+	Resume bool
+
 	ClusterName string
 
 	// InstanceGroups is the list of instance groups to rolling-update;
@@ -150,6 +175,8 @@ func (o *RollingUpdateOptions) InitDefaults() {
 	o.NodeInterval = 15 * time.Second
 	o.BastionInterval = 15 * time.Second
 	o.Interactive = false
+	o.Resume = true
+	o.Parallelism = 1
 
 	o.PostDrainDelay = 5 * time.Second
 	o.ValidationTimeout = 15 * time.Minute
@@ -180,6 +207,7 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Perform rolling update immediately; without --yes rolling-update executes a dry-run")
 	cmd.Flags().BoolVar(&options.Force, "force", options.Force, "Force rolling update, even if no changes")
+	cmd.Flags().BoolVar(&options.IgnoreMaintenanceWindow, "ignore-maintenance-window", options.IgnoreMaintenanceWindow, "Perform rolling update outside of the maintenance window of the cluster")
 	cmd.Flags().BoolVar(&options.CloudOnly, "cloudonly", options.CloudOnly, "Perform rolling update without confirming progress with Kubernetes")
 
 	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for a cluster to validate")
@@ -188,7 +216,9 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().DurationVar(&options.NodeInterval, "node-interval", options.NodeInterval, "Time to wait between restarting worker nodes")
 	cmd.Flags().DurationVar(&options.BastionInterval, "bastion-interval", options.BastionInterval, "Time to wait between restarting bastions")
 	cmd.Flags().DurationVar(&options.PostDrainDelay, "post-drain-delay", options.PostDrainDelay, "Time to wait after draining each node")
-	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", options.Interactive, "Prompt to continue after each instance is updated")
+	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", options.Interactive, "Print the instances to replace, then prompt to approve each instance group and to continue after each instance is updated")
+	cmd.Flags().IntVar(&options.Parallelism, "parallelism", options.Parallelism, "Maximum number of node instance groups to update concurrently")
+	cmd.Flags().BoolVar(&options.Resume, "resume", options.Resume, "Resume an interrupted rolling update from the progress recorded in the state store")
 	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "Instance groups to update (defaults to all if not specified)")
 	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(&options.InstanceGroups, &options.InstanceGroupRoles))
 	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "Instance group roles to update ("+strings.Join(allRoles, ",")+")")
@@ -338,6 +368,7 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		PostDrainDelay:    options.PostDrainDelay,
 		ValidationTimeout: options.ValidationTimeout,
 		ValidateCount:     int(options.ValidateCount),
+		Parallelism:       options.Parallelism,
 		// TODO should we expose this to the UI?
 		ValidateTickDuration:    30 * time.Second,
 		ValidateSuccessDuration: 10 * time.Second,
@@ -412,11 +443,46 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		return nil
 	}
 
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+	d.Checkpoint = instancegroups.NewCheckpoint(configBase)
+	if options.Resume {
+		resumed, err := d.Checkpoint.Load()
+		if err != nil {
+			return err
+		}
+		if len(resumed) > 0 {
+			fmt.Fprintf(out, "\nResuming interrupted rolling-update of instance groups: %s\n", strings.Join(resumed, ", "))
+		}
+	}
+
+	if options.Interactive {
+		if err := printRollingUpdatePlan(out, d, groups); err != nil {
+			return err
+		}
+	}
+
 	if !options.Yes {
 		fmt.Printf("\nMust specify --yes to rolling-update.\n")
 		return nil
 	}
 
+	if !options.IgnoreMaintenanceWindow {
+		window, err := checkMaintenanceWindow(cluster, time.Now())
+		if err != nil {
+			return err
+		}
+		d.MaintenanceWindow = window
+	}
+
+	if !options.Resume {
+		if err := d.Checkpoint.Reset(); err != nil {
+			return err
+		}
+	}
+
 	var clusterValidator validation.ClusterValidator
 	if !options.CloudOnly {
 		clusterValidator, err = validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient)
@@ -426,7 +492,69 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 	}
 	d.ClusterValidator = clusterValidator
 
-	return d.RollingUpdate(groups, list)
+	var groupNames []string
+	for name, group := range groups {
+		if len(group.NeedUpdate) != 0 || options.Force {
+			groupNames = append(groupNames, name)
+		}
+	}
+	sort.Strings(groupNames)
+	details := map[string]string{
+		"instanceGroups": strings.Join(groupNames, ","),
+	}
+
+	started := clusterevents.NewEvent(cluster, clusterevents.RollingUpdateStarted, "rolling update started")
+	started.Details = details
+	publishClusterEvent(ctx, cluster, cloud, started)
+
+	err = d.RollingUpdate(groups, list)
+
+	finished := clusterevents.NewEvent(cluster, clusterevents.RollingUpdateFinished, "rolling update finished")
+	finished.Details = details
+	if err != nil {
+		finished.Message = "rolling update failed"
+		finished.Error = err.Error()
+	}
+	publishClusterEvent(ctx, cluster, cloud, finished)
+
+	return err
+}
+
+// printRollingUpdatePlan prints the instances the rolling update will replace, in order.
+func printRollingUpdatePlan(out io.Writer, d *instancegroups.RollingUpdateCluster, groups map[string]*cloudinstances.CloudInstanceGroup) error {
+	plan, err := d.Plan(groups)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nInstances will be replaced in the following order:\n\n")
+	order := make(map[*instancegroups.PlannedReplacement]int)
+	for i, r := range plan {
+		order[r] = i + 1
+	}
+
+	t := &tables.Table{}
+	// Right-align the order, so the table sorts in replacement order
+	width := len(strconv.Itoa(len(plan)))
+	t.AddColumn("#", func(r *instancegroups.PlannedReplacement) string {
+		return fmt.Sprintf("%*d", width, order[r])
+	})
+	t.AddColumn("INSTANCEGROUP", func(r *instancegroups.PlannedReplacement) string {
+		return r.Instance.CloudInstanceGroup.InstanceGroup.ObjectMeta.Name
+	})
+	t.AddColumn("INSTANCE", func(r *instancegroups.PlannedReplacement) string {
+		return r.Instance.ID
+	})
+	t.AddColumn("NODE", func(r *instancegroups.PlannedReplacement) string {
+		if r.Instance.Node == nil {
+			return ""
+		}
+		return r.Instance.Node.Name
+	})
+	t.AddColumn("REASON", func(r *instancegroups.PlannedReplacement) string {
+		return r.Reason
+	})
+	return t.Render(plan, out, "#", "INSTANCEGROUP", "INSTANCE", "NODE", "REASON")
 }
 
 func completeInstanceGroup(selectedInstanceGroups *[]string, selectedInstanceGroupRoles *[]string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -462,3 +590,24 @@ func completeInstanceGroup(selectedInstanceGroups *[]string, selectedInstanceGro
 		return igs, cobra.ShellCompDirectiveNoFileComp
 	}
 }
+
+// checkMaintenanceWindow returns the maintenance window of the cluster, or nil if it has none.
+// It returns an error if the window is closed at now.
+func checkMaintenanceWindow(cluster *kopsapi.Cluster, now time.Time) (*maintenancewindow.Window, error) {
+	spec := cluster.Spec.MaintenanceWindow
+	if spec == nil || spec.Duration == nil {
+		return nil, nil
+	}
+	window, err := maintenancewindow.New(spec.Schedule, spec.Duration.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window: %v", err)
+	}
+	if window.Contains(now) {
+		return window, nil
+	}
+	next := window.Next(now)
+	if next.IsZero() {
+		return nil, fmt.Errorf("the maintenance window of cluster %q never opens; use --ignore-maintenance-window to update the cluster anyway", cluster.ObjectMeta.Name)
+	}
+	return nil, fmt.Errorf("the maintenance window of cluster %q is closed until %s; use --ignore-maintenance-window to update the cluster now", cluster.ObjectMeta.Name, next.Format(time.RFC3339))
+}
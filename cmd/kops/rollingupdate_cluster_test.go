@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+)
+
+func TestCheckMaintenanceWindow(t *testing.T) {
+	cluster := &kopsapi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+	}
+	saturdayNight := time.Date(2021, 6, 5, 23, 0, 0, 0, time.UTC)
+	monday := time.Date(2021, 6, 7, 12, 0, 0, 0, time.UTC)
+
+	if window, err := checkMaintenanceWindow(cluster, monday); err != nil || window != nil {
+		t.Errorf("unexpected result without a maintenance window: %v, %v", window, err)
+	}
+
+	cluster.Spec.MaintenanceWindow = &kopsapi.MaintenanceWindowSpec{
+		Schedule: "0 22 * * SAT",
+		Duration: &metav1.Duration{Duration: 4 * time.Hour},
+	}
+	window, err := checkMaintenanceWindow(cluster, saturdayNight)
+	if err != nil {
+		t.Errorf("unexpected error inside the maintenance window: %v", err)
+	}
+	if window == nil || !window.Contains(saturdayNight) {
+		t.Errorf("expected the maintenance window to be returned")
+	}
+
+	_, err = checkMaintenanceWindow(cluster, monday)
+	if err == nil {
+		t.Fatalf("expected an error outside the maintenance window")
+	}
+	if !strings.Contains(err.Error(), "closed until 2021-06-12T22:00:00Z") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
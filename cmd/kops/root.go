@@ -37,6 +37,7 @@ import (
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/tracing"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 )
@@ -94,7 +95,14 @@ var rootCommand = RootCmd{
 func Execute() {
 	goflag.Set("logtostderr", "true")
 	goflag.CommandLine.Parse([]string{})
-	if err := rootCommand.cobraCommand.Execute(); err != nil {
+	if err := tracing.Init("kops"); err != nil {
+		klog.Warningf("unable to initialize tracing: %v", err)
+	}
+	err := rootCommand.cobraCommand.Execute()
+	if err := tracing.Flush(context.Background()); err != nil {
+		klog.Warningf("unable to export traces: %v", err)
+	}
+	if err != nil {
 		exitWithError(err)
 	}
 }
@@ -143,18 +151,24 @@ func NewCmdRoot(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.RegisterFlagCompletionFunc("name", commandutils.CompleteClusterName(&rootCommand, false))
 
 	// create subcommands
+	cmd.AddCommand(NewCmdClone(f, out))
 	cmd.AddCommand(NewCmdCreate(f, out))
 	cmd.AddCommand(NewCmdDelete(f, out))
 	cmd.AddCommand(NewCmdDistrust(f, out))
 	cmd.AddCommand(NewCmdEdit(f, out))
 	cmd.AddCommand(NewCmdExport(f, out))
+	cmd.AddCommand(NewCmdFleet(f, out))
 	cmd.AddCommand(NewCmdGet(f, out))
 	cmd.AddCommand(commands.NewCmdHelpers(f, out))
+	cmd.AddCommand(NewCmdHibernate(f, out))
 	cmd.AddCommand(NewCmdPromote(f, out))
 	cmd.AddCommand(NewCmdUpdate(f, out))
 	cmd.AddCommand(NewCmdReplace(f, out))
+	cmd.AddCommand(NewCmdResume(f, out))
 	cmd.AddCommand(NewCmdRollingUpdate(f, out))
 	cmd.AddCommand(NewCmdSet(f, out))
+	cmd.AddCommand(NewCmdShutdown(f, out))
+	cmd.AddCommand(NewCmdSSH(f, out))
 	cmd.AddCommand(NewCmdToolbox(f, out))
 	cmd.AddCommand(NewCmdUnset(f, out))
 	cmd.AddCommand(NewCmdUpgrade(f, out))
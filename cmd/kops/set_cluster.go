@@ -35,11 +35,17 @@ var (
 
         This command changes the desired cluster configuration in the registry.
 
+        Values for list, map and object fields may be given as JSON. A JSON array replaces the
+        current list; a JSON object is applied as a merge patch, so that keys set to null are removed.
+
         kops set does not update the cloud resources; to apply the changes use "kops update cluster".`))
 
 	setClusterExample = templates.Examples(i18n.T(`
 		# Set cluster to run kubernetes version 1.17.0
 		kops set cluster k8s.cluster.site spec.kubernetesVersion=1.17.0
+
+		# Set a kubelet feature gate, keeping the other kubelet settings
+		kops set cluster k8s.cluster.site 'spec.kubelet={"featureGates":{"EphemeralContainers":"true"}}'
 	`))
 )
 
@@ -35,11 +35,25 @@ var (
 
 		This command changes the desired instance group configuration in the registry.
 
+        With --selector, the fields are set on all the instance groups that match a label selector.
+        Besides their own labels, instance groups match the "name" and "role" labels, set to their
+        name and role (Master, Node, Bastion or APIServer). The instance groups are only updated if
+        all of them are valid after the change.
+
+        Values for list, map and object fields may be given as JSON. A JSON array replaces the
+        current list; a JSON object is applied as a merge patch, so that keys set to null are removed.
+
         kops set does not update the cloud resources; to apply the changes use "kops update cluster".`))
 
 	setInstancegroupExample = templates.Examples(i18n.T(`
 		# Set instance group to run image custom-ami-image
 		kops set instancegroup --name k8s-cluster.example.com nodes spec.image=custom-ami-image
+
+		# Set the machine type of all node instance groups
+		kops set instancegroup --name k8s-cluster.example.com --selector role=Node spec.machineType=m5.large
+
+		# Set the instance types of a mixed instances policy
+		kops set instancegroup --name k8s-cluster.example.com nodes 'spec.mixedInstancesPolicy.instances=["m5.large","m5a.large"]'
 	`))
 )
 
@@ -59,7 +73,7 @@ func NewCmdSetInstancegroup(f *util.Factory, out io.Writer) *cobra.Command {
 			for i, arg := range args {
 				index := strings.Index(arg, "=")
 
-				if i == 0 {
+				if i == 0 && options.Selector == "" {
 					if index != -1 {
 						exitWithError(fmt.Errorf("Specify name of instance group to edit"))
 					}
@@ -81,5 +95,7 @@ func NewCmdSetInstancegroup(f *util.Factory, out io.Writer) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVarP(&options.Selector, "selector", "l", options.Selector, "Label selector of the instance groups to set the fields of, instead of an instance group name")
+
 	return cmd
 }
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	shutdownClusterLong = templates.LongDesc(i18n.T(`
+	Shut down a cluster gracefully, so that it can be resumed as it was.
+
+	The operations are done in order:
+
+	1. The nodes are drained.
+	2. The etcd volumes are snapshotted.
+	3. The sizes and suspended processes of the autoscaling groups are recorded in the state store.
+	4. The autoscaling groups are scaled to zero, with the control plane last.
+	5. The autoscaling processes that could start instances are suspended.
+
+	The cluster is resumed with kops resume cluster, which restores the recorded autoscaling groups.
+	kops update cluster refuses to update a shut down cluster.
+
+	Only clusters on AWS can be shut down.`))
+
+	shutdownClusterExample = templates.Examples(i18n.T(`
+	# Shut down a cluster
+	kops shutdown cluster k8s-cluster.example.com --yes
+
+	# Resume the cluster
+	kops resume cluster k8s-cluster.example.com --yes
+	`))
+
+	shutdownClusterShort = i18n.T(`Drain and scale a cluster to zero instances, recording how to resume it.`)
+)
+
+type ShutdownClusterOptions struct {
+	ClusterName string
+	Yes         bool
+
+	// CloudOnly skips draining the nodes
+	CloudOnly bool
+
+	// FailOnDrainError stops the shutdown if a node fails to drain
+	FailOnDrainError bool
+
+	// PostDrainDelay is the duration of a pause after draining each node
+	PostDrainDelay time.Duration
+}
+
+func (o *ShutdownClusterOptions) InitDefaults() {
+	o.PostDrainDelay = 5 * time.Second
+}
+
+func NewCmdShutdownCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ShutdownClusterOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             shutdownClusterShort,
+		Long:              shutdownClusterLong,
+		Example:           shutdownClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(&rootCommand, true),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunShutdownCluster(context.TODO(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Shut down the cluster; without --yes the instance groups are only listed")
+	cmd.Flags().BoolVar(&options.CloudOnly, "cloudonly", options.CloudOnly, "Shut down the cluster without draining the nodes through the Kubernetes API")
+	cmd.Flags().BoolVar(&options.FailOnDrainError, "fail-on-drain-error", options.FailOnDrainError, "Stop the shutdown if a node fails to drain")
+	cmd.Flags().DurationVar(&options.PostDrainDelay, "post-drain-delay", options.PostDrainDelay, "Time to wait after draining each node")
+
+	return cmd
+}
+
+func RunShutdownCluster(ctx context.Context, f *util.Factory, out io.Writer, options *ShutdownClusterOptions) error {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if kopsapi.CloudProviderID(cluster.Spec.CloudProvider) != kopsapi.CloudProviderAWS {
+		return fmt.Errorf("shutdown is only supported on AWS")
+	}
+
+	if err := checkClusterRunning(clientset, cluster); err != nil {
+		return err
+	}
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	instanceGroups, err := listInstanceGroups(ctx, clientset, cluster)
+	if err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		printInstanceGroups(out, "Will drain the nodes, snapshot the etcd volumes and scale the instance groups to zero:", instanceGroups)
+		fmt.Fprintf(out, "\nMust specify --yes to shut down the cluster\n")
+		return nil
+	}
+
+	var nodes []v1.Node
+	var k8sClient kubernetes.Interface
+	if !options.CloudOnly {
+		contextName := cluster.ObjectMeta.Name
+		clientGetter := genericclioptions.NewConfigFlags(true)
+		clientGetter.Context = &contextName
+
+		config, err := clientGetter.ToRESTConfig()
+		if err != nil {
+			return fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+		}
+		k8sClient, err = kubernetes.NewForConfig(config)
+		if err != nil {
+			return fmt.Errorf("cannot build kube client for %q: %v", contextName, err)
+		}
+
+		nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to reach the kubernetes API.\n")
+			fmt.Fprintf(os.Stderr, "Use --cloudonly to shut down the cluster without draining the nodes\n\n")
+			return fmt.Errorf("error listing nodes in cluster: %v", err)
+		}
+		nodes = nodeList.Items
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	groups, err := cloud.GetCloudGroups(cluster, instanceGroups, true, nodes)
+	if err != nil {
+		return err
+	}
+
+	d := &instancegroups.RollingUpdateCluster{
+		Clientset:        clientset,
+		Ctx:              ctx,
+		Cluster:          cluster,
+		Cloud:            cloud,
+		K8sClient:        k8sClient,
+		FailOnDrainError: options.FailOnDrainError,
+		CloudOnly:        options.CloudOnly,
+		ClusterName:      options.ClusterName,
+		PostDrainDelay:   options.PostDrainDelay,
+	}
+	manifest, err := d.ShutdownCluster(groups, configBase)
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range manifest.EtcdSnapshots {
+		fmt.Fprintf(out, "Snapshotted etcd volume to %s\n", snapshot)
+	}
+	printResumeInstructions(out, cluster, "shut down")
+	return nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	sshLong = templates.LongDesc(i18n.T(`
+	Open a shell on an instance of a cluster using AWS Systems Manager Session Manager.
+
+	The cluster must have sessionManager enabled, which starts the SSM agent on the instances
+	and grants them the permissions it needs. No bastion, public address or SSH key is required.
+
+	The instance is specified by its ID or by the name of its node. This command runs
+	"aws ssm start-session", so the AWS CLI and its Session Manager plugin must be installed.`))
+
+	sshExample = templates.Examples(i18n.T(`
+	# Open a shell on an instance
+	kops ssh i-0a5ed581b862d3425 --name k8s-cluster.example.com
+
+	# Open a shell on the instance of a node
+	kops ssh ip-172-20-35-115.ec2.internal --name k8s-cluster.example.com
+	`))
+
+	sshShort = i18n.T(`Open a shell on an instance using Session Manager.`)
+)
+
+// SSHOptions is the options for the ssh command
+type SSHOptions struct {
+	ClusterName string
+	Instance    string
+}
+
+func NewCmdSSH(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &SSHOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "ssh INSTANCE|NODE",
+		Short:   sshShort,
+		Long:    sshLong,
+		Example: sshExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			if len(args) != 1 {
+				return fmt.Errorf("must specify the ID of an instance or the name of a node")
+			}
+			options.Instance = args[0]
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunSSH(context.TODO(), f, out, options)
+		},
+	}
+
+	return cmd
+}
+
+func RunSSH(ctx context.Context, f *util.Factory, out io.Writer, options *SSHOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if !model.UseSessionManager(cluster) {
+		return fmt.Errorf("cluster %q does not have sessionManager enabled", cluster.ObjectMeta.Name)
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+	awsCloud, ok := cloud.(awsup.AWSCloud)
+	if !ok {
+		return fmt.Errorf("kops ssh is only supported on AWS")
+	}
+
+	instanceID, err := findSSHInstance(awsCloud, cluster.ObjectMeta.Name, options.Instance)
+	if err != nil {
+		return err
+	}
+
+	awsPath, err := exec.LookPath("aws")
+	if err != nil {
+		return fmt.Errorf("the AWS CLI is required by kops ssh: %v", err)
+	}
+
+	klog.V(2).Infof("Starting session on instance %q", instanceID)
+	cmd := exec.CommandContext(ctx, awsPath, "ssm", "start-session", "--target", instanceID, "--region", awsCloud.Region())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// findSSHInstance returns the ID of a running instance of the cluster, which is specified by its ID or the name of its node.
+func findSSHInstance(cloud awsup.AWSCloud, clusterName string, instance string) (string, error) {
+	request := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			awsup.NewEC2Filter("tag:"+awsup.TagClusterName, clusterName),
+			awsup.NewEC2Filter("instance-state-name", "running"),
+		},
+	}
+	if strings.HasPrefix(instance, "i-") {
+		request.InstanceIds = []*string{aws.String(instance)}
+	} else {
+		request.Filters = append(request.Filters, awsup.NewEC2Filter("private-dns-name", instance))
+	}
+
+	var instanceIDs []string
+	err := cloud.EC2().DescribeInstancesPages(request, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, i := range reservation.Instances {
+				instanceIDs = append(instanceIDs, aws.StringValue(i.InstanceId))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return "", fmt.Errorf("error finding instance %q: %v", instance, err)
+	}
+
+	switch len(instanceIDs) {
+	case 0:
+		return "", fmt.Errorf("could not find a running instance %q in cluster %q", instance, clusterName)
+	case 1:
+		return instanceIDs[0], nil
+	default:
+		return "", fmt.Errorf("found multiple instances matching %q: %s", instance, strings.Join(instanceIDs, ", "))
+	}
+}
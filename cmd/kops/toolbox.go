@@ -37,6 +37,9 @@ var (
 	toolboxShort = i18n.T(`Misc infrequently used commands.`)
 )
 
+// optionalToolboxCommands are the toolbox commands that are only built with a build tag.
+var optionalToolboxCommands []func(f *util.Factory, out io.Writer) *cobra.Command
+
 func NewCmdToolbox(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "toolbox",
@@ -45,9 +48,24 @@ func NewCmdToolbox(f *util.Factory, out io.Writer) *cobra.Command {
 		Example: toolboxExample,
 	}
 
+	cmd.AddCommand(NewCmdToolboxCheckDeprecations(f, out))
 	cmd.AddCommand(NewCmdToolboxDump(f, out))
+	cmd.AddCommand(NewCmdToolboxEnroll(f, out))
 	cmd.AddCommand(NewCmdToolboxTemplate(f, out))
 	cmd.AddCommand(NewCmdToolboxInstanceSelector(f, out))
+	cmd.AddCommand(NewCmdToolboxCloneCluster(f, out))
+	cmd.AddCommand(NewCmdToolboxImport(f, out))
+	cmd.AddCommand(NewCmdToolboxPatchNodes(f, out))
+	cmd.AddCommand(NewCmdToolboxTunnel(f, out))
+	cmd.AddCommand(NewCmdToolboxBuildImage(f, out))
+	cmd.AddCommand(NewCmdToolboxRotateSSHKey(f, out))
+	cmd.AddCommand(NewCmdToolboxKillNode(f, out))
+	cmd.AddCommand(NewCmdToolboxRenderUserData(f, out))
+	cmd.AddCommand(NewCmdToolboxSpotReport(f, out))
+
+	for _, newCmd := range optionalToolboxCommands {
+		cmd.AddCommand(newCmd(f, out))
+	}
 
 	return cmd
 }
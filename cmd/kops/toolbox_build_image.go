@@ -0,0 +1,339 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/imagebake"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/distributions"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+const (
+	// BuildImageFormatPacker writes a Packer template
+	BuildImageFormatPacker = "packer"
+	// BuildImageFormatImageBuilder writes an EC2 Image Builder component
+	BuildImageFormatImageBuilder = "image-builder"
+)
+
+var (
+	toolboxBuildImageLong = templates.LongDesc(i18n.T(`
+	Write the templates that bake the software and the settings of an instance group into an AMI,
+	so that nodeup only has to configure the instances minimally at boot.
+
+	The image is derived from the image of the instance group, and is provisioned with the containerd
+	version, the kernel modules and the sysctls of the cluster spec and the instance group spec.
+	Either a Packer template (--format packer), which can be built directly with --build, or an
+	EC2 Image Builder component (--format image-builder) is written, along with the provisioning script.
+
+	With --prepull-images, the container images that run on the instances of the instance group,
+	as listed by kops get assets, are pulled into the image store of containerd, so that the
+	instances start their pods without downloading them.
+
+	The built AMI is then used by setting the image of the instance group, which --pin-image does
+	once Packer has built it. The AMI must be rebuilt when the containerd version, the kernel
+	modules, the sysctls or the images change, for example when upgrading the cluster.`))
+
+	toolboxBuildImageExample = templates.Examples(i18n.T(`
+	# Build an AMI for the nodes instance group with Packer
+	kops toolbox build-image --name k8s-cluster.example.com --instance-group nodes --out image --build
+
+	# Build an AMI with the images of the cluster pre-pulled, and use it for the nodes instance group
+	kops toolbox build-image --name k8s-cluster.example.com --instance-group nodes --out image --build \
+	  --prepull-images --pin-image
+
+	# Write an EC2 Image Builder component for the nodes instance group
+	kops toolbox build-image --name k8s-cluster.example.com --instance-group nodes --format image-builder --out image
+	`))
+
+	toolboxBuildImageShort = i18n.T(`Build an image for the instances of an instance group`)
+)
+
+type ToolboxBuildImageOptions struct {
+	ClusterName   string
+	InstanceGroup string
+
+	Format       string
+	OutDir       string
+	InstanceType string
+	SSHUser      string
+	Build        bool
+
+	// PrePullImages bakes the container images of the cluster into the image
+	PrePullImages bool
+	// PinImage sets the image of the instance group to the built AMI
+	PinImage bool
+}
+
+func (o *ToolboxBuildImageOptions) InitDefaults() {
+	o.Format = BuildImageFormatPacker
+	o.OutDir = "image"
+}
+
+func NewCmdToolboxBuildImage(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBuildImageOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "build-image",
+		Short:   toolboxBuildImageShort,
+		Long:    toolboxBuildImageLong,
+		Example: toolboxBuildImageExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err := RunToolboxBuildImage(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "Instance group to build the image for")
+	cmd.Flags().StringVar(&options.Format, "format", options.Format, "Format of the template: packer or image-builder")
+	cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Directory to write the template and the provisioning script to")
+	cmd.Flags().StringVar(&options.InstanceType, "instance-type", options.InstanceType, "Instance type to build the image on (defaults to the machine type of the instance group)")
+	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "User Packer connects to the build instance as (defaults to the default user of the image)")
+	cmd.Flags().BoolVar(&options.Build, "build", options.Build, "Run packer build on the template (packer format only)")
+	cmd.Flags().BoolVar(&options.PrePullImages, "prepull-images", options.PrePullImages, "Pre-pull the container images that run on the instance group into the image (containerd only)")
+	cmd.Flags().BoolVar(&options.PinImage, "pin-image", options.PinImage, "Set the image of the instance group to the built AMI (requires --build)")
+
+	return cmd
+}
+
+func RunToolboxBuildImage(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxBuildImageOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+	if options.InstanceGroup == "" {
+		return fmt.Errorf("--instance-group is required")
+	}
+	if options.Format != BuildImageFormatPacker && options.Format != BuildImageFormatImageBuilder {
+		return fmt.Errorf("unknown format %q; supported formats are %s and %s", options.Format, BuildImageFormatPacker, BuildImageFormatImageBuilder)
+	}
+	if options.Build && options.Format != BuildImageFormatPacker {
+		return fmt.Errorf("--build is only supported with format %s", BuildImageFormatPacker)
+	}
+	if options.PinImage && !options.Build {
+		return fmt.Errorf("--pin-image requires --build")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, options.InstanceGroup, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading instance group %q: %v", options.InstanceGroup, err)
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+	awsCloud, ok := cloud.(awsup.AWSCloud)
+	if !ok {
+		return fmt.Errorf("kops toolbox build-image is only supported on AWS")
+	}
+
+	channel, err := cloudup.ChannelForCluster(cluster)
+	if err != nil {
+		klog.Warningf("%v", err)
+	}
+	fullGroup, err := cloudup.PopulateInstanceGroupSpec(cluster, ig, cloud, channel)
+	if err != nil {
+		return err
+	}
+
+	if err := cloudup.PerformAssignments(cluster, cloud); err != nil {
+		return fmt.Errorf("error populating configuration: %v", err)
+	}
+	assetBuilder := assets.NewAssetBuilder(cluster, false)
+	fullCluster, err := cloudup.PopulateClusterSpec(clientset, cluster, cloud, assetBuilder)
+	if err != nil {
+		return err
+	}
+
+	var containerdURL *url.URL
+	var containerdHash *hashing.Hash
+	if fullCluster.Spec.ContainerRuntime == "containerd" {
+		arch, err := cloudup.MachineArchitecture(cloud, fullGroup.Spec.MachineType)
+		if err != nil {
+			return err
+		}
+		containerdURL, containerdHash, err = cloudup.FindContainerdAsset(fullCluster, assetBuilder, arch)
+		if err != nil {
+			return err
+		}
+	}
+
+	recipe, err := imagebake.NewRecipe(fullCluster, fullGroup, containerdURL, containerdHash)
+	if err != nil {
+		return err
+	}
+
+	if options.PrePullImages {
+		if containerdURL == nil {
+			return fmt.Errorf("--prepull-images requires the containerd container runtime")
+		}
+		updateClusterResults, err := RunUpdateCluster(ctx, f, out, &UpdateClusterOptions{
+			Target:      cloudup.TargetDryRun,
+			GetAssets:   true,
+			ClusterName: options.ClusterName,
+		})
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool)
+		var images []string
+		for _, imageAsset := range updateClusterResults.ImageAssets {
+			if !seen[imageAsset.DownloadLocation] {
+				images = append(images, imageAsset.DownloadLocation)
+				seen[imageAsset.DownloadLocation] = true
+			}
+		}
+		recipe.Images = imagebake.ImagesForRole(images, fullGroup.Spec.Role)
+		klog.Infof("Pre-pulling %d images into the image of instance group %q", len(recipe.Images), fullGroup.ObjectMeta.Name)
+	}
+
+	var templateFile string
+	var template []byte
+	switch options.Format {
+	case BuildImageFormatPacker:
+		instanceType := options.InstanceType
+		if instanceType == "" {
+			instanceType = fullGroup.Spec.MachineType
+		}
+		sshUser := options.SSHUser
+		if sshUser == "" {
+			distribution, found := distributions.FindDistributionFamilyForImage(fullGroup.Spec.Image)
+			if !found {
+				return fmt.Errorf("unable to determine the default user of image %q; use --ssh-user", fullGroup.Spec.Image)
+			}
+			users, err := distribution.DefaultUsers()
+			if err != nil {
+				return err
+			}
+			sshUser = users[0]
+		}
+		image, err := awsCloud.ResolveImage(fullGroup.Spec.Image)
+		if err != nil {
+			return err
+		}
+		templateFile = imagebake.PackerTemplateFile
+		template, err = recipe.PackerTemplate(awsCloud.Region(), aws.StringValue(image.ImageId), instanceType, sshUser)
+		if err != nil {
+			return err
+		}
+	case BuildImageFormatImageBuilder:
+		templateFile = imagebake.ImageBuilderComponentFile
+		template, err = recipe.ImageBuilderComponent()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(options.OutDir, 0755); err != nil {
+		return fmt.Errorf("error creating directory %q: %v", options.OutDir, err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(options.OutDir, imagebake.ProvisionScriptFile), []byte(recipe.ProvisionScript()), 0755); err != nil {
+		return fmt.Errorf("error writing provisioning script: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(options.OutDir, templateFile), template, 0644); err != nil {
+		return fmt.Errorf("error writing template: %v", err)
+	}
+	fmt.Fprintf(out, "Wrote %s and %s to %s\n", templateFile, imagebake.ProvisionScriptFile, options.OutDir)
+
+	if !options.Build {
+		switch options.Format {
+		case BuildImageFormatPacker:
+			fmt.Fprintf(out, "Build the image with:\n  (cd %s && packer build %s)\n", options.OutDir, templateFile)
+		case BuildImageFormatImageBuilder:
+			fmt.Fprintf(out, "Create an EC2 Image Builder recipe with the component and the parent image %q\n", fullGroup.Spec.Image)
+		}
+		return nil
+	}
+
+	packerPath, err := exec.LookPath("packer")
+	if err != nil {
+		return fmt.Errorf("packer is required to build the image: %v", err)
+	}
+	cmd := exec.CommandContext(ctx, packerPath, "build", templateFile)
+	cmd.Dir = options.OutDir
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	klog.V(2).Infof("Running %v", cmd.Args)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error building image: %v", err)
+	}
+
+	manifest, err := ioutil.ReadFile(filepath.Join(options.OutDir, imagebake.PackerManifestFile))
+	if err != nil {
+		return fmt.Errorf("error reading Packer manifest: %v", err)
+	}
+	ami, err := imagebake.ParsePackerManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	if !options.PinImage {
+		fmt.Fprintf(out, "\nUse the image by setting spec.image of instance group %q to %s\n", ig.ObjectMeta.Name, ami)
+		return nil
+	}
+
+	// Read the instance group again, as it may have been changed while the image was built
+	ig, err = clientset.InstanceGroupsFor(cluster).Get(ctx, ig.ObjectMeta.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading instance group %q: %v", options.InstanceGroup, err)
+	}
+	ig.Spec.Image = ami
+	if _, err := clientset.InstanceGroupsFor(cluster).Update(ctx, ig, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating instance group %q: %v", ig.ObjectMeta.Name, err)
+	}
+	fmt.Fprintf(out, "\nSet the image of instance group %q to %s; apply the change with kops update cluster and kops rolling-update cluster\n", ig.ObjectMeta.Name, ami)
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kops/cmd/kops/util"
+	kopsutil "k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/deprecations"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxCheckDeprecationsLong = templates.LongDesc(i18n.T(`
+	Check a cluster for usage of APIs that are removed in a Kubernetes version.
+
+	An API is reported as in use if the API server has served requests for it since it
+	started, or if objects were last applied by kubectl using it. Clients and manifests using
+	the reported APIs must be updated to their replacements before upgrading the cluster.
+
+	The command fails if any removed API is in use. By default the cluster is checked against
+	the Kubernetes minor release following its current kubernetesVersion.`))
+
+	toolboxCheckDeprecationsExample = templates.Examples(i18n.T(`
+	# Check the cluster is ready to be upgraded to the next minor release
+	kops toolbox check-deprecations --name k8s-cluster.example.com
+
+	# Check the cluster is ready to be upgraded to a specific version
+	kops toolbox check-deprecations --name k8s-cluster.example.com --kubernetes-version 1.22.2
+	`))
+
+	toolboxCheckDeprecationsShort = i18n.T(`Check a cluster for usage of removed Kubernetes APIs`)
+)
+
+type ToolboxCheckDeprecationsOptions struct {
+	ClusterName string
+
+	// KubernetesVersion is the version to check against. Defaults to the minor release after the version of the cluster.
+	KubernetesVersion string
+}
+
+func NewCmdToolboxCheckDeprecations(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxCheckDeprecationsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "check-deprecations",
+		Short:   toolboxCheckDeprecationsShort,
+		Long:    toolboxCheckDeprecationsLong,
+		Example: toolboxCheckDeprecationsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err := RunToolboxCheckDeprecations(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.KubernetesVersion, "kubernetes-version", options.KubernetesVersion, "Kubernetes version to check against (defaults to the minor release after the version of the cluster)")
+	cmd.RegisterFlagCompletionFunc("kubernetes-version", completeKubernetesVersion)
+
+	return cmd
+}
+
+func RunToolboxCheckDeprecations(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxCheckDeprecationsOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	var target *semver.Version
+	if options.KubernetesVersion != "" {
+		target, err = kopsutil.ParseKubernetesVersion(options.KubernetesVersion)
+		if err != nil {
+			return fmt.Errorf("unable to parse --kubernetes-version %q: %v", options.KubernetesVersion, err)
+		}
+	} else {
+		current, err := kopsutil.ParseKubernetesVersion(cluster.Spec.KubernetesVersion)
+		if err != nil {
+			return fmt.Errorf("unable to parse kubernetesVersion %q of cluster, specify --kubernetes-version: %v", cluster.Spec.KubernetesVersion, err)
+		}
+		target = &semver.Version{Major: current.Major, Minor: current.Minor + 1}
+	}
+
+	findings, err := scanRemovedAPIs(ctx, cluster.ObjectMeta.Name, *target)
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		fmt.Fprintf(out, "No APIs removed in Kubernetes %s are in use\n", target)
+		return nil
+	}
+
+	if err := renderRemovedAPIs(out, findings); err != nil {
+		return err
+	}
+	return fmt.Errorf("%d APIs removed in Kubernetes %s are in use", len(findings), target)
+}
+
+// scanRemovedAPIs finds the APIs removed in the target Kubernetes version that are in use in the cluster
+func scanRemovedAPIs(ctx context.Context, contextName string, target semver.Version) ([]*deprecations.Finding, error) {
+	clientGetter := genericclioptions.NewConfigFlags(true)
+	clientGetter.Context = &contextName
+
+	config, err := clientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build discovery client for %q: %v", contextName, err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build dynamic client for %q: %v", contextName, err)
+	}
+
+	scanner := &deprecations.Scanner{
+		Discovery: discoveryClient,
+		Dynamic:   dynamicClient,
+		Metrics:   discoveryClient.RESTClient(),
+	}
+	return scanner.Scan(ctx, target)
+}
+
+// maxReportedObjects limits the number of objects listed for each removed API
+const maxReportedObjects = 5
+
+func renderRemovedAPIs(out io.Writer, findings []*deprecations.Finding) error {
+	t := &tables.Table{}
+	t.AddColumn("API", func(f *deprecations.Finding) string {
+		return f.APIVersion()
+	})
+	t.AddColumn("RESOURCE", func(f *deprecations.Finding) string {
+		return f.Resource
+	})
+	t.AddColumn("REMOVED IN", func(f *deprecations.Finding) string {
+		return f.RemovedIn
+	})
+	t.AddColumn("REPLACEMENT", func(f *deprecations.Finding) string {
+		return f.Replacement
+	})
+	t.AddColumn("REQUESTED", func(f *deprecations.Finding) string {
+		if f.Requested {
+			return "yes"
+		}
+		return "no"
+	})
+	t.AddColumn("OBJECTS", func(f *deprecations.Finding) string {
+		if len(f.Objects) > maxReportedObjects {
+			return strings.Join(f.Objects[:maxReportedObjects], ",") + fmt.Sprintf(" and %d more", len(f.Objects)-maxReportedObjects)
+		}
+		return strings.Join(f.Objects, ",")
+	})
+
+	return t.Render(findings, out, "API", "RESOURCE", "REMOVED IN", "REPLACEMENT", "REQUESTED", "OBJECTS")
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxCloneClusterLong = templates.LongDesc(i18n.T(`
+	Generate the manifest of a parallel cluster in another region, for example a disaster recovery standby.
+
+	Zones of the source cluster are remapped to the target region, together with the subnet and
+	instance group names that contain them. Resources that only exist in the source region, such as
+	shared networks and image IDs, are cleared so they are defaulted for the new region.
+
+	The generated cluster records its source and zone mapping in annotations. When the target cluster
+	already exists in the state store, the recorded mapping is reused, so the standby can be kept in
+	sync by regenerating it and applying the output with "kops replace -f".`))
+
+	toolboxCloneClusterExample = templates.Examples(i18n.T(`
+	# Generate a standby cluster in us-west-2
+	kops toolbox clone-cluster --name k8s-cluster.example.com \
+		--target-name dr.k8s-cluster.example.com --region us-west-2 > dr.yaml
+	kops create -f dr.yaml
+
+	# Use an explicit zone mapping and a different network
+	kops toolbox clone-cluster --name k8s-cluster.example.com \
+		--target-name dr.k8s-cluster.example.com --region us-west-2 \
+		--zone-map us-east-1a=us-west-2b,us-east-1b=us-west-2c \
+		--network-cidr 172.21.0.0/16
+
+	# Bring an existing standby back in sync with its source
+	kops toolbox clone-cluster --name k8s-cluster.example.com \
+		--target-name dr.k8s-cluster.example.com | kops replace -f -
+	`))
+
+	toolboxCloneClusterShort = i18n.T(`Generate a copy of a cluster in another region`)
+)
+
+type ToolboxCloneClusterOptions struct {
+	Output string
+
+	ClusterName string
+	commands.CloneClusterOptions
+
+	ZoneMappings []string
+}
+
+func (o *ToolboxCloneClusterOptions) InitDefaults() {
+	o.Output = OutputYaml
+}
+
+func NewCmdToolboxCloneCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxCloneClusterOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "clone-cluster",
+		Short:   toolboxCloneClusterShort,
+		Long:    toolboxCloneClusterLong,
+		Example: toolboxCloneClusterExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err := RunToolboxCloneCluster(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "output format.  One of: yaml, json")
+
+	cmd.Flags().StringVar(&options.TargetName, "target-name", options.TargetName, "Name of the cloned cluster")
+	cmd.Flags().StringVar(&options.Region, "region", options.Region, "Region of the cloned cluster")
+	cmd.Flags().StringSliceVar(&options.ZoneMappings, "zone-map", options.ZoneMappings, "Zone mappings of the form source=target; unmapped zones keep their suffix in the target region")
+	cmd.Flags().StringVar(&options.NetworkCIDR, "network-cidr", options.NetworkCIDR, "Network CIDR of the cloned cluster; subnet CIDRs keep their offset within the network")
+
+	return cmd
+}
+
+func RunToolboxCloneCluster(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxCloneClusterOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+	if options.TargetName == "" {
+		return fmt.Errorf("--target-name is required")
+	}
+
+	zoneMap, err := commands.ParseZoneMap(options.ZoneMappings)
+	if err != nil {
+		return err
+	}
+	options.ZoneMap = zoneMap
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	instanceGroups, err := commands.ReadAllInstanceGroups(ctx, clientset, cluster)
+	if err != nil {
+		return err
+	}
+
+	existing, err := clientset.GetCluster(ctx, options.TargetName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error reading cluster %q: %v", options.TargetName, err)
+	}
+	if err := commands.DefaultCloneOptionsFromExisting(existing, cluster.ObjectMeta.Name, &options.CloneClusterOptions); err != nil {
+		return err
+	}
+
+	clone, clonedGroups, err := commands.CloneCluster(cluster, instanceGroups, &options.CloneClusterOptions)
+	if err != nil {
+		return err
+	}
+
+	obj := []runtime.Object{clone}
+	for _, ig := range clonedGroups {
+		obj = append(obj, ig)
+	}
+
+	switch options.Output {
+	case OutputYaml:
+		return fullOutputYAML(out, obj...)
+	case OutputJSON:
+		return fullOutputJSON(out, obj...)
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+}
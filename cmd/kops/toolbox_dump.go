@@ -21,10 +21,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh"
@@ -32,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/apis/kops"
@@ -45,30 +42,70 @@ import (
 
 var (
 	toolboxDumpLong = templates.LongDesc(i18n.T(`
-	Displays cluster information.  Includes information about cloud and Kubernetes resources.`))
+	Displays cluster information.  Includes information about cloud and Kubernetes resources.
+
+	With --target terraform-import, the cloud resources of the cluster are written instead as Terraform
+	import blocks (--output hcl) or as a script running terraform import (--output script), to bring
+	them under the management of Terraform. This is only supported on AWS.
+
+	With --bundle, a support bundle for issue reports is written as a gzipped tarball. It holds the
+	cloud resources, the cluster and instance group specs with sensitive values redacted, the
+	validation results, the nodes and the kube-system pods and events, the logs of the control plane
+	pods, and excerpts of the journal and logs of the nodes collected over SSH, or with AWS Systems
+	Manager Run Command if --ssm is specified.`))
 
 	toolboxDumpExample = templates.Examples(i18n.T(`
 	# Dump cluster information
 	kops toolbox dump --name k8s-cluster.example.com
+
+	# Write Terraform import blocks for the cloud resources of a cluster
+	kops toolbox dump --name k8s-cluster.example.com --target terraform-import > imports.tf
+
+	# Write a script importing the cloud resources of a cluster into Terraform state
+	kops toolbox dump --name k8s-cluster.example.com --target terraform-import --output script > import.sh
+
+	# Write a support bundle, collecting the node logs with AWS Systems Manager
+	kops toolbox dump --name k8s-cluster.example.com --bundle kops-dump.tar.gz --ssm
 	`))
 
 	toolboxDumpShort = i18n.T(`Dump cluster information`)
 )
 
+const (
+	// DumpTargetTerraformImport writes the cloud resources of the cluster as Terraform imports
+	DumpTargetTerraformImport = "terraform-import"
+
+	// OutputHCL writes Terraform import blocks
+	OutputHCL = "hcl"
+	// OutputScript writes a script running terraform import
+	OutputScript = "script"
+)
+
 type ToolboxDumpOptions struct {
 	Output string
+	Target string
 
 	ClusterName string
 
 	Dir        string
 	PrivateKey string
 	SSHUser    string
+	KnownHosts string
+
+	// Bundle is the path of the support bundle to write
+	Bundle string
+	// SSM collects the node logs with AWS Systems Manager instead of SSH
+	SSM bool
+	// LogLines limits the logs in the support bundle to their last lines
+	LogLines int
 }
 
 func (o *ToolboxDumpOptions) InitDefaults() {
 	o.Output = OutputYaml
 	o.PrivateKey = "~/.ssh/id_rsa"
 	o.SSHUser = "ubuntu"
+	o.KnownHosts = "~/.ssh/known_hosts"
+	o.LogLines = 1000
 }
 
 func NewCmdToolboxDump(f *util.Factory, out io.Writer) *cobra.Command {
@@ -89,6 +126,10 @@ func NewCmdToolboxDump(f *util.Factory, out io.Writer) *cobra.Command {
 
 			options.ClusterName = rootCommand.ClusterName(true)
 
+			if options.Target == DumpTargetTerraformImport && !cmd.Flags().Changed("output") {
+				options.Output = OutputHCL
+			}
+
 			err := RunToolboxDump(ctx, f, out, options)
 			if err != nil {
 				exitWithError(err)
@@ -96,11 +137,17 @@ func NewCmdToolboxDump(f *util.Factory, out io.Writer) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "output format.  One of: yaml, json")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "output format.  One of: yaml, json; or hcl, script with --target terraform-import")
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, "what to dump.  If terraform-import, writes Terraform imports for the cloud resources of the cluster")
 
 	cmd.Flags().StringVar(&options.Dir, "dir", options.Dir, "target directory; if specified will collect logs and other information.")
 	cmd.Flags().StringVar(&options.PrivateKey, "private-key", options.PrivateKey, "private key to use for SSH acccess to instances")
 	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "the remote user for SSH access to instances")
+	cmd.Flags().StringVar(&options.KnownHosts, "known-hosts", options.KnownHosts, "known_hosts file with the host keys of the instances")
+
+	cmd.Flags().StringVar(&options.Bundle, "bundle", options.Bundle, "write a support bundle to this file, as a gzipped tarball")
+	cmd.Flags().BoolVar(&options.SSM, "ssm", options.SSM, "collect the node logs with AWS Systems Manager Run Command instead of SSH")
+	cmd.Flags().IntVar(&options.LogLines, "log-lines", options.LogLines, "number of lines of each log in the support bundle")
 
 	return cmd
 }
@@ -135,77 +182,47 @@ func RunToolboxDump(ctx context.Context, f *util.Factory, out io.Writer, options
 	if err != nil {
 		return err
 	}
-	d, err := resources.BuildDump(ctx, cloud, resourceMap)
-	if err != nil {
-		return err
-	}
-
-	if options.Dir != "" {
-		privateKeyPath := options.PrivateKey
-		if strings.HasPrefix(privateKeyPath, "~/") {
-			privateKeyPath = filepath.Join(os.Getenv("HOME"), privateKeyPath[2:])
-		}
-		key, err := ioutil.ReadFile(privateKeyPath)
-		if err != nil {
-			return fmt.Errorf("error reading private key %q: %v", privateKeyPath, err)
-		}
 
-		signer, err := ssh.ParsePrivateKey(key)
-		if err != nil {
-			return fmt.Errorf("error parsing private key %q: %v", privateKeyPath, err)
+	switch options.Target {
+	case "":
+	case DumpTargetTerraformImport:
+		if options.Dir != "" || options.Bundle != "" {
+			return fmt.Errorf("--dir and --bundle cannot be used with --target %s", options.Target)
 		}
-
-		cluster, err := GetCluster(ctx, f, options.ClusterName)
+		imports, err := resourceops.BuildTerraformImports(cloud, cluster, resourceMap)
 		if err != nil {
 			return err
 		}
+		switch options.Output {
+		case OutputHCL:
+			return resources.WriteTerraformImportBlocks(out, imports)
+		case OutputScript:
+			return resources.WriteTerraformImportScript(out, imports)
+		default:
+			return fmt.Errorf("unsupported output format for --target %s: %q", options.Target, options.Output)
+		}
+	default:
+		return fmt.Errorf("unsupported target: %q", options.Target)
+	}
 
-		contextName := cluster.ObjectMeta.Name
-		clientGetter := genericclioptions.NewConfigFlags(true)
-		clientGetter.Context = &contextName
+	d, err := resources.BuildDump(ctx, cloud, resourceMap)
+	if err != nil {
+		return err
+	}
 
-		var nodes corev1.NodeList
+	if options.Bundle != "" {
+		return writeSupportBundle(ctx, f, out, cluster, cloud, d, options)
+	}
 
-		config, err := clientGetter.ToRESTConfig()
+	if options.Dir != "" {
+		dumper, err := buildSSHLogDumper(options, options.Dir)
 		if err != nil {
-			klog.Warningf("cannot load kubecfg settings for %q: %v", contextName, err)
-		} else {
-			k8sClient, err := kubernetes.NewForConfig(config)
-			if err != nil {
-				klog.Warningf("cannot build kube client for %q: %v", contextName, err)
-			} else {
-
-				nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-				if err != nil {
-					klog.Warningf("error listing nodes in cluster: %v", err)
-				} else {
-					nodes = *nodeList
-				}
-			}
-		}
-
-		sshConfig := &ssh.ClientConfig{
-			Config: ssh.Config{},
-			User:   options.SSHUser,
-			Auth: []ssh.AuthMethod{
-				ssh.PublicKeys(signer),
-			},
-			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+			return err
 		}
 
-		dumper := dump.NewLogDumper(sshConfig, options.Dir)
-
-		var additionalIPs []string
-		for _, instance := range d.Instances {
-			if len(instance.PublicAddresses) != 0 {
-				additionalIPs = append(additionalIPs, instance.PublicAddresses[0])
-				continue
-			}
-
-			klog.Warningf("no public IP for node %q", instance.Name)
-		}
+		nodes := listNodes(ctx, cluster)
 
-		if err := dumper.DumpAllNodes(ctx, nodes, additionalIPs); err != nil {
+		if err := dumper.DumpAllNodes(ctx, nodes, instanceAddresses(d, false)); err != nil {
 			return fmt.Errorf("error dumping nodes: %v", err)
 		}
 	}
@@ -237,3 +254,79 @@ func RunToolboxDump(ctx context.Context, f *util.Factory, out io.Writer, options
 		return fmt.Errorf("unsupported output format: %q", options.Output)
 	}
 }
+
+// buildSSHLogDumper builds the dumper collecting the logs of the nodes over SSH
+func buildSSHLogDumper(options *ToolboxDumpOptions, dir string) (*dump.LogDumper, error) {
+	signer, err := readSSHPrivateKey(options.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := readKnownHosts(options.KnownHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		Config: ssh.Config{},
+		User:   options.SSHUser,
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeys(signer),
+		},
+		HostKeyCallback: hostKeyCallback,
+	}
+
+	return dump.NewLogDumper(sshConfig, dir), nil
+}
+
+// buildKubernetesClient builds a client for the cluster from the kubeconfig, returning nil if the configuration cannot be loaded
+func buildKubernetesClient(cluster *kops.Cluster) (*rest.Config, kubernetes.Interface) {
+	contextName := cluster.ObjectMeta.Name
+	clientGetter := genericclioptions.NewConfigFlags(true)
+	clientGetter.Context = &contextName
+
+	config, err := clientGetter.ToRESTConfig()
+	if err != nil {
+		klog.Warningf("cannot load kubecfg settings for %q: %v", contextName, err)
+		return nil, nil
+	}
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		klog.Warningf("cannot build kube client for %q: %v", contextName, err)
+		return nil, nil
+	}
+	return config, k8sClient
+}
+
+// listNodes lists the nodes of the cluster, returning no nodes if the cluster cannot be reached
+func listNodes(ctx context.Context, cluster *kops.Cluster) corev1.NodeList {
+	var nodes corev1.NodeList
+	if _, k8sClient := buildKubernetesClient(cluster); k8sClient != nil {
+		nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.Warningf("error listing nodes in cluster: %v", err)
+		} else {
+			nodes = *nodeList
+		}
+	}
+	return nodes
+}
+
+// instanceAddresses returns the addresses of the instances used to dump the nodes that are not registered:
+// their public IP, or their instance ID with SSM
+func instanceAddresses(d *resources.Dump, instanceIDs bool) []string {
+	var addresses []string
+	for _, instance := range d.Instances {
+		if instanceIDs {
+			addresses = append(addresses, instance.Name)
+			continue
+		}
+		if len(instance.PublicAddresses) != 0 {
+			addresses = append(addresses, instance.PublicAddresses[0])
+			continue
+		}
+
+		klog.Warningf("no public IP for node %q", instance.Name)
+	}
+	return addresses
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/dump"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"sigs.k8s.io/yaml"
+)
+
+// writeSupportBundle collects the information about the cluster useful in issue reports, and writes it as a gzipped tarball.
+// Only the cloud resources and the cluster spec are required: everything else is collected on a best-effort basis.
+func writeSupportBundle(ctx context.Context, f *util.Factory, out io.Writer, cluster *kops.Cluster, cloud fi.Cloud, d *resources.Dump, options *ToolboxDumpOptions) error {
+	dir, err := ioutil.TempDir("", "kops-dump")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	b, err := kops.ToRawYaml(d)
+	if err != nil {
+		return fmt.Errorf("error marshaling yaml: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "resources.yaml"), b, 0644); err != nil {
+		return err
+	}
+
+	b, err = sanitizedYAML(cluster)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "cluster.yaml"), b, 0644); err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+	instanceGroups, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var igs [][]byte
+	for i := range instanceGroups.Items {
+		b, err := sanitizedYAML(&instanceGroups.Items[i])
+		if err != nil {
+			return err
+		}
+		igs = append(igs, b)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "instancegroups.yaml"), bytes.Join(igs, []byte("\n---\n\n")), 0644); err != nil {
+		return err
+	}
+
+	var nodes corev1.NodeList
+	config, k8sClient := buildKubernetesClient(cluster)
+	if k8sClient != nil {
+		kubernetesDumper := &dump.KubernetesDumper{
+			Client:   k8sClient,
+			MaxLines: int64(options.LogLines),
+		}
+		for _, err := range kubernetesDumper.Dump(ctx, filepath.Join(dir, "kubernetes")) {
+			klog.Warningf("error collecting Kubernetes objects: %v", err)
+		}
+
+		nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.Warningf("error listing nodes in cluster: %v", err)
+		} else {
+			nodes = *nodeList
+		}
+
+		result, err := validateForBundle(cluster, cloud, instanceGroups, config.Host, k8sClient)
+		if err != nil {
+			if err := ioutil.WriteFile(filepath.Join(dir, "validation-error.txt"), []byte(err.Error()+"\n"), 0644); err != nil {
+				return err
+			}
+		} else {
+			b, err = yaml.Marshal(result)
+			if err != nil {
+				return fmt.Errorf("error marshaling validation result: %v", err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, "validation.yaml"), b, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	var logDumper *dump.LogDumper
+	nodesDir := filepath.Join(dir, "nodes")
+	if options.SSM {
+		awsCloud, ok := cloud.(awsup.AWSCloud)
+		if !ok {
+			return fmt.Errorf("--ssm is only supported on AWS")
+		}
+		logDumper = dump.NewSSMLogDumper(awsCloud.SSM(), nodesDir)
+	} else {
+		logDumper, err = buildSSHLogDumper(options, nodesDir)
+		if err != nil {
+			klog.Warningf("not collecting the logs of the nodes: %v", err)
+		}
+	}
+	if logDumper != nil {
+		logDumper.MaxLines = options.LogLines
+		if err := logDumper.DumpAllNodes(ctx, nodes, instanceAddresses(d, options.SSM)); err != nil {
+			klog.Warningf("error collecting the logs of the nodes: %v", err)
+		}
+	}
+
+	bundle, err := os.Create(options.Bundle)
+	if err != nil {
+		return fmt.Errorf("error creating %q: %v", options.Bundle, err)
+	}
+	if err := dump.WriteBundle(bundle, dir, "kops-dump-"+cluster.ObjectMeta.Name); err != nil {
+		bundle.Close()
+		return err
+	}
+	if err := bundle.Close(); err != nil {
+		return fmt.Errorf("error writing %q: %v", options.Bundle, err)
+	}
+
+	fmt.Fprintf(out, "Wrote support bundle to %s\n", options.Bundle)
+	fmt.Fprintf(out, "Sensitive values of the cluster spec were redacted; review the contents of the bundle before sharing it.\n")
+	return nil
+}
+
+// validateForBundle validates the cluster once, as kops validate cluster does
+func validateForBundle(cluster *kops.Cluster, cloud fi.Cloud, instanceGroups *kops.InstanceGroupList, host string, k8sClient kubernetes.Interface) (*validation.ValidationCluster, error) {
+	validator, err := validation.NewClusterValidator(cluster, cloud, instanceGroups, host, k8sClient)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cluster validator: %v", err)
+	}
+	result, err := validator.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("error validating cluster: %v", err)
+	}
+	return result, nil
+}
+
+// sanitizedYAML serializes a kOps object as versioned YAML, redacting its sensitive values
+func sanitizedYAML(obj runtime.Object) ([]byte, error) {
+	b, err := kopscodecs.ToVersionedYaml(obj)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing object: %v", err)
+	}
+	var m map[string]interface{}
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("error parsing object: %v", err)
+	}
+	dump.Sanitize(m)
+	return yaml.Marshal(m)
+}
@@ -0,0 +1,595 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hybrid"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxEnrollLong = templates.LongDesc(i18n.T(`
+	Configure the machines of an instance group of a metal cluster, or of a hybrid instance group
+	of an AWS cluster.
+
+	kOps does not provision the machines of metal clusters or hybrid instance groups. Instead,
+	kops update cluster stores the nodeup script of each instance group in the state store, and
+	this command runs it over SSH on the hosts listed in the instance group, or only on --host.
+	The SSH user needs root or passwordless sudo access. The host keys of the machines are
+	verified against --known-hosts; add them, after checking their fingerprints, with
+	ssh-keyscan before enrolling the machines.
+
+	The machines of a metal cluster are recorded in the state store under the hostname of the
+	machine, or --node-name, which must match the name of the node. kops-controller assigns a
+	node to an instance group only if the node was enrolled into it.
+
+	A machine of a hybrid instance group is enrolled as a single node named --node-name, which
+	gets a credential with which it authenticates to kops-controller: a random bootstrap token,
+	or with --node-public-key, a signing key generated on the machine, whose private key stays
+	at --node-private-key. Enrolling the node again replaces its credential.
+
+	With --ignition, an Ignition config that runs the script on first boot is written instead,
+	for machines that are installed with Flatcar or Fedora CoreOS.`))
+
+	toolboxEnrollExample = templates.Examples(i18n.T(`
+	# Configure all the machines of the nodes instance group
+	kops toolbox enroll --name k8s-cluster.k8s.local --instance-group nodes
+
+	# Configure a single machine of the nodes instance group
+	kops toolbox enroll --name k8s-cluster.k8s.local --instance-group nodes --host 192.168.1.20
+
+	# Write an Ignition config for a machine of the control plane
+	kops toolbox enroll --name k8s-cluster.k8s.local --instance-group master-rack1 --host 192.168.1.10 --node-name master-1 --ignition > master.ign
+
+	# Enroll an on-premises machine into the hybrid instance group of an AWS cluster
+	kops toolbox enroll --name k8s-cluster.example.com --instance-group hybrid-rack1 --node-name rack1-a --host 10.10.1.20
+
+	# Enroll an on-premises machine that authenticates with a signing key generated on the machine
+	kops toolbox enroll --name k8s-cluster.example.com --instance-group hybrid-rack1 --node-name rack1-b --host 10.10.1.21 --node-public-key rack1-b.pem
+	`))
+
+	toolboxEnrollShort = i18n.T(`Configure the machines of a metal cluster or hybrid instance group`)
+)
+
+// enrollScriptPath is where the Ignition config stores the nodeup script on the machine.
+const enrollScriptPath = "/var/lib/kops/nodeup.sh"
+
+type ToolboxEnrollOptions struct {
+	ClusterName   string
+	InstanceGroup string
+
+	Host       string
+	SSHUser    string
+	PrivateKey string
+	KnownHosts string
+
+	Ignition bool
+
+	NodeName       string
+	NodePublicKey  string
+	NodePrivateKey string
+}
+
+func (o *ToolboxEnrollOptions) InitDefaults() {
+	o.PrivateKey = "~/.ssh/id_rsa"
+	o.KnownHosts = "~/.ssh/known_hosts"
+	o.NodePrivateKey = hybrid.DefaultNodePrivateKeyPath
+}
+
+func NewCmdToolboxEnroll(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxEnrollOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "enroll",
+		Short:   toolboxEnrollShort,
+		Long:    toolboxEnrollLong,
+		Example: toolboxEnrollExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err := RunToolboxEnroll(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "the instance group of the machines to configure")
+	cmd.Flags().StringVar(&options.Host, "host", options.Host, "the host to configure; defaults to all the hosts of the instance group")
+	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "the remote user for SSH access to the machines; defaults to the sshUser of the instance group")
+	cmd.Flags().StringVar(&options.PrivateKey, "ssh-private-key", options.PrivateKey, "private key to use for SSH access to the machines")
+	cmd.Flags().StringVar(&options.KnownHosts, "known-hosts", options.KnownHosts, "known_hosts file with the host keys of the machines")
+	cmd.Flags().BoolVar(&options.Ignition, "ignition", options.Ignition, "write an Ignition config instead of connecting to the machines")
+	cmd.Flags().StringVar(&options.NodeName, "node-name", options.NodeName, "the name of the node to enroll; defaults to the hostname of the machine for metal clusters")
+	cmd.Flags().StringVar(&options.NodePublicKey, "node-public-key", options.NodePublicKey, "PEM file with the public key of a signing key of the hybrid machine, to authenticate with instead of a bootstrap token")
+	cmd.Flags().StringVar(&options.NodePrivateKey, "node-private-key", options.NodePrivateKey, "the path on the hybrid machine of the PEM encoded private key of its signing key")
+
+	return cmd
+}
+
+func RunToolboxEnroll(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxEnrollOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+	if options.InstanceGroup == "" {
+		return fmt.Errorf("--instance-group is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, options.InstanceGroup, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading instance group %q: %v", options.InstanceGroup, err)
+	}
+
+	var hosts []string
+	if ig.IsHybrid() {
+		if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+			return fmt.Errorf("hybrid instance groups are only supported on AWS clusters")
+		}
+		if options.NodeName == "" {
+			return fmt.Errorf("--node-name is required for hybrid instance groups")
+		}
+		if options.Host != "" {
+			hosts = []string{options.Host}
+		} else if !options.Ignition {
+			return fmt.Errorf("--host is required for hybrid instance groups, unless --ignition is set")
+		}
+	} else {
+		if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderMetal {
+			return fmt.Errorf("enroll is only supported on metal clusters and hybrid instance groups")
+		}
+
+		if ig.Spec.Metal != nil {
+			hosts = ig.Spec.Metal.Hosts
+		}
+		if options.Host != "" {
+			found := false
+			for _, host := range hosts {
+				if host == options.Host {
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("host %q is not listed in instance group %q; add it to spec.metal.hosts first", options.Host, ig.Name)
+			}
+			hosts = []string{options.Host}
+		}
+		if len(hosts) == 0 {
+			return fmt.Errorf("instance group %q has no hosts; list them in spec.metal.hosts", ig.Name)
+		}
+		if options.NodeName != "" && len(hosts) != 1 {
+			return fmt.Errorf("--host is required with --node-name")
+		}
+		if options.NodeName == "" && options.Ignition {
+			return fmt.Errorf("--node-name is required with --ignition")
+		}
+	}
+	if options.NodeName != "" {
+		if errs := utilvalidation.IsDNS1123Subdomain(options.NodeName); len(errs) != 0 {
+			return fmt.Errorf("invalid --node-name %q: %s", options.NodeName, strings.Join(errs, "; "))
+		}
+	}
+
+	configBase, err := registry.ConfigBase(cluster)
+	if err != nil {
+		return err
+	}
+	scriptPath := configBase.Join(metal.NodeupScriptLocation(ig))
+	script, err := scriptPath.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("nodeup script for instance group %q not found; run kops update cluster --yes first", ig.Name)
+		}
+		return fmt.Errorf("error reading nodeup script %q: %v", scriptPath, err)
+	}
+
+	var nodeCredential []byte
+	if ig.IsHybrid() {
+		nodeCredential, err = enrollHybridNode(configBase, ig, options)
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.Ignition {
+		if len(hosts) != 1 && ig.IsMaster() {
+			return fmt.Errorf("--host is required with --ignition for control plane instance groups")
+		}
+		var dirs []string
+		if ig.IsMaster() {
+			dirs = metal.EtcdVolumeDirs(cluster, hosts[0])
+		}
+		if !ig.IsHybrid() {
+			if err := enrollMetalNode(configBase, ig, options.NodeName, hosts[0]); err != nil {
+				return err
+			}
+		}
+		return writeIgnitionConfig(out, script, dirs, nodeCredential)
+	}
+
+	signer, err := readSSHPrivateKey(options.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	sshUser := options.SSHUser
+	if sshUser == "" && ig.Spec.Metal != nil {
+		sshUser = ig.Spec.Metal.SSHUser
+	}
+	if sshUser == "" {
+		sshUser = "root"
+	}
+
+	hostKeyCallback, err := readKnownHosts(options.KnownHosts)
+	if err != nil {
+		return err
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User: sshUser,
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeys(signer),
+		},
+		HostKeyCallback: hostKeyCallback,
+	}
+
+	for _, host := range hosts {
+		var b bytes.Buffer
+		if ig.IsMaster() {
+			for _, dir := range metal.EtcdVolumeDirs(cluster, host) {
+				fmt.Fprintf(&b, "mkdir -p %q\n", dir)
+			}
+		}
+		if nodeCredential != nil {
+			fmt.Fprintf(&b, "mkdir -p %q\n", filepath.Dir(hybrid.NodeCredentialPath))
+			fmt.Fprintf(&b, "(umask 077; cat > %q) <<'EOF'\n%s\nEOF\n", hybrid.NodeCredentialPath, nodeCredential)
+		}
+		b.Write(script)
+
+		if !ig.IsHybrid() {
+			nodeName := options.NodeName
+			if nodeName == "" {
+				nodeName, err = readHostname(host, sshConfig)
+				if err != nil {
+					return fmt.Errorf("error enrolling host %q: %v", host, err)
+				}
+			}
+			if err := enrollMetalNode(configBase, ig, nodeName, host); err != nil {
+				return err
+			}
+		}
+
+		klog.Infof("enrolling host %q into instance group %q", host, ig.Name)
+		if err := runScriptOverSSH(host, sshConfig, b.Bytes(), out); err != nil {
+			return fmt.Errorf("error enrolling host %q: %v", host, err)
+		}
+	}
+
+	return nil
+}
+
+// enrollHybridNode stores the credential of a node of a hybrid instance group in the state store,
+// and returns the credential file to write to the machine.
+func enrollHybridNode(configBase vfs.Path, ig *kops.InstanceGroup, options *ToolboxEnrollOptions) ([]byte, error) {
+	credential := &hybrid.Credential{
+		NodeName:      options.NodeName,
+		InstanceGroup: ig.Name,
+	}
+	nodeCredential := &hybrid.NodeCredential{
+		NodeName: options.NodeName,
+	}
+
+	if options.NodePublicKey != "" {
+		publicKey, err := ioutil.ReadFile(options.NodePublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("error reading node public key %q: %v", options.NodePublicKey, err)
+		}
+		if _, err := hybrid.ParsePublicKey(string(publicKey)); err != nil {
+			return nil, fmt.Errorf("error parsing node public key %q: %v", options.NodePublicKey, err)
+		}
+		if options.NodePrivateKey == "" {
+			return nil, fmt.Errorf("--node-private-key is required with --node-public-key")
+		}
+		credential.PublicKey = string(publicKey)
+		nodeCredential.PrivateKeyPath = options.NodePrivateKey
+	} else {
+		token, err := hybrid.NewToken()
+		if err != nil {
+			return nil, err
+		}
+		credential.TokenHash = hybrid.HashToken(token)
+		nodeCredential.Token = token
+	}
+
+	b, err := json.Marshal(credential)
+	if err != nil {
+		return nil, fmt.Errorf("error building credential: %v", err)
+	}
+	p := configBase.Join(hybrid.CredentialLocation(options.NodeName))
+	if err := p.WriteFile(bytes.NewReader(b), nil); err != nil {
+		return nil, fmt.Errorf("error writing credential %q: %v", p, err)
+	}
+	klog.Infof("enrolled node %q into instance group %q", options.NodeName, ig.Name)
+
+	b, err = json.Marshal(nodeCredential)
+	if err != nil {
+		return nil, fmt.Errorf("error building node credential: %v", err)
+	}
+	return b, nil
+}
+
+// enrollMetalNode records in the state store that the node runs on a machine of the instance group.
+func enrollMetalNode(configBase vfs.Path, ig *kops.InstanceGroup, nodeName string, host string) error {
+	enrollment := &metal.Enrollment{
+		NodeName:      nodeName,
+		InstanceGroup: ig.Name,
+		Host:          host,
+	}
+	if err := metal.WriteEnrollment(configBase, enrollment); err != nil {
+		return err
+	}
+	klog.Infof("enrolled node %q into instance group %q", nodeName, ig.Name)
+	return nil
+}
+
+// expandHome expands a leading ~/ in a path to the home directory of the user.
+func expandHome(p string) string {
+	if strings.HasPrefix(p, "~/") {
+		return filepath.Join(os.Getenv("HOME"), p[2:])
+	}
+	return p
+}
+
+// readKnownHosts returns a callback that verifies host keys against a known_hosts file.
+func readKnownHosts(knownHostsPath string) (ssh.HostKeyCallback, error) {
+	knownHostsPath = expandHome(knownHostsPath)
+	callback, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading known hosts %q: %v", knownHostsPath, err)
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		if keyErr, ok := err.(*knownhosts.KeyError); ok {
+			if len(keyErr.Want) == 0 {
+				return fmt.Errorf("host key %s of %q is not in %q; verify it and add it with ssh-keyscan", ssh.FingerprintSHA256(key), hostname, knownHostsPath)
+			}
+			return fmt.Errorf("host key %s of %q does not match %q", ssh.FingerprintSHA256(key), hostname, knownHostsPath)
+		}
+		return err
+	}, nil
+}
+
+func readSSHPrivateKey(privateKeyPath string) (ssh.Signer, error) {
+	privateKeyPath = expandHome(privateKeyPath)
+	key, err := ioutil.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading private key %q: %v", privateKeyPath, err)
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key %q: %v", privateKeyPath, err)
+	}
+	return signer, nil
+}
+
+// dialSSH connects to the host, on port 22 unless the host has a port.
+func dialSSH(host string, sshConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	addr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		addr = net.JoinHostPort(host, "22")
+	}
+
+	client, err := ssh.Dial("tcp", addr, sshConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %q: %v", addr, err)
+	}
+	return client, nil
+}
+
+// readHostname returns the hostname of the host, which kubelet uses as the name of the node.
+func readHostname(host string, sshConfig *ssh.ClientConfig) (string, error) {
+	client, err := dialSSH(host, sshConfig)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("error creating SSH session: %v", err)
+	}
+	defer session.Close()
+
+	b, err := session.Output("hostname")
+	if err != nil {
+		return "", fmt.Errorf("error reading hostname: %v", err)
+	}
+	nodeName := strings.ToLower(strings.TrimSpace(string(b)))
+	if errs := utilvalidation.IsDNS1123Subdomain(nodeName); len(errs) != 0 {
+		return "", fmt.Errorf("hostname %q is not a valid node name; set --node-name: %s", nodeName, strings.Join(errs, "; "))
+	}
+	return nodeName, nil
+}
+
+// runScriptOverSSH runs a script as root on the host, passing it on stdin.
+func runScriptOverSSH(host string, sshConfig *ssh.ClientConfig, script []byte, out io.Writer) error {
+	client, err := dialSSH(host, sshConfig)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("error creating SSH session: %v", err)
+	}
+	defer session.Close()
+
+	session.Stdin = bytes.NewReader(script)
+	session.Stdout = out
+	session.Stderr = os.Stderr
+
+	command := "/bin/bash -s"
+	if sshConfig.User != "root" {
+		command = "sudo " + command
+	}
+	if err := session.Run(command); err != nil {
+		return fmt.Errorf("error running nodeup script: %v", err)
+	}
+	return nil
+}
+
+type ignitionConfig struct {
+	Ignition ignitionVersion `json:"ignition"`
+	Storage  ignitionStorage `json:"storage"`
+	Systemd  ignitionSystemd `json:"systemd"`
+}
+
+type ignitionVersion struct {
+	Version string `json:"version"`
+}
+
+type ignitionStorage struct {
+	Directories []ignitionDirectory `json:"directories,omitempty"`
+	Files       []ignitionFile      `json:"files"`
+}
+
+type ignitionDirectory struct {
+	Path string `json:"path"`
+}
+
+type ignitionFile struct {
+	Path     string           `json:"path"`
+	Mode     int              `json:"mode"`
+	Contents ignitionContents `json:"contents"`
+}
+
+type ignitionContents struct {
+	Source string `json:"source"`
+}
+
+type ignitionSystemd struct {
+	Units []ignitionUnit `json:"units"`
+}
+
+type ignitionUnit struct {
+	Name     string `json:"name"`
+	Enabled  bool   `json:"enabled"`
+	Contents string `json:"contents"`
+}
+
+// enrollUnit runs the nodeup script once the network is up.
+const enrollUnit = `[Unit]
+Description=Configure the machine with kOps
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/bash ` + enrollScriptPath + `
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// writeIgnitionConfig writes an Ignition config that runs the nodeup script on first boot.
+// The node credential of a hybrid machine, if any, is written alongside the script.
+func writeIgnitionConfig(out io.Writer, script []byte, dirs []string, nodeCredential []byte) error {
+	config := ignitionConfig{
+		Ignition: ignitionVersion{Version: "3.2.0"},
+		Storage: ignitionStorage{
+			Files: []ignitionFile{
+				{
+					Path: enrollScriptPath,
+					Mode: 0755,
+					Contents: ignitionContents{
+						Source: "data:;base64," + base64.StdEncoding.EncodeToString(script),
+					},
+				},
+			},
+		},
+		Systemd: ignitionSystemd{
+			Units: []ignitionUnit{
+				{
+					Name:     "kops-enroll.service",
+					Enabled:  true,
+					Contents: enrollUnit,
+				},
+			},
+		},
+	}
+	if nodeCredential != nil {
+		config.Storage.Files = append(config.Storage.Files, ignitionFile{
+			Path: hybrid.NodeCredentialPath,
+			Mode: 0600,
+			Contents: ignitionContents{
+				Source: "data:;base64," + base64.StdEncoding.EncodeToString(nodeCredential),
+			},
+		})
+	}
+	for _, dir := range dirs {
+		config.Storage.Directories = append(config.Storage.Directories, ignitionDirectory{Path: dir})
+	}
+
+	b, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error building Ignition config: %v", err)
+	}
+	_, err = fmt.Fprintf(out, "%s\n", b)
+	return err
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxImportLong = templates.LongDesc(i18n.T(`
+	Rebuild kOps resources from the live resources in the cloud.`))
+
+	toolboxImportExample = templates.Examples(i18n.T(`
+	# Rebuild the manifest of a cluster from its AWS resources
+	kops toolbox import cluster --name k8s-cluster.example.com --region us-east-1 > cluster.yaml
+	`))
+
+	toolboxImportShort = i18n.T(`Rebuild kOps resources from the cloud.`)
+)
+
+func NewCmdToolboxImport(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "import",
+		Short:   toolboxImportShort,
+		Long:    toolboxImportLong,
+		Example: toolboxImportExample,
+	}
+
+	cmd.AddCommand(NewCmdToolboxImportCluster(f, out))
+
+	return cmd
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxImportClusterLong = templates.LongDesc(i18n.T(`
+	Rebuild the manifest of a cluster and its instance groups from the live resources of the cluster.
+
+	The subnets, autoscaling groups, launch templates, etcd volumes and API load balancer tagged
+	for the cluster are read to recover a state store that was lost, or to bring a cluster that
+	was built by hand under the management of kOps. Only AWS is supported.
+
+	The keys and secrets of the cluster cannot be recovered from the cloud. Review the generated
+	manifest, in particular the Kubernetes version, networking and addons, before using it.`))
+
+	toolboxImportClusterExample = templates.Examples(i18n.T(`
+	# Rebuild the manifest of a cluster and register it in the state store
+	kops toolbox import cluster --name k8s-cluster.example.com --region us-east-1 > cluster.yaml
+	kops create -f cluster.yaml
+	`))
+
+	toolboxImportClusterShort = i18n.T(`Rebuild the manifest of a cluster from its cloud resources`)
+)
+
+type ToolboxImportClusterOptions struct {
+	Output string
+
+	ClusterName string
+	Region      string
+}
+
+func (o *ToolboxImportClusterOptions) InitDefaults() {
+	o.Output = OutputYaml
+}
+
+func NewCmdToolboxImportCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxImportClusterOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "cluster",
+		Short:   toolboxImportClusterShort,
+		Long:    toolboxImportClusterLong,
+		Example: toolboxImportClusterExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err := RunToolboxImportCluster(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "output format.  One of: yaml, json")
+	cmd.Flags().StringVar(&options.Region, "region", options.Region, "AWS region of the cluster")
+
+	return cmd
+}
+
+func RunToolboxImportCluster(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxImportClusterOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+	if options.Region == "" {
+		return fmt.Errorf("--region is required")
+	}
+
+	tags := map[string]string{awsup.TagClusterName: options.ClusterName}
+	cloud, err := awsup.NewAWSCloud(options.Region, tags)
+	if err != nil {
+		return fmt.Errorf("error initializing AWS client: %v", err)
+	}
+
+	resources, err := commands.DiscoverClusterAWS(cloud, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	cluster, instanceGroups, err := commands.BuildImportedCluster(resources)
+	if err != nil {
+		return err
+	}
+
+	obj := []runtime.Object{cluster}
+	for _, ig := range instanceGroups {
+		obj = append(obj, ig)
+	}
+
+	switch options.Output {
+	case OutputYaml:
+		return fullOutputYAML(out, obj...)
+	case OutputJSON:
+		return fullOutputJSON(out, obj...)
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+}
@@ -282,6 +282,7 @@ func RunToolboxInstanceSelector(ctx context.Context, f *util.Factory, args []str
 		if err != nil {
 			return err
 		}
+		cluster.RemoveInheritedInstanceGroupFields(ig)
 
 		newInstanceGroups = append(newInstanceGroups, ig)
 
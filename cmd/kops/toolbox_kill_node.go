@@ -0,0 +1,315 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxKillNodeLong = templates.LongDesc(i18n.T(`
+	Terminate a node through the cloud API, the way an instance failure would,
+	then watch its instance group replace it and the cluster validate.
+
+	The node is not drained and its Node object is not deleted. Unless an instance
+	or node is given, a random instance is chosen from the selected instance groups.
+	With --detach the instance is first detached from its instance group, so that
+	the replacement is launched before the instance is terminated.
+
+	This is intended for resilience testing, such as game days.`))
+
+	toolboxKillNodeExample = templates.Examples(i18n.T(`
+	# Kill a random worker node of the currently active cluster
+	kops toolbox kill-node --yes
+
+	# Kill a random instance of the nodes-us-east-1a instance group
+	kops toolbox kill-node --instance-group nodes-us-east-1a --yes
+
+	# Kill a specific instance, detaching it from its instance group first
+	kops toolbox kill-node i-0a5ed581b862d3425 --detach --yes
+	`))
+
+	toolboxKillNodeShort = i18n.T(`Terminate a node to test the resilience of the cluster.`)
+)
+
+// ToolboxKillNodeOptions is the command Object for killing a node.
+type ToolboxKillNodeOptions struct {
+	Yes       bool
+	CloudOnly bool
+
+	// Detach detaches the instance from its instance group before it is terminated.
+	Detach bool
+
+	// ValidationTimeout is the timeout for the replacement to join and the cluster to validate.
+	ValidationTimeout time.Duration
+
+	// ValidateCount is the number of times that a cluster needs to be validated after the replacement joined.
+	ValidateCount int32
+
+	// Seed seeds the random choice of instance; zero uses the current time.
+	Seed int64
+
+	ClusterName string
+
+	// Target is the ID of the instance or name of the node to kill; empty chooses a random one.
+	Target string
+
+	// InstanceGroups is the list of instance groups to choose from; empty means all.
+	InstanceGroups []string
+
+	// InstanceGroupRoles is the list of roles of the instance groups to choose from.
+	InstanceGroupRoles []string
+}
+
+func (o *ToolboxKillNodeOptions) InitDefaults() {
+	d := &RollingUpdateOptions{}
+	d.InitDefaults()
+
+	o.ValidationTimeout = d.ValidationTimeout
+	o.ValidateCount = d.ValidateCount
+	o.InstanceGroupRoles = []string{strings.ToLower(string(kopsapi.InstanceGroupRoleNode))}
+}
+
+func NewCmdToolboxKillNode(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxKillNodeOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "kill-node [INSTANCE|NODE]",
+		Short:   toolboxKillNodeShort,
+		Long:    toolboxKillNodeLong,
+		Example: toolboxKillNodeExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			if len(args) > 1 {
+				return fmt.Errorf("can only kill one node at a time")
+			}
+			if len(args) == 1 {
+				options.Target = args[0]
+				if cmd.Flags().Changed("instance-group") || cmd.Flags().Changed("instance-group-roles") {
+					return fmt.Errorf("cannot specify --instance-group or --instance-group-roles with an instance or node")
+				}
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxKillNode(context.TODO(), f, out, options)
+		},
+	}
+
+	allRoles := make([]string, 0, len(kopsapi.AllInstanceGroupRoles))
+	for _, r := range kopsapi.AllInstanceGroupRoles {
+		allRoles = append(allRoles, strings.ToLower(string(r)))
+	}
+
+	cmd.Flags().BoolVar(&options.CloudOnly, "cloudonly", options.CloudOnly, "Kill the node without confirming progress with Kubernetes")
+	cmd.Flags().BoolVar(&options.Detach, "detach", options.Detach, "Detach the instance from its instance group before terminating it")
+	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for the replacement to join and the cluster to validate")
+	cmd.Flags().Int32Var(&options.ValidateCount, "validate-count", options.ValidateCount, "Number of times that a cluster needs to be validated after the replacement joined")
+	cmd.Flags().Int64Var(&options.Seed, "seed", options.Seed, "Seed for the random choice of instance (defaults to the current time)")
+	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "Instance groups to choose from (defaults to all if not specified)")
+	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(&options.InstanceGroups, &options.InstanceGroupRoles))
+	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "Instance group roles to choose from ("+strings.Join(allRoles, ",")+")")
+	cmd.RegisterFlagCompletionFunc("instance-group-roles", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return sets.NewString(allRoles...).Delete(options.InstanceGroupRoles...).List(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to immediately kill the node")
+
+	return cmd
+}
+
+func RunToolboxKillNode(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxKillNodeOptions) error {
+	clientSet, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	var nodes []v1.Node
+	var k8sClient kubernetes.Interface
+	var host string
+	if !options.CloudOnly {
+		k8sClient, host, nodes, err = getNodes(ctx, cluster, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	list, err := clientSet.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var instanceGroups []*kopsapi.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	groups, err := cloud.GetCloudGroups(cluster, instanceGroups, false, nodes)
+	if err != nil {
+		return err
+	}
+
+	seed := options.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	victim, err := chooseKillNodeInstance(groups, options, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return err
+	}
+
+	if victim.Node != nil {
+		fmt.Fprintf(out, "Instance %v (%v) of instance group %v chosen to be killed\n", victim.ID, victim.Node.Name, victim.CloudInstanceGroup.HumanName)
+	} else {
+		fmt.Fprintf(out, "Instance %v of instance group %v chosen to be killed\n", victim.ID, victim.CloudInstanceGroup.HumanName)
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to kill the node\n")
+		return nil
+	}
+
+	d := &instancegroups.RollingUpdateCluster{
+		Cluster:           cluster,
+		Ctx:               ctx,
+		Cloud:             cloud,
+		K8sClient:         k8sClient,
+		FailOnValidate:    true,
+		CloudOnly:         options.CloudOnly,
+		ClusterName:       options.ClusterName,
+		ValidationTimeout: options.ValidationTimeout,
+		ValidateCount:     int(options.ValidateCount),
+		// TODO should we expose this to the UI?
+		ValidateTickDuration:    30 * time.Second,
+		ValidateSuccessDuration: 10 * time.Second,
+	}
+
+	var clusterValidator validation.ClusterValidator
+	if !options.CloudOnly {
+		clusterValidator, err = validation.NewClusterValidator(cluster, cloud, list, host, k8sClient)
+		if err != nil {
+			return fmt.Errorf("cannot create cluster validator: %v", err)
+		}
+	}
+	d.ClusterValidator = clusterValidator
+
+	replacement, err := d.KillInstance(victim, options.Detach)
+	if err != nil {
+		return err
+	}
+
+	if replacement.Node != nil {
+		fmt.Fprintf(out, "\nInstance %v was replaced by instance %v (%v)\n", victim.ID, replacement.ID, replacement.Node.Name)
+	} else {
+		fmt.Fprintf(out, "\nInstance %v was replaced by instance %v\n", victim.ID, replacement.ID)
+	}
+	return nil
+}
+
+// chooseKillNodeInstance returns the instance matching the target, or a random one of the
+// selected instance groups when no target is given.
+func chooseKillNodeInstance(groups map[string]*cloudinstances.CloudInstanceGroup, options *ToolboxKillNodeOptions, rng *rand.Rand) (*cloudinstances.CloudInstance, error) {
+	if options.Target != "" {
+		victim := findDeletionNode(groups, &DeleteInstanceOptions{InstanceID: options.Target, CloudOnly: options.CloudOnly})
+		if victim == nil {
+			return nil, fmt.Errorf("could not find instance %v", options.Target)
+		}
+		if victim.Node == nil && !options.CloudOnly {
+			fmt.Fprintf(os.Stderr, "Instance is not a member of the cluster\n")
+			fmt.Fprintf(os.Stderr, "Use --cloudonly to kill the node without confirming progress with the k8s API\n\n")
+			return nil, fmt.Errorf("error finding node name for instance: %v", victim.ID)
+		}
+		return victim, nil
+	}
+
+	names := sets.NewString(options.InstanceGroups...)
+	roles := make(map[kopsapi.InstanceGroupRole]bool)
+	for _, role := range options.InstanceGroupRoles {
+		r, ok := kopsapi.ParseInstanceGroupRole(role, true)
+		if !ok {
+			return nil, fmt.Errorf("invalid instance group role %q", role)
+		}
+		roles[r] = true
+	}
+
+	var candidates []*cloudinstances.CloudInstance
+	for _, group := range groups {
+		ig := group.InstanceGroup
+		if ig == nil || ig.Spec.Role == kopsapi.InstanceGroupRoleBastion {
+			continue
+		}
+		if names.Len() != 0 && !names.Has(ig.ObjectMeta.Name) {
+			continue
+		}
+		if len(roles) != 0 && !roles[ig.Spec.Role] {
+			continue
+		}
+		for _, instance := range append(append([]*cloudinstances.CloudInstance{}, group.Ready...), group.NeedUpdate...) {
+			if instance.Status == cloudinstances.CloudInstanceStatusDetached {
+				continue
+			}
+			if instance.Node == nil && !options.CloudOnly {
+				continue
+			}
+			candidates = append(candidates, instance)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no instances to kill in the selected instance groups")
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ID < candidates[j].ID
+	})
+	return candidates[rng.Intn(len(candidates))], nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func killNodeTestGroups() map[string]*cloudinstances.CloudInstanceGroup {
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	add := func(name string, role kopsapi.InstanceGroupRole, instances ...string) *cloudinstances.CloudInstanceGroup {
+		group := &cloudinstances.CloudInstanceGroup{
+			HumanName: name,
+			InstanceGroup: &kopsapi.InstanceGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       kopsapi.InstanceGroupSpec{Role: role},
+			},
+		}
+		for _, id := range instances {
+			group.NewCloudInstance(id, cloudinstances.CloudInstanceStatusUpToDate, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: id + ".local"}})
+		}
+		groups[name] = group
+		return group
+	}
+	add("master-1", kopsapi.InstanceGroupRoleMaster, "master-1a")
+	add("bastion", kopsapi.InstanceGroupRoleBastion, "bastion-a")
+	add("nodes-1", kopsapi.InstanceGroupRoleNode, "nodes-1a", "nodes-1b")
+	nodes := add("nodes-2", kopsapi.InstanceGroupRoleNode, "nodes-2a")
+	nodes.NewCloudInstance("nodes-2b", cloudinstances.CloudInstanceStatusUpToDate, nil)
+	nodes.NewCloudInstance("nodes-2c", cloudinstances.CloudInstanceStatusDetached, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "nodes-2c.local"}})
+	return groups
+}
+
+func TestChooseKillNodeInstance(t *testing.T) {
+	grid := []struct {
+		Name          string
+		Options       ToolboxKillNodeOptions
+		Expected      []string
+		ExpectedError string
+	}{
+		{
+			Name:     "worker nodes by default",
+			Options:  ToolboxKillNodeOptions{InstanceGroupRoles: []string{"node"}},
+			Expected: []string{"nodes-1a", "nodes-1b", "nodes-2a"},
+		},
+		{
+			Name:     "cloud only includes instances without nodes",
+			Options:  ToolboxKillNodeOptions{InstanceGroupRoles: []string{"node"}, CloudOnly: true},
+			Expected: []string{"nodes-1a", "nodes-1b", "nodes-2a", "nodes-2b"},
+		},
+		{
+			Name:     "instance group",
+			Options:  ToolboxKillNodeOptions{InstanceGroups: []string{"nodes-2"}},
+			Expected: []string{"nodes-2a"},
+		},
+		{
+			Name:     "all roles except bastions",
+			Options:  ToolboxKillNodeOptions{},
+			Expected: []string{"master-1a", "nodes-1a", "nodes-1b", "nodes-2a"},
+		},
+		{
+			Name:          "no candidates",
+			Options:       ToolboxKillNodeOptions{InstanceGroupRoles: []string{"apiserver"}},
+			ExpectedError: "no instances to kill in the selected instance groups",
+		},
+		{
+			Name:          "invalid role",
+			Options:       ToolboxKillNodeOptions{InstanceGroupRoles: []string{"worker"}},
+			ExpectedError: `invalid instance group role "worker"`,
+		},
+		{
+			Name:     "instance",
+			Options:  ToolboxKillNodeOptions{Target: "master-1a"},
+			Expected: []string{"master-1a"},
+		},
+		{
+			Name:     "node",
+			Options:  ToolboxKillNodeOptions{Target: "nodes-1b.local"},
+			Expected: []string{"nodes-1b"},
+		},
+		{
+			Name:          "unknown instance",
+			Options:       ToolboxKillNodeOptions{Target: "i-unknown"},
+			ExpectedError: "could not find instance i-unknown",
+		},
+		{
+			Name:          "instance without node",
+			Options:       ToolboxKillNodeOptions{Target: "nodes-2b"},
+			ExpectedError: "error finding node name for instance: nodes-2b",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			groups := killNodeTestGroups()
+			chosen := make(map[string]bool)
+			for seed := int64(1); seed <= 50; seed++ {
+				instance, err := chooseKillNodeInstance(groups, &g.Options, rand.New(rand.NewSource(seed)))
+				if g.ExpectedError != "" {
+					if err == nil || err.Error() != g.ExpectedError {
+						t.Fatalf("expected error %q, got %v", g.ExpectedError, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				chosen[instance.ID] = true
+			}
+			if len(chosen) != len(g.Expected) {
+				t.Errorf("expected instances %v to be chosen, got %v", g.Expected, chosen)
+			}
+			for _, id := range g.Expected {
+				if !chosen[id] {
+					t.Errorf("expected instances %v to be chosen, got %v", g.Expected, chosen)
+				}
+			}
+		})
+	}
+}
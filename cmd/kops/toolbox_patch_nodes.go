@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/ospatch"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxPatchNodesLong = templates.LongDesc(i18n.T(`
+	Update the operating system packages of the nodes of a cluster.
+
+	Nodes are patched one at a time. Each node is drained, honoring PodDisruptionBudgets,
+	its packages are updated with the package manager of its distribution, and it is rebooted
+	and uncordoned once it is ready again. Patching stops at the first node that fails.
+
+	Commands are run on the nodes either by a privileged pod (--method Pod), or with AWS Systems
+	Manager Run Command (--method SSM), which needs the SSM agent on the instances.
+
+	Control plane nodes are only patched if their instance groups are selected with --instance-group.`))
+
+	toolboxPatchNodesExample = templates.Examples(i18n.T(`
+	# List the nodes that would be patched
+	kops toolbox patch-nodes --name k8s-cluster.example.com
+
+	# Patch the nodes of an instance group, using Systems Manager
+	kops toolbox patch-nodes --name k8s-cluster.example.com \
+		--instance-group nodes-us-east-1a --method SSM --yes
+	`))
+
+	toolboxPatchNodesShort = i18n.T(`Update the operating system packages of nodes`)
+)
+
+type ToolboxPatchNodesOptions struct {
+	ClusterName string
+
+	InstanceGroups []string
+	Method         string
+	Reboot         bool
+	Image          string
+
+	DrainTimeout  time.Duration
+	PatchTimeout  time.Duration
+	RebootTimeout time.Duration
+
+	Yes bool
+}
+
+func (o *ToolboxPatchNodesOptions) InitDefaults() {
+	o.Reboot = true
+	o.Image = ospatch.DefaultPodImage
+	o.DrainTimeout = 15 * time.Minute
+	o.PatchTimeout = 30 * time.Minute
+	o.RebootTimeout = 15 * time.Minute
+}
+
+func NewCmdToolboxPatchNodes(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxPatchNodesOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "patch-nodes",
+		Short:   toolboxPatchNodesShort,
+		Long:    toolboxPatchNodesLong,
+		Example: toolboxPatchNodesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err := RunToolboxPatchNodes(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "Instance groups to patch (defaults to all but the control plane if not specified)")
+	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(&options.InstanceGroups, nil))
+	cmd.Flags().StringVar(&options.Method, "method", options.Method, "How commands are run on the nodes: Pod or SSM (defaults to the osPatching method of the cluster, or Pod)")
+	cmd.Flags().BoolVar(&options.Reboot, "reboot", options.Reboot, "Reboot the nodes after updating their packages")
+	cmd.Flags().StringVar(&options.Image, "image", options.Image, "Image of the pods running commands on the nodes, which must provide nsenter")
+	cmd.Flags().DurationVar(&options.DrainTimeout, "drain-timeout", options.DrainTimeout, "Maximum time to wait for a node to drain")
+	cmd.Flags().DurationVar(&options.PatchTimeout, "patch-timeout", options.PatchTimeout, "Maximum time to wait for the packages of a node to be updated")
+	cmd.Flags().DurationVar(&options.RebootTimeout, "reboot-timeout", options.RebootTimeout, "Maximum time to wait for a node to be ready after rebooting")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Patch the nodes; without --yes the nodes that would be patched are listed")
+
+	return cmd
+}
+
+func RunToolboxPatchNodes(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxPatchNodesOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	method := options.Method
+	if method == "" {
+		method = kops.OSPatchingMethodPod
+		if cluster.Spec.OSPatching != nil && cluster.Spec.OSPatching.Method != "" {
+			method = cluster.Spec.OSPatching.Method
+		}
+	}
+
+	contextName := cluster.ObjectMeta.Name
+	clientGetter := genericclioptions.NewConfigFlags(true)
+	clientGetter.Context = &contextName
+
+	config, err := clientGetter.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("cannot build kube client for %q: %v", contextName, err)
+	}
+
+	var runner ospatch.CommandRunner
+	switch method {
+	case kops.OSPatchingMethodPod:
+		runner = &ospatch.PodRunner{
+			K8sClient: k8sClient,
+			Namespace: "kube-system",
+			Image:     options.Image,
+		}
+
+	case kops.OSPatchingMethodSSM:
+		cloud, err := cloudup.BuildCloud(cluster)
+		if err != nil {
+			return err
+		}
+		awsCloud, ok := cloud.(awsup.AWSCloud)
+		if !ok {
+			return fmt.Errorf("method %q is only supported on AWS", method)
+		}
+		runner = &ospatch.SSMRunner{SSM: awsCloud.SSM()}
+
+	default:
+		return fmt.Errorf("unknown method %q; supported methods are %s and %s", method, kops.OSPatchingMethodPod, kops.OSPatchingMethodSSM)
+	}
+
+	nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing nodes in cluster: %v", err)
+	}
+	nodes := ospatch.SelectNodes(nodeList.Items, options.InstanceGroups)
+	if len(nodes) == 0 {
+		fmt.Fprintf(out, "No nodes to patch\n")
+		return nil
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Nodes to patch, in order:\n")
+		for _, node := range nodes {
+			fmt.Fprintf(out, "  %s\t%s\n", node.Name, node.Labels[kops.NodeLabelInstanceGroup])
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to patch the nodes.\n")
+		return nil
+	}
+
+	patcher := &ospatch.Patcher{
+		K8sClient:     k8sClient,
+		Runner:        runner,
+		Reboot:        options.Reboot,
+		DrainTimeout:  options.DrainTimeout,
+		PatchTimeout:  options.PatchTimeout,
+		RebootTimeout: options.RebootTimeout,
+		Out:           out,
+	}
+	return patcher.PatchNodes(ctx, nodes)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+const (
+	// renderedNodeupConfigFile is the file the nodeup config of an instance group is rendered to
+	renderedNodeupConfigFile = "nodeupconfig.yaml"
+	// renderedUserDataFile is the file the bootstrap script of an instance group is rendered to
+	renderedUserDataFile = "user-data"
+)
+
+var (
+	toolboxRenderUserDataLong = templates.LongDesc(i18n.T(`
+	Render the nodeup config and the bootstrap script (user data) of instance groups,
+	exactly as kops update cluster would build them, without applying any change.
+
+	The rendered files can be code-reviewed, or diffed between versions of kOps
+	to see how an upgrade changes what runs on the instances.`))
+
+	toolboxRenderUserDataExample = templates.Examples(i18n.T(`
+	# Render the user data of the nodes instance group to stdout
+	kops toolbox render-userdata --name k8s-cluster.example.com --instance-group nodes
+
+	# Render the user data of all instance groups to a directory, one directory per instance group
+	kops toolbox render-userdata --name k8s-cluster.example.com --out userdata
+	`))
+
+	toolboxRenderUserDataShort = i18n.T(`Render the nodeup config and bootstrap script of instance groups.`)
+)
+
+type ToolboxRenderUserDataOptions struct {
+	ClusterName string
+
+	// InstanceGroups is the list of instance groups to render; empty means all.
+	InstanceGroups []string
+
+	// OutDir is the directory to write the rendered files to; empty writes them to stdout.
+	OutDir string
+}
+
+func NewCmdToolboxRenderUserData(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxRenderUserDataOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "render-userdata [CLUSTER]",
+		Short:             toolboxRenderUserDataShort,
+		Long:              toolboxRenderUserDataLong,
+		Example:           toolboxRenderUserDataExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(&rootCommand, true),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxRenderUserData(context.TODO(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "Instance groups to render (defaults to all if not specified)")
+	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(&options.InstanceGroups, nil))
+	cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Directory to write the rendered files to, one directory per instance group, instead of stdout")
+
+	return cmd
+}
+
+func RunToolboxRenderUserData(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxRenderUserDataOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	names := options.InstanceGroups
+	if len(names) == 0 {
+		list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, ig := range list.Items {
+			names = append(names, ig.ObjectMeta.Name)
+		}
+		sort.Strings(names)
+	} else {
+		for _, name := range names {
+			if _, err := clientset.InstanceGroupsFor(cluster).Get(ctx, name, metav1.GetOptions{}); err != nil {
+				return fmt.Errorf("error reading instance group %q: %v", name, err)
+			}
+		}
+	}
+
+	updateOptions := &UpdateClusterOptions{}
+	updateOptions.InitDefaults()
+	updateOptions.ClusterName = options.ClusterName
+	updateOptions.Target = cloudup.TargetDryRun
+	results, err := RunUpdateCluster(ctx, f, ioutil.Discard, updateOptions)
+	if err != nil {
+		return err
+	}
+
+	rendered := 0
+	for _, name := range names {
+		task, found := results.TaskMap["BootstrapScript/"+name]
+		if !found {
+			// Bastions without additional user data don't run nodeup
+			fmt.Fprintf(os.Stderr, "Instance group %q does not run nodeup; skipping\n", name)
+			continue
+		}
+		bootstrapScript, ok := task.(*model.BootstrapScript)
+		if !ok {
+			return fmt.Errorf("unexpected task type %T for instance group %q", task, name)
+		}
+
+		files := []struct {
+			name     string
+			resource fi.Resource
+		}{
+			{name: renderedNodeupConfigFile, resource: bootstrapScript.NodeupConfig()},
+			{name: renderedUserDataFile, resource: bootstrapScript.Script()},
+		}
+		for _, file := range files {
+			data, err := fi.ResourceAsBytes(file.resource)
+			if err != nil {
+				return fmt.Errorf("error rendering %s of instance group %q: %v", file.name, name, err)
+			}
+
+			if options.OutDir == "" {
+				fmt.Fprintf(out, "# Source: %s/%s\n", name, file.name)
+				if _, err := out.Write(data); err != nil {
+					return err
+				}
+				continue
+			}
+
+			dir := filepath.Join(options.OutDir, name)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("error creating directory %q: %v", dir, err)
+			}
+			p := filepath.Join(dir, file.name)
+			if err := ioutil.WriteFile(p, data, 0644); err != nil {
+				return fmt.Errorf("error writing %q: %v", p, err)
+			}
+		}
+		rendered++
+	}
+
+	if options.OutDir != "" {
+		fmt.Fprintf(out, "Wrote the nodeup config and user data of %d instance groups to %s\n", rendered, options.OutDir)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/pkg/testutils/golden"
+)
+
+// TestRenderUserData checks that the rendered user data matches what kops update cluster builds for the minimal cluster.
+func TestRenderUserData(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.MockKopsVersion("1.21.0-alpha.1")
+	h.SetupMockAWS()
+
+	i := newIntegrationTest("minimal.example.com", "minimal")
+	i.srcDir = updateClusterTestBase + i.srcDir
+
+	ctx := context.Background()
+	factory := i.setupCluster(t, "in-"+i.version+".yaml", ctx, bytes.Buffer{})
+
+	outDir := path.Join(h.TempDir, "userdata")
+	var stdout bytes.Buffer
+	options := &ToolboxRenderUserDataOptions{
+		ClusterName: i.clusterName,
+		OutDir:      outDir,
+	}
+	if err := RunToolboxRenderUserData(ctx, factory, &stdout, options); err != nil {
+		t.Fatalf("error rendering user data: %v", err)
+	}
+
+	for ig, golden := range map[string]string{
+		"master-us-test-1a": "master-us-test-1a.masters." + i.clusterName,
+		"nodes":             "nodes." + i.clusterName,
+	} {
+		assertRenderedFile(t, filepath.Join(outDir, ig, renderedUserDataFile), path.Join(i.srcDir, "data", "aws_launch_template_"+golden+"_user_data"))
+		assertRenderedFile(t, filepath.Join(outDir, ig, renderedNodeupConfigFile), path.Join(i.srcDir, "data", "aws_s3_bucket_object_nodeupconfig-"+ig+"_content"))
+	}
+
+	// Rendering to stdout prefixes each file with its source
+	stdout.Reset()
+	options = &ToolboxRenderUserDataOptions{
+		ClusterName:    i.clusterName,
+		InstanceGroups: []string{"nodes"},
+	}
+	if err := RunToolboxRenderUserData(ctx, factory, &stdout, options); err != nil {
+		t.Fatalf("error rendering user data: %v", err)
+	}
+	nodeupConfig, err := ioutil.ReadFile(path.Join(i.srcDir, "data", "aws_s3_bucket_object_nodeupconfig-nodes_content"))
+	if err != nil {
+		t.Fatalf("error reading expected nodeup config: %v", err)
+	}
+	userData, err := ioutil.ReadFile(path.Join(i.srcDir, "data", "aws_launch_template_nodes."+i.clusterName+"_user_data"))
+	if err != nil {
+		t.Fatalf("error reading expected user data: %v", err)
+	}
+	expected := "# Source: nodes/nodeupconfig.yaml\n" + string(nodeupConfig) + "# Source: nodes/user-data\n" + string(userData)
+	if stdout.String() != expected {
+		t.Errorf("unexpected output to stdout:\n%s", stdout.String())
+	}
+}
+
+func assertRenderedFile(t *testing.T, actualPath string, expectedPath string) {
+	t.Helper()
+
+	actual, err := ioutil.ReadFile(actualPath)
+	if err != nil {
+		t.Fatalf("error reading rendered file: %v", err)
+	}
+	golden.AssertMatchesFile(t, string(actual), expectedPath)
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/sshcredentials"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxRotateSSHKeyLong = templates.LongDesc(i18n.T(`
+	Replace the SSH public key of all the instance groups of a cluster.
+
+	The rotation has two stages. The first stage, with --pubkey, makes the new key the "admin" SSH
+	public key and keeps the replaced keys as "admin-previous" keys. Once the cluster is updated,
+	the cloud provides the new key to the instances, for instance through a new EC2 key pair in the
+	launch templates, while nodeup keeps authorizing the previous keys, so that both the new and the
+	previous keys give access to the instances during the transition.
+
+	The second stage, with --finish, removes the previous keys, and the EC2 key pairs created for them,
+	so that they are no longer authorized once the instances are replaced.
+
+	Each stage updates the cluster, and rolls all the instance groups with --rolling-update.
+	Without --yes, the changes to the SSH public keys are listed.`))
+
+	toolboxRotateSSHKeyExample = templates.Examples(i18n.T(`
+	# Replace the SSH public key, and roll the instances so they authorize it
+	kops toolbox rotate-sshkey --name k8s-cluster.example.com -i ~/.ssh/new.pub --rolling-update --yes
+
+	# Stop authorizing the previous SSH public keys
+	kops toolbox rotate-sshkey --name k8s-cluster.example.com --finish --rolling-update --yes
+	`))
+
+	toolboxRotateSSHKeyShort = i18n.T(`Replace the SSH public key of the instances of a cluster`)
+)
+
+type ToolboxRotateSSHKeyOptions struct {
+	ClusterName   string
+	PublicKeyPath string
+
+	// Finish removes the SSH public keys replaced by the rotation
+	Finish bool
+	// RollingUpdate rolls all the instance groups once the cluster is updated
+	RollingUpdate bool
+	Yes           bool
+}
+
+func NewCmdToolboxRotateSSHKey(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxRotateSSHKeyOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "rotate-sshkey",
+		Short:   toolboxRotateSSHKeyShort,
+		Long:    toolboxRotateSSHKeyLong,
+		Example: toolboxRotateSSHKeyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err := RunToolboxRotateSSHKey(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.PublicKeyPath, "pubkey", "i", options.PublicKeyPath, "Path to the new SSH public key")
+	cmd.Flags().BoolVar(&options.Finish, "finish", options.Finish, "Remove the SSH public keys replaced by the rotation")
+	cmd.Flags().BoolVar(&options.RollingUpdate, "rolling-update", options.RollingUpdate, "Roll all the instance groups once the cluster is updated")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Rotate the SSH public key; without --yes the changes are only listed")
+
+	return cmd
+}
+
+// sshKeyRotationStep is a change to the SSH public keys in the state store
+type sshKeyRotationStep struct {
+	// Add is the SSH public key to add, if set
+	Add *kopsapi.SSHCredential
+	// Delete is the SSH public key to delete, if set
+	Delete *kopsapi.SSHCredential
+}
+
+func (s *sshKeyRotationStep) String() string {
+	item, verb := s.Add, "Add"
+	if s.Delete != nil {
+		item, verb = s.Delete, "Delete"
+	}
+	fingerprint, err := sshcredentials.Fingerprint(item.Spec.PublicKey)
+	if err != nil {
+		fingerprint = "<invalid>"
+	}
+	return fmt.Sprintf("%s SSH public key %q %s", verb, item.Name, fingerprint)
+}
+
+// planSSHKeyRotation returns the changes to the SSH public keys that make newKey the primary key, keeping the current
+// primary keys as previous keys. With finish, it returns the changes that remove the previous keys instead.
+func planSSHKeyRotation(primary, previous []*kopsapi.SSHCredential, newKey []byte, finish bool) ([]*sshKeyRotationStep, error) {
+	var steps []*sshKeyRotationStep
+
+	if finish {
+		if len(previous) == 0 {
+			return nil, fmt.Errorf("no SSH key rotation is in progress")
+		}
+		for _, item := range previous {
+			steps = append(steps, &sshKeyRotationStep{Delete: item})
+		}
+		return steps, nil
+	}
+
+	if len(previous) != 0 {
+		return nil, fmt.Errorf("an SSH key rotation is already in progress; finish it with --finish first")
+	}
+
+	newFingerprint, err := sshcredentials.Fingerprint(string(newKey))
+	if err != nil {
+		return nil, fmt.Errorf("error fingerprinting the new SSH public key: %v", err)
+	}
+	for _, item := range primary {
+		fingerprint, err := sshcredentials.Fingerprint(item.Spec.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("error fingerprinting SSH public key %q: %v", item.Name, err)
+		}
+		if fingerprint == newFingerprint {
+			return nil, fmt.Errorf("the new SSH public key is already the %q SSH public key", fi.SecretNameSSHPrimary)
+		}
+	}
+
+	// The previous keys are stored before the current keys are deleted, so that they are never lost.
+	// The current keys are deleted before the new key is added, as some stores hold a single key per name.
+	for _, item := range primary {
+		steps = append(steps, &sshKeyRotationStep{Add: sshCredential(fi.SecretNameSSHPrevious, item.Spec.PublicKey)})
+	}
+	for _, item := range primary {
+		steps = append(steps, &sshKeyRotationStep{Delete: sshCredential(fi.SecretNameSSHPrimary, item.Spec.PublicKey)})
+	}
+	steps = append(steps, &sshKeyRotationStep{Add: sshCredential(fi.SecretNameSSHPrimary, string(newKey))})
+	return steps, nil
+}
+
+func sshCredential(name string, publicKey string) *kopsapi.SSHCredential {
+	item := &kopsapi.SSHCredential{}
+	item.Name = name
+	item.Spec.PublicKey = publicKey
+	return item
+}
+
+func RunToolboxRotateSSHKey(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxRotateSSHKeyOptions) error {
+	var newKey []byte
+	if options.Finish {
+		if options.PublicKeyPath != "" {
+			return fmt.Errorf("--pubkey cannot be used with --finish")
+		}
+	} else {
+		if options.PublicKeyPath == "" {
+			return fmt.Errorf("the new SSH public key is required (use -i), or --finish to finish a rotation")
+		}
+		data, err := ioutil.ReadFile(options.PublicKeyPath)
+		if err != nil {
+			return fmt.Errorf("error reading SSH public key %v: %v", options.PublicKeyPath, err)
+		}
+		if _, _, _, _, err := ssh.ParseAuthorizedKey(data); err != nil {
+			return fmt.Errorf("error parsing SSH public key %v: %v", options.PublicKeyPath, err)
+		}
+		newKey = data
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	primary, err := sshCredentialStore.FindSSHPublicKeys(fi.SecretNameSSHPrimary)
+	if err != nil {
+		return fmt.Errorf("error retrieving SSH public key %q: %v", fi.SecretNameSSHPrimary, err)
+	}
+	previous, err := sshCredentialStore.FindSSHPublicKeys(fi.SecretNameSSHPrevious)
+	if err != nil {
+		return fmt.Errorf("error retrieving SSH public key %q: %v", fi.SecretNameSSHPrevious, err)
+	}
+
+	steps, err := planSSHKeyRotation(primary, previous, newKey, options.Finish)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		fmt.Fprintf(out, "%s\n", step)
+	}
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to rotate the SSH public key\n")
+		return nil
+	}
+
+	for _, step := range steps {
+		if step.Add != nil {
+			if err := sshCredentialStore.AddSSHPublicKey(step.Add.Name, []byte(step.Add.Spec.PublicKey)); err != nil {
+				return fmt.Errorf("error adding SSH public key %q: %v", step.Add.Name, err)
+			}
+		}
+		if step.Delete != nil {
+			if err := sshCredentialStore.DeleteSSHCredential(step.Delete); err != nil {
+				return fmt.Errorf("error deleting SSH public key %q: %v", step.Delete.Name, err)
+			}
+		}
+	}
+
+	updateOptions := &UpdateClusterOptions{}
+	updateOptions.InitDefaults()
+	updateOptions.ClusterName = cluster.ObjectMeta.Name
+	updateOptions.Yes = true
+	if _, err := RunUpdateCluster(ctx, f, out, updateOptions); err != nil {
+		return err
+	}
+
+	if options.Finish {
+		// The launch templates no longer refer to the key pairs of the previous keys once the cluster is updated
+		if err := deletePreviousKeyPairs(cluster, previous); err != nil {
+			return err
+		}
+	}
+
+	if options.RollingUpdate {
+		rollingUpdateOptions := &RollingUpdateOptions{}
+		rollingUpdateOptions.InitDefaults()
+		rollingUpdateOptions.ClusterName = cluster.ObjectMeta.Name
+		rollingUpdateOptions.Yes = true
+		if err := RunRollingUpdateCluster(ctx, f, out, rollingUpdateOptions); err != nil {
+			return err
+		}
+	}
+
+	if !options.Finish {
+		fmt.Fprintf(out, "\nThe previous SSH public keys remain authorized until the rotation is finished with:\n")
+		fmt.Fprintf(out, " * kops toolbox rotate-sshkey --name %s --finish --rolling-update --yes\n", cluster.ObjectMeta.Name)
+	}
+
+	return nil
+}
+
+// deletePreviousKeyPairs deletes the EC2 key pairs that kOps created for the previous SSH public keys
+func deletePreviousKeyPairs(cluster *kopsapi.Cluster, previous []*kopsapi.SSHCredential) error {
+	if kopsapi.CloudProviderID(cluster.Spec.CloudProvider) != kopsapi.CloudProviderAWS || cluster.Spec.SSHKeyName != nil {
+		return nil
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+	awsCloud := cloud.(awsup.AWSCloud)
+
+	for _, item := range previous {
+		modelContext := &model.KopsModelContext{
+			IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+			SSHPublicKeys:   [][]byte{[]byte(item.Spec.PublicKey)},
+		}
+		name, err := modelContext.SSHKeyName()
+		if err != nil {
+			return err
+		}
+
+		klog.Infof("Deleting EC2 key pair %q", name)
+		if _, err := awsCloud.EC2().DeleteKeyPair(&ec2.DeleteKeyPairInput{KeyName: aws.String(name)}); err != nil {
+			return fmt.Errorf("error deleting EC2 key pair %q: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+)
+
+const (
+	rotateSSHKeyOld = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCySdqIU+FhCWl3BNrAvPaOe5VfL2aCARUWwy91ZP+T7LBwFa9lhdttfjp/VX1D1/PVwntn2EhN079m8c2kfdmiZ/iCHqrLyIGSd+BOiCz0lT47znvANSfxYjLUuKrWWWeaXqerJkOsAD4PHchRLbZGPdbfoBKwtb/WT4GMRQmb9vmiaZYjsfdPPM9KkWI9ECoWFGjGehA8D+iYIPR711kRacb1xdYmnjHqxAZHFsb5L8wDWIeAyhy49cBD+lbzTiioq2xWLorXuFmXh6Do89PgzvHeyCLY6816f/kCX6wIFts8A2eaEHFL4rAOsuh6qHmSxGCR9peSyuRW8DxV725x justin@test"
+	rotateSSHKeyNew = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFpyraYd4rUFftiEKzUO4wKFAgTkXxuJcRZwVcsuZJ8G justin@machine"
+)
+
+func TestPlanSSHKeyRotation(t *testing.T) {
+	grid := []struct {
+		Name          string
+		Primary       []*kopsapi.SSHCredential
+		Previous      []*kopsapi.SSHCredential
+		NewKey        string
+		Finish        bool
+		ExpectedSteps []string
+		ExpectedError string
+	}{
+		{
+			Name:    "rotate",
+			Primary: []*kopsapi.SSHCredential{sshCredential("admin", rotateSSHKeyOld)},
+			NewKey:  rotateSSHKeyNew,
+			ExpectedSteps: []string{
+				`Add SSH public key "admin-previous" be:ba:ec:2b:9e:a0:68:b8:19:6b:9a:26:cc:b1:58:ff`,
+				`Delete SSH public key "admin" be:ba:ec:2b:9e:a0:68:b8:19:6b:9a:26:cc:b1:58:ff`,
+				`Add SSH public key "admin" 09:6f:84:57:26:e6:fb:38:32:0c:1a:68:3e:a9:ab:ce`,
+			},
+		},
+		{
+			Name:          "rotate to the same key",
+			Primary:       []*kopsapi.SSHCredential{sshCredential("admin", rotateSSHKeyOld)},
+			NewKey:        rotateSSHKeyOld,
+			ExpectedError: `the new SSH public key is already the "admin" SSH public key`,
+		},
+		{
+			Name:          "rotation in progress",
+			Primary:       []*kopsapi.SSHCredential{sshCredential("admin", rotateSSHKeyNew)},
+			Previous:      []*kopsapi.SSHCredential{sshCredential("admin-previous", rotateSSHKeyOld)},
+			NewKey:        rotateSSHKeyOld,
+			ExpectedError: "an SSH key rotation is already in progress; finish it with --finish first",
+		},
+		{
+			Name:     "finish",
+			Primary:  []*kopsapi.SSHCredential{sshCredential("admin", rotateSSHKeyNew)},
+			Previous: []*kopsapi.SSHCredential{sshCredential("admin-previous", rotateSSHKeyOld)},
+			Finish:   true,
+			ExpectedSteps: []string{
+				`Delete SSH public key "admin-previous" be:ba:ec:2b:9e:a0:68:b8:19:6b:9a:26:cc:b1:58:ff`,
+			},
+		},
+		{
+			Name:          "finish without rotation",
+			Primary:       []*kopsapi.SSHCredential{sshCredential("admin", rotateSSHKeyNew)},
+			Finish:        true,
+			ExpectedError: "no SSH key rotation is in progress",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			steps, err := planSSHKeyRotation(g.Primary, g.Previous, []byte(g.NewKey), g.Finish)
+			if g.ExpectedError != "" {
+				if err == nil || err.Error() != g.ExpectedError {
+					t.Fatalf("expected error %q, got %v", g.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var actual []string
+			for _, step := range steps {
+				actual = append(actual, step.String())
+			}
+			if !reflect.DeepEqual(actual, g.ExpectedSteps) {
+				t.Errorf("unexpected steps\nexpected: %q\nactual:   %q", g.ExpectedSteps, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+//go:build simulate
+// +build simulate
+
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/simulate"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxSimulateLong = templates.LongDesc(i18n.T(`
+	Simulate kops update cluster for cluster manifests, against a mock cloud and an
+	in-memory state store, and write the terraform or cloudformation it renders.
+
+	No cloud credentials or state store are needed. The SSH public key is fixed unless
+	given. Keys and certificates are generated, and kept in the directory given by
+	--keys-dir if set, so that the output only changes when the manifests or kOps
+	change: checking the output in and comparing it in CI shows how a new version of
+	kOps changes a cluster.
+
+	This command is only built into kops with the simulate build tag.`))
+
+	toolboxSimulateExample = templates.Examples(i18n.T(`
+	# Render the terraform of a cluster to a directory
+	kops toolbox simulate -f cluster.yaml --keys-dir keys --out simulated
+
+	# Render cloudformation, from a cluster and instance groups in separate files
+	kops toolbox simulate -f cluster.yaml -f instancegroups.yaml --target cloudformation --out simulated
+	`))
+
+	toolboxSimulateShort = i18n.T(`Render the output of kops update cluster for manifests against a mock cloud.`)
+)
+
+type ToolboxSimulateOptions struct {
+	// Filenames are the manifests of the cluster.
+	Filenames []string
+
+	// SSHPublicKey is the path to the SSH public key of the admin user; a fixed key is used if not set.
+	SSHPublicKey string
+
+	// Target is the format of the output, terraform or cloudformation.
+	Target string
+
+	// OutDir is the directory the output is written to.
+	OutDir string
+
+	// KeysDir is the directory the keys and certificates of the cluster are kept in between simulations.
+	KeysDir string
+}
+
+func init() {
+	optionalToolboxCommands = append(optionalToolboxCommands, NewCmdToolboxSimulate)
+}
+
+func (o *ToolboxSimulateOptions) InitDefaults() {
+	o.Target = cloudup.TargetTerraform
+}
+
+func NewCmdToolboxSimulate(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxSimulateOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "simulate",
+		Short:   toolboxSimulateShort,
+		Long:    toolboxSimulateLong,
+		Example: toolboxSimulateExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxSimulate(context.TODO(), out, options)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&options.Filenames, "filename", "f", options.Filenames, "Manifests of the cluster, its instance groups and optionally its SSH credentials")
+	cmd.MarkFlagRequired("filename")
+	cmd.RegisterFlagCompletionFunc("filename", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"yaml", "json"}, cobra.ShellCompDirectiveFilterFileExt
+	})
+	cmd.Flags().StringVar(&options.SSHPublicKey, "ssh-public-key", options.SSHPublicKey, "SSH public key to use (defaults to a fixed key)")
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, fmt.Sprintf("Format of the output. Supported values: %s, %s", cloudup.TargetTerraform, cloudup.TargetCloudformation))
+	cmd.RegisterFlagCompletionFunc("target", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{cloudup.TargetTerraform, cloudup.TargetCloudformation}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Directory to write the output to")
+	cmd.MarkFlagRequired("out")
+	cmd.Flags().StringVar(&options.KeysDir, "keys-dir", options.KeysDir, "Directory to keep the keys and certificates of the cluster in between simulations (defaults to generating new ones)")
+
+	return cmd
+}
+
+func RunToolboxSimulate(ctx context.Context, out io.Writer, options *ToolboxSimulateOptions) error {
+	if len(options.Filenames) == 0 {
+		return fmt.Errorf("at least one manifest must be specified with -f")
+	}
+	if options.OutDir == "" {
+		return fmt.Errorf("--out is required")
+	}
+
+	simulateOptions := &simulate.Options{
+		Target:  options.Target,
+		OutDir:  options.OutDir,
+		KeysDir: utils.ExpandPath(options.KeysDir),
+	}
+	for _, f := range options.Filenames {
+		var contents []byte
+		var err error
+		if f == "-" {
+			contents, err = ConsumeStdin()
+			if err != nil {
+				return err
+			}
+		} else {
+			contents, err = vfs.Context.ReadFile(f)
+			if err != nil {
+				return fmt.Errorf("error reading file %q: %v", f, err)
+			}
+		}
+		simulateOptions.Manifests = append(simulateOptions.Manifests, contents)
+	}
+	if options.SSHPublicKey != "" {
+		sshPublicKey, err := ioutil.ReadFile(utils.ExpandPath(options.SSHPublicKey))
+		if err != nil {
+			return fmt.Errorf("error reading SSH public key %q: %v", options.SSHPublicKey, err)
+		}
+		simulateOptions.SSHPublicKey = sshPublicKey
+	}
+
+	cluster, err := simulate.Run(ctx, simulateOptions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Wrote the %s of cluster %q to %s\n", simulateOptions.Target, cluster.ObjectMeta.Name, options.OutDir)
+	return nil
+}
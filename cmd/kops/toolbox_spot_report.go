@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/spot"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxSpotReportLong = templates.LongDesc(i18n.T(`
+	Summarize the spot prices and interruption risk of the instance groups that launch spot instances.
+
+	For each instance type of an instance group, the report shows the average spot price in the zones
+	of the instance group, and the zones in which the maxPrice of the instance group is below the average
+	spot price. Spot instances are rarely launched in these zones, and are often interrupted.
+
+	The frequency of interruption and the savings over on-demand instances are read from the AWS Spot
+	Instance Advisor. If its data cannot be read, the report is shown without it.`))
+
+	toolboxSpotReportExample = templates.Examples(i18n.T(`
+	# Summarize the spot instance groups of a cluster
+	kops toolbox spot-report --name k8s-cluster.example.com
+
+	# Average the spot prices over the last week
+	kops toolbox spot-report --name k8s-cluster.example.com --window 168h
+	`))
+
+	toolboxSpotReportShort = i18n.T(`Summarize the spot prices and interruption risk of instance groups`)
+)
+
+type ToolboxSpotReportOptions struct {
+	ClusterName string
+
+	// Window is the period over which spot prices are averaged.
+	Window time.Duration
+
+	// AdvisorURL is the location of the Spot Instance Advisor data. The advisor is not used if empty.
+	AdvisorURL string
+
+	Output string
+}
+
+func NewCmdToolboxSpotReport(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxSpotReportOptions{
+		Window:     spot.PriceHistoryWindow,
+		AdvisorURL: spot.DefaultAdvisorURL,
+		Output:     OutputTable,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "spot-report",
+		Short:   toolboxSpotReportShort,
+		Long:    toolboxSpotReportLong,
+		Example: toolboxSpotReportExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err := RunToolboxSpotReport(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&options.Window, "window", options.Window, "period over which spot prices are averaged")
+	cmd.Flags().StringVar(&options.AdvisorURL, "spot-advisor-url", options.AdvisorURL, "location of the Spot Instance Advisor data; empty to not report interruption frequencies")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "output format. One of: table, yaml, json")
+
+	return cmd
+}
+
+func RunToolboxSpotReport(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxSpotReportOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+	if options.Window <= 0 {
+		return fmt.Errorf("--window must be positive")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		return fmt.Errorf("spot reports are only supported on AWS")
+	}
+
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+	sort.Slice(instanceGroups, func(i, j int) bool {
+		return instanceGroups[i].ObjectMeta.Name < instanceGroups[j].ObjectMeta.Name
+	})
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	var advisor *spot.Advisor
+	if options.AdvisorURL != "" {
+		advisor, err = spot.LoadAdvisor(options.AdvisorURL)
+		if err != nil {
+			klog.Warningf("not reporting interruption frequencies: %v", err)
+		}
+	}
+
+	reports, err := spot.BuildReport(cloud.(awsup.AWSCloud), cluster, instanceGroups, advisor, options.Window)
+	if err != nil {
+		return err
+	}
+
+	switch options.Output {
+	case OutputTable:
+		if len(reports) == 0 {
+			fmt.Fprintf(out, "No instance groups launch spot instances\n")
+			return nil
+		}
+		return renderSpotReport(out, reports)
+
+	case OutputYaml:
+		b, err := kops.ToRawYaml(reports)
+		if err != nil {
+			return fmt.Errorf("error marshaling yaml: %v", err)
+		}
+		_, err = out.Write(b)
+		return err
+
+	case OutputJSON:
+		b, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling json: %v", err)
+		}
+		_, err = out.Write(b)
+		return err
+
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+}
+
+func renderSpotReport(out io.Writer, reports []*spot.InstanceTypeReport) error {
+	t := &tables.Table{}
+	t.AddColumn("INSTANCEGROUP", func(r *spot.InstanceTypeReport) string {
+		return r.InstanceGroup
+	})
+	t.AddColumn("INSTANCE TYPE", func(r *spot.InstanceTypeReport) string {
+		return r.InstanceType
+	})
+	t.AddColumn("MAX PRICE", func(r *spot.InstanceTypeReport) string {
+		if r.MaxPrice == nil {
+			return "on-demand"
+		}
+		return fmt.Sprintf("%.4f", *r.MaxPrice)
+	})
+	t.AddColumn("AVERAGE PRICE", func(r *spot.InstanceTypeReport) string {
+		if len(r.AveragePrices) == 0 {
+			return "unknown"
+		}
+		var lowest, highest float64
+		first := true
+		for _, price := range r.AveragePrices {
+			if first || price < lowest {
+				lowest = price
+			}
+			if first || price > highest {
+				highest = price
+			}
+			first = false
+		}
+		if lowest == highest {
+			return fmt.Sprintf("%.4f", lowest)
+		}
+		return fmt.Sprintf("%.4f-%.4f", lowest, highest)
+	})
+	t.AddColumn("BELOW AVERAGE", func(r *spot.InstanceTypeReport) string {
+		return strings.Join(r.BelowAverage, ",")
+	})
+	t.AddColumn("INTERRUPTION", func(r *spot.InstanceTypeReport) string {
+		if r.Interruption == nil {
+			return "unknown"
+		}
+		return r.Interruption.Label
+	})
+	t.AddColumn("SAVINGS", func(r *spot.InstanceTypeReport) string {
+		if r.Interruption == nil {
+			return "unknown"
+		}
+		return fmt.Sprintf("%d%%", r.Interruption.Savings)
+	})
+
+	return t.Render(reports, out, "INSTANCEGROUP", "INSTANCE TYPE", "MAX PRICE", "AVERAGE PRICE", "BELOW AVERAGE", "INTERRUPTION", "SAVINGS")
+}
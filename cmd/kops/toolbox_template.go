@@ -23,6 +23,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -51,6 +52,7 @@ var (
 
 	kops toolbox template \
 		--values values.yaml --values=another.yaml \
+		--values-schema values.schema.yaml \
 		--set var=value --set-string othervar=true \
 		--snippets file_or_directory --snippets=another.dir \
 		--template file_or_directory --template=directory  \
@@ -64,6 +66,7 @@ var (
 type toolboxTemplateOption struct {
 	clusterName   string
 	configPath    []string
+	schemaPath    string
 	configValue   string
 	failOnMissing bool
 	formatYAML    bool
@@ -97,6 +100,7 @@ func NewCmdToolboxTemplate(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().StringSliceVar(&options.configPath, "values", options.configPath, "Path to a configuration file containing values to include in template")
+	cmd.Flags().StringVar(&options.schemaPath, "values-schema", options.schemaPath, "Path to a JSON schema, in JSON or YAML, which the values must match")
 	cmd.Flags().StringArrayVar(&options.values, "set", options.values, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	cmd.Flags().StringArrayVar(&options.stringValues, "set-string", options.stringValues, "Set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	cmd.Flags().StringSliceVar(&options.templatePath, "template", options.templatePath, "Path to template file or directory of templates to render")
@@ -125,6 +129,17 @@ func runToolBoxTemplate(f *util.Factory, out io.Writer, options *toolboxTemplate
 		return err
 	}
 
+	// @step: check the values against the schema if any
+	if options.schemaPath != "" {
+		schema, err := ioutil.ReadFile(utils.ExpandPath(options.schemaPath))
+		if err != nil {
+			return fmt.Errorf("unable to read values schema: %s, error: %s", options.schemaPath, err)
+		}
+		if err := templater.ValidateValues(schema, context); err != nil {
+			return err
+		}
+	}
+
 	// @step: set clusterName from template's values or cli flag
 	value, ok := context["clusterName"].(string)
 	if ok {
@@ -242,8 +257,7 @@ func runToolBoxTemplate(f *util.Factory, out io.Writer, options *toolboxTemplate
 
 // newTemplateContext is responsible for loading the --values and build a context for the template
 func newTemplateContext(files []string, values []string, stringValues []string) (map[string]interface{}, error) {
-	context := make(map[string]interface{})
-
+	var valueFiles []string
 	for _, x := range files {
 		list, err := expandFiles(utils.ExpandPath(x))
 		if err != nil {
@@ -259,18 +273,17 @@ func newTemplateContext(files []string, values []string, stringValues []string)
 			if err := utils.YamlUnmarshal(content, &ctx); err != nil {
 				return nil, fmt.Errorf("unable decode the configuration file: %s, error: %v", j, err)
 			}
-
-			valueOpts := &helmvalues.Options{
-				Values:       values,
-				ValueFiles:   files,
-				StringValues: stringValues,
-			}
-
-			context, err = valueOpts.MergeValues(nil)
-			if err != nil {
-				return nil, err
-			}
 		}
+		valueFiles = append(valueFiles, list...)
+	}
+
+	// @step: merge the files in the order given, with the files in a directory in lexical order
+	valueOpts := &helmvalues.Options{
+		ValueFiles: valueFiles,
+	}
+	context, err := valueOpts.MergeValues(nil)
+	if err != nil {
+		return nil, err
 	}
 
 	// User specified a value via --set
@@ -313,6 +326,8 @@ func expandFiles(path string) ([]string, error) {
 	}); err != nil {
 		return nil, err
 	}
+	// @step: sort the files so the output does not depend on the filesystem
+	sort.Strings(list)
 
 	return list, nil
 }
@@ -0,0 +1,243 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+const (
+	// TunnelMethodBastion forwards the port over SSH through the bastion
+	TunnelMethodBastion = "Bastion"
+	// TunnelMethodSSM forwards the port with an AWS Systems Manager session
+	TunnelMethodSSM = "SSM"
+)
+
+var (
+	toolboxTunnelLong = templates.LongDesc(i18n.T(`
+	Forward a local port to the API server of a cluster that has a private API endpoint.
+
+	The port is forwarded to a running control plane instance, either over SSH through the
+	bastion (--method Bastion), or with an AWS Systems Manager port forwarding session
+	(--method SSM), which needs sessionManager enabled in the cluster, the AWS CLI and its
+	Session Manager plugin. The tunnel stays open until the command is interrupted.
+
+	kubectl must be told to connect to the local port while verifying the certificate
+	of the API server against its internal name, as printed when the tunnel is opened.`))
+
+	toolboxTunnelExample = templates.Examples(i18n.T(`
+	# Open a tunnel to the API server with Session Manager
+	kops toolbox tunnel --name k8s-cluster.example.com --method SSM
+
+	# Open a tunnel through the bastion on local port 6443
+	kops toolbox tunnel --name k8s-cluster.example.com --method Bastion --local-port 6443
+
+	# Use the tunnel from another terminal
+	kubectl --server https://127.0.0.1:8443 --tls-server-name api.internal.k8s-cluster.example.com get nodes
+	`))
+
+	toolboxTunnelShort = i18n.T(`Forward a local port to the API server of a cluster`)
+)
+
+type ToolboxTunnelOptions struct {
+	ClusterName string
+
+	Method      string
+	LocalPort   int
+	SSHUser     string
+	SSHKey      string
+	BastionHost string
+}
+
+func (o *ToolboxTunnelOptions) InitDefaults() {
+	o.LocalPort = 8443
+	o.SSHUser = "ubuntu"
+}
+
+func NewCmdToolboxTunnel(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxTunnelOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "tunnel",
+		Short:   toolboxTunnelShort,
+		Long:    toolboxTunnelLong,
+		Example: toolboxTunnelExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			err := RunToolboxTunnel(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Method, "method", options.Method, "How the port is forwarded: Bastion or SSM (defaults to SSM if the cluster has sessionManager enabled, otherwise Bastion)")
+	cmd.Flags().IntVar(&options.LocalPort, "local-port", options.LocalPort, "Local port to forward to the API server")
+	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "User to connect to the bastion as")
+	cmd.Flags().StringVarP(&options.SSHKey, "ssh-key", "i", options.SSHKey, "Private key to connect to the bastion with")
+	cmd.Flags().StringVar(&options.BastionHost, "bastion-host", options.BastionHost, "Address of the bastion (defaults to the bastionPublicName of the cluster)")
+
+	return cmd
+}
+
+func RunToolboxTunnel(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxTunnelOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+	if options.LocalPort <= 0 || options.LocalPort > 65535 {
+		return fmt.Errorf("invalid local port %d", options.LocalPort)
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	method := options.Method
+	if method == "" {
+		method = TunnelMethodBastion
+		if model.UseSessionManager(cluster) {
+			method = TunnelMethodSSM
+		}
+	}
+
+	var bastionHost string
+	switch method {
+	case TunnelMethodSSM:
+		if !model.UseSessionManager(cluster) {
+			return fmt.Errorf("cluster %q does not have sessionManager enabled", cluster.ObjectMeta.Name)
+		}
+	case TunnelMethodBastion:
+		bastionHost = options.BastionHost
+		if bastionHost == "" {
+			if cluster.Spec.Topology == nil || cluster.Spec.Topology.Bastion == nil {
+				return fmt.Errorf("cluster %q does not have a bastion", cluster.ObjectMeta.Name)
+			}
+			bastionHost = cluster.Spec.Topology.Bastion.BastionPublicName
+		}
+	default:
+		return fmt.Errorf("unknown method %q; supported methods are %s and %s", method, TunnelMethodBastion, TunnelMethodSSM)
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+	awsCloud, ok := cloud.(awsup.AWSCloud)
+	if !ok {
+		return fmt.Errorf("kops toolbox tunnel is only supported on AWS")
+	}
+
+	instance, err := findControlPlaneInstance(awsCloud, cluster.ObjectMeta.Name)
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	switch method {
+	case TunnelMethodSSM:
+		awsPath, err := exec.LookPath("aws")
+		if err != nil {
+			return fmt.Errorf("the AWS CLI is required for method %s: %v", method, err)
+		}
+		parameters := fmt.Sprintf("portNumber=443,localPortNumber=%d", options.LocalPort)
+		cmd = exec.CommandContext(ctx, awsPath, "ssm", "start-session",
+			"--target", aws.StringValue(instance.InstanceId),
+			"--document-name", "AWS-StartPortForwardingSession",
+			"--parameters", parameters,
+			"--region", awsCloud.Region())
+
+	case TunnelMethodBastion:
+		sshPath, err := exec.LookPath("ssh")
+		if err != nil {
+			return fmt.Errorf("ssh is required for method %s: %v", method, err)
+		}
+		forward := strconv.Itoa(options.LocalPort) + ":" + aws.StringValue(instance.PrivateIpAddress) + ":443"
+		args := []string{"-N", "-L", forward}
+		if options.SSHKey != "" {
+			args = append(args, "-i", options.SSHKey)
+		}
+		args = append(args, options.SSHUser+"@"+bastionHost)
+		cmd = exec.CommandContext(ctx, sshPath, args...)
+	}
+
+	fmt.Fprintf(out, "Forwarding 127.0.0.1:%d to the API server on instance %q\n", options.LocalPort, aws.StringValue(instance.InstanceId))
+	fmt.Fprintf(out, "Connect to the cluster with:\n")
+	fmt.Fprintf(out, "  kubectl --server https://127.0.0.1:%d --tls-server-name %s\n\n", options.LocalPort, cluster.Spec.MasterInternalName)
+
+	klog.V(2).Infof("Running %v", cmd.Args)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// findControlPlaneInstance returns a running control plane instance of the cluster.
+func findControlPlaneInstance(cloud awsup.AWSCloud, clusterName string) (*ec2.Instance, error) {
+	request := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			awsup.NewEC2Filter("tag:"+awsup.TagClusterName, clusterName),
+			awsup.NewEC2Filter("tag-key", awstasks.CloudTagInstanceGroupRolePrefix+strings.ToLower(string(kops.InstanceGroupRoleMaster))),
+			awsup.NewEC2Filter("instance-state-name", "running"),
+		},
+	}
+
+	var found *ec2.Instance
+	err := cloud.EC2().DescribeInstancesPages(request, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			if len(reservation.Instances) != 0 {
+				found = reservation.Instances[0]
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error finding control plane instances: %v", err)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("could not find a running control plane instance in cluster %q", clusterName)
+	}
+	return found, nil
+}
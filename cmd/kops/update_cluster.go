@@ -23,20 +23,26 @@ import (
 	"io"
 	"io/ioutil"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
+	kopsbase "k8s.io/kops"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/clusterevents"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/instancegroups"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/upup/pkg/kutil"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -55,6 +61,16 @@ var (
 	updateClusterExample = templates.Examples(i18n.T(`
 	# After the cluster has been edited or upgraded, update the cloud resources with:
 	kops update cluster k8s-cluster.example.com --yes --state=s3://my-state-store --yes
+
+	# Review the images and settings recommended by the cluster's channel, then apply them and update the cluster
+	kops update cluster k8s-cluster.example.com --channel-recommendations --state=s3://my-state-store
+	kops update cluster k8s-cluster.example.com --channel-recommendations --yes --state=s3://my-state-store
+
+	# Refuse to update the cluster if it violates the policies in a directory
+	kops update cluster k8s-cluster.example.com --policy ./policies --yes --state=s3://my-state-store
+
+	# Only add the missing tags, such as new cloudLabels, to the existing resources of the cluster
+	kops update cluster k8s-cluster.example.com --phase tags --yes --state=s3://my-state-store
 	`))
 
 	updateClusterShort = i18n.T("Update a cluster.")
@@ -69,6 +85,8 @@ type UpdateClusterOptions struct {
 	AllowKopsDowngrade bool
 	// GetAssets is whether this is invoked from the CmdGetAssets.
 	GetAssets bool
+	// Assets overrides the container registry and file repository the cluster's assets are served from.
+	Assets *kops.Assets
 
 	ClusterName string
 
@@ -76,12 +94,24 @@ type UpdateClusterOptions struct {
 	admin         time.Duration
 	user          string
 	internal      bool
+	authPlugin    bool
 
 	Phase string
 
 	// LifecycleOverrides is a slice of taskName=lifecycle name values.  This slice is used
 	// to populate the LifecycleOverrides struct member in ApplyClusterCmd struct.
 	LifecycleOverrides []string
+
+	// ChannelRecommendations applies the images and settings recommended by the cluster's channel before updating.
+	ChannelRecommendations bool
+
+	// Policies are files or directories of Rego policies that the cluster and its planned tasks must satisfy.
+	Policies []string
+
+	// SkipQuotaChecks skips checking the AWS quotas that the planned resources need.
+	SkipQuotaChecks bool
+	// RequestQuotaIncreases requests increases of the AWS quotas that do not cover the planned resources.
+	RequestQuotaIncreases bool
 }
 
 func (o *UpdateClusterOptions) InitDefaults() {
@@ -125,6 +155,7 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().StringVar(&options.user, "user", options.user, "Re-use an existing user in kubeconfig. Value must specify an existing user block in your kubeconfig file.  Implies --create-kube-config")
 	cmd.RegisterFlagCompletionFunc("user", completeKubecfgUser)
 	cmd.Flags().BoolVar(&options.internal, "internal", options.internal, "Use the cluster's internal DNS name. Implies --create-kube-config")
+	cmd.Flags().BoolVar(&options.authPlugin, "auth-plugin", options.authPlugin, "Use the kOps authentication plugin to issue short-lived credentials on demand. Implies --create-kube-config")
 	cmd.Flags().BoolVar(&options.AllowKopsDowngrade, "allow-kops-downgrade", options.AllowKopsDowngrade, "Allow an older version of kOps to update the cluster than last used")
 	cmd.Flags().StringVar(&options.Phase, "phase", options.Phase, "Subset of tasks to run: "+strings.Join(cloudup.Phases.List(), ", "))
 	cmd.RegisterFlagCompletionFunc("phase", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -134,6 +165,11 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	viper.BindPFlag("lifecycle-overrides", cmd.Flags().Lookup("lifecycle-overrides"))
 	viper.BindEnv("lifecycle-overrides", "KOPS_LIFECYCLE_OVERRIDES")
 	cmd.RegisterFlagCompletionFunc("lifecycle-overrides", completeLifecycleOverrides)
+	cmd.Flags().StringSliceVar(&options.Policies, "policy", options.Policies, "Rego policy files or directories that the cluster and its planned changes must satisfy, evaluated with the opa command")
+	cmd.MarkFlagFilename("policy", "rego")
+	cmd.Flags().BoolVar(&options.SkipQuotaChecks, "skip-quota-checks", options.SkipQuotaChecks, "Skip checking that the AWS service quotas cover the resources of the cluster")
+	cmd.Flags().BoolVar(&options.RequestQuotaIncreases, "request-quota-increases", options.RequestQuotaIncreases, "With --yes, request increases of the AWS service quotas that do not cover the resources of the cluster")
+	cmd.Flags().BoolVar(&options.ChannelRecommendations, "channel-recommendations", options.ChannelRecommendations, "Show the images and settings recommended by the cluster's channel, and with --yes apply them to the configuration before updating")
 
 	return cmd
 }
@@ -168,6 +204,15 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		c.CreateKubecfg = true
 	}
 
+	if c.authPlugin && c.user != "" {
+		return nil, fmt.Errorf("cannot use both --auth-plugin and --user")
+	}
+
+	if c.authPlugin && !c.CreateKubecfg {
+		klog.Info("--auth-plugin implies --create-kube-config")
+		c.CreateKubecfg = true
+	}
+
 	if c.user != "" && !c.CreateKubecfg {
 		klog.Info("--user implies --create-kube-config")
 		c.CreateKubecfg = true
@@ -205,11 +250,41 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		return results, err
 	}
 
+	if c.ChannelRecommendations {
+		recommended, changed, err := applyChannelRecommendations(ctx, f, out, cluster, c.Yes)
+		if err != nil {
+			return results, err
+		}
+		if changed && !c.Yes {
+			fmt.Fprintf(out, "\nMust specify --yes to apply the recommended changes and update the cluster\n")
+			return results, nil
+		}
+		cluster = recommended
+	}
+
+	if c.Assets != nil {
+		overrideAssetLocations(cluster, c.Assets)
+	}
+
 	clientset, err := f.Clientset()
 	if err != nil {
 		return results, err
 	}
 
+	if targetName == cloudup.TargetDirect {
+		configBase, err := clientset.ConfigBaseFor(cluster)
+		if err != nil {
+			return results, err
+		}
+		shutdown, err := instancegroups.ReadResumeManifest(configBase)
+		if err != nil {
+			return results, err
+		}
+		if shutdown != nil {
+			return results, fmt.Errorf("cluster %q is shut down; resume it with kops resume cluster before updating it", cluster.ObjectMeta.Name)
+		}
+	}
+
 	keyStore, err := clientset.KeyStore(cluster)
 	if err != nil {
 		return results, err
@@ -250,6 +325,8 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 			phase = cloudup.PhaseSecurity
 		case string(cloudup.PhaseCluster):
 			phase = cloudup.PhaseCluster
+		case string(cloudup.PhaseTags):
+			phase = cloudup.PhaseTags
 		default:
 			return results, fmt.Errorf("unknown phase %q, available phases: %s", c.Phase, strings.Join(cloudup.Phases.List(), ","))
 		}
@@ -291,9 +368,30 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		TargetName:         targetName,
 		LifecycleOverrides: lifecycleOverrideMap,
 		GetAssets:          c.GetAssets,
+		Policies:           c.Policies,
+
+		SkipQuotaChecks:       c.SkipQuotaChecks,
+		RequestQuotaIncreases: c.RequestQuotaIncreases,
 	}
 
-	if err := applyCmd.Run(ctx); err != nil {
+	err = applyCmd.Run(ctx)
+	if !isDryrun {
+		event := clusterevents.NewEvent(cluster, clusterevents.ClusterUpdated, "cluster updated")
+		event.Details = map[string]string{
+			"target":            targetName,
+			"kubernetesVersion": cluster.Spec.KubernetesVersion,
+			"kopsVersion":       kopsbase.Version,
+		}
+		if phase != "" {
+			event.Details["phase"] = string(phase)
+		}
+		if err != nil {
+			event.Message = "cluster update failed"
+			event.Error = err.Error()
+		}
+		publishClusterEvent(ctx, cluster, cloud, event)
+	}
+	if err != nil {
 		return results, err
 	}
 
@@ -303,6 +401,10 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 	results.FileAssets = applyCmd.FileAssets
 	results.Cluster = cluster
 
+	if phase == cloudup.PhaseTags {
+		return results, printTagChanges(out, applyCmd.TagChanges, isDryrun)
+	}
+
 	if isDryrun && !c.GetAssets {
 		target := applyCmd.Target.(*fi.DryRunTarget)
 		if target.HasChanges() {
@@ -325,8 +427,6 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 
 		klog.Infof("Exporting kubecfg for cluster")
 
-		// TODO: Another flag?
-		useKopsAuthenticationPlugin := false
 		conf, err := kubeconfig.BuildKubecfg(
 			cluster,
 			keyStore,
@@ -336,7 +436,7 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 			c.user,
 			c.internal,
 			f.KopsStateStore(),
-			useKopsAuthenticationPlugin)
+			c.authPlugin)
 		if err != nil {
 			return nil, err
 		}
@@ -346,7 +446,7 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 			return nil, err
 		}
 
-		if c.admin == 0 && c.user == "" {
+		if c.admin == 0 && c.user == "" && !c.authPlugin {
 			klog.Warningf("Exported kubecfg with no user authentication; use --admin, --user or --auth-plugin flags with `kops export kubecfg`")
 		}
 	}
@@ -423,6 +523,35 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 	return results, nil
 }
 
+// printTagChanges prints the tags added to the existing resources by the tags phase
+func printTagChanges(out io.Writer, changes []*awstasks.TagChange, dryRun bool) error {
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "No tag changes need to be applied\n")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "TYPE\tID\tNAME\tTAGS\n")
+	for _, change := range changes {
+		var tags []string
+		for k, v := range change.Tags {
+			tags = append(tags, k+"="+v)
+		}
+		sort.Strings(tags)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", change.ResourceType, change.ID, change.Name, strings.Join(tags, ","))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Fprintf(out, "\nMust specify --yes to apply changes\n")
+	} else {
+		fmt.Fprintf(out, "\nTag changes have been applied to the cloud.\n")
+	}
+	return nil
+}
+
 func parseLifecycle(lifecycle string) (fi.Lifecycle, error) {
 	if v, ok := fi.LifecycleNameMap[lifecycle]; ok {
 		return v, nil
@@ -489,3 +618,18 @@ func completeLifecycleOverrides(cmd *cobra.Command, args []string, toComplete st
 	}
 	return completions, cobra.ShellCompDirectiveNoFileComp
 }
+
+// overrideAssetLocations sets the container registry and file repository of the cluster to those set in assets.
+// A container registry replaces any container proxy, as the two cannot be used together.
+func overrideAssetLocations(cluster *kops.Cluster, assets *kops.Assets) {
+	if cluster.Spec.Assets == nil {
+		cluster.Spec.Assets = &kops.Assets{}
+	}
+	if assets.ContainerRegistry != nil {
+		cluster.Spec.Assets.ContainerRegistry = assets.ContainerRegistry
+		cluster.Spec.Assets.ContainerProxy = nil
+	}
+	if assets.FileRepository != nil {
+		cluster.Spec.Assets.FileRepository = assets.FileRepository
+	}
+}
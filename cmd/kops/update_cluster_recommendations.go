@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	kopsutil "k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/reflectutils"
+)
+
+// applyChannelRecommendations applies the recommendations of the cluster's channel to the cluster and its instance groups,
+// printing the changes as a diff of their manifests. The changes are written to the state store if write is true.
+// It returns the cluster with the recommendations applied, and whether anything changed.
+func applyChannelRecommendations(ctx context.Context, f *util.Factory, out io.Writer, cluster *kops.Cluster, write bool) (*kops.Cluster, bool, error) {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return nil, false, err
+	}
+
+	instanceGroups, err := commands.ReadAllInstanceGroups(ctx, clientset, cluster)
+	if err != nil {
+		return nil, false, err
+	}
+
+	channelLocation := cluster.Spec.Channel
+	if channelLocation == "" {
+		channelLocation = kops.DefaultChannel
+	}
+	channel, err := kops.LoadChannel(channelLocation)
+	if err != nil {
+		return nil, false, fmt.Errorf("error loading channel %q: %v", channelLocation, err)
+	}
+
+	kubernetesVersion, err := kopsutil.ParseKubernetesVersion(cluster.Spec.KubernetesVersion)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to parse kubernetesVersion %q: %v", cluster.Spec.KubernetesVersion, err)
+	}
+
+	recommendedCluster := cluster.DeepCopy()
+	var recommendedInstanceGroups []*kops.InstanceGroup
+	for _, ig := range instanceGroups {
+		recommendedInstanceGroups = append(recommendedInstanceGroups, ig.DeepCopy())
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The images and settings an upgrade would apply, without changing the Kubernetes version
+	for _, action := range buildUpgradeActions(recommendedCluster, recommendedInstanceGroups, channel, cloud, kubernetesVersion) {
+		action.apply()
+	}
+
+	recommendations := channel.FindRecommendations(*kubernetesVersion)
+	for _, recommendation := range recommendations {
+		if recommendation.Cluster != nil {
+			reflectutils.JSONMergeStruct(&recommendedCluster.Spec, recommendation.Cluster)
+		}
+	}
+
+	var diffs bytes.Buffer
+	clusterDiff, err := diffManifests(cluster, recommendedCluster)
+	if err != nil {
+		return nil, false, err
+	}
+	if clusterDiff != "" {
+		fmt.Fprintf(&diffs, "\nCluster %s:\n%s", cluster.ObjectMeta.Name, clusterDiff)
+	}
+	for i, ig := range instanceGroups {
+		igDiff, err := diffManifests(ig, recommendedInstanceGroups[i])
+		if err != nil {
+			return nil, false, err
+		}
+		if igDiff != "" {
+			fmt.Fprintf(&diffs, "\nInstanceGroup %s:\n%s", ig.ObjectMeta.Name, igDiff)
+		}
+	}
+
+	if diffs.Len() == 0 {
+		fmt.Fprintf(out, "No changes recommended by channel %q\n", channelLocation)
+		return cluster, false, nil
+	}
+
+	fmt.Fprintf(out, "Changes recommended by channel %q:\n", channelLocation)
+	for _, recommendation := range recommendations {
+		if recommendation.Description != "" {
+			fmt.Fprintf(out, "  * %s: %s\n", recommendation.Name, recommendation.Description)
+		}
+	}
+	if _, err := diffs.WriteTo(out); err != nil {
+		return nil, false, err
+	}
+
+	if !write {
+		return cluster, true, nil
+	}
+
+	if err := commands.UpdateCluster(ctx, clientset, recommendedCluster, recommendedInstanceGroups); err != nil {
+		return nil, false, err
+	}
+	fmt.Fprintf(out, "\nApplied the changes recommended by channel %q to the configuration\n", channelLocation)
+
+	return recommendedCluster, true, nil
+}
+
+// diffManifests returns the differences between the manifests of two versions of an object, or an empty string if they are the same
+func diffManifests(original, recommended runtime.Object) (string, error) {
+	originalYAML, err := kopscodecs.ToVersionedYaml(original)
+	if err != nil {
+		return "", err
+	}
+	recommendedYAML, err := kopscodecs.ToVersionedYaml(recommended)
+	if err != nil {
+		return "", err
+	}
+	if string(originalYAML) == string(recommendedYAML) {
+		return "", nil
+	}
+	return diff.FormatDiff(string(originalYAML), string(recommendedYAML)), nil
+}
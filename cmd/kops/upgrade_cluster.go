@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/spf13/cobra"
@@ -30,11 +31,13 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	kopsutil "k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/pretty"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -45,11 +48,22 @@ var (
 	Automates checking for and applying Kubernetes updates. This upgrades a cluster to the latest recommended
 	production ready Kubernetes version. After this command is run, use ` + pretty.Bash("kops update cluster") + ` and ` + pretty.Bash("kops rolling-update cluster") + `
 	to finish a cluster upgrade.
+
+	Upgrades across several minor versions are planned as a sequence of steps, one minor version at a time,
+	as required by the Kubernetes version skew policy. ` + pretty.Bash("--yes") + ` applies the first step of the plan; run the command
+	again after the rolling update for the next step. With ` + pretty.Bash("--execute") + `, kOps performs every step, updating the cluster,
+	rolling the control plane and then the nodes, and validating the cluster in between.
 	`))
 
 	upgradeClusterExample = templates.Examples(i18n.T(`
 	# Upgrade a cluster's Kubernetes version.
 	kops upgrade cluster k8s-cluster.example.com --yes --state=s3://my-state-store
+
+	# Show the steps to upgrade a cluster to a specific Kubernetes version.
+	kops upgrade cluster k8s-cluster.example.com --kubernetes-version 1.21.2 --state=s3://my-state-store
+
+	# Perform every step of the upgrade, validating the cluster after each rolling update.
+	kops upgrade cluster k8s-cluster.example.com --kubernetes-version 1.21.2 --execute --state=s3://my-state-store
 	`))
 
 	upgradeClusterShort = i18n.T("Upgrade a kubernetes cluster.")
@@ -59,10 +73,26 @@ type UpgradeClusterOptions struct {
 	ClusterName string
 	Yes         bool
 	Channel     string
+
+	// KubernetesVersion is the version to upgrade to. Defaults to the version recommended by the channel.
+	KubernetesVersion string
+	// Execute performs every step of the upgrade plan, updating, rolling and validating the cluster after each one.
+	Execute bool
+	// ValidationTimeout is how long to wait for the cluster to validate after each rolling update.
+	ValidationTimeout time.Duration
+	// AllowRemovedAPIs upgrades the cluster even if it uses APIs removed in the target Kubernetes version.
+	AllowRemovedAPIs bool
+	// SkipClusterChecks upgrades the cluster without checking its nodes and API usage, for clusters that cannot be reached.
+	SkipClusterChecks bool
+}
+
+func (o *UpgradeClusterOptions) InitDefaults() {
+	o.ValidationTimeout = 15 * time.Minute
 }
 
 func NewCmdUpgradeCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	options := &UpgradeClusterOptions{}
+	options.InitDefaults()
 
 	cmd := &cobra.Command{
 		Use:               "cluster [CLUSTER]",
@@ -81,6 +111,12 @@ func NewCmdUpgradeCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", false, "Apply update")
 	cmd.Flags().StringVar(&options.Channel, "channel", "", "Channel to use for upgrade")
 	cmd.RegisterFlagCompletionFunc("channel", completeChannel)
+	cmd.Flags().StringVar(&options.KubernetesVersion, "kubernetes-version", options.KubernetesVersion, "Kubernetes version to upgrade to (defaults to the version recommended by the channel)")
+	cmd.RegisterFlagCompletionFunc("kubernetes-version", completeKubernetesVersion)
+	cmd.Flags().BoolVar(&options.Execute, "execute", options.Execute, "Perform every step of the upgrade: update the cluster, roll the control plane and then the nodes, validating the cluster after each rolling update")
+	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for the cluster to validate after each rolling update, with --execute")
+	cmd.Flags().BoolVar(&options.AllowRemovedAPIs, "allow-removed-apis", options.AllowRemovedAPIs, "Upgrade even if the cluster uses APIs that are removed in the target Kubernetes version")
+	cmd.Flags().BoolVar(&options.SkipClusterChecks, "skip-cluster-checks", options.SkipClusterChecks, "Upgrade without checking the version skew of the nodes and the usage of removed APIs, for clusters that cannot be reached")
 
 	return cmd
 }
@@ -140,12 +176,6 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 		return fmt.Errorf("error loading channel %q: %v", channelLocation, err)
 	}
 
-	channelClusterSpec := channel.Spec.Cluster
-	if channelClusterSpec == nil {
-		// Just to prevent too much nil handling
-		channelClusterSpec = &kopsapi.ClusterSpec{}
-	}
-
 	var currentKubernetesVersion *semver.Version
 	{
 		sv, err := kopsutil.ParseKubernetesVersion(cluster.Spec.KubernetesVersion)
@@ -157,6 +187,16 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 	}
 
 	proposedKubernetesVersion := kopsapi.RecommendedKubernetesVersion(channel, kops.Version)
+	if options.KubernetesVersion != "" {
+		sv, err := kopsutil.ParseKubernetesVersion(options.KubernetesVersion)
+		if err != nil {
+			return fmt.Errorf("unable to parse --kubernetes-version %q: %v", options.KubernetesVersion, err)
+		}
+		if currentKubernetesVersion != nil && sv.LT(*currentKubernetesVersion) {
+			return fmt.Errorf("cannot downgrade cluster from Kubernetes %s to %s", currentKubernetesVersion, sv)
+		}
+		proposedKubernetesVersion = sv
+	}
 
 	// We won't propose a downgrade
 	// TODO: What if a kubernetes version is bad?
@@ -165,21 +205,20 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 		proposedKubernetesVersion = currentKubernetesVersion
 	}
 
-	if proposedKubernetesVersion != nil && currentKubernetesVersion != nil && currentKubernetesVersion.NE(*proposedKubernetesVersion) {
-		actions = append(actions, &upgradeAction{
-			Item:     "Cluster",
-			Property: "KubernetesVersion",
-			Old:      cluster.Spec.KubernetesVersion,
-			New:      proposedKubernetesVersion.String(),
-			apply: func() {
-				cluster.Spec.KubernetesVersion = proposedKubernetesVersion.String()
-			},
-		})
+	// Upgrade one minor version at a time
+	var steps []semver.Version
+	if proposedKubernetesVersion != nil && currentKubernetesVersion != nil {
+		steps = planKubernetesUpgrade(channel, *currentKubernetesVersion, *proposedKubernetesVersion)
+	}
+
+	stepKubernetesVersion := proposedKubernetesVersion
+	if len(steps) != 0 {
+		stepKubernetesVersion = &steps[0]
 	}
 
 	// For further calculations, default to the current kubernetes version
-	if proposedKubernetesVersion == nil {
-		proposedKubernetesVersion = currentKubernetesVersion
+	if stepKubernetesVersion == nil {
+		stepKubernetesVersion = currentKubernetesVersion
 	}
 
 	cloud, err := cloudup.BuildCloud(cluster)
@@ -187,15 +226,159 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 		return err
 	}
 
+	actions = append(actions, buildUpgradeActions(cluster, instanceGroups, channel, cloud, stepKubernetesVersion)...)
+
+	if len(actions) == 0 {
+		// TODO: Allow --force option to force even if not needed?
+		// Note stderr - we try not to print to stdout if no update is needed
+		fmt.Fprintf(os.Stderr, "\nNo upgrade required\n")
+		return nil
+	}
+
+	if len(steps) > 1 {
+		fmt.Fprintf(out, "Upgrading to Kubernetes %s one minor version at a time, rolling the control plane and then the nodes at each step:\n\n", proposedKubernetesVersion)
+		for i, step := range steps {
+			fmt.Fprintf(out, "  %d. %s\n", i+1, step)
+		}
+		fmt.Fprintf(out, "\nStep 1:\n")
+	}
+
+	// If the current version is unknown, the checks cannot be skipped on the grounds that the version does not change
+	if stepKubernetesVersion != nil && (currentKubernetesVersion == nil || stepKubernetesVersion.NE(*currentKubernetesVersion)) {
+		if err := checkClusterVersionSkew(ctx, cluster.ObjectMeta.Name, *stepKubernetesVersion, options.SkipClusterChecks); err != nil {
+			return err
+		}
+	}
+
+	if proposedKubernetesVersion != nil && (currentKubernetesVersion == nil || proposedKubernetesVersion.Major != currentKubernetesVersion.Major || proposedKubernetesVersion.Minor > currentKubernetesVersion.Minor) {
+		if err := checkRemovedAPIs(ctx, out, cluster.ObjectMeta.Name, *proposedKubernetesVersion, options.AllowRemovedAPIs, options.SkipClusterChecks); err != nil {
+			return err
+		}
+	}
+
+	if err := renderUpgradeActions(out, actions); err != nil {
+		return err
+	}
+
+	if !options.Yes && !options.Execute {
+		fmt.Printf("\nMust specify --yes to perform upgrade\n")
+		if len(steps) > 1 {
+			fmt.Printf("or --execute to perform every step of the upgrade\n")
+		}
+		return nil
+	}
+
+	if err := applyUpgradeActions(ctx, clientset, cluster, instanceGroups, actions); err != nil {
+		return err
+	}
+
+	if !options.Execute {
+		fmt.Printf("\nUpdates applied to configuration.\n")
+
+		// TODO: automate this step
+		fmt.Printf("You can now apply these changes, using `kops update cluster %s`\n", cluster.ObjectMeta.Name)
+		if len(steps) > 1 {
+			fmt.Printf("This is step 1 of %d; once the rolling update is complete, run `kops upgrade cluster %s` again for the next step\n", len(steps), cluster.ObjectMeta.Name)
+		}
+		return nil
+	}
+
+	if len(steps) == 0 {
+		// There is no new Kubernetes version, but the other changes still need rolling out
+		if err := executeUpgradeStep(ctx, f, out, options, cluster.ObjectMeta.Name); err != nil {
+			return err
+		}
+	}
+	for i := range steps {
+		if i > 0 {
+			// The previous step has replaced all the nodes, so this is the version skew gate for the next step
+			if err := checkClusterVersionSkew(ctx, cluster.ObjectMeta.Name, steps[i], options.SkipClusterChecks); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "\nStep %d:\n", i+1)
+			actions := buildUpgradeActions(cluster, instanceGroups, channel, cloud, &steps[i])
+			if err := renderUpgradeActions(out, actions); err != nil {
+				return err
+			}
+			if err := applyUpgradeActions(ctx, clientset, cluster, instanceGroups, actions); err != nil {
+				return err
+			}
+		}
+
+		if err := executeUpgradeStep(ctx, f, out, options, cluster.ObjectMeta.Name); err != nil {
+			return fmt.Errorf("upgrade to Kubernetes %s failed: %v", steps[i], err)
+		}
+	}
+
+	fmt.Fprintf(out, "\nCluster %s has been upgraded, and is running Kubernetes %s\n", cluster.ObjectMeta.Name, cluster.Spec.KubernetesVersion)
+
+	return nil
+}
+
+// buildUpgradeActions returns the changes to the cluster and instance groups needed for the given Kubernetes version
+func buildUpgradeActions(cluster *kopsapi.Cluster, instanceGroups []*kopsapi.InstanceGroup, channel *kopsapi.Channel, cloud fi.Cloud, kubernetesVersion *semver.Version) []*upgradeAction {
+	var actions []*upgradeAction
+
+	channelClusterSpec := channel.Spec.Cluster
+	if channelClusterSpec == nil {
+		// Just to prevent too much nil handling
+		channelClusterSpec = &kopsapi.ClusterSpec{}
+	}
+
+	if kubernetesVersion != nil && kubernetesVersion.String() != cluster.Spec.KubernetesVersion {
+		currentKubernetesVersion, err := kopsutil.ParseKubernetesVersion(cluster.Spec.KubernetesVersion)
+		if err == nil && currentKubernetesVersion.NE(*kubernetesVersion) {
+			actions = append(actions, &upgradeAction{
+				Item:     "Cluster",
+				Property: "KubernetesVersion",
+				Old:      cluster.Spec.KubernetesVersion,
+				New:      kubernetesVersion.String(),
+				apply: func() {
+					cluster.Spec.KubernetesVersion = kubernetesVersion.String()
+				},
+			})
+		}
+	}
+
 	// Prompt to upgrade image
-	if proposedKubernetesVersion != nil {
+	if kubernetesVersion != nil {
+		if defaults := cluster.Spec.InstanceGroupDefaults; defaults != nil && channel.HasUpstreamImagePrefix(defaults.Image) {
+			// Instance groups that do not set a machine type get one of the default architecture
+			architecture := architectures.ArchitectureAmd64
+			var err error
+			if defaults.MachineType != "" {
+				architecture, err = cloudup.MachineArchitecture(cloud, defaults.MachineType)
+			}
+			if err != nil {
+				klog.Warningf("Error finding architecture for machine type %q: %v", defaults.MachineType, err)
+			} else if image := channel.FindImage(cloud.ProviderID(), *kubernetesVersion, architecture); image == nil {
+				klog.Warningf("No matching images specified in channel; cannot prompt for upgrade")
+			} else if defaults.Image != image.Name {
+				actions = append(actions, &upgradeAction{
+					Item:     "Cluster",
+					Property: "InstanceGroupDefaults.Image",
+					Old:      defaults.Image,
+					New:      image.Name,
+					apply: func() {
+						defaults.Image = image.Name
+					},
+				})
+			}
+		}
+
 		for _, ig := range instanceGroups {
-			architecture, err := cloudup.MachineArchitecture(cloud, ig.Spec.MachineType)
+			if ig.Spec.Image == "" && cluster.Spec.InstanceGroupDefaults != nil && cluster.Spec.InstanceGroupDefaults.Image != "" {
+				// The image is inherited from the instance group defaults
+				continue
+			}
+			machineType := cluster.InstanceGroupWithDefaults(ig).Spec.MachineType
+			architecture, err := cloudup.MachineArchitecture(cloud, machineType)
 			if err != nil {
-				klog.Warningf("Error finding architecture for machine type %q: %v", ig.Spec.MachineType, err)
+				klog.Warningf("Error finding architecture for machine type %q: %v", machineType, err)
 				continue
 			}
-			image := channel.FindImage(cloud.ProviderID(), *proposedKubernetesVersion, architecture)
+			image := channel.FindImage(cloud.ProviderID(), *kubernetesVersion, architecture)
 			if image == nil {
 				klog.Warningf("No matching images specified in channel; cannot prompt for upgrade")
 				continue
@@ -241,38 +424,29 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 		}
 	}
 
-	if len(actions) == 0 {
-		// TODO: Allow --force option to force even if not needed?
-		// Note stderr - we try not to print to stdout if no update is needed
-		fmt.Fprintf(os.Stderr, "\nNo upgrade required\n")
-		return nil
-	}
-
-	{
-		t := &tables.Table{}
-		t.AddColumn("ITEM", func(a *upgradeAction) string {
-			return a.Item
-		})
-		t.AddColumn("PROPERTY", func(a *upgradeAction) string {
-			return a.Property
-		})
-		t.AddColumn("OLD", func(a *upgradeAction) string {
-			return a.Old
-		})
-		t.AddColumn("NEW", func(a *upgradeAction) string {
-			return a.New
-		})
+	return actions
+}
 
-		err := t.Render(actions, out, "ITEM", "PROPERTY", "OLD", "NEW")
-		if err != nil {
-			return err
-		}
-	}
+func renderUpgradeActions(out io.Writer, actions []*upgradeAction) error {
+	t := &tables.Table{}
+	t.AddColumn("ITEM", func(a *upgradeAction) string {
+		return a.Item
+	})
+	t.AddColumn("PROPERTY", func(a *upgradeAction) string {
+		return a.Property
+	})
+	t.AddColumn("OLD", func(a *upgradeAction) string {
+		return a.Old
+	})
+	t.AddColumn("NEW", func(a *upgradeAction) string {
+		return a.New
+	})
+
+	return t.Render(actions, out, "ITEM", "PROPERTY", "OLD", "NEW")
+}
 
-	if !options.Yes {
-		fmt.Printf("\nMust specify --yes to perform upgrade\n")
-		return nil
-	}
+// applyUpgradeActions applies the actions and writes the cluster and instance groups to the state store
+func applyUpgradeActions(ctx context.Context, clientset simple.Clientset, cluster *kopsapi.Cluster, instanceGroups []*kopsapi.InstanceGroup, actions []*upgradeAction) error {
 	for _, action := range actions {
 		action.apply()
 	}
@@ -288,10 +462,94 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 		}
 	}
 
-	fmt.Printf("\nUpdates applied to configuration.\n")
+	return nil
+}
+
+// checkClusterVersionSkew checks the cluster's nodes can run with a control plane at the given version.
+// If the nodes cannot be listed the upgrade is refused, unless skip is set.
+func checkClusterVersionSkew(ctx context.Context, clusterName string, controlPlane semver.Version, skip bool) error {
+	if skip {
+		klog.Warningf("not checking the kubelet versions against the version skew policy")
+		return nil
+	}
+	kubeletVersions, err := getKubeletVersions(ctx, clusterName)
+	if err != nil {
+		return fmt.Errorf("unable to check the kubelet versions against the version skew policy: %v; specify --skip-cluster-checks to upgrade anyway", err)
+	}
+	return checkKubeletVersionSkew(controlPlane, kubeletVersions)
+}
+
+// checkRemovedAPIs refuses the upgrade if the cluster uses APIs that are removed in the target version, unless allowed.
+// If the cluster cannot be checked the upgrade is refused, unless skip is set.
+func checkRemovedAPIs(ctx context.Context, out io.Writer, clusterName string, target semver.Version, allow bool, skip bool) error {
+	if skip {
+		klog.Warningf("not checking the cluster for usage of APIs removed in Kubernetes %s", target)
+		return nil
+	}
+	findings, err := scanRemovedAPIs(ctx, clusterName, target)
+	if err != nil {
+		return fmt.Errorf("unable to check the cluster for usage of APIs removed in Kubernetes %s: %v; specify --skip-cluster-checks to upgrade anyway", target, err)
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(out, "The cluster uses APIs that are removed in Kubernetes %s:\n\n", target)
+	if err := renderRemovedAPIs(out, findings); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n")
+
+	if allow {
+		klog.Warningf("upgrading even though %d removed APIs are in use", len(findings))
+		return nil
+	}
+	return fmt.Errorf("%d APIs removed in Kubernetes %s are in use; update the clients and manifests using them, or specify --allow-removed-apis to upgrade anyway", len(findings), target)
+}
 
-	// TODO: automate this step
-	fmt.Printf("You can now apply these changes, using `kops update cluster %s`\n", cluster.ObjectMeta.Name)
+// executeUpgradeStep applies the configuration in the state store to the cloud, then rolls and validates
+// the control plane, followed by the other instance groups
+func executeUpgradeStep(ctx context.Context, f *util.Factory, out io.Writer, options *UpgradeClusterOptions, clusterName string) error {
+	updateOptions := &UpdateClusterOptions{}
+	updateOptions.InitDefaults()
+	updateOptions.ClusterName = clusterName
+	updateOptions.Yes = true
+	if _, err := RunUpdateCluster(ctx, f, out, updateOptions); err != nil {
+		return err
+	}
+
+	phases := []struct {
+		Name  string
+		Roles []string
+	}{
+		{Name: "control plane", Roles: []string{string(kopsapi.InstanceGroupRoleMaster), string(kopsapi.InstanceGroupRoleAPIServer)}},
+		{Name: "nodes", Roles: []string{string(kopsapi.InstanceGroupRoleNode), string(kopsapi.InstanceGroupRoleBastion)}},
+	}
+	for _, phase := range phases {
+		fmt.Fprintf(out, "\nRolling the %s\n", phase.Name)
+
+		rollingUpdateOptions := &RollingUpdateOptions{}
+		rollingUpdateOptions.InitDefaults()
+		rollingUpdateOptions.ClusterName = clusterName
+		rollingUpdateOptions.Yes = true
+		rollingUpdateOptions.ValidationTimeout = options.ValidationTimeout
+		rollingUpdateOptions.InstanceGroupRoles = phase.Roles
+		if err := RunRollingUpdateCluster(ctx, f, out, rollingUpdateOptions); err != nil {
+			return fmt.Errorf("rolling update of the %s failed: %v", phase.Name, err)
+		}
+
+		validateOptions := &ValidateClusterOptions{}
+		validateOptions.InitDefaults()
+		validateOptions.ClusterName = clusterName
+		validateOptions.wait = options.ValidationTimeout
+		result, err := RunValidateCluster(ctx, f, out, validateOptions)
+		if err != nil {
+			return fmt.Errorf("cluster did not validate after rolling the %s: %v", phase.Name, err)
+		}
+		if len(result.Failures) != 0 {
+			return fmt.Errorf("cluster did not validate after rolling the %s", phase.Name)
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	kopsutil "k8s.io/kops/pkg/apis/kops/util"
+)
+
+// maxKubeletSkew is the number of minor versions the kubelet may be older than the kube-apiserver,
+// as per the Kubernetes version skew policy
+const maxKubeletSkew = 2
+
+// planKubernetesUpgrade returns the versions to upgrade through to get from current to target,
+// one minor version at a time. Intermediate steps use the version recommended by the channel for that minor.
+func planKubernetesUpgrade(channel *kopsapi.Channel, current semver.Version, target semver.Version) []semver.Version {
+	if target.LTE(current) {
+		return nil
+	}
+
+	var steps []semver.Version
+	if target.Major == current.Major {
+		for minor := current.Minor + 1; minor < target.Minor; minor++ {
+			step := semver.Version{Major: current.Major, Minor: minor}
+			if spec := kopsapi.FindKubernetesVersionSpec(channel.Spec.KubernetesVersions, step); spec != nil && spec.RecommendedVersion != "" {
+				recommended, err := kopsutil.ParseKubernetesVersion(spec.RecommendedVersion)
+				if err == nil && recommended.Major == step.Major && recommended.Minor == step.Minor {
+					step = *recommended
+				}
+			}
+			steps = append(steps, step)
+		}
+	}
+
+	return append(steps, target)
+}
+
+// checkKubeletVersionSkew checks that the kubelets are supported by a control plane running the given version:
+// a kubelet must not be newer than the control plane, nor more than maxKubeletSkew minor versions older
+func checkKubeletVersionSkew(controlPlane semver.Version, kubeletVersions map[string]string) error {
+	var problems []string
+	for node, version := range kubeletVersions {
+		kubelet, err := kopsutil.ParseKubernetesVersion(version)
+		if err != nil {
+			return fmt.Errorf("unable to parse kubelet version %q of node %q: %v", version, node, err)
+		}
+
+		if kubelet.Major != controlPlane.Major || kubelet.Minor > controlPlane.Minor {
+			problems = append(problems, fmt.Sprintf("node %q has kubelet %s, which is newer than %s", node, kubelet, controlPlane))
+		} else if controlPlane.Minor-kubelet.Minor > maxKubeletSkew {
+			problems = append(problems, fmt.Sprintf("node %q has kubelet %s, which is more than %d minor versions older than %s", node, kubelet, maxKubeletSkew, controlPlane))
+		}
+	}
+
+	if len(problems) != 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("version skew policy would be violated by upgrading the control plane to %s:\n  %s", controlPlane, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// getKubeletVersions returns the kubelet version of each node, using the kubeconfig context named after the cluster
+func getKubeletVersions(ctx context.Context, contextName string) (map[string]string, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: contextName}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build kubernetes api client for %q: %v", contextName, err)
+	}
+
+	nodes, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes in cluster: %v", err)
+	}
+
+	versions := make(map[string]string)
+	for _, node := range nodes.Items {
+		versions[node.Name] = node.Status.NodeInfo.KubeletVersion
+	}
+	return versions, nil
+}
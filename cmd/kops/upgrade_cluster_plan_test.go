@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+)
+
+func TestPlanKubernetesUpgrade(t *testing.T) {
+	channel := &kopsapi.Channel{
+		Spec: kopsapi.ChannelSpec{
+			KubernetesVersions: []kopsapi.KubernetesVersionSpec{
+				{Range: ">=1.21.0", RecommendedVersion: "1.21.2"},
+				{Range: ">=1.20.0", RecommendedVersion: "1.20.8"},
+				{Range: ">=1.19.0", RecommendedVersion: "1.19.12"},
+			},
+		},
+	}
+
+	grid := []struct {
+		Current  string
+		Target   string
+		Expected []string
+	}{
+		{Current: "1.21.2", Target: "1.21.2", Expected: nil},
+		{Current: "1.21.2", Target: "1.20.8", Expected: nil},
+		{Current: "1.21.0", Target: "1.21.2", Expected: []string{"1.21.2"}},
+		{Current: "1.20.1", Target: "1.21.2", Expected: []string{"1.21.2"}},
+		{Current: "1.18.3", Target: "1.21.2", Expected: []string{"1.19.12", "1.20.8", "1.21.2"}},
+		// Without a recommendation for 1.23 in the channel, the first patch release is used
+		{Current: "1.22.1", Target: "1.24.0", Expected: []string{"1.23.0", "1.24.0"}},
+	}
+	for _, g := range grid {
+		steps := planKubernetesUpgrade(channel, semver.MustParse(g.Current), semver.MustParse(g.Target))
+		var actual []string
+		for _, step := range steps {
+			actual = append(actual, step.String())
+		}
+		if !reflect.DeepEqual(actual, g.Expected) {
+			t.Errorf("upgrade from %s to %s: expected %v, got %v", g.Current, g.Target, g.Expected, actual)
+		}
+	}
+}
+
+func TestCheckKubeletVersionSkew(t *testing.T) {
+	grid := []struct {
+		ControlPlane string
+		Kubelets     map[string]string
+		ExpectError  string
+	}{
+		{
+			ControlPlane: "1.21.2",
+			Kubelets:     map[string]string{"a": "v1.21.0", "b": "v1.20.8", "c": "v1.19.12"},
+		},
+		{
+			ControlPlane: "1.21.2",
+			Kubelets:     map[string]string{"a": "v1.21.0", "b": "v1.18.20"},
+			ExpectError:  `node "b" has kubelet 1.18.20, which is more than 2 minor versions older than 1.21.2`,
+		},
+		{
+			ControlPlane: "1.20.8",
+			Kubelets:     map[string]string{"a": "v1.21.0"},
+			ExpectError:  `node "a" has kubelet 1.21.0, which is newer than 1.20.8`,
+		},
+		{
+			ControlPlane: "1.20.8",
+			Kubelets:     map[string]string{"a": "not-a-version"},
+			ExpectError:  `unable to parse kubelet version "not-a-version" of node "a"`,
+		},
+	}
+	for _, g := range grid {
+		err := checkKubeletVersionSkew(semver.MustParse(g.ControlPlane), g.Kubelets)
+		if g.ExpectError == "" {
+			if err != nil {
+				t.Errorf("unexpected error for control plane %s and kubelets %v: %v", g.ControlPlane, g.Kubelets, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), g.ExpectError) {
+			t.Errorf("expected error containing %q for control plane %s and kubelets %v, got %v", g.ExpectError, g.ControlPlane, g.Kubelets, err)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"os"
@@ -51,22 +52,53 @@ var (
 		2. All worker nodes are running and have "Ready" status.
 		3. All control plane nodes have the expected pods.
 		4. All pods with a critical priority are running and have "Ready" status.
+
+		Additional checks can be selected with --checks. The available checks are:
+
+		* addon-readiness: the Deployments and DaemonSets of kOps managed addons are available.
+		* certificate-expiry: the API server certificates do not expire within 30 days.
+		* component-health: the API server reports all control plane components as healthy.
+		* node-conditions: no ready node reports memory, disk or PID pressure, or an unavailable network.
+		* pdb-coverage: every PodDisruptionBudget selects pods and allows at least one disruption.
+		* rbac-bindings: the namespaces of the RBAC bindings of the cluster spec exist.
+
+		The cluster can also be validated against a profile with --profile, which reports the result of each
+		control of the profile. The available profiles are:
+
+		* cis: the control plane components and the kubelets follow the recommendations of the CIS Kubernetes Benchmark.
+
+		Results can be written as a JUnit report with --output junit, for consumption by CI systems.
 		`))
 
 	validateClusterExample = templates.Examples(i18n.T(`
 	# Validate the cluster set as the current context of the kube config.
 	# Kops will try for 10 minutes to validate the cluster 3 times.
-	kops validate cluster --wait 10m --count 3`))
+	kops validate cluster --wait 10m --count 3
+
+	# Run all the additional checks except certificate-expiry and write a JUnit report.
+	kops validate cluster --checks all --skip-checks certificate-expiry -o junit > junit.xml
+
+	# Report the compliance of the cluster with the CIS Kubernetes Benchmark.
+	kops validate cluster --profile cis`))
 
 	validateClusterShort = i18n.T(`Validate a kOps cluster.`)
 )
 
+// OutputJUnit writes the validation result as a JUnit XML report.
+const OutputJUnit = "junit"
+
 type ValidateClusterOptions struct {
 	ClusterName string
 	output      string
 	wait        time.Duration
 	count       int
 	kubeconfig  string
+	// checks are the names of the additional validation checks to run, or "all".
+	checks []string
+	// skipChecks are the names of additional validation checks not to run.
+	skipChecks []string
+	// profile is the name of the profile to validate the cluster against.
+	profile string
 }
 
 func (o *ValidateClusterOptions) InitDefaults() {
@@ -99,18 +131,42 @@ func NewCmdValidateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&options.output, "output", "o", options.output, "Output format. One of json|yaml|table.")
+	cmd.Flags().StringVarP(&options.output, "output", "o", options.output, "Output format. One of json|yaml|table|junit.")
 	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"json", "yaml", "table"}, cobra.ShellCompDirectiveNoFileComp
+		return []string{"json", "yaml", "table", "junit"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.Flags().DurationVar(&options.wait, "wait", options.wait, "Amount of time to wait for the cluster to become ready")
 	cmd.Flags().IntVar(&options.count, "count", options.count, "Number of consecutive successful validations required")
 	cmd.Flags().StringVar(&options.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	cmd.Flags().StringSliceVar(&options.checks, "checks", options.checks, "Additional validation checks to run, or \"all\"")
+	cmd.RegisterFlagCompletionFunc("checks", completeValidationChecks)
+	cmd.Flags().StringSliceVar(&options.skipChecks, "skip-checks", options.skipChecks, "Additional validation checks not to run")
+	cmd.RegisterFlagCompletionFunc("skip-checks", completeValidationChecks)
+	cmd.Flags().StringVar(&options.profile, "profile", options.profile, "Profile to validate the cluster against. One of: "+strings.Join(validation.ProfileNames(), "|"))
+	cmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validation.ProfileNames(), cobra.ShellCompDirectiveNoFileComp
+	})
 
 	return cmd
 }
 
+func completeValidationChecks(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return append(validation.CheckNames(), "all"), cobra.ShellCompDirectiveNoFileComp
+}
+
 func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *ValidateClusterOptions) (*validation.ValidationCluster, error) {
+	checks, err := validation.FindChecks(options.checks, options.skipChecks)
+	if err != nil {
+		return nil, err
+	}
+	if options.profile != "" {
+		profile, err := validation.FindProfile(options.profile)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, profile)
+	}
+
 	clientSet, err := f.Clientset()
 	if err != nil {
 		return nil, err
@@ -166,18 +222,22 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, opt
 	timeout := time.Now().Add(options.wait)
 	pollInterval := 10 * time.Second
 
-	validator, err := validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient)
+	validator, err := validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient, checks...)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected error creating validatior: %v", err)
 	}
 
+	var lastResult *validation.ValidationCluster
 	consecutive := 0
 	for {
 		if options.wait > 0 && time.Now().After(timeout) {
-			return nil, fmt.Errorf("wait time exceeded during validation")
+			err := fmt.Errorf("wait time exceeded during validation")
+			publishValidationFailed(ctx, cluster, cloud, lastResult, err)
+			return nil, err
 		}
 
 		result, err := validator.Validate()
+		lastResult = result
 		if err != nil {
 			consecutive = 0
 			if options.wait > 0 {
@@ -210,6 +270,10 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, opt
 			if _, err := out.Write(j); err != nil {
 				return nil, fmt.Errorf("error writing to output: %v", err)
 			}
+		case OutputJUnit:
+			if err := validateClusterOutputJUnit(result, cluster, out); err != nil {
+				return nil, err
+			}
 		default:
 			return nil, fmt.Errorf("unknown output format: %q", options.output)
 		}
@@ -234,7 +298,9 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, opt
 				time.Sleep(pollInterval)
 				continue
 			} else {
-				return nil, fmt.Errorf("cluster not yet healthy")
+				err := fmt.Errorf("cluster not yet healthy")
+				publishValidationFailed(ctx, cluster, cloud, result, err)
+				return nil, err
 			}
 		}
 	}
@@ -287,6 +353,27 @@ func validateClusterOutputTable(result *validation.ValidationCluster, cluster *k
 		}
 	}
 
+	if result.Profile != nil {
+		profileTable := &tables.Table{}
+		profileTable.AddColumn("CONTROL", func(c validation.ProfileControlResult) string {
+			return c.ID
+		})
+		profileTable.AddColumn("RESULT", func(c validation.ProfileControlResult) string {
+			if c.Passed {
+				return "PASS"
+			}
+			return "FAIL"
+		})
+		profileTable.AddColumn("DESCRIPTION", func(c validation.ProfileControlResult) string {
+			return c.Description
+		})
+
+		fmt.Fprintf(out, "\n%s PROFILE\n", strings.ToUpper(result.Profile.Profile))
+		if err := profileTable.Render(result.Profile.Controls, out, "CONTROL", "RESULT", "DESCRIPTION"); err != nil {
+			return fmt.Errorf("error rendering profile table: %v", err)
+		}
+	}
+
 	if len(result.Failures) != 0 {
 		failuresTable := &tables.Table{}
 		failuresTable.AddColumn("KIND", func(e *validation.ValidationError) string {
@@ -311,3 +398,86 @@ func validateClusterOutputTable(result *validation.ValidationCluster, cluster *k
 
 	return nil
 }
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// validateClusterOutputJUnit writes a JUnit report with a test case for the node and pod validation,
+// one for each additional check that was run, and one for each control of the selected profile.
+func validateClusterOutputJUnit(result *validation.ValidationCluster, cluster *kopsapi.Cluster, out io.Writer) error {
+	failures := map[string][]*validation.ValidationError{}
+	for _, failure := range result.Failures {
+		failures[failure.Check] = append(failures[failure.Check], failure)
+	}
+
+	suite := &junitTestSuite{
+		Name: "kops validate cluster " + cluster.Name,
+	}
+	for _, check := range append([]string{""}, result.Checks...) {
+		testCase := junitTestCase{
+			Name:      check,
+			ClassName: "kops.validation",
+		}
+		if check == "" {
+			testCase.Name = "cluster"
+		}
+		if checkFailures := failures[check]; len(checkFailures) != 0 {
+			var lines []string
+			for _, failure := range checkFailures {
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s", failure.Kind, failure.Name, failure.Message))
+			}
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d validation errors", len(checkFailures)),
+				Text:    strings.Join(lines, "\n"),
+			}
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	if result.Profile != nil {
+		controlFailures := map[string][]string{}
+		for _, failure := range failures[result.Profile.Profile] {
+			controlFailures[failure.Control] = append(controlFailures[failure.Control], fmt.Sprintf("%s\t%s\t%s", failure.Kind, failure.Name, failure.Message))
+		}
+		for _, control := range result.Profile.Controls {
+			testCase := junitTestCase{
+				Name:      control.ID + " " + control.Description,
+				ClassName: "kops.validation." + result.Profile.Profile,
+			}
+			if !control.Passed {
+				testCase.Failure = &junitFailure{
+					Message: fmt.Sprintf("%d validation errors", len(controlFailures[control.ID])),
+					Text:    strings.Join(controlFailures[control.ID], "\n"),
+				}
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+	}
+	suite.Tests = len(suite.TestCases)
+
+	b, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal JUnit XML: %v", err)
+	}
+	if _, err := fmt.Fprintf(out, "%s%s\n", xml.Header, b); err != nil {
+		return fmt.Errorf("error writing to output: %v", err)
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package main // import "k8s.io/kops/cmd/nodeup"
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -103,7 +104,7 @@ func main() {
 			}
 			i.RunTasksOptions.InitDefaults()
 			i.RunTasksOptions.MaxTaskDuration = 5 * time.Minute
-			err = i.Run()
+			err = i.Run(context.Background())
 			if err == nil {
 				fmt.Printf("service installed")
 				os.Exit(0)
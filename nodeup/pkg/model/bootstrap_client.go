@@ -26,10 +26,11 @@ import (
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hybrid"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 )
 
-// BootstrapClientBuilder calls kops-controller to bootstrap the node.
+// BootstrapClientBuilder calls kops-controller to bootstrap the node, and to report its boot milestones.
 type BootstrapClientBuilder struct {
 	*NodeupModelContext
 }
@@ -41,11 +42,15 @@ func (b BootstrapClientBuilder) Build(c *fi.ModelBuilderContext) error {
 
 	var authenticator fi.Authenticator
 	var err error
-	switch kops.CloudProviderID(b.Cluster.Spec.CloudProvider) {
-	case kops.CloudProviderAWS:
-		authenticator, err = awsup.NewAWSAuthenticator(b.Cloud.Region())
-	default:
-		return fmt.Errorf("unsupported cloud provider %s", b.Cluster.Spec.CloudProvider)
+	if b.IsHybrid {
+		authenticator, err = b.hybridAuthenticator()
+	} else {
+		switch kops.CloudProviderID(b.Cluster.Spec.CloudProvider) {
+		case kops.CloudProviderAWS:
+			authenticator, err = awsup.NewAWSAuthenticator(b.Cloud.Region())
+		default:
+			return fmt.Errorf("unsupported cloud provider %s", b.Cluster.Spec.CloudProvider)
+		}
 	}
 	if err != nil {
 		return err
@@ -74,7 +79,29 @@ func (b BootstrapClientBuilder) Build(c *fi.ModelBuilderContext) error {
 	}
 
 	c.AddTask(bootstrapClientTask)
+
+	// Instances in a warm pool do not join the cluster, so they have no boot milestones to report
+	if b.ConfigurationMode != ConfigurationModeWarming {
+		c.AddTask(&nodetasks.BootMilestonesTask{
+			ContainerRuntimeService: b.Cluster.Spec.ContainerRuntime + ".service",
+			Client: &nodetasks.KopsBootstrapClient{
+				Authenticator: authenticator,
+				CAs:           bootstrapClient.CAs,
+				BaseURL:       baseURL,
+			},
+		})
+	}
+
 	return nil
 }
 
+// hybridAuthenticator authenticates with the credential that kops toolbox enroll wrote to the machine.
+func (b BootstrapClientBuilder) hybridAuthenticator() (fi.Authenticator, error) {
+	credential, err := hybrid.ReadNodeCredential(hybrid.NodeCredentialPath)
+	if err != nil {
+		return nil, err
+	}
+	return hybrid.NewAuthenticator(credential)
+}
+
 var _ fi.ModelBuilder = &BootstrapClientBuilder{}
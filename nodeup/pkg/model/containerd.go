@@ -17,15 +17,19 @@ limitations under the License.
 package model
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/blang/semver/v4"
+	"github.com/pelletier/go-toml"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/nodeup/pkg/model/resources"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/systemd"
@@ -77,7 +81,9 @@ func (b *ContainerdBuilder) Build(c *fi.ModelBuilderContext) error {
 	}
 
 	// If there are containerd configuration overrides, apply them
-	b.buildConfigFile(c)
+	if err := b.buildConfigFile(c); err != nil {
+		return err
+	}
 
 	if installContainerd {
 		if err := b.installContainerd(c); err != nil {
@@ -158,6 +164,13 @@ func (b *ContainerdBuilder) buildSystemdService(sv semver.Version) *nodetasks.Se
 	manifest.Set("Unit", "Description", "containerd container runtime")
 	manifest.Set("Unit", "Documentation", "https://containerd.io")
 	manifest.Set("Unit", "After", "network.target local-fs.target")
+	if b.HasVolumeMount(nodeup.ContainerdVolumePath) {
+		manifest.Set("Unit", "RequiresMountsFor", nodeup.ContainerdVolumePath)
+	}
+	if b.UseInstanceStorage() {
+		manifest.Set("Unit", "Requires", instanceStorageServiceName)
+		manifest.Set("Unit", "After", instanceStorageServiceName)
+	}
 
 	// Restore the default SELinux security contexts for the containerd and runc binaries
 	if b.Distribution.IsRHELFamily() && b.Cluster.Spec.Docker != nil && fi.BoolValue(b.Cluster.Spec.Docker.SelinuxEnabled) {
@@ -167,6 +180,9 @@ func (b *ContainerdBuilder) buildSystemdService(sv semver.Version) *nodetasks.Se
 
 	manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/containerd")
 	manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+	if b.UseEgressProxy() {
+		manifest.Set("Service", "EnvironmentFile", egressProxyEnvironmentFile)
+	}
 	manifest.Set("Service", "ExecStartPre", "-/sbin/modprobe overlay")
 	manifest.Set("Service", "ExecStart", "/usr/bin/containerd -c "+b.containerdConfigFilePath()+" \"$CONTAINERD_OPTS\"")
 
@@ -223,8 +239,11 @@ func (b *ContainerdBuilder) buildSystemdServiceOverrideContainerOS(c *fi.ModelBu
 	lines := []string{
 		"[Service]",
 		"EnvironmentFile=/etc/environment",
-		"TasksMax=infinity",
 	}
+	if b.UseEgressProxy() {
+		lines = append(lines, "EnvironmentFile="+egressProxyEnvironmentFile)
+	}
+	lines = append(lines, "TasksMax=infinity")
 	contents := strings.Join(lines, "\n")
 
 	c.AddTask(&nodetasks.File{
@@ -246,10 +265,24 @@ func (b *ContainerdBuilder) buildSystemdServiceOverrideContainerOS(c *fi.ModelBu
 
 // buildSystemdServiceOverrideFlatcar is responsible for overriding the containerd service for Flatcar
 func (b *ContainerdBuilder) buildSystemdServiceOverrideFlatcar(c *fi.ModelBuilderContext) {
-	lines := []string{
+	var lines []string
+	var unitLines []string
+	if b.HasVolumeMount(nodeup.ContainerdVolumePath) {
+		unitLines = append(unitLines, "RequiresMountsFor="+nodeup.ContainerdVolumePath)
+	}
+	if b.UseInstanceStorage() {
+		unitLines = append(unitLines, "Requires="+instanceStorageServiceName, "After="+instanceStorageServiceName)
+	}
+	if len(unitLines) > 0 {
+		lines = append(append(lines, "[Unit]"), unitLines...)
+	}
+	lines = append(lines,
 		"[Service]",
-		"Environment=CONTAINERD_CONFIG=" + b.containerdConfigFilePath(),
+		"Environment=CONTAINERD_CONFIG="+b.containerdConfigFilePath(),
 		"EnvironmentFile=/etc/environment",
+	)
+	if b.UseEgressProxy() {
+		lines = append(lines, "EnvironmentFile="+egressProxyEnvironmentFile)
 	}
 	contents := strings.Join(lines, "\n")
 
@@ -297,12 +330,84 @@ func (b *ContainerdBuilder) buildSysconfigFile(c *fi.ModelBuilderContext) error
 }
 
 // buildConfigFile is responsible for creating the containerd configuration file
-func (b *ContainerdBuilder) buildConfigFile(c *fi.ModelBuilderContext) {
+func (b *ContainerdBuilder) buildConfigFile(c *fi.ModelBuilderContext) error {
+	contents := b.NodeupConfig.ContainerdConfig
+
+	// The registry credentials are kept in the dockerconfig secret, and only added to the configuration on the node.
+	// A configOverride is used as is.
+	containerd := b.Cluster.Spec.Containerd
+	if b.Cluster.Spec.ContainerRuntime == "containerd" && (containerd == nil || fi.StringValue(containerd.ConfigOverride) == "") && b.SecretStore != nil {
+		dockercfg, _ := b.SecretStore.Secret("dockerconfig")
+		if dockercfg != nil {
+			var err error
+			contents, err = addRegistryCredentials(contents, dockercfg.Data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	c.AddTask(&nodetasks.File{
 		Path:     b.containerdConfigFilePath(),
-		Contents: fi.NewStringResource(b.NodeupConfig.ContainerdConfig),
+		Contents: fi.NewStringResource(contents),
 		Type:     nodetasks.FileType_File,
 	})
+
+	return nil
+}
+
+// addRegistryCredentials adds the credentials of a Docker config file to the registry configs of containerd.
+func addRegistryCredentials(config string, dockerConfig []byte) (string, error) {
+	var credentials struct {
+		Auths map[string]struct {
+			Auth          string `json:"auth,omitempty"`
+			Username      string `json:"username,omitempty"`
+			Password      string `json:"password,omitempty"`
+			IdentityToken string `json:"identitytoken,omitempty"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(dockerConfig, &credentials); err != nil {
+		return "", fmt.Errorf("error parsing dockerconfig secret: %v", err)
+	}
+	if len(credentials.Auths) == 0 {
+		return config, nil
+	}
+
+	tree, err := toml.Load(config)
+	if err != nil {
+		return "", fmt.Errorf("error parsing containerd config: %v", err)
+	}
+	for server, auth := range credentials.Auths {
+		authPath := []string{"plugins", "io.containerd.grpc.v1.cri", "registry", "configs", registryHost(server), "auth"}
+		for key, value := range map[string]string{
+			"auth":          auth.Auth,
+			"username":      auth.Username,
+			"password":      auth.Password,
+			"identitytoken": auth.IdentityToken,
+		} {
+			if value != "" {
+				tree.SetPath(append(authPath, key), value)
+			}
+		}
+	}
+
+	return tree.String(), nil
+}
+
+// registryHost returns the host containerd pulls from for a server of a Docker config file,
+// which can be a URL such as "https://index.docker.io/v1/".
+func registryHost(server string) string {
+	host := server
+	if strings.Contains(server, "://") {
+		if u, err := url.Parse(server); err == nil {
+			host = u.Host
+		}
+	}
+	host = strings.TrimSuffix(strings.SplitN(host, "/", 2)[0], "/")
+	if host == "index.docker.io" || host == "docker.io" {
+		return "registry-1.docker.io"
+	}
+	return host
 }
 
 // skipInstall determines if kops should skip the installation and configuration of containerd
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/testutils"
 	"k8s.io/kops/upup/pkg/fi"
@@ -177,3 +178,50 @@ func runContainerdBuilderTest(t *testing.T, key string, distro distributions.Dis
 
 	testutils.ValidateTasks(t, filepath.Join(basedir, "tasks.yaml"), context)
 }
+
+func TestAddRegistryCredentials(t *testing.T) {
+	config := `version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+    sandbox_image = "registry.example.com/pause:3.5"
+`
+	dockerConfig := `{
+  "auths": {
+    "https://index.docker.io/v1/": {"auth": "dXNlcjpzZWNyZXQ="},
+    "registry.example.com": {"username": "user", "password": "secret"}
+  }
+}`
+
+	expected := `version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+    sandbox_image = "registry.example.com/pause:3.5"
+
+    [plugins."io.containerd.grpc.v1.cri".registry]
+
+      [plugins."io.containerd.grpc.v1.cri".registry.configs]
+
+        [plugins."io.containerd.grpc.v1.cri".registry.configs."registry-1.docker.io"]
+
+          [plugins."io.containerd.grpc.v1.cri".registry.configs."registry-1.docker.io".auth]
+            auth = "dXNlcjpzZWNyZXQ="
+
+        [plugins."io.containerd.grpc.v1.cri".registry.configs."registry.example.com"]
+
+          [plugins."io.containerd.grpc.v1.cri".registry.configs."registry.example.com".auth]
+            password = "secret"
+            username = "user"
+`
+
+	actual, err := addRegistryCredentials(config, []byte(dockerConfig))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("unexpected containerd config\n%s", diff.FormatDiff(expected, actual))
+	}
+}
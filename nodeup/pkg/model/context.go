@@ -65,6 +65,9 @@ type NodeupModelContext struct {
 	// HasAPIServer is true if the InstanceGroup has a role of master or apiserver (pupulated by Init)
 	HasAPIServer bool
 
+	// IsHybrid is true if the InstanceGroup has a role of hybrid, so the machine is not an instance of the cloud provider (populated by Init)
+	IsHybrid bool
+
 	kubernetesVersion semver.Version
 	bootstrapCerts    map[string]*nodetasks.BootstrapCert
 
@@ -91,6 +94,10 @@ func (c *NodeupModelContext) Init() error {
 	if role == kops.InstanceGroupRoleMaster || role == kops.InstanceGroupRoleAPIServer {
 		c.HasAPIServer = true
 	}
+
+	if role == kops.InstanceGroupRoleHybrid {
+		c.IsHybrid = true
+	}
 	return nil
 }
 
@@ -352,12 +359,41 @@ func (c *NodeupModelContext) UseEtcdTLS() bool {
 	return false
 }
 
+// UseEgressProxy checks if the cluster sends its egress traffic through a proxy
+func (c *NodeupModelContext) UseEgressProxy() bool {
+	return c.Cluster.Spec.EgressProxy != nil && c.Cluster.Spec.EgressProxy.HTTPProxy.Host != ""
+}
+
+// WaitForTimeSync checks if the kubelet must wait for the clock to be synchronized
+func (c *NodeupModelContext) WaitForTimeSync() bool {
+	ntp := c.Cluster.Spec.NTP
+	if ntp == nil || !fi.BoolValue(ntp.WaitForSync) || (ntp.Managed != nil && !*ntp.Managed) {
+		return false
+	}
+	return c.Distribution.IsDebianFamily() || c.Distribution.IsRHELFamily()
+}
+
 // UseVolumeMounts is used to check if we have volume mounts enabled as we need to
 // insert requires and afters in various places
 func (c *NodeupModelContext) UseVolumeMounts() bool {
 	return len(c.NodeupConfig.VolumeMounts) > 0
 }
 
+// UseInstanceStorage checks if the instance store volumes are mounted by nodeup
+func (c *NodeupModelContext) UseInstanceStorage() bool {
+	return c.NodeupConfig.InstanceStorage != nil
+}
+
+// HasVolumeMount checks if a volume of the instance group is mounted on the path
+func (c *NodeupModelContext) HasVolumeMount(path string) bool {
+	for _, x := range c.NodeupConfig.VolumeMounts {
+		if x.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
 // UseEtcdTLSAuth checks the peer-auth is set in both cluster
 // @NOTE: in retrospect i think we should have consolidated the common config in the wrapper struct; it
 // feels weird we set things like version, tls etc per cluster since they both have to be the same.
@@ -395,6 +431,15 @@ func (c *NodeupModelContext) UseBootstrapTokens() bool {
 	return c.Cluster.Spec.Kubelet != nil && c.Cluster.Spec.Kubelet.BootstrapKubeconfig != ""
 }
 
+// StaticPodManifestMode returns the file mode of the static pod manifests written by nodeup,
+// or nil for the default mode.
+func (c *NodeupModelContext) StaticPodManifestMode() *string {
+	if c.Cluster.Spec.SecurityProfile == kops.SecurityProfileCIS {
+		return s("0600")
+	}
+	return nil
+}
+
 // KubectlPath returns distro based path for kubectl
 func (c *NodeupModelContext) KubectlPath() string {
 	kubeletCommand := "/usr/local/bin"
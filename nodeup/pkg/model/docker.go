@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/nodeup/pkg/model/resources"
@@ -196,6 +197,9 @@ func (b *DockerBuilder) buildSystemdService(dockerVersion semver.Version) *nodet
 
 	manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/docker")
 	manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+	if b.UseEgressProxy() {
+		manifest.Set("Service", "EnvironmentFile", egressProxyEnvironmentFile)
+	}
 
 	manifest.Set("Service", "Type", "notify")
 	// Restore the default SELinux security contexts for the Docker binaries
@@ -313,6 +317,9 @@ func (b *DockerBuilder) buildContainerOSConfigurationDropIn(c *fi.ModelBuilderCo
 		"EnvironmentFile=/etc/sysconfig/docker",
 		"EnvironmentFile=/etc/environment",
 	}
+	if b.UseEgressProxy() {
+		lines = append(lines, "EnvironmentFile="+egressProxyEnvironmentFile)
+	}
 
 	// Equivalent of https://github.com/kubernetes/kubernetes/pull/51986
 	lines = append(lines, "TasksMax=infinity")
@@ -389,6 +396,31 @@ func (b *DockerBuilder) buildSysconfig(c *fi.ModelBuilderContext) error {
 		}
 	}
 
+	// The kubelet only rotates the container logs of CRI runtimes, so its limits are applied to the docker log driver
+	if kubelet := b.NodeupConfig.KubeletConfig; kubelet.ContainerLogMaxSize != "" || kubelet.ContainerLogMaxFiles != nil {
+		var logOpts []string
+		for _, opt := range docker.LogOpt {
+			if kubelet.ContainerLogMaxSize != "" && strings.HasPrefix(opt, "max-size=") {
+				continue
+			}
+			if kubelet.ContainerLogMaxFiles != nil && strings.HasPrefix(opt, "max-file=") {
+				continue
+			}
+			logOpts = append(logOpts, opt)
+		}
+		if kubelet.ContainerLogMaxSize != "" {
+			maxSize, err := resource.ParseQuantity(kubelet.ContainerLogMaxSize)
+			if err != nil {
+				return fmt.Errorf("error parsing container log max size %q: %v", kubelet.ContainerLogMaxSize, err)
+			}
+			logOpts = append(logOpts, fmt.Sprintf("max-size=%d", maxSize.Value()))
+		}
+		if kubelet.ContainerLogMaxFiles != nil {
+			logOpts = append(logOpts, fmt.Sprintf("max-file=%d", *kubelet.ContainerLogMaxFiles))
+		}
+		docker.LogOpt = logOpts
+	}
+
 	flagsString, err := flagbuilder.BuildFlags(&docker)
 	if err != nil {
 		return fmt.Errorf("error building docker flags: %v", err)
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/proxy"
+)
+
+// egressProxyEnvironmentFile holds the proxy environment of the systemd units that need it
+const egressProxyEnvironmentFile = "/etc/sysconfig/kops-egress-proxy"
+
+// EgressProxyBuilder writes the environment file used to configure the egress proxy of the container runtime and kubelet
+type EgressProxyBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &EgressProxyBuilder{}
+
+// Build is responsible for writing the egress proxy environment file
+func (b *EgressProxyBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !b.UseEgressProxy() {
+		return nil
+	}
+
+	var lines []string
+	for _, envVar := range proxy.GetProxyEnvVars(b.Cluster.Spec.EgressProxy) {
+		lines = append(lines, fmt.Sprintf("%s=%s", envVar.Name, envVar.Value))
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:     egressProxyEnvironmentFile,
+		Contents: fi.NewStringResource(strings.Join(lines, "\n") + "\n"),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0644"),
+	})
+
+	return nil
+}
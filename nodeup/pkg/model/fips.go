@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// fipsEnabledPath reports whether the kernel runs in FIPS mode
+const fipsEnabledPath = "/proc/sys/crypto/fips_enabled"
+
+// FIPSBuilder checks that the instance runs in FIPS mode and sets the crypto policy of the OS
+type FIPSBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &FIPSBuilder{}
+
+// Build is responsible for configuring FIPS mode before the kubelet starts
+func (b *FIPSBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !fi.BoolValue(b.Cluster.Spec.HostFIPSMode) {
+		return nil
+	}
+
+	if !b.Distribution.SupportsFIPS() {
+		return fmt.Errorf("FIPS mode is not supported on distribution %v", b.Distribution)
+	}
+
+	// FIPS mode must be enabled when the kernel boots, so it is a property of the image
+	enabled, err := ioutil.ReadFile(fipsEnabledPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", fipsEnabledPath, err)
+	}
+	if strings.TrimSpace(string(enabled)) != "1" {
+		return fmt.Errorf("FIPS mode is not enabled in the kernel; use an image that boots with fips=1")
+	}
+
+	if b.Distribution.HasCryptoPolicies() {
+		c.AddTask(&nodetasks.File{
+			Path:            "/etc/crypto-policies/config",
+			Contents:        fi.NewStringResource("FIPS\n"),
+			Type:            nodetasks.FileType_File,
+			Mode:            s("0644"),
+			OnChangeExecute: [][]string{{"update-crypto-policies", "--set", "FIPS"}},
+			BeforeServices:  []string{kubeletService},
+		})
+	}
+
+	return nil
+}
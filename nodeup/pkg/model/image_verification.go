@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// ImageVerificationBuilder verifies the cluster's container images before the kubelet runs them.
+type ImageVerificationBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &ImageVerificationBuilder{}
+
+func (b *ImageVerificationBuilder) Build(c *fi.ModelBuilderContext) error {
+	if b.NodeupConfig == nil || b.NodeupConfig.ImageVerification == nil {
+		return nil
+	}
+
+	// Warming instances are shut down before the kubelet runs anything.
+	if b.ConfigurationMode == "Warming" {
+		return nil
+	}
+
+	config := b.NodeupConfig.ImageVerification
+	for _, image := range config.Images {
+		c.AddTask(&nodetasks.VerifyImageTask{
+			Name:             image,
+			Digest:           config.Digests[image],
+			CosignPublicKeys: config.CosignPublicKeys,
+			Action:           config.Action,
+			Runtime:          b.Cluster.Spec.ContainerRuntime,
+		})
+	}
+
+	return nil
+}
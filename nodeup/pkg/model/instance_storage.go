@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	instanceStorageServiceName = "kops-instance-storage.service"
+	instanceStorageScriptPath  = "/opt/kops/bin/instance-storage-setup"
+	instanceStorageArrayPath   = "/dev/md/kops-instance-storage"
+)
+
+// InstanceStorageBuilder combines, formats and mounts the NVMe instance store volumes of the instance
+type InstanceStorageBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &InstanceStorageBuilder{}
+
+// Build is responsible for mounting the instance store volumes before the container runtime and the kubelet start
+func (b *InstanceStorageBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !b.UseInstanceStorage() {
+		return nil
+	}
+
+	if b.Distribution.IsDebianFamily() || b.Distribution.IsRHELFamily() {
+		c.AddTask(&nodetasks.Package{Name: "mdadm"})
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:     instanceStorageScriptPath,
+		Contents: fi.NewStringResource(b.buildInstanceStorageScript()),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0755"),
+	})
+	c.AddTask(b.buildSystemdService())
+
+	return nil
+}
+
+// buildInstanceStorageScript renders the script that mounts the instance store volumes; it runs at every boot,
+// as the volumes are blank again after the instance is stopped, and does nothing if they are already mounted
+func (b *InstanceStorageBuilder) buildInstanceStorageScript() string {
+	spec := b.NodeupConfig.InstanceStorage
+
+	filesystem := spec.Filesystem
+	if filesystem == "" {
+		filesystem = "ext4"
+	}
+	options := "defaults"
+	if len(spec.MountOptions) > 0 {
+		options = strings.Join(spec.MountOptions, ",")
+	}
+
+	return fmt.Sprintf(`#!/bin/bash
+# Built by kops - do not edit
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+path=%s
+filesystem=%s
+options=%s
+array=%s
+
+if mountpoint -q ${path}; then
+  exit 0
+fi
+
+devices=()
+for link in /dev/disk/by-id/nvme-Amazon_EC2_NVMe_Instance_Storage_*; do
+  [[ -e ${link} ]] || continue
+  device=$(readlink -f ${link})
+  if [[ " ${devices[*]:-} " != *" ${device} "* ]]; then
+    devices+=(${device})
+  fi
+done
+
+if [[ ${#devices[@]} -eq 0 ]]; then
+  echo "no instance store volumes found, ${path} is kept on the root volume"
+  exit 0
+fi
+
+if [[ ${#devices[@]} -eq 1 ]]; then
+  device=${devices[0]}
+else
+  holders=(/sys/class/block/$(basename ${devices[0]})/holders/md*)
+  if [[ -e ${holders[0]} ]]; then
+    # The array was assembled at boot
+    device=/dev/$(basename ${holders[0]})
+  else
+    # The array is created again when the volumes are blank, after the instance was stopped
+    mdadm --assemble ${array} "${devices[@]}" || mdadm --create ${array} --run --level=0 --raid-devices=${#devices[@]} "${devices[@]}"
+    device=${array}
+  fi
+fi
+
+if ! blkid ${device} > /dev/null; then
+  mkfs.${filesystem} ${device}
+fi
+
+mkdir -p ${path}
+mount -t ${filesystem} -o ${options} ${device} ${path}
+`, spec.Path, filesystem, options, instanceStorageArrayPath)
+}
+
+func (b *InstanceStorageBuilder) buildSystemdService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Mount the instance store volumes")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "After", "local-fs.target")
+	manifest.Set("Unit", "Before", "containerd.service docker.service kubelet.service")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", instanceStorageScriptPath)
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", instanceStorageServiceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       instanceStorageServiceName,
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
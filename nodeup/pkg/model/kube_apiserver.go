@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/pkg/k8scodecs"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/pkg/wellknownusers"
 	"k8s.io/kops/upup/pkg/fi"
@@ -42,6 +43,33 @@ import (
 // PathAuthnConfig is the path to the custom webhook authentication config
 const PathAuthnConfig = "/etc/kubernetes/authn.config"
 
+// cisAuditPolicy is the audit policy used by the CIS security profile when no other policy is configured.
+// It logs the metadata of all requests, except for health checks, without logging the content of secrets.
+const cisAuditPolicy = `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+- RequestReceived
+rules:
+- level: None
+  nonResourceURLs:
+  - /healthz*
+  - /livez*
+  - /readyz*
+  - /version
+- level: None
+  users:
+  - system:kube-proxy
+  verbs:
+  - watch
+  resources:
+  - group: ""
+    resources:
+    - endpoints
+    - services
+    - services/status
+- level: Metadata
+`
+
 // KubeAPIServerBuilder install kube-apiserver (just the manifest at the moment)
 type KubeAPIServerBuilder struct {
 	*NodeupModelContext
@@ -66,6 +94,27 @@ func (b *KubeAPIServerBuilder) Build(c *fi.ModelBuilderContext) error {
 		return err
 	}
 
+	if err := b.writeAdmissionConfiguration(c, &kubeAPIServer); err != nil {
+		return err
+	}
+
+	if b.Cluster.Spec.SecurityProfile == kops.SecurityProfileCIS && kubeAPIServer.AuditPolicyFile == components.CISAuditPolicyFile {
+		c.AddTask(&nodetasks.File{
+			Path:     kubeAPIServer.AuditPolicyFile,
+			Contents: fi.NewStringResource(cisAuditPolicy),
+			Type:     nodetasks.FileType_File,
+			Mode:     fi.String("0600"),
+		})
+	}
+
+	if b.Cluster.Spec.SecurityProfile == kops.SecurityProfileCIS && fi.StringValue(kubeAPIServer.AuditLogPath) == components.CISAuditLogPath {
+		c.AddTask(&nodetasks.File{
+			Path: filepath.Dir(components.CISAuditLogPath),
+			Type: nodetasks.FileType_Directory,
+			Mode: fi.String("0700"),
+		})
+	}
+
 	if b.NodeupConfig.APIServerConfig.EncryptionConfigSecretHash != "" {
 		encryptionConfigPath := fi.String(filepath.Join(pathSrvKAPI, "encryptionconfig.yaml"))
 
@@ -177,6 +226,7 @@ func (b *KubeAPIServerBuilder) Build(c *fi.ModelBuilderContext) error {
 			Path:     "/etc/kubernetes/manifests/kube-apiserver.manifest",
 			Contents: fi.NewBytesResource(manifest),
 			Type:     nodetasks.FileType_File,
+			Mode:     b.StaticPodManifestMode(),
 		})
 	}
 
@@ -211,6 +261,103 @@ func (b *KubeAPIServerBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
+// podSecurityConfiguration is the configuration of the PodSecurity admission plugin.
+type podSecurityConfiguration struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Defaults   podSecurityDefaults   `json:"defaults"`
+	Exemptions podSecurityExemptions `json:"exemptions"`
+}
+
+type podSecurityDefaults struct {
+	Enforce        string `json:"enforce"`
+	EnforceVersion string `json:"enforce-version"`
+	Audit          string `json:"audit"`
+	AuditVersion   string `json:"audit-version"`
+	Warn           string `json:"warn"`
+	WarnVersion    string `json:"warn-version"`
+}
+
+type podSecurityExemptions struct {
+	Usernames      []string `json:"usernames"`
+	RuntimeClasses []string `json:"runtimeClasses"`
+	Namespaces     []string `json:"namespaces"`
+}
+
+// admissionConfiguration is the configuration of the admission plugins of kube-apiserver.
+type admissionConfiguration struct {
+	APIVersion string                  `json:"apiVersion"`
+	Kind       string                  `json:"kind"`
+	Plugins    []admissionPluginConfig `json:"plugins"`
+}
+
+type admissionPluginConfig struct {
+	Name          string      `json:"name"`
+	Configuration interface{} `json:"configuration"`
+}
+
+// writeAdmissionConfiguration writes the admission configuration of the PodSecurity admission plugin.
+func (b *KubeAPIServerBuilder) writeAdmissionConfiguration(c *fi.ModelBuilderContext, kubeAPIServer *kops.KubeAPIServerConfig) error {
+	psa := b.Cluster.Spec.PodSecurityAdmission
+	if psa == nil {
+		return nil
+	}
+
+	// The configuration is written as a whole, so a file provided by the user would be overwritten
+	if kubeAPIServer.AdmissionControlConfigFile != "" && kubeAPIServer.AdmissionControlConfigFile != components.PodSecurityAdmissionConfigFile {
+		return fmt.Errorf("admissionControlConfigFile %q cannot be set together with podSecurityAdmission", kubeAPIServer.AdmissionControlConfigFile)
+	}
+	kubeAPIServer.AdmissionControlConfigFile = components.PodSecurityAdmissionConfigFile
+
+	apiVersion := "pod-security.admission.config.k8s.io/v1"
+	if b.IsKubernetesLT("1.23") {
+		apiVersion = "pod-security.admission.config.k8s.io/v1alpha1"
+	} else if b.IsKubernetesLT("1.25") {
+		apiVersion = "pod-security.admission.config.k8s.io/v1beta1"
+	}
+
+	config := admissionConfiguration{
+		APIVersion: "apiserver.config.k8s.io/v1",
+		Kind:       "AdmissionConfiguration",
+		Plugins: []admissionPluginConfig{
+			{
+				Name: "PodSecurity",
+				Configuration: podSecurityConfiguration{
+					APIVersion: apiVersion,
+					Kind:       "PodSecurityConfiguration",
+					Defaults: podSecurityDefaults{
+						Enforce:        psa.Enforce,
+						EnforceVersion: psa.Version,
+						Audit:          psa.Audit,
+						AuditVersion:   psa.Version,
+						Warn:           psa.Warn,
+						WarnVersion:    psa.Version,
+					},
+					Exemptions: podSecurityExemptions{
+						Usernames:      append([]string{}, psa.ExemptUsernames...),
+						RuntimeClasses: append([]string{}, psa.ExemptRuntimeClasses...),
+						Namespaces:     append([]string{}, psa.ExemptNamespaces...),
+					},
+				},
+			},
+		},
+	}
+
+	manifest, err := kops.ToRawYaml(config)
+	if err != nil {
+		return fmt.Errorf("error marshaling admission configuration to yaml: %v", err)
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:     kubeAPIServer.AdmissionControlConfigFile,
+		Contents: fi.NewBytesResource(manifest),
+		Type:     nodetasks.FileType_File,
+		Mode:     fi.String("0600"),
+	})
+
+	return nil
+}
+
 func (b *KubeAPIServerBuilder) writeAuthenticationConfig(c *fi.ModelBuilderContext, kubeAPIServer *kops.KubeAPIServerConfig) error {
 	if b.Cluster.Spec.Authentication == nil || b.Cluster.Spec.Authentication.IsEmpty() {
 		return nil
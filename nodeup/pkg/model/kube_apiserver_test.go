@@ -179,6 +179,13 @@ func TestAwsIamAuthenticator(t *testing.T) {
 	})
 }
 
+func TestPodSecurityAdmissionAPIServerBuilder(t *testing.T) {
+	RunGoldenTest(t, "tests/golden/pod-security-admission", "kube-apiserver", func(nodeupModelContext *NodeupModelContext, target *fi.ModelBuilderContext) error {
+		builder := KubeAPIServerBuilder{NodeupModelContext: nodeupModelContext}
+		return builder.Build(target)
+	})
+}
+
 func TestKubeAPIServerBuilderAMD64(t *testing.T) {
 	RunGoldenTest(t, "tests/golden/side-loading", "kube-apiserver-amd64", func(nodeupModelContext *NodeupModelContext, target *fi.ModelBuilderContext) error {
 		builder := KubeAPIServerBuilder{NodeupModelContext: nodeupModelContext}
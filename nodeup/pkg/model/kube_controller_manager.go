@@ -77,6 +77,7 @@ func (b *KubeControllerManagerBuilder) Build(c *fi.ModelBuilderContext) error {
 			Path:     "/etc/kubernetes/manifests/kube-controller-manager.manifest",
 			Contents: fi.NewBytesResource(manifest),
 			Type:     nodetasks.FileType_File,
+			Mode:     b.StaticPodManifestMode(),
 		})
 	}
 
@@ -74,6 +74,7 @@ func (b *KubeProxyBuilder) Build(c *fi.ModelBuilderContext) error {
 			Path:     "/etc/kubernetes/manifests/kube-proxy.manifest",
 			Contents: fi.NewBytesResource(manifest),
 			Type:     nodetasks.FileType_File,
+			Mode:     b.StaticPodManifestMode(),
 		})
 	}
 
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/configbuilder"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/k8scodecs"
@@ -34,6 +35,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -49,6 +51,47 @@ type SchedulerConfig struct {
 	APIVersion       string                 `json:"apiVersion"`
 	Kind             string                 `json:"kind"`
 	ClientConnection ClientConnectionConfig `json:"clientConnection,omitempty"`
+	Profiles         []SchedulerProfile     `json:"profiles,omitempty"`
+}
+
+// SchedulerProfile is a scheduling profile in the kube-scheduler config file
+type SchedulerProfile struct {
+	SchedulerName string                  `json:"schedulerName,omitempty"`
+	Plugins       *SchedulerPlugins       `json:"plugins,omitempty"`
+	PluginConfig  []SchedulerPluginConfig `json:"pluginConfig,omitempty"`
+}
+
+// SchedulerPlugins are the plugins of a profile at each extension point
+type SchedulerPlugins struct {
+	QueueSort  *SchedulerPluginSet `json:"queueSort,omitempty"`
+	PreFilter  *SchedulerPluginSet `json:"preFilter,omitempty"`
+	Filter     *SchedulerPluginSet `json:"filter,omitempty"`
+	PostFilter *SchedulerPluginSet `json:"postFilter,omitempty"`
+	PreScore   *SchedulerPluginSet `json:"preScore,omitempty"`
+	Score      *SchedulerPluginSet `json:"score,omitempty"`
+	Reserve    *SchedulerPluginSet `json:"reserve,omitempty"`
+	Permit     *SchedulerPluginSet `json:"permit,omitempty"`
+	PreBind    *SchedulerPluginSet `json:"preBind,omitempty"`
+	Bind       *SchedulerPluginSet `json:"bind,omitempty"`
+	PostBind   *SchedulerPluginSet `json:"postBind,omitempty"`
+}
+
+// SchedulerPluginSet are the plugins enabled or disabled at an extension point
+type SchedulerPluginSet struct {
+	Enabled  []SchedulerPlugin `json:"enabled,omitempty"`
+	Disabled []SchedulerPlugin `json:"disabled,omitempty"`
+}
+
+// SchedulerPlugin is a plugin enabled or disabled at an extension point
+type SchedulerPlugin struct {
+	Name   string `json:"name"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// SchedulerPluginConfig are the arguments of a plugin
+type SchedulerPluginConfig struct {
+	Name string                `json:"name"`
+	Args *runtime.RawExtension `json:"args,omitempty"`
 }
 
 // KubeSchedulerBuilder install kube-scheduler
@@ -80,6 +123,7 @@ func (b *KubeSchedulerBuilder) Build(c *fi.ModelBuilderContext) error {
 			Path:     "/etc/kubernetes/manifests/kube-scheduler.manifest",
 			Contents: fi.NewBytesResource(manifest),
 			Type:     nodetasks.FileType_File,
+			Mode:     b.StaticPodManifestMode(),
 		})
 	}
 
@@ -95,13 +139,16 @@ func (b *KubeSchedulerBuilder) Build(c *fi.ModelBuilderContext) error {
 	}
 	{
 		var config *SchedulerConfig
-		if b.IsKubernetesGTE("1.19") {
+		if b.IsKubernetesGTE("1.22") {
+			config = NewSchedulerConfig("kubescheduler.config.k8s.io/v1beta2")
+		} else if b.IsKubernetesGTE("1.19") {
 			config = NewSchedulerConfig("kubescheduler.config.k8s.io/v1beta1")
 		} else if b.IsKubernetesGTE("1.18") {
 			config = NewSchedulerConfig("kubescheduler.config.k8s.io/v1alpha2")
 		} else {
 			config = NewSchedulerConfig("kubescheduler.config.k8s.io/v1alpha1")
 		}
+		config.Profiles = buildSchedulerProfiles(b.Cluster.Spec.KubeScheduler.Profiles)
 
 		manifest, err := configbuilder.BuildConfigYaml(b.Cluster.Spec.KubeScheduler, config)
 		if err != nil {
@@ -129,6 +176,54 @@ func (b *KubeSchedulerBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
+// buildSchedulerProfiles maps the profiles of the kops API to the profiles of the kube-scheduler config file
+func buildSchedulerProfiles(profiles []kops.KubeSchedulerProfile) []SchedulerProfile {
+	var schedulerProfiles []SchedulerProfile
+	for _, profile := range profiles {
+		schedulerProfile := SchedulerProfile{
+			SchedulerName: profile.SchedulerName,
+		}
+		if profile.Plugins != nil {
+			schedulerProfile.Plugins = &SchedulerPlugins{
+				QueueSort:  buildSchedulerPluginSet(profile.Plugins.QueueSort),
+				PreFilter:  buildSchedulerPluginSet(profile.Plugins.PreFilter),
+				Filter:     buildSchedulerPluginSet(profile.Plugins.Filter),
+				PostFilter: buildSchedulerPluginSet(profile.Plugins.PostFilter),
+				PreScore:   buildSchedulerPluginSet(profile.Plugins.PreScore),
+				Score:      buildSchedulerPluginSet(profile.Plugins.Score),
+				Reserve:    buildSchedulerPluginSet(profile.Plugins.Reserve),
+				Permit:     buildSchedulerPluginSet(profile.Plugins.Permit),
+				PreBind:    buildSchedulerPluginSet(profile.Plugins.PreBind),
+				Bind:       buildSchedulerPluginSet(profile.Plugins.Bind),
+				PostBind:   buildSchedulerPluginSet(profile.Plugins.PostBind),
+			}
+		}
+		for _, pluginConfig := range profile.PluginConfig {
+			schedulerProfile.PluginConfig = append(schedulerProfile.PluginConfig, SchedulerPluginConfig{
+				Name: pluginConfig.Name,
+				Args: pluginConfig.Args,
+			})
+		}
+		schedulerProfiles = append(schedulerProfiles, schedulerProfile)
+	}
+	return schedulerProfiles
+}
+
+// buildSchedulerPluginSet maps the plugins of an extension point to the kube-scheduler config file
+func buildSchedulerPluginSet(pluginSet *kops.KubeSchedulerPluginSet) *SchedulerPluginSet {
+	if pluginSet == nil {
+		return nil
+	}
+	schedulerPluginSet := &SchedulerPluginSet{}
+	for _, plugin := range pluginSet.Enabled {
+		schedulerPluginSet.Enabled = append(schedulerPluginSet.Enabled, SchedulerPlugin{Name: plugin.Name, Weight: plugin.Weight})
+	}
+	for _, plugin := range pluginSet.Disabled {
+		schedulerPluginSet.Disabled = append(schedulerPluginSet.Disabled, SchedulerPlugin{Name: plugin.Name})
+	}
+	return schedulerPluginSet
+}
+
 // NewSchedulerConfig initializes a new kube-scheduler config file
 func NewSchedulerConfig(apiVersion string) *SchedulerConfig {
 	schedConfig := new(SchedulerConfig)
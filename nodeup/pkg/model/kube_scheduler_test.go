@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/configbuilder"
 	"k8s.io/kops/upup/pkg/fi"
@@ -70,6 +71,65 @@ kind: KubeSchedulerConfiguration
 	}
 }
 
+func TestParseProfiles(t *testing.T) {
+	expect := []byte(
+		`apiVersion: kubescheduler.config.k8s.io/v1beta2
+clientConnection:
+  kubeconfig: /var/lib/kube-scheduler/kubeconfig
+kind: KubeSchedulerConfiguration
+profiles:
+- pluginConfig:
+  - args:
+      scoringStrategy:
+        resources:
+        - name: cpu
+          weight: 1
+        type: MostAllocated
+    name: NodeResourcesFit
+  plugins:
+    score:
+      disabled:
+      - name: NodeResourcesBalancedAllocation
+      enabled:
+      - name: NodeResourcesFit
+        weight: 5
+- schedulerName: no-spread
+`)
+
+	s := &kops.KubeSchedulerConfig{
+		Profiles: []kops.KubeSchedulerProfile{
+			{
+				Plugins: &kops.KubeSchedulerPlugins{
+					Score: &kops.KubeSchedulerPluginSet{
+						Enabled:  []kops.KubeSchedulerPlugin{{Name: "NodeResourcesFit", Weight: fi.Int32(5)}},
+						Disabled: []kops.KubeSchedulerPlugin{{Name: "NodeResourcesBalancedAllocation"}},
+					},
+				},
+				PluginConfig: []kops.KubeSchedulerPluginConfig{
+					{
+						Name: "NodeResourcesFit",
+						Args: &runtime.RawExtension{Raw: []byte(`{"scoringStrategy":{"type":"MostAllocated","resources":[{"name":"cpu","weight":1}]}}`)},
+					},
+				},
+			},
+			{
+				SchedulerName: "no-spread",
+			},
+		},
+	}
+
+	config := NewSchedulerConfig("kubescheduler.config.k8s.io/v1beta2")
+	config.Profiles = buildSchedulerProfiles(s.Profiles)
+	yaml, err := configbuilder.BuildConfigYaml(s, config)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if !bytes.Equal(yaml, expect) {
+		t.Errorf("unexpected result: \n%s, expected: \n%s", yaml, expect)
+	}
+}
+
 func TestKubeSchedulerBuilder(t *testing.T) {
 	RunGoldenTest(t, "tests/golden/minimal", "kube-scheduler", func(nodeupModelContext *NodeupModelContext, target *fi.ModelBuilderContext) error {
 		builder := KubeSchedulerBuilder{NodeupModelContext: nodeupModelContext}
@@ -20,8 +20,10 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"k8s.io/kops/pkg/model/components"
+	"k8s.io/kops/pkg/model/defaults"
 
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -29,6 +31,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/nodelabels"
 	"k8s.io/kops/pkg/rbac"
@@ -57,22 +60,38 @@ var _ fi.ModelBuilder = &KubeletBuilder{}
 // Build is responsible for building the kubelet configuration
 func (b *KubeletBuilder) Build(c *fi.ModelBuilderContext) error {
 
-	err := b.buildKubeletServingCertificate(c)
+	kubeletConfig, err := b.buildKubeletConfig()
 	if err != nil {
-		return fmt.Errorf("error building kubelet server cert: %v", err)
+		return fmt.Errorf("error building kubelet config: %v", err)
 	}
 
-	kubeletConfig, err := b.buildKubeletConfig()
+	err = b.buildKubeletServingCertificate(c, kubeletConfig)
 	if err != nil {
-		return fmt.Errorf("error building kubelet config: %v", err)
+		return fmt.Errorf("error building kubelet server cert: %v", err)
 	}
 
 	{
-		t, err := b.buildSystemdEnvironmentFile(kubeletConfig)
+		flags, err := b.buildKubeletFlags(kubeletConfig)
 		if err != nil {
 			return err
 		}
-		c.AddTask(t)
+
+		if fi.BoolValue(kubeletConfig.UseConfigFile) {
+			var config string
+			flags, config, err = buildKubeletConfigFile(flags, kubeletConfig.ConfigDropIns)
+			if err != nil {
+				return err
+			}
+			c.AddTask(&nodetasks.File{
+				Path:           kubeletConfigFilePath,
+				Contents:       fi.NewStringResource(config),
+				Type:           nodetasks.FileType_File,
+				Mode:           s("0644"),
+				BeforeServices: []string{kubeletService},
+			})
+		}
+
+		c.AddTask(b.buildSystemdEnvironmentFile(flags))
 	}
 
 	{
@@ -174,8 +193,8 @@ func (b *KubeletBuilder) buildManifestDirectory(kubeletConfig *kops.KubeletConfi
 	return directory, nil
 }
 
-// buildSystemdEnvironmentFile renders the environment file for the kubelet
-func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.KubeletConfigSpec) (*nodetasks.File, error) {
+// buildKubeletFlags returns the command line flags for the kubelet
+func (b *KubeletBuilder) buildKubeletFlags(kubeletConfig *kops.KubeletConfigSpec) ([]string, error) {
 	// @step: ensure the masters do not get a bootstrap configuration
 	if b.UseBootstrapTokens() && b.IsMaster {
 		kubeletConfig.BootstrapKubeconfig = ""
@@ -188,8 +207,7 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 		kubeletConfig.ExperimentalAllowedUnsafeSysctls = nil
 	}
 
-	// TODO: Dump the separate file for flags - just complexity!
-	flags, err := flagbuilder.BuildFlags(kubeletConfig)
+	flags, err := flagbuilder.BuildFlagsList(kubeletConfig)
 	if err != nil {
 		return nil, fmt.Errorf("error building kubelet flags: %v", err)
 	}
@@ -198,8 +216,8 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 	// We build this flag differently because it depends on CloudConfig, and to expose it directly
 	// would be a degree of freedom we don't have (we'd have to write the config to different files)
 	// We can always add this later if it is needed.
-	if b.Cluster.Spec.CloudConfig != nil {
-		flags += " --cloud-config=" + CloudConfigFilePath
+	if b.Cluster.Spec.CloudConfig != nil && !b.IsHybrid {
+		flags = append(flags, "--cloud-config="+CloudConfigFilePath)
 	}
 
 	if b.UsesSecondaryIP() {
@@ -209,35 +227,40 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 		if err != nil {
 			return nil, fmt.Errorf("error fetching the local-ipv4 address from the ec2 meta-data: %v", err)
 		}
-		flags += " --node-ip=" + localIpv4
+		flags = append(flags, "--node-ip="+localIpv4)
 	}
 
 	if b.usesContainerizedMounter() {
 		// We don't want to expose this in the model while it is experimental, but it is needed on COS
-		flags += " --experimental-mounter-path=" + path.Join(containerizedMounterHome, "mounter")
+		flags = append(flags, "--experimental-mounter-path="+path.Join(containerizedMounterHome, "mounter"))
 	}
 
 	// Add container runtime spcific flags
 	switch b.Cluster.Spec.ContainerRuntime {
 	case "docker", "":
-		flags += " --cni-bin-dir=" + b.CNIBinDir()
-		flags += " --cni-conf-dir=" + b.CNIConfDir()
+		flags = append(flags, "--cni-bin-dir="+b.CNIBinDir())
+		flags = append(flags, "--cni-conf-dir="+b.CNIConfDir())
 	case "containerd":
-		flags += " --container-runtime=remote"
-		flags += " --runtime-request-timeout=15m"
+		flags = append(flags, "--container-runtime=remote")
+		flags = append(flags, "--runtime-request-timeout=15m")
 		if b.Cluster.Spec.Containerd == nil || b.Cluster.Spec.Containerd.Address == nil {
-			flags += " --container-runtime-endpoint=unix:///run/containerd/containerd.sock"
+			flags = append(flags, "--container-runtime-endpoint=unix:///run/containerd/containerd.sock")
 		} else {
-			flags += " --container-runtime-endpoint=unix://" + fi.StringValue(b.Cluster.Spec.Containerd.Address)
+			flags = append(flags, "--container-runtime-endpoint=unix://"+fi.StringValue(b.Cluster.Spec.Containerd.Address))
 		}
 	}
 
-	if b.UseKopsControllerForNodeBootstrap() {
-		flags += " --tls-cert-file=" + b.PathSrvKubernetes() + "/kubelet-server.crt"
-		flags += " --tls-private-key-file=" + b.PathSrvKubernetes() + "/kubelet-server.key"
+	if b.UseKopsControllerForNodeBootstrap() && !fi.BoolValue(kubeletConfig.ServerTLSBootstrap) {
+		flags = append(flags, "--tls-cert-file="+b.PathSrvKubernetes()+"/kubelet-server.crt")
+		flags = append(flags, "--tls-private-key-file="+b.PathSrvKubernetes()+"/kubelet-server.key")
 	}
 
-	sysconfig := "DAEMON_ARGS=\"" + flags + "\"\n"
+	return flags, nil
+}
+
+// buildSystemdEnvironmentFile renders the environment file for the kubelet
+func (b *KubeletBuilder) buildSystemdEnvironmentFile(flags []string) *nodetasks.File {
+	sysconfig := "DAEMON_ARGS=\"" + strings.Join(flags, " ") + "\"\n"
 	// Makes kubelet read /root/.docker/config.json properly
 	sysconfig = sysconfig + "HOME=\"/root" + "\"\n"
 
@@ -247,7 +270,7 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 		Type:     nodetasks.FileType_File,
 	}
 
-	return t, nil
+	return t
 }
 
 // buildSystemdService is responsible for generating the kubelet systemd unit
@@ -266,7 +289,22 @@ func (b *KubeletBuilder) buildSystemdService() *nodetasks.Service {
 		klog.Warningf("unknown container runtime %q", b.Cluster.Spec.ContainerRuntime)
 	}
 
+	if b.WaitForTimeSync() {
+		manifest.Set("Unit", "Requires", timeSyncWaitServiceName)
+		manifest.Set("Unit", "After", timeSyncWaitServiceName)
+	}
+	if b.UseInstanceStorage() {
+		manifest.Set("Unit", "Requires", instanceStorageServiceName)
+		manifest.Set("Unit", "After", instanceStorageServiceName)
+	}
+	if b.HasVolumeMount(nodeup.KubeletVolumePath) {
+		manifest.Set("Unit", "RequiresMountsFor", nodeup.KubeletVolumePath)
+	}
+
 	manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/kubelet")
+	if b.UseEgressProxy() {
+		manifest.Set("Service", "EnvironmentFile", egressProxyEnvironmentFile)
+	}
 
 	// @check if we are using bootstrap tokens and file checker
 	if !b.IsMaster && b.UseBootstrapTokens() {
@@ -454,26 +492,7 @@ func (b *KubeletBuilder) buildKubeletConfigSpec() (*kops.KubeletConfigSpec, erro
 			return nil, err
 		}
 
-		// Default maximum pods per node defined by KubeletConfiguration, but
-		// respect any value the user sets explicitly.
-		maxPods := int32(110)
-		if c.MaxPods != nil {
-			maxPods = *c.MaxPods
-		}
-
-		// AWS VPC CNI plugin-specific maximum pod calculation based on:
-		// https://github.com/aws/amazon-vpc-cni-k8s/blob/f52ad45/README.md
-		//
-		// Treat the calculated value as a hard max, since networking with the CNI
-		// plugin won't work correctly once we exceed that maximum.
-		enis := instanceType.InstanceENIs
-		ips := instanceType.InstanceIPsPerENI
-		if enis > 0 && ips > 0 {
-			instanceMaxPods := enis*(ips-1) + 2
-			if int32(instanceMaxPods) < maxPods {
-				maxPods = int32(instanceMaxPods)
-			}
-		}
+		maxPods := defaults.AmazonVPCMaxPods(c.MaxPods, instanceType.InstanceENIs, instanceType.InstanceIPsPerENI)
 
 		// Write back values that could have changed
 		c.MaxPods = &maxPods
@@ -547,7 +566,11 @@ func (b *KubeletBuilder) buildMasterKubeletKubeconfig(c *fi.ModelBuilderContext)
 	return b.BuildIssuedKubeconfig("kubelet", certName, c), nil
 }
 
-func (b *KubeletBuilder) buildKubeletServingCertificate(c *fi.ModelBuilderContext) error {
+func (b *KubeletBuilder) buildKubeletServingCertificate(c *fi.ModelBuilderContext, kubeletConfig *kops.KubeletConfigSpec) error {
+	// With server TLS bootstrap, the kubelet requests its serving certificate from the certificates API
+	if fi.BoolValue(kubeletConfig.ServerTLSBootstrap) {
+		return nil
+	}
 
 	if b.UseKopsControllerForNodeBootstrap() {
 		name := "kubelet-server"
@@ -0,0 +1,255 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// kubeletConfigFilePath is the path of the KubeletConfiguration file
+	kubeletConfigFilePath = "/var/lib/kubelet/kubelet-config.yaml"
+
+	kubeletConfigAPIVersion = "kubelet.config.k8s.io/v1beta1"
+	kubeletConfigKind       = "KubeletConfiguration"
+)
+
+// kubeletConfigValueType is the type of a KubeletConfiguration field, which determines how a flag value is converted
+type kubeletConfigValueType int
+
+const (
+	kubeletConfigString kubeletConfigValueType = iota
+	kubeletConfigBool
+	kubeletConfigInt
+	kubeletConfigStringSlice
+	kubeletConfigStringMap
+	kubeletConfigBoolMap
+	kubeletConfigEvictionMap
+)
+
+// kubeletConfigField is the KubeletConfiguration field that a kubelet flag maps to
+type kubeletConfigField struct {
+	// Path is the path of the field, with nested fields separated by dots
+	Path string
+	Type kubeletConfigValueType
+}
+
+// kubeletConfigFields maps the kubelet flags to their KubeletConfiguration (v1beta1) fields.
+// Flags that are not listed here are still passed on the command line.
+var kubeletConfigFields = map[string]kubeletConfigField{
+	"allowed-unsafe-sysctls":                 {"allowedUnsafeSysctls", kubeletConfigStringSlice},
+	"anonymous-auth":                         {"authentication.anonymous.enabled", kubeletConfigBool},
+	"authentication-token-webhook":           {"authentication.webhook.enabled", kubeletConfigBool},
+	"authentication-token-webhook-cache-ttl": {"authentication.webhook.cacheTTL", kubeletConfigString},
+	"authorization-mode":                     {"authorization.mode", kubeletConfigString},
+	"cgroup-driver":                          {"cgroupDriver", kubeletConfigString},
+	"cgroup-root":                            {"cgroupRoot", kubeletConfigString},
+	"client-ca-file":                         {"authentication.x509.clientCAFile", kubeletConfigString},
+	"cluster-dns":                            {"clusterDNS", kubeletConfigStringSlice},
+	"cluster-domain":                         {"clusterDomain", kubeletConfigString},
+	"container-log-max-files":                {"containerLogMaxFiles", kubeletConfigInt},
+	"container-log-max-size":                 {"containerLogMaxSize", kubeletConfigString},
+	"cpu-cfs-quota":                          {"cpuCFSQuota", kubeletConfigBool},
+	"cpu-cfs-quota-period":                   {"cpuCFSQuotaPeriod", kubeletConfigString},
+	"cpu-manager-policy":                     {"cpuManagerPolicy", kubeletConfigString},
+	"enable-debugging-handlers":              {"enableDebuggingHandlers", kubeletConfigBool},
+	"enforce-node-allocatable":               {"enforceNodeAllocatable", kubeletConfigStringSlice},
+	"event-burst":                            {"eventBurst", kubeletConfigInt},
+	"event-qps":                              {"eventRecordQPS", kubeletConfigInt},
+	"eviction-hard":                          {"evictionHard", kubeletConfigEvictionMap},
+	"eviction-max-pod-grace-period":          {"evictionMaxPodGracePeriod", kubeletConfigInt},
+	"eviction-minimum-reclaim":               {"evictionMinimumReclaim", kubeletConfigStringMap},
+	"eviction-pressure-transition-period":    {"evictionPressureTransitionPeriod", kubeletConfigString},
+	"eviction-soft":                          {"evictionSoft", kubeletConfigEvictionMap},
+	"eviction-soft-grace-period":             {"evictionSoftGracePeriod", kubeletConfigStringMap},
+	"fail-swap-on":                           {"failSwapOn", kubeletConfigBool},
+	"feature-gates":                          {"featureGates", kubeletConfigBoolMap},
+	"hairpin-mode":                           {"hairpinMode", kubeletConfigString},
+	"image-gc-high-threshold":                {"imageGCHighThresholdPercent", kubeletConfigInt},
+	"image-gc-low-threshold":                 {"imageGCLowThresholdPercent", kubeletConfigInt},
+	"kube-reserved":                          {"kubeReserved", kubeletConfigStringMap},
+	"kube-reserved-cgroup":                   {"kubeReservedCgroup", kubeletConfigString},
+	"kubelet-cgroups":                        {"kubeletCgroups", kubeletConfigString},
+	"max-pods":                               {"maxPods", kubeletConfigInt},
+	"node-status-update-frequency":           {"nodeStatusUpdateFrequency", kubeletConfigString},
+	"pod-cidr":                               {"podCIDR", kubeletConfigString},
+	"pod-manifest-path":                      {"staticPodPath", kubeletConfigString},
+	"pod-max-pids":                           {"podPidsLimit", kubeletConfigInt},
+	"protect-kernel-defaults":                {"protectKernelDefaults", kubeletConfigBool},
+	"read-only-port":                         {"readOnlyPort", kubeletConfigInt},
+	"registry-burst":                         {"registryBurst", kubeletConfigInt},
+	"registry-qps":                           {"registryPullQPS", kubeletConfigInt},
+	"resolv-conf":                            {"resolvConf", kubeletConfigString},
+	"rotate-certificates":                    {"rotateCertificates", kubeletConfigBool},
+	"rotate-server-certificates":             {"serverTLSBootstrap", kubeletConfigBool},
+	"runtime-request-timeout":                {"runtimeRequestTimeout", kubeletConfigString},
+	"serialize-image-pulls":                  {"serializeImagePulls", kubeletConfigBool},
+	"streaming-connection-idle-timeout":      {"streamingConnectionIdleTimeout", kubeletConfigString},
+	"system-cgroups":                         {"systemCgroups", kubeletConfigString},
+	"system-reserved":                        {"systemReserved", kubeletConfigStringMap},
+	"system-reserved-cgroup":                 {"systemReservedCgroup", kubeletConfigString},
+	"tls-cert-file":                          {"tlsCertFile", kubeletConfigString},
+	"tls-cipher-suites":                      {"tlsCipherSuites", kubeletConfigStringSlice},
+	"tls-min-version":                        {"tlsMinVersion", kubeletConfigString},
+	"tls-private-key-file":                   {"tlsPrivateKeyFile", kubeletConfigString},
+	"topology-manager-policy":                {"topologyManagerPolicy", kubeletConfigString},
+	"volume-plugin-dir":                      {"volumePluginDir", kubeletConfigString},
+	"volume-stats-agg-period":                {"volumeStatsAggPeriod", kubeletConfigString},
+}
+
+// buildKubeletConfigFile moves the flags that have a KubeletConfiguration equivalent into a config file,
+// deep-merges the drop-ins over it, and returns the remaining flags and the contents of the file
+func buildKubeletConfigFile(flags []string, dropIns map[string]string) ([]string, string, error) {
+	config := map[string]interface{}{}
+
+	var remaining []string
+	for _, flag := range flags {
+		name, value, ok := splitFlag(flag)
+		if !ok {
+			remaining = append(remaining, flag)
+			continue
+		}
+		f, found := kubeletConfigFields[name]
+		if !found {
+			remaining = append(remaining, flag)
+			continue
+		}
+		v, err := convertKubeletConfigValue(f.Type, value)
+		if err != nil {
+			return nil, "", fmt.Errorf("error converting kubelet flag %q: %v", name, err)
+		}
+		setKubeletConfigValue(config, f.Path, v)
+	}
+
+	var names []string
+	for name := range dropIns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dropIn := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(dropIns[name]), &dropIn); err != nil {
+			return nil, "", fmt.Errorf("error parsing kubelet config drop-in %q: %v", name, err)
+		}
+		delete(dropIn, "apiVersion")
+		delete(dropIn, "kind")
+		mergeKubeletConfig(config, dropIn)
+	}
+
+	config["apiVersion"] = kubeletConfigAPIVersion
+	config["kind"] = kubeletConfigKind
+
+	b, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, "", fmt.Errorf("error building kubelet config file: %v", err)
+	}
+
+	remaining = append(remaining, "--config="+kubeletConfigFilePath)
+	return remaining, string(b), nil
+}
+
+// splitFlag splits a flag of the form --name=value
+func splitFlag(flag string) (string, string, bool) {
+	if !strings.HasPrefix(flag, "--") {
+		return "", "", false
+	}
+	tokens := strings.SplitN(strings.TrimPrefix(flag, "--"), "=", 2)
+	if len(tokens) != 2 {
+		return "", "", false
+	}
+	return tokens[0], tokens[1], true
+}
+
+// convertKubeletConfigValue converts the value of a flag to the type of its KubeletConfiguration field
+func convertKubeletConfigValue(t kubeletConfigValueType, value string) (interface{}, error) {
+	switch t {
+	case kubeletConfigString:
+		return value, nil
+
+	case kubeletConfigBool:
+		return strconv.ParseBool(value)
+
+	case kubeletConfigInt:
+		return strconv.ParseInt(value, 10, 64)
+
+	case kubeletConfigStringSlice:
+		var values []interface{}
+		for _, s := range strings.Split(value, ",") {
+			values = append(values, s)
+		}
+		return values, nil
+
+	case kubeletConfigStringMap, kubeletConfigBoolMap, kubeletConfigEvictionMap:
+		m := map[string]interface{}{}
+		for _, s := range strings.Split(value, ",") {
+			separator := "="
+			if t == kubeletConfigEvictionMap {
+				separator = "<"
+			}
+			tokens := strings.SplitN(s, separator, 2)
+			if len(tokens) != 2 {
+				return nil, fmt.Errorf("cannot parse %q", s)
+			}
+			k, v := strings.TrimSpace(tokens[0]), strings.TrimSpace(tokens[1])
+			if t == kubeletConfigBoolMap {
+				b, err := strconv.ParseBool(v)
+				if err != nil {
+					return nil, err
+				}
+				m[k] = b
+			} else {
+				m[k] = v
+			}
+		}
+		return m, nil
+
+	default:
+		return nil, fmt.Errorf("unhandled type %v", t)
+	}
+}
+
+// setKubeletConfigValue sets the field at the dotted path, creating the parent objects as needed
+func setKubeletConfigValue(config map[string]interface{}, path string, value interface{}) {
+	tokens := strings.Split(path, ".")
+	for _, token := range tokens[:len(tokens)-1] {
+		child, ok := config[token].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			config[token] = child
+		}
+		config = child
+	}
+	config[tokens[len(tokens)-1]] = value
+}
+
+// mergeKubeletConfig deep-merges src into dest: objects are merged recursively, other values are replaced
+func mergeKubeletConfig(dest, src map[string]interface{}) {
+	for k, v := range src {
+		srcChild, srcIsMap := v.(map[string]interface{})
+		destChild, destIsMap := dest[k].(map[string]interface{})
+		if srcIsMap && destIsMap {
+			mergeKubeletConfig(destChild, srcChild)
+			continue
+		}
+		dest[k] = v
+	}
+}
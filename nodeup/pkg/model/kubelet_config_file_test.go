@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildKubeletConfigFile(t *testing.T) {
+	flags := []string{
+		"--anonymous-auth=false",
+		"--cgroup-root=/",
+		"--client-ca-file=/srv/kubernetes/ca.crt",
+		"--cluster-dns=100.64.0.10",
+		"--eviction-hard=memory.available<100Mi,nodefs.available<10%",
+		"--feature-gates=CSIMigration=true,RotateKubeletServerCertificate=false",
+		"--hostname-override=ip-10-0-0-1.ec2.internal",
+		"--kube-reserved=cpu=100m,memory=256Mi",
+		"--max-pods=110",
+		"--cloud-config=/etc/kubernetes/cloud.config",
+	}
+	dropIns := map[string]string{
+		"20-workers": strings.Join([]string{
+			"maxPods: 200",
+			"authentication:",
+			"  webhook:",
+			"    enabled: true",
+		}, "\n"),
+		"10-cluster": strings.Join([]string{
+			"apiVersion: kubelet.config.k8s.io/v1beta1",
+			"kind: KubeletConfiguration",
+			"maxPods: 150",
+			"shutdownGracePeriod: 30s",
+		}, "\n"),
+	}
+
+	remaining, config, err := buildKubeletConfigFile(flags, dropIns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedFlags := []string{
+		"--hostname-override=ip-10-0-0-1.ec2.internal",
+		"--cloud-config=/etc/kubernetes/cloud.config",
+		"--config=/var/lib/kubelet/kubelet-config.yaml",
+	}
+	if !reflect.DeepEqual(remaining, expectedFlags) {
+		t.Errorf("unexpected flags: %v", remaining)
+	}
+
+	expectedConfig := strings.Join([]string{
+		"apiVersion: kubelet.config.k8s.io/v1beta1",
+		"authentication:",
+		"  anonymous:",
+		"    enabled: false",
+		"  webhook:",
+		"    enabled: true",
+		"  x509:",
+		"    clientCAFile: /srv/kubernetes/ca.crt",
+		"cgroupRoot: /",
+		"clusterDNS:",
+		"- 100.64.0.10",
+		"evictionHard:",
+		"  memory.available: 100Mi",
+		"  nodefs.available: 10%",
+		"featureGates:",
+		"  CSIMigration: true",
+		"  RotateKubeletServerCertificate: false",
+		"kind: KubeletConfiguration",
+		"kubeReserved:",
+		"  cpu: 100m",
+		"  memory: 256Mi",
+		"maxPods: 200",
+		"shutdownGracePeriod: 30s",
+		"",
+	}, "\n")
+	if config != expectedConfig {
+		t.Errorf("unexpected config, expected:\n%s\nactual:\n%s", expectedConfig, config)
+	}
+}
+
+func TestBuildKubeletConfigFileErrors(t *testing.T) {
+	grid := []struct {
+		flags   []string
+		dropIns map[string]string
+		err     string
+	}{
+		{
+			flags: []string{"--max-pods=many"},
+			err:   `error converting kubelet flag "max-pods"`,
+		},
+		{
+			flags: []string{"--eviction-hard=memory.available"},
+			err:   `error converting kubelet flag "eviction-hard": cannot parse "memory.available"`,
+		},
+		{
+			dropIns: map[string]string{"bad": "- maxPods"},
+			err:     `error parsing kubelet config drop-in "bad"`,
+		},
+	}
+	for _, g := range grid {
+		_, _, err := buildKubeletConfigFile(g.flags, g.dropIns)
+		if err == nil || !strings.HasPrefix(err.Error(), g.err) {
+			t.Errorf("expected error %q, got %v", g.err, err)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package model
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"k8s.io/klog/v2"
@@ -126,6 +127,58 @@ func TestTaintsApplied(t *testing.T) {
 	}
 }
 
+func TestKubeletServerTLSBootstrap(t *testing.T) {
+	for _, serverTLSBootstrap := range []bool{false, true} {
+		cluster := &kops.Cluster{Spec: kops.ClusterSpec{CloudProvider: "aws", KubernetesVersion: "1.21.0", Networking: &kops.NetworkingSpec{}}}
+		ig := &kops.InstanceGroup{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode}}
+
+		config, bootConfig := nodeup.NewConfig(cluster, ig)
+		b := &KubeletBuilder{
+			&NodeupModelContext{
+				Cluster:      cluster,
+				BootConfig:   bootConfig,
+				NodeupConfig: config,
+			},
+		}
+		if err := b.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		kubeletConfig := &kops.KubeletConfigSpec{ServerTLSBootstrap: fi.Bool(serverTLSBootstrap)}
+		flags, err := b.buildKubeletFlags(kubeletConfig)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		hasServingCert := false
+		hasRotateServerCertificates := false
+		for _, flag := range flags {
+			switch {
+			case strings.HasPrefix(flag, "--tls-cert-file="):
+				hasServingCert = true
+			case flag == "--rotate-server-certificates=true":
+				hasRotateServerCertificates = true
+			}
+		}
+		if hasServingCert == serverTLSBootstrap {
+			t.Errorf("serverTLSBootstrap=%v: unexpected --tls-cert-file in %v", serverTLSBootstrap, flags)
+		}
+		if hasRotateServerCertificates != serverTLSBootstrap {
+			t.Errorf("serverTLSBootstrap=%v: unexpected --rotate-server-certificates in %v", serverTLSBootstrap, flags)
+		}
+
+		if serverTLSBootstrap {
+			c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+			if err := b.buildKubeletServingCertificate(c, kubeletConfig); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(c.Tasks) != 0 {
+				t.Errorf("expected no serving certificate tasks with serverTLSBootstrap, got %v", c.Tasks)
+			}
+		}
+	}
+}
+
 func stringSlicesEqual(exp, other []string) bool {
 	if exp == nil && other != nil {
 		return false
@@ -222,12 +275,12 @@ func runKubeletBuilder(t *testing.T, context *fi.ModelBuilderContext, nodeupMode
 		return
 	}
 
-	fileTask, err := builder.buildSystemdEnvironmentFile(kubeletConfig)
+	flags, err := builder.buildKubeletFlags(kubeletConfig)
 	if err != nil {
-		t.Fatalf("error from KubeletBuilder buildSystemdEnvironmentFile: %v", err)
+		t.Fatalf("error from KubeletBuilder buildKubeletFlags: %v", err)
 		return
 	}
-	context.AddTask(fileTask)
+	context.AddTask(builder.buildSystemdEnvironmentFile(flags))
 
 	{
 		task, err := builder.buildManifestDirectory(kubeletConfig)
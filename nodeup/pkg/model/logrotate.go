@@ -37,6 +37,7 @@ var _ fi.ModelBuilder = &LogrotateBuilder{}
 
 // Build is responsible for configuring logrotate
 func (b *LogrotateBuilder) Build(c *fi.ModelBuilderContext) error {
+	b.addJournaldConfig(c)
 
 	switch b.Distribution {
 	case distributions.DistributionContainerOS:
@@ -84,6 +85,31 @@ func (b *LogrotateBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
+// addJournaldConfig limits the disk space used by the journal, and keeps it on the log volume of the instance group
+func (b *LogrotateBuilder) addJournaldConfig(c *fi.ModelBuilderContext) {
+	logging := b.NodeupConfig.Logging
+	if logging == nil || (logging.JournaldSystemMaxUse == "" && logging.Volume == nil) {
+		return
+	}
+
+	lines := []string{"[Journal]"}
+	if logging.Volume != nil {
+		// The journal must be recreated on the log volume, which is mounted over the previous /var/log
+		lines = append(lines, "Storage=persistent")
+	}
+	if logging.JournaldSystemMaxUse != "" {
+		lines = append(lines, "SystemMaxUse="+logging.JournaldSystemMaxUse)
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:            "/etc/systemd/journald.conf.d/10-kops.conf",
+		Contents:        fi.NewStringResource(strings.Join(lines, "\n") + "\n"),
+		Type:            nodetasks.FileType_File,
+		Mode:            s("0644"),
+		OnChangeExecute: [][]string{{"systemctl", "restart", "systemd-journald.service"}},
+	})
+}
+
 // addLogrotateService creates a logrotate systemd task to act as target for the timer, if one is needed
 func (b *LogrotateBuilder) addLogrotateService(c *fi.ModelBuilderContext) error {
 	switch b.Distribution {
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"path/filepath"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	// nodePluginsDir is the directory the artifacts of each plugin are fetched into
+	nodePluginsDir = "/opt/kops/plugins"
+	// defaultNodePluginTimeout is the timeout of a plugin that does not specify one
+	defaultNodePluginTimeout = 5 * time.Minute
+)
+
+// NodePluginBuilder runs the nodeup plugins
+type NodePluginBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &NodePluginBuilder{}
+
+// Build is responsible for adding a task for each nodeup plugin
+func (b *NodePluginBuilder) Build(c *fi.ModelBuilderContext) error {
+	for _, plugin := range b.NodeupConfig.NodePlugins {
+		// Warming instances are shut down before the kubelet starts.
+		if b.ConfigurationMode == "Warming" && plugin.Stage != kops.NodePluginStagePreInstall {
+			continue
+		}
+
+		task := &nodetasks.NodePluginTask{
+			Name:        plugin.Name,
+			Stage:       plugin.Stage,
+			Dir:         filepath.Join(nodePluginsDir, plugin.Name),
+			Artifacts:   plugin.Artifacts,
+			Command:     plugin.Command,
+			Environment: plugin.Environment,
+			Timeout:     defaultNodePluginTimeout,
+			Retries:     int(fi.Int32Value(plugin.Retries)),
+		}
+		if plugin.Timeout != nil {
+			task.Timeout = plugin.Timeout.Duration
+		}
+
+		if plugin.Stage == kops.NodePluginStagePostJoin {
+			nodeName, err := b.NodeName()
+			if err != nil {
+				return err
+			}
+			task.NodeName = nodeName
+			task.Kubeconfig = b.KubeletKubeConfig()
+		}
+
+		c.AddTask(task)
+	}
+
+	return nil
+}
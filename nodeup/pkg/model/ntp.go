@@ -17,12 +17,24 @@ limitations under the License.
 package model
 
 import (
+	"fmt"
+	"strings"
+
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/util/pkg/distributions"
 )
 
+const (
+	timeSyncWaitServiceName    = "kops-time-sync-wait.service"
+	timeSyncWaitScriptPath     = "/opt/kops/bin/time-sync-wait"
+	timeSyncMetricsServiceName = "kops-time-sync-metrics.service"
+	timeSyncMetricsTimerName   = "kops-time-sync-metrics.timer"
+	timeSyncMetricsScriptPath  = "/opt/kops/bin/time-sync-metrics"
+)
+
 // NTPBuilder installs and starts NTP, to ensure accurate clock times.
 // As well as general log confusion, clock-skew of more than 5 minutes
 // causes AWS API calls to fail
@@ -48,33 +60,25 @@ func (b *NTPBuilder) Build(c *fi.ModelBuilderContext) error {
 		return nil
 	}
 
-	var ntpHost string
-	switch b.Cluster.Spec.CloudProvider {
-	case "aws":
-		ntpHost = "169.254.169.123"
-	case "gce":
-		ntpHost = "time.google.com"
-	default:
-		ntpHost = ""
-	}
+	ntpHosts := b.servers()
 
 	if b.Distribution.IsDebianFamily() {
 		if b.Distribution.IsUbuntu() {
-			if ntpHost != "" {
-				c.AddTask(b.buildTimesyncdConf("/etc/systemd/timesyncd.conf", ntpHost))
+			if len(ntpHosts) != 0 {
+				c.AddTask(b.buildTimesyncdConf("/etc/systemd/timesyncd.conf", ntpHosts))
 			}
 			c.AddTask((&nodetasks.Service{Name: "systemd-timesyncd"}).InitDefaults())
 		} else {
 			c.AddTask(&nodetasks.Package{Name: "chrony"})
-			if ntpHost != "" {
-				c.AddTask(b.buildChronydConf("/etc/chrony/chrony.conf", ntpHost))
+			if len(ntpHosts) != 0 {
+				c.AddTask(b.buildChronydConf("/etc/chrony/chrony.conf", ntpHosts))
 			}
 			c.AddTask((&nodetasks.Service{Name: "chrony"}).InitDefaults())
 		}
 	} else if b.Distribution.IsRHELFamily() {
 		c.AddTask(&nodetasks.Package{Name: "chrony"})
-		if ntpHost != "" {
-			c.AddTask(b.buildChronydConf("/etc/chrony.conf", ntpHost))
+		if len(ntpHosts) != 0 {
+			c.AddTask(b.buildChronydConf("/etc/chrony.conf", ntpHosts))
 		}
 		c.AddTask((&nodetasks.Service{Name: "chronyd"}).InitDefaults())
 	} else {
@@ -82,14 +86,55 @@ func (b *NTPBuilder) Build(c *fi.ModelBuilderContext) error {
 		return nil
 	}
 
+	if b.WaitForTimeSync() {
+		c.AddTask(&nodetasks.File{
+			Path:     timeSyncWaitScriptPath,
+			Contents: fi.NewStringResource(timeSyncWaitScript),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0755"),
+		})
+		c.AddTask(b.buildTimeSyncWaitService())
+	}
+
+	if ntp := b.Cluster.Spec.NTP; ntp != nil && ntp.MetricsTextfileDirectory != "" {
+		c.AddTask(&nodetasks.File{
+			Path:     timeSyncMetricsScriptPath,
+			Contents: fi.NewStringResource(fmt.Sprintf(timeSyncMetricsScript, ntp.MetricsTextfileDirectory)),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0755"),
+		})
+		c.AddTask(b.buildTimeSyncMetricsService())
+		c.AddTask(b.buildTimeSyncMetricsTimer())
+	}
+
 	return nil
 }
 
-func (b *NTPBuilder) buildChronydConf(path string, host string) *nodetasks.File {
+// servers returns the NTP servers of the cluster, defaulting to the link-local time service of the cloud provider
+func (b *NTPBuilder) servers() []string {
+	if b.Cluster.Spec.NTP != nil && len(b.Cluster.Spec.NTP.Servers) != 0 {
+		return b.Cluster.Spec.NTP.Servers
+	}
+
+	switch b.Cluster.Spec.CloudProvider {
+	case "aws":
+		return []string{"169.254.169.123"}
+	case "gce":
+		return []string{"metadata.google.internal"}
+	default:
+		return nil
+	}
+}
+
+func (b *NTPBuilder) buildChronydConf(path string, hosts []string) *nodetasks.File {
+	var pools []string
+	for _, host := range hosts {
+		pools = append(pools, "pool "+host+" prefer iburst\n")
+	}
+
 	conf := `# Built by Kops - do NOT edit
 
-pool ` + host + ` prefer iburst
-driftfile /var/lib/chrony/drift
+` + strings.Join(pools, "") + `driftfile /var/lib/chrony/drift
 leapsectz right/UTC
 logdir /var/log/chrony
 makestep 1.0 3
@@ -104,11 +149,11 @@ rtcsync
 	}
 }
 
-func (b *NTPBuilder) buildTimesyncdConf(path string, host string) *nodetasks.File {
+func (b *NTPBuilder) buildTimesyncdConf(path string, hosts []string) *nodetasks.File {
 	conf := `# Built by Kops - do NOT edit
 
 [Time]
-NTP=` + host + `
+NTP=` + strings.Join(hosts, " ") + `
 `
 	return &nodetasks.File{
 		Path:     path,
@@ -118,6 +163,121 @@ NTP=` + host + `
 	}
 }
 
+// buildTimeSyncWaitService keeps the kubelet from starting until the clock is synchronized
+func (b *NTPBuilder) buildTimeSyncWaitService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Wait for the clock to be synchronized")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "After", "chrony.service chronyd.service systemd-timesyncd.service")
+	manifest.Set("Unit", "Before", "kubelet.service")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", timeSyncWaitScriptPath)
+	manifest.Set("Service", "TimeoutStartSec", "600")
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", timeSyncWaitServiceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       timeSyncWaitServiceName,
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
+
+func (b *NTPBuilder) buildTimeSyncMetricsService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Write the clock synchronization metrics once")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "ExecStart", timeSyncMetricsScriptPath)
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", timeSyncMetricsServiceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       timeSyncMetricsServiceName,
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
+
+func (b *NTPBuilder) buildTimeSyncMetricsTimer() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Write the clock synchronization metrics periodically")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Timer", "OnUnitInactiveSec", "60s")
+	manifest.Set("Timer", "Unit", timeSyncMetricsServiceName)
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built timer manifest %q\n%s", timeSyncMetricsTimerName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       timeSyncMetricsTimerName,
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
+
+// timeSyncWaitScript waits up to 5 minutes for the clock to be synchronized, using the status reported by timedatectl
+// for both chrony and systemd-timesyncd; it fails otherwise, so that nodeup retries before starting the kubelet
+const timeSyncWaitScript = `#!/bin/bash
+# Built by kops - do not edit
+
+for i in $(seq 60); do
+  if timedatectl status | grep -q "synchronized: yes"; then
+    exit 0
+  fi
+  sleep 5
+done
+echo "clock is not synchronized"
+exit 1
+`
+
+// timeSyncMetricsScript writes the clock synchronization metrics in the Prometheus text format;
+// the offset is only known when chrony is used
+const timeSyncMetricsScript = `#!/bin/bash
+# Built by kops - do not edit
+
+set -o errexit
+
+dir=%s
+mkdir -p ${dir}
+tmp=$(mktemp ${dir}/kops_time_sync.prom.XXXXXX)
+
+synchronized=0
+if timedatectl status | grep -q "synchronized: yes"; then
+  synchronized=1
+fi
+echo "# HELP kops_time_sync_synchronized Whether the system clock is synchronized." >> ${tmp}
+echo "# TYPE kops_time_sync_synchronized gauge" >> ${tmp}
+echo "kops_time_sync_synchronized ${synchronized}" >> ${tmp}
+
+if command -v chronyc > /dev/null; then
+  offset=$(chronyc -c tracking | cut -d, -f5)
+  if [[ -n "${offset}" ]]; then
+    echo "# HELP kops_time_sync_offset_seconds Offset of the system clock from the NTP time." >> ${tmp}
+    echo "# TYPE kops_time_sync_offset_seconds gauge" >> ${tmp}
+    echo "kops_time_sync_offset_seconds ${offset}" >> ${tmp}
+  fi
+fi
+
+chmod 0644 ${tmp}
+mv ${tmp} ${dir}/kops_time_sync.prom
+`
+
 // managed determines if kops should manage the installation and configuration of NTP.
 func (b *NTPBuilder) managed() bool {
 	n := b.Cluster.Spec.NTP
@@ -174,6 +174,7 @@ type ProtokubeFlags struct {
 	ApplyTaints               *bool    `json:"applyTaints,omitempty" flag:"apply-taints"`
 	Channels                  []string `json:"channels,omitempty" flag:"channels"`
 	Cloud                     *string  `json:"cloud,omitempty" flag:"cloud"`
+	ClusterID                 *string  `json:"cluster-id,omitempty" flag:"cluster-id"`
 	Containerized             *bool    `json:"containerized,omitempty" flag:"containerized"`
 	DNSInternalSuffix         *string  `json:"dnsInternalSuffix,omitempty" flag:"dns-internal-suffix"`
 	DNSProvider               *string  `json:"dnsProvider,omitempty" flag:"dns"`
@@ -213,6 +214,8 @@ type ProtokubeFlags struct {
 	GossipProtocol *string `json:"gossip-protocol" flag:"gossip-protocol"`
 	GossipListen   *string `json:"gossip-listen" flag:"gossip-listen"`
 	GossipSecret   *string `json:"gossip-secret" flag:"gossip-secret"`
+	// GossipSeeds are the peers to join the gossip network through, on clouds where protokube cannot discover them.
+	GossipSeeds []string `json:"gossip-seeds,omitempty" flag:"gossip-seeds"`
 
 	GossipProtocolSecondary *string `json:"gossip-protocol-secondary" flag:"gossip-protocol-secondary" flag-include-empty:"true"`
 	GossipListenSecondary   *string `json:"gossip-listen-secondary" flag:"gossip-listen-secondary"`
@@ -331,6 +334,8 @@ func (t *ProtokubeBuilder) ProtokubeFlags(k8sVersion semver.Version) (*Protokube
 			}
 		}
 
+		f.GossipSeeds = t.NodeupConfig.GossipSeeds
+
 		// @TODO: This is hacky, but we want it so that we can have a different internal & external name
 		internalSuffix := t.Cluster.Spec.MasterInternalName
 		internalSuffix = strings.TrimPrefix(internalSuffix, "api.")
@@ -340,6 +345,11 @@ func (t *ProtokubeBuilder) ProtokubeFlags(k8sVersion semver.Version) (*Protokube
 	if t.Cluster.Spec.CloudProvider != "" {
 		f.Cloud = fi.String(t.Cluster.Spec.CloudProvider)
 
+		// Without a cloud API, protokube cannot determine the cluster from the machine.
+		if kops.CloudProviderID(t.Cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+			f.ClusterID = fi.String(t.Cluster.ObjectMeta.Name)
+		}
+
 		if f.DNSProvider == nil {
 			switch kops.CloudProviderID(t.Cluster.Spec.CloudProvider) {
 			case kops.CloudProviderAWS:
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+const appArmorProfilesDir = "/etc/apparmor.d"
+
+// selinuxEnforceFile is only present when the running kernel has SELinux enabled, and selinuxfs mounted
+const selinuxEnforceFile = "/sys/fs/selinux/enforce"
+
+// SecurityModulesBuilder configures the SELinux mode and loads the AppArmor profiles of the instance group
+type SecurityModulesBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &SecurityModulesBuilder{}
+
+// Build is responsible for configuring the Linux security modules before the kubelet starts
+func (b *SecurityModulesBuilder) Build(c *fi.ModelBuilderContext) error {
+	if selinux := b.NodeupConfig.SELinux; selinux != nil {
+		if !b.Distribution.SupportsSELinux() {
+			return fmt.Errorf("SELinux is not supported on distribution %v", b.Distribution)
+		}
+
+		mode := "enforcing"
+		setenforce := "1"
+		if selinux.Mode == kops.SELinuxModePermissive {
+			mode = "permissive"
+			setenforce = "0"
+		}
+		policy := "targeted"
+		if b.Distribution == distributions.DistributionFlatcar {
+			policy = "mcs"
+		}
+
+		// setenforce fails when SELinux is disabled, which can only be changed by rebooting with the new config
+		var onChangeExecute [][]string
+		if _, err := os.Stat(selinuxEnforceFile); err == nil {
+			onChangeExecute = [][]string{{"setenforce", setenforce}}
+		} else if os.IsNotExist(err) {
+			klog.Warningf("SELinux is disabled, mode %q will only apply after a reboot", mode)
+		} else {
+			return fmt.Errorf("error checking if SELinux is enabled: %v", err)
+		}
+
+		c.AddTask(&nodetasks.File{
+			Path:            "/etc/selinux/config",
+			Contents:        fi.NewStringResource(fmt.Sprintf("# Built by kops - do not edit\nSELINUX=%s\nSELINUXTYPE=%s\n", mode, policy)),
+			Type:            nodetasks.FileType_File,
+			Mode:            s("0644"),
+			OnChangeExecute: onChangeExecute,
+			BeforeServices:  []string{kubeletService},
+		})
+	}
+
+	if apparmor := b.NodeupConfig.AppArmor; apparmor != nil {
+		if !b.Distribution.SupportsAppArmor() {
+			return fmt.Errorf("AppArmor is not supported on distribution %v", b.Distribution)
+		}
+
+		for _, profile := range apparmor.Profiles {
+			path := filepath.Join(appArmorProfilesDir, profile.Name)
+			c.AddTask(&nodetasks.File{
+				Path:            path,
+				Contents:        fi.NewStringResource(profile.Content),
+				Type:            nodetasks.FileType_File,
+				Mode:            s("0644"),
+				OnChangeExecute: [][]string{{"apparmor_parser", "--replace", path}},
+				BeforeServices:  []string{kubeletService},
+			})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+const (
+	sshServiceName           = "kops-sshd-config.service"
+	sshScriptPath            = "/opt/kops/bin/sshd-config-setup"
+	sshdConfigDropInPath     = "/etc/ssh/sshd_config.d/50-kops.conf"
+	sshdConfigStagedPath     = "/opt/kops/conf/sshd_config"
+	sshAuthorizedKeysDir     = "/etc/ssh/kops/authorized_keys"
+	sshTrustedUserCAKeysPath = "/etc/ssh/kops/trusted_user_ca_keys"
+)
+
+// SSHBuilder configures SSH access beyond the primary SSH public key provided by the cloud:
+// additional authorized keys, SSH certificate authorities, and EC2 Instance Connect
+type SSHBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &SSHBuilder{}
+
+// Build is responsible for configuring sshd
+func (b *SSHBuilder) Build(c *fi.ModelBuilderContext) error {
+	config := b.NodeupConfig.SSH
+	if config == nil {
+		return nil
+	}
+
+	if config.InstanceConnect {
+		// EC2 Instance Connect configures sshd itself when it is installed
+		if b.Distribution.IsUbuntu() || b.Distribution == distributions.DistributionAmazonLinux2 {
+			c.AddTask(&nodetasks.Package{Name: "ec2-instance-connect"})
+		} else {
+			klog.Warningf("EC2 Instance Connect is only installed on Ubuntu and Amazon Linux 2")
+		}
+	}
+
+	var settings []string
+
+	if len(config.AuthorizedKeys) != 0 {
+		user, err := b.findSSHUser(config.User)
+		if err != nil {
+			return err
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     filepath.Join(sshAuthorizedKeysDir, user),
+			Contents: fi.NewStringResource(strings.Join(config.AuthorizedKeys, "\n") + "\n"),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0644"),
+		})
+		settings = append(settings, "AuthorizedKeysFile .ssh/authorized_keys "+sshAuthorizedKeysDir+"/%u")
+	}
+
+	if len(config.TrustedUserCAKeys) != 0 {
+		c.AddTask(&nodetasks.File{
+			Path:     sshTrustedUserCAKeysPath,
+			Contents: fi.NewStringResource(strings.Join(config.TrustedUserCAKeys, "\n") + "\n"),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0644"),
+		})
+		settings = append(settings, "TrustedUserCAKeys "+sshTrustedUserCAKeysPath)
+	}
+
+	if len(settings) == 0 {
+		return nil
+	}
+
+	// The drop-in is only moved into place by the script once sshd accepts it
+	c.AddTask(&nodetasks.File{
+		Path:     sshdConfigStagedPath,
+		Contents: fi.NewStringResource("# Built by kops - do not edit\n" + strings.Join(settings, "\n") + "\n"),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0644"),
+	})
+	c.AddTask(&nodetasks.File{
+		Path:     sshScriptPath,
+		Contents: fi.NewStringResource(b.buildSSHScript()),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0755"),
+	})
+	c.AddTask(b.buildSystemdService())
+
+	return nil
+}
+
+// findSSHUser returns the user that additional keys are authorized for:
+// the configured user, or else the first default user of the distribution that exists
+func (b *SSHBuilder) findSSHUser(user string) (string, error) {
+	if user != "" {
+		return user, nil
+	}
+
+	users, err := b.Distribution.DefaultUsers()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine the SSH user; set the user for SSH in the cluster spec: %v", err)
+	}
+	for _, name := range users {
+		if name == "root" {
+			continue
+		}
+		u, err := fi.LookupUser(name)
+		if err != nil {
+			klog.Warningf("error looking up user %q: %v", name, err)
+			continue
+		}
+		if u != nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unable to find a default user for SSH; set the user for SSH in the cluster spec")
+}
+
+// buildSSHScript renders the script that installs the kops drop-in, and makes sshd read it ahead of the settings of the image.
+// Older images do not include the drop-in directory in their sshd configuration.
+// The resulting configuration is validated with sshd before any file is replaced, and files are replaced by renaming,
+// so that an invalid configuration never locks out SSH access.
+func (b *SSHBuilder) buildSSHScript() string {
+	return fmt.Sprintf(`#!/bin/bash
+# Built by kops - do not edit
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+staged=%q
+dropin=%q
+config=/etc/ssh/sshd_config
+
+tmp=$(mktemp -d /etc/ssh/.kops.XXXXXX)
+trap 'rm -rf "${tmp}"' EXIT
+
+# sshd uses the first value of each setting, so the drop-in is read ahead of the settings of the image
+include=
+if ! grep -q -E '^Include /etc/ssh/sshd_config\.d/\*\.conf' "${config}"; then
+  include="Include /etc/ssh/sshd_config.d/*.conf"
+fi
+
+cat "${staged}" "${config}" > "${tmp}/validate"
+sshd -t -f "${tmp}/validate"
+
+mkdir -p "$(dirname "${dropin}")"
+install -m 0644 "${staged}" "$(dirname "${dropin}")/.50-kops.conf.tmp"
+mv -f "$(dirname "${dropin}")/.50-kops.conf.tmp" "${dropin}"
+
+if [[ -n "${include}" ]]; then
+  cp -p "${config}" "${tmp}/sshd_config"
+  { echo "${include}"; cat "${config}"; } > "${tmp}/sshd_config"
+  sshd -t -f "${tmp}/sshd_config"
+  mv -f "${tmp}/sshd_config" "${config}"
+fi
+
+if command -v restorecon >/dev/null 2>&1; then
+  restorecon "${config}" "${dropin}"
+fi
+
+sshd -t -f "${config}"
+if systemctl cat ssh.service >/dev/null 2>&1; then
+  systemctl reload-or-restart ssh.service
+else
+  systemctl reload-or-restart sshd.service
+fi
+`, sshdConfigStagedPath, sshdConfigDropInPath)
+}
+
+func (b *SSHBuilder) buildSystemdService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Configure sshd for kops")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", sshScriptPath)
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", sshServiceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       sshServiceName,
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+const (
+	ssmAgentServiceName = "kops-ssm-agent.service"
+	ssmAgentScriptPath  = "/opt/kops/bin/ssm-agent-setup"
+	ssmAgentPackageDir  = "/var/cache/nodeup/ssm-agent"
+)
+
+// SSMAgentBuilder makes sure the AWS Systems Manager agent is installed and running, for Session Manager access
+type SSMAgentBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &SSMAgentBuilder{}
+
+// Build is responsible for installing the SSM agent when the image does not include it
+func (b *SSMAgentBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !model.UseSessionManager(b.Cluster) || kops.CloudProviderID(b.Cluster.Spec.CloudProvider) != kops.CloudProviderAWS || b.IsHybrid {
+		return nil
+	}
+
+	if b.Distribution == distributions.DistributionFlatcar {
+		klog.Warningf("the SSM agent cannot be installed on Flatcar; Session Manager access requires an image that includes it")
+		return nil
+	}
+
+	var packagePath string
+	pkg, err := b.findSSMAgentPackage()
+	if err != nil {
+		return err
+	}
+	if pkg != nil {
+		packagePath = path.Join(ssmAgentPackageDir, path.Base(pkg.URL))
+		asset, err := b.Assets.Find(path.Base(pkg.URL), "")
+		if err != nil {
+			return fmt.Errorf("error trying to locate asset %q: %v", pkg.URL, err)
+		}
+		if asset == nil {
+			return fmt.Errorf("unable to locate asset %q", pkg.URL)
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     packagePath,
+			Contents: asset,
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0644"),
+		})
+	} else {
+		klog.Warningf("no SSM agent package is configured for %s on %s; Session Manager access requires an image that includes the agent", b.Architecture, b.Distribution)
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:     ssmAgentScriptPath,
+		Contents: fi.NewStringResource(b.buildSSMAgentScript(packagePath)),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0755"),
+	})
+	c.AddTask(b.buildSystemdService())
+
+	return nil
+}
+
+// findSSMAgentPackage returns the pinned SSM agent package for the architecture and package system of the instance, or nil.
+// The package is downloaded and verified by nodeup like the other assets.
+func (b *SSMAgentBuilder) findSSMAgentPackage() (*kops.SessionManagerAgentPackage, error) {
+	var extension string
+	switch {
+	case b.Distribution.IsDebianFamily():
+		extension = ".deb"
+	case b.Distribution.IsRHELFamily():
+		extension = ".rpm"
+	default:
+		return nil, nil
+	}
+
+	for i := range b.Cluster.Spec.SessionManager.AgentPackages {
+		pkg := &b.Cluster.Spec.SessionManager.AgentPackages[i]
+		if pkg.Architecture != string(b.Architecture) {
+			continue
+		}
+		u, err := url.Parse(pkg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse SSM agent package URL %q: %v", pkg.URL, err)
+		}
+		if path.Ext(u.Path) == extension {
+			return pkg, nil
+		}
+	}
+	return nil, nil
+}
+
+// buildSSMAgentScript renders the script that enables the SSM agent, first installing it
+// from the pinned package if the image does not include it
+func (b *SSMAgentBuilder) buildSSMAgentScript(packagePath string) string {
+	var install string
+	switch {
+	case packagePath == "":
+		install = `echo "the SSM agent is not installed and no package is configured for this instance" >&2
+exit 1`
+	case path.Ext(packagePath) == ".deb":
+		install = fmt.Sprintf(`dpkg -i %q`, packagePath)
+	default:
+		install = fmt.Sprintf(`rpm -U --replacepkgs %q`, packagePath)
+	}
+
+	return fmt.Sprintf(`#!/bin/bash
+# Built by kops - do not edit
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# The agent is a snap on Ubuntu, and a package on other images that include it
+for unit in amazon-ssm-agent.service snap.amazon-ssm-agent.amazon-ssm-agent.service; do
+  if systemctl cat "${unit}" >/dev/null 2>&1; then
+    systemctl enable --now "${unit}"
+    exit 0
+  fi
+done
+
+%s
+systemctl enable --now amazon-ssm-agent.service
+`, install)
+}
+
+func (b *SSMAgentBuilder) buildSystemdService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Install and start the AWS Systems Manager agent")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "Wants", "network-online.target")
+	manifest.Set("Unit", "After", "network-online.target")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", ssmAgentScriptPath)
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", ssmAgentServiceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       ssmAgentServiceName,
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
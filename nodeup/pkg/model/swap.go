@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	swapServiceName = "kops-swap.service"
+	swapScriptPath  = "/opt/kops/bin/swap-setup"
+	swapFilePath    = "/var/lib/kops/swapfile"
+	zramDevicePath  = "/dev/zram0"
+)
+
+// SwapBuilder creates the swap file, or the zram device, of the instance group
+type SwapBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &SwapBuilder{}
+
+// Build is responsible for enabling swap before the kubelet starts
+func (b *SwapBuilder) Build(c *fi.ModelBuilderContext) error {
+	swap := b.NodeupConfig.Swap
+	if swap == nil || swap.Size == nil || swap.Size.IsZero() {
+		return nil
+	}
+
+	script, err := b.buildSwapScript()
+	if err != nil {
+		return err
+	}
+	c.AddTask(&nodetasks.File{
+		Path:     swapScriptPath,
+		Contents: fi.NewStringResource(script),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0755"),
+	})
+	c.AddTask(b.buildSystemdService())
+
+	return nil
+}
+
+// buildSwapScript renders the script that enables swap; it does nothing if swap is already enabled
+func (b *SwapBuilder) buildSwapScript() (string, error) {
+	swap := b.NodeupConfig.Swap
+	size := swap.Size.Value()
+	if size <= 0 {
+		return "", fmt.Errorf("invalid swap size %q", swap.Size.String())
+	}
+
+	if fi.BoolValue(swap.ZRAM) {
+		return fmt.Sprintf(`#!/bin/bash
+# Built by kops - do not edit
+
+set -o errexit
+
+if swapon --show=NAME --noheadings | grep -q "^%[1]s$"; then
+  exit 0
+fi
+modprobe zram
+echo %[2]d > /sys/block/zram0/disksize
+mkswap %[1]s
+swapon --priority 100 %[1]s
+`, zramDevicePath, size), nil
+	}
+
+	return fmt.Sprintf(`#!/bin/bash
+# Built by kops - do not edit
+
+set -o errexit
+
+if swapon --show=NAME --noheadings | grep -q "^%[1]s$"; then
+  exit 0
+fi
+if [[ ! -f %[1]s ]]; then
+  mkdir -p $(dirname %[1]s)
+  fallocate --length %[2]d %[1]s
+  chmod 0600 %[1]s
+  mkswap %[1]s
+fi
+swapon %[1]s
+`, swapFilePath, size), nil
+}
+
+func (b *SwapBuilder) buildSystemdService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Enable swap for kubernetes")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "Before", "kubelet.service")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", swapScriptPath)
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", swapServiceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       swapServiceName,
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
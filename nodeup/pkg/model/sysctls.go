@@ -18,6 +18,7 @@ package model
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -26,6 +27,9 @@ import (
 	"k8s.io/kops/util/pkg/distributions"
 )
 
+// kernelModulesFilePath is the modules-load.d file for the kernel modules of the instance group
+const kernelModulesFilePath = "/etc/modules-load.d/99-k8s-instancegroup.conf"
+
 // SysctlBuilder set up our sysctls
 type SysctlBuilder struct {
 	*NodeupModelContext
@@ -150,6 +154,20 @@ func (b *SysctlBuilder) Build(c *fi.ModelBuilderContext) error {
 		"net.ipv4.ip_forward=1",
 		"")
 
+	if fi.BoolValue(b.NodeupConfig.KubeletConfig.ProtectKernelDefaults) {
+		// The kubelet fails to start if these differ from its expected values
+		// See https://github.com/kubernetes/kubernetes/blob/master/pkg/kubelet/cm/container_manager_linux.go
+		sysctls = append(sysctls,
+			"# Kernel settings expected by the kubelet with protectKernelDefaults",
+			"vm.overcommit_memory = 1",
+			"vm.panic_on_oom = 0",
+			"kernel.panic = 10",
+			"kernel.panic_on_oops = 1",
+			"kernel.keys.root_maxkeys = 1000000",
+			"kernel.keys.root_maxbytes = 25000000",
+			"")
+	}
+
 	if params := b.NodeupConfig.SysctlParameters; len(params) > 0 {
 		sysctls = append(sysctls,
 			"# Custom sysctl parameters from instance group spec",
@@ -162,6 +180,28 @@ func (b *SysctlBuilder) Build(c *fi.ModelBuilderContext) error {
 		}
 	}
 
+	if swap := b.NodeupConfig.Swap; swap != nil && swap.Swappiness != nil {
+		sysctls = append(sysctls,
+			"# Swap settings from instance group spec",
+			fmt.Sprintf("vm.swappiness = %d", *swap.Swappiness),
+			"")
+	}
+
+	if params := b.NodeupConfig.Sysctls; len(params) > 0 {
+		sysctls = append(sysctls,
+			"# Sysctls from instance group spec",
+			"")
+		var names []string
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			sysctls = append(sysctls, name+" = "+params[name])
+		}
+		sysctls = append(sysctls, "")
+	}
+
 	if params := b.Cluster.Spec.SysctlParameters; len(params) > 0 {
 		sysctls = append(sysctls,
 			"# Custom sysctl parameters from cluster spec",
@@ -174,11 +214,28 @@ func (b *SysctlBuilder) Build(c *fi.ModelBuilderContext) error {
 		}
 	}
 
+	// Load the kernel modules first, as some of the sysctls only exist once their module is loaded
+	var afterFiles []string
+	if modules := b.NodeupConfig.KernelModules; len(modules) > 0 {
+		var modprobe [][]string
+		for _, module := range modules {
+			modprobe = append(modprobe, []string{"modprobe", module})
+		}
+		c.AddTask(&nodetasks.File{
+			Path:            kernelModulesFilePath,
+			Contents:        fi.NewStringResource(strings.Join(modules, "\n") + "\n"),
+			Type:            nodetasks.FileType_File,
+			OnChangeExecute: modprobe,
+		})
+		afterFiles = append(afterFiles, kernelModulesFilePath)
+	}
+
 	c.AddTask(&nodetasks.File{
 		Path:            "/etc/sysctl.d/99-k8s-general.conf",
 		Contents:        fi.NewStringResource(strings.Join(sysctls, "\n")),
 		Type:            nodetasks.FileType_File,
 		OnChangeExecute: [][]string{{"sysctl", "--system"}},
+		AfterFiles:      afterFiles,
 	})
 
 	return nil
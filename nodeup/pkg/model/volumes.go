@@ -18,17 +18,52 @@ package model
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 
 	"k8s.io/klog/v2"
 	"k8s.io/mount-utils"
 	utilexec "k8s.io/utils/exec"
 )
 
+// stateVolume describes the services keeping their state on the path of a log or state volume
+type stateVolume struct {
+	// services are the services using the path, which are restarted when the volume is mounted over it
+	services []string
+	// stopServices stops the services while their state is copied onto the volume, so that the copy is consistent
+	// and no running pod loses the files written in the meantime
+	stopServices bool
+}
+
+// stateVolumes are the log and state volumes, which are mounted over directories that may already be in use
+var stateVolumes = map[string]stateVolume{
+	nodeup.LogVolumePath:        {services: []string{"systemd-journald.service", "rsyslog.service"}},
+	nodeup.ContainerdVolumePath: {services: []string{"kubelet.service", "containerd.service"}, stopServices: true},
+	nodeup.KubeletVolumePath:    {services: []string{"kubelet.service"}, stopServices: true},
+}
+
+// nvmeEBSDevicePrefix is the prefix of the /dev/disk/by-id links of the EBS volumes attached as NVMe devices,
+// followed by the volume ID without its dash
+const nvmeEBSDevicePrefix = "/dev/disk/by-id/nvme-Amazon_Elastic_Block_Store_"
+
 // VolumesBuilder maintains the volume mounting
 type VolumesBuilder struct {
 	*NodeupModelContext
+
+	// blockDeviceMappings caches the block device mappings of the instance
+	blockDeviceMappings []*ec2.InstanceBlockDeviceMapping
 }
 
 var _ fi.ModelBuilder = &VolumesBuilder{}
@@ -44,6 +79,19 @@ func (b *VolumesBuilder) Build(c *fi.ModelBuilderContext) error {
 
 	// @step: iterate the volume mounts and attempt to mount the devices
 	for _, x := range b.NodeupConfig.VolumeMounts {
+		// @step: resolve the device of the volume, which on instances with NVMe EBS volumes differs from the device name
+		device, err := b.volumeDevice(x.Device)
+		if err != nil {
+			return err
+		}
+		x.Device = device
+
+		// @step: the state volumes must be mounted at boot, before the services using them start
+		state, isStateVolume := stateVolumes[x.Path]
+		if isStateVolume {
+			b.addMountUnit(c, x)
+		}
+
 		// @check the directory exists, else create it
 		if err := b.EnsureDirectory(x.Path); err != nil {
 			return fmt.Errorf("failed to ensure the directory: %s, error: %s", x.Path, err)
@@ -54,14 +102,32 @@ func (b *VolumesBuilder) Build(c *fi.ModelBuilderContext) error {
 			Interface: mount.New(""),
 		}
 
+		// the mount table lists the device the /dev/disk/by-id link points to
+		if target, err := filepath.EvalSymlinks(x.Device); err == nil {
+			device = target
+		}
+
 		// @check if the device is already mounted
-		if found, err := b.IsMounted(m, x.Device, x.Path); err != nil {
+		if found, err := b.IsMounted(m, device, x.Path); err != nil {
 			return fmt.Errorf("Failed to check if device: %s is mounted, error: %s", x.Device, err)
 		} else if found {
 			klog.V(3).Infof("Skipping device: %s, path: %s as already mounted", x.Device, x.Path)
 			continue
 		}
 
+		// @step: the state volumes are mounted over directories already in use, which are copied onto the new volume first
+		var stopped []string
+		if isStateVolume {
+			if state.stopServices {
+				if stopped, err = b.stopServices(m, state.services); err != nil {
+					return err
+				}
+			}
+			if err := b.migrateVolume(m, x); err != nil {
+				return err
+			}
+		}
+
 		klog.Infof("Attempting to format and mount device: %s, path: %s", x.Device, x.Path)
 
 		if err := m.FormatAndMount(x.Device, x.Path, x.Filesystem, x.MountOptions); err != nil {
@@ -69,7 +135,178 @@ func (b *VolumesBuilder) Build(c *fi.ModelBuilderContext) error {
 
 			return err
 		}
+
+		if isStateVolume {
+			if err := b.restoreSELinuxLabels(m, x.Path); err != nil {
+				return err
+			}
+
+			// @step: the services keep using the files of the previous directory until they are restarted
+			if state.stopServices {
+				// in the reverse order, so that containerd starts before the kubelet
+				for i := len(stopped) - 1; i >= 0; i-- {
+					service := stopped[i]
+					if out, err := m.Exec.Command("systemctl", "start", service).CombinedOutput(); err != nil {
+						return fmt.Errorf("failed to start %s after mounting %s: %s: %v", service, x.Path, string(out), err)
+					}
+				}
+			} else {
+				for _, service := range state.services {
+					if out, err := m.Exec.Command("systemctl", "try-restart", service).CombinedOutput(); err != nil {
+						return fmt.Errorf("failed to restart %s after mounting %s: %s: %v", service, x.Path, string(out), err)
+					}
+				}
+			}
+		}
 	}
 
 	return nil
 }
+
+// volumeDevice returns the device to mount for the device name of a volume. The instance types attaching the EBS
+// volumes as NVMe devices do not create the device name of the block device mapping, so the volume is found by its
+// ID in /dev/disk/by-id instead; other volumes and clouds use the device name as it is.
+func (b *VolumesBuilder) volumeDevice(device string) (string, error) {
+	cloud, ok := b.Cloud.(awsup.AWSCloud)
+	if !ok || b.InstanceID == "" {
+		return device, nil
+	}
+
+	if b.blockDeviceMappings == nil {
+		response, err := cloud.EC2().DescribeInstances(&ec2.DescribeInstancesInput{
+			InstanceIds: []*string{aws.String(b.InstanceID)},
+		})
+		if err != nil {
+			return "", fmt.Errorf("error describing instance %q: %v", b.InstanceID, err)
+		}
+		b.blockDeviceMappings = []*ec2.InstanceBlockDeviceMapping{}
+		for _, reservation := range response.Reservations {
+			for _, instance := range reservation.Instances {
+				b.blockDeviceMappings = append(b.blockDeviceMappings, instance.BlockDeviceMappings...)
+			}
+		}
+	}
+
+	volumeID := findBlockDeviceVolumeID(b.blockDeviceMappings, device)
+	if volumeID == "" {
+		return device, nil
+	}
+	if _, err := os.Stat(device); err == nil {
+		// the device name exists, as on the instance types attaching the volumes as Xen devices
+		return device, nil
+	}
+
+	// udev may not have created the link of a volume attached at launch yet
+	path := nvmeEBSDevicePrefix + strings.ReplaceAll(volumeID, "-", "")
+	for i := 0; i < 30; i++ {
+		if _, err := os.Stat(path); err == nil {
+			klog.Infof("Found device: %s of volume %s at: %s", device, volumeID, path)
+			return path, nil
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return "", fmt.Errorf("device: %s of volume %s was not found at %s", device, volumeID, path)
+}
+
+// findBlockDeviceVolumeID returns the ID of the EBS volume mapped to the device name, or "" if there is none
+func findBlockDeviceVolumeID(mappings []*ec2.InstanceBlockDeviceMapping, device string) string {
+	for _, mapping := range mappings {
+		if aws.StringValue(mapping.DeviceName) == device && mapping.Ebs != nil {
+			return aws.StringValue(mapping.Ebs.VolumeId)
+		}
+	}
+	return ""
+}
+
+// stopServices stops the active services among the services and returns them, so that they can be started again
+func (b *VolumesBuilder) stopServices(m *mount.SafeFormatAndMount, services []string) ([]string, error) {
+	var stopped []string
+	for _, service := range services {
+		if err := m.Exec.Command("systemctl", "is-active", "--quiet", service).Run(); err != nil {
+			continue
+		}
+		klog.Infof("Stopping %s while its state is copied", service)
+		if out, err := m.Exec.Command("systemctl", "stop", service).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("failed to stop %s: %s: %v", service, string(out), err)
+		}
+		stopped = append(stopped, service)
+	}
+	return stopped, nil
+}
+
+// migrateVolume formats a new volume and copies the contents of its directory onto it, so that mounting the volume
+// hides none of them; volumes that are already formatted were migrated before and are left as they are
+func (b *VolumesBuilder) migrateVolume(m *mount.SafeFormatAndMount, x kops.VolumeMountSpec) error {
+	format, err := m.GetDiskFormat(x.Device)
+	if err != nil {
+		return fmt.Errorf("failed to check the format of device: %s, error: %s", x.Device, err)
+	}
+	if format != "" {
+		return nil
+	}
+
+	staging, err := ioutil.TempDir("", "kops-volume-")
+	if err != nil {
+		return fmt.Errorf("failed to create the staging directory of device: %s, error: %s", x.Device, err)
+	}
+	defer os.Remove(staging)
+
+	klog.Infof("Copying the contents of %s onto device: %s", x.Path, x.Device)
+
+	if err := m.FormatAndMount(x.Device, staging, x.Filesystem, x.MountOptions); err != nil {
+		return fmt.Errorf("failed to mount the device: %s on: %s, error: %s", x.Device, staging, err)
+	}
+	// cp -a keeps the ownership, permissions and extended attributes, including the SELinux labels
+	out, copyErr := m.Exec.Command("cp", "-a", x.Path+"/.", staging).CombinedOutput()
+	if err := m.Unmount(staging); err != nil {
+		return fmt.Errorf("failed to unmount the device: %s from: %s, error: %s", x.Device, staging, err)
+	}
+	if copyErr != nil {
+		return fmt.Errorf("failed to copy %s onto device: %s: %s: %v", x.Path, x.Device, string(out), copyErr)
+	}
+
+	return nil
+}
+
+// restoreSELinuxLabels labels the files of the path again, as the root of the new volume has no label yet
+func (b *VolumesBuilder) restoreSELinuxLabels(m *mount.SafeFormatAndMount, path string) error {
+	if _, err := m.Exec.LookPath("selinuxenabled"); err != nil {
+		return nil
+	}
+	if err := m.Exec.Command("selinuxenabled").Run(); err != nil {
+		return nil
+	}
+
+	if out, err := m.Exec.Command("restorecon", "-R", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restore the SELinux labels of %s: %s: %v", path, string(out), err)
+	}
+	return nil
+}
+
+// addMountUnit adds a systemd mount unit for the volume, so that it is mounted again at boot
+func (b *VolumesBuilder) addMountUnit(c *fi.ModelBuilderContext, x kops.VolumeMountSpec) {
+	// the paths of the state volumes need no escaping in the unit name
+	name := strings.ReplaceAll(strings.TrimPrefix(x.Path, "/"), "/", "-") + ".mount"
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Mount the volume of "+x.Path)
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Mount", "What", x.Device)
+	manifest.Set("Mount", "Where", x.Path)
+	manifest.Set("Mount", "Type", x.Filesystem)
+	if len(x.MountOptions) > 0 {
+		manifest.Set("Mount", "Options", strings.Join(x.MountOptions, ","))
+	}
+	manifest.Set("Install", "WantedBy", "local-fs.target")
+
+	c.AddTask(&nodetasks.File{
+		Path:     "/etc/systemd/system/" + name,
+		Contents: fi.NewStringResource(manifest.Render()),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0644"),
+		OnChangeExecute: [][]string{
+			{"systemctl", "daemon-reload"},
+			{"systemctl", "enable", name},
+		},
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/blang/semver/v4"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+func TestStateVolumes(t *testing.T) {
+	RunGoldenTest(t, "tests/golden/state-volumes", "state-volumes", func(nodeupModelContext *NodeupModelContext, target *fi.ModelBuilderContext) error {
+		nodeupModelContext.Distribution = distributions.DistributionUbuntu2004
+
+		// the volumes are not mounted, as mounting requires the devices
+		volumes := VolumesBuilder{NodeupModelContext: nodeupModelContext}
+		for _, x := range nodeupModelContext.NodeupConfig.VolumeMounts {
+			volumes.addMountUnit(target, x)
+		}
+
+		containerd := ContainerdBuilder{NodeupModelContext: nodeupModelContext}
+		target.AddTask(containerd.buildSystemdService(semver.MustParse("1.4.6")))
+
+		kubelet := KubeletBuilder{NodeupModelContext: nodeupModelContext}
+		target.AddTask(kubelet.buildSystemdService())
+
+		return nil
+	})
+}
+
+func TestFindBlockDeviceVolumeID(t *testing.T) {
+	mappings := []*ec2.InstanceBlockDeviceMapping{
+		{
+			DeviceName: aws.String("/dev/xvda"),
+			Ebs:        &ec2.EbsInstanceBlockDevice{VolumeId: aws.String("vol-0123456789abcdef0")},
+		},
+		{
+			DeviceName: aws.String("/dev/xvdl"),
+			Ebs:        &ec2.EbsInstanceBlockDevice{VolumeId: aws.String("vol-0fedcba9876543210")},
+		},
+	}
+
+	grid := []struct {
+		Device   string
+		Expected string
+	}{
+		{Device: "/dev/xvdl", Expected: "vol-0fedcba9876543210"},
+		{Device: "/dev/xvda", Expected: "vol-0123456789abcdef0"},
+		{Device: "/dev/xvdm", Expected: ""},
+	}
+	for _, g := range grid {
+		if actual := findBlockDeviceVolumeID(mappings, g.Device); actual != g.Expected {
+			t.Errorf("device %s: expected %q, got %q", g.Device, g.Expected, actual)
+		}
+	}
+}
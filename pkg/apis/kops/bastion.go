@@ -26,4 +26,14 @@ type BastionSpec struct {
 
 type BastionLoadBalancerSpec struct {
 	AdditionalSecurityGroups []string `json:"additionalSecurityGroups,omitempty"`
+	// Class is the class of the bastion's load balancer, Classic or Network.
+	// A Network load balancer also accepts SSH over IPv6 when the utility subnets have IPv6 CIDRs,
+	// but ignores idleTimeoutSeconds and cannot have additionalSecurityGroups.
+	// Default: Classic
+	Class LoadBalancerClass `json:"class,omitempty"`
+	// Subnets sets the addresses of a Network load balancer in the utility subnets of the bastions.
+	Subnets []LoadBalancerSubnetSpec `json:"subnets,omitempty"`
+	// AllocateElasticIPs allocates an Elastic IP for each subnet of a Network load balancer that does not
+	// specify an allocationId, so that the addresses of the load balancer are static. Default: false
+	AllocateElasticIPs *bool `json:"allocateElasticIPs,omitempty"`
 }
@@ -19,6 +19,7 @@ package kops
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/blang/semver/v4"
@@ -31,6 +32,12 @@ import (
 
 var DefaultChannelBase = "https://raw.githubusercontent.com/kubernetes/kops/master/channels/"
 
+// PinnedChannelBase is the location of the well-known channels as of a kops git ref, for channels pinned with <channel>@<ref>
+var PinnedChannelBase = "https://raw.githubusercontent.com/kubernetes/kops/%s/channels/"
+
+// channelRefRegex matches the git refs (tags, branches and commits) a channel can be pinned to
+var channelRefRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
 const (
 	DefaultChannel = "stable"
 )
@@ -52,6 +59,25 @@ type ChannelSpec struct {
 
 	// KubernetesVersions allows us to recommend/requires kubernetes versions
 	KubernetesVersions []KubernetesVersionSpec `json:"kubernetesVersions,omitempty"`
+
+	// Recommendations are settings recommended for the existing clusters following the channel
+	Recommendations []ChannelRecommendationSpec `json:"recommendations,omitempty"`
+}
+
+// ChannelRecommendationSpec is a change recommended for the clusters following a channel,
+// applied with kops update cluster --channel-recommendations
+type ChannelRecommendationSpec struct {
+	// Name identifies the recommendation
+	Name string `json:"name,omitempty"`
+
+	// Description explains why the change is recommended
+	Description string `json:"description,omitempty"`
+
+	// KubernetesVersion is the range of Kubernetes versions of the clusters the recommendation applies to, or all if empty
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Cluster holds the recommended settings, which are merged into the spec of the cluster
+	Cluster *ClusterSpec `json:"cluster,omitempty"`
 }
 
 type KopsVersionSpec struct {
@@ -99,11 +125,21 @@ func ResolveChannel(location string) (*url.URL, error) {
 	}
 
 	if !u.IsAbs() {
-		base, err := url.Parse(DefaultChannelBase)
+		baseLocation := DefaultChannelBase
+		if i := strings.LastIndex(location, "@"); i != -1 {
+			name, ref := location[:i], location[i+1:]
+			if name == "" || strings.Contains(name, "/") || !channelRefRegex.MatchString(ref) {
+				return nil, fmt.Errorf("invalid pinned channel %q, expected <channel>@<ref>, for example stable@v1.22.0", location)
+			}
+			baseLocation = fmt.Sprintf(PinnedChannelBase, ref)
+			u = &url.URL{Path: name}
+		}
+
+		base, err := url.Parse(baseLocation)
 		if err != nil {
-			return nil, fmt.Errorf("invalid base channel location: %q", DefaultChannelBase)
+			return nil, fmt.Errorf("invalid base channel location: %q", baseLocation)
 		}
-		klog.V(4).Infof("resolving %q against default channel location %q", location, DefaultChannelBase)
+		klog.V(4).Infof("resolving %q against default channel location %q", location, baseLocation)
 		u = base.ResolveReference(u)
 	}
 
@@ -275,6 +311,8 @@ const (
 	CloudProviderGCE       CloudProviderID = "gce"
 	CloudProviderOpenstack CloudProviderID = "openstack"
 	CloudProviderAzure     CloudProviderID = "azure"
+	CloudProviderOCI       CloudProviderID = "oci"
+	CloudProviderMetal     CloudProviderID = "metal"
 )
 
 // FindImage returns the image for the cloudprovider, or nil if none found
@@ -314,6 +352,29 @@ func (c *Channel) FindImage(provider CloudProviderID, kubernetesVersion semver.V
 	return matches[0]
 }
 
+// FindRecommendations returns the recommendations of the channel for clusters running a Kubernetes version
+func (c *Channel) FindRecommendations(kubernetesVersion semver.Version) []*ChannelRecommendationSpec {
+	var matches []*ChannelRecommendationSpec
+
+	for i := range c.Spec.Recommendations {
+		recommendation := &c.Spec.Recommendations[i]
+		if recommendation.KubernetesVersion != "" {
+			versionRange, err := semver.ParseRange(recommendation.KubernetesVersion)
+			if err != nil {
+				klog.Warningf("cannot parse KubernetesVersion=%q of recommendation %q", recommendation.KubernetesVersion, recommendation.Name)
+				continue
+			}
+
+			if !versionRange(kubernetesVersion) {
+				continue
+			}
+		}
+		matches = append(matches, recommendation)
+	}
+
+	return matches
+}
+
 // RecommendedKubernetesVersion returns the recommended kubernetes version for a version of kops
 // It is used by default when creating a new cluster, for example
 func RecommendedKubernetesVersion(c *Channel, kopsVersionString string) *semver.Version {
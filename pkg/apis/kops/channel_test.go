@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kops
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+)
+
+func Test_ResolveChannel(t *testing.T) {
+	grid := []struct {
+		Location    string
+		Expected    string
+		ExpectError bool
+	}{
+		{Location: "stable", Expected: "https://raw.githubusercontent.com/kubernetes/kops/master/channels/stable"},
+		{Location: "stable@v1.22.0", Expected: "https://raw.githubusercontent.com/kubernetes/kops/v1.22.0/channels/stable"},
+		{Location: "alpha@release-1.22", Expected: "https://raw.githubusercontent.com/kubernetes/kops/release-1.22/channels/alpha"},
+		{Location: "s3://my-bucket/channels/stable", Expected: "s3://my-bucket/channels/stable"},
+		{Location: "https://example.com/channels/stable@v1", Expected: "https://example.com/channels/stable@v1"},
+		{Location: "stable@", ExpectError: true},
+		{Location: "@v1.22.0", ExpectError: true},
+		{Location: "channels/stable@v1.22.0", ExpectError: true},
+		{Location: "stable@v1.22.0/../../evil", ExpectError: true},
+	}
+	for _, g := range grid {
+		u, err := ResolveChannel(g.Location)
+		if g.ExpectError {
+			if err == nil {
+				t.Errorf("expected error resolving %q, got %v", g.Location, u)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error resolving %q: %v", g.Location, err)
+			continue
+		}
+		if u.String() != g.Expected {
+			t.Errorf("expected %q to resolve to %q, got %q", g.Location, g.Expected, u.String())
+		}
+	}
+}
+
+func Test_FindRecommendations(t *testing.T) {
+	channel := &Channel{
+		Spec: ChannelSpec{
+			Recommendations: []ChannelRecommendationSpec{
+				{Name: "all"},
+				{Name: "1.21", KubernetesVersion: ">=1.21.0 <1.22.0"},
+				{Name: "1.22+", KubernetesVersion: ">=1.22.0"},
+				{Name: "invalid", KubernetesVersion: "not-a-range"},
+			},
+		},
+	}
+
+	grid := []struct {
+		Version  string
+		Expected []string
+	}{
+		{Version: "1.20.5", Expected: []string{"all"}},
+		{Version: "1.21.2", Expected: []string{"all", "1.21"}},
+		{Version: "1.23.0", Expected: []string{"all", "1.22+"}},
+	}
+	for _, g := range grid {
+		var actual []string
+		for _, recommendation := range channel.FindRecommendations(semver.MustParse(g.Version)) {
+			actual = append(actual, recommendation.Name)
+		}
+		if !reflect.DeepEqual(actual, g.Expected) {
+			t.Errorf("expected recommendations %v for %s, got %v", g.Expected, g.Version, actual)
+		}
+	}
+}
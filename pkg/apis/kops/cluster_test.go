@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestWarmPoolSpec_IsEnabled(t *testing.T) {
@@ -200,3 +201,80 @@ func assertResolvesValue(t *testing.T, name string, expected interface{}, warmPo
 		return assert.Equal(t, expected, value.Interface(), msg)
 	}
 }
+
+func TestInstanceGroupWithDefaults(t *testing.T) {
+	maxPods := int32(50)
+	cpuCFSQuota := false
+	cluster := &Cluster{}
+	cluster.Spec.InstanceGroupDefaults = &InstanceGroupSpec{
+		MachineType: "m5.large",
+		Image:       "ubuntu/ubuntu-20.04",
+		CloudLabels: map[string]string{"team": "platform", "env": "prod"},
+		Kubelet:     &KubeletConfigSpec{MaxPods: &maxPods},
+	}
+
+	g := &InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}, Spec: InstanceGroupSpec{Role: InstanceGroupRoleNode, Subnets: []string{"us-test-1a"}}}
+	g.Spec.MachineType = "c5.xlarge"
+	g.Spec.CloudLabels = map[string]string{"team": "data"}
+	g.Spec.Kubelet = &KubeletConfigSpec{CPUCFSQuota: &cpuCFSQuota}
+
+	actual := cluster.InstanceGroupWithDefaults(g)
+	if actual.Spec.MachineType != "c5.xlarge" {
+		t.Errorf("expected machine type of the instance group, got %q", actual.Spec.MachineType)
+	}
+	if actual.Spec.Image != "ubuntu/ubuntu-20.04" {
+		t.Errorf("expected image of the defaults, got %q", actual.Spec.Image)
+	}
+	if actual.Spec.CloudLabels["team"] != "data" || actual.Spec.CloudLabels["env"] != "prod" {
+		t.Errorf("expected merged cloud labels, got %v", actual.Spec.CloudLabels)
+	}
+	if actual.Spec.Kubelet.MaxPods == nil || *actual.Spec.Kubelet.MaxPods != 50 || actual.Spec.Kubelet.CPUCFSQuota == nil || *actual.Spec.Kubelet.CPUCFSQuota {
+		t.Errorf("expected merged kubelet config, got %+v", actual.Spec.Kubelet)
+	}
+	if actual.Spec.Role != InstanceGroupRoleNode || actual.ObjectMeta.Name != "nodes" {
+		t.Errorf("expected role and name of the instance group, got %q and %q", actual.Spec.Role, actual.ObjectMeta.Name)
+	}
+
+	// The inputs must not be modified
+	if g.Spec.Image != "" || len(g.Spec.CloudLabels) != 1 || g.Spec.Kubelet.MaxPods != nil {
+		t.Errorf("instance group was modified: %+v", g.Spec)
+	}
+	if len(cluster.Spec.InstanceGroupDefaults.CloudLabels) != 2 || cluster.Spec.InstanceGroupDefaults.Kubelet.CPUCFSQuota != nil {
+		t.Errorf("defaults were modified: %+v", cluster.Spec.InstanceGroupDefaults)
+	}
+}
+
+func TestRemoveInheritedInstanceGroupFields(t *testing.T) {
+	minSize, maxSize := int32(3), int32(5)
+	cluster := &Cluster{}
+	cluster.Spec.InstanceGroupDefaults = &InstanceGroupSpec{
+		MachineType: "m5.large",
+		Image:       "ubuntu/ubuntu-20.04",
+		MinSize:     &minSize,
+		CloudLabels: map[string]string{"team": "platform", "env": "prod"},
+	}
+
+	g := &InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}, Spec: InstanceGroupSpec{Role: InstanceGroupRoleNode, Subnets: []string{"us-test-1a"}}}
+	g.Spec.MachineType = "m5.large"
+	g.Spec.Image = "ubuntu/ubuntu-22.04"
+	g.Spec.MinSize = &minSize
+	g.Spec.MaxSize = &maxSize
+	g.Spec.CloudLabels = map[string]string{"team": "platform", "env": "dev"}
+
+	cluster.RemoveInheritedInstanceGroupFields(g)
+	if g.Spec.MachineType != "" || g.Spec.MinSize != nil {
+		t.Errorf("expected inherited fields to be cleared, got machine type %q and min size %v", g.Spec.MachineType, g.Spec.MinSize)
+	}
+	if g.Spec.Image != "ubuntu/ubuntu-22.04" || g.Spec.MaxSize == nil || g.Spec.Role != InstanceGroupRoleNode || len(g.Spec.Subnets) != 1 {
+		t.Errorf("expected fields that are not inherited to be kept, got %+v", g.Spec)
+	}
+	if len(g.Spec.CloudLabels) != 1 || g.Spec.CloudLabels["env"] != "dev" {
+		t.Errorf("expected only the cloud labels that are not inherited to be kept, got %v", g.Spec.CloudLabels)
+	}
+
+	g.Spec.CloudLabels = map[string]string{"team": "platform"}
+	cluster.RemoveInheritedInstanceGroupFields(g)
+	if g.Spec.CloudLabels != nil {
+		t.Errorf("expected cloud labels to be cleared, got %v", g.Spec.CloudLabels)
+	}
+}
@@ -20,16 +20,26 @@ package kops
 type ContainerdConfig struct {
 	// Address of containerd's GRPC server (default "/run/containerd/containerd.sock").
 	Address *string `json:"address,omitempty" flag:"address"`
+	// CgroupDriver is the cgroup driver of the runc runtime, either "systemd" or "cgroupfs".
+	// It must match the cgroup driver of the kubelet. Defaults to "systemd" from Kubernetes 1.20.
+	CgroupDriver *string `json:"cgroupDriver,omitempty"`
 	// ConfigOverride is the complete containerd config file provided by the user.
 	ConfigOverride *string `json:"configOverride,omitempty"`
 	// LogLevel controls the logging details [trace, debug, info, warn, error, fatal, panic] (default "info").
 	LogLevel *string `json:"logLevel,omitempty" flag:"log-level"`
+	// NRI configures the Node Resource Interface, which lets plugins adjust containers as they are created.
+	// Requires containerd 1.7 or later.
+	NRI *ContainerdNRIConfig `json:"nri,omitempty"`
 	// Packages overrides the URL and hash for the packages.
 	Packages *PackagesConfig `json:"packages,omitempty"`
+	// Registries configures how images are pulled from each registry, keyed by registry host, e.g. "docker.io".
+	Registries map[string]ContainerdRegistryConfig `json:"registries,omitempty"`
 	// RegistryMirrors is list of image registries
 	RegistryMirrors map[string][]string `json:"registryMirrors,omitempty"`
 	// Root directory for persistent data (default "/var/lib/containerd").
 	Root *string `json:"root,omitempty" flag:"root"`
+	// SandboxImage is the image of the pause container that holds the namespaces of each pod.
+	SandboxImage *string `json:"sandboxImage,omitempty"`
 	// SkipInstall prevents kOps from installing and modifying containerd in any way (default "false").
 	SkipInstall bool `json:"skipInstall,omitempty"`
 	// State directory for execution state files (default "/run/containerd").
@@ -37,3 +47,19 @@ type ContainerdConfig struct {
 	// Version used to pick the containerd package.
 	Version *string `json:"version,omitempty"`
 }
+
+// ContainerdRegistryConfig configures how containerd pulls images from a registry.
+type ContainerdRegistryConfig struct {
+	// Mirrors are the endpoints tried, in order, before the registry itself, e.g. "https://mirror.example.com".
+	Mirrors []string `json:"mirrors,omitempty"`
+}
+
+// ContainerdNRIConfig configures the Node Resource Interface of containerd.
+type ContainerdNRIConfig struct {
+	// Enabled enables NRI.
+	Enabled bool `json:"enabled,omitempty"`
+	// PluginPath is the directory of the NRI plugins started by containerd (default "/opt/nri/plugins").
+	PluginPath *string `json:"pluginPath,omitempty"`
+	// PluginConfigPath is the directory of the configuration of the NRI plugins (default "/etc/nri/conf.d").
+	PluginConfigPath *string `json:"pluginConfigPath,omitempty"`
+}
@@ -23,6 +23,12 @@ const (
 	// AnnotationValueManagementImported is the annotation value that indicates a cluster was imported, typically as part of an upgrade
 	AnnotationValueManagementImported = "imported"
 
+	// AnnotationNameCloneSource is the annotation that records the cluster a cluster was cloned from
+	AnnotationNameCloneSource = "kops.kubernetes.io/clone-source"
+
+	// AnnotationNameCloneZoneMap is the annotation that records the zone mapping used when cloning a cluster
+	AnnotationNameCloneZoneMap = "kops.kubernetes.io/clone-zone-map"
+
 	// UpdatePolicyAutomatic is a value for ClusterSpec.UpdatePolicy and InstanceGroup.UpdatePolicy indicating that upgrades are performed automatically
 	UpdatePolicyAutomatic = "automatic"
 
@@ -39,3 +39,66 @@ func UseCiliumEtcd(cluster *kops.Cluster) bool {
 
 	return false
 }
+
+// UseNodeRemediation is true if kops-controller should remediate nodes reporting problem conditions.
+func UseNodeRemediation(cluster *kops.Cluster) bool {
+	npd := cluster.Spec.NodeProblemDetector
+	if npd == nil || npd.Enabled == nil || !*npd.Enabled || npd.Remediation == nil {
+		return false
+	}
+	return npd.Remediation.Enabled != nil && *npd.Remediation.Enabled
+}
+
+// UseVerticalPodAutoscaler is true if the vertical pod autoscaler addon is enabled,
+// in which case kops-controller issues the certificate of its admission webhook.
+func UseVerticalPodAutoscaler(cluster *kops.Cluster) bool {
+	vpa := cluster.Spec.VerticalPodAutoscaler
+	return vpa != nil && vpa.Enabled != nil && *vpa.Enabled
+}
+
+// UseSessionManager is true if the instances should be accessible with AWS Systems Manager Session Manager.
+func UseSessionManager(cluster *kops.Cluster) bool {
+	sm := cluster.Spec.SessionManager
+	return sm != nil && sm.Enabled != nil && *sm.Enabled
+}
+
+// UseKubeletServerTLSBootstrap is true if the kubelets of the cluster or of any of the instance groups
+// request their serving certificates from the certificates API, in which case kops-controller approves them.
+func UseKubeletServerTLSBootstrap(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) bool {
+	if cluster.Spec.Kubelet != nil && cluster.Spec.Kubelet.ServerTLSBootstrap != nil && *cluster.Spec.Kubelet.ServerTLSBootstrap {
+		return true
+	}
+	if cluster.Spec.MasterKubelet != nil && cluster.Spec.MasterKubelet.ServerTLSBootstrap != nil && *cluster.Spec.MasterKubelet.ServerTLSBootstrap {
+		return true
+	}
+	for _, ig := range instanceGroups {
+		if ig.Spec.Kubelet != nil && ig.Spec.Kubelet.ServerTLSBootstrap != nil && *ig.Spec.Kubelet.ServerTLSBootstrap {
+			return true
+		}
+	}
+	return false
+}
+
+// ScaleInProtectedInstanceGroups returns the names of the instance groups whose instances are protected from scale in
+// until their node is cordoned, in which case kops-controller removes the protection.
+func ScaleInProtectedInstanceGroups(instanceGroups []*kops.InstanceGroup) []string {
+	var names []string
+	for _, ig := range instanceGroups {
+		if ig.Spec.ScaleInProtection == kops.ScaleInProtectionUntilCordoned {
+			names = append(names, ig.ObjectMeta.Name)
+		}
+	}
+	return names
+}
+
+// UseEgressGateways is true if the cluster routes the traffic of selected namespaces through egress gateways,
+// whose Elastic IPs kops-controller associates with the gateway nodes.
+func UseEgressGateways(cluster *kops.Cluster) bool {
+	return len(cluster.Spec.EgressGateways) != 0
+}
+
+// UseNodeLabelReconciliation is true if kops-controller applies the node labels and taints of the instance group
+// to its nodes, instead of the instances being replaced when they change. It applies to instance groups with the Node role.
+func UseNodeLabelReconciliation(cluster *kops.Cluster, ig *kops.InstanceGroup) bool {
+	return cluster.Spec.NodeLabelReconciliation != nil && ig.Spec.Role == kops.InstanceGroupRoleNode
+}
@@ -21,4 +21,12 @@ type NTPConfig struct {
 	// Managed controls if the NTP configuration is managed by kOps.
 	// The NTP configuration task is skipped if this is set to false.
 	Managed *bool `json:"managed,omitempty"`
+	// Servers are the NTP servers or pools used to synchronize the clock.
+	// Defaults to the link-local time service of the cloud provider, when it has one.
+	Servers []string `json:"servers,omitempty"`
+	// WaitForSync delays the start of the kubelet until the clock is synchronized.
+	WaitForSync *bool `json:"waitForSync,omitempty"`
+	// MetricsTextfileDirectory is the directory where the clock synchronization metrics are written every minute,
+	// for the textfile collector of the Prometheus node exporter.
+	MetricsTextfileDirectory string `json:"metricsTextfileDirectory,omitempty"`
 }
@@ -77,6 +77,9 @@ type ClusterSpec struct {
 	// or otherwise allocated to k8s. This is a real CIDR, not the internal k8s network
 	// On AWS, it maps to any additional CIDRs added to a VPC.
 	AdditionalNetworkCIDRs []string `json:"additionalNetworkCIDRs,omitempty"`
+	// NetworkCIDRAllocation records the allocation of the NetworkCIDR from an external IPAM,
+	// which is released when the cluster is deleted.
+	NetworkCIDRAllocation *NetworkCIDRAllocationSpec `json:"networkCIDRAllocation,omitempty"`
 	// NetworkID is an identifier of a network, if we want to reuse/share an existing network (e.g. an AWS VPC)
 	NetworkID string `json:"networkID,omitempty"`
 	// Topology defines the type of network topology to use on the cluster - default public
@@ -143,6 +146,10 @@ type ClusterSpec struct {
 	FileAssets []FileAssetSpec `json:"fileAssets,omitempty"`
 	// EtcdClusters stores the configuration for each cluster
 	EtcdClusters []EtcdClusterSpec `json:"etcdClusters,omitempty"`
+	// FeatureGates are the Kubernetes feature gates to enable or disable on the API server, controller manager,
+	// scheduler, cloud controller manager, kube-proxy and kubelets. A gate set in the deprecated featureGates
+	// of a component takes precedence for that component.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 	// Component configurations
 	Containerd                     *ContainerdConfig             `json:"containerd,omitempty"`
 	Docker                         *DockerConfig                 `json:"docker,omitempty"`
@@ -181,8 +188,12 @@ type ClusterSpec struct {
 	NodeAuthorization *NodeAuthorizationSpec `json:"nodeAuthorization,omitempty"`
 	// CloudLabels defines additional tags or labels on cloud provider resources
 	CloudLabels map[string]string `json:"cloudLabels,omitempty"`
+	// CloudLabelPolicies defines additional tags on the cloud provider resources of some types or in some subnets
+	CloudLabelPolicies []CloudLabelPolicy `json:"cloudLabelPolicies,omitempty"`
 	// Hooks for custom actions e.g. on first installation
 	Hooks []HookSpec `json:"hooks,omitempty"`
+	// NodePlugins are customizations nodeup applies to nodes at defined stages of their lifecycle
+	NodePlugins []NodePluginSpec `json:"nodePlugins,omitempty"`
 	// Alternative locations for files and containers
 	Assets *Assets `json:"assets,omitempty"`
 	// IAM field adds control over the IAM security policies applied to resources
@@ -204,6 +215,10 @@ type ClusterSpec struct {
 	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
 	// ClusterAutoscaler defines the cluaster autoscaler configuration.
 	ClusterAutoscaler *ClusterAutoscalerConfig `json:"clusterAutoscaler,omitempty"`
+	// Descheduler defines the descheduler configuration.
+	Descheduler *DeschedulerConfig `json:"descheduler,omitempty"`
+	// VerticalPodAutoscaler defines the vertical pod autoscaler configuration.
+	VerticalPodAutoscaler *VerticalPodAutoscalerConfig `json:"verticalPodAutoscaler,omitempty"`
 	// WarmPool defines the default warm pool settings for instance groups (AWS only).
 	WarmPool *WarmPoolSpec `json:"warmPool,omitempty"`
 
@@ -212,6 +227,213 @@ type ClusterSpec struct {
 
 	// SnapshotController defines the CSI Snapshot Controller configuration.
 	SnapshotController *SnapshotControllerConfig `json:"snapshotController,omitempty"`
+
+	// ContinuousValidation configures kops-controller to periodically validate the cluster.
+	ContinuousValidation *ContinuousValidationSpec `json:"continuousValidation,omitempty"`
+
+	// OSPatching configures kops-controller to periodically update the operating system packages of the nodes.
+	OSPatching *OSPatchingSpec `json:"osPatching,omitempty"`
+
+	// NodeLabelReconciliation configures kops-controller to apply changes to the node labels and taints
+	// of the instance groups to their nodes, without replacing the instances.
+	NodeLabelReconciliation *NodeLabelReconciliationSpec `json:"nodeLabelReconciliation,omitempty"`
+	// Hibernation scales all the instance groups to zero, keeping the etcd volumes of the control plane.
+	// It is set by kops hibernate cluster and cleared by kops resume cluster.
+	Hibernation *HibernationSpec `json:"hibernation,omitempty"`
+	// MaintenanceWindow restricts disruptive operations, such as rolling updates and the replacement
+	// of nodes by kops-controller, to a recurring window.
+	MaintenanceWindow *MaintenanceWindowSpec `json:"maintenanceWindow,omitempty"`
+	// InstanceGroupDefaults are inherited by all instance groups of the cluster, for each field the instance
+	// group does not set itself. Maps are merged, with the values of the instance group taking precedence.
+	// Role cannot be set.
+	InstanceGroupDefaults *InstanceGroupSpec `json:"instanceGroupDefaults,omitempty"`
+
+	// SessionManager configures access to the instances with AWS Systems Manager Session Manager,
+	// as an alternative to a bastion.
+	SessionManager *SessionManagerSpec `json:"sessionManager,omitempty"`
+
+	// SSH configures SSH access to the instances of the cluster, beyond the primary SSH public key.
+	SSH *SSHSpec `json:"ssh,omitempty"`
+
+	// Events configures the publication of events about changes to the cluster, such as updates and rolling updates.
+	Events *EventsSpec `json:"events,omitempty"`
+
+	// SecurityProfile is a hardening profile applied to the cluster. With "cis", the flags of the
+	// control plane components and the kubelet, and the permissions of the files written by nodeup,
+	// default to the values recommended by the CIS Kubernetes Benchmark.
+	SecurityProfile string `json:"securityProfile,omitempty"`
+
+	// PodSecurityAdmission configures the default Pod Security Standards enforced by the PodSecurity
+	// admission plugin of the API server, for namespaces that do not set their own levels.
+	PodSecurityAdmission *PodSecurityAdmissionSpec `json:"podSecurityAdmission,omitempty"`
+
+	// HostFIPSMode requires the instances of the cluster to run with their kernel in FIPS 140-2 mode,
+	// sets the crypto policy of the OS, and restricts the TLS of the API server and the kubelet
+	// to FIPS-approved cipher suites. The Kubernetes components are not FIPS-validated builds.
+	HostFIPSMode *bool `json:"hostFIPSMode,omitempty"`
+
+	// EgressGateways route the traffic of the pods of selected namespaces through gateway nodes,
+	// so that it leaves the cluster from static Elastic IPs.
+	EgressGateways []EgressGatewaySpec `json:"egressGateways,omitempty"`
+}
+
+// PodSecurityAdmissionSpec configures the PodSecurity admission plugin of the API server.
+// The levels are one of privileged, baseline or restricted. They apply to the namespaces
+// that do not set them with the pod-security.kubernetes.io labels.
+type PodSecurityAdmissionSpec struct {
+	// Enforce is the level of the policy whose violations cause pods to be rejected.
+	// Default: privileged
+	Enforce string `json:"enforce,omitempty"`
+	// Audit is the level of the policy whose violations are recorded in the audit log.
+	// Default: privileged
+	Audit string `json:"audit,omitempty"`
+	// Warn is the level of the policy whose violations are returned as warnings to the user.
+	// Default: privileged
+	Warn string `json:"warn,omitempty"`
+	// Version is the version of the Pod Security Standards of the levels, "latest" or a Kubernetes minor version such as "v1.22".
+	// Default: latest
+	Version string `json:"version,omitempty"`
+	// ExemptNamespaces are the namespaces whose pods are not checked.
+	// Default: kube-system
+	ExemptNamespaces []string `json:"exemptNamespaces,omitempty"`
+	// ExemptUsernames are the users whose pods are not checked.
+	ExemptUsernames []string `json:"exemptUsernames,omitempty"`
+	// ExemptRuntimeClasses are the runtime classes whose pods are not checked.
+	ExemptRuntimeClasses []string `json:"exemptRuntimeClasses,omitempty"`
+}
+
+// EventsSpec configures where kops publishes the events about changes to the cluster (AWS only).
+type EventsSpec struct {
+	// SNSTopicARN is the ARN of an SNS topic the events are published to.
+	SNSTopicARN string `json:"snsTopicARN,omitempty"`
+	// EventBusName is the name or ARN of an EventBridge event bus the events are published to.
+	EventBusName string `json:"eventBusName,omitempty"`
+}
+
+// ContinuousValidationSpec configures periodic validation of the cluster by kops-controller.
+// The results are exported as Prometheus metrics and reported as Kubernetes Events.
+type ContinuousValidationSpec struct {
+	// Interval is the time between validations.
+	// Default: 5m
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Checks are the names of the additional validation checks to run, or "all".
+	Checks []string `json:"checks,omitempty"`
+}
+
+// SessionManagerSpec configures AWS Systems Manager Session Manager access to the instances of the cluster.
+type SessionManagerSpec struct {
+	// Enabled grants the instances the permissions needed by the SSM agent, and starts the agent,
+	// installing it from AgentPackages on images that do not include it.
+	// Default: false
+	Enabled *bool `json:"enabled,omitempty"`
+	// AgentPackages are the SSM agent packages, of pinned versions, installed on images that do not include the agent.
+	// Instances without a package for their architecture and package system must have the agent in their image.
+	AgentPackages []SessionManagerAgentPackage `json:"agentPackages,omitempty"`
+}
+
+// SessionManagerAgentPackage is a package of the SSM agent.
+type SessionManagerAgentPackage struct {
+	// Architecture is the architecture of the package: amd64 or arm64.
+	Architecture string `json:"architecture,omitempty"`
+	// URL is the location of the .deb or .rpm package, for example in the regional distribution bucket of AWS.
+	URL string `json:"url,omitempty"`
+	// Hash is the SHA256 hash of the package.
+	Hash string `json:"hash,omitempty"`
+}
+
+// SSHSpec configures SSH access to the instances of the cluster.
+// Additional "admin" SSH public keys, and the public keys of the "sshca" SSH certificate authorities,
+// are distributed to the instances by nodeup.
+type SSHSpec struct {
+	// User is the user that additional SSH public keys are authorized for.
+	// Default: the default user of the image
+	User string `json:"user,omitempty"`
+	// InstanceConnect installs EC2 Instance Connect on the instances, on images that provide it.
+	// Default: false
+	InstanceConnect *bool `json:"instanceConnect,omitempty"`
+	// PublicKeys are named SSH public keys that nodeup authorizes on the instances, in addition to the "admin" SSH public keys.
+	// Instance groups can select which of them are authorized on their instances.
+	PublicKeys []SSHPublicKeySpec `json:"publicKeys,omitempty"`
+}
+
+// SSHPublicKeySpec is a named SSH public key.
+type SSHPublicKeySpec struct {
+	// Name is the name that instance groups select the key by.
+	Name string `json:"name"`
+	// PublicKey is the SSH public key, in the authorized_keys format.
+	PublicKey string `json:"publicKey"`
+}
+
+// NetworkCIDRAllocationSpec records the allocation of the network CIDR of the cluster from an external IPAM.
+type NetworkCIDRAllocationSpec struct {
+	// Provider is the IPAM the network CIDR is allocated from: AWS (VPC IPAM), Infoblox or Netbox.
+	Provider string `json:"provider"`
+	// Endpoint is the URL of the Infoblox WAPI or of the Netbox API.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Pool is what the network CIDR is allocated from: the ID of the AWS VPC IPAM pool,
+	// the Infoblox network container, or the ID of the Netbox prefix.
+	Pool string `json:"pool"`
+	// AllocationID identifies the allocation in the IPAM.
+	AllocationID string `json:"allocationID"`
+}
+
+// EgressGatewaySpec routes the traffic that pods in selected namespaces send to the destination CIDRs
+// through a gateway node. kops allocates an Elastic IP for the gateway, and kops-controller associates it
+// with the gateway node, so that it is the source address of the traffic seen outside of the cluster.
+type EgressGatewaySpec struct {
+	// Name identifies the egress gateway and its Elastic IP.
+	Name string `json:"name"`
+	// InstanceGroup is the instance group of the gateway node. It must have a single instance,
+	// in public subnets.
+	InstanceGroup string `json:"instanceGroup"`
+	// Namespaces are the namespaces whose pods send their traffic through the gateway.
+	Namespaces []string `json:"namespaces"`
+	// DestinationCIDRs are the destinations of the traffic sent through the gateway.
+	// Default: 0.0.0.0/0
+	DestinationCIDRs []string `json:"destinationCIDRs,omitempty"`
+}
+
+// NodeLabelReconciliationSpec configures the reconciliation of the node labels and taints of the instance groups
+// by kops-controller. The node labels and taints of the instance groups with the Node role are left out of the
+// configuration of their instances, which register with a pending taint until kops-controller has applied them.
+type NodeLabelReconciliationSpec struct {
+	// Interval is the time between reconciliations of each node.
+	// Default: 1m
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// HibernationSpec configures the hibernation of a cluster (AWS only).
+type HibernationSpec struct {
+	// NATGateways deletes the NAT gateways created by kOps while the cluster is hibernated.
+	// Their Elastic IPs are kept, so that the egress addresses of the cluster are preserved.
+	NATGateways bool `json:"natGateways,omitempty"`
+}
+
+// MaintenanceWindowSpec is a recurring window during which disruptive operations are allowed.
+type MaintenanceWindowSpec struct {
+	// Schedule is the cron schedule on which the window opens, in UTC unless prefixed with a CRON_TZ= time zone.
+	// For example "0 22 * * SAT".
+	Schedule string `json:"schedule,omitempty"`
+	// Duration is how long the window stays open.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// OSPatchingSpec configures the periodic update of the operating system packages of the nodes by kops-controller.
+// Nodes are patched one at a time: each node is drained, its packages are updated, and it is rebooted and uncordoned.
+type OSPatchingSpec struct {
+	// Interval is the time between updates of the packages of each node.
+	// Default: 168h
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Method is how commands are run on the nodes: Pod runs a privileged pod on the node,
+	// SSM uses AWS Systems Manager Run Command.
+	// Default: Pod
+	Method string `json:"method,omitempty"`
+	// Reboot reboots the nodes after their packages are updated.
+	// Default: true
+	Reboot *bool `json:"reboot,omitempty"`
+	// InstanceGroups are the names of the instance groups to patch.
+	// Default: all instance groups except the control plane
+	InstanceGroups []string `json:"instanceGroups,omitempty"`
 }
 
 // ServiceAccountIssuerDiscoveryConfig configures an OIDC Issuer.
@@ -294,8 +516,34 @@ type Assets struct {
 	FileRepository *string `json:"fileRepository,omitempty"`
 	// ContainerProxy is a url for a pull-through proxy of a docker registry
 	ContainerProxy *string `json:"containerProxy,omitempty"`
+	// ImageVerification is the trust policy nodeup applies to system container images before they are run.
+	ImageVerification *ImageVerificationSpec `json:"imageVerification,omitempty"`
+}
+
+// ImageVerificationSpec is a trust policy for the system container images used by a cluster.
+// Nodeup verifies each image, then pulls it by digest so that the verified image is the one that runs.
+type ImageVerificationSpec struct {
+	// Action is the action taken when an image fails verification.
+	// Supported values: Enforce, Warn.
+	// Default: Enforce
+	Action ImageVerificationAction `json:"action,omitempty"`
+	// Digests pins images to digests, keyed by the canonical image name, e.g. "k8s.gcr.io/kube-proxy:v1.21.0": "sha256:...".
+	Digests map[string]string `json:"digests,omitempty"`
+	// CosignPublicKeys are PEM encoded ECDSA public keys. When set, every image must carry a cosign signature
+	// made with one of the keys.
+	CosignPublicKeys []string `json:"cosignPublicKeys,omitempty"`
 }
 
+// ImageVerificationAction is the action taken when an image fails verification.
+type ImageVerificationAction string
+
+const (
+	// ImageVerificationActionEnforce fails nodeup, so that the node does not start an unverified image.
+	ImageVerificationActionEnforce ImageVerificationAction = "Enforce"
+	// ImageVerificationActionWarn logs the failure and continues.
+	ImageVerificationActionWarn ImageVerificationAction = "Warn"
+)
+
 // IAMSpec adds control over the IAM security policies applied to resources
 type IAMSpec struct {
 	Legacy                 bool    `json:"legacy"`
@@ -327,6 +575,51 @@ type HookSpec struct {
 	UseRawManifest bool `json:"useRawManifest,omitempty"`
 }
 
+// NodePluginSpec is a customization nodeup applies to nodes at a defined stage of their lifecycle.
+type NodePluginSpec struct {
+	// Name identifies the plugin. An instance group plugin overrides the cluster plugin with the same name.
+	Name string `json:"name,omitempty"`
+	// Disabled indicates that the plugin is not run, e.g. to switch off a cluster plugin for an instance group.
+	Disabled bool `json:"disabled,omitempty"`
+	// Stage is the stage of the node lifecycle at which the plugin runs.
+	// Supported values: PreInstall, PostKubelet, PostJoin.
+	Stage NodePluginStage `json:"stage,omitempty"`
+	// Roles is an optional list of roles the plugin runs on, defaults to all
+	Roles []InstanceGroupRole `json:"roles,omitempty"`
+	// Artifacts are files fetched before the plugin runs, into the directory given by $KOPS_PLUGIN_DIR.
+	Artifacts []NodePluginArtifact `json:"artifacts,omitempty"`
+	// Command is the command run on the host, e.g. ["/bin/sh", "-c", "$KOPS_PLUGIN_DIR/install.sh"].
+	Command []string `json:"command,omitempty"`
+	// Environment is a map of environment variables added to the command
+	Environment map[string]string `json:"environment,omitempty"`
+	// Timeout is the maximum duration of each run of the command.
+	// Default: 5m
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// Retries is the number of times the command is retried when it fails or times out.
+	// Default: 0
+	Retries *int32 `json:"retries,omitempty"`
+}
+
+// NodePluginArtifact is a file fetched for a nodeup plugin.
+type NodePluginArtifact struct {
+	// Source is the URL of the file. It is remapped to assets.fileRepository when that is set.
+	Source string `json:"source,omitempty"`
+	// Hash is the sha256 hash of the file.
+	Hash string `json:"hash,omitempty"`
+}
+
+// NodePluginStage is a stage of the node lifecycle at which nodeup plugins run.
+type NodePluginStage string
+
+const (
+	// NodePluginStagePreInstall runs the plugin before nodeup installs packages and the container runtime.
+	NodePluginStagePreInstall NodePluginStage = "PreInstall"
+	// NodePluginStagePostKubelet runs the plugin once nodeup has started the kubelet.
+	NodePluginStagePostKubelet NodePluginStage = "PostKubelet"
+	// NodePluginStagePostJoin runs the plugin once the node has registered with the cluster.
+	NodePluginStagePostJoin NodePluginStage = "PostJoin"
+)
+
 // ExecContainerAction defines an hood action
 type ExecContainerAction struct {
 	// Image is the docker image
@@ -340,6 +633,8 @@ type ExecContainerAction struct {
 type AuthenticationSpec struct {
 	Kopeio *KopeioAuthenticationSpec `json:"kopeio,omitempty"`
 	Aws    *AwsAuthenticationSpec    `json:"aws,omitempty"`
+	// OIDC configures the API server to accept OpenID Connect tokens, optionally issued by a managed Dex.
+	OIDC *OIDCAuthenticationSpec `json:"oidc,omitempty"`
 }
 
 func (s *AuthenticationSpec) IsEmpty() bool {
@@ -366,6 +661,39 @@ type AwsAuthenticationSpec struct {
 	CPULimit *resource.Quantity `json:"cpuLimit,omitempty"`
 }
 
+// OIDCAuthenticationSpec configures OpenID Connect authentication for users of the API server.
+type OIDCAuthenticationSpec struct {
+	// IssuerURL is the HTTPS URL of the OpenID issuer. When Dex is enabled this is the URL Dex is reachable at.
+	IssuerURL string `json:"issuerURL,omitempty"`
+	// ClientID is the client ID that tokens must be issued for. Default kubernetes
+	ClientID string `json:"clientID,omitempty"`
+	// UsernameClaim is the claim to use as the user name. Default sub
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	// UsernamePrefix is prepended to user names to prevent clashes with other authentication strategies. Default oidc:
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+	// GroupsClaim is the claim to use as the user's groups. Default groups
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// GroupsPrefix is prepended to group names to prevent clashes with other authentication strategies. Default oidc:
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+	// RequiredClaims are claims that must be present in the token with the given value.
+	RequiredClaims map[string]string `json:"requiredClaims,omitempty"`
+	// Dex runs a Dex identity provider in the cluster to issue the tokens.
+	Dex *DexSpec `json:"dex,omitempty"`
+}
+
+// DexSpec configures the managed Dex identity provider.
+type DexSpec struct {
+	// Image is the Dex container image to use.
+	Image string `json:"image,omitempty"`
+	// Replicas is the number of Dex replicas to run. Default 2
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Connectors is the YAML list of Dex connectors used to authenticate users against an upstream identity provider.
+	// Connector secrets should be referenced as $VARIABLES set in the dex-connectors Secret in kube-system.
+	Connectors string `json:"connectors,omitempty"`
+	// RedirectURIs are the additional redirect URIs allowed for the kubernetes client.
+	RedirectURIs []string `json:"redirectURIs,omitempty"`
+}
+
 type AuthorizationSpec struct {
 	AlwaysAllow *AlwaysAllowAuthorizationSpec `json:"alwaysAllow,omitempty"`
 	RBAC        *RBACAuthorizationSpec        `json:"rbac,omitempty"`
@@ -376,6 +704,22 @@ func (s *AuthorizationSpec) IsEmpty() bool {
 }
 
 type RBACAuthorizationSpec struct {
+	// Bindings grant roles to users and groups, so that they have access as soon as the cluster is created.
+	Bindings []RBACBindingSpec `json:"bindings,omitempty"`
+}
+
+// RBACBindingSpec binds a cluster role to users and groups.
+type RBACBindingSpec struct {
+	// Name identifies the binding, which is created as kops:<name>.
+	Name string `json:"name,omitempty"`
+	// ClusterRole is the name of the cluster role to grant.
+	ClusterRole string `json:"clusterRole,omitempty"`
+	// Namespace limits the binding to a namespace by creating a RoleBinding. Default grants the role cluster-wide
+	Namespace string `json:"namespace,omitempty"`
+	// Groups are the groups granted the role.
+	Groups []string `json:"groups,omitempty"`
+	// Users are the users granted the role.
+	Users []string `json:"users,omitempty"`
 }
 
 type AlwaysAllowAuthorizationSpec struct {
@@ -417,6 +761,19 @@ var SupportedLoadBalancerClasses = []string{
 	string(LoadBalancerClassNetwork),
 }
 
+// CloudLabelPolicy defines additional tags on the cloud provider resources matching all of its selectors
+type CloudLabelPolicy struct {
+	// Labels are the tags to apply to the matching resources
+	Labels map[string]string `json:"labels,omitempty"`
+	// ResourceTypes restricts the policy to the resources of these types
+	ResourceTypes []CloudResourceType `json:"resourceTypes,omitempty"`
+	// Subnets restricts the policy to these subnets and to the resources of the instance groups placed only in them
+	Subnets []string `json:"subnets,omitempty"`
+}
+
+// CloudResourceType is a type of cloud provider resource that can be selected by a CloudLabelPolicy
+type CloudResourceType string
+
 // LoadBalancerSubnetSpec provides configuration for subnets used for a load balancer
 type LoadBalancerSubnetSpec struct {
 	// Name specifies the name of the cluster subnet
@@ -449,6 +806,32 @@ type LoadBalancerAccessSpec struct {
 	CrossZoneLoadBalancing *bool `json:"crossZoneLoadBalancing,omitempty"`
 	// Subnets allows you to specify the subnets that must be used for the load balancer
 	Subnets []LoadBalancerSubnetSpec `json:"subnets,omitempty"`
+	// HealthCheck configures the health checks of the target groups of a Network Load Balancer
+	HealthCheck *LoadBalancerHealthCheckSpec `json:"healthCheck,omitempty"`
+	// DeregistrationDelaySeconds is the time a Network Load Balancer waits before deregistering a target,
+	// such as a control plane instance that is being replaced, from 0 to 3600 seconds. Default: 300
+	DeregistrationDelaySeconds *int64 `json:"deregistrationDelaySeconds,omitempty"`
+	// AllocateElasticIPs allocates an Elastic IP for each subnet of a Public Network Load Balancer that does not
+	// specify an allocationId, so that the addresses of the load balancer are static. Default: false
+	AllocateElasticIPs *bool `json:"allocateElasticIPs,omitempty"`
+}
+
+// LoadBalancerHealthCheckSpec configures the health checks of the target groups of a Network Load Balancer
+type LoadBalancerHealthCheckSpec struct {
+	// IntervalSeconds is the time between the health checks of a target, from 5 to 300 seconds. Default: 30
+	IntervalSeconds *int64 `json:"intervalSeconds,omitempty"`
+	// HealthyThreshold is the number of consecutive successful health checks before a target is healthy,
+	// from 2 to 10. Default: 2
+	HealthyThreshold *int64 `json:"healthyThreshold,omitempty"`
+	// UnhealthyThreshold is the number of consecutive failed health checks before a target is unhealthy,
+	// from 2 to 10. Default: 2
+	UnhealthyThreshold *int64 `json:"unhealthyThreshold,omitempty"`
+	// Protocol is the protocol of the health checks: TCP, HTTP or HTTPS. Default: TCP
+	Protocol *string `json:"protocol,omitempty"`
+	// Port is the port of the health checks. Default: the port of the target group
+	Port *int64 `json:"port,omitempty"`
+	// Path is the path of the HTTP and HTTPS health checks. Default: /
+	Path *string `json:"path,omitempty"`
 }
 
 // KubeDNSConfig defines the kube dns configuration
@@ -554,6 +937,9 @@ type EtcdClusterSpec struct {
 type EtcdBackupSpec struct {
 	// BackupStore is the VFS path where we will read/write backup data
 	BackupStore string `json:"backupStore,omitempty"`
+	// ReplicaStore is the VFS path the backups are copied to by kops-controller, for example a bucket in another region
+	// for disaster recovery. The backups removed from the backup store are kept in the replica store.
+	ReplicaStore string `json:"replicaStore,omitempty"`
 	// Image is the etcd backup manager image to use.  Setting this will create a sidecar container in the etcd pod with the specified image.
 	Image string `json:"image,omitempty"`
 }
@@ -734,8 +1120,71 @@ type RollingUpdate struct {
 	// nodes.
 	// +optional
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// Strategy is the strategy used to replace instances.
+	// "InPlace" (the default) surges by detaching instances from the instance group.
+	// "SurgeGroup" creates a temporary copy of the instance group with the new configuration,
+	// waits for its nodes to be ready, then replaces the old instances before removing the copy,
+	// so the instance group never runs below its desired capacity.
+	// SurgeGroup is only supported on AWS and has no effect on instance groups with role "Master".
+	// +optional
+	Strategy RollingUpdateStrategy `json:"strategy,omitempty"`
+	// Drain configures how nodes are drained during the update.
+	// +optional
+	Drain *DrainSpec `json:"drain,omitempty"`
 }
 
+// DrainSpec configures how nodes are drained during a rolling update.
+type DrainSpec struct {
+	// Timeout is the maximum time to wait for a node to drain.
+	// Defaults to no limit.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// GracePeriod overrides the termination grace period of the evicted pods.
+	// Defaults to the grace period of each pod.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	// SkipWaitForDeleteTimeout stops waiting for pods that have been terminating for longer
+	// than this duration, for example because their node is no longer reachable.
+	// Defaults to waiting for all pods.
+	// +optional
+	SkipWaitForDeleteTimeout *metav1.Duration `json:"skipWaitForDeleteTimeout,omitempty"`
+	// PodDisruptionBudgetTimeout is how long evictions blocked by a PodDisruptionBudget are retried
+	// before taking the PodDisruptionBudgetTimeoutAction.
+	// Defaults to the drain timeout.
+	// +optional
+	PodDisruptionBudgetTimeout *metav1.Duration `json:"podDisruptionBudgetTimeout,omitempty"`
+	// PodDisruptionBudgetTimeoutAction is the action taken for pods whose eviction is still blocked
+	// by a PodDisruptionBudget after PodDisruptionBudgetTimeout.
+	// "Block" (the default) keeps retrying the evictions until the drain timeout.
+	// "Ignore" deletes the pods, bypassing their PodDisruptionBudget.
+	// "ScaleUp" temporarily adds a replica to the Deployment or StatefulSet owning each pod
+	// and keeps retrying the evictions until the drain timeout.
+	// +optional
+	PodDisruptionBudgetTimeoutAction PodDisruptionBudgetTimeoutAction `json:"podDisruptionBudgetTimeoutAction,omitempty"`
+}
+
+// PodDisruptionBudgetTimeoutAction is the action taken when a PodDisruptionBudget keeps blocking a drain.
+type PodDisruptionBudgetTimeoutAction string
+
+const (
+	// PodDisruptionBudgetTimeoutActionBlock keeps retrying the evictions.
+	PodDisruptionBudgetTimeoutActionBlock PodDisruptionBudgetTimeoutAction = "Block"
+	// PodDisruptionBudgetTimeoutActionIgnore deletes the pods, bypassing their PodDisruptionBudget.
+	PodDisruptionBudgetTimeoutActionIgnore PodDisruptionBudgetTimeoutAction = "Ignore"
+	// PodDisruptionBudgetTimeoutActionScaleUp adds a replica to the workloads owning the pods.
+	PodDisruptionBudgetTimeoutActionScaleUp PodDisruptionBudgetTimeoutAction = "ScaleUp"
+)
+
+// RollingUpdateStrategy is the strategy used to replace instances during a rolling update.
+type RollingUpdateStrategy string
+
+const (
+	// RollingUpdateStrategyInPlace replaces instances within the instance group.
+	RollingUpdateStrategyInPlace RollingUpdateStrategy = "InPlace"
+	// RollingUpdateStrategySurgeGroup replaces instances using a temporary copy of the instance group.
+	RollingUpdateStrategySurgeGroup RollingUpdateStrategy = "SurgeGroup"
+)
+
 type PackagesConfig struct {
 	// HashAmd64 overrides the hash for the AMD64 package.
 	HashAmd64 *string `json:"hashAmd64,omitempty"`
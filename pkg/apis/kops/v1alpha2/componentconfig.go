@@ -19,6 +19,7 @@ package v1alpha2
 import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // KubeletConfigSpec defines the kubelet configuration
@@ -204,6 +205,10 @@ type KubeletConfigSpec struct {
 	TopologyManagerPolicy string `json:"topologyManagerPolicy,omitempty" flag:"topology-manager-policy"`
 	// rotateCertificates enables client certificate rotation.
 	RotateCertificates *bool `json:"rotateCertificates,omitempty" flag:"rotate-certificates"`
+	// ServerTLSBootstrap requests the kubelet serving certificate from the certificates API, instead of using a
+	// certificate issued by kops or a self-signed certificate. kops-controller approves the requests of nodes whose
+	// names and addresses match those reported by the cloud provider.
+	ServerTLSBootstrap *bool `json:"serverTLSBootstrap,omitempty" flag:"rotate-server-certificates"`
 	// Default kubelet behaviour for kernel tuning. If set, kubelet errors if any of kernel tunables is different than kubelet defaults.
 	// (DEPRECATED: This parameter should be set via the config file specified by the Kubelet's --config flag.
 	ProtectKernelDefaults *bool `json:"protectKernelDefaults,omitempty" flag:"protect-kernel-defaults"`
@@ -223,6 +228,12 @@ type KubeletConfigSpec struct {
 	EnableCadvisorJsonEndpoints *bool `json:"enableCadvisorJsonEndpoints,omitempty" flag:"enable-cadvisor-json-endpoints"`
 	// PodPidsLimit is the maximum number of pids in any pod.
 	PodPidsLimit *int64 `json:"podPidsLimit,omitempty" flag:"pod-max-pids"`
+	// UseConfigFile passes the settings that are part of the versioned KubeletConfiguration to the kubelet in a config file, instead of as flags.
+	UseConfigFile *bool `json:"useConfigFile,omitempty"`
+	// ConfigDropIns are KubeletConfiguration (kubelet.config.k8s.io/v1beta1) fragments, keyed by name, which are deep-merged
+	// in name order over the generated config file. A drop-in in an instance group replaces the cluster's drop-in of the same name.
+	// Requires useConfigFile.
+	ConfigDropIns map[string]string `json:"configDropIns,omitempty"`
 }
 
 // KubeProxyConfig defines the configuration for a proxy
@@ -640,6 +651,9 @@ type KubeControllerManagerConfig struct {
 	AuthorizationAlwaysAllowPaths []string `json:"authorizationAlwaysAllowPaths,omitempty" flag:"authorization-always-allow-paths"`
 	// ExternalCloudVolumePlugin is a fallback mechanism that allows a legacy, in-tree cloudprovider to be used for volume plugins even when an external cloud controller manager is being used.  This can be used instead of installing CSI.  The value should be the same as is used for the --cloud-provider flag, i.e. "aws".
 	ExternalCloudVolumePlugin string `json:"externalCloudVolumePlugin,omitempty" flag:"external-cloud-volume-plugin"`
+	// EnableLeaderMigration enables the leader migration of the cloud controllers to an external cloud controller manager,
+	// so that they keep running in exactly one place while the control plane is upgraded.
+	EnableLeaderMigration *bool `json:"enableLeaderMigration,omitempty" flag:"enable-leader-migration"`
 
 	// EnableProfiling enables profiling via web interface host:port/debug/pprof/
 	EnableProfiling *bool `json:"enableProfiling,omitempty" flag:"profiling"`
@@ -670,6 +684,12 @@ type CloudControllerManagerConfig struct {
 	LeaderElection *LeaderElectionConfiguration `json:"leaderElection,omitempty"`
 	// UseServiceAccountCredentials controls whether we use individual service account credentials for each controller.
 	UseServiceAccountCredentials *bool `json:"useServiceAccountCredentials,omitempty" flag:"use-service-account-credentials"`
+	// ConcurrentServiceSyncs is the number of services that are allowed to sync concurrently.
+	ConcurrentServiceSyncs *int32 `json:"concurrentServiceSyncs,omitempty" flag:"concurrent-service-syncs"`
+	// FeatureGates is set of key=value pairs that describe feature gates for alpha/experimental features.
+	FeatureGates map[string]string `json:"featureGates,omitempty" flag:"feature-gates"`
+	// EnableLeaderMigration enables the leader migration of the cloud controllers from the kube-controller-manager.
+	EnableLeaderMigration *bool `json:"enableLeaderMigration,omitempty" flag:"enable-leader-migration"`
 }
 
 // KubeSchedulerConfig is the configuration for the kube-scheduler
@@ -699,6 +719,9 @@ type KubeSchedulerConfig struct {
 	Qps *resource.Quantity `json:"qps,omitempty"`
 	// Burst sets the maximum qps to send to apiserver after the burst quota is exhausted
 	Burst int32 `json:"burst,omitempty"`
+	// Profiles are the scheduling profiles of kube-scheduler, written to its configuration file.
+	// Requires Kubernetes 1.19 or later.
+	Profiles []KubeSchedulerProfile `json:"profiles,omitempty"`
 	// AuthenticationKubeconfig is the path to an Authentication Kubeconfig
 	AuthenticationKubeconfig string `json:"authenticationKubeconfig,omitempty" flag:"authentication-kubeconfig"`
 	// AuthorizationKubeconfig is the path to an Authorization Kubeconfig
@@ -710,6 +733,67 @@ type KubeSchedulerConfig struct {
 	EnableProfiling *bool `json:"enableProfiling,omitempty" flag:"profiling"`
 }
 
+// KubeSchedulerProfile is a scheduling profile of kube-scheduler. Pods select a profile with their spec.schedulerName.
+type KubeSchedulerProfile struct {
+	// SchedulerName is the name of the profile. Defaults to default-scheduler.
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// Plugins are the plugins to enable or disable at each extension point, in addition to the default plugins.
+	Plugins *KubeSchedulerPlugins `json:"plugins,omitempty"`
+	// PluginConfig are the arguments of the plugins of the profile.
+	PluginConfig []KubeSchedulerPluginConfig `json:"pluginConfig,omitempty"`
+}
+
+// KubeSchedulerPlugins are the scheduler plugins to enable or disable at each extension point of a profile.
+type KubeSchedulerPlugins struct {
+	// QueueSort are the plugins that sort the pods in the scheduling queue.
+	QueueSort *KubeSchedulerPluginSet `json:"queueSort,omitempty"`
+	// PreFilter are the plugins called before the filter plugins.
+	PreFilter *KubeSchedulerPluginSet `json:"preFilter,omitempty"`
+	// Filter are the plugins that filter out the nodes that cannot run a pod.
+	Filter *KubeSchedulerPluginSet `json:"filter,omitempty"`
+	// PostFilter are the plugins called when no node can run a pod, such as preemption.
+	PostFilter *KubeSchedulerPluginSet `json:"postFilter,omitempty"`
+	// PreScore are the plugins called before the score plugins.
+	PreScore *KubeSchedulerPluginSet `json:"preScore,omitempty"`
+	// Score are the plugins that rank the nodes that passed the filter plugins.
+	Score *KubeSchedulerPluginSet `json:"score,omitempty"`
+	// Reserve are the plugins that reserve resources on the chosen node.
+	Reserve *KubeSchedulerPluginSet `json:"reserve,omitempty"`
+	// Permit are the plugins that can prevent or delay the binding of a pod.
+	Permit *KubeSchedulerPluginSet `json:"permit,omitempty"`
+	// PreBind are the plugins called before a pod is bound.
+	PreBind *KubeSchedulerPluginSet `json:"preBind,omitempty"`
+	// Bind are the plugins that bind a pod to its node.
+	Bind *KubeSchedulerPluginSet `json:"bind,omitempty"`
+	// PostBind are the plugins called after a pod is bound.
+	PostBind *KubeSchedulerPluginSet `json:"postBind,omitempty"`
+}
+
+// KubeSchedulerPluginSet are the plugins to enable or disable at an extension point.
+type KubeSchedulerPluginSet struct {
+	// Enabled are the plugins to enable in addition to the default plugins.
+	Enabled []KubeSchedulerPlugin `json:"enabled,omitempty"`
+	// Disabled are the default plugins to disable. "*" disables all of them.
+	Disabled []KubeSchedulerPlugin `json:"disabled,omitempty"`
+}
+
+// KubeSchedulerPlugin is a scheduler plugin.
+type KubeSchedulerPlugin struct {
+	// Name is the name of the plugin.
+	Name string `json:"name"`
+	// Weight is the weight of a score plugin.
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// KubeSchedulerPluginConfig are the arguments of a scheduler plugin.
+type KubeSchedulerPluginConfig struct {
+	// Name is the name of the plugin.
+	Name string `json:"name"`
+	// Args are the arguments of the plugin, in the format of the kube-scheduler configuration file
+	// of the Kubernetes version of the cluster, such as the scoringStrategy of NodeResourcesFit.
+	Args *runtime.RawExtension `json:"args,omitempty"`
+}
+
 // LeaderElectionConfiguration defines the configuration of leader election
 // clients for components that can run with leader election enabled.
 type LeaderElectionConfiguration struct {
@@ -809,6 +893,15 @@ type AzureConfiguration struct {
 	AdminUser string `json:"adminUser,omitempty"`
 }
 
+// OCIConfiguration defines Oracle Cloud Infrastructure specific cluster configuration.
+type OCIConfiguration struct {
+	// CompartmentID is the OCID of the compartment where the cluster is built.
+	CompartmentID string `json:"compartmentId,omitempty"`
+	// TagNamespace is the name of an existing defined tag namespace with a "kops-cluster" tag key.
+	// The instances of the cluster are tagged with it, so that only they are members of its dynamic group.
+	TagNamespace string `json:"tagNamespace,omitempty"`
+}
+
 // CloudConfiguration defines the cloud provider configuration
 type CloudConfiguration struct {
 	// Cross-cloud provider options
@@ -847,8 +940,23 @@ type CloudConfiguration struct {
 	Openstack *OpenstackConfiguration `json:"openstack,omitempty"`
 	// Azure cloud-config options
 	Azure *AzureConfiguration `json:"azure,omitempty"`
+	// OCI cloud-config options
+	OCI *OCIConfiguration `json:"oci,omitempty"`
 	// AWSEBSCSIDriver is the config for the AWS EBS CSI driver
 	AWSEBSCSIDriver *AWSEBSCSIDriver `json:"awsEBSCSIDriver,omitempty"`
+	// EBSDefaultEncryption configures EBS encryption by default for the account and region of the cluster (AWS only).
+	EBSDefaultEncryption *EBSDefaultEncryptionSpec `json:"ebsDefaultEncryption,omitempty"`
+}
+
+// EBSDefaultEncryptionSpec configures EBS encryption by default and the KMS key used for the volumes kOps creates.
+type EBSDefaultEncryptionSpec struct {
+	// Enable allows kOps to turn on EBS encryption by default in the account and region of the cluster.
+	// When false, kOps only verifies that EBS encryption by default is turned on and fails otherwise.
+	// Default: false
+	Enable *bool `json:"enable,omitempty"`
+	// KmsKeyId is the KMS key used for the root, additional and etcd volumes that do not specify a key of their own.
+	// Default: the default EBS encryption key of the account and region.
+	KmsKeyId *string `json:"kmsKeyId,omitempty"`
 }
 
 // AWSEBSCSIDriver is the config for the AWS EBS CSI driver
@@ -866,6 +974,15 @@ type AWSEBSCSIDriver struct {
 	// If not specified, the value is approximated from the instance type.
 	// Default: -
 	VolumeAttachLimit *int `json:"volumeAttachLimit,omitempty"`
+
+	// ExtraTags are additional tags applied to the volumes provisioned by the driver, besides the cluster cloudLabels
+	// and the labels of the cloud label policies for volumes that are not restricted to subnets.
+	ExtraTags map[string]string `json:"extraTags,omitempty"`
+
+	// TagVolumesWithClaim tags the volumes provisioned by the driver with the namespace and name of their
+	// PersistentVolumeClaim and the name of their PersistentVolume, so that their cost can be allocated per namespace.
+	// Default: true
+	TagVolumesWithClaim *bool `json:"tagVolumesWithClaim,omitempty"`
 }
 
 // SnapshotControllerConfig is the config for the CSI Snapshot Controller
@@ -925,6 +1042,30 @@ type NodeProblemDetectorConfig struct {
 	// CPULimit of NodeProblemDetector container.
 	// Default: 10m
 	CPULimit *resource.Quantity `json:"cpuLimit,omitempty"`
+
+	// Remediation configures kops-controller to cordon, and optionally replace,
+	// nodes that persistently report problems.
+	Remediation *NodeRemediationSpec `json:"remediation,omitempty"`
+}
+
+// NodeRemediationSpec configures the remediation of nodes reporting problem conditions.
+type NodeRemediationSpec struct {
+	// Enabled enables the remediation of nodes by kops-controller.
+	// Default: false
+	Enabled *bool `json:"enabled,omitempty"`
+	// Conditions are the types of node conditions that trigger remediation while true.
+	// Default: KernelDeadlock, ReadonlyFilesystem
+	Conditions []string `json:"conditions,omitempty"`
+	// Threshold is how long a condition must have been true before the node is remediated.
+	// Default: 10m
+	Threshold *metav1.Duration `json:"threshold,omitempty"`
+	// MaxConcurrent is the maximum number of nodes being remediated at the same time.
+	// Default: 1
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+	// Recycle drains cordoned nodes and deletes their instances through the cloud provider,
+	// so that the instance group replaces them.
+	// Default: true
+	Recycle *bool `json:"recycle,omitempty"`
 }
 
 // ClusterAutoscalerConfig determines the cluster autoscaler configuration.
@@ -965,6 +1106,56 @@ type ClusterAutoscalerConfig struct {
 	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
 }
 
+// DeschedulerConfig determines the descheduler configuration.
+type DeschedulerConfig struct {
+	// Enabled enables the descheduler.
+	// Default: false
+	Enabled *bool `json:"enabled,omitempty"`
+	// Image is the docker container used.
+	// Default: the latest supported image for the specified kubernetes version.
+	Image *string `json:"image,omitempty"`
+	// Schedule is the cron schedule on which the descheduler runs.
+	// Default: */10 * * * *
+	Schedule *string `json:"schedule,omitempty"`
+	// Profiles are the sets of descheduler strategies to run.
+	// Supported values: Balance, Constraints.
+	// Default: Balance
+	Profiles []string `json:"profiles,omitempty"`
+	// NodeUtilization configures when the Balance profile considers a node under- or overutilized.
+	NodeUtilization *DeschedulerNodeUtilization `json:"nodeUtilization,omitempty"`
+	// MemoryRequest of descheduler container.
+	// Default: 64Mi
+	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
+	// CPURequest of descheduler container.
+	// Default: 100m
+	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
+}
+
+// DeschedulerNodeUtilization holds the utilization thresholds, in percent of the node allocatable,
+// for the cpu, memory and pods resources.
+type DeschedulerNodeUtilization struct {
+	// Thresholds are the utilizations below which a node is underutilized, and receives the pods evicted from overutilized nodes.
+	// Default: 20 for cpu, memory and pods
+	Thresholds map[string]int32 `json:"thresholds,omitempty"`
+	// TargetThresholds are the utilizations above which a node is overutilized, and has pods evicted.
+	// Default: 50 for cpu, memory and pods
+	TargetThresholds map[string]int32 `json:"targetThresholds,omitempty"`
+}
+
+// VerticalPodAutoscalerConfig determines the vertical pod autoscaler configuration.
+type VerticalPodAutoscalerConfig struct {
+	// Enabled enables the vertical pod autoscaler.
+	// Default: false
+	Enabled *bool `json:"enabled,omitempty"`
+	// Version is the version of the recommender, updater and admission controller images.
+	// Default: 0.9.2
+	Version *string `json:"version,omitempty"`
+	// CertificateValidity is how long the serving certificate of the admission controller webhook is valid.
+	// kops-controller issues the certificate from the cluster CA, and renews it after two thirds of its validity.
+	// Default: 720h
+	CertificateValidity *metav1.Duration `json:"certificateValidity,omitempty"`
+}
+
 // MetricsServerConfig determines the metrics server configuration.
 type MetricsServerConfig struct {
 	// Enabled enables the metrics server.
@@ -1004,6 +1195,12 @@ type AWSLoadBalancerControllerConfig struct {
 	Enabled *bool `json:"enabled,omitempty"`
 	// Version is the container image tag used.
 	Version *string `json:"version,omitempty"`
+	// IngressClass is the name of an IngressClass for the controller that kOps creates. The controller then only
+	// reconciles the Ingresses of this class. Default: the controller reconciles the Ingresses of the alb class,
+	// and kOps creates no IngressClass.
+	IngressClass *string `json:"ingressClass,omitempty"`
+	// DefaultIngressClass makes the IngressClass the default class of the Ingresses that do not specify one.
+	DefaultIngressClass *bool `json:"defaultIngressClass,omitempty"`
 }
 
 // HasAdmissionController checks if a specific admission controller is enabled
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha2
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -90,6 +91,8 @@ type InstanceGroupSpec struct {
 	Zones []string `json:"zones,omitempty"`
 	// Hooks is a list of hooks for this instanceGroup, note: these can override the cluster wide ones if required
 	Hooks []HookSpec `json:"hooks,omitempty"`
+	// NodePlugins is a list of nodeup plugins for this instance group, note: these can override the cluster wide ones if required
+	NodePlugins []NodePluginSpec `json:"nodePlugins,omitempty"`
 	// MaxPrice indicates this is a spot-pricing group, with the specified value as our max-price bid
 	MaxPrice *string `json:"maxPrice,omitempty"`
 	// SpotDurationInMinutes indicates this is a spot-block group, with the specified value as the spot reservation time
@@ -119,6 +122,9 @@ type InstanceGroupSpec struct {
 	AdditionalUserData []UserData `json:"additionalUserData,omitempty"`
 	// SuspendProcesses disables the listed Scaling Policies
 	SuspendProcesses []string `json:"suspendProcesses,omitempty"`
+	// StableCapacity suspends the AZRebalance and ReplaceUnhealthy processes of the autoscaling group, in addition to
+	// suspendProcesses, so that it does not replace the instances of stateful groups on its own (AWS only)
+	StableCapacity *bool `json:"stableCapacity,omitempty"`
 	// ExternalLoadBalancers define loadbalancers that should be attached to this instance group
 	ExternalLoadBalancers []LoadBalancer `json:"externalLoadBalancers,omitempty"`
 	// DetailedInstanceMonitoring defines if detailed-monitoring is enabled (AWS only)
@@ -129,10 +135,37 @@ type InstanceGroupSpec struct {
 	SecurityGroupOverride *string `json:"securityGroupOverride,omitempty"`
 	// InstanceProtection makes new instances in an autoscaling group protected from scale in
 	InstanceProtection *bool `json:"instanceProtection,omitempty"`
+	// ScaleInProtection selects how instances are protected from scale in (AWS only). With UntilCordoned, new instances
+	// are protected from scale in, and kops-controller removes the protection from the instances of cordoned nodes,
+	// so that the autoscaling group terminates the drained nodes first.
+	ScaleInProtection string `json:"scaleInProtection,omitempty"`
+	// TerminationPolicies are the policies the autoscaling group uses to choose the instances to terminate on scale in,
+	// in order of precedence (AWS only). Default: Default
+	TerminationPolicies []string `json:"terminationPolicies,omitempty"`
+	// InstanceMaintenancePolicy sets the range of healthy capacity that the autoscaling group keeps while it replaces
+	// instances, such as when it rebalances the instances across availability zones (AWS only).
+	InstanceMaintenancePolicy *InstanceMaintenancePolicySpec `json:"instanceMaintenancePolicy,omitempty"`
 	// SysctlParameters will configure kernel parameters using sysctl(8). When
 	// specified, each parameter must follow the form variable=value, the way
 	// it would appear in sysctl.conf.
 	SysctlParameters []string `json:"sysctlParameters,omitempty"`
+	// Sysctls are kernel parameters to set on the instances, keyed by name.
+	// The names must be in the allowlist of parameters that are safe to set on a node.
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+	// KernelModules are kernel modules to load on the instances at boot.
+	KernelModules []string `json:"kernelModules,omitempty"`
+	// Swap configures swap on the instances.
+	Swap *SwapSpec `json:"swap,omitempty"`
+	// Logging configures the rotation and retention of the logs on the instances.
+	Logging *InstanceGroupLoggingSpec `json:"logging,omitempty"`
+	// StateVolumes are additional volumes for the state of the container runtime and the kubelet (AWS only).
+	StateVolumes *StateVolumesSpec `json:"stateVolumes,omitempty"`
+	// InstanceStorage combines and mounts the NVMe instance store volumes of the machine type (AWS only).
+	InstanceStorage *InstanceStorageSpec `json:"instanceStorage,omitempty"`
+	// SELinux configures SELinux on the instances. Requires an image with SELinux support.
+	SELinux *SELinuxSpec `json:"selinux,omitempty"`
+	// AppArmor configures AppArmor on the instances. Requires an image with AppArmor support.
+	AppArmor *AppArmorSpec `json:"appArmor,omitempty"`
 	// RollingUpdate defines the rolling-update behavior
 	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
 	// InstanceInterruptionBehavior defines if a spot instance should be terminated, hibernated,
@@ -150,6 +183,147 @@ type InstanceGroupSpec struct {
 	UpdatePolicy *string `json:"updatePolicy,omitempty"`
 	// WarmPool configures an ASG warm pool for the instance group
 	WarmPool *WarmPoolSpec `json:"warmPool,omitempty"`
+	// RootVolumeEphemeral places the root volume on the local storage of the instances (Azure only).
+	RootVolumeEphemeral *bool `json:"rootVolumeEphemeral,omitempty"`
+	// AcceleratedNetworking enables accelerated networking on the network interfaces of the instances (Azure only).
+	AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
+	// RegionalInstanceGroup creates a single regional managed instance group spread over the zones of the instance group,
+	// instead of one managed instance group per zone (GCE only).
+	RegionalInstanceGroup *bool `json:"regionalInstanceGroup,omitempty"`
+	// ShieldedVM configures the Shielded VM options of the instances (GCE only).
+	ShieldedVM *ShieldedVMSpec `json:"shieldedVM,omitempty"`
+	// ConfidentialVM runs the instances as Confidential VMs, with their memory encrypted (GCE only).
+	ConfidentialVM *bool `json:"confidentialVM,omitempty"`
+	// NestedVirtualization allows the instances to run virtual machines (GCE only).
+	NestedVirtualization *bool `json:"nestedVirtualization,omitempty"`
+	// ProvisioningPolicy configures the provisioning model and the machine types of the instances (GCE only).
+	ProvisioningPolicy *ProvisioningPolicySpec `json:"provisioningPolicy,omitempty"`
+	// Metal lists the machines of the instance group, which kOps does not provision itself (metal only).
+	Metal *MetalInstanceGroupSpec `json:"metal,omitempty"`
+	// SSHPublicKeys are the names of the SSH public keys of the cluster spec that nodeup authorizes on the instances.
+	// Default: all the SSH public keys of the cluster spec
+	SSHPublicKeys []string `json:"sshPublicKeys,omitempty"`
+}
+
+// ShieldedVMSpec configures the Shielded VM options of the instances of an instance group (GCE only)
+type ShieldedVMSpec struct {
+	// SecureBoot only allows signed boot components to run. The image must support it.
+	SecureBoot *bool `json:"secureBoot,omitempty"`
+	// VTPM enables the virtual Trusted Platform Module. Defaults to true.
+	VTPM *bool `json:"vtpm,omitempty"`
+	// IntegrityMonitoring enables the monitoring of the boot integrity of the instances. Defaults to true.
+	IntegrityMonitoring *bool `json:"integrityMonitoring,omitempty"`
+}
+
+// ProvisioningPolicySpec configures how the instances of an instance group are provisioned (GCE only)
+type ProvisioningPolicySpec struct {
+	// ProvisioningModel is the default provisioning model of the instances, either STANDARD (the default) or SPOT.
+	// Spot instances are created as preemptible instances.
+	ProvisioningModel string `json:"provisioningModel,omitempty"`
+	// MachineTypes are the machine types of the instances. The instances are split between the machine types
+	// in proportion to their weights. The first machine type must be the machine type of the instance group.
+	MachineTypes []ProvisioningMachineTypeSpec `json:"machineTypes,omitempty"`
+	// AutomaticRestart restarts instances stopped by GCE for non-user reasons. Defaults to true for standard instances.
+	// Spot instances cannot be restarted automatically.
+	AutomaticRestart *bool `json:"automaticRestart,omitempty"`
+	// OnHostMaintenance is the action taken on instances during host maintenance, either MIGRATE or TERMINATE.
+	// Defaults to MIGRATE for standard instances; spot instances are always terminated.
+	OnHostMaintenance string `json:"onHostMaintenance,omitempty"`
+}
+
+// ProvisioningMachineTypeSpec is a machine type of an instance group, with its weight (GCE only)
+type ProvisioningMachineTypeSpec struct {
+	// Name is the name of the machine type.
+	Name string `json:"name,omitempty"`
+	// Weight is the share of the instances that use this machine type, relative to the other machine types. Defaults to 1.
+	Weight *int32 `json:"weight,omitempty"`
+	// ProvisioningModel overrides the provisioning model of the instances of this machine type,
+	// to mix standard and spot instances in the same instance group.
+	ProvisioningModel string `json:"provisioningModel,omitempty"`
+}
+
+// MetalInstanceGroupSpec lists the machines of an instance group (metal only)
+type MetalInstanceGroupSpec struct {
+	// Hosts are the addresses of the machines. kOps connects to them over SSH to enroll them in the cluster.
+	Hosts []string `json:"hosts,omitempty"`
+	// SSHUser is the user that kOps connects as. Defaults to root.
+	SSHUser string `json:"sshUser,omitempty"`
+}
+
+// SwapSpec configures swap on the instances of an instance group
+type SwapSpec struct {
+	// Size is the size of the swap file, or of the zram device.
+	Size *resource.Quantity `json:"size,omitempty"`
+	// Swappiness sets the vm.swappiness kernel parameter (0-200).
+	Swappiness *int32 `json:"swappiness,omitempty"`
+	// ZRAM uses a compressed swap device in memory instead of a swap file on the root volume.
+	ZRAM *bool `json:"zram,omitempty"`
+	// SwapBehavior sets how pods can use swap, either LimitedSwap or UnlimitedSwap.
+	// Requires the kubelet to use a config file.
+	SwapBehavior string `json:"swapBehavior,omitempty"`
+}
+
+// InstanceGroupLoggingSpec configures the rotation and retention of the logs on the instances of an instance group.
+type InstanceGroupLoggingSpec struct {
+	// JournaldSystemMaxUse is the maximum disk space (e.g. 500M) used by the persistent journal.
+	JournaldSystemMaxUse string `json:"journaldSystemMaxUse,omitempty"`
+	// Volume is an additional volume mounted on /var/log (AWS only).
+	Volume *VolumeSpec `json:"volume,omitempty"`
+}
+
+// StateVolumesSpec defines the volumes dedicated to the state of the node components.
+// The volumes are formatted and mounted by nodeup, and mounted again at boot before the components start.
+type StateVolumesSpec struct {
+	// Containerd is mounted on /var/lib/containerd, which holds the container images and writable layers.
+	Containerd *VolumeSpec `json:"containerd,omitempty"`
+	// Kubelet is mounted on /var/lib/kubelet, which holds the volumes of the pods.
+	Kubelet *VolumeSpec `json:"kubelet,omitempty"`
+}
+
+// InstanceStorageSpec defines how the NVMe instance store volumes are used.
+// The volumes are combined in a RAID-0 array when there are several, formatted and mounted by a service
+// running at every boot, as their content is lost when the instance is stopped.
+type InstanceStorageSpec struct {
+	// Path is where the volumes are mounted, for example /var/lib/containerd.
+	Path string `json:"path,omitempty"`
+	// Filesystem is the filesystem the volumes are formatted with, ext4 (default) or xfs.
+	Filesystem string `json:"filesystem,omitempty"`
+	// MountOptions are the options used to mount the filesystem.
+	MountOptions []string `json:"mountOptions,omitempty"`
+}
+
+// SELinuxSpec configures SELinux on the instances of an instance group.
+// SELinux is supported on Amazon Linux 2, CentOS, RHEL and Flatcar.
+type SELinuxSpec struct {
+	// Mode is the mode SELinux runs in, either Enforcing or Permissive.
+	// Nodeup fails if SELinux is disabled on the image, and containerd is configured to label the containers.
+	Mode string `json:"mode,omitempty"`
+}
+
+// AppArmorSpec configures AppArmor on the instances of an instance group.
+// AppArmor is supported on Debian and Ubuntu.
+type AppArmorSpec struct {
+	// Profiles are custom profiles that nodeup loads in enforce mode.
+	// Pods select a profile with the container.apparmor.security.beta.kubernetes.io annotation.
+	Profiles []AppArmorProfile `json:"profiles,omitempty"`
+}
+
+// AppArmorProfile is a custom AppArmor profile.
+type AppArmorProfile struct {
+	// Name is the name of the file of the profile in /etc/apparmor.d.
+	Name string `json:"name,omitempty"`
+	// Content is the profile, in the AppArmor policy language.
+	Content string `json:"content,omitempty"`
+}
+
+// InstanceMaintenancePolicySpec is the range of healthy capacity that an autoscaling group keeps while it replaces instances (AWS only)
+type InstanceMaintenancePolicySpec struct {
+	// MinHealthyPercentage is the percentage of the desired capacity that stays healthy while instances are replaced,
+	// from 0 to 100. With 100, replacements are launched before the instances they replace are terminated.
+	MinHealthyPercentage *int64 `json:"minHealthyPercentage,omitempty"`
+	// MaxHealthyPercentage is the percentage of the desired capacity that can be in service while instances are replaced,
+	// from 100 to 200, and at most 100 above minHealthyPercentage.
+	MaxHealthyPercentage *int64 `json:"maxHealthyPercentage,omitempty"`
 }
 
 // InstanceMetadataOptions defines the EC2 instance metadata service options (AWS Only)
@@ -245,4 +419,12 @@ type LoadBalancer struct {
 	LoadBalancerName *string `json:"loadBalancerName,omitempty"`
 	// TargetGroupARN to associate with this instance group (AWS ALB/NLB)
 	TargetGroupARN *string `json:"targetGroupArn,omitempty"`
+	// Name is the name of the port of the instances that the target group sends traffic to, unique in the instance group
+	Name string `json:"name,omitempty"`
+	// Port is the port on which the instances receive the traffic of the target group
+	Port *int64 `json:"port,omitempty"`
+	// Protocol is the protocol with which the target group sends traffic to the instances
+	Protocol *string `json:"protocol,omitempty"`
+	// HealthCheckPort is the port on which the instances answer the health checks of the target group. Default: port
+	HealthCheckPort *int64 `json:"healthCheckPort,omitempty"`
 }
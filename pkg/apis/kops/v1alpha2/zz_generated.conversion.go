@@ -103,6 +103,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AppArmorProfile)(nil), (*kops.AppArmorProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AppArmorProfile_To_kops_AppArmorProfile(a.(*AppArmorProfile), b.(*kops.AppArmorProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AppArmorProfile)(nil), (*AppArmorProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AppArmorProfile_To_v1alpha2_AppArmorProfile(a.(*kops.AppArmorProfile), b.(*AppArmorProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AppArmorSpec)(nil), (*kops.AppArmorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AppArmorSpec_To_kops_AppArmorSpec(a.(*AppArmorSpec), b.(*kops.AppArmorSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AppArmorSpec)(nil), (*AppArmorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AppArmorSpec_To_v1alpha2_AppArmorSpec(a.(*kops.AppArmorSpec), b.(*AppArmorSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Assets)(nil), (*kops.Assets)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_Assets_To_kops_Assets(a.(*Assets), b.(*kops.Assets), scope)
 	}); err != nil {
@@ -253,6 +273,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CloudLabelPolicy)(nil), (*kops.CloudLabelPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CloudLabelPolicy_To_kops_CloudLabelPolicy(a.(*CloudLabelPolicy), b.(*kops.CloudLabelPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.CloudLabelPolicy)(nil), (*CloudLabelPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_CloudLabelPolicy_To_v1alpha2_CloudLabelPolicy(a.(*kops.CloudLabelPolicy), b.(*CloudLabelPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Cluster)(nil), (*kops.Cluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_Cluster_To_kops_Cluster(a.(*Cluster), b.(*kops.Cluster), scope)
 	}); err != nil {
@@ -313,6 +343,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdNRIConfig)(nil), (*kops.ContainerdNRIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(a.(*ContainerdNRIConfig), b.(*kops.ContainerdNRIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdNRIConfig)(nil), (*ContainerdNRIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(a.(*kops.ContainerdNRIConfig), b.(*ContainerdNRIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdRegistryConfig)(nil), (*kops.ContainerdRegistryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(a.(*ContainerdRegistryConfig), b.(*kops.ContainerdRegistryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdRegistryConfig)(nil), (*ContainerdRegistryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(a.(*kops.ContainerdRegistryConfig), b.(*ContainerdRegistryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ContinuousValidationSpec)(nil), (*kops.ContinuousValidationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContinuousValidationSpec_To_kops_ContinuousValidationSpec(a.(*ContinuousValidationSpec), b.(*kops.ContinuousValidationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContinuousValidationSpec)(nil), (*ContinuousValidationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContinuousValidationSpec_To_v1alpha2_ContinuousValidationSpec(a.(*kops.ContinuousValidationSpec), b.(*ContinuousValidationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DNSAccessSpec)(nil), (*kops.DNSAccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(a.(*DNSAccessSpec), b.(*kops.DNSAccessSpec), scope)
 	}); err != nil {
@@ -353,6 +413,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DeschedulerConfig)(nil), (*kops.DeschedulerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DeschedulerConfig_To_kops_DeschedulerConfig(a.(*DeschedulerConfig), b.(*kops.DeschedulerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.DeschedulerConfig)(nil), (*DeschedulerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_DeschedulerConfig_To_v1alpha2_DeschedulerConfig(a.(*kops.DeschedulerConfig), b.(*DeschedulerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DeschedulerNodeUtilization)(nil), (*kops.DeschedulerNodeUtilization)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DeschedulerNodeUtilization_To_kops_DeschedulerNodeUtilization(a.(*DeschedulerNodeUtilization), b.(*kops.DeschedulerNodeUtilization), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.DeschedulerNodeUtilization)(nil), (*DeschedulerNodeUtilization)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_DeschedulerNodeUtilization_To_v1alpha2_DeschedulerNodeUtilization(a.(*kops.DeschedulerNodeUtilization), b.(*DeschedulerNodeUtilization), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DexSpec)(nil), (*kops.DexSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DexSpec_To_kops_DexSpec(a.(*DexSpec), b.(*kops.DexSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.DexSpec)(nil), (*DexSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_DexSpec_To_v1alpha2_DexSpec(a.(*kops.DexSpec), b.(*DexSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DockerConfig)(nil), (*kops.DockerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DockerConfig_To_kops_DockerConfig(a.(*DockerConfig), b.(*kops.DockerConfig), scope)
 	}); err != nil {
@@ -363,6 +453,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DrainSpec)(nil), (*kops.DrainSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DrainSpec_To_kops_DrainSpec(a.(*DrainSpec), b.(*kops.DrainSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.DrainSpec)(nil), (*DrainSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_DrainSpec_To_v1alpha2_DrainSpec(a.(*kops.DrainSpec), b.(*DrainSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EBSDefaultEncryptionSpec)(nil), (*kops.EBSDefaultEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EBSDefaultEncryptionSpec_To_kops_EBSDefaultEncryptionSpec(a.(*EBSDefaultEncryptionSpec), b.(*kops.EBSDefaultEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EBSDefaultEncryptionSpec)(nil), (*EBSDefaultEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EBSDefaultEncryptionSpec_To_v1alpha2_EBSDefaultEncryptionSpec(a.(*kops.EBSDefaultEncryptionSpec), b.(*EBSDefaultEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EgressGatewaySpec)(nil), (*kops.EgressGatewaySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EgressGatewaySpec_To_kops_EgressGatewaySpec(a.(*EgressGatewaySpec), b.(*kops.EgressGatewaySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EgressGatewaySpec)(nil), (*EgressGatewaySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EgressGatewaySpec_To_v1alpha2_EgressGatewaySpec(a.(*kops.EgressGatewaySpec), b.(*EgressGatewaySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EgressProxySpec)(nil), (*kops.EgressProxySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_EgressProxySpec_To_kops_EgressProxySpec(a.(*EgressProxySpec), b.(*kops.EgressProxySpec), scope)
 	}); err != nil {
@@ -423,6 +543,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EventsSpec)(nil), (*kops.EventsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EventsSpec_To_kops_EventsSpec(a.(*EventsSpec), b.(*kops.EventsSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EventsSpec)(nil), (*EventsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EventsSpec_To_v1alpha2_EventsSpec(a.(*kops.EventsSpec), b.(*EventsSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ExecContainerAction)(nil), (*kops.ExecContainerAction)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ExecContainerAction_To_kops_ExecContainerAction(a.(*ExecContainerAction), b.(*kops.ExecContainerAction), scope)
 	}); err != nil {
@@ -513,6 +643,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HibernationSpec)(nil), (*kops.HibernationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_HibernationSpec_To_kops_HibernationSpec(a.(*HibernationSpec), b.(*kops.HibernationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HibernationSpec)(nil), (*HibernationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HibernationSpec_To_v1alpha2_HibernationSpec(a.(*kops.HibernationSpec), b.(*HibernationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HookSpec)(nil), (*kops.HookSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_HookSpec_To_kops_HookSpec(a.(*HookSpec), b.(*kops.HookSpec), scope)
 	}); err != nil {
@@ -553,6 +693,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ImageVerificationSpec)(nil), (*kops.ImageVerificationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(a.(*ImageVerificationSpec), b.(*kops.ImageVerificationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ImageVerificationSpec)(nil), (*ImageVerificationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(a.(*kops.ImageVerificationSpec), b.(*ImageVerificationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceGroup)(nil), (*kops.InstanceGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_InstanceGroup_To_kops_InstanceGroup(a.(*InstanceGroup), b.(*kops.InstanceGroup), scope)
 	}); err != nil {
@@ -573,6 +723,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InstanceGroupLoggingSpec)(nil), (*kops.InstanceGroupLoggingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_InstanceGroupLoggingSpec_To_kops_InstanceGroupLoggingSpec(a.(*InstanceGroupLoggingSpec), b.(*kops.InstanceGroupLoggingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.InstanceGroupLoggingSpec)(nil), (*InstanceGroupLoggingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_InstanceGroupLoggingSpec_To_v1alpha2_InstanceGroupLoggingSpec(a.(*kops.InstanceGroupLoggingSpec), b.(*InstanceGroupLoggingSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceGroupSpec)(nil), (*kops.InstanceGroupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(a.(*InstanceGroupSpec), b.(*kops.InstanceGroupSpec), scope)
 	}); err != nil {
@@ -583,6 +743,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InstanceMaintenancePolicySpec)(nil), (*kops.InstanceMaintenancePolicySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_InstanceMaintenancePolicySpec_To_kops_InstanceMaintenancePolicySpec(a.(*InstanceMaintenancePolicySpec), b.(*kops.InstanceMaintenancePolicySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.InstanceMaintenancePolicySpec)(nil), (*InstanceMaintenancePolicySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_InstanceMaintenancePolicySpec_To_v1alpha2_InstanceMaintenancePolicySpec(a.(*kops.InstanceMaintenancePolicySpec), b.(*InstanceMaintenancePolicySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceMetadataOptions)(nil), (*kops.InstanceMetadataOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_InstanceMetadataOptions_To_kops_InstanceMetadataOptions(a.(*InstanceMetadataOptions), b.(*kops.InstanceMetadataOptions), scope)
 	}); err != nil {
@@ -593,6 +763,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InstanceStorageSpec)(nil), (*kops.InstanceStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(a.(*InstanceStorageSpec), b.(*kops.InstanceStorageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.InstanceStorageSpec)(nil), (*InstanceStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(a.(*kops.InstanceStorageSpec), b.(*InstanceStorageSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Keyset)(nil), (*kops.Keyset)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_Keyset_To_kops_Keyset(a.(*Keyset), b.(*kops.Keyset), scope)
 	}); err != nil {
@@ -703,6 +883,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KubeSchedulerPlugin)(nil), (*kops.KubeSchedulerPlugin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KubeSchedulerPlugin_To_kops_KubeSchedulerPlugin(a.(*KubeSchedulerPlugin), b.(*kops.KubeSchedulerPlugin), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KubeSchedulerPlugin)(nil), (*KubeSchedulerPlugin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KubeSchedulerPlugin_To_v1alpha2_KubeSchedulerPlugin(a.(*kops.KubeSchedulerPlugin), b.(*KubeSchedulerPlugin), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KubeSchedulerPluginConfig)(nil), (*kops.KubeSchedulerPluginConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KubeSchedulerPluginConfig_To_kops_KubeSchedulerPluginConfig(a.(*KubeSchedulerPluginConfig), b.(*kops.KubeSchedulerPluginConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KubeSchedulerPluginConfig)(nil), (*KubeSchedulerPluginConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KubeSchedulerPluginConfig_To_v1alpha2_KubeSchedulerPluginConfig(a.(*kops.KubeSchedulerPluginConfig), b.(*KubeSchedulerPluginConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KubeSchedulerPluginSet)(nil), (*kops.KubeSchedulerPluginSet)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(a.(*KubeSchedulerPluginSet), b.(*kops.KubeSchedulerPluginSet), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KubeSchedulerPluginSet)(nil), (*KubeSchedulerPluginSet)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(a.(*kops.KubeSchedulerPluginSet), b.(*KubeSchedulerPluginSet), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KubeSchedulerPlugins)(nil), (*kops.KubeSchedulerPlugins)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KubeSchedulerPlugins_To_kops_KubeSchedulerPlugins(a.(*KubeSchedulerPlugins), b.(*kops.KubeSchedulerPlugins), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KubeSchedulerPlugins)(nil), (*KubeSchedulerPlugins)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KubeSchedulerPlugins_To_v1alpha2_KubeSchedulerPlugins(a.(*kops.KubeSchedulerPlugins), b.(*KubeSchedulerPlugins), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KubeSchedulerProfile)(nil), (*kops.KubeSchedulerProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KubeSchedulerProfile_To_kops_KubeSchedulerProfile(a.(*KubeSchedulerProfile), b.(*kops.KubeSchedulerProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KubeSchedulerProfile)(nil), (*KubeSchedulerProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KubeSchedulerProfile_To_v1alpha2_KubeSchedulerProfile(a.(*kops.KubeSchedulerProfile), b.(*KubeSchedulerProfile), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KubeletConfigSpec)(nil), (*kops.KubeletConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(a.(*KubeletConfigSpec), b.(*kops.KubeletConfigSpec), scope)
 	}); err != nil {
@@ -763,6 +993,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LoadBalancerHealthCheckSpec)(nil), (*kops.LoadBalancerHealthCheckSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LoadBalancerHealthCheckSpec_To_kops_LoadBalancerHealthCheckSpec(a.(*LoadBalancerHealthCheckSpec), b.(*kops.LoadBalancerHealthCheckSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LoadBalancerHealthCheckSpec)(nil), (*LoadBalancerHealthCheckSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LoadBalancerHealthCheckSpec_To_v1alpha2_LoadBalancerHealthCheckSpec(a.(*kops.LoadBalancerHealthCheckSpec), b.(*LoadBalancerHealthCheckSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LoadBalancerSubnetSpec)(nil), (*kops.LoadBalancerSubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_LoadBalancerSubnetSpec_To_kops_LoadBalancerSubnetSpec(a.(*LoadBalancerSubnetSpec), b.(*kops.LoadBalancerSubnetSpec), scope)
 	}); err != nil {
@@ -783,6 +1023,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MaintenanceWindowSpec)(nil), (*kops.MaintenanceWindowSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(a.(*MaintenanceWindowSpec), b.(*kops.MaintenanceWindowSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.MaintenanceWindowSpec)(nil), (*MaintenanceWindowSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_MaintenanceWindowSpec_To_v1alpha2_MaintenanceWindowSpec(a.(*kops.MaintenanceWindowSpec), b.(*MaintenanceWindowSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MetalInstanceGroupSpec)(nil), (*kops.MetalInstanceGroupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_MetalInstanceGroupSpec_To_kops_MetalInstanceGroupSpec(a.(*MetalInstanceGroupSpec), b.(*kops.MetalInstanceGroupSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.MetalInstanceGroupSpec)(nil), (*MetalInstanceGroupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_MetalInstanceGroupSpec_To_v1alpha2_MetalInstanceGroupSpec(a.(*kops.MetalInstanceGroupSpec), b.(*MetalInstanceGroupSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MetricsServerConfig)(nil), (*kops.MetricsServerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_MetricsServerConfig_To_kops_MetricsServerConfig(a.(*MetricsServerConfig), b.(*kops.MetricsServerConfig), scope)
 	}); err != nil {
@@ -813,6 +1073,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NetworkCIDRAllocationSpec)(nil), (*kops.NetworkCIDRAllocationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NetworkCIDRAllocationSpec_To_kops_NetworkCIDRAllocationSpec(a.(*NetworkCIDRAllocationSpec), b.(*kops.NetworkCIDRAllocationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NetworkCIDRAllocationSpec)(nil), (*NetworkCIDRAllocationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NetworkCIDRAllocationSpec_To_v1alpha2_NetworkCIDRAllocationSpec(a.(*kops.NetworkCIDRAllocationSpec), b.(*NetworkCIDRAllocationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NetworkingSpec)(nil), (*kops.NetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NetworkingSpec_To_kops_NetworkingSpec(a.(*NetworkingSpec), b.(*kops.NetworkingSpec), scope)
 	}); err != nil {
@@ -843,6 +1113,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeLabelReconciliationSpec)(nil), (*kops.NodeLabelReconciliationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeLabelReconciliationSpec_To_kops_NodeLabelReconciliationSpec(a.(*NodeLabelReconciliationSpec), b.(*kops.NodeLabelReconciliationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeLabelReconciliationSpec)(nil), (*NodeLabelReconciliationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeLabelReconciliationSpec_To_v1alpha2_NodeLabelReconciliationSpec(a.(*kops.NodeLabelReconciliationSpec), b.(*NodeLabelReconciliationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeLocalDNSConfig)(nil), (*kops.NodeLocalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(a.(*NodeLocalDNSConfig), b.(*kops.NodeLocalDNSConfig), scope)
 	}); err != nil {
@@ -853,6 +1133,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodePluginArtifact)(nil), (*kops.NodePluginArtifact)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodePluginArtifact_To_kops_NodePluginArtifact(a.(*NodePluginArtifact), b.(*kops.NodePluginArtifact), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodePluginArtifact)(nil), (*NodePluginArtifact)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodePluginArtifact_To_v1alpha2_NodePluginArtifact(a.(*kops.NodePluginArtifact), b.(*NodePluginArtifact), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodePluginSpec)(nil), (*kops.NodePluginSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodePluginSpec_To_kops_NodePluginSpec(a.(*NodePluginSpec), b.(*kops.NodePluginSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodePluginSpec)(nil), (*NodePluginSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodePluginSpec_To_v1alpha2_NodePluginSpec(a.(*kops.NodePluginSpec), b.(*NodePluginSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeProblemDetectorConfig)(nil), (*kops.NodeProblemDetectorConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NodeProblemDetectorConfig_To_kops_NodeProblemDetectorConfig(a.(*NodeProblemDetectorConfig), b.(*kops.NodeProblemDetectorConfig), scope)
 	}); err != nil {
@@ -863,6 +1163,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeRemediationSpec)(nil), (*kops.NodeRemediationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeRemediationSpec_To_kops_NodeRemediationSpec(a.(*NodeRemediationSpec), b.(*kops.NodeRemediationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeRemediationSpec)(nil), (*NodeRemediationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeRemediationSpec_To_v1alpha2_NodeRemediationSpec(a.(*kops.NodeRemediationSpec), b.(*NodeRemediationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeTerminationHandlerConfig)(nil), (*kops.NodeTerminationHandlerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NodeTerminationHandlerConfig_To_kops_NodeTerminationHandlerConfig(a.(*NodeTerminationHandlerConfig), b.(*kops.NodeTerminationHandlerConfig), scope)
 	}); err != nil {
@@ -873,6 +1183,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*OCIConfiguration)(nil), (*kops.OCIConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_OCIConfiguration_To_kops_OCIConfiguration(a.(*OCIConfiguration), b.(*kops.OCIConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.OCIConfiguration)(nil), (*OCIConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_OCIConfiguration_To_v1alpha2_OCIConfiguration(a.(*kops.OCIConfiguration), b.(*OCIConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*OIDCAuthenticationSpec)(nil), (*kops.OIDCAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(a.(*OIDCAuthenticationSpec), b.(*kops.OIDCAuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.OIDCAuthenticationSpec)(nil), (*OIDCAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(a.(*kops.OIDCAuthenticationSpec), b.(*OIDCAuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*OSPatchingSpec)(nil), (*kops.OSPatchingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_OSPatchingSpec_To_kops_OSPatchingSpec(a.(*OSPatchingSpec), b.(*kops.OSPatchingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.OSPatchingSpec)(nil), (*OSPatchingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_OSPatchingSpec_To_v1alpha2_OSPatchingSpec(a.(*kops.OSPatchingSpec), b.(*OSPatchingSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenstackBlockStorageConfig)(nil), (*kops.OpenstackBlockStorageConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(a.(*OpenstackBlockStorageConfig), b.(*kops.OpenstackBlockStorageConfig), scope)
 	}); err != nil {
@@ -943,6 +1283,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PodSecurityAdmissionSpec)(nil), (*kops.PodSecurityAdmissionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PodSecurityAdmissionSpec_To_kops_PodSecurityAdmissionSpec(a.(*PodSecurityAdmissionSpec), b.(*kops.PodSecurityAdmissionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PodSecurityAdmissionSpec)(nil), (*PodSecurityAdmissionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PodSecurityAdmissionSpec_To_v1alpha2_PodSecurityAdmissionSpec(a.(*kops.PodSecurityAdmissionSpec), b.(*PodSecurityAdmissionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ProvisioningMachineTypeSpec)(nil), (*kops.ProvisioningMachineTypeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ProvisioningMachineTypeSpec_To_kops_ProvisioningMachineTypeSpec(a.(*ProvisioningMachineTypeSpec), b.(*kops.ProvisioningMachineTypeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ProvisioningMachineTypeSpec)(nil), (*ProvisioningMachineTypeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ProvisioningMachineTypeSpec_To_v1alpha2_ProvisioningMachineTypeSpec(a.(*kops.ProvisioningMachineTypeSpec), b.(*ProvisioningMachineTypeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ProvisioningPolicySpec)(nil), (*kops.ProvisioningPolicySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ProvisioningPolicySpec_To_kops_ProvisioningPolicySpec(a.(*ProvisioningPolicySpec), b.(*kops.ProvisioningPolicySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ProvisioningPolicySpec)(nil), (*ProvisioningPolicySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ProvisioningPolicySpec_To_v1alpha2_ProvisioningPolicySpec(a.(*kops.ProvisioningPolicySpec), b.(*ProvisioningPolicySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RBACAuthorizationSpec)(nil), (*kops.RBACAuthorizationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(a.(*RBACAuthorizationSpec), b.(*kops.RBACAuthorizationSpec), scope)
 	}); err != nil {
@@ -953,6 +1323,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RBACBindingSpec)(nil), (*kops.RBACBindingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RBACBindingSpec_To_kops_RBACBindingSpec(a.(*RBACBindingSpec), b.(*kops.RBACBindingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RBACBindingSpec)(nil), (*RBACBindingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RBACBindingSpec_To_v1alpha2_RBACBindingSpec(a.(*kops.RBACBindingSpec), b.(*RBACBindingSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RollingUpdate)(nil), (*kops.RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(a.(*RollingUpdate), b.(*kops.RollingUpdate), scope)
 	}); err != nil {
@@ -973,6 +1353,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SELinuxSpec)(nil), (*kops.SELinuxSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SELinuxSpec_To_kops_SELinuxSpec(a.(*SELinuxSpec), b.(*kops.SELinuxSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SELinuxSpec)(nil), (*SELinuxSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SELinuxSpec_To_v1alpha2_SELinuxSpec(a.(*kops.SELinuxSpec), b.(*SELinuxSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SSHCredential)(nil), (*kops.SSHCredential)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_SSHCredential_To_kops_SSHCredential(a.(*SSHCredential), b.(*kops.SSHCredential), scope)
 	}); err != nil {
@@ -1003,6 +1393,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SSHPublicKeySpec)(nil), (*kops.SSHPublicKeySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SSHPublicKeySpec_To_kops_SSHPublicKeySpec(a.(*SSHPublicKeySpec), b.(*kops.SSHPublicKeySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SSHPublicKeySpec)(nil), (*SSHPublicKeySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SSHPublicKeySpec_To_v1alpha2_SSHPublicKeySpec(a.(*kops.SSHPublicKeySpec), b.(*SSHPublicKeySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SSHSpec)(nil), (*kops.SSHSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SSHSpec_To_kops_SSHSpec(a.(*SSHSpec), b.(*kops.SSHSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SSHSpec)(nil), (*SSHSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SSHSpec_To_v1alpha2_SSHSpec(a.(*kops.SSHSpec), b.(*SSHSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ServiceAccountExternalPermission)(nil), (*kops.ServiceAccountExternalPermission)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ServiceAccountExternalPermission_To_kops_ServiceAccountExternalPermission(a.(*ServiceAccountExternalPermission), b.(*kops.ServiceAccountExternalPermission), scope)
 	}); err != nil {
@@ -1023,28 +1433,78 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*SnapshotControllerConfig)(nil), (*kops.SnapshotControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha2_SnapshotControllerConfig_To_kops_SnapshotControllerConfig(a.(*SnapshotControllerConfig), b.(*kops.SnapshotControllerConfig), scope)
+	if err := s.AddGeneratedConversionFunc((*SessionManagerAgentPackage)(nil), (*kops.SessionManagerAgentPackage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SessionManagerAgentPackage_To_kops_SessionManagerAgentPackage(a.(*SessionManagerAgentPackage), b.(*kops.SessionManagerAgentPackage), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*kops.SnapshotControllerConfig)(nil), (*SnapshotControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(a.(*kops.SnapshotControllerConfig), b.(*SnapshotControllerConfig), scope)
+	if err := s.AddGeneratedConversionFunc((*kops.SessionManagerAgentPackage)(nil), (*SessionManagerAgentPackage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SessionManagerAgentPackage_To_v1alpha2_SessionManagerAgentPackage(a.(*kops.SessionManagerAgentPackage), b.(*SessionManagerAgentPackage), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha2_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*SessionManagerSpec)(nil), (*kops.SessionManagerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SessionManagerSpec_To_kops_SessionManagerSpec(a.(*SessionManagerSpec), b.(*kops.SessionManagerSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*kops.TargetSpec)(nil), (*TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_kops_TargetSpec_To_v1alpha2_TargetSpec(a.(*kops.TargetSpec), b.(*TargetSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*kops.SessionManagerSpec)(nil), (*SessionManagerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SessionManagerSpec_To_v1alpha2_SessionManagerSpec(a.(*kops.SessionManagerSpec), b.(*SessionManagerSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*TerraformSpec)(nil), (*kops.TerraformSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(a.(*TerraformSpec), b.(*kops.TerraformSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*ShieldedVMSpec)(nil), (*kops.ShieldedVMSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ShieldedVMSpec_To_kops_ShieldedVMSpec(a.(*ShieldedVMSpec), b.(*kops.ShieldedVMSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ShieldedVMSpec)(nil), (*ShieldedVMSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ShieldedVMSpec_To_v1alpha2_ShieldedVMSpec(a.(*kops.ShieldedVMSpec), b.(*ShieldedVMSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SnapshotControllerConfig)(nil), (*kops.SnapshotControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SnapshotControllerConfig_To_kops_SnapshotControllerConfig(a.(*SnapshotControllerConfig), b.(*kops.SnapshotControllerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SnapshotControllerConfig)(nil), (*SnapshotControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(a.(*kops.SnapshotControllerConfig), b.(*SnapshotControllerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StateVolumesSpec)(nil), (*kops.StateVolumesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_StateVolumesSpec_To_kops_StateVolumesSpec(a.(*StateVolumesSpec), b.(*kops.StateVolumesSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.StateVolumesSpec)(nil), (*StateVolumesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_StateVolumesSpec_To_v1alpha2_StateVolumesSpec(a.(*kops.StateVolumesSpec), b.(*StateVolumesSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SwapSpec)(nil), (*kops.SwapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SwapSpec_To_kops_SwapSpec(a.(*SwapSpec), b.(*kops.SwapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SwapSpec)(nil), (*SwapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SwapSpec_To_v1alpha2_SwapSpec(a.(*kops.SwapSpec), b.(*SwapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TargetSpec)(nil), (*TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TargetSpec_To_v1alpha2_TargetSpec(a.(*kops.TargetSpec), b.(*TargetSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TerraformSpec)(nil), (*kops.TerraformSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(a.(*TerraformSpec), b.(*kops.TerraformSpec), scope)
 	}); err != nil {
 		return err
 	}
@@ -1073,6 +1533,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*VerticalPodAutoscalerConfig)(nil), (*kops.VerticalPodAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_VerticalPodAutoscalerConfig_To_kops_VerticalPodAutoscalerConfig(a.(*VerticalPodAutoscalerConfig), b.(*kops.VerticalPodAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.VerticalPodAutoscalerConfig)(nil), (*VerticalPodAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_VerticalPodAutoscalerConfig_To_v1alpha2_VerticalPodAutoscalerConfig(a.(*kops.VerticalPodAutoscalerConfig), b.(*VerticalPodAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VolumeMountSpec)(nil), (*kops.VolumeMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(a.(*VolumeMountSpec), b.(*kops.VolumeMountSpec), scope)
 	}); err != nil {
@@ -1120,6 +1590,8 @@ func autoConvert_v1alpha2_AWSEBSCSIDriver_To_kops_AWSEBSCSIDriver(in *AWSEBSCSID
 	out.Enabled = in.Enabled
 	out.Version = in.Version
 	out.VolumeAttachLimit = in.VolumeAttachLimit
+	out.ExtraTags = in.ExtraTags
+	out.TagVolumesWithClaim = in.TagVolumesWithClaim
 	return nil
 }
 
@@ -1132,6 +1604,8 @@ func autoConvert_kops_AWSEBSCSIDriver_To_v1alpha2_AWSEBSCSIDriver(in *kops.AWSEB
 	out.Enabled = in.Enabled
 	out.Version = in.Version
 	out.VolumeAttachLimit = in.VolumeAttachLimit
+	out.ExtraTags = in.ExtraTags
+	out.TagVolumesWithClaim = in.TagVolumesWithClaim
 	return nil
 }
 
@@ -1143,6 +1617,8 @@ func Convert_kops_AWSEBSCSIDriver_To_v1alpha2_AWSEBSCSIDriver(in *kops.AWSEBSCSI
 func autoConvert_v1alpha2_AWSLoadBalancerControllerConfig_To_kops_AWSLoadBalancerControllerConfig(in *AWSLoadBalancerControllerConfig, out *kops.AWSLoadBalancerControllerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Version = in.Version
+	out.IngressClass = in.IngressClass
+	out.DefaultIngressClass = in.DefaultIngressClass
 	return nil
 }
 
@@ -1154,6 +1630,8 @@ func Convert_v1alpha2_AWSLoadBalancerControllerConfig_To_kops_AWSLoadBalancerCon
 func autoConvert_kops_AWSLoadBalancerControllerConfig_To_v1alpha2_AWSLoadBalancerControllerConfig(in *kops.AWSLoadBalancerControllerConfig, out *AWSLoadBalancerControllerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Version = in.Version
+	out.IngressClass = in.IngressClass
+	out.DefaultIngressClass = in.DefaultIngressClass
 	return nil
 }
 
@@ -1320,10 +1798,81 @@ func Convert_kops_AmazonVPCNetworkingSpec_To_v1alpha2_AmazonVPCNetworkingSpec(in
 	return autoConvert_kops_AmazonVPCNetworkingSpec_To_v1alpha2_AmazonVPCNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_AppArmorProfile_To_kops_AppArmorProfile(in *AppArmorProfile, out *kops.AppArmorProfile, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Content = in.Content
+	return nil
+}
+
+// Convert_v1alpha2_AppArmorProfile_To_kops_AppArmorProfile is an autogenerated conversion function.
+func Convert_v1alpha2_AppArmorProfile_To_kops_AppArmorProfile(in *AppArmorProfile, out *kops.AppArmorProfile, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AppArmorProfile_To_kops_AppArmorProfile(in, out, s)
+}
+
+func autoConvert_kops_AppArmorProfile_To_v1alpha2_AppArmorProfile(in *kops.AppArmorProfile, out *AppArmorProfile, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Content = in.Content
+	return nil
+}
+
+// Convert_kops_AppArmorProfile_To_v1alpha2_AppArmorProfile is an autogenerated conversion function.
+func Convert_kops_AppArmorProfile_To_v1alpha2_AppArmorProfile(in *kops.AppArmorProfile, out *AppArmorProfile, s conversion.Scope) error {
+	return autoConvert_kops_AppArmorProfile_To_v1alpha2_AppArmorProfile(in, out, s)
+}
+
+func autoConvert_v1alpha2_AppArmorSpec_To_kops_AppArmorSpec(in *AppArmorSpec, out *kops.AppArmorSpec, s conversion.Scope) error {
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]kops.AppArmorProfile, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_AppArmorProfile_To_kops_AppArmorProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Profiles = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_AppArmorSpec_To_kops_AppArmorSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AppArmorSpec_To_kops_AppArmorSpec(in *AppArmorSpec, out *kops.AppArmorSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AppArmorSpec_To_kops_AppArmorSpec(in, out, s)
+}
+
+func autoConvert_kops_AppArmorSpec_To_v1alpha2_AppArmorSpec(in *kops.AppArmorSpec, out *AppArmorSpec, s conversion.Scope) error {
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]AppArmorProfile, len(*in))
+		for i := range *in {
+			if err := Convert_kops_AppArmorProfile_To_v1alpha2_AppArmorProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Profiles = nil
+	}
+	return nil
+}
+
+// Convert_kops_AppArmorSpec_To_v1alpha2_AppArmorSpec is an autogenerated conversion function.
+func Convert_kops_AppArmorSpec_To_v1alpha2_AppArmorSpec(in *kops.AppArmorSpec, out *AppArmorSpec, s conversion.Scope) error {
+	return autoConvert_kops_AppArmorSpec_To_v1alpha2_AppArmorSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_Assets_To_kops_Assets(in *Assets, out *kops.Assets, s conversion.Scope) error {
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(kops.ImageVerificationSpec)
+		if err := Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ImageVerification = nil
+	}
 	return nil
 }
 
@@ -1336,6 +1885,15 @@ func autoConvert_kops_Assets_To_v1alpha2_Assets(in *kops.Assets, out *Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerificationSpec)
+		if err := Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ImageVerification = nil
+	}
 	return nil
 }
 
@@ -1363,6 +1921,15 @@ func autoConvert_v1alpha2_AuthenticationSpec_To_kops_AuthenticationSpec(in *Auth
 	} else {
 		out.Aws = nil
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(kops.OIDCAuthenticationSpec)
+		if err := Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OIDC = nil
+	}
 	return nil
 }
 
@@ -1390,6 +1957,15 @@ func autoConvert_kops_AuthenticationSpec_To_v1alpha2_AuthenticationSpec(in *kops
 	} else {
 		out.Aws = nil
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCAuthenticationSpec)
+		if err := Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OIDC = nil
+	}
 	return nil
 }
 
@@ -1514,6 +2090,19 @@ func Convert_kops_AzureConfiguration_To_v1alpha2_AzureConfiguration(in *kops.Azu
 
 func autoConvert_v1alpha2_BastionLoadBalancerSpec_To_kops_BastionLoadBalancerSpec(in *BastionLoadBalancerSpec, out *kops.BastionLoadBalancerSpec, s conversion.Scope) error {
 	out.AdditionalSecurityGroups = in.AdditionalSecurityGroups
+	out.Class = kops.LoadBalancerClass(in.Class)
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]kops.LoadBalancerSubnetSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_LoadBalancerSubnetSpec_To_kops_LoadBalancerSubnetSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Subnets = nil
+	}
+	out.AllocateElasticIPs = in.AllocateElasticIPs
 	return nil
 }
 
@@ -1524,6 +2113,19 @@ func Convert_v1alpha2_BastionLoadBalancerSpec_To_kops_BastionLoadBalancerSpec(in
 
 func autoConvert_kops_BastionLoadBalancerSpec_To_v1alpha2_BastionLoadBalancerSpec(in *kops.BastionLoadBalancerSpec, out *BastionLoadBalancerSpec, s conversion.Scope) error {
 	out.AdditionalSecurityGroups = in.AdditionalSecurityGroups
+	out.Class = LoadBalancerClass(in.Class)
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]LoadBalancerSubnetSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_LoadBalancerSubnetSpec_To_v1alpha2_LoadBalancerSubnetSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Subnets = nil
+	}
+	out.AllocateElasticIPs = in.AllocateElasticIPs
 	return nil
 }
 
@@ -2005,6 +2607,15 @@ func autoConvert_v1alpha2_CloudConfiguration_To_kops_CloudConfiguration(in *Clou
 	} else {
 		out.Azure = nil
 	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(kops.OCIConfiguration)
+		if err := Convert_v1alpha2_OCIConfiguration_To_kops_OCIConfiguration(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OCI = nil
+	}
 	if in.AWSEBSCSIDriver != nil {
 		in, out := &in.AWSEBSCSIDriver, &out.AWSEBSCSIDriver
 		*out = new(kops.AWSEBSCSIDriver)
@@ -2014,6 +2625,15 @@ func autoConvert_v1alpha2_CloudConfiguration_To_kops_CloudConfiguration(in *Clou
 	} else {
 		out.AWSEBSCSIDriver = nil
 	}
+	if in.EBSDefaultEncryption != nil {
+		in, out := &in.EBSDefaultEncryption, &out.EBSDefaultEncryption
+		*out = new(kops.EBSDefaultEncryptionSpec)
+		if err := Convert_v1alpha2_EBSDefaultEncryptionSpec_To_kops_EBSDefaultEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.EBSDefaultEncryption = nil
+	}
 	return nil
 }
 
@@ -2057,6 +2677,15 @@ func autoConvert_kops_CloudConfiguration_To_v1alpha2_CloudConfiguration(in *kops
 	} else {
 		out.Azure = nil
 	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIConfiguration)
+		if err := Convert_kops_OCIConfiguration_To_v1alpha2_OCIConfiguration(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OCI = nil
+	}
 	if in.AWSEBSCSIDriver != nil {
 		in, out := &in.AWSEBSCSIDriver, &out.AWSEBSCSIDriver
 		*out = new(AWSEBSCSIDriver)
@@ -2066,6 +2695,15 @@ func autoConvert_kops_CloudConfiguration_To_v1alpha2_CloudConfiguration(in *kops
 	} else {
 		out.AWSEBSCSIDriver = nil
 	}
+	if in.EBSDefaultEncryption != nil {
+		in, out := &in.EBSDefaultEncryption, &out.EBSDefaultEncryption
+		*out = new(EBSDefaultEncryptionSpec)
+		if err := Convert_kops_EBSDefaultEncryptionSpec_To_v1alpha2_EBSDefaultEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.EBSDefaultEncryption = nil
+	}
 	return nil
 }
 
@@ -2094,6 +2732,9 @@ func autoConvert_v1alpha2_CloudControllerManagerConfig_To_kops_CloudControllerMa
 		out.LeaderElection = nil
 	}
 	out.UseServiceAccountCredentials = in.UseServiceAccountCredentials
+	out.ConcurrentServiceSyncs = in.ConcurrentServiceSyncs
+	out.FeatureGates = in.FeatureGates
+	out.EnableLeaderMigration = in.EnableLeaderMigration
 	return nil
 }
 
@@ -2122,6 +2763,9 @@ func autoConvert_kops_CloudControllerManagerConfig_To_v1alpha2_CloudControllerMa
 		out.LeaderElection = nil
 	}
 	out.UseServiceAccountCredentials = in.UseServiceAccountCredentials
+	out.ConcurrentServiceSyncs = in.ConcurrentServiceSyncs
+	out.FeatureGates = in.FeatureGates
+	out.EnableLeaderMigration = in.EnableLeaderMigration
 	return nil
 }
 
@@ -2130,6 +2774,46 @@ func Convert_kops_CloudControllerManagerConfig_To_v1alpha2_CloudControllerManage
 	return autoConvert_kops_CloudControllerManagerConfig_To_v1alpha2_CloudControllerManagerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_CloudLabelPolicy_To_kops_CloudLabelPolicy(in *CloudLabelPolicy, out *kops.CloudLabelPolicy, s conversion.Scope) error {
+	out.Labels = in.Labels
+	if in.ResourceTypes != nil {
+		in, out := &in.ResourceTypes, &out.ResourceTypes
+		*out = make([]kops.CloudResourceType, len(*in))
+		for i := range *in {
+			(*out)[i] = kops.CloudResourceType((*in)[i])
+		}
+	} else {
+		out.ResourceTypes = nil
+	}
+	out.Subnets = in.Subnets
+	return nil
+}
+
+// Convert_v1alpha2_CloudLabelPolicy_To_kops_CloudLabelPolicy is an autogenerated conversion function.
+func Convert_v1alpha2_CloudLabelPolicy_To_kops_CloudLabelPolicy(in *CloudLabelPolicy, out *kops.CloudLabelPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CloudLabelPolicy_To_kops_CloudLabelPolicy(in, out, s)
+}
+
+func autoConvert_kops_CloudLabelPolicy_To_v1alpha2_CloudLabelPolicy(in *kops.CloudLabelPolicy, out *CloudLabelPolicy, s conversion.Scope) error {
+	out.Labels = in.Labels
+	if in.ResourceTypes != nil {
+		in, out := &in.ResourceTypes, &out.ResourceTypes
+		*out = make([]CloudResourceType, len(*in))
+		for i := range *in {
+			(*out)[i] = CloudResourceType((*in)[i])
+		}
+	} else {
+		out.ResourceTypes = nil
+	}
+	out.Subnets = in.Subnets
+	return nil
+}
+
+// Convert_kops_CloudLabelPolicy_To_v1alpha2_CloudLabelPolicy is an autogenerated conversion function.
+func Convert_kops_CloudLabelPolicy_To_v1alpha2_CloudLabelPolicy(in *kops.CloudLabelPolicy, out *CloudLabelPolicy, s conversion.Scope) error {
+	return autoConvert_kops_CloudLabelPolicy_To_v1alpha2_CloudLabelPolicy(in, out, s)
+}
+
 func autoConvert_v1alpha2_Cluster_To_kops_Cluster(in *Cluster, out *kops.Cluster, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -2280,6 +2964,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	out.MasterInternalName = in.MasterInternalName
 	out.NetworkCIDR = in.NetworkCIDR
 	out.AdditionalNetworkCIDRs = in.AdditionalNetworkCIDRs
+	if in.NetworkCIDRAllocation != nil {
+		in, out := &in.NetworkCIDRAllocation, &out.NetworkCIDRAllocation
+		*out = new(kops.NetworkCIDRAllocationSpec)
+		if err := Convert_v1alpha2_NetworkCIDRAllocationSpec_To_kops_NetworkCIDRAllocationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NetworkCIDRAllocation = nil
+	}
 	out.NetworkID = in.NetworkID
 	if in.Topology != nil {
 		in, out := &in.Topology, &out.Topology
@@ -2347,6 +3040,7 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.EtcdClusters = nil
 	}
+	out.FeatureGates = in.FeatureGates
 	if in.Containerd != nil {
 		in, out := &in.Containerd, &out.Containerd
 		*out = new(kops.ContainerdConfig)
@@ -2555,6 +3249,17 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.NodeAuthorization = nil
 	}
 	out.CloudLabels = in.CloudLabels
+	if in.CloudLabelPolicies != nil {
+		in, out := &in.CloudLabelPolicies, &out.CloudLabelPolicies
+		*out = make([]kops.CloudLabelPolicy, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_CloudLabelPolicy_To_kops_CloudLabelPolicy(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.CloudLabelPolicies = nil
+	}
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
 		*out = make([]kops.HookSpec, len(*in))
@@ -2566,6 +3271,17 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Hooks = nil
 	}
+	if in.NodePlugins != nil {
+		in, out := &in.NodePlugins, &out.NodePlugins
+		*out = make([]kops.NodePluginSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_NodePluginSpec_To_kops_NodePluginSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.NodePlugins = nil
+	}
 	if in.Assets != nil {
 		in, out := &in.Assets, &out.Assets
 		*out = new(kops.Assets)
@@ -2615,6 +3331,24 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.ClusterAutoscaler = nil
 	}
+	if in.Descheduler != nil {
+		in, out := &in.Descheduler, &out.Descheduler
+		*out = new(kops.DeschedulerConfig)
+		if err := Convert_v1alpha2_DeschedulerConfig_To_kops_DeschedulerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Descheduler = nil
+	}
+	if in.VerticalPodAutoscaler != nil {
+		in, out := &in.VerticalPodAutoscaler, &out.VerticalPodAutoscaler
+		*out = new(kops.VerticalPodAutoscalerConfig)
+		if err := Convert_v1alpha2_VerticalPodAutoscalerConfig_To_kops_VerticalPodAutoscalerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.VerticalPodAutoscaler = nil
+	}
 	if in.WarmPool != nil {
 		in, out := &in.WarmPool, &out.WarmPool
 		*out = new(kops.WarmPoolSpec)
@@ -2642,6 +3376,109 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.SnapshotController = nil
 	}
+	if in.ContinuousValidation != nil {
+		in, out := &in.ContinuousValidation, &out.ContinuousValidation
+		*out = new(kops.ContinuousValidationSpec)
+		if err := Convert_v1alpha2_ContinuousValidationSpec_To_kops_ContinuousValidationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ContinuousValidation = nil
+	}
+	if in.OSPatching != nil {
+		in, out := &in.OSPatching, &out.OSPatching
+		*out = new(kops.OSPatchingSpec)
+		if err := Convert_v1alpha2_OSPatchingSpec_To_kops_OSPatchingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OSPatching = nil
+	}
+	if in.NodeLabelReconciliation != nil {
+		in, out := &in.NodeLabelReconciliation, &out.NodeLabelReconciliation
+		*out = new(kops.NodeLabelReconciliationSpec)
+		if err := Convert_v1alpha2_NodeLabelReconciliationSpec_To_kops_NodeLabelReconciliationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeLabelReconciliation = nil
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(kops.HibernationSpec)
+		if err := Convert_v1alpha2_HibernationSpec_To_kops_HibernationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Hibernation = nil
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(kops.MaintenanceWindowSpec)
+		if err := Convert_v1alpha2_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MaintenanceWindow = nil
+	}
+	if in.InstanceGroupDefaults != nil {
+		in, out := &in.InstanceGroupDefaults, &out.InstanceGroupDefaults
+		*out = new(kops.InstanceGroupSpec)
+		if err := Convert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceGroupDefaults = nil
+	}
+	if in.SessionManager != nil {
+		in, out := &in.SessionManager, &out.SessionManager
+		*out = new(kops.SessionManagerSpec)
+		if err := Convert_v1alpha2_SessionManagerSpec_To_kops_SessionManagerSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SessionManager = nil
+	}
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(kops.SSHSpec)
+		if err := Convert_v1alpha2_SSHSpec_To_kops_SSHSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSH = nil
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(kops.EventsSpec)
+		if err := Convert_v1alpha2_EventsSpec_To_kops_EventsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Events = nil
+	}
+	out.SecurityProfile = in.SecurityProfile
+	if in.PodSecurityAdmission != nil {
+		in, out := &in.PodSecurityAdmission, &out.PodSecurityAdmission
+		*out = new(kops.PodSecurityAdmissionSpec)
+		if err := Convert_v1alpha2_PodSecurityAdmissionSpec_To_kops_PodSecurityAdmissionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PodSecurityAdmission = nil
+	}
+	out.HostFIPSMode = in.HostFIPSMode
+	if in.EgressGateways != nil {
+		in, out := &in.EgressGateways, &out.EgressGateways
+		*out = make([]kops.EgressGatewaySpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_EgressGatewaySpec_To_kops_EgressGatewaySpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.EgressGateways = nil
+	}
 	return nil
 }
 
@@ -2692,6 +3529,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	out.MasterInternalName = in.MasterInternalName
 	out.NetworkCIDR = in.NetworkCIDR
 	out.AdditionalNetworkCIDRs = in.AdditionalNetworkCIDRs
+	if in.NetworkCIDRAllocation != nil {
+		in, out := &in.NetworkCIDRAllocation, &out.NetworkCIDRAllocation
+		*out = new(NetworkCIDRAllocationSpec)
+		if err := Convert_kops_NetworkCIDRAllocationSpec_To_v1alpha2_NetworkCIDRAllocationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NetworkCIDRAllocation = nil
+	}
 	out.NetworkID = in.NetworkID
 	if in.Topology != nil {
 		in, out := &in.Topology, &out.Topology
@@ -2759,6 +3605,7 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.EtcdClusters = nil
 	}
+	out.FeatureGates = in.FeatureGates
 	if in.Containerd != nil {
 		in, out := &in.Containerd, &out.Containerd
 		*out = new(ContainerdConfig)
@@ -2967,6 +3814,17 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 		out.NodeAuthorization = nil
 	}
 	out.CloudLabels = in.CloudLabels
+	if in.CloudLabelPolicies != nil {
+		in, out := &in.CloudLabelPolicies, &out.CloudLabelPolicies
+		*out = make([]CloudLabelPolicy, len(*in))
+		for i := range *in {
+			if err := Convert_kops_CloudLabelPolicy_To_v1alpha2_CloudLabelPolicy(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.CloudLabelPolicies = nil
+	}
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
 		*out = make([]HookSpec, len(*in))
@@ -2978,6 +3836,17 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Hooks = nil
 	}
+	if in.NodePlugins != nil {
+		in, out := &in.NodePlugins, &out.NodePlugins
+		*out = make([]NodePluginSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_NodePluginSpec_To_v1alpha2_NodePluginSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.NodePlugins = nil
+	}
 	if in.Assets != nil {
 		in, out := &in.Assets, &out.Assets
 		*out = new(Assets)
@@ -3027,10 +3896,28 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.ClusterAutoscaler = nil
 	}
-	if in.WarmPool != nil {
-		in, out := &in.WarmPool, &out.WarmPool
-		*out = new(WarmPoolSpec)
-		if err := Convert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec(*in, *out, s); err != nil {
+	if in.Descheduler != nil {
+		in, out := &in.Descheduler, &out.Descheduler
+		*out = new(DeschedulerConfig)
+		if err := Convert_kops_DeschedulerConfig_To_v1alpha2_DeschedulerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Descheduler = nil
+	}
+	if in.VerticalPodAutoscaler != nil {
+		in, out := &in.VerticalPodAutoscaler, &out.VerticalPodAutoscaler
+		*out = new(VerticalPodAutoscalerConfig)
+		if err := Convert_kops_VerticalPodAutoscalerConfig_To_v1alpha2_VerticalPodAutoscalerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.VerticalPodAutoscaler = nil
+	}
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(WarmPoolSpec)
+		if err := Convert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec(*in, *out, s); err != nil {
 			return err
 		}
 	} else {
@@ -3054,6 +3941,109 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.SnapshotController = nil
 	}
+	if in.ContinuousValidation != nil {
+		in, out := &in.ContinuousValidation, &out.ContinuousValidation
+		*out = new(ContinuousValidationSpec)
+		if err := Convert_kops_ContinuousValidationSpec_To_v1alpha2_ContinuousValidationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ContinuousValidation = nil
+	}
+	if in.OSPatching != nil {
+		in, out := &in.OSPatching, &out.OSPatching
+		*out = new(OSPatchingSpec)
+		if err := Convert_kops_OSPatchingSpec_To_v1alpha2_OSPatchingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OSPatching = nil
+	}
+	if in.NodeLabelReconciliation != nil {
+		in, out := &in.NodeLabelReconciliation, &out.NodeLabelReconciliation
+		*out = new(NodeLabelReconciliationSpec)
+		if err := Convert_kops_NodeLabelReconciliationSpec_To_v1alpha2_NodeLabelReconciliationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeLabelReconciliation = nil
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(HibernationSpec)
+		if err := Convert_kops_HibernationSpec_To_v1alpha2_HibernationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Hibernation = nil
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowSpec)
+		if err := Convert_kops_MaintenanceWindowSpec_To_v1alpha2_MaintenanceWindowSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MaintenanceWindow = nil
+	}
+	if in.InstanceGroupDefaults != nil {
+		in, out := &in.InstanceGroupDefaults, &out.InstanceGroupDefaults
+		*out = new(InstanceGroupSpec)
+		if err := Convert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceGroupDefaults = nil
+	}
+	if in.SessionManager != nil {
+		in, out := &in.SessionManager, &out.SessionManager
+		*out = new(SessionManagerSpec)
+		if err := Convert_kops_SessionManagerSpec_To_v1alpha2_SessionManagerSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SessionManager = nil
+	}
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(SSHSpec)
+		if err := Convert_kops_SSHSpec_To_v1alpha2_SSHSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSH = nil
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(EventsSpec)
+		if err := Convert_kops_EventsSpec_To_v1alpha2_EventsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Events = nil
+	}
+	out.SecurityProfile = in.SecurityProfile
+	if in.PodSecurityAdmission != nil {
+		in, out := &in.PodSecurityAdmission, &out.PodSecurityAdmission
+		*out = new(PodSecurityAdmissionSpec)
+		if err := Convert_kops_PodSecurityAdmissionSpec_To_v1alpha2_PodSecurityAdmissionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PodSecurityAdmission = nil
+	}
+	out.HostFIPSMode = in.HostFIPSMode
+	if in.EgressGateways != nil {
+		in, out := &in.EgressGateways, &out.EgressGateways
+		*out = make([]EgressGatewaySpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_EgressGatewaySpec_To_v1alpha2_EgressGatewaySpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.EgressGateways = nil
+	}
 	return nil
 }
 
@@ -3100,8 +4090,18 @@ func Convert_kops_ClusterSubnetSpec_To_v1alpha2_ClusterSubnetSpec(in *kops.Clust
 
 func autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *ContainerdConfig, out *kops.ContainerdConfig, s conversion.Scope) error {
 	out.Address = in.Address
+	out.CgroupDriver = in.CgroupDriver
 	out.ConfigOverride = in.ConfigOverride
 	out.LogLevel = in.LogLevel
+	if in.NRI != nil {
+		in, out := &in.NRI, &out.NRI
+		*out = new(kops.ContainerdNRIConfig)
+		if err := Convert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NRI = nil
+	}
 	if in.Packages != nil {
 		in, out := &in.Packages, &out.Packages
 		*out = new(kops.PackagesConfig)
@@ -3111,8 +4111,22 @@ func autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *Containe
 	} else {
 		out.Packages = nil
 	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make(map[string]kops.ContainerdRegistryConfig, len(*in))
+		for key, val := range *in {
+			newVal := new(kops.ContainerdRegistryConfig)
+			if err := Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Registries = nil
+	}
 	out.RegistryMirrors = in.RegistryMirrors
 	out.Root = in.Root
+	out.SandboxImage = in.SandboxImage
 	out.SkipInstall = in.SkipInstall
 	out.State = in.State
 	out.Version = in.Version
@@ -3126,8 +4140,18 @@ func Convert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *ContainerdCo
 
 func autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.ContainerdConfig, out *ContainerdConfig, s conversion.Scope) error {
 	out.Address = in.Address
+	out.CgroupDriver = in.CgroupDriver
 	out.ConfigOverride = in.ConfigOverride
 	out.LogLevel = in.LogLevel
+	if in.NRI != nil {
+		in, out := &in.NRI, &out.NRI
+		*out = new(ContainerdNRIConfig)
+		if err := Convert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NRI = nil
+	}
 	if in.Packages != nil {
 		in, out := &in.Packages, &out.Packages
 		*out = new(PackagesConfig)
@@ -3137,8 +4161,22 @@ func autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.Con
 	} else {
 		out.Packages = nil
 	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make(map[string]ContainerdRegistryConfig, len(*in))
+		for key, val := range *in {
+			newVal := new(ContainerdRegistryConfig)
+			if err := Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Registries = nil
+	}
 	out.RegistryMirrors = in.RegistryMirrors
 	out.Root = in.Root
+	out.SandboxImage = in.SandboxImage
 	out.SkipInstall = in.SkipInstall
 	out.State = in.State
 	out.Version = in.Version
@@ -3150,6 +4188,72 @@ func Convert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.Contain
 	return autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(in *ContainerdNRIConfig, out *kops.ContainerdNRIConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.PluginPath = in.PluginPath
+	out.PluginConfigPath = in.PluginConfigPath
+	return nil
+}
+
+// Convert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(in *ContainerdNRIConfig, out *kops.ContainerdNRIConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(in, out, s)
+}
+
+func autoConvert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(in *kops.ContainerdNRIConfig, out *ContainerdNRIConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.PluginPath = in.PluginPath
+	out.PluginConfigPath = in.PluginConfigPath
+	return nil
+}
+
+// Convert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig is an autogenerated conversion function.
+func Convert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(in *kops.ContainerdNRIConfig, out *ContainerdNRIConfig, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(in *ContainerdRegistryConfig, out *kops.ContainerdRegistryConfig, s conversion.Scope) error {
+	out.Mirrors = in.Mirrors
+	return nil
+}
+
+// Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(in *ContainerdRegistryConfig, out *kops.ContainerdRegistryConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(in, out, s)
+}
+
+func autoConvert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(in *kops.ContainerdRegistryConfig, out *ContainerdRegistryConfig, s conversion.Scope) error {
+	out.Mirrors = in.Mirrors
+	return nil
+}
+
+// Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig is an autogenerated conversion function.
+func Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(in *kops.ContainerdRegistryConfig, out *ContainerdRegistryConfig, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_ContinuousValidationSpec_To_kops_ContinuousValidationSpec(in *ContinuousValidationSpec, out *kops.ContinuousValidationSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Checks = in.Checks
+	return nil
+}
+
+// Convert_v1alpha2_ContinuousValidationSpec_To_kops_ContinuousValidationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ContinuousValidationSpec_To_kops_ContinuousValidationSpec(in *ContinuousValidationSpec, out *kops.ContinuousValidationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContinuousValidationSpec_To_kops_ContinuousValidationSpec(in, out, s)
+}
+
+func autoConvert_kops_ContinuousValidationSpec_To_v1alpha2_ContinuousValidationSpec(in *kops.ContinuousValidationSpec, out *ContinuousValidationSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Checks = in.Checks
+	return nil
+}
+
+// Convert_kops_ContinuousValidationSpec_To_v1alpha2_ContinuousValidationSpec is an autogenerated conversion function.
+func Convert_kops_ContinuousValidationSpec_To_v1alpha2_ContinuousValidationSpec(in *kops.ContinuousValidationSpec, out *ContinuousValidationSpec, s conversion.Scope) error {
+	return autoConvert_kops_ContinuousValidationSpec_To_v1alpha2_ContinuousValidationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in *DNSAccessSpec, out *kops.DNSAccessSpec, s conversion.Scope) error {
 	return nil
 }
@@ -3258,6 +4362,102 @@ func Convert_kops_DNSSpec_To_v1alpha2_DNSSpec(in *kops.DNSSpec, out *DNSSpec, s
 	return autoConvert_kops_DNSSpec_To_v1alpha2_DNSSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_DeschedulerConfig_To_kops_DeschedulerConfig(in *DeschedulerConfig, out *kops.DeschedulerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Image = in.Image
+	out.Schedule = in.Schedule
+	out.Profiles = in.Profiles
+	if in.NodeUtilization != nil {
+		in, out := &in.NodeUtilization, &out.NodeUtilization
+		*out = new(kops.DeschedulerNodeUtilization)
+		if err := Convert_v1alpha2_DeschedulerNodeUtilization_To_kops_DeschedulerNodeUtilization(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeUtilization = nil
+	}
+	out.MemoryRequest = in.MemoryRequest
+	out.CPURequest = in.CPURequest
+	return nil
+}
+
+// Convert_v1alpha2_DeschedulerConfig_To_kops_DeschedulerConfig is an autogenerated conversion function.
+func Convert_v1alpha2_DeschedulerConfig_To_kops_DeschedulerConfig(in *DeschedulerConfig, out *kops.DeschedulerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DeschedulerConfig_To_kops_DeschedulerConfig(in, out, s)
+}
+
+func autoConvert_kops_DeschedulerConfig_To_v1alpha2_DeschedulerConfig(in *kops.DeschedulerConfig, out *DeschedulerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Image = in.Image
+	out.Schedule = in.Schedule
+	out.Profiles = in.Profiles
+	if in.NodeUtilization != nil {
+		in, out := &in.NodeUtilization, &out.NodeUtilization
+		*out = new(DeschedulerNodeUtilization)
+		if err := Convert_kops_DeschedulerNodeUtilization_To_v1alpha2_DeschedulerNodeUtilization(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeUtilization = nil
+	}
+	out.MemoryRequest = in.MemoryRequest
+	out.CPURequest = in.CPURequest
+	return nil
+}
+
+// Convert_kops_DeschedulerConfig_To_v1alpha2_DeschedulerConfig is an autogenerated conversion function.
+func Convert_kops_DeschedulerConfig_To_v1alpha2_DeschedulerConfig(in *kops.DeschedulerConfig, out *DeschedulerConfig, s conversion.Scope) error {
+	return autoConvert_kops_DeschedulerConfig_To_v1alpha2_DeschedulerConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_DeschedulerNodeUtilization_To_kops_DeschedulerNodeUtilization(in *DeschedulerNodeUtilization, out *kops.DeschedulerNodeUtilization, s conversion.Scope) error {
+	out.Thresholds = in.Thresholds
+	out.TargetThresholds = in.TargetThresholds
+	return nil
+}
+
+// Convert_v1alpha2_DeschedulerNodeUtilization_To_kops_DeschedulerNodeUtilization is an autogenerated conversion function.
+func Convert_v1alpha2_DeschedulerNodeUtilization_To_kops_DeschedulerNodeUtilization(in *DeschedulerNodeUtilization, out *kops.DeschedulerNodeUtilization, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DeschedulerNodeUtilization_To_kops_DeschedulerNodeUtilization(in, out, s)
+}
+
+func autoConvert_kops_DeschedulerNodeUtilization_To_v1alpha2_DeschedulerNodeUtilization(in *kops.DeschedulerNodeUtilization, out *DeschedulerNodeUtilization, s conversion.Scope) error {
+	out.Thresholds = in.Thresholds
+	out.TargetThresholds = in.TargetThresholds
+	return nil
+}
+
+// Convert_kops_DeschedulerNodeUtilization_To_v1alpha2_DeschedulerNodeUtilization is an autogenerated conversion function.
+func Convert_kops_DeschedulerNodeUtilization_To_v1alpha2_DeschedulerNodeUtilization(in *kops.DeschedulerNodeUtilization, out *DeschedulerNodeUtilization, s conversion.Scope) error {
+	return autoConvert_kops_DeschedulerNodeUtilization_To_v1alpha2_DeschedulerNodeUtilization(in, out, s)
+}
+
+func autoConvert_v1alpha2_DexSpec_To_kops_DexSpec(in *DexSpec, out *kops.DexSpec, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Replicas = in.Replicas
+	out.Connectors = in.Connectors
+	out.RedirectURIs = in.RedirectURIs
+	return nil
+}
+
+// Convert_v1alpha2_DexSpec_To_kops_DexSpec is an autogenerated conversion function.
+func Convert_v1alpha2_DexSpec_To_kops_DexSpec(in *DexSpec, out *kops.DexSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DexSpec_To_kops_DexSpec(in, out, s)
+}
+
+func autoConvert_kops_DexSpec_To_v1alpha2_DexSpec(in *kops.DexSpec, out *DexSpec, s conversion.Scope) error {
+	out.Image = in.Image
+	out.Replicas = in.Replicas
+	out.Connectors = in.Connectors
+	out.RedirectURIs = in.RedirectURIs
+	return nil
+}
+
+// Convert_kops_DexSpec_To_v1alpha2_DexSpec is an autogenerated conversion function.
+func Convert_kops_DexSpec_To_v1alpha2_DexSpec(in *kops.DexSpec, out *DexSpec, s conversion.Scope) error {
+	return autoConvert_kops_DexSpec_To_v1alpha2_DexSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_DockerConfig_To_kops_DockerConfig(in *DockerConfig, out *kops.DockerConfig, s conversion.Scope) error {
 	out.AuthorizationPlugins = in.AuthorizationPlugins
 	out.Bridge = in.Bridge
@@ -3352,6 +4552,82 @@ func Convert_kops_DockerConfig_To_v1alpha2_DockerConfig(in *kops.DockerConfig, o
 	return autoConvert_kops_DockerConfig_To_v1alpha2_DockerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_DrainSpec_To_kops_DrainSpec(in *DrainSpec, out *kops.DrainSpec, s conversion.Scope) error {
+	out.Timeout = in.Timeout
+	out.GracePeriod = in.GracePeriod
+	out.SkipWaitForDeleteTimeout = in.SkipWaitForDeleteTimeout
+	out.PodDisruptionBudgetTimeout = in.PodDisruptionBudgetTimeout
+	out.PodDisruptionBudgetTimeoutAction = kops.PodDisruptionBudgetTimeoutAction(in.PodDisruptionBudgetTimeoutAction)
+	return nil
+}
+
+// Convert_v1alpha2_DrainSpec_To_kops_DrainSpec is an autogenerated conversion function.
+func Convert_v1alpha2_DrainSpec_To_kops_DrainSpec(in *DrainSpec, out *kops.DrainSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DrainSpec_To_kops_DrainSpec(in, out, s)
+}
+
+func autoConvert_kops_DrainSpec_To_v1alpha2_DrainSpec(in *kops.DrainSpec, out *DrainSpec, s conversion.Scope) error {
+	out.Timeout = in.Timeout
+	out.GracePeriod = in.GracePeriod
+	out.SkipWaitForDeleteTimeout = in.SkipWaitForDeleteTimeout
+	out.PodDisruptionBudgetTimeout = in.PodDisruptionBudgetTimeout
+	out.PodDisruptionBudgetTimeoutAction = PodDisruptionBudgetTimeoutAction(in.PodDisruptionBudgetTimeoutAction)
+	return nil
+}
+
+// Convert_kops_DrainSpec_To_v1alpha2_DrainSpec is an autogenerated conversion function.
+func Convert_kops_DrainSpec_To_v1alpha2_DrainSpec(in *kops.DrainSpec, out *DrainSpec, s conversion.Scope) error {
+	return autoConvert_kops_DrainSpec_To_v1alpha2_DrainSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_EBSDefaultEncryptionSpec_To_kops_EBSDefaultEncryptionSpec(in *EBSDefaultEncryptionSpec, out *kops.EBSDefaultEncryptionSpec, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.KmsKeyId = in.KmsKeyId
+	return nil
+}
+
+// Convert_v1alpha2_EBSDefaultEncryptionSpec_To_kops_EBSDefaultEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_EBSDefaultEncryptionSpec_To_kops_EBSDefaultEncryptionSpec(in *EBSDefaultEncryptionSpec, out *kops.EBSDefaultEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EBSDefaultEncryptionSpec_To_kops_EBSDefaultEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_EBSDefaultEncryptionSpec_To_v1alpha2_EBSDefaultEncryptionSpec(in *kops.EBSDefaultEncryptionSpec, out *EBSDefaultEncryptionSpec, s conversion.Scope) error {
+	out.Enable = in.Enable
+	out.KmsKeyId = in.KmsKeyId
+	return nil
+}
+
+// Convert_kops_EBSDefaultEncryptionSpec_To_v1alpha2_EBSDefaultEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_EBSDefaultEncryptionSpec_To_v1alpha2_EBSDefaultEncryptionSpec(in *kops.EBSDefaultEncryptionSpec, out *EBSDefaultEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_EBSDefaultEncryptionSpec_To_v1alpha2_EBSDefaultEncryptionSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_EgressGatewaySpec_To_kops_EgressGatewaySpec(in *EgressGatewaySpec, out *kops.EgressGatewaySpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.InstanceGroup = in.InstanceGroup
+	out.Namespaces = in.Namespaces
+	out.DestinationCIDRs = in.DestinationCIDRs
+	return nil
+}
+
+// Convert_v1alpha2_EgressGatewaySpec_To_kops_EgressGatewaySpec is an autogenerated conversion function.
+func Convert_v1alpha2_EgressGatewaySpec_To_kops_EgressGatewaySpec(in *EgressGatewaySpec, out *kops.EgressGatewaySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EgressGatewaySpec_To_kops_EgressGatewaySpec(in, out, s)
+}
+
+func autoConvert_kops_EgressGatewaySpec_To_v1alpha2_EgressGatewaySpec(in *kops.EgressGatewaySpec, out *EgressGatewaySpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.InstanceGroup = in.InstanceGroup
+	out.Namespaces = in.Namespaces
+	out.DestinationCIDRs = in.DestinationCIDRs
+	return nil
+}
+
+// Convert_kops_EgressGatewaySpec_To_v1alpha2_EgressGatewaySpec is an autogenerated conversion function.
+func Convert_kops_EgressGatewaySpec_To_v1alpha2_EgressGatewaySpec(in *kops.EgressGatewaySpec, out *EgressGatewaySpec, s conversion.Scope) error {
+	return autoConvert_kops_EgressGatewaySpec_To_v1alpha2_EgressGatewaySpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_EgressProxySpec_To_kops_EgressProxySpec(in *EgressProxySpec, out *kops.EgressProxySpec, s conversion.Scope) error {
 	if err := Convert_v1alpha2_HTTPProxy_To_kops_HTTPProxy(&in.HTTPProxy, &out.HTTPProxy, s); err != nil {
 		return err
@@ -3402,6 +4678,7 @@ func Convert_kops_EnvVar_To_v1alpha2_EnvVar(in *kops.EnvVar, out *EnvVar, s conv
 
 func autoConvert_v1alpha2_EtcdBackupSpec_To_kops_EtcdBackupSpec(in *EtcdBackupSpec, out *kops.EtcdBackupSpec, s conversion.Scope) error {
 	out.BackupStore = in.BackupStore
+	out.ReplicaStore = in.ReplicaStore
 	out.Image = in.Image
 	return nil
 }
@@ -3413,6 +4690,7 @@ func Convert_v1alpha2_EtcdBackupSpec_To_kops_EtcdBackupSpec(in *EtcdBackupSpec,
 
 func autoConvert_kops_EtcdBackupSpec_To_v1alpha2_EtcdBackupSpec(in *kops.EtcdBackupSpec, out *EtcdBackupSpec, s conversion.Scope) error {
 	out.BackupStore = in.BackupStore
+	out.ReplicaStore = in.ReplicaStore
 	out.Image = in.Image
 	return nil
 }
@@ -3598,6 +4876,28 @@ func Convert_kops_EtcdMemberSpec_To_v1alpha2_EtcdMemberSpec(in *kops.EtcdMemberS
 	return autoConvert_kops_EtcdMemberSpec_To_v1alpha2_EtcdMemberSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_EventsSpec_To_kops_EventsSpec(in *EventsSpec, out *kops.EventsSpec, s conversion.Scope) error {
+	out.SNSTopicARN = in.SNSTopicARN
+	out.EventBusName = in.EventBusName
+	return nil
+}
+
+// Convert_v1alpha2_EventsSpec_To_kops_EventsSpec is an autogenerated conversion function.
+func Convert_v1alpha2_EventsSpec_To_kops_EventsSpec(in *EventsSpec, out *kops.EventsSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EventsSpec_To_kops_EventsSpec(in, out, s)
+}
+
+func autoConvert_kops_EventsSpec_To_v1alpha2_EventsSpec(in *kops.EventsSpec, out *EventsSpec, s conversion.Scope) error {
+	out.SNSTopicARN = in.SNSTopicARN
+	out.EventBusName = in.EventBusName
+	return nil
+}
+
+// Convert_kops_EventsSpec_To_v1alpha2_EventsSpec is an autogenerated conversion function.
+func Convert_kops_EventsSpec_To_v1alpha2_EventsSpec(in *kops.EventsSpec, out *EventsSpec, s conversion.Scope) error {
+	return autoConvert_kops_EventsSpec_To_v1alpha2_EventsSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_ExecContainerAction_To_kops_ExecContainerAction(in *ExecContainerAction, out *kops.ExecContainerAction, s conversion.Scope) error {
 	out.Image = in.Image
 	out.Command = in.Command
@@ -3838,6 +5138,26 @@ func Convert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in *kops.HTTPProxy, out *HTTPP
 	return autoConvert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in, out, s)
 }
 
+func autoConvert_v1alpha2_HibernationSpec_To_kops_HibernationSpec(in *HibernationSpec, out *kops.HibernationSpec, s conversion.Scope) error {
+	out.NATGateways = in.NATGateways
+	return nil
+}
+
+// Convert_v1alpha2_HibernationSpec_To_kops_HibernationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_HibernationSpec_To_kops_HibernationSpec(in *HibernationSpec, out *kops.HibernationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_HibernationSpec_To_kops_HibernationSpec(in, out, s)
+}
+
+func autoConvert_kops_HibernationSpec_To_v1alpha2_HibernationSpec(in *kops.HibernationSpec, out *HibernationSpec, s conversion.Scope) error {
+	out.NATGateways = in.NATGateways
+	return nil
+}
+
+// Convert_kops_HibernationSpec_To_v1alpha2_HibernationSpec is an autogenerated conversion function.
+func Convert_kops_HibernationSpec_To_v1alpha2_HibernationSpec(in *kops.HibernationSpec, out *HibernationSpec, s conversion.Scope) error {
+	return autoConvert_kops_HibernationSpec_To_v1alpha2_HibernationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_HookSpec_To_kops_HookSpec(in *HookSpec, out *kops.HookSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Disabled = in.Disabled
@@ -3992,6 +5312,30 @@ func Convert_kops_IAMSpec_To_v1alpha2_IAMSpec(in *kops.IAMSpec, out *IAMSpec, s
 	return autoConvert_kops_IAMSpec_To_v1alpha2_IAMSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(in *ImageVerificationSpec, out *kops.ImageVerificationSpec, s conversion.Scope) error {
+	out.Action = kops.ImageVerificationAction(in.Action)
+	out.Digests = in.Digests
+	out.CosignPublicKeys = in.CosignPublicKeys
+	return nil
+}
+
+// Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(in *ImageVerificationSpec, out *kops.ImageVerificationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(in, out, s)
+}
+
+func autoConvert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(in *kops.ImageVerificationSpec, out *ImageVerificationSpec, s conversion.Scope) error {
+	out.Action = ImageVerificationAction(in.Action)
+	out.Digests = in.Digests
+	out.CosignPublicKeys = in.CosignPublicKeys
+	return nil
+}
+
+// Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec is an autogenerated conversion function.
+func Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(in *kops.ImageVerificationSpec, out *ImageVerificationSpec, s conversion.Scope) error {
+	return autoConvert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_InstanceGroup_To_kops_InstanceGroup(in *InstanceGroup, out *kops.InstanceGroup, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -4060,6 +5404,44 @@ func Convert_kops_InstanceGroupList_To_v1alpha2_InstanceGroupList(in *kops.Insta
 	return autoConvert_kops_InstanceGroupList_To_v1alpha2_InstanceGroupList(in, out, s)
 }
 
+func autoConvert_v1alpha2_InstanceGroupLoggingSpec_To_kops_InstanceGroupLoggingSpec(in *InstanceGroupLoggingSpec, out *kops.InstanceGroupLoggingSpec, s conversion.Scope) error {
+	out.JournaldSystemMaxUse = in.JournaldSystemMaxUse
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(kops.VolumeSpec)
+		if err := Convert_v1alpha2_VolumeSpec_To_kops_VolumeSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Volume = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_InstanceGroupLoggingSpec_To_kops_InstanceGroupLoggingSpec is an autogenerated conversion function.
+func Convert_v1alpha2_InstanceGroupLoggingSpec_To_kops_InstanceGroupLoggingSpec(in *InstanceGroupLoggingSpec, out *kops.InstanceGroupLoggingSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_InstanceGroupLoggingSpec_To_kops_InstanceGroupLoggingSpec(in, out, s)
+}
+
+func autoConvert_kops_InstanceGroupLoggingSpec_To_v1alpha2_InstanceGroupLoggingSpec(in *kops.InstanceGroupLoggingSpec, out *InstanceGroupLoggingSpec, s conversion.Scope) error {
+	out.JournaldSystemMaxUse = in.JournaldSystemMaxUse
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(VolumeSpec)
+		if err := Convert_kops_VolumeSpec_To_v1alpha2_VolumeSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Volume = nil
+	}
+	return nil
+}
+
+// Convert_kops_InstanceGroupLoggingSpec_To_v1alpha2_InstanceGroupLoggingSpec is an autogenerated conversion function.
+func Convert_kops_InstanceGroupLoggingSpec_To_v1alpha2_InstanceGroupLoggingSpec(in *kops.InstanceGroupLoggingSpec, out *InstanceGroupLoggingSpec, s conversion.Scope) error {
+	return autoConvert_kops_InstanceGroupLoggingSpec_To_v1alpha2_InstanceGroupLoggingSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *InstanceGroupSpec, out *kops.InstanceGroupSpec, s conversion.Scope) error {
 	out.Role = kops.InstanceGroupRole(in.Role)
 	out.Image = in.Image
@@ -4110,6 +5492,17 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.Hooks = nil
 	}
+	if in.NodePlugins != nil {
+		in, out := &in.NodePlugins, &out.NodePlugins
+		*out = make([]kops.NodePluginSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_NodePluginSpec_To_kops_NodePluginSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.NodePlugins = nil
+	}
 	out.MaxPrice = in.MaxPrice
 	out.SpotDurationInMinutes = in.SpotDurationInMinutes
 	out.CPUCredits = in.CPUCredits
@@ -4160,6 +5553,7 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 		out.AdditionalUserData = nil
 	}
 	out.SuspendProcesses = in.SuspendProcesses
+	out.StableCapacity = in.StableCapacity
 	if in.ExternalLoadBalancers != nil {
 		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
 		*out = make([]kops.LoadBalancer, len(*in))
@@ -4183,8 +5577,75 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	}
 	out.SecurityGroupOverride = in.SecurityGroupOverride
 	out.InstanceProtection = in.InstanceProtection
-	out.SysctlParameters = in.SysctlParameters
-	if in.RollingUpdate != nil {
+	out.ScaleInProtection = in.ScaleInProtection
+	out.TerminationPolicies = in.TerminationPolicies
+	if in.InstanceMaintenancePolicy != nil {
+		in, out := &in.InstanceMaintenancePolicy, &out.InstanceMaintenancePolicy
+		*out = new(kops.InstanceMaintenancePolicySpec)
+		if err := Convert_v1alpha2_InstanceMaintenancePolicySpec_To_kops_InstanceMaintenancePolicySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceMaintenancePolicy = nil
+	}
+	out.SysctlParameters = in.SysctlParameters
+	out.Sysctls = in.Sysctls
+	out.KernelModules = in.KernelModules
+	if in.Swap != nil {
+		in, out := &in.Swap, &out.Swap
+		*out = new(kops.SwapSpec)
+		if err := Convert_v1alpha2_SwapSpec_To_kops_SwapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Swap = nil
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(kops.InstanceGroupLoggingSpec)
+		if err := Convert_v1alpha2_InstanceGroupLoggingSpec_To_kops_InstanceGroupLoggingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Logging = nil
+	}
+	if in.StateVolumes != nil {
+		in, out := &in.StateVolumes, &out.StateVolumes
+		*out = new(kops.StateVolumesSpec)
+		if err := Convert_v1alpha2_StateVolumesSpec_To_kops_StateVolumesSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.StateVolumes = nil
+	}
+	if in.InstanceStorage != nil {
+		in, out := &in.InstanceStorage, &out.InstanceStorage
+		*out = new(kops.InstanceStorageSpec)
+		if err := Convert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceStorage = nil
+	}
+	if in.SELinux != nil {
+		in, out := &in.SELinux, &out.SELinux
+		*out = new(kops.SELinuxSpec)
+		if err := Convert_v1alpha2_SELinuxSpec_To_kops_SELinuxSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SELinux = nil
+	}
+	if in.AppArmor != nil {
+		in, out := &in.AppArmor, &out.AppArmor
+		*out = new(kops.AppArmorSpec)
+		if err := Convert_v1alpha2_AppArmorSpec_To_kops_AppArmorSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AppArmor = nil
+	}
+	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(kops.RollingUpdate)
 		if err := Convert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(*in, *out, s); err != nil {
@@ -4214,6 +5675,39 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.WarmPool = nil
 	}
+	out.RootVolumeEphemeral = in.RootVolumeEphemeral
+	out.AcceleratedNetworking = in.AcceleratedNetworking
+	out.RegionalInstanceGroup = in.RegionalInstanceGroup
+	if in.ShieldedVM != nil {
+		in, out := &in.ShieldedVM, &out.ShieldedVM
+		*out = new(kops.ShieldedVMSpec)
+		if err := Convert_v1alpha2_ShieldedVMSpec_To_kops_ShieldedVMSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ShieldedVM = nil
+	}
+	out.ConfidentialVM = in.ConfidentialVM
+	out.NestedVirtualization = in.NestedVirtualization
+	if in.ProvisioningPolicy != nil {
+		in, out := &in.ProvisioningPolicy, &out.ProvisioningPolicy
+		*out = new(kops.ProvisioningPolicySpec)
+		if err := Convert_v1alpha2_ProvisioningPolicySpec_To_kops_ProvisioningPolicySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ProvisioningPolicy = nil
+	}
+	if in.Metal != nil {
+		in, out := &in.Metal, &out.Metal
+		*out = new(kops.MetalInstanceGroupSpec)
+		if err := Convert_v1alpha2_MetalInstanceGroupSpec_To_kops_MetalInstanceGroupSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Metal = nil
+	}
+	out.SSHPublicKeys = in.SSHPublicKeys
 	return nil
 }
 
@@ -4272,6 +5766,17 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	} else {
 		out.Hooks = nil
 	}
+	if in.NodePlugins != nil {
+		in, out := &in.NodePlugins, &out.NodePlugins
+		*out = make([]NodePluginSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_NodePluginSpec_To_v1alpha2_NodePluginSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.NodePlugins = nil
+	}
 	out.MaxPrice = in.MaxPrice
 	out.SpotDurationInMinutes = in.SpotDurationInMinutes
 	out.CPUCredits = in.CPUCredits
@@ -4322,6 +5827,7 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 		out.AdditionalUserData = nil
 	}
 	out.SuspendProcesses = in.SuspendProcesses
+	out.StableCapacity = in.StableCapacity
 	if in.ExternalLoadBalancers != nil {
 		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
 		*out = make([]LoadBalancer, len(*in))
@@ -4345,7 +5851,74 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	}
 	out.SecurityGroupOverride = in.SecurityGroupOverride
 	out.InstanceProtection = in.InstanceProtection
+	out.ScaleInProtection = in.ScaleInProtection
+	out.TerminationPolicies = in.TerminationPolicies
+	if in.InstanceMaintenancePolicy != nil {
+		in, out := &in.InstanceMaintenancePolicy, &out.InstanceMaintenancePolicy
+		*out = new(InstanceMaintenancePolicySpec)
+		if err := Convert_kops_InstanceMaintenancePolicySpec_To_v1alpha2_InstanceMaintenancePolicySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceMaintenancePolicy = nil
+	}
 	out.SysctlParameters = in.SysctlParameters
+	out.Sysctls = in.Sysctls
+	out.KernelModules = in.KernelModules
+	if in.Swap != nil {
+		in, out := &in.Swap, &out.Swap
+		*out = new(SwapSpec)
+		if err := Convert_kops_SwapSpec_To_v1alpha2_SwapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Swap = nil
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(InstanceGroupLoggingSpec)
+		if err := Convert_kops_InstanceGroupLoggingSpec_To_v1alpha2_InstanceGroupLoggingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Logging = nil
+	}
+	if in.StateVolumes != nil {
+		in, out := &in.StateVolumes, &out.StateVolumes
+		*out = new(StateVolumesSpec)
+		if err := Convert_kops_StateVolumesSpec_To_v1alpha2_StateVolumesSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.StateVolumes = nil
+	}
+	if in.InstanceStorage != nil {
+		in, out := &in.InstanceStorage, &out.InstanceStorage
+		*out = new(InstanceStorageSpec)
+		if err := Convert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceStorage = nil
+	}
+	if in.SELinux != nil {
+		in, out := &in.SELinux, &out.SELinux
+		*out = new(SELinuxSpec)
+		if err := Convert_kops_SELinuxSpec_To_v1alpha2_SELinuxSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SELinux = nil
+	}
+	if in.AppArmor != nil {
+		in, out := &in.AppArmor, &out.AppArmor
+		*out = new(AppArmorSpec)
+		if err := Convert_kops_AppArmorSpec_To_v1alpha2_AppArmorSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AppArmor = nil
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(RollingUpdate)
@@ -4376,6 +5949,39 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	} else {
 		out.WarmPool = nil
 	}
+	out.RootVolumeEphemeral = in.RootVolumeEphemeral
+	out.AcceleratedNetworking = in.AcceleratedNetworking
+	out.RegionalInstanceGroup = in.RegionalInstanceGroup
+	if in.ShieldedVM != nil {
+		in, out := &in.ShieldedVM, &out.ShieldedVM
+		*out = new(ShieldedVMSpec)
+		if err := Convert_kops_ShieldedVMSpec_To_v1alpha2_ShieldedVMSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ShieldedVM = nil
+	}
+	out.ConfidentialVM = in.ConfidentialVM
+	out.NestedVirtualization = in.NestedVirtualization
+	if in.ProvisioningPolicy != nil {
+		in, out := &in.ProvisioningPolicy, &out.ProvisioningPolicy
+		*out = new(ProvisioningPolicySpec)
+		if err := Convert_kops_ProvisioningPolicySpec_To_v1alpha2_ProvisioningPolicySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ProvisioningPolicy = nil
+	}
+	if in.Metal != nil {
+		in, out := &in.Metal, &out.Metal
+		*out = new(MetalInstanceGroupSpec)
+		if err := Convert_kops_MetalInstanceGroupSpec_To_v1alpha2_MetalInstanceGroupSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Metal = nil
+	}
+	out.SSHPublicKeys = in.SSHPublicKeys
 	return nil
 }
 
@@ -4384,6 +5990,28 @@ func Convert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.Insta
 	return autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_InstanceMaintenancePolicySpec_To_kops_InstanceMaintenancePolicySpec(in *InstanceMaintenancePolicySpec, out *kops.InstanceMaintenancePolicySpec, s conversion.Scope) error {
+	out.MinHealthyPercentage = in.MinHealthyPercentage
+	out.MaxHealthyPercentage = in.MaxHealthyPercentage
+	return nil
+}
+
+// Convert_v1alpha2_InstanceMaintenancePolicySpec_To_kops_InstanceMaintenancePolicySpec is an autogenerated conversion function.
+func Convert_v1alpha2_InstanceMaintenancePolicySpec_To_kops_InstanceMaintenancePolicySpec(in *InstanceMaintenancePolicySpec, out *kops.InstanceMaintenancePolicySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_InstanceMaintenancePolicySpec_To_kops_InstanceMaintenancePolicySpec(in, out, s)
+}
+
+func autoConvert_kops_InstanceMaintenancePolicySpec_To_v1alpha2_InstanceMaintenancePolicySpec(in *kops.InstanceMaintenancePolicySpec, out *InstanceMaintenancePolicySpec, s conversion.Scope) error {
+	out.MinHealthyPercentage = in.MinHealthyPercentage
+	out.MaxHealthyPercentage = in.MaxHealthyPercentage
+	return nil
+}
+
+// Convert_kops_InstanceMaintenancePolicySpec_To_v1alpha2_InstanceMaintenancePolicySpec is an autogenerated conversion function.
+func Convert_kops_InstanceMaintenancePolicySpec_To_v1alpha2_InstanceMaintenancePolicySpec(in *kops.InstanceMaintenancePolicySpec, out *InstanceMaintenancePolicySpec, s conversion.Scope) error {
+	return autoConvert_kops_InstanceMaintenancePolicySpec_To_v1alpha2_InstanceMaintenancePolicySpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_InstanceMetadataOptions_To_kops_InstanceMetadataOptions(in *InstanceMetadataOptions, out *kops.InstanceMetadataOptions, s conversion.Scope) error {
 	out.HTTPPutResponseHopLimit = in.HTTPPutResponseHopLimit
 	out.HTTPTokens = in.HTTPTokens
@@ -4406,6 +6034,30 @@ func Convert_kops_InstanceMetadataOptions_To_v1alpha2_InstanceMetadataOptions(in
 	return autoConvert_kops_InstanceMetadataOptions_To_v1alpha2_InstanceMetadataOptions(in, out, s)
 }
 
+func autoConvert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(in *InstanceStorageSpec, out *kops.InstanceStorageSpec, s conversion.Scope) error {
+	out.Path = in.Path
+	out.Filesystem = in.Filesystem
+	out.MountOptions = in.MountOptions
+	return nil
+}
+
+// Convert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec is an autogenerated conversion function.
+func Convert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(in *InstanceStorageSpec, out *kops.InstanceStorageSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(in, out, s)
+}
+
+func autoConvert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(in *kops.InstanceStorageSpec, out *InstanceStorageSpec, s conversion.Scope) error {
+	out.Path = in.Path
+	out.Filesystem = in.Filesystem
+	out.MountOptions = in.MountOptions
+	return nil
+}
+
+// Convert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec is an autogenerated conversion function.
+func Convert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(in *kops.InstanceStorageSpec, out *InstanceStorageSpec, s conversion.Scope) error {
+	return autoConvert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_Keyset_To_kops_Keyset(in *Keyset, out *kops.Keyset, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_KeysetSpec_To_kops_KeysetSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -4868,6 +6520,7 @@ func autoConvert_v1alpha2_KubeControllerManagerConfig_To_kops_KubeControllerMana
 	out.AuthorizationKubeconfig = in.AuthorizationKubeconfig
 	out.AuthorizationAlwaysAllowPaths = in.AuthorizationAlwaysAllowPaths
 	out.ExternalCloudVolumePlugin = in.ExternalCloudVolumePlugin
+	out.EnableLeaderMigration = in.EnableLeaderMigration
 	out.EnableProfiling = in.EnableProfiling
 	return nil
 }
@@ -4935,6 +6588,7 @@ func autoConvert_kops_KubeControllerManagerConfig_To_v1alpha2_KubeControllerMana
 	out.AuthorizationKubeconfig = in.AuthorizationKubeconfig
 	out.AuthorizationAlwaysAllowPaths = in.AuthorizationAlwaysAllowPaths
 	out.ExternalCloudVolumePlugin = in.ExternalCloudVolumePlugin
+	out.EnableLeaderMigration = in.EnableLeaderMigration
 	out.EnableProfiling = in.EnableProfiling
 	return nil
 }
@@ -5087,6 +6741,17 @@ func autoConvert_v1alpha2_KubeSchedulerConfig_To_kops_KubeSchedulerConfig(in *Ku
 	out.MaxPersistentVolumes = in.MaxPersistentVolumes
 	out.Qps = in.Qps
 	out.Burst = in.Burst
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]kops.KubeSchedulerProfile, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_KubeSchedulerProfile_To_kops_KubeSchedulerProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Profiles = nil
+	}
 	out.AuthenticationKubeconfig = in.AuthenticationKubeconfig
 	out.AuthorizationKubeconfig = in.AuthorizationKubeconfig
 	out.AuthorizationAlwaysAllowPaths = in.AuthorizationAlwaysAllowPaths
@@ -5118,6 +6783,17 @@ func autoConvert_kops_KubeSchedulerConfig_To_v1alpha2_KubeSchedulerConfig(in *ko
 	out.MaxPersistentVolumes = in.MaxPersistentVolumes
 	out.Qps = in.Qps
 	out.Burst = in.Burst
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]KubeSchedulerProfile, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KubeSchedulerProfile_To_v1alpha2_KubeSchedulerProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Profiles = nil
+	}
 	out.AuthenticationKubeconfig = in.AuthenticationKubeconfig
 	out.AuthorizationKubeconfig = in.AuthorizationKubeconfig
 	out.AuthorizationAlwaysAllowPaths = in.AuthorizationAlwaysAllowPaths
@@ -5130,140 +6806,525 @@ func Convert_kops_KubeSchedulerConfig_To_v1alpha2_KubeSchedulerConfig(in *kops.K
 	return autoConvert_kops_KubeSchedulerConfig_To_v1alpha2_KubeSchedulerConfig(in, out, s)
 }
 
-func autoConvert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in *KubeletConfigSpec, out *kops.KubeletConfigSpec, s conversion.Scope) error {
-	out.APIServers = in.APIServers
-	out.AnonymousAuth = in.AnonymousAuth
-	out.AuthorizationMode = in.AuthorizationMode
-	out.BootstrapKubeconfig = in.BootstrapKubeconfig
-	out.ClientCAFile = in.ClientCAFile
-	out.TLSCertFile = in.TLSCertFile
-	out.TLSPrivateKeyFile = in.TLSPrivateKeyFile
-	out.TLSCipherSuites = in.TLSCipherSuites
-	out.TLSMinVersion = in.TLSMinVersion
-	out.KubeconfigPath = in.KubeconfigPath
-	out.RequireKubeconfig = in.RequireKubeconfig
-	out.LogFormat = in.LogFormat
-	out.LogLevel = in.LogLevel
-	out.PodManifestPath = in.PodManifestPath
-	out.HostnameOverride = in.HostnameOverride
-	out.PodInfraContainerImage = in.PodInfraContainerImage
-	out.SeccompProfileRoot = in.SeccompProfileRoot
-	out.AllowPrivileged = in.AllowPrivileged
-	out.EnableDebuggingHandlers = in.EnableDebuggingHandlers
-	out.RegisterNode = in.RegisterNode
-	out.NodeStatusUpdateFrequency = in.NodeStatusUpdateFrequency
-	out.ClusterDomain = in.ClusterDomain
-	out.ClusterDNS = in.ClusterDNS
-	out.NetworkPluginName = in.NetworkPluginName
-	out.CloudProvider = in.CloudProvider
-	out.KubeletCgroups = in.KubeletCgroups
-	out.RuntimeCgroups = in.RuntimeCgroups
-	out.ReadOnlyPort = in.ReadOnlyPort
-	out.SystemCgroups = in.SystemCgroups
-	out.CgroupRoot = in.CgroupRoot
-	out.ConfigureCBR0 = in.ConfigureCBR0
-	out.HairpinMode = in.HairpinMode
-	out.BabysitDaemons = in.BabysitDaemons
-	out.MaxPods = in.MaxPods
-	out.NvidiaGPUs = in.NvidiaGPUs
-	out.PodCIDR = in.PodCIDR
-	out.ResolverConfig = in.ResolverConfig
-	out.ReconcileCIDR = in.ReconcileCIDR
-	out.RegisterSchedulable = in.RegisterSchedulable
-	out.SerializeImagePulls = in.SerializeImagePulls
-	out.NodeLabels = in.NodeLabels
-	out.NonMasqueradeCIDR = in.NonMasqueradeCIDR
-	out.EnableCustomMetrics = in.EnableCustomMetrics
-	out.NetworkPluginMTU = in.NetworkPluginMTU
-	out.ImageGCHighThresholdPercent = in.ImageGCHighThresholdPercent
-	out.ImageGCLowThresholdPercent = in.ImageGCLowThresholdPercent
-	out.ImagePullProgressDeadline = in.ImagePullProgressDeadline
-	out.EvictionHard = in.EvictionHard
-	out.EvictionSoft = in.EvictionSoft
-	out.EvictionSoftGracePeriod = in.EvictionSoftGracePeriod
-	out.EvictionPressureTransitionPeriod = in.EvictionPressureTransitionPeriod
-	out.EvictionMaxPodGracePeriod = in.EvictionMaxPodGracePeriod
-	out.EvictionMinimumReclaim = in.EvictionMinimumReclaim
-	out.VolumePluginDirectory = in.VolumePluginDirectory
-	out.Taints = in.Taints
-	out.FeatureGates = in.FeatureGates
-	out.KubeReserved = in.KubeReserved
-	out.KubeReservedCgroup = in.KubeReservedCgroup
-	out.SystemReserved = in.SystemReserved
-	out.SystemReservedCgroup = in.SystemReservedCgroup
-	out.EnforceNodeAllocatable = in.EnforceNodeAllocatable
-	out.RuntimeRequestTimeout = in.RuntimeRequestTimeout
-	out.VolumeStatsAggPeriod = in.VolumeStatsAggPeriod
-	out.FailSwapOn = in.FailSwapOn
-	out.ExperimentalAllowedUnsafeSysctls = in.ExperimentalAllowedUnsafeSysctls
-	out.AllowedUnsafeSysctls = in.AllowedUnsafeSysctls
-	out.StreamingConnectionIdleTimeout = in.StreamingConnectionIdleTimeout
-	out.DockerDisableSharedPID = in.DockerDisableSharedPID
-	out.RootDir = in.RootDir
-	out.AuthenticationTokenWebhook = in.AuthenticationTokenWebhook
-	out.AuthenticationTokenWebhookCacheTTL = in.AuthenticationTokenWebhookCacheTTL
-	out.CPUCFSQuota = in.CPUCFSQuota
-	out.CPUCFSQuotaPeriod = in.CPUCFSQuotaPeriod
-	out.CpuManagerPolicy = in.CpuManagerPolicy
-	out.RegistryPullQPS = in.RegistryPullQPS
-	out.RegistryBurst = in.RegistryBurst
-	out.TopologyManagerPolicy = in.TopologyManagerPolicy
-	out.RotateCertificates = in.RotateCertificates
-	out.ProtectKernelDefaults = in.ProtectKernelDefaults
-	out.CgroupDriver = in.CgroupDriver
-	out.HousekeepingInterval = in.HousekeepingInterval
-	out.EventQPS = in.EventQPS
-	out.EventBurst = in.EventBurst
-	out.ContainerLogMaxSize = in.ContainerLogMaxSize
-	out.ContainerLogMaxFiles = in.ContainerLogMaxFiles
-	out.EnableCadvisorJsonEndpoints = in.EnableCadvisorJsonEndpoints
-	out.PodPidsLimit = in.PodPidsLimit
+func autoConvert_v1alpha2_KubeSchedulerPlugin_To_kops_KubeSchedulerPlugin(in *KubeSchedulerPlugin, out *kops.KubeSchedulerPlugin, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
 	return nil
 }
 
-// Convert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec is an autogenerated conversion function.
-func Convert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in *KubeletConfigSpec, out *kops.KubeletConfigSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in, out, s)
+// Convert_v1alpha2_KubeSchedulerPlugin_To_kops_KubeSchedulerPlugin is an autogenerated conversion function.
+func Convert_v1alpha2_KubeSchedulerPlugin_To_kops_KubeSchedulerPlugin(in *KubeSchedulerPlugin, out *kops.KubeSchedulerPlugin, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeSchedulerPlugin_To_kops_KubeSchedulerPlugin(in, out, s)
 }
 
-func autoConvert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in *kops.KubeletConfigSpec, out *KubeletConfigSpec, s conversion.Scope) error {
-	out.APIServers = in.APIServers
-	out.AnonymousAuth = in.AnonymousAuth
-	out.AuthorizationMode = in.AuthorizationMode
-	out.BootstrapKubeconfig = in.BootstrapKubeconfig
-	out.ClientCAFile = in.ClientCAFile
-	out.TLSCertFile = in.TLSCertFile
-	out.TLSPrivateKeyFile = in.TLSPrivateKeyFile
-	out.TLSCipherSuites = in.TLSCipherSuites
-	out.TLSMinVersion = in.TLSMinVersion
-	out.KubeconfigPath = in.KubeconfigPath
-	out.RequireKubeconfig = in.RequireKubeconfig
-	out.LogFormat = in.LogFormat
-	out.LogLevel = in.LogLevel
-	out.PodManifestPath = in.PodManifestPath
-	out.HostnameOverride = in.HostnameOverride
-	out.PodInfraContainerImage = in.PodInfraContainerImage
-	out.SeccompProfileRoot = in.SeccompProfileRoot
-	out.AllowPrivileged = in.AllowPrivileged
-	out.EnableDebuggingHandlers = in.EnableDebuggingHandlers
-	out.RegisterNode = in.RegisterNode
-	out.NodeStatusUpdateFrequency = in.NodeStatusUpdateFrequency
-	out.ClusterDomain = in.ClusterDomain
-	out.ClusterDNS = in.ClusterDNS
-	out.NetworkPluginName = in.NetworkPluginName
-	out.CloudProvider = in.CloudProvider
-	out.KubeletCgroups = in.KubeletCgroups
-	out.RuntimeCgroups = in.RuntimeCgroups
-	out.ReadOnlyPort = in.ReadOnlyPort
-	out.SystemCgroups = in.SystemCgroups
-	out.CgroupRoot = in.CgroupRoot
-	out.ConfigureCBR0 = in.ConfigureCBR0
-	out.HairpinMode = in.HairpinMode
-	out.BabysitDaemons = in.BabysitDaemons
-	out.MaxPods = in.MaxPods
-	out.NvidiaGPUs = in.NvidiaGPUs
-	out.PodCIDR = in.PodCIDR
-	out.ResolverConfig = in.ResolverConfig
+func autoConvert_kops_KubeSchedulerPlugin_To_v1alpha2_KubeSchedulerPlugin(in *kops.KubeSchedulerPlugin, out *KubeSchedulerPlugin, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_kops_KubeSchedulerPlugin_To_v1alpha2_KubeSchedulerPlugin is an autogenerated conversion function.
+func Convert_kops_KubeSchedulerPlugin_To_v1alpha2_KubeSchedulerPlugin(in *kops.KubeSchedulerPlugin, out *KubeSchedulerPlugin, s conversion.Scope) error {
+	return autoConvert_kops_KubeSchedulerPlugin_To_v1alpha2_KubeSchedulerPlugin(in, out, s)
+}
+
+func autoConvert_v1alpha2_KubeSchedulerPluginConfig_To_kops_KubeSchedulerPluginConfig(in *KubeSchedulerPluginConfig, out *kops.KubeSchedulerPluginConfig, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Args = in.Args
+	return nil
+}
+
+// Convert_v1alpha2_KubeSchedulerPluginConfig_To_kops_KubeSchedulerPluginConfig is an autogenerated conversion function.
+func Convert_v1alpha2_KubeSchedulerPluginConfig_To_kops_KubeSchedulerPluginConfig(in *KubeSchedulerPluginConfig, out *kops.KubeSchedulerPluginConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeSchedulerPluginConfig_To_kops_KubeSchedulerPluginConfig(in, out, s)
+}
+
+func autoConvert_kops_KubeSchedulerPluginConfig_To_v1alpha2_KubeSchedulerPluginConfig(in *kops.KubeSchedulerPluginConfig, out *KubeSchedulerPluginConfig, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Args = in.Args
+	return nil
+}
+
+// Convert_kops_KubeSchedulerPluginConfig_To_v1alpha2_KubeSchedulerPluginConfig is an autogenerated conversion function.
+func Convert_kops_KubeSchedulerPluginConfig_To_v1alpha2_KubeSchedulerPluginConfig(in *kops.KubeSchedulerPluginConfig, out *KubeSchedulerPluginConfig, s conversion.Scope) error {
+	return autoConvert_kops_KubeSchedulerPluginConfig_To_v1alpha2_KubeSchedulerPluginConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(in *KubeSchedulerPluginSet, out *kops.KubeSchedulerPluginSet, s conversion.Scope) error {
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]kops.KubeSchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_KubeSchedulerPlugin_To_kops_KubeSchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Enabled = nil
+	}
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]kops.KubeSchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_KubeSchedulerPlugin_To_kops_KubeSchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Disabled = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet is an autogenerated conversion function.
+func Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(in *KubeSchedulerPluginSet, out *kops.KubeSchedulerPluginSet, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(in, out, s)
+}
+
+func autoConvert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(in *kops.KubeSchedulerPluginSet, out *KubeSchedulerPluginSet, s conversion.Scope) error {
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]KubeSchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KubeSchedulerPlugin_To_v1alpha2_KubeSchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Enabled = nil
+	}
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]KubeSchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KubeSchedulerPlugin_To_v1alpha2_KubeSchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Disabled = nil
+	}
+	return nil
+}
+
+// Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet is an autogenerated conversion function.
+func Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(in *kops.KubeSchedulerPluginSet, out *KubeSchedulerPluginSet, s conversion.Scope) error {
+	return autoConvert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(in, out, s)
+}
+
+func autoConvert_v1alpha2_KubeSchedulerPlugins_To_kops_KubeSchedulerPlugins(in *KubeSchedulerPlugins, out *kops.KubeSchedulerPlugins, s conversion.Scope) error {
+	if in.QueueSort != nil {
+		in, out := &in.QueueSort, &out.QueueSort
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.QueueSort = nil
+	}
+	if in.PreFilter != nil {
+		in, out := &in.PreFilter, &out.PreFilter
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreFilter = nil
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Filter = nil
+	}
+	if in.PostFilter != nil {
+		in, out := &in.PostFilter, &out.PostFilter
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostFilter = nil
+	}
+	if in.PreScore != nil {
+		in, out := &in.PreScore, &out.PreScore
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreScore = nil
+	}
+	if in.Score != nil {
+		in, out := &in.Score, &out.Score
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Score = nil
+	}
+	if in.Reserve != nil {
+		in, out := &in.Reserve, &out.Reserve
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Reserve = nil
+	}
+	if in.Permit != nil {
+		in, out := &in.Permit, &out.Permit
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Permit = nil
+	}
+	if in.PreBind != nil {
+		in, out := &in.PreBind, &out.PreBind
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreBind = nil
+	}
+	if in.Bind != nil {
+		in, out := &in.Bind, &out.Bind
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bind = nil
+	}
+	if in.PostBind != nil {
+		in, out := &in.PostBind, &out.PostBind
+		*out = new(kops.KubeSchedulerPluginSet)
+		if err := Convert_v1alpha2_KubeSchedulerPluginSet_To_kops_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostBind = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_KubeSchedulerPlugins_To_kops_KubeSchedulerPlugins is an autogenerated conversion function.
+func Convert_v1alpha2_KubeSchedulerPlugins_To_kops_KubeSchedulerPlugins(in *KubeSchedulerPlugins, out *kops.KubeSchedulerPlugins, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeSchedulerPlugins_To_kops_KubeSchedulerPlugins(in, out, s)
+}
+
+func autoConvert_kops_KubeSchedulerPlugins_To_v1alpha2_KubeSchedulerPlugins(in *kops.KubeSchedulerPlugins, out *KubeSchedulerPlugins, s conversion.Scope) error {
+	if in.QueueSort != nil {
+		in, out := &in.QueueSort, &out.QueueSort
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.QueueSort = nil
+	}
+	if in.PreFilter != nil {
+		in, out := &in.PreFilter, &out.PreFilter
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreFilter = nil
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Filter = nil
+	}
+	if in.PostFilter != nil {
+		in, out := &in.PostFilter, &out.PostFilter
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostFilter = nil
+	}
+	if in.PreScore != nil {
+		in, out := &in.PreScore, &out.PreScore
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreScore = nil
+	}
+	if in.Score != nil {
+		in, out := &in.Score, &out.Score
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Score = nil
+	}
+	if in.Reserve != nil {
+		in, out := &in.Reserve, &out.Reserve
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Reserve = nil
+	}
+	if in.Permit != nil {
+		in, out := &in.Permit, &out.Permit
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Permit = nil
+	}
+	if in.PreBind != nil {
+		in, out := &in.PreBind, &out.PreBind
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreBind = nil
+	}
+	if in.Bind != nil {
+		in, out := &in.Bind, &out.Bind
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bind = nil
+	}
+	if in.PostBind != nil {
+		in, out := &in.PostBind, &out.PostBind
+		*out = new(KubeSchedulerPluginSet)
+		if err := Convert_kops_KubeSchedulerPluginSet_To_v1alpha2_KubeSchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostBind = nil
+	}
+	return nil
+}
+
+// Convert_kops_KubeSchedulerPlugins_To_v1alpha2_KubeSchedulerPlugins is an autogenerated conversion function.
+func Convert_kops_KubeSchedulerPlugins_To_v1alpha2_KubeSchedulerPlugins(in *kops.KubeSchedulerPlugins, out *KubeSchedulerPlugins, s conversion.Scope) error {
+	return autoConvert_kops_KubeSchedulerPlugins_To_v1alpha2_KubeSchedulerPlugins(in, out, s)
+}
+
+func autoConvert_v1alpha2_KubeSchedulerProfile_To_kops_KubeSchedulerProfile(in *KubeSchedulerProfile, out *kops.KubeSchedulerProfile, s conversion.Scope) error {
+	out.SchedulerName = in.SchedulerName
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(kops.KubeSchedulerPlugins)
+		if err := Convert_v1alpha2_KubeSchedulerPlugins_To_kops_KubeSchedulerPlugins(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Plugins = nil
+	}
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = make([]kops.KubeSchedulerPluginConfig, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_KubeSchedulerPluginConfig_To_kops_KubeSchedulerPluginConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PluginConfig = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_KubeSchedulerProfile_To_kops_KubeSchedulerProfile is an autogenerated conversion function.
+func Convert_v1alpha2_KubeSchedulerProfile_To_kops_KubeSchedulerProfile(in *KubeSchedulerProfile, out *kops.KubeSchedulerProfile, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeSchedulerProfile_To_kops_KubeSchedulerProfile(in, out, s)
+}
+
+func autoConvert_kops_KubeSchedulerProfile_To_v1alpha2_KubeSchedulerProfile(in *kops.KubeSchedulerProfile, out *KubeSchedulerProfile, s conversion.Scope) error {
+	out.SchedulerName = in.SchedulerName
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(KubeSchedulerPlugins)
+		if err := Convert_kops_KubeSchedulerPlugins_To_v1alpha2_KubeSchedulerPlugins(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Plugins = nil
+	}
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = make([]KubeSchedulerPluginConfig, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KubeSchedulerPluginConfig_To_v1alpha2_KubeSchedulerPluginConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PluginConfig = nil
+	}
+	return nil
+}
+
+// Convert_kops_KubeSchedulerProfile_To_v1alpha2_KubeSchedulerProfile is an autogenerated conversion function.
+func Convert_kops_KubeSchedulerProfile_To_v1alpha2_KubeSchedulerProfile(in *kops.KubeSchedulerProfile, out *KubeSchedulerProfile, s conversion.Scope) error {
+	return autoConvert_kops_KubeSchedulerProfile_To_v1alpha2_KubeSchedulerProfile(in, out, s)
+}
+
+func autoConvert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in *KubeletConfigSpec, out *kops.KubeletConfigSpec, s conversion.Scope) error {
+	out.APIServers = in.APIServers
+	out.AnonymousAuth = in.AnonymousAuth
+	out.AuthorizationMode = in.AuthorizationMode
+	out.BootstrapKubeconfig = in.BootstrapKubeconfig
+	out.ClientCAFile = in.ClientCAFile
+	out.TLSCertFile = in.TLSCertFile
+	out.TLSPrivateKeyFile = in.TLSPrivateKeyFile
+	out.TLSCipherSuites = in.TLSCipherSuites
+	out.TLSMinVersion = in.TLSMinVersion
+	out.KubeconfigPath = in.KubeconfigPath
+	out.RequireKubeconfig = in.RequireKubeconfig
+	out.LogFormat = in.LogFormat
+	out.LogLevel = in.LogLevel
+	out.PodManifestPath = in.PodManifestPath
+	out.HostnameOverride = in.HostnameOverride
+	out.PodInfraContainerImage = in.PodInfraContainerImage
+	out.SeccompProfileRoot = in.SeccompProfileRoot
+	out.AllowPrivileged = in.AllowPrivileged
+	out.EnableDebuggingHandlers = in.EnableDebuggingHandlers
+	out.RegisterNode = in.RegisterNode
+	out.NodeStatusUpdateFrequency = in.NodeStatusUpdateFrequency
+	out.ClusterDomain = in.ClusterDomain
+	out.ClusterDNS = in.ClusterDNS
+	out.NetworkPluginName = in.NetworkPluginName
+	out.CloudProvider = in.CloudProvider
+	out.KubeletCgroups = in.KubeletCgroups
+	out.RuntimeCgroups = in.RuntimeCgroups
+	out.ReadOnlyPort = in.ReadOnlyPort
+	out.SystemCgroups = in.SystemCgroups
+	out.CgroupRoot = in.CgroupRoot
+	out.ConfigureCBR0 = in.ConfigureCBR0
+	out.HairpinMode = in.HairpinMode
+	out.BabysitDaemons = in.BabysitDaemons
+	out.MaxPods = in.MaxPods
+	out.NvidiaGPUs = in.NvidiaGPUs
+	out.PodCIDR = in.PodCIDR
+	out.ResolverConfig = in.ResolverConfig
+	out.ReconcileCIDR = in.ReconcileCIDR
+	out.RegisterSchedulable = in.RegisterSchedulable
+	out.SerializeImagePulls = in.SerializeImagePulls
+	out.NodeLabels = in.NodeLabels
+	out.NonMasqueradeCIDR = in.NonMasqueradeCIDR
+	out.EnableCustomMetrics = in.EnableCustomMetrics
+	out.NetworkPluginMTU = in.NetworkPluginMTU
+	out.ImageGCHighThresholdPercent = in.ImageGCHighThresholdPercent
+	out.ImageGCLowThresholdPercent = in.ImageGCLowThresholdPercent
+	out.ImagePullProgressDeadline = in.ImagePullProgressDeadline
+	out.EvictionHard = in.EvictionHard
+	out.EvictionSoft = in.EvictionSoft
+	out.EvictionSoftGracePeriod = in.EvictionSoftGracePeriod
+	out.EvictionPressureTransitionPeriod = in.EvictionPressureTransitionPeriod
+	out.EvictionMaxPodGracePeriod = in.EvictionMaxPodGracePeriod
+	out.EvictionMinimumReclaim = in.EvictionMinimumReclaim
+	out.VolumePluginDirectory = in.VolumePluginDirectory
+	out.Taints = in.Taints
+	out.FeatureGates = in.FeatureGates
+	out.KubeReserved = in.KubeReserved
+	out.KubeReservedCgroup = in.KubeReservedCgroup
+	out.SystemReserved = in.SystemReserved
+	out.SystemReservedCgroup = in.SystemReservedCgroup
+	out.EnforceNodeAllocatable = in.EnforceNodeAllocatable
+	out.RuntimeRequestTimeout = in.RuntimeRequestTimeout
+	out.VolumeStatsAggPeriod = in.VolumeStatsAggPeriod
+	out.FailSwapOn = in.FailSwapOn
+	out.ExperimentalAllowedUnsafeSysctls = in.ExperimentalAllowedUnsafeSysctls
+	out.AllowedUnsafeSysctls = in.AllowedUnsafeSysctls
+	out.StreamingConnectionIdleTimeout = in.StreamingConnectionIdleTimeout
+	out.DockerDisableSharedPID = in.DockerDisableSharedPID
+	out.RootDir = in.RootDir
+	out.AuthenticationTokenWebhook = in.AuthenticationTokenWebhook
+	out.AuthenticationTokenWebhookCacheTTL = in.AuthenticationTokenWebhookCacheTTL
+	out.CPUCFSQuota = in.CPUCFSQuota
+	out.CPUCFSQuotaPeriod = in.CPUCFSQuotaPeriod
+	out.CpuManagerPolicy = in.CpuManagerPolicy
+	out.RegistryPullQPS = in.RegistryPullQPS
+	out.RegistryBurst = in.RegistryBurst
+	out.TopologyManagerPolicy = in.TopologyManagerPolicy
+	out.RotateCertificates = in.RotateCertificates
+	out.ServerTLSBootstrap = in.ServerTLSBootstrap
+	out.ProtectKernelDefaults = in.ProtectKernelDefaults
+	out.CgroupDriver = in.CgroupDriver
+	out.HousekeepingInterval = in.HousekeepingInterval
+	out.EventQPS = in.EventQPS
+	out.EventBurst = in.EventBurst
+	out.ContainerLogMaxSize = in.ContainerLogMaxSize
+	out.ContainerLogMaxFiles = in.ContainerLogMaxFiles
+	out.EnableCadvisorJsonEndpoints = in.EnableCadvisorJsonEndpoints
+	out.PodPidsLimit = in.PodPidsLimit
+	out.UseConfigFile = in.UseConfigFile
+	out.ConfigDropIns = in.ConfigDropIns
+	return nil
+}
+
+// Convert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec is an autogenerated conversion function.
+func Convert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in *KubeletConfigSpec, out *kops.KubeletConfigSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in, out, s)
+}
+
+func autoConvert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in *kops.KubeletConfigSpec, out *KubeletConfigSpec, s conversion.Scope) error {
+	out.APIServers = in.APIServers
+	out.AnonymousAuth = in.AnonymousAuth
+	out.AuthorizationMode = in.AuthorizationMode
+	out.BootstrapKubeconfig = in.BootstrapKubeconfig
+	out.ClientCAFile = in.ClientCAFile
+	out.TLSCertFile = in.TLSCertFile
+	out.TLSPrivateKeyFile = in.TLSPrivateKeyFile
+	out.TLSCipherSuites = in.TLSCipherSuites
+	out.TLSMinVersion = in.TLSMinVersion
+	out.KubeconfigPath = in.KubeconfigPath
+	out.RequireKubeconfig = in.RequireKubeconfig
+	out.LogFormat = in.LogFormat
+	out.LogLevel = in.LogLevel
+	out.PodManifestPath = in.PodManifestPath
+	out.HostnameOverride = in.HostnameOverride
+	out.PodInfraContainerImage = in.PodInfraContainerImage
+	out.SeccompProfileRoot = in.SeccompProfileRoot
+	out.AllowPrivileged = in.AllowPrivileged
+	out.EnableDebuggingHandlers = in.EnableDebuggingHandlers
+	out.RegisterNode = in.RegisterNode
+	out.NodeStatusUpdateFrequency = in.NodeStatusUpdateFrequency
+	out.ClusterDomain = in.ClusterDomain
+	out.ClusterDNS = in.ClusterDNS
+	out.NetworkPluginName = in.NetworkPluginName
+	out.CloudProvider = in.CloudProvider
+	out.KubeletCgroups = in.KubeletCgroups
+	out.RuntimeCgroups = in.RuntimeCgroups
+	out.ReadOnlyPort = in.ReadOnlyPort
+	out.SystemCgroups = in.SystemCgroups
+	out.CgroupRoot = in.CgroupRoot
+	out.ConfigureCBR0 = in.ConfigureCBR0
+	out.HairpinMode = in.HairpinMode
+	out.BabysitDaemons = in.BabysitDaemons
+	out.MaxPods = in.MaxPods
+	out.NvidiaGPUs = in.NvidiaGPUs
+	out.PodCIDR = in.PodCIDR
+	out.ResolverConfig = in.ResolverConfig
 	out.ReconcileCIDR = in.ReconcileCIDR
 	out.RegisterSchedulable = in.RegisterSchedulable
 	out.SerializeImagePulls = in.SerializeImagePulls
@@ -5305,6 +7366,7 @@ func autoConvert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in *kops.K
 	out.RegistryBurst = in.RegistryBurst
 	out.TopologyManagerPolicy = in.TopologyManagerPolicy
 	out.RotateCertificates = in.RotateCertificates
+	out.ServerTLSBootstrap = in.ServerTLSBootstrap
 	out.ProtectKernelDefaults = in.ProtectKernelDefaults
 	out.CgroupDriver = in.CgroupDriver
 	out.HousekeepingInterval = in.HousekeepingInterval
@@ -5314,6 +7376,8 @@ func autoConvert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in *kops.K
 	out.ContainerLogMaxFiles = in.ContainerLogMaxFiles
 	out.EnableCadvisorJsonEndpoints = in.EnableCadvisorJsonEndpoints
 	out.PodPidsLimit = in.PodPidsLimit
+	out.UseConfigFile = in.UseConfigFile
+	out.ConfigDropIns = in.ConfigDropIns
 	return nil
 }
 
@@ -5393,6 +7457,10 @@ func Convert_kops_LeaderElectionConfiguration_To_v1alpha2_LeaderElectionConfigur
 func autoConvert_v1alpha2_LoadBalancer_To_kops_LoadBalancer(in *LoadBalancer, out *kops.LoadBalancer, s conversion.Scope) error {
 	out.LoadBalancerName = in.LoadBalancerName
 	out.TargetGroupARN = in.TargetGroupARN
+	out.Name = in.Name
+	out.Port = in.Port
+	out.Protocol = in.Protocol
+	out.HealthCheckPort = in.HealthCheckPort
 	return nil
 }
 
@@ -5404,6 +7472,10 @@ func Convert_v1alpha2_LoadBalancer_To_kops_LoadBalancer(in *LoadBalancer, out *k
 func autoConvert_kops_LoadBalancer_To_v1alpha2_LoadBalancer(in *kops.LoadBalancer, out *LoadBalancer, s conversion.Scope) error {
 	out.LoadBalancerName = in.LoadBalancerName
 	out.TargetGroupARN = in.TargetGroupARN
+	out.Name = in.Name
+	out.Port = in.Port
+	out.Protocol = in.Protocol
+	out.HealthCheckPort = in.HealthCheckPort
 	return nil
 }
 
@@ -5433,6 +7505,17 @@ func autoConvert_v1alpha2_LoadBalancerAccessSpec_To_kops_LoadBalancerAccessSpec(
 	} else {
 		out.Subnets = nil
 	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(kops.LoadBalancerHealthCheckSpec)
+		if err := Convert_v1alpha2_LoadBalancerHealthCheckSpec_To_kops_LoadBalancerHealthCheckSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HealthCheck = nil
+	}
+	out.DeregistrationDelaySeconds = in.DeregistrationDelaySeconds
+	out.AllocateElasticIPs = in.AllocateElasticIPs
 	return nil
 }
 
@@ -5462,6 +7545,17 @@ func autoConvert_kops_LoadBalancerAccessSpec_To_v1alpha2_LoadBalancerAccessSpec(
 	} else {
 		out.Subnets = nil
 	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(LoadBalancerHealthCheckSpec)
+		if err := Convert_kops_LoadBalancerHealthCheckSpec_To_v1alpha2_LoadBalancerHealthCheckSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HealthCheck = nil
+	}
+	out.DeregistrationDelaySeconds = in.DeregistrationDelaySeconds
+	out.AllocateElasticIPs = in.AllocateElasticIPs
 	return nil
 }
 
@@ -5470,6 +7564,36 @@ func Convert_kops_LoadBalancerAccessSpec_To_v1alpha2_LoadBalancerAccessSpec(in *
 	return autoConvert_kops_LoadBalancerAccessSpec_To_v1alpha2_LoadBalancerAccessSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_LoadBalancerHealthCheckSpec_To_kops_LoadBalancerHealthCheckSpec(in *LoadBalancerHealthCheckSpec, out *kops.LoadBalancerHealthCheckSpec, s conversion.Scope) error {
+	out.IntervalSeconds = in.IntervalSeconds
+	out.HealthyThreshold = in.HealthyThreshold
+	out.UnhealthyThreshold = in.UnhealthyThreshold
+	out.Protocol = in.Protocol
+	out.Port = in.Port
+	out.Path = in.Path
+	return nil
+}
+
+// Convert_v1alpha2_LoadBalancerHealthCheckSpec_To_kops_LoadBalancerHealthCheckSpec is an autogenerated conversion function.
+func Convert_v1alpha2_LoadBalancerHealthCheckSpec_To_kops_LoadBalancerHealthCheckSpec(in *LoadBalancerHealthCheckSpec, out *kops.LoadBalancerHealthCheckSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LoadBalancerHealthCheckSpec_To_kops_LoadBalancerHealthCheckSpec(in, out, s)
+}
+
+func autoConvert_kops_LoadBalancerHealthCheckSpec_To_v1alpha2_LoadBalancerHealthCheckSpec(in *kops.LoadBalancerHealthCheckSpec, out *LoadBalancerHealthCheckSpec, s conversion.Scope) error {
+	out.IntervalSeconds = in.IntervalSeconds
+	out.HealthyThreshold = in.HealthyThreshold
+	out.UnhealthyThreshold = in.UnhealthyThreshold
+	out.Protocol = in.Protocol
+	out.Port = in.Port
+	out.Path = in.Path
+	return nil
+}
+
+// Convert_kops_LoadBalancerHealthCheckSpec_To_v1alpha2_LoadBalancerHealthCheckSpec is an autogenerated conversion function.
+func Convert_kops_LoadBalancerHealthCheckSpec_To_v1alpha2_LoadBalancerHealthCheckSpec(in *kops.LoadBalancerHealthCheckSpec, out *LoadBalancerHealthCheckSpec, s conversion.Scope) error {
+	return autoConvert_kops_LoadBalancerHealthCheckSpec_To_v1alpha2_LoadBalancerHealthCheckSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_LoadBalancerSubnetSpec_To_kops_LoadBalancerSubnetSpec(in *LoadBalancerSubnetSpec, out *kops.LoadBalancerSubnetSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.PrivateIPv4Address = in.PrivateIPv4Address
@@ -5514,6 +7638,50 @@ func Convert_kops_LyftVPCNetworkingSpec_To_v1alpha2_LyftVPCNetworkingSpec(in *ko
 	return autoConvert_kops_LyftVPCNetworkingSpec_To_v1alpha2_LyftVPCNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(in *MaintenanceWindowSpec, out *kops.MaintenanceWindowSpec, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Duration = in.Duration
+	return nil
+}
+
+// Convert_v1alpha2_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec is an autogenerated conversion function.
+func Convert_v1alpha2_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(in *MaintenanceWindowSpec, out *kops.MaintenanceWindowSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(in, out, s)
+}
+
+func autoConvert_kops_MaintenanceWindowSpec_To_v1alpha2_MaintenanceWindowSpec(in *kops.MaintenanceWindowSpec, out *MaintenanceWindowSpec, s conversion.Scope) error {
+	out.Schedule = in.Schedule
+	out.Duration = in.Duration
+	return nil
+}
+
+// Convert_kops_MaintenanceWindowSpec_To_v1alpha2_MaintenanceWindowSpec is an autogenerated conversion function.
+func Convert_kops_MaintenanceWindowSpec_To_v1alpha2_MaintenanceWindowSpec(in *kops.MaintenanceWindowSpec, out *MaintenanceWindowSpec, s conversion.Scope) error {
+	return autoConvert_kops_MaintenanceWindowSpec_To_v1alpha2_MaintenanceWindowSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_MetalInstanceGroupSpec_To_kops_MetalInstanceGroupSpec(in *MetalInstanceGroupSpec, out *kops.MetalInstanceGroupSpec, s conversion.Scope) error {
+	out.Hosts = in.Hosts
+	out.SSHUser = in.SSHUser
+	return nil
+}
+
+// Convert_v1alpha2_MetalInstanceGroupSpec_To_kops_MetalInstanceGroupSpec is an autogenerated conversion function.
+func Convert_v1alpha2_MetalInstanceGroupSpec_To_kops_MetalInstanceGroupSpec(in *MetalInstanceGroupSpec, out *kops.MetalInstanceGroupSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_MetalInstanceGroupSpec_To_kops_MetalInstanceGroupSpec(in, out, s)
+}
+
+func autoConvert_kops_MetalInstanceGroupSpec_To_v1alpha2_MetalInstanceGroupSpec(in *kops.MetalInstanceGroupSpec, out *MetalInstanceGroupSpec, s conversion.Scope) error {
+	out.Hosts = in.Hosts
+	out.SSHUser = in.SSHUser
+	return nil
+}
+
+// Convert_kops_MetalInstanceGroupSpec_To_v1alpha2_MetalInstanceGroupSpec is an autogenerated conversion function.
+func Convert_kops_MetalInstanceGroupSpec_To_v1alpha2_MetalInstanceGroupSpec(in *kops.MetalInstanceGroupSpec, out *MetalInstanceGroupSpec, s conversion.Scope) error {
+	return autoConvert_kops_MetalInstanceGroupSpec_To_v1alpha2_MetalInstanceGroupSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_MetricsServerConfig_To_kops_MetricsServerConfig(in *MetricsServerConfig, out *kops.MetricsServerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Image = in.Image
@@ -5570,6 +7738,9 @@ func Convert_kops_MixedInstancesPolicySpec_To_v1alpha2_MixedInstancesPolicySpec(
 
 func autoConvert_v1alpha2_NTPConfig_To_kops_NTPConfig(in *NTPConfig, out *kops.NTPConfig, s conversion.Scope) error {
 	out.Managed = in.Managed
+	out.Servers = in.Servers
+	out.WaitForSync = in.WaitForSync
+	out.MetricsTextfileDirectory = in.MetricsTextfileDirectory
 	return nil
 }
 
@@ -5580,6 +7751,9 @@ func Convert_v1alpha2_NTPConfig_To_kops_NTPConfig(in *NTPConfig, out *kops.NTPCo
 
 func autoConvert_kops_NTPConfig_To_v1alpha2_NTPConfig(in *kops.NTPConfig, out *NTPConfig, s conversion.Scope) error {
 	out.Managed = in.Managed
+	out.Servers = in.Servers
+	out.WaitForSync = in.WaitForSync
+	out.MetricsTextfileDirectory = in.MetricsTextfileDirectory
 	return nil
 }
 
@@ -5588,6 +7762,32 @@ func Convert_kops_NTPConfig_To_v1alpha2_NTPConfig(in *kops.NTPConfig, out *NTPCo
 	return autoConvert_kops_NTPConfig_To_v1alpha2_NTPConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_NetworkCIDRAllocationSpec_To_kops_NetworkCIDRAllocationSpec(in *NetworkCIDRAllocationSpec, out *kops.NetworkCIDRAllocationSpec, s conversion.Scope) error {
+	out.Provider = in.Provider
+	out.Endpoint = in.Endpoint
+	out.Pool = in.Pool
+	out.AllocationID = in.AllocationID
+	return nil
+}
+
+// Convert_v1alpha2_NetworkCIDRAllocationSpec_To_kops_NetworkCIDRAllocationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NetworkCIDRAllocationSpec_To_kops_NetworkCIDRAllocationSpec(in *NetworkCIDRAllocationSpec, out *kops.NetworkCIDRAllocationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NetworkCIDRAllocationSpec_To_kops_NetworkCIDRAllocationSpec(in, out, s)
+}
+
+func autoConvert_kops_NetworkCIDRAllocationSpec_To_v1alpha2_NetworkCIDRAllocationSpec(in *kops.NetworkCIDRAllocationSpec, out *NetworkCIDRAllocationSpec, s conversion.Scope) error {
+	out.Provider = in.Provider
+	out.Endpoint = in.Endpoint
+	out.Pool = in.Pool
+	out.AllocationID = in.AllocationID
+	return nil
+}
+
+// Convert_kops_NetworkCIDRAllocationSpec_To_v1alpha2_NetworkCIDRAllocationSpec is an autogenerated conversion function.
+func Convert_kops_NetworkCIDRAllocationSpec_To_v1alpha2_NetworkCIDRAllocationSpec(in *kops.NetworkCIDRAllocationSpec, out *NetworkCIDRAllocationSpec, s conversion.Scope) error {
+	return autoConvert_kops_NetworkCIDRAllocationSpec_To_v1alpha2_NetworkCIDRAllocationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_NetworkingSpec_To_kops_NetworkingSpec(in *NetworkingSpec, out *kops.NetworkingSpec, s conversion.Scope) error {
 	if in.Classic != nil {
 		in, out := &in.Classic, &out.Classic
@@ -5946,6 +8146,26 @@ func Convert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in *kops.Nod
 	return autoConvert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodeLabelReconciliationSpec_To_kops_NodeLabelReconciliationSpec(in *NodeLabelReconciliationSpec, out *kops.NodeLabelReconciliationSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_v1alpha2_NodeLabelReconciliationSpec_To_kops_NodeLabelReconciliationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeLabelReconciliationSpec_To_kops_NodeLabelReconciliationSpec(in *NodeLabelReconciliationSpec, out *kops.NodeLabelReconciliationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeLabelReconciliationSpec_To_kops_NodeLabelReconciliationSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeLabelReconciliationSpec_To_v1alpha2_NodeLabelReconciliationSpec(in *kops.NodeLabelReconciliationSpec, out *NodeLabelReconciliationSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_kops_NodeLabelReconciliationSpec_To_v1alpha2_NodeLabelReconciliationSpec is an autogenerated conversion function.
+func Convert_kops_NodeLabelReconciliationSpec_To_v1alpha2_NodeLabelReconciliationSpec(in *kops.NodeLabelReconciliationSpec, out *NodeLabelReconciliationSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeLabelReconciliationSpec_To_v1alpha2_NodeLabelReconciliationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(in *NodeLocalDNSConfig, out *kops.NodeLocalDNSConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.LocalIP = in.LocalIP
@@ -5974,6 +8194,100 @@ func Convert_kops_NodeLocalDNSConfig_To_v1alpha2_NodeLocalDNSConfig(in *kops.Nod
 	return autoConvert_kops_NodeLocalDNSConfig_To_v1alpha2_NodeLocalDNSConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodePluginArtifact_To_kops_NodePluginArtifact(in *NodePluginArtifact, out *kops.NodePluginArtifact, s conversion.Scope) error {
+	out.Source = in.Source
+	out.Hash = in.Hash
+	return nil
+}
+
+// Convert_v1alpha2_NodePluginArtifact_To_kops_NodePluginArtifact is an autogenerated conversion function.
+func Convert_v1alpha2_NodePluginArtifact_To_kops_NodePluginArtifact(in *NodePluginArtifact, out *kops.NodePluginArtifact, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodePluginArtifact_To_kops_NodePluginArtifact(in, out, s)
+}
+
+func autoConvert_kops_NodePluginArtifact_To_v1alpha2_NodePluginArtifact(in *kops.NodePluginArtifact, out *NodePluginArtifact, s conversion.Scope) error {
+	out.Source = in.Source
+	out.Hash = in.Hash
+	return nil
+}
+
+// Convert_kops_NodePluginArtifact_To_v1alpha2_NodePluginArtifact is an autogenerated conversion function.
+func Convert_kops_NodePluginArtifact_To_v1alpha2_NodePluginArtifact(in *kops.NodePluginArtifact, out *NodePluginArtifact, s conversion.Scope) error {
+	return autoConvert_kops_NodePluginArtifact_To_v1alpha2_NodePluginArtifact(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodePluginSpec_To_kops_NodePluginSpec(in *NodePluginSpec, out *kops.NodePluginSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Disabled = in.Disabled
+	out.Stage = kops.NodePluginStage(in.Stage)
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]kops.InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = kops.InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]kops.NodePluginArtifact, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_NodePluginArtifact_To_kops_NodePluginArtifact(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Artifacts = nil
+	}
+	out.Command = in.Command
+	out.Environment = in.Environment
+	out.Timeout = in.Timeout
+	out.Retries = in.Retries
+	return nil
+}
+
+// Convert_v1alpha2_NodePluginSpec_To_kops_NodePluginSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodePluginSpec_To_kops_NodePluginSpec(in *NodePluginSpec, out *kops.NodePluginSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodePluginSpec_To_kops_NodePluginSpec(in, out, s)
+}
+
+func autoConvert_kops_NodePluginSpec_To_v1alpha2_NodePluginSpec(in *kops.NodePluginSpec, out *NodePluginSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Disabled = in.Disabled
+	out.Stage = NodePluginStage(in.Stage)
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]NodePluginArtifact, len(*in))
+		for i := range *in {
+			if err := Convert_kops_NodePluginArtifact_To_v1alpha2_NodePluginArtifact(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Artifacts = nil
+	}
+	out.Command = in.Command
+	out.Environment = in.Environment
+	out.Timeout = in.Timeout
+	out.Retries = in.Retries
+	return nil
+}
+
+// Convert_kops_NodePluginSpec_To_v1alpha2_NodePluginSpec is an autogenerated conversion function.
+func Convert_kops_NodePluginSpec_To_v1alpha2_NodePluginSpec(in *kops.NodePluginSpec, out *NodePluginSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodePluginSpec_To_v1alpha2_NodePluginSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_NodeProblemDetectorConfig_To_kops_NodeProblemDetectorConfig(in *NodeProblemDetectorConfig, out *kops.NodeProblemDetectorConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Image = in.Image
@@ -5981,6 +8295,15 @@ func autoConvert_v1alpha2_NodeProblemDetectorConfig_To_kops_NodeProblemDetectorC
 	out.CPURequest = in.CPURequest
 	out.MemoryLimit = in.MemoryLimit
 	out.CPULimit = in.CPULimit
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(kops.NodeRemediationSpec)
+		if err := Convert_v1alpha2_NodeRemediationSpec_To_kops_NodeRemediationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Remediation = nil
+	}
 	return nil
 }
 
@@ -5996,6 +8319,15 @@ func autoConvert_kops_NodeProblemDetectorConfig_To_v1alpha2_NodeProblemDetectorC
 	out.CPURequest = in.CPURequest
 	out.MemoryLimit = in.MemoryLimit
 	out.CPULimit = in.CPULimit
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(NodeRemediationSpec)
+		if err := Convert_kops_NodeRemediationSpec_To_v1alpha2_NodeRemediationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Remediation = nil
+	}
 	return nil
 }
 
@@ -6004,6 +8336,34 @@ func Convert_kops_NodeProblemDetectorConfig_To_v1alpha2_NodeProblemDetectorConfi
 	return autoConvert_kops_NodeProblemDetectorConfig_To_v1alpha2_NodeProblemDetectorConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodeRemediationSpec_To_kops_NodeRemediationSpec(in *NodeRemediationSpec, out *kops.NodeRemediationSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Conditions = in.Conditions
+	out.Threshold = in.Threshold
+	out.MaxConcurrent = in.MaxConcurrent
+	out.Recycle = in.Recycle
+	return nil
+}
+
+// Convert_v1alpha2_NodeRemediationSpec_To_kops_NodeRemediationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeRemediationSpec_To_kops_NodeRemediationSpec(in *NodeRemediationSpec, out *kops.NodeRemediationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeRemediationSpec_To_kops_NodeRemediationSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeRemediationSpec_To_v1alpha2_NodeRemediationSpec(in *kops.NodeRemediationSpec, out *NodeRemediationSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Conditions = in.Conditions
+	out.Threshold = in.Threshold
+	out.MaxConcurrent = in.MaxConcurrent
+	out.Recycle = in.Recycle
+	return nil
+}
+
+// Convert_kops_NodeRemediationSpec_To_v1alpha2_NodeRemediationSpec is an autogenerated conversion function.
+func Convert_kops_NodeRemediationSpec_To_v1alpha2_NodeRemediationSpec(in *kops.NodeRemediationSpec, out *NodeRemediationSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeRemediationSpec_To_v1alpha2_NodeRemediationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_NodeTerminationHandlerConfig_To_kops_NodeTerminationHandlerConfig(in *NodeTerminationHandlerConfig, out *kops.NodeTerminationHandlerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.EnableSpotInterruptionDraining = in.EnableSpotInterruptionDraining
@@ -6016,26 +8376,124 @@ func autoConvert_v1alpha2_NodeTerminationHandlerConfig_To_kops_NodeTerminationHa
 	return nil
 }
 
-// Convert_v1alpha2_NodeTerminationHandlerConfig_To_kops_NodeTerminationHandlerConfig is an autogenerated conversion function.
-func Convert_v1alpha2_NodeTerminationHandlerConfig_To_kops_NodeTerminationHandlerConfig(in *NodeTerminationHandlerConfig, out *kops.NodeTerminationHandlerConfig, s conversion.Scope) error {
-	return autoConvert_v1alpha2_NodeTerminationHandlerConfig_To_kops_NodeTerminationHandlerConfig(in, out, s)
+// Convert_v1alpha2_NodeTerminationHandlerConfig_To_kops_NodeTerminationHandlerConfig is an autogenerated conversion function.
+func Convert_v1alpha2_NodeTerminationHandlerConfig_To_kops_NodeTerminationHandlerConfig(in *NodeTerminationHandlerConfig, out *kops.NodeTerminationHandlerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeTerminationHandlerConfig_To_kops_NodeTerminationHandlerConfig(in, out, s)
+}
+
+func autoConvert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig(in *kops.NodeTerminationHandlerConfig, out *NodeTerminationHandlerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.EnableSpotInterruptionDraining = in.EnableSpotInterruptionDraining
+	out.EnableScheduledEventDraining = in.EnableScheduledEventDraining
+	out.EnablePrometheusMetrics = in.EnablePrometheusMetrics
+	out.EnableSQSTerminationDraining = in.EnableSQSTerminationDraining
+	out.ManagedASGTag = in.ManagedASGTag
+	out.MemoryRequest = in.MemoryRequest
+	out.CPURequest = in.CPURequest
+	return nil
+}
+
+// Convert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig is an autogenerated conversion function.
+func Convert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig(in *kops.NodeTerminationHandlerConfig, out *NodeTerminationHandlerConfig, s conversion.Scope) error {
+	return autoConvert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_OCIConfiguration_To_kops_OCIConfiguration(in *OCIConfiguration, out *kops.OCIConfiguration, s conversion.Scope) error {
+	out.CompartmentID = in.CompartmentID
+	out.TagNamespace = in.TagNamespace
+	return nil
+}
+
+// Convert_v1alpha2_OCIConfiguration_To_kops_OCIConfiguration is an autogenerated conversion function.
+func Convert_v1alpha2_OCIConfiguration_To_kops_OCIConfiguration(in *OCIConfiguration, out *kops.OCIConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha2_OCIConfiguration_To_kops_OCIConfiguration(in, out, s)
+}
+
+func autoConvert_kops_OCIConfiguration_To_v1alpha2_OCIConfiguration(in *kops.OCIConfiguration, out *OCIConfiguration, s conversion.Scope) error {
+	out.CompartmentID = in.CompartmentID
+	out.TagNamespace = in.TagNamespace
+	return nil
+}
+
+// Convert_kops_OCIConfiguration_To_v1alpha2_OCIConfiguration is an autogenerated conversion function.
+func Convert_kops_OCIConfiguration_To_v1alpha2_OCIConfiguration(in *kops.OCIConfiguration, out *OCIConfiguration, s conversion.Scope) error {
+	return autoConvert_kops_OCIConfiguration_To_v1alpha2_OCIConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(in *OIDCAuthenticationSpec, out *kops.OIDCAuthenticationSpec, s conversion.Scope) error {
+	out.IssuerURL = in.IssuerURL
+	out.ClientID = in.ClientID
+	out.UsernameClaim = in.UsernameClaim
+	out.UsernamePrefix = in.UsernamePrefix
+	out.GroupsClaim = in.GroupsClaim
+	out.GroupsPrefix = in.GroupsPrefix
+	out.RequiredClaims = in.RequiredClaims
+	if in.Dex != nil {
+		in, out := &in.Dex, &out.Dex
+		*out = new(kops.DexSpec)
+		if err := Convert_v1alpha2_DexSpec_To_kops_DexSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Dex = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(in *OIDCAuthenticationSpec, out *kops.OIDCAuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(in, out, s)
+}
+
+func autoConvert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(in *kops.OIDCAuthenticationSpec, out *OIDCAuthenticationSpec, s conversion.Scope) error {
+	out.IssuerURL = in.IssuerURL
+	out.ClientID = in.ClientID
+	out.UsernameClaim = in.UsernameClaim
+	out.UsernamePrefix = in.UsernamePrefix
+	out.GroupsClaim = in.GroupsClaim
+	out.GroupsPrefix = in.GroupsPrefix
+	out.RequiredClaims = in.RequiredClaims
+	if in.Dex != nil {
+		in, out := &in.Dex, &out.Dex
+		*out = new(DexSpec)
+		if err := Convert_kops_DexSpec_To_v1alpha2_DexSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Dex = nil
+	}
+	return nil
+}
+
+// Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec is an autogenerated conversion function.
+func Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(in *kops.OIDCAuthenticationSpec, out *OIDCAuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_OSPatchingSpec_To_kops_OSPatchingSpec(in *OSPatchingSpec, out *kops.OSPatchingSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Method = in.Method
+	out.Reboot = in.Reboot
+	out.InstanceGroups = in.InstanceGroups
+	return nil
+}
+
+// Convert_v1alpha2_OSPatchingSpec_To_kops_OSPatchingSpec is an autogenerated conversion function.
+func Convert_v1alpha2_OSPatchingSpec_To_kops_OSPatchingSpec(in *OSPatchingSpec, out *kops.OSPatchingSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_OSPatchingSpec_To_kops_OSPatchingSpec(in, out, s)
 }
 
-func autoConvert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig(in *kops.NodeTerminationHandlerConfig, out *NodeTerminationHandlerConfig, s conversion.Scope) error {
-	out.Enabled = in.Enabled
-	out.EnableSpotInterruptionDraining = in.EnableSpotInterruptionDraining
-	out.EnableScheduledEventDraining = in.EnableScheduledEventDraining
-	out.EnablePrometheusMetrics = in.EnablePrometheusMetrics
-	out.EnableSQSTerminationDraining = in.EnableSQSTerminationDraining
-	out.ManagedASGTag = in.ManagedASGTag
-	out.MemoryRequest = in.MemoryRequest
-	out.CPURequest = in.CPURequest
+func autoConvert_kops_OSPatchingSpec_To_v1alpha2_OSPatchingSpec(in *kops.OSPatchingSpec, out *OSPatchingSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Method = in.Method
+	out.Reboot = in.Reboot
+	out.InstanceGroups = in.InstanceGroups
 	return nil
 }
 
-// Convert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig is an autogenerated conversion function.
-func Convert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig(in *kops.NodeTerminationHandlerConfig, out *NodeTerminationHandlerConfig, s conversion.Scope) error {
-	return autoConvert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig(in, out, s)
+// Convert_kops_OSPatchingSpec_To_v1alpha2_OSPatchingSpec is an autogenerated conversion function.
+func Convert_kops_OSPatchingSpec_To_v1alpha2_OSPatchingSpec(in *kops.OSPatchingSpec, out *OSPatchingSpec, s conversion.Scope) error {
+	return autoConvert_kops_OSPatchingSpec_To_v1alpha2_OSPatchingSpec(in, out, s)
 }
 
 func autoConvert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(in *OpenstackBlockStorageConfig, out *kops.OpenstackBlockStorageConfig, s conversion.Scope) error {
@@ -6308,7 +8766,120 @@ func Convert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(in *kops.PackagesCon
 	return autoConvert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_PodSecurityAdmissionSpec_To_kops_PodSecurityAdmissionSpec(in *PodSecurityAdmissionSpec, out *kops.PodSecurityAdmissionSpec, s conversion.Scope) error {
+	out.Enforce = in.Enforce
+	out.Audit = in.Audit
+	out.Warn = in.Warn
+	out.Version = in.Version
+	out.ExemptNamespaces = in.ExemptNamespaces
+	out.ExemptUsernames = in.ExemptUsernames
+	out.ExemptRuntimeClasses = in.ExemptRuntimeClasses
+	return nil
+}
+
+// Convert_v1alpha2_PodSecurityAdmissionSpec_To_kops_PodSecurityAdmissionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_PodSecurityAdmissionSpec_To_kops_PodSecurityAdmissionSpec(in *PodSecurityAdmissionSpec, out *kops.PodSecurityAdmissionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PodSecurityAdmissionSpec_To_kops_PodSecurityAdmissionSpec(in, out, s)
+}
+
+func autoConvert_kops_PodSecurityAdmissionSpec_To_v1alpha2_PodSecurityAdmissionSpec(in *kops.PodSecurityAdmissionSpec, out *PodSecurityAdmissionSpec, s conversion.Scope) error {
+	out.Enforce = in.Enforce
+	out.Audit = in.Audit
+	out.Warn = in.Warn
+	out.Version = in.Version
+	out.ExemptNamespaces = in.ExemptNamespaces
+	out.ExemptUsernames = in.ExemptUsernames
+	out.ExemptRuntimeClasses = in.ExemptRuntimeClasses
+	return nil
+}
+
+// Convert_kops_PodSecurityAdmissionSpec_To_v1alpha2_PodSecurityAdmissionSpec is an autogenerated conversion function.
+func Convert_kops_PodSecurityAdmissionSpec_To_v1alpha2_PodSecurityAdmissionSpec(in *kops.PodSecurityAdmissionSpec, out *PodSecurityAdmissionSpec, s conversion.Scope) error {
+	return autoConvert_kops_PodSecurityAdmissionSpec_To_v1alpha2_PodSecurityAdmissionSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_ProvisioningMachineTypeSpec_To_kops_ProvisioningMachineTypeSpec(in *ProvisioningMachineTypeSpec, out *kops.ProvisioningMachineTypeSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
+	out.ProvisioningModel = in.ProvisioningModel
+	return nil
+}
+
+// Convert_v1alpha2_ProvisioningMachineTypeSpec_To_kops_ProvisioningMachineTypeSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ProvisioningMachineTypeSpec_To_kops_ProvisioningMachineTypeSpec(in *ProvisioningMachineTypeSpec, out *kops.ProvisioningMachineTypeSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ProvisioningMachineTypeSpec_To_kops_ProvisioningMachineTypeSpec(in, out, s)
+}
+
+func autoConvert_kops_ProvisioningMachineTypeSpec_To_v1alpha2_ProvisioningMachineTypeSpec(in *kops.ProvisioningMachineTypeSpec, out *ProvisioningMachineTypeSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
+	out.ProvisioningModel = in.ProvisioningModel
+	return nil
+}
+
+// Convert_kops_ProvisioningMachineTypeSpec_To_v1alpha2_ProvisioningMachineTypeSpec is an autogenerated conversion function.
+func Convert_kops_ProvisioningMachineTypeSpec_To_v1alpha2_ProvisioningMachineTypeSpec(in *kops.ProvisioningMachineTypeSpec, out *ProvisioningMachineTypeSpec, s conversion.Scope) error {
+	return autoConvert_kops_ProvisioningMachineTypeSpec_To_v1alpha2_ProvisioningMachineTypeSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_ProvisioningPolicySpec_To_kops_ProvisioningPolicySpec(in *ProvisioningPolicySpec, out *kops.ProvisioningPolicySpec, s conversion.Scope) error {
+	out.ProvisioningModel = in.ProvisioningModel
+	if in.MachineTypes != nil {
+		in, out := &in.MachineTypes, &out.MachineTypes
+		*out = make([]kops.ProvisioningMachineTypeSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_ProvisioningMachineTypeSpec_To_kops_ProvisioningMachineTypeSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.MachineTypes = nil
+	}
+	out.AutomaticRestart = in.AutomaticRestart
+	out.OnHostMaintenance = in.OnHostMaintenance
+	return nil
+}
+
+// Convert_v1alpha2_ProvisioningPolicySpec_To_kops_ProvisioningPolicySpec is an autogenerated conversion function.
+func Convert_v1alpha2_ProvisioningPolicySpec_To_kops_ProvisioningPolicySpec(in *ProvisioningPolicySpec, out *kops.ProvisioningPolicySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ProvisioningPolicySpec_To_kops_ProvisioningPolicySpec(in, out, s)
+}
+
+func autoConvert_kops_ProvisioningPolicySpec_To_v1alpha2_ProvisioningPolicySpec(in *kops.ProvisioningPolicySpec, out *ProvisioningPolicySpec, s conversion.Scope) error {
+	out.ProvisioningModel = in.ProvisioningModel
+	if in.MachineTypes != nil {
+		in, out := &in.MachineTypes, &out.MachineTypes
+		*out = make([]ProvisioningMachineTypeSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_ProvisioningMachineTypeSpec_To_v1alpha2_ProvisioningMachineTypeSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.MachineTypes = nil
+	}
+	out.AutomaticRestart = in.AutomaticRestart
+	out.OnHostMaintenance = in.OnHostMaintenance
+	return nil
+}
+
+// Convert_kops_ProvisioningPolicySpec_To_v1alpha2_ProvisioningPolicySpec is an autogenerated conversion function.
+func Convert_kops_ProvisioningPolicySpec_To_v1alpha2_ProvisioningPolicySpec(in *kops.ProvisioningPolicySpec, out *ProvisioningPolicySpec, s conversion.Scope) error {
+	return autoConvert_kops_ProvisioningPolicySpec_To_v1alpha2_ProvisioningPolicySpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(in *RBACAuthorizationSpec, out *kops.RBACAuthorizationSpec, s conversion.Scope) error {
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]kops.RBACBindingSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_RBACBindingSpec_To_kops_RBACBindingSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Bindings = nil
+	}
 	return nil
 }
 
@@ -6318,6 +8889,17 @@ func Convert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(in *RB
 }
 
 func autoConvert_kops_RBACAuthorizationSpec_To_v1alpha2_RBACAuthorizationSpec(in *kops.RBACAuthorizationSpec, out *RBACAuthorizationSpec, s conversion.Scope) error {
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]RBACBindingSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_RBACBindingSpec_To_v1alpha2_RBACBindingSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Bindings = nil
+	}
 	return nil
 }
 
@@ -6326,10 +8908,48 @@ func Convert_kops_RBACAuthorizationSpec_To_v1alpha2_RBACAuthorizationSpec(in *ko
 	return autoConvert_kops_RBACAuthorizationSpec_To_v1alpha2_RBACAuthorizationSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_RBACBindingSpec_To_kops_RBACBindingSpec(in *RBACBindingSpec, out *kops.RBACBindingSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ClusterRole = in.ClusterRole
+	out.Namespace = in.Namespace
+	out.Groups = in.Groups
+	out.Users = in.Users
+	return nil
+}
+
+// Convert_v1alpha2_RBACBindingSpec_To_kops_RBACBindingSpec is an autogenerated conversion function.
+func Convert_v1alpha2_RBACBindingSpec_To_kops_RBACBindingSpec(in *RBACBindingSpec, out *kops.RBACBindingSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RBACBindingSpec_To_kops_RBACBindingSpec(in, out, s)
+}
+
+func autoConvert_kops_RBACBindingSpec_To_v1alpha2_RBACBindingSpec(in *kops.RBACBindingSpec, out *RBACBindingSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ClusterRole = in.ClusterRole
+	out.Namespace = in.Namespace
+	out.Groups = in.Groups
+	out.Users = in.Users
+	return nil
+}
+
+// Convert_kops_RBACBindingSpec_To_v1alpha2_RBACBindingSpec is an autogenerated conversion function.
+func Convert_kops_RBACBindingSpec_To_v1alpha2_RBACBindingSpec(in *kops.RBACBindingSpec, out *RBACBindingSpec, s conversion.Scope) error {
+	return autoConvert_kops_RBACBindingSpec_To_v1alpha2_RBACBindingSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate, out *kops.RollingUpdate, s conversion.Scope) error {
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	out.Strategy = kops.RollingUpdateStrategy(in.Strategy)
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(kops.DrainSpec)
+		if err := Convert_v1alpha2_DrainSpec_To_kops_DrainSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Drain = nil
+	}
 	return nil
 }
 
@@ -6342,6 +8962,16 @@ func autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUp
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	out.Strategy = RollingUpdateStrategy(in.Strategy)
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(DrainSpec)
+		if err := Convert_kops_DrainSpec_To_v1alpha2_DrainSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Drain = nil
+	}
 	return nil
 }
 
@@ -6372,6 +9002,26 @@ func Convert_kops_RomanaNetworkingSpec_To_v1alpha2_RomanaNetworkingSpec(in *kops
 	return autoConvert_kops_RomanaNetworkingSpec_To_v1alpha2_RomanaNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_SELinuxSpec_To_kops_SELinuxSpec(in *SELinuxSpec, out *kops.SELinuxSpec, s conversion.Scope) error {
+	out.Mode = in.Mode
+	return nil
+}
+
+// Convert_v1alpha2_SELinuxSpec_To_kops_SELinuxSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SELinuxSpec_To_kops_SELinuxSpec(in *SELinuxSpec, out *kops.SELinuxSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SELinuxSpec_To_kops_SELinuxSpec(in, out, s)
+}
+
+func autoConvert_kops_SELinuxSpec_To_v1alpha2_SELinuxSpec(in *kops.SELinuxSpec, out *SELinuxSpec, s conversion.Scope) error {
+	out.Mode = in.Mode
+	return nil
+}
+
+// Convert_kops_SELinuxSpec_To_v1alpha2_SELinuxSpec is an autogenerated conversion function.
+func Convert_kops_SELinuxSpec_To_v1alpha2_SELinuxSpec(in *kops.SELinuxSpec, out *SELinuxSpec, s conversion.Scope) error {
+	return autoConvert_kops_SELinuxSpec_To_v1alpha2_SELinuxSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_SSHCredential_To_kops_SSHCredential(in *SSHCredential, out *kops.SSHCredential, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_SSHCredentialSpec_To_kops_SSHCredentialSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -6460,6 +9110,72 @@ func Convert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in *kops.SSHCr
 	return autoConvert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_SSHPublicKeySpec_To_kops_SSHPublicKeySpec(in *SSHPublicKeySpec, out *kops.SSHPublicKeySpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.PublicKey = in.PublicKey
+	return nil
+}
+
+// Convert_v1alpha2_SSHPublicKeySpec_To_kops_SSHPublicKeySpec is an autogenerated conversion function.
+func Convert_v1alpha2_SSHPublicKeySpec_To_kops_SSHPublicKeySpec(in *SSHPublicKeySpec, out *kops.SSHPublicKeySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SSHPublicKeySpec_To_kops_SSHPublicKeySpec(in, out, s)
+}
+
+func autoConvert_kops_SSHPublicKeySpec_To_v1alpha2_SSHPublicKeySpec(in *kops.SSHPublicKeySpec, out *SSHPublicKeySpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.PublicKey = in.PublicKey
+	return nil
+}
+
+// Convert_kops_SSHPublicKeySpec_To_v1alpha2_SSHPublicKeySpec is an autogenerated conversion function.
+func Convert_kops_SSHPublicKeySpec_To_v1alpha2_SSHPublicKeySpec(in *kops.SSHPublicKeySpec, out *SSHPublicKeySpec, s conversion.Scope) error {
+	return autoConvert_kops_SSHPublicKeySpec_To_v1alpha2_SSHPublicKeySpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_SSHSpec_To_kops_SSHSpec(in *SSHSpec, out *kops.SSHSpec, s conversion.Scope) error {
+	out.User = in.User
+	out.InstanceConnect = in.InstanceConnect
+	if in.PublicKeys != nil {
+		in, out := &in.PublicKeys, &out.PublicKeys
+		*out = make([]kops.SSHPublicKeySpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SSHPublicKeySpec_To_kops_SSHPublicKeySpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PublicKeys = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_SSHSpec_To_kops_SSHSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SSHSpec_To_kops_SSHSpec(in *SSHSpec, out *kops.SSHSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SSHSpec_To_kops_SSHSpec(in, out, s)
+}
+
+func autoConvert_kops_SSHSpec_To_v1alpha2_SSHSpec(in *kops.SSHSpec, out *SSHSpec, s conversion.Scope) error {
+	out.User = in.User
+	out.InstanceConnect = in.InstanceConnect
+	if in.PublicKeys != nil {
+		in, out := &in.PublicKeys, &out.PublicKeys
+		*out = make([]SSHPublicKeySpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SSHPublicKeySpec_To_v1alpha2_SSHPublicKeySpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PublicKeys = nil
+	}
+	return nil
+}
+
+// Convert_kops_SSHSpec_To_v1alpha2_SSHSpec is an autogenerated conversion function.
+func Convert_kops_SSHSpec_To_v1alpha2_SSHSpec(in *kops.SSHSpec, out *SSHSpec, s conversion.Scope) error {
+	return autoConvert_kops_SSHSpec_To_v1alpha2_SSHSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_ServiceAccountExternalPermission_To_kops_ServiceAccountExternalPermission(in *ServiceAccountExternalPermission, out *kops.ServiceAccountExternalPermission, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Namespace = in.Namespace
@@ -6522,6 +9238,96 @@ func Convert_kops_ServiceAccountIssuerDiscoveryConfig_To_v1alpha2_ServiceAccount
 	return autoConvert_kops_ServiceAccountIssuerDiscoveryConfig_To_v1alpha2_ServiceAccountIssuerDiscoveryConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_SessionManagerAgentPackage_To_kops_SessionManagerAgentPackage(in *SessionManagerAgentPackage, out *kops.SessionManagerAgentPackage, s conversion.Scope) error {
+	out.Architecture = in.Architecture
+	out.URL = in.URL
+	out.Hash = in.Hash
+	return nil
+}
+
+// Convert_v1alpha2_SessionManagerAgentPackage_To_kops_SessionManagerAgentPackage is an autogenerated conversion function.
+func Convert_v1alpha2_SessionManagerAgentPackage_To_kops_SessionManagerAgentPackage(in *SessionManagerAgentPackage, out *kops.SessionManagerAgentPackage, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SessionManagerAgentPackage_To_kops_SessionManagerAgentPackage(in, out, s)
+}
+
+func autoConvert_kops_SessionManagerAgentPackage_To_v1alpha2_SessionManagerAgentPackage(in *kops.SessionManagerAgentPackage, out *SessionManagerAgentPackage, s conversion.Scope) error {
+	out.Architecture = in.Architecture
+	out.URL = in.URL
+	out.Hash = in.Hash
+	return nil
+}
+
+// Convert_kops_SessionManagerAgentPackage_To_v1alpha2_SessionManagerAgentPackage is an autogenerated conversion function.
+func Convert_kops_SessionManagerAgentPackage_To_v1alpha2_SessionManagerAgentPackage(in *kops.SessionManagerAgentPackage, out *SessionManagerAgentPackage, s conversion.Scope) error {
+	return autoConvert_kops_SessionManagerAgentPackage_To_v1alpha2_SessionManagerAgentPackage(in, out, s)
+}
+
+func autoConvert_v1alpha2_SessionManagerSpec_To_kops_SessionManagerSpec(in *SessionManagerSpec, out *kops.SessionManagerSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	if in.AgentPackages != nil {
+		in, out := &in.AgentPackages, &out.AgentPackages
+		*out = make([]kops.SessionManagerAgentPackage, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SessionManagerAgentPackage_To_kops_SessionManagerAgentPackage(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.AgentPackages = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_SessionManagerSpec_To_kops_SessionManagerSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SessionManagerSpec_To_kops_SessionManagerSpec(in *SessionManagerSpec, out *kops.SessionManagerSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SessionManagerSpec_To_kops_SessionManagerSpec(in, out, s)
+}
+
+func autoConvert_kops_SessionManagerSpec_To_v1alpha2_SessionManagerSpec(in *kops.SessionManagerSpec, out *SessionManagerSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	if in.AgentPackages != nil {
+		in, out := &in.AgentPackages, &out.AgentPackages
+		*out = make([]SessionManagerAgentPackage, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SessionManagerAgentPackage_To_v1alpha2_SessionManagerAgentPackage(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.AgentPackages = nil
+	}
+	return nil
+}
+
+// Convert_kops_SessionManagerSpec_To_v1alpha2_SessionManagerSpec is an autogenerated conversion function.
+func Convert_kops_SessionManagerSpec_To_v1alpha2_SessionManagerSpec(in *kops.SessionManagerSpec, out *SessionManagerSpec, s conversion.Scope) error {
+	return autoConvert_kops_SessionManagerSpec_To_v1alpha2_SessionManagerSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_ShieldedVMSpec_To_kops_ShieldedVMSpec(in *ShieldedVMSpec, out *kops.ShieldedVMSpec, s conversion.Scope) error {
+	out.SecureBoot = in.SecureBoot
+	out.VTPM = in.VTPM
+	out.IntegrityMonitoring = in.IntegrityMonitoring
+	return nil
+}
+
+// Convert_v1alpha2_ShieldedVMSpec_To_kops_ShieldedVMSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ShieldedVMSpec_To_kops_ShieldedVMSpec(in *ShieldedVMSpec, out *kops.ShieldedVMSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ShieldedVMSpec_To_kops_ShieldedVMSpec(in, out, s)
+}
+
+func autoConvert_kops_ShieldedVMSpec_To_v1alpha2_ShieldedVMSpec(in *kops.ShieldedVMSpec, out *ShieldedVMSpec, s conversion.Scope) error {
+	out.SecureBoot = in.SecureBoot
+	out.VTPM = in.VTPM
+	out.IntegrityMonitoring = in.IntegrityMonitoring
+	return nil
+}
+
+// Convert_kops_ShieldedVMSpec_To_v1alpha2_ShieldedVMSpec is an autogenerated conversion function.
+func Convert_kops_ShieldedVMSpec_To_v1alpha2_ShieldedVMSpec(in *kops.ShieldedVMSpec, out *ShieldedVMSpec, s conversion.Scope) error {
+	return autoConvert_kops_ShieldedVMSpec_To_v1alpha2_ShieldedVMSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_SnapshotControllerConfig_To_kops_SnapshotControllerConfig(in *SnapshotControllerConfig, out *kops.SnapshotControllerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.InstallDefaultClass = in.InstallDefaultClass
@@ -6544,6 +9350,86 @@ func Convert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(
 	return autoConvert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_StateVolumesSpec_To_kops_StateVolumesSpec(in *StateVolumesSpec, out *kops.StateVolumesSpec, s conversion.Scope) error {
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(kops.VolumeSpec)
+		if err := Convert_v1alpha2_VolumeSpec_To_kops_VolumeSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Containerd = nil
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(kops.VolumeSpec)
+		if err := Convert_v1alpha2_VolumeSpec_To_kops_VolumeSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Kubelet = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_StateVolumesSpec_To_kops_StateVolumesSpec is an autogenerated conversion function.
+func Convert_v1alpha2_StateVolumesSpec_To_kops_StateVolumesSpec(in *StateVolumesSpec, out *kops.StateVolumesSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_StateVolumesSpec_To_kops_StateVolumesSpec(in, out, s)
+}
+
+func autoConvert_kops_StateVolumesSpec_To_v1alpha2_StateVolumesSpec(in *kops.StateVolumesSpec, out *StateVolumesSpec, s conversion.Scope) error {
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(VolumeSpec)
+		if err := Convert_kops_VolumeSpec_To_v1alpha2_VolumeSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Containerd = nil
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(VolumeSpec)
+		if err := Convert_kops_VolumeSpec_To_v1alpha2_VolumeSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Kubelet = nil
+	}
+	return nil
+}
+
+// Convert_kops_StateVolumesSpec_To_v1alpha2_StateVolumesSpec is an autogenerated conversion function.
+func Convert_kops_StateVolumesSpec_To_v1alpha2_StateVolumesSpec(in *kops.StateVolumesSpec, out *StateVolumesSpec, s conversion.Scope) error {
+	return autoConvert_kops_StateVolumesSpec_To_v1alpha2_StateVolumesSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_SwapSpec_To_kops_SwapSpec(in *SwapSpec, out *kops.SwapSpec, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Swappiness = in.Swappiness
+	out.ZRAM = in.ZRAM
+	out.SwapBehavior = in.SwapBehavior
+	return nil
+}
+
+// Convert_v1alpha2_SwapSpec_To_kops_SwapSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SwapSpec_To_kops_SwapSpec(in *SwapSpec, out *kops.SwapSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SwapSpec_To_kops_SwapSpec(in, out, s)
+}
+
+func autoConvert_kops_SwapSpec_To_v1alpha2_SwapSpec(in *kops.SwapSpec, out *SwapSpec, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Swappiness = in.Swappiness
+	out.ZRAM = in.ZRAM
+	out.SwapBehavior = in.SwapBehavior
+	return nil
+}
+
+// Convert_kops_SwapSpec_To_v1alpha2_SwapSpec is an autogenerated conversion function.
+func Convert_kops_SwapSpec_To_v1alpha2_SwapSpec(in *kops.SwapSpec, out *SwapSpec, s conversion.Scope) error {
+	return autoConvert_kops_SwapSpec_To_v1alpha2_SwapSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -6682,6 +9568,30 @@ func Convert_kops_UserData_To_v1alpha2_UserData(in *kops.UserData, out *UserData
 	return autoConvert_kops_UserData_To_v1alpha2_UserData(in, out, s)
 }
 
+func autoConvert_v1alpha2_VerticalPodAutoscalerConfig_To_kops_VerticalPodAutoscalerConfig(in *VerticalPodAutoscalerConfig, out *kops.VerticalPodAutoscalerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	out.CertificateValidity = in.CertificateValidity
+	return nil
+}
+
+// Convert_v1alpha2_VerticalPodAutoscalerConfig_To_kops_VerticalPodAutoscalerConfig is an autogenerated conversion function.
+func Convert_v1alpha2_VerticalPodAutoscalerConfig_To_kops_VerticalPodAutoscalerConfig(in *VerticalPodAutoscalerConfig, out *kops.VerticalPodAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_VerticalPodAutoscalerConfig_To_kops_VerticalPodAutoscalerConfig(in, out, s)
+}
+
+func autoConvert_kops_VerticalPodAutoscalerConfig_To_v1alpha2_VerticalPodAutoscalerConfig(in *kops.VerticalPodAutoscalerConfig, out *VerticalPodAutoscalerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	out.CertificateValidity = in.CertificateValidity
+	return nil
+}
+
+// Convert_kops_VerticalPodAutoscalerConfig_To_v1alpha2_VerticalPodAutoscalerConfig is an autogenerated conversion function.
+func Convert_kops_VerticalPodAutoscalerConfig_To_v1alpha2_VerticalPodAutoscalerConfig(in *kops.VerticalPodAutoscalerConfig, out *VerticalPodAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_kops_VerticalPodAutoscalerConfig_To_v1alpha2_VerticalPodAutoscalerConfig(in, out, s)
+}
+
 func autoConvert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpec, out *kops.VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -23,9 +23,12 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 )
@@ -38,11 +41,25 @@ func awsValidateCluster(c *kops.Cluster) field.ErrorList {
 			allErrs = append(allErrs, awsValidateAdditionalSecurityGroups(field.NewPath("spec", "api", "loadBalancer", "additionalSecurityGroups"), c.Spec.API.LoadBalancer.AdditionalSecurityGroups)...)
 			allErrs = append(allErrs, awsValidateSSLPolicy(field.NewPath("spec", "api", "loadBalancer", "sslPolicy"), c.Spec.API.LoadBalancer)...)
 			allErrs = append(allErrs, awsValidateLoadBalancerSubnets(field.NewPath("spec", "api", "loadBalancer", "subnets"), c.Spec)...)
+			allErrs = append(allErrs, awsValidateLoadBalancerTargetGroups(field.NewPath("spec", "api", "loadBalancer"), c.Spec.API.LoadBalancer)...)
+			if lbSpec := c.Spec.API.LoadBalancer; fi.BoolValue(lbSpec.AllocateElasticIPs) && (lbSpec.Class != kops.LoadBalancerClassNetwork || lbSpec.Type == kops.LoadBalancerTypeInternal) {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "api", "loadBalancer", "allocateElasticIPs"), "allocateElasticIPs only allowed for Public NLBs"))
+			}
 		}
 	}
 
+	if c.Spec.Topology != nil && c.Spec.Topology.Bastion != nil && c.Spec.Topology.Bastion.LoadBalancer != nil {
+		allErrs = append(allErrs, awsValidateBastionLoadBalancer(field.NewPath("spec", "topology", "bastion", "loadBalancer"), c.Spec)...)
+	}
+
 	allErrs = append(allErrs, awsValidateExternalCloudControllerManager(c)...)
 
+	for i, etcd := range c.Spec.EtcdClusters {
+		for j, m := range etcd.Members {
+			allErrs = append(allErrs, awsValidateEtcdMemberVolume(field.NewPath("spec", "etcdClusters").Index(i).Child("etcdMembers").Index(j), m)...)
+		}
+	}
+
 	return allErrs
 }
 
@@ -88,6 +105,160 @@ func awsValidateInstanceGroup(ig *kops.InstanceGroup, cloud awsup.AWSCloud) fiel
 		allErrs = append(allErrs, awsValidateCPUCredits(field.NewPath("spec"), &ig.Spec, cloud)...)
 	}
 
+	if ig.Spec.InstanceStorage != nil && ig.Spec.MixedInstancesPolicy == nil {
+		allErrs = append(allErrs, awsValidateInstanceStorage(field.NewPath("spec", "instanceStorage"), ig.Spec.MachineType, cloud)...)
+	}
+
+	allErrs = append(allErrs, awsValidateScaleIn(field.NewPath("spec"), ig)...)
+
+	allErrs = append(allErrs, awsValidateSuspendProcesses(field.NewPath("spec", "suspendProcesses"), ig.Spec.SuspendProcesses)...)
+
+	if ig.Spec.InstanceMaintenancePolicy != nil {
+		allErrs = append(allErrs, awsValidateInstanceMaintenancePolicy(field.NewPath("spec", "instanceMaintenancePolicy"), ig.Spec.InstanceMaintenancePolicy)...)
+	}
+
+	return allErrs
+}
+
+// awsValidateInstanceStorage checks that the machine type has instance store volumes;
+// the machine types of mixed instance policies are not checked, as the volumes are mounted when found
+func awsValidateInstanceStorage(fieldPath *field.Path, machineTypes string, cloud awsup.AWSCloud) field.ErrorList {
+	if cloud == nil || machineTypes == "" {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+
+	for _, machineType := range strings.Split(machineTypes, ",") {
+		info, err := awsup.GetMachineTypeInfo(cloud, machineType)
+		if err != nil {
+			// The machine type is already reported as invalid
+			continue
+		}
+		if len(info.EphemeralDisks) == 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath, fmt.Sprintf("machine type %q has no instance store volumes", machineType)))
+		}
+	}
+
+	return allErrs
+}
+
+// awsValidateEtcdMemberVolume checks the size, IOPS and throughput of an etcd volume against the limits of its EBS volume type
+func awsValidateEtcdMemberVolume(fieldPath *field.Path, m kops.EtcdMemberSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	volumeType := fi.StringValue(m.VolumeType)
+	if volumeType == "" {
+		volumeType = ec2.VolumeTypeGp3
+	}
+
+	limits, found := awsup.EBSVolumeTypeLimits[volumeType]
+	if !found {
+		return append(allErrs, field.NotSupported(fieldPath.Child("volumeType"), volumeType, sets.StringKeySet(awsup.EBSVolumeTypeLimits).List()))
+	}
+
+	// The etcd volumes are 20GB by default
+	volumeSize := int32(20)
+	if m.VolumeSize != nil {
+		volumeSize = *m.VolumeSize
+		if volumeSize < limits.MinSize || volumeSize > limits.MaxSize {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("volumeSize"), volumeSize, fmt.Sprintf("must be between %d and %d for %s volumes", limits.MinSize, limits.MaxSize, volumeType)))
+		}
+	}
+
+	// gp3 volumes are created with at least their baseline IOPS; the IOPS and throughput
+	// of volume types that can't provision them are ignored
+	volumeIops := limits.BaselineIops
+	if m.VolumeIops != nil && limits.MaxIops > 0 {
+		if *m.VolumeIops > volumeIops {
+			volumeIops = *m.VolumeIops
+		}
+		if volumeIops > limits.MaxIops {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("volumeIops"), volumeIops, fmt.Sprintf("must not be greater than %d for %s volumes", limits.MaxIops, volumeType)))
+		} else if volumeIops > limits.BaselineIops && volumeSize > 0 && volumeIops > limits.MaxIopsPerGB*volumeSize {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("volumeIops"), volumeIops, fmt.Sprintf("must not be greater than %d per GB of volumeSize for %s volumes", limits.MaxIopsPerGB, volumeType)))
+		}
+	}
+
+	if m.KmsKeyId != nil && !fi.BoolValue(m.EncryptedVolume) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("kmsKeyId"), "kmsKeyId requires encryptedVolume to be true"))
+	}
+
+	if m.VolumeThroughput != nil && limits.MaxThroughput > 0 {
+		volumeThroughput := *m.VolumeThroughput
+		if volumeThroughput > limits.MaxThroughput {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("volumeThroughput"), volumeThroughput, fmt.Sprintf("must not be greater than %d for %s volumes", limits.MaxThroughput, volumeType)))
+		} else if volumeIops > 0 && float64(volumeThroughput) > limits.MaxThroughputPerIops*float64(volumeIops) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("volumeThroughput"), volumeThroughput, fmt.Sprintf("must not be greater than %g per IOPS for %s volumes", limits.MaxThroughputPerIops, volumeType)))
+		}
+	}
+
+	return allErrs
+}
+
+// awsValidateSuspendProcesses validates the names of the autoscaling group processes to suspend
+func awsValidateSuspendProcesses(fieldPath *field.Path, processes []string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := sets.NewString()
+	for i, process := range processes {
+		processPath := fieldPath.Index(i)
+		if !sets.NewString(kops.AutoscalingProcesses...).Has(process) {
+			allErrs = append(allErrs, field.NotSupported(processPath, process, kops.AutoscalingProcesses))
+		} else if seen.Has(process) {
+			allErrs = append(allErrs, field.Duplicate(processPath, process))
+		}
+		seen.Insert(process)
+	}
+
+	return allErrs
+}
+
+// awsValidateInstanceMaintenancePolicy validates the healthy capacity range of the autoscaling group
+func awsValidateInstanceMaintenancePolicy(fieldPath *field.Path, policy *kops.InstanceMaintenancePolicySpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if policy.MinHealthyPercentage == nil {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("minHealthyPercentage"), "minHealthyPercentage must be set with an instance maintenance policy"))
+	} else if min := *policy.MinHealthyPercentage; min < 0 || min > 100 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("minHealthyPercentage"), min, "minHealthyPercentage must be a value between 0 and 100"))
+	}
+
+	if policy.MaxHealthyPercentage == nil {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("maxHealthyPercentage"), "maxHealthyPercentage must be set with an instance maintenance policy"))
+	} else if max := *policy.MaxHealthyPercentage; max < 100 || max > 200 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxHealthyPercentage"), max, "maxHealthyPercentage must be a value between 100 and 200"))
+	}
+
+	if len(allErrs) == 0 && *policy.MaxHealthyPercentage-*policy.MinHealthyPercentage > 100 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("maxHealthyPercentage"), *policy.MaxHealthyPercentage, "maxHealthyPercentage can be at most 100 above minHealthyPercentage"))
+	}
+
+	return allErrs
+}
+
+// awsValidateScaleIn validates the termination policies and scale in protection of the autoscaling group
+func awsValidateScaleIn(fieldPath *field.Path, ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ig.Spec.ScaleInProtection != "" {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("scaleInProtection"), &ig.Spec.ScaleInProtection, kops.ScaleInProtectionModes)...)
+		if ig.Spec.InstanceProtection != nil && !*ig.Spec.InstanceProtection {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("instanceProtection"), "instanceProtection cannot be disabled with scaleInProtection, which protects new instances from scale in"))
+		}
+	}
+
+	seen := sets.NewString()
+	for i, policy := range ig.Spec.TerminationPolicies {
+		policyPath := fieldPath.Child("terminationPolicies").Index(i)
+		if !sets.NewString(kops.TerminationPolicies...).Has(policy) {
+			allErrs = append(allErrs, field.NotSupported(policyPath, policy, kops.TerminationPolicies))
+		} else if seen.Has(policy) {
+			allErrs = append(allErrs, field.Duplicate(policyPath, policy))
+		}
+		seen.Insert(policy)
+	}
+
 	return allErrs
 }
 
@@ -143,6 +314,7 @@ func awsValidateInstanceTypeAndImage(instanceTypeFieldPath *field.Path, imageFie
 			fmt.Sprintf("image specified is invalid: %q", image)))
 	}
 	imageArch := fi.StringValue(imageInfo.Architecture)
+	imageVirtualization := fi.StringValue(imageInfo.VirtualizationType)
 
 	// Spotinst uses the instance type field to keep a "," separated list of instance types
 	for _, instanceType := range strings.Split(instanceTypes, ",") {
@@ -166,7 +338,167 @@ func awsValidateInstanceTypeAndImage(instanceTypeFieldPath *field.Path, imageFie
 				machineArch = fi.StringSliceValue(machineInfo.ProcessorInfo.SupportedArchitectures)
 			}
 			allErrs = append(allErrs, field.Invalid(instanceTypeFieldPath, instanceTypes,
-				fmt.Sprintf("machine type architecture does not match image architecture: %q - %q", strings.Join(machineArch, ","), imageArch)))
+				fmt.Sprintf("machine type architecture does not match image architecture: machine type %q supports %q, image %q is %q", instanceType, strings.Join(machineArch, ","), image, imageArch)))
+		}
+
+		// Instance types that do not report their virtualization types are not checked
+		if imageVirtualization != "" && machineInfo != nil && len(machineInfo.SupportedVirtualizationTypes) != 0 {
+			machineVirtualization := fi.StringSliceValue(machineInfo.SupportedVirtualizationTypes)
+			if !sets.NewString(machineVirtualization...).Has(imageVirtualization) {
+				allErrs = append(allErrs, field.Invalid(instanceTypeFieldPath, instanceTypes,
+					fmt.Sprintf("machine type virtualization does not match image virtualization: machine type %q supports %q, image %q is %q", instanceType, strings.Join(machineVirtualization, ","), image, imageVirtualization)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// awsValidateInstanceTypeZones checks that the machine types of the instance group are offered in all of its zones,
+// as the autoscaling group would otherwise fail to launch instances in the zones where they are not.
+func awsValidateInstanceTypeZones(ig *kops.InstanceGroup, cluster *kops.Cluster, cloud awsup.AWSCloud) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	zones, err := model.FindZonesForInstanceGroup(cluster, ig)
+	if err != nil || len(zones) == 0 {
+		// Unknown subnets are reported when cross-validating the subnets of the instance group
+		return allErrs
+	}
+
+	checkInstanceType := func(fieldPath *field.Path, instanceType string) {
+		offered, err := cloud.ZonesWithInstanceType(instanceType)
+		if err != nil {
+			klog.Warningf("unable to check the zones in which machine type %q is offered: %v", instanceType, err)
+			return
+		}
+		var missing []string
+		for _, zone := range zones {
+			if !offered.Has(zone) {
+				missing = append(missing, zone)
+			}
+		}
+		if len(missing) != 0 {
+			allErrs = append(allErrs, field.Invalid(fieldPath, instanceType,
+				fmt.Sprintf("machine type %q is not offered in zones: %s", instanceType, strings.Join(missing, ", "))))
+		}
+	}
+
+	if ig.Spec.MachineType != "" {
+		// Spotinst uses the instance type field to keep a "," separated list of instance types
+		for _, instanceType := range strings.Split(ig.Spec.MachineType, ",") {
+			checkInstanceType(field.NewPath("spec", "machineType"), instanceType)
+		}
+	}
+
+	if ig.Spec.MixedInstancesPolicy != nil {
+		for i, instanceType := range ig.Spec.MixedInstancesPolicy.Instances {
+			checkInstanceType(field.NewPath("spec", "mixedInstancesPolicy", "instances").Index(i), instanceType)
+		}
+	}
+
+	return allErrs
+}
+
+// awsFindManagedVPC returns the ID of the VPC that kops created for the cluster, or "" if it does not exist yet.
+// It is found the same way the VPC task finds it, by its Name tag and the tags of the cluster.
+func awsFindManagedVPC(cluster *kops.Cluster, cloud awsup.AWSCloud) (string, error) {
+	response, err := cloud.EC2().DescribeVpcs(&ec2.DescribeVpcsInput{
+		Filters: cloud.BuildFilters(fi.String(cluster.ObjectMeta.Name)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error listing VPCs: %v", err)
+	}
+	switch len(response.Vpcs) {
+	case 0:
+		return "", nil
+	case 1:
+		return fi.StringValue(response.Vpcs[0].VpcId), nil
+	default:
+		return "", fmt.Errorf("found multiple VPCs named %q", cluster.ObjectMeta.Name)
+	}
+}
+
+// awsValidateExternalTargetGroups checks that the external target groups of the instance group exist, can be attached
+// to its autoscaling group, and send traffic and health checks to the ports the instances are expected to serve.
+func awsValidateExternalTargetGroups(ig *kops.InstanceGroup, cluster *kops.Cluster, cloud awsup.AWSCloud) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	vpcID := cluster.Spec.NetworkID
+	vpcResolved := vpcID != ""
+
+	for i, lb := range ig.Spec.ExternalLoadBalancers {
+		if lb.TargetGroupARN == nil {
+			continue
+		}
+		fieldPath := field.NewPath("spec", "externalLoadBalancers").Index(i)
+
+		response, err := cloud.ELBV2().DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+			TargetGroupArns: []*string{lb.TargetGroupARN},
+		})
+		if err != nil {
+			if awsup.AWSErrorCode(err) == elbv2.ErrCodeTargetGroupNotFoundException {
+				allErrs = append(allErrs, field.NotFound(fieldPath.Child("targetGroupArn"), *lb.TargetGroupARN))
+			} else {
+				klog.Warningf("unable to check target group %q: %v", *lb.TargetGroupARN, err)
+			}
+			continue
+		}
+		if len(response.TargetGroups) != 1 {
+			allErrs = append(allErrs, field.NotFound(fieldPath.Child("targetGroupArn"), *lb.TargetGroupARN))
+			continue
+		}
+		tg := response.TargetGroups[0]
+
+		if !vpcResolved {
+			// The VPC is managed by kops, so it is only known once it has been created
+			vpcID, err = awsFindManagedVPC(cluster, cloud)
+			if err != nil {
+				klog.Warningf("unable to find the VPC of the cluster: %v", err)
+			}
+			vpcResolved = err == nil
+		}
+		if vpcResolved && tg.VpcId != nil && *tg.VpcId != vpcID {
+			if vpcID == "" {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("targetGroupArn"), *lb.TargetGroupARN,
+					fmt.Sprintf("target group is in VPC %q, but the VPC of the cluster has not been created yet", *tg.VpcId)))
+			} else {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("targetGroupArn"), *lb.TargetGroupARN,
+					fmt.Sprintf("target group is in VPC %q, not in the VPC of the cluster %q", *tg.VpcId, vpcID)))
+			}
+		}
+		if targetType := fi.StringValue(tg.TargetType); targetType != "" && targetType != elbv2.TargetTypeEnumInstance {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("targetGroupArn"), *lb.TargetGroupARN,
+				fmt.Sprintf("target group has target type %q; only target groups of type %q can be attached to instance groups", targetType, elbv2.TargetTypeEnumInstance)))
+		}
+		if lb.Port != nil && tg.Port != nil && *lb.Port != *tg.Port {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("port"), *lb.Port,
+				fmt.Sprintf("target group sends traffic to port %d", *tg.Port)))
+		}
+		if lb.Protocol != nil && tg.Protocol != nil && *lb.Protocol != *tg.Protocol {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("protocol"), *lb.Protocol,
+				fmt.Sprintf("target group sends traffic with protocol %q", *tg.Protocol)))
+		}
+
+		healthCheckPort := lb.HealthCheckPort
+		if healthCheckPort == nil {
+			healthCheckPort = lb.Port
+		}
+		if healthCheckPort != nil {
+			actual := tg.Port
+			if port := fi.StringValue(tg.HealthCheckPort); port != "" && port != "traffic-port" {
+				if parsed, err := strconv.ParseInt(port, 10, 64); err == nil {
+					actual = &parsed
+				}
+			}
+			if actual != nil && *actual != *healthCheckPort {
+				if lb.HealthCheckPort != nil {
+					allErrs = append(allErrs, field.Invalid(fieldPath.Child("healthCheckPort"), *lb.HealthCheckPort,
+						fmt.Sprintf("target group sends health checks to port %d", *actual)))
+				} else {
+					allErrs = append(allErrs, field.Invalid(fieldPath.Child("port"), *lb.Port,
+						fmt.Sprintf("target group sends health checks to port %d; set healthCheckPort if the instances answer them on that port", *actual)))
+				}
+			}
 		}
 	}
 
@@ -198,7 +530,7 @@ func awsValidateMixedInstancesPolicy(path *field.Path, spec *kops.MixedInstances
 
 	// @step: check the instance types are valid
 	for i, instanceType := range spec.Instances {
-		errs = append(errs, awsValidateInstanceTypeAndImage(path.Child("instances").Index(i), path.Child("image"), instanceType, ig.Spec.Image, cloud)...)
+		errs = append(errs, awsValidateInstanceTypeAndImage(path.Child("instances").Index(i), field.NewPath("spec", "image"), instanceType, ig.Spec.Image, cloud)...)
 	}
 
 	if spec.OnDemandBase != nil {
@@ -295,6 +627,112 @@ func awsValidateLoadBalancerSubnets(fieldPath *field.Path, spec kops.ClusterSpec
 	return allErrs
 }
 
+// awsValidateBastionLoadBalancer validates the addresses of the bastion's load balancer in the utility subnets
+func awsValidateBastionLoadBalancer(fieldPath *field.Path, spec kops.ClusterSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	lbSpec := spec.Topology.Bastion.LoadBalancer
+	isNLB := lbSpec.Class == kops.LoadBalancerClassNetwork
+
+	names := sets.NewString()
+	for i, subnet := range lbSpec.Subnets {
+		subnetPath := fieldPath.Child("subnets").Index(i)
+		if !isNLB {
+			allErrs = append(allErrs, field.Forbidden(subnetPath, "subnets only allowed for Network load balancers"))
+			continue
+		}
+
+		if subnet.Name == "" {
+			allErrs = append(allErrs, field.Required(subnetPath.Child("name"), "subnet name can't be empty"))
+		} else {
+			found := false
+			for _, cs := range spec.Subnets {
+				if subnet.Name == cs.Name && cs.Type == kops.SubnetTypeUtility {
+					found = true
+					break
+				}
+			}
+			if !found {
+				allErrs = append(allErrs, field.NotFound(subnetPath.Child("name"), fmt.Sprintf("utility subnet %q not found in cluster subnets", subnet.Name)))
+			}
+			if names.Has(subnet.Name) {
+				allErrs = append(allErrs, field.Duplicate(subnetPath.Child("name"), subnet.Name))
+			}
+			names.Insert(subnet.Name)
+		}
+
+		if subnet.PrivateIPv4Address != nil {
+			allErrs = append(allErrs, field.Forbidden(subnetPath.Child("privateIPv4Address"), "privateIPv4Address only allowed for internal NLBs"))
+		}
+
+		if subnet.AllocationID != nil && *subnet.AllocationID == "" {
+			allErrs = append(allErrs, field.Required(subnetPath.Child("allocationId"), "allocationId can't be empty"))
+		}
+	}
+
+	if fi.BoolValue(lbSpec.AllocateElasticIPs) && !isNLB {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("allocateElasticIPs"), "allocateElasticIPs only allowed for Network load balancers"))
+	}
+
+	return allErrs
+}
+
+// awsValidateLoadBalancerTargetGroups validates the health check and deregistration delay of the API target groups
+func awsValidateLoadBalancerTargetGroups(fieldPath *field.Path, spec *kops.LoadBalancerAccessSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.DeregistrationDelaySeconds != nil {
+		if spec.Class != kops.LoadBalancerClassNetwork {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("deregistrationDelaySeconds"), "deregistrationDelaySeconds should be specified with Network Load Balancer"))
+		}
+		if delay := *spec.DeregistrationDelaySeconds; delay < 0 || delay > 3600 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("deregistrationDelaySeconds"), delay, "deregistrationDelaySeconds must be a value between 0 and 3600"))
+		}
+	}
+
+	healthCheck := spec.HealthCheck
+	if healthCheck == nil {
+		return allErrs
+	}
+	fieldPath = fieldPath.Child("healthCheck")
+
+	if spec.Class != kops.LoadBalancerClassNetwork {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "healthCheck should be specified with Network Load Balancer"))
+	}
+	if healthCheck.IntervalSeconds != nil {
+		if interval := *healthCheck.IntervalSeconds; interval < 5 || interval > 300 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("intervalSeconds"), interval, "intervalSeconds must be a value between 5 and 300"))
+		}
+	}
+	if healthCheck.HealthyThreshold != nil {
+		if threshold := *healthCheck.HealthyThreshold; threshold < 2 || threshold > 10 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("healthyThreshold"), threshold, "healthyThreshold must be a value between 2 and 10"))
+		}
+	}
+	if healthCheck.UnhealthyThreshold != nil {
+		if threshold := *healthCheck.UnhealthyThreshold; threshold < 2 || threshold > 10 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("unhealthyThreshold"), threshold, "unhealthyThreshold must be a value between 2 and 10"))
+		}
+	}
+	if healthCheck.Protocol != nil {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("protocol"), healthCheck.Protocol, []string{"TCP", "HTTP", "HTTPS"})...)
+	}
+	if healthCheck.Port != nil {
+		if port := *healthCheck.Port; port < 1 || port > 65535 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("port"), port, "port must be a value between 1 and 65535"))
+		}
+	}
+	if healthCheck.Path != nil {
+		if healthCheck.Protocol == nil || *healthCheck.Protocol == "TCP" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("path"), "path should only be specified with the HTTP or HTTPS protocol"))
+		} else if !strings.HasPrefix(*healthCheck.Path, "/") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("path"), *healthCheck.Path, "path must start with /"))
+		}
+	}
+
+	return allErrs
+}
+
 func awsValidateCPUCredits(fieldPath *field.Path, spec *kops.InstanceGroupSpec, cloud awsup.AWSCloud) field.ErrorList {
 	allErrs := field.ErrorList{}
 
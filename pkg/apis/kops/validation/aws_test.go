@@ -17,16 +17,20 @@ limitations under the License.
 package validation
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/cloudmock/aws/mockelbv2"
 
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
 )
 
@@ -279,6 +283,19 @@ func TestMixedInstancePolicies(t *testing.T) {
 			},
 			ExpectedErrors: []string{"Invalid value::spec.mixedInstancesPolicy.onDemandAboveBase"},
 		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType: "m3.medium",
+				Image:       "ami-0123456789paravirt",
+				MixedInstancesPolicy: &kops.MixedInstancesPolicySpec{
+					Instances: []string{
+						"m3.medium",
+						"m5.large",
+					},
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.mixedInstancesPolicy.instances[1]"},
+		},
 	}
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 	mockEC2 := &mockec2.MockEC2{}
@@ -292,6 +309,15 @@ func TestMixedInstancePolicies(t *testing.T) {
 		RootDeviceName: aws.String("/dev/xvda"),
 		Architecture:   aws.String("x86_64"),
 	})
+	mockEC2.Images = append(mockEC2.Images, &ec2.Image{
+		CreationDate:       aws.String("2016-10-21T20:07:19.000Z"),
+		ImageId:            aws.String("ami-0123456789paravirt"),
+		Name:               aws.String("paravirtual"),
+		OwnerId:            aws.String(awsup.WellKnownAccountUbuntu),
+		RootDeviceName:     aws.String("/dev/xvda"),
+		Architecture:       aws.String("x86_64"),
+		VirtualizationType: aws.String("paravirtual"),
+	})
 
 	for _, g := range grid {
 		ig := &kops.InstanceGroup{
@@ -307,6 +333,202 @@ func TestMixedInstancePolicies(t *testing.T) {
 
 }
 
+func TestInstanceTypeZones(t *testing.T) {
+	grid := []struct {
+		Input          kops.InstanceGroupSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType: "m5.large",
+				Subnets:     []string{"subnet-us-east-1a", "subnet-us-east-1b"},
+			},
+			ExpectedErrors: nil,
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType: "c5.large",
+				Subnets:     []string{"subnet-us-east-1a"},
+			},
+			ExpectedErrors: nil,
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType: "c5.large",
+				Subnets:     []string{"subnet-us-east-1a", "subnet-us-east-1b"},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.machineType"},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType: "m5.large",
+				Subnets:     []string{"subnet-us-east-1b"},
+				MixedInstancesPolicy: &kops.MixedInstancesPolicySpec{
+					Instances: []string{
+						"m5.large",
+						"c5.large",
+					},
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.mixedInstancesPolicy.instances[1]"},
+		},
+	}
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	cloud.InstanceTypeOfferings = map[string][]string{
+		"c5.large": {"us-east-1a", "us-east-1c"},
+	}
+
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "subnet-us-east-1a", Zone: "us-east-1a"},
+				{Name: "subnet-us-east-1b", Zone: "us-east-1b"},
+			},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "test-nodes",
+			},
+			Spec: g.Input,
+		}
+		errs := awsValidateInstanceTypeZones(ig, cluster, cloud)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestAWSValidateExternalTargetGroups(t *testing.T) {
+	mockELBV2 := &mockelbv2.MockELBV2{}
+	for _, input := range []*elbv2.CreateTargetGroupInput{
+		{Name: aws.String("web"), VpcId: aws.String("vpc-12345678"), Port: aws.Int64(30080), Protocol: aws.String("TCP")},
+		{Name: aws.String("other-vpc"), VpcId: aws.String("vpc-87654321"), Port: aws.Int64(30080), Protocol: aws.String("TCP")},
+		{Name: aws.String("ip"), VpcId: aws.String("vpc-12345678"), Port: aws.Int64(30080), Protocol: aws.String("TCP"), TargetType: aws.String("ip")},
+		{Name: aws.String("health"), VpcId: aws.String("vpc-12345678"), Port: aws.Int64(30443), Protocol: aws.String("HTTPS"), HealthCheckPort: aws.String("30081")},
+	} {
+		if _, err := mockELBV2.CreateTargetGroup(input); err != nil {
+			t.Fatalf("error creating target group: %v", err)
+		}
+	}
+	cloud := awsup.BuildMockAWSCloud("us-test-1", "a")
+	cloud.MockELBV2 = mockELBV2
+
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			NetworkID: "vpc-12345678",
+		},
+	}
+
+	arn := func(name string, n int) *string {
+		return fi.String(fmt.Sprintf("arn:aws:elasticloadbalancing:us-test-1:000000000000:targetgroup/%s/%d", name, n))
+	}
+
+	grid := []struct {
+		Input          kops.LoadBalancer
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.LoadBalancer{TargetGroupARN: arn("web", 1), Name: "http", Port: fi.Int64(30080), Protocol: fi.String("TCP")},
+		},
+		{
+			Input:          kops.LoadBalancer{TargetGroupARN: arn("missing", 9)},
+			ExpectedErrors: []string{"Not found::spec.externalLoadBalancers[0].targetGroupArn"},
+		},
+		{
+			Input:          kops.LoadBalancer{TargetGroupARN: arn("other-vpc", 2)},
+			ExpectedErrors: []string{"Invalid value::spec.externalLoadBalancers[0].targetGroupArn"},
+		},
+		{
+			Input:          kops.LoadBalancer{TargetGroupARN: arn("ip", 3)},
+			ExpectedErrors: []string{"Invalid value::spec.externalLoadBalancers[0].targetGroupArn"},
+		},
+		{
+			Input: kops.LoadBalancer{TargetGroupARN: arn("web", 1), Port: fi.Int64(8080), Protocol: fi.String("HTTP")},
+			ExpectedErrors: []string{
+				"Invalid value::spec.externalLoadBalancers[0].port",
+				"Invalid value::spec.externalLoadBalancers[0].protocol",
+				"Invalid value::spec.externalLoadBalancers[0].port",
+			},
+		},
+		{
+			Input:          kops.LoadBalancer{TargetGroupARN: arn("health", 4), Port: fi.Int64(30443)},
+			ExpectedErrors: []string{"Invalid value::spec.externalLoadBalancers[0].port"},
+		},
+		{
+			Input: kops.LoadBalancer{TargetGroupARN: arn("health", 4), Port: fi.Int64(30443), HealthCheckPort: fi.Int64(30081)},
+		},
+	}
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				ExternalLoadBalancers: []kops.LoadBalancer{g.Input},
+			},
+		}
+		errs := awsValidateExternalTargetGroups(ig, cluster, cloud)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+
+	// Clusters in a VPC managed by kops are checked against the VPC that carries their name
+	mockEC2 := &mockec2.MockEC2{}
+	if _, err := mockEC2.CreateVpcWithId(&ec2.CreateVpcInput{
+		CidrBlock: aws.String("172.20.0.0/16"),
+		TagSpecifications: []*ec2.TagSpecification{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeVpc),
+				Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("managed.example.com")}},
+			},
+		},
+	}, "vpc-12345678"); err != nil {
+		t.Fatalf("error creating VPC: %v", err)
+	}
+	cloud.MockEC2 = mockEC2
+
+	managedGrid := []struct {
+		ClusterName    string
+		Input          kops.LoadBalancer
+		ExpectedErrors []string
+	}{
+		{
+			ClusterName: "managed.example.com",
+			Input:       kops.LoadBalancer{TargetGroupARN: arn("web", 1)},
+		},
+		{
+			ClusterName:    "managed.example.com",
+			Input:          kops.LoadBalancer{TargetGroupARN: arn("other-vpc", 2)},
+			ExpectedErrors: []string{"Invalid value::spec.externalLoadBalancers[0].targetGroupArn"},
+		},
+		{
+			ClusterName:    "new.example.com",
+			Input:          kops.LoadBalancer{TargetGroupARN: arn("web", 1)},
+			ExpectedErrors: []string{"Invalid value::spec.externalLoadBalancers[0].targetGroupArn"},
+		},
+	}
+	for _, g := range managedGrid {
+		managedCluster := &kops.Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: g.ClusterName,
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				ExternalLoadBalancers: []kops.LoadBalancer{g.Input},
+			},
+		}
+		errs := awsValidateExternalTargetGroups(ig, managedCluster, cloud)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
 func TestInstanceMetadataOptions(t *testing.T) {
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 
@@ -366,6 +588,306 @@ func TestInstanceMetadataOptions(t *testing.T) {
 	}
 }
 
+func TestAWSValidateScaleIn(t *testing.T) {
+	grid := []struct {
+		Input          kops.InstanceGroupSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.InstanceGroupSpec{
+				ScaleInProtection:   kops.ScaleInProtectionUntilCordoned,
+				TerminationPolicies: []string{"OldestLaunchTemplate", "AllocationStrategy", "Default"},
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				ScaleInProtection: "Always",
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.scaleInProtection"},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				ScaleInProtection:  kops.ScaleInProtectionUntilCordoned,
+				InstanceProtection: fi.Bool(false),
+			},
+			ExpectedErrors: []string{"Forbidden::spec.instanceProtection"},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				TerminationPolicies: []string{"OldestInstance", "Oldest", "OldestInstance"},
+			},
+			ExpectedErrors: []string{
+				"Unsupported value::spec.terminationPolicies[1]",
+				"Duplicate value::spec.terminationPolicies[2]",
+			},
+		},
+	}
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: g.Input,
+		}
+		errs := awsValidateScaleIn(field.NewPath("spec"), ig)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestAWSValidateSuspendProcesses(t *testing.T) {
+	grid := []struct {
+		Input          []string
+		ExpectedErrors []string
+	}{
+		{
+			Input: []string{"AZRebalance", "ReplaceUnhealthy"},
+		},
+		{
+			Input: []string{"AZRebalance", "Rebalance", "AZRebalance"},
+			ExpectedErrors: []string{
+				"Unsupported value::spec.suspendProcesses[1]",
+				"Duplicate value::spec.suspendProcesses[2]",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := awsValidateSuspendProcesses(field.NewPath("spec", "suspendProcesses"), g.Input)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestAWSValidateInstanceStorage(t *testing.T) {
+	grid := []struct {
+		Input          string
+		ExpectedErrors []string
+	}{
+		{
+			Input: "m3.medium",
+		},
+		{
+			Input:          "c5.large",
+			ExpectedErrors: []string{"Forbidden::spec.instanceStorage"},
+		},
+		{
+			Input: "t2.invalidType",
+		},
+	}
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	for _, g := range grid {
+		errs := awsValidateInstanceStorage(field.NewPath("spec", "instanceStorage"), g.Input, cloud)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestAWSValidateEtcdMemberVolume(t *testing.T) {
+	grid := []struct {
+		Input          kops.EtcdMemberSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.EtcdMemberSpec{},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				VolumeType:       fi.String("gp3"),
+				VolumeIops:       fi.Int32(3000),
+				VolumeSize:       fi.Int32(50),
+				VolumeThroughput: fi.Int32(1000),
+			},
+			ExpectedErrors: []string{"Invalid value::spec.etcdClusters[0].etcdMembers[0].volumeThroughput"},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				VolumeType: fi.String("gp3"),
+				VolumeIops: fi.Int32(16000),
+				VolumeSize: fi.Int32(20),
+			},
+			ExpectedErrors: []string{"Invalid value::spec.etcdClusters[0].etcdMembers[0].volumeIops"},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				VolumeType: fi.String("io1"),
+				VolumeIops: fi.Int32(2000),
+				VolumeSize: fi.Int32(20),
+			},
+			ExpectedErrors: []string{"Invalid value::spec.etcdClusters[0].etcdMembers[0].volumeIops"},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				VolumeType: fi.String("io2"),
+				VolumeIops: fi.Int32(100000),
+				VolumeSize: fi.Int32(100),
+			},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				VolumeType: fi.String("io2"),
+				VolumeIops: fi.Int32(300000),
+				VolumeSize: fi.Int32(70000),
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.etcdClusters[0].etcdMembers[0].volumeSize",
+				"Invalid value::spec.etcdClusters[0].etcdMembers[0].volumeIops",
+			},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				VolumeType:       fi.String("io2"),
+				VolumeIops:       fi.Int32(20000),
+				VolumeSize:       fi.Int32(2),
+				VolumeThroughput: fi.Int32(500),
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.etcdClusters[0].etcdMembers[0].volumeSize",
+				"Invalid value::spec.etcdClusters[0].etcdMembers[0].volumeIops",
+			},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				VolumeType:       fi.String("gp2"),
+				VolumeIops:       fi.Int32(3000),
+				VolumeThroughput: fi.Int32(500),
+			},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				KmsKeyId: fi.String("arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+			},
+			ExpectedErrors: []string{"Forbidden::spec.etcdClusters[0].etcdMembers[0].kmsKeyId"},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				KmsKeyId:        fi.String("arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+				EncryptedVolume: fi.Bool(true),
+			},
+		},
+		{
+			Input: kops.EtcdMemberSpec{
+				VolumeType: fi.String("sc1"),
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.etcdClusters[0].etcdMembers[0].volumeType"},
+		},
+	}
+	for _, g := range grid {
+		errs := awsValidateEtcdMemberVolume(field.NewPath("spec", "etcdClusters").Index(0).Child("etcdMembers").Index(0), g.Input)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestAWSValidateInstanceMaintenancePolicy(t *testing.T) {
+	grid := []struct {
+		Input          kops.InstanceMaintenancePolicySpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.InstanceMaintenancePolicySpec{
+				MinHealthyPercentage: fi.Int64(100),
+				MaxHealthyPercentage: fi.Int64(110),
+			},
+		},
+		{
+			Input: kops.InstanceMaintenancePolicySpec{
+				MinHealthyPercentage: fi.Int64(90),
+			},
+			ExpectedErrors: []string{"Required value::spec.instanceMaintenancePolicy.maxHealthyPercentage"},
+		},
+		{
+			Input: kops.InstanceMaintenancePolicySpec{
+				MinHealthyPercentage: fi.Int64(101),
+				MaxHealthyPercentage: fi.Int64(90),
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.instanceMaintenancePolicy.minHealthyPercentage",
+				"Invalid value::spec.instanceMaintenancePolicy.maxHealthyPercentage",
+			},
+		},
+		{
+			Input: kops.InstanceMaintenancePolicySpec{
+				MinHealthyPercentage: fi.Int64(50),
+				MaxHealthyPercentage: fi.Int64(200),
+			},
+			ExpectedErrors: []string{"Invalid value::spec.instanceMaintenancePolicy.maxHealthyPercentage"},
+		},
+	}
+	for _, g := range grid {
+		errs := awsValidateInstanceMaintenancePolicy(field.NewPath("spec", "instanceMaintenancePolicy"), &g.Input)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestAWSValidateLoadBalancerTargetGroups(t *testing.T) {
+	grid := []struct {
+		Input          kops.LoadBalancerAccessSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.LoadBalancerAccessSpec{
+				Class:                      kops.LoadBalancerClassNetwork,
+				DeregistrationDelaySeconds: fi.Int64(30),
+				HealthCheck: &kops.LoadBalancerHealthCheckSpec{
+					IntervalSeconds:    fi.Int64(10),
+					HealthyThreshold:   fi.Int64(2),
+					UnhealthyThreshold: fi.Int64(2),
+					Protocol:           fi.String("HTTPS"),
+					Port:               fi.Int64(443),
+					Path:               fi.String("/readyz"),
+				},
+			},
+		},
+		{
+			Input: kops.LoadBalancerAccessSpec{
+				Class:                      kops.LoadBalancerClassClassic,
+				DeregistrationDelaySeconds: fi.Int64(30),
+				HealthCheck:                &kops.LoadBalancerHealthCheckSpec{},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.api.loadBalancer.deregistrationDelaySeconds",
+				"Forbidden::spec.api.loadBalancer.healthCheck",
+			},
+		},
+		{
+			Input: kops.LoadBalancerAccessSpec{
+				Class:                      kops.LoadBalancerClassNetwork,
+				DeregistrationDelaySeconds: fi.Int64(3601),
+				HealthCheck: &kops.LoadBalancerHealthCheckSpec{
+					IntervalSeconds:    fi.Int64(1),
+					HealthyThreshold:   fi.Int64(1),
+					UnhealthyThreshold: fi.Int64(11),
+					Protocol:           fi.String("UDP"),
+					Port:               fi.Int64(0),
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.api.loadBalancer.deregistrationDelaySeconds",
+				"Invalid value::spec.api.loadBalancer.healthCheck.intervalSeconds",
+				"Invalid value::spec.api.loadBalancer.healthCheck.healthyThreshold",
+				"Invalid value::spec.api.loadBalancer.healthCheck.unhealthyThreshold",
+				"Unsupported value::spec.api.loadBalancer.healthCheck.protocol",
+				"Invalid value::spec.api.loadBalancer.healthCheck.port",
+			},
+		},
+		{
+			Input: kops.LoadBalancerAccessSpec{
+				Class: kops.LoadBalancerClassNetwork,
+				HealthCheck: &kops.LoadBalancerHealthCheckSpec{
+					Path: fi.String("/healthz"),
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.api.loadBalancer.healthCheck.path"},
+		},
+	}
+	for _, g := range grid {
+		errs := awsValidateLoadBalancerTargetGroups(field.NewPath("spec", "api", "loadBalancer"), &g.Input)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
 func TestLoadBalancerSubnets(t *testing.T) {
 	cidr := "10.0.0.0/24"
 	tests := []struct {
@@ -549,3 +1071,65 @@ func TestLoadBalancerSubnets(t *testing.T) {
 		testErrors(t, test, errs, test.expected)
 	}
 }
+
+func TestAWSValidateBastionLoadBalancer(t *testing.T) {
+	grid := []struct {
+		Input          kops.BastionLoadBalancerSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.BastionLoadBalancerSpec{
+				Class: kops.LoadBalancerClassNetwork,
+				Subnets: []kops.LoadBalancerSubnetSpec{
+					{Name: "utility-a", AllocationID: fi.String("eipalloc-1")},
+				},
+				AllocateElasticIPs: fi.Bool(true),
+			},
+		},
+		{
+			Input: kops.BastionLoadBalancerSpec{
+				Class: kops.LoadBalancerClassClassic,
+				Subnets: []kops.LoadBalancerSubnetSpec{
+					{Name: "utility-a", AllocationID: fi.String("eipalloc-1")},
+				},
+				AllocateElasticIPs: fi.Bool(true),
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.topology.bastion.loadBalancer.subnets[0]",
+				"Forbidden::spec.topology.bastion.loadBalancer.allocateElasticIPs",
+			},
+		},
+		{
+			Input: kops.BastionLoadBalancerSpec{
+				Class: kops.LoadBalancerClassNetwork,
+				Subnets: []kops.LoadBalancerSubnetSpec{
+					{Name: "private-a"},
+					{Name: "utility-a", PrivateIPv4Address: fi.String("10.0.0.10"), AllocationID: fi.String("")},
+					{Name: "utility-a"},
+				},
+			},
+			ExpectedErrors: []string{
+				"Not found::spec.topology.bastion.loadBalancer.subnets[0].name",
+				"Forbidden::spec.topology.bastion.loadBalancer.subnets[1].privateIPv4Address",
+				"Required value::spec.topology.bastion.loadBalancer.subnets[1].allocationId",
+				"Duplicate value::spec.topology.bastion.loadBalancer.subnets[2].name",
+			},
+		},
+	}
+	for _, g := range grid {
+		spec := kops.ClusterSpec{
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "private-a", Type: kops.SubnetTypePrivate},
+				{Name: "utility-a", Type: kops.SubnetTypeUtility},
+			},
+			Topology: &kops.TopologySpec{
+				Bastion: &kops.BastionSpec{
+					LoadBalancer: &g.Input,
+				},
+			},
+		}
+		errs := awsValidateBastionLoadBalancer(field.NewPath("spec", "topology", "bastion", "loadBalancer"), spec)
+
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
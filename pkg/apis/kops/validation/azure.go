@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func azureValidateInstanceGroup(ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldSpec := field.NewPath("spec")
+
+	if ig.Spec.MaxPrice != nil && *ig.Spec.MaxPrice != "" {
+		if _, err := strconv.ParseFloat(*ig.Spec.MaxPrice, 64); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldSpec.Child("maxPrice"), *ig.Spec.MaxPrice, "maxPrice must be a number"))
+		}
+	}
+
+	if ig.Spec.InstanceInterruptionBehavior != nil {
+		// Spot virtual machines are either deleted or deallocated when they are evicted
+		allErrs = append(allErrs, IsValidValue(fieldSpec.Child("instanceInterruptionBehavior"), ig.Spec.InstanceInterruptionBehavior, []string{"terminate", "stop"})...)
+	}
+
+	for i, zone := range ig.Spec.Zones {
+		l := strings.Split(zone, "-")
+		if len(l) != 2 || l[1] == "" {
+			allErrs = append(allErrs, field.Invalid(fieldSpec.Child("zones").Index(i), zone, "Azure zones must be of the form <location>-<zone-number>"))
+		}
+	}
+
+	if fi.BoolValue(ig.Spec.RootVolumeEphemeral) && ig.Spec.RootVolumeType != nil && *ig.Spec.RootVolumeType != "Standard_LRS" {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("rootVolumeType"), "ephemeral root volumes require the Standard_LRS volume type"))
+	}
+
+	return allErrs
+}
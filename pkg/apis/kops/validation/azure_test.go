@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestAzureValidateInstanceGroup(t *testing.T) {
+	grid := []struct {
+		Input          kops.InstanceGroupSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.InstanceGroupSpec{
+				MaxPrice:                     fi.String("0.05"),
+				InstanceInterruptionBehavior: fi.String("stop"),
+				Zones:                        []string{"eastus-1", "eastus-2"},
+				RootVolumeEphemeral:          fi.Bool(true),
+				AcceleratedNetworking:        fi.Bool(true),
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MaxPrice: fi.String(""),
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MaxPrice: fi.String("cheap"),
+			},
+			ExpectedErrors: []string{"Invalid value::spec.maxPrice"},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MaxPrice:                     fi.String("0.05"),
+				InstanceInterruptionBehavior: fi.String("hibernate"),
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.instanceInterruptionBehavior"},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				Zones: []string{"1"},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.zones[0]"},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				RootVolumeEphemeral: fi.Bool(true),
+				RootVolumeType:      fi.String("Premium_LRS"),
+			},
+			ExpectedErrors: []string{"Forbidden::spec.rootVolumeType"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: g.Input,
+		}
+		errs := azureValidateInstanceGroup(ig)
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestCrossValidateAzureOnlyFields(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			CloudProvider:     string(kops.CloudProviderAWS),
+			KubernetesVersion: "1.21.0",
+		},
+	}
+	ig := &kops.InstanceGroup{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "nodes",
+		},
+		Spec: kops.InstanceGroupSpec{
+			Role:                  kops.InstanceGroupRoleNode,
+			RootVolumeEphemeral:   fi.Bool(true),
+			AcceleratedNetworking: fi.Bool(true),
+		},
+	}
+	errs := CrossValidateInstanceGroup(ig, cluster, nil)
+	testErrors(t, ig.Spec, errs, []string{"Forbidden::spec.rootVolumeEphemeral", "Forbidden::spec.acceleratedNetworking"})
+}
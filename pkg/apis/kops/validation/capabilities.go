@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/aliup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+)
+
+// cloudCapabilities returns the optional features that kOps supports on the cloud provider,
+// or nil if the cloud provider is not known.
+func cloudCapabilities(cloudProvider string) fi.CloudCapabilities {
+	switch kops.CloudProviderID(cloudProvider) {
+	case kops.CloudProviderAWS:
+		return awsup.Capabilities()
+	case kops.CloudProviderGCE:
+		return gce.Capabilities()
+	case kops.CloudProviderAzure:
+		return azure.Capabilities()
+	case kops.CloudProviderDO:
+		return do.Capabilities()
+	case kops.CloudProviderALI:
+		return aliup.Capabilities()
+	case kops.CloudProviderOpenstack:
+		return openstack.Capabilities()
+	case kops.CloudProviderOCI:
+		return oci.Capabilities()
+	case kops.CloudProviderMetal:
+		return metal.Capabilities()
+	default:
+		return nil
+	}
+}
+
+// notSupported returns the error for a field that uses a feature the cloud provider does not support.
+func notSupported(fldPath *field.Path, feature string, cloudProvider string) *field.Error {
+	return field.Forbidden(fldPath, fmt.Sprintf("%s not supported on %s", feature, cloudProvider))
+}
+
+// validateCloudCapabilities rejects the parts of the cluster spec that the cloud provider does not support.
+func validateCloudCapabilities(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	capabilities := cloudCapabilities(spec.CloudProvider)
+	if capabilities == nil {
+		return allErrs
+	}
+
+	if spec.API != nil && spec.API.LoadBalancer != nil {
+		if !capabilities.SupportsAPILoadBalancer() {
+			allErrs = append(allErrs, notSupported(fieldPath.Child("api", "loadBalancer"), "API load balancers are", spec.CloudProvider))
+		} else if spec.API.LoadBalancer.Class != "" && len(capabilities.LoadBalancerClasses()) == 0 {
+			allErrs = append(allErrs, notSupported(fieldPath.Child("api", "loadBalancer", "class"), "choosing the class of the API load balancer is", spec.CloudProvider))
+		}
+	}
+
+	if !capabilities.SupportsIPv6() {
+		if spec.IsIPv6Only() {
+			allErrs = append(allErrs, notSupported(fieldPath.Child("nonMasqueradeCIDR"), "IPv6 is", spec.CloudProvider))
+		}
+		for i, subnet := range spec.Subnets {
+			if subnet.IPv6CIDR != "" {
+				allErrs = append(allErrs, notSupported(fieldPath.Child("subnets").Index(i).Child("ipv6CIDR"), "IPv6 is", spec.CloudProvider))
+			}
+		}
+	}
+
+	if spec.WarmPool != nil && !capabilities.SupportsWarmPool() {
+		allErrs = append(allErrs, notSupported(fieldPath.Child("warmPool"), "warm pools are", spec.CloudProvider))
+	}
+
+	return allErrs
+}
+
+// validateInstanceGroupCapabilities rejects the parts of the instance group that the cloud provider does not support.
+func validateInstanceGroupCapabilities(g *kops.InstanceGroup, cluster *kops.Cluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	cloudProvider := cluster.Spec.CloudProvider
+	capabilities := cloudCapabilities(cloudProvider)
+	if capabilities == nil {
+		return allErrs
+	}
+
+	fieldSpec := field.NewPath("spec")
+
+	if g.Spec.MaxPrice != nil && !capabilities.SupportsSpotInstances() {
+		allErrs = append(allErrs, notSupported(fieldSpec.Child("maxPrice"), "spot instances are", cloudProvider))
+	}
+
+	if g.Spec.MixedInstancesPolicy != nil && !capabilities.SupportsMixedInstancesPolicy() {
+		allErrs = append(allErrs, notSupported(fieldSpec.Child("mixedInstancesPolicy"), "mixed instances policies are", cloudProvider))
+	}
+
+	if g.Spec.WarmPool != nil && !capabilities.SupportsWarmPool() {
+		allErrs = append(allErrs, notSupported(fieldSpec.Child("warmPool"), "warm pools are", cloudProvider))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestValidateCloudCapabilities(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				API: &kops.AccessSpec{
+					LoadBalancer: &kops.LoadBalancerAccessSpec{
+						Class: kops.LoadBalancerClassNetwork,
+					},
+				},
+				NonMasqueradeCIDR: "::/0",
+				Subnets: []kops.ClusterSubnetSpec{
+					{Name: "a", IPv6CIDR: "/64#1"},
+				},
+				WarmPool: &kops.WarmPoolSpec{},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				API: &kops.AccessSpec{
+					LoadBalancer: &kops.LoadBalancerAccessSpec{},
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				API: &kops.AccessSpec{
+					LoadBalancer: &kops.LoadBalancerAccessSpec{
+						Class: kops.LoadBalancerClassNetwork,
+					},
+				},
+				NonMasqueradeCIDR: "::/0",
+				Subnets: []kops.ClusterSubnetSpec{
+					{Name: "a", IPv6CIDR: "/64#1"},
+				},
+				WarmPool: &kops.WarmPoolSpec{},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.api.loadBalancer.class",
+				"Forbidden::spec.nonMasqueradeCIDR",
+				"Forbidden::spec.subnets[0].ipv6CIDR",
+				"Forbidden::spec.warmPool",
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "metal",
+				API: &kops.AccessSpec{
+					LoadBalancer: &kops.LoadBalancerAccessSpec{},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.api.loadBalancer",
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "unknown",
+				WarmPool:      &kops.WarmPoolSpec{},
+			},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateCloudCapabilities(&g.Input, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestValidateInstanceGroupCapabilities(t *testing.T) {
+	grid := []struct {
+		CloudProvider  string
+		Input          kops.InstanceGroupSpec
+		ExpectedErrors []string
+	}{
+		{
+			CloudProvider: "aws",
+			Input: kops.InstanceGroupSpec{
+				MaxPrice:             fi.String("0.1"),
+				MixedInstancesPolicy: &kops.MixedInstancesPolicySpec{},
+				WarmPool:             &kops.WarmPoolSpec{},
+			},
+		},
+		{
+			CloudProvider: "azure",
+			Input: kops.InstanceGroupSpec{
+				MaxPrice:             fi.String("0.1"),
+				MixedInstancesPolicy: &kops.MixedInstancesPolicySpec{},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.mixedInstancesPolicy",
+			},
+		},
+		{
+			CloudProvider: "oci",
+			Input: kops.InstanceGroupSpec{
+				MaxPrice: fi.String(""),
+				WarmPool: &kops.WarmPoolSpec{},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.maxPrice",
+				"Forbidden::spec.warmPool",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: g.Input,
+		}
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: g.CloudProvider,
+			},
+		}
+		errs := validateInstanceGroupCapabilities(ig, cluster)
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
@@ -0,0 +1,397 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
+)
+
+// featureGateLifecycle records the Kubernetes versions in which a feature gate changed.
+type featureGateLifecycle struct {
+	// Added is the version that introduced the gate, or empty if the gate predates Kubernetes 1.17.
+	Added string
+	// Deprecated is the version from which the gate is deprecated without having graduated.
+	Deprecated string
+	// GA is the version in which the feature graduated, after which the gate is removed.
+	GA string
+	// Removed is the version from which the components refuse to start with the gate.
+	Removed string
+}
+
+// knownFeatureGates are the feature gates of the Kubernetes components, from Kubernetes 1.17 on. The Removed versions are the
+// first minor releases, up to Kubernetes 1.32, whose feature gate definitions in kubernetes, apiserver, apiextensions-apiserver,
+// controller-manager and component-base no longer contain the gate.
+var knownFeatureGates = map[string]featureGateLifecycle{
+	"APIListChunking":                                {},
+	"APIPriorityAndFairness":                         {Removed: "1.31"},
+	"APIResponseCompression":                         {},
+	"APIServerIdentity":                              {Added: "1.20"},
+	"APIServerTracing":                               {Added: "1.22"},
+	"AdvancedAuditing":                               {GA: "1.12", Removed: "1.28"},
+	"AllAlpha":                                       {},
+	"AllBeta":                                        {},
+	"AllowInsecureBackendProxy":                      {Removed: "1.23"},
+	"AnyVolumeDataSource":                            {Added: "1.18"},
+	"AppArmor":                                       {},
+	"AttachVolumeLimit":                              {GA: "1.17", Removed: "1.21"},
+	"BalanceAttachedNodeVolumes":                     {Removed: "1.22"},
+	"BlockVolume":                                    {GA: "1.18", Removed: "1.21"},
+	"BoundServiceAccountTokenVolume":                 {GA: "1.22", Removed: "1.23"},
+	"CPUManager":                                     {},
+	"CPUManagerPolicyOptions":                        {Added: "1.22"},
+	"CRIContainerLogRotation":                        {GA: "1.21", Removed: "1.22"},
+	"CSIBlockVolume":                                 {GA: "1.18", Removed: "1.21"},
+	"CSIDriverRegistry":                              {GA: "1.18", Removed: "1.21"},
+	"CSIInlineVolume":                                {Removed: "1.27"},
+	"CSIMigration":                                   {Removed: "1.27"},
+	"CSIMigrationAWS":                                {Removed: "1.27"},
+	"CSIMigrationAWSComplete":                        {Removed: "1.21"},
+	"CSIMigrationAzureDisk":                          {Removed: "1.27"},
+	"CSIMigrationAzureDiskComplete":                  {Removed: "1.21"},
+	"CSIMigrationAzureFile":                          {Removed: "1.30"},
+	"CSIMigrationAzureFileComplete":                  {Removed: "1.21"},
+	"CSIMigrationGCE":                                {Removed: "1.28"},
+	"CSIMigrationGCEComplete":                        {Removed: "1.21"},
+	"CSIMigrationOpenStack":                          {Removed: "1.26"},
+	"CSIMigrationOpenStackComplete":                  {Removed: "1.21"},
+	"CSIMigrationvSphere":                            {Added: "1.19", Removed: "1.29"},
+	"CSIMigrationvSphereComplete":                    {Added: "1.19", Deprecated: "1.21", Removed: "1.22"},
+	"CSINodeInfo":                                    {GA: "1.17", Removed: "1.21"},
+	"CSIServiceAccountToken":                         {Added: "1.20", GA: "1.22", Removed: "1.25"},
+	"CSIStorageCapacity":                             {Added: "1.19", Removed: "1.28"},
+	"CSIVolumeFSGroupPolicy":                         {Added: "1.19", Removed: "1.25"},
+	"CSIVolumeHealth":                                {Added: "1.21"},
+	"CSRDuration":                                    {Added: "1.22", Removed: "1.26"},
+	"ConfigurableFSGroupPolicy":                      {Added: "1.18", Removed: "1.25"},
+	"ControllerManagerLeaderMigration":               {Added: "1.21", Removed: "1.27"},
+	"CronJobControllerV2":                            {Added: "1.20", GA: "1.22", Removed: "1.23"},
+	"CustomCPUCFSQuotaPeriod":                        {},
+	"CustomResourceDefaulting":                       {GA: "1.17", Removed: "1.18"},
+	"DaemonSetUpdateSurge":                           {Added: "1.21", Removed: "1.27"},
+	"DefaultPodTopologySpread":                       {Added: "1.19", Removed: "1.26"},
+	"DelegateFSGroupToCSIDriver":                     {Added: "1.22", Removed: "1.28"},
+	"DevicePlugins":                                  {Removed: "1.28"},
+	"DisableAcceleratorUsageMetrics":                 {Added: "1.19", Removed: "1.28"},
+	"DisableCloudProviders":                          {Added: "1.22"},
+	"DownwardAPIHugePages":                           {Added: "1.20", Removed: "1.29"},
+	"DryRun":                                         {GA: "1.19", Removed: "1.28"},
+	"DynamicAuditing":                                {Removed: "1.19"},
+	"DynamicKubeletConfig":                           {Deprecated: "1.22", Removed: "1.26"},
+	"EfficientWatchResumption":                       {Added: "1.20"},
+	"EndpointSlice":                                  {GA: "1.21", Removed: "1.25"},
+	"EndpointSliceProxying":                          {Added: "1.18", GA: "1.22", Removed: "1.25"},
+	"EndpointSliceTerminatingCondition":              {Added: "1.20", Removed: "1.28"},
+	"EphemeralContainers":                            {Removed: "1.27"},
+	"EvenPodsSpread":                                 {GA: "1.19", Removed: "1.21"},
+	"ExecProbeTimeout":                               {Added: "1.20"},
+	"ExpandCSIVolumes":                               {Removed: "1.27"},
+	"ExpandInUsePersistentVolumes":                   {Removed: "1.27"},
+	"ExpandPersistentVolumes":                        {Removed: "1.27"},
+	"ExpandedDNSConfig":                              {Added: "1.22", Removed: "1.30"},
+	"ExperimentalHostUserNamespaceDefaulting":        {Removed: "1.30"},
+	"GenericEphemeralVolume":                         {Added: "1.19", Removed: "1.25"},
+	"GracefulNodeShutdown":                           {Added: "1.20"},
+	"HPAContainerMetrics":                            {Added: "1.20", Removed: "1.32"},
+	"HPAScaleToZero":                                 {},
+	"HugePageStorageMediumSize":                      {Added: "1.18", Removed: "1.24"},
+	"IPv6DualStack":                                  {Removed: "1.27"},
+	"ImmutableEphemeralVolumes":                      {Added: "1.18", GA: "1.21", Removed: "1.24"},
+	"InTreePluginAWSUnregister":                      {Added: "1.21", Removed: "1.31"},
+	"InTreePluginAzureDiskUnregister":                {Added: "1.21", Removed: "1.31"},
+	"InTreePluginAzureFileUnregister":                {Added: "1.21", Removed: "1.31"},
+	"InTreePluginGCEUnregister":                      {Added: "1.21", Removed: "1.31"},
+	"InTreePluginOpenStackUnregister":                {Added: "1.21", Removed: "1.31"},
+	"InTreePluginvSphereUnregister":                  {Added: "1.21", Removed: "1.31"},
+	"IndexedJob":                                     {Added: "1.21", Removed: "1.26"},
+	"IngressClassNamespacedParams":                   {Added: "1.21", Removed: "1.25"},
+	"JobTrackingWithFinalizers":                      {Added: "1.22", Removed: "1.29"},
+	"KubeletCredentialProviders":                     {Added: "1.20", Removed: "1.28"},
+	"KubeletInUserNamespace":                         {Added: "1.22"},
+	"KubeletPodResources":                            {Removed: "1.30"},
+	"KubeletPodResourcesGetAllocatable":              {Added: "1.21", Removed: "1.30"},
+	"LegacyNodeRoleBehavior":                         {Removed: "1.22"},
+	"LocalStorageCapacityIsolation":                  {Removed: "1.27"},
+	"LocalStorageCapacityIsolationFSQuotaMonitoring": {},
+	"LogarithmicScaleDown":                           {Added: "1.21"},
+	"MemoryManager":                                  {Added: "1.21"},
+	"MemoryQoS":                                      {Added: "1.22"},
+	"MixedProtocolLBService":                         {Added: "1.20", Removed: "1.28"},
+	"NamespaceDefaultLabelName":                      {Added: "1.21", GA: "1.22", Removed: "1.24"},
+	"NetworkPolicyEndPort":                           {Added: "1.21", Removed: "1.27"},
+	"NodeDisruptionExclusion":                        {GA: "1.21", Removed: "1.22"},
+	"NodeLease":                                      {GA: "1.17", Removed: "1.23"},
+	"NodeSwap":                                       {Added: "1.22"},
+	"NonPreemptingPriority":                          {Removed: "1.26"},
+	"PodAffinityNamespaceSelector":                   {Added: "1.21", Removed: "1.26"},
+	"PodDeletionCost":                                {Added: "1.21"},
+	"PodOverhead":                                    {Removed: "1.26"},
+	"PodSecurity":                                    {Added: "1.22", Removed: "1.28"},
+	"PodShareProcessNamespace":                       {GA: "1.17", Removed: "1.19"},
+	"PreferNominatedNode":                            {Added: "1.21", Removed: "1.26"},
+	"ProbeTerminationGracePeriod":                    {Added: "1.21", Removed: "1.29"},
+	"ProcMountType":                                  {},
+	"ProxyTerminatingEndpoints":                      {Added: "1.22", Removed: "1.30"},
+	"QOSReserved":                                    {},
+	"ReadWriteOncePod":                               {Added: "1.22", Removed: "1.31"},
+	"RemainingItemCount":                             {},
+	"RemoveSelfLink":                                 {Removed: "1.30"},
+	"RootCAConfigMap":                                {Added: "1.20", GA: "1.20", Removed: "1.22"},
+	"RotateKubeletClientCertificate":                 {GA: "1.19", Removed: "1.21"},
+	"RotateKubeletServerCertificate":                 {},
+	"RunAsGroup":                                     {GA: "1.21", Removed: "1.22"},
+	"RuntimeClass":                                   {GA: "1.20", Removed: "1.24"},
+	"SCTPSupport":                                    {GA: "1.20", Removed: "1.22"},
+	"ScheduleDaemonSetPods":                          {GA: "1.17", Removed: "1.18"},
+	"SeccompDefault":                                 {Added: "1.22", Removed: "1.29"},
+	"SelectorIndex":                                  {Added: "1.18", GA: "1.20", Removed: "1.25"},
+	"ServerSideApply":                                {GA: "1.22", Removed: "1.32"},
+	"ServiceAccountIssuerDiscovery":                  {Added: "1.18", GA: "1.21", Removed: "1.23"},
+	"ServiceAppProtocol":                             {Added: "1.18", GA: "1.20", Removed: "1.22"},
+	"ServiceInternalTrafficPolicy":                   {Added: "1.21", Removed: "1.28"},
+	"ServiceLBNodePortControl":                       {Added: "1.20", Removed: "1.26"},
+	"ServiceLoadBalancerClass":                       {Added: "1.21", Removed: "1.26"},
+	"ServiceLoadBalancerFinalizer":                   {GA: "1.17", Removed: "1.20"},
+	"ServiceNodeExclusion":                           {GA: "1.21", Removed: "1.22"},
+	"ServiceTopology":                                {Deprecated: "1.21", Removed: "1.22"},
+	"SetHostnameAsFQDN":                              {Added: "1.19", GA: "1.22", Removed: "1.24"},
+	"SizeMemoryBackedVolumes":                        {Added: "1.20"},
+	"StartupProbe":                                   {GA: "1.20", Removed: "1.23"},
+	"StatefulSetMinReadySeconds":                     {Added: "1.22", Removed: "1.27"},
+	"StorageObjectInUseProtection":                   {GA: "1.11", Removed: "1.25"},
+	"StorageVersionAPI":                              {Added: "1.20"},
+	"StorageVersionHash":                             {},
+	"StreamingProxyRedirects":                        {Deprecated: "1.18", Removed: "1.24"},
+	"SupportIPVSProxyMode":                           {GA: "1.11", Removed: "1.20"},
+	"SupportNodePidsLimit":                           {GA: "1.20", Removed: "1.23"},
+	"SupportPodPidsLimit":                            {GA: "1.20", Removed: "1.23"},
+	"SuspendJob":                                     {Added: "1.21", Removed: "1.26"},
+	"Sysctls":                                        {GA: "1.21", Removed: "1.23"},
+	"TTLAfterFinished":                               {Removed: "1.25"},
+	"TaintBasedEvictions":                            {GA: "1.18", Removed: "1.20"},
+	"TokenRequest":                                   {GA: "1.20", Removed: "1.21"},
+	"TokenRequestProjection":                         {GA: "1.20", Removed: "1.21"},
+	"TopologyAwareHints":                             {Added: "1.21"},
+	"TopologyManager":                                {Removed: "1.29"},
+	"ValidateProxyRedirects":                         {Removed: "1.24"},
+	"VolumeCapacityPriority":                         {Added: "1.21"},
+	"VolumePVCDataSource":                            {GA: "1.18", Removed: "1.21"},
+	"VolumeSnapshotDataSource":                       {GA: "1.20", Removed: "1.22"},
+	"VolumeSubpath":                                  {Removed: "1.25"},
+	"VolumeSubpathEnvExpansion":                      {GA: "1.17", Removed: "1.19"},
+	"WarningHeaders":                                 {Added: "1.19", GA: "1.22", Removed: "1.24"},
+	"WatchBookmark":                                  {GA: "1.17"},
+	"WinDSR":                                         {},
+	"WinOverlay":                                     {},
+	"WindowsEndpointSliceProxying":                   {Added: "1.19", GA: "1.22", Removed: "1.25"},
+	"WindowsGMSA":                                    {GA: "1.18", Removed: "1.21"},
+	"WindowsHostProcessContainers":                   {Added: "1.22", Removed: "1.28"},
+	"WindowsRunAsUserName":                           {GA: "1.18", Removed: "1.21"},
+}
+
+// checkFeatureGate returns why a feature gate cannot be set at the given Kubernetes version,
+// or else why setting it deserves a warning.
+func checkFeatureGate(name string, kubernetesVersion semver.Version) (problem string, warning string) {
+	lifecycle, found := knownFeatureGates[name]
+	if !found {
+		// The table cannot know the gates of Kubernetes versions newer than kOps.
+		return "", fmt.Sprintf("feature gate %q is not known to kOps, check that it exists in Kubernetes %s", name, kubernetesVersion)
+	}
+
+	gte := func(version string) bool {
+		return version != "" && kubernetesVersion.GTE(semver.MustParse(version+".0"))
+	}
+
+	if lifecycle.Added != "" && !gte(lifecycle.Added) {
+		return fmt.Sprintf("feature gate was added in Kubernetes %s", lifecycle.Added), ""
+	}
+	if gte(lifecycle.Removed) {
+		return fmt.Sprintf("feature gate was removed in Kubernetes %s", lifecycle.Removed), ""
+	}
+	if gte(lifecycle.GA) {
+		if lifecycle.Removed != "" {
+			return "", fmt.Sprintf("feature gate %q graduated in Kubernetes %s and is removed in Kubernetes %s", name, lifecycle.GA, lifecycle.Removed)
+		}
+		return "", fmt.Sprintf("feature gate %q graduated in Kubernetes %s and will be removed in a later release", name, lifecycle.GA)
+	}
+	if gte(lifecycle.Deprecated) {
+		if lifecycle.Removed != "" {
+			return "", fmt.Sprintf("feature gate %q is deprecated since Kubernetes %s and is removed in Kubernetes %s", name, lifecycle.Deprecated, lifecycle.Removed)
+		}
+		return "", fmt.Sprintf("feature gate %q is deprecated since Kubernetes %s and will be removed in a later release", name, lifecycle.Deprecated)
+	}
+	if lifecycle.Removed != "" {
+		return "", fmt.Sprintf("feature gate %q is removed in Kubernetes %s", name, lifecycle.Removed)
+	}
+	return "", ""
+}
+
+// validateFeatureGates fails when a gate of spec.featureGates does not exist yet, or no longer exists,
+// at the Kubernetes version of the cluster.
+func validateFeatureGates(gates map[string]bool, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	kubernetesVersion, err := util.ParseKubernetesVersion(c.Spec.KubernetesVersion)
+	if err != nil {
+		return allErrs
+	}
+
+	var names []string
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if problem, _ := checkFeatureGate(name, *kubernetesVersion); problem != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), gates[name], fmt.Sprintf("%s, and cannot be set on Kubernetes %s", problem, c.Spec.KubernetesVersion)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateComponentFeatureGates checks that the feature gates of a single component are true or false. As these have
+// always taken any gate, gates that do not exist at the Kubernetes version of the cluster are only warned about.
+func validateComponentFeatureGates(gates map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, name := range sortedComponentFeatureGates(gates) {
+		if _, err := strconv.ParseBool(gates[name]); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), gates[name], "must be true or false"))
+		}
+	}
+
+	return allErrs
+}
+
+// FeatureGateWarnings returns a warning for each feature gate of the cluster or of its instance groups that is unknown,
+// has graduated or is deprecated, and for each gate that is still set on a single component of the cluster.
+func FeatureGateWarnings(c *kops.Cluster, groups []*kops.InstanceGroup) []string {
+	var warnings []string
+
+	kubernetesVersion, err := util.ParseKubernetesVersion(c.Spec.KubernetesVersion)
+	if err != nil {
+		return warnings
+	}
+
+	fieldPath := field.NewPath("spec")
+
+	var names []string
+	for name := range c.Spec.FeatureGates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, warning := checkFeatureGate(name, *kubernetesVersion); warning != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", fieldPath.Child("featureGates").Key(name), warning))
+		}
+	}
+
+	componentGates := make(map[string]map[string]string)
+	if c.Spec.KubeAPIServer != nil {
+		componentGates["kubeAPIServer"] = c.Spec.KubeAPIServer.FeatureGates
+	}
+	if c.Spec.KubeControllerManager != nil {
+		componentGates["kubeControllerManager"] = c.Spec.KubeControllerManager.FeatureGates
+	}
+	if c.Spec.KubeScheduler != nil {
+		componentGates["kubeScheduler"] = c.Spec.KubeScheduler.FeatureGates
+	}
+	if c.Spec.ExternalCloudControllerManager != nil {
+		componentGates["cloudControllerManager"] = c.Spec.ExternalCloudControllerManager.FeatureGates
+	}
+	if c.Spec.KubeProxy != nil {
+		componentGates["kubeProxy"] = c.Spec.KubeProxy.FeatureGates
+	}
+	if c.Spec.Kubelet != nil {
+		componentGates["kubelet"] = c.Spec.Kubelet.FeatureGates
+	}
+	if c.Spec.MasterKubelet != nil {
+		componentGates["masterKubelet"] = c.Spec.MasterKubelet.FeatureGates
+	}
+
+	for _, component := range []string{"kubeAPIServer", "kubeControllerManager", "kubeScheduler", "cloudControllerManager", "kubeProxy", "kubelet", "masterKubelet"} {
+		gates := componentGates[component]
+		gatesPath := fieldPath.Child(component, "featureGates")
+
+		// The gates of the components are superseded by spec.featureGates, which applies to all of them.
+		for _, name := range sortedComponentFeatureGates(gates) {
+			clusterPath := fieldPath.Child("featureGates").Key(name)
+			if enabled, found := c.Spec.FeatureGates[name]; !found {
+				warnings = append(warnings, fmt.Sprintf("%s: setting feature gates on a single component is deprecated, set %s instead", gatesPath.Key(name), clusterPath))
+			} else if gates[name] != strconv.FormatBool(enabled) {
+				warnings = append(warnings, fmt.Sprintf("%s: overrides %s, setting feature gates on a single component is deprecated", gatesPath.Key(name), clusterPath))
+			}
+		}
+
+		warnings = append(warnings, componentFeatureGateWarnings(gates, c, *kubernetesVersion, gatesPath)...)
+	}
+
+	// Instance groups can still set gates on their kubelets, for features that only some nodes need.
+	for _, g := range groups {
+		if g.Spec.Kubelet == nil {
+			continue
+		}
+		for _, warning := range componentFeatureGateWarnings(g.Spec.Kubelet.FeatureGates, c, *kubernetesVersion, fieldPath.Child("kubelet", "featureGates")) {
+			warnings = append(warnings, fmt.Sprintf("InstanceGroup %q %s", g.ObjectMeta.Name, warning))
+		}
+	}
+
+	return warnings
+}
+
+// componentFeatureGateWarnings returns the warnings about the gates of a single component, other than the gates that
+// have the same value in spec.featureGates, as these are warned about already.
+func componentFeatureGateWarnings(gates map[string]string, c *kops.Cluster, kubernetesVersion semver.Version, fldPath *field.Path) []string {
+	var warnings []string
+
+	for _, name := range sortedComponentFeatureGates(gates) {
+		value := gates[name]
+		if _, err := strconv.ParseBool(value); err != nil {
+			continue
+		}
+		if enabled, found := c.Spec.FeatureGates[name]; found && value == strconv.FormatBool(enabled) {
+			continue
+		}
+		problem, warning := checkFeatureGate(name, kubernetesVersion)
+		if problem != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: %s, and may prevent the component from starting on Kubernetes %s", fldPath.Key(name), problem, c.Spec.KubernetesVersion))
+		}
+		if warning != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", fldPath.Key(name), warning))
+		}
+	}
+
+	return warnings
+}
+
+// sortedComponentFeatureGates returns the names of the feature gates of a component in order.
+func sortedComponentFeatureGates(gates map[string]string) []string {
+	var names []string
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
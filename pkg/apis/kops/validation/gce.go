@@ -17,8 +17,11 @@ limitations under the License.
 package validation
 
 import (
+	"strings"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
 )
 
 func gceValidateCluster(c *kops.Cluster) field.ErrorList {
@@ -45,3 +48,71 @@ func gceValidateCluster(c *kops.Cluster) field.ErrorList {
 
 	return allErrs
 }
+
+func gceValidateInstanceGroup(ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if fi.BoolValue(ig.Spec.ConfidentialVM) && ig.Spec.MachineType != "" && !strings.HasPrefix(ig.Spec.MachineType, "n2d-") {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "machineType"), "Confidential VMs require an N2D machine type"))
+	}
+
+	if ig.Spec.ProvisioningPolicy != nil {
+		allErrs = append(allErrs, gceValidateProvisioningPolicy(ig, field.NewPath("spec", "provisioningPolicy"))...)
+	}
+
+	return allErrs
+}
+
+func gceValidateProvisioningPolicy(ig *kops.InstanceGroup, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	policy := ig.Spec.ProvisioningPolicy
+
+	if policy.ProvisioningModel != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("provisioningModel"), &policy.ProvisioningModel, kops.ProvisioningModels)...)
+	}
+	if policy.OnHostMaintenance != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("onHostMaintenance"), &policy.OnHostMaintenance, kops.OnHostMaintenanceActions)...)
+		if policy.OnHostMaintenance == kops.OnHostMaintenanceMigrate && fi.BoolValue(ig.Spec.ConfidentialVM) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("onHostMaintenance"), "Confidential VMs cannot be live migrated"))
+		}
+	}
+
+	hasStandard := len(policy.MachineTypes) == 0 && policy.ProvisioningModel != kops.ProvisioningModelSpot
+	seen := make(map[string]bool)
+	for i, machineType := range policy.MachineTypes {
+		f := fldPath.Child("machineTypes").Index(i)
+		if machineType.Name == "" {
+			allErrs = append(allErrs, field.Required(f.Child("name"), "machine type name must be specified"))
+		}
+		if machineType.Weight != nil && *machineType.Weight < 1 {
+			allErrs = append(allErrs, field.Invalid(f.Child("weight"), *machineType.Weight, "weight must be at least 1"))
+		}
+		provisioningModel := policy.ProvisioningModel
+		if machineType.ProvisioningModel != "" {
+			allErrs = append(allErrs, IsValidValue(f.Child("provisioningModel"), &machineType.ProvisioningModel, kops.ProvisioningModels)...)
+			provisioningModel = machineType.ProvisioningModel
+		}
+		if provisioningModel != kops.ProvisioningModelSpot {
+			hasStandard = true
+		}
+		key := machineType.Name + "/" + provisioningModel
+		if seen[key] {
+			allErrs = append(allErrs, field.Duplicate(f, machineType.Name))
+		}
+		seen[key] = true
+	}
+	if len(policy.MachineTypes) != 0 && ig.Spec.MachineType != "" && ig.Spec.MachineType != policy.MachineTypes[0].Name {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "machineType"), "machineType must be the first machine type of the provisioning policy"))
+	}
+
+	if !hasStandard {
+		if fi.BoolValue(policy.AutomaticRestart) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("automaticRestart"), "spot instances cannot be restarted automatically"))
+		}
+		if policy.OnHostMaintenance == kops.OnHostMaintenanceMigrate {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("onHostMaintenance"), "spot instances cannot be live migrated"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestGCEValidateInstanceGroup(t *testing.T) {
+	grid := []struct {
+		Input          kops.InstanceGroupSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType:           "n2d-standard-2",
+				RegionalInstanceGroup: fi.Bool(true),
+				ShieldedVM:            &kops.ShieldedVMSpec{SecureBoot: fi.Bool(true)},
+				ConfidentialVM:        fi.Bool(true),
+				NestedVirtualization:  fi.Bool(true),
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType:    "n1-standard-2",
+				ConfidentialVM: fi.Bool(true),
+			},
+			ExpectedErrors: []string{"Forbidden::spec.machineType"},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType:    "n1-standard-2",
+				ConfidentialVM: fi.Bool(false),
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType: "n2-standard-2",
+				ProvisioningPolicy: &kops.ProvisioningPolicySpec{
+					ProvisioningModel: "SPOT",
+					MachineTypes: []kops.ProvisioningMachineTypeSpec{
+						{Name: "n2-standard-2", ProvisioningModel: "STANDARD"},
+						{Name: "n2-standard-2", Weight: fi.Int32(3)},
+						{Name: "e2-standard-2", Weight: fi.Int32(2)},
+					},
+					AutomaticRestart:  fi.Bool(true),
+					OnHostMaintenance: "MIGRATE",
+				},
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				ProvisioningPolicy: &kops.ProvisioningPolicySpec{
+					ProvisioningModel: "PREEMPTIBLE",
+					OnHostMaintenance: "RESTART",
+				},
+			},
+			ExpectedErrors: []string{
+				"Unsupported value::spec.provisioningPolicy.provisioningModel",
+				"Unsupported value::spec.provisioningPolicy.onHostMaintenance",
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				ProvisioningPolicy: &kops.ProvisioningPolicySpec{
+					ProvisioningModel: "SPOT",
+					AutomaticRestart:  fi.Bool(true),
+					OnHostMaintenance: "MIGRATE",
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.provisioningPolicy.automaticRestart",
+				"Forbidden::spec.provisioningPolicy.onHostMaintenance",
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType: "n2-standard-2",
+				ProvisioningPolicy: &kops.ProvisioningPolicySpec{
+					MachineTypes: []kops.ProvisioningMachineTypeSpec{
+						{Name: "e2-standard-2"},
+						{Name: "e2-standard-2", Weight: fi.Int32(0)},
+						{},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Duplicate value::spec.provisioningPolicy.machineTypes[1]",
+				"Invalid value::spec.provisioningPolicy.machineTypes[1].weight",
+				"Required value::spec.provisioningPolicy.machineTypes[2].name",
+				"Forbidden::spec.machineType",
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType:    "n2d-standard-2",
+				ConfidentialVM: fi.Bool(true),
+				ProvisioningPolicy: &kops.ProvisioningPolicySpec{
+					OnHostMaintenance: "MIGRATE",
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.provisioningPolicy.onHostMaintenance"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: g.Input,
+		}
+		errs := gceValidateInstanceGroup(ig)
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestCrossValidateGCEOnlyFields(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			CloudProvider:     string(kops.CloudProviderAWS),
+			KubernetesVersion: "1.21.0",
+		},
+	}
+	ig := &kops.InstanceGroup{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "nodes",
+		},
+		Spec: kops.InstanceGroupSpec{
+			Role:                  kops.InstanceGroupRoleNode,
+			RegionalInstanceGroup: fi.Bool(true),
+			ShieldedVM:            &kops.ShieldedVMSpec{},
+			ConfidentialVM:        fi.Bool(true),
+			NestedVirtualization:  fi.Bool(true),
+			ProvisioningPolicy:    &kops.ProvisioningPolicySpec{},
+		},
+	}
+	errs := CrossValidateInstanceGroup(ig, cluster, nil)
+	testErrors(t, ig.Spec, errs, []string{
+		"Forbidden::spec.regionalInstanceGroup",
+		"Forbidden::spec.shieldedVM",
+		"Forbidden::spec.confidentialVM",
+		"Forbidden::spec.nestedVirtualization",
+		"Forbidden::spec.provisioningPolicy",
+	})
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/dns"
+)
+
+func hybridValidateInstanceGroup(ig *kops.InstanceGroup, cluster *kops.Cluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldSpec := field.NewPath("spec")
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("role"), "Hybrid role only supported on AWS"))
+		return allErrs
+	}
+
+	// The machines have no cloud identity, so they can only join through kops-controller.
+	if !model.UseKopsControllerForNodeBootstrap(cluster) {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("role"), "Hybrid role requires Kubernetes 1.19 or later"))
+	}
+
+	// Gossip discovers its peers through the cloud API.
+	if dns.IsGossipHostname(cluster.ObjectMeta.Name) {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("role"), "Hybrid role is not supported with gossip DNS"))
+	}
+
+	networking := cluster.Spec.Networking
+	if networking != nil && (networking.AmazonVPC != nil || networking.LyftVPC != nil || (networking.Cilium != nil && networking.Cilium.Ipam == kops.CiliumIpamEni)) {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("role"), "Hybrid role is not supported with networking that assigns pod IPs from the VPC"))
+	}
+
+	if len(ig.Spec.Subnets) != 0 {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("subnets"), "hybrid machines are not in the subnets of the cluster"))
+	}
+	if ig.Spec.MinSize != nil || ig.Spec.MaxSize != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("minSize"), "hybrid machines are enrolled with kops toolbox enroll, not sized"))
+	}
+	if ig.Spec.WarmPool != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("warmPool"), "warm pools are not supported for hybrid machines"))
+	}
+
+	return allErrs
+}
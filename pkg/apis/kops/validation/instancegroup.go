@@ -18,16 +18,21 @@ package validation
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"k8s.io/kops/pkg/nodeidentity/aws"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/distributions"
 )
 
 // ValidateInstanceGroup is responsible for validating the configuration of a instancegroup
@@ -48,6 +53,7 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud) field.ErrorLis
 	case kops.InstanceGroupRoleNode:
 	case kops.InstanceGroupRoleBastion:
 	case kops.InstanceGroupRoleAPIServer:
+	case kops.InstanceGroupRoleHybrid:
 	default:
 		var supported []string
 		for _, role := range kops.AllInstanceGroupRoles {
@@ -79,6 +85,8 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud) field.ErrorLis
 		allErrs = append(allErrs, validateHookSpec(&g.Spec.Hooks[i], field.NewPath("spec", "hooks").Index(i))...)
 	}
 
+	allErrs = append(allErrs, validateNodePlugins(g.Spec.NodePlugins, field.NewPath("spec", "nodePlugins"))...)
+
 	// @check the fileAssets for this instancegroup are valid
 	for i := range g.Spec.FileAssets {
 		allErrs = append(allErrs, validateFileAssetSpec(&g.Spec.FileAssets[i], field.NewPath("spec", "fileAssets").Index(i))...)
@@ -131,14 +139,55 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud) field.ErrorLis
 		allErrs = append(allErrs, validateIGCloudLabels(g, field.NewPath("spec", "cloudLabels"))...)
 	}
 
+	allErrs = append(allErrs, validateSysctls(g.Spec.Sysctls, field.NewPath("spec", "sysctls"))...)
+	allErrs = append(allErrs, validateKernelModules(g.Spec.KernelModules, field.NewPath("spec", "kernelModules"))...)
+
+	if g.Spec.Swap != nil {
+		allErrs = append(allErrs, validateSwap(g.Spec.Swap, field.NewPath("spec", "swap"))...)
+	}
+
+	if g.Spec.Kubelet != nil {
+		allErrs = append(allErrs, validateContainerLogRotation(g.Spec.Kubelet, field.NewPath("spec", "kubelet"))...)
+		allErrs = append(allErrs, validateComponentFeatureGates(g.Spec.Kubelet.FeatureGates, field.NewPath("spec", "kubelet", "featureGates"))...)
+	}
+
+	if g.Spec.Logging != nil {
+		allErrs = append(allErrs, validateInstanceGroupLogging(g, field.NewPath("spec", "logging"))...)
+	}
+
+	if g.Spec.StateVolumes != nil {
+		allErrs = append(allErrs, validateStateVolumes(g, field.NewPath("spec", "stateVolumes"))...)
+	}
+
+	if g.Spec.InstanceStorage != nil {
+		allErrs = append(allErrs, validateInstanceStorage(g, field.NewPath("spec", "instanceStorage"))...)
+	}
+
+	allErrs = append(allErrs, validateSecurityModules(g, field.NewPath("spec"))...)
+
 	if cloud != nil && cloud.ProviderID() == kops.CloudProviderAWS {
 		allErrs = append(allErrs, awsValidateInstanceGroup(g, cloud.(awsup.AWSCloud))...)
 	}
 
+	names := sets.NewString()
+	targetGroupARNs := sets.NewString()
 	for i, lb := range g.Spec.ExternalLoadBalancers {
 		path := field.NewPath("spec", "externalLoadBalancers").Index(i)
 
 		allErrs = append(allErrs, validateExternalLoadBalancer(&lb, path)...)
+
+		if lb.Name != "" {
+			if names.Has(lb.Name) {
+				allErrs = append(allErrs, field.Duplicate(path.Child("name"), lb.Name))
+			}
+			names.Insert(lb.Name)
+		}
+		if lb.TargetGroupARN != nil {
+			if targetGroupARNs.Has(*lb.TargetGroupARN) {
+				allErrs = append(allErrs, field.Duplicate(path.Child("targetGroupArn"), *lb.TargetGroupARN))
+			}
+			targetGroupARNs.Insert(*lb.TargetGroupARN)
+		}
 	}
 
 	allErrs = append(allErrs, IsValidValue(field.NewPath("spec", "updatePolicy"), g.Spec.UpdatePolicy, []string{kops.UpdatePolicyAutomatic, kops.UpdatePolicyExternal})...)
@@ -187,10 +236,16 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		allErrs = append(allErrs, ValidateMasterInstanceGroup(g, cluster)...)
 	}
 
+	allErrs = append(allErrs, validateInstanceGroupCapabilities(g, cluster)...)
+
 	if g.Spec.Role == kops.InstanceGroupRoleAPIServer && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "role"), "Apiserver role only supported on AWS"))
 	}
 
+	if g.Spec.Role == kops.InstanceGroupRoleHybrid {
+		allErrs = append(allErrs, hybridValidateInstanceGroup(g, cluster)...)
+	}
+
 	// Check that instance groups are defined in subnets that are defined in the cluster
 	{
 		clusterSubnets := make(map[string]*kops.ClusterSubnetSpec)
@@ -206,13 +261,129 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		}
 	}
 
+	// Check that the SSH public keys of the instance group are defined in the cluster
+	{
+		clusterKeys := sets.NewString()
+		if cluster.Spec.SSH != nil {
+			for _, key := range cluster.Spec.SSH.PublicKeys {
+				clusterKeys.Insert(key.Name)
+			}
+		}
+
+		for i, name := range g.Spec.SSHPublicKeys {
+			if !clusterKeys.Has(name) {
+				allErrs = append(allErrs, field.NotFound(field.NewPath("spec", "sshPublicKeys").Index(i), name))
+			}
+		}
+	}
+
 	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderAWS {
 		if g.Spec.RootVolumeType != nil {
 			allErrs = append(allErrs, IsValidValue(field.NewPath("spec", "rootVolumeType"), g.Spec.RootVolumeType, []string{"standard", "gp3", "gp2", "io1", "io2"})...)
 		}
+		if cloud != nil && cloud.ProviderID() == kops.CloudProviderAWS {
+			allErrs = append(allErrs, awsValidateInstanceTypeZones(g, cluster, cloud.(awsup.AWSCloud))...)
+			allErrs = append(allErrs, awsValidateExternalTargetGroups(g, cluster, cloud.(awsup.AWSCloud))...)
+		}
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderAzure {
+		allErrs = append(allErrs, azureValidateInstanceGroup(g)...)
+	} else {
+		if g.Spec.RootVolumeEphemeral != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "rootVolumeEphemeral"), "ephemeral root volumes only supported on Azure"))
+		}
+		if g.Spec.AcceleratedNetworking != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "acceleratedNetworking"), "accelerated networking only supported on Azure"))
+		}
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderOpenstack {
+		allErrs = append(allErrs, openstackValidateInstanceGroup(g)...)
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderOCI {
+		allErrs = append(allErrs, ociValidateInstanceGroup(g)...)
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+		allErrs = append(allErrs, metalValidateInstanceGroup(g)...)
+	} else if g.Spec.Metal != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "metal"), "metal is only supported on the metal cloud provider"))
+	}
+
+	if g.Spec.Logging != nil && g.Spec.Logging.Volume != nil && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "logging", "volume"), "log volumes are only supported on AWS"))
+	}
+
+	if g.Spec.InstanceStorage != nil && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "instanceStorage"), "instance storage is only supported on AWS"))
+	}
+
+	if g.Spec.StateVolumes != nil {
+		if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "stateVolumes"), "state volumes are only supported on AWS"))
+		}
+		if g.Spec.StateVolumes.Containerd != nil && cluster.Spec.ContainerRuntime == "docker" {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "stateVolumes", "containerd"), "a containerd state volume requires the containerd container runtime"))
+		}
+	}
+
+	if cluster.Spec.CloudConfig != nil && cluster.Spec.CloudConfig.EBSDefaultEncryption != nil {
+		allErrs = append(allErrs, validateInstanceGroupEBSDefaultEncryption(g)...)
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderGCE {
+		allErrs = append(allErrs, gceValidateInstanceGroup(g)...)
 	} else {
-		if g.Spec.WarmPool != nil {
-			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "warmPool"), "warm pool only supported on AWS"))
+		if g.Spec.RegionalInstanceGroup != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "regionalInstanceGroup"), "regional instance groups only supported on GCE"))
+		}
+		if g.Spec.ShieldedVM != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "shieldedVM"), "Shielded VMs only supported on GCE"))
+		}
+		if g.Spec.ConfidentialVM != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "confidentialVM"), "Confidential VMs only supported on GCE"))
+		}
+		if g.Spec.NestedVirtualization != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "nestedVirtualization"), "nested virtualization only supported on GCE"))
+		}
+		if g.Spec.ProvisioningPolicy != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "provisioningPolicy"), "provisioning policies only supported on GCE"))
+		}
+	}
+
+	if g.Spec.Swap != nil {
+		fldPath := field.NewPath("spec", "swap")
+		if !cluster.IsKubernetesGTE("1.22") {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "swap requires Kubernetes 1.22 or later"))
+		}
+		if g.Spec.Swap.SwapBehavior != "" {
+			useConfigFile := cluster.Spec.Kubelet != nil && fi.BoolValue(cluster.Spec.Kubelet.UseConfigFile)
+			if g.Spec.Role == kops.InstanceGroupRoleMaster {
+				useConfigFile = cluster.Spec.MasterKubelet != nil && fi.BoolValue(cluster.Spec.MasterKubelet.UseConfigFile)
+			}
+			if g.Spec.Kubelet != nil && g.Spec.Kubelet.UseConfigFile != nil {
+				useConfigFile = *g.Spec.Kubelet.UseConfigFile
+			}
+			if !useConfigFile {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("swapBehavior"), "swapBehavior requires the kubelet to use a config file (kubelet.useConfigFile)"))
+			}
+		}
+	}
+
+	if g.Spec.Kubelet != nil && fi.BoolValue(g.Spec.Kubelet.ServerTLSBootstrap) && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "kubelet", "serverTLSBootstrap"), "kubelet server TLS bootstrap is only supported on AWS"))
+	}
+
+	if fi.BoolValue(cluster.Spec.HostFIPSMode) {
+		allErrs = append(allErrs, validateFIPSImage(g, field.NewPath("spec", "image"))...)
+	}
+
+	for _, gateway := range cluster.Spec.EgressGateways {
+		if gateway.InstanceGroup == g.ObjectMeta.Name {
+			allErrs = append(allErrs, validateEgressGatewayInstanceGroup(g, cluster)...)
+			break
 		}
 	}
 
@@ -314,6 +485,272 @@ func validateNodeLabels(labels map[string]string, fldPath *field.Path) (allErrs
 	return allErrs
 }
 
+// safeSysctlPrefixes are the prefixes of the kernel parameters that may be set with spec.sysctls.
+// Other parameters can still be set with spec.sysctlParameters.
+var safeSysctlPrefixes = []string{
+	"fs.aio-max-nr",
+	"fs.file-max",
+	"fs.inotify.",
+	"fs.nr_open",
+	"kernel.keys.",
+	"kernel.msgmax",
+	"kernel.msgmnb",
+	"kernel.pid_max",
+	"kernel.sem",
+	"kernel.shmall",
+	"kernel.shmmax",
+	"kernel.threads-max",
+	"net.core.",
+	"net.ipv4.",
+	"net.ipv6.",
+	"net.netfilter.nf_conntrack_",
+	"vm.",
+}
+
+var sysctlNameRegex = regexp.MustCompile(`^[a-z0-9_-]+(\.[a-zA-Z0-9_-]+)+$`)
+
+func validateSysctls(sysctls map[string]string, fldPath *field.Path) (allErrs field.ErrorList) {
+	for name, value := range sysctls {
+		if !sysctlNameRegex.MatchString(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), name, "must be a dot-separated kernel parameter name"))
+			continue
+		}
+
+		safe := false
+		for _, prefix := range safeSysctlPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				safe = true
+				break
+			}
+		}
+		if !safe {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(name), "kernel parameter is not in the allowlist; use sysctlParameters to set it"))
+		}
+
+		if strings.TrimSpace(value) == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Key(name), "value must be set"))
+		} else if strings.ContainsAny(value, "\n=") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), value, "value must not contain newlines or '='"))
+		}
+	}
+	return allErrs
+}
+
+var kernelModuleRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+func validateKernelModules(modules []string, fldPath *field.Path) (allErrs field.ErrorList) {
+	seen := make(map[string]bool)
+	for i, module := range modules {
+		if !kernelModuleRegex.MatchString(module) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), module, "must be a kernel module name"))
+		}
+		if seen[module] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), module))
+		}
+		seen[module] = true
+	}
+	return allErrs
+}
+
+func validateSwap(swap *kops.SwapSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if swap.Size == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("size"), "swap size must be set"))
+	} else if swap.Size.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), swap.Size.String(), "swap size must be greater than 0"))
+	}
+
+	if swap.Swappiness != nil && (*swap.Swappiness < 0 || *swap.Swappiness > 200) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("swappiness"), *swap.Swappiness, "swappiness must be between 0 and 200"))
+	}
+
+	if swap.SwapBehavior != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("swapBehavior"), &swap.SwapBehavior, []string{"LimitedSwap", "UnlimitedSwap"})...)
+	}
+
+	return allErrs
+}
+
+var journaldSizeRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?[KMGTPE]?$`)
+
+func validateInstanceGroupLogging(g *kops.InstanceGroup, fldPath *field.Path) (allErrs field.ErrorList) {
+	logging := g.Spec.Logging
+
+	if logging.JournaldSystemMaxUse != "" && !journaldSizeRegexp.MatchString(logging.JournaldSystemMaxUse) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("journaldSystemMaxUse"), logging.JournaldSystemMaxUse, "must be a size, such as 500M"))
+	}
+
+	if logging.Volume != nil {
+		volumePath := fldPath.Child("volume")
+		allErrs = append(allErrs, validateVolumeSpec(volumePath, *logging.Volume)...)
+		for _, x := range g.Spec.Volumes {
+			if logging.Volume.Device != "" && x.Device == logging.Volume.Device {
+				allErrs = append(allErrs, field.Duplicate(volumePath.Child("device"), x.Device))
+			}
+		}
+		for _, x := range g.Spec.VolumeMounts {
+			if x.Path == nodeup.LogVolumePath {
+				allErrs = append(allErrs, field.Forbidden(volumePath, "a volume is already mounted on "+nodeup.LogVolumePath))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateStateVolumes(g *kops.InstanceGroup, fldPath *field.Path) (allErrs field.ErrorList) {
+	devices := sets.NewString()
+	for _, x := range g.Spec.Volumes {
+		devices.Insert(x.Device)
+	}
+	if g.Spec.Logging != nil && g.Spec.Logging.Volume != nil {
+		devices.Insert(g.Spec.Logging.Volume.Device)
+	}
+
+	for _, stateVolume := range []struct {
+		name   string
+		path   string
+		volume *kops.VolumeSpec
+	}{
+		{"containerd", nodeup.ContainerdVolumePath, g.Spec.StateVolumes.Containerd},
+		{"kubelet", nodeup.KubeletVolumePath, g.Spec.StateVolumes.Kubelet},
+	} {
+		if stateVolume.volume == nil {
+			continue
+		}
+		volumePath := fldPath.Child(stateVolume.name)
+		allErrs = append(allErrs, validateVolumeSpec(volumePath, *stateVolume.volume)...)
+		if stateVolume.volume.Device != "" {
+			if devices.Has(stateVolume.volume.Device) {
+				allErrs = append(allErrs, field.Duplicate(volumePath.Child("device"), stateVolume.volume.Device))
+			}
+			devices.Insert(stateVolume.volume.Device)
+		}
+		for _, x := range g.Spec.VolumeMounts {
+			if x.Path == stateVolume.path {
+				allErrs = append(allErrs, field.Forbidden(volumePath, "a volume is already mounted on "+stateVolume.path))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// instanceStoragePathRegexp and mountOptionRegexp only accept the characters that need no quoting in the setup script
+var (
+	instanceStoragePathRegexp = regexp.MustCompile(`^(/[a-zA-Z0-9_.-]+)+$`)
+	mountOptionRegexp         = regexp.MustCompile(`^[a-zA-Z0-9_.:=-]+$`)
+)
+
+func validateInstanceStorage(g *kops.InstanceGroup, fldPath *field.Path) (allErrs field.ErrorList) {
+	spec := g.Spec.InstanceStorage
+
+	if spec.Path == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("path"), "path must be set"))
+	} else if !instanceStoragePathRegexp.MatchString(spec.Path) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), spec.Path, "must be an absolute path other than /"))
+	} else {
+		mounted := []string{}
+		for _, x := range g.Spec.VolumeMounts {
+			mounted = append(mounted, x.Path)
+		}
+		if g.Spec.Logging != nil && g.Spec.Logging.Volume != nil {
+			mounted = append(mounted, nodeup.LogVolumePath)
+		}
+		if g.Spec.StateVolumes != nil && g.Spec.StateVolumes.Containerd != nil {
+			mounted = append(mounted, nodeup.ContainerdVolumePath)
+		}
+		if g.Spec.StateVolumes != nil && g.Spec.StateVolumes.Kubelet != nil {
+			mounted = append(mounted, nodeup.KubeletVolumePath)
+		}
+		for _, path := range mounted {
+			if path == spec.Path {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("path"), "a volume is already mounted on "+path))
+			}
+		}
+	}
+
+	if spec.Filesystem != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("filesystem"), &spec.Filesystem, []string{"ext4", "xfs"})...)
+	}
+
+	for i, option := range spec.MountOptions {
+		if !mountOptionRegexp.MatchString(option) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("mountOptions").Index(i), option, "must be a mount option, such as noatime"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateSecurityModules(g *kops.InstanceGroup, fldPath *field.Path) (allErrs field.ErrorList) {
+	if g.Spec.SELinux == nil && g.Spec.AppArmor == nil {
+		return allErrs
+	}
+
+	if g.Spec.SELinux != nil && g.Spec.AppArmor != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("appArmor"), "appArmor cannot be used together with selinux"))
+	}
+
+	// Only well-known images can be checked; other images are checked by nodeup
+	distribution, found := distributions.FindDistributionFamilyForImage(g.Spec.Image)
+
+	if g.Spec.SELinux != nil {
+		selinuxPath := fldPath.Child("selinux")
+		if g.Spec.SELinux.Mode == "" {
+			allErrs = append(allErrs, field.Required(selinuxPath.Child("mode"), "selinux mode must be set"))
+		} else {
+			allErrs = append(allErrs, IsValidValue(selinuxPath.Child("mode"), &g.Spec.SELinux.Mode, []string{kops.SELinuxModeEnforcing, kops.SELinuxModePermissive})...)
+		}
+		if found && !distribution.SupportsSELinux() {
+			allErrs = append(allErrs, field.Forbidden(selinuxPath, fmt.Sprintf("selinux is not supported by the distribution of image %q", g.Spec.Image)))
+		}
+	}
+
+	if g.Spec.AppArmor != nil {
+		appArmorPath := fldPath.Child("appArmor")
+		names := make(map[string]bool)
+		for i, profile := range g.Spec.AppArmor.Profiles {
+			profilePath := appArmorPath.Child("profiles").Index(i)
+			if profile.Name == "" {
+				allErrs = append(allErrs, field.Required(profilePath.Child("name"), "profile name must be set"))
+			} else if strings.ContainsAny(profile.Name, "/\\") || profile.Name == "." || profile.Name == ".." {
+				allErrs = append(allErrs, field.Invalid(profilePath.Child("name"), profile.Name, "profile name must be a valid file name"))
+			} else if names[profile.Name] {
+				allErrs = append(allErrs, field.Duplicate(profilePath.Child("name"), profile.Name))
+			}
+			names[profile.Name] = true
+
+			if profile.Content == "" {
+				allErrs = append(allErrs, field.Required(profilePath.Child("content"), "profile content must be set"))
+			}
+		}
+		if found && !distribution.SupportsAppArmor() {
+			allErrs = append(allErrs, field.Forbidden(appArmorPath, fmt.Sprintf("appArmor is not supported by the distribution of image %q", g.Spec.Image)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateFIPSImage checks that a well-known image supports FIPS mode; other images are checked by nodeup
+func validateFIPSImage(g *kops.InstanceGroup, fldPath *field.Path) (allErrs field.ErrorList) {
+	distribution, found := distributions.FindDistributionFamilyForImage(g.Spec.Image)
+	if !found {
+		return allErrs
+	}
+
+	supported := distribution.SupportsFIPS()
+	if distribution.IsUbuntu() && !strings.Contains(strings.ToLower(g.Spec.Image), "fips") {
+		// Only the FIPS images of Ubuntu Pro are validated
+		supported = false
+	}
+	if !supported {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("image %q does not support FIPS mode", g.Spec.Image)))
+	}
+
+	return allErrs
+}
+
 func validateIGCloudLabels(ig *kops.InstanceGroup, fldPath *field.Path) (allErrs field.ErrorList) {
 	labels := ig.Spec.CloudLabels
 	if labels == nil {
@@ -353,7 +790,30 @@ func validateExternalLoadBalancer(lb *kops.LoadBalancer, fldPath *field.Path) fi
 		}
 	}
 
-	if lb.TargetGroupARN != nil {
+	if lb.TargetGroupARN == nil {
+		if lb.Name != "" || lb.Port != nil || lb.Protocol != nil || lb.HealthCheckPort != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "name, port, protocol and healthCheckPort can only be specified with targetGroupArn"))
+		}
+	} else {
+		if lb.Name != "" {
+			for _, msg := range utilvalidation.IsDNS1123Label(lb.Name) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), lb.Name, msg))
+			}
+		}
+		if lb.Port != nil {
+			for _, msg := range utilvalidation.IsValidPortNum(int(*lb.Port)) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("port"), *lb.Port, msg))
+			}
+		}
+		if lb.Protocol != nil {
+			allErrs = append(allErrs, IsValidValue(fldPath.Child("protocol"), lb.Protocol, []string{"HTTP", "HTTPS", "TCP", "TCP_UDP", "TLS", "UDP"})...)
+		}
+		if lb.HealthCheckPort != nil {
+			for _, msg := range utilvalidation.IsValidPortNum(int(*lb.HealthCheckPort)) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("healthCheckPort"), *lb.HealthCheckPort, msg))
+			}
+		}
+
 		actual := fi.StringValue(lb.TargetGroupARN)
 
 		parsed, err := arn.Parse(actual)
@@ -385,3 +845,37 @@ func validateExternalLoadBalancer(lb *kops.LoadBalancer, fldPath *field.Path) fi
 
 	return allErrs
 }
+
+// validateEgressGatewayInstanceGroup checks that the instance group of an egress gateway has a single node,
+// with a public IP address the Elastic IP of the gateway can replace.
+func validateEgressGatewayInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster) (allErrs field.ErrorList) {
+	if g.Spec.Role != kops.InstanceGroupRoleNode {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "role"), "the instance group of an egress gateway must have the Node role"))
+	}
+	if g.Spec.MaxSize == nil || *g.Spec.MaxSize != 1 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "maxSize"), "the instance group of an egress gateway must have a single instance"))
+	}
+	subnetTypes := make(map[string]kops.SubnetType)
+	for _, subnet := range cluster.Spec.Subnets {
+		subnetTypes[subnet.Name] = subnet.Type
+	}
+	for i, subnet := range g.Spec.Subnets {
+		if subnetType, found := subnetTypes[subnet]; found && subnetType != kops.SubnetTypePublic {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "subnets").Index(i), "the instance group of an egress gateway must be in public subnets"))
+		}
+	}
+	return allErrs
+}
+
+// validateInstanceGroupEBSDefaultEncryption forbids unencrypted volumes, which cannot be created when EBS encryption by default is turned on.
+func validateInstanceGroupEBSDefaultEncryption(g *kops.InstanceGroup) (allErrs field.ErrorList) {
+	if g.Spec.RootVolumeEncryption != nil && !*g.Spec.RootVolumeEncryption {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "rootVolumeEncryption"), "root volumes are always encrypted when ebsDefaultEncryption is set"))
+	}
+	for i, volume := range g.Spec.Volumes {
+		if volume.Encrypted != nil && !*volume.Encrypted {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "volumes").Index(i).Child("encrypted"), "volumes are always encrypted when ebsDefaultEncryption is set"))
+		}
+	}
+	return allErrs
+}
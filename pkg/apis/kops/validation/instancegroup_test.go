@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/kops/pkg/nodeidentity/aws"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
@@ -233,6 +234,320 @@ func TestValidBootDevice(t *testing.T) {
 	}
 }
 
+func TestValidFIPSImage(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			CloudProvider: "aws",
+			HostFIPSMode:  fi.Bool(true),
+		},
+	}
+	grid := []struct {
+		image    string
+		expected []string
+	}{
+		{
+			image: "137112412989/amzn2-ami-hvm-2.0.20210721.2-x86_64-gp2",
+		},
+		{
+			image: "309956199498/RHEL-8.4.0_HVM-20210504-x86_64-2-Hourly2-GP2",
+		},
+		{
+			image: "aws-marketplace/ubuntu-pro-fips-server/images/hvm-ssd/ubuntu-bionic-18.04-amd64-pro-fips-server-20210430",
+		},
+		{
+			image: "example/custom-image",
+		},
+		{
+			image:    "099720109477/ubuntu/images/hvm-ssd/ubuntu-focal-20.04-amd64-server-20210720",
+			expected: []string{"Forbidden::spec.image"},
+		},
+		{
+			image:    "075585003325/Flatcar-stable-2765.2.6-hvm",
+			expected: []string{"Forbidden::spec.image"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:  "Node",
+				Image: g.image,
+			},
+		}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g.image, errs, g.expected)
+	}
+}
+
+func TestValidKubeletServerTLSBootstrap(t *testing.T) {
+	grid := []struct {
+		cloudProvider string
+		expected      []string
+	}{
+		{
+			cloudProvider: "aws",
+		},
+		{
+			cloudProvider: "gce",
+			expected:      []string{"Forbidden::spec.kubelet.serverTLSBootstrap"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: g.cloudProvider,
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role: "Node",
+				Kubelet: &kops.KubeletConfigSpec{
+					ServerTLSBootstrap: fi.Bool(true),
+				},
+			},
+		}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g.cloudProvider, errs, g.expected)
+	}
+}
+
+func TestValidInstanceGroupEBSDefaultEncryption(t *testing.T) {
+	grid := []struct {
+		spec     kops.InstanceGroupSpec
+		expected []string
+	}{
+		{
+			spec: kops.InstanceGroupSpec{
+				RootVolumeEncryption: fi.Bool(true),
+				Volumes: []kops.VolumeSpec{
+					{Device: "/dev/xvdd", Size: 20},
+				},
+			},
+		},
+		{
+			spec: kops.InstanceGroupSpec{
+				RootVolumeEncryption: fi.Bool(false),
+				Volumes: []kops.VolumeSpec{
+					{Device: "/dev/xvdd", Size: 20, Encrypted: fi.Bool(false)},
+				},
+			},
+			expected: []string{
+				"Forbidden::spec.rootVolumeEncryption",
+				"Forbidden::spec.volumes[0].encrypted",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: "aws",
+				CloudConfig: &kops.CloudConfiguration{
+					EBSDefaultEncryption: &kops.EBSDefaultEncryptionSpec{},
+				},
+			},
+		}
+		g.spec.Role = kops.InstanceGroupRoleNode
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: g.spec,
+		}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g.spec, errs, g.expected)
+	}
+}
+
+func TestValidHybridInstanceGroup(t *testing.T) {
+	grid := []struct {
+		cloudProvider string
+		clusterName   string
+		networking    kops.NetworkingSpec
+		spec          kops.InstanceGroupSpec
+		expected      []string
+	}{
+		{
+			cloudProvider: "aws",
+			clusterName:   "cluster.example.com",
+			networking:    kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}},
+		},
+		{
+			cloudProvider: "gce",
+			clusterName:   "cluster.example.com",
+			networking:    kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}},
+			expected:      []string{"Forbidden::spec.role"},
+		},
+		{
+			cloudProvider: "aws",
+			clusterName:   "cluster.k8s.local",
+			networking:    kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}},
+			expected:      []string{"Forbidden::spec.role"},
+		},
+		{
+			cloudProvider: "aws",
+			clusterName:   "cluster.example.com",
+			networking:    kops.NetworkingSpec{AmazonVPC: &kops.AmazonVPCNetworkingSpec{}},
+			expected:      []string{"Forbidden::spec.role"},
+		},
+		{
+			cloudProvider: "aws",
+			clusterName:   "cluster.example.com",
+			networking:    kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}},
+			spec: kops.InstanceGroupSpec{
+				Subnets:  []string{"us-east-1a"},
+				MinSize:  fi.Int32(1),
+				WarmPool: &kops.WarmPoolSpec{},
+			},
+			expected: []string{
+				"Forbidden::spec.subnets",
+				"Forbidden::spec.minSize",
+				"Forbidden::spec.warmPool",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: g.clusterName,
+			},
+			Spec: kops.ClusterSpec{
+				CloudProvider:     g.cloudProvider,
+				KubernetesVersion: "1.21.0",
+				Networking:        &g.networking,
+				Subnets: []kops.ClusterSubnetSpec{
+					{Name: "us-east-1a"},
+				},
+			},
+		}
+		g.spec.Role = kops.InstanceGroupRoleHybrid
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "hybrid",
+			},
+			Spec: g.spec,
+		}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g, errs, g.expected)
+	}
+}
+
+func TestValidEgressGatewayInstanceGroup(t *testing.T) {
+	grid := []struct {
+		name     string
+		role     kops.InstanceGroupRole
+		maxSize  int32
+		subnets  []string
+		expected []string
+	}{
+		{
+			name:    "egress",
+			role:    kops.InstanceGroupRoleNode,
+			maxSize: 1,
+			subnets: []string{"utility-us-east-1a"},
+		},
+		{
+			name:    "other",
+			role:    kops.InstanceGroupRoleNode,
+			maxSize: 3,
+			subnets: []string{"us-east-1a"},
+		},
+		{
+			name:    "egress",
+			role:    kops.InstanceGroupRoleNode,
+			maxSize: 2,
+			subnets: []string{"utility-us-east-1a", "us-east-1a"},
+			expected: []string{
+				"Forbidden::spec.maxSize",
+				"Forbidden::spec.subnets[1]",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Subnets: []kops.ClusterSubnetSpec{
+					{Name: "us-east-1a", Type: kops.SubnetTypePrivate},
+					{Name: "utility-us-east-1a", Type: kops.SubnetTypePublic},
+				},
+				EgressGateways: []kops.EgressGatewaySpec{
+					{Name: "partner", InstanceGroup: "egress", Namespaces: []string{"payments"}},
+				},
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: g.name,
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:    g.role,
+				MinSize: fi.Int32(1),
+				MaxSize: fi.Int32(g.maxSize),
+				Subnets: g.subnets,
+			},
+		}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g, errs, g.expected)
+	}
+}
+
+func TestValidInstanceGroupSSHPublicKeys(t *testing.T) {
+	grid := []struct {
+		sshPublicKeys []string
+		expected      []string
+	}{
+		{},
+		{
+			sshPublicKeys: []string{"team-a"},
+		},
+		{
+			sshPublicKeys: []string{"team-a", "team-c"},
+			expected:      []string{"Not found::spec.sshPublicKeys[1]"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Subnets: []kops.ClusterSubnetSpec{
+					{Name: "us-east-1a", Type: kops.SubnetTypePrivate},
+				},
+				SSH: &kops.SSHSpec{
+					PublicKeys: []kops.SSHPublicKeySpec{
+						{Name: "team-a", PublicKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFpyraYd4rUFftiEKzUO4wKFAgTkXxuJcRZwVcsuZJ8G alice@example.com"},
+						{Name: "team-b", PublicKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFpyraYd4rUFftiEKzUO4wKFAgTkXxuJcRZwVcsuZJ8G bob@example.com"},
+					},
+				},
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:          kops.InstanceGroupRoleNode,
+				MinSize:       fi.Int32(1),
+				MaxSize:       fi.Int32(1),
+				Subnets:       []string{"us-east-1a"},
+				SSHPublicKeys: g.sshPublicKeys,
+			},
+		}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g, errs, g.expected)
+	}
+}
+
 func TestValidNodeLabels(t *testing.T) {
 
 	grid := []struct {
@@ -270,6 +585,388 @@ func TestValidNodeLabels(t *testing.T) {
 	}
 }
 
+func TestValidSysctls(t *testing.T) {
+	grid := []struct {
+		sysctls  map[string]string
+		modules  []string
+		expected []string
+	}{
+		{
+			sysctls: map[string]string{
+				"fs.inotify.max_user_watches": "1048576",
+				"net.ipv4.tcp_keepalive_time": "600",
+			},
+			modules: []string{"br_netfilter", "ip_vs"},
+		},
+		{
+			sysctls:  map[string]string{"kernel.modules_disabled": "1"},
+			expected: []string{"Forbidden::spec.sysctls[kernel.modules_disabled]"},
+		},
+		{
+			sysctls:  map[string]string{"vm.swappiness": ""},
+			expected: []string{"Required value::spec.sysctls[vm.swappiness]"},
+		},
+		{
+			sysctls:  map[string]string{"vm swappiness": "10"},
+			expected: []string{"Invalid value::spec.sysctls[vm swappiness]"},
+		},
+		{
+			modules:  []string{"ip_vs", "ip_vs"},
+			expected: []string{"Duplicate value::spec.kernelModules[1]"},
+		},
+		{
+			modules:  []string{"../ip_vs"},
+			expected: []string{"Invalid value::spec.kernelModules[0]"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:          "Node",
+				Sysctls:       g.sysctls,
+				KernelModules: g.modules,
+			},
+		}
+		errs := ValidateInstanceGroup(ig, nil)
+		testErrors(t, g, errs, g.expected)
+	}
+}
+
+func TestValidSwap(t *testing.T) {
+	grid := []struct {
+		swap     *kops.SwapSpec
+		expected []string
+	}{
+		{
+			swap: &kops.SwapSpec{
+				Size:         resource.NewQuantity(4*1024*1024*1024, resource.BinarySI),
+				Swappiness:   fi.Int32(10),
+				SwapBehavior: "LimitedSwap",
+			},
+		},
+		{
+			swap:     &kops.SwapSpec{},
+			expected: []string{"Required value::spec.swap.size"},
+		},
+		{
+			swap: &kops.SwapSpec{
+				Size:       resource.NewQuantity(1024, resource.BinarySI),
+				Swappiness: fi.Int32(300),
+			},
+			expected: []string{"Invalid value::spec.swap.swappiness"},
+		},
+		{
+			swap: &kops.SwapSpec{
+				Size:         resource.NewQuantity(1024, resource.BinarySI),
+				SwapBehavior: "Everything",
+			},
+			expected: []string{"Unsupported value::spec.swap.swapBehavior"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role: "Node",
+				Swap: g.swap,
+			},
+		}
+		errs := ValidateInstanceGroup(ig, nil)
+		testErrors(t, g.swap, errs, g.expected)
+	}
+}
+
+func TestValidInstanceGroupLogging(t *testing.T) {
+	grid := []struct {
+		logging      *kops.InstanceGroupLoggingSpec
+		kubelet      *kops.KubeletConfigSpec
+		volumeMounts []kops.VolumeMountSpec
+		expected     []string
+	}{
+		{
+			logging: &kops.InstanceGroupLoggingSpec{
+				JournaldSystemMaxUse: "500M",
+				Volume: &kops.VolumeSpec{
+					Device: "/dev/xvdl",
+					Size:   20,
+				},
+			},
+		},
+		{
+			logging: &kops.InstanceGroupLoggingSpec{
+				JournaldSystemMaxUse: "500MB",
+			},
+			kubelet: &kops.KubeletConfigSpec{
+				ContainerLogMaxSize:  "lots",
+				ContainerLogMaxFiles: fi.Int32(1),
+			},
+			expected: []string{
+				"Invalid value::spec.kubelet.containerLogMaxSize",
+				"Invalid value::spec.kubelet.containerLogMaxFiles",
+				"Invalid value::spec.logging.journaldSystemMaxUse",
+			},
+		},
+		{
+			logging: &kops.InstanceGroupLoggingSpec{
+				Volume: &kops.VolumeSpec{},
+			},
+			expected: []string{
+				"Required value::spec.logging.volume.device",
+				"Invalid value::spec.logging.volume.size",
+			},
+		},
+		{
+			logging: &kops.InstanceGroupLoggingSpec{
+				Volume: &kops.VolumeSpec{
+					Device: "/dev/xvdl",
+					Size:   20,
+				},
+			},
+			volumeMounts: []kops.VolumeMountSpec{
+				{Device: "/dev/xvdd", Filesystem: "ext4", Path: "/var/log"},
+			},
+			expected: []string{"Forbidden::spec.logging.volume"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:         "Node",
+				Kubelet:      g.kubelet,
+				Logging:      g.logging,
+				VolumeMounts: g.volumeMounts,
+			},
+		}
+		errs := ValidateInstanceGroup(ig, nil)
+		testErrors(t, g.logging, errs, g.expected)
+	}
+}
+
+func TestValidStateVolumes(t *testing.T) {
+	grid := []struct {
+		stateVolumes *kops.StateVolumesSpec
+		logging      *kops.InstanceGroupLoggingSpec
+		volumeMounts []kops.VolumeMountSpec
+		expected     []string
+	}{
+		{
+			stateVolumes: &kops.StateVolumesSpec{
+				Containerd: &kops.VolumeSpec{Device: "/dev/xvdc", Size: 100},
+				Kubelet:    &kops.VolumeSpec{Device: "/dev/xvdk", Size: 50},
+			},
+		},
+		{
+			stateVolumes: &kops.StateVolumesSpec{
+				Containerd: &kops.VolumeSpec{},
+			},
+			expected: []string{
+				"Required value::spec.stateVolumes.containerd.device",
+				"Invalid value::spec.stateVolumes.containerd.size",
+			},
+		},
+		{
+			stateVolumes: &kops.StateVolumesSpec{
+				Containerd: &kops.VolumeSpec{Device: "/dev/xvdl", Size: 100},
+				Kubelet:    &kops.VolumeSpec{Device: "/dev/xvdl", Size: 50},
+			},
+			logging: &kops.InstanceGroupLoggingSpec{
+				Volume: &kops.VolumeSpec{Device: "/dev/xvdl", Size: 20},
+			},
+			expected: []string{
+				"Duplicate value::spec.stateVolumes.containerd.device",
+				"Duplicate value::spec.stateVolumes.kubelet.device",
+			},
+		},
+		{
+			stateVolumes: &kops.StateVolumesSpec{
+				Kubelet: &kops.VolumeSpec{Device: "/dev/xvdk", Size: 50},
+			},
+			volumeMounts: []kops.VolumeMountSpec{
+				{Device: "/dev/xvdd", Filesystem: "ext4", Path: "/var/lib/kubelet"},
+			},
+			expected: []string{"Forbidden::spec.stateVolumes.kubelet"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:         "Node",
+				StateVolumes: g.stateVolumes,
+				Logging:      g.logging,
+				VolumeMounts: g.volumeMounts,
+			},
+		}
+		errs := ValidateInstanceGroup(ig, nil)
+		testErrors(t, g.stateVolumes, errs, g.expected)
+	}
+}
+
+func TestValidInstanceStorage(t *testing.T) {
+	grid := []struct {
+		instanceStorage *kops.InstanceStorageSpec
+		stateVolumes    *kops.StateVolumesSpec
+		expected        []string
+	}{
+		{
+			instanceStorage: &kops.InstanceStorageSpec{
+				Path:         "/var/lib/containerd",
+				Filesystem:   "xfs",
+				MountOptions: []string{"noatime", "discard"},
+			},
+		},
+		{
+			instanceStorage: &kops.InstanceStorageSpec{
+				Filesystem:   "btrfs",
+				MountOptions: []string{"noatime;reboot"},
+			},
+			expected: []string{
+				"Required value::spec.instanceStorage.path",
+				"Unsupported value::spec.instanceStorage.filesystem",
+				"Invalid value::spec.instanceStorage.mountOptions[0]",
+			},
+		},
+		{
+			instanceStorage: &kops.InstanceStorageSpec{
+				Path: "/",
+			},
+			expected: []string{"Invalid value::spec.instanceStorage.path"},
+		},
+		{
+			instanceStorage: &kops.InstanceStorageSpec{
+				Path: "/var/lib/containerd",
+			},
+			stateVolumes: &kops.StateVolumesSpec{
+				Containerd: &kops.VolumeSpec{Device: "/dev/xvdc", Size: 100},
+			},
+			expected: []string{"Forbidden::spec.instanceStorage.path"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:            "Node",
+				InstanceStorage: g.instanceStorage,
+				StateVolumes:    g.stateVolumes,
+			},
+		}
+		errs := ValidateInstanceGroup(ig, nil)
+		testErrors(t, g.instanceStorage, errs, g.expected)
+	}
+}
+
+func TestValidSecurityModules(t *testing.T) {
+	grid := []struct {
+		image    string
+		selinux  *kops.SELinuxSpec
+		appArmor *kops.AppArmorSpec
+		expected []string
+	}{
+		{
+			image:   "amazon/amzn2-ami-hvm-2.0.20210721.2-x86_64-gp2",
+			selinux: &kops.SELinuxSpec{Mode: kops.SELinuxModeEnforcing},
+		},
+		{
+			image:   "example/custom-image",
+			selinux: &kops.SELinuxSpec{Mode: kops.SELinuxModePermissive},
+		},
+		{
+			image:    "amazon/amzn2-ami-hvm-2.0.20210721.2-x86_64-gp2",
+			selinux:  &kops.SELinuxSpec{},
+			expected: []string{"Required value::spec.selinux.mode"},
+		},
+		{
+			image:    "amazon/amzn2-ami-hvm-2.0.20210721.2-x86_64-gp2",
+			selinux:  &kops.SELinuxSpec{Mode: "Disabled"},
+			expected: []string{"Unsupported value::spec.selinux.mode"},
+		},
+		{
+			image:    "099720109477/ubuntu/images/hvm-ssd/ubuntu-focal-20.04-amd64-server-20210720",
+			selinux:  &kops.SELinuxSpec{Mode: kops.SELinuxModeEnforcing},
+			expected: []string{"Forbidden::spec.selinux"},
+		},
+		{
+			image: "099720109477/ubuntu/images/hvm-ssd/ubuntu-focal-20.04-amd64-server-20210720",
+			appArmor: &kops.AppArmorSpec{
+				Profiles: []kops.AppArmorProfile{
+					{Name: "k8s-deny-write", Content: "profile k8s-deny-write {}"},
+				},
+			},
+		},
+		{
+			image: "099720109477/ubuntu/images/hvm-ssd/ubuntu-focal-20.04-amd64-server-20210720",
+			appArmor: &kops.AppArmorSpec{
+				Profiles: []kops.AppArmorProfile{
+					{Name: "k8s-deny-write", Content: "profile k8s-deny-write {}"},
+					{Name: "k8s-deny-write", Content: "profile k8s-deny-write {}"},
+					{Name: "../k8s", Content: "profile k8s {}"},
+					{Content: "profile k8s {}"},
+					{Name: "k8s-empty"},
+				},
+			},
+			expected: []string{
+				"Duplicate value::spec.appArmor.profiles[1].name",
+				"Invalid value::spec.appArmor.profiles[2].name",
+				"Required value::spec.appArmor.profiles[3].name",
+				"Required value::spec.appArmor.profiles[4].content",
+			},
+		},
+		{
+			image: "amazon/amzn2-ami-hvm-2.0.20210721.2-x86_64-gp2",
+			appArmor: &kops.AppArmorSpec{
+				Profiles: []kops.AppArmorProfile{
+					{Name: "k8s-deny-write", Content: "profile k8s-deny-write {}"},
+				},
+			},
+			expected: []string{"Forbidden::spec.appArmor"},
+		},
+		{
+			image:   "example/custom-image",
+			selinux: &kops.SELinuxSpec{Mode: kops.SELinuxModeEnforcing},
+			appArmor: &kops.AppArmorSpec{
+				Profiles: []kops.AppArmorProfile{
+					{Name: "k8s-deny-write", Content: "profile k8s-deny-write {}"},
+				},
+			},
+			expected: []string{"Forbidden::spec.appArmor"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:     "Node",
+				Image:    g.image,
+				SELinux:  g.selinux,
+				AppArmor: g.appArmor,
+			},
+		}
+		errs := ValidateInstanceGroup(ig, nil)
+		testErrors(t, g, errs, g.expected)
+	}
+}
+
 func TestValidateIGCloudLabels(t *testing.T) {
 
 	grid := []struct {
@@ -385,6 +1082,68 @@ func TestIGUpdatePolicy(t *testing.T) {
 	}
 }
 
+func TestValidExternalLoadBalancers(t *testing.T) {
+	const tg1 = "arn:aws:elasticloadbalancing:us-test-1:000000000000:targetgroup/web/1"
+	const tg2 = "arn:aws:elasticloadbalancing:us-test-1:000000000000:targetgroup/metrics/2"
+	for _, test := range []struct {
+		label    string
+		lbs      []kops.LoadBalancer
+		expected []string
+	}{
+		{
+			label: "named ports",
+			lbs: []kops.LoadBalancer{
+				{TargetGroupARN: fi.String(tg1), Name: "http", Port: fi.Int64(30080), Protocol: fi.String("TCP")},
+				{TargetGroupARN: fi.String(tg2), Name: "metrics", Port: fi.Int64(30090), HealthCheckPort: fi.Int64(30091)},
+			},
+		},
+		{
+			label: "duplicates",
+			lbs: []kops.LoadBalancer{
+				{TargetGroupARN: fi.String(tg1), Name: "http"},
+				{TargetGroupARN: fi.String(tg1), Name: "http"},
+			},
+			expected: []string{
+				"Duplicate value::spec.externalLoadBalancers[1].name",
+				"Duplicate value::spec.externalLoadBalancers[1].targetGroupArn",
+			},
+		},
+		{
+			label: "invalid ports",
+			lbs: []kops.LoadBalancer{
+				{TargetGroupARN: fi.String(tg1), Name: "HTTP", Port: fi.Int64(0), Protocol: fi.String("SCTP"), HealthCheckPort: fi.Int64(70000)},
+			},
+			expected: []string{
+				"Invalid value::spec.externalLoadBalancers[0].name",
+				"Invalid value::spec.externalLoadBalancers[0].port",
+				"Unsupported value::spec.externalLoadBalancers[0].protocol",
+				"Invalid value::spec.externalLoadBalancers[0].healthCheckPort",
+			},
+		},
+		{
+			label: "classic load balancer",
+			lbs: []kops.LoadBalancer{
+				{LoadBalancerName: fi.String("web"), Port: fi.Int64(30080)},
+			},
+			expected: []string{"Forbidden::spec.externalLoadBalancers[0]"},
+		},
+	} {
+		ig := kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-ig",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:                  "Node",
+				ExternalLoadBalancers: test.lbs,
+			},
+		}
+		t.Run(test.label, func(t *testing.T) {
+			errs := ValidateInstanceGroup(&ig, nil)
+			testErrors(t, test.label, errs, test.expected)
+		})
+	}
+}
+
 func TestValidInstanceGroup(t *testing.T) {
 	grid := []struct {
 		IG             *kops.InstanceGroup
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/featureflag"
@@ -82,6 +83,10 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 		requiresNetworkCIDR = false
 	case kops.CloudProviderAWS:
 	case kops.CloudProviderAzure:
+	case kops.CloudProviderOCI:
+	case kops.CloudProviderMetal:
+		requiresNetworkCIDR = false
+		requiresSubnetCIDR = false
 	case kops.CloudProviderOpenstack:
 		requiresNetworkCIDR = false
 		requiresSubnetCIDR = false
@@ -93,6 +98,8 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 			string(kops.CloudProviderALI),
 			string(kops.CloudProviderAzure),
 			string(kops.CloudProviderAWS),
+			string(kops.CloudProviderOCI),
+			string(kops.CloudProviderMetal),
 			string(kops.CloudProviderOpenstack),
 		}))
 	}
@@ -306,6 +313,11 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 			k8sCloudProvider = "alicloud"
 		case kops.CloudProviderAzure:
 			k8sCloudProvider = "azure"
+		case kops.CloudProviderOCI:
+			// There is no in-tree cloud provider for OCI.
+			k8sCloudProvider = ""
+		case kops.CloudProviderMetal:
+			k8sCloudProvider = ""
 		default:
 			// We already added an error above
 			k8sCloudProvider = "ignore"
@@ -442,6 +454,13 @@ func DeepValidate(c *kops.Cluster, groups []*kops.InstanceGroup, strict bool, cl
 		return fmt.Errorf("must configure at least one Node InstanceGroup")
 	}
 
+	// Validate the instance groups as they are built, with the fields they inherit from the cluster
+	var fullGroups []*kops.InstanceGroup
+	for _, g := range groups {
+		fullGroups = append(fullGroups, c.InstanceGroupWithDefaults(g))
+	}
+	groups = fullGroups
+
 	for _, g := range groups {
 		errs := CrossValidateInstanceGroup(g, c, cloud)
 
@@ -455,6 +474,10 @@ func DeepValidate(c *kops.Cluster, groups []*kops.InstanceGroup, strict bool, cl
 		}
 	}
 
+	for _, warning := range subnetCapacityWarnings(c, groups, cloud) {
+		klog.Warningf("%s", warning)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/dns"
+)
+
+func metalValidateCluster(c *kops.Cluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// Without a cloud, the machines find each other using gossip.
+	if !dns.IsGossipHostname(c.ObjectMeta.Name) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("metadata", "name"), "metal clusters must use gossip DNS (a name ending in .k8s.local)"))
+	}
+
+	return allErrs
+}
+
+func metalValidateInstanceGroup(ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldSpec := field.NewPath("spec")
+
+	if ig.Spec.Role == kops.InstanceGroupRoleBastion {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("role"), "bastions are not supported on metal"))
+	}
+
+	if ig.Spec.Metal != nil {
+		fieldHosts := fieldSpec.Child("metal", "hosts")
+		hosts := make(map[string]bool)
+		for i, host := range ig.Spec.Metal.Hosts {
+			if host == "" {
+				allErrs = append(allErrs, field.Required(fieldHosts.Index(i), "host must not be empty"))
+			} else if hosts[host] {
+				allErrs = append(allErrs, field.Duplicate(fieldHosts.Index(i), host))
+			}
+			hosts[host] = true
+		}
+	}
+
+	return allErrs
+}
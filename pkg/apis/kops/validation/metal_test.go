@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestMetalValidateCluster(t *testing.T) {
+	grid := []struct {
+		Name           string
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Name:  "test.k8s.local",
+			Input: kops.ClusterSpec{},
+		},
+		{
+			Name:  "test.example.com",
+			Input: kops.ClusterSpec{},
+			ExpectedErrors: []string{
+				"Forbidden::metadata.name",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: g.Name,
+			},
+			Spec: g.Input,
+		}
+		errs := metalValidateCluster(cluster)
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestMetalValidateInstanceGroup(t *testing.T) {
+	grid := []struct {
+		Input          kops.InstanceGroupSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.InstanceGroupSpec{
+				Role: kops.InstanceGroupRoleNode,
+				Metal: &kops.MetalInstanceGroupSpec{
+					Hosts: []string{"192.168.1.10", "node-2"},
+				},
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				Role: kops.InstanceGroupRoleBastion,
+			},
+			ExpectedErrors: []string{"Forbidden::spec.role"},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				Role: kops.InstanceGroupRoleNode,
+				Metal: &kops.MetalInstanceGroupSpec{
+					Hosts: []string{"192.168.1.10", "", "192.168.1.10"},
+				},
+			},
+			ExpectedErrors: []string{
+				"Required value::spec.metal.hosts[1]",
+				"Duplicate value::spec.metal.hosts[2]",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: g.Input,
+		}
+		errs := metalValidateInstanceGroup(ig)
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
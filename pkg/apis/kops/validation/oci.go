@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/dns"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+func ociValidateCluster(c *kops.Cluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldSpec := field.NewPath("spec")
+
+	if c.Spec.CloudConfig == nil || c.Spec.CloudConfig.OCI == nil || c.Spec.CloudConfig.OCI.CompartmentID == "" {
+		allErrs = append(allErrs, field.Required(fieldSpec.Child("cloudConfig", "oci", "compartmentId"), "compartmentId must be set on OCI"))
+	}
+	if c.Spec.CloudConfig == nil || c.Spec.CloudConfig.OCI == nil || c.Spec.CloudConfig.OCI.TagNamespace == "" {
+		allErrs = append(allErrs, field.Required(fieldSpec.Child("cloudConfig", "oci", "tagNamespace"), "tagNamespace must be set on OCI, to limit the dynamic group of the cluster to its instances"))
+	}
+
+	// There is no DNS provider for OCI yet.
+	if !dns.IsGossipHostname(c.ObjectMeta.Name) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("metadata", "name"), "OCI clusters must use gossip DNS (a name ending in .k8s.local)"))
+	}
+
+	if c.Spec.NetworkID != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("networkID"), "shared VCNs are not supported on OCI"))
+	}
+
+	for i, subnet := range c.Spec.Subnets {
+		fieldSubnet := fieldSpec.Child("subnets").Index(i)
+		// Private subnets would require a NAT gateway, which is not supported yet.
+		if subnet.Type != kops.SubnetTypePublic && subnet.Type != kops.SubnetTypeUtility {
+			allErrs = append(allErrs, field.Forbidden(fieldSubnet.Child("type"), "only public subnets are supported on OCI"))
+		}
+		if subnet.ProviderID != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldSubnet.Child("id"), "shared subnets are not supported on OCI"))
+		}
+	}
+
+	return allErrs
+}
+
+func ociValidateInstanceGroup(ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldSpec := field.NewPath("spec")
+
+	for i, zone := range ig.Spec.Zones {
+		if _, err := oci.ZoneToRegion(zone); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldSpec.Child("zones").Index(i), zone, "OCI zones must be of the form <region>-ad-<availability-domain-number>"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestOCIValidateCluster(t *testing.T) {
+	grid := []struct {
+		Name           string
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Name: "test.k8s.local",
+			Input: kops.ClusterSpec{
+				CloudConfig: &kops.CloudConfiguration{
+					OCI: &kops.OCIConfiguration{
+						CompartmentID: "ocid1.compartment.oc1..test",
+						TagNamespace:  "kops",
+					},
+				},
+				Subnets: []kops.ClusterSubnetSpec{
+					{
+						Name: "us-ashburn-1",
+						Type: kops.SubnetTypePublic,
+					},
+				},
+			},
+		},
+		{
+			Name:  "test.k8s.local",
+			Input: kops.ClusterSpec{},
+			ExpectedErrors: []string{
+				"Required value::spec.cloudConfig.oci.compartmentId",
+				"Required value::spec.cloudConfig.oci.tagNamespace",
+			},
+		},
+		{
+			Name: "test.example.com",
+			Input: kops.ClusterSpec{
+				CloudConfig: &kops.CloudConfiguration{
+					OCI: &kops.OCIConfiguration{
+						CompartmentID: "ocid1.compartment.oc1..test",
+						TagNamespace:  "kops",
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::metadata.name",
+			},
+		},
+		{
+			Name: "test.k8s.local",
+			Input: kops.ClusterSpec{
+				CloudConfig: &kops.CloudConfiguration{
+					OCI: &kops.OCIConfiguration{
+						CompartmentID: "ocid1.compartment.oc1..test",
+						TagNamespace:  "kops",
+					},
+				},
+				NetworkID: "ocid1.vcn.oc1.iad.test",
+				Subnets: []kops.ClusterSubnetSpec{
+					{
+						Name:       "us-ashburn-1",
+						Type:       kops.SubnetTypePrivate,
+						ProviderID: "ocid1.subnet.oc1.iad.test",
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.networkID",
+				"Forbidden::spec.subnets[0].type",
+				"Forbidden::spec.subnets[0].id",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: g.Name,
+			},
+			Spec: g.Input,
+		}
+		errs := ociValidateCluster(cluster)
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func TestOCIValidateInstanceGroup(t *testing.T) {
+	grid := []struct {
+		Input          kops.InstanceGroupSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.InstanceGroupSpec{
+				Zones: []string{"us-ashburn-1-ad-1", "us-ashburn-1-ad-2"},
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				Zones: []string{"us-ashburn-1"},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.zones[0]"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: g.Input,
+		}
+		errs := ociValidateInstanceGroup(ig)
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package validation
 
 import (
+	"strconv"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
 func openstackValidateCluster(c *kops.Cluster) (errList field.ErrorList) {
@@ -36,3 +39,30 @@ func openstackValidateCluster(c *kops.Cluster) (errList field.ErrorList) {
 	}
 	return errList
 }
+
+func openstackValidateInstanceGroup(ig *kops.InstanceGroup) (errList field.ErrorList) {
+	fldPath := field.NewPath("metadata", "annotations")
+	annotations := ig.ObjectMeta.Annotations
+
+	if v, ok := annotations[openstack.OS_ANNOTATION+openstack.SERVER_GROUP_AFFINITY]; ok {
+		errList = append(errList, IsValidValue(fldPath.Key(openstack.OS_ANNOTATION+openstack.SERVER_GROUP_AFFINITY), &v, openstack.ServerGroupAffinityPolicies)...)
+	}
+
+	for _, key := range []string{openstack.BOOT_VOLUME_SIZE, openstack.FLAVOR_MIN_VCPUS, openstack.FLAVOR_MIN_RAM} {
+		v, ok := annotations[openstack.OS_ANNOTATION+key]
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(v); err != nil || n <= 0 {
+			errList = append(errList, field.Invalid(fldPath.Key(openstack.OS_ANNOTATION+key), v, "must be a positive integer"))
+		}
+	}
+
+	if _, ok := annotations[openstack.OS_ANNOTATION+openstack.BOOT_VOLUME_TYPE]; ok {
+		if _, ok := annotations[openstack.OS_ANNOTATION+openstack.BOOT_FROM_VOLUME]; !ok {
+			errList = append(errList, field.Forbidden(fldPath.Key(openstack.OS_ANNOTATION+openstack.BOOT_VOLUME_TYPE), "boot volume type requires booting from volume"))
+		}
+	}
+
+	return errList
+}
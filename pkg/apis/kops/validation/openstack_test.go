@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/kops/upup/pkg/fi"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 )
 
@@ -88,3 +89,54 @@ func Test_ValidateTopology(t *testing.T) {
 		testErrors(t, g.Input, errs, g.ExpectedErrors)
 	}
 }
+
+func TestOpenstackValidateInstanceGroup(t *testing.T) {
+	grid := []struct {
+		Annotations    map[string]string
+		ExpectedErrors []string
+	}{
+		{
+			Annotations: map[string]string{
+				"openstack.kops.io/serverGroupAffinity": "soft-anti-affinity",
+				"openstack.kops.io/osVolumeBoot":        "true",
+				"openstack.kops.io/osVolumeSize":        "20",
+				"openstack.kops.io/osVolumeType":        "ssd",
+				"openstack.kops.io/flavorMinVCPUs":      "4",
+				"openstack.kops.io/flavorMinRAM":        "8192",
+			},
+		},
+		{
+			Annotations: map[string]string{
+				"openstack.kops.io/serverGroupAffinity": "spread",
+			},
+			ExpectedErrors: []string{"Unsupported value::metadata.annotations[openstack.kops.io/serverGroupAffinity]"},
+		},
+		{
+			Annotations: map[string]string{
+				"openstack.kops.io/flavorMinVCPUs": "0",
+				"openstack.kops.io/flavorMinRAM":   "8G",
+			},
+			ExpectedErrors: []string{
+				"Invalid value::metadata.annotations[openstack.kops.io/flavorMinVCPUs]",
+				"Invalid value::metadata.annotations[openstack.kops.io/flavorMinRAM]",
+			},
+		},
+		{
+			Annotations: map[string]string{
+				"openstack.kops.io/osVolumeType": "ssd",
+			},
+			ExpectedErrors: []string{"Forbidden::metadata.annotations[openstack.kops.io/osVolumeType]"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "nodes",
+				Annotations: g.Annotations,
+			},
+		}
+		errs := openstackValidateInstanceGroup(ig)
+		testErrors(t, g.Annotations, errs, g.ExpectedErrors)
+	}
+}
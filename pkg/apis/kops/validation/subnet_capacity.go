@@ -0,0 +1,253 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model/defaults"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+const (
+	// awsReservedSubnetAddresses is the number of addresses AWS reserves in every subnet.
+	awsReservedSubnetAddresses = 5
+
+	// subnetCapacityWarningRatio is the fraction of the addresses of a subnet above which its worst-case usage is reported.
+	subnetCapacityWarningRatio = 0.8
+
+	// vpcCNIPrefixSize is the number of addresses of the /28 prefixes the AWS VPC CNI assigns in prefix delegation mode.
+	vpcCNIPrefixSize = 16
+
+	// classicLoadBalancerAddresses is the number of free addresses a Classic Load Balancer needs in each of its subnets.
+	classicLoadBalancerAddresses = 8
+)
+
+// SubnetUsage is the worst-case number of IPv4 addresses used in a subnet of the cluster.
+type SubnetUsage struct {
+	// Subnet is the subnet of the cluster.
+	Subnet *kops.ClusterSubnetSpec
+	// Capacity is the number of addresses of the subnet that can be assigned.
+	Capacity int
+	// Instances is the number of addresses used by the instances, and by their pods when they are assigned VPC addresses.
+	Instances int
+	// Overhead is the number of addresses used by the API load balancer and the NAT gateways.
+	Overhead int
+}
+
+// Total returns the worst-case number of addresses used in the subnet.
+func (u *SubnetUsage) Total() int {
+	return u.Instances + u.Overhead
+}
+
+// EstimateSubnetUsage estimates the worst-case usage of the IPv4 subnets of an AWS cluster: every instance group
+// at its maximum size plus its rolling update surge, spread evenly across its subnets, with every node running
+// its maximum number of pods. The cloud is used to look up the network limits of the instance types; when it is nil,
+// the maximum number of pods of the kubelet is used instead.
+func EstimateSubnetUsage(c *kops.Cluster, groups []*kops.InstanceGroup, cloud fi.Cloud) []*SubnetUsage {
+	var usages []*SubnetUsage
+	usageByName := make(map[string]*SubnetUsage)
+	for i := range c.Spec.Subnets {
+		subnet := &c.Spec.Subnets[i]
+		_, ipNet, err := net.ParseCIDR(subnet.CIDR)
+		if err != nil || ipNet.IP.To4() == nil {
+			continue
+		}
+		ones, bits := ipNet.Mask.Size()
+		usage := &SubnetUsage{
+			Subnet:   subnet,
+			Capacity: (1 << uint(bits-ones)) - awsReservedSubnetAddresses,
+		}
+		usages = append(usages, usage)
+		usageByName[subnet.Name] = usage
+	}
+
+	for _, g := range groups {
+		if len(g.Spec.Subnets) == 0 {
+			continue
+		}
+		perSubnet := int(math.Ceil(float64(instanceGroupMaxInstances(c, g)) / float64(len(g.Spec.Subnets))))
+		addresses := perSubnet * instanceAddresses(c, g, cloud)
+		for _, name := range g.Spec.Subnets {
+			if usage := usageByName[name]; usage != nil {
+				usage.Instances += addresses
+			}
+		}
+	}
+
+	if c.Spec.API != nil && c.Spec.API.LoadBalancer != nil {
+		lb := c.Spec.API.LoadBalancer
+		addresses := classicLoadBalancerAddresses
+		if lb.Class == kops.LoadBalancerClassNetwork {
+			addresses = 1
+		}
+		// The load balancer may be placed in any of the candidate subnets of each zone.
+		for _, usage := range usages {
+			if apiLoadBalancerCandidateSubnet(lb, usage.Subnet) {
+				usage.Overhead += addresses
+			}
+		}
+	}
+
+	hasPrivateSubnets := false
+	for _, subnet := range c.Spec.Subnets {
+		if subnet.Type == kops.SubnetTypePrivate && subnet.Egress == "" {
+			hasPrivateSubnets = true
+		}
+	}
+	if hasPrivateSubnets {
+		for _, usage := range usages {
+			if usage.Subnet.Type == kops.SubnetTypeUtility && usage.Subnet.ProviderID == "" {
+				usage.Overhead++
+			}
+		}
+	}
+
+	return usages
+}
+
+// subnetCapacityWarnings returns a warning for each subnet whose worst-case usage exceeds most of its capacity.
+// The estimate is an upper bound that clusters rarely reach, so it does not fail validation.
+func subnetCapacityWarnings(c *kops.Cluster, groups []*kops.InstanceGroup, cloud fi.Cloud) []string {
+	var warnings []string
+	if kops.CloudProviderID(c.Spec.CloudProvider) != kops.CloudProviderAWS {
+		return warnings
+	}
+
+	for _, usage := range EstimateSubnetUsage(c, groups, cloud) {
+		total := usage.Total()
+		if total <= int(float64(usage.Capacity)*subnetCapacityWarningRatio) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("subnet %q (%s) may need up to %d addresses (%d for instances and pods, %d for load balancers and NAT gateways), but has %d",
+			usage.Subnet.Name, usage.Subnet.CIDR, total, usage.Instances, usage.Overhead, usage.Capacity))
+	}
+
+	return warnings
+}
+
+// instanceGroupMaxInstances returns the maximum number of instances of the instance group, including the instances surged during rolling updates.
+func instanceGroupMaxInstances(c *kops.Cluster, g *kops.InstanceGroup) int {
+	maxSize := 2
+	if g.Spec.Role == kops.InstanceGroupRoleMaster || g.Spec.Role == kops.InstanceGroupRoleBastion {
+		maxSize = 1
+	}
+	if g.Spec.MaxSize != nil {
+		maxSize = int(*g.Spec.MaxSize)
+	} else if g.Spec.MinSize != nil && int(*g.Spec.MinSize) > maxSize {
+		maxSize = int(*g.Spec.MinSize)
+	}
+
+	if g.Spec.Role == kops.InstanceGroupRoleMaster {
+		return maxSize
+	}
+	var maxSurge *intstr.IntOrString
+	if g.Spec.RollingUpdate != nil {
+		maxSurge = g.Spec.RollingUpdate.MaxSurge
+	}
+	if maxSurge == nil && c.Spec.RollingUpdate != nil {
+		maxSurge = c.Spec.RollingUpdate.MaxSurge
+	}
+	surge := 1
+	if maxSurge != nil {
+		if value, err := intstr.GetScaledValueFromIntOrPercent(maxSurge, maxSize, true); err == nil {
+			surge = value
+		}
+	}
+	return maxSize + surge
+}
+
+// instanceAddresses returns the worst-case number of addresses used by an instance of the instance group and its pods.
+func instanceAddresses(c *kops.Cluster, g *kops.InstanceGroup, cloud fi.Cloud) int {
+	networking := c.Spec.Networking
+	vpcCNI := networking != nil && networking.AmazonVPC != nil
+	ciliumENI := networking != nil && networking.Cilium != nil && networking.Cilium.Ipam == kops.CiliumIpamEni
+	if !vpcCNI && !ciliumENI {
+		return 1
+	}
+
+	maxPods := defaults.DefaultMaxPods
+	if c.Spec.Kubelet != nil && c.Spec.Kubelet.MaxPods != nil {
+		maxPods = int(*c.Spec.Kubelet.MaxPods)
+	}
+	if g.Spec.Role == kops.InstanceGroupRoleMaster && c.Spec.MasterKubelet != nil && c.Spec.MasterKubelet.MaxPods != nil {
+		maxPods = int(*c.Spec.MasterKubelet.MaxPods)
+	}
+	if g.Spec.Kubelet != nil && g.Spec.Kubelet.MaxPods != nil {
+		maxPods = int(*g.Spec.Kubelet.MaxPods)
+	}
+
+	if vpcCNI && vpcCNIPrefixDelegation(networking.AmazonVPC) {
+		// The pods are assigned addresses from /28 prefixes, and a warm prefix is kept available.
+		prefixes := int(math.Ceil(float64(maxPods)/vpcCNIPrefixSize)) + 1
+		return 1 + prefixes*vpcCNIPrefixSize
+	}
+
+	// Every address of the network interfaces of the instance may be assigned, to pods or as warm addresses.
+	if awsCloud, ok := cloud.(awsup.AWSCloud); ok {
+		maxAddresses := 0
+		for _, machineType := range strings.Split(g.Spec.MachineType, ",") {
+			info, err := awsup.GetMachineTypeInfo(awsCloud, strings.TrimSpace(machineType))
+			if err != nil || info.InstanceENIs == 0 || info.InstanceIPsPerENI == 0 {
+				maxAddresses = 0
+				break
+			}
+			if addresses := info.InstanceENIs * info.InstanceIPsPerENI; addresses > maxAddresses {
+				maxAddresses = addresses
+			}
+		}
+		if maxAddresses != 0 {
+			return maxAddresses
+		}
+	}
+	return 1 + maxPods
+}
+
+// vpcCNIPrefixDelegation returns true if the AWS VPC CNI assigns /28 prefixes to the network interfaces.
+func vpcCNIPrefixDelegation(spec *kops.AmazonVPCNetworkingSpec) bool {
+	for _, env := range spec.Env {
+		if env.Name == "ENABLE_PREFIX_DELEGATION" {
+			return env.Value == "true"
+		}
+	}
+	return false
+}
+
+// apiLoadBalancerCandidateSubnet returns true if the API load balancer may be placed in the subnet.
+func apiLoadBalancerCandidateSubnet(lb *kops.LoadBalancerAccessSpec, subnet *kops.ClusterSubnetSpec) bool {
+	if len(lb.Subnets) != 0 {
+		for _, lbSubnet := range lb.Subnets {
+			if lbSubnet.Name == subnet.Name {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch lb.Type {
+	case kops.LoadBalancerTypeInternal:
+		return subnet.Type == kops.SubnetTypePrivate || subnet.Type == kops.SubnetTypePublic
+	default:
+		return subnet.Type == kops.SubnetTypePublic || subnet.Type == kops.SubnetTypeUtility
+	}
+}
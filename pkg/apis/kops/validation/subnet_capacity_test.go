@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func Test_SubnetCapacityWarnings(t *testing.T) {
+	grid := []struct {
+		Description       string
+		Networking        *kops.NetworkingSpec
+		Subnets           []kops.ClusterSubnetSpec
+		LoadBalancer      *kops.LoadBalancerAccessSpec
+		MaxSurge          *intstr.IntOrString
+		MaxPods           *int32
+		MaxSize           int32
+		ExpectedInstances int
+		ExpectedOverhead  int
+		ExpectedWarnings  int
+	}{
+		{
+			Description:       "overlay networking uses one address per node",
+			Networking:        &kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}},
+			Subnets:           []kops.ClusterSubnetSpec{{Name: "a", CIDR: "10.0.0.0/28", Type: kops.SubnetTypePublic}},
+			MaxSize:           3,
+			ExpectedInstances: 1 + 4,
+		},
+		{
+			Description:       "amazonvpc uses an address per pod",
+			Networking:        &kops.NetworkingSpec{AmazonVPC: &kops.AmazonVPCNetworkingSpec{}},
+			Subnets:           []kops.ClusterSubnetSpec{{Name: "a", CIDR: "10.0.0.0/24", Type: kops.SubnetTypePublic}},
+			MaxSize:           2,
+			ExpectedInstances: 111 + 3*111,
+			ExpectedWarnings:  1,
+		},
+		{
+			Description:       "maxPods and maxSurge limit the usage",
+			Networking:        &kops.NetworkingSpec{AmazonVPC: &kops.AmazonVPCNetworkingSpec{}},
+			Subnets:           []kops.ClusterSubnetSpec{{Name: "a", CIDR: "10.0.0.0/24", Type: kops.SubnetTypePublic}},
+			MaxSurge:          intStr(intstr.FromInt(0)),
+			MaxPods:           fi.Int32(20),
+			MaxSize:           2,
+			ExpectedInstances: 21 + 2*21,
+		},
+		{
+			Description: "prefix delegation allocates /28 prefixes",
+			Networking: &kops.NetworkingSpec{AmazonVPC: &kops.AmazonVPCNetworkingSpec{
+				Env: []kops.EnvVar{{Name: "ENABLE_PREFIX_DELEGATION", Value: "true"}},
+			}},
+			Subnets:           []kops.ClusterSubnetSpec{{Name: "a", CIDR: "10.0.0.0/20", Type: kops.SubnetTypePublic}},
+			MaxSize:           2,
+			ExpectedInstances: 129 + 3*129,
+		},
+		{
+			Description: "shared subnets",
+			Networking:  &kops.NetworkingSpec{Cilium: &kops.CiliumNetworkingSpec{Ipam: kops.CiliumIpamEni}},
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "a", CIDR: "10.0.0.0/24", Type: kops.SubnetTypePublic, ProviderID: "subnet-a"},
+			},
+			MaxSize:           2,
+			ExpectedInstances: 111 + 3*111,
+			ExpectedWarnings:  1,
+		},
+		{
+			Description: "load balancers and NAT gateways",
+			Networking:  &kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}},
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "a", CIDR: "10.0.0.0/28", Type: kops.SubnetTypePrivate},
+				{Name: "utility-a", CIDR: "10.0.1.0/28", Type: kops.SubnetTypeUtility},
+			},
+			LoadBalancer:      &kops.LoadBalancerAccessSpec{Type: kops.LoadBalancerTypePublic},
+			MaxSize:           2,
+			ExpectedInstances: 1 + 3,
+			ExpectedOverhead:  8 + 1,
+			ExpectedWarnings:  1,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			cluster := &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider: string(kops.CloudProviderAWS),
+					Networking:    g.Networking,
+					Subnets:       g.Subnets,
+					API:           &kops.AccessSpec{LoadBalancer: g.LoadBalancer},
+					Kubelet:       &kops.KubeletConfigSpec{MaxPods: g.MaxPods},
+				},
+			}
+			if g.MaxSurge != nil {
+				cluster.Spec.RollingUpdate = &kops.RollingUpdate{MaxSurge: g.MaxSurge}
+			}
+			groups := []*kops.InstanceGroup{
+				{
+					Spec: kops.InstanceGroupSpec{
+						Role:    kops.InstanceGroupRoleMaster,
+						Subnets: []string{g.Subnets[0].Name},
+					},
+				},
+				{
+					Spec: kops.InstanceGroupSpec{
+						Role:    kops.InstanceGroupRoleNode,
+						MaxSize: fi.Int32(g.MaxSize),
+						Subnets: []string{g.Subnets[0].Name},
+					},
+				},
+			}
+
+			usages := EstimateSubnetUsage(cluster, groups, nil)
+			if usages[0].Instances != g.ExpectedInstances {
+				t.Errorf("expected %d addresses for instances, got %d", g.ExpectedInstances, usages[0].Instances)
+			}
+			overhead := 0
+			for _, usage := range usages {
+				overhead += usage.Overhead
+			}
+			if overhead != g.ExpectedOverhead {
+				t.Errorf("expected %d addresses of overhead, got %d", g.ExpectedOverhead, overhead)
+			}
+
+			warnings := subnetCapacityWarnings(cluster, groups, nil)
+			if len(warnings) != g.ExpectedWarnings {
+				t.Errorf("expected %d warnings, got %v", g.ExpectedWarnings, warnings)
+			}
+		})
+	}
+}
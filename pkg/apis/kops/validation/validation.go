@@ -17,28 +17,41 @@ limitations under the License.
 package validation
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/blang/semver/v4"
+	"golang.org/x/crypto/ssh"
 	"golang.org/x/net/ipv4"
 	"golang.org/x/net/ipv6"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cosign"
+	"k8s.io/kops/pkg/maintenancewindow"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
 )
 
 func newValidateCluster(cluster *kops.Cluster) field.ErrorList {
@@ -70,16 +83,34 @@ func newValidateCluster(cluster *kops.Cluster) field.ErrorList {
 		allErrs = append(allErrs, gceValidateCluster(cluster)...)
 	case kops.CloudProviderOpenstack:
 		allErrs = append(allErrs, openstackValidateCluster(cluster)...)
+	case kops.CloudProviderOCI:
+		allErrs = append(allErrs, ociValidateCluster(cluster)...)
+	case kops.CloudProviderMetal:
+		allErrs = append(allErrs, metalValidateCluster(cluster)...)
 	}
 
 	return allErrs
 }
 
+// validateChannel checks the channel is a name, a pinned name (<channel>@<ref>) or a URL
+func validateChannel(channel string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if channel == "" {
+		return allErrs
+	}
+	if _, err := kops.ResolveChannel(channel); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, channel, err.Error()))
+	}
+	return allErrs
+}
+
 func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, validateSubnets(spec, fieldPath.Child("subnets"))...)
 
+	allErrs = append(allErrs, validateChannel(spec.Channel, fieldPath.Child("channel"))...)
+
 	// SSHAccess
 	for i, cidr := range spec.SSHAccess {
 		allErrs = append(allErrs, validateCIDR(cidr, fieldPath.Child("sshAccess").Index(i))...)
@@ -112,6 +143,8 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, validateHookSpec(&spec.Hooks[i], fieldPath.Child("hooks").Index(i))...)
 	}
 
+	allErrs = append(allErrs, validateNodePlugins(spec.NodePlugins, fieldPath.Child("nodePlugins"))...)
+
 	if spec.FileAssets != nil {
 		for i, x := range spec.FileAssets {
 			allErrs = append(allErrs, validateFileAssetSpec(&x, fieldPath.Child("fileAssets").Index(i))...)
@@ -134,6 +167,41 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, validateKubelet(spec.MasterKubelet, c, fieldPath.Child("masterKubelet"))...)
 	}
 
+	if spec.KubeControllerManager != nil {
+		allErrs = append(allErrs, validateKubeControllerManager(spec.KubeControllerManager, c, fieldPath.Child("kubeControllerManager"))...)
+	}
+
+	if spec.KubeScheduler != nil {
+		allErrs = append(allErrs, validateKubeScheduler(spec.KubeScheduler, c, fieldPath.Child("kubeScheduler"))...)
+	}
+
+	if spec.ExternalCloudControllerManager != nil {
+		allErrs = append(allErrs, validateCloudControllerManager(spec.ExternalCloudControllerManager, c, fieldPath.Child("cloudControllerManager"))...)
+	}
+
+	allErrs = append(allErrs, validateFeatureGates(spec.FeatureGates, c, fieldPath.Child("featureGates"))...)
+	if spec.KubeAPIServer != nil {
+		allErrs = append(allErrs, validateComponentFeatureGates(spec.KubeAPIServer.FeatureGates, fieldPath.Child("kubeAPIServer", "featureGates"))...)
+	}
+	if spec.KubeControllerManager != nil {
+		allErrs = append(allErrs, validateComponentFeatureGates(spec.KubeControllerManager.FeatureGates, fieldPath.Child("kubeControllerManager", "featureGates"))...)
+	}
+	if spec.KubeScheduler != nil {
+		allErrs = append(allErrs, validateComponentFeatureGates(spec.KubeScheduler.FeatureGates, fieldPath.Child("kubeScheduler", "featureGates"))...)
+	}
+	if spec.ExternalCloudControllerManager != nil {
+		allErrs = append(allErrs, validateComponentFeatureGates(spec.ExternalCloudControllerManager.FeatureGates, fieldPath.Child("cloudControllerManager", "featureGates"))...)
+	}
+	if spec.KubeProxy != nil {
+		allErrs = append(allErrs, validateComponentFeatureGates(spec.KubeProxy.FeatureGates, fieldPath.Child("kubeProxy", "featureGates"))...)
+	}
+	if spec.Kubelet != nil {
+		allErrs = append(allErrs, validateComponentFeatureGates(spec.Kubelet.FeatureGates, fieldPath.Child("kubelet", "featureGates"))...)
+	}
+	if spec.MasterKubelet != nil {
+		allErrs = append(allErrs, validateComponentFeatureGates(spec.MasterKubelet.FeatureGates, fieldPath.Child("masterKubelet", "featureGates"))...)
+	}
+
 	if spec.Networking != nil {
 		allErrs = append(allErrs, validateNetworking(c, spec.Networking, fieldPath.Child("networking"))...)
 		if spec.Networking.Calico != nil {
@@ -149,6 +217,14 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, validateClusterAutoscaler(c, spec.ClusterAutoscaler, fieldPath.Child("clusterAutoscaler"))...)
 	}
 
+	if spec.Descheduler != nil {
+		allErrs = append(allErrs, validateDescheduler(spec.Descheduler, fieldPath.Child("descheduler"))...)
+	}
+
+	if spec.VerticalPodAutoscaler != nil {
+		allErrs = append(allErrs, validateVerticalPodAutoscaler(c, spec.VerticalPodAutoscaler, fieldPath.Child("verticalPodAutoscaler"))...)
+	}
+
 	if spec.NodeTerminationHandler != nil {
 		allErrs = append(allErrs, validateNodeTerminationHandler(c, spec.NodeTerminationHandler, fieldPath.Child("nodeTerminationHandler"))...)
 	}
@@ -167,6 +243,78 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 
 	}
 
+	if spec.ContinuousValidation != nil {
+		allErrs = append(allErrs, validateContinuousValidation(spec.ContinuousValidation, fieldPath.Child("continuousValidation"))...)
+	}
+
+	if spec.NTP != nil {
+		allErrs = append(allErrs, validateNTP(spec.NTP, fieldPath.Child("ntp"))...)
+	}
+
+	if spec.OSPatching != nil {
+		allErrs = append(allErrs, validateOSPatching(spec, fieldPath.Child("osPatching"))...)
+	}
+
+	if spec.NodeLabelReconciliation != nil {
+		allErrs = append(allErrs, validateNodeLabelReconciliation(spec.NodeLabelReconciliation, fieldPath.Child("nodeLabelReconciliation"))...)
+	}
+
+	if spec.Authorization != nil && spec.Authorization.RBAC != nil {
+		allErrs = append(allErrs, validateRBACBindings(spec.Authorization.RBAC.Bindings, fieldPath.Child("authorization", "rbac", "bindings"))...)
+	}
+
+	if spec.Authentication != nil && spec.Authentication.OIDC != nil {
+		allErrs = append(allErrs, validateOIDCAuthentication(spec.Authentication.OIDC, fieldPath.Child("authentication", "oidc"))...)
+	}
+
+	if spec.MaintenanceWindow != nil {
+		allErrs = append(allErrs, validateMaintenanceWindow(spec.MaintenanceWindow, fieldPath.Child("maintenanceWindow"))...)
+	}
+
+	if spec.InstanceGroupDefaults != nil {
+		allErrs = append(allErrs, validateInstanceGroupDefaults(spec.InstanceGroupDefaults, fieldPath.Child("instanceGroupDefaults"))...)
+	}
+
+	if spec.Hibernation != nil {
+		allErrs = append(allErrs, validateHibernation(spec, fieldPath.Child("hibernation"))...)
+	}
+
+	if spec.SessionManager != nil {
+		allErrs = append(allErrs, validateSessionManager(spec.SessionManager, kops.CloudProviderID(spec.CloudProvider), fieldPath.Child("sessionManager"))...)
+	}
+
+	if spec.SSH != nil {
+		allErrs = append(allErrs, validateSSH(spec.SSH, kops.CloudProviderID(spec.CloudProvider), fieldPath.Child("ssh"))...)
+	}
+
+	if spec.Events != nil {
+		allErrs = append(allErrs, validateEvents(spec.Events, kops.CloudProviderID(spec.CloudProvider), fieldPath.Child("events"))...)
+	}
+
+	if spec.PodSecurityAdmission != nil {
+		allErrs = append(allErrs, validatePodSecurityAdmission(c, spec.PodSecurityAdmission, fieldPath.Child("podSecurityAdmission"))...)
+	}
+
+	if spec.SecurityProfile != "" {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("securityProfile"), &spec.SecurityProfile, []string{kops.SecurityProfileCIS})...)
+	}
+
+	for i := range spec.CloudLabelPolicies {
+		allErrs = append(allErrs, validateCloudLabelPolicy(spec, &spec.CloudLabelPolicies[i], fieldPath.Child("cloudLabelPolicies").Index(i))...)
+	}
+
+	if spec.NetworkCIDRAllocation != nil {
+		allErrs = append(allErrs, validateNetworkCIDRAllocation(spec, fieldPath)...)
+	}
+
+	if len(spec.EgressGateways) != 0 {
+		allErrs = append(allErrs, validateEgressGateways(c, fieldPath.Child("egressGateways"))...)
+	}
+
+	if spec.NodeProblemDetector != nil && spec.NodeProblemDetector.Remediation != nil {
+		allErrs = append(allErrs, validateNodeRemediation(spec.NodeProblemDetector, fieldPath.Child("nodeProblemDetector", "remediation"))...)
+	}
+
 	// IAM additional policies
 	if spec.AdditionalPolicies != nil {
 		for k, v := range *spec.AdditionalPolicies {
@@ -201,7 +349,7 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 	}
 
 	if spec.Containerd != nil {
-		allErrs = append(allErrs, validateContainerdConfig(spec.Containerd, fieldPath.Child("containerd"))...)
+		allErrs = append(allErrs, validateContainerdConfig(spec, spec.Containerd, fieldPath.Child("containerd"))...)
 	}
 
 	if spec.Docker != nil {
@@ -212,6 +360,9 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		if spec.Assets.ContainerProxy != nil && spec.Assets.ContainerRegistry != nil {
 			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("assets", "containerProxy"), "containerProxy cannot be used in conjunction with containerRegistry"))
 		}
+		if spec.Assets.ImageVerification != nil {
+			allErrs = append(allErrs, validateImageVerification(spec.Assets.ImageVerification, fieldPath.Child("assets", "imageVerification"))...)
+		}
 	}
 
 	if spec.IAM == nil || spec.IAM.Legacy {
@@ -237,12 +388,22 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, validateCloudConfiguration(spec.CloudConfig, fieldPath.Child("cloudConfig"))...)
 	}
 
-	if spec.WarmPool != nil {
-		if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
-			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "warmPool"), "warm pool only supported on AWS"))
-		} else {
-			allErrs = append(allErrs, validateWarmPool(spec.WarmPool, fieldPath.Child("warmPool"))...)
-		}
+	if spec.CloudConfig != nil && spec.CloudConfig.EBSDefaultEncryption != nil {
+		allErrs = append(allErrs, validateEBSDefaultEncryption(spec, fieldPath.Child("cloudConfig", "ebsDefaultEncryption"))...)
+	}
+
+	if spec.CloudConfig != nil && spec.CloudConfig.AWSEBSCSIDriver != nil {
+		allErrs = append(allErrs, validateAWSEBSCSIDriver(spec.CloudConfig.AWSEBSCSIDriver, fieldPath.Child("cloudConfig", "awsEBSCSIDriver"))...)
+	}
+
+	if kops.CloudProviderID(spec.CloudProvider) == kops.CloudProviderAWS && hasAWSEBSCSIDriver(*spec) {
+		allErrs = append(allErrs, validateAWSEBSCSIDriverClusterTags(spec, fieldPath)...)
+	}
+
+	allErrs = append(allErrs, validateCloudCapabilities(spec, fieldPath)...)
+
+	if spec.WarmPool != nil && kops.CloudProviderID(spec.CloudProvider) == kops.CloudProviderAWS {
+		allErrs = append(allErrs, validateWarmPool(spec.WarmPool, fieldPath.Child("warmPool"))...)
 	}
 
 	if spec.IAM != nil {
@@ -368,6 +529,16 @@ func validateTopology(topology *kops.TopologySpec, fieldPath *field.Path) field.
 		if bastion.IdleTimeoutSeconds != nil && *bastion.IdleTimeoutSeconds > 3600 {
 			allErrs = append(allErrs, field.Invalid(fieldPath.Child("bastion", "idleTimeoutSeconds"), *bastion.IdleTimeoutSeconds, "bastion idleTimeoutSeconds cannot be greater than one hour"))
 		}
+		if bastion.LoadBalancer != nil {
+			lbPath := fieldPath.Child("bastion", "loadBalancer")
+			if bastion.LoadBalancer.Class != "" {
+				class := string(bastion.LoadBalancer.Class)
+				allErrs = append(allErrs, IsValidValue(lbPath.Child("class"), &class, kops.SupportedLoadBalancerClasses)...)
+			}
+			if bastion.LoadBalancer.Class == kops.LoadBalancerClassNetwork && len(bastion.LoadBalancer.AdditionalSecurityGroups) > 0 {
+				allErrs = append(allErrs, field.Forbidden(lbPath.Child("additionalSecurityGroups"), "a Network load balancer cannot have security groups"))
+			}
+		}
 	}
 
 	if topology.DNS != nil {
@@ -415,14 +586,6 @@ func validateSubnets(cluster *kops.ClusterSpec, fieldPath *field.Path) field.Err
 		}
 	}
 
-	if kops.CloudProviderID(cluster.CloudProvider) != kops.CloudProviderAWS {
-		for i := range subnets {
-			if subnets[i].IPv6CIDR != "" {
-				allErrs = append(allErrs, field.Forbidden(fieldPath.Index(i).Child("ipv6CIDR"), "ipv6CIDR can only be specified for AWS"))
-			}
-		}
-	}
-
 	return allErrs
 }
 
@@ -515,6 +678,65 @@ func validateExecContainerAction(v *kops.ExecContainerAction, fldPath *field.Pat
 	return allErrs
 }
 
+var sha256HashRegexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+func validateNodePlugins(plugins []kops.NodePluginSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i := range plugins {
+		v := &plugins[i]
+		fieldPath := fldPath.Index(i)
+
+		if v.Name == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("name"), "name must be specified"))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Label(v.Name) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), v.Name, msg))
+			}
+			if names.Has(v.Name) {
+				allErrs = append(allErrs, field.Duplicate(fieldPath.Child("name"), v.Name))
+			}
+			names.Insert(v.Name)
+		}
+
+		// a disabled plugin only overrides the cluster plugin with the same name
+		if v.Disabled {
+			continue
+		}
+
+		stage := string(v.Stage)
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("stage"), &stage, []string{
+			string(kops.NodePluginStagePreInstall),
+			string(kops.NodePluginStagePostKubelet),
+			string(kops.NodePluginStagePostJoin),
+		})...)
+
+		if len(v.Command) == 0 {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("command"), "command must be specified"))
+		}
+
+		for j, artifact := range v.Artifacts {
+			artifactPath := fieldPath.Child("artifacts").Index(j)
+			if u, err := url.Parse(artifact.Source); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(artifactPath.Child("source"), artifact.Source, "must be an http or https URL"))
+			}
+			if !sha256HashRegexp.MatchString(artifact.Hash) {
+				allErrs = append(allErrs, field.Invalid(artifactPath.Child("hash"), artifact.Hash, "must be a sha256 hash of 64 hex characters"))
+			}
+		}
+
+		if v.Timeout != nil && v.Timeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("timeout"), v.Timeout.Duration.String(), "must be greater than zero"))
+		}
+		if v.Retries != nil && *v.Retries < 0 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("retries"), *v.Retries, "must not be negative"))
+		}
+	}
+
+	return allErrs
+}
+
 func validateKubeAPIServer(v *kops.KubeAPIServerConfig, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -575,6 +797,161 @@ func validateKubeAPIServer(v *kops.KubeAPIServerConfig, c *kops.Cluster, fldPath
 	return allErrs
 }
 
+func validateKubeControllerManager(kcm *kops.KubeControllerManagerConfig, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if fi.BoolValue(kcm.EnableLeaderMigration) && !c.IsKubernetesGTE("1.21") {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("enableLeaderMigration"), "leader migration requires Kubernetes 1.21 or later"))
+	}
+
+	return allErrs
+}
+
+func validateKubeScheduler(ks *kops.KubeSchedulerConfig, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(ks.Profiles) == 0 {
+		return allErrs
+	}
+
+	if !c.IsKubernetesGTE("1.19") {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("profiles"), "scheduler profiles require Kubernetes 1.19 or later"))
+	}
+	if fi.BoolValue(ks.UsePolicyConfigMap) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("profiles"), "scheduler profiles cannot be used with usePolicyConfigMap"))
+	}
+
+	schedulerNames := sets.NewString()
+	for i, profile := range ks.Profiles {
+		fldPath := fldPath.Child("profiles").Index(i)
+
+		schedulerName := profile.SchedulerName
+		if schedulerName == "" {
+			schedulerName = "default-scheduler"
+		}
+		if schedulerNames.Has(schedulerName) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("schedulerName"), schedulerName))
+		}
+		schedulerNames.Insert(schedulerName)
+
+		allErrs = append(allErrs, validateKubeSchedulerPluginConfig(profile.PluginConfig, fldPath.Child("pluginConfig"))...)
+
+		if profile.Plugins == nil {
+			continue
+		}
+		fldPath = fldPath.Child("plugins")
+		pluginSets := []struct {
+			name string
+			set  *kops.KubeSchedulerPluginSet
+		}{
+			{"queueSort", profile.Plugins.QueueSort},
+			{"preFilter", profile.Plugins.PreFilter},
+			{"filter", profile.Plugins.Filter},
+			{"postFilter", profile.Plugins.PostFilter},
+			{"preScore", profile.Plugins.PreScore},
+			{"score", profile.Plugins.Score},
+			{"reserve", profile.Plugins.Reserve},
+			{"permit", profile.Plugins.Permit},
+			{"preBind", profile.Plugins.PreBind},
+			{"bind", profile.Plugins.Bind},
+			{"postBind", profile.Plugins.PostBind},
+		}
+		for _, pluginSet := range pluginSets {
+			if pluginSet.set == nil {
+				continue
+			}
+			allErrs = append(allErrs, validateKubeSchedulerPlugins(pluginSet.set.Enabled, pluginSet.name == "score", fldPath.Child(pluginSet.name, "enabled"))...)
+			allErrs = append(allErrs, validateKubeSchedulerPlugins(pluginSet.set.Disabled, false, fldPath.Child(pluginSet.name, "disabled"))...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateKubeSchedulerPlugins checks the plugins enabled or disabled at an extension point.
+// Weights only apply to the score plugins being enabled.
+func validateKubeSchedulerPlugins(plugins []kops.KubeSchedulerPlugin, weighted bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, plugin := range plugins {
+		if plugin.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("name"), ""))
+		} else if names.Has(plugin.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), plugin.Name))
+		}
+		names.Insert(plugin.Name)
+
+		if plugin.Weight != nil {
+			if !weighted {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("weight"), "weight can only be set on the score plugins being enabled"))
+			} else if *plugin.Weight < 1 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("weight"), *plugin.Weight, "must be at least 1"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateKubeSchedulerPluginConfig checks the arguments of the plugins of a profile. The arguments depend on the plugin
+// and on the version of the configuration file, so they are left to kube-scheduler beyond being an object.
+func validateKubeSchedulerPluginConfig(pluginConfig []kops.KubeSchedulerPluginConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, config := range pluginConfig {
+		if config.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("name"), ""))
+		} else if names.Has(config.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), config.Name))
+		}
+		names.Insert(config.Name)
+
+		if config.Args == nil || len(config.Args.Raw) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("args"), ""))
+			continue
+		}
+		var args map[string]interface{}
+		if err := json.Unmarshal(config.Args.Raw, &args); err != nil || args == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("args"), string(config.Args.Raw), "must be an object"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateCloudControllerManager(ccm *kops.CloudControllerManagerConfig, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ccm.ConcurrentServiceSyncs != nil && *ccm.ConcurrentServiceSyncs < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("concurrentServiceSyncs"), *ccm.ConcurrentServiceSyncs, "must be at least 1"))
+	}
+
+	for name, value := range ccm.FeatureGates {
+		if _, err := strconv.ParseBool(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("featureGates").Key(name), value, "must be true or false"))
+		}
+	}
+
+	if fi.BoolValue(ccm.EnableLeaderMigration) {
+		if !c.IsKubernetesGTE("1.21") {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("enableLeaderMigration"), "leader migration requires Kubernetes 1.21 or later"))
+		}
+		if kops.CloudProviderID(c.Spec.CloudProvider) == kops.CloudProviderDO {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("enableLeaderMigration"), "DigitalOcean has no in-tree cloud controllers to migrate from"))
+		}
+	}
+
+	// The AWS cloud controller manager finds the resources of the cluster by the kubernetes.io/cluster/<name> tags
+	// that kOps applies with the name of the cluster
+	if kops.CloudProviderID(c.Spec.CloudProvider) == kops.CloudProviderAWS && ccm.ClusterName != "" && ccm.ClusterName != c.ObjectMeta.Name {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("clusterName"), "the AWS cloud controller manager must use the name of the cluster, which kOps tags the resources of the cluster with"))
+	}
+
+	return allErrs
+}
+
 func validateKubeProxy(k *kops.KubeProxyConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -635,14 +1012,44 @@ func validateKubelet(k *kops.KubeletConfigSpec, c *kops.Cluster, kubeletPath *fi
 			}
 		}
 
+		if fi.BoolValue(k.ServerTLSBootstrap) && kops.CloudProviderID(c.Spec.CloudProvider) != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(kubeletPath.Child("serverTLSBootstrap"), "kubelet server TLS bootstrap is only supported on AWS"))
+		}
+
 		if k.LogFormat != "" {
 			allErrs = append(allErrs, IsValidValue(kubeletPath.Child("logFormat"), &k.LogFormat, []string{"text", "json"})...)
 		}
 
+		for name, dropIn := range k.ConfigDropIns {
+			obj := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(dropIn), &obj); err != nil {
+				allErrs = append(allErrs, field.Invalid(kubeletPath.Child("configDropIns").Key(name), dropIn, fmt.Sprintf("drop-in must be a YAML object: %v", err)))
+			}
+		}
+
+		allErrs = append(allErrs, validateContainerLogRotation(k, kubeletPath)...)
 	}
 	return allErrs
 }
 
+// validateContainerLogRotation validates the rotation of the container logs, which the kubelet applies to CRI runtimes
+// and nodeup to the log driver of docker
+func validateContainerLogRotation(k *kops.KubeletConfigSpec, kubeletPath *field.Path) (allErrs field.ErrorList) {
+	if k.ContainerLogMaxSize != "" {
+		if size, err := resource.ParseQuantity(k.ContainerLogMaxSize); err != nil {
+			allErrs = append(allErrs, field.Invalid(kubeletPath.Child("containerLogMaxSize"), k.ContainerLogMaxSize, "must be a quantity, such as 10Mi"))
+		} else if size.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(kubeletPath.Child("containerLogMaxSize"), k.ContainerLogMaxSize, "must be greater than 0"))
+		}
+	}
+
+	if k.ContainerLogMaxFiles != nil && *k.ContainerLogMaxFiles < 2 {
+		allErrs = append(allErrs, field.Invalid(kubeletPath.Child("containerLogMaxFiles"), *k.ContainerLogMaxFiles, "must be at least 2"))
+	}
+
+	return allErrs
+}
+
 func validateNetworking(cluster *kops.Cluster, v *kops.NetworkingSpec, fldPath *field.Path) field.ErrorList {
 	c := &cluster.Spec
 	allErrs := field.ErrorList{}
@@ -986,6 +1393,9 @@ func validateEtcdClusterSpec(spec kops.EtcdClusterSpec, c *kops.Cluster, fieldPa
 	for i, m := range spec.Members {
 		allErrs = append(allErrs, validateEtcdMemberSpec(m, fieldPath.Child("etcdMembers").Index(i))...)
 	}
+	if spec.Backups != nil && spec.Backups.ReplicaStore != "" {
+		allErrs = append(allErrs, validateEtcdReplicaStore(spec, fieldPath.Child("backups", "replicaStore"))...)
+	}
 
 	return allErrs
 }
@@ -1004,6 +1414,26 @@ func validateEtcdBackupStore(specs []kops.EtcdClusterSpec, fieldPath *field.Path
 	return allErrs
 }
 
+// validateEtcdReplicaStore checks that the backups can be copied to the replica store without copying them again
+func validateEtcdReplicaStore(spec kops.EtcdClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	replicaStore := spec.Backups.ReplicaStore
+	if spec.Provider == kops.EtcdProviderTypeLegacy {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "the backups can only be replicated with etcd-manager"))
+	}
+	if _, err := vfs.Context.BuildVfsPath(replicaStore); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, replicaStore, fmt.Sprintf("cannot parse VFS path: %v", err)))
+	}
+
+	backupStore := strings.TrimSuffix(spec.Backups.BackupStore, "/") + "/"
+	if replica := strings.TrimSuffix(replicaStore, "/") + "/"; strings.HasPrefix(replica, backupStore) || strings.HasPrefix(backupStore, replica) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "the replica store must not overlap the backup store"))
+	}
+
+	return allErrs
+}
+
 // validateEtcdTLS checks the TLS settings for etcd are valid
 func validateEtcdTLS(specs []kops.EtcdClusterSpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -1235,7 +1665,7 @@ func validateContainerRuntime(runtime *string, fldPath *field.Path) field.ErrorL
 	return allErrs
 }
 
-func validateContainerdConfig(config *kops.ContainerdConfig, fldPath *field.Path) field.ErrorList {
+func validateContainerdConfig(spec *kops.ClusterSpec, config *kops.ContainerdConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	if config.Version != nil {
@@ -1248,6 +1678,40 @@ func validateContainerdConfig(config *kops.ContainerdConfig, fldPath *field.Path
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("version"), config.Version,
 				"unsupported legacy version"))
 		}
+		if config.NRI != nil && config.NRI.Enabled && err == nil && sv.LT(semver.MustParse("1.7.0")) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("nri", "enabled"), "NRI requires containerd 1.7 or later"))
+		}
+	}
+
+	// The configuration is generated for containerd running in CRI mode;
+	// when containerd runs for Docker, kOps sets configOverride itself.
+	if spec.ContainerRuntime == "containerd" && fi.StringValue(config.ConfigOverride) != "" {
+		if config.CgroupDriver != nil || config.NRI != nil || len(config.Registries) > 0 || len(config.RegistryMirrors) > 0 || config.SandboxImage != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("configOverride"), "configOverride cannot be used in conjunction with cgroupDriver, nri, registries, registryMirrors or sandboxImage"))
+		}
+	}
+
+	if config.CgroupDriver != nil {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("cgroupDriver"), config.CgroupDriver, []string{"systemd", "cgroupfs"})...)
+		if spec.Kubelet != nil && spec.Kubelet.CgroupDriver != "" && spec.Kubelet.CgroupDriver != *config.CgroupDriver {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("cgroupDriver"), fmt.Sprintf("cgroupDriver must match the kubelet cgroupDriver %q", spec.Kubelet.CgroupDriver)))
+		}
+	}
+
+	if config.SandboxImage != nil && *config.SandboxImage == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("sandboxImage"), "sandboxImage must not be empty"))
+	}
+
+	for name, registry := range config.Registries {
+		registryPath := fldPath.Child("registries").Key(name)
+		if _, found := config.RegistryMirrors[name]; found && len(registry.Mirrors) > 0 {
+			allErrs = append(allErrs, field.Forbidden(registryPath.Child("mirrors"), "mirrors cannot be set for a registry also in registryMirrors"))
+		}
+		for i, mirror := range registry.Mirrors {
+			if u, err := url.Parse(mirror); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(registryPath.Child("mirrors").Index(i), mirror, "must be an http or https URL"))
+			}
+		}
 	}
 
 	if config.Packages != nil {
@@ -1395,6 +1859,45 @@ func validateRollingUpdate(rollingUpdate *kops.RollingUpdate, fldpath *field.Pat
 			allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSurge"), "Cannot be zero if maxUnavailable is zero"))
 		}
 	}
+	if rollingUpdate.Strategy != "" {
+		strategy := string(rollingUpdate.Strategy)
+		allErrs = append(allErrs, IsValidValue(fldpath.Child("strategy"), &strategy, []string{string(kops.RollingUpdateStrategyInPlace), string(kops.RollingUpdateStrategySurgeGroup)})...)
+		if onMasterInstanceGroup && rollingUpdate.Strategy == kops.RollingUpdateStrategySurgeGroup {
+			allErrs = append(allErrs, field.Forbidden(fldpath.Child("strategy"), "Cannot surge instance groups with role \"Master\""))
+		}
+	}
+	if rollingUpdate.Drain != nil {
+		allErrs = append(allErrs, validateDrain(rollingUpdate.Drain, fldpath.Child("drain"))...)
+	}
+	return allErrs
+}
+
+func validateDrain(drain *kops.DrainSpec, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, d := range []struct {
+		name  string
+		value *metav1.Duration
+	}{
+		{"timeout", drain.Timeout},
+		{"gracePeriod", drain.GracePeriod},
+		{"skipWaitForDeleteTimeout", drain.SkipWaitForDeleteTimeout},
+		{"podDisruptionBudgetTimeout", drain.PodDisruptionBudgetTimeout},
+	} {
+		if d.value != nil && d.value.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child(d.name), d.value.Duration.String(), "Cannot be negative"))
+		}
+	}
+	if drain.Timeout != nil && drain.Timeout.Duration > 0 && drain.PodDisruptionBudgetTimeout != nil && drain.PodDisruptionBudgetTimeout.Duration > drain.Timeout.Duration {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("podDisruptionBudgetTimeout"), drain.PodDisruptionBudgetTimeout.Duration.String(), "Cannot be longer than the drain timeout"))
+	}
+	if drain.PodDisruptionBudgetTimeoutAction != "" {
+		action := string(drain.PodDisruptionBudgetTimeoutAction)
+		allErrs = append(allErrs, IsValidValue(fldpath.Child("podDisruptionBudgetTimeoutAction"), &action, []string{
+			string(kops.PodDisruptionBudgetTimeoutActionBlock),
+			string(kops.PodDisruptionBudgetTimeoutActionIgnore),
+			string(kops.PodDisruptionBudgetTimeoutActionScaleUp),
+		})...)
+	}
 	return allErrs
 }
 
@@ -1432,6 +1935,60 @@ func validateClusterAutoscaler(cluster *kops.Cluster, spec *kops.ClusterAutoscal
 	return allErrs
 }
 
+func validateDescheduler(spec *kops.DeschedulerConfig, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.Schedule != nil && len(strings.Fields(*spec.Schedule)) != 5 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), *spec.Schedule, "must be a cron schedule with five fields"))
+	}
+
+	profiles := sets.NewString()
+	for i, profile := range spec.Profiles {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("profiles").Index(i), &profile, kops.SupportedDeschedulerProfiles)...)
+		if profiles.Has(profile) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("profiles").Index(i), profile))
+		}
+		profiles.Insert(profile)
+	}
+
+	if utilization := spec.NodeUtilization; utilization != nil {
+		resources := []string{"cpu", "memory", "pods"}
+		for _, thresholds := range []struct {
+			name   string
+			values map[string]int32
+		}{
+			{"thresholds", utilization.Thresholds},
+			{"targetThresholds", utilization.TargetThresholds},
+		} {
+			for resource, value := range thresholds.values {
+				fld := fldPath.Child("nodeUtilization", thresholds.name).Key(resource)
+				allErrs = append(allErrs, IsValidValue(fld, &resource, resources)...)
+				if value < 0 || value > 100 {
+					allErrs = append(allErrs, field.Invalid(fld, value, "must be a percentage between 0 and 100"))
+				}
+			}
+		}
+		for resource, threshold := range utilization.Thresholds {
+			if target, found := utilization.TargetThresholds[resource]; found && threshold > target {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeUtilization", "thresholds").Key(resource), threshold, "must not be greater than the target threshold"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateVerticalPodAutoscaler(cluster *kops.Cluster, spec *kops.VerticalPodAutoscalerConfig, fldPath *field.Path) (allErrs field.ErrorList) {
+	if !fi.BoolValue(spec.Enabled) {
+		return allErrs
+	}
+	if metricsServer := cluster.Spec.MetricsServer; metricsServer == nil || !fi.BoolValue(metricsServer.Enabled) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("enabled"), "Vertical pod autoscaler requires that metrics server is enabled"))
+	}
+	if spec.CertificateValidity != nil && spec.CertificateValidity.Duration < time.Hour {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("certificateValidity"), spec.CertificateValidity.Duration.String(), "must be at least 1h"))
+	}
+	return allErrs
+}
+
 func validateNodeTerminationHandler(cluster *kops.Cluster, spec *kops.NodeTerminationHandlerConfig, fldPath *field.Path) (allErrs field.ErrorList) {
 	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
 		allErrs = append(allErrs, field.Forbidden(fldPath, "Node Termination Handler supports only AWS"))
@@ -1455,6 +2012,19 @@ func validateAWSLoadBalancerController(cluster *kops.Cluster, spec *kops.AWSLoad
 			allErrs = append(allErrs, field.Forbidden(fldPath, "AWS Load Balancer Controller requires that cert manager is enabled"))
 		}
 	}
+
+	if spec.IngressClass != nil {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(*spec.IngressClass) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ingressClass"), *spec.IngressClass, msg))
+		}
+		if !cluster.IsKubernetesGTE("1.19") {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("ingressClass"), "ingressClass requires Kubernetes 1.19 or later"))
+		}
+	}
+	if fi.BoolValue(spec.DefaultIngressClass) && spec.IngressClass == nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("defaultIngressClass"), "defaultIngressClass requires ingressClass"))
+	}
+
 	return allErrs
 }
 
@@ -1469,6 +2039,72 @@ func validateCloudConfiguration(cloudConfig *kops.CloudConfiguration, fldPath *f
 	return allErrs
 }
 
+func validateEBSDefaultEncryption(spec *kops.ClusterSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "EBS default encryption is only supported on AWS"))
+	}
+
+	if kmsKeyId := spec.CloudConfig.EBSDefaultEncryption.KmsKeyId; kmsKeyId != nil && *kmsKeyId == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("kmsKeyId"), "kmsKeyId must not be empty when set"))
+	}
+
+	// EBS encryption by default makes it impossible to create unencrypted volumes
+	for i, etcd := range spec.EtcdClusters {
+		for j, m := range etcd.Members {
+			if m.EncryptedVolume != nil && !*m.EncryptedVolume {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "etcdClusters").Index(i).Child("etcdMembers").Index(j).Child("encryptedVolume"), "etcd volumes are always encrypted when ebsDefaultEncryption is set"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateAWSEBSCSIDriver(driver *kops.AWSEBSCSIDriver, fldPath *field.Path) (allErrs field.ErrorList) {
+	allErrs = append(allErrs, validateCloudLabels(driver.ExtraTags, fldPath.Child("extraTags"))...)
+	allErrs = append(allErrs, validateAWSEBSCSIDriverTags(driver.ExtraTags, fldPath.Child("extraTags"))...)
+
+	return allErrs
+}
+
+// validateAWSEBSCSIDriverClusterTags validates the cloudLabels and the labels of the cloud label policies that the EBS CSI
+// driver applies to the volumes it provisions
+func validateAWSEBSCSIDriverClusterTags(spec *kops.ClusterSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	allErrs = append(allErrs, validateAWSEBSCSIDriverTags(spec.CloudLabels, fldPath.Child("cloudLabels"))...)
+
+	for i, policy := range spec.CloudLabelPolicies {
+		if len(policy.Subnets) != 0 {
+			continue
+		}
+		appliesToVolumes := len(policy.ResourceTypes) == 0
+		for _, resourceType := range policy.ResourceTypes {
+			if resourceType == kops.CloudResourceTypeVolume {
+				appliesToVolumes = true
+			}
+		}
+		if appliesToVolumes {
+			allErrs = append(allErrs, validateAWSEBSCSIDriverTags(policy.Labels, fldPath.Child("cloudLabelPolicies").Index(i).Child("labels"))...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateAWSEBSCSIDriverTags validates tags passed to the EBS CSI driver, which takes them as a comma separated list
+// of key=value pairs that cannot be escaped
+func validateAWSEBSCSIDriverTags(tags map[string]string, fldPath *field.Path) (allErrs field.ErrorList) {
+	for k, v := range tags {
+		if strings.ContainsAny(k, ",=") {
+			allErrs = append(allErrs, field.Invalid(fldPath, k, "tag keys cannot contain commas or equal signs, as they are passed to the EBS CSI driver"))
+		}
+		if strings.Contains(v, ",") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(k), v, "tag values cannot contain commas, as they are passed to the EBS CSI driver"))
+		}
+	}
+
+	return allErrs
+}
+
 func validateWarmPool(warmPool *kops.WarmPoolSpec, fldPath *field.Path) (allErrs field.ErrorList) {
 	if warmPool.MaxSize != nil {
 		if *warmPool.MaxSize < 0 {
@@ -1483,6 +2119,392 @@ func validateWarmPool(warmPool *kops.WarmPoolSpec, fldPath *field.Path) (allErrs
 	return allErrs
 }
 
+var imageDigestRegexp = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+func validateImageVerification(spec *kops.ImageVerificationSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.Action != "" {
+		value := string(spec.Action)
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("action"), &value, []string{
+			string(kops.ImageVerificationActionEnforce),
+			string(kops.ImageVerificationActionWarn),
+		})...)
+	}
+
+	for image, digest := range spec.Digests {
+		if !imageDigestRegexp.MatchString(digest) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("digests").Key(image), digest, "must be a sha256 digest, e.g. sha256:<64 hex characters>"))
+		}
+	}
+
+	for i, key := range spec.CosignPublicKeys {
+		if _, err := cosign.ParsePublicKey([]byte(key)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cosignPublicKeys").Index(i), key, fmt.Sprintf("must be a PEM encoded ECDSA public key: %v", err)))
+		}
+	}
+
+	if len(spec.Digests) == 0 && len(spec.CosignPublicKeys) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of digests or cosignPublicKeys must be set"))
+	}
+
+	return allErrs
+}
+
+func validateContinuousValidation(spec *kops.ContinuousValidationSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.Interval != nil && spec.Interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), spec.Interval.Duration.String(), "must be greater than zero"))
+	}
+	for i, check := range spec.Checks {
+		if check == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("checks").Index(i), ""))
+		}
+	}
+	return allErrs
+}
+
+func validateNTP(spec *kops.NTPConfig, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.Managed != nil && !*spec.Managed {
+		if len(spec.Servers) != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("servers"), "servers can only be set when NTP is managed"))
+		}
+		if fi.BoolValue(spec.WaitForSync) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("waitForSync"), "waitForSync can only be set when NTP is managed"))
+		}
+		if spec.MetricsTextfileDirectory != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("metricsTextfileDirectory"), "metricsTextfileDirectory can only be set when NTP is managed"))
+		}
+	}
+	for i, server := range spec.Servers {
+		if strings.TrimSpace(server) == "" || strings.ContainsAny(server, " \t\n") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("servers").Index(i), server, "must be a host name or an IP address"))
+		}
+	}
+	if spec.MetricsTextfileDirectory != "" && !path.IsAbs(spec.MetricsTextfileDirectory) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("metricsTextfileDirectory"), spec.MetricsTextfileDirectory, "must be an absolute path"))
+	}
+	return allErrs
+}
+
+func validateSessionManager(spec *kops.SessionManagerSpec, cloudProvider kops.CloudProviderID, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if fi.BoolValue(spec.Enabled) && cloudProvider != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("enabled"), "Session Manager is only supported on AWS"))
+	}
+
+	for i, pkg := range spec.AgentPackages {
+		fldPath := fieldPath.Child("agentPackages").Index(i)
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("architecture"), &pkg.Architecture, []string{string(architectures.ArchitectureAmd64), string(architectures.ArchitectureArm64)})...)
+		if u, err := url.Parse(pkg.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), pkg.URL, "must be an http or https URL"))
+		} else if ext := path.Ext(u.Path); ext != ".deb" && ext != ".rpm" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), pkg.URL, "must be a .deb or .rpm package"))
+		}
+		if pkg.Hash == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("hash"), "the SHA256 hash of the package must be set"))
+		} else if h, err := hashing.FromString(pkg.Hash); err != nil || h.Algorithm != hashing.HashAlgorithmSHA256 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hash"), pkg.Hash, "must be a SHA256 hash"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateSSH(spec *kops.SSHSpec, cloud kops.CloudProviderID, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.User != "" {
+		if spec.User == "root" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("user"), "SSH public keys cannot be authorized for root"))
+		} else if !sshUserRegexp.MatchString(spec.User) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("user"), spec.User, "must be a valid user name"))
+		}
+	}
+
+	if fi.BoolValue(spec.InstanceConnect) && cloud != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("instanceConnect"), "EC2 Instance Connect is only supported on AWS"))
+	}
+
+	names := sets.NewString()
+	for i, key := range spec.PublicKeys {
+		keyPath := fldPath.Child("publicKeys").Index(i)
+		if key.Name == "" {
+			allErrs = append(allErrs, field.Required(keyPath.Child("name"), ""))
+		} else if names.Has(key.Name) {
+			allErrs = append(allErrs, field.Duplicate(keyPath.Child("name"), key.Name))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Label(key.Name) {
+				allErrs = append(allErrs, field.Invalid(keyPath.Child("name"), key.Name, msg))
+			}
+			names.Insert(key.Name)
+		}
+
+		if key.PublicKey == "" {
+			allErrs = append(allErrs, field.Required(keyPath.Child("publicKey"), ""))
+		} else if publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey)); err != nil {
+			allErrs = append(allErrs, field.Invalid(keyPath.Child("publicKey"), key.PublicKey, fmt.Sprintf("must be an SSH public key: %v", err)))
+		} else if _, ok := publicKey.(*ssh.Certificate); ok {
+			allErrs = append(allErrs, field.Invalid(keyPath.Child("publicKey"), key.PublicKey, "must be an SSH public key, not a certificate"))
+		}
+	}
+
+	return allErrs
+}
+
+var sshUserRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
+
+func validateEvents(spec *kops.EventsSpec, cloud kops.CloudProviderID, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.SNSTopicARN == "" && spec.EventBusName == "" {
+		return allErrs
+	}
+	if cloud != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "events are only supported on AWS"))
+	}
+	if spec.SNSTopicARN != "" {
+		parsedARN, err := arn.Parse(spec.SNSTopicARN)
+		if err != nil || parsedARN.Service != "sns" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("snsTopicARN"), spec.SNSTopicARN,
+				"must be a valid SNS topic ARN such as arn:aws:sns:us-east-1:123456789012:kops-events"))
+		}
+	}
+	if strings.HasPrefix(spec.EventBusName, "arn:") {
+		parsedARN, err := arn.Parse(spec.EventBusName)
+		if err != nil || parsedARN.Service != "events" || !strings.HasPrefix(parsedARN.Resource, "event-bus/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("eventBusName"), spec.EventBusName,
+				"must be an event bus name or a valid event bus ARN such as arn:aws:events:us-east-1:123456789012:event-bus/kops-events"))
+		}
+	}
+	return allErrs
+}
+
+func validateCloudLabelPolicy(spec *kops.ClusterSpec, policy *kops.CloudLabelPolicy, fldPath *field.Path) (allErrs field.ErrorList) {
+	if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "cloud label policies are only supported on AWS"))
+	}
+	if len(policy.Labels) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("labels"), ""))
+	}
+	allErrs = append(allErrs, validateCloudLabels(policy.Labels, fldPath.Child("labels"))...)
+	if len(policy.ResourceTypes) == 0 && len(policy.Subnets) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "either resourceTypes or subnets must be specified; use cloudLabels to tag all resources"))
+	}
+	for i, resourceType := range policy.ResourceTypes {
+		value := string(resourceType)
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("resourceTypes").Index(i), &value, kops.SupportedCloudResourceTypes)...)
+	}
+	subnets := sets.NewString()
+	for _, subnet := range spec.Subnets {
+		subnets.Insert(subnet.Name)
+	}
+	for i, subnet := range policy.Subnets {
+		if !subnets.Has(subnet) {
+			allErrs = append(allErrs, field.NotFound(fldPath.Child("subnets").Index(i), subnet))
+		}
+	}
+	return allErrs
+}
+
+func validatePodSecurityAdmission(c *kops.Cluster, spec *kops.PodSecurityAdmissionSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if c.IsKubernetesLT("1.22") {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Pod Security Admission requires Kubernetes 1.22 or later"))
+	}
+
+	// nodeup writes the whole admission configuration, so it cannot be combined with one provided by the user
+	if apiserver := c.Spec.KubeAPIServer; apiserver != nil && apiserver.AdmissionControlConfigFile != "" && apiserver.AdmissionControlConfigFile != components.PodSecurityAdmissionConfigFile {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "podSecurityAdmission cannot be set together with kubeAPIServer.admissionControlConfigFile"))
+	}
+
+	for _, level := range []struct {
+		name  string
+		value string
+	}{
+		{"enforce", spec.Enforce},
+		{"audit", spec.Audit},
+		{"warn", spec.Warn},
+	} {
+		if level.value != "" {
+			allErrs = append(allErrs, IsValidValue(fldPath.Child(level.name), &level.value, kops.SupportedPodSecurityLevels)...)
+		}
+	}
+
+	if spec.Version != "" && spec.Version != "latest" && !podSecurityVersionRegex.MatchString(spec.Version) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("version"), spec.Version, "version must be \"latest\" or a Kubernetes minor version such as \"v1.22\""))
+	}
+
+	for i, namespace := range spec.ExemptNamespaces {
+		for _, msg := range utilvalidation.IsDNS1123Label(namespace) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("exemptNamespaces").Index(i), namespace, msg))
+		}
+	}
+
+	return allErrs
+}
+
+var podSecurityVersionRegex = regexp.MustCompile(`^v1\.[0-9]+$`)
+
+func validateOSPatching(spec *kops.ClusterSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	patching := spec.OSPatching
+	if patching.Interval != nil && patching.Interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), patching.Interval.Duration.String(), "must be greater than zero"))
+	}
+	if patching.Method != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("method"), &patching.Method, []string{kops.OSPatchingMethodPod, kops.OSPatchingMethodSSM})...)
+		if patching.Method == kops.OSPatchingMethodSSM && kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("method"), "SSM is only supported on AWS"))
+		}
+	}
+	for i, name := range patching.InstanceGroups {
+		if name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("instanceGroups").Index(i), ""))
+		}
+	}
+	return allErrs
+}
+
+func validateHibernation(spec *kops.ClusterSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "hibernation is only supported on AWS"))
+	}
+	return allErrs
+}
+
+func validateRBACBindings(bindings []kops.RBACBindingSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	names := sets.NewString()
+	for i := range bindings {
+		binding := &bindings[i]
+		fieldPath := fldPath.Index(i)
+
+		if binding.Name == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("name"), "name must be specified"))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Label(binding.Name) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), binding.Name, msg))
+			}
+			if names.Has(binding.Name) {
+				allErrs = append(allErrs, field.Duplicate(fieldPath.Child("name"), binding.Name))
+			}
+			names.Insert(binding.Name)
+		}
+
+		if binding.ClusterRole == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("clusterRole"), "clusterRole must be specified"))
+		} else if strings.ContainsAny(binding.ClusterRole, "/%") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("clusterRole"), binding.ClusterRole, "clusterRole may not contain '/' or '%'"))
+		}
+
+		if binding.Namespace != "" {
+			for _, msg := range utilvalidation.IsDNS1123Label(binding.Namespace) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("namespace"), binding.Namespace, msg))
+			}
+		}
+
+		if len(binding.Groups) == 0 && len(binding.Users) == 0 {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("groups"), "at least one group or user must be specified"))
+		}
+		for j, group := range binding.Groups {
+			if group == "" {
+				allErrs = append(allErrs, field.Required(fieldPath.Child("groups").Index(j), ""))
+			}
+		}
+		for j, user := range binding.Users {
+			if user == "" {
+				allErrs = append(allErrs, field.Required(fieldPath.Child("users").Index(j), ""))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateOIDCAuthentication(spec *kops.OIDCAuthenticationSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.IssuerURL == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("issuerURL"), "an issuer URL is required"))
+	} else if u, err := url.Parse(spec.IssuerURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("issuerURL"), spec.IssuerURL, "must be an https URL"))
+	}
+
+	if spec.Dex != nil {
+		dexPath := fldPath.Child("dex")
+		if spec.Dex.Replicas != nil && *spec.Dex.Replicas < 1 {
+			allErrs = append(allErrs, field.Invalid(dexPath.Child("replicas"), *spec.Dex.Replicas, "must be at least 1"))
+		}
+		if spec.Dex.Connectors == "" {
+			allErrs = append(allErrs, field.Required(dexPath.Child("connectors"), "at least one connector is required"))
+		} else {
+			var connectors []map[string]interface{}
+			if err := yaml.Unmarshal([]byte(spec.Dex.Connectors), &connectors); err != nil {
+				allErrs = append(allErrs, field.Invalid(dexPath.Child("connectors"), spec.Dex.Connectors, fmt.Sprintf("must be a YAML list of connectors: %v", err)))
+			} else if len(connectors) == 0 {
+				allErrs = append(allErrs, field.Required(dexPath.Child("connectors"), "at least one connector is required"))
+			}
+		}
+		for i, uri := range spec.Dex.RedirectURIs {
+			if u, err := url.Parse(uri); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(dexPath.Child("redirectURIs").Index(i), uri, "must be an http or https URL"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateMaintenanceWindow(spec *kops.MaintenanceWindowSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.Schedule == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("schedule"), "a cron schedule is required"))
+	} else if _, err := maintenancewindow.ParseSchedule(spec.Schedule); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), spec.Schedule, err.Error()))
+	}
+	if spec.Duration == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("duration"), "a duration is required"))
+	} else if spec.Duration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("duration"), spec.Duration.Duration.String(), "must be greater than zero"))
+	}
+	return allErrs
+}
+
+func validateInstanceGroupDefaults(spec *kops.InstanceGroupSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.Role != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("role"), "the role must be set on each instance group"))
+	}
+	if spec.RollingUpdate != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("rollingUpdate"), "use spec.rollingUpdate to configure rolling updates for all instance groups"))
+	}
+	return allErrs
+}
+
+func validateNodeLabelReconciliation(spec *kops.NodeLabelReconciliationSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if spec.Interval != nil && spec.Interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), spec.Interval.Duration.String(), "must be greater than zero"))
+	}
+	return allErrs
+}
+
+func validateNodeRemediation(npd *kops.NodeProblemDetectorConfig, fldPath *field.Path) (allErrs field.ErrorList) {
+	spec := npd.Remediation
+	if !fi.BoolValue(spec.Enabled) {
+		return allErrs
+	}
+	if !fi.BoolValue(npd.Enabled) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("enabled"), "remediation requires the node problem detector to be enabled"))
+	}
+	conditions := sets.NewString()
+	for i, condition := range spec.Conditions {
+		if condition == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("conditions").Index(i), ""))
+		} else if condition == "Ready" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("conditions").Index(i), "the Ready condition is true on healthy nodes"))
+		} else if conditions.Has(condition) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("conditions").Index(i), condition))
+		}
+		conditions.Insert(condition)
+	}
+	if spec.Threshold != nil && spec.Threshold.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("threshold"), spec.Threshold.Duration.String(), "must be greater than zero"))
+	}
+	if spec.MaxConcurrent != nil && *spec.MaxConcurrent < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxConcurrent"), *spec.MaxConcurrent, "must be at least 1"))
+	}
+	return allErrs
+}
+
 func validateSnapshotController(cluster *kops.Cluster, spec *kops.SnapshotControllerConfig, fldPath *field.Path) (allErrs field.ErrorList) {
 	if spec != nil && fi.BoolValue(spec.Enabled) {
 		if !cluster.IsKubernetesGTE("1.20") {
@@ -1497,3 +2519,93 @@ func validateSnapshotController(cluster *kops.Cluster, spec *kops.SnapshotContro
 	}
 	return allErrs
 }
+
+func validateEgressGateways(c *kops.Cluster, fldPath *field.Path) (allErrs field.ErrorList) {
+	if kops.CloudProviderID(c.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "egress gateways are only supported on AWS"))
+	}
+
+	if c.Spec.Networking == nil || c.Spec.Networking.Cilium == nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "egress gateways require Cilium networking"))
+	} else {
+		cilium := c.Spec.Networking.Cilium
+		if cilium.Version != "" {
+			version, err := semver.ParseTolerant(cilium.Version)
+			if err == nil && version.LT(semver.MustParse("1.10.0")) {
+				allErrs = append(allErrs, field.Forbidden(fldPath, "egress gateways require Cilium 1.10 or later"))
+			}
+		}
+		if !cilium.EnableNodePort {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "egress gateways require Cilium to replace kube-proxy (networking.cilium.enableNodePort)"))
+		}
+		if !fi.BoolValue(cilium.EnableBPFMasquerade) {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "egress gateways require Cilium BPF masquerading (networking.cilium.enableBPFMasquerade)"))
+		}
+	}
+
+	names := sets.NewString()
+	for i, gateway := range c.Spec.EgressGateways {
+		gatewayPath := fldPath.Index(i)
+		if gateway.Name == "" {
+			allErrs = append(allErrs, field.Required(gatewayPath.Child("name"), ""))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Label(gateway.Name) {
+				allErrs = append(allErrs, field.Invalid(gatewayPath.Child("name"), gateway.Name, msg))
+			}
+			if names.Has(gateway.Name) {
+				allErrs = append(allErrs, field.Duplicate(gatewayPath.Child("name"), gateway.Name))
+			}
+			names.Insert(gateway.Name)
+		}
+		if gateway.InstanceGroup == "" {
+			allErrs = append(allErrs, field.Required(gatewayPath.Child("instanceGroup"), ""))
+		}
+		if len(gateway.Namespaces) == 0 {
+			allErrs = append(allErrs, field.Required(gatewayPath.Child("namespaces"), ""))
+		}
+		for j, namespace := range gateway.Namespaces {
+			for _, msg := range utilvalidation.IsDNS1123Label(namespace) {
+				allErrs = append(allErrs, field.Invalid(gatewayPath.Child("namespaces").Index(j), namespace, msg))
+			}
+		}
+		for j, cidr := range gateway.DestinationCIDRs {
+			cidrPath := gatewayPath.Child("destinationCIDRs").Index(j)
+			allErrs = append(allErrs, validateCIDR(cidr, cidrPath)...)
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.IP.To4() == nil {
+				allErrs = append(allErrs, field.Invalid(cidrPath, cidr, "must be an IPv4 CIDR"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateNetworkCIDRAllocation(spec *kops.ClusterSpec, specPath *field.Path) (allErrs field.ErrorList) {
+	allocation := spec.NetworkCIDRAllocation
+	fldPath := specPath.Child("networkCIDRAllocation")
+	allErrs = append(allErrs, IsValidValue(fldPath.Child("provider"), &allocation.Provider, []string{
+		kops.NetworkCIDRAllocationProviderAWS,
+		kops.NetworkCIDRAllocationProviderInfoblox,
+		kops.NetworkCIDRAllocationProviderNetbox,
+	})...)
+	if allocation.Provider == kops.NetworkCIDRAllocationProviderAWS && kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("provider"), "AWS VPC IPAM is only supported on AWS"))
+	}
+	if allocation.Provider == kops.NetworkCIDRAllocationProviderInfoblox || allocation.Provider == kops.NetworkCIDRAllocationProviderNetbox {
+		if allocation.Endpoint == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("endpoint"), fmt.Sprintf("%s requires an endpoint", allocation.Provider)))
+		} else if u, err := url.Parse(allocation.Endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("endpoint"), allocation.Endpoint, "must be an http or https URL"))
+		}
+	}
+	if allocation.Pool == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("pool"), ""))
+	}
+	if allocation.AllocationID == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("allocationID"), ""))
+	}
+	if spec.NetworkCIDR == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("networkCIDR"), "the network CIDR allocated from the IPAM must be set"))
+	}
+	return allErrs
+}
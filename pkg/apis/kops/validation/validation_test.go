@@ -17,8 +17,13 @@ limitations under the License.
 package validation
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -326,6 +331,82 @@ func TestValidateKubeAPIServer(t *testing.T) {
 	}
 }
 
+func Test_Validate_ContainerdConfig(t *testing.T) {
+	grid := []struct {
+		Input          kops.ContainerdConfig
+		Kubelet        *kops.KubeletConfigSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ContainerdConfig{
+				CgroupDriver: fi.String("systemd"),
+				NRI:          &kops.ContainerdNRIConfig{Enabled: true},
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {
+						Mirrors: []string{"https://mirror.example.com"},
+					},
+				},
+				SandboxImage: fi.String("k8s.gcr.io/pause:3.5"),
+				Version:      fi.String("1.7.0"),
+			},
+			Kubelet: &kops.KubeletConfigSpec{CgroupDriver: "systemd"},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				ConfigOverride: fi.String("version = 2"),
+				SandboxImage:   fi.String("k8s.gcr.io/pause:3.5"),
+			},
+			ExpectedErrors: []string{"Forbidden::containerd.configOverride"},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				CgroupDriver: fi.String("none"),
+			},
+			ExpectedErrors: []string{"Unsupported value::containerd.cgroupDriver"},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				CgroupDriver: fi.String("cgroupfs"),
+			},
+			Kubelet:        &kops.KubeletConfigSpec{CgroupDriver: "systemd"},
+			ExpectedErrors: []string{"Forbidden::containerd.cgroupDriver"},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				NRI:     &kops.ContainerdNRIConfig{Enabled: true},
+				Version: fi.String("1.4.6"),
+			},
+			ExpectedErrors: []string{"Forbidden::containerd.nri.enabled"},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				RegistryMirrors: map[string][]string{"docker.io": {"https://mirror.example.com"}},
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {
+						Mirrors: []string{"https://other-mirror.example.com"},
+					},
+					"quay.io": {
+						Mirrors: []string{"mirror.example.com"},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::containerd.registries[docker.io].mirrors",
+				"Invalid value::containerd.registries[quay.io].mirrors[0]",
+			},
+		},
+	}
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{
+			ContainerRuntime: "containerd",
+			Containerd:       &g.Input,
+			Kubelet:          g.Kubelet,
+		}
+		errs := validateContainerdConfig(spec, &g.Input, field.NewPath("containerd"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
 func Test_Validate_DockerConfig_Storage(t *testing.T) {
 	for _, name := range []string{"aufs", "zfs", "overlay"} {
 		config := &kops.DockerConfig{Storage: &name}
@@ -1034,6 +1115,58 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 			},
 			ExpectedErrors: []string{"Forbidden::testField.maxSurge"},
 		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: kops.RollingUpdateStrategySurgeGroup,
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: "Replace",
+			},
+			ExpectedErrors: []string{"Unsupported value::testField.strategy"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: kops.RollingUpdateStrategySurgeGroup,
+			},
+			OnMasterIG:     true,
+			ExpectedErrors: []string{"Forbidden::testField.strategy"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Drain: &kops.DrainSpec{
+					Timeout:                          &metav1.Duration{Duration: time.Hour},
+					PodDisruptionBudgetTimeout:       &metav1.Duration{Duration: 10 * time.Minute},
+					PodDisruptionBudgetTimeoutAction: kops.PodDisruptionBudgetTimeoutActionScaleUp,
+				},
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Drain: &kops.DrainSpec{
+					GracePeriod: &metav1.Duration{Duration: -time.Second},
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.drain.gracePeriod"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Drain: &kops.DrainSpec{
+					Timeout:                    &metav1.Duration{Duration: time.Minute},
+					PodDisruptionBudgetTimeout: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.drain.podDisruptionBudgetTimeout"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Drain: &kops.DrainSpec{
+					PodDisruptionBudgetTimeoutAction: "Evict",
+				},
+			},
+			ExpectedErrors: []string{"Unsupported value::testField.drain.podDisruptionBudgetTimeoutAction"},
+		},
 	}
 	for _, g := range grid {
 		errs := validateRollingUpdate(&g.Input, field.NewPath("testField"), g.OnMasterIG)
@@ -1045,214 +1178,2012 @@ func intStr(i intstr.IntOrString) *intstr.IntOrString {
 	return &i
 }
 
-func Test_Validate_NodeLocalDNS(t *testing.T) {
+func Test_Validate_ContinuousValidation(t *testing.T) {
 	grid := []struct {
-		Input          kops.ClusterSpec
+		Input          kops.ContinuousValidationSpec
 		ExpectedErrors []string
 	}{
 		{
-			Input: kops.ClusterSpec{
-				KubeProxy: &kops.KubeProxyConfig{
-					ProxyMode: "iptables",
-				},
-				KubeDNS: &kops.KubeDNSConfig{
-					Provider: "CoreDNS",
-					NodeLocalDNS: &kops.NodeLocalDNSConfig{
-						Enabled: fi.Bool(true),
-					},
-				},
-			},
-			ExpectedErrors: []string{},
+			Input: kops.ContinuousValidationSpec{},
 		},
 		{
-			Input: kops.ClusterSpec{
-				Kubelet: &kops.KubeletConfigSpec{
-					ClusterDNS: "100.64.0.10",
-				},
-				KubeProxy: &kops.KubeProxyConfig{
-					ProxyMode: "ipvs",
-				},
-				KubeDNS: &kops.KubeDNSConfig{
-					Provider: "CoreDNS",
-					NodeLocalDNS: &kops.NodeLocalDNSConfig{
-						Enabled: fi.Bool(true),
-					},
-				},
+			Input: kops.ContinuousValidationSpec{
+				Interval: &metav1.Duration{Duration: time.Minute},
+				Checks:   []string{"node-conditions"},
 			},
-			ExpectedErrors: []string{"Forbidden::spec.kubelet.clusterDNS"},
 		},
 		{
-			Input: kops.ClusterSpec{
-				Kubelet: &kops.KubeletConfigSpec{
-					ClusterDNS: "100.64.0.10",
-				},
-				KubeProxy: &kops.KubeProxyConfig{
-					ProxyMode: "ipvs",
-				},
-				KubeDNS: &kops.KubeDNSConfig{
-					Provider: "CoreDNS",
-					NodeLocalDNS: &kops.NodeLocalDNSConfig{
-						Enabled: fi.Bool(true),
-					},
-				},
-				Networking: &kops.NetworkingSpec{
-					Cilium: &kops.CiliumNetworkingSpec{},
-				},
+			Input: kops.ContinuousValidationSpec{
+				Interval: &metav1.Duration{Duration: 0},
 			},
-			ExpectedErrors: []string{"Forbidden::spec.kubelet.clusterDNS"},
+			ExpectedErrors: []string{"Invalid value::testField.interval"},
 		},
 		{
-			Input: kops.ClusterSpec{
-				Kubelet: &kops.KubeletConfigSpec{
-					ClusterDNS: "169.254.20.10",
-				},
-				KubeProxy: &kops.KubeProxyConfig{
-					ProxyMode: "iptables",
-				},
-				KubeDNS: &kops.KubeDNSConfig{
-					Provider: "CoreDNS",
-					NodeLocalDNS: &kops.NodeLocalDNSConfig{
-						Enabled: fi.Bool(true),
-						LocalIP: "169.254.20.10",
-					},
-				},
-				Networking: &kops.NetworkingSpec{
-					Cilium: &kops.CiliumNetworkingSpec{},
-				},
+			Input: kops.ContinuousValidationSpec{
+				Checks: []string{"node-conditions", ""},
 			},
-			ExpectedErrors: []string{},
+			ExpectedErrors: []string{"Required value::testField.checks[1]"},
 		},
 	}
-
 	for _, g := range grid {
-		errs := validateNodeLocalDNS(&g.Input, field.NewPath("spec"))
+		errs := validateContinuousValidation(&g.Input, field.NewPath("testField"))
 		testErrors(t, g.Input, errs, g.ExpectedErrors)
 	}
 }
 
-func Test_Validate_CloudConfiguration(t *testing.T) {
+func Test_Validate_NTP(t *testing.T) {
 	grid := []struct {
-		Description    string
-		Input          kops.CloudConfiguration
+		Input          kops.NTPConfig
 		ExpectedErrors []string
 	}{
 		{
-			Description: "neither",
-			Input:       kops.CloudConfiguration{},
+			Input: kops.NTPConfig{},
 		},
 		{
-			Description: "all false",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(false),
+			Input: kops.NTPConfig{
+				Servers:                  []string{"time.example.com", "10.0.0.1"},
+				WaitForSync:              fi.Bool(true),
+				MetricsTextfileDirectory: "/var/lib/node_exporter/textfile_collector",
 			},
 		},
 		{
-			Description: "all true",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(true),
+			Input: kops.NTPConfig{
+				Servers: []string{"time.example.com", "", "time1 time2"},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.servers[1]",
+				"Invalid value::testField.servers[2]",
 			},
 		},
 		{
-			Description: "os false",
-			Input: kops.CloudConfiguration{
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(false),
-					},
-				}},
+			Input: kops.NTPConfig{
+				MetricsTextfileDirectory: "textfile_collector",
+			},
+			ExpectedErrors: []string{"Invalid value::testField.metricsTextfileDirectory"},
 		},
 		{
-			Description: "os true",
-			Input: kops.CloudConfiguration{
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(true),
-					},
-				}},
+			Input: kops.NTPConfig{
+				Managed:                  fi.Bool(false),
+				Servers:                  []string{"time.example.com"},
+				WaitForSync:              fi.Bool(true),
+				MetricsTextfileDirectory: "/var/lib/node_exporter/textfile_collector",
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField.servers",
+				"Forbidden::testField.waitForSync",
+				"Forbidden::testField.metricsTextfileDirectory",
+			},
 		},
+	}
+	for _, g := range grid {
+		errs := validateNTP(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_OSPatching(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
 		{
-			Description: "all false, os false",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(false),
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(false),
-					},
-				}},
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				OSPatching:    &kops.OSPatchingSpec{},
+			},
 		},
 		{
-			Description: "all false, os true",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(false),
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(true),
-					},
-				}},
-			ExpectedErrors: []string{"Forbidden::cloudConfig.manageStorageClasses"},
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				OSPatching: &kops.OSPatchingSpec{
+					Interval:       &metav1.Duration{Duration: 24 * time.Hour},
+					Method:         "SSM",
+					InstanceGroups: []string{"nodes"},
+				},
+			},
 		},
 		{
-			Description: "all true, os false",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(true),
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(false),
-					},
-				}},
-			ExpectedErrors: []string{"Forbidden::cloudConfig.manageStorageClasses"},
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				OSPatching: &kops.OSPatchingSpec{
+					Interval:       &metav1.Duration{Duration: 0},
+					Method:         "Ansible",
+					InstanceGroups: []string{""},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.interval",
+				"Unsupported value::testField.method",
+				"Required value::testField.instanceGroups[0]",
+			},
 		},
 		{
-			Description: "all true, os true",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(true),
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(true),
-					},
-				}},
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				OSPatching: &kops.OSPatchingSpec{
+					Method: "SSM",
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::testField.method"},
 		},
 	}
-
 	for _, g := range grid {
-		fldPath := field.NewPath("cloudConfig")
-		t.Run(g.Description, func(t *testing.T) {
-			errs := validateCloudConfiguration(&g.Input, fldPath)
-			testErrors(t, g.Input, errs, g.ExpectedErrors)
-		})
+		errs := validateOSPatching(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
 	}
 }
 
-func TestValidateSAExternalPermissions(t *testing.T) {
+func Test_Validate_EtcdReplicaStore(t *testing.T) {
 	grid := []struct {
-		Description    string
-		Input          []kops.ServiceAccountExternalPermission
+		Input          kops.EtcdClusterSpec
 		ExpectedErrors []string
 	}{
-
 		{
-			Description: "Duplicate SA",
-			Input: []kops.ServiceAccountExternalPermission{
-				{
-					Name:      "MySA",
-					Namespace: "MyNS",
-					AWS: &kops.AWSPermission{
-						PolicyARNs: []string{"-"},
-					},
+			Input: kops.EtcdClusterSpec{
+				Backups: &kops.EtcdBackupSpec{
+					BackupStore:  "s3://state-store/example.com/backups/etcd/main",
+					ReplicaStore: "s3://state-store-dr/example.com/backups/etcd/main",
 				},
-				{
-					Name:      "MySA",
-					Namespace: "MyNS",
-					AWS: &kops.AWSPermission{
-						PolicyARNs: []string{"-"},
-					},
+			},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Backups: &kops.EtcdBackupSpec{
+					BackupStore:  "s3://state-store/example.com/backups/etcd/main",
+					ReplicaStore: "s3://state-store/example.com/backups/etcd/main/replica",
 				},
 			},
-			ExpectedErrors: []string{"Duplicate value::iam.serviceAccountExternalPermissions[MyNS/MySA]"},
+			ExpectedErrors: []string{"Forbidden::testField"},
 		},
 		{
-			Description: "Missing permissions",
-			Input: []kops.ServiceAccountExternalPermission{
+			Input: kops.EtcdClusterSpec{
+				Provider: kops.EtcdProviderTypeLegacy,
+				Backups: &kops.EtcdBackupSpec{
+					BackupStore:  "s3://state-store/example.com/backups/etcd/main",
+					ReplicaStore: "unknown://state-store-dr/example.com/backups/etcd/main",
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField",
+				"Invalid value::testField",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateEtcdReplicaStore(g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_EBSDefaultEncryption(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				CloudConfig: &kops.CloudConfiguration{
+					EBSDefaultEncryption: &kops.EBSDefaultEncryptionSpec{
+						KmsKeyId: fi.String("arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+					},
+				},
+				EtcdClusters: []kops.EtcdClusterSpec{
+					{
+						Members: []kops.EtcdMemberSpec{
+							{EncryptedVolume: fi.Bool(true)},
+						},
+					},
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				CloudConfig: &kops.CloudConfiguration{
+					EBSDefaultEncryption: &kops.EBSDefaultEncryptionSpec{
+						KmsKeyId: fi.String(""),
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField",
+				"Required value::testField.kmsKeyId",
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				CloudConfig: &kops.CloudConfiguration{
+					EBSDefaultEncryption: &kops.EBSDefaultEncryptionSpec{
+						Enable: fi.Bool(true),
+					},
+				},
+				EtcdClusters: []kops.EtcdClusterSpec{
+					{
+						Members: []kops.EtcdMemberSpec{
+							{EncryptedVolume: fi.Bool(false)},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.etcdClusters[0].etcdMembers[0].encryptedVolume"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateEBSDefaultEncryption(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AWSEBSCSIDriver(t *testing.T) {
+	grid := []struct {
+		Input          kops.AWSEBSCSIDriver
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AWSEBSCSIDriver{
+				ExtraTags: map[string]string{
+					"team":   "platform",
+					"filter": "a=b",
+				},
+			},
+		},
+		{
+			Input: kops.AWSEBSCSIDriver{
+				ExtraTags: map[string]string{
+					"KubernetesCluster": "example.com",
+					"a,b":               "c",
+					"team":              "platform,storage",
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField.extraTags.KubernetesCluster",
+				"Invalid value::testField.extraTags",
+				"Invalid value::testField.extraTags[team]",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAWSEBSCSIDriver(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AWSEBSCSIDriverClusterTags(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				CloudLabels: map[string]string{
+					"team":   "platform",
+					"filter": "a=b",
+				},
+				CloudLabelPolicies: []kops.CloudLabelPolicy{
+					{
+						Labels:  map[string]string{"a,b": "c"},
+						Subnets: []string{"us-test-1a"},
+					},
+					{
+						Labels:        map[string]string{"d=e": "f"},
+						ResourceTypes: []kops.CloudResourceType{kops.CloudResourceTypeInstance},
+					},
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudLabels: map[string]string{
+					"a,b":  "c",
+					"team": "platform,storage",
+				},
+				CloudLabelPolicies: []kops.CloudLabelPolicy{
+					{
+						Labels:        map[string]string{"d=e": "f"},
+						ResourceTypes: []kops.CloudResourceType{kops.CloudResourceTypeVolume},
+					},
+					{
+						Labels: map[string]string{"g": "h,i"},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.cloudLabels",
+				"Invalid value::testField.cloudLabels[team]",
+				"Invalid value::testField.cloudLabelPolicies[0].labels",
+				"Invalid value::testField.cloudLabelPolicies[1].labels[g]",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAWSEBSCSIDriverClusterTags(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeLabelReconciliation(t *testing.T) {
+	grid := []struct {
+		Input          kops.NodeLabelReconciliationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.NodeLabelReconciliationSpec{},
+		},
+		{
+			Input: kops.NodeLabelReconciliationSpec{
+				Interval: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+		},
+		{
+			Input: kops.NodeLabelReconciliationSpec{
+				Interval: &metav1.Duration{Duration: -time.Minute},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.interval"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateNodeLabelReconciliation(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_MaintenanceWindow(t *testing.T) {
+	grid := []struct {
+		Input          kops.MaintenanceWindowSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.MaintenanceWindowSpec{
+				Schedule: "0 22 * * SAT",
+				Duration: &metav1.Duration{Duration: 4 * time.Hour},
+			},
+		},
+		{
+			Input: kops.MaintenanceWindowSpec{},
+			ExpectedErrors: []string{
+				"Required value::spec.maintenanceWindow.schedule",
+				"Required value::spec.maintenanceWindow.duration",
+			},
+		},
+		{
+			Input: kops.MaintenanceWindowSpec{
+				Schedule: "@weekly",
+				Duration: &metav1.Duration{},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.maintenanceWindow.schedule",
+				"Invalid value::spec.maintenanceWindow.duration",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateMaintenanceWindow(&g.Input, field.NewPath("spec", "maintenanceWindow"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_InstanceGroupDefaults(t *testing.T) {
+	grid := []struct {
+		Input          kops.InstanceGroupSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.InstanceGroupSpec{
+				MachineType: "m5.large",
+				CloudLabels: map[string]string{"team": "platform"},
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				Role:          kops.InstanceGroupRoleNode,
+				RollingUpdate: &kops.RollingUpdate{},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.instanceGroupDefaults.role",
+				"Forbidden::spec.instanceGroupDefaults.rollingUpdate",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateInstanceGroupDefaults(&g.Input, field.NewPath("spec", "instanceGroupDefaults"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Hibernation(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Hibernation: &kops.HibernationSpec{
+					NATGateways: true,
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				Hibernation:   &kops.HibernationSpec{},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.hibernation"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateHibernation(&g.Input, field.NewPath("spec", "hibernation"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_RBACBindings(t *testing.T) {
+	grid := []struct {
+		Input          []kops.RBACBindingSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.RBACBindingSpec{
+				{
+					Name:        "operators",
+					ClusterRole: "cluster-admin",
+					Groups:      []string{"oidc:platform"},
+				},
+				{
+					Name:        "developers",
+					ClusterRole: "edit",
+					Namespace:   "apps",
+					Users:       []string{"alice@example.com"},
+				},
+			},
+		},
+		{
+			Input: []kops.RBACBindingSpec{
+				{},
+			},
+			ExpectedErrors: []string{
+				"Required value::spec.authorization.rbac.bindings[0].name",
+				"Required value::spec.authorization.rbac.bindings[0].clusterRole",
+				"Required value::spec.authorization.rbac.bindings[0].groups",
+			},
+		},
+		{
+			Input: []kops.RBACBindingSpec{
+				{
+					Name:        "Operators",
+					ClusterRole: "view",
+					Namespace:   "kube_system",
+					Groups:      []string{""},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.authorization.rbac.bindings[0].name",
+				"Invalid value::spec.authorization.rbac.bindings[0].namespace",
+				"Required value::spec.authorization.rbac.bindings[0].groups[0]",
+			},
+		},
+		{
+			Input: []kops.RBACBindingSpec{
+				{
+					Name:        "operators",
+					ClusterRole: "view",
+					Groups:      []string{"ops"},
+				},
+				{
+					Name:        "operators",
+					ClusterRole: "edit",
+					Groups:      []string{"ops"},
+				},
+			},
+			ExpectedErrors: []string{"Duplicate value::spec.authorization.rbac.bindings[1].name"},
+		},
+		{
+			Input: []kops.RBACBindingSpec{
+				{
+					Name:        "operators",
+					ClusterRole: "system:aggregate-to-view",
+					Groups:      []string{"ops"},
+				},
+				{
+					Name:        "developers",
+					ClusterRole: "apps/edit",
+					Groups:      []string{"dev"},
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.authorization.rbac.bindings[1].clusterRole"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateRBACBindings(g.Input, field.NewPath("spec", "authorization", "rbac", "bindings"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_OIDCAuthentication(t *testing.T) {
+	grid := []struct {
+		Input          kops.OIDCAuthenticationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL: "https://dex.example.com",
+				Dex: &kops.DexSpec{
+					Connectors: "- type: github\n  id: github\n  name: GitHub\n",
+				},
+			},
+		},
+		{
+			Input:          kops.OIDCAuthenticationSpec{},
+			ExpectedErrors: []string{"Required value::spec.authentication.oidc.issuerURL"},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL: "http://dex.example.com",
+			},
+			ExpectedErrors: []string{"Invalid value::spec.authentication.oidc.issuerURL"},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL: "https://dex.example.com",
+				Dex: &kops.DexSpec{
+					Replicas:     fi.Int32(0),
+					RedirectURIs: []string{"localhost:8000"},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.authentication.oidc.dex.replicas",
+				"Required value::spec.authentication.oidc.dex.connectors",
+				"Invalid value::spec.authentication.oidc.dex.redirectURIs[0]",
+			},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL: "https://dex.example.com",
+				Dex: &kops.DexSpec{
+					Connectors: "type: github",
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.authentication.oidc.dex.connectors"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateOIDCAuthentication(&g.Input, field.NewPath("spec", "authentication", "oidc"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NetworkCIDRAllocation(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				NetworkCIDR:   "10.12.0.0/16",
+				NetworkCIDRAllocation: &kops.NetworkCIDRAllocationSpec{
+					Provider:     "AWS",
+					Pool:         "ipam-pool-0123",
+					AllocationID: "ipam-pool-alloc-4567",
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				NetworkCIDR:   "10.12.0.0/16",
+				NetworkCIDRAllocation: &kops.NetworkCIDRAllocationSpec{
+					Provider:     "Netbox",
+					Endpoint:     "https://netbox.example.com",
+					Pool:         "12",
+					AllocationID: "345",
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				NetworkCIDRAllocation: &kops.NetworkCIDRAllocationSpec{
+					Provider: "AWS",
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.networkCIDRAllocation.provider",
+				"Required value::spec.networkCIDRAllocation.pool",
+				"Required value::spec.networkCIDRAllocation.allocationID",
+				"Required value::spec.networkCIDR",
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				NetworkCIDR:   "10.12.0.0/16",
+				NetworkCIDRAllocation: &kops.NetworkCIDRAllocationSpec{
+					Provider:     "Infoblox",
+					Endpoint:     "infoblox.example.com",
+					Pool:         "10.0.0.0/8",
+					AllocationID: "network/ZG5z:10.12.0.0/16/default",
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.networkCIDRAllocation.endpoint"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				NetworkCIDR:   "10.12.0.0/16",
+				NetworkCIDRAllocation: &kops.NetworkCIDRAllocationSpec{
+					Provider:     "Phpipam",
+					Pool:         "1",
+					AllocationID: "2",
+				},
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.networkCIDRAllocation.provider"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateNetworkCIDRAllocation(&g.Input, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_EgressGateways(t *testing.T) {
+	cilium := func() *kops.NetworkingSpec {
+		return &kops.NetworkingSpec{
+			Cilium: &kops.CiliumNetworkingSpec{
+				Version:             "v1.10.3",
+				EnableNodePort:      true,
+				EnableBPFMasquerade: fi.Bool(true),
+			},
+		}
+	}
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Networking:    cilium(),
+				EgressGateways: []kops.EgressGatewaySpec{
+					{
+						Name:             "partner",
+						InstanceGroup:    "egress",
+						Namespaces:       []string{"payments"},
+						DestinationCIDRs: []string{"203.0.113.0/24"},
+					},
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				Networking:    &kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}},
+				EgressGateways: []kops.EgressGatewaySpec{
+					{Name: "partner", InstanceGroup: "egress", Namespaces: []string{"payments"}},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField",
+				"Forbidden::testField",
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Networking: &kops.NetworkingSpec{
+					Cilium: &kops.CiliumNetworkingSpec{Version: "v1.9.10"},
+				},
+				EgressGateways: []kops.EgressGatewaySpec{
+					{Name: "partner", InstanceGroup: "egress", Namespaces: []string{"payments"}},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField",
+				"Forbidden::testField",
+				"Forbidden::testField",
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Networking:    cilium(),
+				EgressGateways: []kops.EgressGatewaySpec{
+					{Name: "partner", InstanceGroup: "egress", Namespaces: []string{"payments"}},
+					{Name: "partner", Namespaces: []string{"Payments"}, DestinationCIDRs: []string{"203.0.113.1/24", "2001:db8::/32"}},
+					{Name: "Partner.2"},
+				},
+			},
+			ExpectedErrors: []string{
+				"Duplicate value::testField[1].name",
+				"Required value::testField[1].instanceGroup",
+				"Invalid value::testField[1].namespaces[0]",
+				"Invalid value::testField[1].destinationCIDRs[0]",
+				"Invalid value::testField[1].destinationCIDRs[1]",
+				"Invalid value::testField[2].name",
+				"Required value::testField[2].instanceGroup",
+				"Required value::testField[2].namespaces",
+			},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{Spec: g.Input}
+		errs := validateEgressGateways(cluster, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Descheduler(t *testing.T) {
+	grid := []struct {
+		Input          kops.DeschedulerConfig
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.DeschedulerConfig{
+				Enabled:  fi.Bool(true),
+				Schedule: fi.String("0 * * * *"),
+				Profiles: []string{"Balance", "Constraints"},
+				NodeUtilization: &kops.DeschedulerNodeUtilization{
+					Thresholds:       map[string]int32{"cpu": 20, "memory": 20},
+					TargetThresholds: map[string]int32{"cpu": 70, "memory": 70},
+				},
+			},
+		},
+		{
+			Input: kops.DeschedulerConfig{
+				Schedule: fi.String("@hourly"),
+				Profiles: []string{"Balance", "Spread", "Balance"},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.schedule",
+				"Unsupported value::testField.profiles[1]",
+				"Duplicate value::testField.profiles[2]",
+			},
+		},
+		{
+			Input: kops.DeschedulerConfig{
+				NodeUtilization: &kops.DeschedulerNodeUtilization{
+					Thresholds:       map[string]int32{"cpu": 60, "gpu": 10},
+					TargetThresholds: map[string]int32{"cpu": 50, "memory": 150},
+				},
+			},
+			ExpectedErrors: []string{
+				"Unsupported value::testField.nodeUtilization.thresholds[gpu]",
+				"Invalid value::testField.nodeUtilization.targetThresholds[memory]",
+				"Invalid value::testField.nodeUtilization.thresholds[cpu]",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateDescheduler(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AWSLoadBalancerController(t *testing.T) {
+	grid := []struct {
+		Input             kops.AWSLoadBalancerControllerConfig
+		KubernetesVersion string
+		ExpectedErrors    []string
+	}{
+		{
+			Input: kops.AWSLoadBalancerControllerConfig{
+				Enabled:             fi.Bool(true),
+				IngressClass:        fi.String("alb"),
+				DefaultIngressClass: fi.Bool(true),
+			},
+		},
+		{
+			Input: kops.AWSLoadBalancerControllerConfig{
+				Enabled:      fi.Bool(true),
+				IngressClass: fi.String("ALB"),
+			},
+			KubernetesVersion: "1.18.0",
+			ExpectedErrors: []string{
+				"Invalid value::testField.ingressClass",
+				"Forbidden::testField.ingressClass",
+			},
+		},
+		{
+			Input: kops.AWSLoadBalancerControllerConfig{
+				Enabled:             fi.Bool(true),
+				DefaultIngressClass: fi.Bool(true),
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField.defaultIngressClass",
+			},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: "1.21.0",
+				CertManager: &kops.CertManagerConfig{
+					Enabled: fi.Bool(true),
+				},
+			},
+		}
+		if g.KubernetesVersion != "" {
+			cluster.Spec.KubernetesVersion = g.KubernetesVersion
+		}
+		errs := validateAWSLoadBalancerController(cluster, &g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_VerticalPodAutoscaler(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				MetricsServer: &kops.MetricsServerConfig{Enabled: fi.Bool(true)},
+				VerticalPodAutoscaler: &kops.VerticalPodAutoscalerConfig{
+					Enabled:             fi.Bool(true),
+					CertificateValidity: &metav1.Duration{Duration: 24 * time.Hour},
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				VerticalPodAutoscaler: &kops.VerticalPodAutoscalerConfig{
+					Enabled: fi.Bool(false),
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				VerticalPodAutoscaler: &kops.VerticalPodAutoscalerConfig{
+					Enabled:             fi.Bool(true),
+					CertificateValidity: &metav1.Duration{Duration: time.Minute},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField.enabled",
+				"Invalid value::testField.certificateValidity",
+			},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{Spec: g.Input}
+		errs := validateVerticalPodAutoscaler(cluster, g.Input.VerticalPodAutoscaler, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Channel(t *testing.T) {
+	grid := []struct {
+		Input          string
+		ExpectedErrors []string
+	}{
+		{Input: ""},
+		{Input: "stable"},
+		{Input: "alpha@v1.22.0"},
+		{Input: "s3://my-bucket/channels/stable"},
+		{Input: "stable@", ExpectedErrors: []string{"Invalid value::testField"}},
+		{Input: "stable@v1.22.0/../evil", ExpectedErrors: []string{"Invalid value::testField"}},
+	}
+	for _, g := range grid {
+		errs := validateChannel(g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_SSH(t *testing.T) {
+	grid := []struct {
+		Input          kops.SSHSpec
+		Cloud          kops.CloudProviderID
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.SSHSpec{},
+			Cloud: kops.CloudProviderGCE,
+		},
+		{
+			Input: kops.SSHSpec{
+				User:            "ec2-user",
+				InstanceConnect: fi.Bool(true),
+			},
+			Cloud: kops.CloudProviderAWS,
+		},
+		{
+			Input: kops.SSHSpec{
+				User: "root",
+			},
+			Cloud:          kops.CloudProviderAWS,
+			ExpectedErrors: []string{"Forbidden::testField.user"},
+		},
+		{
+			Input: kops.SSHSpec{
+				User:            "Not a user",
+				InstanceConnect: fi.Bool(true),
+			},
+			Cloud: kops.CloudProviderOpenstack,
+			ExpectedErrors: []string{
+				"Invalid value::testField.user",
+				"Forbidden::testField.instanceConnect",
+			},
+		},
+		{
+			Input: kops.SSHSpec{
+				PublicKeys: []kops.SSHPublicKeySpec{
+					{Name: "team-a", PublicKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFpyraYd4rUFftiEKzUO4wKFAgTkXxuJcRZwVcsuZJ8G alice@example.com"},
+				},
+			},
+			Cloud: kops.CloudProviderAWS,
+		},
+		{
+			Input: kops.SSHSpec{
+				PublicKeys: []kops.SSHPublicKeySpec{
+					{Name: "team-a", PublicKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFpyraYd4rUFftiEKzUO4wKFAgTkXxuJcRZwVcsuZJ8G alice@example.com"},
+					{Name: "team-a", PublicKey: "not a key"},
+					{Name: "Team B"},
+				},
+			},
+			Cloud: kops.CloudProviderAWS,
+			ExpectedErrors: []string{
+				"Duplicate value::testField.publicKeys[1].name",
+				"Invalid value::testField.publicKeys[1].publicKey",
+				"Invalid value::testField.publicKeys[2].name",
+				"Required value::testField.publicKeys[2].publicKey",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateSSH(&g.Input, g.Cloud, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_SessionManager(t *testing.T) {
+	grid := []struct {
+		Input          kops.SessionManagerSpec
+		Cloud          kops.CloudProviderID
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.SessionManagerSpec{Enabled: fi.Bool(true)},
+			Cloud: kops.CloudProviderAWS,
+		},
+		{
+			Input:          kops.SessionManagerSpec{Enabled: fi.Bool(true)},
+			Cloud:          kops.CloudProviderGCE,
+			ExpectedErrors: []string{"Forbidden::testField.enabled"},
+		},
+		{
+			Input: kops.SessionManagerSpec{
+				Enabled: fi.Bool(true),
+				AgentPackages: []kops.SessionManagerAgentPackage{
+					{
+						Architecture: "amd64",
+						URL:          "https://s3.us-east-1.amazonaws.com/amazon-ssm-us-east-1/3.1.192.0/linux_amd64/amazon-ssm-agent.rpm",
+						Hash:         "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+					},
+				},
+			},
+			Cloud: kops.CloudProviderAWS,
+		},
+		{
+			Input: kops.SessionManagerSpec{
+				Enabled: fi.Bool(true),
+				AgentPackages: []kops.SessionManagerAgentPackage{
+					{
+						Architecture: "s390x",
+						URL:          "https://example.com/amazon-ssm-agent.tar.gz",
+						Hash:         "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+					},
+					{
+						Architecture: "arm64",
+						URL:          "https://example.com/amazon-ssm-agent.deb",
+					},
+				},
+			},
+			Cloud: kops.CloudProviderAWS,
+			ExpectedErrors: []string{
+				"Unsupported value::testField.agentPackages[0].architecture",
+				"Invalid value::testField.agentPackages[0].url",
+				"Invalid value::testField.agentPackages[0].hash",
+				"Required value::testField.agentPackages[1].hash",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateSessionManager(&g.Input, g.Cloud, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Events(t *testing.T) {
+	grid := []struct {
+		Input          kops.EventsSpec
+		Cloud          kops.CloudProviderID
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.EventsSpec{},
+			Cloud: kops.CloudProviderGCE,
+		},
+		{
+			Input: kops.EventsSpec{
+				SNSTopicARN:  "arn:aws:sns:us-east-1:123456789012:kops-events",
+				EventBusName: "kops-events",
+			},
+			Cloud: kops.CloudProviderAWS,
+		},
+		{
+			Input: kops.EventsSpec{
+				EventBusName: "arn:aws:events:us-east-1:123456789012:event-bus/kops-events",
+			},
+			Cloud: kops.CloudProviderAWS,
+		},
+		{
+			Input: kops.EventsSpec{
+				SNSTopicARN:  "arn:aws:sqs:us-east-1:123456789012:kops-events",
+				EventBusName: "arn:aws:events:us-east-1:123456789012:rule/kops-events",
+			},
+			Cloud: kops.CloudProviderAWS,
+			ExpectedErrors: []string{
+				"Invalid value::testField.snsTopicARN",
+				"Invalid value::testField.eventBusName",
+			},
+		},
+		{
+			Input: kops.EventsSpec{
+				EventBusName: "kops-events",
+			},
+			Cloud:          kops.CloudProviderGCE,
+			ExpectedErrors: []string{"Forbidden::testField"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateEvents(&g.Input, g.Cloud, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_CloudLabelPolicy(t *testing.T) {
+	grid := []struct {
+		Input          kops.CloudLabelPolicy
+		Cloud          kops.CloudProviderID
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.CloudLabelPolicy{
+				Labels:        map[string]string{"cost-center": "1234"},
+				ResourceTypes: []kops.CloudResourceType{kops.CloudResourceTypeInstance, kops.CloudResourceTypeVolume},
+			},
+			Cloud: kops.CloudProviderAWS,
+		},
+		{
+			Input: kops.CloudLabelPolicy{
+				Labels:  map[string]string{"team": "payments"},
+				Subnets: []string{"us-east-1a"},
+			},
+			Cloud: kops.CloudProviderAWS,
+		},
+		{
+			Input: kops.CloudLabelPolicy{
+				Labels:        map[string]string{"cost-center": "1234"},
+				ResourceTypes: []kops.CloudResourceType{"Bucket"},
+				Subnets:       []string{"us-east-1b"},
+			},
+			Cloud: kops.CloudProviderAWS,
+			ExpectedErrors: []string{
+				"Unsupported value::testField.resourceTypes[0]",
+				"Not found::testField.subnets[0]",
+			},
+		},
+		{
+			Input: kops.CloudLabelPolicy{
+				Labels: map[string]string{"KubernetesCluster": "other"},
+			},
+			Cloud: kops.CloudProviderAWS,
+			ExpectedErrors: []string{
+				"Forbidden::testField.labels.KubernetesCluster",
+				"Required value::testField",
+			},
+		},
+		{
+			Input: kops.CloudLabelPolicy{
+				ResourceTypes: []kops.CloudResourceType{kops.CloudResourceTypeInstance},
+			},
+			Cloud: kops.CloudProviderGCE,
+			ExpectedErrors: []string{
+				"Forbidden::testField",
+				"Required value::testField.labels",
+			},
+		},
+	}
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{
+			CloudProvider: string(g.Cloud),
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "us-east-1a"},
+			},
+		}
+		errs := validateCloudLabelPolicy(spec, &g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_BastionLoadBalancer(t *testing.T) {
+	grid := []struct {
+		Input          kops.BastionLoadBalancerSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.BastionLoadBalancerSpec{},
+		},
+		{
+			Input: kops.BastionLoadBalancerSpec{
+				Class:                    kops.LoadBalancerClassClassic,
+				AdditionalSecurityGroups: []string{"sg-1234"},
+			},
+		},
+		{
+			Input: kops.BastionLoadBalancerSpec{
+				Class: kops.LoadBalancerClassNetwork,
+			},
+		},
+		{
+			Input: kops.BastionLoadBalancerSpec{
+				Class: "Application",
+			},
+			ExpectedErrors: []string{"Unsupported value::testField.bastion.loadBalancer.class"},
+		},
+		{
+			Input: kops.BastionLoadBalancerSpec{
+				Class:                    kops.LoadBalancerClassNetwork,
+				AdditionalSecurityGroups: []string{"sg-1234"},
+			},
+			ExpectedErrors: []string{"Forbidden::testField.bastion.loadBalancer.additionalSecurityGroups"},
+		},
+	}
+	for _, g := range grid {
+		topology := &kops.TopologySpec{
+			Masters: kops.TopologyPrivate,
+			Nodes:   kops.TopologyPrivate,
+			Bastion: &kops.BastionSpec{
+				LoadBalancer: &g.Input,
+			},
+		}
+		errs := validateTopology(topology, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeRemediation(t *testing.T) {
+	grid := []struct {
+		Input          kops.NodeProblemDetectorConfig
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.NodeProblemDetectorConfig{
+				Remediation: &kops.NodeRemediationSpec{},
+			},
+		},
+		{
+			Input: kops.NodeProblemDetectorConfig{
+				Enabled: fi.Bool(true),
+				Remediation: &kops.NodeRemediationSpec{
+					Enabled:       fi.Bool(true),
+					Conditions:    []string{"KernelDeadlock", "ReadonlyFilesystem"},
+					Threshold:     &metav1.Duration{Duration: 10 * time.Minute},
+					MaxConcurrent: fi.Int32(1),
+				},
+			},
+		},
+		{
+			Input: kops.NodeProblemDetectorConfig{
+				Remediation: &kops.NodeRemediationSpec{
+					Enabled: fi.Bool(true),
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::testField.enabled"},
+		},
+		{
+			Input: kops.NodeProblemDetectorConfig{
+				Enabled: fi.Bool(true),
+				Remediation: &kops.NodeRemediationSpec{
+					Enabled:    fi.Bool(true),
+					Conditions: []string{"KernelDeadlock", "", "Ready", "KernelDeadlock"},
+				},
+			},
+			ExpectedErrors: []string{
+				"Required value::testField.conditions[1]",
+				"Forbidden::testField.conditions[2]",
+				"Duplicate value::testField.conditions[3]",
+			},
+		},
+		{
+			Input: kops.NodeProblemDetectorConfig{
+				Enabled: fi.Bool(true),
+				Remediation: &kops.NodeRemediationSpec{
+					Enabled:       fi.Bool(true),
+					Threshold:     &metav1.Duration{Duration: 0},
+					MaxConcurrent: fi.Int32(0),
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.threshold",
+				"Invalid value::testField.maxConcurrent",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateNodeRemediation(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_ImageVerification(t *testing.T) {
+	publicKey := `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEDVNR3+eyTCJD0K2pXYoRiip6028N
+Zo5Wi5fpJJNxaXHoz8QQ/b8/JDwvcDFRLH95XBMZdYp60+H+ISfY/lhLXQ==
+-----END PUBLIC KEY-----
+`
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	grid := []struct {
+		Input          kops.ImageVerificationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ImageVerificationSpec{
+				Action:           kops.ImageVerificationActionWarn,
+				Digests:          map[string]string{"k8s.gcr.io/kube-proxy:v1.21.0": digest},
+				CosignPublicKeys: []string{publicKey},
+			},
+		},
+		{
+			Input:          kops.ImageVerificationSpec{},
+			ExpectedErrors: []string{"Required value::testField"},
+		},
+		{
+			Input: kops.ImageVerificationSpec{
+				Action:           "Ignore",
+				CosignPublicKeys: []string{publicKey},
+			},
+			ExpectedErrors: []string{"Unsupported value::testField.action"},
+		},
+		{
+			Input: kops.ImageVerificationSpec{
+				Digests: map[string]string{"k8s.gcr.io/kube-proxy:v1.21.0": "sha256:abc"},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.digests[k8s.gcr.io/kube-proxy:v1.21.0]"},
+		},
+		{
+			Input: kops.ImageVerificationSpec{
+				CosignPublicKeys: []string{"not a key"},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.cosignPublicKeys[0]"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateImageVerification(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodePlugins(t *testing.T) {
+	hash := strings.Repeat("a", 64)
+
+	grid := []struct {
+		Input          []kops.NodePluginSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.NodePluginSpec{
+				{
+					Name:      "install-agent",
+					Stage:     kops.NodePluginStagePreInstall,
+					Artifacts: []kops.NodePluginArtifact{{Source: "https://example.com/agent.tar.gz", Hash: hash}},
+					Command:   []string{"/bin/sh", "-c", "tar -xzf agent.tar.gz"},
+					Timeout:   &metav1.Duration{Duration: time.Minute},
+					Retries:   fi.Int32(2),
+				},
+				{
+					Name:     "disabled",
+					Disabled: true,
+				},
+			},
+		},
+		{
+			Input: []kops.NodePluginSpec{
+				{Stage: kops.NodePluginStagePostJoin, Command: []string{"true"}},
+				{Name: "Invalid_Name", Stage: kops.NodePluginStagePostJoin, Command: []string{"true"}},
+			},
+			ExpectedErrors: []string{
+				"Required value::testField[0].name",
+				"Invalid value::testField[1].name",
+			},
+		},
+		{
+			Input: []kops.NodePluginSpec{
+				{Name: "plugin", Stage: kops.NodePluginStagePostKubelet, Command: []string{"true"}},
+				{Name: "plugin", Stage: kops.NodePluginStagePostKubelet, Command: []string{"true"}},
+			},
+			ExpectedErrors: []string{"Duplicate value::testField[1].name"},
+		},
+		{
+			Input: []kops.NodePluginSpec{
+				{Name: "plugin", Stage: "Whenever"},
+			},
+			ExpectedErrors: []string{
+				"Unsupported value::testField[0].stage",
+				"Required value::testField[0].command",
+			},
+		},
+		{
+			Input: []kops.NodePluginSpec{
+				{
+					Name:    "plugin",
+					Stage:   kops.NodePluginStagePostKubelet,
+					Command: []string{"true"},
+					Artifacts: []kops.NodePluginArtifact{
+						{Source: "s3://bucket/agent", Hash: hash},
+						{Source: "https://example.com/agent", Hash: "abc"},
+					},
+					Timeout: &metav1.Duration{},
+					Retries: fi.Int32(-1),
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField[0].artifacts[0].source",
+				"Invalid value::testField[0].artifacts[1].hash",
+				"Invalid value::testField[0].timeout",
+				"Invalid value::testField[0].retries",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateNodePlugins(g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeLocalDNS(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				KubeProxy: &kops.KubeProxyConfig{
+					ProxyMode: "iptables",
+				},
+				KubeDNS: &kops.KubeDNSConfig{
+					Provider: "CoreDNS",
+					NodeLocalDNS: &kops.NodeLocalDNSConfig{
+						Enabled: fi.Bool(true),
+					},
+				},
+			},
+			ExpectedErrors: []string{},
+		},
+		{
+			Input: kops.ClusterSpec{
+				Kubelet: &kops.KubeletConfigSpec{
+					ClusterDNS: "100.64.0.10",
+				},
+				KubeProxy: &kops.KubeProxyConfig{
+					ProxyMode: "ipvs",
+				},
+				KubeDNS: &kops.KubeDNSConfig{
+					Provider: "CoreDNS",
+					NodeLocalDNS: &kops.NodeLocalDNSConfig{
+						Enabled: fi.Bool(true),
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.kubelet.clusterDNS"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				Kubelet: &kops.KubeletConfigSpec{
+					ClusterDNS: "100.64.0.10",
+				},
+				KubeProxy: &kops.KubeProxyConfig{
+					ProxyMode: "ipvs",
+				},
+				KubeDNS: &kops.KubeDNSConfig{
+					Provider: "CoreDNS",
+					NodeLocalDNS: &kops.NodeLocalDNSConfig{
+						Enabled: fi.Bool(true),
+					},
+				},
+				Networking: &kops.NetworkingSpec{
+					Cilium: &kops.CiliumNetworkingSpec{},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.kubelet.clusterDNS"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				Kubelet: &kops.KubeletConfigSpec{
+					ClusterDNS: "169.254.20.10",
+				},
+				KubeProxy: &kops.KubeProxyConfig{
+					ProxyMode: "iptables",
+				},
+				KubeDNS: &kops.KubeDNSConfig{
+					Provider: "CoreDNS",
+					NodeLocalDNS: &kops.NodeLocalDNSConfig{
+						Enabled: fi.Bool(true),
+						LocalIP: "169.254.20.10",
+					},
+				},
+				Networking: &kops.NetworkingSpec{
+					Cilium: &kops.CiliumNetworkingSpec{},
+				},
+			},
+			ExpectedErrors: []string{},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateNodeLocalDNS(&g.Input, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_CloudConfiguration(t *testing.T) {
+	grid := []struct {
+		Description    string
+		Input          kops.CloudConfiguration
+		ExpectedErrors []string
+	}{
+		{
+			Description: "neither",
+			Input:       kops.CloudConfiguration{},
+		},
+		{
+			Description: "all false",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(false),
+			},
+		},
+		{
+			Description: "all true",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(true),
+			},
+		},
+		{
+			Description: "os false",
+			Input: kops.CloudConfiguration{
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(false),
+					},
+				}},
+		},
+		{
+			Description: "os true",
+			Input: kops.CloudConfiguration{
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(true),
+					},
+				}},
+		},
+		{
+			Description: "all false, os false",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(false),
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(false),
+					},
+				}},
+		},
+		{
+			Description: "all false, os true",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(false),
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(true),
+					},
+				}},
+			ExpectedErrors: []string{"Forbidden::cloudConfig.manageStorageClasses"},
+		},
+		{
+			Description: "all true, os false",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(true),
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(false),
+					},
+				}},
+			ExpectedErrors: []string{"Forbidden::cloudConfig.manageStorageClasses"},
+		},
+		{
+			Description: "all true, os true",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(true),
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(true),
+					},
+				}},
+		},
+	}
+
+	for _, g := range grid {
+		fldPath := field.NewPath("cloudConfig")
+		t.Run(g.Description, func(t *testing.T) {
+			errs := validateCloudConfiguration(&g.Input, fldPath)
+			testErrors(t, g.Input, errs, g.ExpectedErrors)
+		})
+	}
+}
+
+func Test_Validate_CloudControllerManager(t *testing.T) {
+	grid := []struct {
+		Description       string
+		CloudProvider     kops.CloudProviderID
+		KubernetesVersion string
+		Input             kops.CloudControllerManagerConfig
+		ExpectedErrors    []string
+	}{
+		{
+			Description:       "empty",
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.22.0",
+		},
+		{
+			Description:       "valid",
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.22.0",
+			Input: kops.CloudControllerManagerConfig{
+				ConcurrentServiceSyncs: fi.Int32(5),
+				FeatureGates:           map[string]string{"RemoveSelfLink": "false"},
+				EnableLeaderMigration:  fi.Bool(true),
+			},
+		},
+		{
+			Description:       "invalid concurrent service syncs",
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.22.0",
+			Input: kops.CloudControllerManagerConfig{
+				ConcurrentServiceSyncs: fi.Int32(0),
+			},
+			ExpectedErrors: []string{"Invalid value::cloudControllerManager.concurrentServiceSyncs"},
+		},
+		{
+			Description:       "invalid feature gate",
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.22.0",
+			Input: kops.CloudControllerManagerConfig{
+				FeatureGates: map[string]string{"RemoveSelfLink": "maybe"},
+			},
+			ExpectedErrors: []string{"Invalid value::cloudControllerManager.featureGates[RemoveSelfLink]"},
+		},
+		{
+			Description:       "leader migration before 1.21",
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.20.0",
+			Input: kops.CloudControllerManagerConfig{
+				EnableLeaderMigration: fi.Bool(true),
+			},
+			ExpectedErrors: []string{"Forbidden::cloudControllerManager.enableLeaderMigration"},
+		},
+		{
+			Description:       "leader migration disabled before 1.21",
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.20.0",
+			Input: kops.CloudControllerManagerConfig{
+				EnableLeaderMigration: fi.Bool(false),
+			},
+		},
+		{
+			Description:       "leader migration on digitalocean",
+			CloudProvider:     kops.CloudProviderDO,
+			KubernetesVersion: "1.22.0",
+			Input: kops.CloudControllerManagerConfig{
+				EnableLeaderMigration: fi.Bool(true),
+			},
+			ExpectedErrors: []string{"Forbidden::cloudControllerManager.enableLeaderMigration"},
+		},
+		{
+			Description:       "cluster name on aws",
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.22.0",
+			Input: kops.CloudControllerManagerConfig{
+				ClusterName: "cluster.example.com",
+			},
+		},
+		{
+			Description:       "other cluster name on aws",
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.22.0",
+			Input: kops.CloudControllerManagerConfig{
+				ClusterName: "other",
+			},
+			ExpectedErrors: []string{"Forbidden::cloudControllerManager.clusterName"},
+		},
+		{
+			Description:       "other cluster name on openstack",
+			CloudProvider:     kops.CloudProviderOpenstack,
+			KubernetesVersion: "1.22.0",
+			Input: kops.CloudControllerManagerConfig{
+				ClusterName: "other",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			cluster := &kops.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster.example.com"},
+				Spec: kops.ClusterSpec{
+					CloudProvider:     string(g.CloudProvider),
+					KubernetesVersion: g.KubernetesVersion,
+				},
+			}
+			errs := validateCloudControllerManager(&g.Input, cluster, field.NewPath("cloudControllerManager"))
+			testErrors(t, g.Input, errs, g.ExpectedErrors)
+		})
+	}
+}
+
+func Test_Validate_KubeScheduler(t *testing.T) {
+	grid := []struct {
+		Description       string
+		KubernetesVersion string
+		Input             kops.KubeSchedulerConfig
+		ExpectedErrors    []string
+	}{
+		{
+			Description:       "empty",
+			KubernetesVersion: "1.18.0",
+		},
+		{
+			Description:       "bin packing",
+			KubernetesVersion: "1.21.0",
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.KubeSchedulerProfile{
+					{
+						Plugins: &kops.KubeSchedulerPlugins{
+							Score: &kops.KubeSchedulerPluginSet{
+								Enabled:  []kops.KubeSchedulerPlugin{{Name: "NodeResourcesMostAllocated", Weight: fi.Int32(5)}},
+								Disabled: []kops.KubeSchedulerPlugin{{Name: "NodeResourcesLeastAllocated"}},
+							},
+						},
+					},
+					{
+						SchedulerName: "no-spread",
+						Plugins: &kops.KubeSchedulerPlugins{
+							PreScore: &kops.KubeSchedulerPluginSet{
+								Disabled: []kops.KubeSchedulerPlugin{{Name: "PodTopologySpread"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Description:       "before 1.19",
+			KubernetesVersion: "1.18.0",
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.KubeSchedulerProfile{{}},
+			},
+			ExpectedErrors: []string{"Forbidden::kubeScheduler.profiles"},
+		},
+		{
+			Description:       "with policy configmap",
+			KubernetesVersion: "1.21.0",
+			Input: kops.KubeSchedulerConfig{
+				UsePolicyConfigMap: fi.Bool(true),
+				Profiles:           []kops.KubeSchedulerProfile{{}},
+			},
+			ExpectedErrors: []string{"Forbidden::kubeScheduler.profiles"},
+		},
+		{
+			Description:       "duplicate scheduler name",
+			KubernetesVersion: "1.21.0",
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.KubeSchedulerProfile{{}, {SchedulerName: "default-scheduler"}},
+			},
+			ExpectedErrors: []string{"Duplicate value::kubeScheduler.profiles[1].schedulerName"},
+		},
+		{
+			Description:       "plugin without name",
+			KubernetesVersion: "1.21.0",
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.KubeSchedulerProfile{
+					{
+						Plugins: &kops.KubeSchedulerPlugins{
+							Filter: &kops.KubeSchedulerPluginSet{
+								Disabled: []kops.KubeSchedulerPlugin{{}},
+							},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Required value::kubeScheduler.profiles[0].plugins.filter.disabled[0].name"},
+		},
+		{
+			Description:       "duplicate plugin",
+			KubernetesVersion: "1.21.0",
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.KubeSchedulerProfile{
+					{
+						Plugins: &kops.KubeSchedulerPlugins{
+							Score: &kops.KubeSchedulerPluginSet{
+								Enabled: []kops.KubeSchedulerPlugin{{Name: "ImageLocality"}, {Name: "ImageLocality"}},
+							},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Duplicate value::kubeScheduler.profiles[0].plugins.score.enabled[1].name"},
+		},
+		{
+			Description:       "invalid weight",
+			KubernetesVersion: "1.21.0",
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.KubeSchedulerProfile{
+					{
+						Plugins: &kops.KubeSchedulerPlugins{
+							Score: &kops.KubeSchedulerPluginSet{
+								Enabled: []kops.KubeSchedulerPlugin{{Name: "ImageLocality", Weight: fi.Int32(0)}},
+							},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::kubeScheduler.profiles[0].plugins.score.enabled[0].weight"},
+		},
+		{
+			Description:       "weight outside score",
+			KubernetesVersion: "1.21.0",
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.KubeSchedulerProfile{
+					{
+						Plugins: &kops.KubeSchedulerPlugins{
+							Filter: &kops.KubeSchedulerPluginSet{
+								Enabled: []kops.KubeSchedulerPlugin{{Name: "NodePorts", Weight: fi.Int32(2)}},
+							},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::kubeScheduler.profiles[0].plugins.filter.enabled[0].weight"},
+		},
+		{
+			Description:       "plugin config",
+			KubernetesVersion: "1.22.0",
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.KubeSchedulerProfile{
+					{
+						PluginConfig: []kops.KubeSchedulerPluginConfig{
+							{
+								Name: "NodeResourcesFit",
+								Args: &runtime.RawExtension{Raw: []byte(`{"scoringStrategy":{"type":"MostAllocated"}}`)},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Description:       "invalid plugin config",
+			KubernetesVersion: "1.22.0",
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.KubeSchedulerProfile{
+					{
+						PluginConfig: []kops.KubeSchedulerPluginConfig{
+							{
+								Name: "NodeResourcesFit",
+								Args: &runtime.RawExtension{Raw: []byte(`["MostAllocated"]`)},
+							},
+							{
+								Name: "NodeResourcesFit",
+							},
+							{
+								Args: &runtime.RawExtension{Raw: []byte(`{}`)},
+							},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::kubeScheduler.profiles[0].pluginConfig[0].args",
+				"Duplicate value::kubeScheduler.profiles[0].pluginConfig[1].name",
+				"Required value::kubeScheduler.profiles[0].pluginConfig[1].args",
+				"Required value::kubeScheduler.profiles[0].pluginConfig[2].name",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			cluster := &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					KubernetesVersion: g.KubernetesVersion,
+				},
+			}
+			errs := validateKubeScheduler(&g.Input, cluster, field.NewPath("kubeScheduler"))
+			testErrors(t, g.Input, errs, g.ExpectedErrors)
+		})
+	}
+}
+
+func Test_Validate_FeatureGates(t *testing.T) {
+	grid := []struct {
+		Description       string
+		KubernetesVersion string
+		FeatureGates      map[string]bool
+		KubeletGates      map[string]string
+		KubeProxyGates    map[string]string
+		GroupKubeletGates map[string]string
+		ExpectedErrors    []string
+		ExpectedWarnings  []string
+	}{
+		{
+			Description:       "none",
+			KubernetesVersion: "1.22.0",
+		},
+		{
+			Description:       "available",
+			KubernetesVersion: "1.22.0",
+			FeatureGates:      map[string]bool{"GracefulNodeShutdown": true, "AllAlpha": false},
+		},
+		{
+			Description:       "graduated",
+			KubernetesVersion: "1.22.0",
+			FeatureGates:      map[string]bool{"ServerSideApply": true},
+			ExpectedWarnings:  []string{`spec.featureGates[ServerSideApply]: feature gate "ServerSideApply" graduated in Kubernetes 1.22 and is removed in Kubernetes 1.32`},
+		},
+		{
+			Description:       "removed in a later version",
+			KubernetesVersion: "1.22.0",
+			FeatureGates:      map[string]bool{"PodSecurity": true},
+			ExpectedWarnings:  []string{`spec.featureGates[PodSecurity]: feature gate "PodSecurity" is removed in Kubernetes 1.28`},
+		},
+		{
+			Description:       "unknown",
+			KubernetesVersion: "1.22.0",
+			FeatureGates:      map[string]bool{"Accelerators": true},
+			ExpectedWarnings:  []string{`spec.featureGates[Accelerators]: feature gate "Accelerators" is not known to kOps, check that it exists in Kubernetes 1.22.0`},
+		},
+		{
+			Description:       "not yet added",
+			KubernetesVersion: "1.21.5",
+			FeatureGates:      map[string]bool{"NodeSwap": true},
+			ExpectedErrors:    []string{"Invalid value::spec.featureGates[NodeSwap]"},
+		},
+		{
+			Description:       "removed",
+			KubernetesVersion: "1.19.0",
+			FeatureGates:      map[string]bool{"DynamicAuditing": true},
+			ExpectedErrors:    []string{"Invalid value::spec.featureGates[DynamicAuditing]"},
+		},
+		{
+			Description:       "removed after graduating",
+			KubernetesVersion: "1.21.0",
+			FeatureGates:      map[string]bool{"CSINodeInfo": true},
+			ExpectedErrors:    []string{"Invalid value::spec.featureGates[CSINodeInfo]"},
+		},
+		{
+			Description:       "unknown component gate",
+			KubernetesVersion: "1.22.0",
+			KubeletGates:      map[string]string{"Accelerators": "true"},
+			ExpectedWarnings: []string{
+				`spec.kubelet.featureGates[Accelerators]: setting feature gates on a single component is deprecated, set spec.featureGates[Accelerators] instead`,
+				`spec.kubelet.featureGates[Accelerators]: feature gate "Accelerators" is not known to kOps, check that it exists in Kubernetes 1.22.0`,
+			},
+		},
+		{
+			Description:       "invalid component gate",
+			KubernetesVersion: "1.22.0",
+			KubeletGates:      map[string]string{"NodeSwap": "yes please"},
+			ExpectedErrors:    []string{"Invalid value::spec.kubelet.featureGates[NodeSwap]"},
+			ExpectedWarnings:  []string{`spec.kubelet.featureGates[NodeSwap]: setting feature gates on a single component is deprecated, set spec.featureGates[NodeSwap] instead`},
+		},
+		{
+			Description:       "component gate copied from the cluster",
+			KubernetesVersion: "1.22.0",
+			FeatureGates:      map[string]bool{"TopologyAwareHints": true},
+			KubeProxyGates:    map[string]string{"TopologyAwareHints": "true"},
+		},
+		{
+			Description:       "component gate overriding the cluster",
+			KubernetesVersion: "1.22.0",
+			FeatureGates:      map[string]bool{"TopologyAwareHints": true},
+			KubeProxyGates:    map[string]string{"TopologyAwareHints": "false"},
+			ExpectedWarnings:  []string{`spec.kubeProxy.featureGates[TopologyAwareHints]: overrides spec.featureGates[TopologyAwareHints], setting feature gates on a single component is deprecated`},
+		},
+		{
+			Description:       "instance group gate",
+			KubernetesVersion: "1.22.0",
+			GroupKubeletGates: map[string]string{"NodeSwap": "true", "DynamicAuditing": "true"},
+			ExpectedWarnings:  []string{`InstanceGroup "nodes" spec.kubelet.featureGates[DynamicAuditing]: feature gate was removed in Kubernetes 1.19, and may prevent the component from starting on Kubernetes 1.22.0`},
+		},
+		{
+			Description:       "invalid instance group gate",
+			KubernetesVersion: "1.22.0",
+			GroupKubeletGates: map[string]string{"NodeSwap": "on"},
+			ExpectedErrors:    []string{"Invalid value::spec.kubelet.featureGates[NodeSwap]"},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			cluster := &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					KubernetesVersion: g.KubernetesVersion,
+					FeatureGates:      g.FeatureGates,
+					Kubelet: &kops.KubeletConfigSpec{
+						FeatureGates: g.KubeletGates,
+					},
+					KubeProxy: &kops.KubeProxyConfig{
+						FeatureGates: g.KubeProxyGates,
+					},
+				},
+			}
+			groups := []*kops.InstanceGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "nodes",
+					},
+					Spec: kops.InstanceGroupSpec{
+						Kubelet: &kops.KubeletConfigSpec{
+							FeatureGates: g.GroupKubeletGates,
+						},
+					},
+				},
+			}
+			fldPath := field.NewPath("spec")
+			errs := validateFeatureGates(cluster.Spec.FeatureGates, cluster, fldPath.Child("featureGates"))
+			errs = append(errs, validateComponentFeatureGates(cluster.Spec.Kubelet.FeatureGates, fldPath.Child("kubelet", "featureGates"))...)
+			errs = append(errs, validateComponentFeatureGates(cluster.Spec.KubeProxy.FeatureGates, fldPath.Child("kubeProxy", "featureGates"))...)
+			errs = append(errs, validateComponentFeatureGates(groups[0].Spec.Kubelet.FeatureGates, fldPath.Child("kubelet", "featureGates"))...)
+			testErrors(t, g.Description, errs, g.ExpectedErrors)
+
+			warnings := FeatureGateWarnings(cluster, groups)
+			if !reflect.DeepEqual(warnings, g.ExpectedWarnings) {
+				t.Errorf("expected warnings %q, got %q", g.ExpectedWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestValidateSAExternalPermissions(t *testing.T) {
+	grid := []struct {
+		Description    string
+		Input          []kops.ServiceAccountExternalPermission
+		ExpectedErrors []string
+	}{
+
+		{
+			Description: "Duplicate SA",
+			Input: []kops.ServiceAccountExternalPermission{
+				{
+					Name:      "MySA",
+					Namespace: "MyNS",
+					AWS: &kops.AWSPermission{
+						PolicyARNs: []string{"-"},
+					},
+				},
+				{
+					Name:      "MySA",
+					Namespace: "MyNS",
+					AWS: &kops.AWSPermission{
+						PolicyARNs: []string{"-"},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Duplicate value::iam.serviceAccountExternalPermissions[MyNS/MySA]"},
+		},
+		{
+			Description: "Missing permissions",
+			Input: []kops.ServiceAccountExternalPermission{
 				{
 					Name:      "MySA",
 					Namespace: "MyNS",
@@ -1311,3 +3242,66 @@ func TestValidateSAExternalPermissions(t *testing.T) {
 	}
 
 }
+
+func Test_Validate_PodSecurityAdmission(t *testing.T) {
+	grid := []struct {
+		KubernetesVersion          string
+		AdmissionControlConfigFile string
+		Input                      kops.PodSecurityAdmissionSpec
+		ExpectedErrors             []string
+	}{
+		{
+			KubernetesVersion: "1.22.0",
+			Input: kops.PodSecurityAdmissionSpec{
+				Enforce:          "baseline",
+				Warn:             "restricted",
+				Version:          "v1.22",
+				ExemptNamespaces: []string{"kube-system", "monitoring"},
+			},
+		},
+		{
+			KubernetesVersion: "1.23.0",
+			Input: kops.PodSecurityAdmissionSpec{
+				Audit:   "restricted",
+				Version: "latest",
+			},
+		},
+		{
+			KubernetesVersion: "1.21.0",
+			Input:             kops.PodSecurityAdmissionSpec{},
+			ExpectedErrors:    []string{"Forbidden::testField"},
+		},
+		{
+			KubernetesVersion: "1.22.0",
+			Input: kops.PodSecurityAdmissionSpec{
+				Enforce:          "strict",
+				Version:          "1.22",
+				ExemptNamespaces: []string{"Kube_System"},
+			},
+			ExpectedErrors: []string{
+				"Unsupported value::testField.enforce",
+				"Invalid value::testField.version",
+				"Invalid value::testField.exemptNamespaces[0]",
+			},
+		},
+		{
+			KubernetesVersion:          "1.22.0",
+			AdmissionControlConfigFile: "/srv/kubernetes/kube-apiserver/admission-configuration.yaml",
+			Input:                      kops.PodSecurityAdmissionSpec{Enforce: "baseline"},
+		},
+		{
+			KubernetesVersion:          "1.22.0",
+			AdmissionControlConfigFile: "/etc/kubernetes/admission.yaml",
+			Input:                      kops.PodSecurityAdmissionSpec{Enforce: "baseline"},
+			ExpectedErrors:             []string{"Forbidden::testField"},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{Spec: kops.ClusterSpec{
+			KubernetesVersion: g.KubernetesVersion,
+			KubeAPIServer:     &kops.KubeAPIServerConfig{AdmissionControlConfigFile: g.AdmissionControlConfigFile},
+		}}
+		errs := validatePodSecurityAdmission(cluster, &g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
@@ -16,8 +16,22 @@ limitations under the License.
 
 package nodeup
 
+import "time"
+
 const BootstrapAPIVersion = "bootstrap.kops.k8s.io/v1alpha1"
 
+// Boot milestones are the points a node reaches while it starts, in the order they are normally reached.
+const (
+	// BootMilestoneInstanceLaunched is when the cloud provider launched the instance.
+	BootMilestoneInstanceLaunched = "instance-launched"
+	// BootMilestoneContainerRuntimeReady is when the container runtime service started.
+	BootMilestoneContainerRuntimeReady = "container-runtime-ready"
+	// BootMilestoneKubeletRegistered is when the kubelet registered the Node object.
+	BootMilestoneKubeletRegistered = "kubelet-registered"
+	// BootMilestoneNodeReady is when the node first became Ready.
+	BootMilestoneNodeReady = "node-ready"
+)
+
 // BootstrapRequest is a request from nodeup to kops-controller for bootstrapping a node.
 type BootstrapRequest struct {
 	// APIVersion defines the versioned schema of this representation of a request.
@@ -56,3 +70,11 @@ type NodeConfigCertificate struct {
 	// Cert is the certificate data.
 	Cert string `json:"cert,omitempty"`
 }
+
+// BootMilestonesRequest is a report from nodeup to kops-controller of the boot milestones the node reached.
+type BootMilestonesRequest struct {
+	// APIVersion defines the versioned schema of this representation of a request.
+	APIVersion string `json:"apiVersion"`
+	// Milestones are the times the milestones were reached, keyed by milestone.
+	Milestones map[string]time.Time `json:"milestones"`
+}
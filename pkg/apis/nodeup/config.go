@@ -19,7 +19,9 @@ package nodeup
 import (
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/nodelabels"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/architectures"
@@ -37,10 +39,15 @@ type Config struct {
 	ClusterName string `json:",omitempty"`
 	// Channels is a list of channels that we should apply
 	Channels []string `json:"channels,omitempty"`
+	// GossipSeeds are the addresses of the control plane machines that protokube contacts to join the gossip network,
+	// on clouds where it cannot discover them.
+	GossipSeeds []string `json:"gossipSeeds,omitempty"`
 	// ApiserverAdditionalIPs are additional IP address to put in the apiserver server cert.
 	ApiserverAdditionalIPs []string `json:",omitempty"`
 	// WarmPoolImages are the container images to pre-pull during instance pre-initialization
 	WarmPoolImages []string `json:"warmPoolImages,omitempty"`
+	// ImageVerification holds the container images to verify against the cluster's trust policy before they are run.
+	ImageVerification *ImageVerificationConfig `json:"imageVerification,omitempty"`
 
 	// Manifests for running etcd
 	EtcdManifests []string `json:"etcdManifests,omitempty"`
@@ -62,6 +69,20 @@ type Config struct {
 	// specified, each parameter must follow the form variable=value, the way
 	// it would appear in sysctl.conf.
 	SysctlParameters []string `json:",omitempty"`
+	// Sysctls are the kernel parameters from the instance group spec, keyed by name.
+	Sysctls map[string]string `json:",omitempty"`
+	// KernelModules are the kernel modules to load at boot.
+	KernelModules []string `json:",omitempty"`
+	// Swap configures swap on the instance.
+	Swap *kops.SwapSpec `json:",omitempty"`
+	// Logging configures the rotation and retention of the logs on the instance.
+	Logging *kops.InstanceGroupLoggingSpec `json:",omitempty"`
+	// InstanceStorage configures the mounting of the instance store volumes.
+	InstanceStorage *kops.InstanceStorageSpec `json:",omitempty"`
+	// SELinux configures SELinux on the instance.
+	SELinux *kops.SELinuxSpec `json:",omitempty"`
+	// AppArmor configures AppArmor on the instance.
+	AppArmor *kops.AppArmorSpec `json:",omitempty"`
 	// UpdatePolicy determines the policy for applying upgrades automatically.
 	UpdatePolicy string
 	// VolumeMounts are a collection of volume mounts.
@@ -71,13 +92,30 @@ type Config struct {
 	FileAssets []kops.FileAssetSpec `json:",omitempty"`
 	// Hooks are for custom actions, for example on first installation.
 	Hooks [][]kops.HookSpec
+	// NodePlugins are the nodeup plugins to run, with their artifacts remapped to the locations they are fetched from.
+	NodePlugins []kops.NodePluginSpec `json:"nodePlugins,omitempty"`
 	// ContainerdConfig config holds the configuration for containerd
 	ContainerdConfig string `json:"containerdConfig,omitempty"`
 
 	// APIServerConfig is additional configuration for nodes running an APIServer.
 	APIServerConfig *APIServerConfig `json:",omitempty"`
+
+	// SSH is the configuration of SSH access to the instance, beyond the primary SSH public key.
+	SSH *SSHConfig `json:"ssh,omitempty"`
 }
 
+const (
+	// LogVolumePath is where the log volume of the instance group is mounted
+	LogVolumePath = "/var/log"
+	// ContainerdVolumePath is where the containerd state volume of the instance group is mounted
+	ContainerdVolumePath = "/var/lib/containerd"
+	// KubeletVolumePath is where the kubelet state volume of the instance group is mounted
+	KubeletVolumePath = "/var/lib/kubelet"
+)
+
+// swapConfigDropIn is the name of the kubelet config drop-in setting the swap behavior
+const swapConfigDropIn = "00-swap"
+
 // BootConfig is the configuration for the nodeup binary that might be too big to fit in userdata.
 type BootConfig struct {
 	// CloudProvider is the cloud provider in use.
@@ -111,6 +149,30 @@ type Image struct {
 	Hash string `json:"hash,omitempty"`
 }
 
+// ImageVerificationConfig is the configuration for verifying container images.
+type ImageVerificationConfig struct {
+	// Images are the images to verify, as referenced by the manifests that run them.
+	Images []string `json:"images,omitempty"`
+	// Digests are the digests images are pinned to, keyed by image.
+	Digests map[string]string `json:"digests,omitempty"`
+	// CosignPublicKeys are the PEM encoded keys, one of which must have signed each image.
+	CosignPublicKeys []string `json:"cosignPublicKeys,omitempty"`
+	// Action is the action taken when an image fails verification.
+	Action kops.ImageVerificationAction `json:"action,omitempty"`
+}
+
+// SSHConfig is the configuration of SSH access to the instance.
+type SSHConfig struct {
+	// User is the user that the AuthorizedKeys are authorized for; empty for the default user of the image.
+	User string `json:"user,omitempty"`
+	// AuthorizedKeys are the SSH public keys to authorize.
+	AuthorizedKeys []string `json:"authorizedKeys,omitempty"`
+	// TrustedUserCAKeys are the public keys of the certificate authorities trusted to sign user certificates.
+	TrustedUserCAKeys []string `json:"trustedUserCAKeys,omitempty"`
+	// InstanceConnect is true if EC2 Instance Connect should be installed.
+	InstanceConnect bool `json:"instanceConnect,omitempty"`
+}
+
 // StaticManifest is a generic static manifest
 type StaticManifest struct {
 	// Key identifies the static manifest
@@ -143,9 +205,15 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		CAs:              map[string]string{},
 		KeypairIDs:       map[string]string{},
 		SysctlParameters: instanceGroup.Spec.SysctlParameters,
+		Sysctls:          instanceGroup.Spec.Sysctls,
+		KernelModules:    instanceGroup.Spec.KernelModules,
+		SELinux:          instanceGroup.Spec.SELinux,
+		AppArmor:         instanceGroup.Spec.AppArmor,
+		InstanceStorage:  instanceGroup.Spec.InstanceStorage,
 		VolumeMounts:     instanceGroup.Spec.VolumeMounts,
 		FileAssets:       append(filterFileAssets(instanceGroup.Spec.FileAssets, role), filterFileAssets(cluster.Spec.FileAssets, role)...),
 		Hooks:            [][]kops.HookSpec{igHooks, clusterHooks},
+		NodePlugins:      filterNodePlugins(instanceGroup.Spec.NodePlugins, cluster.Spec.NodePlugins, role),
 	}
 
 	bootConfig := BootConfig{
@@ -186,11 +254,72 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		}
 	}
 
-	// We include the NodeLabels in the userdata even for Kubernetes 1.16 and later so that
-	// rolling update will still replace nodes when they change.
-	config.KubeletConfig.NodeLabels = nodelabels.BuildNodeLabels(cluster, instanceGroup)
+	if role == kops.InstanceGroupRoleHybrid {
+		// Hybrid machines are not instances of the cloud provider.
+		config.KubeletConfig.CloudProvider = ""
+	}
+
+	if model.UseNodeLabelReconciliation(cluster, instanceGroup) {
+		// kops-controller applies the node labels and taints of the instance group, so changing them
+		// must not replace the nodes. The nodes register unschedulable until kops-controller has applied them.
+		config.KubeletConfig.Taints = append(config.KubeletConfig.Taints, nodelabels.TaintInstanceGroupPending+"=:"+string(v1.TaintEffectNoSchedule))
+	} else {
+		// We include the NodeLabels in the userdata even for Kubernetes 1.16 and later so that
+		// rolling update will still replace nodes when they change.
+		config.KubeletConfig.NodeLabels = nodelabels.BuildNodeLabels(cluster, instanceGroup)
+
+		config.KubeletConfig.Taints = append(config.KubeletConfig.Taints, instanceGroup.Spec.Taints...)
+	}
+
+	if swap := instanceGroup.Spec.Swap; swap != nil && swap.Size != nil && !swap.Size.IsZero() {
+		config.Swap = swap
+
+		// The kubelet refuses to start on a node with swap, unless NodeSwap is enabled and failSwapOn is false
+		config.KubeletConfig.FailSwapOn = fi.Bool(false)
+		if config.KubeletConfig.FeatureGates == nil {
+			config.KubeletConfig.FeatureGates = map[string]string{}
+		}
+		if _, found := config.KubeletConfig.FeatureGates["NodeSwap"]; !found {
+			config.KubeletConfig.FeatureGates["NodeSwap"] = "true"
+		}
+		if swap.SwapBehavior != "" {
+			if config.KubeletConfig.ConfigDropIns == nil {
+				config.KubeletConfig.ConfigDropIns = map[string]string{}
+			}
+			config.KubeletConfig.ConfigDropIns[swapConfigDropIn] = "memorySwap:\n  swapBehavior: " + swap.SwapBehavior + "\n"
+		}
+	}
+
+	if logging := instanceGroup.Spec.Logging; logging != nil {
+		config.Logging = logging
 
-	config.KubeletConfig.Taints = append(config.KubeletConfig.Taints, instanceGroup.Spec.Taints...)
+		if logging.Volume != nil {
+			config.VolumeMounts = append(append([]kops.VolumeMountSpec{}, config.VolumeMounts...), kops.VolumeMountSpec{
+				Device:     logging.Volume.Device,
+				Filesystem: "ext4",
+				Path:       LogVolumePath,
+			})
+		}
+	}
+
+	if stateVolumes := instanceGroup.Spec.StateVolumes; stateVolumes != nil {
+		volumeMounts := append([]kops.VolumeMountSpec{}, config.VolumeMounts...)
+		if stateVolumes.Containerd != nil {
+			volumeMounts = append(volumeMounts, kops.VolumeMountSpec{
+				Device:     stateVolumes.Containerd.Device,
+				Filesystem: "ext4",
+				Path:       ContainerdVolumePath,
+			})
+		}
+		if stateVolumes.Kubelet != nil {
+			volumeMounts = append(volumeMounts, kops.VolumeMountSpec{
+				Device:     stateVolumes.Kubelet.Device,
+				Filesystem: "ext4",
+				Path:       KubeletVolumePath,
+			})
+		}
+		config.VolumeMounts = volumeMounts
+	}
 
 	if instanceGroup.Spec.UpdatePolicy != nil {
 		config.UpdatePolicy = *instanceGroup.Spec.UpdatePolicy
@@ -231,6 +360,26 @@ func filterHooks(h []kops.HookSpec, role kops.InstanceGroupRole) []kops.HookSpec
 	return hooks
 }
 
+// filterNodePlugins returns the enabled plugins for the role, with instance group plugins
+// overriding the cluster plugins of the same name.
+func filterNodePlugins(igPlugins []kops.NodePluginSpec, clusterPlugins []kops.NodePluginSpec, role kops.InstanceGroupRole) []kops.NodePluginSpec {
+	var plugins []kops.NodePluginSpec
+	names := make(map[string]bool)
+	for _, plugin := range append(append([]kops.NodePluginSpec{}, igPlugins...), clusterPlugins...) {
+		if names[plugin.Name] {
+			continue
+		}
+		names[plugin.Name] = true
+
+		if plugin.Disabled || (len(plugin.Roles) > 0 && !containsRole(role, plugin.Roles)) {
+			continue
+		}
+		plugin.Roles = nil
+		plugins = append(plugins, plugin)
+	}
+	return plugins
+}
+
 func containsRole(v kops.InstanceGroupRole, list []kops.InstanceGroupRole) bool {
 	for _, x := range list {
 		if v == x {
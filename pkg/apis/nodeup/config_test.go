@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestFilterNodePlugins(t *testing.T) {
+	clusterPlugins := []kops.NodePluginSpec{
+		{Name: "agent", Stage: kops.NodePluginStagePreInstall, Command: []string{"cluster-agent"}},
+		{Name: "masters-only", Stage: kops.NodePluginStagePostKubelet, Roles: []kops.InstanceGroupRole{kops.InstanceGroupRoleMaster}},
+		{Name: "label", Stage: kops.NodePluginStagePostJoin, Roles: []kops.InstanceGroupRole{kops.InstanceGroupRoleNode}},
+		{Name: "disabled-by-ig", Stage: kops.NodePluginStagePostJoin},
+	}
+	igPlugins := []kops.NodePluginSpec{
+		{Name: "agent", Stage: kops.NodePluginStagePreInstall, Command: []string{"ig-agent"}},
+		{Name: "disabled-by-ig", Disabled: true},
+	}
+
+	actual := filterNodePlugins(igPlugins, clusterPlugins, kops.InstanceGroupRoleNode)
+	expected := []kops.NodePluginSpec{
+		{Name: "agent", Stage: kops.NodePluginStagePreInstall, Command: []string{"ig-agent"}},
+		{Name: "label", Stage: kops.NodePluginStagePostJoin},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected plugins, expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestNewConfigSwap(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			Kubelet: &kops.KubeletConfigSpec{
+				FeatureGates: map[string]string{"CSIMigration": "true"},
+			},
+		},
+	}
+	ig := &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			Role: kops.InstanceGroupRoleNode,
+			Swap: &kops.SwapSpec{
+				Size:         resource.NewQuantity(1024*1024*1024, resource.BinarySI),
+				SwapBehavior: "LimitedSwap",
+			},
+		},
+	}
+
+	config, _ := NewConfig(cluster, ig)
+
+	if config.Swap == nil {
+		t.Fatalf("expected swap to be set")
+	}
+	if config.KubeletConfig.FailSwapOn == nil || *config.KubeletConfig.FailSwapOn {
+		t.Errorf("expected failSwapOn to be false, was %v", config.KubeletConfig.FailSwapOn)
+	}
+	expectedGates := map[string]string{"CSIMigration": "true", "NodeSwap": "true"}
+	if !reflect.DeepEqual(config.KubeletConfig.FeatureGates, expectedGates) {
+		t.Errorf("unexpected feature gates: %v", config.KubeletConfig.FeatureGates)
+	}
+	if cluster.Spec.Kubelet.FeatureGates["NodeSwap"] != "" {
+		t.Errorf("cluster feature gates were modified")
+	}
+	expectedDropIns := map[string]string{swapConfigDropIn: "memorySwap:\n  swapBehavior: LimitedSwap\n"}
+	if !reflect.DeepEqual(config.KubeletConfig.ConfigDropIns, expectedDropIns) {
+		t.Errorf("unexpected config drop-ins: %v", config.KubeletConfig.ConfigDropIns)
+	}
+}
+
+func TestNewConfigLogging(t *testing.T) {
+	cluster := &kops.Cluster{}
+	ig := &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			Role: kops.InstanceGroupRoleNode,
+			VolumeMounts: []kops.VolumeMountSpec{
+				{Device: "/dev/xvdd", Filesystem: "ext4", Path: "/data"},
+			},
+			Logging: &kops.InstanceGroupLoggingSpec{
+				Volume: &kops.VolumeSpec{
+					Device: "/dev/xvdl",
+					Size:   20,
+				},
+			},
+		},
+	}
+
+	config, _ := NewConfig(cluster, ig)
+
+	if config.Logging == nil {
+		t.Fatalf("expected logging to be set")
+	}
+	expectedMounts := []kops.VolumeMountSpec{
+		{Device: "/dev/xvdd", Filesystem: "ext4", Path: "/data"},
+		{Device: "/dev/xvdl", Filesystem: "ext4", Path: LogVolumePath},
+	}
+	if !reflect.DeepEqual(config.VolumeMounts, expectedMounts) {
+		t.Errorf("unexpected volume mounts: %v", config.VolumeMounts)
+	}
+	if len(ig.Spec.VolumeMounts) != 1 {
+		t.Errorf("instance group volume mounts were modified")
+	}
+}
+
+func TestNewConfigStateVolumes(t *testing.T) {
+	cluster := &kops.Cluster{}
+	ig := &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			Role: kops.InstanceGroupRoleNode,
+			VolumeMounts: []kops.VolumeMountSpec{
+				{Device: "/dev/xvdd", Filesystem: "ext4", Path: "/data"},
+			},
+			StateVolumes: &kops.StateVolumesSpec{
+				Containerd: &kops.VolumeSpec{
+					Device: "/dev/xvdc",
+					Size:   100,
+				},
+				Kubelet: &kops.VolumeSpec{
+					Device: "/dev/xvdk",
+					Size:   50,
+				},
+			},
+		},
+	}
+
+	config, _ := NewConfig(cluster, ig)
+
+	expectedMounts := []kops.VolumeMountSpec{
+		{Device: "/dev/xvdd", Filesystem: "ext4", Path: "/data"},
+		{Device: "/dev/xvdc", Filesystem: "ext4", Path: ContainerdVolumePath},
+		{Device: "/dev/xvdk", Filesystem: "ext4", Path: KubeletVolumePath},
+	}
+	if !reflect.DeepEqual(config.VolumeMounts, expectedMounts) {
+		t.Errorf("unexpected volume mounts: %v", config.VolumeMounts)
+	}
+	if len(ig.Spec.VolumeMounts) != 1 {
+		t.Errorf("instance group volume mounts were modified")
+	}
+}
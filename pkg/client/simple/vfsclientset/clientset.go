@@ -147,6 +147,14 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == "cluster-completed.spec" || relativePath == registry.PathKopsVersionUpdated {
 			continue
 		}
+		// "shutdown.json" is written by kops shutdown cluster.
+		if relativePath == "shutdown.json" {
+			continue
+		}
+		// "rolling-update.json" is the progress of an interrupted kops rolling-update cluster.
+		if relativePath == "rolling-update.json" {
+			continue
+		}
 		if strings.HasPrefix(relativePath, "addons/") {
 			continue
 		}
@@ -168,6 +176,14 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 		if strings.HasPrefix(relativePath, "manifests/") {
 			continue
 		}
+		// "metal/" holds the enrollments of the machines of a metal cluster.
+		if strings.HasPrefix(relativePath, "metal/") {
+			continue
+		}
+		// "hybrid/" holds the credentials of the hybrid nodes written by kops toolbox enroll.
+		if strings.HasPrefix(relativePath, "hybrid/") {
+			continue
+		}
 		// TODO: offer an option _not_ to delete backups?
 		if strings.HasPrefix(relativePath, "backups/") {
 			continue
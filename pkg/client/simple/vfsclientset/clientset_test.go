@@ -17,6 +17,7 @@ limitations under the License.
 package vfsclientset
 
 import (
+	"bytes"
 	"testing"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -70,3 +71,51 @@ func TestSSHCredentialStoreOnOwnCFS(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, actual)
 	}
 }
+
+func TestDeleteAllClusterState(t *testing.T) {
+	grid := []struct {
+		Files         []string
+		ExpectRefusal bool
+	}{
+		{
+			Files: []string{"config", "cluster-completed.spec", "instancegroup/nodes", "pki/private/ca/keyset.yaml"},
+		},
+		{
+			Files: []string{"config", "rolling-update.json"},
+		},
+		{
+			Files: []string{"config", "igconfig/node/nodes/nodeup.sh", "metal/node-1.json"},
+		},
+		{
+			Files: []string{"config", "hybrid/node-1.json"},
+		},
+		{
+			Files:         []string{"config", "unexpected.txt"},
+			ExpectRefusal: true,
+		},
+	}
+
+	for _, g := range grid {
+		vfs.Context.ResetMemfsContext(true)
+		basePath, err := vfs.Context.BuildVfsPath("memfs://state/cluster.example.com")
+		if err != nil {
+			t.Fatalf("error building path: %v", err)
+		}
+		for _, f := range g.Files {
+			if err := basePath.Join(f).WriteFile(bytes.NewReader([]byte("{}")), nil); err != nil {
+				t.Fatalf("error writing %s: %v", f, err)
+			}
+		}
+
+		err = DeleteAllClusterState(basePath)
+		if g.ExpectRefusal {
+			if err == nil {
+				t.Errorf("expected deleting %v to be refused", g.Files)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error deleting %v: %v", g.Files, err)
+		}
+	}
+}
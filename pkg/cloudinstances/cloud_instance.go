@@ -50,4 +50,6 @@ type CloudInstance struct {
 	PrivateIP string
 	// State is in which state the instance is in
 	State State
+	// UpdateReason describes why the instance needs to be updated, if known.
+	UpdateReason string
 }
@@ -89,6 +89,7 @@ func (group *CloudInstanceGroup) AdjustNeedUpdate() {
 			if makeNotReady {
 				group.NeedUpdate = append(group.NeedUpdate, member)
 				member.Status = CloudInstanceStatusNeedsUpdate
+				member.UpdateReason = "node has the kops.k8s.io/needs-update annotation"
 			} else {
 				newReady = append(newReady, member)
 			}
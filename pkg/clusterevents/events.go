@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/user"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// The types of the events published by kops
+const (
+	ClusterUpdated        = "ClusterUpdated"
+	RollingUpdateStarted  = "RollingUpdateStarted"
+	RollingUpdateFinished = "RollingUpdateFinished"
+	ValidationFailed      = "ValidationFailed"
+)
+
+// Source is the source of the events put on EventBridge
+const Source = "kops"
+
+// Event is a change to a cluster, published as a JSON document
+type Event struct {
+	// Type is the type of the event, e.g. ClusterUpdated
+	Type string `json:"type"`
+	// Cluster is the name of the cluster
+	Cluster string `json:"cluster"`
+	// Time is when the event happened
+	Time time.Time `json:"time"`
+	// User is the local user that ran kops
+	User string `json:"user,omitempty"`
+	// Message is a human readable description of the event
+	Message string `json:"message,omitempty"`
+	// Error is set on events about operations that failed
+	Error string `json:"error,omitempty"`
+	// Details holds additional information specific to the type of event
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// NewEvent builds an event of the given type for the cluster
+func NewEvent(cluster *kops.Cluster, eventType string, message string) *Event {
+	e := &Event{
+		Type:    eventType,
+		Cluster: cluster.ObjectMeta.Name,
+		Time:    time.Now().UTC(),
+		Message: message,
+	}
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+	return e
+}
+
+// Publisher publishes events to the SNS topic and EventBridge bus configured in the cluster spec
+type Publisher struct {
+	Spec kops.EventsSpec
+
+	SNS         snsiface.SNSAPI
+	EventBridge eventbridgeiface.EventBridgeAPI
+}
+
+// NewPublisher builds the publisher for the cluster, returning nil if the cluster does not publish events
+func NewPublisher(cluster *kops.Cluster, cloud fi.Cloud) (*Publisher, error) {
+	spec := cluster.Spec.Events
+	if spec == nil || (spec.SNSTopicARN == "" && spec.EventBusName == "") {
+		return nil, nil
+	}
+	awsCloud, ok := cloud.(awsup.AWSCloud)
+	if !ok {
+		return nil, fmt.Errorf("events are only supported on AWS")
+	}
+	return &Publisher{
+		Spec:        *spec,
+		SNS:         awsCloud.SNS(),
+		EventBridge: awsCloud.EventBridge(),
+	}, nil
+}
+
+// Publish sends the event to every configured destination
+func (p *Publisher) Publish(ctx context.Context, event *Event) error {
+	if p == nil {
+		return nil
+	}
+
+	detail, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %v", err)
+	}
+
+	var errs []error
+	if p.Spec.SNSTopicARN != "" {
+		if err := p.publishSNS(ctx, event, string(detail)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if p.Spec.EventBusName != "" {
+		if err := p.putEvent(ctx, event, string(detail)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("error publishing %s event: %v", event.Type, errs)
+	}
+	return nil
+}
+
+func (p *Publisher) publishSNS(ctx context.Context, event *Event, detail string) error {
+	subject := fmt.Sprintf("kops %s: %s", event.Type, event.Cluster)
+	// SNS limits subjects to 100 characters
+	if len(subject) > 100 {
+		subject = subject[:100]
+	}
+	request := &sns.PublishInput{
+		TopicArn: aws.String(p.Spec.SNSTopicARN),
+		Subject:  aws.String(subject),
+		Message:  aws.String(detail),
+		// Attributes allow subscriptions to filter the events
+		MessageAttributes: map[string]*sns.MessageAttributeValue{
+			"type": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(event.Type),
+			},
+			"cluster": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(event.Cluster),
+			},
+		},
+	}
+	if _, err := p.SNS.PublishWithContext(ctx, request); err != nil {
+		return fmt.Errorf("error publishing to SNS topic %q: %v", p.Spec.SNSTopicARN, err)
+	}
+	return nil
+}
+
+func (p *Publisher) putEvent(ctx context.Context, event *Event, detail string) error {
+	request := &eventbridge.PutEventsInput{
+		Entries: []*eventbridge.PutEventsRequestEntry{
+			{
+				EventBusName: aws.String(p.Spec.EventBusName),
+				Source:       aws.String(Source),
+				DetailType:   aws.String(event.Type),
+				Detail:       aws.String(detail),
+				Time:         aws.Time(event.Time),
+			},
+		},
+	}
+	response, err := p.EventBridge.PutEventsWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("error putting event on EventBridge bus %q: %v", p.Spec.EventBusName, err)
+	}
+	if aws.Int64Value(response.FailedEntryCount) != 0 {
+		for _, entry := range response.Entries {
+			if entry.ErrorCode != nil {
+				return fmt.Errorf("error putting event on EventBridge bus %q: %s: %s", p.Spec.EventBusName, aws.StringValue(entry.ErrorCode), aws.StringValue(entry.ErrorMessage))
+			}
+		}
+		return fmt.Errorf("error putting event on EventBridge bus %q", p.Spec.EventBusName)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterevents
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/testutils"
+)
+
+type fakeSNS struct {
+	snsiface.SNSAPI
+	published []*sns.PublishInput
+}
+
+func (f *fakeSNS) PublishWithContext(ctx aws.Context, input *sns.PublishInput, opts ...request.Option) (*sns.PublishOutput, error) {
+	f.published = append(f.published, input)
+	return &sns.PublishOutput{}, nil
+}
+
+type fakeEventBridge struct {
+	eventbridgeiface.EventBridgeAPI
+	entries []*eventbridge.PutEventsRequestEntry
+	fail    bool
+}
+
+func (f *fakeEventBridge) PutEventsWithContext(ctx aws.Context, input *eventbridge.PutEventsInput, opts ...request.Option) (*eventbridge.PutEventsOutput, error) {
+	if f.fail {
+		return &eventbridge.PutEventsOutput{
+			FailedEntryCount: aws.Int64(1),
+			Entries: []*eventbridge.PutEventsResultEntry{
+				{ErrorCode: aws.String("AccessDeniedException"), ErrorMessage: aws.String("not authorized")},
+			},
+		}, nil
+	}
+	f.entries = append(f.entries, input.Entries...)
+	return &eventbridge.PutEventsOutput{FailedEntryCount: aws.Int64(0)}, nil
+}
+
+func TestPublish(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("test.k8s.local")
+	snsClient := &fakeSNS{}
+	eventBridgeClient := &fakeEventBridge{}
+	p := &Publisher{
+		Spec: kops.EventsSpec{
+			SNSTopicARN:  "arn:aws:sns:us-test-1:123456789012:kops-events",
+			EventBusName: "kops-events",
+		},
+		SNS:         snsClient,
+		EventBridge: eventBridgeClient,
+	}
+
+	event := NewEvent(cluster, RollingUpdateFinished, "rolling update finished")
+	event.Error = "validation timed out"
+	event.Details = map[string]string{"instanceGroups": "nodes"}
+	if err := p.Publish(context.TODO(), event); err != nil {
+		t.Fatalf("unexpected error from Publish: %v", err)
+	}
+
+	if len(snsClient.published) != 1 {
+		t.Fatalf("expected one SNS message, got %d", len(snsClient.published))
+	}
+	message := snsClient.published[0]
+	if aws.StringValue(message.TopicArn) != p.Spec.SNSTopicARN {
+		t.Errorf("unexpected topic %q", aws.StringValue(message.TopicArn))
+	}
+	if aws.StringValue(message.Subject) != "kops RollingUpdateFinished: test.k8s.local" {
+		t.Errorf("unexpected subject %q", aws.StringValue(message.Subject))
+	}
+	if aws.StringValue(message.MessageAttributes["type"].StringValue) != RollingUpdateFinished {
+		t.Errorf("unexpected message attributes %v", message.MessageAttributes)
+	}
+
+	var decoded Event
+	if err := json.Unmarshal([]byte(aws.StringValue(message.Message)), &decoded); err != nil {
+		t.Fatalf("error decoding message: %v", err)
+	}
+	if decoded.Cluster != "test.k8s.local" || decoded.Type != RollingUpdateFinished || decoded.Error != "validation timed out" || decoded.Details["instanceGroups"] != "nodes" {
+		t.Errorf("unexpected message %+v", decoded)
+	}
+
+	if len(eventBridgeClient.entries) != 1 {
+		t.Fatalf("expected one EventBridge entry, got %d", len(eventBridgeClient.entries))
+	}
+	entry := eventBridgeClient.entries[0]
+	if aws.StringValue(entry.Source) != Source || aws.StringValue(entry.DetailType) != RollingUpdateFinished || aws.StringValue(entry.EventBusName) != "kops-events" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if aws.StringValue(entry.Detail) != aws.StringValue(message.Message) {
+		t.Errorf("expected the same document on SNS and EventBridge, got %q", aws.StringValue(entry.Detail))
+	}
+}
+
+func TestPublishFailedEntry(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("test.k8s.local")
+	p := &Publisher{
+		Spec:        kops.EventsSpec{EventBusName: "kops-events"},
+		EventBridge: &fakeEventBridge{fail: true},
+	}
+
+	err := p.Publish(context.TODO(), NewEvent(cluster, ClusterUpdated, ""))
+	if err == nil || !strings.Contains(err.Error(), "AccessDeniedException") {
+		t.Errorf("expected the failed entry to be reported, got %v", err)
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("test.k8s.local")
+
+	p, err := NewPublisher(cluster, nil)
+	if err != nil || p != nil {
+		t.Errorf("expected no publisher without events, got %v, %v", p, err)
+	}
+	// Publishing with no publisher is a no-op
+	if err := p.Publish(context.TODO(), NewEvent(cluster, ClusterUpdated, "")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cluster.Spec.Events = &kops.EventsSpec{EventBusName: "kops-events"}
+	if _, err := NewPublisher(cluster, nil); err == nil || !strings.Contains(err.Error(), "only supported on AWS") {
+		t.Errorf("expected an error for non-AWS clouds, got %v", err)
+	}
+}
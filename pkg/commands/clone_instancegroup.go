@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+
+	api "k8s.io/kops/pkg/apis/kops"
+)
+
+// CloneInstanceGroupOptions contains the options for cloning an instance group.
+type CloneInstanceGroupOptions struct {
+	// Name is the name of the cloned instance group.
+	Name string
+	// Subnets optionally replaces the subnets of the source instance group.
+	Subnets []string
+	// Zones optionally replaces the zones of the source instance group.
+	Zones []string
+}
+
+// CloneInstanceGroup returns a copy of the instance group with a new name, and optionally in other subnets.
+func CloneInstanceGroup(ig *api.InstanceGroup, options *CloneInstanceGroupOptions) (*api.InstanceGroup, error) {
+	if options.Name == "" {
+		return nil, fmt.Errorf("name of the cloned instance group is required")
+	}
+	if options.Name == ig.ObjectMeta.Name {
+		return nil, fmt.Errorf("name of the cloned instance group must differ from the source instance group name")
+	}
+	if ig.IsMaster() {
+		return nil, fmt.Errorf("cannot clone control plane instance group %q: the etcd members of the cluster must be configured for each control plane instance group", ig.ObjectMeta.Name)
+	}
+
+	cloned := &api.InstanceGroup{}
+	cloned.ObjectMeta.Name = options.Name
+	cloned.ObjectMeta.Labels = make(map[string]string)
+	for k, v := range ig.ObjectMeta.Labels {
+		cloned.ObjectMeta.Labels[k] = v
+	}
+	ig.Spec.DeepCopyInto(&cloned.Spec)
+
+	if len(options.Subnets) != 0 {
+		cloned.Spec.Subnets = append([]string{}, options.Subnets...)
+	}
+	if len(options.Zones) != 0 {
+		cloned.Spec.Zones = append([]string{}, options.Zones...)
+	}
+	if cloned.Spec.NodeLabels[api.NodeLabelInstanceGroup] == ig.ObjectMeta.Name {
+		cloned.Spec.NodeLabels[api.NodeLabelInstanceGroup] = cloned.ObjectMeta.Name
+	}
+
+	return cloned, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestCloneInstanceGroup(t *testing.T) {
+	source := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "nodes-us-test-1a",
+			Labels: map[string]string{kops.LabelClusterName: "minimal.example.com"},
+		},
+		Spec: kops.InstanceGroupSpec{
+			Role:        kops.InstanceGroupRoleNode,
+			MachineType: "m5.large",
+			MinSize:     fi.Int32(2),
+			MaxSize:     fi.Int32(5),
+			Subnets:     []string{"us-test-1a"},
+			NodeLabels: map[string]string{
+				kops.NodeLabelInstanceGroup: "nodes-us-test-1a",
+				"workload":                  "batch",
+			},
+		},
+	}
+
+	grid := []struct {
+		Options  CloneInstanceGroupOptions
+		Expected kops.InstanceGroup
+	}{
+		{
+			Options: CloneInstanceGroupOptions{
+				Name: "nodes-us-test-1b",
+			},
+			Expected: kops.InstanceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "nodes-us-test-1b",
+					Labels: map[string]string{kops.LabelClusterName: "minimal.example.com"},
+				},
+				Spec: kops.InstanceGroupSpec{
+					Role:        kops.InstanceGroupRoleNode,
+					MachineType: "m5.large",
+					MinSize:     fi.Int32(2),
+					MaxSize:     fi.Int32(5),
+					Subnets:     []string{"us-test-1a"},
+					NodeLabels: map[string]string{
+						kops.NodeLabelInstanceGroup: "nodes-us-test-1b",
+						"workload":                  "batch",
+					},
+				},
+			},
+		},
+		{
+			Options: CloneInstanceGroupOptions{
+				Name:    "nodes-us-test-1b",
+				Subnets: []string{"us-test-1b", "us-test-1c"},
+				Zones:   []string{"us-test-1b"},
+			},
+			Expected: kops.InstanceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "nodes-us-test-1b",
+					Labels: map[string]string{kops.LabelClusterName: "minimal.example.com"},
+				},
+				Spec: kops.InstanceGroupSpec{
+					Role:        kops.InstanceGroupRoleNode,
+					MachineType: "m5.large",
+					MinSize:     fi.Int32(2),
+					MaxSize:     fi.Int32(5),
+					Subnets:     []string{"us-test-1b", "us-test-1c"},
+					Zones:       []string{"us-test-1b"},
+					NodeLabels: map[string]string{
+						kops.NodeLabelInstanceGroup: "nodes-us-test-1b",
+						"workload":                  "batch",
+					},
+				},
+			},
+		},
+	}
+	for _, g := range grid {
+		cloned, err := CloneInstanceGroup(source, &g.Options)
+		if err != nil {
+			t.Errorf("unexpected error cloning with options %+v: %v", g.Options, err)
+			continue
+		}
+		if !reflect.DeepEqual(*cloned, g.Expected) {
+			t.Errorf("unexpected clone with options %+v:\nexpected %+v\nactual   %+v", g.Options, g.Expected, *cloned)
+		}
+	}
+
+	if source.Spec.NodeLabels[kops.NodeLabelInstanceGroup] != "nodes-us-test-1a" || len(source.Spec.Subnets) != 1 {
+		t.Errorf("source instance group was modified: %+v", source.Spec)
+	}
+}
+
+func TestCloneInstanceGroupErrors(t *testing.T) {
+	grid := []struct {
+		Source   kops.InstanceGroup
+		Options  CloneInstanceGroupOptions
+		Expected string
+	}{
+		{
+			Source:   kops.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}},
+			Expected: "name of the cloned instance group is required",
+		},
+		{
+			Source:   kops.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}},
+			Options:  CloneInstanceGroupOptions{Name: "nodes"},
+			Expected: "name of the cloned instance group must differ from the source instance group name",
+		},
+		{
+			Source: kops.InstanceGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "master-us-test-1a"},
+				Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleMaster},
+			},
+			Options:  CloneInstanceGroupOptions{Name: "master-us-test-1b"},
+			Expected: "cannot clone control plane instance group \"master-us-test-1a\": the etcd members of the cluster must be configured for each control plane instance group",
+		},
+	}
+	for _, g := range grid {
+		_, err := CloneInstanceGroup(&g.Source, &g.Options)
+		if err == nil || err.Error() != g.Expected {
+			t.Errorf("expected error %q, got %v", g.Expected, err)
+		}
+	}
+}
@@ -68,6 +68,11 @@ func UpdateCluster(ctx context.Context, clientset simple.Clientset, cluster *kop
 
 // UpdateInstanceGroup writes the updated instance group to the state store after performing validation
 func UpdateInstanceGroup(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster, allInstanceGroups []*kops.InstanceGroup, instanceGroupToUpdate *kops.InstanceGroup) error {
+	return UpdateInstanceGroups(ctx, clientset, cluster, allInstanceGroups, []*kops.InstanceGroup{instanceGroupToUpdate})
+}
+
+// UpdateInstanceGroups writes the updated instance groups to the state store, after validating all of them
+func UpdateInstanceGroups(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster, allInstanceGroups []*kops.InstanceGroup, instanceGroupsToUpdate []*kops.InstanceGroup) error {
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return err
@@ -84,15 +89,19 @@ func UpdateInstanceGroup(ctx context.Context, clientset simple.Clientset, cluste
 		return err
 	}
 
-	err = validation.CrossValidateInstanceGroup(instanceGroupToUpdate, fullCluster, cloud).ToAggregate()
-	if err != nil {
-		return err
+	for _, instanceGroupToUpdate := range instanceGroupsToUpdate {
+		err = validation.CrossValidateInstanceGroup(instanceGroupToUpdate, fullCluster, cloud).ToAggregate()
+		if err != nil {
+			return fmt.Errorf("instance group %q: %v", instanceGroupToUpdate.GetName(), err)
+		}
 	}
 
-	// Validation was successful so commit the changed instance group.
-	_, err = clientset.InstanceGroupsFor(cluster).Update(ctx, instanceGroupToUpdate, metav1.UpdateOptions{})
-	if err != nil {
-		return err
+	// Validation was successful so commit the changed instance groups.
+	for _, instanceGroupToUpdate := range instanceGroupsToUpdate {
+		_, err = clientset.InstanceGroupsFor(cluster).Update(ctx, instanceGroupToUpdate, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -322,6 +322,26 @@ func TestSetClusterFields(t *testing.T) {
 				},
 			},
 		},
+		{
+			Fields: []string{
+				`spec.kubelet={"maxPods":50,"featureGates":{"Foo":"true"}}`,
+				`spec.kubelet={"featureGates":{"Bar":"false"}}`,
+			},
+			Input: kops.Cluster{
+				Spec: kops.ClusterSpec{},
+			},
+			Output: kops.Cluster{
+				Spec: kops.ClusterSpec{
+					Kubelet: &kops.KubeletConfigSpec{
+						MaxPods: fi.Int32(50),
+						FeatureGates: map[string]string{
+							"Foo": "true",
+							"Bar": "false",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, g := range grid {
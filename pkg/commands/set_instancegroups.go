@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"k8s.io/kops/cmd/kops/util"
@@ -37,6 +38,8 @@ type SetInstanceGroupOptions struct {
 	Fields            []string
 	ClusterName       string
 	InstanceGroupName string
+	// Selector is a label selector of the instance groups to set the fields of, instead of InstanceGroupName.
+	Selector string
 }
 
 // RunSetInstancegroup implements the set instancegroup command logic.
@@ -48,9 +51,12 @@ func RunSetInstancegroup(ctx context.Context, f *util.Factory, cmd *cobra.Comman
 	if options.ClusterName == "" {
 		return field.Required(field.NewPath("clusterName"), "Cluster name is required")
 	}
-	if options.InstanceGroupName == "" {
+	if options.InstanceGroupName == "" && options.Selector == "" {
 		return field.Required(field.NewPath("instancegroupName"), "Instance Group name is required")
 	}
+	if options.InstanceGroupName != "" && options.Selector != "" {
+		return fmt.Errorf("cannot specify both an instance group name and a selector")
+	}
 
 	clientset, err := f.Clientset()
 	if err != nil {
@@ -66,29 +72,70 @@ func RunSetInstancegroup(ctx context.Context, f *util.Factory, cmd *cobra.Comman
 	if err != nil {
 		return err
 	}
-	var instanceGroupToUpdate *api.InstanceGroup
-	for _, instanceGroup := range instanceGroups {
-		if instanceGroup.GetName() == options.InstanceGroupName {
-			instanceGroupToUpdate = instanceGroup
+	var instanceGroupsToUpdate []*api.InstanceGroup
+	if options.Selector != "" {
+		instanceGroupsToUpdate, err = SelectInstanceGroups(options.Selector, instanceGroups)
+		if err != nil {
+			return err
+		}
+		if len(instanceGroupsToUpdate) == 0 {
+			return fmt.Errorf("no instance groups match selector %q", options.Selector)
+		}
+	} else {
+		for _, instanceGroup := range instanceGroups {
+			if instanceGroup.GetName() == options.InstanceGroupName {
+				instanceGroupsToUpdate = append(instanceGroupsToUpdate, instanceGroup)
+			}
+		}
+		if len(instanceGroupsToUpdate) == 0 {
+			return fmt.Errorf("unable to find instance group with name %q", options.InstanceGroupName)
 		}
 	}
-	if instanceGroupToUpdate == nil {
-		return fmt.Errorf("unable to find instance group with name %q", options.InstanceGroupName)
+
+	for _, instanceGroup := range instanceGroupsToUpdate {
+		err = SetInstancegroupFields(options.Fields, instanceGroup)
+		if err != nil {
+			return fmt.Errorf("instance group %q: %v", instanceGroup.GetName(), err)
+		}
 	}
 
-	err = SetInstancegroupFields(options.Fields, instanceGroupToUpdate)
+	err = UpdateInstanceGroups(ctx, clientset, cluster, instanceGroups, instanceGroupsToUpdate)
 	if err != nil {
 		return err
 	}
 
-	err = UpdateInstanceGroup(ctx, clientset, cluster, instanceGroups, instanceGroupToUpdate)
-	if err != nil {
-		return err
+	if options.Selector != "" {
+		for _, instanceGroup := range instanceGroupsToUpdate {
+			fmt.Fprintf(out, "Updated instance group %q\n", instanceGroup.GetName())
+		}
 	}
 
 	return nil
 }
 
+// SelectInstanceGroups returns the instance groups that match a label selector.
+// Besides their own labels, instance groups match the "name" and "role" labels, set to their name and role.
+func SelectInstanceGroups(selector string, instanceGroups []*api.InstanceGroup) ([]*api.InstanceGroup, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing selector %q: %v", selector, err)
+	}
+
+	var selected []*api.InstanceGroup
+	for _, instanceGroup := range instanceGroups {
+		set := labels.Set{}
+		for k, v := range instanceGroup.ObjectMeta.Labels {
+			set[k] = v
+		}
+		set["name"] = instanceGroup.GetName()
+		set["role"] = string(instanceGroup.Spec.Role)
+		if s.Matches(set) {
+			selected = append(selected, instanceGroup)
+		}
+	}
+	return selected, nil
+}
+
 // SetInstancegroupFields sets field values in the instance group.
 func SetInstancegroupFields(fields []string, instanceGroup *api.InstanceGroup) error {
 	for _, field := range fields {
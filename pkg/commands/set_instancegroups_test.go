@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 )
@@ -102,6 +103,24 @@ func TestSetInstanceGroupsFields(t *testing.T) {
 				},
 			},
 		},
+		{
+			Fields: []string{
+				`spec.additionalSecurityGroups=["group1","group2"]`,
+				`spec.mixedInstancesPolicy={"instances":["m5.large","m5a.large"],"onDemandBase":1}`,
+			},
+			Output: kops.InstanceGroup{
+				Spec: kops.InstanceGroupSpec{
+					AdditionalSecurityGroups: []string{
+						"group1",
+						"group2",
+					},
+					MixedInstancesPolicy: &kops.MixedInstancesPolicySpec{
+						Instances:    []string{"m5.large", "m5a.large"},
+						OnDemandBase: fi.Int64(1),
+					},
+				},
+			},
+		},
 	}
 
 	for _, g := range grid {
@@ -120,3 +139,59 @@ func TestSetInstanceGroupsFields(t *testing.T) {
 
 	}
 }
+
+func TestSelectInstanceGroups(t *testing.T) {
+	instanceGroups := []*kops.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "master-us-test-1a"},
+			Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleMaster},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes-us-test-1a", Labels: map[string]string{"team": "batch"}},
+			Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes-us-test-1b", Labels: map[string]string{"team": "web"}},
+			Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode},
+		},
+	}
+
+	grid := []struct {
+		Selector string
+		Expected []string
+	}{
+		{
+			Selector: "role=Node",
+			Expected: []string{"nodes-us-test-1a", "nodes-us-test-1b"},
+		},
+		{
+			Selector: "role=Node,team!=web",
+			Expected: []string{"nodes-us-test-1a"},
+		},
+		{
+			Selector: "name in (master-us-test-1a,nodes-us-test-1b)",
+			Expected: []string{"master-us-test-1a", "nodes-us-test-1b"},
+		},
+		{
+			Selector: "team=data",
+		},
+	}
+	for _, g := range grid {
+		selected, err := SelectInstanceGroups(g.Selector, instanceGroups)
+		if err != nil {
+			t.Errorf("unexpected error for selector %q: %v", g.Selector, err)
+			continue
+		}
+		var names []string
+		for _, ig := range selected {
+			names = append(names, ig.ObjectMeta.Name)
+		}
+		if !reflect.DeepEqual(names, g.Expected) {
+			t.Errorf("unexpected instance groups for selector %q: expected %v, got %v", g.Selector, g.Expected, names)
+		}
+	}
+
+	if _, err := SelectInstanceGroups("role in (Node", instanceGroups); err == nil {
+		t.Errorf("expected an error parsing an invalid selector")
+	}
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+)
+
+// CloneClusterOptions contains the options for cloning a cluster into another region.
+type CloneClusterOptions struct {
+	// TargetName is the name of the cloned cluster.
+	TargetName string
+	// Region is the region the cloned cluster is placed in.
+	Region string
+	// ZoneMap maps zones of the source cluster to zones of the cloned cluster.
+	// Zones that are not mapped are translated by replacing the source region prefix.
+	ZoneMap map[string]string
+	// NetworkCIDR optionally replaces the network CIDR of the source cluster.
+	// Subnet CIDRs are shifted so they keep their offset within the network.
+	NetworkCIDR string
+}
+
+// ParseZoneMap parses zone mappings of the form source=target.
+func ParseZoneMap(mappings []string) (map[string]string, error) {
+	zoneMap := make(map[string]string)
+	for _, mapping := range mappings {
+		for _, m := range strings.Split(mapping, ",") {
+			m = strings.TrimSpace(m)
+			if m == "" {
+				continue
+			}
+			kv := strings.SplitN(m, "=", 2)
+			if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+				return nil, fmt.Errorf("zone mapping %q must be of the form source=target", m)
+			}
+			zoneMap[kv[0]] = kv[1]
+		}
+	}
+	return zoneMap, nil
+}
+
+// FormatZoneMap renders a zone map in the form accepted by ParseZoneMap.
+func FormatZoneMap(zoneMap map[string]string) string {
+	var mappings []string
+	for k, v := range zoneMap {
+		mappings = append(mappings, k+"="+v)
+	}
+	sort.Strings(mappings)
+	return strings.Join(mappings, ",")
+}
+
+// DefaultCloneOptionsFromExisting fills options that were not specified from an existing clone,
+// so that a standby cluster can be regenerated from its source with the same mapping.
+func DefaultCloneOptionsFromExisting(existing *api.Cluster, sourceName string, options *CloneClusterOptions) error {
+	if existing == nil {
+		return nil
+	}
+	if existing.ObjectMeta.Annotations[api.AnnotationNameCloneSource] != sourceName {
+		return fmt.Errorf("cluster %q exists and was not cloned from %q", existing.ObjectMeta.Name, sourceName)
+	}
+
+	if options.Region == "" {
+		region, err := clusterRegion(existing)
+		if err != nil {
+			return err
+		}
+		options.Region = region
+	}
+	if len(options.ZoneMap) == 0 {
+		zoneMap, err := ParseZoneMap([]string{existing.ObjectMeta.Annotations[api.AnnotationNameCloneZoneMap]})
+		if err != nil {
+			return fmt.Errorf("error parsing annotation %s: %v", api.AnnotationNameCloneZoneMap, err)
+		}
+		options.ZoneMap = zoneMap
+	}
+	if options.NetworkCIDR == "" {
+		options.NetworkCIDR = existing.Spec.NetworkCIDR
+	}
+	return nil
+}
+
+// CloneCluster returns a copy of the cluster and its instance groups, remapped to the target region.
+// The returned objects are suitable for "kops create -f" or "kops replace -f".
+func CloneCluster(cluster *api.Cluster, instanceGroups []*api.InstanceGroup, options *CloneClusterOptions) (*api.Cluster, []*api.InstanceGroup, error) {
+	if options.TargetName == "" {
+		return nil, nil, fmt.Errorf("target cluster name is required")
+	}
+	if options.TargetName == cluster.ObjectMeta.Name {
+		return nil, nil, fmt.Errorf("target cluster name must differ from the source cluster name")
+	}
+	if options.Region == "" {
+		return nil, nil, fmt.Errorf("target region is required")
+	}
+
+	sourceRegion, err := clusterRegion(cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	zoneMap := make(map[string]string)
+	for _, subnet := range cluster.Spec.Subnets {
+		if subnet.Zone == "" {
+			continue
+		}
+		target := options.ZoneMap[subnet.Zone]
+		if target == "" {
+			if sourceRegion == "" || !strings.HasPrefix(subnet.Zone, sourceRegion) {
+				return nil, nil, fmt.Errorf("unable to determine target zone for %q; specify a zone mapping", subnet.Zone)
+			}
+			target = options.Region + strings.TrimPrefix(subnet.Zone, sourceRegion)
+		}
+		zoneMap[subnet.Zone] = target
+	}
+
+	// Zone names are commonly embedded in subnet and instance group names, so rename them consistently.
+	renames := make(map[string]string)
+	rename := func(name string) string {
+		if renamed, ok := renames[name]; ok {
+			return renamed
+		}
+		renamed := name
+		for source, target := range zoneMap {
+			renamed = strings.ReplaceAll(renamed, source, target)
+		}
+		renames[name] = renamed
+		return renamed
+	}
+
+	sourceName := cluster.ObjectMeta.Name
+	clone := &api.Cluster{}
+	clone.ObjectMeta.Name = options.TargetName
+	clone.ObjectMeta.Annotations = make(map[string]string)
+	for k, v := range cluster.ObjectMeta.Annotations {
+		clone.ObjectMeta.Annotations[k] = v
+	}
+	clone.ObjectMeta.Annotations[api.AnnotationNameCloneSource] = sourceName
+	clone.ObjectMeta.Annotations[api.AnnotationNameCloneZoneMap] = FormatZoneMap(zoneMap)
+	cluster.Spec.DeepCopyInto(&clone.Spec)
+
+	spec := &clone.Spec
+	// The state store locations and any shared network are specific to the source cluster and region.
+	spec.ConfigBase = ""
+	spec.ConfigStore = ""
+	spec.KeyStore = ""
+	spec.SecretStore = ""
+	if spec.NetworkID != "" {
+		klog.Warningf("source cluster uses shared network %q; the clone will create its own network", spec.NetworkID)
+		spec.NetworkID = ""
+	}
+	spec.MasterPublicName = renameDNSName(spec.MasterPublicName, sourceName, options.TargetName)
+	spec.MasterInternalName = renameDNSName(spec.MasterInternalName, sourceName, options.TargetName)
+
+	var remapCIDR func(string) (string, error)
+	if options.NetworkCIDR != "" && options.NetworkCIDR != spec.NetworkCIDR {
+		remapCIDR, err = cidrRemapper(spec.NetworkCIDR, options.NetworkCIDR)
+		if err != nil {
+			return nil, nil, err
+		}
+		spec.NetworkCIDR = options.NetworkCIDR
+		spec.AdditionalNetworkCIDRs = nil
+	}
+
+	for i := range spec.Subnets {
+		subnet := &spec.Subnets[i]
+		subnet.Name = rename(subnet.Name)
+		if subnet.Zone != "" {
+			subnet.Zone = zoneMap[subnet.Zone]
+		}
+		if subnet.Region != "" {
+			subnet.Region = options.Region
+		}
+		subnet.ProviderID = ""
+		subnet.PublicIP = ""
+		if subnet.Egress != "" && subnet.Egress != api.EgressExternal {
+			klog.Warningf("clearing egress %q of subnet %q; it refers to a resource in the source region", subnet.Egress, subnet.Name)
+			subnet.Egress = ""
+		}
+		if remapCIDR != nil && subnet.CIDR != "" {
+			subnet.CIDR, err = remapCIDR(subnet.CIDR)
+			if err != nil {
+				return nil, nil, fmt.Errorf("subnet %q: %v", subnet.Name, err)
+			}
+		}
+		if subnet.IPv6CIDR != "" && !strings.HasPrefix(subnet.IPv6CIDR, "/") {
+			subnet.IPv6CIDR = ""
+		}
+	}
+
+	for i := range spec.EtcdClusters {
+		for j := range spec.EtcdClusters[i].Members {
+			member := &spec.EtcdClusters[i].Members[j]
+			if member.InstanceGroup != nil {
+				renamed := rename(*member.InstanceGroup)
+				member.InstanceGroup = &renamed
+			}
+		}
+	}
+
+	var clonedGroups []*api.InstanceGroup
+	for _, ig := range instanceGroups {
+		cloned := &api.InstanceGroup{}
+		cloned.ObjectMeta.Name = rename(ig.ObjectMeta.Name)
+		cloned.ObjectMeta.Labels = make(map[string]string)
+		for k, v := range ig.ObjectMeta.Labels {
+			cloned.ObjectMeta.Labels[k] = v
+		}
+		cloned.ObjectMeta.Labels[api.LabelClusterName] = options.TargetName
+		ig.Spec.DeepCopyInto(&cloned.Spec)
+
+		for i, subnet := range cloned.Spec.Subnets {
+			cloned.Spec.Subnets[i] = rename(subnet)
+		}
+		for i, zone := range cloned.Spec.Zones {
+			if target, ok := zoneMap[zone]; ok {
+				cloned.Spec.Zones[i] = target
+			}
+		}
+		if cloned.Spec.NodeLabels[api.NodeLabelInstanceGroup] == ig.ObjectMeta.Name {
+			cloned.Spec.NodeLabels[api.NodeLabelInstanceGroup] = cloned.ObjectMeta.Name
+		}
+		if strings.HasPrefix(cloned.Spec.Image, "ami-") {
+			klog.Warningf("clearing region-specific image %q of instance group %q; the default image will be used", cloned.Spec.Image, cloned.ObjectMeta.Name)
+			cloned.Spec.Image = ""
+		}
+
+		clonedGroups = append(clonedGroups, cloned)
+	}
+
+	return clone, clonedGroups, nil
+}
+
+// clusterRegion returns the region of the cluster, determined from its subnets.
+func clusterRegion(cluster *api.Cluster) (string, error) {
+	region := ""
+	for _, subnet := range cluster.Spec.Subnets {
+		r := subnet.Region
+		if r == "" && subnet.Zone != "" {
+			switch api.CloudProviderID(cluster.Spec.CloudProvider) {
+			case api.CloudProviderGCE, api.CloudProviderAzure:
+				if i := strings.LastIndex(subnet.Zone, "-"); i > 0 {
+					r = subnet.Zone[:i]
+				}
+			default:
+				if len(subnet.Zone) > 2 {
+					r = subnet.Zone[:len(subnet.Zone)-1]
+				}
+			}
+		}
+		if r == "" {
+			continue
+		}
+		if region != "" && r != region {
+			return "", fmt.Errorf("cluster spans multiple regions (%q and %q)", region, r)
+		}
+		region = r
+	}
+	return region, nil
+}
+
+// renameDNSName replaces the cluster name suffix of a DNS name.
+func renameDNSName(name, sourceName, targetName string) string {
+	if name == "" {
+		return ""
+	}
+	if strings.HasSuffix(name, "."+sourceName) {
+		return strings.TrimSuffix(name, sourceName) + targetName
+	}
+	// Not derived from the cluster name; let it be defaulted again.
+	return ""
+}
+
+// cidrRemapper returns a function that moves an IPv4 CIDR from the source network to the target network,
+// preserving its offset within the network.
+func cidrRemapper(sourceNetwork, targetNetwork string) (func(string) (string, error), error) {
+	if sourceNetwork == "" {
+		return nil, fmt.Errorf("source cluster does not specify a network CIDR")
+	}
+	_, source, err := net.ParseCIDR(sourceNetwork)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing network CIDR %q: %v", sourceNetwork, err)
+	}
+	_, target, err := net.ParseCIDR(targetNetwork)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing network CIDR %q: %v", targetNetwork, err)
+	}
+	if source.IP.To4() == nil || target.IP.To4() == nil {
+		return nil, fmt.Errorf("only IPv4 network CIDRs can be remapped")
+	}
+	sourceOnes, _ := source.Mask.Size()
+	targetOnes, _ := target.Mask.Size()
+	if sourceOnes != targetOnes {
+		return nil, fmt.Errorf("network CIDR %q must have the same size as %q", targetNetwork, sourceNetwork)
+	}
+
+	sourceBase := binary.BigEndian.Uint32(source.IP.To4())
+	targetBase := binary.BigEndian.Uint32(target.IP.To4())
+
+	return func(cidr string) (string, error) {
+		ip, subnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return "", fmt.Errorf("error parsing CIDR %q: %v", cidr, err)
+		}
+		if !source.Contains(ip) {
+			return "", fmt.Errorf("CIDR %q is not within network CIDR %q", cidr, sourceNetwork)
+		}
+		ones, _ := subnet.Mask.Size()
+		offset := binary.BigEndian.Uint32(subnet.IP.To4()) - sourceBase
+		remapped := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(remapped, targetBase+offset)
+		return fmt.Sprintf("%s/%d", remapped, ones), nil
+	}, nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestCloneCluster(t *testing.T) {
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "example.com"},
+		Spec: kops.ClusterSpec{
+			CloudProvider:    "aws",
+			ConfigBase:       "memfs://state/example.com",
+			MasterPublicName: "api.example.com",
+			NetworkCIDR:      "172.20.0.0/16",
+			NetworkID:        "vpc-12345678",
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "us-east-1a", Zone: "us-east-1a", CIDR: "172.20.32.0/19", ProviderID: "subnet-1", Type: kops.SubnetTypePrivate},
+				{Name: "utility-us-east-1b", Zone: "us-east-1b", CIDR: "172.20.4.0/22", Type: kops.SubnetTypeUtility},
+			},
+			EtcdClusters: []kops.EtcdClusterSpec{
+				{
+					Name: "main",
+					Members: []kops.EtcdMemberSpec{
+						{Name: "a", InstanceGroup: fi.String("master-us-east-1a")},
+					},
+				},
+			},
+		},
+	}
+	instanceGroups := []*kops.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "master-us-east-1a",
+				Labels: map[string]string{kops.LabelClusterName: "example.com"},
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:       kops.InstanceGroupRoleMaster,
+				Image:      "ami-12345678",
+				Subnets:    []string{"us-east-1a"},
+				NodeLabels: map[string]string{kops.NodeLabelInstanceGroup: "master-us-east-1a"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+			Spec: kops.InstanceGroupSpec{
+				Role:    kops.InstanceGroupRoleNode,
+				Image:   "099720109477/ubuntu/images/hvm-ssd/ubuntu-focal-20.04-amd64-server-20210415",
+				Subnets: []string{"us-east-1a", "utility-us-east-1b"},
+			},
+		},
+	}
+
+	clone, groups, err := CloneCluster(cluster, instanceGroups, &CloneClusterOptions{
+		TargetName:  "dr.example.com",
+		Region:      "us-west-2",
+		ZoneMap:     map[string]string{"us-east-1b": "us-west-2c"},
+		NetworkCIDR: "172.21.0.0/16",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if clone.ObjectMeta.Name != "dr.example.com" {
+		t.Errorf("unexpected name %q", clone.ObjectMeta.Name)
+	}
+	if clone.ObjectMeta.Annotations[kops.AnnotationNameCloneSource] != "example.com" {
+		t.Errorf("unexpected clone source annotation %q", clone.ObjectMeta.Annotations[kops.AnnotationNameCloneSource])
+	}
+	if zm := clone.ObjectMeta.Annotations[kops.AnnotationNameCloneZoneMap]; zm != "us-east-1a=us-west-2a,us-east-1b=us-west-2c" {
+		t.Errorf("unexpected zone map annotation %q", zm)
+	}
+	if clone.Spec.ConfigBase != "" || clone.Spec.NetworkID != "" {
+		t.Errorf("expected region specific fields to be cleared, got %q and %q", clone.Spec.ConfigBase, clone.Spec.NetworkID)
+	}
+	if clone.Spec.MasterPublicName != "api.dr.example.com" {
+		t.Errorf("unexpected master public name %q", clone.Spec.MasterPublicName)
+	}
+
+	expectedSubnets := []kops.ClusterSubnetSpec{
+		{Name: "us-west-2a", Zone: "us-west-2a", CIDR: "172.21.32.0/19", Type: kops.SubnetTypePrivate},
+		{Name: "utility-us-west-2c", Zone: "us-west-2c", CIDR: "172.21.4.0/22", Type: kops.SubnetTypeUtility},
+	}
+	if !reflect.DeepEqual(clone.Spec.Subnets, expectedSubnets) {
+		t.Errorf("unexpected subnets: %+v", clone.Spec.Subnets)
+	}
+	if ig := fi.StringValue(clone.Spec.EtcdClusters[0].Members[0].InstanceGroup); ig != "master-us-west-2a" {
+		t.Errorf("unexpected etcd member instance group %q", ig)
+	}
+
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 instance groups, got %d", len(groups))
+	}
+	master := groups[0]
+	if master.ObjectMeta.Name != "master-us-west-2a" {
+		t.Errorf("unexpected instance group name %q", master.ObjectMeta.Name)
+	}
+	if master.ObjectMeta.Labels[kops.LabelClusterName] != "dr.example.com" {
+		t.Errorf("unexpected cluster label %q", master.ObjectMeta.Labels[kops.LabelClusterName])
+	}
+	if master.Spec.Image != "" {
+		t.Errorf("expected AMI to be cleared, got %q", master.Spec.Image)
+	}
+	if master.Spec.NodeLabels[kops.NodeLabelInstanceGroup] != "master-us-west-2a" {
+		t.Errorf("unexpected instance group node label %q", master.Spec.NodeLabels[kops.NodeLabelInstanceGroup])
+	}
+	nodes := groups[1]
+	if !reflect.DeepEqual(nodes.Spec.Subnets, []string{"us-west-2a", "utility-us-west-2c"}) {
+		t.Errorf("unexpected instance group subnets %v", nodes.Spec.Subnets)
+	}
+	if nodes.Spec.Image != instanceGroups[1].Spec.Image {
+		t.Errorf("expected image by name to be kept, got %q", nodes.Spec.Image)
+	}
+
+	if cluster.Spec.Subnets[0].Zone != "us-east-1a" || instanceGroups[0].ObjectMeta.Name != "master-us-east-1a" {
+		t.Errorf("source cluster was modified")
+	}
+}
+
+func TestCloneClusterErrors(t *testing.T) {
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "example.com"},
+		Spec: kops.ClusterSpec{
+			CloudProvider: "aws",
+			NetworkCIDR:   "172.20.0.0/16",
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "us-east-1a", Zone: "us-east-1a", CIDR: "172.20.32.0/19"},
+			},
+		},
+	}
+
+	grid := []struct {
+		Description string
+		Options     CloneClusterOptions
+	}{
+		{
+			Description: "missing target name",
+			Options:     CloneClusterOptions{Region: "us-west-2"},
+		},
+		{
+			Description: "same name",
+			Options:     CloneClusterOptions{TargetName: "example.com", Region: "us-west-2"},
+		},
+		{
+			Description: "missing region",
+			Options:     CloneClusterOptions{TargetName: "dr.example.com"},
+		},
+		{
+			Description: "network size mismatch",
+			Options:     CloneClusterOptions{TargetName: "dr.example.com", Region: "us-west-2", NetworkCIDR: "10.0.0.0/8"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			if _, _, err := CloneCluster(cluster, nil, &g.Options); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestDefaultCloneOptionsFromExisting(t *testing.T) {
+	existing := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dr.example.com",
+			Annotations: map[string]string{
+				kops.AnnotationNameCloneSource:  "example.com",
+				kops.AnnotationNameCloneZoneMap: "us-east-1a=us-west-2b",
+			},
+		},
+		Spec: kops.ClusterSpec{
+			CloudProvider: "aws",
+			NetworkCIDR:   "172.21.0.0/16",
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "us-west-2b", Zone: "us-west-2b"},
+			},
+		},
+	}
+
+	options := &CloneClusterOptions{TargetName: "dr.example.com"}
+	if err := DefaultCloneOptionsFromExisting(existing, "example.com", options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &CloneClusterOptions{
+		TargetName:  "dr.example.com",
+		Region:      "us-west-2",
+		ZoneMap:     map[string]string{"us-east-1a": "us-west-2b"},
+		NetworkCIDR: "172.21.0.0/16",
+	}
+	if !reflect.DeepEqual(options, expected) {
+		t.Errorf("unexpected options: %+v", options)
+	}
+
+	if err := DefaultCloneOptionsFromExisting(existing, "other.example.com", &CloneClusterOptions{}); err == nil {
+		t.Errorf("expected error for cluster cloned from another source")
+	}
+}
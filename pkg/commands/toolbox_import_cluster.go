@@ -0,0 +1,571 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	clusterAutoscalerNodeTemplateTaint = "k8s.io/cluster-autoscaler/node-template/taint/"
+	subnetTypeTag                      = "SubnetType"
+	subnetInternalELBTag               = "kubernetes.io/role/internal-elb"
+)
+
+// ImportedClusterResources holds the live cloud resources of a cluster that its specification is rebuilt from.
+type ImportedClusterResources struct {
+	// ClusterName is the name of the cluster.
+	ClusterName string
+	// VPC is the network of the cluster.
+	VPC *ec2.Vpc
+	// Subnets are the subnets tagged for the cluster.
+	Subnets []*ec2.Subnet
+	// AutoscalingGroups are the autoscaling groups of the instance groups.
+	AutoscalingGroups []*autoscaling.Group
+	// LaunchTemplates holds the current launch template data, by autoscaling group name.
+	LaunchTemplates map[string]*ec2.ResponseLaunchTemplateData
+	// EtcdVolumes are the volumes holding the data of the etcd members.
+	EtcdVolumes []*ec2.Volume
+	// APILoadBalancer is the load balancer of the API, if it has one.
+	APILoadBalancer *ImportedLoadBalancer
+}
+
+// ImportedLoadBalancer describes a load balancer of a cluster.
+type ImportedLoadBalancer struct {
+	Class    api.LoadBalancerClass
+	Internal bool
+}
+
+// BuildImportedCluster rebuilds the specifications of a cluster and its instance groups from its cloud resources.
+// Only what can be determined from the resources is set; the rest is defaulted when the cluster is created.
+func BuildImportedCluster(r *ImportedClusterResources) (*api.Cluster, []*api.InstanceGroup, error) {
+	clusterName := r.ClusterName
+	if len(r.AutoscalingGroups) == 0 {
+		return nil, nil, fmt.Errorf("no autoscaling groups found for cluster %q", clusterName)
+	}
+
+	cluster := &api.Cluster{}
+	cluster.ObjectMeta.Name = clusterName
+	cluster.Spec.CloudProvider = string(api.CloudProviderAWS)
+
+	if r.VPC != nil {
+		cluster.Spec.NetworkCIDR = aws.StringValue(r.VPC.CidrBlock)
+		if isClusterOwned(r.VPC.Tags, clusterName) {
+			for _, association := range r.VPC.CidrBlockAssociationSet {
+				cidr := aws.StringValue(association.CidrBlock)
+				if cidr == cluster.Spec.NetworkCIDR || association.CidrBlockState == nil || aws.StringValue(association.CidrBlockState.State) != ec2.VpcCidrBlockStateCodeAssociated {
+					continue
+				}
+				cluster.Spec.AdditionalNetworkCIDRs = append(cluster.Spec.AdditionalNetworkCIDRs, cidr)
+			}
+		} else {
+			cluster.Spec.NetworkID = aws.StringValue(r.VPC.VpcId)
+		}
+	}
+
+	subnetNames := make(map[string]string)
+	subnetZones := make(map[string]string)
+	hasPrivateSubnets := false
+	for _, subnet := range r.Subnets {
+		spec := importSubnet(subnet, clusterName)
+		subnetNames[aws.StringValue(subnet.SubnetId)] = spec.Name
+		subnetZones[spec.Name] = spec.Zone
+		if spec.Type == api.SubnetTypePrivate {
+			hasPrivateSubnets = true
+		}
+		cluster.Spec.Subnets = append(cluster.Spec.Subnets, spec)
+	}
+	sort.Slice(cluster.Spec.Subnets, func(i, j int) bool {
+		return cluster.Spec.Subnets[i].Name < cluster.Spec.Subnets[j].Name
+	})
+
+	topology := api.TopologyPublic
+	if hasPrivateSubnets {
+		topology = api.TopologyPrivate
+	}
+	cluster.Spec.Topology = &api.TopologySpec{
+		Masters: topology,
+		Nodes:   topology,
+		DNS:     &api.DNSSpec{Type: api.DNSTypePublic},
+	}
+
+	if r.APILoadBalancer != nil {
+		lb := &api.LoadBalancerAccessSpec{
+			Class: r.APILoadBalancer.Class,
+			Type:  api.LoadBalancerTypePublic,
+		}
+		if r.APILoadBalancer.Internal {
+			lb.Type = api.LoadBalancerTypeInternal
+		}
+		cluster.Spec.API = &api.AccessSpec{LoadBalancer: lb}
+	} else {
+		cluster.Spec.API = &api.AccessSpec{DNS: &api.DNSAccessSpec{}}
+	}
+
+	var instanceGroups []*api.InstanceGroup
+	var controlPlaneUserData string
+	for _, asg := range r.AutoscalingGroups {
+		asgName := aws.StringValue(asg.AutoScalingGroupName)
+		lt := r.LaunchTemplates[asgName]
+		ig, err := importInstanceGroup(asg, lt, subnetNames, clusterName)
+		if err != nil {
+			return nil, nil, err
+		}
+		instanceGroups = append(instanceGroups, ig)
+
+		if lt != nil {
+			if cluster.Spec.SSHKeyName == nil && lt.KeyName != nil {
+				cluster.Spec.SSHKeyName = lt.KeyName
+			}
+			if ig.Spec.Role == api.InstanceGroupRoleMaster && controlPlaneUserData == "" {
+				userData, err := decodeUserData(aws.StringValue(lt.UserData))
+				if err != nil {
+					klog.Warningf("cannot read the user data of autoscaling group %q: %v", asgName, err)
+				}
+				controlPlaneUserData = userData
+			}
+		}
+
+		if ig.Spec.Role == api.InstanceGroupRoleBastion && cluster.Spec.Topology.Bastion == nil {
+			cluster.Spec.Topology.Bastion = &api.BastionSpec{
+				BastionPublicName: "bastion." + clusterName,
+			}
+		}
+	}
+	sort.Slice(instanceGroups, func(i, j int) bool {
+		return instanceGroups[i].ObjectMeta.Name < instanceGroups[j].ObjectMeta.Name
+	})
+
+	etcdClusters, err := importEtcdClusters(r.EtcdVolumes, instanceGroups, subnetZones, clusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster.Spec.EtcdClusters = etcdClusters
+
+	if controlPlaneUserData == "" {
+		klog.Warningf("cannot determine the Kubernetes version and networking of cluster %q; set them before creating the cluster", clusterName)
+		cluster.Spec.Networking = &api.NetworkingSpec{CNI: &api.CNINetworkingSpec{}}
+	} else {
+		importBootstrapClusterSpec(cluster, controlPlaneUserData)
+	}
+
+	return cluster, instanceGroups, nil
+}
+
+// importSubnet rebuilds the specification of a subnet; subnets that are not owned by the cluster are referenced by their ID
+func importSubnet(subnet *ec2.Subnet, clusterName string) api.ClusterSubnetSpec {
+	id := aws.StringValue(subnet.SubnetId)
+	tags := ec2TagMap(subnet.Tags)
+
+	spec := api.ClusterSubnetSpec{
+		Name: strings.TrimSuffix(tags["Name"], "."+clusterName),
+		Zone: aws.StringValue(subnet.AvailabilityZone),
+		CIDR: aws.StringValue(subnet.CidrBlock),
+		Type: api.SubnetType(tags[subnetTypeTag]),
+	}
+	if spec.Name == "" || spec.Name == tags["Name"] {
+		spec.Name = id
+	}
+	if spec.Type == "" {
+		if _, found := tags[subnetInternalELBTag]; found {
+			spec.Type = api.SubnetTypePrivate
+		} else {
+			spec.Type = api.SubnetTypePublic
+		}
+	}
+	for _, association := range subnet.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState != nil && aws.StringValue(association.Ipv6CidrBlockState.State) == ec2.SubnetCidrBlockStateCodeAssociated {
+			spec.IPv6CIDR = aws.StringValue(association.Ipv6CidrBlock)
+		}
+	}
+	if !isClusterOwned(subnet.Tags, clusterName) {
+		spec.ProviderID = id
+	}
+	return spec
+}
+
+// importInstanceGroup rebuilds the specification of an instance group from its autoscaling group and launch template
+func importInstanceGroup(asg *autoscaling.Group, lt *ec2.ResponseLaunchTemplateData, subnetNames map[string]string, clusterName string) (*api.InstanceGroup, error) {
+	asgName := aws.StringValue(asg.AutoScalingGroupName)
+	tags := make(map[string]string)
+	for _, tag := range asg.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+
+	ig := &api.InstanceGroup{}
+	ig.ObjectMeta.Labels = map[string]string{api.LabelClusterName: clusterName}
+	ig.ObjectMeta.Name = tags[nodeidentityaws.CloudTagInstanceGroupName]
+	if ig.ObjectMeta.Name == "" {
+		ig.ObjectMeta.Name = strings.SplitN(asgName, ".", 2)[0]
+	}
+
+	switch {
+	case hasTag(tags, awsup.TagNameRolePrefix+"master"):
+		ig.Spec.Role = api.InstanceGroupRoleMaster
+	case hasTag(tags, awsup.TagNameRolePrefix+"apiserver"):
+		ig.Spec.Role = api.InstanceGroupRoleAPIServer
+	case hasTag(tags, awsup.TagNameRolePrefix+"bastion"):
+		ig.Spec.Role = api.InstanceGroupRoleBastion
+	case hasTag(tags, awsup.TagNameRolePrefix+"node"):
+		ig.Spec.Role = api.InstanceGroupRoleNode
+	default:
+		return nil, fmt.Errorf("cannot determine the role of autoscaling group %q", asgName)
+	}
+
+	ig.Spec.MinSize = fi.Int32(int32(aws.Int64Value(asg.MinSize)))
+	ig.Spec.MaxSize = fi.Int32(int32(aws.Int64Value(asg.MaxSize)))
+
+	for _, subnetID := range strings.Split(aws.StringValue(asg.VPCZoneIdentifier), ",") {
+		if subnetID == "" {
+			continue
+		}
+		name, found := subnetNames[subnetID]
+		if !found {
+			return nil, fmt.Errorf("autoscaling group %q uses subnet %q, which is not tagged for the cluster", asgName, subnetID)
+		}
+		ig.Spec.Subnets = append(ig.Spec.Subnets, name)
+	}
+
+	for k, v := range tags {
+		switch {
+		case strings.HasPrefix(k, nodeidentityaws.ClusterAutoscalerNodeTemplateLabel):
+			label := strings.TrimPrefix(k, nodeidentityaws.ClusterAutoscalerNodeTemplateLabel)
+			if !isBuiltinNodeLabel(label) {
+				if ig.Spec.NodeLabels == nil {
+					ig.Spec.NodeLabels = make(map[string]string)
+				}
+				ig.Spec.NodeLabels[label] = v
+			}
+		case strings.HasPrefix(k, clusterAutoscalerNodeTemplateTaint):
+			key := strings.TrimPrefix(k, clusterAutoscalerNodeTemplateTaint)
+			if strings.HasPrefix(v, ":") {
+				ig.Spec.Taints = append(ig.Spec.Taints, key+v)
+			} else {
+				ig.Spec.Taints = append(ig.Spec.Taints, key+"="+v)
+			}
+		case !isBuiltinCloudTag(k, clusterName):
+			if ig.Spec.CloudLabels == nil {
+				ig.Spec.CloudLabels = make(map[string]string)
+			}
+			ig.Spec.CloudLabels[k] = v
+		}
+	}
+	sort.Strings(ig.Spec.Taints)
+
+	if policy := asg.MixedInstancesPolicy; policy != nil {
+		spec := &api.MixedInstancesPolicySpec{}
+		if policy.LaunchTemplate != nil {
+			for _, override := range policy.LaunchTemplate.Overrides {
+				spec.Instances = append(spec.Instances, aws.StringValue(override.InstanceType))
+			}
+		}
+		if distribution := policy.InstancesDistribution; distribution != nil {
+			spec.OnDemandAllocationStrategy = distribution.OnDemandAllocationStrategy
+			spec.OnDemandBase = distribution.OnDemandBaseCapacity
+			spec.OnDemandAboveBase = distribution.OnDemandPercentageAboveBaseCapacity
+			spec.SpotAllocationStrategy = distribution.SpotAllocationStrategy
+			spec.SpotInstancePools = distribution.SpotInstancePools
+		}
+		ig.Spec.MixedInstancesPolicy = spec
+	}
+
+	if lt == nil {
+		klog.Warningf("cannot find the launch template of autoscaling group %q; set its image and machine type", asgName)
+		return ig, nil
+	}
+
+	ig.Spec.Image = aws.StringValue(lt.ImageId)
+	ig.Spec.MachineType = aws.StringValue(lt.InstanceType)
+	if ig.Spec.MachineType == "" && ig.Spec.MixedInstancesPolicy != nil && len(ig.Spec.MixedInstancesPolicy.Instances) != 0 {
+		ig.Spec.MachineType = ig.Spec.MixedInstancesPolicy.Instances[0]
+	}
+
+	for _, mapping := range lt.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+		ig.Spec.RootVolumeSize = int32Value(mapping.Ebs.VolumeSize)
+		ig.Spec.RootVolumeType = mapping.Ebs.VolumeType
+		ig.Spec.RootVolumeIops = int32Value(mapping.Ebs.Iops)
+		ig.Spec.RootVolumeThroughput = int32Value(mapping.Ebs.Throughput)
+		ig.Spec.RootVolumeEncryption = mapping.Ebs.Encrypted
+		ig.Spec.RootVolumeEncryptionKey = mapping.Ebs.KmsKeyId
+		break
+	}
+
+	if len(lt.NetworkInterfaces) != 0 {
+		ig.Spec.AssociatePublicIP = lt.NetworkInterfaces[0].AssociatePublicIpAddress
+	}
+	if lt.Monitoring != nil && aws.BoolValue(lt.Monitoring.Enabled) {
+		ig.Spec.DetailedInstanceMonitoring = fi.Bool(true)
+	}
+	if lt.Placement != nil && aws.StringValue(lt.Placement.Tenancy) != "" && aws.StringValue(lt.Placement.Tenancy) != ec2.TenancyDefault {
+		ig.Spec.Tenancy = aws.StringValue(lt.Placement.Tenancy)
+	}
+	if lt.MetadataOptions != nil {
+		ig.Spec.InstanceMetadata = &api.InstanceMetadataOptions{
+			HTTPPutResponseHopLimit: lt.MetadataOptions.HttpPutResponseHopLimit,
+			HTTPTokens:              lt.MetadataOptions.HttpTokens,
+		}
+	}
+	if lt.InstanceMarketOptions != nil && lt.InstanceMarketOptions.SpotOptions != nil {
+		ig.Spec.MaxPrice = lt.InstanceMarketOptions.SpotOptions.MaxPrice
+	}
+
+	if lt.IamInstanceProfile != nil {
+		arn := aws.StringValue(lt.IamInstanceProfile.Arn)
+		if arn != "" && !strings.HasSuffix(arn, "/"+defaultIAMName(ig.Spec.Role, clusterName)) {
+			ig.Spec.IAM = &api.IAMProfileSpec{Profile: fi.String(arn)}
+		}
+	}
+
+	return ig, nil
+}
+
+// importEtcdClusters rebuilds the etcd clusters from the volumes of their members.
+// The volumes are tagged with k8s.io/etcd/<cluster>=<member>/<all members>.
+func importEtcdClusters(volumes []*ec2.Volume, instanceGroups []*api.InstanceGroup, subnetZones map[string]string, clusterName string) ([]api.EtcdClusterSpec, error) {
+	igByZone := make(map[string]string)
+	for _, ig := range instanceGroups {
+		if ig.Spec.Role != api.InstanceGroupRoleMaster {
+			continue
+		}
+		for _, subnet := range ig.Spec.Subnets {
+			zone := subnetZones[subnet]
+			if _, found := igByZone[zone]; !found {
+				igByZone[zone] = ig.ObjectMeta.Name
+			}
+		}
+	}
+
+	clusters := make(map[string]*api.EtcdClusterSpec)
+	for _, volume := range volumes {
+		volumeID := aws.StringValue(volume.VolumeId)
+		for _, tag := range volume.Tags {
+			key := aws.StringValue(tag.Key)
+			if !strings.HasPrefix(key, awsup.TagNameEtcdClusterPrefix) {
+				continue
+			}
+			etcdClusterName := strings.TrimPrefix(key, awsup.TagNameEtcdClusterPrefix)
+			memberName := strings.SplitN(aws.StringValue(tag.Value), "/", 2)[0]
+			if memberName == "" {
+				return nil, fmt.Errorf("cannot determine the etcd member of volume %q", volumeID)
+			}
+
+			zone := aws.StringValue(volume.AvailabilityZone)
+			ig, found := igByZone[zone]
+			if !found {
+				return nil, fmt.Errorf("cannot find a control plane instance group in zone %q for etcd volume %q", zone, volumeID)
+			}
+
+			etcdCluster := clusters[etcdClusterName]
+			if etcdCluster == nil {
+				etcdCluster = &api.EtcdClusterSpec{Name: etcdClusterName}
+				clusters[etcdClusterName] = etcdCluster
+			}
+			member := api.EtcdMemberSpec{
+				Name:          memberName,
+				InstanceGroup: fi.String(ig),
+				VolumeType:    volume.VolumeType,
+				VolumeSize:    int32Value(volume.Size),
+			}
+			if aws.BoolValue(volume.Encrypted) {
+				member.EncryptedVolume = fi.Bool(true)
+				member.KmsKeyId = volume.KmsKeyId
+			}
+			if aws.StringValue(volume.VolumeType) != ec2.VolumeTypeGp2 {
+				member.VolumeIops = int32Value(volume.Iops)
+				member.VolumeThroughput = int32Value(volume.Throughput)
+			}
+			etcdCluster.Members = append(etcdCluster.Members, member)
+		}
+	}
+
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("no etcd volumes found for cluster %q", clusterName)
+	}
+
+	var etcdClusters []api.EtcdClusterSpec
+	for _, etcdCluster := range clusters {
+		sort.Slice(etcdCluster.Members, func(i, j int) bool {
+			return etcdCluster.Members[i].Name < etcdCluster.Members[j].Name
+		})
+		etcdClusters = append(etcdClusters, *etcdCluster)
+	}
+	sort.Slice(etcdClusters, func(i, j int) bool {
+		return etcdClusters[i].Name < etcdClusters[j].Name
+	})
+	return etcdClusters, nil
+}
+
+var (
+	clusterSpecUserDataRegexp           = regexp.MustCompile(`(?s)cat > conf/cluster_spec.yaml << '__EOF_CLUSTER_SPEC'\n(.*?)\n__EOF_CLUSTER_SPEC`)
+	compressedClusterSpecUserDataRegexp = regexp.MustCompile(`echo "([A-Za-z0-9+/=]+)" \| base64 -d \| gzip -d > conf/cluster_spec.yaml`)
+	kubernetesVersionImageRegexp        = regexp.MustCompile(`/kube-apiserver(?:-[a-z0-9]+)?:v?([0-9]+\.[0-9]+\.[0-9]+(?:-[0-9A-Za-z.]+)?)`)
+)
+
+// importBootstrapClusterSpec sets the Kubernetes version, container runtime and networking of the cluster
+// from the cluster spec that is embedded in the user data of the control plane
+func importBootstrapClusterSpec(cluster *api.Cluster, userData string) {
+	var specYAML string
+	if match := clusterSpecUserDataRegexp.FindStringSubmatch(userData); match != nil {
+		specYAML = match[1]
+	} else if match := compressedClusterSpecUserDataRegexp.FindStringSubmatch(userData); match != nil {
+		decoded, err := gunzipBase64(match[1])
+		if err != nil {
+			klog.Warningf("cannot decompress the cluster spec in the user data: %v", err)
+		}
+		specYAML = decoded
+	}
+
+	spec := &api.ClusterSpec{}
+	if specYAML != "" {
+		if err := yaml.Unmarshal([]byte(specYAML), spec); err != nil {
+			klog.Warningf("cannot parse the cluster spec in the user data: %v", err)
+		}
+	}
+
+	cluster.Spec.ContainerRuntime = spec.ContainerRuntime
+
+	if spec.KubeAPIServer != nil {
+		if match := kubernetesVersionImageRegexp.FindStringSubmatch(spec.KubeAPIServer.Image); match != nil {
+			cluster.Spec.KubernetesVersion = match[1]
+		}
+	}
+	if cluster.Spec.KubernetesVersion == "" {
+		klog.Warningf("cannot determine the Kubernetes version of cluster %q; set it before creating the cluster", cluster.ObjectMeta.Name)
+	}
+
+	if spec.Kubelet != nil && spec.Kubelet.NetworkPluginName == "kubenet" {
+		cluster.Spec.Networking = &api.NetworkingSpec{Kubenet: &api.KubenetNetworkingSpec{}}
+	} else {
+		klog.Warningf("cannot determine the CNI of cluster %q; set spec.networking before creating the cluster", cluster.ObjectMeta.Name)
+		cluster.Spec.Networking = &api.NetworkingSpec{CNI: &api.CNINetworkingSpec{}}
+	}
+}
+
+// decodeUserData decodes the base64 user data of a launch template, which may also be gzipped
+func decodeUserData(encoded string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return "", err
+		}
+		data, err = ioutil.ReadAll(r)
+		if err != nil {
+			return "", err
+		}
+	}
+	return string(data), nil
+}
+
+func gunzipBase64(encoded string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// defaultIAMName returns the name of the IAM instance profile that kOps creates for a role
+func defaultIAMName(role api.InstanceGroupRole, clusterName string) string {
+	switch role {
+	case api.InstanceGroupRoleMaster:
+		return "masters." + clusterName
+	case api.InstanceGroupRoleAPIServer:
+		return "apiservers." + clusterName
+	case api.InstanceGroupRoleBastion:
+		return "bastions." + clusterName
+	default:
+		return "nodes." + clusterName
+	}
+}
+
+// isBuiltinNodeLabel returns true for the node labels that kOps sets itself
+func isBuiltinNodeLabel(label string) bool {
+	return strings.HasPrefix(label, "node-role.kubernetes.io/") ||
+		label == "kubernetes.io/role" ||
+		label == nodeidentityaws.CloudTagInstanceGroupName ||
+		label == "kops.k8s.io/kops-controller-pki" ||
+		label == "node.kubernetes.io/exclude-from-external-load-balancers"
+}
+
+// isBuiltinCloudTag returns true for the tags that kOps sets itself on autoscaling groups
+func isBuiltinCloudTag(key string, clusterName string) bool {
+	return key == "Name" ||
+		key == awsup.TagClusterName ||
+		key == awsup.TagNameKopsRole ||
+		key == awsup.TagNameClusterOwnershipPrefix+clusterName ||
+		key == nodeidentityaws.CloudTagInstanceGroupName ||
+		key == "aws-node-termination-handler/managed" ||
+		strings.HasPrefix(key, awsup.TagNameRolePrefix) ||
+		strings.HasPrefix(key, "k8s.io/cluster-autoscaler/") ||
+		strings.HasPrefix(key, "aws:")
+}
+
+func isClusterOwned(tags []*ec2.Tag, clusterName string) bool {
+	return ec2TagMap(tags)[awsup.TagNameClusterOwnershipPrefix+clusterName] == "owned"
+}
+
+func hasTag(tags map[string]string, key string) bool {
+	_, found := tags[key]
+	return found
+}
+
+func ec2TagMap(tags []*ec2.Tag) map[string]string {
+	m := make(map[string]string)
+	for _, tag := range tags {
+		m[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return m
+}
+
+func int32Value(v *int64) *int32 {
+	if v == nil || *v == 0 {
+		return nil
+	}
+	return fi.Int32(int32(*v))
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+	resourcesaws "k8s.io/kops/pkg/resources/aws"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// DiscoverClusterAWS finds the live resources of a cluster on AWS by their tags.
+// The cloud must be built with the tags of the cluster.
+func DiscoverClusterAWS(cloud awsup.AWSCloud, clusterName string) (*ImportedClusterResources, error) {
+	r := &ImportedClusterResources{
+		ClusterName:     clusterName,
+		LaunchTemplates: make(map[string]*ec2.ResponseLaunchTemplateData),
+	}
+
+	// Shared subnets are only tagged with the ownership tag of the cluster
+	subnets := make(map[string]*ec2.Subnet)
+	for _, filter := range []*ec2.Filter{
+		awsup.NewEC2Filter("tag:"+awsup.TagClusterName, clusterName),
+		{Name: aws.String("tag-key"), Values: aws.StringSlice([]string{awsup.TagNameClusterOwnershipPrefix + clusterName})},
+	} {
+		response, err := cloud.EC2().DescribeSubnets(&ec2.DescribeSubnetsInput{Filters: []*ec2.Filter{filter}})
+		if err != nil {
+			return nil, fmt.Errorf("error listing subnets: %v", err)
+		}
+		for _, subnet := range response.Subnets {
+			subnets[aws.StringValue(subnet.SubnetId)] = subnet
+		}
+	}
+	if len(subnets) == 0 {
+		return nil, fmt.Errorf("no subnets found for cluster %q", clusterName)
+	}
+
+	vpcID := ""
+	for _, subnet := range subnets {
+		r.Subnets = append(r.Subnets, subnet)
+		if vpcID != "" && vpcID != aws.StringValue(subnet.VpcId) {
+			return nil, fmt.Errorf("the subnets of cluster %q are in several VPCs", clusterName)
+		}
+		vpcID = aws.StringValue(subnet.VpcId)
+	}
+
+	{
+		response, err := cloud.EC2().DescribeVpcs(&ec2.DescribeVpcsInput{VpcIds: []*string{aws.String(vpcID)}})
+		if err != nil {
+			return nil, fmt.Errorf("error describing VPC %q: %v", vpcID, err)
+		}
+		if len(response.Vpcs) != 1 {
+			return nil, fmt.Errorf("VPC %q not found", vpcID)
+		}
+		r.VPC = response.Vpcs[0]
+	}
+
+	asgs, err := awsup.FindAutoscalingGroups(cloud, map[string]string{awsup.TagClusterName: clusterName})
+	if err != nil {
+		return nil, err
+	}
+	r.AutoscalingGroups = asgs
+
+	for _, asg := range asgs {
+		spec := asg.LaunchTemplate
+		if spec == nil && asg.MixedInstancesPolicy != nil && asg.MixedInstancesPolicy.LaunchTemplate != nil {
+			spec = asg.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification
+		}
+		if spec == nil {
+			klog.Warningf("autoscaling group %q does not use a launch template", aws.StringValue(asg.AutoScalingGroupName))
+			continue
+		}
+		data, err := describeLaunchTemplateData(cloud, spec)
+		if err != nil {
+			return nil, err
+		}
+		r.LaunchTemplates[aws.StringValue(asg.AutoScalingGroupName)] = data
+	}
+
+	{
+		request := &ec2.DescribeVolumesInput{
+			Filters: []*ec2.Filter{
+				awsup.NewEC2Filter("tag:"+awsup.TagClusterName, clusterName),
+				{Name: aws.String("tag-key"), Values: aws.StringSlice([]string{awsup.TagNameRolePrefix + awsup.TagRoleMaster})},
+			},
+		}
+		err := cloud.EC2().DescribeVolumesPages(request, func(p *ec2.DescribeVolumesOutput, lastPage bool) bool {
+			r.EtcdVolumes = append(r.EtcdVolumes, p.Volumes...)
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing etcd volumes: %v", err)
+		}
+	}
+
+	apiName := "api." + clusterName
+	elbs, elbTags, err := resourcesaws.DescribeELBs(cloud)
+	if err != nil {
+		return nil, err
+	}
+	for _, lb := range elbs {
+		if resourcesaws.FindELBName(elbTags[aws.StringValue(lb.LoadBalancerName)]) == apiName {
+			r.APILoadBalancer = &ImportedLoadBalancer{
+				Class:    api.LoadBalancerClassClassic,
+				Internal: aws.StringValue(lb.Scheme) == "internal",
+			}
+		}
+	}
+	nlbs, nlbTags, err := resourcesaws.DescribeELBV2s(cloud)
+	if err != nil {
+		return nil, err
+	}
+	for _, lb := range nlbs {
+		if aws.StringValue(lb.Type) == elbv2.LoadBalancerTypeEnumNetwork && resourcesaws.FindELBV2Name(nlbTags[aws.StringValue(lb.LoadBalancerArn)]) == apiName {
+			r.APILoadBalancer = &ImportedLoadBalancer{
+				Class:    api.LoadBalancerClassNetwork,
+				Internal: aws.StringValue(lb.Scheme) == elbv2.LoadBalancerSchemeEnumInternal,
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// describeLaunchTemplateData returns the data of the version of a launch template used by an autoscaling group
+func describeLaunchTemplateData(cloud awsup.AWSCloud, spec *autoscaling.LaunchTemplateSpecification) (*ec2.ResponseLaunchTemplateData, error) {
+	version := aws.StringValue(spec.Version)
+	if version == "" {
+		version = "$Default"
+	}
+	request := &ec2.DescribeLaunchTemplateVersionsInput{
+		Versions: []*string{aws.String(version)},
+	}
+	if spec.LaunchTemplateId != nil {
+		request.LaunchTemplateId = spec.LaunchTemplateId
+	} else {
+		request.LaunchTemplateName = spec.LaunchTemplateName
+	}
+
+	response, err := cloud.EC2().DescribeLaunchTemplateVersions(request)
+	if err != nil {
+		return nil, fmt.Errorf("error describing launch template %q: %v", aws.StringValue(spec.LaunchTemplateName), err)
+	}
+	if len(response.LaunchTemplateVersions) != 1 {
+		return nil, fmt.Errorf("version %q of launch template %q not found", version, aws.StringValue(spec.LaunchTemplateName))
+	}
+	return response.LaunchTemplateVersions[0].LaunchTemplateData, nil
+}
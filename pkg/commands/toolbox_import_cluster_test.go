@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func ec2Tags(tags map[string]string) []*ec2.Tag {
+	var l []*ec2.Tag
+	for k, v := range tags {
+		l = append(l, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return l
+}
+
+func asgTags(tags map[string]string) []*autoscaling.TagDescription {
+	var l []*autoscaling.TagDescription
+	for k, v := range tags {
+		l = append(l, &autoscaling.TagDescription{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return l
+}
+
+func TestBuildImportedCluster(t *testing.T) {
+	clusterName := "example.com"
+	controlPlaneUserData := `#!/bin/bash
+cat > conf/cluster_spec.yaml << '__EOF_CLUSTER_SPEC'
+containerRuntime: containerd
+kubeAPIServer:
+  image: k8s.gcr.io/kube-apiserver:v1.21.2@sha256:0123
+kubelet:
+  networkPluginName: kubenet
+__EOF_CLUSTER_SPEC
+`
+
+	r := &ImportedClusterResources{
+		ClusterName: clusterName,
+		VPC: &ec2.Vpc{
+			VpcId:     aws.String("vpc-1"),
+			CidrBlock: aws.String("172.20.0.0/16"),
+			Tags:      ec2Tags(map[string]string{"kubernetes.io/cluster/example.com": "owned"}),
+		},
+		Subnets: []*ec2.Subnet{
+			{
+				SubnetId:         aws.String("subnet-a"),
+				AvailabilityZone: aws.String("us-test-1a"),
+				CidrBlock:        aws.String("172.20.32.0/19"),
+				Tags: ec2Tags(map[string]string{
+					"Name":                              "us-test-1a.example.com",
+					"SubnetType":                        "Private",
+					"kubernetes.io/cluster/example.com": "owned",
+				}),
+			},
+			{
+				SubnetId:         aws.String("subnet-shared"),
+				AvailabilityZone: aws.String("us-test-1a"),
+				CidrBlock:        aws.String("172.20.0.0/22"),
+				Tags: ec2Tags(map[string]string{
+					"Name":                              "shared-utility",
+					"kubernetes.io/cluster/example.com": "shared",
+				}),
+			},
+		},
+		AutoscalingGroups: []*autoscaling.Group{
+			{
+				AutoScalingGroupName: aws.String("master-us-test-1a.masters.example.com"),
+				MinSize:              aws.Int64(1),
+				MaxSize:              aws.Int64(1),
+				VPCZoneIdentifier:    aws.String("subnet-a"),
+				Tags: asgTags(map[string]string{
+					"KubernetesCluster":         clusterName,
+					"k8s.io/role/master":        "1",
+					"kops.k8s.io/instancegroup": "master-us-test-1a",
+					"k8s.io/cluster-autoscaler/node-template/label/kubernetes.io/role": "master",
+				}),
+			},
+			{
+				AutoScalingGroupName: aws.String("nodes.example.com"),
+				MinSize:              aws.Int64(2),
+				MaxSize:              aws.Int64(5),
+				VPCZoneIdentifier:    aws.String("subnet-a"),
+				Tags: asgTags(map[string]string{
+					"KubernetesCluster":         clusterName,
+					"k8s.io/role/node":          "1",
+					"kops.k8s.io/instancegroup": "nodes",
+					"team":                      "platform",
+					"k8s.io/cluster-autoscaler/node-template/label/example.com/pool":      "general",
+					"k8s.io/cluster-autoscaler/node-template/taint/dedicated":             "general:NoSchedule",
+					"k8s.io/cluster-autoscaler/node-template/taint/spot":                  ":PreferNoSchedule",
+					"k8s.io/cluster-autoscaler/enabled":                                   "",
+					"k8s.io/cluster-autoscaler/node-template/resources/ephemeral-storage": "64Gi",
+				}),
+			},
+		},
+		LaunchTemplates: map[string]*ec2.ResponseLaunchTemplateData{
+			"master-us-test-1a.masters.example.com": {
+				ImageId:      aws.String("ami-1"),
+				InstanceType: aws.String("m5.large"),
+				KeyName:      aws.String("kubernetes.example.com-c4:a6:ed"),
+				UserData:     aws.String(base64.StdEncoding.EncodeToString([]byte(controlPlaneUserData))),
+				IamInstanceProfile: &ec2.LaunchTemplateIamInstanceProfileSpecification{
+					Arn: aws.String("arn:aws:iam::123:instance-profile/masters.example.com"),
+				},
+			},
+			"nodes.example.com": {
+				ImageId:      aws.String("ami-1"),
+				InstanceType: aws.String("t3.medium"),
+				BlockDeviceMappings: []*ec2.LaunchTemplateBlockDeviceMapping{
+					{DeviceName: aws.String("/dev/sda1"), Ebs: &ec2.LaunchTemplateEbsBlockDevice{VolumeSize: aws.Int64(64), VolumeType: aws.String("gp3")}},
+				},
+				IamInstanceProfile: &ec2.LaunchTemplateIamInstanceProfileSpecification{
+					Arn: aws.String("arn:aws:iam::123:instance-profile/custom"),
+				},
+			},
+		},
+		EtcdVolumes: []*ec2.Volume{
+			{
+				VolumeId:         aws.String("vol-1"),
+				AvailabilityZone: aws.String("us-test-1a"),
+				VolumeType:       aws.String("gp2"),
+				Size:             aws.Int64(20),
+				Tags:             ec2Tags(map[string]string{"k8s.io/etcd/main": "a/a"}),
+			},
+			{
+				VolumeId:         aws.String("vol-2"),
+				AvailabilityZone: aws.String("us-test-1a"),
+				VolumeType:       aws.String("gp2"),
+				Size:             aws.Int64(20),
+				Tags:             ec2Tags(map[string]string{"k8s.io/etcd/events": "a/a"}),
+			},
+		},
+		APILoadBalancer: &ImportedLoadBalancer{Class: kops.LoadBalancerClassNetwork},
+	}
+
+	cluster, instanceGroups, err := BuildImportedCluster(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cluster.Spec.KubernetesVersion != "1.21.2" {
+		t.Errorf("unexpected KubernetesVersion %q", cluster.Spec.KubernetesVersion)
+	}
+	if cluster.Spec.ContainerRuntime != "containerd" {
+		t.Errorf("unexpected ContainerRuntime %q", cluster.Spec.ContainerRuntime)
+	}
+	if cluster.Spec.Networking == nil || cluster.Spec.Networking.Kubenet == nil {
+		t.Errorf("expected kubenet networking, got %+v", cluster.Spec.Networking)
+	}
+	if cluster.Spec.NetworkCIDR != "172.20.0.0/16" || cluster.Spec.NetworkID != "" {
+		t.Errorf("unexpected network %q/%q", cluster.Spec.NetworkCIDR, cluster.Spec.NetworkID)
+	}
+	if cluster.Spec.Topology.Masters != kops.TopologyPrivate {
+		t.Errorf("unexpected topology %q", cluster.Spec.Topology.Masters)
+	}
+	if cluster.Spec.API.LoadBalancer == nil || cluster.Spec.API.LoadBalancer.Class != kops.LoadBalancerClassNetwork || cluster.Spec.API.LoadBalancer.Type != kops.LoadBalancerTypePublic {
+		t.Errorf("unexpected API %+v", cluster.Spec.API)
+	}
+	if fi.StringValue(cluster.Spec.SSHKeyName) != "kubernetes.example.com-c4:a6:ed" {
+		t.Errorf("unexpected SSHKeyName %q", fi.StringValue(cluster.Spec.SSHKeyName))
+	}
+
+	expectedSubnets := []kops.ClusterSubnetSpec{
+		{Name: "subnet-shared", Zone: "us-test-1a", CIDR: "172.20.0.0/22", Type: kops.SubnetTypePublic, ProviderID: "subnet-shared"},
+		{Name: "us-test-1a", Zone: "us-test-1a", CIDR: "172.20.32.0/19", Type: kops.SubnetTypePrivate},
+	}
+	if !reflect.DeepEqual(cluster.Spec.Subnets, expectedSubnets) {
+		t.Errorf("unexpected subnets %+v", cluster.Spec.Subnets)
+	}
+
+	expectedEtcd := []kops.EtcdClusterSpec{
+		{Name: "events", Members: []kops.EtcdMemberSpec{{Name: "a", InstanceGroup: fi.String("master-us-test-1a"), VolumeType: fi.String("gp2"), VolumeSize: fi.Int32(20)}}},
+		{Name: "main", Members: []kops.EtcdMemberSpec{{Name: "a", InstanceGroup: fi.String("master-us-test-1a"), VolumeType: fi.String("gp2"), VolumeSize: fi.Int32(20)}}},
+	}
+	if !reflect.DeepEqual(cluster.Spec.EtcdClusters, expectedEtcd) {
+		t.Errorf("unexpected etcd clusters %+v", cluster.Spec.EtcdClusters)
+	}
+
+	if len(instanceGroups) != 2 {
+		t.Fatalf("expected 2 instance groups, got %d", len(instanceGroups))
+	}
+	master, nodes := instanceGroups[0], instanceGroups[1]
+	if master.ObjectMeta.Name != "master-us-test-1a" || master.Spec.Role != kops.InstanceGroupRoleMaster {
+		t.Errorf("unexpected control plane instance group %+v", master)
+	}
+	if master.Spec.IAM != nil || master.Spec.NodeLabels != nil || master.Spec.CloudLabels != nil {
+		t.Errorf("unexpected fields set on control plane instance group %+v", master.Spec)
+	}
+	if master.ObjectMeta.Labels[kops.LabelClusterName] != clusterName {
+		t.Errorf("unexpected labels %v", master.ObjectMeta.Labels)
+	}
+
+	if nodes.ObjectMeta.Name != "nodes" || nodes.Spec.Role != kops.InstanceGroupRoleNode {
+		t.Errorf("unexpected node instance group %+v", nodes)
+	}
+	if fi.Int32Value(nodes.Spec.MinSize) != 2 || fi.Int32Value(nodes.Spec.MaxSize) != 5 {
+		t.Errorf("unexpected sizes %d/%d", fi.Int32Value(nodes.Spec.MinSize), fi.Int32Value(nodes.Spec.MaxSize))
+	}
+	if nodes.Spec.MachineType != "t3.medium" || fi.Int32Value(nodes.Spec.RootVolumeSize) != 64 || fi.StringValue(nodes.Spec.RootVolumeType) != "gp3" {
+		t.Errorf("unexpected machine %+v", nodes.Spec)
+	}
+	if !reflect.DeepEqual(nodes.Spec.Subnets, []string{"us-test-1a"}) {
+		t.Errorf("unexpected subnets %v", nodes.Spec.Subnets)
+	}
+	if !reflect.DeepEqual(nodes.Spec.NodeLabels, map[string]string{"example.com/pool": "general"}) {
+		t.Errorf("unexpected node labels %v", nodes.Spec.NodeLabels)
+	}
+	if !reflect.DeepEqual(nodes.Spec.Taints, []string{"dedicated=general:NoSchedule", "spot:PreferNoSchedule"}) {
+		t.Errorf("unexpected taints %v", nodes.Spec.Taints)
+	}
+	if !reflect.DeepEqual(nodes.Spec.CloudLabels, map[string]string{"team": "platform"}) {
+		t.Errorf("unexpected cloud labels %v", nodes.Spec.CloudLabels)
+	}
+	if nodes.Spec.IAM == nil || fi.StringValue(nodes.Spec.IAM.Profile) != "arn:aws:iam::123:instance-profile/custom" {
+		t.Errorf("unexpected IAM %+v", nodes.Spec.IAM)
+	}
+}
+
+func TestBuildImportedClusterWithoutAutoscalingGroups(t *testing.T) {
+	_, _, err := BuildImportedCluster(&ImportedClusterResources{ClusterName: "example.com"})
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cosign verifies container image signatures made with sigstore's cosign,
+// for the key-based signatures cosign stores alongside the image in its registry.
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// SignatureAnnotation is the layer annotation holding the base64 encoded signature of the layer.
+const SignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// ParsePublicKey parses a PEM encoded ECDSA public key, the kind of key generated by `cosign generate-key-pair`.
+func ParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %v", err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T, expected ECDSA", key)
+	}
+	return ecdsaKey, nil
+}
+
+// SignatureTag returns the tag cosign stores the signatures of the image with the given digest at.
+func SignatureTag(repository name.Repository, digest v1.Hash) name.Tag {
+	return repository.Tag(digest.Algorithm + "-" + digest.Hex + ".sig")
+}
+
+// payload is the "simple signing" payload signed by cosign.
+type payload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// VerifyPayload checks that signature is a signature of the payload made with one of the keys,
+// and that the payload is a signature of the image with the given digest.
+func VerifyPayload(data []byte, signature []byte, digest v1.Hash, keys []*ecdsa.PublicKey) error {
+	hash := sha256.Sum256(data)
+	verified := false
+	for _, key := range keys {
+		if ecdsa.VerifyASN1(key, hash[:], signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return fmt.Errorf("signature does not match any trusted key")
+	}
+
+	p := &payload{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("error parsing signature payload: %v", err)
+	}
+	if p.Critical.Image.DockerManifestDigest != digest.String() {
+		return fmt.Errorf("signature is for digest %q, not %q", p.Critical.Image.DockerManifestDigest, digest)
+	}
+	return nil
+}
+
+// Verify checks that the image with the given digest in the repository has a cosign signature
+// made with one of the keys.
+func Verify(repository name.Repository, digest v1.Hash, keys []*ecdsa.PublicKey, options ...remote.Option) error {
+	tag := SignatureTag(repository, digest)
+	image, err := remote.Image(tag, options...)
+	if err != nil {
+		return fmt.Errorf("error fetching signatures %q: %v", tag, err)
+	}
+	manifest, err := image.Manifest()
+	if err != nil {
+		return fmt.Errorf("error reading signatures %q: %v", tag, err)
+	}
+
+	var problems []string
+	for _, layer := range manifest.Layers {
+		encoded := layer.Annotations[SignatureAnnotation]
+		if encoded == "" {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid signature encoding: %v", err))
+			continue
+		}
+		data, err := readLayer(image, layer.Digest)
+		if err != nil {
+			return fmt.Errorf("error reading signature payload from %q: %v", tag, err)
+		}
+		if err := VerifyPayload(data, signature, digest, keys); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		return nil
+	}
+
+	if len(problems) == 0 {
+		return fmt.Errorf("no signatures found in %q", tag)
+	}
+	return fmt.Errorf("no valid signature found in %q: %s", tag, strings.Join(problems, "; "))
+}
+
+func readLayer(image v1.Image, digest v1.Hash) ([]byte, error) {
+	layer, err := image.LayerByDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+	r, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+const imageDigest = "sha256:0123456789012345678901234567890123456789012345678901234567890123"
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	return key
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, digest string) ([]byte, []byte) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"example.com/image"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, digest))
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatalf("error signing: %v", err)
+	}
+	return payload, signature
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key := generateKey(t)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("error marshalling key: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	parsed, err := ParsePublicKey(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !parsed.Equal(&key.PublicKey) {
+		t.Errorf("parsed key does not match")
+	}
+
+	if _, err := ParsePublicKey([]byte("not a key")); err == nil {
+		t.Errorf("expected error parsing invalid key")
+	}
+}
+
+func TestVerifyPayload(t *testing.T) {
+	trusted := generateKey(t)
+	untrusted := generateKey(t)
+	digest, _ := v1.NewHash(imageDigest)
+	otherDigest, _ := v1.NewHash("sha256:" + strings.Repeat("f", 64))
+
+	grid := []struct {
+		name     string
+		signer   *ecdsa.PrivateKey
+		digest   v1.Hash
+		expected string
+	}{
+		{
+			name:   "valid",
+			signer: trusted,
+			digest: digest,
+		},
+		{
+			name:     "untrusted key",
+			signer:   untrusted,
+			digest:   digest,
+			expected: "signature does not match any trusted key",
+		},
+		{
+			name:     "other image",
+			signer:   trusted,
+			digest:   otherDigest,
+			expected: fmt.Sprintf("signature is for digest %q, not %q", imageDigest, otherDigest),
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			payload, signature := sign(t, g.signer, imageDigest)
+			err := VerifyPayload(payload, signature, g.digest, []*ecdsa.PublicKey{&trusted.PublicKey})
+			if g.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != g.expected {
+				t.Errorf("expected error %q, got %v", g.expected, err)
+			}
+		})
+	}
+}
+
+// signatureRegistry serves the cosign signature of imageDigest in the repository "image".
+func signatureRegistry(t *testing.T, payload []byte, signature []byte) *httptest.Server {
+	payloadDigest := fmt.Sprintf("sha256:%x", sha256.Sum256(payload))
+	config := []byte("{}")
+	configDigest := fmt.Sprintf("sha256:%x", sha256.Sum256(config))
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     types.OCIManifestSchema1,
+		"config": map[string]interface{}{
+			"mediaType": types.OCIConfigJSON,
+			"size":      len(config),
+			"digest":    configDigest,
+		},
+		"layers": []map[string]interface{}{
+			{
+				"mediaType": "application/vnd.dev.cosign.simplesigning.v1+json",
+				"size":      len(payload),
+				"digest":    payloadDigest,
+				"annotations": map[string]string{
+					SignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error building manifest: %v", err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/image/manifests/" + strings.Replace(imageDigest, ":", "-", 1) + ".sig":
+			w.Header().Set("Content-Type", string(types.OCIManifestSchema1))
+			w.Write(manifest)
+		case "/v2/image/blobs/" + payloadDigest:
+			w.Write(payload)
+		case "/v2/image/blobs/" + configDigest:
+			w.Write(config)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestVerify(t *testing.T) {
+	trusted := generateKey(t)
+	untrusted := generateKey(t)
+	digest, _ := v1.NewHash(imageDigest)
+
+	payload, signature := sign(t, trusted, imageDigest)
+	server := signatureRegistry(t, payload, signature)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	repository, err := name.NewRepository(u.Host+"/image", name.Insecure)
+	if err != nil {
+		t.Fatalf("error parsing repository: %v", err)
+	}
+
+	if err := Verify(repository, digest, []*ecdsa.PublicKey{&untrusted.PublicKey, &trusted.PublicKey}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err = Verify(repository, digest, []*ecdsa.PublicKey{&untrusted.PublicKey})
+	if err == nil || !strings.Contains(err.Error(), "signature does not match any trusted key") {
+		t.Errorf("expected untrusted signature error, got %v", err)
+	}
+
+	unsigned, _ := v1.NewHash("sha256:" + strings.Repeat("f", 64))
+	if err := Verify(repository, unsigned, []*ecdsa.PublicKey{&trusted.PublicKey}); err == nil {
+		t.Errorf("expected error verifying unsigned image")
+	}
+}
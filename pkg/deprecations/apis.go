@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecations
+
+import (
+	"fmt"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RemovedAPI is a version of a resource that is no longer served from a Kubernetes release onwards
+type RemovedAPI struct {
+	schema.GroupVersionResource
+
+	// RemovedIn is the Kubernetes minor release no longer serving the API
+	RemovedIn string
+	// Replacement is the API version to migrate to
+	Replacement string
+}
+
+// APIVersion returns the API version of the removed API, as found in manifests
+func (a *RemovedAPI) APIVersion() string {
+	return a.GroupVersion().String()
+}
+
+func (a *RemovedAPI) String() string {
+	return fmt.Sprintf("%s %s", a.APIVersion(), a.Resource)
+}
+
+func removed(group, version, resource, removedIn, replacement string) RemovedAPI {
+	return RemovedAPI{
+		GroupVersionResource: schema.GroupVersionResource{Group: group, Version: version, Resource: resource},
+		RemovedIn:            removedIn,
+		Replacement:          replacement,
+	}
+}
+
+// RemovedAPIs are the removals announced in the Kubernetes deprecation guide
+var RemovedAPIs = []RemovedAPI{
+	removed("extensions", "v1beta1", "daemonsets", "1.16", "apps/v1"),
+	removed("extensions", "v1beta1", "deployments", "1.16", "apps/v1"),
+	removed("extensions", "v1beta1", "networkpolicies", "1.16", "networking.k8s.io/v1"),
+	removed("extensions", "v1beta1", "podsecuritypolicies", "1.16", "policy/v1beta1"),
+	removed("extensions", "v1beta1", "replicasets", "1.16", "apps/v1"),
+	removed("apps", "v1beta1", "deployments", "1.16", "apps/v1"),
+	removed("apps", "v1beta1", "statefulsets", "1.16", "apps/v1"),
+	removed("apps", "v1beta2", "daemonsets", "1.16", "apps/v1"),
+	removed("apps", "v1beta2", "deployments", "1.16", "apps/v1"),
+	removed("apps", "v1beta2", "replicasets", "1.16", "apps/v1"),
+	removed("apps", "v1beta2", "statefulsets", "1.16", "apps/v1"),
+
+	removed("admissionregistration.k8s.io", "v1beta1", "mutatingwebhookconfigurations", "1.22", "admissionregistration.k8s.io/v1"),
+	removed("admissionregistration.k8s.io", "v1beta1", "validatingwebhookconfigurations", "1.22", "admissionregistration.k8s.io/v1"),
+	removed("apiextensions.k8s.io", "v1beta1", "customresourcedefinitions", "1.22", "apiextensions.k8s.io/v1"),
+	removed("apiregistration.k8s.io", "v1beta1", "apiservices", "1.22", "apiregistration.k8s.io/v1"),
+	removed("authentication.k8s.io", "v1beta1", "tokenreviews", "1.22", "authentication.k8s.io/v1"),
+	removed("authorization.k8s.io", "v1beta1", "localsubjectaccessreviews", "1.22", "authorization.k8s.io/v1"),
+	removed("authorization.k8s.io", "v1beta1", "selfsubjectaccessreviews", "1.22", "authorization.k8s.io/v1"),
+	removed("authorization.k8s.io", "v1beta1", "subjectaccessreviews", "1.22", "authorization.k8s.io/v1"),
+	removed("certificates.k8s.io", "v1beta1", "certificatesigningrequests", "1.22", "certificates.k8s.io/v1"),
+	removed("coordination.k8s.io", "v1beta1", "leases", "1.22", "coordination.k8s.io/v1"),
+	removed("extensions", "v1beta1", "ingresses", "1.22", "networking.k8s.io/v1"),
+	removed("networking.k8s.io", "v1beta1", "ingressclasses", "1.22", "networking.k8s.io/v1"),
+	removed("networking.k8s.io", "v1beta1", "ingresses", "1.22", "networking.k8s.io/v1"),
+	removed("rbac.authorization.k8s.io", "v1beta1", "clusterrolebindings", "1.22", "rbac.authorization.k8s.io/v1"),
+	removed("rbac.authorization.k8s.io", "v1beta1", "clusterroles", "1.22", "rbac.authorization.k8s.io/v1"),
+	removed("rbac.authorization.k8s.io", "v1beta1", "rolebindings", "1.22", "rbac.authorization.k8s.io/v1"),
+	removed("rbac.authorization.k8s.io", "v1beta1", "roles", "1.22", "rbac.authorization.k8s.io/v1"),
+	removed("scheduling.k8s.io", "v1beta1", "priorityclasses", "1.22", "scheduling.k8s.io/v1"),
+	removed("storage.k8s.io", "v1beta1", "csidrivers", "1.22", "storage.k8s.io/v1"),
+	removed("storage.k8s.io", "v1beta1", "csinodes", "1.22", "storage.k8s.io/v1"),
+	removed("storage.k8s.io", "v1beta1", "storageclasses", "1.22", "storage.k8s.io/v1"),
+	removed("storage.k8s.io", "v1beta1", "volumeattachments", "1.22", "storage.k8s.io/v1"),
+
+	removed("autoscaling", "v2beta1", "horizontalpodautoscalers", "1.25", "autoscaling/v2"),
+	removed("batch", "v1beta1", "cronjobs", "1.25", "batch/v1"),
+	removed("discovery.k8s.io", "v1beta1", "endpointslices", "1.25", "discovery.k8s.io/v1"),
+	removed("events.k8s.io", "v1beta1", "events", "1.25", "events.k8s.io/v1"),
+	removed("node.k8s.io", "v1beta1", "runtimeclasses", "1.25", "node.k8s.io/v1"),
+	removed("policy", "v1beta1", "poddisruptionbudgets", "1.25", "policy/v1"),
+	removed("policy", "v1beta1", "podsecuritypolicies", "1.25", ""),
+
+	removed("autoscaling", "v2beta2", "horizontalpodautoscalers", "1.26", "autoscaling/v2"),
+	removed("flowcontrol.apiserver.k8s.io", "v1beta1", "flowschemas", "1.26", "flowcontrol.apiserver.k8s.io/v1beta2"),
+	removed("flowcontrol.apiserver.k8s.io", "v1beta1", "prioritylevelconfigurations", "1.26", "flowcontrol.apiserver.k8s.io/v1beta2"),
+
+	removed("storage.k8s.io", "v1beta1", "csistoragecapacities", "1.27", "storage.k8s.io/v1"),
+}
+
+// isRemovedBy returns true if a release removing APIs is at or before the target version
+func isRemovedBy(removedIn string, target semver.Version) bool {
+	release, err := semver.ParseTolerant(removedIn)
+	if err != nil {
+		return false
+	}
+	if release.Major != target.Major {
+		return release.Major < target.Major
+	}
+	return release.Minor <= target.Minor
+}
+
+// RemovedBy returns the APIs that are no longer served by the target Kubernetes version
+func RemovedBy(target semver.Version) []RemovedAPI {
+	var apis []RemovedAPI
+	for _, api := range RemovedAPIs {
+		if isRemovedBy(api.RemovedIn, target) {
+			apis = append(apis, api)
+		}
+	}
+	return apis
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// requestedDeprecatedAPIsMetric is set by the API server for each deprecated API it has served since it started
+const requestedDeprecatedAPIsMetric = "apiserver_requested_deprecated_apis"
+
+// lastAppliedAnnotation holds the manifest an object was last applied from by kubectl
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// listPageSize is the number of objects fetched per list request
+const listPageSize = 500
+
+// Finding is a removed API that is still in use in a cluster
+type Finding struct {
+	RemovedAPI
+
+	// Requested is true if the API server has served requests for the API since it started
+	Requested bool
+	// Objects are the objects last applied using the API, as namespace/name or name
+	Objects []string
+}
+
+// Scanner looks for usage of removed APIs in a live cluster
+type Scanner struct {
+	Discovery discovery.DiscoveryInterface
+	Dynamic   dynamic.Interface
+
+	// Metrics is used to fetch the metrics of the API server; if nil, API requests are not checked
+	Metrics rest.Interface
+}
+
+// Scan returns the APIs removed in the target Kubernetes version that are still in use.
+// An API is in use if the API server reports requests for it, or if objects were last applied with it.
+func (s *Scanner) Scan(ctx context.Context, target semver.Version) ([]*Finding, error) {
+	findings := make(map[schema.GroupVersionResource]*Finding)
+	for _, api := range RemovedBy(target) {
+		findings[api.GroupVersionResource] = &Finding{RemovedAPI: api}
+	}
+
+	if s.Metrics != nil {
+		metrics, err := s.Metrics.Get().AbsPath("/metrics").DoRaw(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error reading API server metrics: %v", err)
+		}
+		for _, requested := range ParseRequestedDeprecatedAPIs(metrics) {
+			if !isRemovedBy(requested.RemovedIn, target) {
+				continue
+			}
+			finding := findings[requested.GroupVersionResource]
+			if finding == nil {
+				finding = &Finding{RemovedAPI: requested}
+				findings[requested.GroupVersionResource] = finding
+			}
+			finding.Requested = true
+		}
+	}
+
+	byGroupVersion := make(map[schema.GroupVersion][]*Finding)
+	for _, finding := range findings {
+		gv := finding.GroupVersion()
+		byGroupVersion[gv] = append(byGroupVersion[gv], finding)
+	}
+	for gv, gvFindings := range byGroupVersion {
+		resources, err := s.Discovery.ServerResourcesForGroupVersion(gv.String())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// Not served, so nothing can be using it
+				continue
+			}
+			return nil, fmt.Errorf("error discovering resources of %s: %v", gv, err)
+		}
+
+		for _, finding := range gvFindings {
+			if !isListable(resources, finding.Resource) {
+				continue
+			}
+			objects, err := s.lastAppliedWith(ctx, finding.GroupVersionResource)
+			if err != nil {
+				return nil, err
+			}
+			finding.Objects = objects
+		}
+	}
+
+	var inUse []*Finding
+	for _, finding := range findings {
+		if finding.Requested || len(finding.Objects) != 0 {
+			inUse = append(inUse, finding)
+		}
+	}
+	sort.Slice(inUse, func(i, j int) bool {
+		if inUse[i].RemovedIn != inUse[j].RemovedIn {
+			return inUse[i].RemovedIn < inUse[j].RemovedIn
+		}
+		return inUse[i].String() < inUse[j].String()
+	})
+	return inUse, nil
+}
+
+// lastAppliedWith lists the objects of a resource whose last applied manifest uses the API version of the resource
+func (s *Scanner) lastAppliedWith(ctx context.Context, gvr schema.GroupVersionResource) ([]string, error) {
+	apiVersion := gvr.GroupVersion().String()
+
+	var objects []string
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		list, err := s.Dynamic.Resource(gvr).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s %s: %v", apiVersion, gvr.Resource, err)
+		}
+		for _, item := range list.Items {
+			if lastAppliedAPIVersion(item.GetAnnotations()[lastAppliedAnnotation]) != apiVersion {
+				continue
+			}
+			name := item.GetName()
+			if item.GetNamespace() != "" {
+				name = item.GetNamespace() + "/" + name
+			}
+			objects = append(objects, name)
+		}
+		if list.GetContinue() == "" {
+			break
+		}
+		opts.Continue = list.GetContinue()
+	}
+	sort.Strings(objects)
+	return objects, nil
+}
+
+// lastAppliedAPIVersion returns the apiVersion of a last-applied-configuration manifest
+func lastAppliedAPIVersion(manifest string) string {
+	if manifest == "" {
+		return ""
+	}
+	var header struct {
+		APIVersion string `json:"apiVersion"`
+	}
+	if err := json.Unmarshal([]byte(manifest), &header); err != nil {
+		return ""
+	}
+	return header.APIVersion
+}
+
+func isListable(resources *metav1.APIResourceList, resource string) bool {
+	for _, r := range resources.APIResources {
+		if r.Name != resource {
+			continue
+		}
+		for _, verb := range r.Verbs {
+			if verb == "list" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var metricLabel = regexp.MustCompile(`(\w+)="((?:[^"\\]|\\.)*)"`)
+
+// ParseRequestedDeprecatedAPIs returns the deprecated APIs the API server reports having served,
+// from its metrics in the Prometheus text format
+func ParseRequestedDeprecatedAPIs(metrics []byte) []RemovedAPI {
+	seen := make(map[schema.GroupVersionResource]bool)
+	var apis []RemovedAPI
+
+	scanner := bufio.NewScanner(bytes.NewReader(metrics))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, requestedDeprecatedAPIsMetric+"{") {
+			continue
+		}
+		end := strings.LastIndex(line, "}")
+		if end == -1 || strings.TrimSpace(line[end+1:]) == "0" {
+			continue
+		}
+
+		labels := make(map[string]string)
+		for _, match := range metricLabel.FindAllStringSubmatch(line[:end], -1) {
+			labels[match[1]] = match[2]
+		}
+		// APIs deprecated without a planned removal are not reported
+		if labels["removed_release"] == "" || labels["resource"] == "" {
+			continue
+		}
+
+		api := RemovedAPI{
+			GroupVersionResource: schema.GroupVersionResource{
+				Group:    labels["group"],
+				Version:  labels["version"],
+				Resource: labels["resource"],
+			},
+			RemovedIn: labels["removed_release"],
+		}
+		if seen[api.GroupVersionResource] {
+			continue
+		}
+		seen[api.GroupVersionResource] = true
+		for _, known := range RemovedAPIs {
+			if known.GroupVersionResource == api.GroupVersionResource {
+				api.Replacement = known.Replacement
+			}
+		}
+		apis = append(apis, api)
+	}
+	return apis
+}
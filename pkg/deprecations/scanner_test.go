@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+func TestRemovedBy(t *testing.T) {
+	grid := []struct {
+		Target   string
+		Included string
+		Excluded string
+	}{
+		{Target: "1.21.5", Included: "extensions/v1beta1 deployments", Excluded: "extensions/v1beta1 ingresses"},
+		{Target: "1.22.0", Included: "extensions/v1beta1 ingresses", Excluded: "batch/v1beta1 cronjobs"},
+		{Target: "1.25.3", Included: "batch/v1beta1 cronjobs", Excluded: "autoscaling/v2beta2 horizontalpodautoscalers"},
+		{Target: "2.0.0", Included: "storage.k8s.io/v1beta1 csistoragecapacities"},
+	}
+	for _, g := range grid {
+		found := make(map[string]bool)
+		for _, api := range RemovedBy(semver.MustParse(g.Target)) {
+			found[api.String()] = true
+		}
+		if !found[g.Included] {
+			t.Errorf("expected %s to be removed by %s", g.Included, g.Target)
+		}
+		if g.Excluded != "" && found[g.Excluded] {
+			t.Errorf("expected %s not to be removed by %s", g.Excluded, g.Target)
+		}
+	}
+}
+
+const testMetrics = `# HELP apiserver_requested_deprecated_apis [STABLE] Gauge of deprecated APIs that have been requested, broken out by API group, version, resource, subresource, and removed_release.
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="extensions",removed_release="1.22",resource="ingresses",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="extensions",removed_release="1.22",resource="ingresses",subresource="status",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="batch",removed_release="1.25",resource="cronjobs",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="example.com",removed_release="1.22",resource="widgets",subresource="",version="v1alpha1"} 1
+apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="poddisruptionbudgets",subresource="",version="v1beta1"} 0
+apiserver_requested_deprecated_apis{group="flowcontrol.apiserver.k8s.io",removed_release="",resource="flowschemas",subresource="",version="v1alpha1"} 1
+apiserver_request_total{code="200",resource="ingresses",version="v1beta1"} 12
+`
+
+func TestParseRequestedDeprecatedAPIs(t *testing.T) {
+	var actual []string
+	for _, api := range ParseRequestedDeprecatedAPIs([]byte(testMetrics)) {
+		actual = append(actual, api.String()+" "+api.RemovedIn+" "+api.Replacement)
+	}
+	expected := []string{
+		"extensions/v1beta1 ingresses 1.22 networking.k8s.io/v1",
+		"batch/v1beta1 cronjobs 1.25 batch/v1",
+		"example.com/v1alpha1 widgets 1.22 ",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestScan(t *testing.T) {
+	resourceList := func(groupVersion string) *metav1.APIResourceList {
+		return &metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: groupVersion,
+			APIResources: []metav1.APIResource{
+				{Name: "ingresses", Namespaced: true, Kind: "Ingress", Verbs: []string{"get", "list"}},
+				{Name: "ingresses/status", Namespaced: true, Kind: "Ingress", Verbs: []string{"get"}},
+			},
+		}
+	}
+	ingress := func(apiVersion, namespace, name, lastAppliedAPIVersion string) map[string]interface{} {
+		metadata := map[string]interface{}{"name": name, "namespace": namespace}
+		if lastAppliedAPIVersion != "" {
+			metadata["annotations"] = map[string]interface{}{
+				lastAppliedAnnotation: `{"apiVersion":"` + lastAppliedAPIVersion + `","kind":"Ingress"}`,
+			}
+		}
+		return map[string]interface{}{"apiVersion": apiVersion, "kind": "Ingress", "metadata": metadata}
+	}
+	ingresses := func(apiVersion string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       "IngressList",
+			"metadata":   map[string]interface{}{},
+			"items": []interface{}{
+				ingress(apiVersion, "default", "old", "networking.k8s.io/v1beta1"),
+				ingress(apiVersion, "default", "new", "networking.k8s.io/v1"),
+				ingress(apiVersion, "kube-system", "unmanaged", ""),
+			},
+		}
+	}
+
+	responses := map[string]interface{}{
+		"/apis/extensions/v1beta1":                  resourceList("extensions/v1beta1"),
+		"/apis/extensions/v1beta1/ingresses":        ingresses("extensions/v1beta1"),
+		"/apis/networking.k8s.io/v1beta1":           resourceList("networking.k8s.io/v1beta1"),
+		"/apis/networking.k8s.io/v1beta1/ingresses": ingresses("networking.k8s.io/v1beta1"),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(testMetrics))
+			return
+		}
+		response, found := responses[r.URL.Path]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		t.Fatalf("error building discovery client: %v", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("error building dynamic client: %v", err)
+	}
+	scanner := &Scanner{
+		Discovery: discoveryClient,
+		Dynamic:   dynamicClient,
+		Metrics:   discoveryClient.RESTClient(),
+	}
+
+	findings, err := scanner.Scan(context.Background(), semver.MustParse("1.22.1"))
+	if err != nil {
+		t.Fatalf("unexpected error scanning: %v", err)
+	}
+
+	type result struct {
+		API       string
+		Requested bool
+		Objects   []string
+	}
+	var actual []result
+	for _, finding := range findings {
+		actual = append(actual, result{API: finding.String(), Requested: finding.Requested, Objects: finding.Objects})
+	}
+	expected := []result{
+		{API: "example.com/v1alpha1 widgets", Requested: true},
+		{API: "extensions/v1beta1 ingresses", Requested: true},
+		{API: "networking.k8s.io/v1beta1 ingresses", Objects: []string{"default/old"}},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
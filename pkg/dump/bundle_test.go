@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestWriteBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := writeFile(filepath.Join(dir, "cluster.yaml"), []byte("cluster")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(filepath.Join(dir, "logs", "node1", "kubelet.log"), []byte("kubelet")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, dir, "kops-dump"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("error reading gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	actual := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("error reading tar: %v", err)
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("error reading %s: %v", header.Name, err)
+		}
+		actual[header.Name] = string(b)
+	}
+
+	expected := map[string]string{
+		"kops-dump/cluster.yaml":           "cluster",
+		"kops-dump/logs/":                  "",
+		"kops-dump/logs/node1/":            "",
+		"kops-dump/logs/node1/kubelet.log": "kubelet",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected bundle contents: %v", actual)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	in := `
+spec:
+  kubernetesVersion: 1.21.0
+  fileAssets:
+  - name: creds
+    content: secret-content
+  hooks:
+  - manifest: |
+      [Unit]
+  authentication:
+    oidc:
+      clientSecret: abc
+  additionalUserData:
+  - name: empty
+    content: ""
+  kubeAPIServer:
+    tokenAuthFile: /etc/tokens
+  containerd:
+    configOverride: |
+      version = 2
+    registries:
+      registry.example.com:
+        auth: dXNlcjpwYXNz
+        username: user
+  cloudConfig:
+    accessKeyID: AKIAEXAMPLE
+`
+	expected := `
+spec:
+  kubernetesVersion: 1.21.0
+  fileAssets:
+  - name: creds
+    content: REDACTED
+  hooks:
+  - manifest: REDACTED
+  authentication:
+    oidc:
+      clientSecret: REDACTED
+  additionalUserData:
+  - name: empty
+    content: ""
+  kubeAPIServer:
+    tokenAuthFile: REDACTED
+  containerd:
+    configOverride: REDACTED
+    registries:
+      registry.example.com:
+        auth: REDACTED
+        username: user
+  cloudConfig:
+    accessKeyID: REDACTED
+`
+
+	var actual, want map[string]interface{}
+	if err := yaml.Unmarshal([]byte(in), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatal(err)
+	}
+	Sanitize(actual)
+	if !reflect.DeepEqual(actual, want) {
+		t.Errorf("unexpected sanitized object: %v", actual)
+	}
+}
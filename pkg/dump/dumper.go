@@ -32,24 +32,35 @@ import (
 	"k8s.io/klog/v2"
 )
 
-// logDumper gets all the nodes from a kubernetes cluster and dumps a well-known set of logs
-type logDumper struct {
+// LogDumper gets all the nodes from a kubernetes cluster and dumps a well-known set of logs
+type LogDumper struct {
 	sshClientFactory sshClientFactory
 
+	// nodeAddress returns the address used to connect to a node
+	nodeAddress func(node *corev1.Node) string
+
 	artifactsDir string
 
 	services []string
 	files    []string
+
+	// MaxLines limits the logs collected from the journal and from files to their last lines, if greater than zero
+	MaxLines int
 }
 
-// NewLogDumper is the constructor for a logDumper
-func NewLogDumper(sshConfig *ssh.ClientConfig, artifactsDir string) *logDumper {
+// NewLogDumper is the constructor for a LogDumper
+func NewLogDumper(sshConfig *ssh.ClientConfig, artifactsDir string) *LogDumper {
 	sshClientFactory := &sshClientFactoryImplementation{
 		sshConfig: sshConfig,
 	}
 
-	d := &logDumper{
+	return newLogDumper(sshClientFactory, externalIP, artifactsDir)
+}
+
+func newLogDumper(sshClientFactory sshClientFactory, nodeAddress func(node *corev1.Node) string, artifactsDir string) *LogDumper {
+	d := &LogDumper{
 		sshClientFactory: sshClientFactory,
+		nodeAddress:      nodeAddress,
 		artifactsDir:     artifactsDir,
 	}
 
@@ -85,11 +96,11 @@ func NewLogDumper(sshConfig *ssh.ClientConfig, artifactsDir string) *logDumper {
 }
 
 // DumpAllNodes connects to every node from kubectl get nodes and dumps the logs.
-// additionalIPs holds IP addresses of instances found by the deployment tool;
+// additionalIPs holds the addresses of instances found by the deployment tool (instance IDs for NewSSMLogDumper);
 // if the IPs are not found from kubectl get nodes, then these will be dumped also.
 // This allows for dumping log on nodes even if they don't register as a kubernetes
 // node, or if a node fails to register, or if the whole cluster fails to start.
-func (d *logDumper) DumpAllNodes(ctx context.Context, nodes corev1.NodeList, additionalIPs []string) error {
+func (d *LogDumper) DumpAllNodes(ctx context.Context, nodes corev1.NodeList, additionalIPs []string) error {
 	var dumped []*corev1.Node
 
 	for i := range nodes.Items {
@@ -100,13 +111,7 @@ func (d *logDumper) DumpAllNodes(ctx context.Context, nodes corev1.NodeList, add
 
 		node := &nodes.Items[i]
 
-		ip := ""
-		for _, address := range node.Status.Addresses {
-			if address.Type == "ExternalIP" {
-				ip = address.Address
-				break
-			}
-		}
+		ip := d.nodeAddress(node)
 
 		err := d.dumpNode(ctx, node.Name, ip)
 		if err != nil {
@@ -116,7 +121,7 @@ func (d *logDumper) DumpAllNodes(ctx context.Context, nodes corev1.NodeList, add
 		}
 	}
 
-	notDumped := findInstancesNotDumped(additionalIPs, dumped)
+	notDumped := d.findInstancesNotDumped(additionalIPs, dumped)
 	for _, ip := range notDumped {
 		if ctx.Err() != nil {
 			log.Printf("stopping dumping nodes: %v", ctx.Err())
@@ -133,14 +138,25 @@ func (d *logDumper) DumpAllNodes(ctx context.Context, nodes corev1.NodeList, add
 	return nil
 }
 
+// externalIP returns the external IP of the node, used to connect to it over SSH
+func externalIP(node *corev1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == "ExternalIP" {
+			return address.Address
+		}
+	}
+	return ""
+}
+
 // findInstancesNotDumped returns ips from the slice that do not appear as any address of the nodes
-func findInstancesNotDumped(ips []string, dumped []*corev1.Node) []string {
+func (d *LogDumper) findInstancesNotDumped(ips []string, dumped []*corev1.Node) []string {
 	var notDumped []string
 	dumpedAddresses := make(map[string]bool)
 	for _, node := range dumped {
 		for _, address := range node.Status.Addresses {
 			dumpedAddresses[address.Address] = true
 		}
+		dumpedAddresses[d.nodeAddress(node)] = true
 	}
 
 	for _, ip := range ips {
@@ -152,7 +168,7 @@ func findInstancesNotDumped(ips []string, dumped []*corev1.Node) []string {
 }
 
 // DumpNode connects to a node and dumps the logs.
-func (d *logDumper) dumpNode(ctx context.Context, name string, ip string) error {
+func (d *LogDumper) dumpNode(ctx context.Context, name string, ip string) error {
 	if ip == "" {
 		return fmt.Errorf("could not find address for %v, ", name)
 	}
@@ -196,13 +212,13 @@ type sshClientFactory interface {
 // logDumperNode holds state for a particular node we are dumping
 type logDumperNode struct {
 	client sshClient
-	dumper *logDumper
+	dumper *LogDumper
 
 	dir string
 }
 
 // connectToNode makes an SSH connection to the node and returns a logDumperNode
-func (d *logDumper) connectToNode(ctx context.Context, nodeName string, host string) (*logDumperNode, error) {
+func (d *LogDumper) connectToNode(ctx context.Context, nodeName string, host string) (*logDumperNode, error) {
 	client, err := d.sshClientFactory.Dial(ctx, host)
 	if err != nil {
 		return nil, fmt.Errorf("unable to SSH to %q: %v", host, err)
@@ -227,14 +243,21 @@ func (n *logDumperNode) dump(ctx context.Context) []error {
 
 	var errors []error
 
+	journalctl := "sudo journalctl"
+	cat := "sudo cat"
+	if n.dumper.MaxLines > 0 {
+		journalctl = fmt.Sprintf("sudo journalctl --lines=%d", n.dumper.MaxLines)
+		cat = fmt.Sprintf("sudo tail -n %d", n.dumper.MaxLines)
+	}
+
 	// Capture kernel log
-	if err := n.shellToFile(ctx, "sudo journalctl --output=short-precise -k", filepath.Join(n.dir, "kern.log")); err != nil {
+	if err := n.shellToFile(ctx, journalctl+" --output=short-precise -k", filepath.Join(n.dir, "kern.log")); err != nil {
 		errors = append(errors, err)
 	}
 
 	// Capture full journal - needed so we can see e.g. disk mounts
 	// This does duplicate the other files, but ensures we have all output
-	if err := n.shellToFile(ctx, "sudo journalctl --output=short-precise", filepath.Join(n.dir, "journal.log")); err != nil {
+	if err := n.shellToFile(ctx, journalctl+" --output=short-precise", filepath.Join(n.dir, "journal.log")); err != nil {
 		errors = append(errors, err)
 	}
 
@@ -247,7 +270,7 @@ func (n *logDumperNode) dump(ctx context.Context) []error {
 		name := s + ".service"
 		for _, service := range services {
 			if service == name {
-				if err := n.shellToFile(ctx, "sudo journalctl --output=cat -u "+name, filepath.Join(n.dir, s+".log")); err != nil {
+				if err := n.shellToFile(ctx, journalctl+" --output=cat -u "+name, filepath.Join(n.dir, s+".log")); err != nil {
 					errors = append(errors, err)
 				}
 			}
@@ -265,7 +288,7 @@ func (n *logDumperNode) dump(ctx context.Context) []error {
 			if !strings.HasPrefix(f, prefix) {
 				continue
 			}
-			if err := n.shellToFile(ctx, "sudo cat '"+strings.ReplaceAll(f, "'", "'\\''")+"'", filepath.Join(n.dir, filepath.Base(f))); err != nil {
+			if err := n.shellToFile(ctx, cat+" '"+strings.ReplaceAll(f, "'", "'\\''")+"'", filepath.Join(n.dir, filepath.Base(f))); err != nil {
 				errors = append(errors, err)
 			}
 		}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// controlPlaneLabels are the labels of control plane nodes
+var controlPlaneLabels = []string{
+	"node-role.kubernetes.io/master",
+	"node-role.kubernetes.io/control-plane",
+}
+
+// KubernetesDumper collects Kubernetes objects and the logs of the control plane pods
+type KubernetesDumper struct {
+	Client kubernetes.Interface
+
+	// MaxLines limits the pod logs to their last lines, if greater than zero
+	MaxLines int64
+}
+
+// Dump writes the nodes, the pods and events of kube-system, and the logs of the kube-system pods
+// running on control plane nodes to dir. Errors are logged, so that as much as possible is collected.
+func (d *KubernetesDumper) Dump(ctx context.Context, dir string) []error {
+	var errors []error
+
+	nodes, err := d.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errors = append(errors, fmt.Errorf("error listing nodes: %v", err))
+		nodes = &corev1.NodeList{}
+	} else if err := writeYAML(filepath.Join(dir, "nodes.yaml"), nodes); err != nil {
+		errors = append(errors, err)
+	}
+
+	controlPlaneNodes := make(map[string]bool)
+	for _, node := range nodes.Items {
+		for _, label := range controlPlaneLabels {
+			if _, found := node.Labels[label]; found {
+				controlPlaneNodes[node.Name] = true
+			}
+		}
+	}
+
+	events, err := d.Client.CoreV1().Events(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errors = append(errors, fmt.Errorf("error listing events: %v", err))
+	} else if err := writeYAML(filepath.Join(dir, metav1.NamespaceSystem, "events.yaml"), events); err != nil {
+		errors = append(errors, err)
+	}
+
+	pods, err := d.Client.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errors = append(errors, fmt.Errorf("error listing pods: %v", err))
+		return errors
+	}
+	if err := writeYAML(filepath.Join(dir, metav1.NamespaceSystem, "pods.yaml"), pods); err != nil {
+		errors = append(errors, err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !controlPlaneNodes[pod.Spec.NodeName] {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if err := d.dumpLogs(ctx, pod, container.Name, filepath.Join(dir, metav1.NamespaceSystem, "logs", pod.Name, container.Name+".log")); err != nil {
+				errors = append(errors, err)
+			}
+		}
+	}
+
+	return errors
+}
+
+func (d *KubernetesDumper) dumpLogs(ctx context.Context, pod *corev1.Pod, container string, destPath string) error {
+	options := &corev1.PodLogOptions{
+		Container: container,
+	}
+	if d.MaxLines > 0 {
+		options.TailLines = &d.MaxLines
+	}
+	klog.V(2).Infof("collecting logs of %s/%s", pod.Name, container)
+	b, err := d.Client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).DoRaw(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting logs of container %s of pod %s: %v", container, pod.Name, err)
+	}
+	return writeFile(destPath, b)
+}
+
+func writeYAML(destPath string, obj interface{}) error {
+	b, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("error marshaling %s: %v", destPath, err)
+	}
+	return writeFile(destPath, b)
+}
+
+func writeFile(destPath string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("error creating directory %q: %v", filepath.Dir(destPath), err)
+	}
+	if err := ioutil.WriteFile(destPath, b, 0644); err != nil {
+		return fmt.Errorf("error writing %q: %v", destPath, err)
+	}
+	return nil
+}
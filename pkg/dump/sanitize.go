@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"strings"
+)
+
+// Redacted replaces the sensitive values of sanitized objects
+const Redacted = "REDACTED"
+
+// inlineContentKeys are keys holding inline files or manifests, such as file assets, hooks, additional user data
+// and the containerd config override, which commonly embed credentials
+var inlineContentKeys = map[string]bool{
+	"content":        true,
+	"manifest":       true,
+	"configOverride": true,
+}
+
+// sensitiveKeys are keys (compared case-insensitively) holding credentials, such as the auth of
+// the registries of containerd and docker config files
+var sensitiveKeys = map[string]bool{
+	"auth":          true,
+	"authorization": true,
+}
+
+// sensitiveKeySubstrings match (case-insensitively) the keys holding credentials
+var sensitiveKeySubstrings = []string{
+	"password",
+	"passphrase",
+	"secret",
+	"token",
+	"credential",
+	"privatekey",
+	"privatematerial",
+	"accesskey",
+	"apikey",
+}
+
+// Sanitize replaces the sensitive values of an object decoded from JSON or YAML, in place,
+// so that it can be shared in a support bundle
+func Sanitize(obj interface{}) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSensitiveKey(key) {
+				if value != nil && value != "" {
+					v[key] = Redacted
+				}
+				continue
+			}
+			Sanitize(value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			Sanitize(value)
+		}
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	if inlineContentKeys[key] {
+		return true
+	}
+	lower := strings.ToLower(key)
+	if sensitiveKeys[lower] {
+		return true
+	}
+	for _, s := range sensitiveKeySubstrings {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
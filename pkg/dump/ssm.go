@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// ssmMaxOutput is the number of characters of output returned by SSM Run Command
+const ssmMaxOutput = 24000
+
+// ssmPollInterval is the interval between checks of the status of a command
+var ssmPollInterval = 2 * time.Second
+
+// NewSSMLogDumper builds a log dumper that runs commands on the nodes with AWS Systems Manager Run Command,
+// instead of SSH. Nodes are addressed by instance ID.
+// Run Command only returns the last 24000 characters of the output of each command, so MaxLines should be set.
+func NewSSMLogDumper(ssmClient ssmiface.SSMAPI, artifactsDir string) *LogDumper {
+	return newLogDumper(&ssmClientFactory{ssm: ssmClient}, instanceID, artifactsDir)
+}
+
+// instanceID returns the EC2 instance ID of the node, from its provider ID
+func instanceID(node *corev1.Node) string {
+	providerID := node.Spec.ProviderID
+	if !strings.HasPrefix(providerID, "aws://") {
+		return ""
+	}
+	tokens := strings.Split(strings.TrimPrefix(providerID, "aws://"), "/")
+	if len(tokens) != 3 || !strings.HasPrefix(tokens[2], "i-") {
+		return ""
+	}
+	return tokens[2]
+}
+
+// ssmClientFactory is an sshClientFactory for SSM Run Command
+type ssmClientFactory struct {
+	ssm ssmiface.SSMAPI
+}
+
+var _ sshClientFactory = &ssmClientFactory{}
+
+// Dial implements sshClientFactory::Dial; host is the instance ID
+func (f *ssmClientFactory) Dial(ctx context.Context, host string) (sshClient, error) {
+	return &ssmClient{ssm: f.ssm, instanceID: host}, nil
+}
+
+// ssmClient runs commands on an instance with SSM Run Command
+type ssmClient struct {
+	ssm        ssmiface.SSMAPI
+	instanceID string
+}
+
+var _ sshClient = &ssmClient{}
+
+// ExecPiped implements sshClient::ExecPiped
+func (c *ssmClient) ExecPiped(ctx context.Context, command string, stdout io.Writer, stderr io.Writer) error {
+	klog.V(2).Infof("running SSM command on %s: %v", c.instanceID, command)
+
+	// Run Command truncates the output, so we keep its end, which holds the latest log lines
+	script := fmt.Sprintf("%s | tail -c %d", command, ssmMaxOutput)
+
+	response, err := c.ssm.SendCommandWithContext(ctx, &ssm.SendCommandInput{
+		DocumentName: aws.String("AWS-RunShellScript"),
+		InstanceIds:  []*string{aws.String(c.instanceID)},
+		Comment:      aws.String("kops: collect logs"),
+		Parameters: map[string][]*string{
+			"commands": {aws.String(script)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error sending command to instance %q: %v", c.instanceID, err)
+	}
+	commandID := aws.StringValue(response.Command.CommandId)
+
+	var invocation *ssm.GetCommandInvocationOutput
+	err = wait.PollImmediateUntil(ssmPollInterval, func() (bool, error) {
+		invocation, err = c.ssm.GetCommandInvocationWithContext(ctx, &ssm.GetCommandInvocationInput{
+			CommandId:  aws.String(commandID),
+			InstanceId: aws.String(c.instanceID),
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == ssm.ErrCodeInvocationDoesNotExist {
+				// The invocation is not visible immediately after the command is sent
+				return false, nil
+			}
+			return false, err
+		}
+		switch aws.StringValue(invocation.Status) {
+		case ssm.CommandInvocationStatusPending, ssm.CommandInvocationStatusInProgress, ssm.CommandInvocationStatusDelayed:
+			return false, nil
+		}
+		return true, nil
+	}, ctx.Done())
+	if err != nil {
+		return fmt.Errorf("error waiting for command %q on instance %q: %v", commandID, c.instanceID, err)
+	}
+
+	if _, err := io.WriteString(stdout, aws.StringValue(invocation.StandardOutputContent)); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(stderr, aws.StringValue(invocation.StandardErrorContent)); err != nil {
+		return err
+	}
+	if aws.StringValue(invocation.Status) != ssm.CommandInvocationStatusSuccess {
+		return fmt.Errorf("command %q on instance %q finished with status %s", commandID, c.instanceID, aws.StringValue(invocation.Status))
+	}
+	return nil
+}
+
+// Close implements sshClient::Close
+func (c *ssmClient) Close() error {
+	return nil
+}
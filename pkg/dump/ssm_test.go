@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type fakeSSM struct {
+	ssmiface.SSMAPI
+
+	commands    []string
+	invocations int
+}
+
+func (f *fakeSSM) SendCommandWithContext(ctx aws.Context, input *ssm.SendCommandInput, opts ...request.Option) (*ssm.SendCommandOutput, error) {
+	f.commands = append(f.commands, aws.StringValue(input.Parameters["commands"][0]))
+	return &ssm.SendCommandOutput{Command: &ssm.Command{CommandId: aws.String("command-1")}}, nil
+}
+
+func (f *fakeSSM) GetCommandInvocationWithContext(ctx aws.Context, input *ssm.GetCommandInvocationInput, opts ...request.Option) (*ssm.GetCommandInvocationOutput, error) {
+	f.invocations++
+	switch f.invocations {
+	case 1:
+		return nil, awserr.New(ssm.ErrCodeInvocationDoesNotExist, "not found", nil)
+	case 2:
+		return &ssm.GetCommandInvocationOutput{Status: aws.String(ssm.CommandInvocationStatusInProgress)}, nil
+	}
+	return &ssm.GetCommandInvocationOutput{
+		Status:                aws.String(ssm.CommandInvocationStatusSuccess),
+		StandardOutputContent: aws.String("log line"),
+	}, nil
+}
+
+func TestSSMClientExecPiped(t *testing.T) {
+	ssmPollInterval = time.Millisecond
+
+	fake := &fakeSSM{}
+	client, err := (&ssmClientFactory{ssm: fake}).Dial(context.Background(), "i-1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := client.ExecPiped(context.Background(), "sudo journalctl --no-pager --lines=10", &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "log line" {
+		t.Errorf("unexpected stdout %q", stdout.String())
+	}
+	if len(fake.commands) != 1 || !strings.HasSuffix(fake.commands[0], "| tail -c 24000") {
+		t.Errorf("unexpected commands %v", fake.commands)
+	}
+	if fake.invocations != 3 {
+		t.Errorf("expected 3 invocation checks, got %d", fake.invocations)
+	}
+}
+
+func TestInstanceID(t *testing.T) {
+	grid := map[string]string{
+		"aws:///us-east-1a/i-0123456789abcdef0": "i-0123456789abcdef0",
+		"aws:///us-east-1a/not-an-instance":     "",
+		"gce://project/zone/instance":           "",
+		"":                                      "",
+	}
+	for providerID, expected := range grid {
+		node := &corev1.Node{Spec: corev1.NodeSpec{ProviderID: providerID}}
+		if actual := instanceID(node); actual != expected {
+			t.Errorf("instanceID(%q) = %q, expected %q", providerID, actual, expected)
+		}
+	}
+}
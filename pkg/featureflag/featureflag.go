@@ -99,6 +99,12 @@ var (
 	UseAddonOperators = New("UseAddonOperators", Bool(false))
 	// AWSIPv6 activates experimental AWS IPv6 support.
 	AWSIPv6 = New("AWSIPv6", Bool(false))
+	// OCI toggles the Oracle Cloud Infrastructure support.
+	OCI = New("OCI", Bool(false))
+	// Metal toggles the support for machines that kOps does not provision, which it configures over SSH.
+	Metal = New("Metal", Bool(false))
+	// HybridNodes enables instance groups of machines outside the cloud provider, which join an AWS cluster.
+	HybridNodes = New("HybridNodes", Bool(false))
 	// TerraformManagedFiles enables rendering managed files into the Terraform configuration.
 	TerraformManagedFiles = New("TerraformManagedFiles", Bool(true))
 )
@@ -0,0 +1,372 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imagebake builds the templates that bake the software and the settings of an instance group
+// into a machine image, so that nodeup only has to configure the instances minimally at boot.
+package imagebake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+const (
+	// ProvisionScriptFile is the name of the script that provisions the image
+	ProvisionScriptFile = "provision.sh"
+	// PackerTemplateFile is the name of the Packer template
+	PackerTemplateFile = "packer.json"
+	// PackerManifestFile is the name of the manifest in which Packer records the images it built
+	PackerManifestFile = "manifest.json"
+	// ImageBuilderComponentFile is the name of the EC2 Image Builder component document
+	ImageBuilderComponentFile = "component.yaml"
+
+	// kernelModulesFilePath is where nodeup lists the kernel modules of the instance group
+	kernelModulesFilePath = "/etc/modules-load.d/99-k8s-instancegroup.conf"
+	// sysctlsFilePath is where the baked kernel parameters are written; nodeup applies its own file after it
+	sysctlsFilePath = "/etc/sysctl.d/98-kops-image.conf"
+)
+
+// Recipe is what is baked into the image of an instance group
+type Recipe struct {
+	// ClusterName is the name of the cluster the image is built for
+	ClusterName string
+	// InstanceGroupName is the name of the instance group the image is built for
+	InstanceGroupName string
+
+	// ContainerdURL is the location of the containerd package, or nil if containerd is not baked
+	ContainerdURL *url.URL
+	// ContainerdHash is the hash of the containerd package
+	ContainerdHash *hashing.Hash
+
+	// KernelModules are the kernel modules to load at boot
+	KernelModules []string
+	// Sysctls are the kernel parameters, as lines of a sysctl.d file
+	Sysctls []string
+
+	// Images are the container images pre-pulled into the image store of containerd
+	Images []string
+}
+
+// NewRecipe builds the recipe for an instance group of a cluster, from their full specs.
+// containerdURL and containerdHash locate the package of the container runtime, if it is containerd.
+func NewRecipe(cluster *kops.Cluster, ig *kops.InstanceGroup, containerdURL *url.URL, containerdHash *hashing.Hash) (*Recipe, error) {
+	if containerdURL != nil && (containerdHash == nil || containerdHash.Algorithm != hashing.HashAlgorithmSHA256) {
+		return nil, fmt.Errorf("a sha256 hash of the containerd package is required")
+	}
+
+	r := &Recipe{
+		ClusterName:       cluster.ObjectMeta.Name,
+		InstanceGroupName: ig.ObjectMeta.Name,
+		ContainerdURL:     containerdURL,
+		ContainerdHash:    containerdHash,
+		KernelModules:     ig.Spec.KernelModules,
+	}
+
+	var names []string
+	for name := range ig.Spec.Sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r.Sysctls = append(r.Sysctls, name+" = "+ig.Spec.Sysctls[name])
+	}
+	for _, params := range [][]string{ig.Spec.SysctlParameters, cluster.Spec.SysctlParameters} {
+		for _, param := range params {
+			if !strings.ContainsRune(param, '=') {
+				return nil, fmt.Errorf("invalid sysctl parameter: expected %q to contain '='", param)
+			}
+			r.Sysctls = append(r.Sysctls, param)
+		}
+	}
+
+	return r, nil
+}
+
+// ImageName is the name of the images built from the recipe, without the timestamp suffix
+func (r *Recipe) ImageName() string {
+	return "kops-" + r.InstanceGroupName + "-" + r.ClusterName
+}
+
+// ProvisionScript returns the script that provisions the image.
+// Files are written where nodeup writes them, so that nodeup leaves them unchanged at boot.
+func (r *Recipe) ProvisionScript() string {
+	var b bytes.Buffer
+	b.WriteString("#!/bin/bash\n")
+	b.WriteString("# Built by kops - do not edit\n\n")
+	b.WriteString("set -o errexit\nset -o nounset\nset -o pipefail\n")
+
+	if r.ContainerdURL != nil {
+		b.WriteString("\n# Install containerd where nodeup installs it\n")
+		b.WriteString("workdir=$(mktemp -d)\n")
+		fmt.Fprintf(&b, "curl -fsSL --retry 5 -o \"${workdir}/containerd.tar.gz\" %q\n", r.ContainerdURL.String())
+		fmt.Fprintf(&b, "echo \"%s  ${workdir}/containerd.tar.gz\" | sha256sum -c -\n", r.ContainerdHash.Hex())
+		b.WriteString("tar -xzf \"${workdir}/containerd.tar.gz\" -C \"${workdir}\"\n")
+		b.WriteString("if [[ -d \"${workdir}/usr/local\" ]]; then\n")
+		b.WriteString("  install -m 0755 \"${workdir}\"/usr/local/bin/{containerd*,crictl,ctr} \"${workdir}\"/usr/local/sbin/runc /usr/bin/\n")
+		b.WriteString("else\n")
+		b.WriteString("  install -m 0755 \"${workdir}\"/docker/{containerd*,ctr,runc} /usr/bin/\n")
+		b.WriteString("fi\n")
+		b.WriteString("rm -rf \"${workdir}\"\n")
+	}
+
+	if len(r.Images) > 0 && r.ContainerdURL != nil {
+		b.WriteString("\n# Pre-pull the container images, so the kubelet finds them in the image store of containerd\n")
+		b.WriteString("containerd --log-level warn &\n")
+		b.WriteString("containerd_pid=$!\n")
+		b.WriteString("for i in $(seq 60); do ctr version >/dev/null 2>&1 && break; sleep 1; done\n")
+		for _, image := range r.Images {
+			fmt.Fprintf(&b, "ctr --namespace k8s.io images pull %q\n", qualifyImage(image))
+		}
+		b.WriteString("kill \"${containerd_pid}\"\n")
+		b.WriteString("wait \"${containerd_pid}\" || true\n")
+	}
+
+	if len(r.KernelModules) > 0 {
+		b.WriteString("\n# Load the kernel modules of the instance group at boot\n")
+		writeFile(&b, kernelModulesFilePath, r.KernelModules)
+	}
+
+	if len(r.Sysctls) > 0 {
+		b.WriteString("\n# Set the kernel parameters of the instance group and the cluster\n")
+		writeFile(&b, sysctlsFilePath, r.Sysctls)
+	}
+
+	return b.String()
+}
+
+// writeFile writes a shell command that creates the file at path with the given lines
+func writeFile(b *bytes.Buffer, path string, lines []string) {
+	fmt.Fprintf(b, "cat > %s <<'EOF'\n", path)
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("EOF\n")
+}
+
+// PackerTemplate returns a Packer template that builds an AMI with the amazon-ebs builder,
+// from sourceAMI, the ID of the image of the instance group.
+func (r *Recipe) PackerTemplate(region, sourceAMI, instanceType, sshUser string) ([]byte, error) {
+	type builder struct {
+		Type         string            `json:"type"`
+		Region       string            `json:"region"`
+		SourceAMI    string            `json:"source_ami"`
+		InstanceType string            `json:"instance_type"`
+		SSHUsername  string            `json:"ssh_username"`
+		AMIName      string            `json:"ami_name"`
+		Tags         map[string]string `json:"tags"`
+	}
+	type provisioner struct {
+		Type           string `json:"type"`
+		Script         string `json:"script"`
+		ExecuteCommand string `json:"execute_command"`
+	}
+	type postProcessor struct {
+		Type   string `json:"type"`
+		Output string `json:"output"`
+	}
+	type template struct {
+		Builders       []builder       `json:"builders"`
+		Provisioners   []provisioner   `json:"provisioners"`
+		PostProcessors []postProcessor `json:"post-processors"`
+	}
+
+	if !strings.HasPrefix(sourceAMI, "ami-") {
+		return nil, fmt.Errorf("source image %q is not an AMI ID", sourceAMI)
+	}
+
+	b := builder{
+		Type:         "amazon-ebs",
+		Region:       region,
+		SourceAMI:    sourceAMI,
+		InstanceType: instanceType,
+		SSHUsername:  sshUser,
+		AMIName:      r.ImageName() + "-{{timestamp}}",
+		Tags: map[string]string{
+			"Cluster":       r.ClusterName,
+			"InstanceGroup": r.InstanceGroupName,
+			"SourceImage":   sourceAMI,
+		},
+	}
+
+	t := template{
+		Builders: []builder{b},
+		Provisioners: []provisioner{
+			{
+				Type:           "shell",
+				Script:         ProvisionScriptFile,
+				ExecuteCommand: "chmod +x {{ .Path }}; sudo {{ .Vars }} {{ .Path }}",
+			},
+		},
+		PostProcessors: []postProcessor{
+			{
+				Type:   "manifest",
+				Output: PackerManifestFile,
+			},
+		},
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error building Packer template: %v", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ImageBuilderComponent returns an EC2 Image Builder component document that runs the provisioning script,
+// for an image recipe whose parent image is the image of the instance group.
+func (r *Recipe) ImageBuilderComponent() ([]byte, error) {
+	type inputs struct {
+		Commands []string `json:"commands"`
+	}
+	type step struct {
+		Name   string `json:"name"`
+		Action string `json:"action"`
+		Inputs inputs `json:"inputs"`
+	}
+	type phase struct {
+		Name  string `json:"name"`
+		Steps []step `json:"steps"`
+	}
+	type component struct {
+		Name          string  `json:"name"`
+		Description   string  `json:"description"`
+		SchemaVersion string  `json:"schemaVersion"`
+		Phases        []phase `json:"phases"`
+	}
+
+	c := component{
+		Name:          r.ImageName(),
+		Description:   fmt.Sprintf("Provisions the image of instance group %q of cluster %q", r.InstanceGroupName, r.ClusterName),
+		SchemaVersion: "1.0",
+		Phases: []phase{
+			{
+				Name: "build",
+				Steps: []step{
+					{
+						Name:   "Provision",
+						Action: "ExecuteBash",
+						Inputs: inputs{Commands: []string{r.ProvisionScript()}},
+					},
+				},
+			},
+		},
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("error building EC2 Image Builder component: %v", err)
+	}
+	return data, nil
+}
+
+// ParsePackerManifest returns the ID of the AMI built by the last run of Packer recorded in its manifest
+func ParsePackerManifest(data []byte) (string, error) {
+	var manifest struct {
+		Builds []struct {
+			ArtifactID  string `json:"artifact_id"`
+			PackerRunID string `json:"packer_run_uuid"`
+		} `json:"builds"`
+		LastRunID string `json:"last_run_uuid"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", fmt.Errorf("error parsing Packer manifest: %v", err)
+	}
+
+	for i := len(manifest.Builds) - 1; i >= 0; i-- {
+		build := manifest.Builds[i]
+		if build.PackerRunID != manifest.LastRunID {
+			continue
+		}
+		// The artifact of the amazon-ebs builder is region:ami-id
+		tokens := strings.SplitN(build.ArtifactID, ":", 2)
+		if len(tokens) == 2 && strings.HasPrefix(tokens[1], "ami-") {
+			return tokens[1], nil
+		}
+		return "", fmt.Errorf("unexpected artifact %q in Packer manifest", build.ArtifactID)
+	}
+	return "", fmt.Errorf("no image built by the last run of Packer found in its manifest")
+}
+
+// controlPlaneImages are the repositories of the images that only run on the control plane.
+// The API server and its health check also run on instance groups with the APIServer role.
+var controlPlaneImages = map[string]bool{
+	"dns-controller":          true,
+	"etcd-manager":            true,
+	"kops-controller":         true,
+	"kube-controller-manager": true,
+	"kube-scheduler":          true,
+}
+
+var apiserverImages = map[string]bool{
+	"kube-apiserver":             true,
+	"kube-apiserver-healthcheck": true,
+}
+
+// ImagesForRole returns the images that run on the instances of an instance group with the given role
+func ImagesForRole(images []string, role kops.InstanceGroupRole) []string {
+	var filtered []string
+	for _, image := range images {
+		repository := imageRepository(image)
+		switch role {
+		case kops.InstanceGroupRoleMaster:
+		case kops.InstanceGroupRoleAPIServer:
+			if controlPlaneImages[repository] {
+				continue
+			}
+		default:
+			if controlPlaneImages[repository] || apiserverImages[repository] {
+				continue
+			}
+		}
+		filtered = append(filtered, image)
+	}
+	return filtered
+}
+
+// imageRepository returns the last path element of the repository of an image, without its tag or digest
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, "/"); i >= 0 {
+		image = image[i+1:]
+	}
+	if i := strings.Index(image, ":"); i >= 0 {
+		image = image[:i]
+	}
+	return image
+}
+
+// qualifyImage returns the fully qualified name of an image, as ctr does not apply the defaults of Docker Hub
+func qualifyImage(image string) string {
+	tokens := strings.SplitN(image, "/", 2)
+	if len(tokens) == 1 {
+		return "docker.io/library/" + image
+	}
+	if !strings.ContainsAny(tokens[0], ".:") && tokens[0] != "localhost" {
+		return "docker.io/" + image
+	}
+	return image
+}
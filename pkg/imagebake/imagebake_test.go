@@ -0,0 +1,291 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagebake
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/util/pkg/hashing"
+	"sigs.k8s.io/yaml"
+)
+
+func buildRecipe(t *testing.T) *Recipe {
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+		Spec: kops.ClusterSpec{
+			SysctlParameters: []string{"fs.inotify.max_user_watches=524288"},
+		},
+	}
+	ig := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+		Spec: kops.InstanceGroupSpec{
+			KernelModules: []string{"br_netfilter", "overlay"},
+			Sysctls: map[string]string{
+				"net.ipv4.tcp_keepalive_time": "600",
+				"net.core.somaxconn":          "4096",
+			},
+		},
+	}
+	u, _ := url.Parse("https://github.com/containerd/containerd/releases/download/v1.4.6/cri-containerd-cni-1.4.6-linux-amd64.tar.gz")
+	h := hashing.MustFromString("6ae4763598c9583f8b50605f19d6c7e9ef93c216706465e73dfc84ee6b63a238")
+
+	r, err := NewRecipe(cluster, ig, u, h)
+	if err != nil {
+		t.Fatalf("unexpected error from NewRecipe: %v", err)
+	}
+	return r
+}
+
+func TestProvisionScript(t *testing.T) {
+	r := buildRecipe(t)
+
+	expected := `#!/bin/bash
+# Built by kops - do not edit
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# Install containerd where nodeup installs it
+workdir=$(mktemp -d)
+curl -fsSL --retry 5 -o "${workdir}/containerd.tar.gz" "https://github.com/containerd/containerd/releases/download/v1.4.6/cri-containerd-cni-1.4.6-linux-amd64.tar.gz"
+echo "6ae4763598c9583f8b50605f19d6c7e9ef93c216706465e73dfc84ee6b63a238  ${workdir}/containerd.tar.gz" | sha256sum -c -
+tar -xzf "${workdir}/containerd.tar.gz" -C "${workdir}"
+if [[ -d "${workdir}/usr/local" ]]; then
+  install -m 0755 "${workdir}"/usr/local/bin/{containerd*,crictl,ctr} "${workdir}"/usr/local/sbin/runc /usr/bin/
+else
+  install -m 0755 "${workdir}"/docker/{containerd*,ctr,runc} /usr/bin/
+fi
+rm -rf "${workdir}"
+
+# Load the kernel modules of the instance group at boot
+cat > /etc/modules-load.d/99-k8s-instancegroup.conf <<'EOF'
+br_netfilter
+overlay
+EOF
+
+# Set the kernel parameters of the instance group and the cluster
+cat > /etc/sysctl.d/98-kops-image.conf <<'EOF'
+net.core.somaxconn = 4096
+net.ipv4.tcp_keepalive_time = 600
+fs.inotify.max_user_watches=524288
+EOF
+`
+	if actual := r.ProvisionScript(); actual != expected {
+		t.Errorf("unexpected provision script\n%s", diff.FormatDiff(expected, actual))
+	}
+}
+
+func TestPackerTemplate(t *testing.T) {
+	r := buildRecipe(t)
+
+	data, err := r.PackerTemplate("us-test-1", "ami-0123456789abcdef0", "t3.medium", "ubuntu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{
+  "builders": [
+    {
+      "type": "amazon-ebs",
+      "region": "us-test-1",
+      "source_ami": "ami-0123456789abcdef0",
+      "instance_type": "t3.medium",
+      "ssh_username": "ubuntu",
+      "ami_name": "kops-nodes-minimal.example.com-{{timestamp}}",
+      "tags": {
+        "Cluster": "minimal.example.com",
+        "InstanceGroup": "nodes",
+        "SourceImage": "ami-0123456789abcdef0"
+      }
+    }
+  ],
+  "provisioners": [
+    {
+      "type": "shell",
+      "script": "provision.sh",
+      "execute_command": "chmod +x {{ .Path }}; sudo {{ .Vars }} {{ .Path }}"
+    }
+  ],
+  "post-processors": [
+    {
+      "type": "manifest",
+      "output": "manifest.json"
+    }
+  ]
+}
+`
+	if actual := string(data); actual != expected {
+		t.Errorf("unexpected Packer template\n%s", diff.FormatDiff(expected, actual))
+	}
+
+	if _, err := r.PackerTemplate("us-test-1", "ubuntu/ubuntu-focal", "t3.medium", "ubuntu"); err == nil {
+		t.Errorf("expected an error for a source image that is not an AMI ID")
+	}
+}
+
+func TestImageBuilderComponent(t *testing.T) {
+	r := buildRecipe(t)
+
+	data, err := r.ImageBuilderComponent()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var component struct {
+		SchemaVersion string `json:"schemaVersion"`
+		Phases        []struct {
+			Name  string `json:"name"`
+			Steps []struct {
+				Action string `json:"action"`
+				Inputs struct {
+					Commands []string `json:"commands"`
+				} `json:"inputs"`
+			} `json:"steps"`
+		} `json:"phases"`
+	}
+	if err := yaml.Unmarshal(data, &component); err != nil {
+		t.Fatalf("error parsing component: %v", err)
+	}
+	if component.SchemaVersion != "1.0" || component.Phases[0].Name != "build" || component.Phases[0].Steps[0].Action != "ExecuteBash" {
+		t.Errorf("unexpected component\n%s", data)
+	}
+	if commands := component.Phases[0].Steps[0].Inputs.Commands; len(commands) != 1 || commands[0] != r.ProvisionScript() {
+		t.Errorf("expected the component to run the provision script, got %v", commands)
+	}
+}
+
+func TestNewRecipeRequiresSHA256(t *testing.T) {
+	u, _ := url.Parse("https://example.com/containerd.tar.gz")
+	h := hashing.MustFromString("da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	if _, err := NewRecipe(&kops.Cluster{}, &kops.InstanceGroup{}, u, h); err == nil {
+		t.Errorf("expected an error for a sha1 hash")
+	}
+}
+
+func TestProvisionScriptPrePullsImages(t *testing.T) {
+	r := buildRecipe(t)
+	r.KernelModules = nil
+	r.Sysctls = nil
+	r.Images = []string{
+		"k8s.gcr.io/kube-proxy:v1.21.0",
+		"calico/node:v3.19.1",
+		"busybox:1.33",
+	}
+
+	expected := `
+# Pre-pull the container images, so the kubelet finds them in the image store of containerd
+containerd --log-level warn &
+containerd_pid=$!
+for i in $(seq 60); do ctr version >/dev/null 2>&1 && break; sleep 1; done
+ctr --namespace k8s.io images pull "k8s.gcr.io/kube-proxy:v1.21.0"
+ctr --namespace k8s.io images pull "docker.io/calico/node:v3.19.1"
+ctr --namespace k8s.io images pull "docker.io/library/busybox:1.33"
+kill "${containerd_pid}"
+wait "${containerd_pid}" || true
+`
+	if actual := r.ProvisionScript(); !strings.HasSuffix(actual, expected) {
+		t.Errorf("unexpected provision script\n%s", actual)
+	}
+
+	r.ContainerdURL = nil
+	if actual := r.ProvisionScript(); strings.Contains(actual, "ctr") {
+		t.Errorf("expected images not to be pre-pulled without containerd\n%s", actual)
+	}
+}
+
+func TestImagesForRole(t *testing.T) {
+	images := []string{
+		"k8s.gcr.io/kube-apiserver:v1.21.0",
+		"k8s.gcr.io/kube-controller-manager:v1.21.0",
+		"k8s.gcr.io/kube-proxy:v1.21.0",
+		"k8s.gcr.io/kops/kops-controller:1.22.0",
+		"k8s.gcr.io/kops/kube-apiserver-healthcheck:1.22.0",
+		"k8s.gcr.io/etcdadm/etcd-manager:3.0.20210707@sha256:4f0e7b1e7d1c1bbd7b2cd5a2b73bcb3fbd8e76e0e9e5d1d1a4d6f0cd6d6c2a1e",
+		"k8s.gcr.io/coredns/coredns:v1.8.3",
+	}
+
+	grid := []struct {
+		role     kops.InstanceGroupRole
+		expected []string
+	}{
+		{
+			role:     kops.InstanceGroupRoleMaster,
+			expected: images,
+		},
+		{
+			role: kops.InstanceGroupRoleAPIServer,
+			expected: []string{
+				"k8s.gcr.io/kube-apiserver:v1.21.0",
+				"k8s.gcr.io/kube-proxy:v1.21.0",
+				"k8s.gcr.io/kops/kube-apiserver-healthcheck:1.22.0",
+				"k8s.gcr.io/coredns/coredns:v1.8.3",
+			},
+		},
+		{
+			role: kops.InstanceGroupRoleNode,
+			expected: []string{
+				"k8s.gcr.io/kube-proxy:v1.21.0",
+				"k8s.gcr.io/coredns/coredns:v1.8.3",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		if actual := ImagesForRole(images, g.role); !reflect.DeepEqual(actual, g.expected) {
+			t.Errorf("unexpected images for role %s: %v", g.role, actual)
+		}
+	}
+}
+
+func TestParsePackerManifest(t *testing.T) {
+	manifest := `{
+  "builds": [
+    {
+      "name": "amazon-ebs",
+      "builder_type": "amazon-ebs",
+      "artifact_id": "us-test-1:ami-00000000000000001",
+      "packer_run_uuid": "7d3b8c9e-0000-0000-0000-000000000001"
+    },
+    {
+      "name": "amazon-ebs",
+      "builder_type": "amazon-ebs",
+      "artifact_id": "us-test-1:ami-00000000000000002",
+      "packer_run_uuid": "7d3b8c9e-0000-0000-0000-000000000002"
+    }
+  ],
+  "last_run_uuid": "7d3b8c9e-0000-0000-0000-000000000002"
+}`
+
+	ami, err := ParsePackerManifest([]byte(manifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ami != "ami-00000000000000002" {
+		t.Errorf("unexpected AMI %q", ami)
+	}
+
+	if _, err := ParsePackerManifest([]byte(`{"builds": [], "last_run_uuid": "x"}`)); err == nil {
+		t.Errorf("expected an error for a manifest without builds")
+	}
+}
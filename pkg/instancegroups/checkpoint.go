@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// checkpointFile is the name of the file in the cluster's config base holding the rolling update progress.
+const checkpointFile = "rolling-update.json"
+
+// rollingUpdateProgress is the progress of a rolling update, as persisted in the state store.
+type rollingUpdateProgress struct {
+	// Groups holds the progress of each instance group, by name.
+	Groups map[string]*instanceGroupProgress `json:"groups,omitempty"`
+}
+
+// instanceGroupProgress is the progress of the rolling update of an instance group.
+type instanceGroupProgress struct {
+	// Validated is set once the cluster has validated before updating the instance group.
+	Validated bool `json:"validated,omitempty"`
+	// Pending is the IDs of the instances that were selected for update.
+	Pending []string `json:"pending,omitempty"`
+	// Replaced is the IDs of the instances that have been drained and terminated.
+	Replaced []string `json:"replaced,omitempty"`
+}
+
+// Checkpoint persists the progress of a rolling update in the state store,
+// so an interrupted rolling update can resume where it left off.
+type Checkpoint struct {
+	path vfs.Path
+
+	mutex    sync.Mutex
+	progress rollingUpdateProgress
+}
+
+// NewCheckpoint returns a Checkpoint stored under the config base of a cluster.
+func NewCheckpoint(configBase vfs.Path) *Checkpoint {
+	return &Checkpoint{
+		path: configBase.Join(checkpointFile),
+	}
+}
+
+// Load reads the progress of an interrupted rolling update, if any.
+// It returns the names of the instance groups that had been started.
+func (c *Checkpoint) Load() ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data, err := c.path.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading rolling update progress from %q: %v", c.path, err)
+	}
+
+	progress := rollingUpdateProgress{}
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return nil, fmt.Errorf("error parsing rolling update progress from %q: %v", c.path, err)
+	}
+	c.progress = progress
+
+	return sets.StringKeySet(progress.Groups).List(), nil
+}
+
+// Reset discards the progress of any interrupted rolling update.
+func (c *Checkpoint) Reset() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.progress = rollingUpdateProgress{}
+	if err := c.path.Remove(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing rolling update progress from %q: %v", c.path, err)
+	}
+	return nil
+}
+
+// groupProgress returns a copy of the recorded progress of the named instance group, or nil if none was recorded.
+func (c *Checkpoint) groupProgress(name string) *instanceGroupProgress {
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	progress := c.progress.Groups[name]
+	if progress == nil {
+		return nil
+	}
+	return &instanceGroupProgress{
+		Validated: progress.Validated,
+		Pending:   append([]string(nil), progress.Pending...),
+		Replaced:  append([]string(nil), progress.Replaced...),
+	}
+}
+
+// recordStarted records that the cluster validated and the instances to update in the named instance group.
+func (c *Checkpoint) recordStarted(name string, update []*cloudinstances.CloudInstance) {
+	c.record(name, func(progress *instanceGroupProgress) {
+		progress.Validated = true
+		pending := sets.NewString(progress.Pending...)
+		for _, u := range update {
+			pending.Insert(u.ID)
+		}
+		progress.Pending = pending.List()
+	})
+}
+
+// recordReplaced records that an instance of the named instance group has been drained and terminated.
+func (c *Checkpoint) recordReplaced(name string, id string) {
+	c.record(name, func(progress *instanceGroupProgress) {
+		progress.Replaced = sets.NewString(progress.Replaced...).Insert(id).List()
+	})
+}
+
+// clear removes the progress of the named instance groups, removing the checkpoint once no progress remains.
+func (c *Checkpoint) clear(names []string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, name := range names {
+		delete(c.progress.Groups, name)
+	}
+	if len(c.progress.Groups) == 0 {
+		if err := c.path.Remove(); err != nil && !os.IsNotExist(err) {
+			klog.Warningf("error removing rolling update progress from %q: %v", c.path, err)
+		}
+		return
+	}
+	c.save()
+}
+
+func (c *Checkpoint) record(name string, fn func(progress *instanceGroupProgress)) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.progress.Groups == nil {
+		c.progress.Groups = make(map[string]*instanceGroupProgress)
+	}
+	progress := c.progress.Groups[name]
+	if progress == nil {
+		progress = &instanceGroupProgress{}
+		c.progress.Groups[name] = progress
+	}
+	fn(progress)
+	c.save()
+}
+
+// save writes the progress to the state store. Failures are not fatal to the rolling update,
+// they only prevent it from being resumed.
+func (c *Checkpoint) save() {
+	data, err := json.Marshal(&c.progress)
+	if err != nil {
+		klog.Warningf("error serializing rolling update progress: %v", err)
+		return
+	}
+	if err := c.path.WriteFile(bytes.NewReader(data), nil); err != nil {
+		klog.Warningf("error writing rolling update progress to %q: %v", c.path, err)
+	}
+}
+
+// resumeUpdate returns the instances to update when resuming the rolling update of an instance group.
+// Instances that were selected for update and have not yet been replaced are kept, as are instances
+// that newly need updating. Instances that were created by the interrupted rolling update are not
+// updated again, even when forcing the update.
+func (p *instanceGroupProgress) resumeUpdate(needUpdate []*cloudinstances.CloudInstance, update []*cloudinstances.CloudInstance) []*cloudinstances.CloudInstance {
+	pending := sets.NewString(p.Pending...)
+	replaced := sets.NewString(p.Replaced...)
+	needsUpdate := sets.NewString()
+	for _, u := range needUpdate {
+		needsUpdate.Insert(u.ID)
+	}
+
+	var result []*cloudinstances.CloudInstance
+	for _, u := range update {
+		if replaced.Has(u.ID) {
+			continue
+		}
+		if pending.Has(u.ID) || needsUpdate.Has(u.ID) {
+			result = append(result, u)
+		}
+	}
+	return result
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kubectl/pkg/drain"
+)
+
+// podDisruptionBudgetBlocker is a pod whose eviction is prevented by a PodDisruptionBudget.
+type podDisruptionBudgetBlocker struct {
+	Pod                 *corev1.Pod
+	PodDisruptionBudget *policyv1beta1.PodDisruptionBudget
+}
+
+func (b *podDisruptionBudgetBlocker) String() string {
+	status := b.PodDisruptionBudget.Status
+	return fmt.Sprintf("pod %s/%s is blocked by PodDisruptionBudget %q (%d of %d desired pods healthy, %d disruptions allowed)",
+		b.Pod.Namespace, b.Pod.Name, b.PodDisruptionBudget.Name, status.CurrentHealthy, status.DesiredHealthy, status.DisruptionsAllowed)
+}
+
+// drainSettings returns the drain settings of the instance group of u.
+func (c *RollingUpdateCluster) drainSettings(u *cloudinstances.CloudInstance) *api.DrainSpec {
+	ig := &api.InstanceGroup{}
+	if u.CloudInstanceGroup != nil && u.CloudInstanceGroup.InstanceGroup != nil {
+		ig = u.CloudInstanceGroup.InstanceGroup
+	}
+	return resolveSettings(c.Cluster, ig, 0).Drain
+}
+
+// runNodeDrain drains the named node, taking the PodDisruptionBudgetTimeoutAction
+// for pods whose eviction is still blocked after the PodDisruptionBudgetTimeout.
+func (c *RollingUpdateCluster) runNodeDrain(helper *drain.Helper, nodeName string, settings *api.DrainSpec) error {
+	var timeout, pdbTimeout time.Duration
+	if settings.Timeout != nil {
+		timeout = settings.Timeout.Duration
+	}
+	if settings.PodDisruptionBudgetTimeout != nil {
+		pdbTimeout = settings.PodDisruptionBudgetTimeout.Duration
+	}
+
+	if pdbTimeout <= 0 || (timeout > 0 && pdbTimeout >= timeout) {
+		helper.Timeout = timeout
+		return c.withPodDisruptionBudgetDiagnostics(nodeName, drain.RunNodeDrain(helper, nodeName))
+	}
+
+	start := time.Now()
+	helper.Timeout = pdbTimeout
+	err := drain.RunNodeDrain(helper, nodeName)
+	if err == nil {
+		return nil
+	}
+
+	blockers, diagErr := c.findPodDisruptionBudgetBlockers(nodeName)
+	if diagErr != nil {
+		klog.Warningf("error looking for PodDisruptionBudgets blocking the drain of node %q: %v", nodeName, diagErr)
+	}
+	for _, blocker := range blockers {
+		klog.Warningf("Drain of node %q is stuck: %s.", nodeName, blocker)
+	}
+
+	if timeout > 0 {
+		helper.Timeout = timeout - time.Since(start)
+		if helper.Timeout <= 0 {
+			return c.withPodDisruptionBudgetDiagnostics(nodeName, err)
+		}
+	} else {
+		helper.Timeout = 0
+	}
+
+	if len(blockers) > 0 {
+		switch settings.PodDisruptionBudgetTimeoutAction {
+		case api.PodDisruptionBudgetTimeoutActionIgnore:
+			klog.Warningf("Deleting pods on node %q, ignoring their PodDisruptionBudgets.", nodeName)
+			helper.DisableEviction = true
+		case api.PodDisruptionBudgetTimeoutActionScaleUp:
+			restore := c.scaleUpOwners(blockers)
+			defer restore()
+		}
+	}
+
+	return c.withPodDisruptionBudgetDiagnostics(nodeName, drain.RunNodeDrain(helper, nodeName))
+}
+
+// withPodDisruptionBudgetDiagnostics adds the pods blocked by PodDisruptionBudgets to a drain error.
+func (c *RollingUpdateCluster) withPodDisruptionBudgetDiagnostics(nodeName string, err error) error {
+	if err == nil {
+		return nil
+	}
+	blockers, diagErr := c.findPodDisruptionBudgetBlockers(nodeName)
+	if diagErr != nil || len(blockers) == 0 {
+		return err
+	}
+	var messages []string
+	for _, blocker := range blockers {
+		messages = append(messages, blocker.String())
+	}
+	return fmt.Errorf("%v; %s", err, strings.Join(messages, "; "))
+}
+
+// findPodDisruptionBudgetBlockers returns the pods on the named node whose eviction is prevented by a PodDisruptionBudget.
+func (c *RollingUpdateCluster) findPodDisruptionBudgetBlockers(nodeName string) ([]*podDisruptionBudgetBlocker, error) {
+	pods, err := c.K8sClient.CoreV1().Pods(metav1.NamespaceAll).List(c.Ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods: %v", err)
+	}
+
+	pdbsByNamespace := make(map[string][]policyv1beta1.PodDisruptionBudget)
+	var blockers []*podDisruptionBudgetBlocker
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != nodeName || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if controller := metav1.GetControllerOf(pod); controller != nil && controller.Kind == "DaemonSet" {
+			continue
+		}
+
+		pdbs, found := pdbsByNamespace[pod.Namespace]
+		if !found {
+			list, err := c.K8sClient.PolicyV1beta1().PodDisruptionBudgets(pod.Namespace).List(c.Ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("error listing PodDisruptionBudgets in namespace %q: %v", pod.Namespace, err)
+			}
+			pdbs = list.Items
+			pdbsByNamespace[pod.Namespace] = pdbs
+		}
+
+		for j := range pdbs {
+			pdb := &pdbs[j]
+			if pdb.Status.DisruptionsAllowed > 0 {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			blockers = append(blockers, &podDisruptionBudgetBlocker{Pod: pod, PodDisruptionBudget: pdb})
+			break
+		}
+	}
+	return blockers, nil
+}
+
+// scaleUpOwners adds a replica to the Deployments and StatefulSets owning the blocked pods,
+// so their PodDisruptionBudgets allow an eviction. It returns a function restoring the original replicas.
+func (c *RollingUpdateCluster) scaleUpOwners(blockers []*podDisruptionBudgetBlocker) func() {
+	type owner struct {
+		kind      string
+		namespace string
+		name      string
+	}
+	scaled := make(map[owner]int32)
+
+	for _, blocker := range blockers {
+		kind, name, err := c.scalableOwnerOf(blocker.Pod)
+		if err != nil {
+			klog.Warningf("error finding owner of pod %s/%s: %v", blocker.Pod.Namespace, blocker.Pod.Name, err)
+			continue
+		}
+		if kind == "" {
+			klog.Warningf("Pod %s/%s is not owned by a Deployment or StatefulSet; not scaling up.", blocker.Pod.Namespace, blocker.Pod.Name)
+			continue
+		}
+		o := owner{kind: kind, namespace: blocker.Pod.Namespace, name: name}
+		if _, found := scaled[o]; found {
+			continue
+		}
+		original, err := c.adjustReplicas(o.kind, o.namespace, o.name, func(replicas int32) int32 { return replicas + 1 })
+		if err != nil {
+			klog.Warningf("error scaling up %s %s/%s: %v", o.kind, o.namespace, o.name, err)
+			continue
+		}
+		klog.Infof("Scaled up %s %s/%s to %d replicas to allow eviction of pod %q.", o.kind, o.namespace, o.name, original+1, blocker.Pod.Name)
+		scaled[o] = original
+	}
+
+	return func() {
+		for o, original := range scaled {
+			target := original
+			_, err := c.adjustReplicas(o.kind, o.namespace, o.name, func(replicas int32) int32 {
+				if replicas != target+1 {
+					// The replicas have been changed by someone else since we scaled up.
+					return replicas
+				}
+				return target
+			})
+			if err != nil {
+				klog.Warningf("error restoring replicas of %s %s/%s: %v", o.kind, o.namespace, o.name, err)
+				continue
+			}
+			klog.Infof("Restored %s %s/%s to %d replicas.", o.kind, o.namespace, o.name, target)
+		}
+	}
+}
+
+// scalableOwnerOf returns the kind and name of the Deployment or StatefulSet owning the pod,
+// or an empty kind if the pod is not owned by either.
+func (c *RollingUpdateCluster) scalableOwnerOf(pod *corev1.Pod) (string, string, error) {
+	controller := metav1.GetControllerOf(pod)
+	if controller == nil {
+		return "", "", nil
+	}
+	switch controller.Kind {
+	case "StatefulSet":
+		return controller.Kind, controller.Name, nil
+	case "ReplicaSet":
+		rs, err := c.K8sClient.AppsV1().ReplicaSets(pod.Namespace).Get(c.Ctx, controller.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", "", err
+		}
+		if rsController := metav1.GetControllerOf(rs); rsController != nil && rsController.Kind == "Deployment" {
+			return rsController.Kind, rsController.Name, nil
+		}
+	}
+	return "", "", nil
+}
+
+// adjustReplicas updates the replicas of a Deployment or StatefulSet and returns the previous value.
+func (c *RollingUpdateCluster) adjustReplicas(kind, namespace, name string, adjust func(int32) int32) (int32, error) {
+	switch kind {
+	case "Deployment":
+		deployment, err := c.K8sClient.AppsV1().Deployments(namespace).Get(c.Ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		original := replicasValue(deployment.Spec.Replicas)
+		replicas := adjust(original)
+		deployment.Spec.Replicas = &replicas
+		_, err = c.K8sClient.AppsV1().Deployments(namespace).Update(c.Ctx, deployment, metav1.UpdateOptions{})
+		return original, err
+	case "StatefulSet":
+		statefulSet, err := c.K8sClient.AppsV1().StatefulSets(namespace).Get(c.Ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		original := replicasValue(statefulSet.Spec.Replicas)
+		replicas := adjust(original)
+		statefulSet.Spec.Replicas = &replicas
+		_, err = c.K8sClient.AppsV1().StatefulSets(namespace).Update(c.Ctx, statefulSet, metav1.UpdateOptions{})
+		return original, err
+	default:
+		return 0, fmt.Errorf("unsupported kind %q", kind)
+	}
+}
+
+// replicasValue returns the replicas of a workload, which default to 1.
+func replicasValue(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -82,20 +82,42 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 
 	noneReady := len(group.Ready) == 0
 	numInstances := len(group.Ready) + len(group.NeedUpdate)
-	update := group.NeedUpdate
-	if c.Force {
-		update = append(update, group.Ready...)
+	update, progress := c.instancesToUpdate(group)
+	if progress != nil {
+		klog.Infof("Resuming rolling update of InstanceGroup %s, %d instances already replaced.", group.InstanceGroup.Name, len(progress.Replaced))
+		if len(progress.Replaced) > 0 {
+			// Replacements have already been created with the current spec.
+			noneReady = false
+		}
 	}
 
 	if len(update) == 0 {
 		return nil
 	}
 
+	if c.Interactive {
+		approved, stopPrompting, err := promptInstanceGroup(group, len(update))
+		if err != nil {
+			return err
+		}
+		if stopPrompting {
+			// Is a pointer to a struct, changes here push back into the original
+			c.Interactive = false
+		}
+		if !approved {
+			klog.Infof("Skipping update of InstanceGroup %s.", group.InstanceGroup.Name)
+			return nil
+		}
+	}
+
 	if isBastion {
 		klog.V(3).Info("Not validating the cluster as instance is a bastion.")
+	} else if progress != nil && progress.Validated {
+		klog.V(3).Infof("Not validating the cluster as the update of InstanceGroup %s is being resumed.", group.InstanceGroup.Name)
 	} else if err = c.maybeValidate("", 1, group); err != nil {
 		return err
 	}
+	c.Checkpoint.recordStarted(group.InstanceGroup.Name, update)
 
 	if !c.CloudOnly {
 		err = c.taintAllNeedUpdate(group, update)
@@ -139,6 +161,13 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 	}
 	update = nonWarmPool
 
+	if settings.Strategy == api.RollingUpdateStrategySurgeGroup && *settings.DrainAndTerminate && !c.CloudOnly && !isBastion && group.InstanceGroup.Spec.Role != api.InstanceGroupRoleMaster {
+		if surgeCloud, ok := c.Cloud.(fi.SurgeGroupCloud); ok {
+			return c.rollingUpdateWithSurgeGroup(surgeCloud, group, update, sleepAfterTerminate)
+		}
+		klog.Warningf("Cloud provider does not support surge groups; updating InstanceGroup %s in place.", group.InstanceGroup.Name)
+	}
+
 	if c.Interactive {
 		if maxSurge > 1 {
 			maxSurge = 1
@@ -153,6 +182,9 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 		for numSurge := 1; numSurge <= maxSurge; numSurge++ {
 			u := update[len(update)-numSurge+skippedNodes]
 			if u.Status != cloudinstances.CloudInstanceStatusDetached {
+				if err := c.checkMaintenanceWindow(); err != nil {
+					return err
+				}
 				if err := c.detachInstance(u); err != nil {
 					// If detaching a node fails, we simply proceed to the next one instead of
 					// bubbling up the error.
@@ -184,6 +216,10 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 	terminateChan := make(chan error, maxConcurrency)
 
 	for uIdx, u := range update {
+		if err := c.checkMaintenanceWindow(); err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+
 		go func(m *cloudinstances.CloudInstance) {
 			terminateChan <- c.drainTerminateAndWait(m, sleepAfterTerminate)
 		}(u)
@@ -256,6 +292,23 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 	return nil
 }
 
+// checkMaintenanceWindow returns an error if the maintenance window of the cluster has closed,
+// so that no more instances are replaced until it opens again.
+func (c *RollingUpdateCluster) checkMaintenanceWindow() error {
+	if c.MaintenanceWindow == nil {
+		return nil
+	}
+	now := time.Now()
+	if c.MaintenanceWindow.Contains(now) {
+		return nil
+	}
+	next := c.MaintenanceWindow.Next(now)
+	if next.IsZero() {
+		return fmt.Errorf("the maintenance window has closed")
+	}
+	return fmt.Errorf("the maintenance window has closed; run the rolling update again once it opens at %s to replace the remaining instances", next.Format(time.RFC3339))
+}
+
 func prioritizeUpdate(update []*cloudinstances.CloudInstance) []*cloudinstances.CloudInstance {
 	// The priorities are, in order:
 	//   attached before detached
@@ -425,6 +478,7 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 		klog.Errorf("error deleting instance %q, node %q: %v", instanceID, nodeName, err)
 		return err
 	}
+	c.Checkpoint.recordReplaced(u.CloudInstanceGroup.InstanceGroup.Name, instanceID)
 
 	if err := c.reconcileInstanceGroup(); err != nil {
 		klog.Errorf("error reconciling instance group %q: %v", u.CloudInstanceGroup.HumanName, err)
@@ -617,6 +671,8 @@ func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error
 		return fmt.Errorf("node name not set")
 	}
 
+	settings := c.drainSettings(u)
+
 	helper := &drain.Helper{
 		Ctx:                 c.Ctx,
 		Client:              c.K8sClient,
@@ -628,9 +684,12 @@ func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error
 
 		// We want to proceed even when pods are using emptyDir volumes
 		DeleteEmptyDirData: true,
-
-		// Other options we might want to set:
-		// Timeout?
+	}
+	if settings.GracePeriod != nil {
+		helper.GracePeriodSeconds = int(settings.GracePeriod.Duration.Seconds())
+	}
+	if settings.SkipWaitForDeleteTimeout != nil {
+		helper.SkipWaitForDeleteTimeoutSeconds = int(settings.SkipWaitForDeleteTimeout.Duration.Seconds())
 	}
 
 	if err := drain.RunCordonOrUncordon(helper, u.Node, true); err != nil {
@@ -647,7 +706,7 @@ func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error
 		return fmt.Errorf("error excluding node from load balancer: %v", err)
 	}
 
-	if err := drain.RunNodeDrain(helper, u.Node.Name); err != nil {
+	if err := c.runNodeDrain(helper, u.Node.Name, settings); err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil
 		}
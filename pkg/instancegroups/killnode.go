@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// KillInstance terminates an instance the way a failure would, without draining it or deleting its node,
+// then waits for its group to replace it and for the cluster to validate.
+// If detach is set the instance is first detached from its group, so that the replacement is launched straight away.
+// It returns the replacement instance.
+func (c *RollingUpdateCluster) KillInstance(u *cloudinstances.CloudInstance, detach bool) (*cloudinstances.CloudInstance, error) {
+	group := u.CloudInstanceGroup
+
+	existing := sets.NewString()
+	for _, instance := range append(append([]*cloudinstances.CloudInstance{}, group.Ready...), group.NeedUpdate...) {
+		existing.Insert(instance.ID)
+	}
+
+	if detach {
+		if group.InstanceGroup.IsMaster() {
+			klog.Warning("cannot detach master instances. Assuming --detach=false")
+		} else if err := c.detachInstance(u); err != nil {
+			return nil, fmt.Errorf("failed to detach instance: %v", err)
+		}
+	}
+
+	if err := c.deleteInstance(u); err != nil {
+		return nil, err
+	}
+
+	replacement, err := c.waitForReplacement(group, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.maybeValidate(" after killing instance", c.ValidateCount, group); err != nil {
+		return replacement, err
+	}
+	return replacement, nil
+}
+
+// waitForReplacement waits for an instance that is not one of the existing instances to join the group,
+// and unless CloudOnly is set, to register its node.
+func (c *RollingUpdateCluster) waitForReplacement(group *cloudinstances.CloudInstanceGroup, existing sets.String) (*cloudinstances.CloudInstance, error) {
+	ctx, cancel := context.WithTimeout(c.Ctx, c.ValidationTimeout)
+	defer cancel()
+
+	name := group.InstanceGroup.ObjectMeta.Name
+	klog.Infof("Waiting for instance group %q to replace the instance.", name)
+	for {
+		var nodes []corev1.Node
+		if !c.CloudOnly {
+			nodeList, err := c.K8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				klog.Infof("Unable to list nodes, will retry in %q: %v", c.ValidateTickDuration, err)
+			} else {
+				nodes = nodeList.Items
+			}
+		}
+
+		groups, err := c.Cloud.GetCloudGroups(c.Cluster, []*api.InstanceGroup{group.InstanceGroup}, false, nodes)
+		if err != nil {
+			klog.Infof("Unable to list instances, will retry in %q: %v", c.ValidateTickDuration, err)
+		} else if current := groups[name]; current != nil {
+			for _, instance := range append(append([]*cloudinstances.CloudInstance{}, current.Ready...), current.NeedUpdate...) {
+				if existing.Has(instance.ID) {
+					continue
+				}
+				if c.CloudOnly {
+					klog.Infof("Instance %q replaced the killed instance.", instance.ID)
+					return instance, nil
+				}
+				if instance.Node != nil {
+					klog.Infof("Instance %q, node %q, replaced the killed instance.", instance.ID, instance.Node.Name)
+					return instance, nil
+				}
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("instance group %q did not replace the instance within %s", name, c.ValidationTimeout)
+		}
+		time.Sleep(c.ValidateTickDuration)
+	}
+}
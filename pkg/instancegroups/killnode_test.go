@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// replacingCloud reports a replacement instance in the group from the second listing onwards.
+type replacingCloud struct {
+	*awsup.MockAWSCloud
+	group       *cloudinstances.CloudInstanceGroup
+	replacement string
+	listings    int
+}
+
+func (c *replacingCloud) GetCloudGroups(cluster *kopsapi.Cluster, instancegroups []*kopsapi.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	c.listings++
+	group := &cloudinstances.CloudInstanceGroup{
+		HumanName:     c.group.HumanName,
+		InstanceGroup: c.group.InstanceGroup,
+	}
+	for _, instance := range c.group.Ready {
+		group.NewCloudInstance(instance.ID, cloudinstances.CloudInstanceStatusUpToDate, instance.Node)
+	}
+	if c.listings > 1 {
+		var node *v1.Node
+		for i := range nodes {
+			if nodes[i].Name == c.replacement+".local" {
+				node = &nodes[i]
+			}
+		}
+		group.NewCloudInstance(c.replacement, cloudinstances.CloudInstanceStatusUpToDate, node)
+	}
+	return map[string]*cloudinstances.CloudInstanceGroup{group.InstanceGroup.ObjectMeta.Name: group}, nil
+}
+
+type detachRecorder struct {
+	autoscalingiface.AutoScalingAPI
+	instances []string
+}
+
+func (m *detachRecorder) DetachInstances(input *autoscaling.DetachInstancesInput) (*autoscaling.DetachInstancesOutput, error) {
+	m.instances = append(m.instances, aws.StringValueSlice(input.InstanceIds)...)
+	return &autoscaling.DetachInstancesOutput{}, nil
+}
+
+func TestKillInstance(t *testing.T) {
+	for _, detach := range []bool{false, true} {
+		c, mockCloud := getTestSetup()
+		c.ValidationTimeout = time.Minute
+
+		groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+		makeGroup(groups, c.K8sClient, mockCloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 0)
+		group := groups["node-1"]
+		detached := &detachRecorder{AutoScalingAPI: mockCloud.MockAutoscaling}
+		if detach {
+			mockCloud.MockAutoscaling = detached
+			mockCloud.MockEC2 = &ec2IgnoreTags{EC2API: mockCloud.MockEC2}
+		}
+		cloud := &replacingCloud{MockAWSCloud: mockCloud, group: group, replacement: "node-1d"}
+		c.Cloud = cloud
+
+		_, err := c.K8sClient.CoreV1().Nodes().Create(context.Background(), &v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-1d.local"}}, v1meta.CreateOptions{})
+		if err != nil {
+			t.Fatalf("error creating node: %v", err)
+		}
+
+		killed := group.Ready[1]
+		replacement, err := c.KillInstance(killed, detach)
+		if assert.NoError(t, err, "KillInstance") {
+			assert.Equal(t, "node-1d", replacement.ID, "replacement instance")
+			assert.Equal(t, "node-1d.local", replacement.Node.Name, "replacement node")
+		}
+		if detach {
+			assert.Equal(t, []string{killed.ID}, detached.instances, "detached instances")
+		} else {
+			assert.Empty(t, detached.instances, "detached instances")
+		}
+		assert.Equal(t, 2, cloud.listings, "listings of the instance group")
+
+		response, err := mockCloud.Autoscaling().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+			AutoScalingGroupNames: []*string{aws.String("node-1")},
+		})
+		if err != nil {
+			t.Fatalf("error describing autoscaling group: %v", err)
+		}
+		var remaining []string
+		for _, instance := range response.AutoScalingGroups[0].Instances {
+			remaining = append(remaining, aws.StringValue(instance.InstanceId))
+		}
+		assert.ElementsMatch(t, []string{"node-1a", "node-1c"}, remaining, "instances of the autoscaling group")
+
+		// The node is not drained or deleted, as it would not be when an instance fails.
+		_, err = c.K8sClient.CoreV1().Nodes().Get(context.Background(), killed.Node.Name, v1meta.GetOptions{})
+		assert.NoError(t, err, "node of the killed instance")
+	}
+}
+
+func TestKillInstanceReplacementTimeout(t *testing.T) {
+	c, mockCloud := getTestSetup()
+	c.ValidationTimeout = 10 * time.Millisecond
+	c.ClusterValidator = &assertNotCalledClusterValidator{T: t}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, mockCloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 0)
+	group := groups["node-1"]
+	// The replacement never registers a node.
+	c.Cloud = &replacingCloud{MockAWSCloud: mockCloud, group: group, replacement: "node-1d"}
+
+	_, err := c.KillInstance(group.Ready[0], false)
+	assert.Error(t, err, "KillInstance")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// PlannedReplacement is an instance that a rolling update will replace.
+type PlannedReplacement struct {
+	// Instance is the instance to replace.
+	Instance *cloudinstances.CloudInstance
+	// Reason describes why the instance will be replaced.
+	Reason string
+}
+
+// Plan returns the instances that a rolling update of the groups will replace, in the order they will be replaced.
+// Instance groups updated concurrently are listed in alphabetical order.
+func (c *RollingUpdateCluster) Plan(groups map[string]*cloudinstances.CloudInstanceGroup) ([]*PlannedReplacement, error) {
+	roles := []api.InstanceGroupRole{api.InstanceGroupRoleBastion, api.InstanceGroupRoleMaster, api.InstanceGroupRoleAPIServer, api.InstanceGroupRoleNode}
+
+	byRole := make(map[api.InstanceGroupRole]map[string]*cloudinstances.CloudInstanceGroup)
+	for k, group := range groups {
+		role := group.InstanceGroup.Spec.Role
+		switch role {
+		case api.InstanceGroupRoleBastion, api.InstanceGroupRoleMaster, api.InstanceGroupRoleAPIServer, api.InstanceGroupRoleNode:
+		default:
+			return nil, fmt.Errorf("unknown group type for group %q", group.InstanceGroup.ObjectMeta.Name)
+		}
+		if byRole[role] == nil {
+			byRole[role] = make(map[string]*cloudinstances.CloudInstanceGroup)
+		}
+		byRole[role][k] = group
+	}
+
+	var plan []*PlannedReplacement
+	for _, role := range roles {
+		for _, k := range sortGroups(byRole[role]) {
+			update, _ := c.instancesToUpdate(byRole[role][k])
+
+			var warmPool, other []*cloudinstances.CloudInstance
+			for _, u := range update {
+				if u.State == cloudinstances.WarmPool {
+					warmPool = append(warmPool, u)
+				} else {
+					other = append(other, u)
+				}
+			}
+			for _, u := range append(warmPool, prioritizeUpdate(other)...) {
+				plan = append(plan, &PlannedReplacement{
+					Instance: u,
+					Reason:   replacementReason(u),
+				})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// instancesToUpdate returns the instances of the group to update, taking into account the progress
+// of an interrupted rolling update, which is also returned.
+func (c *RollingUpdateCluster) instancesToUpdate(group *cloudinstances.CloudInstanceGroup) ([]*cloudinstances.CloudInstance, *instanceGroupProgress) {
+	update := group.NeedUpdate
+	if c.Force {
+		update = append(update, group.Ready...)
+	}
+
+	progress := c.Checkpoint.groupProgress(group.InstanceGroup.Name)
+	if progress != nil {
+		update = progress.resumeUpdate(group.NeedUpdate, update)
+	}
+	return update, progress
+}
+
+func replacementReason(u *cloudinstances.CloudInstance) string {
+	switch {
+	case u.State == cloudinstances.WarmPool:
+		return "warm pool instance"
+	case u.UpdateReason != "":
+		return u.UpdateReason
+	case u.Status == cloudinstances.CloudInstanceStatusUpToDate:
+		return "forced"
+	default:
+		return "instance specification changed"
+	}
+}
+
+// promptInstanceGroup asks the user to approve the update of an instance group.
+func promptInstanceGroup(group *cloudinstances.CloudInstanceGroup, numInstances int) (approved bool, stopPrompting bool, err error) {
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Printf("Update InstanceGroup %q, replacing %d instances? (Y)es, (S)kip, (N)o, (A)lwaysYes: [Y] ", group.InstanceGroup.Name, numInstances)
+	scanner.Scan()
+	err = scanner.Err()
+	if err != nil {
+		klog.Infof("unable to interpret input: %v", err)
+		return false, false, err
+	}
+	val := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	switch val {
+	case "n":
+		klog.Info("User signaled to stop")
+		os.Exit(3)
+	case "s":
+		return false, false, nil
+	case "a":
+		klog.Info("Always Yes, stop prompting for rest of hosts")
+		return true, true, nil
+	}
+	return true, false, nil
+}
@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/maintenancewindow"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi"
 )
@@ -77,6 +78,15 @@ type RollingUpdateCluster struct {
 
 	// ValidateCount is the amount of time that a cluster needs to be validated after single node update
 	ValidateCount int
+
+	// Parallelism is the maximum number of node instance groups to update concurrently
+	Parallelism int
+
+	// Checkpoint, if set, persists the progress of the rolling update so it can be resumed if interrupted
+	Checkpoint *Checkpoint
+
+	// MaintenanceWindow, if set, stops the rolling update before replacing another instance once the window closes
+	MaintenanceWindow *maintenancewindow.Window
 }
 
 // AdjustNeedUpdate adjusts the set of instances that need updating, using factors outside those known by the cloud implementation
@@ -165,39 +175,31 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 
 	// Upgrade API servers
 	{
+		resultsMutex.Lock()
 		for k := range apiServerGroups {
 			results[k] = fmt.Errorf("function panic apiservers")
 		}
+		resultsMutex.Unlock()
 
 		for _, k := range sortGroups(apiServerGroups) {
 			err := c.rollingUpdateInstanceGroup(apiServerGroups[k], c.NodeInterval)
 
+			resultsMutex.Lock()
 			results[k] = err
+			resultsMutex.Unlock()
 
 			// TODO: Bail on error?
 		}
 	}
 
 	// Upgrade nodes
-	{
-		// We run nodes in series, even if they are in separate instance groups
-		// typically they will not being separate instance groups. If you roll the nodes in parallel
-		// you can get into a scenario where you can evict multiple statefulset pods from the same
-		// statefulset at the same time. Further improvements needs to be made to protect from this as
-		// well.
-
-		for k := range nodeGroups {
-			results[k] = fmt.Errorf("function panic nodes")
-		}
-
-		for _, k := range sortGroups(nodeGroups) {
-			err := c.rollingUpdateInstanceGroup(nodeGroups[k], c.NodeInterval)
-
-			results[k] = err
-
-			// TODO: Bail on error?
-		}
-	}
+	//
+	// By default we run nodes in series, even if they are in separate instance groups
+	// typically they will not being separate instance groups. If you roll the nodes in parallel
+	// you can get into a scenario where you can evict multiple statefulset pods from the same
+	// statefulset at the same time. Further improvements needs to be made to protect from this as
+	// well.
+	c.rollingUpdateNodeGroups(nodeGroups, results, &resultsMutex)
 
 	for _, err := range results {
 		if err != nil {
@@ -205,10 +207,53 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 		}
 	}
 
+	var names []string
+	for _, group := range groups {
+		names = append(names, group.InstanceGroup.Name)
+	}
+	c.Checkpoint.clear(names)
+
 	klog.Infof("Rolling update completed for cluster %q!", c.ClusterName)
 	return nil
 }
 
+// rollingUpdateNodeGroups updates the node instance groups in alphabetical order, up to Parallelism of them at a time,
+// recording the outcome for each group in results.
+func (c *RollingUpdateCluster) rollingUpdateNodeGroups(groups map[string]*cloudinstances.CloudInstanceGroup, results map[string]error, resultsMutex *sync.Mutex) {
+	parallelism := c.Parallelism
+	if parallelism < 1 || c.Interactive {
+		parallelism = 1
+	}
+
+	resultsMutex.Lock()
+	for k := range groups {
+		results[k] = fmt.Errorf("function panic nodes")
+	}
+	resultsMutex.Unlock()
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, parallelism)
+	for _, k := range sortGroups(groups) {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(k string) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			err := c.rollingUpdateInstanceGroup(groups[k], c.NodeInterval)
+
+			resultsMutex.Lock()
+			results[k] = err
+			resultsMutex.Unlock()
+
+			// TODO: Bail on error?
+		}(k)
+	}
+	wg.Wait()
+}
+
 func sortGroups(groupMap map[string]*cloudinstances.CloudInstanceGroup) []string {
 	groups := make([]string, 0, len(groupMap))
 	for group := range groupMap {
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestRollingUpdateCheckpointRecordsProgress(t *testing.T) {
+	c, cloud := getTestSetup()
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "clusters/test.k8s.local")
+	c.Checkpoint = NewCheckpoint(configBase)
+	c.ClusterValidator = &failAfterOneNodeClusterValidator{
+		Cloud:       cloud,
+		Group:       "node-1",
+		ReturnError: false,
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.Error(t, err, "rolling update")
+
+	checkpoint := NewCheckpoint(configBase)
+	resumed, err := checkpoint.Load()
+	if !assert.NoError(t, err, "loading checkpoint") {
+		return
+	}
+	assert.Equal(t, []string{"node-1"}, resumed, "resumed instance groups")
+	assert.Equal(t, &instanceGroupProgress{
+		Validated: true,
+		Pending:   []string{"node-1a", "node-1b", "node-1c"},
+		Replaced:  []string{"node-1a"},
+	}, checkpoint.groupProgress("node-1"))
+}
+
+func TestRollingUpdateCheckpointResume(t *testing.T) {
+	c, cloud := getTestSetup()
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "clusters/test.k8s.local")
+	c.Checkpoint = NewCheckpoint(configBase)
+	c.Checkpoint.recordStarted("node-1", []*cloudinstances.CloudInstance{{ID: "node-1a"}, {ID: "node-1b"}, {ID: "node-1x"}})
+	c.Checkpoint.recordReplaced("node-1", "node-1x")
+	c.Force = true
+
+	// node-1c has been created by the interrupted rolling update
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 2)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+
+	_, err = configBase.Join(checkpointFile).ReadFile()
+	assert.True(t, os.IsNotExist(err), "checkpoint removed after completion")
+}
+
+func TestResumeUpdate(t *testing.T) {
+	progress := &instanceGroupProgress{
+		Pending:  []string{"old-1", "old-2", "old-3"},
+		Replaced: []string{"old-1"},
+	}
+	old1 := &cloudinstances.CloudInstance{ID: "old-1"}
+	old2 := &cloudinstances.CloudInstance{ID: "old-2"}
+	changed := &cloudinstances.CloudInstance{ID: "changed"}
+	replacement := &cloudinstances.CloudInstance{ID: "replacement"}
+
+	needUpdate := []*cloudinstances.CloudInstance{old1, old2, changed}
+	update := append(append([]*cloudinstances.CloudInstance{}, needUpdate...), replacement)
+
+	assert.Equal(t, []*cloudinstances.CloudInstance{old2, changed}, progress.resumeUpdate(needUpdate, update))
+}
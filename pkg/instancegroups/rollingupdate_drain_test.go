@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func controllerRef(kind, name string) []v1meta.OwnerReference {
+	return []v1meta.OwnerReference{{Kind: kind, Name: name, Controller: fi.Bool(true)}}
+}
+
+func addDrainTestObjects(t *testing.T, client *fake.Clientset) {
+	objects := []runtime.Object{
+		&v1.Pod{
+			ObjectMeta: v1meta.ObjectMeta{Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web"}, OwnerReferences: controllerRef("ReplicaSet", "web-abc")},
+			Spec:       v1.PodSpec{NodeName: "node-1"},
+		},
+		&v1.Pod{
+			ObjectMeta: v1meta.ObjectMeta{Name: "db-0", Namespace: "default", Labels: map[string]string{"app": "db"}, OwnerReferences: controllerRef("StatefulSet", "db")},
+			Spec:       v1.PodSpec{NodeName: "node-1"},
+		},
+		&v1.Pod{
+			ObjectMeta: v1meta.ObjectMeta{Name: "cache-1", Namespace: "default", Labels: map[string]string{"app": "cache"}},
+			Spec:       v1.PodSpec{NodeName: "node-1"},
+		},
+		&v1.Pod{
+			ObjectMeta: v1meta.ObjectMeta{Name: "web-2", Namespace: "default", Labels: map[string]string{"app": "web"}, OwnerReferences: controllerRef("ReplicaSet", "web-abc")},
+			Spec:       v1.PodSpec{NodeName: "node-2"},
+		},
+		&v1.Pod{
+			ObjectMeta: v1meta.ObjectMeta{Name: "agent-1", Namespace: "default", Labels: map[string]string{"app": "web"}, OwnerReferences: controllerRef("DaemonSet", "agent")},
+			Spec:       v1.PodSpec{NodeName: "node-1"},
+		},
+		&policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: v1meta.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       policyv1beta1.PodDisruptionBudgetSpec{Selector: &v1meta.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+			Status:     policyv1beta1.PodDisruptionBudgetStatus{CurrentHealthy: 2, DesiredHealthy: 2},
+		},
+		&policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: v1meta.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       policyv1beta1.PodDisruptionBudgetSpec{Selector: &v1meta.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+			Status:     policyv1beta1.PodDisruptionBudgetStatus{CurrentHealthy: 3, DesiredHealthy: 3},
+		},
+		&policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: v1meta.ObjectMeta{Name: "cache", Namespace: "default"},
+			Spec:       policyv1beta1.PodDisruptionBudgetSpec{Selector: &v1meta.LabelSelector{MatchLabels: map[string]string{"app": "cache"}}},
+			Status:     policyv1beta1.PodDisruptionBudgetStatus{CurrentHealthy: 3, DesiredHealthy: 2, DisruptionsAllowed: 1},
+		},
+		&appsv1.ReplicaSet{
+			ObjectMeta: v1meta.ObjectMeta{Name: "web-abc", Namespace: "default", OwnerReferences: controllerRef("Deployment", "web")},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: v1meta.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: fi.Int32(2)},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: v1meta.ObjectMeta{Name: "db", Namespace: "default"},
+		},
+	}
+	for _, obj := range objects {
+		if err := client.Tracker().Add(obj); err != nil {
+			t.Fatalf("error adding object: %v", err)
+		}
+	}
+}
+
+func TestFindPodDisruptionBudgetBlockers(t *testing.T) {
+	c, _ := getTestSetup()
+	addDrainTestObjects(t, c.K8sClient.(*fake.Clientset))
+
+	blockers, err := c.findPodDisruptionBudgetBlockers("node-1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var messages []string
+	for _, blocker := range blockers {
+		messages = append(messages, blocker.String())
+	}
+	assert.ElementsMatch(t, []string{
+		`pod default/web-1 is blocked by PodDisruptionBudget "web" (2 of 2 desired pods healthy, 0 disruptions allowed)`,
+		`pod default/db-0 is blocked by PodDisruptionBudget "db" (3 of 3 desired pods healthy, 0 disruptions allowed)`,
+	}, messages)
+}
+
+func TestScaleUpOwners(t *testing.T) {
+	c, _ := getTestSetup()
+	addDrainTestObjects(t, c.K8sClient.(*fake.Clientset))
+
+	blockers, err := c.findPodDisruptionBudgetBlockers("node-1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	restore := c.scaleUpOwners(blockers)
+
+	deployment, err := c.K8sClient.AppsV1().Deployments("default").Get(c.Ctx, "web", v1meta.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas, "scaled up deployment replicas")
+	statefulSet, err := c.K8sClient.AppsV1().StatefulSets("default").Get(c.Ctx, "db", v1meta.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), *statefulSet.Spec.Replicas, "scaled up statefulset replicas")
+
+	// Simulate someone else changing the statefulset while draining
+	statefulSet.Spec.Replicas = fi.Int32(5)
+	_, err = c.K8sClient.AppsV1().StatefulSets("default").Update(c.Ctx, statefulSet, v1meta.UpdateOptions{})
+	assert.NoError(t, err)
+
+	restore()
+
+	deployment, err = c.K8sClient.AppsV1().Deployments("default").Get(c.Ctx, "web", v1meta.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas, "restored deployment replicas")
+	statefulSet, err = c.K8sClient.AppsV1().StatefulSets("default").Get(c.Ctx, "db", v1meta.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), *statefulSet.Spec.Replicas, "externally changed statefulset replicas")
+}
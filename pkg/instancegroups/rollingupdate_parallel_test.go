@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/validation"
+)
+
+// concurrencyCountingValidator records the maximum number of concurrent validations.
+type concurrencyCountingValidator struct {
+	mutex     sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (v *concurrencyCountingValidator) Validate() (*validation.ValidationCluster, error) {
+	v.mutex.Lock()
+	v.active++
+	if v.active > v.maxActive {
+		v.maxActive = v.active
+	}
+	v.mutex.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	v.mutex.Lock()
+	v.active--
+	v.mutex.Unlock()
+	return &validation.ValidationCluster{}, nil
+}
+
+func TestRollingUpdateParallelism(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		parallelism int
+		expected    int
+	}{
+		{
+			name:     "default",
+			expected: 1,
+		},
+		{
+			name:        "parallel",
+			parallelism: 3,
+			expected:    3,
+		},
+		{
+			name:        "more than groups",
+			parallelism: 5,
+			expected:    3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, cloud := getTestSetup()
+			c.Parallelism = tc.parallelism
+			validator := &concurrencyCountingValidator{}
+			c.ClusterValidator = validator
+
+			groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+			makeGroup(groups, c.K8sClient, cloud, "master-1", kopsapi.InstanceGroupRoleMaster, 1, 1)
+			makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 1, 1)
+			makeGroup(groups, c.K8sClient, cloud, "node-2", kopsapi.InstanceGroupRoleNode, 1, 1)
+			makeGroup(groups, c.K8sClient, cloud, "node-3", kopsapi.InstanceGroupRoleNode, 1, 1)
+
+			err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+			assert.NoError(t, err, "rolling update")
+
+			assert.Equal(t, tc.expected, validator.maxActive, "concurrently updated instance groups")
+			for _, name := range []string{"master-1", "node-1", "node-2", "node-3"} {
+				assertGroupInstanceCount(t, cloud, name, 0)
+			}
+		})
+	}
+}
+
+func TestRollingUpdateParallelismAPIServersInSeries(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Parallelism = 3
+	validator := &concurrencyCountingValidator{}
+	c.ClusterValidator = validator
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "apiserver-1", kopsapi.InstanceGroupRoleAPIServer, 1, 1)
+	makeGroup(groups, c.K8sClient, cloud, "apiserver-2", kopsapi.InstanceGroupRoleAPIServer, 1, 1)
+	makeGroup(groups, c.K8sClient, cloud, "apiserver-3", kopsapi.InstanceGroupRoleAPIServer, 1, 1)
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, 1, validator.maxActive, "concurrently updated instance groups")
+	for _, name := range []string{"apiserver-1", "apiserver-2", "apiserver-3"} {
+		assertGroupInstanceCount(t, cloud, name, 0)
+	}
+}
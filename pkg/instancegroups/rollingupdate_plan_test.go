@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func TestPlan(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-2", kopsapi.InstanceGroupRoleNode, 2, 2)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 2, 1)
+	makeGroup(groups, c.K8sClient, cloud, "master-1", kopsapi.InstanceGroupRoleMaster, 1, 1)
+	makeGroup(groups, c.K8sClient, cloud, "bastion-1", kopsapi.InstanceGroupRoleBastion, 1, 1)
+	groups["node-2"].NeedUpdate[0].Status = cloudinstances.CloudInstanceStatusDetached
+	groups["node-2"].NeedUpdate[1].UpdateReason = "user data changed"
+	groups["node-1"].NeedUpdate[0].State = cloudinstances.WarmPool
+
+	type planned struct {
+		ID     string
+		Reason string
+	}
+	getPlan := func() []planned {
+		plan, err := c.Plan(groups)
+		if !assert.NoError(t, err, "plan") {
+			return nil
+		}
+		var result []planned
+		for _, r := range plan {
+			result = append(result, planned{ID: r.Instance.ID, Reason: r.Reason})
+		}
+		return result
+	}
+
+	assert.Equal(t, []planned{
+		{ID: "bastion-1a", Reason: "instance specification changed"},
+		{ID: "master-1a", Reason: "instance specification changed"},
+		{ID: "node-1a", Reason: "warm pool instance"},
+		{ID: "node-2b", Reason: "user data changed"},
+		{ID: "node-2a", Reason: "instance specification changed"},
+	}, getPlan())
+
+	c.Force = true
+	assert.Equal(t, []planned{
+		{ID: "bastion-1a", Reason: "instance specification changed"},
+		{ID: "master-1a", Reason: "instance specification changed"},
+		{ID: "node-1a", Reason: "warm pool instance"},
+		{ID: "node-1b", Reason: "forced"},
+		{ID: "node-2b", Reason: "user data changed"},
+		{ID: "node-2a", Reason: "instance specification changed"},
+	}, getPlan())
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclient "k8s.io/client-go/testing"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+type surgeGroupTest struct {
+	autoscalingiface.AutoScalingAPI
+	t           *testing.T
+	k8sClient   *fake.Clientset
+	ready       bool
+	mutex       sync.Mutex
+	created     []string
+	deleted     []string
+	numDetached int
+}
+
+func (m *surgeGroupTest) CreateAutoScalingGroup(input *autoscaling.CreateAutoScalingGroupInput) (*autoscaling.CreateAutoScalingGroupOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	output, err := m.AutoScalingAPI.CreateAutoScalingGroup(input)
+	if err != nil {
+		return nil, err
+	}
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	m.created = append(m.created, name)
+
+	var instanceIDs []*string
+	for i := 0; i < int(aws.Int64Value(input.DesiredCapacity)); i++ {
+		id := fmt.Sprintf("%s-%d", name, i)
+		status := v1.ConditionFalse
+		if m.ready {
+			status = v1.ConditionTrue
+		}
+		node := &v1.Node{
+			ObjectMeta: v1meta.ObjectMeta{Name: id + ".local"},
+			Spec:       v1.NodeSpec{ProviderID: "aws:///us-east-1a/" + id},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: status}},
+			},
+		}
+		_ = m.k8sClient.Tracker().Add(node)
+		instanceIDs = append(instanceIDs, aws.String(id))
+	}
+	if _, err := m.AutoScalingAPI.AttachInstances(&autoscaling.AttachInstancesInput{
+		AutoScalingGroupName: input.AutoScalingGroupName,
+		InstanceIds:          instanceIDs,
+	}); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (m *surgeGroupTest) DeleteAutoScalingGroup(input *autoscaling.DeleteAutoScalingGroupInput) (*autoscaling.DeleteAutoScalingGroupOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.deleted = append(m.deleted, aws.StringValue(input.AutoScalingGroupName))
+	return m.AutoScalingAPI.DeleteAutoScalingGroup(input)
+}
+
+func (m *surgeGroupTest) DetachInstances(input *autoscaling.DetachInstancesInput) (*autoscaling.DetachInstancesOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.numDetached += len(input.InstanceIds)
+	return &autoscaling.DetachInstancesOutput{}, nil
+}
+
+func TestRollingUpdateSurgeGroup(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kops.InstanceGroupRoleNode, 3, 3)
+	groups["node-1"].InstanceGroup.Spec.RollingUpdate = &kops.RollingUpdate{
+		Strategy: kops.RollingUpdateStrategySurgeGroup,
+	}
+
+	surgeGroupTest := &surgeGroupTest{
+		AutoScalingAPI: cloud.MockAutoscaling,
+		t:              t,
+		k8sClient:      c.K8sClient.(*fake.Clientset),
+		ready:          true,
+	}
+	cloud.MockAutoscaling = surgeGroupTest
+
+	err := c.RollingUpdate(groups, &kops.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, []string{"node-1-surge"}, surgeGroupTest.created, "created surge groups")
+	assert.Equal(t, []string{"node-1-surge"}, surgeGroupTest.deleted, "deleted surge groups")
+	assert.Equal(t, 0, surgeGroupTest.numDetached, "number of detached instances")
+
+	deleted := map[string]bool{}
+	for _, action := range c.K8sClient.(*fake.Clientset).Actions() {
+		if a, ok := action.(testingclient.DeleteAction); ok && a.GetVerb() == "delete" {
+			assert.Equal(t, "nodes", a.GetResource().Resource)
+			if strings.Contains(a.GetName(), "surge") {
+				assert.True(t, deleted["node-1a.local"] && deleted["node-1b.local"] && deleted["node-1c.local"], "old nodes are deleted before surge nodes")
+			}
+			deleted[a.GetName()] = true
+		}
+	}
+	assert.Len(t, deleted, 6, "number of deleted nodes")
+
+	asgGroups, _ := cloud.Autoscaling().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String("node-1")},
+	})
+	for _, group := range asgGroups.AutoScalingGroups {
+		assert.Emptyf(t, group.Instances, "Not all instances terminated in group %s", group.AutoScalingGroupName)
+	}
+}
+
+func TestRollingUpdateSurgeGroupNotReady(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kops.InstanceGroupRoleNode, 3, 3)
+	c.Cluster.Spec.RollingUpdate = &kops.RollingUpdate{
+		Strategy: kops.RollingUpdateStrategySurgeGroup,
+	}
+
+	surgeGroupTest := &surgeGroupTest{
+		AutoScalingAPI: cloud.MockAutoscaling,
+		t:              t,
+		k8sClient:      c.K8sClient.(*fake.Clientset),
+	}
+	cloud.MockAutoscaling = surgeGroupTest
+
+	err := c.RollingUpdate(groups, &kops.InstanceGroupList{})
+	assert.Error(t, err, "rolling update")
+
+	assert.Equal(t, []string{"node-1-surge"}, surgeGroupTest.created, "created surge groups")
+	assert.Empty(t, surgeGroupTest.deleted, "surge group is kept")
+	assert.Len(t, groups["node-1"].NeedUpdate, 3, "no instances replaced")
+	for _, action := range c.K8sClient.(*fake.Clientset).Actions() {
+		assert.NotEqual(t, "delete", action.GetVerb(), "no nodes deleted")
+	}
+}
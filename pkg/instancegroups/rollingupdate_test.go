@@ -30,6 +30,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -39,6 +40,7 @@ import (
 	"k8s.io/kops/cloudmock/aws/mockautoscaling"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/maintenancewindow"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
@@ -329,6 +331,38 @@ func TestRollingUpdateNoneNeedUpdateWithForce(t *testing.T) {
 	}
 }
 
+func TestRollingUpdateMaintenanceWindow(t *testing.T) {
+	c, cloud := getTestSetup()
+	groups := getGroupsAllNeedUpdate(c.K8sClient, cloud)
+
+	window, err := maintenancewindow.New("0 0 30 FEB *", time.Hour)
+	require.NoError(t, err)
+	c.MaintenanceWindow = window
+
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), "the maintenance window has closed")
+	}
+
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+	assertGroupInstanceCount(t, cloud, "node-2", 3)
+	assertGroupInstanceCount(t, cloud, "master-1", 2)
+	assertGroupInstanceCount(t, cloud, "bastion-1", 1)
+
+	c, cloud = getTestSetup()
+	groups = getGroupsAllNeedUpdate(c.K8sClient, cloud)
+
+	window, err = maintenancewindow.New("* * * * *", time.Minute)
+	require.NoError(t, err)
+	c.MaintenanceWindow = window
+
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+	assertGroupInstanceCount(t, cloud, "master-1", 0)
+}
+
 func TestRollingUpdateEmptyGroup(t *testing.T) {
 
 	c, cloud := getTestSetup()
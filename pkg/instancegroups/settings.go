@@ -39,6 +39,19 @@ func resolveSettings(cluster *kops.Cluster, group *kops.InstanceGroup, numInstan
 		if rollingUpdate.MaxSurge == nil {
 			rollingUpdate.MaxSurge = def.MaxSurge
 		}
+		if rollingUpdate.Strategy == "" {
+			rollingUpdate.Strategy = def.Strategy
+		}
+	}
+
+	var drainDefaults *kops.DrainSpec
+	if cluster.Spec.RollingUpdate != nil {
+		drainDefaults = cluster.Spec.RollingUpdate.Drain
+	}
+	rollingUpdate.Drain = resolveDrainSettings(rollingUpdate.Drain, drainDefaults)
+
+	if rollingUpdate.Strategy == "" {
+		rollingUpdate.Strategy = kops.RollingUpdateStrategyInPlace
 	}
 
 	if rollingUpdate.DrainAndTerminate == nil {
@@ -79,3 +92,32 @@ func resolveSettings(cluster *kops.Cluster, group *kops.InstanceGroup, numInstan
 
 	return rollingUpdate
 }
+
+// resolveDrainSettings returns a copy of drain with unset fields inherited from def, if any.
+func resolveDrainSettings(drain, def *kops.DrainSpec) *kops.DrainSpec {
+	resolved := kops.DrainSpec{}
+	if drain != nil {
+		resolved = *drain
+	}
+	if def != nil {
+		if resolved.Timeout == nil {
+			resolved.Timeout = def.Timeout
+		}
+		if resolved.GracePeriod == nil {
+			resolved.GracePeriod = def.GracePeriod
+		}
+		if resolved.SkipWaitForDeleteTimeout == nil {
+			resolved.SkipWaitForDeleteTimeout = def.SkipWaitForDeleteTimeout
+		}
+		if resolved.PodDisruptionBudgetTimeout == nil {
+			resolved.PodDisruptionBudgetTimeout = def.PodDisruptionBudgetTimeout
+		}
+		if resolved.PodDisruptionBudgetTimeoutAction == "" {
+			resolved.PodDisruptionBudgetTimeoutAction = def.PodDisruptionBudgetTimeoutAction
+		}
+	}
+	if resolved.PodDisruptionBudgetTimeoutAction == "" {
+		resolved.PodDisruptionBudgetTimeoutAction = kops.PodDisruptionBudgetTimeoutActionBlock
+	}
+	return &resolved
+}
@@ -20,8 +20,10 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/kops/pkg/apis/kops"
 )
@@ -221,3 +223,81 @@ func TestAWSDefault(t *testing.T) {
 	assert.Equal(t, intstr.Int, resolved.MaxUnavailable.Type)
 	assert.Equal(t, int32(0), resolved.MaxUnavailable.IntVal)
 }
+
+func TestStrategy(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cluster  *kops.RollingUpdate
+		group    *kops.RollingUpdate
+		expected kops.RollingUpdateStrategy
+	}{
+		{
+			name:     "default",
+			expected: kops.RollingUpdateStrategyInPlace,
+		},
+		{
+			name:     "cluster",
+			cluster:  &kops.RollingUpdate{Strategy: kops.RollingUpdateStrategySurgeGroup},
+			group:    &kops.RollingUpdate{},
+			expected: kops.RollingUpdateStrategySurgeGroup,
+		},
+		{
+			name:     "group",
+			cluster:  &kops.RollingUpdate{Strategy: kops.RollingUpdateStrategySurgeGroup},
+			group:    &kops.RollingUpdate{Strategy: kops.RollingUpdateStrategyInPlace},
+			expected: kops.RollingUpdateStrategyInPlace,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved := resolveSettings(&kops.Cluster{
+				Spec: kops.ClusterSpec{
+					RollingUpdate: tc.cluster,
+				},
+			}, &kops.InstanceGroup{
+				Spec: kops.InstanceGroupSpec{
+					RollingUpdate: tc.group,
+				},
+			}, 1)
+			assert.Equal(t, tc.expected, resolved.Strategy)
+		})
+	}
+}
+
+func TestDrainSettings(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			RollingUpdate: &kops.RollingUpdate{
+				Drain: &kops.DrainSpec{
+					Timeout:                          &metav1.Duration{Duration: time.Hour},
+					PodDisruptionBudgetTimeoutAction: kops.PodDisruptionBudgetTimeoutActionIgnore,
+				},
+			},
+		},
+	}
+	group := &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			RollingUpdate: &kops.RollingUpdate{
+				Drain: &kops.DrainSpec{
+					GracePeriod:                      &metav1.Duration{Duration: time.Minute},
+					PodDisruptionBudgetTimeoutAction: kops.PodDisruptionBudgetTimeoutActionScaleUp,
+				},
+			},
+		},
+	}
+
+	resolved := resolveSettings(cluster, group, 1)
+	assert.Equal(t, &kops.DrainSpec{
+		Timeout:                          &metav1.Duration{Duration: time.Hour},
+		GracePeriod:                      &metav1.Duration{Duration: time.Minute},
+		PodDisruptionBudgetTimeoutAction: kops.PodDisruptionBudgetTimeoutActionScaleUp,
+	}, resolved.Drain)
+	assert.Nil(t, cluster.Spec.RollingUpdate.Drain.GracePeriod, "cluster defaults not modified")
+
+	group.Spec.RollingUpdate.Drain.PodDisruptionBudgetTimeoutAction = ""
+	resolved = resolveSettings(&kops.Cluster{}, group, 1)
+	assert.Equal(t, kops.PodDisruptionBudgetTimeoutActionBlock, resolved.Drain.PodDisruptionBudgetTimeoutAction)
+	assert.Empty(t, group.Spec.RollingUpdate.Drain.PodDisruptionBudgetTimeoutAction, "group settings not modified")
+
+	resolved = resolveSettings(&kops.Cluster{}, &kops.InstanceGroup{}, 1)
+	assert.Equal(t, &kops.DrainSpec{PodDisruptionBudgetTimeoutAction: kops.PodDisruptionBudgetTimeoutActionBlock}, resolved.Drain)
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// resumeManifestFile is the name of the file in the cluster's config base holding the state of a shut down cluster.
+const resumeManifestFile = "shutdown.json"
+
+// shutdownProcesses are the autoscaling processes suspended while a cluster is shut down, so that
+// nothing starts instances again. Terminate is left running, so the groups can scale to zero.
+var shutdownProcesses = []string{
+	"Launch",
+	"HealthCheck",
+	"ReplaceUnhealthy",
+	"AZRebalance",
+	"AlarmNotification",
+	"ScheduledActions",
+	"AddToLoadBalancer",
+}
+
+// ResumeManifest is the state of a cluster before it was shut down, as persisted in the state store.
+type ResumeManifest struct {
+	// Groups holds the autoscaling groups of the cluster as they were before the shutdown.
+	Groups []*ResumeGroup `json:"groups,omitempty"`
+	// EtcdSnapshots holds the IDs of the snapshots taken of the etcd volumes.
+	EtcdSnapshots []string `json:"etcdSnapshots,omitempty"`
+}
+
+// ResumeGroup is the state of an autoscaling group before the cluster was shut down.
+type ResumeGroup struct {
+	// InstanceGroup is the name of the instance group.
+	InstanceGroup string `json:"instanceGroup"`
+	// Role is the role of the instance group.
+	Role api.InstanceGroupRole `json:"role"`
+	// AutoscalingGroup is the name of the autoscaling group.
+	AutoscalingGroup string `json:"autoscalingGroup"`
+	MinSize          int64  `json:"minSize"`
+	MaxSize          int64  `json:"maxSize"`
+	DesiredCapacity  int64  `json:"desiredCapacity"`
+	// SuspendedProcesses are the processes that were already suspended, which are kept suspended on resume.
+	SuspendedProcesses []string `json:"suspendedProcesses,omitempty"`
+}
+
+// ReadResumeManifest reads the state of a shut down cluster, returning nil if the cluster is not shut down.
+func ReadResumeManifest(configBase vfs.Path) (*ResumeManifest, error) {
+	p := configBase.Join(resumeManifestFile)
+	data, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading resume manifest from %q: %v", p, err)
+	}
+
+	manifest := &ResumeManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("error parsing resume manifest from %q: %v", p, err)
+	}
+	return manifest, nil
+}
+
+func writeResumeManifest(configBase vfs.Path, manifest *ResumeManifest) error {
+	p := configBase.Join(resumeManifestFile)
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("error serializing resume manifest: %v", err)
+	}
+	if err := p.WriteFile(bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error writing resume manifest to %q: %v", p, err)
+	}
+	return nil
+}
+
+// shutdownOrder orders instance groups by the order in which they are shut down: the control plane comes last.
+func shutdownOrder(role api.InstanceGroupRole) int {
+	switch role {
+	case api.InstanceGroupRoleNode:
+		return 0
+	case api.InstanceGroupRoleBastion:
+		return 1
+	case api.InstanceGroupRoleAPIServer:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// ShutdownCluster shuts the cluster down. The nodes are drained and the etcd volumes are snapshotted, then the
+// prior state of the autoscaling groups is recorded in the state store, the groups are scaled to zero with the
+// control plane last, and the autoscaling processes that could start instances are suspended.
+func (c *RollingUpdateCluster) ShutdownCluster(groups map[string]*cloudinstances.CloudInstanceGroup, configBase vfs.Path) (*ResumeManifest, error) {
+	cloud, ok := c.Cloud.(awsup.AWSCloud)
+	if !ok {
+		return nil, fmt.Errorf("shutdown is only supported on AWS")
+	}
+
+	existing, err := ReadResumeManifest(configBase)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("cluster %q is already shut down", c.Cluster.ObjectMeta.Name)
+	}
+
+	var ordered []*cloudinstances.CloudInstanceGroup
+	for _, group := range groups {
+		ordered = append(ordered, group)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		oi, oj := shutdownOrder(ordered[i].InstanceGroup.Spec.Role), shutdownOrder(ordered[j].InstanceGroup.Spec.Role)
+		if oi != oj {
+			return oi < oj
+		}
+		return ordered[i].InstanceGroup.ObjectMeta.Name < ordered[j].InstanceGroup.ObjectMeta.Name
+	})
+
+	manifest := &ResumeManifest{}
+	for _, group := range ordered {
+		asg, ok := group.Raw.(*autoscaling.Group)
+		if !ok {
+			return nil, fmt.Errorf("instance group %q has no autoscaling group", group.InstanceGroup.ObjectMeta.Name)
+		}
+		resumeGroup := &ResumeGroup{
+			InstanceGroup:    group.InstanceGroup.ObjectMeta.Name,
+			Role:             group.InstanceGroup.Spec.Role,
+			AutoscalingGroup: aws.StringValue(asg.AutoScalingGroupName),
+			MinSize:          int64(group.MinSize),
+			MaxSize:          int64(group.MaxSize),
+			DesiredCapacity:  int64(group.TargetSize),
+		}
+		for _, process := range asg.SuspendedProcesses {
+			resumeGroup.SuspendedProcesses = append(resumeGroup.SuspendedProcesses, aws.StringValue(process.ProcessName))
+		}
+		manifest.Groups = append(manifest.Groups, resumeGroup)
+	}
+
+	if !c.CloudOnly {
+		for _, group := range ordered {
+			if group.InstanceGroup.IsMaster() || group.InstanceGroup.IsBastion() {
+				continue
+			}
+			for _, u := range append(group.Ready, group.NeedUpdate...) {
+				if u.Node == nil {
+					continue
+				}
+				klog.Infof("Draining node %q.", u.Node.Name)
+				if err := c.drainNode(u); err != nil {
+					if c.FailOnDrainError {
+						return nil, fmt.Errorf("failed to drain node %q: %v", u.Node.Name, err)
+					}
+					klog.Infof("Ignoring error draining node %q: %v", u.Node.Name, err)
+				}
+			}
+		}
+	}
+
+	snapshots, err := snapshotEtcdVolumes(cloud, c.Cluster.ObjectMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	manifest.EtcdSnapshots = snapshots
+
+	// The manifest marks the cluster as shut down, so it is only written once nothing can fail before the groups are scaled
+	if err := writeResumeManifest(configBase, manifest); err != nil {
+		return nil, err
+	}
+
+	for _, g := range manifest.Groups {
+		klog.Infof("Scaling instance group %q to zero.", g.InstanceGroup)
+		request := &autoscaling.UpdateAutoScalingGroupInput{
+			AutoScalingGroupName: aws.String(g.AutoscalingGroup),
+			MinSize:              aws.Int64(0),
+			MaxSize:              aws.Int64(0),
+			DesiredCapacity:      aws.Int64(0),
+		}
+		if _, err := cloud.Autoscaling().UpdateAutoScalingGroup(request); err != nil {
+			return nil, fmt.Errorf("error scaling autoscaling group %q to zero: %v", g.AutoscalingGroup, err)
+		}
+		if _, err := cloud.Autoscaling().SuspendProcesses(&autoscaling.ScalingProcessQuery{
+			AutoScalingGroupName: aws.String(g.AutoscalingGroup),
+			ScalingProcesses:     aws.StringSlice(shutdownProcesses),
+		}); err != nil {
+			return nil, fmt.Errorf("error suspending processes of autoscaling group %q: %v", g.AutoscalingGroup, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// snapshotEtcdVolumes snapshots the etcd volumes of the cluster, returning the IDs of the snapshots.
+func snapshotEtcdVolumes(cloud awsup.AWSCloud, clusterName string) ([]string, error) {
+	response, err := cloud.EC2().DescribeVolumes(&ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			awsup.NewEC2Filter("tag:"+awsup.TagClusterName, clusterName),
+			awsup.NewEC2Filter("tag:"+awsup.TagNameRolePrefix+"master", "1"),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing etcd volumes: %v", err)
+	}
+
+	var snapshots []string
+	for _, volume := range response.Volumes {
+		isEtcd := false
+		for _, tag := range volume.Tags {
+			if strings.HasPrefix(aws.StringValue(tag.Key), awsup.TagNameEtcdClusterPrefix) {
+				isEtcd = true
+			}
+		}
+		if !isEtcd {
+			continue
+		}
+
+		volumeID := aws.StringValue(volume.VolumeId)
+		klog.Infof("Snapshotting etcd volume %q.", volumeID)
+		snapshot, err := cloud.EC2().CreateSnapshot(&ec2.CreateSnapshotInput{
+			VolumeId:    volume.VolumeId,
+			Description: aws.String(fmt.Sprintf("Shutdown of cluster %s", clusterName)),
+			TagSpecifications: []*ec2.TagSpecification{
+				{
+					ResourceType: aws.String(ec2.ResourceTypeSnapshot),
+					Tags:         volume.Tags,
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error snapshotting etcd volume %q: %v", volumeID, err)
+		}
+		snapshots = append(snapshots, aws.StringValue(snapshot.SnapshotId))
+	}
+	return snapshots, nil
+}
+
+// ResumeCluster restores the autoscaling groups of a shut down cluster, with the control plane first,
+// and removes the resume manifest from the state store.
+func ResumeCluster(cloud awsup.AWSCloud, configBase vfs.Path, manifest *ResumeManifest) error {
+	for i := len(manifest.Groups) - 1; i >= 0; i-- {
+		g := manifest.Groups[i]
+		klog.Infof("Scaling instance group %q back to %d instances.", g.InstanceGroup, g.DesiredCapacity)
+		request := &autoscaling.UpdateAutoScalingGroupInput{
+			AutoScalingGroupName: aws.String(g.AutoscalingGroup),
+			MinSize:              aws.Int64(g.MinSize),
+			MaxSize:              aws.Int64(g.MaxSize),
+			DesiredCapacity:      aws.Int64(g.DesiredCapacity),
+		}
+		if _, err := cloud.Autoscaling().UpdateAutoScalingGroup(request); err != nil {
+			return fmt.Errorf("error scaling autoscaling group %q: %v", g.AutoscalingGroup, err)
+		}
+
+		resume := sets.NewString(shutdownProcesses...).Difference(sets.NewString(g.SuspendedProcesses...))
+		if resume.Len() > 0 {
+			if _, err := cloud.Autoscaling().ResumeProcesses(&autoscaling.ScalingProcessQuery{
+				AutoScalingGroupName: aws.String(g.AutoscalingGroup),
+				ScalingProcesses:     aws.StringSlice(resume.List()),
+			}); err != nil {
+				return fmt.Errorf("error resuming processes of autoscaling group %q: %v", g.AutoscalingGroup, err)
+			}
+		}
+	}
+
+	p := configBase.Join(resumeManifestFile)
+	if err := p.Remove(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing resume manifest from %q: %v", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclient "k8s.io/client-go/testing"
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func makeShutdownGroup(groups map[string]*cloudinstances.CloudInstanceGroup, c *RollingUpdateCluster, cloud awsup.AWSCloud, name string, role kopsapi.InstanceGroupRole, count int) {
+	makeGroup(groups, c.K8sClient, cloud, name, role, count, 0)
+	group := groups[name]
+	group.MinSize = 1
+	group.TargetSize = count
+	group.MaxSize = 5
+	group.Raw = &autoscaling.Group{AutoScalingGroupName: aws.String(name)}
+}
+
+func describeGroup(t *testing.T, cloud awsup.AWSCloud, name string) *autoscaling.Group {
+	response, err := cloud.Autoscaling().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(name)},
+	})
+	if err != nil || len(response.AutoScalingGroups) != 1 {
+		t.Fatalf("error describing autoscaling group %q: %v", name, err)
+	}
+	return response.AutoScalingGroups[0]
+}
+
+func suspendedProcesses(group *autoscaling.Group) []string {
+	var processes []string
+	for _, p := range group.SuspendedProcesses {
+		processes = append(processes, aws.StringValue(p.ProcessName))
+	}
+	return processes
+}
+
+func TestShutdownAndResumeCluster(t *testing.T) {
+	c, cloud := getTestSetup()
+	mockEC2 := &mockec2.MockEC2{}
+	cloud.MockEC2 = cloud.MockAutoscaling.(*mockautoscaling.MockAutoscaling).GetEC2Shim(mockEC2)
+	for _, tags := range []map[string]string{
+		{awsup.TagClusterName: "test.k8s.local", awsup.TagNameRolePrefix + "master": "1", awsup.TagNameEtcdClusterPrefix + "main": "a/a"},
+		{awsup.TagClusterName: "other.k8s.local", awsup.TagNameRolePrefix + "master": "1", awsup.TagNameEtcdClusterPrefix + "main": "a/a"},
+	} {
+		var ec2Tags []*ec2.Tag
+		for k, v := range tags {
+			ec2Tags = append(ec2Tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		mockEC2.CreateVolume(&ec2.CreateVolumeInput{
+			Size:              aws.Int64(20),
+			TagSpecifications: []*ec2.TagSpecification{{ResourceType: aws.String(ec2.ResourceTypeVolume), Tags: ec2Tags}},
+		})
+	}
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "clusters/test.k8s.local")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeShutdownGroup(groups, c, cloud, "master-1", kopsapi.InstanceGroupRoleMaster, 1)
+	makeShutdownGroup(groups, c, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3)
+	cloud.Autoscaling().SuspendProcesses(&autoscaling.ScalingProcessQuery{
+		AutoScalingGroupName: aws.String("node-1"),
+		ScalingProcesses:     aws.StringSlice([]string{"AZRebalance"}),
+	})
+	groups["node-1"].Raw.(*autoscaling.Group).SuspendedProcesses = []*autoscaling.SuspendedProcess{{ProcessName: aws.String("AZRebalance")}}
+
+	manifest, err := c.ShutdownCluster(groups, configBase)
+	if !assert.NoError(t, err, "shutdown") {
+		return
+	}
+	assert.Equal(t, &ResumeManifest{
+		Groups: []*ResumeGroup{
+			{
+				InstanceGroup:      "node-1",
+				Role:               kopsapi.InstanceGroupRoleNode,
+				AutoscalingGroup:   "node-1",
+				MinSize:            1,
+				MaxSize:            5,
+				DesiredCapacity:    3,
+				SuspendedProcesses: []string{"AZRebalance"},
+			},
+			{
+				InstanceGroup:    "master-1",
+				Role:             kopsapi.InstanceGroupRoleMaster,
+				AutoscalingGroup: "master-1",
+				MinSize:          1,
+				MaxSize:          5,
+				DesiredCapacity:  1,
+			},
+		},
+		EtcdSnapshots: []string{"snap-1"},
+	}, manifest, "resume manifest")
+
+	assert.Equal(t, "vol-1", aws.StringValue(mockEC2.Snapshots["snap-1"].VolumeId), "snapshotted volume")
+
+	for _, name := range []string{"master-1", "node-1"} {
+		group := describeGroup(t, cloud, name)
+		assert.Equal(t, int64(0), aws.Int64Value(group.DesiredCapacity), "%s desired capacity", name)
+		assert.Equal(t, int64(0), aws.Int64Value(group.MaxSize), "%s max size", name)
+		assert.ElementsMatch(t, shutdownProcesses, suspendedProcesses(group), "%s suspended processes", name)
+	}
+
+	stored, err := ReadResumeManifest(configBase)
+	if !assert.NoError(t, err, "reading resume manifest") {
+		return
+	}
+	assert.Equal(t, manifest, stored, "stored resume manifest")
+
+	_, err = c.ShutdownCluster(groups, configBase)
+	assert.EqualError(t, err, "cluster \"test.k8s.local\" is already shut down")
+
+	err = ResumeCluster(cloud, configBase, stored)
+	if !assert.NoError(t, err, "resume") {
+		return
+	}
+
+	master := describeGroup(t, cloud, "master-1")
+	assert.Equal(t, int64(1), aws.Int64Value(master.DesiredCapacity), "master desired capacity")
+	assert.Empty(t, suspendedProcesses(master), "master suspended processes")
+	node := describeGroup(t, cloud, "node-1")
+	assert.Equal(t, int64(3), aws.Int64Value(node.DesiredCapacity), "node desired capacity")
+	assert.Equal(t, int64(5), aws.Int64Value(node.MaxSize), "node max size")
+	assert.Equal(t, []string{"AZRebalance"}, suspendedProcesses(node), "node suspended processes")
+
+	_, err = configBase.Join(resumeManifestFile).ReadFile()
+	assert.True(t, os.IsNotExist(err), "resume manifest removed after resume")
+}
+
+func TestShutdownClusterDrainFailure(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.FailOnDrainError = true
+	c.K8sClient.(*fake.Clientset).PrependReactor("patch", "nodes", func(action testingclient.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("cannot cordon node")
+	})
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "clusters/test.k8s.local")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeShutdownGroup(groups, c, cloud, "master-1", kopsapi.InstanceGroupRoleMaster, 1)
+	makeShutdownGroup(groups, c, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 1)
+
+	_, err := c.ShutdownCluster(groups, configBase)
+	assert.Error(t, err, "shutdown")
+
+	_, err = configBase.Join(resumeManifestFile).ReadFile()
+	assert.True(t, os.IsNotExist(err), "resume manifest not written when the drain fails")
+	assert.Equal(t, int64(1), aws.Int64Value(describeGroup(t, cloud, "node-1").DesiredCapacity), "node desired capacity")
+}
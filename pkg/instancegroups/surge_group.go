@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// rollingUpdateWithSurgeGroup replaces the instances in update without reducing the capacity of the group.
+// A temporary copy of the group is created with the new configuration and, once its nodes are ready,
+// the old instances are drained and replaced. Finally, the nodes of the copy are drained and the copy is deleted.
+func (c *RollingUpdateCluster) rollingUpdateWithSurgeGroup(cloud fi.SurgeGroupCloud, group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstance, sleepAfterTerminate time.Duration) error {
+	size := len(update)
+
+	if err := c.checkMaintenanceWindow(); err != nil {
+		return err
+	}
+
+	klog.Infof("Creating surge group with %d instances for %q.", size, group.HumanName)
+	name, err := cloud.CreateSurgeGroup(group, size)
+	if err != nil {
+		return err
+	}
+
+	surgeNodes, err := c.waitForSurgeGroup(cloud, name, size)
+	if err != nil {
+		// We leave the surge group in place, so a later rolling update can resume without losing capacity.
+		return err
+	}
+
+	terminateChan := make(chan error, size)
+	for _, u := range update {
+		go func(m *cloudinstances.CloudInstance) {
+			terminateChan <- c.drainTerminateAndWait(m, sleepAfterTerminate)
+		}(u)
+	}
+	for runningDrains := size; runningDrains > 0; {
+		err = <-terminateChan
+		runningDrains--
+		if err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+	}
+
+	if err := c.maybeValidate(" after replacing instances", c.ValidateCount, group); err != nil {
+		return err
+	}
+
+	for _, node := range surgeNodes {
+		u := &cloudinstances.CloudInstance{
+			Node:               node,
+			CloudInstanceGroup: group,
+		}
+		klog.Infof("Draining surge node %q.", node.Name)
+		if err := c.drainNode(u); err != nil {
+			if c.FailOnDrainError {
+				return fmt.Errorf("failed to drain node %q: %v", node.Name, err)
+			}
+			klog.Infof("Ignoring error draining node %q: %v", node.Name, err)
+		}
+	}
+
+	klog.Infof("Deleting surge group %q.", name)
+	if err := cloud.DeleteSurgeGroup(name); err != nil {
+		return err
+	}
+	for _, node := range surgeNodes {
+		if err := c.deleteNode(node); err != nil {
+			return fmt.Errorf("error deleting node %q: %v", node.Name, err)
+		}
+	}
+
+	return c.maybeValidate(" after deleting surge group", c.ValidateCount, group)
+}
+
+// waitForSurgeGroup waits until the surge group has the expected number of instances with ready nodes
+func (c *RollingUpdateCluster) waitForSurgeGroup(cloud fi.SurgeGroupCloud, name string, size int) ([]*corev1.Node, error) {
+	ctx, cancel := context.WithTimeout(c.Ctx, c.ValidationTimeout)
+	defer cancel()
+
+	for {
+		ids, err := cloud.GetSurgeGroupInstances(name)
+		if err != nil {
+			klog.Warningf("error listing instances of surge group %q: %v", name, err)
+		} else if nodes, err := c.K8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err != nil {
+			klog.Warningf("error listing nodes: %v", err)
+		} else {
+			nodeMap := cloudinstances.GetNodeMap(nodes.Items, c.Cluster)
+			var ready []*corev1.Node
+			for _, id := range ids {
+				if node := nodeMap[id]; node != nil && validation.IsNodeReady(node) {
+					ready = append(ready, node)
+				}
+			}
+			if len(ready) >= size {
+				klog.Infof("Surge group %q is ready.", name)
+				return ready, nil
+			}
+			klog.Infof("%d of %d nodes of surge group %q are ready, will retry in %s.", len(ready), size, name, c.ValidateTickDuration)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("surge group %q did not become ready within %s", name, c.ValidationTimeout)
+		case <-time.After(c.ValidateTickDuration):
+		}
+	}
+}
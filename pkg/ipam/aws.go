@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// awsAllocator allocates CIDRs from AWS VPC IPAM pools.
+// The vendored AWS SDK predates VPC IPAM, so the EC2 operations are defined here.
+type awsAllocator struct {
+	ec2Client *ec2.EC2
+}
+
+var _ Allocator = &awsAllocator{}
+
+func newAWSAllocator(region string) (*awsAllocator, error) {
+	if region == "" {
+		return nil, fmt.Errorf("region is required for AWS VPC IPAM")
+	}
+	s, err := session.NewSession(aws.NewConfig().WithCredentialsChainVerboseErrors(true))
+	if err != nil {
+		return nil, fmt.Errorf("error starting new AWS session: %v", err)
+	}
+	return &awsAllocator{
+		ec2Client: ec2.New(s, aws.NewConfig().WithRegion(region)),
+	}, nil
+}
+
+type allocateIpamPoolCidrInput struct {
+	_ struct{} `type:"structure"`
+
+	IpamPoolId    *string `type:"string" required:"true"`
+	NetmaskLength *int64  `type:"integer"`
+	Description   *string `type:"string"`
+}
+
+type allocateIpamPoolCidrOutput struct {
+	_ struct{} `type:"structure"`
+
+	IpamPoolAllocation *ipamPoolAllocation `locationName:"ipamPoolAllocation" type:"structure"`
+}
+
+type ipamPoolAllocation struct {
+	_ struct{} `type:"structure"`
+
+	Cidr                 *string `locationName:"cidr" type:"string"`
+	IpamPoolAllocationId *string `locationName:"ipamPoolAllocationId" type:"string"`
+}
+
+type releaseIpamPoolAllocationInput struct {
+	_ struct{} `type:"structure"`
+
+	IpamPoolId           *string `type:"string" required:"true"`
+	Cidr                 *string `type:"string" required:"true"`
+	IpamPoolAllocationId *string `type:"string" required:"true"`
+}
+
+type releaseIpamPoolAllocationOutput struct {
+	_ struct{} `type:"structure"`
+
+	Success *bool `locationName:"success" type:"boolean"`
+}
+
+func (a *awsAllocator) Allocate(ctx context.Context, pool string, prefixLength int, description string) (*Allocation, error) {
+	input := &allocateIpamPoolCidrInput{
+		IpamPoolId:    aws.String(pool),
+		NetmaskLength: aws.Int64(int64(prefixLength)),
+		Description:   aws.String(description),
+	}
+	output := &allocateIpamPoolCidrOutput{}
+	req := a.ec2Client.NewRequest(&request.Operation{Name: "AllocateIpamPoolCidr", HTTPMethod: "POST", HTTPPath: "/"}, input, output)
+	req.SetContext(ctx)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+
+	if output.IpamPoolAllocation == nil || aws.StringValue(output.IpamPoolAllocation.Cidr) == "" {
+		return nil, fmt.Errorf("AllocateIpamPoolCidr did not return a CIDR")
+	}
+	return &Allocation{
+		CIDR: aws.StringValue(output.IpamPoolAllocation.Cidr),
+		ID:   aws.StringValue(output.IpamPoolAllocation.IpamPoolAllocationId),
+	}, nil
+}
+
+func (a *awsAllocator) Release(ctx context.Context, pool string, allocation *Allocation) error {
+	input := &releaseIpamPoolAllocationInput{
+		IpamPoolId:           aws.String(pool),
+		Cidr:                 aws.String(allocation.CIDR),
+		IpamPoolAllocationId: aws.String(allocation.ID),
+	}
+	output := &releaseIpamPoolAllocationOutput{}
+	req := a.ec2Client.NewRequest(&request.Operation{Name: "ReleaseIpamPoolAllocation", HTTPMethod: "POST", HTTPPath: "/"}, input, output)
+	req.SetContext(ctx)
+	if err := req.Send(); err != nil {
+		return err
+	}
+
+	if !aws.BoolValue(output.Success) {
+		return fmt.Errorf("ReleaseIpamPoolAllocation was not successful")
+	}
+	return nil
+}
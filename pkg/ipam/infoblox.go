@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// infobloxAllocator allocates networks from Infoblox network containers, through the WAPI.
+// The endpoint is the base URL of the WAPI, for example https://infoblox.example.com/wapi/v2.10.
+type infobloxAllocator struct {
+	endpoint   string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+var _ Allocator = &infobloxAllocator{}
+
+func newInfobloxAllocator(endpoint string) (*infobloxAllocator, error) {
+	username := os.Getenv("INFOBLOX_USERNAME")
+	password := os.Getenv("INFOBLOX_PASSWORD")
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("INFOBLOX_USERNAME and INFOBLOX_PASSWORD must be set to use Infoblox")
+	}
+	return &infobloxAllocator{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		username:   username,
+		password:   password,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+type infobloxNetwork struct {
+	Ref     string `json:"_ref,omitempty"`
+	Network string `json:"network"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Allocate creates the next available network of the network container. The pool is the CIDR of the
+// network container, optionally prefixed by the network view, as in "internal/10.0.0.0/8".
+func (a *infobloxAllocator) Allocate(ctx context.Context, pool string, prefixLength int, description string) (*Allocation, error) {
+	view := "default"
+	container := pool
+	if tokens := strings.Split(pool, "/"); len(tokens) == 3 {
+		view = tokens[0]
+		container = tokens[1] + "/" + tokens[2]
+	}
+
+	request := &infobloxNetwork{
+		Network: fmt.Sprintf("func:nextavailablenetwork:%s,%s,%d", container, view, prefixLength),
+		Comment: description,
+	}
+	response := &infobloxNetwork{}
+	if err := a.do(ctx, http.MethodPost, "/network?_return_fields=network", request, response); err != nil {
+		return nil, err
+	}
+	if response.Network == "" || response.Ref == "" {
+		return nil, fmt.Errorf("infoblox did not return the allocated network")
+	}
+	return &Allocation{
+		CIDR: response.Network,
+		ID:   response.Ref,
+	}, nil
+}
+
+// Release deletes the network, whose reference is the ID of the allocation.
+func (a *infobloxAllocator) Release(ctx context.Context, pool string, allocation *Allocation) error {
+	return a.do(ctx, http.MethodDelete, "/"+allocation.ID, nil, nil)
+}
+
+func (a *infobloxAllocator) do(ctx context.Context, method string, path string, request interface{}, response interface{}) error {
+	var body io.Reader
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("error building request: %v", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.endpoint+path, body)
+	if err != nil {
+		return fmt.Errorf("error building request: %v", err)
+	}
+	req.SetBasicAuth(a.username, a.password)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling infoblox: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading infoblox response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("infoblox %s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if response != nil {
+		if err := json.Unmarshal(data, response); err != nil {
+			return fmt.Errorf("error parsing infoblox response: %v", err)
+		}
+	}
+	return nil
+}
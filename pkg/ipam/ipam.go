@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ipam allocates the network CIDRs of clusters from external IP address management systems.
+package ipam
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// Allocation is a CIDR allocated from an IPAM.
+type Allocation struct {
+	// CIDR is the allocated CIDR.
+	CIDR string
+	// ID identifies the allocation in the IPAM, to release it.
+	ID string
+}
+
+// Allocator allocates and releases CIDRs in an IPAM.
+type Allocator interface {
+	// Allocate allocates a free CIDR with the prefix length from the pool.
+	Allocate(ctx context.Context, pool string, prefixLength int, description string) (*Allocation, error)
+	// Release releases a CIDR allocated from the pool.
+	Release(ctx context.Context, pool string, allocation *Allocation) error
+}
+
+// NewAllocator builds the Allocator for the provider. The region is only used by AWS VPC IPAM,
+// and the endpoint by Infoblox and Netbox, whose credentials are read from the environment.
+func NewAllocator(provider string, endpoint string, region string) (Allocator, error) {
+	switch provider {
+	case kops.NetworkCIDRAllocationProviderAWS:
+		return newAWSAllocator(region)
+	case kops.NetworkCIDRAllocationProviderInfoblox:
+		return newInfobloxAllocator(endpoint)
+	case kops.NetworkCIDRAllocationProviderNetbox:
+		return newNetboxAllocator(endpoint)
+	default:
+		return nil, fmt.Errorf("unknown IPAM provider %q", provider)
+	}
+}
+
+// AllocateNetworkCIDR allocates the network CIDR of the cluster, and records the allocation in its spec.
+func AllocateNetworkCIDR(ctx context.Context, allocator Allocator, cluster *kops.Cluster, provider string, endpoint string, pool string, prefixLength int) error {
+	allocation, err := allocator.Allocate(ctx, pool, prefixLength, "kops cluster "+cluster.ObjectMeta.Name)
+	if err != nil {
+		return fmt.Errorf("error allocating network CIDR from %s pool %q: %v", provider, pool, err)
+	}
+
+	cluster.Spec.NetworkCIDR = allocation.CIDR
+	cluster.Spec.NetworkCIDRAllocation = &kops.NetworkCIDRAllocationSpec{
+		Provider:     provider,
+		Endpoint:     endpoint,
+		Pool:         pool,
+		AllocationID: allocation.ID,
+	}
+	return nil
+}
+
+// ReleaseNetworkCIDR releases the network CIDR of the cluster, if it was allocated from an IPAM.
+func ReleaseNetworkCIDR(ctx context.Context, cluster *kops.Cluster, region string) error {
+	spec := cluster.Spec.NetworkCIDRAllocation
+	if spec == nil {
+		return nil
+	}
+
+	allocator, err := NewAllocator(spec.Provider, spec.Endpoint, region)
+	if err != nil {
+		return err
+	}
+	allocation := &Allocation{
+		CIDR: cluster.Spec.NetworkCIDR,
+		ID:   spec.AllocationID,
+	}
+	if err := allocator.Release(ctx, spec.Pool, allocation); err != nil {
+		return fmt.Errorf("error releasing network CIDR %q from %s pool %q: %v", allocation.CIDR, spec.Provider, spec.Pool, err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestAWSAllocator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		params, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("error parsing request: %v", err)
+		}
+		switch params.Get("Action") {
+		case "AllocateIpamPoolCidr":
+			if params.Get("IpamPoolId") != "ipam-pool-0123" || params.Get("NetmaskLength") != "16" {
+				t.Errorf("unexpected parameters: %v", params)
+			}
+			w.Write([]byte(`<AllocateIpamPoolCidrResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <requestId>1</requestId>
+  <ipamPoolAllocation>
+    <cidr>10.12.0.0/16</cidr>
+    <ipamPoolAllocationId>ipam-pool-alloc-4567</ipamPoolAllocationId>
+  </ipamPoolAllocation>
+</AllocateIpamPoolCidrResponse>`))
+		case "ReleaseIpamPoolAllocation":
+			if params.Get("IpamPoolAllocationId") != "ipam-pool-alloc-4567" || params.Get("Cidr") != "10.12.0.0/16" {
+				t.Errorf("unexpected parameters: %v", params)
+			}
+			w.Write([]byte(`<ReleaseIpamPoolAllocationResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <requestId>2</requestId>
+  <success>true</success>
+</ReleaseIpamPoolAllocationResponse>`))
+		default:
+			t.Errorf("unexpected action %q", params.Get("Action"))
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	config := aws.NewConfig().
+		WithRegion("us-east-1").
+		WithEndpoint(server.URL).
+		WithCredentials(credentials.NewStaticCredentials("id", "secret", ""))
+	allocator := &awsAllocator{ec2Client: ec2.New(session.Must(session.NewSession(config)))}
+
+	testAllocator(t, allocator, "ipam-pool-0123", &Allocation{CIDR: "10.12.0.0/16", ID: "ipam-pool-alloc-4567"})
+}
+
+func TestInfobloxAllocator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/wapi/v2.10/network":
+			request := &infobloxNetwork{}
+			if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+				t.Fatalf("error parsing request: %v", err)
+			}
+			if request.Network != "func:nextavailablenetwork:10.0.0.0/8,internal,16" {
+				t.Errorf("unexpected network %q", request.Network)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"_ref": "network/ZG5zLm5ldHdvcmskMTAuMTIuMC4wLzE2LzA:10.12.0.0/16/internal", "network": "10.12.0.0/16"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/wapi/v2.10/network/ZG5zLm5ldHdvcmskMTAuMTIuMC4wLzE2LzA:10.12.0.0/16/internal":
+			w.Write([]byte(`"network/ZG5zLm5ldHdvcmskMTAuMTIuMC4wLzE2LzA:10.12.0.0/16/internal"`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	allocator := &infobloxAllocator{
+		endpoint:   server.URL + "/wapi/v2.10",
+		username:   "admin",
+		password:   "secret",
+		httpClient: server.Client(),
+	}
+
+	testAllocator(t, allocator, "internal/10.0.0.0/8", &Allocation{CIDR: "10.12.0.0/16", ID: "network/ZG5zLm5ldHdvcmskMTAuMTIuMC4wLzE2LzA:10.12.0.0/16/internal"})
+}
+
+func TestNetboxAllocator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token 0123456789" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/ipam/prefixes/12/available-prefixes/":
+			request := &netboxAvailablePrefixRequest{}
+			if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+				t.Fatalf("error parsing request: %v", err)
+			}
+			if request.PrefixLength != 16 {
+				t.Errorf("unexpected prefix length %d", request.PrefixLength)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 345, "prefix": "10.12.0.0/16", "status": {"value": "active"}}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/ipam/prefixes/345/":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	allocator := &netboxAllocator{
+		endpoint:   server.URL,
+		token:      "0123456789",
+		httpClient: server.Client(),
+	}
+
+	testAllocator(t, allocator, "12", &Allocation{CIDR: "10.12.0.0/16", ID: "345"})
+}
+
+func testAllocator(t *testing.T, allocator Allocator, pool string, expected *Allocation) {
+	ctx := context.Background()
+
+	allocation, err := allocator.Allocate(ctx, pool, 16, "kops cluster minimal.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error allocating: %v", err)
+	}
+	if *allocation != *expected {
+		t.Errorf("unexpected allocation %+v, expected %+v", allocation, expected)
+	}
+
+	if err := allocator.Release(ctx, pool, allocation); err != nil {
+		t.Errorf("unexpected error releasing: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// netboxAllocator allocates prefixes from the available prefixes of Netbox prefixes, through the REST API.
+// The endpoint is the base URL of Netbox, for example https://netbox.example.com.
+type netboxAllocator struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+}
+
+var _ Allocator = &netboxAllocator{}
+
+func newNetboxAllocator(endpoint string) (*netboxAllocator, error) {
+	token := os.Getenv("NETBOX_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("NETBOX_TOKEN must be set to use Netbox")
+	}
+	return &netboxAllocator{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		token:      token,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+type netboxAvailablePrefixRequest struct {
+	PrefixLength int    `json:"prefix_length"`
+	Description  string `json:"description,omitempty"`
+}
+
+type netboxPrefix struct {
+	ID     int    `json:"id"`
+	Prefix string `json:"prefix"`
+}
+
+// Allocate creates a prefix in the available prefixes of the parent prefix, whose ID is the pool.
+func (a *netboxAllocator) Allocate(ctx context.Context, pool string, prefixLength int, description string) (*Allocation, error) {
+	if _, err := strconv.Atoi(pool); err != nil {
+		return nil, fmt.Errorf("netbox pool %q is not the ID of a prefix", pool)
+	}
+
+	request := &netboxAvailablePrefixRequest{
+		PrefixLength: prefixLength,
+		Description:  description,
+	}
+	response := &netboxPrefix{}
+	if err := a.do(ctx, http.MethodPost, "/api/ipam/prefixes/"+pool+"/available-prefixes/", request, response); err != nil {
+		return nil, err
+	}
+	if response.Prefix == "" || response.ID == 0 {
+		return nil, fmt.Errorf("netbox did not return the allocated prefix")
+	}
+	return &Allocation{
+		CIDR: response.Prefix,
+		ID:   strconv.Itoa(response.ID),
+	}, nil
+}
+
+// Release deletes the prefix, whose ID is the ID of the allocation.
+func (a *netboxAllocator) Release(ctx context.Context, pool string, allocation *Allocation) error {
+	if _, err := strconv.Atoi(allocation.ID); err != nil {
+		return fmt.Errorf("netbox allocation %q is not the ID of a prefix", allocation.ID)
+	}
+	return a.do(ctx, http.MethodDelete, "/api/ipam/prefixes/"+allocation.ID+"/", nil, nil)
+}
+
+func (a *netboxAllocator) do(ctx context.Context, method string, path string, request interface{}, response interface{}) error {
+	var body io.Reader
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("error building request: %v", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.endpoint+path, body)
+	if err != nil {
+		return fmt.Errorf("error building request: %v", err)
+	}
+	req.Header.Set("Authorization", "Token "+a.token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling netbox: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading netbox response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("netbox %s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if response != nil {
+		if err := json.Unmarshal(data, response); err != nil {
+			return fmt.Errorf("error parsing netbox response: %v", err)
+		}
+	}
+	return nil
+}
@@ -100,7 +100,7 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 	b := NewKubeconfigBuilder()
 
 	// Use the secondary load balancer port if a certificate is on the primary listener
-	if admin != 0 && cluster.Spec.API != nil && cluster.Spec.API.LoadBalancer != nil && cluster.Spec.API.LoadBalancer.SSLCertificate != "" && cluster.Spec.API.LoadBalancer.Class == kops.LoadBalancerClassNetwork {
+	if (admin != 0 || useKopsAuthenticationPlugin) && cluster.Spec.API != nil && cluster.Spec.API.LoadBalancer != nil && cluster.Spec.API.LoadBalancer.SSLCertificate != "" && cluster.Spec.API.LoadBalancer.Class == kops.LoadBalancerClassNetwork {
 		server = server + ":8443"
 	}
 
@@ -124,7 +124,20 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 		}
 	}
 
-	if admin != 0 {
+	if useKopsAuthenticationPlugin {
+		// The plugin mints short-lived client certificates on demand, so we
+		// never write long-lived admin credentials to the kubeconfig.
+		b.AuthenticationExec = []string{
+			"kops",
+			"helpers",
+			"kubectl-auth",
+			"--cluster=" + clusterName,
+			"--state=" + kopsStateStore,
+		}
+		if admin != 0 {
+			b.AuthenticationExec = append(b.AuthenticationExec, "--lifetime="+admin.String())
+		}
+	} else if admin != 0 {
 		cn := "kubecfg"
 		user, err := user.Current()
 		if err != nil || user == nil {
@@ -156,16 +169,6 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 		}
 	}
 
-	if useKopsAuthenticationPlugin {
-		b.AuthenticationExec = []string{
-			"kops",
-			"helpers",
-			"kubectl-auth",
-			"--cluster=" + clusterName,
-			"--state=" + kopsStateStore,
-		}
-	}
-
 	b.Server = server
 
 	k8sVersion, err := util.ParseKubernetesVersion(cluster.Spec.KubernetesVersion)
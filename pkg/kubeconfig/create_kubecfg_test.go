@@ -308,6 +308,52 @@ func TestBuildKubecfg(t *testing.T) {
 			},
 			wantClientCert: false,
 		},
+		{
+			name: "Public DNS with kops auth plugin and admin lifetime",
+			args: args{
+				cluster:                     publicCluster,
+				status:                      fakeStatusCloud{},
+				admin:                       DefaultKubecfgAdminLifetime,
+				useKopsAuthenticationPlugin: true,
+			},
+			want: &KubeconfigBuilder{
+				Context: "testcluster",
+				Server:  "https://testcluster.test.com",
+				CACerts: []byte(nextCertificate + certData),
+				User:    "testcluster",
+				AuthenticationExec: []string{
+					"kops",
+					"helpers",
+					"kubectl-auth",
+					"--cluster=testcluster",
+					"--state=memfs://example-state-store",
+					"--lifetime=18h0m0s",
+				},
+			},
+			wantClientCert: false,
+		},
+		{
+			name: "Public DNS with kops auth plugin and secondary NLB port",
+			args: args{
+				cluster:                     certNLBCluster,
+				status:                      fakeStatusCloud{},
+				useKopsAuthenticationPlugin: true,
+			},
+			want: &KubeconfigBuilder{
+				Context: "testcluster",
+				Server:  "https://testcluster.test.com:8443",
+				CACerts: []byte(nextCertificate + certData),
+				User:    "testcluster",
+				AuthenticationExec: []string{
+					"kops",
+					"helpers",
+					"kubectl-auth",
+					"--cluster=testcluster",
+					"--state=memfs://example-state-store",
+				},
+			},
+			wantClientCert: false,
+		},
 		{
 			name: "Test Kube Config Data For internal DNS name with admin",
 			args: args{
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenancewindow evaluates the maintenance window of a cluster,
+// the recurring period during which disruptive operations are allowed.
+package maintenancewindow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// parser accepts the five standard fields of a cron schedule: minute, hour, day of month, month and day of week.
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// ParseSchedule parses a cron schedule such as "0 2 * * SAT". Times are evaluated in UTC,
+// unless the schedule is prefixed with a time zone, such as "CRON_TZ=Europe/Paris 0 2 * * SAT".
+func ParseSchedule(s string) (cron.Schedule, error) {
+	spec := strings.TrimSpace(s)
+	if !strings.HasPrefix(spec, "CRON_TZ=") && !strings.HasPrefix(spec, "TZ=") {
+		spec = "CRON_TZ=UTC " + spec
+	}
+	schedule, err := parser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %v", s, err)
+	}
+	return schedule, nil
+}
+
+// Window is a recurring maintenance window, opening at each time selected by the schedule
+// and staying open for the duration.
+type Window struct {
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// New parses the schedule and returns the window it describes.
+func New(schedule string, duration time.Duration) (*Window, error) {
+	s, err := ParseSchedule(schedule)
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("maintenance window duration must be greater than zero")
+	}
+	return &Window{schedule: s, duration: duration}, nil
+}
+
+// Contains returns true if the window is open at t.
+func (w *Window) Contains(t time.Time) bool {
+	start := w.schedule.Next(t.Add(-w.duration))
+	return !start.IsZero() && !start.After(t)
+}
+
+// Next returns the time the window next opens after t, or the zero time if the schedule
+// never opens it.
+func (w *Window) Next(t time.Time) time.Time {
+	return w.schedule.Next(t)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenancewindow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	grid := []struct {
+		schedule string
+		valid    bool
+	}{
+		{schedule: "0 2 * * *", valid: true},
+		{schedule: "*/15 1-5 1,15 JAN-jun sat,Sun", valid: true},
+		{schedule: "30 22 * * 0", valid: true},
+		{schedule: "5/10 * * * *", valid: true},
+		{schedule: "CRON_TZ=Europe/Paris 0 2 * * *", valid: true},
+		{schedule: "@daily"},
+		{schedule: "0 2 * *"},
+		{schedule: "60 2 * * *"},
+		{schedule: "0 24 * * *"},
+		{schedule: "0 2 0 * *"},
+		{schedule: "0 2 * 13 *"},
+		{schedule: "0 2 * * 8"},
+		{schedule: "0 5-2 * * *"},
+		{schedule: "*/0 * * * *"},
+		{schedule: "0 2 * * someday"},
+		{schedule: "CRON_TZ=Nowhere/Special 0 2 * * *"},
+	}
+	for _, g := range grid {
+		_, err := ParseSchedule(g.schedule)
+		if g.valid && err != nil {
+			t.Errorf("unexpected error parsing %q: %v", g.schedule, err)
+		}
+		if !g.valid && err == nil {
+			t.Errorf("expected error parsing %q", g.schedule)
+		}
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	grid := []struct {
+		schedule string
+		time     string
+		expected bool
+	}{
+		{schedule: "0 2 * * *", time: "2021-06-05T02:00:00Z", expected: true},
+		{schedule: "0 2 * * *", time: "2021-06-05T02:00:59Z", expected: true},
+		{schedule: "0 2 * * *", time: "2021-06-05T02:01:00Z", expected: false},
+		{schedule: "0 2 * * *", time: "2021-06-05T04:00:00+02:00", expected: true},
+		{schedule: "CRON_TZ=Europe/Paris 0 2 * * *", time: "2021-06-05T00:00:00Z", expected: true},
+		{schedule: "*/15 * * * *", time: "2021-06-05T10:45:00Z", expected: true},
+		{schedule: "*/15 * * * *", time: "2021-06-05T10:50:00Z", expected: false},
+		{schedule: "0 0 * * SAT", time: "2021-06-05T00:00:00Z", expected: true},
+		{schedule: "0 0 * * 0", time: "2021-06-06T00:00:00Z", expected: true},
+		{schedule: "0 0 * * 0", time: "2021-06-05T00:00:00Z", expected: false},
+		{schedule: "0 0 1 * MON", time: "2021-06-01T00:00:00Z", expected: true},
+		{schedule: "0 0 1 * MON", time: "2021-06-07T00:00:00Z", expected: true},
+		{schedule: "0 0 1 * MON", time: "2021-06-08T00:00:00Z", expected: false},
+		{schedule: "0 0 * DEC *", time: "2021-06-01T00:00:00Z", expected: false},
+	}
+	for _, g := range grid {
+		// A window of a minute is open exactly at the times matched by its schedule.
+		w, err := New(g.schedule, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", g.schedule, err)
+		}
+		at, err := time.Parse(time.RFC3339, g.time)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", g.time, err)
+		}
+		if actual := w.Contains(at); actual != g.expected {
+			t.Errorf("%q matching %s: expected %v, got %v", g.schedule, g.time, g.expected, actual)
+		}
+	}
+}
+
+func TestWindow(t *testing.T) {
+	// Saturdays from 22:00 to 02:00 UTC.
+	w, err := New("0 22 * * SAT", 4*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	grid := []struct {
+		time     string
+		contains bool
+		next     string
+	}{
+		{time: "2021-06-05T21:59:59Z", contains: false, next: "2021-06-05T22:00:00Z"},
+		{time: "2021-06-05T22:00:00Z", contains: true, next: "2021-06-12T22:00:00Z"},
+		{time: "2021-06-06T01:59:00Z", contains: true, next: "2021-06-12T22:00:00Z"},
+		{time: "2021-06-06T02:00:00Z", contains: false, next: "2021-06-12T22:00:00Z"},
+		{time: "2021-06-09T12:00:00Z", contains: false, next: "2021-06-12T22:00:00Z"},
+	}
+	for _, g := range grid {
+		at, err := time.Parse(time.RFC3339, g.time)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", g.time, err)
+		}
+		if actual := w.Contains(at); actual != g.contains {
+			t.Errorf("Contains(%s): expected %v, got %v", g.time, g.contains, actual)
+		}
+		if actual := w.Next(at).Format(time.RFC3339); actual != g.next {
+			t.Errorf("Next(%s): expected %s, got %s", g.time, g.next, actual)
+		}
+	}
+}
+
+func TestWindowNeverOpens(t *testing.T) {
+	w, err := New("0 0 30 FEB *", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next := w.Next(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)); !next.IsZero() {
+		t.Errorf("expected zero time, got %s", next)
+	}
+}
+
+func TestNewRequiresDuration(t *testing.T) {
+	if _, err := New("0 2 * * *", 0); err == nil {
+		t.Errorf("expected error for a zero duration")
+	}
+}
@@ -19,6 +19,7 @@ package awsmodel
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -27,6 +28,7 @@ import (
 	"k8s.io/kops/pkg/dns"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/utils"
 )
 
@@ -161,7 +163,7 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 			klog.V(1).Infof("WARNING: You are overwriting the Load Balancers, Security Group. When this is done you are responsible for ensure the correct rules!")
 		}
 
-		tags := b.CloudTags(loadBalancerName, false)
+		tags := b.CloudTagsForResource(kops.CloudResourceTypeLoadBalancer, loadBalancerName, false)
 		for k, v := range b.Cluster.Spec.CloudLabels {
 			tags[k] = v
 		}
@@ -242,7 +244,7 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 		} else if b.APILoadBalancerClass() == kops.LoadBalancerClassNetwork {
 
 			tcpGroupName := b.NLBTargetGroupName("tcp")
-			tcpGroupTags := b.CloudTags(tcpGroupName, false)
+			tcpGroupTags := b.CloudTagsForResource(kops.CloudResourceTypeTargetGroup, tcpGroupName, false)
 
 			// Override the returned name to be the expected NLB TG name
 			tcpGroupTags["Name"] = tcpGroupName
@@ -258,6 +260,7 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 				UnhealthyThreshold: fi.Int64(2),
 				Shared:             fi.Bool(false),
 			}
+			applyTargetGroupHealthCheck(tg, lbSpec)
 
 			c.AddTask(tg)
 
@@ -265,7 +268,7 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 
 			if lbSpec.SSLCertificate != "" {
 				tlsGroupName := b.NLBTargetGroupName("tls")
-				tlsGroupTags := b.CloudTags(tlsGroupName, false)
+				tlsGroupTags := b.CloudTagsForResource(kops.CloudResourceTypeTargetGroup, tlsGroupName, false)
 
 				// Override the returned name to be the expected NLB TG name
 				tlsGroupTags["Name"] = tlsGroupName
@@ -280,10 +283,14 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 					UnhealthyThreshold: fi.Int64(2),
 					Shared:             fi.Bool(false),
 				}
+				applyTargetGroupHealthCheck(secondaryTG, lbSpec)
 				c.AddTask(secondaryTG)
 				nlb.TargetGroups = append(nlb.TargetGroups, secondaryTG)
 			}
 			sort.Stable(awstasks.OrderTargetGroupsByName(nlb.TargetGroups))
+			if lbSpec.Type == kops.LoadBalancerTypePublic && fi.BoolValue(lbSpec.AllocateElasticIPs) {
+				b.addLoadBalancerElasticIPs(c, b.Lifecycle, "api", nlb.SubnetMappings)
+			}
 			c.AddTask(nlb)
 		}
 
@@ -298,7 +305,7 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 			RemoveExtraRules: []string{"port=443"},
 			VPC:              b.LinkToVPC(),
 		}
-		lbSG.Tags = b.CloudTags(*lbSG.Name, false)
+		lbSG.Tags = b.CloudTagsForResource(kops.CloudResourceTypeSecurityGroup, *lbSG.Name, false)
 
 		if lbSpec.SecurityGroupOverride != nil {
 			lbSG.ID = fi.String(*lbSpec.SecurityGroupOverride)
@@ -576,3 +583,45 @@ func (b *APILoadBalancerBuilder) chooseBestSubnetForELB(zone string, subnets []*
 
 	return scoredSubnets[0].subnet
 }
+
+// applyTargetGroupHealthCheck sets the health check and deregistration delay of an API target group from the load balancer spec
+func applyTargetGroupHealthCheck(tg *awstasks.TargetGroup, lbSpec *kops.LoadBalancerAccessSpec) {
+	tg.DeregistrationDelay = lbSpec.DeregistrationDelaySeconds
+
+	healthCheck := lbSpec.HealthCheck
+	if healthCheck == nil {
+		return
+	}
+	if healthCheck.HealthyThreshold != nil {
+		tg.HealthyThreshold = healthCheck.HealthyThreshold
+	}
+	if healthCheck.UnhealthyThreshold != nil {
+		tg.UnhealthyThreshold = healthCheck.UnhealthyThreshold
+	}
+	tg.HealthCheckInterval = healthCheck.IntervalSeconds
+	tg.HealthCheckProtocol = healthCheck.Protocol
+	tg.HealthCheckPath = healthCheck.Path
+	if healthCheck.Port != nil {
+		tg.HealthCheckPort = fi.String(strconv.FormatInt(*healthCheck.Port, 10))
+	}
+}
+
+// addLoadBalancerElasticIPs allocates an Elastic IP for each subnet mapping of a Network Load Balancer
+// that has no AllocationID. The Elastic IPs are tagged with the load balancer, and released with the cluster.
+func (b *AWSModelContext) addLoadBalancerElasticIPs(c *fi.ModelBuilderContext, lifecycle fi.Lifecycle, loadBalancer string, subnetMappings []*awstasks.SubnetMapping) {
+	for _, subnetMapping := range subnetMappings {
+		if subnetMapping.AllocationID != nil {
+			continue
+		}
+		name := b.LoadBalancerElasticIPName(loadBalancer, subnetMapping.Subnet)
+		tags := b.CloudTagsForResource(kops.CloudResourceTypeElasticIP, name, false)
+		tags[awsup.TagNameLoadBalancer] = loadBalancer
+		eip := &awstasks.ElasticIP{
+			Name:      fi.String(name),
+			Lifecycle: lifecycle,
+			Tags:      tags,
+		}
+		c.AddTask(eip)
+		subnetMapping.ElasticIP = eip
+	}
+}
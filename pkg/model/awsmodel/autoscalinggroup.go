@@ -23,6 +23,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/featureflag"
@@ -65,6 +66,11 @@ func (b *AutoscalingGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	for _, ig := range b.InstanceGroups {
 		name := b.AutoscalingGroupName(ig)
 
+		// The machines of hybrid instance groups are provisioned outside AWS.
+		if ig.IsHybrid() {
+			continue
+		}
+
 		if featureflag.SpotinstHybrid.Enabled() {
 			if HybridInstanceGroup(ig) {
 				klog.V(2).Infof("Skipping instance group: %q", name)
@@ -97,6 +103,10 @@ func (b *AutoscalingGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			if warmPool.IsEnabled() {
 				warmPoolTask.MinSize = warmPool.MinSize
 				warmPoolTask.MaxSize = warmPool.MaxSize
+				if b.Cluster.Spec.Hibernation != nil {
+					warmPoolTask.MinSize = 0
+					warmPoolTask.MaxSize = fi.Int64(0)
+				}
 
 				if warmPool.EnableLifecycleHook {
 					hookName := "kops-warmpool"
@@ -135,13 +145,10 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.ModelBuilde
 		return nil, fmt.Errorf("unable to find IAM profile link for instance group %q: %w", ig.ObjectMeta.Name, err)
 	}
 
-	rootVolumeSize, err := defaults.DefaultInstanceGroupVolumeSize(ig.Spec.Role)
+	rootVolumeSize, err := defaults.InstanceGroupVolumeSize(ig)
 	if err != nil {
 		return nil, err
 	}
-	if fi.Int32Value(ig.Spec.RootVolumeSize) > 0 {
-		rootVolumeSize = fi.Int32Value(ig.Spec.RootVolumeSize)
-	}
 
 	rootVolumeType := fi.StringValue(ig.Spec.RootVolumeType)
 	if rootVolumeType == "" {
@@ -157,13 +164,17 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.ModelBuilde
 	if fi.BoolValue(ig.Spec.RootVolumeEncryption) && ig.Spec.RootVolumeEncryptionKey != nil {
 		rootVolumeKmsKey = *ig.Spec.RootVolumeEncryptionKey
 	}
+	if ig.Spec.RootVolumeEncryptionKey == nil && b.DefaultVolumeKmsKeyId() != nil {
+		rootVolumeEncryption = true
+		rootVolumeKmsKey = *b.DefaultVolumeKmsKeyId()
+	}
 
 	securityGroups, err := b.buildSecurityGroups(c, ig)
 	if err != nil {
 		return nil, err
 	}
 
-	tags, err := b.CloudTagsForInstanceGroup(ig)
+	tags, err := b.InstanceCloudTagsForInstanceGroup(ig)
 	if err != nil {
 		return nil, fmt.Errorf("error building cloud tags: %v", err)
 	}
@@ -183,6 +194,7 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.ModelBuilde
 		ImageID:                      fi.String(ig.Spec.Image),
 		InstanceInterruptionBehavior: ig.Spec.InstanceInterruptionBehavior,
 		InstanceMonitoring:           fi.Bool(false),
+		InstanceTags:                 b.cloudLabelPolicyTags(kops.CloudResourceTypeInstance, ig, tags),
 		InstanceType:                 fi.String(strings.Split(ig.Spec.MachineType, ",")[0]),
 		IPv6AddressCount:             fi.Int64(0),
 		RootVolumeIops:               fi.Int64(int64(fi.Int32Value(ig.Spec.RootVolumeIops))),
@@ -194,6 +206,7 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.ModelBuilde
 		SecurityGroups:               securityGroups,
 		Tags:                         tags,
 		UserData:                     userData,
+		VolumeTags:                   b.cloudLabelPolicyTags(kops.CloudResourceTypeVolume, ig, tags),
 	}
 
 	{
@@ -227,9 +240,23 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.ModelBuilde
 		}
 	}
 
-	// @step: add any additional block devices
+	// @step: add any additional block devices, including the log and state volumes
+	var volumes []*kops.VolumeSpec
 	for i := range ig.Spec.Volumes {
-		x := &ig.Spec.Volumes[i]
+		volumes = append(volumes, &ig.Spec.Volumes[i])
+	}
+	if ig.Spec.Logging != nil && ig.Spec.Logging.Volume != nil {
+		volumes = append(volumes, ig.Spec.Logging.Volume)
+	}
+	if ig.Spec.StateVolumes != nil {
+		if ig.Spec.StateVolumes.Containerd != nil {
+			volumes = append(volumes, ig.Spec.StateVolumes.Containerd)
+		}
+		if ig.Spec.StateVolumes.Kubelet != nil {
+			volumes = append(volumes, ig.Spec.StateVolumes.Kubelet)
+		}
+	}
+	for _, x := range volumes {
 		if x.Type == "" {
 			x.Type = DefaultVolumeType
 		}
@@ -255,11 +282,16 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.ModelBuilde
 		if x.Encrypted != nil {
 			encryption = fi.BoolValue(x.Encrypted)
 		}
+		kmsKey := x.Key
+		if kmsKey == nil && b.DefaultVolumeKmsKeyId() != nil {
+			encryption = true
+			kmsKey = b.DefaultVolumeKmsKeyId()
+		}
 		lt.BlockDeviceMappings = append(lt.BlockDeviceMappings, &awstasks.BlockDeviceMapping{
 			DeviceName:             fi.String(x.Device),
 			EbsDeleteOnTermination: fi.Bool(deleteOnTermination),
 			EbsEncrypted:           fi.Bool(encryption),
-			EbsKmsKey:              x.Key,
+			EbsKmsKey:              kmsKey,
 			EbsVolumeIops:          x.Iops,
 			EbsVolumeSize:          fi.Int64(x.Size),
 			EbsVolumeThroughput:    x.Throughput,
@@ -323,6 +355,19 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.ModelBuilde
 }
 
 // buildSecurityGroups is responsible for building security groups for a launch template.
+// cloudLabelPolicyTags returns the tags of the cloud label policies matching the resources of the specified type
+// launched for the InstanceGroup, except those already in the tags of the InstanceGroup, which take precedence
+func (b *AutoscalingGroupModelBuilder) cloudLabelPolicyTags(resourceType kops.CloudResourceType, ig *kops.InstanceGroup, igTags map[string]string) map[string]string {
+	tags := b.CloudLabelPolicyTags(resourceType, ig.Spec.Subnets...)
+	for k := range igTags {
+		delete(tags, k)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
 func (b *AutoscalingGroupModelBuilder) buildSecurityGroups(c *fi.ModelBuilderContext, ig *kops.InstanceGroup) ([]*awstasks.SecurityGroup, error) {
 	// @step: if required we add the override for the security group for this instancegroup
 	sgLink := b.LinkToSecurityGroup(ig.Spec.Role)
@@ -405,6 +450,11 @@ func (b *AutoscalingGroupModelBuilder) buildAutoScalingGroupTask(c *fi.ModelBuil
 		maxSize = fi.Int64(2)
 	}
 
+	if b.Cluster.Spec.Hibernation != nil {
+		minSize = fi.Int64(0)
+		maxSize = fi.Int64(0)
+	}
+
 	t.MinSize = minSize
 	t.MaxSize = maxSize
 
@@ -424,14 +474,48 @@ func (b *AutoscalingGroupModelBuilder) buildAutoScalingGroupTask(c *fi.ModelBuil
 		return nil, fmt.Errorf("error building cloud tags: %v", err)
 	}
 	t.Tags = tags
+	// Tags scoped to Autoscaling Groups must not reach the instances they launch
+	t.NonPropagatedTags = b.cloudLabelPolicyTags(kops.CloudResourceTypeAutoScalingGroup, ig, tags)
+	casTags, err := b.ClusterAutoscalerTagsForInstanceGroup(ig)
+	if err != nil {
+		return nil, fmt.Errorf("error building cluster autoscaler tags: %v", err)
+	}
+	for k, v := range casTags {
+		if t.NonPropagatedTags == nil {
+			t.NonPropagatedTags = make(map[string]string)
+		}
+		t.NonPropagatedTags[k] = v
+	}
 
 	processes := []string{}
 	processes = append(processes, ig.Spec.SuspendProcesses...)
+	if fi.BoolValue(ig.Spec.StableCapacity) {
+		for _, p := range kops.StableCapacityProcesses {
+			if !sets.NewString(processes...).Has(p) {
+				processes = append(processes, p)
+			}
+		}
+	}
 	t.SuspendProcesses = &processes
 
 	if ig.Spec.InstanceProtection != nil {
 		t.InstanceProtection = ig.Spec.InstanceProtection
 	}
+	if ig.Spec.ScaleInProtection == kops.ScaleInProtectionUntilCordoned {
+		// kops-controller removes the protection from the instances of cordoned nodes
+		t.InstanceProtection = fi.Bool(true)
+	}
+	if len(ig.Spec.TerminationPolicies) != 0 {
+		t.TerminationPolicies = ig.Spec.TerminationPolicies
+	}
+
+	// Groups without a policy have the policy removed, so that it does not outlive the spec
+	t.MinHealthyPercentage = fi.Int64(awsup.NoInstanceMaintenancePolicy)
+	t.MaxHealthyPercentage = fi.Int64(awsup.NoInstanceMaintenancePolicy)
+	if policy := ig.Spec.InstanceMaintenancePolicy; policy != nil {
+		t.MinHealthyPercentage = policy.MinHealthyPercentage
+		t.MaxHealthyPercentage = policy.MaxHealthyPercentage
+	}
 
 	t.LoadBalancers = []*awstasks.ClassicLoadBalancer{}
 	t.TargetGroups = []*awstasks.TargetGroup{}
@@ -453,7 +537,11 @@ func (b *AutoscalingGroupModelBuilder) buildAutoScalingGroupTask(c *fi.ModelBuil
 		}
 
 		if ig.Spec.Role == kops.InstanceGroupRoleBastion {
-			t.LoadBalancers = append(t.LoadBalancers, b.LinkToCLB("bastion"))
+			if b.UseNetworkLoadBalancerForBastion() {
+				t.TargetGroups = append(t.TargetGroups, b.LinkToTargetGroup("bastion"))
+			} else {
+				t.LoadBalancers = append(t.LoadBalancers, b.LinkToCLB("bastion"))
+			}
 		}
 	}
 
@@ -18,6 +18,7 @@ package awsmodel
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -112,6 +113,198 @@ func TestRootVolumeOptimizationFlag(t *testing.T) {
 	}
 }
 
+func TestHibernationScalesToZero(t *testing.T) {
+	cluster := buildMinimalCluster()
+	cluster.Spec.Hibernation = &kops.HibernationSpec{}
+	ig := buildNodeInstanceGroup("subnet-us-mock-1a")
+	ig.Spec.MinSize = fi.Int32(2)
+	ig.Spec.MaxSize = fi.Int32(5)
+
+	b := AutoscalingGroupModelBuilder{
+		AWSModelContext: &AWSModelContext{
+			KopsModelContext: &model.KopsModelContext{
+				IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+				SSHPublicKeys:   [][]byte{[]byte(sshPublicKeyEntry)},
+				InstanceGroups:  []*kops.InstanceGroup{ig},
+			},
+		},
+		BootstrapScriptBuilder: &model.BootstrapScriptBuilder{
+			Lifecycle: fi.LifecycleSync,
+			Cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					Networking: &kops.NetworkingSpec{},
+				},
+			},
+		},
+		Cluster: cluster,
+	}
+
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+
+	// We need the CA for the bootstrap script
+	caTask := &fitasks.Keypair{
+		Name:    fi.String(fi.CertificateIDCA),
+		Subject: "cn=kubernetes",
+		Type:    "ca",
+	}
+	c.AddTask(caTask)
+	for _, keypair := range []string{
+		"etcd-clients-ca",
+	} {
+		task := &fitasks.Keypair{
+			Name:    fi.String(keypair),
+			Subject: "cn=" + keypair,
+			Type:    "ca",
+		}
+		c.AddTask(task)
+	}
+
+	if err := b.Build(c); err != nil {
+		t.Fatalf("error from Build: %v", err)
+	}
+
+	asg := c.Tasks["AutoscalingGroup/nodes.testcluster.test.com"].(*awstasks.AutoscalingGroup)
+
+	if fi.Int64Value(asg.MinSize) != 0 || fi.Int64Value(asg.MaxSize) != 0 {
+		t.Fatalf("expected hibernated autoscaling group to be scaled to zero, got min %d max %d", fi.Int64Value(asg.MinSize), fi.Int64Value(asg.MaxSize))
+	}
+}
+
+func TestDefaultVolumeKmsKey(t *testing.T) {
+	cluster := buildMinimalCluster()
+	cluster.Spec.CloudConfig = &kops.CloudConfiguration{
+		EBSDefaultEncryption: &kops.EBSDefaultEncryptionSpec{
+			KmsKeyId: fi.String("default-key"),
+		},
+	}
+	ig := buildNodeInstanceGroup("subnet-us-mock-1a")
+	ig.Spec.Volumes = []kops.VolumeSpec{
+		{Device: "/dev/xvdd", Size: 20},
+		{Device: "/dev/xvde", Size: 20, Encrypted: fi.Bool(true), Key: fi.String("volume-key")},
+	}
+
+	b := AutoscalingGroupModelBuilder{
+		AWSModelContext: &AWSModelContext{
+			KopsModelContext: &model.KopsModelContext{
+				IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+				SSHPublicKeys:   [][]byte{[]byte(sshPublicKeyEntry)},
+				InstanceGroups:  []*kops.InstanceGroup{ig},
+			},
+		},
+		BootstrapScriptBuilder: &model.BootstrapScriptBuilder{
+			Lifecycle: fi.LifecycleSync,
+			Cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					Networking: &kops.NetworkingSpec{},
+				},
+			},
+		},
+		Cluster: cluster,
+	}
+
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+
+	// We need the CA for the bootstrap script
+	caTask := &fitasks.Keypair{
+		Name:    fi.String(fi.CertificateIDCA),
+		Subject: "cn=kubernetes",
+		Type:    "ca",
+	}
+	c.AddTask(caTask)
+	for _, keypair := range []string{
+		"etcd-clients-ca",
+	} {
+		task := &fitasks.Keypair{
+			Name:    fi.String(keypair),
+			Subject: "cn=" + keypair,
+			Type:    "ca",
+		}
+		c.AddTask(task)
+	}
+
+	if err := b.Build(c); err != nil {
+		t.Fatalf("error from Build: %v", err)
+	}
+
+	lt := c.Tasks["LaunchTemplate/nodes.testcluster.test.com"].(*awstasks.LaunchTemplate)
+
+	if !fi.BoolValue(lt.RootVolumeEncryption) || fi.StringValue(lt.RootVolumeKmsKey) != "default-key" {
+		t.Errorf("expected root volume to be encrypted with the default key, got encryption %v key %q", fi.BoolValue(lt.RootVolumeEncryption), fi.StringValue(lt.RootVolumeKmsKey))
+	}
+	expected := map[string]string{
+		"/dev/xvdd": "default-key",
+		"/dev/xvde": "volume-key",
+	}
+	for _, bdm := range lt.BlockDeviceMappings {
+		if !fi.BoolValue(bdm.EbsEncrypted) || fi.StringValue(bdm.EbsKmsKey) != expected[fi.StringValue(bdm.DeviceName)] {
+			t.Errorf("expected volume %s to be encrypted with key %q, got encryption %v key %q", fi.StringValue(bdm.DeviceName), expected[fi.StringValue(bdm.DeviceName)], fi.BoolValue(bdm.EbsEncrypted), fi.StringValue(bdm.EbsKmsKey))
+		}
+	}
+}
+
+func TestStableCapacitySuspendsProcesses(t *testing.T) {
+	cluster := buildMinimalCluster()
+	ig := buildNodeInstanceGroup("subnet-us-mock-1a")
+	ig.Spec.SuspendProcesses = []string{"AZRebalance", "ScheduledActions"}
+	ig.Spec.StableCapacity = fi.Bool(true)
+
+	b := AutoscalingGroupModelBuilder{
+		AWSModelContext: &AWSModelContext{
+			KopsModelContext: &model.KopsModelContext{
+				IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+				SSHPublicKeys:   [][]byte{[]byte(sshPublicKeyEntry)},
+				InstanceGroups:  []*kops.InstanceGroup{ig},
+			},
+		},
+		BootstrapScriptBuilder: &model.BootstrapScriptBuilder{
+			Lifecycle: fi.LifecycleSync,
+			Cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					Networking: &kops.NetworkingSpec{},
+				},
+			},
+		},
+		Cluster: cluster,
+	}
+
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+
+	// We need the CA for the bootstrap script
+	caTask := &fitasks.Keypair{
+		Name:    fi.String(fi.CertificateIDCA),
+		Subject: "cn=kubernetes",
+		Type:    "ca",
+	}
+	c.AddTask(caTask)
+	for _, keypair := range []string{
+		"etcd-clients-ca",
+	} {
+		task := &fitasks.Keypair{
+			Name:    fi.String(keypair),
+			Subject: "cn=" + keypair,
+			Type:    "ca",
+		}
+		c.AddTask(task)
+	}
+
+	if err := b.Build(c); err != nil {
+		t.Fatalf("error from Build: %v", err)
+	}
+
+	asg := c.Tasks["AutoscalingGroup/nodes.testcluster.test.com"].(*awstasks.AutoscalingGroup)
+
+	expected := []string{"AZRebalance", "ScheduledActions", "ReplaceUnhealthy"}
+	if !reflect.DeepEqual(*asg.SuspendProcesses, expected) {
+		t.Fatalf("expected suspended processes %v, got %v", expected, *asg.SuspendProcesses)
+	}
+}
+
 func TestAPIServerAdditionalSecurityGroupsWithNLB(t *testing.T) {
 	const sgIDAPIServer = "sg-01234567890abcdef"
 
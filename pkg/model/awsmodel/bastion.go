@@ -17,6 +17,7 @@ limitations under the License.
 package awsmodel
 
 import (
+	"sort"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -35,6 +36,7 @@ const (
 //
 // Bastion instances live in the utility subnets created in the private topology.
 // All traffic goes through an ELB, and the ELB has port 22 open to SSHAccess.
+// With a Network load balancer, the bastion instances have port 22 open to SSHAccess instead.
 // Bastion instances have access to all internal master and node instances.
 
 type BastionModelBuilder struct {
@@ -100,19 +102,57 @@ func (b *BastionModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		}
 	}
 
+	useNLB := b.UseNetworkLoadBalancerForBastion()
+
 	// Allow incoming SSH traffic to bastions, through the ELB
 	// TODO: Could we get away without an ELB here?  Tricky to fix if dns-controller breaks though...
-	for _, dest := range bastionGroups {
-		t := &awstasks.SecurityGroupRule{
-			Name:          fi.String("ssh-elb-to-bastion" + dest.Suffix),
-			Lifecycle:     b.SecurityLifecycle,
-			SecurityGroup: dest.Task,
-			SourceGroup:   b.LinkToELBSecurityGroup(BastionELBSecurityGroupPrefix),
-			Protocol:      fi.String("tcp"),
-			FromPort:      fi.Int64(22),
-			ToPort:        fi.Int64(22),
+	if !useNLB {
+		for _, dest := range bastionGroups {
+			t := &awstasks.SecurityGroupRule{
+				Name:          fi.String("ssh-elb-to-bastion" + dest.Suffix),
+				Lifecycle:     b.SecurityLifecycle,
+				SecurityGroup: dest.Task,
+				SourceGroup:   b.LinkToELBSecurityGroup(BastionELBSecurityGroupPrefix),
+				Protocol:      fi.String("tcp"),
+				FromPort:      fi.Int64(22),
+				ToPort:        fi.Int64(22),
+			}
+			AddDirectionalGroupRule(c, t)
+		}
+	} else {
+		// An NLB preserves the source address of clients, and has no security group,
+		// so SSHAccess applies to the bastions themselves; the NLB health checks come from the VPC
+		for _, dest := range bastionGroups {
+			for _, sshAccess := range b.Cluster.Spec.SSHAccess {
+				t := &awstasks.SecurityGroupRule{
+					Name:          fi.String("ssh-external-to-bastion-" + sshAccess + dest.Suffix),
+					Lifecycle:     b.SecurityLifecycle,
+					SecurityGroup: dest.Task,
+					Protocol:      fi.String("tcp"),
+					FromPort:      fi.Int64(22),
+					ToPort:        fi.Int64(22),
+				}
+				if utils.IsIPv6CIDR(sshAccess) {
+					t.IPv6CIDR = fi.String(sshAccess)
+				} else {
+					t.CIDR = fi.String(sshAccess)
+				}
+				AddDirectionalGroupRule(c, t)
+			}
+
+			for _, cidr := range append([]string{b.Cluster.Spec.NetworkCIDR}, b.Cluster.Spec.AdditionalNetworkCIDRs...) {
+				t := &awstasks.SecurityGroupRule{
+					Name:          fi.String("ssh-nlb-to-bastion-" + cidr + dest.Suffix),
+					Lifecycle:     b.SecurityLifecycle,
+					SecurityGroup: dest.Task,
+					Protocol:      fi.String("tcp"),
+					FromPort:      fi.Int64(22),
+					ToPort:        fi.Int64(22),
+					CIDR:          fi.String(cidr),
+				}
+				AddDirectionalGroupRule(c, t)
+			}
 		}
-		AddDirectionalGroupRule(c, t)
 	}
 
 	// Allow bastion nodes to SSH to masters
@@ -147,6 +187,63 @@ func (b *BastionModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		}
 	}
 
+	var elbSubnets []*awstasks.Subnet
+	zones := sets.NewString()
+	{
+		for _, ig := range bastionInstanceGroups {
+			subnets, err := b.GatherSubnets(ig)
+			if err != nil {
+				return err
+			}
+			for _, s := range subnets {
+				zones.Insert(s.Zone)
+			}
+		}
+
+		for zoneName := range zones {
+			utilitySubnet, err := b.LinkToUtilitySubnetInZone(zoneName)
+			if err != nil {
+				return err
+			}
+			elbSubnets = append(elbSubnets, utilitySubnet)
+		}
+	}
+
+	var lb awstasks.DNSTarget
+	if useNLB {
+		lb = b.buildNetworkLoadBalancer(c, elbSubnets, b.utilitySubnetsHaveIPv6(zones))
+	} else {
+		elb, err := b.buildClassicLoadBalancer(c, elbSubnets)
+		if err != nil {
+			return err
+		}
+		lb = elb
+	}
+
+	bastionPublicName := ""
+	if b.Cluster.Spec.Topology != nil && b.Cluster.Spec.Topology.Bastion != nil {
+		bastionPublicName = b.Cluster.Spec.Topology.Bastion.BastionPublicName
+	}
+	if bastionPublicName != "" {
+		// Here we implement the bastion CNAME logic
+		// By default bastions will create a CNAME that follows the `bastion-$clustername` formula
+		t := &awstasks.DNSName{
+			Name:      fi.String(bastionPublicName),
+			Lifecycle: b.Lifecycle,
+
+			Zone:               b.LinkToDNSZone(),
+			ResourceName:       fi.String(bastionPublicName),
+			ResourceType:       fi.String("A"),
+			TargetLoadBalancer: lb,
+		}
+		c.AddTask(t)
+
+	}
+	return nil
+}
+
+// buildClassicLoadBalancer adds the Classic ELB of the bastions, and its security group
+func (b *BastionModelBuilder) buildClassicLoadBalancer(c *fi.ModelBuilderContext, elbSubnets []*awstasks.Subnet) (*awstasks.ClassicLoadBalancer, error) {
 	// Create security group for bastion ELB
 	{
 		t := &awstasks.SecurityGroup{
@@ -156,7 +253,7 @@ func (b *BastionModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			Description:      fi.String("Security group for bastion ELB"),
 			RemoveExtraRules: []string{"port=22"},
 		}
-		t.Tags = b.CloudTags(*t.Name, false)
+		t.Tags = b.CloudTagsForResource(kops.CloudResourceTypeSecurityGroup, *t.Name, false)
 		c.AddTask(t)
 	}
 
@@ -200,28 +297,6 @@ func (b *BastionModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		AddDirectionalGroupRule(c, t)
 	}
 
-	var elbSubnets []*awstasks.Subnet
-	{
-		zones := sets.NewString()
-		for _, ig := range bastionInstanceGroups {
-			subnets, err := b.GatherSubnets(ig)
-			if err != nil {
-				return err
-			}
-			for _, s := range subnets {
-				zones.Insert(s.Zone)
-			}
-		}
-
-		for zoneName := range zones {
-			utilitySubnet, err := b.LinkToUtilitySubnetInZone(zoneName)
-			if err != nil {
-				return err
-			}
-			elbSubnets = append(elbSubnets, utilitySubnet)
-		}
-	}
-
 	// Create ELB itself
 	var elb *awstasks.ClassicLoadBalancer
 	{
@@ -232,7 +307,7 @@ func (b *BastionModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			idleTimeout = time.Second * time.Duration(*b.Cluster.Spec.Topology.Bastion.IdleTimeoutSeconds)
 		}
 
-		tags := b.CloudTags(loadBalancerName, false)
+		tags := b.CloudTagsForResource(kops.CloudResourceTypeLoadBalancer, loadBalancerName, false)
 		for k, v := range b.Cluster.Spec.CloudLabels {
 			tags[k] = v
 		}
@@ -276,7 +351,7 @@ func (b *BastionModelBuilder) Build(c *fi.ModelBuilderContext) error {
 					Shared:    fi.Bool(true),
 				}
 				if err := c.EnsureTask(t); err != nil {
-					return err
+					return nil, err
 				}
 				elb.SecurityGroups = append(elb.SecurityGroups, t)
 			}
@@ -285,24 +360,95 @@ func (b *BastionModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		c.AddTask(elb)
 	}
 
-	bastionPublicName := ""
-	if b.Cluster.Spec.Topology != nil && b.Cluster.Spec.Topology.Bastion != nil {
-		bastionPublicName = b.Cluster.Spec.Topology.Bastion.BastionPublicName
+	return elb, nil
+}
+
+// utilitySubnetsHaveIPv6 returns true if all the utility subnets in the zones have an IPv6 CIDR
+func (b *BastionModelBuilder) utilitySubnetsHaveIPv6(zones sets.String) bool {
+	for i := range b.Cluster.Spec.Subnets {
+		subnet := &b.Cluster.Spec.Subnets[i]
+		if subnet.Type == kops.SubnetTypeUtility && zones.Has(subnet.Zone) && subnet.IPv6CIDR == "" {
+			return false
+		}
 	}
-	if bastionPublicName != "" {
-		// Here we implement the bastion CNAME logic
-		// By default bastions will create a CNAME that follows the `bastion-$clustername` formula
-		t := &awstasks.DNSName{
-			Name:      fi.String(bastionPublicName),
-			Lifecycle: b.Lifecycle,
+	return zones.Len() > 0
+}
 
-			Zone:               b.LinkToDNSZone(),
-			ResourceName:       fi.String(bastionPublicName),
-			ResourceType:       fi.String("A"),
-			TargetLoadBalancer: elb,
+// buildNetworkLoadBalancer adds the NLB of the bastions, and its target group.
+// When the subnets have IPv6 CIDRs, the NLB is dual-stack so that SSH is also accepted over IPv6.
+func (b *BastionModelBuilder) buildNetworkLoadBalancer(c *fi.ModelBuilderContext, elbSubnets []*awstasks.Subnet, dualStack bool) *awstasks.NetworkLoadBalancer {
+	loadBalancerName := b.LBName32("bastion")
+
+	tags := b.CloudTagsForResource(kops.CloudResourceTypeLoadBalancer, loadBalancerName, false)
+	for k, v := range b.Cluster.Spec.CloudLabels {
+		tags[k] = v
+	}
+	// Override the returned name to be the expected NLB name
+	tags["Name"] = "bastion." + b.ClusterName()
+
+	targetGroupName := b.NLBTargetGroupName("bastion")
+	targetGroupTags := b.CloudTagsForResource(kops.CloudResourceTypeTargetGroup, targetGroupName, false)
+	// Override the returned name to be the expected NLB TG name
+	targetGroupTags["Name"] = targetGroupName
+
+	tg := &awstasks.TargetGroup{
+		Name:               fi.String(targetGroupName),
+		Lifecycle:          b.Lifecycle,
+		VPC:                b.LinkToVPC(),
+		Tags:               targetGroupTags,
+		Protocol:           fi.String("TCP"),
+		Port:               fi.Int64(22),
+		HealthyThreshold:   fi.Int64(2),
+		UnhealthyThreshold: fi.Int64(2),
+		Shared:             fi.Bool(false),
+	}
+	c.AddTask(tg)
+
+	lbSpec := b.Cluster.Spec.Topology.Bastion.LoadBalancer
+
+	var subnetMappings []*awstasks.SubnetMapping
+	for _, subnet := range elbSubnets {
+		subnetMapping := &awstasks.SubnetMapping{Subnet: subnet}
+		for _, s := range lbSpec.Subnets {
+			if fi.StringValue(b.LinkToSubnet(&kops.ClusterSubnetSpec{Name: s.Name}).Name) == fi.StringValue(subnet.Name) {
+				subnetMapping.PrivateIPv4Address = s.PrivateIPv4Address
+				subnetMapping.AllocationID = s.AllocationID
+			}
 		}
-		c.AddTask(t)
+		subnetMappings = append(subnetMappings, subnetMapping)
+	}
+	sort.Slice(subnetMappings, func(i, j int) bool {
+		return fi.StringValue(subnetMappings[i].Subnet.Name) < fi.StringValue(subnetMappings[j].Subnet.Name)
+	})
+	if fi.BoolValue(lbSpec.AllocateElasticIPs) {
+		b.addLoadBalancerElasticIPs(c, b.Lifecycle, "bastion", subnetMappings)
+	}
 
+	nlb := &awstasks.NetworkLoadBalancer{
+		Name:      fi.String(b.NLBName("bastion")),
+		Lifecycle: b.Lifecycle,
+
+		LoadBalancerName: fi.String(loadBalancerName),
+		SubnetMappings:   subnetMappings,
+		Listeners: []*awstasks.NetworkLoadBalancerListener{
+			{
+				Port:            22,
+				TargetGroupName: targetGroupName,
+			},
+		},
+		TargetGroups: []*awstasks.TargetGroup{tg},
+
+		CrossZoneLoadBalancing: fi.Bool(true),
+
+		Tags:          tags,
+		VPC:           b.LinkToVPC(),
+		Type:          fi.String("network"),
+		IpAddressType: fi.String("ipv4"),
 	}
-	return nil
+	if dualStack {
+		nlb.IpAddressType = fi.String("dualstack")
+	}
+	c.AddTask(nlb)
+
+	return nlb
 }
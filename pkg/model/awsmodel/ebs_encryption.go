@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsmodel
+
+import (
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+// EBSEncryptionModelBuilder verifies or turns on EBS encryption by default
+type EBSEncryptionModelBuilder struct {
+	*AWSModelContext
+
+	Lifecycle fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &EBSEncryptionModelBuilder{}
+
+func (b *EBSEncryptionModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	if b.Cluster.Spec.CloudConfig == nil || b.Cluster.Spec.CloudConfig.EBSDefaultEncryption == nil {
+		return nil
+	}
+
+	c.AddTask(&awstasks.EBSEncryptionByDefault{
+		Name:      fi.String("ebs-encryption-by-default"),
+		Lifecycle: b.Lifecycle,
+		Enabled:   fi.Bool(true),
+		Manage:    fi.Bool(fi.BoolValue(b.Cluster.Spec.CloudConfig.EBSDefaultEncryption.Enable)),
+	})
+
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsmodel
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// EgressGatewayModelBuilder allocates the Elastic IPs of the egress gateways.
+// kops-controller associates them with the gateway nodes.
+type EgressGatewayModelBuilder struct {
+	*AWSModelContext
+	Lifecycle fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &EgressGatewayModelBuilder{}
+
+func (b *EgressGatewayModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	for _, gateway := range b.Cluster.Spec.EgressGateways {
+		name := b.EgressGatewayElasticIPName(gateway.Name)
+		tags := b.CloudTagsForResource(kops.CloudResourceTypeElasticIP, name, false)
+		tags[awsup.TagNameEgressGateway] = gateway.Name
+		c.AddTask(&awstasks.ElasticIP{
+			Name:      fi.String(name),
+			Lifecycle: b.Lifecycle,
+			Tags:      tags,
+		})
+	}
+
+	return nil
+}
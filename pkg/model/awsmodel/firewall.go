@@ -326,7 +326,7 @@ func (b *AWSModelContext) GetSecurityGroups(role kops.InstanceGroupRole) ([]Secu
 				// TODO: Protocol 4 for calico
 			},
 		}
-		baseGroup.Tags = b.CloudTags(name, false)
+		baseGroup.Tags = b.CloudTagsForResource(kops.CloudResourceTypeSecurityGroup, name, false)
 	} else if role == kops.InstanceGroupRoleNode {
 		name := b.SecurityGroupName(role)
 		baseGroup = &awstasks.SecurityGroup{
@@ -335,7 +335,7 @@ func (b *AWSModelContext) GetSecurityGroups(role kops.InstanceGroupRole) ([]Secu
 			Description:      fi.String("Security group for nodes"),
 			RemoveExtraRules: []string{"port=22"},
 		}
-		baseGroup.Tags = b.CloudTags(name, false)
+		baseGroup.Tags = b.CloudTagsForResource(kops.CloudResourceTypeSecurityGroup, name, false)
 	} else if role == kops.InstanceGroupRoleBastion {
 		name := b.SecurityGroupName(role)
 		baseGroup = &awstasks.SecurityGroup{
@@ -344,7 +344,7 @@ func (b *AWSModelContext) GetSecurityGroups(role kops.InstanceGroupRole) ([]Secu
 			Description:      fi.String("Security group for bastion"),
 			RemoveExtraRules: []string{"port=22"},
 		}
-		baseGroup.Tags = b.CloudTags(name, false)
+		baseGroup.Tags = b.CloudTagsForResource(kops.CloudResourceTypeSecurityGroup, name, false)
 	} else {
 		return nil, fmt.Errorf("not a supported security group type")
 	}
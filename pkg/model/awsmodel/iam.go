@@ -63,6 +63,10 @@ func (b *IAMModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	// Collect Instance Profile ARNs and their associated Instance Group roles
 	sharedProfileARNsToIGRole := make(map[string]kops.InstanceGroupRole)
 	for _, ig := range b.InstanceGroups {
+		// Hybrid machines have no instance profile.
+		if ig.IsHybrid() {
+			continue
+		}
 		if ig.Spec.IAM != nil && ig.Spec.IAM.Profile != nil {
 			specProfile := fi.StringValue(ig.Spec.IAM.Profile)
 			if matchingRole, ok := sharedProfileARNsToIGRole[specProfile]; ok {
@@ -201,7 +205,7 @@ func (b *IAMModelBuilder) buildIAMRole(role iam.Subject, iamName string, c *fi.M
 		Lifecycle: b.Lifecycle,
 
 		RolePolicyDocument: rolePolicy,
-		Tags:               b.CloudTags(iamName, false),
+		Tags:               b.CloudTagsForResource(kops.CloudResourceTypeIAM, iamName, false),
 	}
 
 	if isServiceAccount {
@@ -225,6 +229,7 @@ func (b *IAMModelBuilder) buildIAMRolePolicy(role iam.Subject, iamName string, i
 	iamPolicy := &iam.PolicyResource{
 		Builder: &iam.PolicyBuilder{
 			Cluster:              b.Cluster,
+			InstanceGroups:       b.InstanceGroups,
 			Role:                 role,
 			Region:               b.Region,
 			UseServiceAccountIAM: b.UseServiceAccountIAM(),
@@ -288,7 +293,7 @@ func (b *IAMModelBuilder) buildIAMTasks(role iam.Subject, iamName string, c *fi.
 				Name:      fi.String(iamName),
 				Lifecycle: b.Lifecycle,
 				Shared:    fi.Bool(shared),
-				Tags:      b.CloudTags(iamName, shared),
+				Tags:      b.CloudTagsForResource(kops.CloudResourceTypeIAM, iamName, shared),
 			}
 			c.AddTask(iamInstanceProfile)
 		}
@@ -47,7 +47,7 @@ func isUnmanaged(subnet *kops.ClusterSubnetSpec) bool {
 func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	sharedVPC := b.Cluster.SharedVPC()
 	vpcName := b.ClusterName()
-	tags := b.CloudTags(vpcName, sharedVPC)
+	tags := b.CloudTagsForResource(kops.CloudResourceTypeVPC, vpcName, sharedVPC)
 
 	// VPC that holds everything for the cluster
 	{
@@ -167,7 +167,7 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			VPC:       b.LinkToVPC(),
 			Shared:    fi.Bool(sharedVPC),
 		}
-		igw.Tags = b.CloudTags(*igw.Name, *igw.Shared)
+		igw.Tags = b.CloudTagsForResource(kops.CloudResourceTypeInternetGateway, *igw.Name, *igw.Shared)
 		c.AddTask(igw)
 
 		if !allSubnetsShared {
@@ -175,7 +175,7 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			// That subnet will be owned, and will be associated with our RouteTable.
 			// On deletion we delete the subnet & the route table.
 			sharedRouteTable := false
-			routeTableTags := b.CloudTags(vpcName, sharedRouteTable)
+			routeTableTags := b.CloudTagsForResource(kops.CloudResourceTypeRouteTable, vpcName, sharedRouteTable)
 			routeTableTags[awsup.TagNameKopsRole] = "public"
 			publicRouteTable = &awstasks.RouteTable{
 				Name:      fi.String(b.ClusterName()),
@@ -219,7 +219,7 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			klog.V(2).Infof("skipping subnet tags. Ensure these are maintained externally.")
 		} else {
 			klog.V(2).Infof("applying subnet tags")
-			tags = b.CloudTags(subnetName, sharedSubnet)
+			tags = b.CloudTagsForResource(kops.CloudResourceTypeSubnet, subnetName, sharedSubnet, subnetSpec.Name)
 			tags["SubnetType"] = string(subnetSpec.Type)
 
 			switch subnetSpec.Type {
@@ -298,6 +298,9 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		}
 	}
 
+	// The NAT gateways created by kOps are deleted while the cluster is hibernated, and so are the routes to them
+	natGatewaysHibernated := b.Cluster.Spec.Hibernation != nil && b.Cluster.Spec.Hibernation.NATGateways
+
 	// Set up private route tables & egress
 	for zone, info := range infoByZone {
 		if len(info.PrivateSubnets) == 0 {
@@ -364,15 +367,17 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 				}
 				c.AddTask(eip)
 
-				ngw = &awstasks.NatGateway{
-					Name:                 fi.String(zone + "." + b.ClusterName()),
-					Lifecycle:            b.Lifecycle,
-					Subnet:               utilitySubnet,
-					ElasticIP:            eip,
-					AssociatedRouteTable: b.LinkToPrivateRouteTableInZone(zone),
-					Tags:                 b.CloudTags(zone+"."+b.ClusterName(), false),
+				if !natGatewaysHibernated {
+					ngw = &awstasks.NatGateway{
+						Name:                 fi.String(zone + "." + b.ClusterName()),
+						Lifecycle:            b.Lifecycle,
+						Subnet:               utilitySubnet,
+						ElasticIP:            eip,
+						AssociatedRouteTable: b.LinkToPrivateRouteTableInZone(zone),
+						Tags:                 b.CloudTagsForResource(kops.CloudResourceTypeNatGateway, zone+"."+b.ClusterName(), false),
+					}
+					c.AddTask(ngw)
 				}
-				c.AddTask(ngw)
 
 			} else if strings.HasPrefix(egress, "i-") {
 
@@ -408,7 +413,7 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 				eip.PublicIP = fi.String(publicIP)
 				eip.Tags = b.CloudTags(*eip.Name, true)
 			} else {
-				eip.Tags = b.CloudTags(*eip.Name, false)
+				eip.Tags = b.CloudTagsForResource(kops.CloudResourceTypeElasticIP, *eip.Name, false)
 			}
 
 			c.AddTask(eip)
@@ -421,15 +426,17 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			// in the public subnet.
 
 			//var ngw = &awstasks.NatGateway{}
-			ngw = &awstasks.NatGateway{
-				Name:                 fi.String(zone + "." + b.ClusterName()),
-				Lifecycle:            b.Lifecycle,
-				Subnet:               utilitySubnet,
-				ElasticIP:            eip,
-				AssociatedRouteTable: b.LinkToPrivateRouteTableInZone(zone),
-				Tags:                 b.CloudTags(zone+"."+b.ClusterName(), false),
+			if !natGatewaysHibernated {
+				ngw = &awstasks.NatGateway{
+					Name:                 fi.String(zone + "." + b.ClusterName()),
+					Lifecycle:            b.Lifecycle,
+					Subnet:               utilitySubnet,
+					ElasticIP:            eip,
+					AssociatedRouteTable: b.LinkToPrivateRouteTableInZone(zone),
+					Tags:                 b.CloudTagsForResource(kops.CloudResourceTypeNatGateway, zone+"."+b.ClusterName(), false),
+				}
+				c.AddTask(ngw)
 			}
-			c.AddTask(ngw)
 		}
 
 		// Private Route Table
@@ -438,7 +445,7 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		// We create an owned route table if we created any subnet in that zone.
 		// Otherwise we consider it shared.
 		routeTableShared := allSubnetsSharedInZone[zone]
-		routeTableTags := b.CloudTags(b.NamePrivateRouteTableInZone(zone), routeTableShared)
+		routeTableTags := b.CloudTagsForResource(kops.CloudResourceTypeRouteTable, b.NamePrivateRouteTableInZone(zone), routeTableShared)
 		routeTableTags[awsup.TagNameKopsRole] = "private-" + zone
 		rt := &awstasks.RouteTable{
 			Name:      fi.String(b.NamePrivateRouteTableInZone(zone)),
@@ -450,6 +457,11 @@ func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		}
 		c.AddTask(rt)
 
+		if natGatewaysHibernated && ngw == nil && in == nil && tgwID == nil {
+			// The NAT gateway is deleted while the cluster is hibernated
+			continue
+		}
+
 		// Private Routes
 		//
 		// Routes for the private route table.
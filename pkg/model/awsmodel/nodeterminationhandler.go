@@ -76,6 +76,9 @@ type NodeTerminationHandlerBuilder struct {
 func (b *NodeTerminationHandlerBuilder) Build(c *fi.ModelBuilderContext) error {
 
 	for _, ig := range b.InstanceGroups {
+		if ig.IsHybrid() {
+			continue
+		}
 		err := b.configureASG(c, ig)
 		if err != nil {
 			return err
@@ -124,7 +127,7 @@ func (b *NodeTerminationHandlerBuilder) buildSQSQueue(c *fi.ModelBuilderContext)
 		Lifecycle:              b.Lifecycle,
 		Policy:                 fi.NewStringResource(policy),
 		MessageRetentionPeriod: DefaultMessageRetentionPeriod,
-		Tags:                   b.CloudTags(queueName, false),
+		Tags:                   b.CloudTagsForResource(kops.CloudResourceTypeSQSQueue, queueName, false),
 	}
 
 	c.AddTask(task)
@@ -147,7 +150,7 @@ func (b *NodeTerminationHandlerBuilder) buildEventBridgeRules(c *fi.ModelBuilder
 		ruleTask := &awstasks.EventBridgeRule{
 			Name:      ruleName,
 			Lifecycle: b.Lifecycle,
-			Tags:      b.CloudTags(*ruleName, false),
+			Tags:      b.CloudTagsForResource(kops.CloudResourceTypeEventRule, *ruleName, false),
 
 			EventPattern: &pattern,
 			TargetArn:    &targetArn,
@@ -142,6 +142,10 @@ func (b *SpotInstanceGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	for _, ig := range b.InstanceGroups {
 		name := b.AutoscalingGroupName(ig)
 
+		if ig.IsHybrid() {
+			continue
+		}
+
 		if featureflag.SpotinstHybrid.Enabled() {
 			if !HybridInstanceGroup(ig) {
 				klog.V(2).Infof("Skipping instance group: %q", name)
@@ -766,7 +770,11 @@ func (b *SpotInstanceGroupModelBuilder) buildLoadBalancers(c *fi.ModelBuilderCon
 	}
 
 	if ig.Spec.Role == kops.InstanceGroupRoleBastion {
-		loadBalancers = append(loadBalancers, b.LinkToCLB("bastion"))
+		if b.UseNetworkLoadBalancerForBastion() {
+			targetGroups = append(targetGroups, b.LinkToTargetGroup("bastion"))
+		} else {
+			loadBalancers = append(loadBalancers, b.LinkToCLB("bastion"))
+		}
 	}
 
 	for _, extLB := range ig.Spec.ExternalLoadBalancers {
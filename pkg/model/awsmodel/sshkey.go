@@ -17,6 +17,7 @@ limitations under the License.
 package awsmodel
 
 import (
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 )
@@ -41,7 +42,7 @@ func (b *SSHKeyModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	t := &awstasks.SSHKey{
 		Name:      fi.String(name),
 		Lifecycle: b.Lifecycle,
-		Tags:      b.CloudTags(b.ClusterName(), false),
+		Tags:      b.CloudTagsForResource(kops.CloudResourceTypeKeyPair, b.ClusterName(), false),
 		Shared:    fi.StringValue(b.Cluster.Spec.SSHKeyName) != "",
 	}
 	if len(b.SSHPublicKeys) >= 1 {
@@ -18,6 +18,7 @@ package azuremodel
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
@@ -85,6 +86,18 @@ func (b *VMScaleSetModelBuilder) buildVMScaleSetTask(
 		return nil, err
 	}
 
+	if t.Zones, err = getZones(&ig.Spec); err != nil {
+		return nil, err
+	}
+
+	if ig.Spec.MaxPrice != nil {
+		if err := setSpotPriority(t, &ig.Spec); err != nil {
+			return nil, err
+		}
+	}
+
+	t.AcceleratedNetworking = ig.Spec.AcceleratedNetworking
+
 	sp, err := getStorageProfile(&ig.Spec)
 	if err != nil {
 		return nil, err
@@ -93,10 +106,8 @@ func (b *VMScaleSetModelBuilder) buildVMScaleSetTask(
 		VirtualMachineScaleSetStorageProfile: sp,
 	}
 
-	if n := len(b.SSHPublicKeys); n > 0 {
-		if n > 1 {
-			return nil, fmt.Errorf("expected at most one SSH public key; found %d keys", n)
-		}
+	if len(b.SSHPublicKeys) > 0 {
+		// Additional keys are authorized by nodeup
 		t.SSHPublicKey = fi.String(string(b.SSHPublicKeys[0]))
 	}
 
@@ -157,6 +168,49 @@ func getCapacity(spec *kops.InstanceGroupSpec) (*int64, error) {
 	return fi.Int64(int64(minSize)), nil
 }
 
+// getZones returns the availability zones of the VM Scale Set; zones are of the form <location>-<zone-number>.
+func getZones(spec *kops.InstanceGroupSpec) ([]string, error) {
+	var zones []string
+	for _, zone := range spec.Zones {
+		l := strings.Split(zone, "-")
+		if len(l) != 2 {
+			return nil, fmt.Errorf("invalid Azure zone: %q", zone)
+		}
+		zones = append(zones, l[1])
+	}
+	return zones, nil
+}
+
+// setSpotPriority configures the VM Scale Set to use Spot virtual machines.
+// An empty max price caps the price at the on-demand price.
+func setSpotPriority(t *azuretasks.VMScaleSet, spec *kops.InstanceGroupSpec) error {
+	maxPrice := float64(-1)
+	if *spec.MaxPrice != "" {
+		var err error
+		maxPrice, err = strconv.ParseFloat(*spec.MaxPrice, 64)
+		if err != nil {
+			return fmt.Errorf("invalid maxPrice %q: %v", *spec.MaxPrice, err)
+		}
+	}
+
+	evictionPolicy := compute.Delete
+	if spec.InstanceInterruptionBehavior != nil {
+		switch *spec.InstanceInterruptionBehavior {
+		case "terminate":
+			evictionPolicy = compute.Delete
+		case "stop":
+			evictionPolicy = compute.Deallocate
+		default:
+			return fmt.Errorf("unsupported instanceInterruptionBehavior on Azure: %q", *spec.InstanceInterruptionBehavior)
+		}
+	}
+
+	t.Priority = to.StringPtr(string(compute.Spot))
+	t.EvictionPolicy = to.StringPtr(string(evictionPolicy))
+	t.MaxPrice = to.Float64Ptr(maxPrice)
+	return nil
+}
+
 func getStorageProfile(spec *kops.InstanceGroupSpec) (*compute.VirtualMachineScaleSetStorageProfile, error) {
 	var volumeSize int32
 	if spec.RootVolumeSize != nil {
@@ -169,9 +223,13 @@ func getStorageProfile(spec *kops.InstanceGroupSpec) (*compute.VirtualMachineSca
 		}
 	}
 
+	ephemeral := fi.BoolValue(spec.RootVolumeEphemeral)
+
 	var storageAccountType compute.StorageAccountTypes
 	if spec.RootVolumeType != nil {
 		storageAccountType = compute.StorageAccountTypes(*spec.RootVolumeType)
+	} else if ephemeral {
+		storageAccountType = compute.StorageAccountTypesStandardLRS
 	} else {
 		storageAccountType = compute.StorageAccountTypesPremiumLRS
 	}
@@ -181,18 +239,27 @@ func getStorageProfile(spec *kops.InstanceGroupSpec) (*compute.VirtualMachineSca
 		return nil, err
 	}
 
+	osDisk := &compute.VirtualMachineScaleSetOSDisk{
+		// TODO(kenji): Support Windows.
+		OsType:       compute.OperatingSystemTypes(compute.Linux),
+		CreateOption: compute.DiskCreateOptionTypesFromImage,
+		DiskSizeGB:   to.Int32Ptr(volumeSize),
+		ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
+			StorageAccountType: storageAccountType,
+		},
+		Caching: compute.CachingTypes(compute.HostCachingReadWrite),
+	}
+	if ephemeral {
+		// Ephemeral OS disks only support read-only caching
+		osDisk.DiffDiskSettings = &compute.DiffDiskSettings{
+			Option: compute.Local,
+		}
+		osDisk.Caching = compute.CachingTypes(compute.HostCachingReadOnly)
+	}
+
 	return &compute.VirtualMachineScaleSetStorageProfile{
 		ImageReference: imageReference,
-		OsDisk: &compute.VirtualMachineScaleSetOSDisk{
-			// TODO(kenji): Support Windows.
-			OsType:       compute.OperatingSystemTypes(compute.Linux),
-			CreateOption: compute.DiskCreateOptionTypesFromImage,
-			DiskSizeGB:   to.Int32Ptr(volumeSize),
-			ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
-				StorageAccountType: storageAccountType,
-			},
-			Caching: compute.CachingTypes(compute.HostCachingReadWrite),
-		},
+		OsDisk:         osDisk,
 	}, nil
 }
 
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/pkg/model"
 	"k8s.io/kops/pkg/model/defaults"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
 )
 
@@ -176,6 +177,34 @@ func TestGetStorageProfile(t *testing.T) {
 				},
 			},
 		},
+		{
+			spec: kops.InstanceGroupSpec{
+				Image:               "Canonical:UbuntuServer:18.04-LTS:latest",
+				Role:                kops.InstanceGroupRoleNode,
+				RootVolumeSize:      fi.Int32(64),
+				RootVolumeEphemeral: fi.Bool(true),
+			},
+			profile: &compute.VirtualMachineScaleSetStorageProfile{
+				ImageReference: &compute.ImageReference{
+					Publisher: to.StringPtr("Canonical"),
+					Offer:     to.StringPtr("UbuntuServer"),
+					Sku:       to.StringPtr("18.04-LTS"),
+					Version:   to.StringPtr("latest"),
+				},
+				OsDisk: &compute.VirtualMachineScaleSetOSDisk{
+					OsType:       compute.OperatingSystemTypes(compute.Linux),
+					CreateOption: compute.DiskCreateOptionTypesFromImage,
+					DiskSizeGB:   to.Int32Ptr(64),
+					ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
+						StorageAccountType: compute.StorageAccountTypesStandardLRS,
+					},
+					Caching: compute.CachingTypes(compute.HostCachingReadOnly),
+					DiffDiskSettings: &compute.DiffDiskSettings{
+						Option: compute.Local,
+					},
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -241,3 +270,103 @@ func TestParseImage(t *testing.T) {
 		})
 	}
 }
+
+func TestGetZones(t *testing.T) {
+	testCases := []struct {
+		zones    []string
+		success  bool
+		expected []string
+	}{
+		{
+			zones:    nil,
+			success:  true,
+			expected: nil,
+		},
+		{
+			zones:    []string{"eastus-1", "eastus-3"},
+			success:  true,
+			expected: []string{"1", "3"},
+		},
+		{
+			zones:   []string{"eastus"},
+			success: false,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			zones, err := getZones(&kops.InstanceGroupSpec{Zones: tc.zones})
+			if !tc.success {
+				if err == nil {
+					t.Fatalf("unexpected success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(zones, tc.expected) {
+				t.Fatalf("expected %v, but got %v", tc.expected, zones)
+			}
+		})
+	}
+}
+
+func TestSetSpotPriority(t *testing.T) {
+	testCases := []struct {
+		spec           kops.InstanceGroupSpec
+		success        bool
+		evictionPolicy compute.VirtualMachineEvictionPolicyTypes
+		maxPrice       float64
+	}{
+		{
+			spec:           kops.InstanceGroupSpec{MaxPrice: fi.String("")},
+			success:        true,
+			evictionPolicy: compute.Delete,
+			maxPrice:       -1,
+		},
+		{
+			spec: kops.InstanceGroupSpec{
+				MaxPrice:                     fi.String("0.05"),
+				InstanceInterruptionBehavior: fi.String("stop"),
+			},
+			success:        true,
+			evictionPolicy: compute.Deallocate,
+			maxPrice:       0.05,
+		},
+		{
+			spec:    kops.InstanceGroupSpec{MaxPrice: fi.String("cheap")},
+			success: false,
+		},
+		{
+			spec: kops.InstanceGroupSpec{
+				MaxPrice:                     fi.String("0.05"),
+				InstanceInterruptionBehavior: fi.String("hibernate"),
+			},
+			success: false,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			vmss := &azuretasks.VMScaleSet{}
+			err := setSpotPriority(vmss, &tc.spec)
+			if !tc.success {
+				if err == nil {
+					t.Fatalf("unexpected success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if a, e := fi.StringValue(vmss.Priority), string(compute.Spot); a != e {
+				t.Errorf("expected priority %s, but got %s", e, a)
+			}
+			if a, e := fi.StringValue(vmss.EvictionPolicy), string(tc.evictionPolicy); a != e {
+				t.Errorf("expected eviction policy %s, but got %s", e, a)
+			}
+			if a, e := *vmss.MaxPrice, tc.maxPrice; a != e {
+				t.Errorf("expected max price %f, but got %f", e, a)
+			}
+		})
+	}
+}
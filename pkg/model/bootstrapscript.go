@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"os"
 	"sort"
-	"strconv"
 	"strings"
 	"text/template"
 
@@ -41,6 +40,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/fitasks"
 	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/mirrors"
+	"k8s.io/kops/util/pkg/proxy"
 )
 
 type NodeUpConfigBuilder interface {
@@ -273,6 +273,16 @@ func (b *BootstrapScript) GetName() *string {
 	return &b.Name
 }
 
+// Script returns the bootstrap script, which can be opened once the task has run.
+func (b *BootstrapScript) Script() fi.Resource {
+	return &b.resource
+}
+
+// NodeupConfig returns the nodeup config, which can be opened once the task has run.
+func (b *BootstrapScript) NodeupConfig() fi.Resource {
+	return &b.nodeupConfig
+}
+
 func (b *BootstrapScript) GetDependencies(tasks map[string]fi.Task) []fi.Task {
 	var deps []fi.Task
 
@@ -439,19 +449,7 @@ func (b *BootstrapScript) createProxyEnv(ps *kops.EgressProxySpec) string {
 	var buffer bytes.Buffer
 
 	if ps != nil && ps.HTTPProxy.Host != "" {
-		var httpProxyURL string
-
-		// TODO double check that all the code does this
-		// TODO move this into a validate so we can enforce the string syntax
-		if !strings.HasPrefix(ps.HTTPProxy.Host, "http://") {
-			httpProxyURL = "http://"
-		}
-
-		if ps.HTTPProxy.Port != 0 {
-			httpProxyURL += ps.HTTPProxy.Host + ":" + strconv.Itoa(ps.HTTPProxy.Port)
-		} else {
-			httpProxyURL += ps.HTTPProxy.Host
-		}
+		httpProxyURL := proxy.ProxyURL(ps)
 
 		// Set env variables for base environment
 		buffer.WriteString(`echo "http_proxy=` + httpProxyURL + `" >> /etc/environment` + "\n")
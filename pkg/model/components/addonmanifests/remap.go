@@ -34,6 +34,7 @@ import (
 	"k8s.io/kops/pkg/model/components/addonmanifests/dnscontroller"
 	"k8s.io/kops/pkg/model/iam"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/proxy"
 )
 
 func RemapAddonManifest(addon *addonsapi.AddonSpec, context *model.KopsModelContext, assetBuilder *assets.AssetBuilder, manifest []byte) ([]byte, error) {
@@ -61,6 +62,11 @@ func RemapAddonManifest(addon *addonsapi.AddonSpec, context *model.KopsModelCont
 			return nil, fmt.Errorf("failed to add service account for %q: %w", name, err)
 		}
 
+		err = addProxyEnv(context, objects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add proxy environment for %q: %w", name, err)
+		}
+
 		b, err := objects.ToYAML()
 		if err != nil {
 			return nil, err
@@ -115,6 +121,61 @@ func addServiceAccountRole(context *model.KopsModelContext, objects kubemanifest
 	return nil
 }
 
+// addProxyEnv sets the egress proxy environment variables on the containers of the addon workloads,
+// keeping any value already set by the manifest
+func addProxyEnv(context *model.KopsModelContext, objects kubemanifest.ObjectList) error {
+	egressProxy := context.Cluster.Spec.EgressProxy
+	if egressProxy == nil || egressProxy.HTTPProxy.Host == "" {
+		return nil
+	}
+	proxyEnv := proxy.GetProxyEnvVars(egressProxy)
+
+	for _, object := range objects {
+		switch object.Kind() {
+		case "DaemonSet", "Deployment", "StatefulSet":
+		default:
+			continue
+		}
+		if object.APIVersion() != "apps/v1" {
+			continue
+		}
+		podSpec := &corev1.PodSpec{}
+
+		if err := object.Reparse(podSpec, "spec", "template", "spec"); err != nil {
+			return fmt.Errorf("failed to parse spec.template.spec from %s: %v", object.Kind(), err)
+		}
+
+		for i := range podSpec.InitContainers {
+			podSpec.InitContainers[i].Env = mergeEnv(podSpec.InitContainers[i].Env, proxyEnv)
+		}
+		for i := range podSpec.Containers {
+			podSpec.Containers[i].Env = mergeEnv(podSpec.Containers[i].Env, proxyEnv)
+		}
+
+		if err := object.Set(podSpec, "spec", "template", "spec"); err != nil {
+			return fmt.Errorf("failed to set object: %w", err)
+		}
+	}
+	return nil
+}
+
+// mergeEnv appends the variables of extra that are not already set in env
+func mergeEnv(env []corev1.EnvVar, extra []corev1.EnvVar) []corev1.EnvVar {
+	for _, e := range extra {
+		found := false
+		for _, existing := range env {
+			if existing.Name == e.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			env = append(env, e)
+		}
+	}
+	return env
+}
+
 func getWellknownServiceAccount(name string) iam.Subject {
 	switch name {
 	case "aws-load-balancer-controller":
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonmanifests
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+)
+
+func TestAddProxyEnv(t *testing.T) {
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: example/init
+      containers:
+      - name: example
+        image: example/example
+        env:
+        - name: no_proxy
+          value: custom
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: example
+data:
+  key: value
+`
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: no_proxy
+          value: custom
+        - name: http_proxy
+          value: http://proxy.example.com:3128
+        - name: https_proxy
+          value: http://proxy.example.com:3128
+        - name: NO_PROXY
+          value: 127.0.0.1,localhost
+        image: example/example
+        name: example
+        resources: {}
+      initContainers:
+      - env:
+        - name: http_proxy
+          value: http://proxy.example.com:3128
+        - name: https_proxy
+          value: http://proxy.example.com:3128
+        - name: NO_PROXY
+          value: 127.0.0.1,localhost
+        - name: no_proxy
+          value: 127.0.0.1,localhost
+        image: example/init
+        name: init
+        resources: {}
+
+---
+
+apiVersion: v1
+data:
+  key: value
+kind: ConfigMap
+metadata:
+  name: example
+`
+
+	context := &model.KopsModelContext{
+		IAMModelContext: iam.IAMModelContext{
+			Cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					EgressProxy: &kops.EgressProxySpec{
+						HTTPProxy: kops.HTTPProxy{
+							Host: "proxy.example.com",
+							Port: 3128,
+						},
+						ProxyExcludes: "127.0.0.1,localhost",
+					},
+				},
+			},
+		},
+	}
+
+	objects, err := kubemanifest.LoadObjectsFrom([]byte(manifest))
+	if err != nil {
+		t.Fatalf("error parsing manifest: %v", err)
+	}
+	if err := addProxyEnv(context, objects); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err := objects.ToYAML()
+	if err != nil {
+		t.Fatalf("error serializing manifest: %v", err)
+	}
+	if string(actual) != expected {
+		t.Errorf("unexpected manifest, actual:\n%s\nexpected:\n%s", actual, expected)
+	}
+}
@@ -18,6 +18,7 @@ package components
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -28,6 +29,10 @@ import (
 	"github.com/blang/semver/v4"
 )
 
+// PodSecurityAdmissionConfigFile is the path of the admission configuration that nodeup writes
+// for the PodSecurity admission plugin.
+const PodSecurityAdmissionConfigFile = "/srv/kubernetes/kube-apiserver/admission-configuration.yaml"
+
 // KubeAPIServerOptionsBuilder adds options for the apiserver to the model
 type KubeAPIServerOptionsBuilder struct {
 	*OptionsContext
@@ -75,6 +80,9 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 		if clusterSpec.Authentication.Kopeio != nil {
 			c.AuthenticationTokenWebhookConfigFile = fi.String("/etc/kubernetes/authn.config")
 		}
+		if clusterSpec.Authentication.OIDC != nil {
+			configureOIDC(c, clusterSpec.Authentication.OIDC)
+		}
 	}
 
 	if clusterSpec.Authorization == nil || clusterSpec.Authorization.IsEmpty() {
@@ -118,6 +126,11 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 		c.CloudProvider = "alicloud"
 	case kops.CloudProviderAzure:
 		c.CloudProvider = "azure"
+	case kops.CloudProviderOCI:
+		// There is no in-tree cloud provider for OCI.
+		c.CloudProvider = ""
+	case kops.CloudProviderMetal:
+		c.CloudProvider = ""
 	default:
 		return fmt.Errorf("unknown cloudprovider %q", clusterSpec.CloudProvider)
 	}
@@ -172,6 +185,46 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 		c.EnableAdmissionPlugins = append(c.EnableAdmissionPlugins, c.AppendAdmissionPlugins...)
 	}
 
+	if psa := clusterSpec.PodSecurityAdmission; psa != nil {
+		if psa.Enforce == "" {
+			psa.Enforce = kops.PodSecurityLevelPrivileged
+		}
+		if psa.Audit == "" {
+			psa.Audit = kops.PodSecurityLevelPrivileged
+		}
+		if psa.Warn == "" {
+			psa.Warn = kops.PodSecurityLevelPrivileged
+		}
+		if psa.Version == "" {
+			psa.Version = "latest"
+		}
+		if psa.ExemptNamespaces == nil {
+			psa.ExemptNamespaces = []string{"kube-system"}
+		}
+
+		// The PodSecurity admission plugin is enabled by default from Kubernetes 1.23
+		enabled := false
+		for _, plugin := range c.EnableAdmissionPlugins {
+			if plugin == "PodSecurity" {
+				enabled = true
+			}
+		}
+		if !enabled {
+			c.EnableAdmissionPlugins = append(c.EnableAdmissionPlugins, "PodSecurity")
+		}
+		if b.IsKubernetesLT("1.23") {
+			if c.FeatureGates == nil {
+				c.FeatureGates = make(map[string]string)
+			}
+			if _, found := c.FeatureGates["PodSecurity"]; !found {
+				c.FeatureGates["PodSecurity"] = "true"
+			}
+		}
+		if c.AdmissionControlConfigFile == "" {
+			c.AdmissionControlConfigFile = PodSecurityAdmissionConfigFile
+		}
+	}
+
 	// We make sure to disable AnonymousAuth
 	c.AnonymousAuth = fi.Bool(false)
 
@@ -226,3 +279,41 @@ func (b *KubeAPIServerOptionsBuilder) configureAggregation(clusterSpec *kops.Clu
 
 	return nil
 }
+
+// configureOIDC sets the OIDC flags of the apiserver from the authentication spec,
+// leaving any flag set explicitly on the kubeAPIServer spec untouched.
+func configureOIDC(c *kops.KubeAPIServerConfig, oidc *kops.OIDCAuthenticationSpec) {
+	if c.OIDCIssuerURL == nil {
+		c.OIDCIssuerURL = fi.String(oidc.IssuerURL)
+	}
+	if c.OIDCClientID == nil {
+		c.OIDCClientID = fi.String(stringOrDefault(oidc.ClientID, "kubernetes"))
+	}
+	if c.OIDCUsernameClaim == nil {
+		c.OIDCUsernameClaim = fi.String(stringOrDefault(oidc.UsernameClaim, "sub"))
+	}
+	if c.OIDCUsernamePrefix == nil {
+		c.OIDCUsernamePrefix = fi.String(stringOrDefault(oidc.UsernamePrefix, "oidc:"))
+	}
+	if c.OIDCGroupsClaim == nil {
+		c.OIDCGroupsClaim = fi.String(stringOrDefault(oidc.GroupsClaim, "groups"))
+	}
+	if c.OIDCGroupsPrefix == nil {
+		c.OIDCGroupsPrefix = fi.String(stringOrDefault(oidc.GroupsPrefix, "oidc:"))
+	}
+	if c.OIDCRequiredClaim == nil && len(oidc.RequiredClaims) != 0 {
+		var claims []string
+		for k, v := range oidc.RequiredClaims {
+			claims = append(claims, k+"="+v)
+		}
+		sort.Strings(claims)
+		c.OIDCRequiredClaim = claims
+	}
+}
+
+func stringOrDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
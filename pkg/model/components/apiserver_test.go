@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestConfigureOIDC(t *testing.T) {
+	c := &kops.KubeAPIServerConfig{
+		OIDCUsernameClaim: fi.String("email"),
+	}
+	configureOIDC(c, &kops.OIDCAuthenticationSpec{
+		IssuerURL:    "https://dex.example.com",
+		GroupsPrefix: "corp:",
+		RequiredClaims: map[string]string{
+			"hd":  "example.com",
+			"aud": "kubernetes",
+		},
+	})
+
+	expected := &kops.KubeAPIServerConfig{
+		OIDCIssuerURL:      fi.String("https://dex.example.com"),
+		OIDCClientID:       fi.String("kubernetes"),
+		OIDCUsernameClaim:  fi.String("email"),
+		OIDCUsernamePrefix: fi.String("oidc:"),
+		OIDCGroupsClaim:    fi.String("groups"),
+		OIDCGroupsPrefix:   fi.String("corp:"),
+		OIDCRequiredClaim:  []string{"aud=kubernetes", "hd=example.com"},
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("unexpected apiserver config: %+v", c)
+	}
+}
@@ -42,36 +42,48 @@ func (b *AWSCloudControllerManagerOptionsBuilder) BuildOptions(o interface{}) er
 		return nil
 	}
 
-	eccm.ClusterName = b.ClusterName
+	if eccm.ClusterName == "" {
+		eccm.ClusterName = b.ClusterName
+	}
 
-	eccm.ClusterCIDR = clusterSpec.NonMasqueradeCIDR
+	// The settings carried over from kube-controller-manager take precedence
+	if eccm.ClusterCIDR == "" {
+		eccm.ClusterCIDR = clusterSpec.NonMasqueradeCIDR
+	}
 
-	eccm.AllocateNodeCIDRs = fi.Bool(true)
-	eccm.ConfigureCloudRoutes = fi.Bool(false)
+	if eccm.AllocateNodeCIDRs == nil {
+		eccm.AllocateNodeCIDRs = fi.Bool(true)
+	}
 
 	// TODO: we want to consolidate this with the logic from KCM
+	configureCloudRoutes := false
 	networking := clusterSpec.Networking
 	if networking == nil {
-		eccm.ConfigureCloudRoutes = fi.Bool(true)
+		configureCloudRoutes = true
 	} else if networking.Kubenet != nil {
-		eccm.ConfigureCloudRoutes = fi.Bool(true)
+		configureCloudRoutes = true
 	} else if networking.GCE != nil {
-		eccm.ConfigureCloudRoutes = fi.Bool(false)
-		eccm.CIDRAllocatorType = fi.String("CloudAllocator")
+		configureCloudRoutes = false
+		if eccm.CIDRAllocatorType == nil {
+			eccm.CIDRAllocatorType = fi.String("CloudAllocator")
+		}
 
 		if eccm.ClusterCIDR == "" {
 			eccm.ClusterCIDR = clusterSpec.PodCIDR
 		}
 	} else if networking.External != nil {
-		eccm.ConfigureCloudRoutes = fi.Bool(false)
+		configureCloudRoutes = false
 	} else if UsesCNI(networking) {
-		eccm.ConfigureCloudRoutes = fi.Bool(false)
+		configureCloudRoutes = false
 	} else if networking.Kopeio != nil {
 		// Kopeio is based on kubenet / external
-		eccm.ConfigureCloudRoutes = fi.Bool(false)
+		configureCloudRoutes = false
 	} else {
 		return fmt.Errorf("no networking mode set")
 	}
+	if eccm.ConfigureCloudRoutes == nil {
+		eccm.ConfigureCloudRoutes = fi.Bool(configureCloudRoutes)
+	}
 
 	return nil
 }
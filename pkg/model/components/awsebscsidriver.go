@@ -52,6 +52,10 @@ func (b *AWSEBSCSIDriverOptionsBuilder) BuildOptions(o interface{}) error {
 		c.Version = fi.String(version)
 	}
 
+	if c.TagVolumesWithClaim == nil {
+		c.TagVolumesWithClaim = fi.Bool(true)
+	}
+
 	return nil
 
 }
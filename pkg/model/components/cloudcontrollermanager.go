@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// CloudControllerManagerOptionsBuilder adds the options of the external cloud controller manager
+// that apply to every cloud provider.
+type CloudControllerManagerOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &CloudControllerManagerOptionsBuilder{}
+
+// BuildOptions carries the settings of the in-tree cloud controllers over to the external cloud controller manager.
+func (b *CloudControllerManagerOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+
+	if kops.CloudProviderID(clusterSpec.CloudProvider) == kops.CloudProviderDO {
+		// DigitalOcean has no in-tree cloud provider, so its cloud controller manager is always deployed.
+		if clusterSpec.ExternalCloudControllerManager == nil {
+			clusterSpec.ExternalCloudControllerManager = &kops.CloudControllerManagerConfig{}
+		}
+		eccm := clusterSpec.ExternalCloudControllerManager
+		if eccm.LeaderElection == nil {
+			eccm.LeaderElection = &kops.LeaderElectionConfiguration{LeaderElect: fi.Bool(true)}
+		}
+		if eccm.UseServiceAccountCredentials == nil {
+			eccm.UseServiceAccountCredentials = fi.Bool(false)
+		}
+		return nil
+	}
+
+	eccm := clusterSpec.ExternalCloudControllerManager
+	if eccm == nil {
+		return nil
+	}
+
+	// The cloud controllers used to run in kube-controller-manager, whose settings they keep.
+	// The cloud provider specific builders, which run next, only default the settings that are still unset.
+	kcm := clusterSpec.KubeControllerManager
+	if kcm != nil {
+		if eccm.ConcurrentServiceSyncs == nil && kcm.ConcurrentServiceSyncs != nil {
+			eccm.ConcurrentServiceSyncs = fi.Int32(*kcm.ConcurrentServiceSyncs)
+		}
+		if eccm.ClusterCIDR == "" {
+			eccm.ClusterCIDR = kcm.ClusterCIDR
+		}
+		if eccm.AllocateNodeCIDRs == nil && kcm.AllocateNodeCIDRs != nil {
+			eccm.AllocateNodeCIDRs = fi.Bool(*kcm.AllocateNodeCIDRs)
+		}
+		if eccm.ConfigureCloudRoutes == nil && kcm.ConfigureCloudRoutes != nil {
+			eccm.ConfigureCloudRoutes = fi.Bool(*kcm.ConfigureCloudRoutes)
+		}
+		if eccm.CIDRAllocatorType == nil && kcm.CIDRAllocatorType != nil {
+			eccm.CIDRAllocatorType = fi.String(*kcm.CIDRAllocatorType)
+		}
+	}
+
+	// Leader migration is not enabled by default: it only helps if kube-controller-manager was already running with it
+	// before the external cloud controller manager is deployed, and needs to be turned off again once the migration is
+	// done, as described in the documentation of cloudControllerManager.
+
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func Test_Build_CCM_Builder(t *testing.T) {
+	grid := []struct {
+		name              string
+		cloudProvider     kops.CloudProviderID
+		kubernetesVersion string
+		eccm              *kops.CloudControllerManagerConfig
+		kcm               *kops.KubeControllerManagerConfig
+		expectedECCM      *kops.CloudControllerManagerConfig
+		expectedKCM       *kops.KubeControllerManagerConfig
+	}{
+		{
+			name:              "in-tree",
+			cloudProvider:     kops.CloudProviderAWS,
+			kubernetesVersion: "1.22.0",
+			kcm:               &kops.KubeControllerManagerConfig{},
+			expectedKCM:       &kops.KubeControllerManagerConfig{},
+		},
+		{
+			name:              "external carries over the in-tree settings",
+			cloudProvider:     kops.CloudProviderAWS,
+			kubernetesVersion: "1.21.0",
+			eccm:              &kops.CloudControllerManagerConfig{},
+			kcm: &kops.KubeControllerManagerConfig{
+				ConcurrentServiceSyncs: fi.Int32(10),
+				ClusterCIDR:            "100.96.0.0/11",
+				AllocateNodeCIDRs:      fi.Bool(true),
+				ConfigureCloudRoutes:   fi.Bool(false),
+				CIDRAllocatorType:      fi.String("RangeAllocator"),
+			},
+			expectedECCM: &kops.CloudControllerManagerConfig{
+				ConcurrentServiceSyncs: fi.Int32(10),
+				ClusterCIDR:            "100.96.0.0/11",
+				AllocateNodeCIDRs:      fi.Bool(true),
+				ConfigureCloudRoutes:   fi.Bool(false),
+				CIDRAllocatorType:      fi.String("RangeAllocator"),
+			},
+			expectedKCM: &kops.KubeControllerManagerConfig{
+				ConcurrentServiceSyncs: fi.Int32(10),
+				ClusterCIDR:            "100.96.0.0/11",
+				AllocateNodeCIDRs:      fi.Bool(true),
+				ConfigureCloudRoutes:   fi.Bool(false),
+				CIDRAllocatorType:      fi.String("RangeAllocator"),
+			},
+		},
+		{
+			name:              "external keeps its own settings and does not default leader migration",
+			cloudProvider:     kops.CloudProviderAWS,
+			kubernetesVersion: "1.22.0",
+			eccm: &kops.CloudControllerManagerConfig{
+				ConcurrentServiceSyncs: fi.Int32(5),
+				ConfigureCloudRoutes:   fi.Bool(true),
+			},
+			kcm: &kops.KubeControllerManagerConfig{
+				ConcurrentServiceSyncs: fi.Int32(10),
+				ConfigureCloudRoutes:   fi.Bool(false),
+			},
+			expectedECCM: &kops.CloudControllerManagerConfig{
+				ConcurrentServiceSyncs: fi.Int32(5),
+				ConfigureCloudRoutes:   fi.Bool(true),
+			},
+			expectedKCM: &kops.KubeControllerManagerConfig{
+				ConcurrentServiceSyncs: fi.Int32(10),
+				ConfigureCloudRoutes:   fi.Bool(false),
+			},
+		},
+		{
+			name:              "leader migration disabled",
+			cloudProvider:     kops.CloudProviderOpenstack,
+			kubernetesVersion: "1.22.0",
+			eccm: &kops.CloudControllerManagerConfig{
+				EnableLeaderMigration: fi.Bool(false),
+			},
+			kcm: &kops.KubeControllerManagerConfig{
+				EnableLeaderMigration: fi.Bool(false),
+			},
+			expectedECCM: &kops.CloudControllerManagerConfig{
+				EnableLeaderMigration: fi.Bool(false),
+			},
+			expectedKCM: &kops.KubeControllerManagerConfig{
+				EnableLeaderMigration: fi.Bool(false),
+			},
+		},
+		{
+			name:              "digitalocean",
+			cloudProvider:     kops.CloudProviderDO,
+			kubernetesVersion: "1.22.0",
+			kcm:               &kops.KubeControllerManagerConfig{},
+			expectedECCM: &kops.CloudControllerManagerConfig{
+				LeaderElection:               &kops.LeaderElectionConfiguration{LeaderElect: fi.Bool(true)},
+				UseServiceAccountCredentials: fi.Bool(false),
+			},
+			expectedKCM: &kops.KubeControllerManagerConfig{},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			version, err := util.ParseKubernetesVersion(g.kubernetesVersion)
+			if err != nil {
+				t.Fatalf("unexpected error from ParseKubernetesVersion %s: %v", g.kubernetesVersion, err)
+			}
+
+			spec := &kops.ClusterSpec{
+				CloudProvider:                  string(g.cloudProvider),
+				KubernetesVersion:              g.kubernetesVersion,
+				ExternalCloudControllerManager: g.eccm,
+				KubeControllerManager:          g.kcm,
+			}
+
+			b := &CloudControllerManagerOptionsBuilder{
+				OptionsContext: &OptionsContext{
+					KubernetesVersion: *version,
+				},
+			}
+			if err := b.BuildOptions(spec); err != nil {
+				t.Fatalf("unexpected error from BuildOptions: %v", err)
+			}
+
+			assert.Equal(t, g.expectedECCM, spec.ExternalCloudControllerManager)
+			assert.Equal(t, g.expectedKCM, spec.KubeControllerManager)
+		})
+	}
+}
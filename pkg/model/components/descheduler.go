@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// DeschedulerOptionsBuilder adds options for the descheduler to the model
+type DeschedulerOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &DeschedulerOptionsBuilder{}
+
+func (b *DeschedulerOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	descheduler := clusterSpec.Descheduler
+	if descheduler == nil || !fi.BoolValue(descheduler.Enabled) {
+		return nil
+	}
+
+	if descheduler.Image == nil {
+		image := "k8s.gcr.io/descheduler/descheduler:v0.21.0"
+		v, err := util.ParseKubernetesVersion(clusterSpec.KubernetesVersion)
+		if err == nil {
+			switch v.Minor {
+			case 20:
+				image = "k8s.gcr.io/descheduler/descheduler:v0.20.0"
+			case 19:
+				image = "k8s.gcr.io/descheduler/descheduler:v0.19.0"
+			case 18:
+				image = "k8s.gcr.io/descheduler/descheduler:v0.18.0"
+			}
+		}
+		descheduler.Image = fi.String(image)
+	}
+
+	if descheduler.Schedule == nil {
+		descheduler.Schedule = fi.String("*/10 * * * *")
+	}
+	if len(descheduler.Profiles) == 0 {
+		descheduler.Profiles = []string{kops.DeschedulerProfileBalance}
+	}
+
+	if descheduler.NodeUtilization == nil {
+		descheduler.NodeUtilization = &kops.DeschedulerNodeUtilization{}
+	}
+	if descheduler.NodeUtilization.Thresholds == nil {
+		descheduler.NodeUtilization.Thresholds = map[string]int32{"cpu": 20, "memory": 20, "pods": 20}
+	}
+	if descheduler.NodeUtilization.TargetThresholds == nil {
+		descheduler.NodeUtilization.TargetThresholds = map[string]int32{"cpu": 50, "memory": 50, "pods": 50}
+	}
+
+	return nil
+}
@@ -39,6 +39,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
 	"k8s.io/kops/util/pkg/env"
@@ -447,6 +449,26 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec) (*v1.Pod
 			}
 			config.VolumeNameTag = openstack.TagNameEtcdClusterPrefix + etcdCluster.Name
 
+		case kops.CloudProviderOCI:
+			config.VolumeProvider = "oci"
+
+			// "." and "/" are not allowed in OCI tag keys.
+			config.VolumeTag = []string{
+				fmt.Sprintf("%s=%s", oci.TagClusterName, b.Cluster.Name),
+				oci.TagNameEtcdClusterPrefix + etcdCluster.Name,
+				fmt.Sprintf("%s=%s", oci.TagKopsRole, oci.TagRoleMaster),
+			}
+			config.VolumeNameTag = oci.TagNameEtcdClusterPrefix + etcdCluster.Name
+
+		case kops.CloudProviderMetal:
+			// The volumes are directories under /mnt/disks on the control plane machines,
+			// which kops toolbox enroll creates before running nodeup.
+			config.VolumeProvider = "external"
+
+			config.VolumeTag = []string{
+				metal.EtcdVolumePrefix(b.Cluster.Name, etcdCluster.Name),
+			}
+
 		default:
 			return nil, fmt.Errorf("CloudProvider %q not supported with etcd-manager", b.Cluster.Spec.CloudProvider)
 		}
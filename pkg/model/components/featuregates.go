@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"strconv"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// FeatureGatesOptionsBuilder applies the feature gates of the cluster to the Kubernetes components
+type FeatureGatesOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &FeatureGatesOptionsBuilder{}
+
+// BuildOptions copies spec.featureGates into the feature gates of the API server, controller manager, scheduler,
+// external cloud controller manager, kube-proxy and kubelet, unless the component sets the gate itself. It runs
+// before the builders of the components, so that the gates they default are overridden by the cluster too.
+func (b *FeatureGatesOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	if len(clusterSpec.FeatureGates) == 0 {
+		return nil
+	}
+
+	if clusterSpec.KubeAPIServer == nil {
+		clusterSpec.KubeAPIServer = &kops.KubeAPIServerConfig{}
+	}
+	clusterSpec.KubeAPIServer.FeatureGates = mergeFeatureGates(clusterSpec.KubeAPIServer.FeatureGates, clusterSpec.FeatureGates)
+
+	if clusterSpec.KubeControllerManager == nil {
+		clusterSpec.KubeControllerManager = &kops.KubeControllerManagerConfig{}
+	}
+	clusterSpec.KubeControllerManager.FeatureGates = mergeFeatureGates(clusterSpec.KubeControllerManager.FeatureGates, clusterSpec.FeatureGates)
+
+	if clusterSpec.KubeScheduler == nil {
+		clusterSpec.KubeScheduler = &kops.KubeSchedulerConfig{}
+	}
+	clusterSpec.KubeScheduler.FeatureGates = mergeFeatureGates(clusterSpec.KubeScheduler.FeatureGates, clusterSpec.FeatureGates)
+
+	// The external cloud controller manager is only run when it is configured.
+	if clusterSpec.ExternalCloudControllerManager != nil {
+		clusterSpec.ExternalCloudControllerManager.FeatureGates = mergeFeatureGates(clusterSpec.ExternalCloudControllerManager.FeatureGates, clusterSpec.FeatureGates)
+	}
+
+	if clusterSpec.KubeProxy == nil {
+		clusterSpec.KubeProxy = &kops.KubeProxyConfig{}
+	}
+	clusterSpec.KubeProxy.FeatureGates = mergeFeatureGates(clusterSpec.KubeProxy.FeatureGates, clusterSpec.FeatureGates)
+
+	// The kubelet of the control plane is merged with this one, and keeps any gate it sets itself.
+	if clusterSpec.Kubelet == nil {
+		clusterSpec.Kubelet = &kops.KubeletConfigSpec{}
+	}
+	clusterSpec.Kubelet.FeatureGates = mergeFeatureGates(clusterSpec.Kubelet.FeatureGates, clusterSpec.FeatureGates)
+
+	return nil
+}
+
+// mergeFeatureGates adds the gates that are not already set to the feature gates of a component.
+func mergeFeatureGates(componentGates map[string]string, gates map[string]bool) map[string]string {
+	if componentGates == nil {
+		componentGates = make(map[string]string)
+	}
+	for name, enabled := range gates {
+		if _, found := componentGates[name]; !found {
+			componentGates[name] = strconv.FormatBool(enabled)
+		}
+	}
+	return componentGates
+}
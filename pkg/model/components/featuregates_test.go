@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func Test_Build_FeatureGates_Builder(t *testing.T) {
+	spec := &kops.ClusterSpec{
+		FeatureGates: map[string]bool{
+			"GracefulNodeShutdown": true,
+			"TopologyAwareHints":   false,
+		},
+		KubeAPIServer: &kops.KubeAPIServerConfig{
+			FeatureGates: map[string]string{
+				"TopologyAwareHints": "true",
+			},
+		},
+		Kubelet: &kops.KubeletConfigSpec{
+			FeatureGates: map[string]string{
+				"NodeSwap": "true",
+			},
+		},
+		ExternalCloudControllerManager: &kops.CloudControllerManagerConfig{},
+	}
+
+	b := &FeatureGatesOptionsBuilder{OptionsContext: &OptionsContext{}}
+	if err := b.BuildOptions(spec); err != nil {
+		t.Fatalf("unexpected error from BuildOptions: %v", err)
+	}
+
+	assert.Equal(t, map[string]string{"GracefulNodeShutdown": "true", "TopologyAwareHints": "true"}, spec.KubeAPIServer.FeatureGates)
+	assert.Equal(t, map[string]string{"GracefulNodeShutdown": "true", "TopologyAwareHints": "false"}, spec.KubeControllerManager.FeatureGates)
+	assert.Equal(t, map[string]string{"GracefulNodeShutdown": "true", "TopologyAwareHints": "false"}, spec.KubeScheduler.FeatureGates)
+	assert.Equal(t, map[string]string{"GracefulNodeShutdown": "true", "NodeSwap": "true", "TopologyAwareHints": "false"}, spec.Kubelet.FeatureGates)
+	assert.Equal(t, map[string]string{"GracefulNodeShutdown": "true", "TopologyAwareHints": "false"}, spec.ExternalCloudControllerManager.FeatureGates)
+	assert.Equal(t, map[string]string{"GracefulNodeShutdown": "true", "TopologyAwareHints": "false"}, spec.KubeProxy.FeatureGates)
+	assert.Nil(t, spec.MasterKubelet)
+}
+
+func Test_Build_FeatureGates_Builder_NoCloudControllerManager(t *testing.T) {
+	spec := &kops.ClusterSpec{
+		FeatureGates: map[string]bool{
+			"GracefulNodeShutdown": true,
+		},
+	}
+
+	b := &FeatureGatesOptionsBuilder{OptionsContext: &OptionsContext{}}
+	if err := b.BuildOptions(spec); err != nil {
+		t.Fatalf("unexpected error from BuildOptions: %v", err)
+	}
+
+	assert.Nil(t, spec.ExternalCloudControllerManager)
+}
+
+func Test_Build_FeatureGates_Builder_Empty(t *testing.T) {
+	spec := &kops.ClusterSpec{}
+
+	b := &FeatureGatesOptionsBuilder{OptionsContext: &OptionsContext{}}
+	if err := b.BuildOptions(spec); err != nil {
+		t.Fatalf("unexpected error from BuildOptions: %v", err)
+	}
+
+	assert.Equal(t, &kops.ClusterSpec{}, spec)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// FIPSTLSCipherSuites are the TLS cipher suites approved by FIPS 140-2.
+var FIPSTLSCipherSuites = []string{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+}
+
+// FIPSOptionsBuilder restricts the TLS of the API server and the kubelet
+// to FIPS-approved cipher suites when the hosts of the cluster run in FIPS mode.
+// Options that are set explicitly in the cluster spec are left unchanged.
+type FIPSOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &FIPSOptionsBuilder{}
+
+func (b *FIPSOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	if !fi.BoolValue(clusterSpec.HostFIPSMode) {
+		return nil
+	}
+
+	if clusterSpec.KubeAPIServer == nil {
+		clusterSpec.KubeAPIServer = &kops.KubeAPIServerConfig{}
+	}
+	if clusterSpec.KubeAPIServer.TLSCipherSuites == nil {
+		clusterSpec.KubeAPIServer.TLSCipherSuites = FIPSTLSCipherSuites
+	}
+	if clusterSpec.KubeAPIServer.TLSMinVersion == "" {
+		clusterSpec.KubeAPIServer.TLSMinVersion = "VersionTLS12"
+	}
+
+	// The kubelet of the control plane inherits these options, as MasterKubelet is merged over Kubelet
+	if clusterSpec.Kubelet == nil {
+		clusterSpec.Kubelet = &kops.KubeletConfigSpec{}
+	}
+	if clusterSpec.Kubelet.TLSCipherSuites == nil {
+		clusterSpec.Kubelet.TLSCipherSuites = FIPSTLSCipherSuites
+	}
+	if clusterSpec.Kubelet.TLSMinVersion == "" {
+		clusterSpec.Kubelet.TLSMinVersion = "VersionTLS12"
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+func Test_Build_FIPS(t *testing.T) {
+	c := buildCluster()
+	c.Spec.HostFIPSMode = fi.Bool(true)
+	c.Spec.SecurityProfile = api.SecurityProfileCIS
+	c.Spec.Kubelet = &api.KubeletConfigSpec{
+		TLSMinVersion: "VersionTLS13",
+	}
+
+	for _, b := range []loader.OptionsBuilder{
+		&FIPSOptionsBuilder{&OptionsContext{}},
+		&SecurityProfileOptionsBuilder{&OptionsContext{}},
+	} {
+		if err := b.BuildOptions(&c.Spec); err != nil {
+			t.Fatalf("unexpected error from BuildOptions: %v", err)
+		}
+	}
+
+	if !reflect.DeepEqual(c.Spec.KubeAPIServer.TLSCipherSuites, FIPSTLSCipherSuites) {
+		t.Errorf("unexpected apiserver cipher suites %v", c.Spec.KubeAPIServer.TLSCipherSuites)
+	}
+	if !reflect.DeepEqual(c.Spec.Kubelet.TLSCipherSuites, FIPSTLSCipherSuites) {
+		t.Errorf("unexpected kubelet cipher suites %v", c.Spec.Kubelet.TLSCipherSuites)
+	}
+	if c.Spec.KubeAPIServer.TLSMinVersion != "VersionTLS12" {
+		t.Errorf("unexpected apiserver min TLS version %q", c.Spec.KubeAPIServer.TLSMinVersion)
+	}
+	if c.Spec.Kubelet.TLSMinVersion != "VersionTLS13" {
+		t.Errorf("expected explicit kubelet min TLS version to be kept, got %q", c.Spec.Kubelet.TLSMinVersion)
+	}
+}
+
+func Test_Build_FIPS_Disabled(t *testing.T) {
+	c := buildCluster()
+
+	b := &FIPSOptionsBuilder{&OptionsContext{}}
+	if err := b.BuildOptions(&c.Spec); err != nil {
+		t.Fatalf("unexpected error from BuildOptions: %v", err)
+	}
+
+	if c.Spec.KubeAPIServer != nil && c.Spec.KubeAPIServer.TLSCipherSuites != nil {
+		t.Errorf("unexpected apiserver cipher suites %v", c.Spec.KubeAPIServer.TLSCipherSuites)
+	}
+}
@@ -97,6 +97,13 @@ func (b *KubeControllerManagerOptionsBuilder) BuildOptions(o interface{}) error
 	case kops.CloudProviderAzure:
 		kcm.CloudProvider = "azure"
 
+	case kops.CloudProviderOCI:
+		// There is no in-tree cloud provider for OCI.
+		kcm.CloudProvider = ""
+
+	case kops.CloudProviderMetal:
+		kcm.CloudProvider = ""
+
 	default:
 		return fmt.Errorf("unknown cloudprovider %q", clusterSpec.CloudProvider)
 	}
@@ -220,6 +220,11 @@ func (b *KubeletOptionsBuilder) BuildOptions(o interface{}) error {
 		}
 	}
 
+	// Use the cgroup driver of containerd when it is set, so that they match
+	if clusterSpec.ContainerRuntime == "containerd" && clusterSpec.Containerd != nil && clusterSpec.Containerd.CgroupDriver != nil && clusterSpec.Kubelet.CgroupDriver == "" {
+		clusterSpec.Kubelet.CgroupDriver = *clusterSpec.Containerd.CgroupDriver
+	}
+
 	// Set systemd as the default cgroup driver for kubelet from k8s 1.20
 	if b.IsKubernetesGTE("1.20") && clusterSpec.Kubelet.CgroupDriver == "" {
 		clusterSpec.Kubelet.CgroupDriver = "systemd"
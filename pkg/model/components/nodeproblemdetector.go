@@ -17,7 +17,10 @@ limitations under the License.
 package components
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/loader"
@@ -65,5 +68,29 @@ func (b *NodeProblemDetectorOptionsBuilder) BuildOptions(o interface{}) error {
 		npd.Image = fi.String("k8s.gcr.io/node-problem-detector/node-problem-detector:v0.8.8")
 	}
 
+	if npd.Remediation != nil {
+		remediation := npd.Remediation
+
+		if remediation.Enabled == nil {
+			remediation.Enabled = fi.Bool(false)
+		}
+
+		if len(remediation.Conditions) == 0 {
+			remediation.Conditions = []string{"KernelDeadlock", "ReadonlyFilesystem"}
+		}
+
+		if remediation.Threshold == nil {
+			remediation.Threshold = &metav1.Duration{Duration: 10 * time.Minute}
+		}
+
+		if remediation.MaxConcurrent == nil {
+			remediation.MaxConcurrent = fi.Int32(1)
+		}
+
+		if remediation.Recycle == nil {
+			remediation.Recycle = fi.Bool(true)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// CISAuditPolicyFile is the path of the audit policy that nodeup writes for the CIS security profile,
+// when no other audit policy is configured.
+const CISAuditPolicyFile = "/srv/kubernetes/kube-apiserver/audit-policy.yaml"
+
+// CISAuditLogPath is the path of the audit log of the CIS security profile, when no other path is configured.
+// The log has a directory of its own, as kube-apiserver can only rotate it with the whole directory mounted read-write.
+const CISAuditLogPath = "/var/log/kube-apiserver/audit.log"
+
+// CISTLSCipherSuites are the strong TLS cipher suites recommended by the CIS Kubernetes Benchmark.
+var CISTLSCipherSuites = []string{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+}
+
+// SecurityProfileOptionsBuilder defaults the options of the control plane components and the kubelet
+// to the values recommended by the security profile of the cluster.
+// Options that are set explicitly in the cluster spec are left unchanged.
+type SecurityProfileOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &SecurityProfileOptionsBuilder{}
+
+func (b *SecurityProfileOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	if clusterSpec.SecurityProfile != kops.SecurityProfileCIS {
+		return nil
+	}
+
+	if clusterSpec.KubeAPIServer == nil {
+		clusterSpec.KubeAPIServer = &kops.KubeAPIServerConfig{}
+	}
+	apiserver := clusterSpec.KubeAPIServer
+	if apiserver.EnableProfiling == nil {
+		apiserver.EnableProfiling = fi.Bool(false)
+	}
+	if apiserver.AuditLogPath == nil {
+		apiserver.AuditLogPath = fi.String(CISAuditLogPath)
+	}
+	if apiserver.AuditLogMaxAge == nil {
+		apiserver.AuditLogMaxAge = fi.Int32(30)
+	}
+	if apiserver.AuditLogMaxBackups == nil {
+		apiserver.AuditLogMaxBackups = fi.Int32(10)
+	}
+	if apiserver.AuditLogMaxSize == nil {
+		apiserver.AuditLogMaxSize = fi.Int32(100)
+	}
+	if apiserver.AuditPolicyFile == "" {
+		apiserver.AuditPolicyFile = CISAuditPolicyFile
+	}
+	if apiserver.TLSCipherSuites == nil {
+		apiserver.TLSCipherSuites = CISTLSCipherSuites
+	}
+
+	if clusterSpec.KubeControllerManager == nil {
+		clusterSpec.KubeControllerManager = &kops.KubeControllerManagerConfig{}
+	}
+	kcm := clusterSpec.KubeControllerManager
+	if kcm.EnableProfiling == nil {
+		kcm.EnableProfiling = fi.Bool(false)
+	}
+	if kcm.TerminatedPodGCThreshold == nil {
+		// The default of kube-controller-manager, but the benchmark requires the flag to be set
+		kcm.TerminatedPodGCThreshold = fi.Int32(12500)
+	}
+	if kcm.UseServiceAccountCredentials == nil {
+		kcm.UseServiceAccountCredentials = fi.Bool(true)
+	}
+
+	if clusterSpec.KubeScheduler == nil {
+		clusterSpec.KubeScheduler = &kops.KubeSchedulerConfig{}
+	}
+	if clusterSpec.KubeScheduler.EnableProfiling == nil {
+		clusterSpec.KubeScheduler.EnableProfiling = fi.Bool(false)
+	}
+
+	// The kubelet of the control plane inherits these options, as MasterKubelet is merged over Kubelet
+	if clusterSpec.Kubelet == nil {
+		clusterSpec.Kubelet = &kops.KubeletConfigSpec{}
+	}
+	kubelet := clusterSpec.Kubelet
+	if kubelet.AnonymousAuth == nil {
+		kubelet.AnonymousAuth = fi.Bool(false)
+	}
+	if kubelet.AuthorizationMode == "" {
+		kubelet.AuthorizationMode = "Webhook"
+	}
+	if kubelet.AuthenticationTokenWebhook == nil {
+		kubelet.AuthenticationTokenWebhook = fi.Bool(true)
+	}
+	if kubelet.ReadOnlyPort == nil {
+		kubelet.ReadOnlyPort = fi.Int32(0)
+	}
+	if kubelet.ProtectKernelDefaults == nil {
+		kubelet.ProtectKernelDefaults = fi.Bool(true)
+	}
+	if kubelet.TLSCipherSuites == nil {
+		kubelet.TLSCipherSuites = CISTLSCipherSuites
+	}
+
+	return nil
+}
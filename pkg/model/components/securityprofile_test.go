@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"testing"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func Test_Build_SecurityProfile_CIS(t *testing.T) {
+	c := buildCluster()
+	c.Spec.SecurityProfile = api.SecurityProfileCIS
+	c.Spec.Kubelet = &api.KubeletConfigSpec{
+		ReadOnlyPort: fi.Int32(10255),
+	}
+
+	b := &SecurityProfileOptionsBuilder{&OptionsContext{}}
+	if err := b.BuildOptions(&c.Spec); err != nil {
+		t.Fatalf("unexpected error from BuildOptions: %v", err)
+	}
+
+	if fi.BoolValue(c.Spec.KubeAPIServer.EnableProfiling) || fi.BoolValue(c.Spec.KubeControllerManager.EnableProfiling) || fi.BoolValue(c.Spec.KubeScheduler.EnableProfiling) {
+		t.Errorf("expected profiling to be disabled")
+	}
+	if c.Spec.KubeAPIServer.AuditPolicyFile != CISAuditPolicyFile {
+		t.Errorf("unexpected audit policy file %q", c.Spec.KubeAPIServer.AuditPolicyFile)
+	}
+	if fi.StringValue(c.Spec.KubeAPIServer.AuditLogPath) != CISAuditLogPath {
+		t.Errorf("unexpected audit log path %q", fi.StringValue(c.Spec.KubeAPIServer.AuditLogPath))
+	}
+	if fi.Int32Value(c.Spec.KubeAPIServer.AuditLogMaxAge) != 30 {
+		t.Errorf("unexpected audit log max age %d", fi.Int32Value(c.Spec.KubeAPIServer.AuditLogMaxAge))
+	}
+	if c.Spec.Kubelet.AuthorizationMode != "Webhook" || !fi.BoolValue(c.Spec.Kubelet.ProtectKernelDefaults) {
+		t.Errorf("unexpected kubelet options %+v", c.Spec.Kubelet)
+	}
+	if fi.Int32Value(c.Spec.Kubelet.ReadOnlyPort) != 10255 {
+		t.Errorf("expected explicit kubelet readOnlyPort to be kept, got %d", fi.Int32Value(c.Spec.Kubelet.ReadOnlyPort))
+	}
+}
+
+func Test_Build_SecurityProfile_None(t *testing.T) {
+	c := buildCluster()
+
+	b := &SecurityProfileOptionsBuilder{&OptionsContext{}}
+	if err := b.BuildOptions(&c.Spec); err != nil {
+		t.Fatalf("unexpected error from BuildOptions: %v", err)
+	}
+
+	if c.Spec.KubeAPIServer != nil && c.Spec.KubeAPIServer.AuditPolicyFile != "" {
+		t.Errorf("unexpected audit policy file %q", c.Spec.KubeAPIServer.AuditPolicyFile)
+	}
+}
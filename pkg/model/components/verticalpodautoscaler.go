@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// VerticalPodAutoscalerOptionsBuilder adds options for the vertical pod autoscaler to the model
+type VerticalPodAutoscalerOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &VerticalPodAutoscalerOptionsBuilder{}
+
+func (b *VerticalPodAutoscalerOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	vpa := clusterSpec.VerticalPodAutoscaler
+	if vpa == nil || !fi.BoolValue(vpa.Enabled) {
+		return nil
+	}
+
+	if vpa.Version == nil {
+		vpa.Version = fi.String("0.9.2")
+	}
+	if vpa.CertificateValidity == nil {
+		vpa.CertificateValidity = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+	}
+
+	return nil
+}
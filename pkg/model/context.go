@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/model/components"
+	"k8s.io/kops/pkg/model/defaults"
 	"k8s.io/kops/pkg/model/iam"
 	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
 	"k8s.io/kops/pkg/nodelabels"
@@ -39,7 +40,10 @@ import (
 )
 
 const (
-	clusterAutoscalerNodeTemplateTaint = "k8s.io/cluster-autoscaler/node-template/taint/"
+	clusterAutoscalerNodeTemplateTaint    = "k8s.io/cluster-autoscaler/node-template/taint/"
+	clusterAutoscalerNodeTemplateResource = "k8s.io/cluster-autoscaler/node-template/resources/"
+	clusterAutoscalerAutoDiscoveryEnabled = "k8s.io/cluster-autoscaler/enabled"
+	clusterAutoscalerAutoDiscoveryCluster = "k8s.io/cluster-autoscaler/"
 )
 
 // KopsModelContext is the kops model
@@ -185,6 +189,70 @@ func (b *KopsModelContext) CloudTagsForInstanceGroup(ig *kops.InstanceGroup) (ma
 	return labels, nil
 }
 
+// InstanceCloudTagsForInstanceGroup computes the tags to apply to the launch configuration of the instances
+// in the specified InstanceGroup. When kops-controller reconciles the node labels and taints of the InstanceGroup,
+// the cluster autoscaler tags for them are left out, except those identifying the role and InstanceGroup of the
+// node, so that changing them does not replace the instances. The tags of the autoscaling group keep them all.
+func (b *KopsModelContext) InstanceCloudTagsForInstanceGroup(ig *kops.InstanceGroup) (map[string]string, error) {
+	tags, err := b.CloudTagsForInstanceGroup(ig)
+	if err != nil {
+		return nil, err
+	}
+	if !model.UseNodeLabelReconciliation(b.Cluster, ig) {
+		return tags, nil
+	}
+
+	for k := range tags {
+		switch {
+		case strings.HasPrefix(k, clusterAutoscalerNodeTemplateTaint):
+			delete(tags, k)
+		case strings.HasPrefix(k, nodeidentityaws.ClusterAutoscalerNodeTemplateLabel):
+			switch strings.TrimPrefix(k, nodeidentityaws.ClusterAutoscalerNodeTemplateLabel) {
+			case nodelabels.RoleLabelName15, nodelabels.RoleLabelNode16, kops.NodeLabelInstanceGroup:
+			default:
+				delete(tags, k)
+			}
+		}
+	}
+	return tags, nil
+}
+
+// ClusterAutoscalerTagsForInstanceGroup returns the tags used by the managed cluster autoscaler addon to discover
+// the instance group and to scale it up from zero without a running node to build a template from.
+// The autoscaler only reads them from the autoscaling group, so they are not meant for the instances.
+func (b *KopsModelContext) ClusterAutoscalerTagsForInstanceGroup(ig *kops.InstanceGroup) (map[string]string, error) {
+	cas := b.Cluster.Spec.ClusterAutoscaler
+	if cas == nil || !fi.BoolValue(cas.Enabled) {
+		return nil, nil
+	}
+	if ig.Spec.Role != kops.InstanceGroupRoleNode || (ig.Spec.Autoscale != nil && !*ig.Spec.Autoscale) {
+		return nil, nil
+	}
+
+	labels := make(map[string]string)
+	labels[clusterAutoscalerAutoDiscoveryEnabled] = ""
+	labels[clusterAutoscalerAutoDiscoveryCluster+b.ClusterName()] = ""
+
+	// Taints without a value are only known to the autoscaler if they have a tag as well
+	for _, v := range ig.Spec.Taints {
+		if strings.Contains(v, "=") {
+			continue
+		}
+		splits := strings.SplitN(v, ":", 2)
+		if len(splits) > 1 {
+			labels[clusterAutoscalerNodeTemplateTaint+splits[0]] = ":" + splits[1]
+		}
+	}
+
+	rootVolumeSize, err := defaults.InstanceGroupVolumeSize(ig)
+	if err != nil {
+		return nil, err
+	}
+	labels[clusterAutoscalerNodeTemplateResource+"ephemeral-storage"] = fmt.Sprintf("%dGi", rootVolumeSize)
+
+	return labels, nil
+}
+
 // CloudTags computes the tags to apply to a normal cloud resource with the specified name
 func (b *KopsModelContext) CloudTags(name string, shared bool) map[string]string {
 	tags := make(map[string]string)
@@ -225,6 +293,69 @@ func (b *KopsModelContext) CloudTags(name string, shared bool) map[string]string
 	return tags
 }
 
+// CloudTagsForResource computes the tags to apply to a cloud resource of the specified type and name,
+// including the tags of the matching cloud label policies. subnets are the names of the cluster subnets
+// the resource belongs to, if any.
+func (b *KopsModelContext) CloudTagsForResource(resourceType kops.CloudResourceType, name string, shared bool, subnets ...string) map[string]string {
+	tags := b.CloudTags(name, shared)
+	if shared {
+		// We don't apply user-specified labels to shared resources
+		return tags
+	}
+	for k, v := range b.CloudLabelPolicyTags(resourceType, subnets...) {
+		tags[k] = v
+	}
+	return tags
+}
+
+// CloudLabelPolicyTags returns the tags of the cloud label policies matching a resource of the specified type,
+// belonging to the specified cluster subnets. Later policies take precedence.
+func (b *KopsModelContext) CloudLabelPolicyTags(resourceType kops.CloudResourceType, subnets ...string) map[string]string {
+	tags := make(map[string]string)
+	for _, policy := range b.Cluster.Spec.CloudLabelPolicies {
+		if !cloudLabelPolicyMatches(&policy, resourceType, subnets) {
+			continue
+		}
+		for k, v := range policy.Labels {
+			tags[k] = v
+		}
+	}
+	return tags
+}
+
+// cloudLabelPolicyMatches returns true if the resource has one of the policy resource types,
+// and belongs only to the policy subnets
+func cloudLabelPolicyMatches(policy *kops.CloudLabelPolicy, resourceType kops.CloudResourceType, subnets []string) bool {
+	if len(policy.ResourceTypes) != 0 {
+		found := false
+		for _, t := range policy.ResourceTypes {
+			if t == resourceType {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(policy.Subnets) != 0 {
+		if len(subnets) == 0 {
+			return false
+		}
+		for _, subnet := range subnets {
+			found := false
+			for _, s := range policy.Subnets {
+				if s == subnet {
+					found = true
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // UseKopsControllerForNodeBootstrap checks if nodeup should use kops-controller to bootstrap.
 func (b *KopsModelContext) UseKopsControllerForNodeBootstrap() bool {
 	return model.UseKopsControllerForNodeBootstrap(b.Cluster)
@@ -311,6 +442,15 @@ func (b *KopsModelContext) UseNetworkLoadBalancer() bool {
 	return b.Cluster.Spec.API.LoadBalancer.Class == kops.LoadBalancerClassNetwork
 }
 
+// UseNetworkLoadBalancerForBastion checks if the bastions are behind a Network LoadBalancer
+func (b *KopsModelContext) UseNetworkLoadBalancerForBastion() bool {
+	topology := b.Cluster.Spec.Topology
+	if topology == nil || topology.Bastion == nil || topology.Bastion.LoadBalancer == nil {
+		return false
+	}
+	return topology.Bastion.LoadBalancer.Class == kops.LoadBalancerClassNetwork
+}
+
 // UseEtcdManager checks to see if etcd manager is enabled
 func (b *KopsModelContext) UseEtcdManager() bool {
 	for _, x := range b.Cluster.Spec.EtcdClusters {
@@ -415,3 +555,11 @@ func (b *KopsModelContext) NodePortRange() (utilnet.PortRange, error) {
 func (b *KopsModelContext) UseServiceAccountIAM() bool {
 	return featureflag.UseServiceAccountIAM.Enabled()
 }
+
+// DefaultVolumeKmsKeyId returns the KMS key for the volumes that do not specify a key of their own, or nil if there is none.
+func (b *KopsModelContext) DefaultVolumeKmsKeyId() *string {
+	if b.Cluster.Spec.CloudConfig == nil || b.Cluster.Spec.CloudConfig.EBSDefaultEncryption == nil {
+		return nil
+	}
+	return b.Cluster.Spec.CloudConfig.EBSDefaultEncryption.KmsKeyId
+}
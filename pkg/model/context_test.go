@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestCloudTagsForResource(t *testing.T) {
+	b := &KopsModelContext{
+		IAMModelContext: iam.IAMModelContext{Cluster: &kops.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+			Spec: kops.ClusterSpec{
+				CloudProvider: string(kops.CloudProviderAWS),
+				CloudLabels:   map[string]string{"team": "platform"},
+				CloudLabelPolicies: []kops.CloudLabelPolicy{
+					{
+						Labels:        map[string]string{"cost-center": "1234"},
+						ResourceTypes: []kops.CloudResourceType{kops.CloudResourceTypeInstance, kops.CloudResourceTypeVolume},
+					},
+					{
+						Labels:  map[string]string{"team": "payments"},
+						Subnets: []string{"us-test-1a", "us-test-1b"},
+					},
+				},
+			},
+		}},
+	}
+
+	grid := []struct {
+		ResourceType kops.CloudResourceType
+		Shared       bool
+		Subnets      []string
+		Expected     map[string]string
+	}{
+		{
+			ResourceType: kops.CloudResourceTypeIAM,
+			Expected: map[string]string{
+				"Name":              "test",
+				"KubernetesCluster": "minimal.example.com",
+				"kubernetes.io/cluster/minimal.example.com": "owned",
+				"team": "platform",
+			},
+		},
+		{
+			ResourceType: kops.CloudResourceTypeInstance,
+			Subnets:      []string{"us-test-1a"},
+			Expected: map[string]string{
+				"Name":              "test",
+				"KubernetesCluster": "minimal.example.com",
+				"kubernetes.io/cluster/minimal.example.com": "owned",
+				"team":        "payments",
+				"cost-center": "1234",
+			},
+		},
+		{
+			ResourceType: kops.CloudResourceTypeSubnet,
+			Subnets:      []string{"us-test-1a", "us-test-1c"},
+			Expected: map[string]string{
+				"Name":              "test",
+				"KubernetesCluster": "minimal.example.com",
+				"kubernetes.io/cluster/minimal.example.com": "owned",
+				"team": "platform",
+			},
+		},
+		{
+			ResourceType: kops.CloudResourceTypeSubnet,
+			Shared:       true,
+			Subnets:      []string{"us-test-1a"},
+			Expected: map[string]string{
+				"kubernetes.io/cluster/minimal.example.com": "shared",
+			},
+		},
+	}
+	for _, g := range grid {
+		actual := b.CloudTagsForResource(g.ResourceType, "test", g.Shared, g.Subnets...)
+		if !reflect.DeepEqual(actual, g.Expected) {
+			t.Errorf("unexpected tags for %s in %v: %v", g.ResourceType, g.Subnets, actual)
+		}
+	}
+}
+
+func TestClusterAutoscalerTagsForInstanceGroup(t *testing.T) {
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+		Spec: kops.ClusterSpec{
+			CloudProvider:     string(kops.CloudProviderAWS),
+			KubernetesVersion: "1.21.0",
+		},
+	}
+	ig := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+		Spec: kops.InstanceGroupSpec{
+			Role:           kops.InstanceGroupRoleNode,
+			RootVolumeSize: fi.Int32(64),
+			Taints:         []string{"dedicated=gpu:NoSchedule", "spot:PreferNoSchedule"},
+		},
+	}
+	b := &KopsModelContext{IAMModelContext: iam.IAMModelContext{Cluster: cluster}}
+
+	casTags := []string{
+		"k8s.io/cluster-autoscaler/enabled",
+		"k8s.io/cluster-autoscaler/minimal.example.com",
+		"k8s.io/cluster-autoscaler/node-template/taint/spot",
+		"k8s.io/cluster-autoscaler/node-template/resources/ephemeral-storage",
+	}
+
+	tags, err := b.CloudTagsForInstanceGroup(ig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, k := range casTags {
+		if _, found := tags[k]; found {
+			t.Errorf("unexpected tag %q without cluster autoscaler", k)
+		}
+	}
+
+	cluster.Spec.ClusterAutoscaler = &kops.ClusterAutoscalerConfig{Enabled: fi.Bool(true)}
+	tags, err = b.CloudTagsForInstanceGroup(ig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, k := range casTags {
+		if _, found := tags[k]; found {
+			t.Errorf("unexpected tag %q in the tags propagated to the instances", k)
+		}
+	}
+
+	tags, err = b.ClusterAutoscalerTagsForInstanceGroup(ig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"k8s.io/cluster-autoscaler/enabled":                                   "",
+		"k8s.io/cluster-autoscaler/minimal.example.com":                       "",
+		"k8s.io/cluster-autoscaler/node-template/taint/spot":                  ":PreferNoSchedule",
+		"k8s.io/cluster-autoscaler/node-template/resources/ephemeral-storage": "64Gi",
+	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("unexpected cluster autoscaler tags: %v", tags)
+	}
+
+	ig.Spec.Autoscale = fi.Bool(false)
+	tags, err = b.ClusterAutoscalerTagsForInstanceGroup(ig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 0 {
+		t.Errorf("unexpected cluster autoscaler tags for instance group with autoscale disabled: %v", tags)
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaults
+
+// DefaultMaxPods is the default maximum number of pods of the kubelet, as defined by KubeletConfiguration
+const DefaultMaxPods = 110
+
+// AmazonVPCMaxPods returns the maximum number of pods of a node using the AWS VPC CNI plugin.
+// Any maxPods set for the kubelet is respected, but the number of addresses of the instance is a hard maximum,
+// since networking with the CNI plugin won't work correctly once it is exceeded:
+// https://github.com/aws/amazon-vpc-cni-k8s/blob/f52ad45/README.md
+// enis and ipsPerENI are those of the instance type, or 0 if unknown.
+func AmazonVPCMaxPods(kubeletMaxPods *int32, enis int, ipsPerENI int) int32 {
+	maxPods := int32(DefaultMaxPods)
+	if kubeletMaxPods != nil {
+		maxPods = *kubeletMaxPods
+	}
+
+	if enis > 0 && ipsPerENI > 0 {
+		instanceMaxPods := int32(enis*(ipsPerENI-1) + 2)
+		if instanceMaxPods < maxPods {
+			maxPods = instanceMaxPods
+		}
+	}
+	return maxPods
+}
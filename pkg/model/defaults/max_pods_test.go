@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaults
+
+import "testing"
+
+func TestAmazonVPCMaxPods(t *testing.T) {
+	maxPods := func(v int32) *int32 { return &v }
+
+	grid := []struct {
+		KubeletMaxPods *int32
+		ENIs           int
+		IPsPerENI      int
+		Expected       int32
+	}{
+		{Expected: DefaultMaxPods},
+		// t3.medium
+		{ENIs: 3, IPsPerENI: 6, Expected: 17},
+		// m5.large
+		{ENIs: 3, IPsPerENI: 10, Expected: 29},
+		{KubeletMaxPods: maxPods(20), ENIs: 3, IPsPerENI: 10, Expected: 20},
+		// m5.24xlarge
+		{ENIs: 15, IPsPerENI: 50, Expected: DefaultMaxPods},
+		{KubeletMaxPods: maxPods(250), ENIs: 15, IPsPerENI: 50, Expected: 250},
+	}
+	for _, g := range grid {
+		actual := AmazonVPCMaxPods(g.KubeletMaxPods, g.ENIs, g.IPsPerENI)
+		if actual != g.Expected {
+			t.Errorf("expected %d pods for %d ENIs with %d IPs, got %d", g.Expected, g.ENIs, g.IPsPerENI, actual)
+		}
+	}
+}
@@ -46,3 +46,11 @@ func DefaultInstanceGroupVolumeSize(role kops.InstanceGroupRole) (int32, error)
 		return -1, fmt.Errorf("unknown InstanceGroup Role %s", role)
 	}
 }
+
+// InstanceGroupVolumeSize returns the root volume size of an InstanceGroup, defaulted by its role
+func InstanceGroupVolumeSize(ig *kops.InstanceGroup) (int32, error) {
+	if ig.Spec.RootVolumeSize != nil && *ig.Spec.RootVolumeSize > 0 {
+		return *ig.Spec.RootVolumeSize, nil
+	}
+	return DefaultInstanceGroupVolumeSize(ig.Spec.Role)
+}
@@ -18,6 +18,7 @@ package gcemodel
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -84,7 +85,6 @@ func (b *AutoscalingGroupModelBuilder) buildInstanceTemplate(c *fi.ModelBuilderC
 				BootDiskSizeGB: i64(int64(volumeSize)),
 				BootDiskImage:  s(ig.Spec.Image),
 
-				// TODO: Support preemptible nodes?
 				Preemptible: fi.Bool(false),
 
 				HasExternalIP: fi.Bool(b.Cluster.Spec.Topology.Masters == kops.TopologyPublic),
@@ -102,6 +102,27 @@ func (b *AutoscalingGroupModelBuilder) buildInstanceTemplate(c *fi.ModelBuilderC
 				},
 			}
 
+			if policy := ig.Spec.ProvisioningPolicy; policy != nil {
+				provisioningModel := policy.ProvisioningModel
+				if len(policy.MachineTypes) != 0 && policy.MachineTypes[0].ProvisioningModel != "" {
+					provisioningModel = policy.MachineTypes[0].ProvisioningModel
+				}
+				setProvisioningModel(t, policy, provisioningModel)
+			}
+
+			if ig.Spec.ShieldedVM != nil {
+				t.ShieldedVM = fi.Bool(true)
+				t.ShieldedSecureBoot = fi.Bool(fi.BoolValue(ig.Spec.ShieldedVM.SecureBoot))
+				t.ShieldedVTPM = fi.Bool(ig.Spec.ShieldedVM.VTPM == nil || *ig.Spec.ShieldedVM.VTPM)
+				t.ShieldedIntegrityMonitoring = fi.Bool(ig.Spec.ShieldedVM.IntegrityMonitoring == nil || *ig.Spec.ShieldedVM.IntegrityMonitoring)
+			}
+			if fi.BoolValue(ig.Spec.ConfidentialVM) {
+				t.ConfidentialCompute = fi.Bool(true)
+			}
+			if fi.BoolValue(ig.Spec.NestedVirtualization) {
+				t.NestedVirtualization = fi.Bool(true)
+			}
+
 			nodeRole, err := iam.BuildNodeRoleSubject(ig.Spec.Role, false)
 			if err != nil {
 				return nil, err
@@ -171,6 +192,72 @@ func (b *AutoscalingGroupModelBuilder) buildInstanceTemplate(c *fi.ModelBuilderC
 	}
 }
 
+// setProvisioningModel sets the scheduling options of an instance template for a provisioning model
+func setProvisioningModel(t *gcetasks.InstanceTemplate, policy *kops.ProvisioningPolicySpec, provisioningModel string) {
+	if provisioningModel == kops.ProvisioningModelSpot {
+		t.Preemptible = fi.Bool(true)
+		t.AutomaticRestart = nil
+		t.OnHostMaintenance = nil
+		return
+	}
+
+	t.Preemptible = fi.Bool(false)
+	t.AutomaticRestart = policy.AutomaticRestart
+	t.OnHostMaintenance = nil
+	if policy.OnHostMaintenance != "" {
+		t.OnHostMaintenance = fi.String(policy.OnHostMaintenance)
+	}
+}
+
+// buildAdditionalInstanceTemplates builds an instance template for each machine type of the provisioning policy after the first,
+// along with the percentage of the instances that use it. The first machine type uses the base instance template.
+func (b *AutoscalingGroupModelBuilder) buildAdditionalInstanceTemplates(ig *kops.InstanceGroup, base *gcetasks.InstanceTemplate) ([]*gcetasks.InstanceTemplate, []int64) {
+	additionalTemplates := []*gcetasks.InstanceTemplate{}
+	targetSizePercents := []int64{}
+
+	policy := ig.Spec.ProvisioningPolicy
+	if policy == nil || len(policy.MachineTypes) < 2 {
+		return additionalTemplates, targetSizePercents
+	}
+
+	totalWeight := int64(0)
+	for _, machineType := range policy.MachineTypes {
+		totalWeight += machineTypeWeight(machineType)
+	}
+
+	for _, machineType := range policy.MachineTypes[1:] {
+		provisioningModel := policy.ProvisioningModel
+		if machineType.ProvisioningModel != "" {
+			provisioningModel = machineType.ProvisioningModel
+		}
+
+		suffix := machineType.Name
+		if provisioningModel == kops.ProvisioningModelSpot {
+			suffix += "-spot"
+		}
+		name := b.SafeObjectName(ig.ObjectMeta.Name + "-" + suffix)
+
+		t := *base
+		t.Name = s(name)
+		t.NamePrefix = s(gce.LimitedLengthName(name, gcetasks.InstanceTemplateNamePrefixMaxLength))
+		t.MachineType = s(machineType.Name)
+		t.ID = nil
+		setProvisioningModel(&t, policy, provisioningModel)
+
+		additionalTemplates = append(additionalTemplates, &t)
+		targetSizePercents = append(targetSizePercents, 100*machineTypeWeight(machineType)/totalWeight)
+	}
+
+	return additionalTemplates, targetSizePercents
+}
+
+func machineTypeWeight(machineType kops.ProvisioningMachineTypeSpec) int64 {
+	if machineType.Weight == nil {
+		return 1
+	}
+	return int64(*machineType.Weight)
+}
+
 func (b *AutoscalingGroupModelBuilder) splitToZones(ig *kops.InstanceGroup) (map[string]int, error) {
 	// Indented to keep diff manageable
 	// TODO: Remove spurious indent
@@ -190,10 +277,7 @@ func (b *AutoscalingGroupModelBuilder) splitToZones(ig *kops.InstanceGroup) (map
 		}
 
 		// We have to assign instances to the various zones
-		// TODO: Switch to regional managed instance group
-		// But we can't yet use RegionInstanceGroups:
-		// 1) no support in terraform
-		// 2) we can't steer to specific zones AFAICT, only to all zones in the region
+		// Instance groups with regionalInstanceGroup set use a regional managed instance group instead
 
 		targetSizes := make([]int, len(zones))
 		totalSize := 0
@@ -223,6 +307,44 @@ func (b *AutoscalingGroupModelBuilder) splitToZones(ig *kops.InstanceGroup) (map
 	}
 }
 
+// buildRegionalInstanceGroupManager builds a single regional managed instance group, which lets GCE spread the instances over the zones
+func (b *AutoscalingGroupModelBuilder) buildRegionalInstanceGroupManager(ig *kops.InstanceGroup, instanceTemplate *gcetasks.InstanceTemplate) (*gcetasks.InstanceGroupManager, error) {
+	zones, err := b.FindZonesForInstanceGroup(ig)
+	if err != nil {
+		return nil, err
+	}
+	zones = append([]string(nil), zones...)
+	sort.Strings(zones)
+
+	// TODO: Duplicated from aws - move to defaults?
+	minSize := 1
+	if ig.Spec.MinSize != nil {
+		minSize = int(fi.Int32Value(ig.Spec.MinSize))
+	} else if ig.Spec.Role == kops.InstanceGroupRoleNode {
+		minSize = 2
+	}
+
+	t := &gcetasks.InstanceGroupManager{
+		Name:             s(gce.NameForRegionalInstanceGroupManager(b.Cluster, ig)),
+		Lifecycle:        b.Lifecycle,
+		Region:           s(b.Region),
+		Zones:            zones,
+		TargetSize:       fi.Int64(int64(minSize)),
+		BaseInstanceName: s(ig.ObjectMeta.Name),
+		InstanceTemplate: instanceTemplate,
+	}
+
+	// Attach masters to load balancer if we're using one
+	switch ig.Spec.Role {
+	case kops.InstanceGroupRoleMaster:
+		if b.UseLoadBalancerForAPI() {
+			t.TargetPools = append(t.TargetPools, b.LinkToTargetPool("api"))
+		}
+	}
+
+	return t, nil
+}
+
 func (b *AutoscalingGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	for _, ig := range b.InstanceGroups {
 		instanceTemplate, err := b.buildInstanceTemplate(c, ig)
@@ -232,6 +354,22 @@ func (b *AutoscalingGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 
 		c.AddTask(instanceTemplate)
 
+		additionalTemplates, targetSizePercents := b.buildAdditionalInstanceTemplates(ig, instanceTemplate)
+		for _, t := range additionalTemplates {
+			c.AddTask(t)
+		}
+
+		if fi.BoolValue(ig.Spec.RegionalInstanceGroup) {
+			t, err := b.buildRegionalInstanceGroupManager(ig, instanceTemplate)
+			if err != nil {
+				return err
+			}
+			t.AdditionalInstanceTemplates = additionalTemplates
+			t.AdditionalTargetSizePercents = targetSizePercents
+			c.AddTask(t)
+			continue
+		}
+
 		instanceCountByZone, err := b.splitToZones(ig)
 		if err != nil {
 			return err
@@ -247,6 +385,9 @@ func (b *AutoscalingGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 				TargetSize:       fi.Int64(int64(targetSize)),
 				BaseInstanceName: s(ig.ObjectMeta.Name),
 				InstanceTemplate: instanceTemplate,
+
+				AdditionalInstanceTemplates:  additionalTemplates,
+				AdditionalTargetSizePercents: targetSizePercents,
 			}
 
 			// Attach masters to load balancer if we're using one
@@ -231,6 +231,7 @@ func (l *Statement) Equal(r *Statement) bool {
 // AWS IAM policy document for a given instance group role.
 type PolicyBuilder struct {
 	Cluster              *kops.Cluster
+	InstanceGroups       []*kops.InstanceGroup
 	HostedZoneID         string
 	KMSKeys              []string
 	Region               string
@@ -250,6 +251,9 @@ func (b *PolicyBuilder) BuildAWSPolicy() (*Policy, error) {
 			}
 		}
 	}
+	if b.Cluster.Spec.CloudConfig != nil && b.Cluster.Spec.CloudConfig.EBSDefaultEncryption != nil && b.Cluster.Spec.CloudConfig.EBSDefaultEncryption.KmsKeyId != nil {
+		b.KMSKeys = append(b.KMSKeys, *b.Cluster.Spec.CloudConfig.EBSDefaultEncryption.KmsKeyId)
+	}
 
 	p, err := b.Role.BuildAWSPolicy(b)
 	if err != nil {
@@ -374,6 +378,26 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 	if b.Cluster.Spec.SnapshotController != nil && fi.BoolValue(b.Cluster.Spec.SnapshotController.Enabled) {
 		addSnapshotPersmissions(p)
 	}
+
+	if model.UseNodeRemediation(b.Cluster) {
+		addNodeRemediationPermissions(p)
+	}
+
+	if model.UseSessionManager(b.Cluster) {
+		addSSMAgentPermissions(p)
+	}
+
+	if b.Cluster.Spec.OSPatching != nil && b.Cluster.Spec.OSPatching.Method == kops.OSPatchingMethodSSM {
+		addOSPatchingSSMPermissions(p)
+	}
+
+	if model.UseEgressGateways(b.Cluster) {
+		addEgressGatewayPermissions(p)
+	}
+
+	if len(model.ScaleInProtectedInstanceGroups(b.InstanceGroups)) != 0 {
+		addScaleInProtectionPermissions(p)
+	}
 	return p, nil
 }
 
@@ -406,6 +430,10 @@ func (r *NodeRoleNode) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		addCalicoSrcDstCheckPermissions(p)
 	}
 
+	if model.UseSessionManager(b.Cluster) || (b.Cluster.Spec.OSPatching != nil && b.Cluster.Spec.OSPatching.Method == kops.OSPatchingMethodSSM) {
+		addSSMAgentPermissions(p)
+	}
+
 	return p, nil
 }
 
@@ -606,24 +634,28 @@ func (b *PolicyBuilder) buildS3GetStatements(p *Policy, iamS3Path string) error
 func WriteableVFSPaths(cluster *kops.Cluster, role Subject) ([]vfs.Path, error) {
 	var paths []vfs.Path
 
-	// etcd-manager needs write permissions to the backup store
+	// etcd-manager needs write permissions to the backup store, and kops-controller to the replica store
 	switch role.(type) {
 	case *NodeRoleMaster:
 		backupStores := sets.NewString()
 		for _, c := range cluster.Spec.EtcdClusters {
-			if c.Backups == nil || c.Backups.BackupStore == "" || backupStores.Has(c.Backups.BackupStore) {
+			if c.Backups == nil {
 				continue
 			}
-			backupStore := c.Backups.BackupStore
+			for _, backupStore := range []string{c.Backups.BackupStore, c.Backups.ReplicaStore} {
+				if backupStore == "" || backupStores.Has(backupStore) {
+					continue
+				}
 
-			vfsPath, err := vfs.Context.BuildVfsPath(backupStore)
-			if err != nil {
-				return nil, fmt.Errorf("cannot parse VFS path %q: %v", backupStore, err)
-			}
+				vfsPath, err := vfs.Context.BuildVfsPath(backupStore)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse VFS path %q: %v", backupStore, err)
+				}
 
-			paths = append(paths, vfsPath)
+				paths = append(paths, vfsPath)
 
-			backupStores.Insert(backupStore)
+				backupStores.Insert(backupStore)
+			}
 		}
 	}
 
@@ -1046,6 +1078,56 @@ func addMasterASPolicies(p *Policy) {
 	)
 }
 
+// addNodeRemediationPermissions allows kops-controller to replace the instances of nodes reporting problems.
+func addNodeRemediationPermissions(p *Policy) {
+	p.clusterTaggedAction.Insert(
+		"ec2:TerminateInstances",
+	)
+}
+
+// addEgressGatewayPermissions allows kops-controller to associate the Elastic IPs of the egress gateways with the gateway nodes.
+func addEgressGatewayPermissions(p *Policy) {
+	p.unconditionalAction.Insert(
+		"ec2:DescribeAddresses",
+	)
+	p.clusterTaggedAction.Insert(
+		"ec2:AssociateAddress",
+	)
+}
+
+// addScaleInProtectionPermissions allows kops-controller to remove the scale in protection of the instances of cordoned nodes.
+func addScaleInProtectionPermissions(p *Policy) {
+	p.clusterTaggedAction.Insert(
+		"autoscaling:DescribeAutoScalingInstances",
+		"autoscaling:SetInstanceProtection",
+	)
+}
+
+// addSSMAgentPermissions allows the SSM agent of an instance to register with Systems Manager and run commands.
+func addSSMAgentPermissions(p *Policy) {
+	p.unconditionalAction.Insert(
+		"ssm:UpdateInstanceInformation",
+		"ssmmessages:CreateControlChannel",
+		"ssmmessages:CreateDataChannel",
+		"ssmmessages:OpenControlChannel",
+		"ssmmessages:OpenDataChannel",
+		"ec2messages:AcknowledgeMessage",
+		"ec2messages:DeleteMessage",
+		"ec2messages:FailMessage",
+		"ec2messages:GetEndpoint",
+		"ec2messages:GetMessages",
+		"ec2messages:SendReply",
+	)
+}
+
+// addOSPatchingSSMPermissions allows kops-controller to update the packages of nodes with Systems Manager Run Command.
+func addOSPatchingSSMPermissions(p *Policy) {
+	p.unconditionalAction.Insert(
+		"ssm:SendCommand",
+		"ssm:GetCommandInvocation",
+	)
+}
+
 func addASLifecyclePolicies(p *Policy, enableHookSupport bool) {
 	if enableHookSupport {
 		p.clusterTaggedAction.Insert(
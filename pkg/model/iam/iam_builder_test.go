@@ -18,6 +18,7 @@ package iam
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -239,3 +240,42 @@ func TestEmptyPolicy(t *testing.T) {
 	}
 
 }
+
+func TestWriteableVFSPathsEtcdReplicaStore(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			EtcdClusters: []kops.EtcdClusterSpec{
+				{
+					Name: "main",
+					Backups: &kops.EtcdBackupSpec{
+						BackupStore:  "s3://state-store/example.com/backups/etcd/main",
+						ReplicaStore: "s3://state-store-dr/example.com/backups/etcd/main",
+					},
+				},
+				{
+					Name: "events",
+					Backups: &kops.EtcdBackupSpec{
+						BackupStore: "s3://state-store/example.com/backups/etcd/events",
+					},
+				},
+			},
+		},
+	}
+
+	paths, err := WriteableVFSPaths(cluster, &NodeRoleMaster{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actual []string
+	for _, p := range paths {
+		actual = append(actual, p.Path())
+	}
+	expected := []string{
+		"s3://state-store/example.com/backups/etcd/main",
+		"s3://state-store-dr/example.com/backups/etcd/main",
+		"s3://state-store/example.com/backups/etcd/events",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected writeable paths: %v", actual)
+	}
+}
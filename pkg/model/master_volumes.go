@@ -35,6 +35,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/dotasks"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gcetasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstacktasks"
 )
@@ -114,6 +116,10 @@ func (b *MasterVolumeBuilder) Build(c *fi.ModelBuilderContext) error {
 				b.addALIVolume(c, name, volumeSize, zone, etcd, m, allMembers)
 			case kops.CloudProviderAzure:
 				b.addAzureVolume(c, name, volumeSize, zone, etcd, m, allMembers)
+			case kops.CloudProviderOCI:
+				b.addOCIVolume(c, name, volumeSize, zone, etcd, m, allMembers)
+			case kops.CloudProviderMetal:
+				// The etcd volumes are directories on the control plane machines, which kOps does not manage.
 			default:
 				return fmt.Errorf("unknown cloudprovider %q", b.Cluster.Spec.CloudProvider)
 			}
@@ -143,25 +149,6 @@ func (b *MasterVolumeBuilder) addAWSVolume(c *fi.ModelBuilderContext, name strin
 			volumeThroughput = DefaultAWSEtcdVolumeGp3Throughput
 		}
 	}
-	volumeIopsSizeRatio := float64(volumeIops) / float64(volumeSize)
-	volumeThroughputIopsRatio := float64(volumeThroughput) / float64(volumeIops)
-	switch volumeType {
-	case ec2.VolumeTypeIo1:
-		if volumeIopsSizeRatio >= 50.0 {
-			return fmt.Errorf("volumeIops to volumeSize ratio must be lower than 50. For %s ratio is %.02f", name, volumeIopsSizeRatio)
-		}
-	case ec2.VolumeTypeIo2:
-		if volumeIopsSizeRatio >= 500.0 {
-			return fmt.Errorf("volumeIops to volumeSize ratio must be lower than 500. For %s ratio is %.02f", name, volumeIopsSizeRatio)
-		}
-	case ec2.VolumeTypeGp3:
-		if volumeIops > 3000 && volumeIopsSizeRatio >= 500.0 {
-			return fmt.Errorf("volumeIops to volumeSize ratio must be lower than 500. For %s ratio is %.02f", name, volumeIopsSizeRatio)
-		}
-		if volumeThroughputIopsRatio >= 0.25 {
-			return fmt.Errorf("volumeThroughput to volumeIops ratio must be lower than 0.25. For %s ratio is %.02f", name, volumeThroughputIopsRatio)
-		}
-	}
 
 	// The tags are how protokube knows to mount the volume and use it for etcd
 	tags := make(map[string]string)
@@ -170,6 +157,9 @@ func (b *MasterVolumeBuilder) addAWSVolume(c *fi.ModelBuilderContext, name strin
 	for k, v := range b.Cluster.Spec.CloudLabels {
 		tags[k] = v
 	}
+	for k, v := range b.CloudLabelPolicyTags(kops.CloudResourceTypeVolume) {
+		tags[k] = v
+	}
 
 	//tags[awsup.TagClusterName] = b.C.cluster.Name
 	// This is the configuration of the etcd cluster
@@ -181,6 +171,13 @@ func (b *MasterVolumeBuilder) addAWSVolume(c *fi.ModelBuilderContext, name strin
 	tags["kubernetes.io/cluster/"+b.Cluster.ObjectMeta.Name] = "owned"
 
 	encrypted := fi.BoolValue(m.EncryptedVolume)
+	kmsKeyId := m.KmsKeyId
+	encryptionDefaulted := false
+	if kmsKeyId == nil && b.DefaultVolumeKmsKeyId() != nil {
+		encrypted = true
+		kmsKeyId = b.DefaultVolumeKmsKeyId()
+		encryptionDefaulted = true
+	}
 
 	t := &awstasks.EBSVolume{
 		Name:      fi.String(name),
@@ -189,9 +186,11 @@ func (b *MasterVolumeBuilder) addAWSVolume(c *fi.ModelBuilderContext, name strin
 		AvailabilityZone: fi.String(zone),
 		SizeGB:           fi.Int64(int64(volumeSize)),
 		VolumeType:       fi.String(volumeType),
-		KmsKeyId:         m.KmsKeyId,
+		KmsKeyId:         kmsKeyId,
 		Encrypted:        fi.Bool(encrypted),
 		Tags:             tags,
+
+		EncryptionDefaulted: encryptionDefaulted,
 	}
 	switch volumeType {
 	case ec2.VolumeTypeGp3:
@@ -388,3 +387,40 @@ func (b *MasterVolumeBuilder) addAzureVolume(
 	}
 	c.AddTask(t)
 }
+
+func (b *MasterVolumeBuilder) addOCIVolume(
+	c *fi.ModelBuilderContext,
+	name string,
+	volumeSize int32,
+	zone string,
+	etcd kops.EtcdClusterSpec,
+	m kops.EtcdMemberSpec,
+	allMembers []string,
+) {
+	// OCI block volumes are at least 50 GB.
+	const minimumOCIVolumeSize = 50
+	if volumeSize < minimumOCIVolumeSize {
+		volumeSize = minimumOCIVolumeSize
+	}
+
+	tags := make(map[string]string)
+	// Apply all user defined labels on the volumes.
+	for k, v := range b.Cluster.Spec.CloudLabels {
+		tags[oci.SafeTagKey(k)] = v
+	}
+	// The tags are used by etcd-manager to mount the volume and use it for etcd.
+	tags[oci.TagClusterName] = b.ClusterName()
+	// This is the configuration of the etcd cluster.
+	tags[oci.TagNameEtcdClusterPrefix+etcd.Name] = m.Name + "/" + strings.Join(allMembers, ",")
+	// This says "only mount on a master".
+	tags[oci.TagKopsRole] = oci.TagRoleMaster
+
+	t := &ocitasks.Volume{
+		Name:      fi.String(name),
+		Lifecycle: b.Lifecycle,
+		Zone:      fi.String(zone),
+		SizeGB:    fi.Int64(int64(volumeSize)),
+		Tags:      tags,
+	}
+	c.AddTask(t)
+}
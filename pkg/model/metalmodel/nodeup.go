@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metalmodel
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+	"k8s.io/kops/upup/pkg/fi/fitasks"
+)
+
+// NodeupScriptModelBuilder stores the nodeup script of each instance group in the state store,
+// from where `kops toolbox enroll` runs it on the machines of the instance group.
+type NodeupScriptModelBuilder struct {
+	*model.KopsModelContext
+	BootstrapScriptBuilder *model.BootstrapScriptBuilder
+	Lifecycle              fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &NodeupScriptModelBuilder{}
+
+// Build builds a managed file with the nodeup script of each instance group.
+func (b *NodeupScriptModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	for _, ig := range b.InstanceGroups {
+		script, err := b.BootstrapScriptBuilder.ResourceNodeUp(c, ig)
+		if err != nil {
+			return fmt.Errorf("error building nodeup script for instance group %q: %v", ig.Name, err)
+		}
+
+		c.AddTask(&fitasks.ManagedFile{
+			Name:      fi.String("nodeup-script-" + ig.Name),
+			Lifecycle: b.Lifecycle,
+			Location:  fi.String(metal.NodeupScriptLocation(ig)),
+			Contents:  script,
+		})
+	}
+	return nil
+}
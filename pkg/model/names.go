@@ -270,6 +270,16 @@ func (b *KopsModelContext) InstanceName(ig *kops.InstanceGroup, suffix string) s
 	return b.AutoscalingGroupName(ig) + suffix
 }
 
+// LoadBalancerElasticIPName is the name of the Elastic IP of a load balancer in a subnet
+func (b *KopsModelContext) LoadBalancerElasticIPName(loadBalancer string, subnet *awstasks.Subnet) string {
+	return loadBalancer + "." + fi.StringValue(subnet.Name)
+}
+
+// EgressGatewayElasticIPName is the name of the Elastic IP of an egress gateway
+func (b *KopsModelContext) EgressGatewayElasticIPName(gatewayName string) string {
+	return gatewayName + ".egress." + b.ClusterName()
+}
+
 func QueueNamePrefix(clusterName string) string {
 	// periods aren't allowed in queue name
 	return strings.ReplaceAll(clusterName, ".", "-")
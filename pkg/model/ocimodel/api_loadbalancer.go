@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+)
+
+// APILoadBalancerModelBuilder builds a network load balancer for accessing the API
+type APILoadBalancerModelBuilder struct {
+	*OCIModelContext
+	Lifecycle fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &APILoadBalancerModelBuilder{}
+
+// Build builds tasks for creating the network load balancer of the API.
+func (b *APILoadBalancerModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !b.UseLoadBalancerForAPI() {
+		return nil
+	}
+
+	lbSpec := b.Cluster.Spec.API.LoadBalancer
+	nlb := &ocitasks.NetworkLoadBalancer{
+		Name:      fi.String(b.NameForLoadBalancer()),
+		Lifecycle: b.Lifecycle,
+		Tags:      b.CloudTags(),
+	}
+	nlb.Tags[oci.TagKopsRole] = oci.TagRoleAPILoadBalancer
+
+	switch lbSpec.Type {
+	case kops.LoadBalancerTypeInternal:
+		nlb.Private = fi.Bool(true)
+	case kops.LoadBalancerTypePublic:
+		nlb.Private = fi.Bool(false)
+	default:
+		return fmt.Errorf("unknown load balancer Type: %q", lbSpec.Type)
+	}
+
+	subnet, err := b.subnetForLoadBalancer()
+	if err != nil {
+		return err
+	}
+	nlb.Subnet = b.LinkToSubnet(subnet)
+
+	c.AddTask(nlb)
+	return nil
+}
+
+// subnetForLoadBalancer returns the subnet of the first master instance group.
+func (b *APILoadBalancerModelBuilder) subnetForLoadBalancer() (*kops.ClusterSubnetSpec, error) {
+	for _, ig := range b.MasterInstanceGroups() {
+		subnets, err := b.GatherSubnets(ig)
+		if err != nil {
+			return nil, err
+		}
+		if len(subnets) != 1 {
+			return nil, fmt.Errorf("expected exactly one subnet for InstanceGroup %q; subnets was %s", ig.Name, ig.Spec.Subnets)
+		}
+		return subnets[0], nil
+	}
+	return nil, fmt.Errorf("no suitable subnets found")
+}
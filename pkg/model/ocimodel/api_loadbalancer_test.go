@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+)
+
+func TestAPILoadBalancerModelBuilder_Build(t *testing.T) {
+	b := APILoadBalancerModelBuilder{
+		OCIModelContext: newTestOCIModelContext(),
+	}
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	err := b.Build(c)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	nlb, ok := c.Tasks["NetworkLoadBalancer/api.testcluster.test.com"].(*ocitasks.NetworkLoadBalancer)
+	if !ok {
+		t.Fatalf("load balancer task not found in %v", c.Tasks)
+	}
+	if fi.BoolValue(nlb.Private) {
+		t.Errorf("expected a public load balancer")
+	}
+	if nlb.Tags[oci.TagKopsRole] != oci.TagRoleAPILoadBalancer {
+		t.Errorf("expected the load balancer to be tagged as the API load balancer, but got %v", nlb.Tags)
+	}
+	if fi.StringValue(nlb.Subnet.Name) != "us-ashburn-1.testcluster.test.com" {
+		t.Errorf("unexpected subnet %q", fi.StringValue(nlb.Subnet.Name))
+	}
+}
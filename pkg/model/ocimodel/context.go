@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+)
+
+// OCIModelContext is the context for building OCI tasks.
+type OCIModelContext struct {
+	*model.KopsModelContext
+}
+
+// NameForVCN returns the name of the VCN the cluster is located in.
+func (c *OCIModelContext) NameForVCN() string {
+	return c.ClusterName()
+}
+
+// LinkToVCN returns the VCN the cluster is located in.
+func (c *OCIModelContext) LinkToVCN() *ocitasks.VCN {
+	return &ocitasks.VCN{Name: fi.String(c.NameForVCN())}
+}
+
+// LinkToInternetGateway returns the internet gateway of the VCN.
+func (c *OCIModelContext) LinkToInternetGateway() *ocitasks.InternetGateway {
+	return &ocitasks.InternetGateway{Name: fi.String(c.ClusterName())}
+}
+
+// LinkToRouteTable returns the route table of the public subnets.
+func (c *OCIModelContext) LinkToRouteTable() *ocitasks.RouteTable {
+	return &ocitasks.RouteTable{Name: fi.String(c.ClusterName())}
+}
+
+// LinkToSecurityList returns the security list of the subnets.
+func (c *OCIModelContext) LinkToSecurityList() *ocitasks.SecurityList {
+	return &ocitasks.SecurityList{Name: fi.String(c.ClusterName())}
+}
+
+// NameForSubnet returns the name of the OCI subnet for a cluster subnet.
+func (c *OCIModelContext) NameForSubnet(spec *kops.ClusterSubnetSpec) string {
+	return spec.Name + "." + c.ClusterName()
+}
+
+// LinkToSubnet returns the OCI subnet for a cluster subnet.
+func (c *OCIModelContext) LinkToSubnet(spec *kops.ClusterSubnetSpec) *ocitasks.Subnet {
+	return &ocitasks.Subnet{Name: fi.String(c.NameForSubnet(spec))}
+}
+
+// NameForLoadBalancer returns the name of the network load balancer for the API.
+func (c *OCIModelContext) NameForLoadBalancer() string {
+	return "api." + c.ClusterName()
+}
+
+// LinkToLoadBalancer returns the network load balancer for the API.
+func (c *OCIModelContext) LinkToLoadBalancer() *ocitasks.NetworkLoadBalancer {
+	return &ocitasks.NetworkLoadBalancer{Name: fi.String(c.NameForLoadBalancer())}
+}
+
+// NameForDynamicGroup returns the name of the dynamic group of the cluster instances.
+func (c *OCIModelContext) NameForDynamicGroup() string {
+	return "kops-" + oci.SafeIdentityName(c.ClusterName())
+}
+
+// CloudTags computes the freeform tags to apply to the resources of the cluster.
+func (c *OCIModelContext) CloudTags() map[string]string {
+	tags := make(map[string]string)
+	for k, v := range c.Cluster.Spec.CloudLabels {
+		tags[oci.SafeTagKey(k)] = v
+	}
+	// The cluster tag takes priority as resources are discovered by it.
+	tags[oci.TagClusterName] = c.ClusterName()
+	return tags
+}
+
+// DefinedTags computes the defined tags that identify the instances of the cluster in the matching rule of its dynamic group.
+func (c *OCIModelContext) DefinedTags() map[string]map[string]string {
+	return map[string]map[string]string{
+		c.Cluster.Spec.CloudConfig.OCI.TagNamespace: {
+			oci.DefinedTagKeyCluster: c.ClusterName(),
+		},
+	}
+}
+
+// CloudTagsForInstanceGroup computes the freeform tags to apply to the instances in the specified InstanceGroup.
+func (c *OCIModelContext) CloudTagsForInstanceGroup(ig *kops.InstanceGroup) map[string]string {
+	tags := c.CloudTags()
+	for k, v := range ig.Spec.CloudLabels {
+		tags[oci.SafeTagKey(k)] = v
+	}
+	// The system tags take priority because the cluster likely breaks without them.
+	tags[oci.TagClusterName] = c.ClusterName()
+	tags[oci.TagKopsInstanceGroup] = ig.Name
+	tags[oci.TagKopsRole] = string(ig.Spec.Role)
+	return tags
+}
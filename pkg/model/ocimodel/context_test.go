@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCloudTagsForInstanceGroup(t *testing.T) {
+	c := newTestOCIModelContext()
+	c.Cluster.Spec.CloudLabels = map[string]string{
+		"cluster.label/key": "cluster_label_value",
+		"test_label":        "from_cluster",
+	}
+	ig := c.InstanceGroups[1]
+	ig.Spec.CloudLabels = map[string]string{
+		"ig_label_key": "ig_label_value",
+		"test_label":   "from_ig",
+	}
+
+	actual := c.CloudTagsForInstanceGroup(ig)
+	expected := map[string]string{
+		"cluster_label_key": "cluster_label_value",
+		"ig_label_key":      "ig_label_value",
+		"test_label":        "from_ig",
+		"KubernetesCluster": "testcluster.test.com",
+		"KopsInstanceGroup": "nodes",
+		"KopsRole":          "Node",
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected tags %+v, but got %+v", expected, actual)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"fmt"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+)
+
+// IAMModelBuilder grants the cluster instances access to the OCI API through instance principals.
+type IAMModelBuilder struct {
+	*OCIModelContext
+	Lifecycle fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &IAMModelBuilder{}
+
+// Build builds tasks for creating the dynamic group of the cluster instances and its policy.
+func (b *IAMModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	name := b.NameForDynamicGroup()
+	compartmentID := b.Cluster.Spec.CloudConfig.OCI.CompartmentID
+
+	// Freeform tags cannot be used in matching rules, so the instances
+	// are identified by the defined tag of the cluster.
+	matchingRule := fmt.Sprintf("ALL {instance.compartment.id = '%s', tag.%s.%s.value = '%s'}",
+		compartmentID, b.Cluster.Spec.CloudConfig.OCI.TagNamespace, oci.DefinedTagKeyCluster, b.ClusterName())
+	dg := &ocitasks.DynamicGroup{
+		Name:         fi.String(name),
+		Lifecycle:    b.Lifecycle,
+		Description:  fi.String("Instances of the Kubernetes cluster " + b.ClusterName()),
+		MatchingRule: fi.String(matchingRule),
+		Tags:         b.CloudTags(),
+	}
+	c.AddTask(dg)
+
+	var statements []string
+	for _, permission := range []string{
+		// Mounting the etcd volumes on the masters.
+		"manage volume-family",
+		"use instance-family",
+		// Discovering the instances and the network of the cluster.
+		"read virtual-network-family",
+		"read instance-pools",
+	} {
+		statements = append(statements, fmt.Sprintf("Allow dynamic-group %s to %s in compartment id %s", name, permission, compartmentID))
+	}
+
+	c.AddTask(&ocitasks.Policy{
+		Name:         fi.String(name),
+		Lifecycle:    b.Lifecycle,
+		Description:  fi.String("Access to the OCI API for the Kubernetes cluster " + b.ClusterName()),
+		Statements:   statements,
+		DynamicGroup: dg,
+		Tags:         b.CloudTags(),
+	})
+
+	return nil
+}
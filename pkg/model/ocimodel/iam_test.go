@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+)
+
+func TestIAMModelBuilder_Build(t *testing.T) {
+	b := IAMModelBuilder{
+		OCIModelContext: newTestOCIModelContext(),
+	}
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	err := b.Build(c)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	dg, ok := c.Tasks["DynamicGroup/kops-testcluster-test-com"].(*ocitasks.DynamicGroup)
+	if !ok {
+		t.Fatalf("dynamic group task not found in %v", c.Tasks)
+	}
+	if expected := "ALL {instance.compartment.id = 'ocid1.compartment.oc1..test', tag.kops.kops-cluster.value = 'testcluster.test.com'}"; fi.StringValue(dg.MatchingRule) != expected {
+		t.Errorf("expected matching rule %q, but got %q", expected, fi.StringValue(dg.MatchingRule))
+	}
+
+	policy := c.Tasks["Policy/kops-testcluster-test-com"].(*ocitasks.Policy)
+	for _, statement := range policy.Statements {
+		if !strings.HasPrefix(statement, "Allow dynamic-group kops-testcluster-test-com to ") {
+			t.Errorf("unexpected statement %q", statement)
+		}
+	}
+}
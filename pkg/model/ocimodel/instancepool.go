@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/defaults"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+)
+
+const (
+	// Flexible shapes are sized explicitly; these are the sizes used for their instances.
+	defaultMasterFlexOCPUs       = 2
+	defaultMasterFlexMemoryInGBs = 12
+	defaultNodeFlexOCPUs         = 1
+	defaultNodeFlexMemoryInGBs   = 6
+)
+
+// InstancePoolModelBuilder configures InstancePool objects
+type InstancePoolModelBuilder struct {
+	*OCIModelContext
+	BootstrapScriptBuilder *model.BootstrapScriptBuilder
+	Lifecycle              fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &InstancePoolModelBuilder{}
+
+// Build is responsible for constructing the instance pools from the kops spec.
+func (b *InstancePoolModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	for _, ig := range b.InstanceGroups {
+		pool, err := b.buildInstancePoolTask(c, ig)
+		if err != nil {
+			return err
+		}
+		c.AddTask(pool)
+	}
+	return nil
+}
+
+func (b *InstancePoolModelBuilder) buildInstancePoolTask(c *fi.ModelBuilderContext, ig *kops.InstanceGroup) (*ocitasks.InstancePool, error) {
+	t := &ocitasks.InstancePool{
+		Name:      fi.String(b.AutoscalingGroupName(ig)),
+		Lifecycle: b.Lifecycle,
+		Shape:     fi.String(ig.Spec.MachineType),
+		Image:     fi.String(ig.Spec.Image),
+		Zones:     ig.Spec.Zones,
+		Tags:      b.CloudTagsForInstanceGroup(ig),
+
+		DefinedTags: b.DefinedTags(),
+	}
+
+	if len(t.Zones) == 0 {
+		return nil, fmt.Errorf("must specify at least one zone for InstanceGroup %q", ig.Name)
+	}
+
+	size, err := getSize(&ig.Spec)
+	if err != nil {
+		return nil, err
+	}
+	t.Size = fi.Int(size)
+
+	if strings.HasSuffix(ig.Spec.MachineType, ".Flex") {
+		if ig.Spec.Role == kops.InstanceGroupRoleMaster {
+			t.OCPUs = fi.Float32(defaultMasterFlexOCPUs)
+			t.MemoryInGBs = fi.Float32(defaultMasterFlexMemoryInGBs)
+		} else {
+			t.OCPUs = fi.Float32(defaultNodeFlexOCPUs)
+			t.MemoryInGBs = fi.Float32(defaultNodeFlexMemoryInGBs)
+		}
+	}
+
+	volumeSize := fi.Int32Value(ig.Spec.RootVolumeSize)
+	if volumeSize == 0 {
+		volumeSize, err = defaults.DefaultInstanceGroupVolumeSize(ig.Spec.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.RootVolumeSizeGB = fi.Int64(int64(volumeSize))
+
+	subnets, err := b.GatherSubnets(ig)
+	if err != nil {
+		return nil, err
+	}
+	if len(subnets) != 1 {
+		return nil, fmt.Errorf("expected exactly one subnet for InstanceGroup %q; subnets was %s", ig.Name, ig.Spec.Subnets)
+	}
+	subnet := subnets[0]
+	t.Subnet = b.LinkToSubnet(subnet)
+
+	switch subnet.Type {
+	case kops.SubnetTypePublic, kops.SubnetTypeUtility:
+		t.AssignPublicIP = fi.Bool(true)
+		if ig.Spec.AssociatePublicIP != nil {
+			t.AssignPublicIP = ig.Spec.AssociatePublicIP
+		}
+	default:
+		return nil, fmt.Errorf("unexpected subnet type: for InstanceGroup %q; type was %s", ig.Name, subnet.Type)
+	}
+
+	if len(b.SSHPublicKeys) > 0 {
+		// Additional keys are authorized by nodeup
+		t.SSHPublicKey = fi.String(string(b.SSHPublicKeys[0]))
+	}
+
+	if t.UserData, err = b.BootstrapScriptBuilder.ResourceNodeUp(c, ig); err != nil {
+		return nil, err
+	}
+
+	if ig.Spec.Role == kops.InstanceGroupRoleMaster && b.UseLoadBalancerForAPI() {
+		t.LoadBalancer = b.LinkToLoadBalancer()
+	}
+
+	return t, nil
+}
+
+// getSize returns the size of the instance pool, which has no autoscaling.
+func getSize(spec *kops.InstanceGroupSpec) (int, error) {
+	// Follow the convention that all other CSPs have.
+	minSize := int32(1)
+	maxSize := int32(1)
+	if spec.MinSize != nil {
+		minSize = fi.Int32Value(spec.MinSize)
+	} else if spec.Role == kops.InstanceGroupRoleNode {
+		minSize = 2
+	}
+	if spec.MaxSize != nil {
+		maxSize = *spec.MaxSize
+	} else if spec.Role == kops.InstanceGroupRoleNode {
+		maxSize = 2
+	}
+	if minSize != maxSize {
+		return 0, fmt.Errorf("instance group must have the same min and max size in OCI, but got %d and %d", minSize, maxSize)
+	}
+	return int(minSize), nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+	"k8s.io/kops/upup/pkg/fi/fitasks"
+)
+
+func TestInstancePoolModelBuilder_Build(t *testing.T) {
+	ctx := newTestOCIModelContext()
+	b := InstancePoolModelBuilder{
+		OCIModelContext: ctx,
+		BootstrapScriptBuilder: &model.BootstrapScriptBuilder{
+			KopsModelContext: ctx.KopsModelContext,
+			Lifecycle:        fi.LifecycleSync,
+			Cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					Networking: &kops.NetworkingSpec{},
+				},
+			},
+		},
+	}
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+
+	for _, name := range []string{fi.CertificateIDCA, "etcd-clients-ca", "apiserver-aggregator-ca", "service-account"} {
+		c.AddTask(&fitasks.Keypair{
+			Name:    fi.String(name),
+			Subject: "cn=" + name,
+			Type:    "ca",
+		})
+	}
+
+	err := b.Build(c)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	master, ok := c.Tasks["InstancePool/master-us-ashburn-1-ad-1.masters.testcluster.test.com"].(*ocitasks.InstancePool)
+	if !ok {
+		t.Fatalf("master instance pool task not found in %v", c.Tasks)
+	}
+	if master.LoadBalancer == nil {
+		t.Errorf("expected the masters to be registered with the API load balancer")
+	}
+	if fi.Float32Value(master.OCPUs) != defaultMasterFlexOCPUs {
+		t.Errorf("unexpected OCPUs %v", fi.Float32Value(master.OCPUs))
+	}
+	if !fi.BoolValue(master.AssignPublicIP) {
+		t.Errorf("expected a public IP in a public subnet")
+	}
+
+	nodes := c.Tasks["InstancePool/nodes.testcluster.test.com"].(*ocitasks.InstancePool)
+	if nodes.LoadBalancer != nil {
+		t.Errorf("unexpected load balancer for nodes")
+	}
+}
+
+func TestGetSize(t *testing.T) {
+	testCases := []struct {
+		spec    kops.InstanceGroupSpec
+		success bool
+		size    int
+	}{
+		{
+			spec: kops.InstanceGroupSpec{
+				Role:    kops.InstanceGroupRoleMaster,
+				MinSize: fi.Int32(3),
+				MaxSize: fi.Int32(3),
+			},
+			success: true,
+			size:    3,
+		},
+		{
+			spec: kops.InstanceGroupSpec{
+				Role: kops.InstanceGroupRoleMaster,
+			},
+			success: true,
+			size:    1,
+		},
+		{
+			spec: kops.InstanceGroupSpec{
+				Role: kops.InstanceGroupRoleNode,
+			},
+			success: true,
+			size:    2,
+		},
+		{
+			spec: kops.InstanceGroupSpec{
+				Role:    kops.InstanceGroupRoleNode,
+				MinSize: fi.Int32(1),
+				MaxSize: fi.Int32(2),
+			},
+			success: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			size, err := getSize(&tc.spec)
+			if !tc.success {
+				if err == nil {
+					t.Fatalf("unexpected success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if size != tc.size {
+				t.Errorf("expected %d, but got %d", tc.size, size)
+			}
+		})
+	}
+}
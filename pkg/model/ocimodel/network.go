@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+)
+
+// NetworkModelBuilder configures a VCN, its gateway, routing and subnets.
+type NetworkModelBuilder struct {
+	*OCIModelContext
+	Lifecycle fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &NetworkModelBuilder{}
+
+// Build builds tasks for creating a VCN and subnets.
+func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	c.AddTask(&ocitasks.VCN{
+		Name:      fi.String(b.NameForVCN()),
+		Lifecycle: b.Lifecycle,
+		CIDR:      fi.String(b.Cluster.Spec.NetworkCIDR),
+		Tags:      b.CloudTags(),
+	})
+
+	c.AddTask(&ocitasks.InternetGateway{
+		Name:      fi.String(b.ClusterName()),
+		Lifecycle: b.Lifecycle,
+		VCN:       b.LinkToVCN(),
+		Tags:      b.CloudTags(),
+	})
+
+	c.AddTask(&ocitasks.RouteTable{
+		Name:            fi.String(b.ClusterName()),
+		Lifecycle:       b.Lifecycle,
+		VCN:             b.LinkToVCN(),
+		InternetGateway: b.LinkToInternetGateway(),
+		Tags:            b.CloudTags(),
+	})
+
+	c.AddTask(&ocitasks.SecurityList{
+		Name:         fi.String(b.ClusterName()),
+		Lifecycle:    b.Lifecycle,
+		VCN:          b.LinkToVCN(),
+		IngressRules: b.ingressRules(),
+		Tags:         b.CloudTags(),
+	})
+
+	for i := range b.Cluster.Spec.Subnets {
+		subnetSpec := &b.Cluster.Spec.Subnets[i]
+		switch subnetSpec.Type {
+		case kops.SubnetTypePublic, kops.SubnetTypeUtility:
+		default:
+			return fmt.Errorf("subnet %q has unsupported type %q; only public subnets are supported on OCI", subnetSpec.Name, subnetSpec.Type)
+		}
+		c.AddTask(&ocitasks.Subnet{
+			Name:         fi.String(b.NameForSubnet(subnetSpec)),
+			Lifecycle:    b.Lifecycle,
+			VCN:          b.LinkToVCN(),
+			CIDR:         fi.String(subnetSpec.CIDR),
+			RouteTable:   b.LinkToRouteTable(),
+			SecurityList: b.LinkToSecurityList(),
+			Public:       fi.Bool(true),
+			Tags:         b.CloudTags(),
+		})
+	}
+
+	return nil
+}
+
+// ingressRules allows all traffic within the VCN, and SSH and API access from the configured CIDRs.
+func (b *NetworkModelBuilder) ingressRules() []*ocitasks.SecurityListRule {
+	rules := []*ocitasks.SecurityListRule{
+		{
+			Source:   fi.String(b.Cluster.Spec.NetworkCIDR),
+			Protocol: fi.String(ocitasks.ProtocolAll),
+		},
+	}
+	for _, cidr := range b.Cluster.Spec.SSHAccess {
+		rules = append(rules, &ocitasks.SecurityListRule{
+			Source:   fi.String(cidr),
+			Protocol: fi.String(ocitasks.ProtocolTCP),
+			FromPort: fi.Int(22),
+			ToPort:   fi.Int(22),
+		})
+	}
+	for _, cidr := range b.Cluster.Spec.KubernetesAPIAccess {
+		rules = append(rules, &ocitasks.SecurityListRule{
+			Source:   fi.String(cidr),
+			Protocol: fi.String(ocitasks.ProtocolTCP),
+			FromPort: fi.Int(ocitasks.APIPort),
+			ToPort:   fi.Int(ocitasks.APIPort),
+		})
+	}
+	return rules
+}
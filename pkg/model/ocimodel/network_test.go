@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+)
+
+func TestNetworkModelBuilder_Build(t *testing.T) {
+	b := NetworkModelBuilder{
+		OCIModelContext: newTestOCIModelContext(),
+	}
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	err := b.Build(c)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	subnet, ok := c.Tasks["Subnet/us-ashburn-1.testcluster.test.com"].(*ocitasks.Subnet)
+	if !ok {
+		t.Fatalf("subnet task not found in %v", c.Tasks)
+	}
+	if !fi.BoolValue(subnet.Public) {
+		t.Errorf("expected a public subnet")
+	}
+
+	sl := c.Tasks["SecurityList/testcluster.test.com"].(*ocitasks.SecurityList)
+	if len(sl.IngressRules) != 3 {
+		t.Errorf("expected 3 ingress rules, but got %d", len(sl.IngressRules))
+	}
+}
+
+func TestNetworkModelBuilder_PrivateSubnet(t *testing.T) {
+	b := NetworkModelBuilder{
+		OCIModelContext: newTestOCIModelContext(),
+	}
+	b.Cluster.Spec.Subnets[0].Type = kops.SubnetTypePrivate
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	if err := b.Build(c); err == nil {
+		t.Errorf("expected an error for a private subnet")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocimodel
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func newTestOCIModelContext() *OCIModelContext {
+	cluster := newTestCluster()
+	return &OCIModelContext{
+		KopsModelContext: &model.KopsModelContext{
+			IAMModelContext: iam.IAMModelContext{
+				Cluster: cluster,
+			},
+			InstanceGroups: []*kops.InstanceGroup{
+				newTestInstanceGroup("master-us-ashburn-1-ad-1", kops.InstanceGroupRoleMaster),
+				newTestInstanceGroup("nodes", kops.InstanceGroupRoleNode),
+			},
+			SSHPublicKeys: [][]byte{[]byte("ssh-rsa ...")},
+		},
+	}
+}
+
+func newTestCluster() *kops.Cluster {
+	return &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "testcluster.test.com",
+		},
+		Spec: kops.ClusterSpec{
+			CloudProvider: string(kops.CloudProviderOCI),
+			API: &kops.AccessSpec{
+				LoadBalancer: &kops.LoadBalancerAccessSpec{
+					Type: kops.LoadBalancerTypePublic,
+				},
+			},
+			NetworkCIDR:         "10.0.0.0/16",
+			SSHAccess:           []string{"0.0.0.0/0"},
+			KubernetesAPIAccess: []string{"0.0.0.0/0"},
+			Subnets: []kops.ClusterSubnetSpec{
+				{
+					Name:   "us-ashburn-1",
+					CIDR:   "10.0.32.0/19",
+					Region: "us-ashburn-1",
+					Type:   kops.SubnetTypePublic,
+				},
+			},
+			CloudLabels: map[string]string{
+				"k8s.io/owner": "test",
+			},
+			CloudConfig: &kops.CloudConfiguration{
+				OCI: &kops.OCIConfiguration{
+					TagNamespace:  "kops",
+					CompartmentID: "ocid1.compartment.oc1..test",
+				},
+			},
+		},
+	}
+}
+
+func newTestInstanceGroup(name string, role kops.InstanceGroupRole) *kops.InstanceGroup {
+	return &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kops.InstanceGroupSpec{
+			Role:        role,
+			Image:       "Canonical-Ubuntu-20.04-2021.07.19-0",
+			MachineType: "VM.Standard.A1.Flex",
+			MinSize:     fi.Int32(1),
+			MaxSize:     fi.Int32(1),
+			Subnets:     []string{"us-ashburn-1"},
+			Zones:       []string{"us-ashburn-1-ad-1"},
+		},
+	}
+}
@@ -87,6 +87,10 @@ func (b *ServerGroupModelBuilder) buildInstances(c *fi.ModelBuilderContext, sg *
 		igMeta[openstack.BOOT_VOLUME_SIZE] = v
 	}
 
+	if v, ok := ig.ObjectMeta.Annotations[openstack.OS_ANNOTATION+openstack.BOOT_VOLUME_TYPE]; ok {
+		igMeta[openstack.BOOT_VOLUME_TYPE] = v
+	}
+
 	startupScript, err := b.BootstrapScriptBuilder.ResourceNodeUp(c, ig)
 	if err != nil {
 		return fmt.Errorf("could not create startup script for instance group %s: %v", ig.Name, err)
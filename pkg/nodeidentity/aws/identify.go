@@ -143,6 +143,14 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 	info := &nodeidentity.Info{
 		InstanceID: instanceID,
 		Labels:     labels,
+		LaunchTime: aws.TimeValue(instance.LaunchTime),
+	}
+
+	// Only the addresses the kubelet reports in the node status; secondary ENI addresses may belong to pods.
+	for _, address := range []*string{instance.PrivateDnsName, instance.PublicDnsName, instance.PrivateIpAddress, instance.PublicIpAddress} {
+		if aws.StringValue(address) != "" {
+			info.Addresses = append(info.Addresses, aws.StringValue(address))
+		}
 	}
 
 	for _, tag := range instance.Tags {
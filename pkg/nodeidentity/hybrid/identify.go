@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hybrid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/nodeidentity"
+	"k8s.io/kops/pkg/nodelabels"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hybrid"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// cacheTTL is how long the cluster and instance groups are cached.
+const cacheTTL = time.Hour
+
+// nodeIdentifier identifies the hybrid nodes of a cluster, and passes the other nodes to the identifier of the cloud provider.
+type nodeIdentifier struct {
+	configBase vfs.Path
+	cloud      nodeidentity.Identifier
+	cache      *vfs.Cache
+}
+
+var _ nodeidentity.Identifier = &nodeIdentifier{}
+
+// New creates and returns a nodeidentity.Identifier for clusters with hybrid nodes.
+// The machines outside the cloud provider have no providerID, so they are identified by the credential
+// they were enrolled with, and get the labels of the instance group they were enrolled into.
+func New(configBase string, cloud nodeidentity.Identifier) (nodeidentity.Identifier, error) {
+	p, err := vfs.Context.BuildVfsPath(configBase)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse ConfigBase %q: %v", configBase, err)
+	}
+
+	return &nodeIdentifier{
+		configBase: p,
+		cloud:      cloud,
+		cache:      vfs.NewCache(),
+	}, nil
+}
+
+// IdentifyNode returns the identity information of the node.
+func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.Info, error) {
+	if node.Spec.ProviderID != "" {
+		return i.cloud.IdentifyNode(ctx, node)
+	}
+
+	credential, err := hybrid.ReadCredential(i.configBase, node.Name)
+	if err != nil {
+		return nil, fmt.Errorf("providerID was not set for node %s, and it is not a hybrid node: %v", node.Name, err)
+	}
+
+	cluster, err := i.loadCluster()
+	if err != nil {
+		return nil, err
+	}
+	ig, err := i.loadInstanceGroup(credential.InstanceGroup)
+	if err != nil {
+		return nil, err
+	}
+	ig = cluster.InstanceGroupWithDefaults(ig)
+	if ig.Spec.Role != kops.InstanceGroupRoleHybrid {
+		return nil, fmt.Errorf("node %s is enrolled into instance group %q, which does not have the Hybrid role", node.Name, ig.Name)
+	}
+
+	return &nodeidentity.Info{
+		InstanceID: node.Name,
+		Labels:     nodelabels.BuildNodeLabels(cluster, ig),
+	}, nil
+}
+
+func (i *nodeIdentifier) loadCluster() (*kops.Cluster, error) {
+	p := i.configBase.Join(registry.PathClusterCompleted)
+	b, err := i.cache.Read(p, cacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("error loading Cluster %q: %v", p, err)
+	}
+
+	o, _, err := kopscodecs.Decode(b, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Cluster %q: %v", p, err)
+	}
+	if cluster, ok := o.(*kops.Cluster); ok {
+		return cluster, nil
+	}
+	return nil, fmt.Errorf("unexpected object type for Cluster %q: %T", p, o)
+}
+
+func (i *nodeIdentifier) loadInstanceGroup(name string) (*kops.InstanceGroup, error) {
+	p := i.configBase.Join("instancegroup", name)
+	b, err := i.cache.Read(p, cacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("error loading InstanceGroup %q: %v", p, err)
+	}
+
+	ig := &kops.InstanceGroup{}
+	if err := utils.YamlUnmarshal(b, ig); err != nil {
+		return nil, fmt.Errorf("error parsing InstanceGroup %q: %v", p, err)
+	}
+	return ig, nil
+}
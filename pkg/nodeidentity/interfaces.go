@@ -18,6 +18,7 @@ package nodeidentity
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 )
@@ -29,6 +30,10 @@ type Identifier interface {
 type Info struct {
 	InstanceID string
 	Labels     map[string]string
+	// LaunchTime is when the instance was launched, if the cloud provider reports it.
+	LaunchTime time.Time
+	// Addresses are the hostnames and IP addresses of the instance, if the cloud provider reports them.
+	Addresses []string
 }
 
 type LegacyIdentifier interface {
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kops/pkg/nodeidentity"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// nodeIdentifier identifies a node on metal.
+type nodeIdentifier struct {
+	configBase vfs.Path
+}
+
+var _ nodeidentity.LegacyIdentifier = &nodeIdentifier{}
+
+// New creates and returns a nodeidentity.LegacyIdentifier for Nodes running on metal.
+// There is no cloud API to query, so the instance group comes from the enrollment of the node in the state store.
+func New(configBase string) (nodeidentity.LegacyIdentifier, error) {
+	p, err := vfs.Context.BuildVfsPath(configBase)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse ConfigBase %q: %v", configBase, err)
+	}
+	return &nodeIdentifier{configBase: p}, nil
+}
+
+// IdentifyNode returns the identity information of the node from its enrollment.
+// The labels of the node are set by the node itself, so they cannot be trusted to identify it.
+func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.LegacyInfo, error) {
+	enrollment, err := metal.ReadEnrollment(i.configBase, node.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nodeidentity.LegacyInfo{
+		InstanceID:    node.Name,
+		InstanceGroup: enrollment.InstanceGroup,
+	}, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kops/pkg/nodeidentity"
+)
+
+const (
+	// InstanceGroupNameTag is the key of the freeform tag used to identify
+	// the instance group an instance belongs to.
+	InstanceGroupNameTag = "KopsInstanceGroup"
+	// providerIDPrefix is the prefix of the providerID set by the OCI cloud-controller-manager.
+	providerIDPrefix = "oci://"
+)
+
+type instanceGetter interface {
+	getInstance(ctx context.Context, id string) (*core.Instance, error)
+}
+
+type client struct {
+	computeClient *core.ComputeClient
+}
+
+var _ instanceGetter = &client{}
+
+func (c *client) getInstance(ctx context.Context, id string) (*core.Instance, error) {
+	resp, err := c.computeClient.GetInstance(ctx, core.GetInstanceRequest{
+		InstanceId: &id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Instance, nil
+}
+
+// nodeIdentifier identifies a node from OCI.
+type nodeIdentifier struct {
+	instanceGetter instanceGetter
+}
+
+var _ nodeidentity.LegacyIdentifier = &nodeIdentifier{}
+
+// New creates and returns a nodeidentity.LegacyIdentifier for Nodes running on OCI.
+// It authenticates as the instance principal of the instance it runs on.
+func New() (nodeidentity.LegacyIdentifier, error) {
+	configProvider, err := auth.InstancePrincipalConfigurationProvider()
+	if err != nil {
+		return nil, fmt.Errorf("error creating an instance principal configuration provider: %v", err)
+	}
+	computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error creating a compute client: %v", err)
+	}
+
+	return &nodeIdentifier{
+		instanceGetter: &client{computeClient: &computeClient},
+	}, nil
+}
+
+// IdentifyNode queries OCI for the node identity information.
+func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.LegacyInfo, error) {
+	providerID := node.Spec.ProviderID
+	if providerID == "" {
+		return nil, fmt.Errorf("providerID was not set for node %s", node.Name)
+	}
+	if !strings.HasPrefix(providerID, providerIDPrefix) {
+		return nil, fmt.Errorf("providerID %q not recognized", providerID)
+	}
+	instanceID := strings.TrimPrefix(providerID, providerIDPrefix)
+
+	instance, err := i.instanceGetter.getInstance(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting instance %s: %v", instanceID, err)
+	}
+	if instance.LifecycleState != core.InstanceLifecycleStateRunning {
+		return nil, fmt.Errorf("found instance %q, but state is %q", instanceID, instance.LifecycleState)
+	}
+
+	ig := instance.FreeformTags[InstanceGroupNameTag]
+	if ig == "" {
+		return nil, fmt.Errorf("could not find tag %q on instance %s", InstanceGroupNameTag, instanceID)
+	}
+
+	return &nodeidentity.LegacyInfo{
+		InstanceID:    instanceID,
+		InstanceGroup: ig,
+	}, nil
+}
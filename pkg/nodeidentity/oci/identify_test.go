@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	v1 "k8s.io/api/core/v1"
+)
+
+type mockClient struct {
+	instances map[string]*core.Instance
+}
+
+var _ instanceGetter = &mockClient{}
+
+func (c *mockClient) getInstance(ctx context.Context, id string) (*core.Instance, error) {
+	instance, ok := c.instances[id]
+	if !ok {
+		return nil, fmt.Errorf("no instance found for %s", id)
+	}
+	return instance, nil
+}
+
+func TestIdentifyNode(t *testing.T) {
+	identifier := &nodeIdentifier{
+		instanceGetter: &mockClient{
+			instances: map[string]*core.Instance{
+				"ocid1.instance.oc1.iad.running": {
+					LifecycleState: core.InstanceLifecycleStateRunning,
+					FreeformTags: map[string]string{
+						InstanceGroupNameTag: "nodes",
+					},
+				},
+				"ocid1.instance.oc1.iad.stopped": {
+					LifecycleState: core.InstanceLifecycleStateStopped,
+					FreeformTags: map[string]string{
+						InstanceGroupNameTag: "nodes",
+					},
+				},
+				"ocid1.instance.oc1.iad.untagged": {
+					LifecycleState: core.InstanceLifecycleStateRunning,
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		providerID    string
+		instanceGroup string
+		success       bool
+	}{
+		{
+			providerID:    "oci://ocid1.instance.oc1.iad.running",
+			instanceGroup: "nodes",
+			success:       true,
+		},
+		{
+			providerID: "oci://ocid1.instance.oc1.iad.stopped",
+			success:    false,
+		},
+		{
+			providerID: "oci://ocid1.instance.oc1.iad.untagged",
+			success:    false,
+		},
+		{
+			providerID: "oci://ocid1.instance.oc1.iad.unknown",
+			success:    false,
+		},
+		{
+			providerID: "aws:///instanceID",
+			success:    false,
+		},
+		{
+			providerID: "",
+			success:    false,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			node := &v1.Node{
+				Spec: v1.NodeSpec{
+					ProviderID: tc.providerID,
+				},
+			}
+			info, err := identifier.IdentifyNode(context.TODO(), node)
+			if err != nil {
+				if tc.success {
+					t.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+			if !tc.success {
+				t.Errorf("unexpected success")
+			}
+			if info.InstanceGroup != tc.instanceGroup {
+				t.Errorf("expected %s, but got %s", tc.instanceGroup, info.InstanceGroup)
+			}
+		})
+	}
+}
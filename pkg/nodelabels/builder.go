@@ -31,8 +31,13 @@ const (
 	RoleLabelMaster16    = "node-role.kubernetes.io/master"
 	RoleLabelAPIServer16 = "node-role.kubernetes.io/api-server"
 	RoleLabelNode16      = "node-role.kubernetes.io/node"
+	RoleLabelHybrid16    = "node-role.kubernetes.io/hybrid"
 
 	RoleLabelControlPlane20 = "node-role.kubernetes.io/control-plane"
+
+	// TaintInstanceGroupPending is the taint nodes register with when kops-controller applies the node labels
+	// and taints of their instance group, until it has applied them.
+	TaintInstanceGroupPending = "kops.k8s.io/instancegroup-pending"
 )
 
 // BuildNodeLabels returns the node labels for the specified instance group
@@ -72,6 +77,12 @@ func BuildNodeLabels(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) m
 		nodeLabels[RoleLabelName15] = RoleNodeLabelValue15
 	}
 
+	if instanceGroup.Spec.Role == kops.InstanceGroupRoleHybrid {
+		// Hybrid machines are not instances of the cloud provider, so its load balancers cannot target them.
+		nodeLabels[RoleLabelHybrid16] = ""
+		nodeLabels["node.kubernetes.io/exclude-from-external-load-balancers"] = ""
+	}
+
 	if isControlPlane {
 		if nodeLabels == nil {
 			nodeLabels = make(map[string]string)
@@ -111,6 +111,31 @@ func TestBuildNodeLabels(t *testing.T) {
 				"node3":         "override3",
 			},
 		},
+		{
+			name: "RoleHybrid",
+			cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					KubernetesVersion: "v1.21.0",
+					Kubelet: &kops.KubeletConfigSpec{
+						NodeLabels: map[string]string{
+							"node1": "node1",
+						},
+					},
+				},
+			},
+			ig: &kops.InstanceGroup{
+				Spec: kops.InstanceGroupSpec{
+					Role: kops.InstanceGroupRoleHybrid,
+				},
+			},
+			expected: map[string]string{
+				RoleLabelNode16:   "",
+				RoleLabelHybrid16: "",
+				RoleLabelName15:   RoleNodeLabelValue15,
+				"node.kubernetes.io/exclude-from-external-load-balancers": "",
+				"node1": "node1",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ospatch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/nodelabels"
+	"k8s.io/kubectl/pkg/drain"
+)
+
+// PatchScript updates the operating system packages with the package manager of the distribution.
+const PatchScript = `set -o errexit
+if command -v apt-get >/dev/null 2>&1; then
+  export DEBIAN_FRONTEND=noninteractive
+  apt-get update -q
+  apt-get upgrade -q -y -o Dpkg::Options::=--force-confdef -o Dpkg::Options::=--force-confold
+elif command -v dnf >/dev/null 2>&1; then
+  dnf upgrade -y
+elif command -v yum >/dev/null 2>&1; then
+  yum update -y
+elif command -v zypper >/dev/null 2>&1; then
+  zypper --non-interactive update
+else
+  echo "no supported package manager found" >&2
+  exit 1
+fi
+`
+
+// RebootScript schedules a reboot of the node a few seconds later, so that the command completes first.
+const RebootScript = `systemd-run --on-active=10 --timer-property=AccuracySec=1s systemctl reboot`
+
+// pollInterval is the time between checks of the progress of a command or a reboot.
+const pollInterval = 10 * time.Second
+
+// CommandRunner runs shell scripts as root on nodes.
+type CommandRunner interface {
+	// RunCommand runs the script on the node and waits for it to complete.
+	RunCommand(ctx context.Context, node *corev1.Node, script string) error
+}
+
+// Patcher updates the operating system packages of nodes, one node at a time.
+// Each node is drained first, honoring PodDisruptionBudgets, then rebooted if required,
+// and uncordoned once it is ready again.
+type Patcher struct {
+	// K8sClient is the client for the cluster being patched.
+	K8sClient kubernetes.Interface
+	// Runner runs the patch and reboot scripts on the nodes.
+	Runner CommandRunner
+	// Reboot is true if nodes should be rebooted after their packages are updated.
+	Reboot bool
+
+	// DrainTimeout is the maximum time spent draining a node; zero means no limit.
+	DrainTimeout time.Duration
+	// PatchTimeout is the maximum time spent updating the packages of a node.
+	PatchTimeout time.Duration
+	// RebootTimeout is the maximum time for a node to be ready again after it is rebooted.
+	RebootTimeout time.Duration
+
+	// Out receives progress messages.
+	Out io.Writer
+}
+
+// PatchNodes updates the operating system packages of the nodes in turn, stopping at the first failure.
+func (p *Patcher) PatchNodes(ctx context.Context, nodes []corev1.Node) error {
+	for i := range nodes {
+		if err := p.PatchNode(ctx, nodes[i].Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PatchNode updates the operating system packages of the named node.
+// If the packages cannot be updated, the node is uncordoned and an error is returned.
+// If the node does not become ready after it is rebooted, it is left cordoned.
+func (p *Patcher) PatchNode(ctx context.Context, nodeName string) error {
+	node, err := p.K8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting node %q: %v", nodeName, err)
+	}
+	bootID := node.Status.NodeInfo.BootID
+
+	helper := p.drainHelper(ctx)
+	fmt.Fprintf(p.Out, "Draining node %q\n", nodeName)
+	if err := drain.RunCordonOrUncordon(helper, node, true); err != nil {
+		return fmt.Errorf("error cordoning node %q: %v", nodeName, err)
+	}
+	if err := drain.RunNodeDrain(helper, nodeName); err != nil {
+		return p.uncordonAfterError(ctx, node, fmt.Errorf("error draining node %q: %v", nodeName, err))
+	}
+
+	fmt.Fprintf(p.Out, "Updating packages on node %q\n", nodeName)
+	if err := p.runCommand(ctx, node, PatchScript); err != nil {
+		return p.uncordonAfterError(ctx, node, fmt.Errorf("error updating packages on node %q: %v", nodeName, err))
+	}
+
+	if p.Reboot {
+		fmt.Fprintf(p.Out, "Rebooting node %q\n", nodeName)
+		if err := p.runCommand(ctx, node, RebootScript); err != nil {
+			return p.uncordonAfterError(ctx, node, fmt.Errorf("error rebooting node %q: %v", nodeName, err))
+		}
+		if err := p.waitForReboot(ctx, nodeName, bootID); err != nil {
+			return err
+		}
+	}
+
+	node, err = p.K8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting node %q: %v", nodeName, err)
+	}
+	if err := drain.RunCordonOrUncordon(helper, node, false); err != nil {
+		return fmt.Errorf("error uncordoning node %q: %v", nodeName, err)
+	}
+	fmt.Fprintf(p.Out, "Patched node %q\n", nodeName)
+	return nil
+}
+
+func (p *Patcher) runCommand(ctx context.Context, node *corev1.Node, script string) error {
+	if p.PatchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.PatchTimeout)
+		defer cancel()
+	}
+	return p.Runner.RunCommand(ctx, node, script)
+}
+
+// waitForReboot waits until the node reports a new boot ID and is ready.
+func (p *Patcher) waitForReboot(ctx context.Context, nodeName string, bootID string) error {
+	err := wait.PollImmediate(pollInterval, p.RebootTimeout, func() (bool, error) {
+		node, err := p.K8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			// The API server may be briefly unavailable; keep waiting.
+			return false, nil
+		}
+		if node.Status.NodeInfo.BootID == bootID {
+			return false, nil
+		}
+		return isNodeReady(node), nil
+	})
+	if err != nil {
+		return fmt.Errorf("node %q did not become ready after rebooting: %v", nodeName, err)
+	}
+	return nil
+}
+
+func (p *Patcher) uncordonAfterError(ctx context.Context, node *corev1.Node, cause error) error {
+	if err := drain.RunCordonOrUncordon(p.drainHelper(ctx), node, false); err != nil {
+		return fmt.Errorf("%v; error uncordoning node: %v", cause, err)
+	}
+	return cause
+}
+
+func (p *Patcher) drainHelper(ctx context.Context) *drain.Helper {
+	return &drain.Helper{
+		Ctx:                 ctx,
+		Client:              p.K8sClient,
+		Force:               true,
+		GracePeriodSeconds:  -1,
+		IgnoreAllDaemonSets: true,
+		DeleteEmptyDirData:  true,
+		Timeout:             p.DrainTimeout,
+		Out:                 p.Out,
+		ErrOut:              p.Out,
+	}
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// SelectNodes returns the nodes of the named instance groups, or of all instance groups if none is named.
+// Control plane nodes are only returned if their instance groups are named.
+func SelectNodes(nodes []corev1.Node, instanceGroups []string) []corev1.Node {
+	names := sets.NewString(instanceGroups...)
+
+	var selected []corev1.Node
+	for _, node := range nodes {
+		ig := node.Labels[kops.NodeLabelInstanceGroup]
+		if names.Len() != 0 {
+			if names.Has(ig) {
+				selected = append(selected, node)
+			}
+			continue
+		}
+		if _, ok := node.Labels[nodelabels.RoleLabelMaster16]; ok {
+			continue
+		}
+		if _, ok := node.Labels[nodelabels.RoleLabelControlPlane20]; ok {
+			continue
+		}
+		selected = append(selected, node)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+	return selected
+}
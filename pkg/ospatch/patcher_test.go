@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ospatch
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// fakeRunner records the scripts it runs, and simulates reboots by changing the boot ID of the node.
+type fakeRunner struct {
+	k8sClient kubernetes.Interface
+	scripts   []string
+	err       error
+}
+
+func (r *fakeRunner) RunCommand(ctx context.Context, node *corev1.Node, script string) error {
+	r.scripts = append(r.scripts, script)
+	if r.err != nil {
+		return r.err
+	}
+	if script == RebootScript {
+		n, err := r.k8sClient.CoreV1().Nodes().Get(ctx, node.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		n.Status.NodeInfo.BootID = n.Status.NodeInfo.BootID + "-rebooted"
+		if _, err := r.k8sClient.CoreV1().Nodes().UpdateStatus(ctx, n, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func makeNode(name string, ig string, labels map[string]string) corev1.Node {
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{kops.NodeLabelInstanceGroup: ig},
+		},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{BootID: "boot-" + name},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	for k, v := range labels {
+		node.Labels[k] = v
+	}
+	return node
+}
+
+func TestPatchNode(t *testing.T) {
+	grid := []struct {
+		name              string
+		reboot            bool
+		runnerErr         error
+		expectedScripts   []string
+		expectErr         bool
+		expectUnscheduled bool
+	}{
+		{
+			name:            "patch and reboot",
+			reboot:          true,
+			expectedScripts: []string{PatchScript, RebootScript},
+		},
+		{
+			name:            "patch without reboot",
+			expectedScripts: []string{PatchScript},
+		},
+		{
+			name:            "failed patch",
+			reboot:          true,
+			runnerErr:       errors.New("failed"),
+			expectedScripts: []string{PatchScript},
+			expectErr:       true,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			ctx := context.Background()
+			node := makeNode("node-1", "nodes", nil)
+			k8sClient := fake.NewSimpleClientset(&node)
+			runner := &fakeRunner{k8sClient: k8sClient, err: g.runnerErr}
+
+			patcher := &Patcher{
+				K8sClient:     k8sClient,
+				Runner:        runner,
+				Reboot:        g.reboot,
+				RebootTimeout: time.Minute,
+				Out:           ioutil.Discard,
+			}
+			err := patcher.PatchNode(ctx, node.Name)
+			if g.expectErr && err == nil {
+				t.Errorf("expected error")
+			}
+			if !g.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(runner.scripts, g.expectedScripts) {
+				t.Errorf("unexpected scripts: %q", runner.scripts)
+			}
+
+			actual, err := k8sClient.CoreV1().Nodes().Get(ctx, node.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("error getting node: %v", err)
+			}
+			if actual.Spec.Unschedulable != g.expectUnscheduled {
+				t.Errorf("expected unschedulable %v, got %v", g.expectUnscheduled, actual.Spec.Unschedulable)
+			}
+		})
+	}
+}
+
+func TestSelectNodes(t *testing.T) {
+	nodes := []corev1.Node{
+		makeNode("node-b", "nodes-b", nil),
+		makeNode("master-a", "master-a", map[string]string{"node-role.kubernetes.io/master": ""}),
+		makeNode("node-a", "nodes-a", nil),
+		makeNode("control-plane-a", "control-plane-a", map[string]string{"node-role.kubernetes.io/control-plane": ""}),
+	}
+
+	grid := []struct {
+		instanceGroups []string
+		expected       []string
+	}{
+		{
+			expected: []string{"node-a", "node-b"},
+		},
+		{
+			instanceGroups: []string{"nodes-b"},
+			expected:       []string{"node-b"},
+		},
+		{
+			instanceGroups: []string{"master-a", "nodes-a"},
+			expected:       []string{"master-a", "node-a"},
+		},
+	}
+	for _, g := range grid {
+		var actual []string
+		for _, node := range SelectNodes(nodes, g.instanceGroups) {
+			actual = append(actual, node.Name)
+		}
+		if !reflect.DeepEqual(actual, g.expected) {
+			t.Errorf("instance groups %v: expected %v, got %v", g.instanceGroups, g.expected, actual)
+		}
+	}
+}
+
+func TestInstanceIDForNode(t *testing.T) {
+	grid := []struct {
+		providerID string
+		expected   string
+	}{
+		{providerID: "aws:///us-test-1a/i-0123456789abcdef0", expected: "i-0123456789abcdef0"},
+		{providerID: ""},
+		{providerID: "gce://project/us-test1-a/node-1"},
+		{providerID: "aws:///us-test-1a"},
+	}
+	for _, g := range grid {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Spec:       corev1.NodeSpec{ProviderID: g.providerID},
+		}
+		actual, err := instanceIDForNode(node)
+		if g.expected == "" {
+			if err == nil {
+				t.Errorf("providerID %q: expected error, got %q", g.providerID, actual)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("providerID %q: unexpected error: %v", g.providerID, err)
+		} else if actual != g.expected {
+			t.Errorf("providerID %q: expected %q, got %q", g.providerID, g.expected, actual)
+		}
+	}
+}
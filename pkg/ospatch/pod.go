@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ospatch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// DefaultPodImage is the image of the pods running commands on nodes; it only needs nsenter.
+const DefaultPodImage = "busybox:1.34.1"
+
+// podLogLines is the number of lines of the output of a failed command included in the error.
+const podLogLines = 20
+
+// PodRunner runs commands on nodes with a privileged pod, which enters the namespaces of the host.
+type PodRunner struct {
+	// K8sClient is the client for the cluster.
+	K8sClient kubernetes.Interface
+	// Namespace is the namespace of the pods.
+	Namespace string
+	// Image is the image of the pods.
+	Image string
+}
+
+var _ CommandRunner = &PodRunner{}
+
+// RunCommand implements CommandRunner.RunCommand.
+func (r *PodRunner) RunCommand(ctx context.Context, node *corev1.Node, script string) error {
+	pod, err := r.K8sClient.CoreV1().Pods(r.Namespace).Create(ctx, r.buildPod(node.Name, script), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("error creating pod: %v", err)
+	}
+	defer func() {
+		// The context may have expired, so we don't use it to clean up.
+		if err := r.K8sClient.CoreV1().Pods(r.Namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{}); err != nil {
+			klog.Warningf("error deleting pod %s/%s: %v", r.Namespace, pod.Name, err)
+		}
+	}()
+
+	var phase corev1.PodPhase
+	err = wait.PollImmediateUntil(pollInterval, func() (bool, error) {
+		p, err := r.K8sClient.CoreV1().Pods(r.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase = p.Status.Phase
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	}, ctx.Done())
+	if err != nil {
+		return fmt.Errorf("error waiting for pod %s/%s: %v", r.Namespace, pod.Name, err)
+	}
+
+	if phase == corev1.PodFailed {
+		return fmt.Errorf("command failed: %s", r.podLogs(ctx, pod.Name))
+	}
+	return nil
+}
+
+// buildPod builds a pod that runs the script on the node, in the namespaces of the host.
+func (r *PodRunner) buildPod(nodeName string, script string) *corev1.Pod {
+	privileged := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kops-patch-",
+			Namespace:    r.Namespace,
+			Labels: map[string]string{
+				"k8s-app": "kops-patch",
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:      nodeName,
+			HostPID:       true,
+			RestartPolicy: corev1.RestartPolicyNever,
+			Tolerations: []corev1.Toleration{
+				{Operator: corev1.TolerationOpExists},
+			},
+			PriorityClassName: "system-node-critical",
+			Containers: []corev1.Container{
+				{
+					Name:    "patch",
+					Image:   r.Image,
+					Command: []string{"nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid", "--", "sh", "-c", script},
+					SecurityContext: &corev1.SecurityContext{
+						Privileged: &privileged,
+					},
+				},
+			},
+		},
+	}
+}
+
+// podLogs returns the last lines of the output of the pod, for reporting errors.
+func (r *PodRunner) podLogs(ctx context.Context, podName string) string {
+	tailLines := int64(podLogLines)
+	b, err := r.K8sClient.CoreV1().Pods(r.Namespace).GetLogs(podName, &corev1.PodLogOptions{TailLines: &tailLines}).DoRaw(ctx)
+	if err != nil {
+		return fmt.Sprintf("unable to get output: %v", err)
+	}
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ospatch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// SSMRunner runs commands on nodes with AWS Systems Manager Run Command.
+// The instances need the SSM agent and an instance profile that allows it to register.
+type SSMRunner struct {
+	// SSM is the Systems Manager client for the region of the cluster.
+	SSM ssmiface.SSMAPI
+}
+
+var _ CommandRunner = &SSMRunner{}
+
+// RunCommand implements CommandRunner.RunCommand.
+func (r *SSMRunner) RunCommand(ctx context.Context, node *corev1.Node, script string) error {
+	instanceID, err := instanceIDForNode(node)
+	if err != nil {
+		return err
+	}
+
+	request := &ssm.SendCommandInput{
+		DocumentName: aws.String("AWS-RunShellScript"),
+		InstanceIds:  []*string{aws.String(instanceID)},
+		Comment:      aws.String("kops: patch node " + node.Name),
+		Parameters: map[string][]*string{
+			"commands": {aws.String(script)},
+		},
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		// The execution timeout of the command is a string of seconds.
+		request.Parameters["executionTimeout"] = []*string{aws.String(fmt.Sprintf("%d", int64(time.Until(deadline).Seconds())))}
+	}
+	response, err := r.SSM.SendCommandWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("error sending command to instance %q: %v", instanceID, err)
+	}
+	commandID := aws.StringValue(response.Command.CommandId)
+
+	var invocation *ssm.GetCommandInvocationOutput
+	err = wait.PollUntil(pollInterval, func() (bool, error) {
+		invocation, err = r.SSM.GetCommandInvocationWithContext(ctx, &ssm.GetCommandInvocationInput{
+			CommandId:  aws.String(commandID),
+			InstanceId: aws.String(instanceID),
+		})
+		if err != nil {
+			if awsup.AWSErrorCode(err) == ssm.ErrCodeInvocationDoesNotExist {
+				// The invocation is not visible immediately after the command is sent.
+				return false, nil
+			}
+			return false, err
+		}
+		switch aws.StringValue(invocation.Status) {
+		case ssm.CommandInvocationStatusPending, ssm.CommandInvocationStatusInProgress, ssm.CommandInvocationStatusDelayed:
+			return false, nil
+		}
+		return true, nil
+	}, ctx.Done())
+	if err != nil {
+		return fmt.Errorf("error waiting for command %q on instance %q: %v", commandID, instanceID, err)
+	}
+
+	if aws.StringValue(invocation.Status) != ssm.CommandInvocationStatusSuccess {
+		return fmt.Errorf("command %q on instance %q finished with status %s: %s", commandID, instanceID,
+			aws.StringValue(invocation.Status), strings.TrimSpace(aws.StringValue(invocation.StandardErrorContent)))
+	}
+	return nil
+}
+
+// instanceIDForNode returns the EC2 instance ID of the node, from its provider ID.
+func instanceIDForNode(node *corev1.Node) (string, error) {
+	providerID := node.Spec.ProviderID
+	if providerID == "" {
+		return "", fmt.Errorf("providerID was not set for node %s", node.Name)
+	}
+	if !strings.HasPrefix(providerID, "aws://") {
+		return "", fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(providerID, "aws://"), "/")
+	if len(tokens) != 3 || !strings.HasPrefix(tokens[2], "i-") {
+		return "", fmt.Errorf("providerID %q not recognized for node %s", providerID, node.Name)
+	}
+	return tokens[2], nil
+}
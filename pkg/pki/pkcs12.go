@@ -0,0 +1,422 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	crypto_rand "crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// pkcs12Iterations is the number of iterations of the key derivations, as used by OpenSSL.
+	pkcs12Iterations = 2048
+	// pkcs12SaltSize is the size of the salts of the key derivations.
+	pkcs12SaltSize = 16
+)
+
+var (
+	oidDataContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedDataContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+	oidCertBag                  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidPKCS8ShroudedKeyBag      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertTypeX509Certificate  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidLocalKeyID               = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidJavaTrustStore           = asn1.ObjectIdentifier{2, 16, 840, 1, 113894, 746875, 1, 1}
+	oidAnyExtendedKeyUsage      = asn1.ObjectIdentifier{2, 5, 29, 37, 0}
+	oidPBES2                    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2                   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHmacWithSHA256           = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES256CBC                = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidSHA256                   = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+)
+
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+type encryptedData struct {
+	Version              int
+	EncryptedContentInfo encryptedContentInfo
+}
+
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int
+}
+
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue     `asn1:"tag:0,explicit"`
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	Prf        pkix.AlgorithmIdentifier
+}
+
+// EncodePKCS12 encodes a certificate, its private key and the certificates of its CAs as a PKCS#12 archive,
+// such as consumed by Java keystores and secret managers. The certificates and the private key are encrypted
+// with the password using PBES2 with PBKDF2-HMAC-SHA-256 and AES-256-CBC, and the archive is authenticated
+// with HMAC-SHA-256, as OpenSSL 3 does by default. When privateKey is nil, only the certificates are encoded,
+// as a trust store.
+func EncodePKCS12(certificate *Certificate, privateKey *PrivateKey, caCertificates []*Certificate, password string) ([]byte, error) {
+	if password == "" {
+		return nil, fmt.Errorf("a password is required to encode a PKCS#12 archive")
+	}
+	if privateKey != nil && certificate == nil {
+		return nil, fmt.Errorf("a private key requires a certificate")
+	}
+
+	var certBags []safeBag
+	var keyBags []safeBag
+
+	// Java only reads the certificates of trust stores that are marked as trusted
+	var trustedAttributes []pkcs12Attribute
+	if privateKey == nil {
+		attribute, err := newPKCS12Attribute(oidJavaTrustStore, oidAnyExtendedKeyUsage)
+		if err != nil {
+			return nil, err
+		}
+		trustedAttributes = []pkcs12Attribute{attribute}
+	}
+
+	var localKeyID []pkcs12Attribute
+	if certificate != nil {
+		attributes := trustedAttributes
+		if privateKey != nil {
+			sum := sha1.Sum(certificate.Certificate.Raw)
+			attribute, err := newPKCS12Attribute(oidLocalKeyID, sum[:])
+			if err != nil {
+				return nil, err
+			}
+			localKeyID = []pkcs12Attribute{attribute}
+			attributes = localKeyID
+		}
+
+		bag, err := newCertBag(certificate, attributes)
+		if err != nil {
+			return nil, err
+		}
+		certBags = append(certBags, bag)
+	}
+	for _, ca := range caCertificates {
+		bag, err := newCertBag(ca, trustedAttributes)
+		if err != nil {
+			return nil, err
+		}
+		certBags = append(certBags, bag)
+	}
+	if len(certBags) == 0 {
+		return nil, fmt.Errorf("no certificates to encode")
+	}
+
+	if privateKey != nil {
+		pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey.Key)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding private key: %v", err)
+		}
+		algorithm, encrypted, err := pbes2Encrypt(password, pkcs8)
+		if err != nil {
+			return nil, err
+		}
+		keyInfo, err := asn1.Marshal(encryptedPrivateKeyInfo{
+			Algorithm:     algorithm,
+			EncryptedData: encrypted,
+		})
+		if err != nil {
+			return nil, err
+		}
+		keyBags = append(keyBags, safeBag{
+			ID:         oidPKCS8ShroudedKeyBag,
+			Value:      asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: keyInfo},
+			Attributes: localKeyID,
+		})
+	}
+
+	// The certificates are encrypted as a whole; the key bag is encrypted itself.
+	certsContentInfo, err := newEncryptedDataContentInfo(certBags, password)
+	if err != nil {
+		return nil, err
+	}
+	authenticatedSafe := []contentInfo{certsContentInfo}
+	if len(keyBags) != 0 {
+		keysContentInfo, err := newDataContentInfo(keyBags)
+		if err != nil {
+			return nil, err
+		}
+		authenticatedSafe = append(authenticatedSafe, keysContentInfo)
+	}
+	authenticatedSafeBytes, err := asn1.Marshal(authenticatedSafe)
+	if err != nil {
+		return nil, err
+	}
+
+	macSalt, err := randomBytes(pkcs12SaltSize)
+	if err != nil {
+		return nil, err
+	}
+	macKey := pkcs12KDF(macSalt, bmpString(password), pkcs12Iterations, 3, sha256.Size)
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(authenticatedSafeBytes)
+
+	authSafeContent, err := asn1.Marshal(authenticatedSafeBytes)
+	if err != nil {
+		return nil, err
+	}
+	pfx := pfxPdu{
+		Version: 3,
+		AuthSafe: contentInfo{
+			ContentType: oidDataContentType,
+			Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: authSafeContent},
+		},
+		MacData: macData{
+			Mac: digestInfo{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue},
+				Digest:    mac.Sum(nil),
+			},
+			MacSalt:    macSalt,
+			Iterations: pkcs12Iterations,
+		},
+	}
+	return asn1.Marshal(pfx)
+}
+
+func newPKCS12Attribute(id asn1.ObjectIdentifier, value interface{}) (pkcs12Attribute, error) {
+	encoded, err := asn1.Marshal(value)
+	if err != nil {
+		return pkcs12Attribute{}, err
+	}
+	return pkcs12Attribute{
+		ID:    id,
+		Value: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: encoded},
+	}, nil
+}
+
+func newCertBag(certificate *Certificate, attributes []pkcs12Attribute) (safeBag, error) {
+	data, err := asn1.Marshal(certBag{ID: oidCertTypeX509Certificate, Data: certificate.Certificate.Raw})
+	if err != nil {
+		return safeBag{}, err
+	}
+	return safeBag{
+		ID:         oidCertBag,
+		Value:      asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: data},
+		Attributes: attributes,
+	}, nil
+}
+
+func newDataContentInfo(bags []safeBag) (contentInfo, error) {
+	safeContents, err := asn1.Marshal(bags)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	data, err := asn1.Marshal(safeContents)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return contentInfo{
+		ContentType: oidDataContentType,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: data},
+	}, nil
+}
+
+func newEncryptedDataContentInfo(bags []safeBag, password string) (contentInfo, error) {
+	safeContents, err := asn1.Marshal(bags)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	algorithm, encrypted, err := pbes2Encrypt(password, safeContents)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	data, err := asn1.Marshal(encryptedData{
+		Version: 0,
+		EncryptedContentInfo: encryptedContentInfo{
+			ContentType:                oidDataContentType,
+			ContentEncryptionAlgorithm: algorithm,
+			EncryptedContent:           encrypted,
+		},
+	})
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return contentInfo{
+		ContentType: oidEncryptedDataContentType,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: data},
+	}, nil
+}
+
+// pbes2Encrypt encrypts data with PBES2, using PBKDF2-HMAC-SHA-256 and AES-256-CBC as specified in RFC 8018,
+// returning the algorithm identifier with its parameters.
+func pbes2Encrypt(password string, data []byte) (pkix.AlgorithmIdentifier, []byte, error) {
+	salt, err := randomBytes(pkcs12SaltSize)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+
+	// PBES2 takes the password as UTF-8, unlike the PKCS#12 key derivation
+	key := pbkdf2.Key([]byte(password), salt, pkcs12Iterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+
+	padding := block.BlockSize() - len(data)%block.BlockSize()
+	padded := append(append([]byte{}, data...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:       salt,
+		Iterations: pkcs12Iterations,
+		Prf:        pkix.AlgorithmIdentifier{Algorithm: oidHmacWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	encodedIV, err := asn1.Marshal(iv)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: encodedIV}},
+	})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	return pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}}, encrypted, nil
+}
+
+// pkcs12KDF derives key material from a password as specified in RFC 7292 appendix B.2, with SHA-256.
+func pkcs12KDF(salt, password []byte, iterations int, id byte, size int) []byte {
+	const v = 64
+
+	fill := func(pattern []byte) []byte {
+		if len(pattern) == 0 {
+			return nil
+		}
+		n := v * ((len(pattern) + v - 1) / v)
+		return bytes.Repeat(pattern, (n+len(pattern)-1)/len(pattern))[:n]
+	}
+
+	d := bytes.Repeat([]byte{id}, v)
+	i := append(fill(salt), fill(password)...)
+
+	var result []byte
+	for len(result) < size {
+		h := sha256.Sum256(append(append([]byte{}, d...), i...))
+		a := h[:]
+		for j := 1; j < iterations; j++ {
+			h = sha256.Sum256(a)
+			a = h[:]
+		}
+		result = append(result, a...)
+
+		// I_j = (I_j + B + 1) mod 2^(8v) for each v-byte block of I
+		b := new(big.Int).SetBytes(fill(a)[:v])
+		b.Add(b, big.NewInt(1))
+		for j := 0; j < len(i); j += v {
+			ij := new(big.Int).SetBytes(i[j : j+v])
+			ij.Add(ij, b)
+			ijBytes := ij.Bytes()
+			if len(ijBytes) > v {
+				ijBytes = ijBytes[len(ijBytes)-v:]
+			}
+			block := i[j : j+v]
+			for k := range block {
+				block[k] = 0
+			}
+			copy(block[v-len(ijBytes):], ijBytes)
+		}
+	}
+	return result[:size]
+}
+
+// bmpString encodes a password as a null-terminated big-endian UTF-16 string.
+func bmpString(s string) []byte {
+	var encoded []byte
+	for _, r := range utf16.Encode([]rune(s)) {
+		encoded = append(encoded, byte(r>>8), byte(r))
+	}
+	return append(encoded, 0, 0)
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := crypto_rand.Read(b); err != nil {
+		return nil, fmt.Errorf("error generating random bytes: %v", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// decodedPKCS12 holds the contents of a PKCS#12 archive
+type decodedPKCS12 struct {
+	certificates []*x509.Certificate
+	privateKeys  []crypto.Signer
+	trusted      int
+}
+
+// decodePKCS12 verifies and decrypts a PKCS#12 archive as encoded by EncodePKCS12
+func decodePKCS12(data []byte, password string) (*decodedPKCS12, error) {
+	var pfx pfxPdu
+	if _, err := asn1.Unmarshal(data, &pfx); err != nil {
+		return nil, err
+	}
+	var authenticatedSafeBytes []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &authenticatedSafeBytes); err != nil {
+		return nil, err
+	}
+
+	if !pfx.MacData.Mac.Algorithm.Algorithm.Equal(oidSHA256) {
+		return nil, fmt.Errorf("unexpected MAC algorithm %v", pfx.MacData.Mac.Algorithm.Algorithm)
+	}
+	macKey := pkcs12KDF(pfx.MacData.MacSalt, bmpString(password), pfx.MacData.Iterations, 3, sha256.Size)
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(authenticatedSafeBytes)
+	if !hmac.Equal(mac.Sum(nil), pfx.MacData.Mac.Digest) {
+		return nil, fmt.Errorf("incorrect password")
+	}
+
+	var authenticatedSafe []contentInfo
+	if _, err := asn1.Unmarshal(authenticatedSafeBytes, &authenticatedSafe); err != nil {
+		return nil, err
+	}
+
+	decoded := &decodedPKCS12{}
+	for _, ci := range authenticatedSafe {
+		var safeContents []byte
+		switch {
+		case ci.ContentType.Equal(oidDataContentType):
+			if _, err := asn1.Unmarshal(ci.Content.Bytes, &safeContents); err != nil {
+				return nil, err
+			}
+		case ci.ContentType.Equal(oidEncryptedDataContentType):
+			var ed encryptedData
+			if _, err := asn1.Unmarshal(ci.Content.Bytes, &ed); err != nil {
+				return nil, err
+			}
+			var err error
+			safeContents, err = pbes2Decrypt(ed.EncryptedContentInfo.ContentEncryptionAlgorithm, ed.EncryptedContentInfo.EncryptedContent, password)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected content type %v", ci.ContentType)
+		}
+
+		var bags []safeBag
+		if _, err := asn1.Unmarshal(safeContents, &bags); err != nil {
+			return nil, err
+		}
+		for _, bag := range bags {
+			for _, attribute := range bag.Attributes {
+				if attribute.ID.Equal(oidJavaTrustStore) {
+					decoded.trusted++
+				}
+			}
+			switch {
+			case bag.ID.Equal(oidCertBag):
+				var cb certBag
+				if _, err := asn1.Unmarshal(bag.Value.Bytes, &cb); err != nil {
+					return nil, err
+				}
+				certificate, err := x509.ParseCertificate(cb.Data)
+				if err != nil {
+					return nil, err
+				}
+				decoded.certificates = append(decoded.certificates, certificate)
+			case bag.ID.Equal(oidPKCS8ShroudedKeyBag):
+				var keyInfo encryptedPrivateKeyInfo
+				if _, err := asn1.Unmarshal(bag.Value.Bytes, &keyInfo); err != nil {
+					return nil, err
+				}
+				pkcs8, err := pbes2Decrypt(keyInfo.Algorithm, keyInfo.EncryptedData, password)
+				if err != nil {
+					return nil, err
+				}
+				key, err := x509.ParsePKCS8PrivateKey(pkcs8)
+				if err != nil {
+					return nil, err
+				}
+				decoded.privateKeys = append(decoded.privateKeys, key.(crypto.Signer))
+			default:
+				return nil, fmt.Errorf("unexpected bag %v", bag.ID)
+			}
+		}
+	}
+	return decoded, nil
+}
+
+// pbes2Decrypt decrypts data encrypted by pbes2Encrypt
+func pbes2Decrypt(algorithm pkix.AlgorithmIdentifier, data []byte, password string) ([]byte, error) {
+	if !algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unexpected encryption algorithm %v", algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, err
+	}
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, err
+	}
+	if !kdfParams.Prf.Algorithm.Equal(oidHmacWithSHA256) || !params.EncryptionScheme.Algorithm.Equal(oidAES256CBC) {
+		return nil, fmt.Errorf("unexpected PBES2 parameters %v and %v", kdfParams.Prf.Algorithm, params.EncryptionScheme.Algorithm)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(password), kdfParams.Salt, kdfParams.Iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, data)
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(decrypted[len(decrypted)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return decrypted[:len(decrypted)-padding], nil
+}
+
+func TestEncodePKCS12(t *testing.T) {
+	caCertificate, caPrivateKey, _, err := IssueCert(&IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "Test CA"},
+	}, nil)
+	require.NoError(t, err)
+	certificate, privateKey, _, err := IssueCert(&IssueCertRequest{
+		Signer:   "ca",
+		Type:     "client",
+		Subject:  pkix.Name{CommonName: "client"},
+		Validity: time.Hour,
+	}, &mockKeystore{t: t, signer: "ca", cert: caCertificate, key: caPrivateKey})
+	require.NoError(t, err)
+
+	data, err := EncodePKCS12(certificate, privateKey, nil, "secret")
+	require.NoError(t, err)
+	decoded, err := decodePKCS12(data, "secret")
+	require.NoError(t, err)
+	if assert.Len(t, decoded.certificates, 1, "certificates") {
+		assert.Equal(t, certificate.Certificate.Raw, decoded.certificates[0].Raw, "certificate")
+	}
+	if assert.Len(t, decoded.privateKeys, 1, "private keys") {
+		assert.Equal(t, privateKey.Key.Public(), decoded.privateKeys[0].Public(), "private key")
+	}
+	assert.Equal(t, 0, decoded.trusted, "trusted certificates")
+
+	_, err = decodePKCS12(data, "wrong")
+	assert.EqualError(t, err, "incorrect password")
+
+	data, err = EncodePKCS12(certificate, privateKey, []*Certificate{caCertificate}, "secret")
+	require.NoError(t, err)
+	decoded, err = decodePKCS12(data, "secret")
+	require.NoError(t, err)
+	if assert.Len(t, decoded.certificates, 2, "certificates with CA") {
+		assert.Equal(t, certificate.Certificate.Raw, decoded.certificates[0].Raw, "certificate with CA")
+		assert.Equal(t, caCertificate.Certificate.Raw, decoded.certificates[1].Raw, "CA certificate")
+	}
+
+	data, err = EncodePKCS12(nil, nil, []*Certificate{caCertificate}, "secret")
+	require.NoError(t, err)
+	decoded, err = decodePKCS12(data, "secret")
+	require.NoError(t, err)
+	if assert.Len(t, decoded.certificates, 1, "trust store certificates") {
+		assert.Equal(t, caCertificate.Certificate.Raw, decoded.certificates[0].Raw, "trust store certificate")
+	}
+	assert.Empty(t, decoded.privateKeys, "trust store private keys")
+	assert.Equal(t, 1, decoded.trusted, "trusted certificates")
+
+	_, err = EncodePKCS12(certificate, privateKey, nil, "")
+	assert.Error(t, err, "empty password")
+}
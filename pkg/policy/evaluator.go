@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Query is the rule that policies add their violations to, as messages
+const Query = "data.kops.deny"
+
+// Evaluator evaluates Rego policies with the opa command
+type Evaluator struct {
+	// OPA is the path of the opa command; if empty, it is looked up in the PATH
+	OPA string
+	// Policies are the files or directories holding the policies
+	Policies []string
+}
+
+// evalOutput is the output of opa eval --format json
+type evalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value interface{} `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// Evaluate returns the violations of the policies by the input, sorted
+func (e *Evaluator) Evaluate(ctx context.Context, input *Input) ([]string, error) {
+	opa := e.OPA
+	if opa == "" {
+		path, err := exec.LookPath("opa")
+		if err != nil {
+			return nil, fmt.Errorf("the opa command is needed to evaluate policies, see https://www.openpolicyagent.org/docs/latest/#running-opa")
+		}
+		opa = path
+	}
+
+	args := []string{"eval", "--format", "json", "--stdin-input"}
+	for _, p := range e.Policies {
+		if _, err := os.Stat(p); err != nil {
+			return nil, fmt.Errorf("error reading policy %q: %v", p, err)
+		}
+		args = append(args, "--data", p)
+	}
+	args = append(args, Query)
+
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("error building policy input: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, opa, args...)
+	cmd.Stdin = bytes.NewReader(inputJSON)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error evaluating policies: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseViolations(stdout.Bytes())
+}
+
+// parseViolations returns the violations in the output of opa eval.
+// Violations are either messages, or objects with a msg field.
+// An undefined deny rule is an error, so that policies in the wrong package do not pass silently.
+func parseViolations(output []byte) ([]string, error) {
+	var result evalOutput
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("error parsing output of opa eval: %v", err)
+	}
+	if len(result.Result) == 0 {
+		return nil, fmt.Errorf("%s is undefined; policies must define deny in package kops", Query)
+	}
+
+	var violations []string
+	for _, r := range result.Result {
+		for _, expression := range r.Expressions {
+			values, ok := expression.Value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s must be a set of violations, got %v", Query, expression.Value)
+			}
+			for _, value := range values {
+				switch v := value.(type) {
+				case string:
+					violations = append(violations, v)
+				case map[string]interface{}:
+					if msg, ok := v["msg"].(string); ok {
+						violations = append(violations, msg)
+						continue
+					}
+					data, _ := json.Marshal(v)
+					violations = append(violations, string(data))
+				default:
+					data, _ := json.Marshal(v)
+					violations = append(violations, string(data))
+				}
+			}
+		}
+	}
+	sort.Strings(violations)
+	return violations, nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// Input is the document policies are evaluated against
+type Input struct {
+	// Cluster is the cluster, with its spec fully populated
+	Cluster interface{} `json:"cluster"`
+	// InstanceGroups are the instance groups of the cluster
+	InstanceGroups []interface{} `json:"instanceGroups"`
+	// Tasks are the tasks kOps plans to apply, by key (<Type>/<Name>)
+	Tasks map[string]*Task `json:"tasks"`
+}
+
+// Task is a planned task, as seen by policies
+type Task struct {
+	// Type is the type of the task, for example LaunchTemplate
+	Type string `json:"type"`
+	// Name is the name of the task
+	Name string `json:"name"`
+	// Spec holds the fields of the task. References to other tasks are replaced by their keys,
+	// and resources such as user data are omitted.
+	Spec map[string]interface{} `json:"spec"`
+}
+
+// BuildInput builds the document policies are evaluated against
+func BuildInput(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, tasks map[string]fi.Task) (*Input, error) {
+	input := &Input{
+		Tasks: make(map[string]*Task),
+	}
+
+	var err error
+	if input.Cluster, err = toGeneric(cluster); err != nil {
+		return nil, fmt.Errorf("error converting cluster: %v", err)
+	}
+
+	input.InstanceGroups = []interface{}{}
+	for _, ig := range instanceGroups {
+		v, err := toGeneric(ig)
+		if err != nil {
+			return nil, fmt.Errorf("error converting instance group %q: %v", ig.ObjectMeta.Name, err)
+		}
+		input.InstanceGroups = append(input.InstanceGroups, v)
+	}
+
+	for key, task := range tasks {
+		t := &Task{
+			Type: fi.TypeNameForTask(task),
+			Spec: map[string]interface{}{},
+		}
+		if hasName, ok := task.(fi.HasName); ok {
+			t.Name = fi.StringValue(hasName.GetName())
+		}
+		if spec, ok := taskValue(reflect.ValueOf(task), true, map[uintptr]bool{}).(map[string]interface{}); ok {
+			t.Spec = spec
+		}
+		input.Tasks[key] = t
+	}
+
+	return input, nil
+}
+
+// toGeneric converts an API object to the generic form of its JSON representation
+func toGeneric(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+var resourceType = reflect.TypeOf((*fi.Resource)(nil)).Elem()
+var taskType = reflect.TypeOf((*fi.Task)(nil)).Elem()
+
+// taskValue converts a field of a task to a generic value.
+// Other tasks are referenced by their key rather than nested, except for the task itself (top).
+// Pointers already being converted (visiting) are omitted, so that cyclic references terminate.
+func taskValue(v reflect.Value, top bool, visiting map[uintptr]bool) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Type().Implements(resourceType) && !v.Type().Implements(taskType) {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if !top && v.Type().Implements(taskType) {
+			if task, ok := v.Interface().(fi.Task); ok {
+				if hasName, ok := task.(fi.HasName); ok {
+					return fi.TypeNameForTask(task) + "/" + fi.StringValue(hasName.GetName())
+				}
+			}
+		}
+		if v.Kind() == reflect.Ptr {
+			p := v.Pointer()
+			if visiting[p] {
+				return nil
+			}
+			visiting[p] = true
+			defer delete(visiting, p)
+		}
+		return taskValue(v.Elem(), top, visiting)
+
+	case reflect.Struct:
+		fields := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				// Unexported
+				continue
+			}
+			value := taskValue(v.Field(i), false, visiting)
+			if value == nil {
+				continue
+			}
+			if field.Anonymous {
+				if embedded, ok := value.(map[string]interface{}); ok {
+					for k, ev := range embedded {
+						fields[k] = ev
+					}
+					continue
+				}
+			}
+			fields[field.Name] = value
+		}
+		return fields
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// Binary data, such as certificates
+			return nil
+		}
+		values := []interface{}{}
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, taskValue(v.Index(i), false, visiting))
+		}
+		return values
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		values := make(map[string]interface{})
+		for _, k := range v.MapKeys() {
+			values[fmt.Sprint(k.Interface())] = taskValue(v.MapIndex(k), false, visiting)
+		}
+		return values
+
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+
+	default:
+		// Functions, channels and the like
+		return nil
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type Network struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+	CIDR      *string
+}
+
+func (n *Network) GetName() *string      { return n.Name }
+func (n *Network) Run(*fi.Context) error { return nil }
+
+type Peering struct {
+	Name   *string
+	Config *PeeringConfig
+}
+
+type PeeringConfig struct {
+	Peer *PeeringConfig
+	MTU  *int32
+}
+
+func (p *Peering) GetName() *string      { return p.Name }
+func (p *Peering) Run(*fi.Context) error { return nil }
+
+type Server struct {
+	Name           *string
+	Lifecycle      fi.Lifecycle
+	Network        *Network
+	SecurityGroups []*Network
+	PublicIP       *bool
+	RootVolumeSize *int32
+	UserData       fi.Resource
+	Tags           map[string]string
+	Certificate    []byte
+}
+
+func (s *Server) GetName() *string      { return s.Name }
+func (s *Server) Run(*fi.Context) error { return nil }
+
+func TestBuildInput(t *testing.T) {
+	network := &Network{Name: fi.String("main"), Lifecycle: fi.LifecycleSync, CIDR: fi.String("10.0.0.0/16")}
+	server := &Server{
+		Name:           fi.String("nodes"),
+		Lifecycle:      fi.LifecycleSync,
+		Network:        network,
+		SecurityGroups: []*Network{network},
+		PublicIP:       fi.Bool(true),
+		RootVolumeSize: fi.Int32(64),
+		UserData:       fi.NewStringResource("#!/bin/bash"),
+		Tags:           map[string]string{"team": "infra"},
+		Certificate:    []byte("secret"),
+	}
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+		Spec:       kops.ClusterSpec{KubernetesVersion: "1.21.0"},
+	}
+	instanceGroups := []*kops.InstanceGroup{
+		{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}, Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode}},
+	}
+
+	input, err := BuildInput(cluster, instanceGroups, map[string]fi.Task{
+		"Network/main": network,
+		"Server/nodes": server,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error building input: %v", err)
+	}
+
+	// Policies see the JSON form of the input
+	data, err := json.Marshal(input)
+	if err != nil {
+		t.Fatalf("error marshaling input: %v", err)
+	}
+	var actual map[string]interface{}
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("error unmarshaling input: %v", err)
+	}
+
+	expectedTasks := map[string]interface{}{
+		"Network/main": map[string]interface{}{
+			"type": "Network",
+			"name": "main",
+			"spec": map[string]interface{}{"Name": "main", "Lifecycle": "Sync", "CIDR": "10.0.0.0/16"},
+		},
+		"Server/nodes": map[string]interface{}{
+			"type": "Server",
+			"name": "nodes",
+			"spec": map[string]interface{}{
+				"Name":           "nodes",
+				"Lifecycle":      "Sync",
+				"Network":        "Network/main",
+				"SecurityGroups": []interface{}{"Network/main"},
+				"PublicIP":       true,
+				"RootVolumeSize": float64(64),
+				"Tags":           map[string]interface{}{"team": "infra"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(actual["tasks"], expectedTasks) {
+		t.Errorf("unexpected tasks\nexpected: %v\nactual:   %v", expectedTasks, actual["tasks"])
+	}
+
+	spec := actual["cluster"].(map[string]interface{})["spec"].(map[string]interface{})
+	if spec["kubernetesVersion"] != "1.21.0" {
+		t.Errorf("expected the cluster spec in the input, got %v", actual["cluster"])
+	}
+	if len(actual["instanceGroups"].([]interface{})) != 1 {
+		t.Errorf("expected one instance group in the input, got %v", actual["instanceGroups"])
+	}
+}
+
+func TestBuildInputCyclicReference(t *testing.T) {
+	config := &PeeringConfig{MTU: fi.Int32(1500)}
+	config.Peer = &PeeringConfig{Peer: config}
+	peering := &Peering{Name: fi.String("peering"), Config: config}
+
+	input, err := BuildInput(&kops.Cluster{}, nil, map[string]fi.Task{"Peering/peering": peering})
+	if err != nil {
+		t.Fatalf("unexpected error building input: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"Name": "peering",
+		"Config": map[string]interface{}{
+			"Peer": map[string]interface{}{},
+			"MTU":  int64(1500),
+		},
+	}
+	if !reflect.DeepEqual(input.Tasks["Peering/peering"].Spec, expected) {
+		t.Errorf("unexpected spec\nexpected: %v\nactual:   %v", expected, input.Tasks["Peering/peering"].Spec)
+	}
+}
+
+func TestParseViolations(t *testing.T) {
+	grid := []struct {
+		Output      string
+		Expected    []string
+		ExpectError bool
+	}{
+		{
+			Output:      `{}`,
+			ExpectError: true,
+		},
+		{
+			Output:   `{"result":[{"expressions":[{"value":[]}]}]}`,
+			Expected: nil,
+		},
+		{
+			Output:   `{"result":[{"expressions":[{"value":["volumes must be encrypted","nodes must not have public IPs"],"text":"data.kops.deny"}]}]}`,
+			Expected: []string{"nodes must not have public IPs", "volumes must be encrypted"},
+		},
+		{
+			Output:   `{"result":[{"expressions":[{"value":[{"msg":"no public IPs","task":"LaunchTemplate/nodes"},{"task":"EBSVolume/a"}]}]}]}`,
+			Expected: []string{"no public IPs", `{"task":"EBSVolume/a"}`},
+		},
+		{
+			Output:      `{"result":[{"expressions":[{"value":true}]}]}`,
+			ExpectError: true,
+		},
+		{
+			Output:      `not json`,
+			ExpectError: true,
+		},
+	}
+	for _, g := range grid {
+		violations, err := parseViolations([]byte(g.Output))
+		if g.ExpectError {
+			if err == nil {
+				t.Errorf("expected error parsing %q", g.Output)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %v", g.Output, err)
+			continue
+		}
+		if !reflect.DeepEqual(violations, g.Expected) {
+			t.Errorf("expected violations %q parsing %q, got %q", g.Expected, g.Output, violations)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake opa command is a shell script")
+	}
+
+	dir := t.TempDir()
+	policyPath := filepath.Join(dir, "policy.rego")
+	if err := ioutil.WriteFile(policyPath, []byte("package kops\n"), 0644); err != nil {
+		t.Fatalf("error writing policy: %v", err)
+	}
+	opa := filepath.Join(dir, "opa")
+	script := `#!/bin/sh
+echo "$@" > "` + dir + `/args"
+cat > "` + dir + `/input"
+echo '{"result":[{"expressions":[{"value":["nodes must not have public IPs"]}]}]}'
+`
+	if err := ioutil.WriteFile(opa, []byte(script), 0755); err != nil {
+		t.Fatalf("error writing fake opa: %v", err)
+	}
+
+	evaluator := &Evaluator{OPA: opa, Policies: []string{policyPath}}
+	violations, err := evaluator.Evaluate(context.Background(), &Input{Tasks: map[string]*Task{}})
+	if err != nil {
+		t.Fatalf("unexpected error evaluating: %v", err)
+	}
+	if !reflect.DeepEqual(violations, []string{"nodes must not have public IPs"}) {
+		t.Errorf("unexpected violations: %q", violations)
+	}
+
+	args, err := ioutil.ReadFile(filepath.Join(dir, "args"))
+	if err != nil {
+		t.Fatalf("error reading arguments of opa: %v", err)
+	}
+	expectedArgs := "eval --format json --stdin-input --data " + policyPath + " data.kops.deny"
+	if strings.TrimSpace(string(args)) != expectedArgs {
+		t.Errorf("expected opa %s, got opa %s", expectedArgs, args)
+	}
+
+	input, err := ioutil.ReadFile(filepath.Join(dir, "input"))
+	if err != nil {
+		t.Fatalf("error reading input of opa: %v", err)
+	}
+	if !strings.Contains(string(input), `"tasks":{}`) {
+		t.Errorf("unexpected input of opa: %s", input)
+	}
+
+	evaluator.Policies = []string{filepath.Join(dir, "missing.rego")}
+	if _, err := evaluator.Evaluate(context.Background(), &Input{}); err == nil {
+		t.Errorf("expected error evaluating a missing policy")
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// Quota is a limit of an AWS account, usually managed by the Service Quotas service.
+type Quota struct {
+	// ServiceCode and QuotaCode identify the quota in the Service Quotas service; they are empty for fixed limits.
+	ServiceCode string
+	QuotaCode   string
+	// Name is the name of the quota.
+	Name string
+	// Unit is what the quota counts, e.g. "vCPUs".
+	Unit string
+}
+
+func (q *Quota) String() string {
+	if q.QuotaCode == "" {
+		return q.Name
+	}
+	return fmt.Sprintf("%s (%s/%s)", q.Name, q.ServiceCode, q.QuotaCode)
+}
+
+var (
+	StandardOnDemandVCPUs = &Quota{ServiceCode: "ec2", QuotaCode: "L-1216C47A", Name: "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances", Unit: "vCPUs"}
+	FOnDemandVCPUs        = &Quota{ServiceCode: "ec2", QuotaCode: "L-74FC7D96", Name: "Running On-Demand F instances", Unit: "vCPUs"}
+	GOnDemandVCPUs        = &Quota{ServiceCode: "ec2", QuotaCode: "L-DB2E81BA", Name: "Running On-Demand G and VT instances", Unit: "vCPUs"}
+	InfOnDemandVCPUs      = &Quota{ServiceCode: "ec2", QuotaCode: "L-1945791B", Name: "Running On-Demand Inf instances", Unit: "vCPUs"}
+	POnDemandVCPUs        = &Quota{ServiceCode: "ec2", QuotaCode: "L-417A185B", Name: "Running On-Demand P instances", Unit: "vCPUs"}
+	XOnDemandVCPUs        = &Quota{ServiceCode: "ec2", QuotaCode: "L-7295265B", Name: "Running On-Demand X instances", Unit: "vCPUs"}
+
+	StandardSpotVCPUs = &Quota{ServiceCode: "ec2", QuotaCode: "L-34B43A08", Name: "All Standard (A, C, D, H, I, M, R, T, Z) Spot Instance Requests", Unit: "vCPUs"}
+	FSpotVCPUs        = &Quota{ServiceCode: "ec2", QuotaCode: "L-88CF9481", Name: "All F Spot Instance Requests", Unit: "vCPUs"}
+	GSpotVCPUs        = &Quota{ServiceCode: "ec2", QuotaCode: "L-3819A6DF", Name: "All G and VT Spot Instance Requests", Unit: "vCPUs"}
+	InfSpotVCPUs      = &Quota{ServiceCode: "ec2", QuotaCode: "L-B5D1601B", Name: "All Inf Spot Instance Requests", Unit: "vCPUs"}
+	PSpotVCPUs        = &Quota{ServiceCode: "ec2", QuotaCode: "L-7212CCBC", Name: "All P Spot Instance Requests", Unit: "vCPUs"}
+	XSpotVCPUs        = &Quota{ServiceCode: "ec2", QuotaCode: "L-E3A00192", Name: "All X Spot Instance Requests", Unit: "vCPUs"}
+
+	ElasticIPs            = &Quota{ServiceCode: "ec2", QuotaCode: "L-0263D0A3", Name: "EC2-VPC Elastic IPs", Unit: "addresses"}
+	NATGatewaysPerZone    = &Quota{ServiceCode: "vpc", QuotaCode: "L-FE5A380F", Name: "NAT gateways per Availability Zone", Unit: "NAT gateways"}
+	RulesPerSecurityGroup = &Quota{ServiceCode: "vpc", QuotaCode: "L-0EA8095F", Name: "Inbound or outbound rules per security group", Unit: "rules"}
+
+	// VolumeAttachmentsPerInstance is the number of EBS volumes, network interfaces and instance store volumes
+	// that can be attached to an instance; it depends on the instance type and cannot be increased.
+	VolumeAttachmentsPerInstance = &Quota{Name: "Volume attachments per instance", Unit: "attachments"}
+)
+
+// Requirement is the use of a quota by the cluster.
+type Requirement struct {
+	Quota *Quota
+	// Scope restricts the quota, e.g. to an availability zone or a security group; it is empty for regional quotas.
+	Scope string
+	// Minimum is the amount of the quota the cluster needs with its instance groups at their minimum size.
+	Minimum float64
+	// Maximum is the amount of the quota the cluster needs with its instance groups at their maximum size.
+	Maximum float64
+	// InUse is the amount of the quota used by other resources of the account.
+	InUse float64
+	// Limit is the value of quotas that are not managed by the Service Quotas service.
+	Limit *float64
+}
+
+func (r *Requirement) String() string {
+	if r.Scope == "" {
+		return r.Quota.String()
+	}
+	return fmt.Sprintf("%s for %s", r.Quota, r.Scope)
+}
+
+// Shortfall is a requirement that the quota does not cover.
+type Shortfall struct {
+	*Requirement
+	// Limit is the value of the quota.
+	Limit float64
+	// Blocking is true when the quota does not cover the minimum the cluster needs, rather than only its maximum.
+	Blocking bool
+}
+
+// DesiredValue is the value of the quota that covers the maximum the cluster needs.
+func (s *Shortfall) DesiredValue() float64 {
+	return s.InUse + s.Maximum
+}
+
+// Requestable returns true if an increase of the quota can be requested.
+func (s *Shortfall) Requestable() bool {
+	return s.Quota.QuotaCode != ""
+}
+
+func (s *Shortfall) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: the cluster needs %v %s", s.Requirement, s.Minimum, s.Quota.Unit)
+	if s.Maximum != s.Minimum {
+		fmt.Fprintf(&b, " at its minimum size and %v at its maximum size", s.Maximum)
+	}
+	if s.InUse != 0 {
+		fmt.Fprintf(&b, ", %v are used by other resources", s.InUse)
+	}
+	fmt.Fprintf(&b, ", but the quota is %v", s.Limit)
+	if s.Requestable() {
+		fmt.Fprintf(&b, "; request an increase with \"aws service-quotas request-service-quota-increase --service-code %s --quota-code %s --desired-value %v\"",
+			s.Quota.ServiceCode, s.Quota.QuotaCode, s.DesiredValue())
+	} else {
+		fmt.Fprintf(&b, "; use fewer %s or a larger instance type", s.Quota.Unit)
+	}
+	return b.String()
+}
+
+// Checker compares requirements with the quotas of the AWS account.
+type Checker struct {
+	Cloud awsup.AWSCloud
+
+	limits map[*Quota]*float64
+}
+
+// Check returns the requirements that the quotas do not cover.
+// Quotas that cannot be read, e.g. because of missing permissions, are skipped with a warning.
+func (c *Checker) Check(requirements []*Requirement) []*Shortfall {
+	var shortfalls []*Shortfall
+	for _, r := range requirements {
+		limit := r.Limit
+		if limit == nil {
+			limit = c.getLimit(r.Quota)
+		}
+		if limit == nil {
+			continue
+		}
+		if r.InUse+r.Maximum <= *limit {
+			continue
+		}
+		shortfalls = append(shortfalls, &Shortfall{
+			Requirement: r,
+			Limit:       *limit,
+			Blocking:    r.InUse+r.Minimum > *limit,
+		})
+	}
+	return shortfalls
+}
+
+// getLimit returns the applied value of the quota, or its default value if it was never changed.
+func (c *Checker) getLimit(quota *Quota) *float64 {
+	if limit, found := c.limits[quota]; found {
+		return limit
+	}
+	if c.limits == nil {
+		c.limits = make(map[*Quota]*float64)
+	}
+
+	var limit *float64
+	response, err := c.Cloud.ServiceQuotas().GetServiceQuota(&servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(quota.ServiceCode),
+		QuotaCode:   aws.String(quota.QuotaCode),
+	})
+	if isNoSuchResource(err) {
+		var defaultResponse *servicequotas.GetAWSDefaultServiceQuotaOutput
+		defaultResponse, err = c.Cloud.ServiceQuotas().GetAWSDefaultServiceQuota(&servicequotas.GetAWSDefaultServiceQuotaInput{
+			ServiceCode: aws.String(quota.ServiceCode),
+			QuotaCode:   aws.String(quota.QuotaCode),
+		})
+		if err == nil {
+			limit = defaultResponse.Quota.Value
+		}
+	} else if err == nil {
+		limit = response.Quota.Value
+	}
+
+	if isNoSuchResource(err) {
+		klog.V(2).Infof("quota %s not found, skipping", quota)
+	} else if err != nil {
+		klog.Warningf("unable to read quota %s, skipping: %v", quota, err)
+	}
+	c.limits[quota] = limit
+	return limit
+}
+
+// RequestIncrease requests an increase of the quota to its desired value.
+// It returns false if an increase of the quota was already requested.
+func (c *Checker) RequestIncrease(s *Shortfall) (bool, error) {
+	_, err := c.Cloud.ServiceQuotas().RequestServiceQuotaIncrease(&servicequotas.RequestServiceQuotaIncreaseInput{
+		ServiceCode:  aws.String(s.Quota.ServiceCode),
+		QuotaCode:    aws.String(s.Quota.QuotaCode),
+		DesiredValue: aws.Float64(s.DesiredValue()),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == servicequotas.ErrCodeResourceAlreadyExistsException {
+			return false, nil
+		}
+		return false, fmt.Errorf("error requesting an increase of quota %s: %v", s.Quota, err)
+	}
+	return true, nil
+}
+
+func isNoSuchResource(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == servicequotas.ErrCodeNoSuchResourceException
+}
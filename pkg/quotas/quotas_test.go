@@ -0,0 +1,249 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/cloudmock/aws/mockservicequotas"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+func TestCheckQuotas(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-test-1", "ab")
+	mockEC2 := &mockec2.MockEC2{
+		Addresses: map[string]*ec2.Address{
+			"eipalloc-1": {AllocationId: aws.String("eipalloc-1"), PublicIp: aws.String("192.0.2.1")},
+			"eipalloc-2": {AllocationId: aws.String("eipalloc-2"), PublicIp: aws.String("192.0.2.2")},
+			"eipalloc-3": {AllocationId: aws.String("eipalloc-3"), PublicIp: aws.String("192.0.2.3")},
+			"eipalloc-4": {AllocationId: aws.String("eipalloc-4"), PublicIp: aws.String("192.0.2.4")},
+		},
+	}
+	cloud.MockEC2 = mockEC2
+	mockServiceQuotas := &mockservicequotas.MockServiceQuotas{
+		Quotas: map[string]float64{
+			"ec2/L-1216C47A": 10,
+			"ec2/L-0263D0A3": 5,
+			"vpc/L-FE5A380F": 1,
+			"vpc/L-0EA8095F": 60,
+		},
+	}
+	cloud.MockServiceQuotas = mockServiceQuotas
+
+	subnetA := &awstasks.Subnet{Name: aws.String("us-test-1a.example.com"), AvailabilityZone: aws.String("us-test-1a")}
+	subnetB := &awstasks.Subnet{Name: aws.String("us-test-1b.example.com"), AvailabilityZone: aws.String("us-test-1b")}
+	nodesSG := &awstasks.SecurityGroup{Name: aws.String("nodes.example.com")}
+	mastersSG := &awstasks.SecurityGroup{Name: aws.String("masters.example.com")}
+
+	tasks := map[string]fi.Task{
+		"AutoscalingGroup/nodes.example.com": &awstasks.AutoscalingGroup{
+			Name:           aws.String("nodes.example.com"),
+			MinSize:        fi.Int64(2),
+			MaxSize:        fi.Int64(8),
+			LaunchTemplate: &awstasks.LaunchTemplate{InstanceType: aws.String("m5.large")},
+		},
+		"AutoscalingGroup/spot.example.com": &awstasks.AutoscalingGroup{
+			Name:                   aws.String("spot.example.com"),
+			MinSize:                fi.Int64(1),
+			MaxSize:                fi.Int64(3),
+			MixedInstanceOverrides: []string{"m5.large", "c5.large"},
+			MixedOnDemandBase:      fi.Int64(1),
+			MixedOnDemandAboveBase: fi.Int64(0),
+			LaunchTemplate:         &awstasks.LaunchTemplate{InstanceType: aws.String("m5.large")},
+		},
+		"AutoscalingGroup/master-us-test-1a.masters.example.com": &awstasks.AutoscalingGroup{
+			Name:    aws.String("master-us-test-1a.masters.example.com"),
+			MinSize: fi.Int64(1),
+			MaxSize: fi.Int64(1),
+			LaunchTemplate: &awstasks.LaunchTemplate{
+				InstanceType:        aws.String("m5.large"),
+				BlockDeviceMappings: []*awstasks.BlockDeviceMapping{{DeviceName: aws.String("/dev/sdf")}},
+			},
+			Tags: map[string]string{"k8s.io/role/master": "1"},
+		},
+		"EBSVolume/a.etcd-main.example.com": &awstasks.EBSVolume{
+			Tags: map[string]string{"k8s.io/etcd/main": "a/a", "k8s.io/role/master": "1"},
+		},
+		"EBSVolume/a.etcd-events.example.com": &awstasks.EBSVolume{
+			Tags: map[string]string{"k8s.io/etcd/events": "a/a", "k8s.io/role/master": "1"},
+		},
+		"ElasticIP/us-test-1a.example.com":  &awstasks.ElasticIP{Name: aws.String("us-test-1a.example.com")},
+		"ElasticIP/us-test-1b.example.com":  &awstasks.ElasticIP{Name: aws.String("us-test-1b.example.com")},
+		"ElasticIP/shared":                  &awstasks.ElasticIP{Name: aws.String("shared"), ID: aws.String("eipalloc-1")},
+		"ElasticIP/ignored":                 &awstasks.ElasticIP{Name: aws.String("ignored"), Lifecycle: fi.LifecycleIgnore},
+		"NatGateway/us-test-1a.example.com": &awstasks.NatGateway{Name: aws.String("us-test-1a.example.com"), Subnet: subnetA},
+		"NatGateway/us-test-1b.example.com": &awstasks.NatGateway{Name: aws.String("us-test-1b.example.com"), Subnet: subnetB},
+		"SecurityGroupRule/ssh-external-to-node-0.0.0.0/0": &awstasks.SecurityGroupRule{
+			SecurityGroup: nodesSG,
+			CIDR:          aws.String("0.0.0.0/0"),
+		},
+		"SecurityGroupRule/from-masters-to-nodes": &awstasks.SecurityGroupRule{
+			SecurityGroup: nodesSG,
+			SourceGroup:   mastersSG,
+		},
+		"SecurityGroupRule/egress-nodes": &awstasks.SecurityGroupRule{
+			SecurityGroup: nodesSG,
+			CIDR:          aws.String("0.0.0.0/0"),
+			Egress:        fi.Bool(true),
+		},
+	}
+
+	requirements, err := BuildRequirements(cloud, "example.com", tasks)
+	if err != nil {
+		t.Fatalf("unexpected error building requirements: %v", err)
+	}
+
+	expected := map[string][3]float64{
+		StandardOnDemandVCPUs.String():                       {8, 20, 0},
+		StandardSpotVCPUs.String():                           {0, 4, 0},
+		ElasticIPs.String():                                  {2, 2, 4},
+		NATGatewaysPerZone.String() + " for zone us-test-1a": {1, 1, 0},
+		NATGatewaysPerZone.String() + " for zone us-test-1b": {1, 1, 0},
+		RulesPerSecurityGroup.String() + ` for IPv4 inbound rules of security group "nodes.example.com"`:                    {2, 2, 0},
+		RulesPerSecurityGroup.String() + ` for IPv6 inbound rules of security group "nodes.example.com"`:                    {1, 1, 0},
+		RulesPerSecurityGroup.String() + ` for IPv4 outbound rules of security group "nodes.example.com"`:                   {1, 1, 0},
+		VolumeAttachmentsPerInstance.String() + ` for autoscaling group "master-us-test-1a.masters.example.com" (m5.large)`: {5, 5, 0},
+		VolumeAttachmentsPerInstance.String() + ` for autoscaling group "nodes.example.com" (m5.large)`:                     {2, 2, 0},
+		VolumeAttachmentsPerInstance.String() + ` for autoscaling group "spot.example.com" (m5.large)`:                      {2, 2, 0},
+	}
+	actual := make(map[string][3]float64)
+	for _, r := range requirements {
+		actual[r.String()] = [3]float64{r.Minimum, r.Maximum, r.InUse}
+	}
+	for name, values := range expected {
+		if actual[name] != values {
+			t.Errorf("requirement %s: expected minimum, maximum and in use %v, got %v", name, values, actual[name])
+		}
+	}
+	if len(actual) != len(expected) {
+		t.Errorf("expected %d requirements, got %v", len(expected), actual)
+	}
+
+	checker := &Checker{Cloud: cloud}
+	shortfalls := checker.Check(requirements)
+	if len(shortfalls) != 2 {
+		t.Fatalf("expected 2 shortfalls, got %v", shortfalls)
+	}
+
+	vcpus, addresses := shortfalls[0], shortfalls[1]
+	if vcpus.Quota != StandardOnDemandVCPUs || vcpus.Blocking || vcpus.DesiredValue() != 20 {
+		t.Errorf("unexpected vCPU shortfall: %s", vcpus)
+	}
+	if addresses.Quota != ElasticIPs || !addresses.Blocking || addresses.DesiredValue() != 6 {
+		t.Errorf("unexpected Elastic IP shortfall: %s", addresses)
+	}
+	if !strings.Contains(addresses.String(), "--service-code ec2 --quota-code L-0263D0A3 --desired-value 6") {
+		t.Errorf("expected the shortfall to explain how to request an increase, got %q", addresses)
+	}
+
+	requested, err := checker.RequestIncrease(addresses)
+	if err != nil || !requested {
+		t.Fatalf("expected the increase to be requested, got %v, %v", requested, err)
+	}
+	requested, err = checker.RequestIncrease(addresses)
+	if err != nil || requested {
+		t.Errorf("expected the increase to be already requested, got %v, %v", requested, err)
+	}
+	if len(mockServiceQuotas.Requests) != 1 || aws.Float64Value(mockServiceQuotas.Requests[0].DesiredValue) != 6 {
+		t.Errorf("unexpected quota increase requests: %v", mockServiceQuotas.Requests)
+	}
+}
+
+func TestInstanceFamily(t *testing.T) {
+	grid := []struct {
+		name     string
+		info     *ec2.InstanceTypeInfo
+		expected string
+	}{
+		{
+			name:     "general purpose",
+			info:     instanceTypeInfo(2, 8*1024),
+			expected: familyStandard,
+		},
+		{
+			name: "FPGA",
+			info: &ec2.InstanceTypeInfo{
+				FpgaInfo: &ec2.FpgaInfo{Fpgas: []*ec2.FpgaDeviceInfo{{Manufacturer: aws.String("Xilinx"), Name: aws.String("Virtex UltraScale (VU9P)")}}},
+			},
+			expected: familyF,
+		},
+		{
+			name: "inference",
+			info: &ec2.InstanceTypeInfo{
+				InferenceAcceleratorInfo: &ec2.InferenceAcceleratorInfo{Accelerators: []*ec2.InferenceDeviceInfo{{Manufacturer: aws.String("AWS"), Name: aws.String("Inferentia")}}},
+			},
+			expected: familyInf,
+		},
+		{
+			name:     "graphics",
+			info:     &ec2.InstanceTypeInfo{GpuInfo: &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{{Manufacturer: aws.String("NVIDIA"), Name: aws.String("T4")}}}},
+			expected: familyG,
+		},
+		{
+			name:     "AMD graphics",
+			info:     &ec2.InstanceTypeInfo{GpuInfo: &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{{Manufacturer: aws.String("AMD"), Name: aws.String("Radeon Pro V520")}}}},
+			expected: familyG,
+		},
+		{
+			name:     "training",
+			info:     &ec2.InstanceTypeInfo{GpuInfo: &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{{Manufacturer: aws.String("NVIDIA"), Name: aws.String("A100")}}}},
+			expected: familyP,
+		},
+		{
+			name:     "other accelerators",
+			info:     &ec2.InstanceTypeInfo{GpuInfo: &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{{Manufacturer: aws.String("Habana"), Name: aws.String("Gaudi HL-205")}}}},
+			expected: "",
+		},
+		{
+			name:     "memory optimized",
+			info:     instanceTypeInfo(4, 64*1024),
+			expected: familyX,
+		},
+		{
+			name:     "high memory",
+			info:     instanceTypeInfo(448, 6*1024*1024),
+			expected: "",
+		},
+		{
+			name: "mac",
+			info: &ec2.InstanceTypeInfo{
+				ProcessorInfo: &ec2.ProcessorInfo{SupportedArchitectures: aws.StringSlice([]string{"x86_64_mac"})},
+			},
+			expected: "",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			if actual := instanceFamily(g.info); actual != g.expected {
+				t.Errorf("expected family %q, got %q", g.expected, actual)
+			}
+		})
+	}
+}
+
+func instanceTypeInfo(vcpus int64, memoryMiB int64) *ec2.InstanceTypeInfo {
+	return &ec2.InstanceTypeInfo{
+		MemoryInfo: &ec2.MemoryInfo{SizeInMiB: aws.Int64(memoryMiB)},
+		VCpuInfo:   &ec2.VCpuInfo{DefaultVCpus: aws.Int64(vcpus)},
+	}
+}
@@ -0,0 +1,473 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+const (
+	// nitroAttachmentLimit is the number of EBS volumes, network interfaces and instance store volumes that can be attached to most Nitro instances.
+	nitroAttachmentLimit = 28
+	// xenAttachmentLimit is the number of EBS volumes above which Xen instances may fail to boot.
+	xenAttachmentLimit = 40
+)
+
+// The families of instance types that have their own quotas of vCPUs.
+const (
+	familyStandard = "Standard"
+	familyF        = "F"
+	familyG        = "G"
+	familyInf      = "Inf"
+	familyP        = "P"
+	familyX        = "X"
+)
+
+const (
+	// highMemoryMiB is the memory above which instance types are High Memory (U) instances.
+	highMemoryMiB = 6 * 1024 * 1024
+	// xMemoryPerVCPUMiB is the memory per vCPU above which instance types are memory optimized X instances.
+	xMemoryPerVCPUMiB = 15 * 1024
+)
+
+// pGPUs are the GPUs of the accelerated computing instance types that count against the P quotas, rather than the G quotas.
+var pGPUs = map[string]bool{
+	"K80":  true,
+	"V100": true,
+	"A100": true,
+	"H100": true,
+}
+
+// onDemandQuotas are the quotas of the vCPUs of running On-Demand instances, by instance family.
+var onDemandQuotas = map[string]*Quota{
+	familyStandard: StandardOnDemandVCPUs,
+	familyF:        FOnDemandVCPUs,
+	familyG:        GOnDemandVCPUs,
+	familyInf:      InfOnDemandVCPUs,
+	familyP:        POnDemandVCPUs,
+	familyX:        XOnDemandVCPUs,
+}
+
+// spotQuotas are the quotas of the vCPUs of Spot instance requests, by instance family.
+var spotQuotas = map[string]*Quota{
+	familyStandard: StandardSpotVCPUs,
+	familyF:        FSpotVCPUs,
+	familyG:        GSpotVCPUs,
+	familyInf:      InfSpotVCPUs,
+	familyP:        PSpotVCPUs,
+	familyX:        XSpotVCPUs,
+}
+
+// BuildRequirements computes the quotas that the planned tasks of the cluster need, and how much of them
+// is used by the resources of the region that are not part of the cluster.
+func BuildRequirements(cloud awsup.AWSCloud, clusterName string, tasks map[string]fi.Task) ([]*Requirement, error) {
+	b := &requirementsBuilder{
+		cloud:        cloud,
+		clusterName:  clusterName,
+		requirements: make(map[string]*Requirement),
+	}
+
+	var names []string
+	for name, task := range tasks {
+		if hasLifecycle, ok := task.(fi.HasLifecycle); ok && hasLifecycle.GetLifecycle() == fi.LifecycleIgnore {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Every master attaches one volume of each etcd cluster
+	etcdClusters := make(map[string]bool)
+	for _, name := range names {
+		if volume, ok := tasks[name].(*awstasks.EBSVolume); ok {
+			for key := range volume.Tags {
+				if strings.HasPrefix(key, awsup.TagNameEtcdClusterPrefix) {
+					etcdClusters[key] = true
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		switch t := tasks[name].(type) {
+		case *awstasks.AutoscalingGroup:
+			if err := b.addAutoscalingGroup(t, len(etcdClusters)); err != nil {
+				return nil, err
+			}
+
+		case *awstasks.ElasticIP:
+			if t.ID == nil && !fi.BoolValue(t.Shared) {
+				b.add(ElasticIPs, "", 1, 1)
+			}
+
+		case *awstasks.NatGateway:
+			if t.ID == nil && !fi.BoolValue(t.Shared) && t.Subnet != nil && t.Subnet.AvailabilityZone != nil {
+				b.add(NATGatewaysPerZone, fmt.Sprintf("zone %s", fi.StringValue(t.Subnet.AvailabilityZone)), 1, 1)
+			}
+
+		case *awstasks.SecurityGroupRule:
+			b.addSecurityGroupRule(t)
+		}
+	}
+
+	if err := b.addInstanceUsage(); err != nil {
+		return nil, err
+	}
+	if err := b.addAddressUsage(); err != nil {
+		return nil, err
+	}
+	if err := b.addNatGatewayUsage(); err != nil {
+		return nil, err
+	}
+
+	var requirements []*Requirement
+	for _, key := range b.keys {
+		requirements = append(requirements, b.requirements[key])
+	}
+	return requirements, nil
+}
+
+type requirementsBuilder struct {
+	cloud       awsup.AWSCloud
+	clusterName string
+
+	keys         []string
+	requirements map[string]*Requirement
+}
+
+func (b *requirementsBuilder) get(quota *Quota, scope string) *Requirement {
+	return b.requirements[quota.Name+"/"+scope]
+}
+
+func (b *requirementsBuilder) add(quota *Quota, scope string, minimum, maximum float64) *Requirement {
+	key := quota.Name + "/" + scope
+	r := b.requirements[key]
+	if r == nil {
+		r = &Requirement{Quota: quota, Scope: scope}
+		b.requirements[key] = r
+		b.keys = append(b.keys, key)
+	}
+	r.Minimum += minimum
+	r.Maximum += maximum
+	return r
+}
+
+func (b *requirementsBuilder) addAutoscalingGroup(t *awstasks.AutoscalingGroup, etcdClusters int) error {
+	if t.LaunchTemplate == nil {
+		return nil
+	}
+
+	instanceTypes := t.MixedInstanceOverrides
+	if len(instanceTypes) == 0 && t.LaunchTemplate.InstanceType != nil {
+		instanceTypes = []string{fi.StringValue(t.LaunchTemplate.InstanceType)}
+	}
+
+	// The instance type with the most vCPUs is the worst case
+	var instanceType string
+	var info *ec2.InstanceTypeInfo
+	for _, it := range instanceTypes {
+		i, err := b.cloud.DescribeInstanceType(it)
+		if err != nil {
+			return fmt.Errorf("error describing instance type %q: %v", it, err)
+		}
+		if info == nil || instanceTypeVCPUs(i) > instanceTypeVCPUs(info) {
+			instanceType, info = it, i
+		}
+	}
+	if info == nil {
+		return nil
+	}
+	vcpus := float64(instanceTypeVCPUs(info))
+
+	minSize := fi.Int64Value(t.MinSize)
+	maxSize := fi.Int64Value(t.MaxSize)
+	onDemandMin, onDemandMax := minSize, maxSize
+	if fi.StringValue(t.LaunchTemplate.SpotPrice) != "" {
+		onDemandMin, onDemandMax = 0, 0
+	} else if len(t.MixedInstanceOverrides) != 0 {
+		onDemandMin = onDemandInstances(t, minSize)
+		onDemandMax = onDemandInstances(t, maxSize)
+	}
+
+	family := instanceFamily(info)
+	if quota := onDemandQuotas[family]; quota != nil && onDemandMax != 0 {
+		b.add(quota, "", float64(onDemandMin)*vcpus, float64(onDemandMax)*vcpus)
+	}
+	if quota := spotQuotas[family]; quota != nil && maxSize != onDemandMax {
+		b.add(quota, "", float64(minSize-onDemandMin)*vcpus, float64(maxSize-onDemandMax)*vcpus)
+	}
+
+	if info.Hypervisor != nil {
+		// The root volume and the additional volumes of the instance group
+		attachments := 1 + len(t.LaunchTemplate.BlockDeviceMappings)
+		if t.Tags[awsup.TagNameRolePrefix+awsup.TagRoleMaster] == "1" {
+			attachments += etcdClusters
+		}
+		limit := float64(xenAttachmentLimit)
+		if aws.StringValue(info.Hypervisor) == ec2.InstanceTypeHypervisorNitro {
+			limit = nitroAttachmentLimit
+			// The primary network interface and the NVMe instance store volumes share the limit
+			attachments++
+			if info.InstanceStorageInfo != nil {
+				for _, disk := range info.InstanceStorageInfo.Disks {
+					attachments += int(aws.Int64Value(disk.Count))
+				}
+			}
+		}
+		r := b.add(VolumeAttachmentsPerInstance, fmt.Sprintf("autoscaling group %q (%s)", fi.StringValue(t.Name), instanceType), float64(attachments), float64(attachments))
+		r.Limit = &limit
+	}
+
+	return nil
+}
+
+func (b *requirementsBuilder) addSecurityGroupRule(t *awstasks.SecurityGroupRule) {
+	if t.SecurityGroup == nil {
+		return
+	}
+
+	direction := "inbound"
+	if fi.BoolValue(t.Egress) {
+		direction = "outbound"
+	}
+
+	// Rules that reference a security group count against both the IPv4 and the IPv6 rules
+	var families []string
+	if t.CIDR != nil || t.SourceGroup != nil {
+		families = append(families, "IPv4")
+	}
+	if t.IPv6CIDR != nil || t.SourceGroup != nil {
+		families = append(families, "IPv6")
+	}
+	for _, family := range families {
+		b.add(RulesPerSecurityGroup, fmt.Sprintf("%s %s rules of security group %q", family, direction, fi.StringValue(t.SecurityGroup.Name)), 1, 1)
+	}
+}
+
+// addInstanceUsage adds the vCPUs of the running instances that are not part of the cluster.
+func (b *requirementsBuilder) addInstanceUsage() error {
+	needed := false
+	for family := range onDemandQuotas {
+		if b.has(onDemandQuotas[family]) || b.has(spotQuotas[family]) {
+			needed = true
+		}
+	}
+	if !needed {
+		return nil
+	}
+
+	request := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			awsup.NewEC2Filter("instance-state-name", ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning),
+		},
+	}
+	var describeErr error
+	err := b.cloud.EC2().DescribeInstancesPages(request, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if b.isClusterResource(instance.Tags) {
+					continue
+				}
+				if instance.State == nil {
+					continue
+				}
+				state := aws.StringValue(instance.State.Name)
+				if state != ec2.InstanceStateNamePending && state != ec2.InstanceStateNameRunning {
+					continue
+				}
+				quotas := onDemandQuotas
+				if aws.StringValue(instance.InstanceLifecycle) == ec2.InstanceLifecycleTypeSpot {
+					quotas = spotQuotas
+				}
+				if instance.CpuOptions == nil {
+					continue
+				}
+				info, err := b.cloud.DescribeInstanceType(aws.StringValue(instance.InstanceType))
+				if err != nil {
+					describeErr = fmt.Errorf("error describing instance type %q: %v", aws.StringValue(instance.InstanceType), err)
+					return false
+				}
+				quota := quotas[instanceFamily(info)]
+				if quota == nil {
+					continue
+				}
+				if r := b.get(quota, ""); r != nil {
+					r.InUse += float64(aws.Int64Value(instance.CpuOptions.CoreCount) * aws.Int64Value(instance.CpuOptions.ThreadsPerCore))
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("error listing instances: %v", err)
+	}
+	return describeErr
+}
+
+// addAddressUsage adds the Elastic IPs that are not part of the cluster.
+func (b *requirementsBuilder) addAddressUsage() error {
+	r := b.get(ElasticIPs, "")
+	if r == nil {
+		return nil
+	}
+
+	response, err := b.cloud.EC2().DescribeAddresses(&ec2.DescribeAddressesInput{})
+	if err != nil {
+		return fmt.Errorf("error listing Elastic IPs: %v", err)
+	}
+	for _, address := range response.Addresses {
+		if !b.isClusterResource(address.Tags) {
+			r.InUse++
+		}
+	}
+	return nil
+}
+
+// addNatGatewayUsage adds the NAT gateways that are not part of the cluster, in the zones of the NAT gateways of the cluster.
+func (b *requirementsBuilder) addNatGatewayUsage() error {
+	if !b.has(NATGatewaysPerZone) {
+		return nil
+	}
+
+	subnetIDs := make(map[string]int)
+	err := b.cloud.EC2().DescribeNatGatewaysPages(&ec2.DescribeNatGatewaysInput{}, func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+		for _, ngw := range page.NatGateways {
+			state := aws.StringValue(ngw.State)
+			if state != ec2.NatGatewayStatePending && state != ec2.NatGatewayStateAvailable {
+				continue
+			}
+			if !b.isClusterResource(ngw.Tags) {
+				subnetIDs[aws.StringValue(ngw.SubnetId)]++
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("error listing NAT gateways: %v", err)
+	}
+	if len(subnetIDs) == 0 {
+		return nil
+	}
+
+	request := &ec2.DescribeSubnetsInput{}
+	for id := range subnetIDs {
+		request.SubnetIds = append(request.SubnetIds, aws.String(id))
+	}
+	response, err := b.cloud.EC2().DescribeSubnets(request)
+	if err != nil {
+		return fmt.Errorf("error listing the subnets of NAT gateways: %v", err)
+	}
+	for _, subnet := range response.Subnets {
+		if r := b.get(NATGatewaysPerZone, fmt.Sprintf("zone %s", aws.StringValue(subnet.AvailabilityZone))); r != nil {
+			r.InUse += float64(subnetIDs[aws.StringValue(subnet.SubnetId)])
+		}
+	}
+	return nil
+}
+
+// has returns true if the cluster needs the quota.
+func (b *requirementsBuilder) has(quota *Quota) bool {
+	for _, r := range b.requirements {
+		if r.Quota == quota {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *requirementsBuilder) isClusterResource(tags []*ec2.Tag) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == awsup.TagClusterName && aws.StringValue(tag.Value) == b.clusterName {
+			return true
+		}
+	}
+	return false
+}
+
+// onDemandInstances returns how many of the instances of a mixed instances autoscaling group are On-Demand instances.
+func onDemandInstances(t *awstasks.AutoscalingGroup, instances int64) int64 {
+	base := fi.Int64Value(t.MixedOnDemandBase)
+	if base > instances {
+		base = instances
+	}
+	aboveBase := int64(100)
+	if t.MixedOnDemandAboveBase != nil {
+		aboveBase = *t.MixedOnDemandAboveBase
+	}
+	return base + int64(math.Ceil(float64(instances-base)*float64(aboveBase)/100))
+}
+
+// instanceFamily returns the family of the vCPU quotas that an instance type counts against, from its EC2 instance type data,
+// or "" if kOps does not check the quotas of the family. The instance types of families that the data does not tell apart,
+// such as the Trn and HPC instances, are counted as Standard instances.
+func instanceFamily(info *ec2.InstanceTypeInfo) string {
+	if info.ProcessorInfo != nil {
+		for _, architecture := range info.ProcessorInfo.SupportedArchitectures {
+			if strings.HasSuffix(aws.StringValue(architecture), "_mac") {
+				return ""
+			}
+		}
+	}
+	if info.FpgaInfo != nil {
+		return familyF
+	}
+	if info.InferenceAcceleratorInfo != nil {
+		return familyInf
+	}
+	if info.GpuInfo != nil {
+		family := ""
+		for _, gpu := range info.GpuInfo.Gpus {
+			switch aws.StringValue(gpu.Manufacturer) {
+			case "NVIDIA":
+				if pGPUs[aws.StringValue(gpu.Name)] {
+					return familyP
+				}
+				family = familyG
+			case "AMD":
+				family = familyG
+			}
+		}
+		// Other accelerators, such as the Gaudi accelerators of DL instances, have quotas of their own
+		return family
+	}
+	if info.MemoryInfo != nil {
+		memory := aws.Int64Value(info.MemoryInfo.SizeInMiB)
+		if memory >= highMemoryMiB {
+			return ""
+		}
+		if vcpus := instanceTypeVCPUs(info); vcpus != 0 && memory/vcpus >= xMemoryPerVCPUMiB {
+			return familyX
+		}
+	}
+	return familyStandard
+}
+
+func instanceTypeVCPUs(info *ec2.InstanceTypeInfo) int64 {
+	if info.VCpuInfo == nil {
+		return 0
+	}
+	return aws.Int64Value(info.VCpuInfo.DefaultVCpus)
+}
@@ -68,7 +68,9 @@ func ListResourcesAWS(cloud awsup.AWSCloud, clusterName string) (map[string]*res
 		ListVolumes,
 		// EC2 VPC
 		ListDhcpOptions,
+		ListEgressGatewayElasticIPs,
 		ListInternetGateways,
+		ListLoadBalancerElasticIPs,
 		ListRouteTables,
 		ListSubnets,
 		ListVPCs,
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Costs are estimated from the on-demand prices of us-east-1, in US dollars per month.
+// Prices differ between regions, so these are only meant to give an order of magnitude.
+
+// volumeGBMonthPrice is the price of a GB of storage per month, by volume type
+var volumeGBMonthPrice = map[string]float64{
+	ec2.VolumeTypeStandard: 0.05,
+	ec2.VolumeTypeGp2:      0.10,
+	ec2.VolumeTypeGp3:      0.08,
+	ec2.VolumeTypeIo1:      0.125,
+	ec2.VolumeTypeIo2:      0.125,
+	ec2.VolumeTypeSt1:      0.045,
+	ec2.VolumeTypeSc1:      0.015,
+}
+
+const (
+	// provisionedIOPSMonthPrice is the price of a provisioned IOPS per month, for io1 and io2 volumes
+	provisionedIOPSMonthPrice = 0.065
+	// gp3IOPSMonthPrice is the price of an IOPS per month above the baseline of gp3 volumes
+	gp3IOPSMonthPrice = 0.005
+	gp3BaselineIOPS   = 3000
+	// gp3ThroughputMonthPrice is the price of a MiB/s of throughput per month above the baseline of gp3 volumes
+	gp3ThroughputMonthPrice = 0.04
+	gp3BaselineThroughput   = 125
+
+	// publicIPv4MonthPrice is the price of a public IPv4 address per month
+	publicIPv4MonthPrice = 3.65
+)
+
+// EstimateVolumeMonthlyCost estimates the monthly cost of keeping a volume
+func EstimateVolumeMonthlyCost(volume *ec2.Volume) float64 {
+	volumeType := aws.StringValue(volume.VolumeType)
+	cost := float64(aws.Int64Value(volume.Size)) * volumeGBMonthPrice[volumeType]
+
+	iops := aws.Int64Value(volume.Iops)
+	switch volumeType {
+	case ec2.VolumeTypeIo1, ec2.VolumeTypeIo2:
+		cost += float64(iops) * provisionedIOPSMonthPrice
+	case ec2.VolumeTypeGp3:
+		if iops > gp3BaselineIOPS {
+			cost += float64(iops-gp3BaselineIOPS) * gp3IOPSMonthPrice
+		}
+		if throughput := aws.Int64Value(volume.Throughput); throughput > gp3BaselineThroughput {
+			cost += float64(throughput-gp3BaselineThroughput) * gp3ThroughputMonthPrice
+		}
+	}
+
+	return cost
+}
+
+// EstimateNetworkInterfaceMonthlyCost estimates the monthly cost of keeping a network interface,
+// which is only charged for its public IPv4 address
+func EstimateNetworkInterfaceMonthlyCost(ni *ec2.NetworkInterface) float64 {
+	if ni.Association != nil && aws.StringValue(ni.Association.PublicIp) != "" {
+		return publicIPv4MonthPrice
+	}
+	return 0
+}
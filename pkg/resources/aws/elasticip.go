@@ -17,10 +17,15 @@ limitations under the License.
 package aws
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
 
 	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 )
 
 func buildElasticIPResource(address *ec2.Address, forceShared bool, clusterName string) *resources.Resource {
@@ -46,3 +51,51 @@ func buildElasticIPResource(address *ec2.Address, forceShared bool, clusterName
 
 	return r
 }
+
+// ListEgressGatewayElasticIPs lists the Elastic IPs of the egress gateways of the cluster.
+// They are released once the gateway nodes they are associated with are deleted.
+func ListEgressGatewayElasticIPs(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	klog.V(2).Infof("Listing EC2 Elastic IPs of egress gateways")
+	request := &ec2.DescribeAddressesInput{
+		Filters: append(BuildEC2Filters(c), awsup.NewEC2Filter("tag-key", awsup.TagNameEgressGateway)),
+	}
+	response, err := c.EC2().DescribeAddresses(request)
+	if err != nil {
+		return nil, fmt.Errorf("error describing addresses: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, address := range response.Addresses {
+		resourceTracker := buildElasticIPResource(address, false, clusterName)
+		if address.InstanceId != nil {
+			resourceTracker.Blocked = append(resourceTracker.Blocked, ec2.ResourceTypeInstance+":"+aws.StringValue(address.InstanceId))
+		}
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+// ListLoadBalancerElasticIPs lists the Elastic IPs allocated for the load balancers of the cluster.
+// They are released once the load balancers they are associated with are deleted.
+func ListLoadBalancerElasticIPs(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	klog.V(2).Infof("Listing EC2 Elastic IPs of load balancers")
+	request := &ec2.DescribeAddressesInput{
+		Filters: append(BuildEC2Filters(c), awsup.NewEC2Filter("tag-key", awsup.TagNameLoadBalancer)),
+	}
+	response, err := c.EC2().DescribeAddresses(request)
+	if err != nil {
+		return nil, fmt.Errorf("error describing addresses: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, address := range response.Addresses {
+		resourceTrackers = append(resourceTrackers, buildElasticIPResource(address, false, clusterName))
+	}
+
+	return resourceTrackers, nil
+}
@@ -0,0 +1,439 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+const (
+	TypeLaunchTemplateVersion = "launch-template-version"
+	TypeNetworkInterface      = "network-interface"
+)
+
+// vpcCNIClusterTag is the tag the Amazon VPC CNI puts on the network interfaces it creates, when it knows the cluster name
+const vpcCNIClusterTag = "cluster.k8s.amazonaws.com/name"
+
+// ListOrphanedResourcesAWS finds resources of the cluster that are no longer used by it:
+// launch template versions that are not current, unattached volumes, unattached network interfaces,
+// and security groups that are neither attached to anything nor referenced by a launch template.
+// Volumes of etcd and of persistent volumes are never considered orphaned.
+func ListOrphanedResourcesAWS(cloud awsup.AWSCloud, clusterName string) (map[string]*resources.Resource, error) {
+	resourceTrackers := make(map[string]*resources.Resource)
+
+	listFunctions := []listFn{
+		ListOrphanedLaunchTemplateVersions,
+		ListOrphanedVolumes,
+		ListOrphanedNetworkInterfaces,
+		ListOrphanedSecurityGroups,
+	}
+
+	for _, fn := range listFunctions {
+		rt, err := fn(cloud, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range rt {
+			resourceTrackers[t.Type+":"+t.ID] = t
+		}
+	}
+
+	return resourceTrackers, nil
+}
+
+// ListOrphanedLaunchTemplateVersions finds the versions of the launch templates of the cluster that are
+// neither the default nor the latest version, and are not used by an autoscaling group or its instances.
+func ListOrphanedLaunchTemplateVersions(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	launchTemplates, err := FindAutoScalingLaunchTemplates(cloud, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(launchTemplates) == 0 {
+		return nil, nil
+	}
+
+	asgs, err := awsup.FindAutoscalingGroups(c, c.Tags())
+	if err != nil {
+		return nil, err
+	}
+	inUse := launchTemplateVersionsInUse(asgs)
+
+	var resourceTrackers []*resources.Resource
+	for _, lt := range launchTemplates {
+		versions, err := describeLaunchTemplateVersions(c, lt.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range orphanedLaunchTemplateVersions(versions, inUse) {
+			id := aws.StringValue(version.LaunchTemplateId) + ":" + strconv.FormatInt(aws.Int64Value(version.VersionNumber), 10)
+			resourceTrackers = append(resourceTrackers, &resources.Resource{
+				Name:    aws.StringValue(version.LaunchTemplateName),
+				ID:      id,
+				Type:    TypeLaunchTemplateVersion,
+				Deleter: DeleteLaunchTemplateVersion,
+				Obj:     version,
+			})
+		}
+	}
+
+	return resourceTrackers, nil
+}
+
+func describeLaunchTemplateVersions(c awsup.AWSCloud, launchTemplateID string) ([]*ec2.LaunchTemplateVersion, error) {
+	klog.V(2).Infof("Listing versions of EC2 LaunchTemplate %q", launchTemplateID)
+
+	var versions []*ec2.LaunchTemplateVersion
+	request := &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: aws.String(launchTemplateID),
+	}
+	err := c.EC2().DescribeLaunchTemplateVersionsPages(request, func(p *ec2.DescribeLaunchTemplateVersionsOutput, lastPage bool) bool {
+		versions = append(versions, p.LaunchTemplateVersions...)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing versions of LaunchTemplate %q: %v", launchTemplateID, err)
+	}
+	return versions, nil
+}
+
+// launchTemplateVersionsInUse returns the launch template versions referenced by the autoscaling groups
+// and their instances, as "<id>:<version>" where the version may also be $Latest or $Default.
+func launchTemplateVersionsInUse(asgs []*autoscaling.Group) sets.String {
+	inUse := sets.NewString()
+	add := func(spec *autoscaling.LaunchTemplateSpecification) {
+		if spec == nil {
+			return
+		}
+		inUse.Insert(aws.StringValue(spec.LaunchTemplateId) + ":" + aws.StringValue(spec.Version))
+	}
+
+	for _, asg := range asgs {
+		add(asg.LaunchTemplate)
+		if asg.MixedInstancesPolicy != nil && asg.MixedInstancesPolicy.LaunchTemplate != nil {
+			add(asg.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification)
+		}
+		for _, instance := range asg.Instances {
+			add(instance.LaunchTemplate)
+		}
+	}
+	return inUse
+}
+
+// orphanedLaunchTemplateVersions returns the versions that are not the default, not the latest and not in use
+func orphanedLaunchTemplateVersions(versions []*ec2.LaunchTemplateVersion, inUse sets.String) []*ec2.LaunchTemplateVersion {
+	var latest int64
+	for _, version := range versions {
+		if n := aws.Int64Value(version.VersionNumber); n > latest {
+			latest = n
+		}
+	}
+
+	var orphaned []*ec2.LaunchTemplateVersion
+	for _, version := range versions {
+		n := aws.Int64Value(version.VersionNumber)
+		if n == latest || aws.BoolValue(version.DefaultVersion) {
+			continue
+		}
+		if inUse.Has(aws.StringValue(version.LaunchTemplateId) + ":" + strconv.FormatInt(n, 10)) {
+			continue
+		}
+		orphaned = append(orphaned, version)
+	}
+	return orphaned
+}
+
+func DeleteLaunchTemplateVersion(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	version := r.Obj.(*ec2.LaunchTemplateVersion)
+	versionNumber := strconv.FormatInt(aws.Int64Value(version.VersionNumber), 10)
+
+	klog.V(2).Infof("Deleting EC2 LaunchTemplate version %q", r.ID)
+	response, err := c.EC2().DeleteLaunchTemplateVersions(&ec2.DeleteLaunchTemplateVersionsInput{
+		LaunchTemplateId: version.LaunchTemplateId,
+		Versions:         []*string{aws.String(versionNumber)},
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting LaunchTemplate version %q: %v", r.ID, err)
+	}
+	for _, failure := range response.UnsuccessfullyDeletedLaunchTemplateVersions {
+		if failure.ResponseError != nil {
+			return fmt.Errorf("error deleting LaunchTemplate version %q: %s", r.ID, aws.StringValue(failure.ResponseError.Message))
+		}
+	}
+	return nil
+}
+
+// ListOrphanedVolumes finds the volumes of the cluster that are not attached to an instance
+func ListOrphanedVolumes(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	volumes, err := DescribeVolumes(cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, volume := range volumes {
+		id := aws.StringValue(volume.VolumeId)
+		if HasSharedTag(ec2.ResourceTypeVolume+":"+id, volume.Tags, clusterName) || !isOrphanedVolume(volume) {
+			continue
+		}
+		resourceTrackers = append(resourceTrackers, &resources.Resource{
+			Name:                 FindName(volume.Tags),
+			ID:                   id,
+			Type:                 "volume",
+			Deleter:              DeleteVolume,
+			EstimatedMonthlyCost: EstimateVolumeMonthlyCost(volume),
+		})
+	}
+
+	return resourceTrackers, nil
+}
+
+// isOrphanedVolume returns true for an unattached volume, unless it holds etcd data or backs a persistent volume.
+// The volumes of etcd are unattached while their control plane instance is replaced.
+func isOrphanedVolume(volume *ec2.Volume) bool {
+	if aws.StringValue(volume.State) != ec2.VolumeStateAvailable {
+		return false
+	}
+	for _, tag := range volume.Tags {
+		key := aws.StringValue(tag.Key)
+		if strings.HasPrefix(key, "k8s.io/etcd/") || strings.HasPrefix(key, "kubernetes.io/created-for/pv/") || key == "CSIVolumeName" {
+			return false
+		}
+	}
+	return true
+}
+
+// ListOrphanedNetworkInterfaces finds the network interfaces of the cluster that are not attached to an instance,
+// including those created by the Amazon VPC CNI
+func ListOrphanedNetworkInterfaces(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	filterSets := buildEC2FiltersForCluster(clusterName)
+	filterSets = append(filterSets, []*ec2.Filter{
+		awsup.NewEC2Filter("tag:"+vpcCNIClusterTag, clusterName),
+	})
+
+	klog.V(2).Infof("Listing unattached EC2 NetworkInterfaces")
+	networkInterfaces := make(map[string]*ec2.NetworkInterface)
+	for _, filters := range filterSets {
+		request := &ec2.DescribeNetworkInterfacesInput{
+			Filters: append(filters, awsup.NewEC2Filter("status", ec2.NetworkInterfaceStatusAvailable)),
+		}
+		err := c.EC2().DescribeNetworkInterfacesPages(request, func(p *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+			for _, ni := range p.NetworkInterfaces {
+				networkInterfaces[aws.StringValue(ni.NetworkInterfaceId)] = ni
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing NetworkInterfaces: %v", err)
+		}
+	}
+
+	var resourceTrackers []*resources.Resource
+	for id, ni := range networkInterfaces {
+		if aws.BoolValue(ni.RequesterManaged) || HasSharedTag(TypeNetworkInterface+":"+id, ni.TagSet, clusterName) {
+			continue
+		}
+		resourceTrackers = append(resourceTrackers, &resources.Resource{
+			Name:                 FindName(ni.TagSet),
+			ID:                   id,
+			Type:                 TypeNetworkInterface,
+			Deleter:              DeleteNetworkInterface,
+			EstimatedMonthlyCost: EstimateNetworkInterfaceMonthlyCost(ni),
+		})
+	}
+
+	return resourceTrackers, nil
+}
+
+func DeleteNetworkInterface(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	id := r.ID
+
+	klog.V(2).Infof("Deleting EC2 NetworkInterface %q", id)
+	_, err := c.EC2().DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{
+		NetworkInterfaceId: aws.String(id),
+	})
+	if err != nil {
+		if awsup.AWSErrorCode(err) == "InvalidNetworkInterfaceID.NotFound" {
+			klog.V(2).Infof("Got InvalidNetworkInterfaceID.NotFound error deleting NetworkInterface %q; will treat as already-deleted", id)
+			return nil
+		}
+		if IsDependencyViolation(err) {
+			return err
+		}
+		return fmt.Errorf("error deleting NetworkInterface %q: %v", id, err)
+	}
+	return nil
+}
+
+// ListOrphanedSecurityGroups finds the security groups tagged as owned by the cluster that are not attached to a network interface
+// and are not referenced by the current version of a launch template of the cluster. These are typically
+// left behind by load balancers of services that were deleted.
+func ListOrphanedSecurityGroups(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	groups, err := DescribeSecurityGroups(cloud, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := sets.NewString()
+	for id, sg := range groups {
+		if hasClusterOwnedTag(sg.Tags, clusterName) {
+			candidates.Insert(id)
+		}
+	}
+	if candidates.Len() == 0 {
+		return nil, nil
+	}
+
+	inUse := sets.NewString()
+	{
+		request := &ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{
+				{Name: aws.String("group-id"), Values: aws.StringSlice(candidates.List())},
+			},
+		}
+		err := c.EC2().DescribeNetworkInterfacesPages(request, func(p *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+			for _, ni := range p.NetworkInterfaces {
+				for _, group := range ni.Groups {
+					inUse.Insert(aws.StringValue(group.GroupId))
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing NetworkInterfaces: %v", err)
+		}
+	}
+
+	launchTemplates, err := FindAutoScalingLaunchTemplates(cloud, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	for _, lt := range launchTemplates {
+		request := &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String(lt.ID),
+			Versions:         aws.StringSlice([]string{"$Latest", "$Default"}),
+		}
+		response, err := c.EC2().DescribeLaunchTemplateVersions(request)
+		if err != nil {
+			return nil, fmt.Errorf("error describing LaunchTemplate %q: %v", lt.ID, err)
+		}
+		for _, version := range response.LaunchTemplateVersions {
+			data := version.LaunchTemplateData
+			if data == nil {
+				continue
+			}
+			inUse.Insert(aws.StringValueSlice(data.SecurityGroupIds)...)
+			for _, ni := range data.NetworkInterfaces {
+				inUse.Insert(aws.StringValueSlice(ni.Groups)...)
+			}
+		}
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, id := range candidates.Difference(inUse).List() {
+		sg := groups[id]
+		resourceTrackers = append(resourceTrackers, &resources.Resource{
+			Name:    FindName(sg.Tags),
+			ID:      id,
+			Type:    ec2.ResourceTypeSecurityGroup,
+			Deleter: DeleteOrphanedSecurityGroup,
+			Obj:     sg,
+		})
+	}
+
+	return resourceTrackers, nil
+}
+
+// hasClusterOwnedTag returns true if the resource has the "kubernetes.io/cluster/<clusterName>" tag set to "owned".
+// Unlike HasOwnedTag, the legacy KubernetesCluster tag is not taken as ownership: security groups are only
+// deleted as orphans when they are unambiguously owned by the cluster.
+func hasClusterOwnedTag(tags []*ec2.Tag, clusterName string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == "kubernetes.io/cluster/"+clusterName {
+			return aws.StringValue(tag.Value) == "owned"
+		}
+	}
+	return false
+}
+
+// DeleteOrphanedSecurityGroup deletes a security group, first revoking the rules of other security groups
+// that reference it, as those would otherwise prevent its deletion
+func DeleteOrphanedSecurityGroup(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	id := r.ID
+
+	request := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			awsup.NewEC2Filter("ip-permission.group-id", id),
+		},
+	}
+	response, err := c.EC2().DescribeSecurityGroups(request)
+	if err != nil {
+		return fmt.Errorf("error listing SecurityGroups referencing %q: %v", id, err)
+	}
+	for _, sg := range response.SecurityGroups {
+		var permissions []*ec2.IpPermission
+		for _, permission := range sg.IpPermissions {
+			for _, pair := range permission.UserIdGroupPairs {
+				if aws.StringValue(pair.GroupId) != id {
+					continue
+				}
+				permissions = append(permissions, &ec2.IpPermission{
+					IpProtocol:       permission.IpProtocol,
+					FromPort:         permission.FromPort,
+					ToPort:           permission.ToPort,
+					UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: pair.GroupId}},
+				})
+			}
+		}
+		if len(permissions) == 0 {
+			continue
+		}
+
+		klog.V(2).Infof("Revoking rules of SecurityGroup %q referencing %q", aws.StringValue(sg.GroupId), id)
+		_, err := c.EC2().RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+			GroupId:       sg.GroupId,
+			IpPermissions: permissions,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot revoke rules of SecurityGroup %q referencing %q: %v", aws.StringValue(sg.GroupId), id, err)
+		}
+	}
+
+	return DeleteSecurityGroup(cloud, r)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestOrphanedLaunchTemplateVersions(t *testing.T) {
+	asgs := []*autoscaling.Group{
+		{
+			LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+				LaunchTemplateId: aws.String("lt-1"),
+				Version:          aws.String("$Latest"),
+			},
+			Instances: []*autoscaling.Instance{
+				{
+					LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+						LaunchTemplateId: aws.String("lt-1"),
+						Version:          aws.String("3"),
+					},
+				},
+			},
+		},
+	}
+
+	var versions []*ec2.LaunchTemplateVersion
+	for i := int64(1); i <= 5; i++ {
+		versions = append(versions, &ec2.LaunchTemplateVersion{
+			LaunchTemplateId: aws.String("lt-1"),
+			VersionNumber:    aws.Int64(i),
+			DefaultVersion:   aws.Bool(i == 1),
+		})
+	}
+
+	var actual []int64
+	for _, version := range orphanedLaunchTemplateVersions(versions, launchTemplateVersionsInUse(asgs)) {
+		actual = append(actual, aws.Int64Value(version.VersionNumber))
+	}
+
+	// 1 is the default, 3 is used by an instance and 5 is the latest
+	expected := []int64{2, 4}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected orphaned versions: expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestIsOrphanedVolume(t *testing.T) {
+	grid := []struct {
+		Name     string
+		Volume   *ec2.Volume
+		Expected bool
+	}{
+		{
+			Name: "unattached",
+			Volume: &ec2.Volume{
+				State: aws.String(ec2.VolumeStateAvailable),
+			},
+			Expected: true,
+		},
+		{
+			Name: "attached",
+			Volume: &ec2.Volume{
+				State: aws.String(ec2.VolumeStateInUse),
+			},
+		},
+		{
+			Name: "etcd",
+			Volume: &ec2.Volume{
+				State: aws.String(ec2.VolumeStateAvailable),
+				Tags: []*ec2.Tag{
+					{Key: aws.String("k8s.io/etcd/main"), Value: aws.String("a/a,b,c")},
+				},
+			},
+		},
+		{
+			Name: "persistent volume",
+			Volume: &ec2.Volume{
+				State: aws.String(ec2.VolumeStateAvailable),
+				Tags: []*ec2.Tag{
+					{Key: aws.String("kubernetes.io/created-for/pv/name"), Value: aws.String("pvc-1234")},
+				},
+			},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			actual := isOrphanedVolume(g.Volume)
+			if actual != g.Expected {
+				t.Errorf("expected %v, got %v", g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestHasClusterOwnedTag(t *testing.T) {
+	grid := []struct {
+		Name     string
+		Tags     []*ec2.Tag
+		Expected bool
+	}{
+		{
+			Name: "owned",
+			Tags: []*ec2.Tag{
+				{Key: aws.String("kubernetes.io/cluster/cluster.example.com"), Value: aws.String("owned")},
+			},
+			Expected: true,
+		},
+		{
+			Name: "shared",
+			Tags: []*ec2.Tag{
+				{Key: aws.String("kubernetes.io/cluster/cluster.example.com"), Value: aws.String("shared")},
+			},
+		},
+		{
+			Name: "legacy tag only",
+			Tags: []*ec2.Tag{
+				{Key: aws.String("KubernetesCluster"), Value: aws.String("cluster.example.com")},
+			},
+		},
+		{
+			Name: "other cluster",
+			Tags: []*ec2.Tag{
+				{Key: aws.String("kubernetes.io/cluster/other.example.com"), Value: aws.String("owned")},
+			},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			actual := hasClusterOwnedTag(g.Tags, "cluster.example.com")
+			if actual != g.Expected {
+				t.Errorf("expected %v, got %v", g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestEstimateVolumeMonthlyCost(t *testing.T) {
+	grid := []struct {
+		Volume   *ec2.Volume
+		Expected float64
+	}{
+		{
+			Volume: &ec2.Volume{
+				VolumeType: aws.String(ec2.VolumeTypeGp2),
+				Size:       aws.Int64(20),
+			},
+			Expected: 2,
+		},
+		{
+			Volume: &ec2.Volume{
+				VolumeType: aws.String(ec2.VolumeTypeGp3),
+				Size:       aws.Int64(100),
+				Iops:       aws.Int64(4000),
+				Throughput: aws.Int64(225),
+			},
+			Expected: 8 + 5 + 4,
+		},
+		{
+			Volume: &ec2.Volume{
+				VolumeType: aws.String(ec2.VolumeTypeIo1),
+				Size:       aws.Int64(8),
+				Iops:       aws.Int64(100),
+			},
+			Expected: 1 + 6.5,
+		},
+	}
+
+	for _, g := range grid {
+		actual := EstimateVolumeMonthlyCost(g.Volume)
+		if math.Abs(actual-g.Expected) > 0.001 {
+			t.Errorf("unexpected cost for %s volume: expected %v, actual %v", aws.StringValue(g.Volume.VolumeType), g.Expected, actual)
+		}
+	}
+}
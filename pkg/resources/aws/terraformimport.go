@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/resources"
+)
+
+// terraformResourceTypes maps the types of the resources of a cluster to the Terraform resource types managing them
+var terraformResourceTypes = map[string]string{
+	"autoscaling-group":           "aws_autoscaling_group",
+	TypeAutoscalingLaunchConfig:   "aws_launch_template",
+	"keypair":                     "aws_key_pair",
+	ec2.ResourceTypeSecurityGroup: "aws_security_group",
+	"volume":                      "aws_ebs_volume",
+	"dhcp-options":                "aws_vpc_dhcp_options",
+	"internet-gateway":            "aws_internet_gateway",
+	ec2.ResourceTypeRouteTable:    "aws_route_table",
+	ec2.ResourceTypeSubnet:        "aws_subnet",
+	ec2.ResourceTypeVpc:           "aws_vpc",
+	TypeTargetGroup:               "aws_lb_target_group",
+	TypeNatGateway:                "aws_nat_gateway",
+	TypeElasticIp:                 "aws_eip",
+	"iam-role":                    "aws_iam_role",
+	"iam-instance-profile":        "aws_iam_instance_profile",
+	"oidc-provider":               "aws_iam_openid_connect_provider",
+	"sqs":                         "aws_sqs_queue",
+	"eventbridge":                 "aws_cloudwatch_event_rule",
+
+	// The Terraform resource type of load balancers depends on their kind; the others are not imported
+	ec2.ResourceTypeInstance: "",
+	"route53-record":         "",
+	"cloud-formation":        "",
+	TypeLoadBalancer:         "",
+}
+
+// BuildTerraformImportsAWS returns the Terraform imports for the resources owned by the cluster.
+// Instances are managed by their autoscaling groups and DNS records by dns-controller, so they are not imported.
+func BuildTerraformImportsAWS(resourceMap map[string]*resources.Resource, clusterName string) []*resources.TerraformImport {
+	var imports []*resources.TerraformImport
+	for _, r := range resourceMap {
+		if r.Shared {
+			continue
+		}
+
+		resourceType, found := terraformResourceTypes[r.Type]
+		if !found {
+			klog.Warningf("cannot import resource %q of type %q into terraform", r.ID, r.Type)
+			continue
+		}
+
+		name := r.Name
+		id := r.ID
+		switch r.Type {
+		case TypeLoadBalancer:
+			// Network load balancers are identified by their ARN, classic load balancers by their name
+			if strings.HasPrefix(r.ID, "arn:") {
+				resourceType = "aws_lb"
+			} else {
+				resourceType = "aws_elb"
+			}
+		case "keypair":
+			id = r.Name
+		case "sqs":
+			name = path.Base(r.ID)
+		case "oidc-provider":
+			name = clusterName
+		}
+		if resourceType == "" {
+			continue
+		}
+		if name == "" {
+			name = id
+		}
+
+		imports = append(imports, &resources.TerraformImport{
+			ResourceType: resourceType,
+			Name:         resources.TerraformResourceName(name),
+			ID:           id,
+		})
+	}
+
+	return resources.SortTerraformImports(imports)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/kops/pkg/resources"
+)
+
+func TestBuildTerraformImportsAWS(t *testing.T) {
+	resourceMap := map[string]*resources.Resource{
+		"vpc:vpc-1234": {
+			Name: "minimal.example.com",
+			ID:   "vpc-1234",
+			Type: "vpc",
+		},
+		"subnet:subnet-shared": {
+			Name:   "shared",
+			ID:     "subnet-shared",
+			Type:   "subnet",
+			Shared: true,
+		},
+		"instance:i-1234": {
+			Name: "nodes.minimal.example.com",
+			ID:   "i-1234",
+			Type: "instance",
+		},
+		"keypair:key-1234": {
+			Name: "kubernetes.minimal.example.com-c4:a6:ed",
+			ID:   "key-1234",
+			Type: "keypair",
+		},
+		"load-balancer:api-minimal-example-com-123": {
+			Name: "api.minimal.example.com",
+			ID:   "api-minimal-example-com-123",
+			Type: "load-balancer",
+		},
+		"load-balancer:arn:aws:elasticloadbalancing:us-test-1:123:loadbalancer/net/api/1": {
+			Name: "api-minimal-example-com-456",
+			ID:   "arn:aws:elasticloadbalancing:us-test-1:123:loadbalancer/net/api/1",
+			Type: "load-balancer",
+		},
+		"sqs:https://sqs.us-test-1.amazonaws.com/123/minimal-example-com-nth": {
+			Name: "https://sqs.us-test-1.amazonaws.com/123/minimal-example-com-nth",
+			ID:   "https://sqs.us-test-1.amazonaws.com/123/minimal-example-com-nth",
+			Type: "sqs",
+		},
+		"security-group:sg-1": {
+			Name: "nodes.minimal.example.com",
+			ID:   "sg-1",
+			Type: "security-group",
+		},
+		"security-group:sg-2": {
+			Name: "nodes.minimal.example.com",
+			ID:   "sg-2",
+			Type: "security-group",
+		},
+		"sg-2": {
+			Name: "nodes.minimal.example.com",
+			ID:   "sg-2",
+			Type: "security-group",
+		},
+		"security-group:sg-3": {
+			Name: "nodes.minimal.example.com-sg-2",
+			ID:   "sg-3",
+			Type: "security-group",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := resources.WriteTerraformImportBlocks(&buf, BuildTerraformImportsAWS(resourceMap, "minimal.example.com")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `import {
+  to = aws_elb.api-minimal-example-com
+  id = "api-minimal-example-com-123"
+}
+
+import {
+  to = aws_key_pair.kubernetes-minimal-example-com-c4_a6_ed
+  id = "kubernetes.minimal.example.com-c4:a6:ed"
+}
+
+import {
+  to = aws_lb.api-minimal-example-com-456
+  id = "arn:aws:elasticloadbalancing:us-test-1:123:loadbalancer/net/api/1"
+}
+
+import {
+  to = aws_security_group.nodes-minimal-example-com
+  id = "sg-1"
+}
+
+import {
+  to = aws_security_group.nodes-minimal-example-com-sg-2
+  id = "sg-3"
+}
+
+import {
+  to = aws_security_group.nodes-minimal-example-com-sg-2-2
+  id = "sg-2"
+}
+
+import {
+  to = aws_sqs_queue.minimal-example-com-nth
+  id = "https://sqs.us-test-1.amazonaws.com/123/minimal-example-com-nth"
+}
+
+import {
+  to = aws_vpc.minimal-example-com
+  id = "vpc-1234"
+}
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected import blocks:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
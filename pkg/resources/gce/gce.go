@@ -198,38 +198,58 @@ func (d *clusterDiscoveryGCE) listInstanceGroupManagersAndInstances() ([]*resour
 
 	ctx := context.Background()
 
+	var migs []*compute.InstanceGroupManager
 	for _, zoneName := range d.zones {
 		is, err := c.Compute().InstanceGroupManagers().List(ctx, project, zoneName)
 		if err != nil {
 			return nil, fmt.Errorf("error listing InstanceGroupManagers: %v", err)
 		}
-		for i := range is {
-			mig := is[i] // avoid closure-in-loop go-tcha
-			instanceTemplate := instanceTemplates[mig.InstanceTemplate]
-			if instanceTemplate == nil {
-				klog.V(2).Infof("Ignoring MIG with unmanaged InstanceTemplate: %s", mig.InstanceTemplate)
-				continue
-			}
+		migs = append(migs, is...)
+	}
+	{
+		is, err := c.Compute().RegionInstanceGroupManagers().List(ctx, project, c.Region())
+		if err != nil {
+			return nil, fmt.Errorf("error listing regional InstanceGroupManagers: %v", err)
+		}
+		migs = append(migs, is...)
+	}
 
-			resourceTracker := &resources.Resource{
-				Name:    mig.Name,
-				ID:      zoneName + "/" + mig.Name,
-				Type:    typeInstanceGroupManager,
-				Deleter: func(cloud fi.Cloud, r *resources.Resource) error { return gce.DeleteInstanceGroupManager(c, mig) },
-				Obj:     mig,
-			}
+	for i := range migs {
+		mig := migs[i] // avoid closure-in-loop go-tcha
+		instanceTemplate := instanceTemplates[mig.InstanceTemplate]
+		if instanceTemplate == nil {
+			klog.V(2).Infof("Ignoring MIG with unmanaged InstanceTemplate: %s", mig.InstanceTemplate)
+			continue
+		}
 
-			resourceTracker.Blocks = append(resourceTracker.Blocks, typeInstanceTemplate+":"+instanceTemplate.Name)
+		location := gce.LastComponent(mig.Zone)
+		if mig.Region != "" {
+			location = gce.LastComponent(mig.Region)
+		}
 
-			klog.V(4).Infof("Found resource: %s", mig.SelfLink)
-			resourceTrackers = append(resourceTrackers, resourceTracker)
+		resourceTracker := &resources.Resource{
+			Name:    mig.Name,
+			ID:      location + "/" + mig.Name,
+			Type:    typeInstanceGroupManager,
+			Deleter: func(cloud fi.Cloud, r *resources.Resource) error { return gce.DeleteInstanceGroupManager(c, mig) },
+			Obj:     mig,
+		}
 
-			instanceTrackers, err := d.listManagedInstances(mig)
-			if err != nil {
-				return nil, fmt.Errorf("error listing instances in InstanceGroupManager: %v", err)
+		resourceTracker.Blocks = append(resourceTracker.Blocks, typeInstanceTemplate+":"+instanceTemplate.Name)
+		for _, version := range mig.Versions {
+			if t := instanceTemplates[version.InstanceTemplate]; t != nil && t != instanceTemplate {
+				resourceTracker.Blocks = append(resourceTracker.Blocks, typeInstanceTemplate+":"+t.Name)
 			}
-			resourceTrackers = append(resourceTrackers, instanceTrackers...)
 		}
+
+		klog.V(4).Infof("Found resource: %s", mig.SelfLink)
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+
+		instanceTrackers, err := d.listManagedInstances(mig)
+		if err != nil {
+			return nil, fmt.Errorf("error listing instances in InstanceGroupManager: %v", err)
+		}
+		resourceTrackers = append(resourceTrackers, instanceTrackers...)
 	}
 
 	return resourceTrackers, nil
@@ -240,8 +260,6 @@ func (d *clusterDiscoveryGCE) listManagedInstances(igm *compute.InstanceGroupMan
 
 	var resourceTrackers []*resources.Resource
 
-	zoneName := gce.LastComponent(igm.Zone)
-
 	instances, err := gce.ListManagedInstances(c, igm)
 	if err != nil {
 		return nil, err
@@ -250,6 +268,11 @@ func (d *clusterDiscoveryGCE) listManagedInstances(igm *compute.InstanceGroupMan
 	for _, i := range instances {
 		url := i.Instance // avoid closure-in-loop go-tcha
 		name := gce.LastComponent(url)
+		// Instances of a regional MIG are spread over several zones
+		zoneName := gce.LastComponent(igm.Zone)
+		if u, err := gce.ParseGoogleCloudURL(url); err == nil {
+			zoneName = u.Zone
+		}
 
 		resourceTracker := &resources.Resource{
 			Name: name,
@@ -0,0 +1,424 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/networkloadbalancer"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+const (
+	typeVCN                   = "VCN"
+	typeSubnet                = "Subnet"
+	typeInternetGateway       = "InternetGateway"
+	typeRouteTable            = "RouteTable"
+	typeSecurityList          = "SecurityList"
+	typeInstancePool          = "InstancePool"
+	typeInstanceConfiguration = "InstanceConfiguration"
+	typeVolume                = "Volume"
+	typeNetworkLoadBalancer   = "NetworkLoadBalancer"
+	typeDynamicGroup          = "DynamicGroup"
+	typePolicy                = "Policy"
+)
+
+// ListResourcesOCI lists all resources for the cluster by querying OCI.
+func ListResourcesOCI(cloud oci.OCICloud, cluster *kopsapi.Cluster) (map[string]*resources.Resource, error) {
+	g := resourceGetter{
+		cloud:   cloud,
+		cluster: cluster,
+	}
+	return g.listResourcesOCI()
+}
+
+type resourceGetter struct {
+	cloud   oci.OCICloud
+	cluster *kopsapi.Cluster
+
+	// keys of the resources listed so far, by type.
+	keys map[string][]string
+}
+
+func (g *resourceGetter) listResourcesOCI() (map[string]*resources.Resource, error) {
+	rs, err := g.listAll()
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make(map[string]*resources.Resource)
+	for _, r := range rs {
+		resources[toKey(r.Type, r.ID)] = r
+	}
+	return resources, nil
+}
+
+// listAll lists all resources owned by kops for the cluster.
+// The listers run in order, so that each resource can block the deletion
+// of the resources of the types listed before it.
+func (g *resourceGetter) listAll() ([]*resources.Resource, error) {
+	fns := []func(ctx context.Context) ([]*resources.Resource, error){
+		g.listVCNs,
+		g.listInternetGateways,
+		g.listRouteTables,
+		g.listSecurityLists,
+		g.listSubnets,
+		g.listNetworkLoadBalancers,
+		g.listVolumes,
+		g.listInstanceConfigurations,
+		g.listInstancePools,
+		g.listDynamicGroups,
+		g.listPolicies,
+	}
+
+	g.keys = map[string][]string{}
+	var resources []*resources.Resource
+	ctx := context.TODO()
+	for _, fn := range fns {
+		rs, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			g.keys[r.Type] = append(g.keys[r.Type], toKey(r.Type, r.ID))
+		}
+		resources = append(resources, rs...)
+	}
+	return resources, nil
+}
+
+// blocks returns the keys of all listed resources of the given types.
+func (g *resourceGetter) blocks(types ...string) []string {
+	var keys []string
+	for _, t := range types {
+		keys = append(keys, g.keys[t]...)
+	}
+	return keys
+}
+
+func (g *resourceGetter) listVCNs(ctx context.Context) ([]*resources.Resource, error) {
+	vcns, err := g.cloud.VCN().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range vcns {
+		vcn := &vcns[i]
+		if !g.isOwnedByCluster(vcn.FreeformTags) {
+			continue
+		}
+		rs = append(rs, &resources.Resource{
+			Obj:  vcn,
+			Type: typeVCN,
+			ID:   fi.StringValue(vcn.Id),
+			Name: fi.StringValue(vcn.DisplayName),
+			Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+				return g.cloud.VCN().Delete(context.TODO(), r.ID)
+			},
+		})
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) listInternetGateways(ctx context.Context) ([]*resources.Resource, error) {
+	igs, err := g.cloud.InternetGateway().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range igs {
+		ig := &igs[i]
+		if !g.isOwnedByCluster(ig.FreeformTags) {
+			continue
+		}
+		rs = append(rs, &resources.Resource{
+			Obj:  ig,
+			Type: typeInternetGateway,
+			ID:   fi.StringValue(ig.Id),
+			Name: fi.StringValue(ig.DisplayName),
+			Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+				return g.cloud.InternetGateway().Delete(context.TODO(), r.ID)
+			},
+			Blocks: g.blocks(typeVCN),
+		})
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) listRouteTables(ctx context.Context) ([]*resources.Resource, error) {
+	rts, err := g.cloud.RouteTable().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range rts {
+		rt := &rts[i]
+		if !g.isOwnedByCluster(rt.FreeformTags) {
+			continue
+		}
+		rs = append(rs, &resources.Resource{
+			Obj:  rt,
+			Type: typeRouteTable,
+			ID:   fi.StringValue(rt.Id),
+			Name: fi.StringValue(rt.DisplayName),
+			Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+				return g.cloud.RouteTable().Delete(context.TODO(), r.ID)
+			},
+			// The route table references the internet gateway.
+			Blocks: g.blocks(typeVCN, typeInternetGateway),
+		})
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) listSecurityLists(ctx context.Context) ([]*resources.Resource, error) {
+	sls, err := g.cloud.SecurityList().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range sls {
+		sl := &sls[i]
+		if !g.isOwnedByCluster(sl.FreeformTags) {
+			continue
+		}
+		rs = append(rs, &resources.Resource{
+			Obj:  sl,
+			Type: typeSecurityList,
+			ID:   fi.StringValue(sl.Id),
+			Name: fi.StringValue(sl.DisplayName),
+			Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+				return g.cloud.SecurityList().Delete(context.TODO(), r.ID)
+			},
+			Blocks: g.blocks(typeVCN),
+		})
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) listSubnets(ctx context.Context) ([]*resources.Resource, error) {
+	subnets, err := g.cloud.Subnet().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range subnets {
+		subnet := &subnets[i]
+		if !g.isOwnedByCluster(subnet.FreeformTags) {
+			continue
+		}
+		rs = append(rs, &resources.Resource{
+			Obj:  subnet,
+			Type: typeSubnet,
+			ID:   fi.StringValue(subnet.Id),
+			Name: fi.StringValue(subnet.DisplayName),
+			Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+				return g.cloud.Subnet().Delete(context.TODO(), r.ID)
+			},
+			Blocks: g.blocks(typeVCN, typeRouteTable, typeSecurityList),
+		})
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) listNetworkLoadBalancers(ctx context.Context) ([]*resources.Resource, error) {
+	nlbs, err := g.cloud.NetworkLoadBalancer().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range nlbs {
+		nlb := &nlbs[i]
+		if !g.isOwnedByCluster(nlb.FreeformTags) {
+			continue
+		}
+		rs = append(rs, g.toNetworkLoadBalancerResource(nlb))
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) toNetworkLoadBalancerResource(nlb *networkloadbalancer.NetworkLoadBalancerSummary) *resources.Resource {
+	return &resources.Resource{
+		Obj:  nlb,
+		Type: typeNetworkLoadBalancer,
+		ID:   fi.StringValue(nlb.Id),
+		Name: fi.StringValue(nlb.DisplayName),
+		Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+			return g.cloud.NetworkLoadBalancer().Delete(context.TODO(), r.ID)
+		},
+		Blocks: g.blocks(typeSubnet),
+	}
+}
+
+func (g *resourceGetter) listVolumes(ctx context.Context) ([]*resources.Resource, error) {
+	volumes, err := g.cloud.Volume().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range volumes {
+		volume := &volumes[i]
+		if !g.isOwnedByCluster(volume.FreeformTags) {
+			continue
+		}
+		rs = append(rs, &resources.Resource{
+			Obj:  volume,
+			Type: typeVolume,
+			ID:   fi.StringValue(volume.Id),
+			Name: fi.StringValue(volume.DisplayName),
+			Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+				return g.cloud.Volume().Delete(context.TODO(), r.ID)
+			},
+		})
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) listInstanceConfigurations(ctx context.Context) ([]*resources.Resource, error) {
+	configs, err := g.cloud.InstanceConfiguration().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range configs {
+		config := &configs[i]
+		if !g.isOwnedByCluster(config.FreeformTags) {
+			continue
+		}
+		rs = append(rs, &resources.Resource{
+			Obj:  config,
+			Type: typeInstanceConfiguration,
+			ID:   fi.StringValue(config.Id),
+			Name: fi.StringValue(config.DisplayName),
+			Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+				return g.cloud.InstanceConfiguration().Delete(context.TODO(), r.ID)
+			},
+		})
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) listInstancePools(ctx context.Context) ([]*resources.Resource, error) {
+	pools, err := g.cloud.InstancePool().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range pools {
+		pool := &pools[i]
+		if !g.isOwnedByCluster(pool.FreeformTags) {
+			continue
+		}
+		rs = append(rs, g.toInstancePoolResource(pool))
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) toInstancePoolResource(pool *core.InstancePoolSummary) *resources.Resource {
+	return &resources.Resource{
+		Obj:  pool,
+		Type: typeInstancePool,
+		ID:   fi.StringValue(pool.Id),
+		Name: fi.StringValue(pool.DisplayName),
+		Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+			// Terminating the pool also terminates its instances.
+			return g.cloud.InstancePool().Terminate(context.TODO(), r.ID)
+		},
+		// The instances of the pool are attached to the subnets, the load balancer and the etcd volumes.
+		Blocks: g.blocks(typeSubnet, typeNetworkLoadBalancer, typeVolume, typeInstanceConfiguration),
+	}
+}
+
+func (g *resourceGetter) listDynamicGroups(ctx context.Context) ([]*resources.Resource, error) {
+	// Dynamic groups always live in the root compartment.
+	dgs, err := g.cloud.DynamicGroup().List(ctx, g.cloud.TenancyID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range dgs {
+		dg := &dgs[i]
+		if !g.isOwnedByCluster(dg.FreeformTags) {
+			continue
+		}
+		rs = append(rs, g.toDynamicGroupResource(dg))
+	}
+	return rs, nil
+}
+
+func (g *resourceGetter) toDynamicGroupResource(dg *identity.DynamicGroup) *resources.Resource {
+	return &resources.Resource{
+		Obj:  dg,
+		Type: typeDynamicGroup,
+		ID:   fi.StringValue(dg.Id),
+		Name: fi.StringValue(dg.Name),
+		Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+			return g.cloud.DynamicGroup().Delete(context.TODO(), r.ID)
+		},
+	}
+}
+
+func (g *resourceGetter) listPolicies(ctx context.Context) ([]*resources.Resource, error) {
+	policies, err := g.cloud.Policy().List(ctx, g.cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*resources.Resource
+	for i := range policies {
+		policy := &policies[i]
+		if !g.isOwnedByCluster(policy.FreeformTags) {
+			continue
+		}
+		rs = append(rs, &resources.Resource{
+			Obj:  policy,
+			Type: typePolicy,
+			ID:   fi.StringValue(policy.Id),
+			Name: fi.StringValue(policy.Name),
+			Deleter: func(_ fi.Cloud, r *resources.Resource) error {
+				return g.cloud.Policy().Delete(context.TODO(), r.ID)
+			},
+			// The policy statements reference the dynamic group.
+			Blocks: g.blocks(typeDynamicGroup),
+		})
+	}
+	return rs, nil
+}
+
+// isOwnedByCluster returns true if the resource is owned by the cluster.
+func (g *resourceGetter) isOwnedByCluster(tags map[string]string) bool {
+	return tags[oci.TagClusterName] == g.cluster.Name
+}
+
+func toKey(rtype, id string) string {
+	return rtype + ":" + id
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/networkloadbalancer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+	"k8s.io/kops/upup/pkg/fi/cloudup/ocitasks"
+)
+
+func TestListResourcesOCI(t *testing.T) {
+	const (
+		clusterName  = "cluster.example.com"
+		vcnID        = "vcn"
+		subnetID     = "subnet"
+		igID         = "ig"
+		rtID         = "rt"
+		slID         = "sl"
+		nlbID        = "nlb"
+		volumeID     = "volume"
+		configID     = "config"
+		poolID       = "pool"
+		dgID         = "dg"
+		policyID     = "policy"
+		irrelevantID = "irrelevant"
+	)
+	clusterTags := map[string]string{
+		oci.TagClusterName: clusterName,
+	}
+	otherTags := map[string]string{
+		oci.TagClusterName: "other.example.com",
+	}
+
+	cloud := ocitasks.NewMockOCICloud("us-ashburn-1")
+	cloud.VCNsClient.VCNs[vcnID] = core.Vcn{Id: fi.String(vcnID), FreeformTags: clusterTags}
+	cloud.VCNsClient.VCNs[irrelevantID] = core.Vcn{Id: fi.String(irrelevantID), FreeformTags: otherTags}
+	cloud.SubnetsClient.Subnets[subnetID] = core.Subnet{Id: fi.String(subnetID), FreeformTags: clusterTags}
+	cloud.InternetGatewaysClient.IGs[igID] = core.InternetGateway{Id: fi.String(igID), FreeformTags: clusterTags}
+	cloud.RouteTablesClient.RTs[rtID] = core.RouteTable{Id: fi.String(rtID), FreeformTags: clusterTags}
+	cloud.SecurityListsClient.SLs[slID] = core.SecurityList{Id: fi.String(slID), FreeformTags: clusterTags}
+	cloud.NetworkLoadBalancersClient.NLBs[nlbID] = networkloadbalancer.NetworkLoadBalancer{Id: fi.String(nlbID), FreeformTags: clusterTags}
+	cloud.VolumesClient.Volumes[volumeID] = core.Volume{Id: fi.String(volumeID), FreeformTags: clusterTags}
+	cloud.VolumesClient.Volumes[irrelevantID] = core.Volume{Id: fi.String(irrelevantID)}
+	cloud.InstanceConfigurationsClient.Configs[configID] = core.InstanceConfiguration{Id: fi.String(configID), FreeformTags: clusterTags}
+	cloud.InstancePoolsClient.Pools[poolID] = core.InstancePool{Id: fi.String(poolID), FreeformTags: clusterTags}
+	cloud.DynamicGroupsClient.DGs[dgID] = identity.DynamicGroup{Id: fi.String(dgID), FreeformTags: clusterTags}
+	cloud.PoliciesClient.Policies[policyID] = identity.Policy{Id: fi.String(policyID), FreeformTags: clusterTags}
+
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+	}
+	actual, err := ListResourcesOCI(cloud, cluster)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	a := map[string][]string{}
+	for k, r := range actual {
+		blocks := append([]string{}, r.Blocks...)
+		sort.Strings(blocks)
+		a[k] = blocks
+	}
+	e := map[string][]string{
+		toKey(typeVCN, vcnID):                      {},
+		toKey(typeInternetGateway, igID):           {toKey(typeVCN, vcnID)},
+		toKey(typeRouteTable, rtID):                {toKey(typeInternetGateway, igID), toKey(typeVCN, vcnID)},
+		toKey(typeSecurityList, slID):              {toKey(typeVCN, vcnID)},
+		toKey(typeSubnet, subnetID):                {toKey(typeRouteTable, rtID), toKey(typeSecurityList, slID), toKey(typeVCN, vcnID)},
+		toKey(typeNetworkLoadBalancer, nlbID):      {toKey(typeSubnet, subnetID)},
+		toKey(typeVolume, volumeID):                {},
+		toKey(typeInstanceConfiguration, configID): {},
+		toKey(typeInstancePool, poolID): {
+			toKey(typeInstanceConfiguration, configID),
+			toKey(typeNetworkLoadBalancer, nlbID),
+			toKey(typeSubnet, subnetID),
+			toKey(typeVolume, volumeID),
+		},
+		toKey(typeDynamicGroup, dgID): {},
+		toKey(typePolicy, policyID):   {toKey(typeDynamicGroup, dgID)},
+	}
+	if !reflect.DeepEqual(a, e) {
+		t.Errorf("expected %+v, but got %+v", e, a)
+	}
+
+	// Deleting the listed resources removes only those of the cluster.
+	for _, r := range actual {
+		if err := r.Deleter(cloud, r); err != nil {
+			t.Errorf("unexpected error deleting %s: %v", r.ID, err)
+		}
+	}
+	if _, ok := cloud.VCNsClient.VCNs[irrelevantID]; !ok {
+		t.Errorf("expected the VCN of another cluster to be kept")
+	}
+	if _, ok := cloud.VolumesClient.Volumes[volumeID]; ok {
+		t.Errorf("expected the volume of the cluster to be deleted")
+	}
+}
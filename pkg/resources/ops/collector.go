@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/pkg/resources/azure"
 	"k8s.io/kops/pkg/resources/digitalocean"
 	"k8s.io/kops/pkg/resources/gce"
+	"k8s.io/kops/pkg/resources/oci"
 	"k8s.io/kops/pkg/resources/openstack"
 	"k8s.io/kops/upup/pkg/fi"
 	cloudali "k8s.io/kops/upup/pkg/fi/cloudup/aliup"
@@ -33,6 +34,7 @@ import (
 	cloudazure "k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	clouddo "k8s.io/kops/upup/pkg/fi/cloudup/do"
 	cloudgce "k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	cloudoci "k8s.io/kops/upup/pkg/fi/cloudup/oci"
 	cloudopenstack "k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
@@ -52,7 +54,32 @@ func ListResources(cloud fi.Cloud, cluster *kops.Cluster, region string) (map[st
 		return ali.ListResourcesALI(cloud.(cloudali.ALICloud), clusterName, region)
 	case kops.CloudProviderAzure:
 		return azure.ListResourcesAzure(cloud.(cloudazure.AzureCloud), cluster)
+	case kops.CloudProviderOCI:
+		return oci.ListResourcesOCI(cloud.(cloudoci.OCICloud), cluster)
+	case kops.CloudProviderMetal:
+		// kOps does not provision the machines of metal clusters, so there is nothing to delete.
+		return map[string]*resources.Resource{}, nil
 	default:
 		return nil, fmt.Errorf("delete on clusters on %q not (yet) supported", cloud.ProviderID())
 	}
 }
+
+// ListOrphanedResources collects the resources of the cluster that it no longer uses
+func ListOrphanedResources(cloud fi.Cloud, cluster *kops.Cluster) (map[string]*resources.Resource, error) {
+	switch cloud.ProviderID() {
+	case kops.CloudProviderAWS:
+		return aws.ListOrphanedResourcesAWS(cloud.(awsup.AWSCloud), cluster.Name)
+	default:
+		return nil, fmt.Errorf("finding orphaned resources on %q not (yet) supported", cloud.ProviderID())
+	}
+}
+
+// BuildTerraformImports maps the resources of the cluster to the Terraform resources that should manage them
+func BuildTerraformImports(cloud fi.Cloud, cluster *kops.Cluster, resourceMap map[string]*resources.Resource) ([]*resources.TerraformImport, error) {
+	switch cloud.ProviderID() {
+	case kops.CloudProviderAWS:
+		return aws.BuildTerraformImportsAWS(resourceMap, cluster.Name), nil
+	default:
+		return nil, fmt.Errorf("terraform imports on %q not (yet) supported", cloud.ProviderID())
+	}
+}
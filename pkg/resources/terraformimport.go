@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TerraformImport maps a cloud resource to the Terraform resource that should manage it
+type TerraformImport struct {
+	// ResourceType is the Terraform resource type, e.g. aws_vpc
+	ResourceType string
+	// Name is the local name of the Terraform resource
+	Name string
+	// ID is the identifier of the cloud resource, as expected by terraform import
+	ID string
+}
+
+// Address returns the Terraform address of the resource
+func (i *TerraformImport) Address() string {
+	return i.ResourceType + "." + i.Name
+}
+
+var invalidTerraformNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// TerraformResourceName converts the name of a cloud resource to a Terraform local name,
+// the same way the Terraform target of kOps names the resources it writes
+func TerraformResourceName(name string) string {
+	name = strings.NewReplacer(".", "-", "/", "--", ":", "_").Replace(name)
+	name = invalidTerraformNameChars.ReplaceAllString(name, "-")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' {
+		name = "_" + name
+	}
+	return name
+}
+
+// SortTerraformImports sorts the imports by address, dropping those that repeat another import
+// and renaming any that share an address with another, so that each address is imported once
+func SortTerraformImports(imports []*TerraformImport) []*TerraformImport {
+	sort.Slice(imports, func(i, j int) bool {
+		if imports[i].Address() != imports[j].Address() {
+			return imports[i].Address() < imports[j].Address()
+		}
+		return imports[i].ID < imports[j].ID
+	})
+
+	var result []*TerraformImport
+	for i, imp := range imports {
+		if i != 0 && *imp == *imports[i-1] {
+			continue
+		}
+		result = append(result, imp)
+	}
+
+	addresses := make(map[string]bool)
+	for _, imp := range result {
+		addresses[imp.Address()] = true
+	}
+	for i := 1; i < len(result); i++ {
+		if result[i].Address() != result[i-1].Address() {
+			continue
+		}
+		name := result[i].Name + "-" + TerraformResourceName(result[i].ID)
+		for n := 2; addresses[result[i].ResourceType+"."+name]; n++ {
+			name = fmt.Sprintf("%s-%s-%d", result[i].Name, TerraformResourceName(result[i].ID), n)
+		}
+		result[i].Name = name
+		addresses[result[i].Address()] = true
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Address() < result[j].Address()
+	})
+	return result
+}
+
+// WriteTerraformImportBlocks writes Terraform import blocks for the resources
+func WriteTerraformImportBlocks(w io.Writer, imports []*TerraformImport) error {
+	imports = SortTerraformImports(imports)
+	for i, imp := range imports {
+		if i != 0 {
+			if _, err := fmt.Fprintf(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "import {\n  to = %s\n  id = %q\n}\n", imp.Address(), imp.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTerraformImportScript writes a shell script running terraform import for the resources
+func WriteTerraformImportScript(w io.Writer, imports []*TerraformImport) error {
+	imports = SortTerraformImports(imports)
+	if _, err := fmt.Fprintf(w, "#!/bin/bash\n\nset -o errexit\nset -o nounset\nset -o pipefail\n\n"); err != nil {
+		return err
+	}
+	for _, imp := range imports {
+		if _, err := fmt.Fprintf(w, "terraform import '%s' '%s'\n", imp.Address(), imp.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
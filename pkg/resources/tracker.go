@@ -39,5 +39,8 @@ type Resource struct {
 	// Dumper populates the dump with any information from the resource
 	Dumper func(op *DumpOperation, r *Resource) error
 
+	// EstimatedMonthlyCost is the estimated cost of keeping the resource, in US dollars per month
+	EstimatedMonthlyCost float64
+
 	Obj interface{}
 }
@@ -0,0 +1,167 @@
+//go:build simulate
+// +build simulate
+
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/cloudmock/aws/mockelb"
+	"k8s.io/kops/cloudmock/aws/mockelbv2"
+	"k8s.io/kops/cloudmock/aws/mockeventbridge"
+	"k8s.io/kops/cloudmock/aws/mockiam"
+	"k8s.io/kops/cloudmock/aws/mockroute53"
+	"k8s.io/kops/cloudmock/aws/mockservicequotas"
+	"k8s.io/kops/cloudmock/aws/mocksqs"
+	gcemock "k8s.io/kops/cloudmock/gce"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/dns"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// installMockCloud installs a mock of the cloud of the cluster, with the resources that the cluster
+// expects to exist beforehand: its zones, DNS zone and images.
+func installMockCloud(cluster *kopsapi.Cluster, instanceGroups []*kopsapi.InstanceGroup) error {
+	switch kopsapi.CloudProviderID(cluster.Spec.CloudProvider) {
+	case kopsapi.CloudProviderAWS:
+		return installMockAWS(cluster, instanceGroups)
+	case kopsapi.CloudProviderGCE:
+		return installMockGCE(cluster)
+	default:
+		return fmt.Errorf("simulating clusters is only supported on %s and %s, not %q", kopsapi.CloudProviderAWS, kopsapi.CloudProviderGCE, cluster.Spec.CloudProvider)
+	}
+}
+
+func installMockAWS(cluster *kopsapi.Cluster, instanceGroups []*kopsapi.InstanceGroup) error {
+	region, err := awsup.FindRegion(cluster)
+	if err != nil {
+		return err
+	}
+	if region == "" {
+		return fmt.Errorf("unable to determine the region of the cluster from its subnets")
+	}
+
+	zoneLetters := make(map[string]bool)
+	for _, subnet := range cluster.Spec.Subnets {
+		zoneLetters[strings.TrimPrefix(subnet.Zone, region)] = true
+	}
+	var letters []string
+	for letter := range zoneLetters {
+		letters = append(letters, letter)
+	}
+	sort.Strings(letters)
+
+	cloud := awsup.InstallMockAWSCloud(region, strings.Join(letters, ""))
+	mockEC2 := &mockec2.MockEC2{}
+	cloud.MockEC2 = mockEC2
+	mockRoute53 := &mockroute53.MockRoute53{}
+	cloud.MockRoute53 = mockRoute53
+	cloud.MockELB = &mockelb.MockELB{}
+	cloud.MockELBV2 = &mockelbv2.MockELBV2{}
+	cloud.MockIAM = &mockiam.MockIAM{}
+	cloud.MockAutoscaling = &mockautoscaling.MockAutoscaling{}
+	cloud.MockSQS = &mocksqs.MockSQS{}
+	cloud.MockEventBridge = &mockeventbridge.MockEventBridge{}
+	cloud.MockServiceQuotas = &mockservicequotas.MockServiceQuotas{}
+
+	if !dns.IsGossipHostname(cluster.ObjectMeta.Name) {
+		zone := &route53.HostedZone{
+			Id: aws.String("/hostedzone/Z1AFAKE1ZON3YO"),
+			Config: &route53.HostedZoneConfig{
+				PrivateZone: aws.Bool(false),
+			},
+		}
+		switch {
+		case cluster.Spec.DNSZone == "":
+			zone.Name = aws.String(clusterParentDomain(cluster.ObjectMeta.Name) + ".")
+		case strings.Contains(cluster.Spec.DNSZone, "."):
+			zone.Name = aws.String(strings.TrimSuffix(cluster.Spec.DNSZone, ".") + ".")
+		default:
+			// The DNS zone is given by its ID
+			zone.Id = aws.String("/hostedzone/" + cluster.Spec.DNSZone)
+			zone.Name = aws.String(clusterParentDomain(cluster.ObjectMeta.Name) + ".")
+		}
+		mockRoute53.MockCreateZone(zone, nil)
+	}
+
+	images := make(map[string]bool)
+	for _, ig := range instanceGroups {
+		image := cluster.InstanceGroupWithDefaults(ig).Spec.Image
+		if image == "" || images[image] {
+			continue
+		}
+		images[image] = true
+		mockEC2.Images = append(mockEC2.Images, mockImage(image, len(images)))
+	}
+	return nil
+}
+
+// mockImage returns an image that resolves from the image specification of an instance group.
+// Images are numbered from ami-12345678, as in the integration tests.
+func mockImage(spec string, n int) *ec2.Image {
+	image := &ec2.Image{
+		CreationDate:   aws.String("2021-01-01T00:00:00.000Z"),
+		ImageId:        aws.String(fmt.Sprintf("ami-%08d", 12345677+n)),
+		Name:           aws.String(spec),
+		RootDeviceName: aws.String("/dev/xvda"),
+		Architecture:   aws.String("x86_64"),
+	}
+	if strings.HasPrefix(spec, "ami-") {
+		image.ImageId = aws.String(spec)
+	} else if tokens := strings.SplitN(spec, "/", 2); len(tokens) == 2 {
+		image.Name = aws.String(tokens[1])
+	}
+	if strings.Contains(spec, "arm64") {
+		image.Architecture = aws.String("arm64")
+	}
+	return image
+}
+
+// clusterParentDomain returns the domain the cluster name is in, which kOps looks for a DNS zone of by default.
+func clusterParentDomain(clusterName string) string {
+	if i := strings.Index(clusterName, "."); i != -1 {
+		return clusterName[i+1:]
+	}
+	return clusterName
+}
+
+func installMockGCE(cluster *kopsapi.Cluster) error {
+	region := ""
+	for _, subnet := range cluster.Spec.Subnets {
+		if subnet.Region != "" {
+			region = subnet.Region
+		}
+	}
+	if region == "" {
+		return fmt.Errorf("on GCE, subnets must include Regions")
+	}
+	if cluster.Spec.Project == "" {
+		return fmt.Errorf("project is required for GCE")
+	}
+
+	gcemock.InstallMockGCECloud(region, cluster.Spec.Project)
+	return nil
+}
@@ -0,0 +1,250 @@
+//go:build simulate
+// +build simulate
+
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulate runs kops update cluster for cluster manifests against a mock cloud and an in-memory
+// state store, the way the integration tests of kOps do, and writes the terraform or cloudformation it renders.
+// Comparing the output between versions of kOps shows how an upgrade of kOps changes a cluster.
+//
+// The package installs a mock cloud, so it is only built with the simulate build tag and kept out of
+// release builds of kOps.
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/text"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	// registryPath is the in-memory state store simulations run against
+	registryPath = "memfs://simulate"
+
+	// defaultSSHPublicKey is the SSH public key used when none is given, so that the output is stable
+	defaultSSHPublicKey = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAgQCtWu40XQo8dczLsCq0OWV+hxm9uV3WxeH9Kgh4sMzQxNtoU1pvW0XdjpkBesRKGoolfWeCLXWxpyQb1IaiMkKoz7MdhQ/6UKjMjP66aFWWp3pwD0uj0HuJ7tq4gKHKRYGTaZIRWpzUiANBrjugVgA+Sd7E/mYwc/DMXkIyRZbvhQ==\n"
+)
+
+// Options configures a simulation.
+type Options struct {
+	// Manifests are the contents of the manifests of the cluster, as accepted by kops create -f:
+	// one Cluster, its InstanceGroups and optionally SSHCredentials.
+	Manifests [][]byte
+
+	// SSHPublicKey is the SSH public key of the admin user; a fixed key is used if not set.
+	SSHPublicKey []byte
+
+	// Target is the format of the output, cloudup.TargetTerraform (the default) or cloudup.TargetCloudformation.
+	Target string
+
+	// OutDir is the directory the output is written to.
+	OutDir string
+
+	// KeysDir is a local directory the keys and certificates of the cluster are kept in between simulations.
+	// Keysets missing from it are generated and written to it, so that later simulations render the same output.
+	// If not set, new keys are generated for every simulation.
+	KeysDir string
+}
+
+// Run simulates kops update cluster for the manifests, writing the output to options.OutDir.
+// Run resets the in-memory filesystem and installs a mock cloud, so it must not run concurrently
+// with other simulations or with code using them.
+func Run(ctx context.Context, options *Options) (*kopsapi.Cluster, error) {
+	target := options.Target
+	if target == "" {
+		target = cloudup.TargetTerraform
+	}
+	if target != cloudup.TargetTerraform && target != cloudup.TargetCloudformation {
+		return nil, fmt.Errorf("unsupported target %q; supported targets are %s and %s", target, cloudup.TargetTerraform, cloudup.TargetCloudformation)
+	}
+	if options.OutDir == "" {
+		return nil, fmt.Errorf("OutDir is required")
+	}
+
+	cluster, instanceGroups, sshCredentials, err := parseManifests(options.Manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := installMockCloud(cluster, instanceGroups); err != nil {
+		return nil, err
+	}
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath(registryPath)
+	if err != nil {
+		return nil, err
+	}
+	clientset := vfsclientset.NewVFSClientset(basePath)
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if err := cloudup.PerformAssignments(cluster, cloud); err != nil {
+		return nil, fmt.Errorf("error populating configuration: %v", err)
+	}
+	if _, err := clientset.CreateCluster(ctx, cluster); err != nil {
+		return nil, fmt.Errorf("error creating cluster: %v", err)
+	}
+	for _, ig := range instanceGroups {
+		if _, err := clientset.InstanceGroupsFor(cluster).Create(ctx, ig, metav1.CreateOptions{}); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return nil, fmt.Errorf("instanceGroup %q is defined more than once", ig.ObjectMeta.Name)
+			}
+			return nil, fmt.Errorf("error creating instanceGroup %q: %v", ig.ObjectMeta.Name, err)
+		}
+	}
+
+	sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if len(sshCredentials) == 0 {
+		sshPublicKey := options.SSHPublicKey
+		if len(sshPublicKey) == 0 {
+			sshPublicKey = []byte(defaultSSHPublicKey)
+		}
+		sshCredentials = append(sshCredentials, sshPublicKey)
+	}
+	for _, sshCredential := range sshCredentials {
+		if err := sshCredentialStore.AddSSHPublicKey(fi.SecretNameSSHPrimary, sshCredential); err != nil {
+			return nil, fmt.Errorf("error adding SSH public key: %v", err)
+		}
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return nil, err
+	}
+	var keysDirStore fi.CAStore
+	storedKeysets := map[string]*fi.Keyset{}
+	if options.KeysDir != "" {
+		keysDirStore = fi.NewVFSCAStore(cluster, vfs.NewFSPath(options.KeysDir))
+		if _, err := os.Stat(options.KeysDir); err == nil {
+			storedKeysets, err = keysDirStore.ListKeysets()
+			if err != nil {
+				return nil, fmt.Errorf("error reading keysets from %q: %v", options.KeysDir, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	for name, keyset := range storedKeysets {
+		if err := keyStore.StoreKeyset(name, keyset); err != nil {
+			return nil, fmt.Errorf("error storing keyset %q: %v", name, err)
+		}
+	}
+
+	// Read the cluster back, as kops update cluster would
+	cluster, err = clientset.GetCluster(ctx, cluster.ObjectMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	runTasksOptions := fi.RunTasksOptions{}
+	runTasksOptions.InitDefaults()
+	runTasksOptions.MaxTaskDuration = 30 * time.Second
+
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Cloud:           cloud,
+		Clientset:       clientset,
+		Cluster:         cluster,
+		RunTasksOptions: &runTasksOptions,
+		OutDir:          options.OutDir,
+		TargetName:      target,
+	}
+	if err := applyCmd.Run(ctx); err != nil {
+		return nil, err
+	}
+
+	if keysDirStore != nil {
+		keysets, err := keyStore.ListKeysets()
+		if err != nil {
+			return nil, err
+		}
+		for name, keyset := range keysets {
+			if storedKeysets[name] != nil {
+				continue
+			}
+			if err := keysDirStore.StoreKeyset(name, keyset); err != nil {
+				return nil, fmt.Errorf("error writing keyset %q to %q: %v", name, options.KeysDir, err)
+			}
+		}
+	}
+	return cluster, nil
+}
+
+// parseManifests decodes the cluster, its instance groups and its SSH public keys from the manifests.
+func parseManifests(manifests [][]byte) (*kopsapi.Cluster, []*kopsapi.InstanceGroup, [][]byte, error) {
+	var cluster *kopsapi.Cluster
+	var instanceGroups []*kopsapi.InstanceGroup
+	var sshCredentials [][]byte
+	for _, manifest := range manifests {
+		for _, section := range text.SplitContentToSections(manifest) {
+			if strings.TrimSpace(string(section)) == "" {
+				continue
+			}
+			o, gvk, err := kopscodecs.Decode(section, nil)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("error parsing manifest: %v", err)
+			}
+
+			switch v := o.(type) {
+			case *kopsapi.Cluster:
+				if cluster != nil {
+					return nil, nil, nil, fmt.Errorf("manifests define more than one cluster: %q and %q", cluster.ObjectMeta.Name, v.ObjectMeta.Name)
+				}
+				cluster = v
+			case *kopsapi.InstanceGroup:
+				instanceGroups = append(instanceGroups, v)
+			case *kopsapi.SSHCredential:
+				if v.Spec.PublicKey == "" {
+					return nil, nil, nil, fmt.Errorf("spec.PublicKey is required")
+				}
+				sshCredentials = append(sshCredentials, []byte(v.Spec.PublicKey))
+			default:
+				return nil, nil, nil, fmt.Errorf("unhandled kind %q", gvk)
+			}
+		}
+	}
+
+	if cluster == nil {
+		return nil, nil, nil, fmt.Errorf("manifests do not define a cluster")
+	}
+	for _, ig := range instanceGroups {
+		if clusterName := ig.ObjectMeta.Labels[kopsapi.LabelClusterName]; clusterName != cluster.ObjectMeta.Name {
+			return nil, nil, nil, fmt.Errorf("instanceGroup %q has %s label %q, not the name of cluster %q", ig.ObjectMeta.Name, kopsapi.LabelClusterName, clusterName, cluster.ObjectMeta.Name)
+		}
+	}
+	if len(instanceGroups) == 0 {
+		return nil, nil, nil, fmt.Errorf("manifests do not define any instance groups")
+	}
+	return cluster, instanceGroups, sshCredentials, nil
+}
@@ -0,0 +1,151 @@
+//go:build simulate
+// +build simulate
+
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestRunMinimal(t *testing.T) {
+	srcDir := "../../tests/integration/update_cluster/minimal"
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+	h.MockKopsVersion("1.21.0-alpha.1")
+
+	manifest, err := ioutil.ReadFile(path.Join(srcDir, "in-v1alpha2.yaml"))
+	if err != nil {
+		t.Fatalf("error reading manifest: %v", err)
+	}
+	sshPublicKey, err := ioutil.ReadFile(path.Join(srcDir, "id_rsa.pub"))
+	if err != nil {
+		t.Fatalf("error reading SSH public key: %v", err)
+	}
+
+	keysDir := path.Join(h.TempDir, "keys")
+	var outputs []map[string]string
+	for i := 0; i < 2; i++ {
+		outDir := path.Join(h.TempDir, fmt.Sprintf("out%d", i))
+		cluster, err := Run(context.Background(), &Options{
+			Manifests:    [][]byte{manifest},
+			SSHPublicKey: sshPublicKey,
+			OutDir:       outDir,
+			KeysDir:      keysDir,
+		})
+		if err != nil {
+			t.Fatalf("error simulating cluster: %v", err)
+		}
+		if cluster.ObjectMeta.Name != "minimal.example.com" {
+			t.Errorf("unexpected cluster name %q", cluster.ObjectMeta.Name)
+		}
+		outputs = append(outputs, readOutput(t, outDir))
+	}
+
+	if _, found := outputs[0]["kubernetes.tf"]; !found {
+		t.Fatalf("simulation did not write kubernetes.tf")
+	}
+	if !reflect.DeepEqual(outputs[0], outputs[1]) {
+		t.Errorf("simulations with the same keys directory rendered different output")
+	}
+
+	if _, err := os.Stat(path.Join(keysDir, "private", fi.CertificateIDCA, "keyset.yaml")); err != nil {
+		t.Errorf("expected the %s keyset to be written to the keys directory: %v", fi.CertificateIDCA, err)
+	}
+}
+
+// readOutput reads the files of a simulation, by their path relative to outDir.
+func readOutput(t *testing.T, outDir string) map[string]string {
+	output := make(map[string]string)
+	err := filepath.Walk(outDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		contents, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(outDir, p)
+		if err != nil {
+			return err
+		}
+		output[relativePath] = string(contents)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error reading output of simulation: %v", err)
+	}
+	return output
+}
+
+func TestRunErrors(t *testing.T) {
+	grid := []struct {
+		name     string
+		manifest string
+		options  Options
+		expected string
+	}{
+		{
+			name:     "no cluster",
+			manifest: "apiVersion: kops.k8s.io/v1alpha2\nkind: InstanceGroup\nmetadata:\n  name: nodes\n",
+			expected: "manifests do not define a cluster",
+		},
+		{
+			name:     "no instance groups",
+			manifest: "apiVersion: kops.k8s.io/v1alpha2\nkind: Cluster\nmetadata:\n  name: minimal.example.com\n",
+			expected: "manifests do not define any instance groups",
+		},
+		{
+			name:     "wrong cluster label",
+			manifest: "apiVersion: kops.k8s.io/v1alpha2\nkind: Cluster\nmetadata:\n  name: minimal.example.com\n---\napiVersion: kops.k8s.io/v1alpha2\nkind: InstanceGroup\nmetadata:\n  name: nodes\n  labels:\n    kops.k8s.io/cluster: other.example.com\n",
+			expected: "instanceGroup \"nodes\" has kops.k8s.io/cluster label \"other.example.com\", not the name of cluster \"minimal.example.com\"",
+		},
+		{
+			name:     "unsupported target",
+			options:  Options{Target: "direct"},
+			expected: "unsupported target \"direct\"; supported targets are terraform and cloudformation",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			options := g.options
+			options.Manifests = [][]byte{[]byte(g.manifest)}
+			if options.OutDir == "" {
+				options.OutDir = t.TempDir()
+			}
+			_, err := Run(context.Background(), &options)
+			if err == nil {
+				t.Fatalf("expected error %q", g.expected)
+			}
+			if err.Error() != g.expected {
+				t.Errorf("unexpected error: expected %q, got %q", g.expected, err.Error())
+			}
+		})
+	}
+}
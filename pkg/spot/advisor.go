@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// DefaultAdvisorURL is the location of the data published by the AWS Spot Instance Advisor.
+const DefaultAdvisorURL = "https://spot-bid-advisor.s3.amazonaws.com/spot-advisor-data.json"
+
+// advisorOS is the operating system of the advice; kOps only runs Linux instances.
+const advisorOS = "Linux"
+
+// Advisor holds the interruption frequency and savings of spot instances, as published by the Spot Instance Advisor.
+type Advisor struct {
+	Ranges []AdvisorRange `json:"ranges"`
+
+	// Advice is indexed by region, then operating system, then instance type.
+	Advice map[string]map[string]map[string]AdvisorEntry `json:"spot_advisor"`
+}
+
+// AdvisorRange is a range of the frequency of interruption of spot instances.
+type AdvisorRange struct {
+	Index int    `json:"index"`
+	Label string `json:"label"`
+	// Max is the upper bound of the frequency of interruption, in percent.
+	Max int `json:"max"`
+}
+
+// AdvisorEntry is the advice for an instance type in a region.
+type AdvisorEntry struct {
+	// Savings is the savings over on-demand instances, in percent.
+	Savings int `json:"s"`
+	// Range is the index of the range of the frequency of interruption.
+	Range int `json:"r"`
+}
+
+// Interruption describes the frequency of interruption of spot instances of an instance type.
+type Interruption struct {
+	// Label is the range of the frequency of interruption, for example "<5%".
+	Label string `json:"label"`
+	// Max is the upper bound of the frequency of interruption, in percent.
+	Max int `json:"max"`
+	// Savings is the savings over on-demand instances, in percent.
+	Savings int `json:"savings"`
+}
+
+// LoadAdvisor reads the Spot Instance Advisor data from the location.
+func LoadAdvisor(location string) (*Advisor, error) {
+	data, err := vfs.Context.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("error reading spot advisor data from %q: %v", location, err)
+	}
+	return ParseAdvisor(data)
+}
+
+// ParseAdvisor parses Spot Instance Advisor data.
+func ParseAdvisor(data []byte) (*Advisor, error) {
+	advisor := &Advisor{}
+	if err := json.Unmarshal(data, advisor); err != nil {
+		return nil, fmt.Errorf("error parsing spot advisor data: %v", err)
+	}
+	return advisor, nil
+}
+
+// Lookup returns the frequency of interruption of the instance type in the region, or nil if it is unknown.
+func (a *Advisor) Lookup(region, instanceType string) *Interruption {
+	if a == nil {
+		return nil
+	}
+	entry, found := a.Advice[region][advisorOS][instanceType]
+	if !found {
+		return nil
+	}
+	for _, r := range a.Ranges {
+		if r.Index == entry.Range {
+			return &Interruption{Label: r.Label, Max: r.Max, Savings: entry.Savings}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spot
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// PriceHistoryWindow is the period over which spot prices are averaged by default.
+const PriceHistoryWindow = 24 * time.Hour
+
+// productDescription is the product of the spot prices; kOps only runs Linux instances.
+const productDescription = "Linux/UNIX"
+
+// Prices are spot prices in USD per hour, by instance type and then availability zone.
+type Prices map[string]map[string]float64
+
+// Get returns the price of the instance type in the zone, and whether it is known.
+func (p Prices) Get(instanceType, zone string) (float64, bool) {
+	price, found := p[instanceType][zone]
+	return price, found
+}
+
+// IsSpot returns true if the instance group launches spot instances.
+func IsSpot(ig *kops.InstanceGroup) bool {
+	if ig.Spec.MixedInstancesPolicy != nil {
+		// The autoscaling group only launches on-demand instances unless some capacity above the base is spot
+		return ig.Spec.MixedInstancesPolicy.OnDemandAboveBase != nil && *ig.Spec.MixedInstancesPolicy.OnDemandAboveBase < 100
+	}
+	return ig.Spec.MaxPrice != nil
+}
+
+// MaxPrice returns the maximum spot price of the instance group, or false if none is set.
+func MaxPrice(ig *kops.InstanceGroup) (float64, bool, error) {
+	if ig.Spec.MaxPrice == nil {
+		return 0, false, nil
+	}
+	price, err := strconv.ParseFloat(*ig.Spec.MaxPrice, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid maxPrice %q: %v", *ig.Spec.MaxPrice, err)
+	}
+	return price, true, nil
+}
+
+// InstanceTypes returns the instance types that the instance group may launch.
+func InstanceTypes(ig *kops.InstanceGroup) []string {
+	if ig.Spec.MixedInstancesPolicy != nil && len(ig.Spec.MixedInstancesPolicy.Instances) != 0 {
+		return ig.Spec.MixedInstancesPolicy.Instances
+	}
+	if ig.Spec.MachineType == "" {
+		return nil
+	}
+	return strings.Split(ig.Spec.MachineType, ",")
+}
+
+// AveragePrices returns the spot prices of the instance types in the zones, averaged over the window before now.
+// The price history only records price changes, so each price is weighted by how long it was in effect.
+func AveragePrices(cloud awsup.AWSCloud, instanceTypes []string, zones []string, window time.Duration) (Prices, error) {
+	if len(instanceTypes) == 0 || len(zones) == 0 {
+		return Prices{}, nil
+	}
+
+	now := time.Now()
+	start := now.Add(-window)
+
+	request := &ec2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       aws.StringSlice(instanceTypes),
+		ProductDescriptions: aws.StringSlice([]string{productDescription}),
+		StartTime:           aws.Time(start),
+		EndTime:             aws.Time(now),
+		Filters:             []*ec2.Filter{awsup.NewEC2Filter("availability-zone", zones...)},
+	}
+
+	history := make(map[string]map[string][]*ec2.SpotPrice)
+	err := cloud.EC2().DescribeSpotPriceHistoryPages(request, func(page *ec2.DescribeSpotPriceHistoryOutput, lastPage bool) bool {
+		for _, price := range page.SpotPriceHistory {
+			instanceType := aws.StringValue(price.InstanceType)
+			zone := aws.StringValue(price.AvailabilityZone)
+			if history[instanceType] == nil {
+				history[instanceType] = make(map[string][]*ec2.SpotPrice)
+			}
+			history[instanceType][zone] = append(history[instanceType][zone], price)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing spot price history: %v", err)
+	}
+
+	prices := make(Prices)
+	for instanceType, byZone := range history {
+		for zone, points := range byZone {
+			average, ok, err := timeWeightedAverage(points, start, now)
+			if err != nil {
+				return nil, fmt.Errorf("error reading spot prices of %q in %q: %v", instanceType, zone, err)
+			}
+			if !ok {
+				continue
+			}
+			if prices[instanceType] == nil {
+				prices[instanceType] = make(map[string]float64)
+			}
+			prices[instanceType][zone] = average
+		}
+	}
+	return prices, nil
+}
+
+// timeWeightedAverage averages the prices between start and end, each price being in effect until the next one.
+func timeWeightedAverage(points []*ec2.SpotPrice, start, end time.Time) (float64, bool, error) {
+	sort.Slice(points, func(i, j int) bool {
+		return aws.TimeValue(points[i].Timestamp).Before(aws.TimeValue(points[j].Timestamp))
+	})
+
+	var total, duration float64
+	for i, point := range points {
+		price, err := strconv.ParseFloat(aws.StringValue(point.SpotPrice), 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid spot price %q: %v", aws.StringValue(point.SpotPrice), err)
+		}
+
+		from := aws.TimeValue(point.Timestamp)
+		if from.Before(start) {
+			// The history includes the price in effect at the start of the window
+			from = start
+		}
+		until := end
+		if i+1 < len(points) {
+			until = aws.TimeValue(points[i+1].Timestamp)
+		}
+		if !until.After(from) {
+			continue
+		}
+
+		seconds := until.Sub(from).Seconds()
+		total += price * seconds
+		duration += seconds
+	}
+
+	if duration == 0 {
+		return 0, false, nil
+	}
+	return total / duration, true, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spot
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// InstanceTypeReport summarizes the spot prices and interruption risk of an instance type of an instance group.
+type InstanceTypeReport struct {
+	InstanceGroup string `json:"instanceGroup"`
+	InstanceType  string `json:"instanceType"`
+
+	// MaxPrice is the maximum spot price of the instance group, if set.
+	MaxPrice *float64 `json:"maxPrice,omitempty"`
+	// AveragePrices are the average spot prices of the instance type, by zone.
+	AveragePrices map[string]float64 `json:"averagePrices,omitempty"`
+	// BelowAverage are the zones in which the maximum price is below the average spot price.
+	BelowAverage []string `json:"belowAverage,omitempty"`
+
+	// Interruption is the interruption risk of the instance type in the region, if known.
+	Interruption *Interruption `json:"interruption,omitempty"`
+}
+
+// BuildReport summarizes the spot prices and interruption risk of the instance types of the instance groups that
+// launch spot instances. The advisor is optional.
+func BuildReport(cloud awsup.AWSCloud, cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, advisor *Advisor, window time.Duration) ([]*InstanceTypeReport, error) {
+	var reports []*InstanceTypeReport
+
+	for _, ig := range instanceGroups {
+		if !IsSpot(ig) {
+			continue
+		}
+
+		maxPrice, hasMaxPrice, err := MaxPrice(ig)
+		if err != nil {
+			return nil, fmt.Errorf("instance group %q: %v", ig.ObjectMeta.Name, err)
+		}
+
+		zones, err := model.FindZonesForInstanceGroup(cluster, ig)
+		if err != nil {
+			return nil, fmt.Errorf("instance group %q: %v", ig.ObjectMeta.Name, err)
+		}
+
+		instanceTypes := InstanceTypes(ig)
+		prices, err := AveragePrices(cloud, instanceTypes, zones, window)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instanceType := range instanceTypes {
+			report := &InstanceTypeReport{
+				InstanceGroup: ig.ObjectMeta.Name,
+				InstanceType:  instanceType,
+				AveragePrices: prices[instanceType],
+				Interruption:  advisor.Lookup(cloud.Region(), instanceType),
+			}
+			if hasMaxPrice {
+				report.MaxPrice = &maxPrice
+				report.BelowAverage = BelowAverage(prices, instanceType, zones, maxPrice)
+			}
+			reports = append(reports, report)
+		}
+	}
+
+	return reports, nil
+}
+
+// BelowAverage returns the zones in which the maximum price is below the average spot price of the instance type.
+func BelowAverage(prices Prices, instanceType string, zones []string, maxPrice float64) []string {
+	var below []string
+	for _, zone := range zones {
+		if average, found := prices.Get(instanceType, zone); found && maxPrice < average {
+			below = append(below, zone)
+		}
+	}
+	sort.Strings(below)
+	return below
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spot
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+const advisorData = `{
+  "ranges": [
+    {"index": 0, "label": "<5%", "max": 5},
+    {"index": 1, "label": "5-10%", "max": 11},
+    {"index": 4, "label": ">20%", "max": 100}
+  ],
+  "spot_advisor": {
+    "us-east-1": {
+      "Linux": {
+        "m5.large": {"s": 70, "r": 0},
+        "c5.large": {"s": 60, "r": 4}
+      },
+      "Windows": {
+        "t3.medium": {"s": 40, "r": 1}
+      }
+    }
+  }
+}`
+
+func spotPrice(instanceType, zone string, price string, age time.Duration) *ec2.SpotPrice {
+	return &ec2.SpotPrice{
+		InstanceType:       aws.String(instanceType),
+		AvailabilityZone:   aws.String(zone),
+		ProductDescription: aws.String(productDescription),
+		SpotPrice:          aws.String(price),
+		Timestamp:          aws.Time(time.Now().Add(-age)),
+	}
+}
+
+func buildCloud() *awsup.MockAWSCloud {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "ab")
+	cloud.MockEC2 = &mockec2.MockEC2{
+		SpotPriceHistory: []*ec2.SpotPrice{
+			// m5.large costs 0.02 for 6h and 0.04 for 18h in us-east-1a
+			spotPrice("m5.large", "us-east-1a", "0.01", 48*time.Hour),
+			spotPrice("m5.large", "us-east-1a", "0.02", 30*time.Hour),
+			spotPrice("m5.large", "us-east-1a", "0.04", 18*time.Hour),
+			spotPrice("m5.large", "us-east-1b", "0.03", 36*time.Hour),
+			spotPrice("c5.large", "us-east-1a", "0.05", 12*time.Hour),
+			{
+				InstanceType:       aws.String("m5.large"),
+				AvailabilityZone:   aws.String("us-east-1a"),
+				ProductDescription: aws.String("Windows"),
+				SpotPrice:          aws.String("1.00"),
+				Timestamp:          aws.Time(time.Now().Add(-time.Hour)),
+			},
+		},
+	}
+	return cloud
+}
+
+func TestAveragePrices(t *testing.T) {
+	cloud := buildCloud()
+
+	prices, err := AveragePrices(cloud, []string{"m5.large", "c5.large", "t3.medium"}, []string{"us-east-1a", "us-east-1b"}, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]float64{
+		"m5.large/us-east-1a": 0.035,
+		"m5.large/us-east-1b": 0.03,
+		"c5.large/us-east-1a": 0.05,
+	}
+	actual := make(map[string]float64)
+	for instanceType, byZone := range prices {
+		for zone, price := range byZone {
+			actual[instanceType+"/"+zone] = price
+		}
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected prices: %v", actual)
+	}
+	for k, price := range expected {
+		if math.Abs(actual[k]-price) > 0.0001 {
+			t.Errorf("unexpected average price of %s: expected %v, got %v", k, price, actual[k])
+		}
+	}
+}
+
+func TestAdvisor(t *testing.T) {
+	advisor, err := ParseAdvisor([]byte(advisorData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	grid := []struct {
+		Region       string
+		InstanceType string
+		Expected     *Interruption
+	}{
+		{Region: "us-east-1", InstanceType: "m5.large", Expected: &Interruption{Label: "<5%", Max: 5, Savings: 70}},
+		{Region: "us-east-1", InstanceType: "c5.large", Expected: &Interruption{Label: ">20%", Max: 100, Savings: 60}},
+		{Region: "us-east-1", InstanceType: "t3.medium"},
+		{Region: "us-west-2", InstanceType: "m5.large"},
+	}
+	for _, g := range grid {
+		actual := advisor.Lookup(g.Region, g.InstanceType)
+		if !reflect.DeepEqual(actual, g.Expected) {
+			t.Errorf("unexpected advice for %s in %s: expected %+v, got %+v", g.InstanceType, g.Region, g.Expected, actual)
+		}
+	}
+
+	var missing *Advisor
+	if actual := missing.Lookup("us-east-1", "m5.large"); actual != nil {
+		t.Errorf("unexpected advice without advisor data: %+v", actual)
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	cloud := buildCloud()
+	advisor, err := ParseAdvisor([]byte(advisorData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "us-east-1a", Zone: "us-east-1a"},
+				{Name: "us-east-1b", Zone: "us-east-1b"},
+			},
+		},
+	}
+	instanceGroups := []*kops.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "on-demand"},
+			Spec:       kops.InstanceGroupSpec{MachineType: "m5.large", Subnets: []string{"us-east-1a"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "spot"},
+			Spec: kops.InstanceGroupSpec{
+				MachineType: "m5.large",
+				MaxPrice:    aws.String("0.032"),
+				Subnets:     []string{"us-east-1a", "us-east-1b"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mixed"},
+			Spec: kops.InstanceGroupSpec{
+				MachineType: "m5.large",
+				MixedInstancesPolicy: &kops.MixedInstancesPolicySpec{
+					Instances:         []string{"c5.large"},
+					OnDemandAboveBase: aws.Int64(0),
+				},
+				Subnets: []string{"us-east-1a"},
+			},
+		},
+	}
+
+	reports, err := BuildReport(cloud, cluster, instanceGroups, advisor, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected reports for the spot instance groups, got %d", len(reports))
+	}
+
+	spotReport := reports[0]
+	if spotReport.InstanceGroup != "spot" || spotReport.InstanceType != "m5.large" {
+		t.Errorf("unexpected report: %+v", spotReport)
+	}
+	if !reflect.DeepEqual(spotReport.BelowAverage, []string{"us-east-1a"}) {
+		t.Errorf("expected maxPrice to be below the average price in us-east-1a, got %v", spotReport.BelowAverage)
+	}
+	if spotReport.Interruption == nil || spotReport.Interruption.Label != "<5%" {
+		t.Errorf("unexpected interruption: %+v", spotReport.Interruption)
+	}
+
+	mixedReport := reports[1]
+	if mixedReport.InstanceGroup != "mixed" || mixedReport.InstanceType != "c5.large" || mixedReport.MaxPrice != nil || len(mixedReport.BelowAverage) != 0 {
+		t.Errorf("unexpected report: %+v", mixedReport)
+	}
+}
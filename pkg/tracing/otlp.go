@@ -0,0 +1,233 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTLPExporter exports spans to an OpenTelemetry collector using OTLP over HTTP, with the JSON encoding
+type OTLPExporter struct {
+	// URL is the traces endpoint, e.g. http://localhost:4318/v1/traces
+	URL string
+	// Headers are added to every export request
+	Headers map[string]string
+
+	Client *http.Client
+}
+
+// NewOTLPExporterFromEnv builds an exporter from the standard OpenTelemetry environment variables,
+// returning nil if no endpoint is configured.
+func NewOTLPExporterFromEnv() (*OTLPExporter, error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return nil, nil
+		}
+		endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %v", endpoint, err)
+	}
+
+	headers := make(map[string]string)
+	for _, header := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		if strings.TrimSpace(header) == "" {
+			continue
+		}
+		tokens := strings.SplitN(header, "=", 2)
+		if len(tokens) != 2 {
+			return nil, fmt.Errorf("invalid header %q in OTEL_EXPORTER_OTLP_HEADERS", header)
+		}
+		value, err := url.QueryUnescape(strings.TrimSpace(tokens[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid header %q in OTEL_EXPORTER_OTLP_HEADERS: %v", header, err)
+		}
+		headers[strings.TrimSpace(tokens[0])] = value
+	}
+
+	return &OTLPExporter{
+		URL:     endpoint,
+		Headers: headers,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Export implements Exporter
+func (e *OTLPExporter) Export(ctx context.Context, serviceName string, spans []*Span) error {
+	body, err := json.Marshal(buildOTLPRequest(serviceName, spans))
+	if err != nil {
+		return fmt.Errorf("error encoding spans: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error exporting spans to %s: %v", e.URL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		b, _ := ioutil.ReadAll(response.Body)
+		return fmt.Errorf("error exporting spans to %s: %s: %s", e.URL, response.Status, string(b))
+	}
+	return nil
+}
+
+// The types below follow the JSON mapping of the OTLP protobuf messages
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func buildOTLPRequest(serviceName string, spans []*Span) *otlpRequest {
+	scope := otlpScopeSpans{
+		Scope: otlpScope{Name: "k8s.io/kops"},
+	}
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           hex.EncodeToString(span.TraceID[:]),
+			SpanID:            hex.EncodeToString(span.SpanID[:]),
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        buildOTLPAttributes(span.Attributes),
+			Status:            otlpStatus{Code: otlpStatusOK},
+		}
+		if span.ParentID != [8]byte{} {
+			s.ParentSpanID = hex.EncodeToString(span.ParentID[:])
+		}
+		if span.Error != nil {
+			s.Status = otlpStatus{Code: otlpStatusError, Message: span.Error.Error()}
+		}
+		scope.Spans = append(scope.Spans, s)
+	}
+
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: buildOTLPAttributes([]Attribute{{Key: "service.name", Value: serviceName}}),
+				},
+				ScopeSpans: []otlpScopeSpans{scope},
+			},
+		},
+	}
+}
+
+func buildOTLPAttributes(attributes []Attribute) []otlpKeyValue {
+	var kvs []otlpKeyValue
+	for _, attribute := range attributes {
+		var v otlpAnyValue
+		switch value := attribute.Value.(type) {
+		case bool:
+			v.BoolValue = &value
+		case int:
+			s := strconv.Itoa(value)
+			v.IntValue = &s
+		case int32:
+			s := strconv.FormatInt(int64(value), 10)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(value, 10)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &value
+		default:
+			s := fmt.Sprintf("%v", value)
+			v.StringValue = &s
+		}
+		kvs = append(kvs, otlpKeyValue{Key: attribute.Key, Value: v})
+	}
+	return kvs
+}
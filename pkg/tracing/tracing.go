@@ -0,0 +1,255 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// SpanKind is the OpenTelemetry kind of a span
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindClient   SpanKind = 3
+)
+
+// Span is a timed operation, exported as an OpenTelemetry span.
+// All methods are safe to call on a nil Span, which is returned when tracing is disabled.
+type Span struct {
+	tracer *Tracer
+
+	TraceID  [16]byte
+	SpanID   [8]byte
+	ParentID [8]byte
+
+	Name  string
+	Kind  SpanKind
+	Start time.Time
+	End   time.Time
+	Error error
+
+	mutex      sync.Mutex
+	Attributes []Attribute
+}
+
+// Attribute is a key/value pair recorded on a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Exporter sends finished spans to a tracing backend
+type Exporter interface {
+	Export(ctx context.Context, serviceName string, spans []*Span) error
+}
+
+// Tracer records spans and hands them to an exporter on Flush
+type Tracer struct {
+	serviceName string
+	exporter    Exporter
+
+	mutex sync.Mutex
+	root  *Span
+	ended []*Span
+}
+
+// NewTracer builds a tracer that exports spans for serviceName to the exporter
+func NewTracer(serviceName string, exporter Exporter) *Tracer {
+	return &Tracer{
+		serviceName: serviceName,
+		exporter:    exporter,
+	}
+}
+
+var (
+	globalMutex  sync.Mutex
+	globalTracer *Tracer
+)
+
+// Init enables tracing when an OTLP endpoint is configured with the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT environment variables.
+func Init(serviceName string) error {
+	exporter, err := NewOTLPExporterFromEnv()
+	if err != nil {
+		return err
+	}
+	if exporter == nil {
+		return nil
+	}
+	SetTracer(NewTracer(serviceName, exporter))
+	return nil
+}
+
+// SetTracer sets the tracer used by Start; a nil tracer disables tracing
+func SetTracer(t *Tracer) {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+	globalTracer = t
+}
+
+func getTracer() *Tracer {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+	return globalTracer
+}
+
+// Enabled returns true if spans are being recorded
+func Enabled() bool {
+	return getTracer() != nil
+}
+
+// Flush exports all the finished spans
+func Flush(ctx context.Context) error {
+	t := getTracer()
+	if t == nil {
+		return nil
+	}
+	return t.Flush(ctx)
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the span stored in the context, if any
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Start starts a span named name, with attributes given as alternating keys and values.
+// The span is a child of the span in ctx; if there is none it is a child of the
+// outermost span still running, so that work without a context (such as cloud requests)
+// is attached to the operation in progress.
+func Start(ctx context.Context, name string, keysAndValues ...interface{}) (context.Context, *Span) {
+	return StartKind(ctx, name, SpanKindInternal, keysAndValues...)
+}
+
+// StartKind is Start, for a span of the given kind
+func StartKind(ctx context.Context, name string, kind SpanKind, keysAndValues ...interface{}) (context.Context, *Span) {
+	t := getTracer()
+	if t == nil {
+		return ctx, nil
+	}
+	span := t.start(SpanFromContext(ctx), name, kind, time.Now())
+	span.SetAttributes(keysAndValues...)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// StartAt starts a span with an explicit start time, for operations observed after the fact
+func StartAt(ctx context.Context, name string, kind SpanKind, start time.Time) *Span {
+	t := getTracer()
+	if t == nil {
+		return nil
+	}
+	return t.start(SpanFromContext(ctx), name, kind, start)
+}
+
+func (t *Tracer) start(parent *Span, name string, kind SpanKind, start time.Time) *Span {
+	span := &Span{
+		tracer: t,
+		Name:   name,
+		Kind:   kind,
+		Start:  start,
+	}
+	randomBytes(span.SpanID[:])
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if parent == nil {
+		parent = t.root
+	}
+	if parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		randomBytes(span.TraceID[:])
+		t.root = span
+	}
+	return span
+}
+
+// SetAttributes records attributes given as alternating keys and values
+func (s *Span) SetAttributes(keysAndValues ...interface{}) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		s.Attributes = append(s.Attributes, Attribute{
+			Key:   fmt.Sprintf("%v", keysAndValues[i]),
+			Value: keysAndValues[i+1],
+		})
+	}
+}
+
+// Finish ends the span, recording err as its status
+func (s *Span) Finish(err error) {
+	s.FinishAt(time.Now(), err)
+}
+
+// FinishAt ends the span at the given time, recording err as its status
+func (s *Span) FinishAt(end time.Time, err error) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.End = end
+	s.Error = err
+	s.mutex.Unlock()
+
+	t := s.tracer
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.root == s {
+		t.root = nil
+	}
+	t.ended = append(t.ended, s)
+}
+
+// Flush exports the finished spans
+func (t *Tracer) Flush(ctx context.Context) error {
+	t.mutex.Lock()
+	spans := t.ended
+	t.ended = nil
+	t.mutex.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+	klog.V(4).InfoS("Exporting spans", "count", len(spans))
+	return t.exporter.Export(ctx, t.serviceName, spans)
+}
+
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		klog.Warningf("unable to generate random span ID: %v", err)
+	}
+}
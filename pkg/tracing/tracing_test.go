@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestTracerExportsOTLP(t *testing.T) {
+	var requests []otlpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("expected Authorization header, got %q", r.Header.Get("Authorization"))
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("error reading body: %v", err)
+		}
+		var request otlpRequest
+		if err := json.Unmarshal(b, &request); err != nil {
+			t.Fatalf("error parsing body %s: %v", string(b), err)
+		}
+		requests = append(requests, request)
+	}))
+	defer server.Close()
+
+	SetTracer(NewTracer("kops", &OTLPExporter{
+		URL:     server.URL + "/v1/traces",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}))
+	defer SetTracer(nil)
+
+	ctx, root := Start(context.Background(), "ApplyCluster", "kops.cluster", "test.k8s.local")
+	_, child := Start(ctx, "Subnet/a", "kops.task", "Subnet/a")
+	child.SetAttributes("kops.resource_id", "subnet-1234")
+	child.Finish(errors.New("subnet is not ready"))
+	// Spans without a parent in the context are attached to the running root span
+	_, request := StartKind(context.TODO(), "ec2/DescribeSubnets", SpanKindClient, "http.status_code", 200, "kops.retries", 0)
+	request.Finish(nil)
+	root.Finish(nil)
+
+	if err := Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error from Flush: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected one export request, got %d", len(requests))
+	}
+	if v := *requests[0].ResourceSpans[0].Resource.Attributes[0].Value.StringValue; v != "kops" {
+		t.Errorf("expected service.name kops, got %q", v)
+	}
+
+	spans := make(map[string]otlpSpan)
+	for _, span := range requests[0].ResourceSpans[0].ScopeSpans[0].Spans {
+		spans[span.Name] = span
+	}
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %+v", spans)
+	}
+
+	rootSpan := spans["ApplyCluster"]
+	if rootSpan.ParentSpanID != "" || len(rootSpan.TraceID) != 32 || len(rootSpan.SpanID) != 16 {
+		t.Errorf("unexpected root span %+v", rootSpan)
+	}
+	for _, name := range []string{"Subnet/a", "ec2/DescribeSubnets"} {
+		span := spans[name]
+		if span.TraceID != rootSpan.TraceID || span.ParentSpanID != rootSpan.SpanID {
+			t.Errorf("expected span %q to be a child of the root span, got %+v", name, span)
+		}
+	}
+
+	task := spans["Subnet/a"]
+	if task.Status.Code != otlpStatusError || task.Status.Message != "subnet is not ready" {
+		t.Errorf("unexpected status %+v", task.Status)
+	}
+	if len(task.Attributes) != 2 || task.Attributes[1].Key != "kops.resource_id" || *task.Attributes[1].Value.StringValue != "subnet-1234" {
+		t.Errorf("unexpected attributes %+v", task.Attributes)
+	}
+	if spans["ec2/DescribeSubnets"].Kind != SpanKindClient || *spans["ec2/DescribeSubnets"].Attributes[0].Value.IntValue != "200" {
+		t.Errorf("unexpected span %+v", spans["ec2/DescribeSubnets"])
+	}
+
+	// Spans are only exported once
+	if err := Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error from Flush: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("expected no further export requests, got %d", len(requests))
+	}
+}
+
+func TestDisabledTracing(t *testing.T) {
+	ctx, span := Start(context.Background(), "ApplyCluster")
+	if span != nil || SpanFromContext(ctx) != nil {
+		t.Errorf("expected no span when tracing is disabled")
+	}
+	span.SetAttributes("key", "value")
+	span.Finish(nil)
+	if err := Flush(ctx); err != nil {
+		t.Errorf("unexpected error from Flush: %v", err)
+	}
+}
+
+func TestNewOTLPExporterFromEnv(t *testing.T) {
+	grid := []struct {
+		Env      map[string]string
+		URL      string
+		Headers  map[string]string
+		Disabled bool
+	}{
+		{
+			Disabled: true,
+		},
+		{
+			Env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318/"},
+			URL: "http://collector:4318/v1/traces",
+		},
+		{
+			Env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT":        "http://collector:4318",
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "https://traces.example.com/api/traces",
+				"OTEL_EXPORTER_OTLP_HEADERS":         "x-api-key=abc%3D, x-team = infra",
+			},
+			URL:     "https://traces.example.com/api/traces",
+			Headers: map[string]string{"x-api-key": "abc=", "x-team": "infra"},
+		},
+	}
+	for _, g := range grid {
+		for _, k := range []string{"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_HEADERS"} {
+			old, found := os.LookupEnv(k)
+			if found {
+				defer os.Setenv(k, old)
+			} else {
+				defer os.Unsetenv(k)
+			}
+			if v, ok := g.Env[k]; ok {
+				os.Setenv(k, v)
+			} else {
+				os.Unsetenv(k)
+			}
+		}
+
+		exporter, err := NewOTLPExporterFromEnv()
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v", g.Env, err)
+			continue
+		}
+		if g.Disabled {
+			if exporter != nil {
+				t.Errorf("expected no exporter for %v, got %+v", g.Env, exporter)
+			}
+			continue
+		}
+		if exporter.URL != g.URL {
+			t.Errorf("expected URL %q, got %q", g.URL, exporter.URL)
+		}
+		for k, v := range g.Headers {
+			if exporter.Headers[k] != v {
+				t.Errorf("expected header %s=%q, got %q", k, v, exporter.Headers[k])
+			}
+		}
+		if len(exporter.Headers) != len(g.Headers) {
+			t.Errorf("unexpected headers %v", exporter.Headers)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templater
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"sigs.k8s.io/yaml"
+)
+
+// ValidateValues checks the template values against a JSON schema, which can be written as either JSON or YAML
+func ValidateValues(schema []byte, values map[string]interface{}) error {
+	schemaJSON, err := yaml.YAMLToJSON(schema)
+	if err != nil {
+		return fmt.Errorf("unable to parse the values schema: %v", err)
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaJSON), gojsonschema.NewGoLoader(values))
+	if err != nil {
+		return fmt.Errorf("unable to validate the values against the schema: %v", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	var problems []string
+	for _, e := range result.Errors() {
+		problems = append(problems, e.String())
+	}
+	sort.Strings(problems)
+
+	return fmt.Errorf("values do not match the schema:\n  %s", strings.Join(problems, "\n  "))
+}
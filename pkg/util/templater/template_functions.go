@@ -17,13 +17,18 @@ limitations under the License.
 package templater
 
 import (
+	"fmt"
+	"net"
+	"sort"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
+	"github.com/apparentlymart/go-cidr/cidr"
 	"github.com/blang/semver/v4"
 	"k8s.io/kops"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/architectures"
 )
 
@@ -70,5 +75,88 @@ func (r *Templater) templateFuncsMap(tm *template.Template) template.FuncMap {
 		return imageSpec.Name
 	}
 
+	funcs["CIDRSubnet"] = cidrSubnet
+	funcs["CIDRHost"] = cidrHost
+	funcs["SelectZones"] = selectZones
+
 	return funcs
 }
+
+// cidrSubnet returns the netNum'th subnet of size newSize within prefix, e.g. CIDRSubnet "10.0.0.0/16" 20 1 is 10.0.16.0/20
+func cidrSubnet(prefix string, newSize interface{}, netNum interface{}) (string, error) {
+	size, err := toInt(newSize)
+	if err != nil {
+		return "", err
+	}
+	num, err := toInt(netNum)
+	if err != nil {
+		return "", err
+	}
+	return utils.CIDRSubnet(prefix, size, int64(num))
+}
+
+// cidrHost returns the hostNum'th address within prefix, e.g. CIDRHost "10.0.0.0/16" 10 is 10.0.0.10
+func cidrHost(prefix string, hostNum interface{}) (string, error) {
+	_, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse CIDR %q: %v", prefix, err)
+	}
+	num, err := toInt(hostNum)
+	if err != nil {
+		return "", err
+	}
+	ip, err := cidr.Host(network, num)
+	if err != nil {
+		return "", fmt.Errorf("unable to calculate host %d in %q: %v", num, prefix, err)
+	}
+	return ip.String(), nil
+}
+
+// selectZones returns the first count zones in sorted order, so the same values always select the same zones
+func selectZones(count interface{}, zones interface{}) ([]string, error) {
+	n, err := toInt(count)
+	if err != nil {
+		return nil, err
+	}
+
+	var sorted []string
+	switch v := zones.(type) {
+	case []string:
+		sorted = append(sorted, v...)
+	case []interface{}:
+		for _, zone := range v {
+			s, ok := zone.(string)
+			if !ok {
+				return nil, fmt.Errorf("zone %v is not a string", zone)
+			}
+			sorted = append(sorted, s)
+		}
+	default:
+		return nil, fmt.Errorf("zones must be a list of strings, got %T", zones)
+	}
+	sort.Strings(sorted)
+
+	if n < 0 || n > len(sorted) {
+		return nil, fmt.Errorf("unable to select %d zones from %v", n, sorted)
+	}
+	return sorted[:n], nil
+}
+
+// toInt converts the numeric types found in template values, which are float64 when they come from a values file
+func toInt(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int32:
+		return int(n), nil
+	case int64:
+		return int(n), nil
+	case float64:
+		if n != float64(int(n)) {
+			return 0, fmt.Errorf("%v is not a whole number", n)
+		}
+		return int(n), nil
+	default:
+		return 0, fmt.Errorf("%v is not a number", v)
+	}
+}
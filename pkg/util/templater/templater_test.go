@@ -19,6 +19,7 @@ package templater
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"k8s.io/kops/pkg/diff"
@@ -109,6 +110,88 @@ func TestRenderChannelFunctions(t *testing.T) {
 	makeRenderTests(t, cases)
 }
 
+func TestRenderNetworkFunctions(t *testing.T) {
+	cases := []renderTest{
+		{
+			Template: `{{ CIDRSubnet "10.0.0.0/16" 20 1 }}`,
+			Expected: "10.0.16.0/20",
+		},
+		{
+			Context:  map[string]interface{}{"cidr": "10.0.0.0/16", "size": float64(24), "index": float64(3)},
+			Template: `{{ CIDRSubnet .cidr .size .index }}`,
+			Expected: "10.0.3.0/24",
+		},
+		{
+			Template: `{{ CIDRSubnet "10.0.0.0/16" 8 1 }}`,
+			NotOK:    true,
+		},
+		{
+			Template: `{{ CIDRHost "10.0.16.0/20" 10 }}`,
+			Expected: "10.0.16.10",
+		},
+		{
+			Template: `{{ CIDRHost "10.0.16.0/30" 10 }}`,
+			NotOK:    true,
+		},
+		{
+			Context:  map[string]interface{}{"zones": []interface{}{"us-east-1c", "us-east-1a", "us-east-1b"}},
+			Template: `{{ SelectZones 2 .zones | join "," }}`,
+			Expected: "us-east-1a,us-east-1b",
+		},
+		{
+			Context:  map[string]interface{}{"zones": []string{"us-east-1a"}},
+			Template: `{{ SelectZones 2 .zones | join "," }}`,
+			NotOK:    true,
+		},
+	}
+	makeRenderTests(t, cases)
+}
+
+func TestValidateValues(t *testing.T) {
+	schema := `
+type: object
+required: [clusterName, nodeCount]
+properties:
+  clusterName:
+    type: string
+  nodeCount:
+    type: integer
+    minimum: 1
+`
+	grid := []struct {
+		Values      map[string]interface{}
+		ExpectError string
+	}{
+		{
+			Values: map[string]interface{}{"clusterName": "test.example.com", "nodeCount": float64(3)},
+		},
+		{
+			Values:      map[string]interface{}{"clusterName": "test.example.com", "nodeCount": "3"},
+			ExpectError: "nodeCount: Invalid type. Expected: integer, given: string",
+		},
+		{
+			Values:      map[string]interface{}{"nodeCount": 0},
+			ExpectError: "(root): clusterName is required\n  nodeCount: Must be greater than or equal to 1",
+		},
+	}
+	for _, g := range grid {
+		err := ValidateValues([]byte(schema), g.Values)
+		if g.ExpectError == "" {
+			if err != nil {
+				t.Errorf("unexpected error for %v: %v", g.Values, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("expected error for %v", g.Values)
+			continue
+		}
+		if !strings.Contains(err.Error(), g.ExpectError) {
+			t.Errorf("expected error containing %q, got %q", g.ExpectError, err.Error())
+		}
+	}
+}
+
 func TestRenderSnippet(t *testing.T) {
 	cases := []renderTest{
 		{
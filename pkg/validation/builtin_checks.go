@@ -0,0 +1,313 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	RegisterCheck(&componentHealthCheck{})
+	RegisterCheck(&addonReadinessCheck{})
+	RegisterCheck(&nodeConditionsCheck{})
+	RegisterCheck(&podDisruptionBudgetCheck{})
+	RegisterCheck(&rbacBindingsCheck{})
+	RegisterCheck(&certificateExpiryCheck{threshold: 30 * 24 * time.Hour, now: time.Now})
+}
+
+// componentHealthCheck reports control plane components that the API server considers unhealthy.
+type componentHealthCheck struct{}
+
+func (c *componentHealthCheck) Name() string {
+	return "component-health"
+}
+
+func (c *componentHealthCheck) Run(ctx context.Context, cc *CheckContext) ([]*ValidationError, error) {
+	components, err := cc.K8sClient.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing component statuses: %v", err)
+	}
+
+	var failures []*ValidationError
+	for _, component := range components.Items {
+		for _, condition := range component.Conditions {
+			if condition.Type != v1.ComponentHealthy || condition.Status == v1.ConditionTrue {
+				continue
+			}
+			message := fmt.Sprintf("component %q is unhealthy", component.Name)
+			if condition.Error != "" {
+				message += ": " + condition.Error
+			}
+			failures = append(failures, &ValidationError{
+				Kind:    "ComponentStatus",
+				Name:    component.Name,
+				Message: message,
+			})
+		}
+	}
+	return failures, nil
+}
+
+// addonLabel is set by kOps on the objects of the addons it manages.
+const addonLabel = "addon.kops.k8s.io/name"
+
+// addonReadinessCheck reports kOps managed addons whose Deployments or DaemonSets are not fully available.
+type addonReadinessCheck struct{}
+
+func (c *addonReadinessCheck) Name() string {
+	return "addon-readiness"
+}
+
+func (c *addonReadinessCheck) Run(ctx context.Context, cc *CheckContext) ([]*ValidationError, error) {
+	var failures []*ValidationError
+
+	deployments, err := cc.K8sClient.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: addonLabel})
+	if err != nil {
+		return nil, fmt.Errorf("error listing Deployments: %v", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		if deployment.Status.AvailableReplicas < desired {
+			failures = append(failures, addonFailure(&deployment.ObjectMeta, "Deployment", deployment.Status.AvailableReplicas, desired))
+		}
+	}
+
+	daemonSets, err := cc.K8sClient.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: addonLabel})
+	if err != nil {
+		return nil, fmt.Errorf("error listing DaemonSets: %v", err)
+	}
+	for i := range daemonSets.Items {
+		daemonSet := &daemonSets.Items[i]
+		if daemonSet.Status.NumberAvailable < daemonSet.Status.DesiredNumberScheduled {
+			failures = append(failures, addonFailure(&daemonSet.ObjectMeta, "DaemonSet", daemonSet.Status.NumberAvailable, daemonSet.Status.DesiredNumberScheduled))
+		}
+	}
+
+	return failures, nil
+}
+
+func addonFailure(meta *metav1.ObjectMeta, kind string, available, desired int32) *ValidationError {
+	return &ValidationError{
+		Kind:    kind,
+		Name:    meta.Namespace + "/" + meta.Name,
+		Message: fmt.Sprintf("addon %q %s %q has %d of %d replicas available", meta.Labels[addonLabel], kind, meta.Name, available, desired),
+	}
+}
+
+// nodeConditionsCheck reports ready nodes that have a pressure or network condition.
+type nodeConditionsCheck struct{}
+
+var problemNodeConditions = []v1.NodeConditionType{
+	v1.NodeMemoryPressure,
+	v1.NodeDiskPressure,
+	v1.NodePIDPressure,
+	v1.NodeNetworkUnavailable,
+}
+
+func (c *nodeConditionsCheck) Name() string {
+	return "node-conditions"
+}
+
+func (c *nodeConditionsCheck) Run(ctx context.Context, cc *CheckContext) ([]*ValidationError, error) {
+	var failures []*ValidationError
+	for _, node := range cc.Nodes {
+		for _, condition := range node.Status.Conditions {
+			if condition.Status != v1.ConditionTrue {
+				continue
+			}
+			for _, problem := range problemNodeConditions {
+				if condition.Type != problem {
+					continue
+				}
+				failures = append(failures, &ValidationError{
+					Kind:          "Node",
+					Name:          node.Name,
+					Message:       fmt.Sprintf("node %q has condition %s", node.Name, condition.Type),
+					InstanceGroup: cc.NodeInstanceGroups[node.Name],
+				})
+			}
+		}
+	}
+	return failures, nil
+}
+
+// podDisruptionBudgetCheck reports PodDisruptionBudgets that would block draining nodes,
+// either because they allow no disruptions or because they select no pods.
+type podDisruptionBudgetCheck struct{}
+
+func (c *podDisruptionBudgetCheck) Name() string {
+	return "pdb-coverage"
+}
+
+func (c *podDisruptionBudgetCheck) Run(ctx context.Context, cc *CheckContext) ([]*ValidationError, error) {
+	pdbs, err := cc.K8sClient.PolicyV1beta1().PodDisruptionBudgets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing PodDisruptionBudgets: %v", err)
+	}
+
+	var failures []*ValidationError
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		name := pdb.Namespace + "/" + pdb.Name
+		if pdb.Status.ExpectedPods == 0 {
+			matches, err := pdbSelectsPods(ctx, cc, pdb)
+			if err != nil {
+				return nil, err
+			}
+			if !matches {
+				failures = append(failures, &ValidationError{
+					Kind:    "PodDisruptionBudget",
+					Name:    name,
+					Message: fmt.Sprintf("PodDisruptionBudget %q does not select any pods", name),
+				})
+			}
+			continue
+		}
+		if pdb.Status.DisruptionsAllowed == 0 {
+			failures = append(failures, &ValidationError{
+				Kind:    "PodDisruptionBudget",
+				Name:    name,
+				Message: fmt.Sprintf("PodDisruptionBudget %q allows no disruptions; draining nodes running its %d pods will block", name, pdb.Status.ExpectedPods),
+			})
+		}
+	}
+	return failures, nil
+}
+
+func pdbSelectsPods(ctx context.Context, cc *CheckContext, pdb *policyv1beta1.PodDisruptionBudget) (bool, error) {
+	if pdb.Spec.Selector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid selector on PodDisruptionBudget %s/%s: %v", pdb.Namespace, pdb.Name, err)
+	}
+	pods, err := cc.K8sClient.CoreV1().Pods(pdb.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String(), Limit: 1})
+	if err != nil {
+		return false, fmt.Errorf("error listing pods for PodDisruptionBudget %s/%s: %v", pdb.Namespace, pdb.Name, err)
+	}
+	return len(pods.Items) != 0, nil
+}
+
+// rbacBindingsCheck reports the namespaces of the RBAC bindings of the cluster spec that do not exist,
+// as the rbac-bindings addon fails to create RoleBindings in them.
+type rbacBindingsCheck struct{}
+
+func (c *rbacBindingsCheck) Name() string {
+	return "rbac-bindings"
+}
+
+func (c *rbacBindingsCheck) Run(ctx context.Context, cc *CheckContext) ([]*ValidationError, error) {
+	if cc.Cluster == nil || cc.Cluster.Spec.Authorization == nil || cc.Cluster.Spec.Authorization.RBAC == nil {
+		return nil, nil
+	}
+
+	var failures []*ValidationError
+	for _, binding := range cc.Cluster.Spec.Authorization.RBAC.Bindings {
+		if binding.Namespace == "" {
+			continue
+		}
+		_, err := cc.K8sClient.CoreV1().Namespaces().Get(ctx, binding.Namespace, metav1.GetOptions{})
+		if err == nil {
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting namespace %q: %v", binding.Namespace, err)
+		}
+		failures = append(failures, &ValidationError{
+			Kind:    "Namespace",
+			Name:    binding.Namespace,
+			Message: fmt.Sprintf("namespace %q of RBAC binding %q does not exist", binding.Namespace, binding.Name),
+		})
+	}
+	return failures, nil
+}
+
+// certificateExpiryCheck reports API server certificates that expire within the threshold.
+type certificateExpiryCheck struct {
+	threshold time.Duration
+	now       func() time.Time
+}
+
+func (c *certificateExpiryCheck) Name() string {
+	return "certificate-expiry"
+}
+
+func (c *certificateExpiryCheck) Run(ctx context.Context, cc *CheckContext) ([]*ValidationError, error) {
+	if cc.Host == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(cc.Host)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Kubernetes cluster API URL: %v", err)
+	}
+	if u.Scheme != "https" {
+		return nil, nil
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 10 * time.Second},
+		// We only inspect the dates of the presented certificates; the client
+		// performing the validation has already verified the chain.
+		Config: &tls.Config{InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %v", address, err)
+	}
+	defer conn.Close()
+
+	var failures []*ValidationError
+	deadline := c.now().Add(c.threshold)
+	for _, cert := range conn.(*tls.Conn).ConnectionState().PeerCertificates {
+		if cert.NotAfter.After(deadline) {
+			continue
+		}
+		name := cert.Subject.CommonName
+		if name == "" && len(cert.DNSNames) != 0 {
+			name = cert.DNSNames[0]
+		}
+		message := fmt.Sprintf("certificate %q expires at %s", name, cert.NotAfter.UTC().Format(time.RFC3339))
+		if cert.NotAfter.Before(c.now()) {
+			message = fmt.Sprintf("certificate %q expired at %s", name, cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		failures = append(failures, &ValidationError{
+			Kind:    "Certificate",
+			Name:    name,
+			Message: message,
+		})
+	}
+	return failures, nil
+}
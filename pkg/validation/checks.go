@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// Check is an optional validation performed in addition to the node and pod
+// validation that is always done on a cluster.
+type Check interface {
+	// Name is the name used to select the check, e.g. "node-conditions".
+	Name() string
+	// Run performs the check, returning a ValidationError for every problem found.
+	// An error is returned only if the check itself could not be performed.
+	Run(ctx context.Context, c *CheckContext) ([]*ValidationError, error)
+}
+
+// CheckContext holds the state of the cluster made available to a Check.
+type CheckContext struct {
+	Cluster   *kops.Cluster
+	K8sClient kubernetes.Interface
+	// Host is the URL of the kubernetes API server.
+	Host string
+	// Nodes holds the nodes that were found to be ready.
+	Nodes []v1.Node
+	// NodeInstanceGroups maps a node name to the InstanceGroup it belongs to.
+	NodeInstanceGroups map[string]*kops.InstanceGroup
+}
+
+// Profile is a Check that validates the cluster against a set of controls, such as a security benchmark.
+// Every ValidationError reported by a Profile names the control that is not satisfied.
+type Profile interface {
+	Check
+	// Controls lists the controls of the profile, in the order they are reported.
+	Controls() []ProfileControl
+}
+
+// ProfileControl is a control of a Profile.
+type ProfileControl struct {
+	// ID identifies the control in the profile, e.g. "1.2.21".
+	ID string `json:"id"`
+	// Description is the recommendation of the control.
+	Description string `json:"description"`
+}
+
+// ProfileReport holds the result of validating the cluster against a Profile.
+type ProfileReport struct {
+	// Profile is the name of the profile.
+	Profile string `json:"profile"`
+	// Controls holds the result of every control of the profile.
+	Controls []ProfileControlResult `json:"controls,omitempty"`
+}
+
+// ProfileControlResult is the result of a control of a Profile.
+type ProfileControlResult struct {
+	ProfileControl `json:",inline"`
+	// Passed is true if no validation error was reported for the control.
+	Passed bool `json:"passed"`
+}
+
+var (
+	checksMutex sync.Mutex
+	checks      = map[string]Check{}
+	profiles    = map[string]Profile{}
+)
+
+// RegisterCheck makes a Check available for selection by name.
+func RegisterCheck(check Check) {
+	checksMutex.Lock()
+	defer checksMutex.Unlock()
+
+	name := check.Name()
+	if _, found := checks[name]; found {
+		panic(fmt.Sprintf("validation check %q registered twice", name))
+	}
+	checks[name] = check
+}
+
+// CheckNames returns the names of all registered checks, sorted.
+func CheckNames() []string {
+	checksMutex.Lock()
+	defer checksMutex.Unlock()
+
+	var names []string
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterProfile makes a Profile available for selection by name.
+// Profiles are not selected by the "all" check name.
+func RegisterProfile(profile Profile) {
+	checksMutex.Lock()
+	defer checksMutex.Unlock()
+
+	name := profile.Name()
+	if _, found := profiles[name]; found {
+		panic(fmt.Sprintf("validation profile %q registered twice", name))
+	}
+	profiles[name] = profile
+}
+
+// ProfileNames returns the names of all registered profiles, sorted.
+func ProfileNames() []string {
+	checksMutex.Lock()
+	defer checksMutex.Unlock()
+
+	var names []string
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FindProfile returns the registered profile with the given name.
+func FindProfile(name string) (Profile, error) {
+	checksMutex.Lock()
+	defer checksMutex.Unlock()
+
+	profile := profiles[name]
+	if profile == nil {
+		return nil, fmt.Errorf("unknown validation profile %q", name)
+	}
+	return profile, nil
+}
+
+// FindChecks returns the registered checks with the given names.
+// The special name "all" selects every registered check; names in skip are then removed.
+func FindChecks(names []string, skip []string) ([]Check, error) {
+	selected := map[string]bool{}
+	for _, name := range names {
+		if name == "all" {
+			for _, name := range CheckNames() {
+				selected[name] = true
+			}
+			continue
+		}
+		selected[name] = true
+	}
+	for _, name := range skip {
+		delete(selected, name)
+	}
+
+	checksMutex.Lock()
+	defer checksMutex.Unlock()
+
+	var found []Check
+	var unknown []string
+	for name := range selected {
+		check := checks[name]
+		if check == nil {
+			unknown = append(unknown, name)
+			continue
+		}
+		found = append(found, check)
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown validation checks: %s", strings.Join(unknown, ", "))
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Name() < found[j].Name()
+	})
+	return found, nil
+}
+
+// runChecks runs each check, recording its name and any failures in the result.
+func (v *ValidationCluster) runChecks(ctx context.Context, checks []Check, c *CheckContext) error {
+	for _, check := range checks {
+		failures, err := check.Run(ctx, c)
+		if err != nil {
+			return fmt.Errorf("error running %q check: %v", check.Name(), err)
+		}
+		v.Checks = append(v.Checks, check.Name())
+		for _, failure := range failures {
+			failure.Check = check.Name()
+			v.addError(failure)
+		}
+		if profile, ok := check.(Profile); ok {
+			v.Profile = buildProfileReport(profile, failures)
+		}
+	}
+	return nil
+}
+
+// buildProfileReport records which controls of the profile have no validation errors.
+func buildProfileReport(profile Profile, failures []*ValidationError) *ProfileReport {
+	failed := map[string]bool{}
+	for _, failure := range failures {
+		failed[failure.Control] = true
+	}
+
+	report := &ProfileReport{Profile: profile.Name()}
+	for _, control := range profile.Controls() {
+		report.Controls = append(report.Controls, ProfileControlResult{
+			ProfileControl: control,
+			Passed:         !failed[control.ID],
+		})
+	}
+	return report
+}
@@ -0,0 +1,465 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func findCheck(t *testing.T, name string) Check {
+	checks, err := FindChecks([]string{name}, nil)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	return checks[0]
+}
+
+func TestFindChecks(t *testing.T) {
+	grid := []struct {
+		names    []string
+		skip     []string
+		expected []string
+		err      string
+	}{
+		{},
+		{
+			names:    []string{"node-conditions", "addon-readiness"},
+			expected: []string{"addon-readiness", "node-conditions"},
+		},
+		{
+			names:    []string{"all"},
+			skip:     []string{"certificate-expiry", "component-health"},
+			expected: []string{"addon-readiness", "node-conditions", "pdb-coverage", "rbac-bindings"},
+		},
+		{
+			names: []string{"node-conditions", "no-such-check", "another"},
+			err:   "unknown validation checks: another, no-such-check",
+		},
+	}
+	for _, g := range grid {
+		checks, err := FindChecks(g.names, g.skip)
+		if g.err != "" {
+			assert.EqualError(t, err, g.err)
+			continue
+		}
+		require.NoError(t, err)
+		var names []string
+		for _, check := range checks {
+			names = append(names, check.Name())
+		}
+		assert.Equal(t, g.expected, names)
+	}
+}
+
+func Test_ValidateNodeConditionsCheck(t *testing.T) {
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	groups["node-1"] = &cloudinstances.CloudInstanceGroup{
+		InstanceGroup: &kopsapi.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-1",
+			},
+			Spec: kopsapi.InstanceGroupSpec{
+				Role: kopsapi.InstanceGroupRoleNode,
+			},
+		},
+		MinSize:    2,
+		TargetSize: 2,
+		Ready: []*cloudinstances.CloudInstance{
+			{
+				ID: "i-00001",
+				Node: &v1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-1a"},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{Type: "Ready", Status: v1.ConditionTrue},
+							{Type: v1.NodeDiskPressure, Status: v1.ConditionTrue},
+							{Type: v1.NodeMemoryPressure, Status: v1.ConditionFalse},
+						},
+					},
+				},
+			},
+			{
+				ID: "i-00002",
+				Node: &v1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-1b"},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{Type: "Ready", Status: v1.ConditionTrue},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	v, err := testValidateWithChecks(t, groups, nil, findCheck(t, "node-conditions"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-conditions"}, v.Checks)
+	if !assert.Len(t, v.Failures, 1) ||
+		!assert.Equal(t, &ValidationError{
+			Kind:          "Node",
+			Name:          "node-1a",
+			Message:       "node \"node-1a\" has condition DiskPressure",
+			InstanceGroup: groups["node-1"].InstanceGroup,
+			Check:         "node-conditions",
+		}, v.Failures[0]) {
+		printDebug(t, v)
+	}
+}
+
+func Test_ValidateAddonReadinessCheck(t *testing.T) {
+	objects := []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "kube-system",
+				Name:      "coredns",
+				Labels:    map[string]string{addonLabel: "coredns.addons.k8s.io"},
+			},
+			Spec:   appsv1.DeploymentSpec{Replicas: fi.Int32(2)},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "kube-system",
+				Name:      "dns-controller",
+				Labels:    map[string]string{addonLabel: "dns-controller.addons.k8s.io"},
+			},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "unmanaged",
+			},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "kube-system",
+				Name:      "kube-proxy",
+				Labels:    map[string]string{addonLabel: "kube-proxy.addons.k8s.io"},
+			},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberAvailable: 2},
+		},
+	}
+
+	failures, err := findCheck(t, "addon-readiness").Run(context.TODO(), &CheckContext{K8sClient: fake.NewSimpleClientset(objects...)})
+	require.NoError(t, err)
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "Deployment",
+			Name:    "kube-system/coredns",
+			Message: "addon \"coredns.addons.k8s.io\" Deployment \"coredns\" has 1 of 2 replicas available",
+		},
+		{
+			Kind:    "DaemonSet",
+			Name:    "kube-system/kube-proxy",
+			Message: "addon \"kube-proxy.addons.k8s.io\" DaemonSet \"kube-proxy\" has 2 of 3 replicas available",
+		},
+	}, failures)
+}
+
+func Test_ValidatePodDisruptionBudgetCheck(t *testing.T) {
+	objects := []runtime.Object{
+		&policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "blocking"},
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "blocking"}},
+			},
+			Status: policyv1beta1.PodDisruptionBudgetStatus{ExpectedPods: 2, DisruptionsAllowed: 0},
+		},
+		&policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "healthy"},
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "healthy"}},
+			},
+			Status: policyv1beta1.PodDisruptionBudgetStatus{ExpectedPods: 2, DisruptionsAllowed: 1},
+		},
+		&policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "orphaned"},
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gone"}},
+			},
+		},
+	}
+
+	failures, err := findCheck(t, "pdb-coverage").Run(context.TODO(), &CheckContext{K8sClient: fake.NewSimpleClientset(objects...)})
+	require.NoError(t, err)
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "PodDisruptionBudget",
+			Name:    "default/blocking",
+			Message: "PodDisruptionBudget \"default/blocking\" allows no disruptions; draining nodes running its 2 pods will block",
+		},
+		{
+			Kind:    "PodDisruptionBudget",
+			Name:    "default/orphaned",
+			Message: "PodDisruptionBudget \"default/orphaned\" does not select any pods",
+		},
+	}, failures)
+}
+
+func Test_ValidateComponentHealthCheck(t *testing.T) {
+	objects := []runtime.Object{
+		&v1.ComponentStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: "scheduler"},
+			Conditions: []v1.ComponentCondition{
+				{Type: v1.ComponentHealthy, Status: v1.ConditionTrue},
+			},
+		},
+		&v1.ComponentStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcd-0"},
+			Conditions: []v1.ComponentCondition{
+				{Type: v1.ComponentHealthy, Status: v1.ConditionFalse, Error: "connection refused"},
+			},
+		},
+	}
+
+	failures, err := findCheck(t, "component-health").Run(context.TODO(), &CheckContext{K8sClient: fake.NewSimpleClientset(objects...)})
+	require.NoError(t, err)
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "ComponentStatus",
+			Name:    "etcd-0",
+			Message: "component \"etcd-0\" is unhealthy: connection refused",
+		},
+	}, failures)
+}
+
+func Test_ValidateRBACBindingsCheck(t *testing.T) {
+	cluster := &kopsapi.Cluster{
+		Spec: kopsapi.ClusterSpec{
+			Authorization: &kopsapi.AuthorizationSpec{
+				RBAC: &kopsapi.RBACAuthorizationSpec{
+					Bindings: []kopsapi.RBACBindingSpec{
+						{Name: "operators", ClusterRole: "cluster-admin", Groups: []string{"ops"}},
+						{Name: "developers", ClusterRole: "edit", Namespace: "apps", Groups: []string{"dev"}},
+						{Name: "testers", ClusterRole: "view", Namespace: "qa", Groups: []string{"qa"}},
+					},
+				},
+			},
+		},
+	}
+	objects := []runtime.Object{
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+	}
+
+	failures, err := findCheck(t, "rbac-bindings").Run(context.TODO(), &CheckContext{Cluster: cluster, K8sClient: fake.NewSimpleClientset(objects...)})
+	require.NoError(t, err)
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "Namespace",
+			Name:    "qa",
+			Message: "namespace \"qa\" of RBAC binding \"testers\" does not exist",
+		},
+	}, failures)
+}
+
+func Test_ValidateCertificateExpiryCheck(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	notAfter := server.Certificate().NotAfter
+
+	grid := []struct {
+		now      time.Time
+		expected string
+	}{
+		{
+			now: notAfter.Add(-60 * 24 * time.Hour),
+		},
+		{
+			now:      notAfter.Add(-24 * time.Hour),
+			expected: "certificate \"example.com\" expires at " + notAfter.UTC().Format(time.RFC3339),
+		},
+		{
+			now:      notAfter.Add(time.Hour),
+			expected: "certificate \"example.com\" expired at " + notAfter.UTC().Format(time.RFC3339),
+		},
+	}
+	for _, g := range grid {
+		check := &certificateExpiryCheck{threshold: 30 * 24 * time.Hour, now: func() time.Time { return g.now }}
+		failures, err := check.Run(context.TODO(), &CheckContext{Host: server.URL})
+		require.NoError(t, err)
+		if g.expected == "" {
+			assert.Empty(t, failures)
+			continue
+		}
+		if assert.Len(t, failures, 1) {
+			assert.Equal(t, g.expected, failures[0].Message)
+			assert.Equal(t, "Certificate", failures[0].Kind)
+		}
+	}
+}
+
+func Test_ValidateCISProfile(t *testing.T) {
+	apiserverArgs := []string{
+		"--anonymous-auth=false",
+		"--authorization-mode=Node,RBAC",
+		"--enable-admission-plugins=NamespaceLifecycle,NodeRestriction",
+		"--audit-log-path=/var/log/kube-apiserver/audit.log",
+		"--audit-log-maxage=30",
+		"--audit-log-maxbackup=10",
+		"--audit-log-maxsize=100",
+		"--audit-policy-file=/srv/kubernetes/kube-apiserver/audit-policy.yaml",
+		"--tls-cipher-suites=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	}
+	objects := []runtime.Object{
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "kube-system",
+				Name:      "kube-apiserver-master-1",
+				Labels:    map[string]string{"k8s-app": "kube-apiserver"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Name:    "kube-apiserver",
+						Command: []string{"/usr/local/bin/kube-apiserver"},
+						Args:    apiserverArgs,
+					},
+					{
+						Name: "healthcheck",
+						Args: []string{"--profiling=false"},
+					},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "kube-system",
+				Name:      "kube-controller-manager-master-1",
+				Labels:    map[string]string{"k8s-app": "kube-controller-manager"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Name:    "kube-controller-manager",
+						Command: []string{"/bin/sh", "-c", "exec /usr/local/bin/kube-controller-manager --profiling=false --terminated-pod-gc-threshold=12500 --use-service-account-credentials=true --service-account-private-key-file=/srv/kubernetes/service-account.key 2>&1 | /bin/tee -a /var/log/kube-controller-manager.log"},
+					},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "kube-system",
+				Name:      "kube-scheduler-master-1",
+				Labels:    map[string]string{"k8s-app": "kube-scheduler"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Name: "kube-scheduler",
+						Args: []string{"--profiling=false"},
+					},
+				},
+			},
+		},
+	}
+
+	kubeletConfigs := map[string]string{
+		"node-1": `{"authentication":{"anonymous":{"enabled":false},"x509":{"clientCAFile":"/srv/kubernetes/ca.crt"}},"authorization":{"mode":"Webhook"},"streamingConnectionIdleTimeout":"4h0m0s","protectKernelDefaults":true,"makeIPTablesUtilChains":true,"tlsCipherSuites":["TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"]}`,
+		"node-2": `{"authentication":{"anonymous":{"enabled":true},"x509":{"clientCAFile":"/srv/kubernetes/ca.crt"}},"authorization":{"mode":"AlwaysAllow"},"readOnlyPort":10255,"streamingConnectionIdleTimeout":"4h0m0s","makeIPTablesUtilChains":true}`,
+	}
+	ig := &kopsapi.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}}
+	profile := &cisProfile{
+		kubeletConfig: func(ctx context.Context, cc *CheckContext, node string) (*cisKubeletConfig, error) {
+			config := &cisKubeletConfig{}
+			err := json.Unmarshal([]byte(kubeletConfigs[node]), config)
+			return config, err
+		},
+	}
+
+	failures, err := profile.Run(context.TODO(), &CheckContext{
+		K8sClient: fake.NewSimpleClientset(objects...),
+		Nodes: []v1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+		},
+		NodeInstanceGroups: map[string]*kopsapi.InstanceGroup{"node-1": ig, "node-2": ig},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "Pod",
+			Name:    "kube-system/kube-apiserver-master-1",
+			Message: "kube-apiserver does not satisfy CIS 1.2.21: Ensure that the --profiling argument is set to false",
+			Control: "1.2.21",
+		},
+		{
+			Kind:          "Node",
+			Name:          "node-2",
+			Message:       "kubelet does not satisfy CIS 4.2.1: Ensure that the anonymous-auth argument is set to false",
+			InstanceGroup: ig,
+			Control:       "4.2.1",
+		},
+		{
+			Kind:          "Node",
+			Name:          "node-2",
+			Message:       "kubelet does not satisfy CIS 4.2.2: Ensure that the --authorization-mode argument is not set to AlwaysAllow",
+			InstanceGroup: ig,
+			Control:       "4.2.2",
+		},
+		{
+			Kind:          "Node",
+			Name:          "node-2",
+			Message:       "kubelet does not satisfy CIS 4.2.4: Ensure that the --read-only-port argument is set to 0",
+			InstanceGroup: ig,
+			Control:       "4.2.4",
+		},
+		{
+			Kind:          "Node",
+			Name:          "node-2",
+			Message:       "kubelet does not satisfy CIS 4.2.6: Ensure that the --protect-kernel-defaults argument is set to true",
+			InstanceGroup: ig,
+			Control:       "4.2.6",
+		},
+		{
+			Kind:          "Node",
+			Name:          "node-2",
+			Message:       "kubelet does not satisfy CIS 4.2.13: Ensure that the Kubelet only makes use of Strong Cryptographic Ciphers",
+			InstanceGroup: ig,
+			Control:       "4.2.13",
+		},
+	}, failures)
+
+	report := buildProfileReport(profile, failures)
+	assert.Equal(t, "cis", report.Profile)
+	assert.Len(t, report.Controls, len(cisFlagControls)+len(cisKubeletControls))
+	for _, control := range report.Controls {
+		switch control.ID {
+		case "1.2.21", "4.2.1", "4.2.2", "4.2.4", "4.2.6", "4.2.13":
+			assert.False(t, control.Passed, control.ID)
+		default:
+			assert.True(t, control.Passed, control.ID)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	RegisterProfile(&cisProfile{kubeletConfig: kubeletConfigz})
+}
+
+// cisFlagControl is a control of the CIS Kubernetes Benchmark on a flag of a control plane component.
+type cisFlagControl struct {
+	ProfileControl
+	// component is the k8s-app label of the static pods of the component.
+	component string
+	flag      string
+	// satisfied returns true if the value of the flag satisfies the control.
+	// set is false if the flag is not passed to the component.
+	satisfied func(value string, set bool) bool
+}
+
+// cisKubeletControl is a control of the CIS Kubernetes Benchmark on the configuration of the kubelet.
+type cisKubeletControl struct {
+	ProfileControl
+	satisfied func(config *cisKubeletConfig) bool
+}
+
+// cisKubeletConfig holds the fields of the kubelet configuration checked by the CIS Kubernetes Benchmark,
+// as returned by the configz endpoint of the kubelet.
+type cisKubeletConfig struct {
+	Authentication struct {
+		Anonymous struct {
+			Enabled *bool `json:"enabled"`
+		} `json:"anonymous"`
+		X509 struct {
+			ClientCAFile string `json:"clientCAFile"`
+		} `json:"x509"`
+	} `json:"authentication"`
+	Authorization struct {
+		Mode string `json:"mode"`
+	} `json:"authorization"`
+	ReadOnlyPort                   int32    `json:"readOnlyPort"`
+	StreamingConnectionIdleTimeout string   `json:"streamingConnectionIdleTimeout"`
+	ProtectKernelDefaults          bool     `json:"protectKernelDefaults"`
+	MakeIPTablesUtilChains         *bool    `json:"makeIPTablesUtilChains"`
+	TLSCipherSuites                []string `json:"tlsCipherSuites"`
+}
+
+var cisFlagControls = []cisFlagControl{
+	{
+		ProfileControl: ProfileControl{ID: "1.2.1", Description: "Ensure that the --anonymous-auth argument is set to false"},
+		component:      "kube-apiserver",
+		flag:           "anonymous-auth",
+		satisfied:      cisFlagEquals("false"),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.7", Description: "Ensure that the --authorization-mode argument is not set to AlwaysAllow"},
+		component:      "kube-apiserver",
+		flag:           "authorization-mode",
+		satisfied:      cisFlagExcludes("AlwaysAllow"),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.8", Description: "Ensure that the --authorization-mode argument includes Node"},
+		component:      "kube-apiserver",
+		flag:           "authorization-mode",
+		satisfied:      cisFlagIncludes("Node"),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.9", Description: "Ensure that the --authorization-mode argument includes RBAC"},
+		component:      "kube-apiserver",
+		flag:           "authorization-mode",
+		satisfied:      cisFlagIncludes("RBAC"),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.17", Description: "Ensure that the admission control plugin NodeRestriction is set"},
+		component:      "kube-apiserver",
+		flag:           "enable-admission-plugins",
+		satisfied:      cisFlagIncludes("NodeRestriction"),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.21", Description: "Ensure that the --profiling argument is set to false"},
+		component:      "kube-apiserver",
+		flag:           "profiling",
+		satisfied:      cisFlagEquals("false"),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.22", Description: "Ensure that the --audit-log-path argument is set"},
+		component:      "kube-apiserver",
+		flag:           "audit-log-path",
+		satisfied:      cisFlagSet,
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.23", Description: "Ensure that the --audit-log-maxage argument is set to 30 or as appropriate"},
+		component:      "kube-apiserver",
+		flag:           "audit-log-maxage",
+		satisfied:      cisFlagAtLeast(30),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.24", Description: "Ensure that the --audit-log-maxbackup argument is set to 10 or as appropriate"},
+		component:      "kube-apiserver",
+		flag:           "audit-log-maxbackup",
+		satisfied:      cisFlagAtLeast(10),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.25", Description: "Ensure that the --audit-log-maxsize argument is set to 100 or as appropriate"},
+		component:      "kube-apiserver",
+		flag:           "audit-log-maxsize",
+		satisfied:      cisFlagAtLeast(100),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.2.35", Description: "Ensure that the API Server only makes use of Strong Cryptographic Ciphers"},
+		component:      "kube-apiserver",
+		flag:           "tls-cipher-suites",
+		satisfied:      cisFlagStrongCiphers,
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.3.1", Description: "Ensure that the --terminated-pod-gc-threshold argument is set as appropriate"},
+		component:      "kube-controller-manager",
+		flag:           "terminated-pod-gc-threshold",
+		satisfied:      cisFlagSet,
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.3.2", Description: "Ensure that the --profiling argument is set to false"},
+		component:      "kube-controller-manager",
+		flag:           "profiling",
+		satisfied:      cisFlagEquals("false"),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.3.3", Description: "Ensure that the --use-service-account-credentials argument is set to true"},
+		component:      "kube-controller-manager",
+		flag:           "use-service-account-credentials",
+		satisfied:      cisFlagEquals("true"),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.3.4", Description: "Ensure that the --service-account-private-key-file argument is set as appropriate"},
+		component:      "kube-controller-manager",
+		flag:           "service-account-private-key-file",
+		satisfied:      cisFlagSet,
+	},
+	{
+		ProfileControl: ProfileControl{ID: "1.4.1", Description: "Ensure that the --profiling argument is set to false"},
+		component:      "kube-scheduler",
+		flag:           "profiling",
+		satisfied:      cisFlagEquals("false"),
+	},
+	{
+		ProfileControl: ProfileControl{ID: "3.2.1", Description: "Ensure that a minimal audit policy is created"},
+		component:      "kube-apiserver",
+		flag:           "audit-policy-file",
+		satisfied:      cisFlagSet,
+	},
+}
+
+var cisKubeletControls = []cisKubeletControl{
+	{
+		ProfileControl: ProfileControl{ID: "4.2.1", Description: "Ensure that the anonymous-auth argument is set to false"},
+		satisfied: func(config *cisKubeletConfig) bool {
+			return config.Authentication.Anonymous.Enabled != nil && !*config.Authentication.Anonymous.Enabled
+		},
+	},
+	{
+		ProfileControl: ProfileControl{ID: "4.2.2", Description: "Ensure that the --authorization-mode argument is not set to AlwaysAllow"},
+		satisfied: func(config *cisKubeletConfig) bool {
+			return config.Authorization.Mode != "" && config.Authorization.Mode != "AlwaysAllow"
+		},
+	},
+	{
+		ProfileControl: ProfileControl{ID: "4.2.3", Description: "Ensure that the --client-ca-file argument is set as appropriate"},
+		satisfied: func(config *cisKubeletConfig) bool {
+			return config.Authentication.X509.ClientCAFile != ""
+		},
+	},
+	{
+		ProfileControl: ProfileControl{ID: "4.2.4", Description: "Ensure that the --read-only-port argument is set to 0"},
+		satisfied: func(config *cisKubeletConfig) bool {
+			return config.ReadOnlyPort == 0
+		},
+	},
+	{
+		ProfileControl: ProfileControl{ID: "4.2.5", Description: "Ensure that the --streaming-connection-idle-timeout argument is not set to 0"},
+		satisfied: func(config *cisKubeletConfig) bool {
+			return config.StreamingConnectionIdleTimeout != "0s" && config.StreamingConnectionIdleTimeout != "0"
+		},
+	},
+	{
+		ProfileControl: ProfileControl{ID: "4.2.6", Description: "Ensure that the --protect-kernel-defaults argument is set to true"},
+		satisfied: func(config *cisKubeletConfig) bool {
+			return config.ProtectKernelDefaults
+		},
+	},
+	{
+		ProfileControl: ProfileControl{ID: "4.2.7", Description: "Ensure that the --make-iptables-util-chains argument is set to true"},
+		satisfied: func(config *cisKubeletConfig) bool {
+			return config.MakeIPTablesUtilChains == nil || *config.MakeIPTablesUtilChains
+		},
+	},
+	{
+		ProfileControl: ProfileControl{ID: "4.2.13", Description: "Ensure that the Kubelet only makes use of Strong Cryptographic Ciphers"},
+		satisfied: func(config *cisKubeletConfig) bool {
+			return cisFlagStrongCiphers(strings.Join(config.TLSCipherSuites, ","), len(config.TLSCipherSuites) != 0)
+		},
+	},
+}
+
+// cisStrongCiphers are the TLS cipher suites considered strong by the CIS Kubernetes Benchmark.
+var cisStrongCiphers = map[string]bool{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256":       true,
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":         true,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305":        true,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256": true,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":         true,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305":          true,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256":   true,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384":       true,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":               true,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":               true,
+}
+
+func cisFlagSet(value string, set bool) bool {
+	return set && value != ""
+}
+
+func cisFlagEquals(expected string) func(string, bool) bool {
+	return func(value string, set bool) bool {
+		return set && value == expected
+	}
+}
+
+func cisFlagIncludes(item string) func(string, bool) bool {
+	return func(value string, set bool) bool {
+		return set && containsString(strings.Split(value, ","), item)
+	}
+}
+
+func cisFlagExcludes(item string) func(string, bool) bool {
+	return func(value string, set bool) bool {
+		return set && !containsString(strings.Split(value, ","), item)
+	}
+}
+
+func cisFlagAtLeast(min int) func(string, bool) bool {
+	return func(value string, set bool) bool {
+		n, err := strconv.Atoi(value)
+		return set && err == nil && n >= min
+	}
+}
+
+func cisFlagStrongCiphers(value string, set bool) bool {
+	if !set || value == "" {
+		return false
+	}
+	for _, cipher := range strings.Split(value, ",") {
+		if !cisStrongCiphers[cipher] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// cisProfile validates the flags of the control plane components and the configuration of the kubelets
+// against the CIS Kubernetes Benchmark.
+type cisProfile struct {
+	// kubeletConfig returns the configuration of the kubelet of a node.
+	kubeletConfig func(ctx context.Context, cc *CheckContext, node string) (*cisKubeletConfig, error)
+}
+
+var _ Profile = &cisProfile{}
+
+func (c *cisProfile) Name() string {
+	return "cis"
+}
+
+func (c *cisProfile) Controls() []ProfileControl {
+	var controls []ProfileControl
+	for _, control := range cisFlagControls {
+		controls = append(controls, control.ProfileControl)
+	}
+	for _, control := range cisKubeletControls {
+		controls = append(controls, control.ProfileControl)
+	}
+	return controls
+}
+
+func (c *cisProfile) Run(ctx context.Context, cc *CheckContext) ([]*ValidationError, error) {
+	var failures []*ValidationError
+
+	pods := map[string][]v1.Pod{}
+	for _, control := range cisFlagControls {
+		if _, found := pods[control.component]; !found {
+			list, err := cc.K8sClient.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: "k8s-app=" + control.component})
+			if err != nil {
+				return nil, fmt.Errorf("error listing %s pods: %v", control.component, err)
+			}
+			pods[control.component] = list.Items
+		}
+		for i := range pods[control.component] {
+			pod := &pods[control.component][i]
+			value, set := podFlags(pod, control.component)[control.flag]
+			if control.satisfied(value, set) {
+				continue
+			}
+			failures = append(failures, &ValidationError{
+				Kind:    "Pod",
+				Name:    pod.Namespace + "/" + pod.Name,
+				Message: fmt.Sprintf("%s does not satisfy CIS %s: %s", control.component, control.ID, control.Description),
+				Control: control.ID,
+			})
+		}
+	}
+
+	for _, node := range cc.Nodes {
+		config, err := c.kubeletConfig(ctx, cc, node.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, control := range cisKubeletControls {
+			if control.satisfied(config) {
+				continue
+			}
+			failures = append(failures, &ValidationError{
+				Kind:          "Node",
+				Name:          node.Name,
+				Message:       fmt.Sprintf("kubelet does not satisfy CIS %s: %s", control.ID, control.Description),
+				InstanceGroup: cc.NodeInstanceGroups[node.Name],
+				Control:       control.ID,
+			})
+		}
+	}
+
+	return failures, nil
+}
+
+// podFlags returns the flags passed to the container of the component, without their leading dashes.
+// The flags of commands run through a shell are found by splitting the command on whitespace.
+func podFlags(pod *v1.Pod, component string) map[string]string {
+	flags := map[string]string{}
+	for _, container := range pod.Spec.Containers {
+		if container.Name != component {
+			continue
+		}
+		for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+			for _, field := range strings.Fields(arg) {
+				if !strings.HasPrefix(field, "--") {
+					continue
+				}
+				kv := strings.SplitN(strings.TrimPrefix(field, "--"), "=", 2)
+				if len(kv) == 1 {
+					flags[kv[0]] = "true"
+				} else {
+					flags[kv[0]] = kv[1]
+				}
+			}
+		}
+	}
+	return flags
+}
+
+// kubeletConfigz reads the configuration of the kubelet of a node through the node proxy of the API server.
+func kubeletConfigz(ctx context.Context, cc *CheckContext, node string) (*cisKubeletConfig, error) {
+	b, err := cc.K8sClient.CoreV1().RESTClient().Get().Resource("nodes").Name(node).SubResource("proxy").Suffix("configz").DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the kubelet configuration of node %q: %v", node, err)
+	}
+	var configz struct {
+		KubeletConfig cisKubeletConfig `json:"kubeletconfig"`
+	}
+	if err := json.Unmarshal(b, &configz); err != nil {
+		return nil, fmt.Errorf("error parsing the kubelet configuration of node %q: %v", node, err)
+	}
+	return &configz.KubeletConfig, nil
+}
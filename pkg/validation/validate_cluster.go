@@ -41,6 +41,12 @@ type ValidationCluster struct {
 	Failures []*ValidationError `json:"failures,omitempty"`
 
 	Nodes []*ValidationNode `json:"nodes,omitempty"`
+
+	// Checks lists the names of the additional checks that were run.
+	Checks []string `json:"checks,omitempty"`
+
+	// Profile is the report of the validation against a profile, if one was selected.
+	Profile *ProfileReport `json:"profile,omitempty"`
 }
 
 // ValidationError holds a validation failure
@@ -50,6 +56,10 @@ type ValidationError struct {
 	Message string `json:"message,omitempty"`
 	// The InstanceGroup field is used to indicate which instance group this validation error is coming from
 	InstanceGroup *kops.InstanceGroup `json:"instanceGroup,omitempty"`
+	// The Check field is set to the name of the additional check that reported this validation error
+	Check string `json:"check,omitempty"`
+	// The Control field is set to the ID of the profile control that is not satisfied
+	Control string `json:"control,omitempty"`
 }
 
 type ClusterValidator interface {
@@ -63,6 +73,7 @@ type clusterValidatorImpl struct {
 	instanceGroups []*kops.InstanceGroup
 	host           string
 	k8sClient      kubernetes.Interface
+	checks         []Check
 }
 
 func (v *ValidationCluster) addError(failure *ValidationError) {
@@ -98,7 +109,8 @@ func hasPlaceHolderIP(host string) (bool, error) {
 	return false, nil
 }
 
-func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupList *kops.InstanceGroupList, host string, k8sClient kubernetes.Interface) (ClusterValidator, error) {
+// NewClusterValidator builds a ClusterValidator, which runs the given checks in addition to validating nodes and pods.
+func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupList *kops.InstanceGroupList, host string, k8sClient kubernetes.Interface, checks ...Check) (ClusterValidator, error) {
 	var instanceGroups []*kops.InstanceGroup
 
 	for i := range instanceGroupList.Items {
@@ -116,6 +128,7 @@ func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupLis
 		instanceGroups: instanceGroups,
 		host:           host,
 		k8sClient:      k8sClient,
+		checks:         checks,
 	}, nil
 }
 
@@ -160,11 +173,23 @@ func (v *clusterValidatorImpl) Validate() (*ValidationCluster, error) {
 		return nil, err
 	}
 	readyNodes, nodeInstanceGroupMapping := validation.validateNodes(cloudGroups, v.instanceGroups)
+	readyNodes = append(readyNodes, validation.validateHybridNodes(nodeList.Items, v.instanceGroups, nodeInstanceGroupMapping)...)
 
 	if err := validation.collectPodFailures(ctx, v.k8sClient, readyNodes, nodeInstanceGroupMapping); err != nil {
 		return nil, fmt.Errorf("cannot get pod health for %q: %v", clusterName, err)
 	}
 
+	checkContext := &CheckContext{
+		Cluster:            v.cluster,
+		K8sClient:          v.k8sClient,
+		Host:               v.host,
+		Nodes:              readyNodes,
+		NodeInstanceGroups: nodeInstanceGroupMapping,
+	}
+	if err := validation.runChecks(ctx, v.checks, checkContext); err != nil {
+		return nil, fmt.Errorf("cannot validate %q: %v", clusterName, err)
+	}
+
 	return validation, nil
 }
 
@@ -344,7 +369,7 @@ func (v *ValidationCluster) validateNodes(cloudGroups map[string]*cloudinstances
 				n.Zone = node.ObjectMeta.Labels["failure-domain.beta.kubernetes.io/zone"]
 			}
 
-			ready := isNodeReady(node)
+			ready := IsNodeReady(node)
 			if ready {
 				readyNodes = append(readyNodes, *node)
 			}
@@ -369,7 +394,8 @@ func (v *ValidationCluster) validateNodes(cloudGroups map[string]*cloudinstances
 	}
 
 	for _, ig := range groups {
-		if !groupsSeen[ig.Name] {
+		// The machines of hybrid instance groups are not in the cloud provider.
+		if !groupsSeen[ig.Name] && !ig.IsHybrid() {
 			v.addError(&ValidationError{
 				Kind:          "InstanceGroup",
 				Name:          ig.Name,
@@ -381,3 +407,49 @@ func (v *ValidationCluster) validateNodes(cloudGroups map[string]*cloudinstances
 
 	return readyNodes, nodeInstanceGroupMapping
 }
+
+// validateHybridNodes validates the nodes of hybrid instance groups, which kops-controller labels with
+// their instance group as they are not known to the cloud provider.
+func (v *ValidationCluster) validateHybridNodes(nodes []v1.Node, groups []*kops.InstanceGroup, nodeInstanceGroupMapping map[string]*kops.InstanceGroup) []v1.Node {
+	hybridGroups := map[string]*kops.InstanceGroup{}
+	for _, ig := range groups {
+		if ig.IsHybrid() {
+			hybridGroups[ig.Name] = ig
+		}
+	}
+	if len(hybridGroups) == 0 {
+		return nil
+	}
+
+	var readyNodes []v1.Node
+	for i := range nodes {
+		node := &nodes[i]
+		ig := hybridGroups[node.Labels[kops.NodeLabelInstanceGroup]]
+		if ig == nil || node.Spec.ProviderID != "" {
+			continue
+		}
+
+		nodeInstanceGroupMapping[node.Name] = ig
+
+		n := &ValidationNode{
+			Name:     node.Name,
+			Hostname: node.ObjectMeta.Labels["kubernetes.io/hostname"],
+			Role:     "hybrid",
+			Status:   getNodeReadyStatus(node),
+		}
+
+		if IsNodeReady(node) {
+			readyNodes = append(readyNodes, *node)
+		} else {
+			v.addError(&ValidationError{
+				Kind:          "Node",
+				Name:          node.Name,
+				Message:       fmt.Sprintf("node %q of role %q is not ready", node.Name, n.Role),
+				InstanceGroup: ig,
+			})
+		}
+
+		v.Nodes = append(v.Nodes, n)
+	}
+	return readyNodes
+}
@@ -69,6 +69,10 @@ func (c *MockCloud) GetCloudGroups(cluster *kopsapi.Cluster, instancegroups []*k
 }
 
 func testValidate(t *testing.T, groups map[string]*cloudinstances.CloudInstanceGroup, objects []runtime.Object) (*ValidationCluster, error) {
+	return testValidateWithChecks(t, groups, objects)
+}
+
+func testValidateWithChecks(t *testing.T, groups map[string]*cloudinstances.CloudInstanceGroup, objects []runtime.Object, checks ...Check) (*ValidationCluster, error) {
 	cluster := &kopsapi.Cluster{
 		ObjectMeta: metav1.ObjectMeta{Name: "testcluster.k8s.local"},
 	}
@@ -121,7 +125,7 @@ func testValidate(t *testing.T, groups map[string]*cloudinstances.CloudInstanceG
 
 	mockcloud := BuildMockCloud(t, groups, cluster, instanceGroups)
 
-	validator, err := NewClusterValidator(cluster, mockcloud, &kopsapi.InstanceGroupList{Items: instanceGroups}, "https://api.testcluster.k8s.local", fake.NewSimpleClientset(objects...))
+	validator, err := NewClusterValidator(cluster, mockcloud, &kopsapi.InstanceGroupList{Items: instanceGroups}, "https://api.testcluster.k8s.local", fake.NewSimpleClientset(objects...), checks...)
 	if err != nil {
 		return nil, err
 	}
@@ -892,3 +896,62 @@ func Test_ValidateDetachedNodesNotValidated(t *testing.T) {
 		printDebug(t, v)
 	}
 }
+
+func Test_ValidateHybridNodes(t *testing.T) {
+	cluster := &kopsapi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcluster.k8s.local"},
+	}
+
+	instanceGroups := []kopsapi.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "hybrid-1",
+			},
+			Spec: kopsapi.InstanceGroupSpec{
+				Role: kopsapi.InstanceGroupRoleHybrid,
+			},
+		},
+	}
+
+	objects := []runtime.Object{
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "rack1-a",
+				Labels: map[string]string{kopsapi.NodeLabelInstanceGroup: "hybrid-1"},
+			},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{
+					{Type: "Ready", Status: v1.ConditionTrue},
+				},
+			},
+		},
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "rack1-b",
+				Labels: map[string]string{kopsapi.NodeLabelInstanceGroup: "hybrid-1"},
+			},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{
+					{Type: "Ready", Status: v1.ConditionFalse},
+				},
+			},
+		},
+	}
+
+	mockcloud := BuildMockCloud(t, nil, cluster, instanceGroups)
+
+	validator, err := NewClusterValidator(cluster, mockcloud, &kopsapi.InstanceGroupList{Items: instanceGroups}, "https://api.testcluster.k8s.local", fake.NewSimpleClientset(objects...))
+	require.NoError(t, err)
+	v, err := validator.Validate()
+	require.NoError(t, err)
+	assert.Len(t, v.Nodes, 2)
+	if !assert.Len(t, v.Failures, 1) ||
+		!assert.Equal(t, &ValidationError{
+			Kind:          "Node",
+			Name:          "rack1-b",
+			Message:       "node \"rack1-b\" of role \"hybrid\" is not ready",
+			InstanceGroup: &instanceGroups[0],
+		}, v.Failures[0]) {
+		printDebug(t, v)
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package wellknownports
 
 const (
+	// KopsControllerMetricsPort is the port where kops-controller serves metrics.
+	KopsControllerMetricsPort = 3987
+
 	// KopsControllerPort is the port where kops-controller listens.
 	KopsControllerPort = 3988
 
@@ -67,6 +67,7 @@ func run() error {
 	var flagChannels, tlsCert, tlsKey, tlsCA, peerCert, peerKey, peerCA string
 	var etcdBackupImage, etcdBackupStore, etcdImageSource, etcdElectionTimeout, etcdHeartbeatInterval string
 	var dnsUpdateInterval int
+	var gossipSeedAddresses []string
 
 	flag.BoolVar(&applyTaints, "apply-taints", applyTaints, "Apply taints to nodes based on the role")
 	flag.BoolVar(&containerized, "containerized", containerized, "Set if we are running containerized.")
@@ -84,6 +85,7 @@ func run() error {
 	flag.StringVar(&gossipProtocolSecondary, "gossip-protocol-secondary", "memberlist", "mesh/memberlist")
 	flag.StringVar(&gossipListenSecondary, "gossip-listen-secondary", fmt.Sprintf("0.0.0.0:%d", wellknownports.ProtokubeGossipMemberlist), "address:port on which to bind for gossip")
 	flags.StringVar(&gossipSecretSecondary, "gossip-secret-secondary", gossipSecret, "Secret to use to secure gossip")
+	flags.StringSliceVar(&gossipSeedAddresses, "gossip-seeds", gossipSeedAddresses, "Addresses of the peers to join the gossip network through, on clouds without discovery (metal)")
 	flag.StringVar(&peerCA, "peer-ca", peerCA, "Path to a file containing the peer ca in PEM format")
 	flag.StringVar(&peerCert, "peer-cert", peerCert, "Path to a file containing the peer certificate")
 	flag.StringVar(&peerKey, "peer-key", peerKey, "Path to a file containing the private key for the peers")
@@ -206,6 +208,28 @@ func run() error {
 		if clusterID == "" {
 			clusterID = azureVolumes.ClusterID()
 		}
+	} else if cloud == "oci" {
+		klog.Info("Initializing OCI volumes")
+		ociVolumes, err := protokube.NewOCIVolumes()
+		if err != nil {
+			klog.Errorf("Error initializing OCI: %q", err)
+			os.Exit(1)
+		}
+		volumes = ociVolumes
+		internalIP = ociVolumes.InternalIP()
+
+		if clusterID == "" {
+			clusterID = ociVolumes.ClusterID()
+		}
+	} else if cloud == "metal" {
+		klog.Info("Initializing metal volumes")
+		metalVolumes, err := protokube.NewMetalVolumes(clusterID, gossipSeedAddresses)
+		if err != nil {
+			klog.Errorf("Error initializing metal: %q", err)
+			os.Exit(1)
+		}
+		volumes = metalVolumes
+		internalIP = metalVolumes.InternalIP()
 	} else {
 		klog.Errorf("Unknown cloud %q", cloud)
 		os.Exit(1)
@@ -286,6 +310,18 @@ func run() error {
 				return err
 			}
 			gossipName = volumes.(*protokube.AzureVolumes).InstanceID()
+		} else if cloud == "oci" {
+			gossipSeeds, err = volumes.(*protokube.OCIVolumes).GossipSeeds()
+			if err != nil {
+				return err
+			}
+			gossipName = volumes.(*protokube.OCIVolumes).InstanceID()
+		} else if cloud == "metal" {
+			gossipSeeds, err = volumes.(*protokube.MetalVolumes).GossipSeeds()
+			if err != nil {
+				return err
+			}
+			gossipName = volumes.(*protokube.MetalVolumes).InstanceID()
 		} else {
 			klog.Fatalf("seed provider for %q not yet implemented", cloud)
 		}
@@ -0,0 +1,194 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+const (
+	instanceMetadataURL = "http://169.254.169.254/opc/v2/instance/"
+	vnicsMetadataURL    = "http://169.254.169.254/opc/v2/vnics/"
+)
+
+type instanceMetadata struct {
+	ID                  string            `json:"id"`
+	CompartmentID       string            `json:"compartmentId"`
+	CanonicalRegionName string            `json:"canonicalRegionName"`
+	FreeformTags        map[string]string `json:"freeformTags"`
+}
+
+type vnicMetadata struct {
+	VnicID    string `json:"vnicId"`
+	PrivateIP string `json:"privateIp"`
+}
+
+// Client is an OCI client authenticated as the instance principal.
+type Client struct {
+	metadata *instanceMetadata
+	vnics    []*vnicMetadata
+
+	computeClient        *core.ComputeClient
+	virtualNetworkClient *core.VirtualNetworkClient
+}
+
+// NewClient returns a new Client.
+func NewClient() (*Client, error) {
+	m := &instanceMetadata{}
+	if err := queryInstanceMetadata(instanceMetadataURL, m); err != nil {
+		return nil, fmt.Errorf("error querying instance metadata: %s", err)
+	}
+	if m.CompartmentID == "" {
+		return nil, fmt.Errorf("empty compartment ID")
+	}
+	var vnics []*vnicMetadata
+	if err := queryInstanceMetadata(vnicsMetadataURL, &vnics); err != nil {
+		return nil, fmt.Errorf("error querying VNIC metadata: %s", err)
+	}
+
+	configProvider, err := auth.InstancePrincipalConfigurationProvider()
+	if err != nil {
+		return nil, fmt.Errorf("error creating an instance principal configuration provider: %s", err)
+	}
+
+	computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error creating a compute client: %s", err)
+	}
+	computeClient.SetRegion(m.CanonicalRegionName)
+
+	virtualNetworkClient, err := core.NewVirtualNetworkClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error creating a virtual network client: %s", err)
+	}
+	virtualNetworkClient.SetRegion(m.CanonicalRegionName)
+
+	return &Client{
+		metadata:             m,
+		vnics:                vnics,
+		computeClient:        &computeClient,
+		virtualNetworkClient: &virtualNetworkClient,
+	}, nil
+}
+
+// GetInstanceID returns the OCID of the instance.
+func (c *Client) GetInstanceID() string {
+	return c.metadata.ID
+}
+
+// GetTags returns the freeform tags of the instance queried from the instance metadata service.
+func (c *Client) GetTags() map[string]string {
+	return c.metadata.FreeformTags
+}
+
+// GetInternalIP returns the private IP of the primary VNIC of the instance.
+// This function returns nil if no private IP is found.
+func (c *Client) GetInternalIP() net.IP {
+	for _, vnic := range c.vnics {
+		if vnic.PrivateIP != "" {
+			return net.ParseIP(vnic.PrivateIP)
+		}
+	}
+	return nil
+}
+
+// ListInstances returns the running instances in the compartment of the instance.
+func (c *Client) ListInstances(ctx context.Context) ([]core.Instance, error) {
+	var l []core.Instance
+	req := core.ListInstancesRequest{
+		CompartmentId:  common.String(c.metadata.CompartmentID),
+		LifecycleState: core.InstanceLifecycleStateRunning,
+	}
+	for {
+		resp, err := c.computeClient.ListInstances(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+// ListVnicAttachments returns the VNIC attachments in the compartment of the instance.
+func (c *Client) ListVnicAttachments(ctx context.Context) ([]core.VnicAttachment, error) {
+	var l []core.VnicAttachment
+	req := core.ListVnicAttachmentsRequest{
+		CompartmentId: common.String(c.metadata.CompartmentID),
+	}
+	for {
+		resp, err := c.computeClient.ListVnicAttachments(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+// GetVnic returns the VNIC with the given OCID.
+func (c *Client) GetVnic(ctx context.Context, id string) (*core.Vnic, error) {
+	resp, err := c.virtualNetworkClient.GetVnic(ctx, core.GetVnicRequest{
+		VnicId: common.String(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Vnic, nil
+}
+
+// queryInstanceMetadata queries the OCI instance metadata service (v2) documented in
+// https://docs.oracle.com/en-us/iaas/Content/Compute/Tasks/gettingmetadata.htm.
+func queryInstanceMetadata(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating a new request: %s", err)
+	}
+	req.Header.Add("Authorization", "Bearer Oracle")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request to the metadata server: %s", err)
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("metadata server returned non-200 status code: %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading a response from the metadata server: %s", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error unmarshalling metadata: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestQueryInstanceMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer Oracle" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{
+  "id": "ocid1.instance.oc1.iad.test",
+  "displayName": "inst-test",
+  "compartmentId": "ocid1.compartment.oc1..test",
+  "region": "iad",
+  "canonicalRegionName": "us-ashburn-1",
+  "freeformTags": {
+    "KubernetesCluster": "test-cluster",
+    "KopsRole": "Master"
+  }
+}`))
+	}))
+	defer server.Close()
+
+	m := &instanceMetadata{}
+	if err := queryInstanceMetadata(server.URL, m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := &instanceMetadata{
+		ID:                  "ocid1.instance.oc1.iad.test",
+		CompartmentID:       "ocid1.compartment.oc1..test",
+		CanonicalRegionName: "us-ashburn-1",
+		FreeformTags: map[string]string{
+			"KubernetesCluster": "test-cluster",
+			"KopsRole":          "Master",
+		},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("expected metadata %+v, but got %+v", expected, m)
+	}
+}
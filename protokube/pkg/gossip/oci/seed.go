@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/protokube/pkg/gossip"
+)
+
+type client interface {
+	ListInstances(ctx context.Context) ([]core.Instance, error)
+	ListVnicAttachments(ctx context.Context) ([]core.VnicAttachment, error)
+	GetVnic(ctx context.Context, id string) (*core.Vnic, error)
+}
+
+var _ client = &Client{}
+
+// SeedProvider is an OCI implementation of gossip.SeedProvider.
+type SeedProvider struct {
+	client client
+	tags   map[string]string
+}
+
+var _ gossip.SeedProvider = &SeedProvider{}
+
+// NewSeedProvider returns a new SeedProvider.
+func NewSeedProvider(client client, tags map[string]string) (*SeedProvider, error) {
+	return &SeedProvider{
+		client: client,
+		tags:   tags,
+	}, nil
+}
+
+// GetSeeds returns a slice of strings used as seeds of Gossip.
+// This follows the implementation of AWS and creates seeds from
+// private IPs of instances in the cluster.
+func (p *SeedProvider) GetSeeds() ([]string, error) {
+	ctx := context.TODO()
+	instances, err := p.client.ListInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing instances: %s", err)
+	}
+
+	instanceIDs := make(map[string]bool)
+	for _, instance := range instances {
+		if p.isInstanceForCluster(&instance) {
+			instanceIDs[*instance.Id] = true
+		}
+	}
+	klog.V(2).Infof("Found %d instances for the cluster (out of %d)", len(instanceIDs), len(instances))
+
+	attachments, err := p.client.ListVnicAttachments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing VNIC attachments: %s", err)
+	}
+
+	var seeds []string
+	for _, attachment := range attachments {
+		if attachment.InstanceId == nil || !instanceIDs[*attachment.InstanceId] {
+			continue
+		}
+		if attachment.LifecycleState != core.VnicAttachmentLifecycleStateAttached || attachment.VnicId == nil {
+			continue
+		}
+		vnic, err := p.client.GetVnic(ctx, *attachment.VnicId)
+		if err != nil {
+			return nil, fmt.Errorf("error getting VNIC %s: %s", *attachment.VnicId, err)
+		}
+		if vnic.PrivateIp != nil {
+			seeds = append(seeds, *vnic.PrivateIp)
+		}
+	}
+	return seeds, nil
+}
+
+func (p *SeedProvider) isInstanceForCluster(instance *core.Instance) bool {
+	for k, v := range p.tags {
+		if instance.FreeformTags[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+type mockClient struct {
+	instances   []core.Instance
+	attachments []core.VnicAttachment
+	vnics       map[string]*core.Vnic
+}
+
+var _ client = &mockClient{}
+
+func (c *mockClient) ListInstances(ctx context.Context) ([]core.Instance, error) {
+	return c.instances, nil
+}
+
+func (c *mockClient) ListVnicAttachments(ctx context.Context) ([]core.VnicAttachment, error) {
+	return c.attachments, nil
+}
+
+func (c *mockClient) GetVnic(ctx context.Context, id string) (*core.Vnic, error) {
+	return c.vnics[id], nil
+}
+
+func TestGetSeeds(t *testing.T) {
+	const (
+		clusterTag  = "KubernetesCluster"
+		clusterName = "test-cluster"
+	)
+
+	client := &mockClient{
+		instances: []core.Instance{
+			{
+				Id:           common.String("instance0"),
+				FreeformTags: map[string]string{clusterTag: clusterName},
+			},
+			{
+				Id:           common.String("instance1"),
+				FreeformTags: map[string]string{clusterTag: clusterName},
+			},
+			{
+				Id:           common.String("instance2"),
+				FreeformTags: map[string]string{clusterTag: "other-cluster"},
+			},
+		},
+		attachments: []core.VnicAttachment{
+			{
+				InstanceId:     common.String("instance0"),
+				VnicId:         common.String("vnic0"),
+				LifecycleState: core.VnicAttachmentLifecycleStateAttached,
+			},
+			{
+				InstanceId:     common.String("instance1"),
+				VnicId:         common.String("vnic1"),
+				LifecycleState: core.VnicAttachmentLifecycleStateAttached,
+			},
+			{
+				InstanceId:     common.String("instance1"),
+				VnicId:         common.String("vnic1-detached"),
+				LifecycleState: core.VnicAttachmentLifecycleStateDetached,
+			},
+			{
+				InstanceId:     common.String("instance2"),
+				VnicId:         common.String("vnic2"),
+				LifecycleState: core.VnicAttachmentLifecycleStateAttached,
+			},
+		},
+		vnics: map[string]*core.Vnic{
+			"vnic0":          {PrivateIp: common.String("10.0.0.10")},
+			"vnic1":          {PrivateIp: common.String("10.0.0.11")},
+			"vnic1-detached": {PrivateIp: common.String("10.0.0.12")},
+			"vnic2":          {PrivateIp: common.String("10.0.0.13")},
+		},
+	}
+
+	provider, err := NewSeedProvider(client, map[string]string{clusterTag: clusterName})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	actual, err := provider.GetSeeds()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	sort.Strings(actual)
+	expected := []string{"10.0.0.10", "10.0.0.11"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected seeds %v, but got %v", expected, actual)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protokube
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"k8s.io/kops/protokube/pkg/gossip"
+)
+
+// MetalVolumes implements the Volumes interface for machines that kOps does not provision.
+// There is no cloud API, so the identity of the machine is read from the operating system.
+type MetalVolumes struct {
+	clusterID   string
+	instanceID  string
+	internalIP  net.IP
+	gossipSeeds []string
+}
+
+var _ Volumes = &MetalVolumes{}
+
+// NewMetalVolumes returns a new MetalVolumes.
+func NewMetalVolumes(clusterID string, gossipSeeds []string) (*MetalVolumes, error) {
+	instanceID, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("error getting hostname: %v", err)
+	}
+	internalIP, err := findInternalIP()
+	if err != nil {
+		return nil, err
+	}
+	return &MetalVolumes{
+		clusterID:   clusterID,
+		instanceID:  instanceID,
+		internalIP:  internalIP,
+		gossipSeeds: gossipSeeds,
+	}, nil
+}
+
+// findInternalIP returns the first IPv4 address of the interfaces that are up, other than the loopback interface.
+func findInternalIP() (net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("error listing network interfaces: %v", err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("error listing addresses of network interface %s: %v", iface.Name, err)
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			if ip := ipNet.IP.To4(); ip != nil && ip.IsGlobalUnicast() {
+				return ip, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no IPv4 address found on the network interfaces")
+}
+
+// ClusterID implements Volumes ClusterID.
+func (v *MetalVolumes) ClusterID() string {
+	return v.clusterID
+}
+
+// InstanceID implements Volumes InstanceID.
+func (v *MetalVolumes) InstanceID() string {
+	return v.instanceID
+}
+
+// InternalIP implements Volumes InternalIP.
+func (v *MetalVolumes) InternalIP() net.IP {
+	return v.internalIP
+}
+
+// GossipSeeds returns the static seeds passed to protokube, which are the control plane machines.
+func (v *MetalVolumes) GossipSeeds() (gossip.SeedProvider, error) {
+	if len(v.gossipSeeds) == 0 {
+		return nil, fmt.Errorf("no gossip seeds were specified")
+	}
+	return gossip.NewStaticSeedProvider(v.gossipSeeds), nil
+}
+
+// AttachVolume is not implemented; etcd-manager manages the volumes, not protokube.
+func (v *MetalVolumes) AttachVolume(volume *Volume) error {
+	return nil
+}
+
+// FindVolumes is not implemented; etcd-manager manages the volumes, not protokube.
+func (v *MetalVolumes) FindVolumes() ([]*Volume, error) {
+	return nil, nil
+}
+
+// FindMountedVolume is not implemented; etcd-manager manages the volumes, not protokube.
+func (v *MetalVolumes) FindMountedVolume(volume *Volume) (string, error) {
+	return "", nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protokube
+
+import (
+	"fmt"
+	"net"
+
+	"k8s.io/kops/protokube/pkg/gossip"
+	gossipoci "k8s.io/kops/protokube/pkg/gossip/oci"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+// OCIVolumes implements the Volumes interface for OCI.
+type OCIVolumes struct {
+	client *gossipoci.Client
+
+	clusterTag string
+	instanceID string
+	internalIP net.IP
+}
+
+var _ Volumes = &OCIVolumes{}
+
+// NewOCIVolumes returns a new OCIVolumes.
+func NewOCIVolumes() (*OCIVolumes, error) {
+	client, err := gossipoci.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("error creating a new OCI client: %s", err)
+	}
+
+	clusterTag := client.GetTags()[oci.TagClusterName]
+	if clusterTag == "" {
+		return nil, fmt.Errorf("cluster tag %q not found", oci.TagClusterName)
+	}
+	instanceID := client.GetInstanceID()
+	if instanceID == "" {
+		return nil, fmt.Errorf("empty instance ID")
+	}
+	internalIP := client.GetInternalIP()
+	if internalIP == nil {
+		return nil, fmt.Errorf("error querying internal IP")
+	}
+	return &OCIVolumes{
+		client:     client,
+		clusterTag: clusterTag,
+		instanceID: instanceID,
+		internalIP: internalIP,
+	}, nil
+}
+
+// ClusterID implements Volumes ClusterID.
+func (v *OCIVolumes) ClusterID() string {
+	return v.clusterTag
+}
+
+// InstanceID implements Volumes InstanceID.
+func (v *OCIVolumes) InstanceID() string {
+	return v.instanceID
+}
+
+// InternalIP implements Volumes InternalIP.
+func (v *OCIVolumes) InternalIP() net.IP {
+	return v.internalIP
+}
+
+func (v *OCIVolumes) GossipSeeds() (gossip.SeedProvider, error) {
+	tags := map[string]string{
+		oci.TagClusterName: v.clusterTag,
+	}
+	return gossipoci.NewSeedProvider(v.client, tags)
+}
+
+// AttachVolume is not implemented; etcd-manager manages the volumes, not protokube.
+func (v *OCIVolumes) AttachVolume(volume *Volume) error {
+	return nil
+}
+
+// FindVolumes is not implemented; etcd-manager manages the volumes, not protokube.
+func (v *OCIVolumes) FindVolumes() ([]*Volume, error) {
+	return nil, nil
+}
+
+// FindMountedVolume is not implemented; etcd-manager manages the volumes, not protokube.
+func (v *OCIVolumes) FindMountedVolume(volume *Volume) (string, error) {
+	return "", nil
+}
@@ -190,6 +190,21 @@ resource "aws_autoscaling_group" "nodes-minimal-example-com" {
     propagate_at_launch = true
     value               = "owned"
   }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/enabled"
+    propagate_at_launch = false
+    value               = ""
+  }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/minimal.example.com"
+    propagate_at_launch = false
+    value               = ""
+  }
+  tag {
+    key                 = "k8s.io/cluster-autoscaler/node-template/resources/ephemeral-storage"
+    propagate_at_launch = false
+    value               = "128Gi"
+  }
   vpc_zone_identifier = [aws_subnet.us-test-1a-minimal-example-com.id]
 }
 
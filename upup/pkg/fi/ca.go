@@ -36,6 +36,10 @@ const CertificateIDCA = "kubernetes-ca"
 const (
 	// SecretNameSSHPrimary is the Name for the primary SSH key
 	SecretNameSSHPrimary = "admin"
+	// SecretNameSSHPrevious is the Name for the SSH keys replaced by a rotation of the primary SSH key, which remain authorized until the rotation is finished
+	SecretNameSSHPrevious = "admin-previous"
+	// SecretNameSSHCA is the Name for the public keys of the SSH certificate authorities trusted to sign user certificates
+	SecretNameSSHCA = "sshca"
 )
 
 const (
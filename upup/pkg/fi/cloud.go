@@ -54,6 +54,38 @@ type Cloud interface {
 	GetApiIngressStatus(cluster *kops.Cluster) ([]ApiIngressStatus, error)
 }
 
+// SurgeGroupCloud is implemented by clouds that can surge an instance group by running a temporary copy of it.
+type SurgeGroupCloud interface {
+	// CreateSurgeGroup creates a temporary copy of the group, using the group's current configuration,
+	// with the specified number of instances. It returns the name of the surge group.
+	// If the surge group already exists, it is resized instead.
+	CreateSurgeGroup(group *cloudinstances.CloudInstanceGroup, size int) (string, error)
+
+	// GetSurgeGroupInstances returns the IDs of the instances in the surge group that are not terminating.
+	GetSurgeGroupInstances(name string) ([]string, error)
+
+	// DeleteSurgeGroup deletes the surge group, including its instances.
+	DeleteSurgeGroup(name string) error
+}
+
+// CloudCapabilities describes the optional features that kOps supports on a cloud provider.
+// Validation rejects cluster specs and instance groups that use a feature the cloud provider does not support,
+// rather than letting the model builders ignore it.
+type CloudCapabilities interface {
+	// SupportsAPILoadBalancer is true if kOps can create a load balancer in front of the API servers.
+	SupportsAPILoadBalancer() bool
+	// LoadBalancerClasses returns the classes of API load balancer that can be chosen, or nil if the class cannot be chosen.
+	LoadBalancerClasses() []kops.LoadBalancerClass
+	// SupportsIPv6 is true if kOps can assign IPv6 CIDRs to subnets and run IPv6-only clusters.
+	SupportsIPv6() bool
+	// SupportsSpotInstances is true if instance groups can request spot instances with maxPrice.
+	SupportsSpotInstances() bool
+	// SupportsMixedInstancesPolicy is true if instance groups can mix instance types with mixedInstancesPolicy.
+	SupportsMixedInstancesPolicy() bool
+	// SupportsWarmPool is true if instance groups can keep a warm pool of pre-initialized instances.
+	SupportsWarmPool() bool
+}
+
 type VPCInfo struct {
 	// CIDR is the IP address range for the VPC
 	CIDR string
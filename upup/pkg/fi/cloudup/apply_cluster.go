@@ -33,9 +33,11 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/pelletier/go-toml"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	kopsbase "k8s.io/kops"
 	"k8s.io/kops/pkg/apis/kops"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/apis/kops/validation"
@@ -54,18 +56,27 @@ import (
 	"k8s.io/kops/pkg/model/domodel"
 	"k8s.io/kops/pkg/model/gcemodel"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/model/metalmodel"
+	"k8s.io/kops/pkg/model/ocimodel"
 	"k8s.io/kops/pkg/model/openstackmodel"
+	"k8s.io/kops/pkg/policy"
+	"k8s.io/kops/pkg/quotas"
+	"k8s.io/kops/pkg/spot"
 	"k8s.io/kops/pkg/templates"
+	"k8s.io/kops/pkg/tracing"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/models"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/aliup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/bootstrapchannelbuilder"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
@@ -140,9 +151,20 @@ type ApplyClusterCmd struct {
 	// GetAssets is whether this is called just to obtain the list of assets.
 	GetAssets bool
 
+	// Policies are files or directories of Rego policies that the cluster and its planned tasks must satisfy
+	Policies []string
+
+	// SkipQuotaChecks skips checking the AWS quotas that the planned tasks need
+	SkipQuotaChecks bool
+	// RequestQuotaIncreases requests increases of the AWS quotas that do not cover the planned tasks
+	RequestQuotaIncreases bool
+
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.Task
 
+	// TagChanges are the tags added to the existing resources with PhaseTags (output)
+	TagChanges []*awstasks.TagChange
+
 	// ImageAssets are the image assets we use (output).
 	ImageAssets []*assets.ImageAsset
 	// FileAssets are the file assets we use (output).
@@ -150,6 +172,13 @@ type ApplyClusterCmd struct {
 }
 
 func (c *ApplyClusterCmd) Run(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "ApplyCluster", "kops.cluster", c.Cluster.ObjectMeta.Name, "kops.target", c.TargetName)
+	err := c.run(ctx)
+	span.Finish(err)
+	return err
+}
+
+func (c *ApplyClusterCmd) run(ctx context.Context) error {
 	if c.InstanceGroups == nil {
 		list, err := c.Clientset.InstanceGroupsFor(c.Cluster).List(ctx, metav1.ListOptions{})
 		if err != nil {
@@ -197,6 +226,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		networkLifecycle = fi.LifecycleExistsAndWarnIfChanges
 		clusterLifecycle = fi.LifecycleIgnore
 
+	case PhaseTags:
+		// The tasks are not run, their tags are compared with those of the existing resources
+
 	case PhaseCluster:
 		if c.TargetName == TargetDryRun {
 			securityLifecycle = fi.LifecycleExistsAndWarnIfChanges
@@ -215,6 +247,11 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		clusterLifecycle = fi.LifecycleIgnore
 	}
 
+	// Warn about the feature gates as they are written, before they are copied to the components
+	for _, warning := range validation.FeatureGateWarnings(c.Cluster, c.InstanceGroups) {
+		klog.Warningf("%s", warning)
+	}
+
 	assetBuilder := assets.NewAssetBuilder(c.Cluster, c.GetAssets)
 	err = c.upgradeSpecs(assetBuilder)
 	if err != nil {
@@ -382,6 +419,30 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		}
 	}
 
+	var sshPreviousPublicKeys [][]byte
+	{
+		keys, err := sshCredentialStore.FindSSHPublicKeys(fi.SecretNameSSHPrevious)
+		if err != nil {
+			return fmt.Errorf("error retrieving SSH public key %q: %v", fi.SecretNameSSHPrevious, err)
+		}
+
+		for _, k := range keys {
+			sshPreviousPublicKeys = append(sshPreviousPublicKeys, []byte(k.Spec.PublicKey))
+		}
+	}
+
+	var sshCAPublicKeys [][]byte
+	{
+		keys, err := sshCredentialStore.FindSSHPublicKeys(fi.SecretNameSSHCA)
+		if err != nil {
+			return fmt.Errorf("error retrieving SSH public key %q: %v", fi.SecretNameSSHCA, err)
+		}
+
+		for _, k := range keys {
+			sshCAPublicKeys = append(sshCAPublicKeys, []byte(k.Spec.PublicKey))
+		}
+	}
+
 	modelContext := &model.KopsModelContext{
 		IAMModelContext: iam.IAMModelContext{Cluster: cluster},
 		InstanceGroups:  c.InstanceGroups,
@@ -420,10 +481,6 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			if len(sshPublicKeys) == 0 && c.Cluster.Spec.SSHKeyName == nil {
 				return fmt.Errorf("SSH public key must be specified when running with AWS (create with `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub`)", cluster.ObjectMeta.Name)
 			}
-
-			if len(sshPublicKeys) > 1 {
-				return fmt.Errorf("exactly one 'admin' SSH public key can be specified when running with AWS; please delete a key using `kops delete secret`")
-			}
 		}
 
 	case kops.CloudProviderALI:
@@ -439,10 +496,6 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			if len(sshPublicKeys) == 0 {
 				return fmt.Errorf("SSH public key must be specified when running with ALICloud (create with `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub`)", cluster.ObjectMeta.Name)
 			}
-
-			if len(sshPublicKeys) != 1 {
-				return fmt.Errorf("exactly one 'admin' SSH public key can be specified when running with ALICloud; please delete a key using `kops delete secret`")
-			}
 		}
 	case kops.CloudProviderAzure:
 		{
@@ -453,9 +506,21 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			if len(sshPublicKeys) == 0 {
 				return fmt.Errorf("SSH public key must be specified when running with AzureCloud (create with `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub`)", cluster.ObjectMeta.Name)
 			}
+		}
+	case kops.CloudProviderOCI:
+		{
+			if !featureflag.OCI.Enabled() {
+				return fmt.Errorf("OCI support is currently alpha, and is feature-gated. Please export KOPS_FEATURE_FLAGS=OCI")
+			}
 
-			if len(sshPublicKeys) != 1 {
-				return fmt.Errorf("exactly one 'admin' SSH public key can be specified when running with AzureCloud; please delete a key using `kops delete secret`")
+			if len(sshPublicKeys) == 0 {
+				return fmt.Errorf("SSH public key must be specified when running with OCI (create with `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub`)", cluster.ObjectMeta.Name)
+			}
+		}
+	case kops.CloudProviderMetal:
+		{
+			if !featureflag.Metal.Enabled() {
+				return fmt.Errorf("metal support is currently alpha, and is feature-gated. Please export KOPS_FEATURE_FLAGS=Metal")
 			}
 		}
 	case kops.CloudProviderOpenstack:
@@ -463,10 +528,6 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			if len(sshPublicKeys) == 0 {
 				return fmt.Errorf("SSH public key must be specified when running with Openstack (create with `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub`)", cluster.ObjectMeta.Name)
 			}
-
-			if len(sshPublicKeys) != 1 {
-				return fmt.Errorf("exactly one 'admin' SSH public key can be specified when running with Openstack; please delete a key using `kops delete secret`")
-			}
 		}
 	default:
 		return fmt.Errorf("unknown CloudProvider %q", cluster.Spec.CloudProvider)
@@ -489,7 +550,12 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		cloud:            cloud,
 	}
 
-	configBuilder, err := newNodeUpConfigBuilder(cluster, assetBuilder, c.Assets, encryptionConfigSecretHash)
+	// The keys replaced by a rotation stay authorized on the instances, but the cloud only provides the new keys
+	var sshAuthorizedKeys [][]byte
+	sshAuthorizedKeys = append(sshAuthorizedKeys, sshPublicKeys...)
+	sshAuthorizedKeys = append(sshAuthorizedKeys, sshPreviousPublicKeys...)
+
+	configBuilder, err := newNodeUpConfigBuilder(cluster, c.InstanceGroups, assetBuilder, c.Assets, encryptionConfigSecretHash, sshAuthorizedKeys, sshCAPublicKeys)
 	if err != nil {
 		return err
 	}
@@ -556,6 +622,8 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				&awsmodel.APILoadBalancerBuilder{AWSModelContext: awsModelContext, Lifecycle: clusterLifecycle, SecurityLifecycle: securityLifecycle},
 				&awsmodel.BastionModelBuilder{AWSModelContext: awsModelContext, Lifecycle: clusterLifecycle, SecurityLifecycle: securityLifecycle},
 				&awsmodel.DNSModelBuilder{AWSModelContext: awsModelContext, Lifecycle: clusterLifecycle},
+				&awsmodel.EBSEncryptionModelBuilder{AWSModelContext: awsModelContext, Lifecycle: clusterLifecycle},
+				&awsmodel.EgressGatewayModelBuilder{AWSModelContext: awsModelContext, Lifecycle: networkLifecycle},
 				&awsmodel.ExternalAccessModelBuilder{AWSModelContext: awsModelContext, Lifecycle: securityLifecycle},
 				&awsmodel.FirewallModelBuilder{AWSModelContext: awsModelContext, Lifecycle: securityLifecycle},
 				&awsmodel.SSHKeyModelBuilder{AWSModelContext: awsModelContext, Lifecycle: securityLifecycle},
@@ -587,6 +655,12 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				l.Builders = append(l.Builders, awsModelBuilder)
 			}
 
+			l.Builders = append(l.Builders, &awsmodel.HybridNodeupScriptModelBuilder{
+				AWSModelContext:        awsModelContext,
+				BootstrapScriptBuilder: bootstrapScriptBuilder,
+				Lifecycle:              clusterLifecycle,
+			})
+
 			nth := c.Cluster.Spec.NodeTerminationHandler
 			if nth != nil && fi.BoolValue(nth.Enabled) && fi.BoolValue(nth.EnableSQSTerminationDraining) {
 				l.Builders = append(l.Builders, &awsmodel.NodeTerminationHandlerBuilder{
@@ -648,6 +722,20 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 
 				&azuremodel.VMScaleSetModelBuilder{AzureModelContext: azureModelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: clusterLifecycle},
 			)
+		case kops.CloudProviderOCI:
+			ociModelContext := &ocimodel.OCIModelContext{
+				KopsModelContext: modelContext,
+			}
+			l.Builders = append(l.Builders,
+				&ocimodel.APILoadBalancerModelBuilder{OCIModelContext: ociModelContext, Lifecycle: clusterLifecycle},
+				&ocimodel.IAMModelBuilder{OCIModelContext: ociModelContext, Lifecycle: securityLifecycle},
+				&ocimodel.NetworkModelBuilder{OCIModelContext: ociModelContext, Lifecycle: networkLifecycle},
+				&ocimodel.InstancePoolModelBuilder{OCIModelContext: ociModelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: clusterLifecycle},
+			)
+		case kops.CloudProviderMetal:
+			l.Builders = append(l.Builders,
+				&metalmodel.NodeupScriptModelBuilder{KopsModelContext: modelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: clusterLifecycle},
+			)
 		case kops.CloudProviderOpenstack:
 			openstackModelContext := &openstackmodel.OpenstackModelContext{
 				KopsModelContext: modelContext,
@@ -669,6 +757,25 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		return fmt.Errorf("error building tasks: %v", err)
 	}
 
+	if len(c.Policies) != 0 {
+		if err := c.evaluatePolicies(ctx); err != nil {
+			return err
+		}
+	}
+
+	if c.Phase == PhaseTags {
+		return c.reconcileTags(cloud)
+	}
+
+	if awsCloud, ok := cloud.(awsup.AWSCloud); ok && !c.GetAssets && (c.TargetName == TargetDirect || c.TargetName == TargetDryRun) {
+		if !c.SkipQuotaChecks {
+			if err := c.checkQuotas(awsCloud); err != nil {
+				return err
+			}
+		}
+		c.warnSpotMaxPrices(awsCloud)
+	}
+
 	var target fi.Target
 	shouldPrecreateDNS := true
 
@@ -687,6 +794,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			target = aliup.NewALIAPITarget(cloud.(aliup.ALICloud))
 		case kops.CloudProviderAzure:
 			target = azure.NewAzureAPITarget(cloud.(azure.AzureCloud))
+		case kops.CloudProviderOCI:
+			target = oci.NewOCIAPITarget(cloud.(oci.OCICloud))
+		case kops.CloudProviderMetal:
+			target = metal.NewMetalAPITarget(cloud.(metal.MetalCloud))
 		default:
 			return fmt.Errorf("direct configuration not supported with CloudProvider:%q", cluster.Spec.CloudProvider)
 		}
@@ -754,7 +865,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		}
 	}
 
-	context, err := fi.NewContext(target, cluster, cloud, keyStore, secretStore, configBase, checkExisting, c.TaskMap)
+	context, err := fi.NewContext(ctx, target, cluster, cloud, keyStore, secretStore, configBase, checkExisting, c.TaskMap)
 	if err != nil {
 		return fmt.Errorf("error building context: %v", err)
 	}
@@ -793,6 +904,159 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	return nil
 }
 
+// reconcileTags adds the missing tags of the planned tasks to the existing resources, without running the tasks
+func (c *ApplyClusterCmd) reconcileTags(cloud fi.Cloud) error {
+	awsCloud, ok := cloud.(awsup.AWSCloud)
+	if !ok {
+		return fmt.Errorf("the %s phase is only supported on AWS", PhaseTags)
+	}
+	if c.TargetName != TargetDirect && c.TargetName != TargetDryRun {
+		return fmt.Errorf("the %s phase is not supported with target %q", PhaseTags, c.TargetName)
+	}
+
+	changes, err := awstasks.FindTagChanges(awsCloud, c.Cluster.ObjectMeta.Name, c.TaskMap)
+	if err != nil {
+		return fmt.Errorf("error finding tag changes: %v", err)
+	}
+	if c.TargetName == TargetDirect {
+		if err := awstasks.ApplyTagChanges(awsCloud, changes); err != nil {
+			return fmt.Errorf("error applying tag changes: %v", err)
+		}
+	}
+	c.TagChanges = changes
+	return nil
+}
+
+// evaluatePolicies returns an error if the cluster or its planned tasks violate the policies
+func (c *ApplyClusterCmd) evaluatePolicies(ctx context.Context) error {
+	input, err := policy.BuildInput(c.Cluster, c.InstanceGroups, c.TaskMap)
+	if err != nil {
+		return err
+	}
+
+	evaluator := &policy.Evaluator{Policies: c.Policies}
+	violations, err := evaluator.Evaluate(ctx, input)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		klog.V(2).Infof("cluster satisfies the policies")
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "cluster violates %d policies:", len(violations))
+	for _, violation := range violations {
+		fmt.Fprintf(&b, "\n  * %s", violation)
+	}
+	return fmt.Errorf("%s", b.String())
+}
+
+// warnSpotMaxPrices warns when the maximum spot price of an instance group is below the recent average spot price
+// of its machine types in its zones, as spot instances are then rarely launched and often interrupted.
+func (c *ApplyClusterCmd) warnSpotMaxPrices(cloud awsup.AWSCloud) {
+	for _, ig := range c.InstanceGroups {
+		maxPrice, hasMaxPrice, err := spot.MaxPrice(ig)
+		if err != nil || !hasMaxPrice || !spot.IsSpot(ig) {
+			continue
+		}
+
+		zones, err := apimodel.FindZonesForInstanceGroup(c.Cluster, ig)
+		if err != nil || len(zones) == 0 {
+			continue
+		}
+
+		instanceTypes := spot.InstanceTypes(ig)
+		prices, err := spot.AveragePrices(cloud, instanceTypes, zones, spot.PriceHistoryWindow)
+		if err != nil {
+			klog.Warningf("unable to check the maxPrice of instance group %q against spot prices: %v", ig.ObjectMeta.Name, err)
+			continue
+		}
+
+		for _, instanceType := range instanceTypes {
+			for _, zone := range spot.BelowAverage(prices, instanceType, zones, maxPrice) {
+				average, _ := prices.Get(instanceType, zone)
+				klog.Warningf("instance group %q: maxPrice %s is below the average spot price of %q in %s over the last %v (%.4f); spot instances may rarely launch or be interrupted often",
+					ig.ObjectMeta.Name, *ig.Spec.MaxPrice, instanceType, zone, spot.PriceHistoryWindow, average)
+			}
+		}
+	}
+}
+
+// checkQuotas returns an error if the AWS quotas do not cover what the planned tasks need with the instance groups at their minimum size,
+// and warns if they do not cover their maximum size. With RequestQuotaIncreases, it requests increases of the quotas that are too low.
+func (c *ApplyClusterCmd) checkQuotas(cloud awsup.AWSCloud) error {
+	requirements, err := quotas.BuildRequirements(cloud, c.Cluster.ObjectMeta.Name, c.TaskMap)
+	if err != nil {
+		return fmt.Errorf("error computing the quotas the cluster needs: %v", err)
+	}
+
+	checker := &quotas.Checker{Cloud: cloud}
+	shortfalls := checker.Check(requirements)
+	if len(shortfalls) == 0 {
+		klog.V(2).Infof("quotas cover the resources of the cluster")
+		return nil
+	}
+
+	var blocking []*quotas.Shortfall
+	for _, shortfall := range shortfalls {
+		if shortfall.Blocking {
+			blocking = append(blocking, shortfall)
+		} else {
+			klog.Warningf("quota may not allow the cluster to scale to its maximum size: %s", shortfall)
+		}
+	}
+
+	if c.RequestQuotaIncreases {
+		if c.TargetName != TargetDirect {
+			klog.Warningf("not requesting quota increases in dry-run mode")
+		} else {
+			// Several shortfalls of a quota, e.g. in different zones, are covered by a single request of the largest value
+			requests := make(map[*quotas.Quota]*quotas.Shortfall)
+			var order []*quotas.Quota
+			for _, shortfall := range shortfalls {
+				if !shortfall.Requestable() {
+					continue
+				}
+				if existing := requests[shortfall.Quota]; existing == nil {
+					order = append(order, shortfall.Quota)
+					requests[shortfall.Quota] = shortfall
+				} else if shortfall.DesiredValue() > existing.DesiredValue() {
+					requests[shortfall.Quota] = shortfall
+				}
+			}
+			for _, quota := range order {
+				shortfall := requests[quota]
+				requested, err := checker.RequestIncrease(shortfall)
+				if err != nil {
+					return err
+				}
+				if requested {
+					klog.Infof("requested an increase of quota %s to %v", quota, shortfall.DesiredValue())
+				} else {
+					klog.Infof("an increase of quota %s was already requested", quota)
+				}
+			}
+		}
+	}
+
+	if len(blocking) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d quotas do not cover the cluster at its minimum size:", len(blocking))
+	for _, shortfall := range blocking {
+		fmt.Fprintf(&b, "\n  * %s", shortfall)
+	}
+	if c.TargetName != TargetDirect {
+		klog.Warningf("%s", b.String())
+		return nil
+	}
+	b.WriteString("\nrequest the quota increases, or use --request-quota-increases, then update the cluster once they are granted; use --skip-quota-checks to update it anyway")
+	return fmt.Errorf("%s", b.String())
+}
+
 // upgradeSpecs ensures that fields are fully populated / defaulted
 func (c *ApplyClusterCmd) upgradeSpecs(assetBuilder *assets.AssetBuilder) error {
 	fullCluster, err := PopulateClusterSpec(c.Clientset, c.Cluster, c.Cloud, assetBuilder)
@@ -1049,13 +1313,19 @@ func (c *ApplyClusterCmd) addFileAssets(assetBuilder *assets.AssetBuilder) error
 			c.Assets[arch] = append(c.Assets[arch], mirrors.BuildMirroredAsset(lyftAsset, lyftAssetHash))
 		}
 
+		ssmAgentAssets, err := findSSMAgentAssets(c.Cluster, assetBuilder, arch)
+		if err != nil {
+			return err
+		}
+		c.Assets[arch] = append(c.Assets[arch], ssmAgentAssets...)
+
 		var containerRuntimeAssetUrl *url.URL
 		var containerRuntimeAssetHash *hashing.Hash
 		switch c.Cluster.Spec.ContainerRuntime {
 		case "docker":
 			containerRuntimeAssetUrl, containerRuntimeAssetHash, err = findDockerAsset(c.Cluster, assetBuilder, arch)
 		case "containerd":
-			containerRuntimeAssetUrl, containerRuntimeAssetHash, err = findContainerdAsset(c.Cluster, assetBuilder, arch)
+			containerRuntimeAssetUrl, containerRuntimeAssetHash, err = FindContainerdAsset(c.Cluster, assetBuilder, arch)
 		default:
 			err = fmt.Errorf("unknown container runtime: %q", c.Cluster.Spec.ContainerRuntime)
 		}
@@ -1071,6 +1341,62 @@ func (c *ApplyClusterCmd) addFileAssets(assetBuilder *assets.AssetBuilder) error
 		c.NodeUpAssets[arch] = asset
 	}
 
+	return c.addNodePluginArtifacts(assetBuilder)
+}
+
+// findSSMAgentAssets returns the pinned SSM agent packages of the architecture, installed by nodeup on images that do not include the agent
+func findSSMAgentAssets(cluster *kops.Cluster, assetBuilder *assets.AssetBuilder, arch architectures.Architecture) ([]*mirrors.MirroredAsset, error) {
+	if !apimodel.UseSessionManager(cluster) || kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		return nil, nil
+	}
+
+	var result []*mirrors.MirroredAsset
+	for _, pkg := range cluster.Spec.SessionManager.AgentPackages {
+		if pkg.Architecture != string(arch) {
+			continue
+		}
+		u, err := url.Parse(pkg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse SSM agent package URL %q: %v", pkg.URL, err)
+		}
+		h, err := hashing.FromString(pkg.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse SSM agent package hash %q: %v", pkg.Hash, err)
+		}
+		u, err = assetBuilder.RemapFileAndSHAValue(u, pkg.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("unable to remap SSM agent package: %v", err)
+		}
+		result = append(result, mirrors.BuildMirroredAsset(u, h))
+	}
+	return result, nil
+}
+
+// addNodePluginArtifacts adds the artifacts of the cluster's and instance groups' nodeup plugins within the assetBuilder
+func (c *ApplyClusterCmd) addNodePluginArtifacts(assetBuilder *assets.AssetBuilder) error {
+	plugins := append([]kops.NodePluginSpec{}, c.Cluster.Spec.NodePlugins...)
+	for _, ig := range c.InstanceGroups {
+		plugins = append(plugins, ig.Spec.NodePlugins...)
+	}
+
+	added := make(map[string]bool)
+	for _, plugin := range plugins {
+		for _, artifact := range plugin.Artifacts {
+			if added[artifact.Source] {
+				continue
+			}
+			added[artifact.Source] = true
+
+			u, err := url.Parse(artifact.Source)
+			if err != nil {
+				return fmt.Errorf("unable to parse artifact %q of nodeup plugin %q: %v", artifact.Source, plugin.Name, err)
+			}
+			if _, err := assetBuilder.RemapFileAndSHAValue(u, artifact.Hash); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -1119,9 +1445,12 @@ type nodeUpConfigBuilder struct {
 	protokubeAsset             map[architectures.Architecture][]*mirrors.MirroredAsset
 	channelsAsset              map[architectures.Architecture][]*mirrors.MirroredAsset
 	encryptionConfigSecretHash string
+	sshPublicKeys              [][]byte
+	sshCAPublicKeys            [][]byte
+	gossipSeeds                []string
 }
 
-func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBuilder, assets map[architectures.Architecture][]*mirrors.MirroredAsset, encryptionConfigSecretHash string) (model.NodeUpConfigBuilder, error) {
+func newNodeUpConfigBuilder(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, assetBuilder *assets.AssetBuilder, assets map[architectures.Architecture][]*mirrors.MirroredAsset, encryptionConfigSecretHash string, sshPublicKeys [][]byte, sshCAPublicKeys [][]byte) (model.NodeUpConfigBuilder, error) {
 	configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigBase)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing config base %q: %v", cluster.Spec.ConfigBase, err)
@@ -1253,6 +1582,16 @@ func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 		}
 	}
 
+	// Without a cloud API to discover them, protokube joins the gossip network through the control plane machines.
+	var gossipSeeds []string
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+		for _, ig := range instanceGroups {
+			if ig.IsMaster() && ig.Spec.Metal != nil {
+				gossipSeeds = append(gossipSeeds, ig.Spec.Metal.Hosts...)
+			}
+		}
+	}
+
 	configBuilder := nodeUpConfigBuilder{
 		assetBuilder:               assetBuilder,
 		assets:                     assets,
@@ -1264,6 +1603,9 @@ func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 		protokubeAsset:             protokubeAsset,
 		channelsAsset:              channelsAsset,
 		encryptionConfigSecretHash: encryptionConfigSecretHash,
+		sshPublicKeys:              sshPublicKeys,
+		sshCAPublicKeys:            sshCAPublicKeys,
+		gossipSeeds:                gossipSeeds,
 	}
 
 	return &configBuilder, nil
@@ -1366,7 +1708,8 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 		}
 	}
 
-	useConfigServer := featureflag.KopsControllerStateStore.Enabled() && (role != kops.InstanceGroupRoleMaster)
+	// Hybrid machines have no cloud identity with which to read the state store.
+	useConfigServer := (featureflag.KopsControllerStateStore.Enabled() && (role != kops.InstanceGroupRoleMaster)) || role == kops.InstanceGroupRoleHybrid
 	if useConfigServer {
 		baseURL := url.URL{
 			Scheme: "https",
@@ -1409,22 +1752,68 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 
 	config.Images = n.images[role]
 	config.Channels = n.channels
+	config.GossipSeeds = n.gossipSeeds
 	config.EtcdManifests = n.etcdManifests[role]
 
 	if cluster.Spec.ContainerRuntime == "containerd" {
 		if cluster.Spec.Containerd == nil {
 			cluster.Spec.Containerd = &kops.ContainerdConfig{}
 		}
-		config.ContainerdConfig = buildContainerdConfig(cluster)
+		config.ContainerdConfig = buildContainerdConfig(cluster, ig)
 	}
 
 	if ig.Spec.WarmPool != nil || cluster.Spec.WarmPool != nil {
 		config.WarmPoolImages = n.buildWarmPoolImages(ig)
 	}
 
+	if cluster.Spec.Assets != nil && cluster.Spec.Assets.ImageVerification != nil {
+		config.ImageVerification = n.buildImageVerification(cluster.Spec.Assets.ImageVerification)
+	}
+
+	config.NodePlugins = n.remapNodePluginArtifacts(config.NodePlugins)
+
+	config.SSH = n.buildSSHConfig(ig)
+
 	return config, bootConfig, nil
 }
 
+// buildSSHConfig returns the configuration of SSH access for nodeup, or nil if there is nothing beyond
+// the primary SSH public key to configure. The cloud only provides a single key to the instances,
+// so when there are several "admin" keys, or keys replaced by a rotation, nodeup authorizes all of them,
+// along with the SSH public keys of the cluster spec selected by the instance group.
+func (n *nodeUpConfigBuilder) buildSSHConfig(ig *kops.InstanceGroup) *nodeup.SSHConfig {
+	config := &nodeup.SSHConfig{}
+
+	var selectedKeys []string
+	if n.cluster.Spec.SSH != nil {
+		selected := sets.NewString(ig.Spec.SSHPublicKeys...)
+		for _, key := range n.cluster.Spec.SSH.PublicKeys {
+			if selected.Len() == 0 || selected.Has(key.Name) {
+				selectedKeys = append(selectedKeys, strings.TrimSpace(key.PublicKey))
+			}
+		}
+	}
+
+	if len(n.sshPublicKeys) > 1 || len(selectedKeys) != 0 {
+		for _, key := range n.sshPublicKeys {
+			config.AuthorizedKeys = append(config.AuthorizedKeys, strings.TrimSpace(string(key)))
+		}
+		config.AuthorizedKeys = append(config.AuthorizedKeys, selectedKeys...)
+	}
+	for _, key := range n.sshCAPublicKeys {
+		config.TrustedUserCAKeys = append(config.TrustedUserCAKeys, strings.TrimSpace(string(key)))
+	}
+	if n.cluster.Spec.SSH != nil {
+		config.User = n.cluster.Spec.SSH.User
+		config.InstanceConnect = fi.BoolValue(n.cluster.Spec.SSH.InstanceConnect)
+	}
+
+	if len(config.AuthorizedKeys) == 0 && len(config.TrustedUserCAKeys) == 0 && !config.InstanceConnect {
+		return nil
+	}
+	return config
+}
+
 func getTasksCertificate(caTasks map[string]*fitasks.Keypair, name string, config *nodeup.Config, includeKeypairID bool) error {
 	cas, err := fi.ResourceAsString(caTasks[name].Certificates())
 	if err != nil {
@@ -1438,7 +1827,7 @@ func getTasksCertificate(caTasks map[string]*fitasks.Keypair, name string, confi
 	return nil
 }
 
-func buildContainerdConfig(cluster *kops.Cluster) string {
+func buildContainerdConfig(cluster *kops.Cluster, ig *kops.InstanceGroup) string {
 	if cluster.Spec.ContainerRuntime != "containerd" {
 		return ""
 	}
@@ -1455,9 +1844,34 @@ func buildContainerdConfig(cluster *kops.Cluster) string {
 	for name, endpoints := range containerd.RegistryMirrors {
 		config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "registry", "mirrors", name, "endpoint"}, endpoints)
 	}
+	for name, registry := range containerd.Registries {
+		if len(registry.Mirrors) > 0 {
+			config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "registry", "mirrors", name, "endpoint"}, registry.Mirrors)
+		}
+	}
+	if containerd.SandboxImage != nil {
+		config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "sandbox_image"}, *containerd.SandboxImage)
+	}
 	config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes", "runc", "runtime_type"}, "io.containerd.runc.v2")
-	// only enable systemd cgroups for kubernetes >= 1.20
-	config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes", "runc", "options", "SystemdCgroup"}, cluster.IsKubernetesGTE("1.20"))
+	// only enable systemd cgroups for kubernetes >= 1.20, unless a cgroup driver is set
+	systemdCgroup := cluster.IsKubernetesGTE("1.20")
+	if containerd.CgroupDriver != nil {
+		systemdCgroup = *containerd.CgroupDriver == "systemd"
+	}
+	config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes", "runc", "options", "SystemdCgroup"}, systemdCgroup)
+	if nri := containerd.NRI; nri != nil && nri.Enabled {
+		config.SetPath([]string{"plugins", "io.containerd.nri.v1.nri", "disable"}, false)
+		if nri.PluginPath != nil {
+			config.SetPath([]string{"plugins", "io.containerd.nri.v1.nri", "plugin_path"}, *nri.PluginPath)
+		}
+		if nri.PluginConfigPath != nil {
+			config.SetPath([]string{"plugins", "io.containerd.nri.v1.nri", "plugin_config_path"}, *nri.PluginConfigPath)
+		}
+	}
+	if ig != nil && ig.Spec.SELinux != nil {
+		// Label the containers, so that the SELinux policy applies to them
+		config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "enable_selinux"}, true)
+	}
 	if components.UsesKubenet(cluster.Spec.Networking) {
 		// Using containerd with Kubenet requires special configuration.
 		// This is a temporary backwards-compatible solution for kubenet users and will be deprecated when Kubenet is deprecated:
@@ -1467,6 +1881,70 @@ func buildContainerdConfig(cluster *kops.Cluster) string {
 	return config.String()
 }
 
+// remapNodePluginArtifacts returns the plugins with their artifacts' sources replaced by the locations
+// the asset builder remapped them to.
+func (n *nodeUpConfigBuilder) remapNodePluginArtifacts(plugins []kops.NodePluginSpec) []kops.NodePluginSpec {
+	if n.assetBuilder == nil {
+		return plugins
+	}
+
+	downloadURLs := make(map[string]string)
+	for _, fileAsset := range n.assetBuilder.FileAssets {
+		if fileAsset.CanonicalURL != nil && fileAsset.DownloadURL != nil {
+			downloadURLs[fileAsset.CanonicalURL.String()] = fileAsset.DownloadURL.String()
+		}
+	}
+
+	var remapped []kops.NodePluginSpec
+	for _, plugin := range plugins {
+		artifacts := make([]kops.NodePluginArtifact, 0, len(plugin.Artifacts))
+		for _, artifact := range plugin.Artifacts {
+			if u, err := url.Parse(artifact.Source); err == nil {
+				if downloadURL, found := downloadURLs[u.String()]; found {
+					artifact.Source = downloadURL
+				}
+			}
+			artifacts = append(artifacts, artifact)
+		}
+		plugin.Artifacts = artifacts
+		remapped = append(remapped, plugin)
+	}
+	return remapped
+}
+
+// buildImageVerification returns the configuration for verifying all the container images used by the cluster.
+// Every node verifies every image, as pods of any component may be scheduled on it.
+func (n *nodeUpConfigBuilder) buildImageVerification(spec *kops.ImageVerificationSpec) *nodeup.ImageVerificationConfig {
+	config := &nodeup.ImageVerificationConfig{
+		Digests:          map[string]string{},
+		CosignPublicKeys: spec.CosignPublicKeys,
+		Action:           spec.Action,
+	}
+	if config.Action == "" {
+		config.Action = kops.ImageVerificationActionEnforce
+	}
+
+	images := map[string]bool{}
+	if n.assetBuilder != nil {
+		for _, image := range n.assetBuilder.ImageAssets {
+			images[image.DownloadLocation] = true
+			// Digests are pinned by canonical name, but copied images keep their digest.
+			if digest := spec.Digests[image.CanonicalLocation]; digest != "" {
+				config.Digests[image.DownloadLocation] = digest
+			}
+			if digest := spec.Digests[image.DownloadLocation]; digest != "" {
+				config.Digests[image.DownloadLocation] = digest
+			}
+		}
+	}
+	for image := range images {
+		config.Images = append(config.Images, image)
+	}
+	sort.Strings(config.Images)
+
+	return config
+}
+
 // buildWarmPoolImages returns a list of container images that should be pre-pulled during instance pre-initialization
 func (n *nodeUpConfigBuilder) buildWarmPoolImages(ig *kops.InstanceGroup) []string {
 	if ig == nil || ig.Spec.Role == kops.InstanceGroupRoleMaster {
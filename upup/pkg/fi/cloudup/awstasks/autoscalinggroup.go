@@ -52,10 +52,16 @@ type AutoscalingGroup struct {
 	LaunchTemplate *LaunchTemplate
 	// LoadBalancers is a list of elastic load balancer names to add to the autoscaling group
 	LoadBalancers []*ClassicLoadBalancer
+	// MaxHealthyPercentage is the percentage of the desired capacity that can be in service while instances
+	// are replaced, or -1 when the asg has no instance maintenance policy
+	MaxHealthyPercentage *int64
 	// MaxSize is the max number of nodes in asg
 	MaxSize *int64
 	// Metrics is a collection of metrics to monitor
 	Metrics []string
+	// MinHealthyPercentage is the percentage of the desired capacity that stays healthy while instances
+	// are replaced, or -1 when the asg has no instance maintenance policy
+	MinHealthyPercentage *int64
 	// MinSize is the smallest number of nodes in the asg
 	MinSize *int64
 	// MixedInstanceOverrides is a collection of instance types to use with fleet policy
@@ -83,8 +89,12 @@ type AutoscalingGroup struct {
 	SuspendProcesses *[]string
 	// Tags is a collection of keypairs to apply to the node on launch
 	Tags map[string]string
+	// NonPropagatedTags is a collection of keypairs to apply to the Autoscaling Group only, not to the nodes it launches
+	NonPropagatedTags map[string]string
 	// TargetGroups is a list of ALB/NLB target group ARNs to add to the autoscaling group
 	TargetGroups []*TargetGroup
+	// TerminationPolicies are the policies choosing the instances to terminate on scale in, in order of precedence
+	TerminationPolicies []string
 }
 
 var _ fi.CompareWithID = &AutoscalingGroup{}
@@ -94,6 +104,15 @@ func (e *AutoscalingGroup) CompareWithID() *string {
 	return e.Name
 }
 
+var _ fi.InstanceChangeClassifier = &AutoscalingGroup{}
+
+// RequiresInstanceReplacement returns true for the fields of the mixed instances policy, such as the instance types
+// and the split of On-Demand and Spot Instances, as they only apply to the instances launched afterwards.
+// The other changes to the ASG, such as its size, tags and suspended processes, are applied to the existing instances.
+func (e *AutoscalingGroup) RequiresInstanceReplacement(fieldName string) bool {
+	return strings.HasPrefix(fieldName, "Mixed")
+}
+
 // Find is used to discover the ASG in the cloud provider
 func (e *AutoscalingGroup) Find(c *fi.Context) (*AutoscalingGroup, error) {
 	cloud := c.Cloud.(awsup.AWSCloud)
@@ -194,6 +213,13 @@ func (e *AutoscalingGroup) Find(c *fi.Context) (*AutoscalingGroup, error) {
 			if strings.HasPrefix(aws.StringValue(tag.Key), "aws:cloudformation:") {
 				continue
 			}
+			if !aws.BoolValue(tag.PropagateAtLaunch) {
+				if actual.NonPropagatedTags == nil {
+					actual.NonPropagatedTags = make(map[string]string)
+				}
+				actual.NonPropagatedTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+				continue
+			}
 			actual.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
 		}
 	}
@@ -241,6 +267,10 @@ func (e *AutoscalingGroup) Find(c *fi.Context) (*AutoscalingGroup, error) {
 	}
 
 	actual.SuspendProcesses = &processes
+	if e.SuspendProcesses != nil && len(processCompare(e.SuspendProcesses, &processes)) == 0 && len(processCompare(&processes, e.SuspendProcesses)) == 0 {
+		// The order of the suspended processes is not significant
+		actual.SuspendProcesses = e.SuspendProcesses
+	}
 
 	// Avoid spurious changes
 	actual.Lifecycle = e.Lifecycle
@@ -249,9 +279,43 @@ func (e *AutoscalingGroup) Find(c *fi.Context) (*AutoscalingGroup, error) {
 		actual.InstanceProtection = g.NewInstancesProtectedFromScaleIn
 	}
 
+	if len(g.TerminationPolicies) != 0 {
+		actual.TerminationPolicies = aws.StringValueSlice(g.TerminationPolicies)
+	}
+	if len(e.TerminationPolicies) == 0 && reflect.DeepEqual(actual.TerminationPolicies, []string{terminationPolicyDefault}) {
+		// Autoscaling groups created without termination policies report the default policy
+		actual.TerminationPolicies = nil
+	}
+
+	// The policy was recorded from the response that findAutoscalingGroup read the group from
+	if policy := cloud.GetInstanceMaintenancePolicy(fi.StringValue(g.AutoScalingGroupName)); policy != nil {
+		actual.MinHealthyPercentage = fi.Int64(policy.MinHealthyPercentage)
+		actual.MaxHealthyPercentage = fi.Int64(policy.MaxHealthyPercentage)
+	} else {
+		actual.MinHealthyPercentage = fi.Int64(awsup.NoInstanceMaintenancePolicy)
+		actual.MaxHealthyPercentage = fi.Int64(awsup.NoInstanceMaintenancePolicy)
+	}
+
 	return actual, nil
 }
 
+// instanceMaintenancePolicy returns the instance maintenance policy of the autoscaling group, or nil if it has none
+func (e *AutoscalingGroup) instanceMaintenancePolicy() *awsup.InstanceMaintenancePolicy {
+	if e.MinHealthyPercentage == nil || e.MaxHealthyPercentage == nil {
+		return nil
+	}
+	if *e.MinHealthyPercentage < 0 || *e.MaxHealthyPercentage < 0 {
+		return nil
+	}
+	return &awsup.InstanceMaintenancePolicy{
+		MinHealthyPercentage: *e.MinHealthyPercentage,
+		MaxHealthyPercentage: *e.MaxHealthyPercentage,
+	}
+}
+
+// terminationPolicyDefault is the termination policy of autoscaling groups that do not set any
+const terminationPolicyDefault = "Default"
+
 // findAutoscalingGroup is responsible for finding all the autoscaling groups for us
 func findAutoscalingGroup(cloud awsup.AWSCloud, name string) (*autoscaling.Group, error) {
 	request := &autoscaling.DescribeAutoScalingGroupsInput{
@@ -331,6 +395,7 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 			MaxSize:                          e.MaxSize,
 			NewInstancesProtectedFromScaleIn: e.InstanceProtection,
 			Tags:                             v.AutoscalingGroupTags(),
+			TerminationPolicies:              aws.StringSlice(e.TerminationPolicies),
 			VPCZoneIdentifier:                fi.String(strings.Join(e.AutoscalingGroupSubnets(), ",")),
 		}
 
@@ -396,6 +461,13 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 			return fmt.Errorf("error creating AutoScalingGroup: %s", message)
 		}
 
+		// @step: set the instance maintenance policy, which the vendored sdk cannot send on creation
+		if policy := e.instanceMaintenancePolicy(); policy != nil {
+			if err := t.Cloud.SetInstanceMaintenancePolicy(fi.StringValue(e.Name), policy); err != nil {
+				return fmt.Errorf("error setting instance maintenance policy for AutoscalingGroup: %v", err)
+			}
+		}
+
 		// @step: attempt to enable the metrics for us
 		if _, err := t.Cloud.Autoscaling().EnableMetricsCollection(&autoscaling.EnableMetricsCollectionInput{
 			AutoScalingGroupName: e.Name,
@@ -408,7 +480,7 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 		if len(*e.SuspendProcesses) > 0 {
 			toSuspend := []*string{}
 			for _, p := range *e.SuspendProcesses {
-				toSuspend = append(toSuspend, &p)
+				toSuspend = append(toSuspend, fi.String(p))
 			}
 
 			processQuery := &autoscaling.ScalingProcessQuery{}
@@ -502,15 +574,16 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 
 		var updateTagsRequest *autoscaling.CreateOrUpdateTagsInput
 		var deleteTagsRequest *autoscaling.DeleteTagsInput
-		if changes.Tags != nil {
+		if changes.Tags != nil || changes.NonPropagatedTags != nil {
 			updateTagsRequest = &autoscaling.CreateOrUpdateTagsInput{Tags: e.AutoscalingGroupTags()}
 
-			if a != nil && len(a.Tags) > 0 {
+			if a != nil && (len(a.Tags) > 0 || len(a.NonPropagatedTags) > 0) {
 				deleteTagsRequest = &autoscaling.DeleteTagsInput{}
-				deleteTagsRequest.Tags = e.getASGTagsToDelete(a.Tags)
+				deleteTagsRequest.Tags = append(e.getASGTagsToDelete(a.Tags), e.getASGTagsToDelete(a.NonPropagatedTags)...)
 			}
 
 			changes.Tags = nil
+			changes.NonPropagatedTags = nil
 		}
 
 		var attachLBRequest *autoscaling.AttachLoadBalancersInput
@@ -598,6 +671,22 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 			changes.InstanceProtection = nil
 		}
 
+		// Removing the termination policies restores the default policy
+		if !reflect.DeepEqual(a.TerminationPolicies, e.TerminationPolicies) {
+			request.TerminationPolicies = aws.StringSlice(e.TerminationPolicies)
+			if len(e.TerminationPolicies) == 0 {
+				request.TerminationPolicies = aws.StringSlice([]string{terminationPolicyDefault})
+			}
+			changes.TerminationPolicies = nil
+		}
+
+		setInstanceMaintenancePolicy := false
+		if changes.MinHealthyPercentage != nil || changes.MaxHealthyPercentage != nil {
+			setInstanceMaintenancePolicy = true
+			changes.MinHealthyPercentage = nil
+			changes.MaxHealthyPercentage = nil
+		}
+
 		empty := &AutoscalingGroup{}
 		if !reflect.DeepEqual(empty, changes) {
 			klog.Warningf("cannot apply changes to AutoScalingGroup: %v", changes)
@@ -609,6 +698,12 @@ func (v *AutoscalingGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Autos
 			return fmt.Errorf("error updating AutoscalingGroup: %v", err)
 		}
 
+		if setInstanceMaintenancePolicy {
+			if err := t.Cloud.SetInstanceMaintenancePolicy(fi.StringValue(e.Name), e.instanceMaintenancePolicy()); err != nil {
+				return fmt.Errorf("error setting instance maintenance policy for AutoscalingGroup: %v", err)
+			}
+		}
+
 		if deleteTagsRequest != nil && len(deleteTagsRequest.Tags) > 0 {
 			if _, err := t.Cloud.Autoscaling().DeleteTags(deleteTagsRequest); err != nil {
 				return fmt.Errorf("error deleting old AutoscalingGroup tags: %v", err)
@@ -686,6 +781,15 @@ func (e *AutoscalingGroup) AutoscalingGroupTags() []*autoscaling.Tag {
 			PropagateAtLaunch: aws.Bool(true),
 		})
 	}
+	for k, v := range e.NonPropagatedTags {
+		list = append(list, &autoscaling.Tag{
+			Key:               aws.String(k),
+			Value:             aws.String(v),
+			ResourceId:        e.Name,
+			ResourceType:      aws.String("auto-scaling-group"),
+			PropagateAtLaunch: aws.Bool(false),
+		})
+	}
 
 	return list
 }
@@ -748,7 +852,11 @@ func (e *AutoscalingGroup) getASGTagsToDelete(currentTags map[string]string) []*
 	tagsToDelete := []*autoscaling.Tag{}
 
 	for k, v := range currentTags {
-		if _, ok := e.Tags[k]; !ok {
+		_, ok := e.Tags[k]
+		if !ok {
+			_, ok = e.NonPropagatedTags[k]
+		}
+		if !ok {
 			tagsToDelete = append(tagsToDelete, &autoscaling.Tag{
 				Key:          aws.String(k),
 				Value:        aws.String(v),
@@ -850,21 +958,28 @@ type terraformMixedInstancesPolicy struct {
 	InstanceDistribution []*terraformAutoscalingInstanceDistribution `json:"instances_distribution,omitempty" cty:"instances_distribution"`
 }
 
+type terraformAutoscalingInstanceMaintenancePolicy struct {
+	MinHealthyPercentage *int64 `json:"min_healthy_percentage,omitempty" cty:"min_healthy_percentage"`
+	MaxHealthyPercentage *int64 `json:"max_healthy_percentage,omitempty" cty:"max_healthy_percentage"`
+}
+
 type terraformAutoscalingGroup struct {
-	Name                    *string                                          `json:"name,omitempty" cty:"name"`
-	LaunchConfigurationName *terraformWriter.Literal                         `json:"launch_configuration,omitempty" cty:"launch_configuration"`
-	LaunchTemplate          *terraformAutoscalingLaunchTemplateSpecification `json:"launch_template,omitempty" cty:"launch_template"`
-	MaxSize                 *int64                                           `json:"max_size,omitempty" cty:"max_size"`
-	MinSize                 *int64                                           `json:"min_size,omitempty" cty:"min_size"`
-	MixedInstancesPolicy    []*terraformMixedInstancesPolicy                 `json:"mixed_instances_policy,omitempty" cty:"mixed_instances_policy"`
-	VPCZoneIdentifier       []*terraformWriter.Literal                       `json:"vpc_zone_identifier,omitempty" cty:"vpc_zone_identifier"`
-	Tags                    []*terraformASGTag                               `json:"tag,omitempty" cty:"tag"`
-	MetricsGranularity      *string                                          `json:"metrics_granularity,omitempty" cty:"metrics_granularity"`
-	EnabledMetrics          []*string                                        `json:"enabled_metrics,omitempty" cty:"enabled_metrics"`
-	SuspendedProcesses      []*string                                        `json:"suspended_processes,omitempty" cty:"suspended_processes"`
-	InstanceProtection      *bool                                            `json:"protect_from_scale_in,omitempty" cty:"protect_from_scale_in"`
-	LoadBalancers           []*terraformWriter.Literal                       `json:"load_balancers,omitempty" cty:"load_balancers"`
-	TargetGroupARNs         []*terraformWriter.Literal                       `json:"target_group_arns,omitempty" cty:"target_group_arns"`
+	Name                      *string                                          `json:"name,omitempty" cty:"name"`
+	LaunchConfigurationName   *terraformWriter.Literal                         `json:"launch_configuration,omitempty" cty:"launch_configuration"`
+	LaunchTemplate            *terraformAutoscalingLaunchTemplateSpecification `json:"launch_template,omitempty" cty:"launch_template"`
+	MaxSize                   *int64                                           `json:"max_size,omitempty" cty:"max_size"`
+	MinSize                   *int64                                           `json:"min_size,omitempty" cty:"min_size"`
+	MixedInstancesPolicy      []*terraformMixedInstancesPolicy                 `json:"mixed_instances_policy,omitempty" cty:"mixed_instances_policy"`
+	VPCZoneIdentifier         []*terraformWriter.Literal                       `json:"vpc_zone_identifier,omitempty" cty:"vpc_zone_identifier"`
+	Tags                      []*terraformASGTag                               `json:"tag,omitempty" cty:"tag"`
+	MetricsGranularity        *string                                          `json:"metrics_granularity,omitempty" cty:"metrics_granularity"`
+	EnabledMetrics            []*string                                        `json:"enabled_metrics,omitempty" cty:"enabled_metrics"`
+	SuspendedProcesses        []*string                                        `json:"suspended_processes,omitempty" cty:"suspended_processes"`
+	InstanceProtection        *bool                                            `json:"protect_from_scale_in,omitempty" cty:"protect_from_scale_in"`
+	LoadBalancers             []*terraformWriter.Literal                       `json:"load_balancers,omitempty" cty:"load_balancers"`
+	TargetGroupARNs           []*terraformWriter.Literal                       `json:"target_group_arns,omitempty" cty:"target_group_arns"`
+	TerminationPolicies       []*string                                        `json:"termination_policies,omitempty" cty:"termination_policies"`
+	InstanceMaintenancePolicy []*terraformAutoscalingInstanceMaintenancePolicy `json:"instance_maintenance_policy,omitempty" cty:"instance_maintenance_policy"`
 }
 
 // RenderTerraform is responsible for rendering the terraform codebase
@@ -877,6 +992,17 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 		EnabledMetrics:     aws.StringSlice(e.Metrics),
 		InstanceProtection: e.InstanceProtection,
 	}
+	if len(e.TerminationPolicies) != 0 {
+		tf.TerminationPolicies = aws.StringSlice(e.TerminationPolicies)
+	}
+	if policy := e.instanceMaintenancePolicy(); policy != nil {
+		tf.InstanceMaintenancePolicy = []*terraformAutoscalingInstanceMaintenancePolicy{
+			{
+				MinHealthyPercentage: fi.Int64(policy.MinHealthyPercentage),
+				MaxHealthyPercentage: fi.Int64(policy.MaxHealthyPercentage),
+			},
+		}
+	}
 
 	for _, s := range e.Subnets {
 		tf.VPCZoneIdentifier = append(tf.VPCZoneIdentifier, s.TerraformLink())
@@ -890,6 +1016,14 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 			PropagateAtLaunch: fi.Bool(true),
 		})
 	}
+	for _, k := range maps.SortedKeys(e.NonPropagatedTags) {
+		v := e.NonPropagatedTags[k]
+		tf.Tags = append(tf.Tags, &terraformASGTag{
+			Key:               fi.String(k),
+			Value:             fi.String(v),
+			PropagateAtLaunch: fi.Bool(false),
+		})
+	}
 
 	for _, k := range e.LoadBalancers {
 		tf.LoadBalancers = append(tf.LoadBalancers, k.TerraformLink())
@@ -1043,18 +1177,25 @@ type cloudformationMixedInstancesPolicy struct {
 	InstanceDistribution *cloudformationAutoscalingInstanceDistribution `json:"InstancesDistribution,omitempty"`
 }
 
+type cloudformationAutoscalingInstanceMaintenancePolicy struct {
+	MinHealthyPercentage *int64 `json:"MinHealthyPercentage,omitempty"`
+	MaxHealthyPercentage *int64 `json:"MaxHealthyPercentage,omitempty"`
+}
+
 type cloudformationAutoscalingGroup struct {
-	Name                    *string                                               `json:"AutoScalingGroupName,omitempty"`
-	LaunchConfigurationName *cloudformation.Literal                               `json:"LaunchConfigurationName,omitempty"`
-	LaunchTemplate          *cloudformationAutoscalingLaunchTemplateSpecification `json:"LaunchTemplate,omitempty"`
-	MaxSize                 *string                                               `json:"MaxSize,omitempty"`
-	MinSize                 *string                                               `json:"MinSize,omitempty"`
-	VPCZoneIdentifier       []*cloudformation.Literal                             `json:"VPCZoneIdentifier,omitempty"`
-	Tags                    []*cloudformationASGTag                               `json:"Tags,omitempty"`
-	MetricsCollection       []*cloudformationASGMetricsCollection                 `json:"MetricsCollection,omitempty"`
-	MixedInstancesPolicy    *cloudformationMixedInstancesPolicy                   `json:"MixedInstancesPolicy,omitempty"`
-	LoadBalancerNames       []*cloudformation.Literal                             `json:"LoadBalancerNames,omitempty"`
-	TargetGroupARNs         []*cloudformation.Literal                             `json:"TargetGroupARNs,omitempty"`
+	Name                      *string                                               `json:"AutoScalingGroupName,omitempty"`
+	LaunchConfigurationName   *cloudformation.Literal                               `json:"LaunchConfigurationName,omitempty"`
+	LaunchTemplate            *cloudformationAutoscalingLaunchTemplateSpecification `json:"LaunchTemplate,omitempty"`
+	MaxSize                   *string                                               `json:"MaxSize,omitempty"`
+	MinSize                   *string                                               `json:"MinSize,omitempty"`
+	VPCZoneIdentifier         []*cloudformation.Literal                             `json:"VPCZoneIdentifier,omitempty"`
+	Tags                      []*cloudformationASGTag                               `json:"Tags,omitempty"`
+	MetricsCollection         []*cloudformationASGMetricsCollection                 `json:"MetricsCollection,omitempty"`
+	MixedInstancesPolicy      *cloudformationMixedInstancesPolicy                   `json:"MixedInstancesPolicy,omitempty"`
+	LoadBalancerNames         []*cloudformation.Literal                             `json:"LoadBalancerNames,omitempty"`
+	TargetGroupARNs           []*cloudformation.Literal                             `json:"TargetGroupARNs,omitempty"`
+	TerminationPolicies       []*string                                             `json:"TerminationPolicies,omitempty"`
+	InstanceMaintenancePolicy *cloudformationAutoscalingInstanceMaintenancePolicy   `json:"InstanceMaintenancePolicy,omitempty"`
 }
 
 // RenderCloudformation is responsible for generating the cloudformation template
@@ -1070,6 +1211,15 @@ func (_ *AutoscalingGroup) RenderCloudformation(t *cloudformation.Cloudformation
 			},
 		},
 	}
+	if len(e.TerminationPolicies) != 0 {
+		cf.TerminationPolicies = aws.StringSlice(e.TerminationPolicies)
+	}
+	if policy := e.instanceMaintenancePolicy(); policy != nil {
+		cf.InstanceMaintenancePolicy = &cloudformationAutoscalingInstanceMaintenancePolicy{
+			MinHealthyPercentage: fi.Int64(policy.MinHealthyPercentage),
+			MaxHealthyPercentage: fi.Int64(policy.MaxHealthyPercentage),
+		}
+	}
 
 	if e.UseMixedInstancesPolicy() {
 		cf.MixedInstancesPolicy = &cloudformationMixedInstancesPolicy{
@@ -1113,6 +1263,14 @@ func (_ *AutoscalingGroup) RenderCloudformation(t *cloudformation.Cloudformation
 			PropagateAtLaunch: fi.Bool(true),
 		})
 	}
+	for _, k := range maps.SortedKeys(e.NonPropagatedTags) {
+		v := e.NonPropagatedTags[k]
+		cf.Tags = append(cf.Tags, &cloudformationASGTag{
+			Key:               fi.String(k),
+			Value:             fi.String(v),
+			PropagateAtLaunch: fi.Bool(false),
+		})
+	}
 
 	for _, k := range e.LoadBalancers {
 		cf.LoadBalancerNames = append(cf.LoadBalancerNames, k.CloudformationLink())
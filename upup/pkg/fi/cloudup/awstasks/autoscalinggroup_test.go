@@ -35,6 +35,9 @@ func TestGetASGTagsToDelete(t *testing.T) {
 			"KubernetesCluster": "MyCluster",
 			"Name":              "nodes.cluster.k8s.local",
 		},
+		NonPropagatedTags: map[string]string{
+			"CostCenter": "infra",
+		},
 	}
 
 	cases := []struct {
@@ -61,6 +64,12 @@ func TestGetASGTagsToDelete(t *testing.T) {
 			},
 			ExpectedTagsToDelete: []*autoscaling.Tag{},
 		},
+		{
+			CurrentTags: map[string]string{
+				"CostCenter": "infra",
+			},
+			ExpectedTagsToDelete: []*autoscaling.Tag{},
+		},
 		{
 			CurrentTags: map[string]string{
 				"KubernetesCluster": "MyCluster",
@@ -319,6 +328,94 @@ resource "aws_autoscaling_group" "test1" {
   vpc_zone_identifier = [aws_subnet.test-sg.id]
 }
 
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.34.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &AutoscalingGroup{
+				Name:                fi.String("test2"),
+				InstanceProtection:  fi.Bool(true),
+				LaunchTemplate:      &LaunchTemplate{Name: fi.String("test_lt")},
+				MaxSize:             fi.Int64(10),
+				MinSize:             fi.Int64(1),
+				TerminationPolicies: []string{"OldestLaunchTemplate", "AllocationStrategy"},
+				Subnets: []*Subnet{
+					{
+						Name: fi.String("test-sg"),
+						ID:   fi.String("sg-1111"),
+					},
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_autoscaling_group" "test2" {
+  launch_template {
+    id      = aws_launch_template.test_lt.id
+    version = aws_launch_template.test_lt.latest_version
+  }
+  max_size              = 10
+  min_size              = 1
+  name                  = "test2"
+  protect_from_scale_in = true
+  termination_policies  = ["OldestLaunchTemplate", "AllocationStrategy"]
+  vpc_zone_identifier   = [aws_subnet.test-sg.id]
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.34.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &AutoscalingGroup{
+				Name:                 fi.String("test3"),
+				LaunchTemplate:       &LaunchTemplate{Name: fi.String("test_lt")},
+				MaxHealthyPercentage: fi.Int64(110),
+				MaxSize:              fi.Int64(10),
+				MinHealthyPercentage: fi.Int64(100),
+				MinSize:              fi.Int64(1),
+				Subnets: []*Subnet{
+					{
+						Name: fi.String("test-sg"),
+						ID:   fi.String("sg-1111"),
+					},
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_autoscaling_group" "test3" {
+  instance_maintenance_policy {
+    max_healthy_percentage = 110
+    min_healthy_percentage = 100
+  }
+  launch_template {
+    id      = aws_launch_template.test_lt.id
+    version = aws_launch_template.test_lt.latest_version
+  }
+  max_size            = 10
+  min_size            = 1
+  name                = "test3"
+  vpc_zone_identifier = [aws_subnet.test-sg.id]
+}
+
 terraform {
   required_version = ">= 0.12.26"
   required_providers {
@@ -427,3 +524,20 @@ func TestAutoscalingGroupCloudformationRender(t *testing.T) {
 
 	doRenderTests(t, "RenderCloudformation", cases)
 }
+
+func TestAutoscalingGroupRequiresInstanceReplacement(t *testing.T) {
+	grid := map[string]bool{
+		"MinSize":                     false,
+		"Tags":                        false,
+		"SuspendProcesses":            false,
+		"MixedInstanceOverrides":      true,
+		"MixedOnDemandAboveBase":      true,
+		"MixedSpotAllocationStrategy": true,
+	}
+	asg := &AutoscalingGroup{}
+	for fieldName, expected := range grid {
+		if actual := asg.RequiresInstanceReplacement(fieldName); actual != expected {
+			t.Errorf("expected RequiresInstanceReplacement(%q) to be %v, got %v", fieldName, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+)
+
+// EBSEncryptionByDefault ensures that EBS encryption by default is turned on in the region of the cluster.
+// It is an account-wide setting, so it is never turned off again by kOps.
+// +kops:fitask
+type EBSEncryptionByDefault struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	// Enabled is whether EBS encryption by default is turned on
+	Enabled *bool
+	// Manage is whether kOps may turn on EBS encryption by default, rather than only verifying it
+	Manage *bool
+}
+
+func (e *EBSEncryptionByDefault) Find(c *fi.Context) (*EBSEncryptionByDefault, error) {
+	cloud := c.Cloud.(awsup.AWSCloud)
+
+	response, err := cloud.EC2().GetEbsEncryptionByDefault(&ec2.GetEbsEncryptionByDefaultInput{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting EBS encryption by default: %v", err)
+	}
+
+	actual := &EBSEncryptionByDefault{
+		Name:      e.Name,
+		Lifecycle: e.Lifecycle,
+		Enabled:   response.EbsEncryptionByDefault,
+		Manage:    e.Manage,
+	}
+
+	return actual, nil
+}
+
+func (e *EBSEncryptionByDefault) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *EBSEncryptionByDefault) CheckChanges(a, e, changes *EBSEncryptionByDefault) error {
+	if a != nil && !fi.BoolValue(a.Enabled) && fi.BoolValue(e.Enabled) && !fi.BoolValue(e.Manage) {
+		return fmt.Errorf("EBS encryption by default is not turned on in the region of the cluster; " +
+			"turn it on with \"aws ec2 enable-ebs-encryption-by-default --region <region>\" " +
+			"or set spec.cloudConfig.ebsDefaultEncryption.enable to let kOps turn it on")
+	}
+	return nil
+}
+
+func (_ *EBSEncryptionByDefault) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *EBSEncryptionByDefault) error {
+	return e.enable(t.Cloud, changes)
+}
+
+// RenderTerraform turns EBS encryption by default on through the API rather than rendering an
+// aws_ebs_encryption_by_default resource: as the setting is region-wide, destroying the resource with the cluster
+// would turn it off for all the other users of the account.
+func (_ *EBSEncryptionByDefault) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *EBSEncryptionByDefault) error {
+	return e.enable(t.Cloud.(awsup.AWSCloud), changes)
+}
+
+// RenderCloudformation turns EBS encryption by default on through the API, as CloudFormation has no resource for it.
+func (_ *EBSEncryptionByDefault) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *EBSEncryptionByDefault) error {
+	return e.enable(t.Cloud.(awsup.AWSCloud), changes)
+}
+
+// enable turns EBS encryption by default on, if it is off; CheckChanges has already refused to go on
+// if kOps may not turn it on
+func (e *EBSEncryptionByDefault) enable(cloud awsup.AWSCloud, changes *EBSEncryptionByDefault) error {
+	if changes.Enabled == nil || !fi.BoolValue(e.Enabled) {
+		return nil
+	}
+
+	klog.Infof("Turning on EBS encryption by default in region %q", cloud.Region())
+	_, err := cloud.EC2().EnableEbsEncryptionByDefault(&ec2.EnableEbsEncryptionByDefaultInput{})
+	if err != nil {
+		return fmt.Errorf("error turning on EBS encryption by default: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+func TestEBSEncryptionByDefault(t *testing.T) {
+	grid := []struct {
+		Enabled         bool
+		Manage          bool
+		ExpectedEnabled bool
+		ExpectedError   string
+	}{
+		{
+			Enabled:         true,
+			ExpectedEnabled: true,
+		},
+		{
+			Enabled:         false,
+			ExpectedEnabled: false,
+			ExpectedError:   "EBS encryption by default is not turned on",
+		},
+		{
+			Enabled:         false,
+			Manage:          true,
+			ExpectedEnabled: true,
+		},
+		{
+			Enabled:         true,
+			Manage:          true,
+			ExpectedEnabled: true,
+		},
+	}
+
+	for _, g := range grid {
+		cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+		c := &mockec2.MockEC2{EbsEncryptionByDefault: g.Enabled}
+		cloud.MockEC2 = c
+
+		allTasks := map[string]fi.Task{
+			"ebs-encryption-by-default": &EBSEncryptionByDefault{
+				Name:      s("ebs-encryption-by-default"),
+				Lifecycle: fi.LifecycleSync,
+				Enabled:   fi.Bool(true),
+				Manage:    fi.Bool(g.Manage),
+			},
+		}
+
+		target := &awsup.AWSAPITarget{
+			Cloud: cloud,
+		}
+
+		context, err := fi.NewContext(context.TODO(), target, nil, cloud, nil, nil, nil, true, allTasks)
+		if err != nil {
+			t.Fatalf("error building context: %v", err)
+		}
+
+		err = context.RunTasks(testRunTasksOptions)
+		context.Close()
+		if g.ExpectedError == "" {
+			if err != nil {
+				t.Errorf("unexpected error from RunTasks with enabled=%v manage=%v: %v", g.Enabled, g.Manage, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), g.ExpectedError) {
+			t.Errorf("expected error containing %q from RunTasks with enabled=%v manage=%v, got %v", g.ExpectedError, g.Enabled, g.Manage, err)
+		}
+
+		if c.EbsEncryptionByDefault != g.ExpectedEnabled {
+			t.Errorf("expected EBS encryption by default to be %v with enabled=%v manage=%v, was %v", g.ExpectedEnabled, g.Enabled, g.Manage, c.EbsEncryptionByDefault)
+		}
+	}
+}
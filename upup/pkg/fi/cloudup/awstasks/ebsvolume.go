@@ -44,6 +44,10 @@ type EBSVolume struct {
 	VolumeIops       *int64
 	VolumeThroughput *int64
 	VolumeType       *string
+
+	// EncryptionDefaulted is set when Encrypted and KmsKeyId come from the cluster-wide EBS encryption defaults.
+	// Existing volumes then keep their encryption, as it cannot be changed in place.
+	EncryptionDefaulted bool
 }
 
 var _ fi.CompareWithID = &EBSVolume{}
@@ -100,21 +104,26 @@ func (e *EBSVolume) find(cloud awsup.AWSCloud) (*EBSVolume, error) {
 	klog.V(2).Info("found existing volume")
 	v := response.Volumes[0]
 	actual := &EBSVolume{
-		ID:               v.VolumeId,
-		AvailabilityZone: v.AvailabilityZone,
-		VolumeType:       v.VolumeType,
-		SizeGB:           v.Size,
-		KmsKeyId:         v.KmsKeyId,
-		Encrypted:        v.Encrypted,
-		Name:             e.Name,
-		VolumeIops:       v.Iops,
-		VolumeThroughput: v.Throughput,
+		ID:                  v.VolumeId,
+		AvailabilityZone:    v.AvailabilityZone,
+		VolumeType:          v.VolumeType,
+		SizeGB:              v.Size,
+		KmsKeyId:            v.KmsKeyId,
+		Encrypted:           v.Encrypted,
+		Name:                e.Name,
+		VolumeIops:          v.Iops,
+		VolumeThroughput:    v.Throughput,
+		EncryptionDefaulted: e.EncryptionDefaulted,
 	}
 
 	actual.Tags = mapEC2TagsToMap(v.Tags)
 
 	// Avoid spurious changes
 	actual.Lifecycle = e.Lifecycle
+	if e.EncryptionDefaulted {
+		e.Encrypted = actual.Encrypted
+		e.KmsKeyId = actual.KmsKeyId
+	}
 
 	return actual, nil
 }
@@ -122,6 +122,25 @@ func (e *ElasticIP) find(cloud awsup.AWSCloud) (*ElasticIP, error) {
 		klog.V(2).Infof("Found public IP via tag: %v", *publicIP)
 	}
 
+	// Find via the Name and cluster tags, for ElasticIPs that are not related to another resource,
+	// or whose NatGateway has been deleted, such as while the cluster is hibernated
+	if allocationID == nil && publicIP == nil && e.TagOnSubnet == nil && e.Name != nil {
+		response, err := cloud.EC2().DescribeAddresses(&ec2.DescribeAddressesInput{
+			Filters: cloud.BuildFilters(e.Name),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing ElasticIPs: %v", err)
+		}
+		if response == nil || len(response.Addresses) == 0 {
+			return nil, nil
+		}
+		if len(response.Addresses) != 1 {
+			return nil, fmt.Errorf("found multiple ElasticIPs with name %q", *e.Name)
+		}
+		allocationID = response.Addresses[0].AllocationId
+		klog.V(2).Infof("Found ElasticIP AllocationID %q via tags", aws.StringValue(allocationID))
+	}
+
 	if publicIP != nil || allocationID != nil {
 		request := &ec2.DescribeAddressesInput{}
 		if allocationID != nil {
@@ -54,6 +54,8 @@ type LaunchTemplate struct {
 	InstanceInterruptionBehavior *string
 	// InstanceMonitoring indicates if monitoring is enabled
 	InstanceMonitoring *bool
+	// InstanceTags are additional keypairs to apply to the instance on launch; keys also in Tags are ignored.
+	InstanceTags map[string]string
 	// InstanceType is the type of instance we are using
 	InstanceType *string
 	// Ipv6AddressCount is the number of IPv6 addresses to assign with the primary network interface.
@@ -86,12 +88,16 @@ type LaunchTemplate struct {
 	Tenancy *string
 	// UserData is the user data configuration
 	UserData fi.Resource
+	// VolumeTags are additional keypairs to apply to the volumes on launch; keys also in Tags are ignored.
+	VolumeTags map[string]string
 }
 
 var (
 	_ fi.CompareWithID     = &LaunchTemplate{}
 	_ fi.ProducesDeletions = &LaunchTemplate{}
 	_ fi.Deletion          = &deleteLaunchTemplate{}
+
+	_ fi.InstanceChangeClassifier = &LaunchTemplate{}
 )
 
 // CompareWithID implements the comparable interface
@@ -99,6 +105,12 @@ func (t *LaunchTemplate) CompareWithID() *string {
 	return t.ID
 }
 
+// RequiresInstanceReplacement returns true for all fields, as any change creates a new version of the launch template,
+// which only applies to new instances.
+func (t *LaunchTemplate) RequiresInstanceReplacement(fieldName string) bool {
+	return true
+}
+
 // buildRootDevice is responsible for retrieving a boot device mapping from the image name
 func (t *LaunchTemplate) buildRootDevice(cloud awsup.AWSCloud) (map[string]*BlockDeviceMapping, error) {
 	image := fi.StringValue(t.ImageID)
@@ -140,6 +152,51 @@ func (t *LaunchTemplate) Run(c *fi.Context) error {
 	return fi.DefaultDeltaRunMethod(t, c)
 }
 
+// instanceTags returns the tags to apply to the instances on launch
+func (t *LaunchTemplate) instanceTags() map[string]string {
+	return mergeLaunchTags(t.Tags, t.InstanceTags)
+}
+
+// volumeTags returns the tags to apply to the volumes on launch
+func (t *LaunchTemplate) volumeTags() map[string]string {
+	return mergeLaunchTags(t.Tags, t.VolumeTags)
+}
+
+// mergeLaunchTags adds the extra tags to the tags of the launch template, which take precedence
+func mergeLaunchTags(tags map[string]string, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return tags
+	}
+	merged := make(map[string]string)
+	for k, v := range extra {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return merged
+}
+
+// splitLaunchTags splits the tags applied on launch into the tags of the launch template and the extra tags
+func splitLaunchTags(launchTags map[string]string, tags map[string]string, extra map[string]string) (map[string]string, map[string]string) {
+	var baseTags, extraTags map[string]string
+	for k, v := range launchTags {
+		_, isBase := tags[k]
+		if _, isExtra := extra[k]; isExtra && !isBase {
+			if extraTags == nil {
+				extraTags = make(map[string]string)
+			}
+			extraTags[k] = v
+		} else {
+			if baseTags == nil {
+				baseTags = make(map[string]string)
+			}
+			baseTags[k] = v
+		}
+	}
+	return baseTags, extraTags
+}
+
 // Normalize is responsible for normalizing any data within the resource
 func (t *LaunchTemplate) Normalize() {
 	sort.Stable(OrderSecurityGroupsById(t.SecurityGroups))
@@ -101,19 +101,18 @@ func (t *LaunchTemplate) RenderAWS(c *awsup.AWSAPITarget, a, e, changes *LaunchT
 	// @step: add the tags
 	var tags []*ec2.Tag
 	if len(t.Tags) > 0 {
-		for k, v := range t.Tags {
-			tags = append(tags, &ec2.Tag{
-				Key:   aws.String(k),
-				Value: aws.String(v),
-			})
-		}
+		tags = mapToEC2Tags(t.Tags)
+	}
+	if instanceTags := t.instanceTags(); len(instanceTags) > 0 {
 		data.TagSpecifications = append(data.TagSpecifications, &ec2.LaunchTemplateTagSpecificationRequest{
 			ResourceType: aws.String(ec2.ResourceTypeInstance),
-			Tags:         tags,
+			Tags:         mapToEC2Tags(instanceTags),
 		})
+	}
+	if volumeTags := t.volumeTags(); len(volumeTags) > 0 {
 		data.TagSpecifications = append(data.TagSpecifications, &ec2.LaunchTemplateTagSpecificationRequest{
 			ResourceType: aws.String(ec2.ResourceTypeVolume),
-			Tags:         tags,
+			Tags:         mapToEC2Tags(volumeTags),
 		})
 	}
 	// @step: add the userdata
@@ -291,13 +290,24 @@ func (t *LaunchTemplate) Find(c *fi.Context) (*LaunchTemplate, error) {
 		actual.UserData = fi.NewStringResource(string(ud))
 	}
 
-	// @step: add tags
-	if len(lt.LaunchTemplateData.TagSpecifications) > 0 {
-		ts := lt.LaunchTemplateData.TagSpecifications[0]
-		if ts.Tags != nil {
-			tags := mapEC2TagsToMap(ts.Tags)
-			actual.Tags = tags
+	// @step: add tags, separating the tags of the launch template from the extra tags of the instances and volumes
+	var volumeTags map[string]string
+	for _, ts := range lt.LaunchTemplateData.TagSpecifications {
+		switch aws.StringValue(ts.ResourceType) {
+		case ec2.ResourceTypeInstance:
+			actual.Tags, actual.InstanceTags = splitLaunchTags(mapEC2TagsToMap(ts.Tags), t.Tags, t.InstanceTags)
+		case ec2.ResourceTypeVolume:
+			volumeTags = mapEC2TagsToMap(ts.Tags)
+		}
+	}
+	for k, v := range volumeTags {
+		if tv, found := actual.Tags[k]; found && tv == v {
+			continue
+		}
+		if actual.VolumeTags == nil {
+			actual.VolumeTags = make(map[string]string)
 		}
+		actual.VolumeTags[k] = v
 	}
 
 	// @step: add instance metadata options
@@ -304,15 +304,16 @@ func (t *LaunchTemplate) RenderCloudformation(target *cloudformation.Cloudformat
 		})
 	}
 
-	if e.Tags != nil {
-		tags := buildCloudformationTags(t.Tags)
+	if instanceTags := e.instanceTags(); instanceTags != nil {
 		data.TagSpecifications = append(data.TagSpecifications, &cloudformationLaunchTemplateTagSpecification{
 			ResourceType: fi.String("instance"),
-			Tags:         tags,
+			Tags:         buildCloudformationTags(instanceTags),
 		})
+	}
+	if volumeTags := e.volumeTags(); volumeTags != nil {
 		data.TagSpecifications = append(data.TagSpecifications, &cloudformationLaunchTemplateTagSpecification{
 			ResourceType: fi.String("volume"),
-			Tags:         tags,
+			Tags:         buildCloudformationTags(volumeTags),
 		})
 	}
 
@@ -312,15 +312,19 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 		})
 	}
 
-	if e.Tags != nil {
+	if instanceTags := e.instanceTags(); instanceTags != nil {
 		tf.TagSpecifications = append(tf.TagSpecifications, &terraformLaunchTemplateTagSpecification{
 			ResourceType: fi.String("instance"),
-			Tags:         e.Tags,
+			Tags:         instanceTags,
 		})
+	}
+	if volumeTags := e.volumeTags(); volumeTags != nil {
 		tf.TagSpecifications = append(tf.TagSpecifications, &terraformLaunchTemplateTagSpecification{
 			ResourceType: fi.String("volume"),
-			Tags:         e.Tags,
+			Tags:         volumeTags,
 		})
+	}
+	if e.Tags != nil {
 		tf.Tags = e.Tags
 	}
 
@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLaunchTemplateLaunchTags(t *testing.T) {
+	lt := &LaunchTemplate{
+		Tags:         map[string]string{"Name": "nodes", "team": "platform"},
+		InstanceTags: map[string]string{"cost-center": "1234", "team": "payments"},
+		VolumeTags:   map[string]string{"backup": "daily"},
+	}
+
+	instanceTags := lt.instanceTags()
+	expectedInstanceTags := map[string]string{"Name": "nodes", "team": "platform", "cost-center": "1234"}
+	if !reflect.DeepEqual(instanceTags, expectedInstanceTags) {
+		t.Errorf("unexpected instance tags %v", instanceTags)
+	}
+	volumeTags := lt.volumeTags()
+	expectedVolumeTags := map[string]string{"Name": "nodes", "team": "platform", "backup": "daily"}
+	if !reflect.DeepEqual(volumeTags, expectedVolumeTags) {
+		t.Errorf("unexpected volume tags %v", volumeTags)
+	}
+
+	tags, extra := splitLaunchTags(instanceTags, lt.Tags, lt.InstanceTags)
+	if !reflect.DeepEqual(tags, lt.Tags) {
+		t.Errorf("unexpected launch template tags %v", tags)
+	}
+	if !reflect.DeepEqual(extra, map[string]string{"cost-center": "1234"}) {
+		t.Errorf("unexpected extra tags %v", extra)
+	}
+
+	// Without extra tags, the instances and volumes get the tags of the launch template
+	lt = &LaunchTemplate{Tags: map[string]string{"Name": "nodes"}}
+	if !reflect.DeepEqual(lt.instanceTags(), lt.Tags) || !reflect.DeepEqual(lt.volumeTags(), lt.Tags) {
+		t.Errorf("unexpected tags %v %v", lt.instanceTags(), lt.volumeTags())
+	}
+}
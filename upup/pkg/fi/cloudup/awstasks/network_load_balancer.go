@@ -459,8 +459,8 @@ func (s *NetworkLoadBalancer) CheckChanges(a, e, changes *NetworkLoadBalancer) e
 			expectedSubnets := make(map[string]*string)
 			for _, s := range e.SubnetMappings {
 				//expectedSubnets[*s.Subnet.ID] = s
-				if s.AllocationID != nil {
-					expectedSubnets[*s.Subnet.ID] = s.AllocationID
+				if s.allocationID() != nil {
+					expectedSubnets[*s.Subnet.ID] = s.allocationID()
 				}
 				if s.PrivateIPv4Address != nil {
 					expectedSubnets[*s.Subnet.ID] = s.PrivateIPv4Address
@@ -511,7 +511,7 @@ func (_ *NetworkLoadBalancer) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Ne
 		for _, subnetMapping := range e.SubnetMappings {
 			request.SubnetMappings = append(request.SubnetMappings, &elbv2.SubnetMapping{
 				SubnetId:           subnetMapping.Subnet.ID,
-				AllocationId:       subnetMapping.AllocationID,
+				AllocationId:       subnetMapping.allocationID(),
 				PrivateIPv4Address: subnetMapping.PrivateIPv4Address,
 			})
 		}
@@ -585,12 +585,12 @@ func (_ *NetworkLoadBalancer) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *Ne
 			hasChanges := false
 			for _, s := range e.SubnetMappings {
 				aIP, ok := actualSubnets[*s.Subnet.ID]
-				if !ok || (fi.StringValue(s.PrivateIPv4Address) != fi.StringValue(aIP) && fi.StringValue(s.AllocationID) != fi.StringValue(aIP)) {
+				if !ok || (fi.StringValue(s.PrivateIPv4Address) != fi.StringValue(aIP) && fi.StringValue(s.allocationID()) != fi.StringValue(aIP)) {
 					hasChanges = true
 				}
 				awsSubnetMappings = append(awsSubnetMappings, &elbv2.SubnetMapping{
 					SubnetId:           s.Subnet.ID,
-					AllocationId:       s.AllocationID,
+					AllocationId:       s.allocationID(),
 					PrivateIPv4Address: s.PrivateIPv4Address,
 				})
 			}
@@ -672,7 +672,7 @@ type terraformNetworkLoadBalancer struct {
 
 type terraformNetworkLoadBalancerSubnetMapping struct {
 	Subnet             *terraformWriter.Literal `json:"subnet_id" cty:"subnet_id"`
-	AllocationID       *string                  `json:"allocation_id,omitempty" cty:"allocation_id"`
+	AllocationID       *terraformWriter.Literal `json:"allocation_id,omitempty" cty:"allocation_id"`
 	PrivateIPv4Address *string                  `json:"private_ipv4_address,omitempty" cty:"private_ipv4_address"`
 }
 
@@ -702,7 +702,7 @@ func (_ *NetworkLoadBalancer) RenderTerraform(t *terraform.TerraformTarget, a, e
 	for _, subnetMapping := range e.SubnetMappings {
 		nlbTF.SubnetMappings = append(nlbTF.SubnetMappings, terraformNetworkLoadBalancerSubnetMapping{
 			Subnet:             subnetMapping.Subnet.TerraformLink(),
-			AllocationID:       subnetMapping.AllocationID,
+			AllocationID:       subnetMapping.terraformAllocationID(),
 			PrivateIPv4Address: subnetMapping.PrivateIPv4Address,
 		})
 	}
@@ -769,7 +769,7 @@ type cloudformationNetworkLoadBalancer struct {
 
 type cloudformationSubnetMapping struct {
 	Subnet             *cloudformation.Literal `json:"SubnetId"`
-	AllocationId       *string                 `json:"AllocationId,omitempty"`
+	AllocationId       *cloudformation.Literal `json:"AllocationId,omitempty"`
 	PrivateIPv4Address *string                 `json:"PrivateIPv4Address,omitempty"`
 }
 
@@ -800,7 +800,7 @@ func (_ *NetworkLoadBalancer) RenderCloudformation(t *cloudformation.Cloudformat
 	for _, subnetMapping := range e.SubnetMappings {
 		nlbCF.SubnetMappings = append(nlbCF.SubnetMappings, &cloudformationSubnetMapping{
 			Subnet:             subnetMapping.Subnet.CloudformationLink(),
-			AllocationId:       subnetMapping.AllocationID,
+			AllocationId:       subnetMapping.cloudformationAllocationID(),
 			PrivateIPv4Address: subnetMapping.PrivateIPv4Address,
 		})
 	}
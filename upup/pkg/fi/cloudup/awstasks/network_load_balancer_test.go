@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestNetworkLoadBalancerTerraformRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &NetworkLoadBalancer{
+				Name:             fi.String("bastion"),
+				LoadBalancerName: fi.String("bastion-test"),
+				SubnetMappings: []*SubnetMapping{
+					{
+						Subnet:       &Subnet{Name: fi.String("utility-a")},
+						AllocationID: fi.String("eipalloc-1"),
+					},
+					{
+						Subnet:    &Subnet{Name: fi.String("utility-b")},
+						ElasticIP: &ElasticIP{Name: fi.String("bastion.utility-b")},
+					},
+				},
+				CrossZoneLoadBalancing: fi.Bool(true),
+				Tags:                   map[string]string{"Name": "bastion"},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_lb" "bastion" {
+  enable_cross_zone_load_balancing = true
+  internal                         = false
+  load_balancer_type               = "network"
+  name                             = "bastion-test"
+  subnet_mapping {
+    allocation_id = "eipalloc-1"
+    subnet_id     = aws_subnet.utility-a.id
+  }
+  subnet_mapping {
+    allocation_id = aws_eip.bastion-utility-b.id
+    subnet_id     = aws_subnet.utility-b.id
+  }
+  tags = {
+    "Name" = "bastion"
+  }
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.34.0"
+    }
+  }
+}
+`,
+		},
+	}
+
+	doRenderTests(t, "RenderTerraform", cases)
+}
@@ -18,7 +18,10 @@ package awstasks
 
 import (
 	"k8s.io/klog/v2"
+
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
 
 type SubnetMapping struct {
@@ -28,6 +31,37 @@ type SubnetMapping struct {
 	PrivateIPv4Address *string
 	// AllocationID only valid for NLBs
 	AllocationID *string
+	// ElasticIP is the Elastic IP allocated by kOps for the subnet, used when AllocationID is not set.
+	// Only valid for NLBs
+	ElasticIP *ElasticIP
+}
+
+// allocationID returns the ID of the Elastic IP of the subnet mapping, if any.
+func (e *SubnetMapping) allocationID() *string {
+	if e.AllocationID == nil && e.ElasticIP != nil {
+		return e.ElasticIP.ID
+	}
+	return e.AllocationID
+}
+
+func (e *SubnetMapping) terraformAllocationID() *terraformWriter.Literal {
+	if e.AllocationID == nil && e.ElasticIP != nil {
+		return e.ElasticIP.TerraformLink()
+	}
+	if e.AllocationID == nil {
+		return nil
+	}
+	return terraformWriter.LiteralFromStringValue(*e.AllocationID)
+}
+
+func (e *SubnetMapping) cloudformationAllocationID() *cloudformation.Literal {
+	if e.AllocationID == nil && e.ElasticIP != nil {
+		return e.ElasticIP.CloudformationAllocationID()
+	}
+	if e.AllocationID == nil {
+		return nil
+	}
+	return cloudformation.LiteralString(*e.AllocationID)
 }
 
 // OrderSubnetsById implements sort.Interface for []Subnet, based on ID
@@ -42,8 +76,8 @@ func (a OrderSubnetMappingsByID) Less(i, j int) bool {
 		if a[i].PrivateIPv4Address != nil && a[j].PrivateIPv4Address != nil {
 			return fi.StringValue(a[i].PrivateIPv4Address) < fi.StringValue(a[j].PrivateIPv4Address)
 		}
-		if a[i].AllocationID != nil && a[j].AllocationID != nil {
-			return fi.StringValue(a[i].AllocationID) < fi.StringValue(a[j].AllocationID)
+		if a[i].allocationID() != nil && a[j].allocationID() != nil {
+			return fi.StringValue(a[i].allocationID()) < fi.StringValue(a[j].allocationID())
 		}
 	}
 	return v1 < v2
@@ -74,7 +108,7 @@ func subnetMappingSlicesEqualIgnoreOrder(l, r []*SubnetMapping) bool {
 		if fi.StringValue(s.PrivateIPv4Address) != fi.StringValue(s2.PrivateIPv4Address) {
 			return false
 		}
-		if fi.StringValue(s.AllocationID) != fi.StringValue(s2.AllocationID) {
+		if fi.StringValue(s.allocationID()) != fi.StringValue(s2.allocationID()) {
 			return false
 		}
 	}
@@ -87,6 +121,9 @@ func (e *SubnetMapping) GetDependencies(tasks map[string]fi.Task) []fi.Task {
 		if _, ok := task.(*Subnet); ok {
 			deps = append(deps, task)
 		}
+		if _, ok := task.(*ElasticIP); ok && e.ElasticIP != nil {
+			deps = append(deps, task)
+		}
 	}
 	return deps
 }
@@ -39,6 +39,20 @@ func mapEC2TagsToMap(tags []*ec2.Tag) map[string]string {
 	return m
 }
 
+func mapToEC2Tags(tags map[string]string) []*ec2.Tag {
+	if tags == nil {
+		return nil
+	}
+	m := make([]*ec2.Tag, 0)
+	for k, v := range tags {
+		m = append(m, &ec2.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		})
+	}
+	return m
+}
+
 func mapIAMTagsToMap(tags []*iam.Tag) map[string]string {
 	if tags == nil {
 		return nil
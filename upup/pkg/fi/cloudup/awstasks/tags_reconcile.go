@@ -0,0 +1,306 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// resourceTypeAutoScalingGroup is the resource type of the tag changes of autoscaling groups
+const resourceTypeAutoScalingGroup = "auto-scaling-group"
+
+// describeTagsBatchSize is the number of resources whose tags are described at once
+const describeTagsBatchSize = 200
+
+// TagChange is a set of tags to add to, or to update on, an existing resource
+type TagChange struct {
+	// ResourceType is the EC2 resource type, or auto-scaling-group
+	ResourceType string
+	// ID is the ID of the resource, or the name of the autoscaling group
+	ID string
+	// Name is the value of the Name tag of the resource
+	Name string
+	// Tags are the tags that are missing or have a different value
+	Tags map[string]string
+}
+
+// tagTarget identifies the resources that should have some tags
+type tagTarget struct {
+	resourceType string
+	name         string
+}
+
+// FindTagChanges compares the tags of the tasks with those of the existing EC2 resources and autoscaling groups
+// owned by the cluster, without running the tasks. The resources are matched by type and Name tag; the instances and
+// volumes launched from a launch template are matched by the Name tag they get from the launch template.
+// Tags that are not set by the tasks are left alone.
+func FindTagChanges(cloud awsup.AWSCloud, clusterName string, tasks map[string]fi.Task) ([]*TagChange, error) {
+	desired := make(map[tagTarget]map[string]string)
+	add := func(resourceType string, name *string, shared *bool, tags map[string]string) {
+		if fi.BoolValue(shared) || len(tags) == 0 {
+			return
+		}
+		n := tags["Name"]
+		if n == "" {
+			n = fi.StringValue(name)
+		}
+		desired[tagTarget{resourceType: resourceType, name: n}] = tags
+	}
+	for _, task := range tasks {
+		switch t := task.(type) {
+		case *AutoscalingGroup:
+			// The Name of an autoscaling group is its ID, it doesn't have a Name tag of its own
+			if len(t.Tags) != 0 {
+				desired[tagTarget{resourceType: resourceTypeAutoScalingGroup, name: fi.StringValue(t.Name)}] = t.Tags
+			}
+		case *DHCPOptions:
+			add(ec2.ResourceTypeDhcpOptions, t.Name, t.Shared, t.Tags)
+		case *EBSVolume:
+			add(ec2.ResourceTypeVolume, t.Name, nil, t.Tags)
+		case *ElasticIP:
+			add(ec2.ResourceTypeElasticIp, t.Name, t.Shared, t.Tags)
+		case *InternetGateway:
+			add(ec2.ResourceTypeInternetGateway, t.Name, t.Shared, t.Tags)
+		case *LaunchTemplate:
+			add(ec2.ResourceTypeLaunchTemplate, t.Name, nil, t.Tags)
+			add(ec2.ResourceTypeInstance, t.Name, nil, t.instanceTags())
+			add(ec2.ResourceTypeVolume, t.Name, nil, t.volumeTags())
+		case *NatGateway:
+			add(ec2.ResourceTypeNatgateway, t.Name, t.Shared, t.Tags)
+		case *RouteTable:
+			add(ec2.ResourceTypeRouteTable, t.Name, t.Shared, t.Tags)
+		case *SecurityGroup:
+			add(ec2.ResourceTypeSecurityGroup, t.Name, t.Shared, t.Tags)
+		case *Subnet:
+			add(ec2.ResourceTypeSubnet, t.Name, t.Shared, t.Tags)
+		case *VPC:
+			add(ec2.ResourceTypeVpc, t.Name, t.Shared, t.Tags)
+		}
+	}
+
+	ownershipTag := "kubernetes.io/cluster/" + clusterName
+
+	var changes []*TagChange
+
+	ec2Tags, ec2Types, err := describeOwnedEC2Tags(cloud, ownershipTag)
+	if err != nil {
+		return nil, err
+	}
+	for id, tags := range ec2Tags {
+		change := buildTagChange(ec2Types[id], id, tags, desired)
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	asgTags, err := describeOwnedAutoscalingGroupTags(cloud, ownershipTag)
+	if err != nil {
+		return nil, err
+	}
+	for name, tags := range asgTags {
+		change := buildTagChange(resourceTypeAutoScalingGroup, name, tags, desired)
+		if change != nil {
+			change.Name = name
+			changes = append(changes, change)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].ResourceType != changes[j].ResourceType {
+			return changes[i].ResourceType < changes[j].ResourceType
+		}
+		return changes[i].ID < changes[j].ID
+	})
+	return changes, nil
+}
+
+// buildTagChange returns the tags of the resource that don't have their desired value, or nil
+func buildTagChange(resourceType string, id string, actual map[string]string, desired map[tagTarget]map[string]string) *TagChange {
+	name := actual["Name"]
+	if resourceType == resourceTypeAutoScalingGroup {
+		name = id
+	}
+	expected, found := desired[tagTarget{resourceType: resourceType, name: name}]
+	if !found {
+		klog.V(4).Infof("no task found for %s %s (%s)", resourceType, id, name)
+		return nil
+	}
+
+	change := &TagChange{
+		ResourceType: resourceType,
+		ID:           id,
+		Name:         actual["Name"],
+	}
+	for k, v := range expected {
+		if value, found := actual[k]; found && value == v {
+			continue
+		}
+		if change.Tags == nil {
+			change.Tags = make(map[string]string)
+		}
+		change.Tags[k] = v
+	}
+	if len(change.Tags) == 0 {
+		return nil
+	}
+	return change
+}
+
+// ApplyTagChanges adds the tags of the changes to the resources
+func ApplyTagChanges(cloud awsup.AWSCloud, changes []*TagChange) error {
+	for _, change := range changes {
+		klog.V(2).Infof("tagging %s %s with %v", change.ResourceType, change.ID, change.Tags)
+		if change.ResourceType == resourceTypeAutoScalingGroup {
+			request := &autoscaling.CreateOrUpdateTagsInput{}
+			for k, v := range change.Tags {
+				request.Tags = append(request.Tags, &autoscaling.Tag{
+					Key:               aws.String(k),
+					Value:             aws.String(v),
+					ResourceId:        aws.String(change.ID),
+					ResourceType:      aws.String(resourceTypeAutoScalingGroup),
+					PropagateAtLaunch: aws.Bool(true),
+				})
+			}
+			if _, err := cloud.Autoscaling().CreateOrUpdateTags(request); err != nil {
+				return fmt.Errorf("error tagging autoscaling group %q: %v", change.ID, err)
+			}
+			continue
+		}
+
+		if err := cloud.CreateTags(change.ID, change.Tags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeOwnedEC2Tags returns the tags and the types of the EC2 resources owned by the cluster, by ID
+func describeOwnedEC2Tags(cloud awsup.AWSCloud, ownershipTag string) (map[string]map[string]string, map[string]string, error) {
+	types := make(map[string]string)
+	err := describeEC2Tags(cloud, &ec2.DescribeTagsInput{
+		Filters: []*ec2.Filter{awsup.NewEC2Filter("key", ownershipTag)},
+	}, func(tag *ec2.TagDescription) {
+		if aws.StringValue(tag.Value) == "owned" {
+			types[aws.StringValue(tag.ResourceId)] = aws.StringValue(tag.ResourceType)
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ids []string
+	for id := range types {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	tags := make(map[string]map[string]string)
+	for len(ids) != 0 {
+		batch := ids
+		if len(batch) > describeTagsBatchSize {
+			batch = batch[:describeTagsBatchSize]
+		}
+		ids = ids[len(batch):]
+
+		err := describeEC2Tags(cloud, &ec2.DescribeTagsInput{
+			Filters: []*ec2.Filter{awsup.NewEC2Filter("resource-id", batch...)},
+		}, func(tag *ec2.TagDescription) {
+			id := aws.StringValue(tag.ResourceId)
+			if tags[id] == nil {
+				tags[id] = make(map[string]string)
+			}
+			tags[id][aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return tags, types, nil
+}
+
+func describeEC2Tags(cloud awsup.AWSCloud, request *ec2.DescribeTagsInput, fn func(tag *ec2.TagDescription)) error {
+	for {
+		response, err := cloud.EC2().DescribeTags(request)
+		if err != nil {
+			return fmt.Errorf("error listing tags: %v", err)
+		}
+		for _, tag := range response.Tags {
+			fn(tag)
+		}
+		if aws.StringValue(response.NextToken) == "" {
+			return nil
+		}
+		request.NextToken = response.NextToken
+	}
+}
+
+// describeOwnedAutoscalingGroupTags returns the tags of the autoscaling groups owned by the cluster, by name
+func describeOwnedAutoscalingGroupTags(cloud awsup.AWSCloud, ownershipTag string) (map[string]map[string]string, error) {
+	var names []string
+	err := cloud.Autoscaling().DescribeTagsPages(&autoscaling.DescribeTagsInput{
+		Filters: []*autoscaling.Filter{
+			{Name: aws.String("key"), Values: []*string{aws.String(ownershipTag)}},
+		},
+	}, func(page *autoscaling.DescribeTagsOutput, lastPage bool) bool {
+		for _, tag := range page.Tags {
+			if aws.StringValue(tag.Value) == "owned" {
+				names = append(names, aws.StringValue(tag.ResourceId))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing autoscaling group tags: %v", err)
+	}
+	sort.Strings(names)
+
+	tags := make(map[string]map[string]string)
+	for len(names) != 0 {
+		batch := names
+		if len(batch) > describeTagsBatchSize {
+			batch = batch[:describeTagsBatchSize]
+		}
+		names = names[len(batch):]
+
+		err := cloud.Autoscaling().DescribeTagsPages(&autoscaling.DescribeTagsInput{
+			Filters: []*autoscaling.Filter{
+				{Name: aws.String("auto-scaling-group"), Values: aws.StringSlice(batch)},
+			},
+		}, func(page *autoscaling.DescribeTagsOutput, lastPage bool) bool {
+			for _, tag := range page.Tags {
+				name := aws.StringValue(tag.ResourceId)
+				if tags[name] == nil {
+					tags[name] = make(map[string]string)
+				}
+				tags[name][aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing autoscaling group tags: %v", err)
+		}
+	}
+	return tags, nil
+}
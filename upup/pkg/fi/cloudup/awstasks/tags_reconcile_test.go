@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+func TestFindAndApplyTagChanges(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+	a := &mockautoscaling.MockAutoscaling{}
+	cloud.MockAutoscaling = a
+
+	owned := &ec2.Tag{Key: aws.String("kubernetes.io/cluster/cluster.example.com"), Value: aws.String("owned")}
+	shared := &ec2.Tag{Key: aws.String("kubernetes.io/cluster/cluster.example.com"), Value: aws.String("shared")}
+	if _, err := c.CreateTags(&ec2.CreateTagsInput{
+		Resources: []*string{aws.String("sg-1")},
+		Tags:      []*ec2.Tag{owned, {Key: aws.String("Name"), Value: aws.String("nodes.cluster.example.com")}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateTags(&ec2.CreateTagsInput{
+		Resources: []*string{aws.String("vol-1")},
+		Tags:      []*ec2.Tag{owned, {Key: aws.String("Name"), Value: aws.String("nodes.cluster.example.com")}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateTags(&ec2.CreateTagsInput{
+		Resources: []*string{aws.String("subnet-1")},
+		Tags:      []*ec2.Tag{shared, {Key: aws.String("Name"), Value: aws.String("us-east-1a.cluster.example.com")}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.CreateAutoScalingGroup(&autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("nodes.cluster.example.com"),
+		Tags: []*autoscaling.Tag{
+			{
+				Key:          owned.Key,
+				Value:        owned.Value,
+				ResourceId:   aws.String("nodes.cluster.example.com"),
+				ResourceType: aws.String("auto-scaling-group"),
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := map[string]fi.Task{
+		"SecurityGroup/nodes.cluster.example.com": &SecurityGroup{
+			Name: aws.String("nodes.cluster.example.com"),
+			Tags: map[string]string{
+				"Name":        "nodes.cluster.example.com",
+				"cost-center": "1234",
+			},
+		},
+		"LaunchTemplate/nodes.cluster.example.com": &LaunchTemplate{
+			Name: aws.String("nodes.cluster.example.com"),
+			Tags: map[string]string{
+				"Name": "nodes.cluster.example.com",
+			},
+			VolumeTags: map[string]string{"backup": "daily"},
+		},
+		"Subnet/us-east-1a.cluster.example.com": &Subnet{
+			Name:   aws.String("us-east-1a.cluster.example.com"),
+			Shared: aws.Bool(true),
+			Tags: map[string]string{
+				"Name":        "us-east-1a.cluster.example.com",
+				"cost-center": "1234",
+			},
+		},
+		"AutoscalingGroup/nodes.cluster.example.com": &AutoscalingGroup{
+			Name: aws.String("nodes.cluster.example.com"),
+			Tags: map[string]string{
+				"kubernetes.io/cluster/cluster.example.com": "owned",
+				"team": "payments",
+			},
+		},
+	}
+
+	changes, err := FindTagChanges(cloud, "cluster.example.com", tasks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []*TagChange{
+		{
+			ResourceType: "auto-scaling-group",
+			ID:           "nodes.cluster.example.com",
+			Name:         "nodes.cluster.example.com",
+			Tags:         map[string]string{"team": "payments"},
+		},
+		{
+			ResourceType: ec2.ResourceTypeSecurityGroup,
+			ID:           "sg-1",
+			Name:         "nodes.cluster.example.com",
+			Tags:         map[string]string{"cost-center": "1234"},
+		},
+		{
+			ResourceType: ec2.ResourceTypeVolume,
+			ID:           "vol-1",
+			Name:         "nodes.cluster.example.com",
+			Tags:         map[string]string{"backup": "daily"},
+		},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		for _, change := range changes {
+			t.Logf("change: %+v", change)
+		}
+		t.Fatalf("unexpected changes")
+	}
+
+	if err := ApplyTagChanges(cloud, changes); err != nil {
+		t.Fatalf("unexpected error applying changes: %v", err)
+	}
+	changes, err = FindTagChanges(cloud, "cluster.example.com", tasks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes after applying them, got %d", len(changes))
+	}
+}
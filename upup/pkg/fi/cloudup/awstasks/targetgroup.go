@@ -18,6 +18,7 @@ package awstasks
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -47,8 +48,23 @@ type TargetGroup struct {
 
 	HealthyThreshold   *int64
 	UnhealthyThreshold *int64
+
+	// HealthCheckInterval is the time in seconds between the health checks of a target
+	HealthCheckInterval *int64
+	// HealthCheckPath is the path of HTTP and HTTPS health checks
+	HealthCheckPath *string
+	// HealthCheckPort is the port of the health checks, or traffic-port for the port of the target group
+	HealthCheckPort *string
+	// HealthCheckProtocol is the protocol of the health checks
+	HealthCheckProtocol *string
+
+	// DeregistrationDelay is the time in seconds the load balancer waits before deregistering a target
+	DeregistrationDelay *int64
 }
 
+// targetGroupAttributeDeregistrationDelay is the attribute holding the deregistration delay of a target group
+const targetGroupAttributeDeregistrationDelay = "deregistration_delay.timeout_seconds"
+
 var _ fi.CompareWithID = &TargetGroup{}
 
 func (e *TargetGroup) CompareWithID() *string {
@@ -92,9 +108,30 @@ func (e *TargetGroup) Find(c *fi.Context) (*TargetGroup, error) {
 		HealthyThreshold:   tg.HealthyThresholdCount,
 		UnhealthyThreshold: tg.UnhealthyThresholdCount,
 		VPC:                &VPC{ID: tg.VpcId},
+
+		HealthCheckInterval: tg.HealthCheckIntervalSeconds,
+		HealthCheckPath:     tg.HealthCheckPath,
+		HealthCheckPort:     tg.HealthCheckPort,
+		HealthCheckProtocol: tg.HealthCheckProtocol,
 	}
 	e.ARN = tg.TargetGroupArn
 
+	attributesResp, err := cloud.ELBV2().DescribeTargetGroupAttributes(&elbv2.DescribeTargetGroupAttributesInput{
+		TargetGroupArn: tg.TargetGroupArn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing attributes of targetgroup %s: %v", fi.StringValue(tg.TargetGroupName), err)
+	}
+	for _, attribute := range attributesResp.Attributes {
+		if fi.StringValue(attribute.Key) == targetGroupAttributeDeregistrationDelay {
+			delay, err := strconv.ParseInt(fi.StringValue(attribute.Value), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing deregistration delay %q of targetgroup %s: %v", fi.StringValue(attribute.Value), fi.StringValue(tg.TargetGroupName), err)
+			}
+			actual.DeregistrationDelay = fi.Int64(delay)
+		}
+	}
+
 	tagsResp, err := cloud.ELBV2().DescribeTags(&elbv2.DescribeTagsInput{
 		ResourceArns: []*string{tg.TargetGroupArn},
 	})
@@ -171,13 +208,17 @@ func (_ *TargetGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *TargetGrou
 
 	if a == nil {
 		request := &elbv2.CreateTargetGroupInput{
-			Name:                    e.Name,
-			Port:                    e.Port,
-			Protocol:                e.Protocol,
-			VpcId:                   e.VPC.ID,
-			HealthyThresholdCount:   e.HealthyThreshold,
-			UnhealthyThresholdCount: e.UnhealthyThreshold,
-			Tags:                    awsup.ELBv2Tags(e.Tags),
+			Name:                       e.Name,
+			Port:                       e.Port,
+			Protocol:                   e.Protocol,
+			VpcId:                      e.VPC.ID,
+			HealthyThresholdCount:      e.HealthyThreshold,
+			UnhealthyThresholdCount:    e.UnhealthyThreshold,
+			HealthCheckIntervalSeconds: e.HealthCheckInterval,
+			HealthCheckPath:            e.HealthCheckPath,
+			HealthCheckPort:            e.HealthCheckPort,
+			HealthCheckProtocol:        e.HealthCheckProtocol,
+			Tags:                       awsup.ELBv2Tags(e.Tags),
 		}
 
 		klog.V(2).Infof("Creating Target Group for NLB")
@@ -188,12 +229,60 @@ func (_ *TargetGroup) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *TargetGrou
 
 		targetGroupArn := *response.TargetGroups[0].TargetGroupArn
 		e.ARN = fi.String(targetGroupArn)
+
+		if e.DeregistrationDelay != nil {
+			if err := e.modifyDeregistrationDelay(t.Cloud); err != nil {
+				return err
+			}
+		}
 	} else {
 		if a.ARN != nil {
 			if err := t.AddELBV2Tags(fi.StringValue(a.ARN), e.Tags); err != nil {
 				return err
 			}
 		}
+
+		if changes.HealthyThreshold != nil || changes.UnhealthyThreshold != nil || changes.HealthCheckInterval != nil ||
+			changes.HealthCheckPath != nil || changes.HealthCheckPort != nil || changes.HealthCheckProtocol != nil {
+			request := &elbv2.ModifyTargetGroupInput{
+				TargetGroupArn:             a.ARN,
+				HealthyThresholdCount:      e.HealthyThreshold,
+				UnhealthyThresholdCount:    e.UnhealthyThreshold,
+				HealthCheckIntervalSeconds: e.HealthCheckInterval,
+				HealthCheckPath:            e.HealthCheckPath,
+				HealthCheckPort:            e.HealthCheckPort,
+				HealthCheckProtocol:        e.HealthCheckProtocol,
+			}
+
+			klog.V(2).Infof("Modifying health check of Target Group %q", fi.StringValue(e.Name))
+			if _, err := t.Cloud.ELBV2().ModifyTargetGroup(request); err != nil {
+				return fmt.Errorf("error modifying target group %q: %v", fi.StringValue(e.Name), err)
+			}
+		}
+
+		if changes.DeregistrationDelay != nil {
+			if err := e.modifyDeregistrationDelay(t.Cloud); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *TargetGroup) modifyDeregistrationDelay(cloud awsup.AWSCloud) error {
+	request := &elbv2.ModifyTargetGroupAttributesInput{
+		TargetGroupArn: e.ARN,
+		Attributes: []*elbv2.TargetGroupAttribute{
+			{
+				Key:   aws.String(targetGroupAttributeDeregistrationDelay),
+				Value: aws.String(strconv.FormatInt(fi.Int64Value(e.DeregistrationDelay), 10)),
+			},
+		},
+	}
+
+	klog.V(2).Infof("Setting deregistration delay of Target Group %q", fi.StringValue(e.Name))
+	if _, err := cloud.ELBV2().ModifyTargetGroupAttributes(request); err != nil {
+		return fmt.Errorf("error setting deregistration delay of target group %q: %v", fi.StringValue(e.Name), err)
 	}
 	return nil
 }
@@ -208,18 +297,22 @@ func (a OrderTargetGroupsByName) Less(i, j int) bool {
 }
 
 type terraformTargetGroup struct {
-	Name        string                          `json:"name" cty:"name"`
-	Port        int64                           `json:"port" cty:"port"`
-	Protocol    string                          `json:"protocol" cty:"protocol"`
-	VPCID       terraformWriter.Literal         `json:"vpc_id" cty:"vpc_id"`
-	Tags        map[string]string               `json:"tags,omitempty" cty:"tags"`
-	HealthCheck terraformTargetGroupHealthCheck `json:"health_check" cty:"health_check"`
+	Name                string                          `json:"name" cty:"name"`
+	Port                int64                           `json:"port" cty:"port"`
+	Protocol            string                          `json:"protocol" cty:"protocol"`
+	VPCID               terraformWriter.Literal         `json:"vpc_id" cty:"vpc_id"`
+	Tags                map[string]string               `json:"tags,omitempty" cty:"tags"`
+	HealthCheck         terraformTargetGroupHealthCheck `json:"health_check" cty:"health_check"`
+	DeregistrationDelay *int64                          `json:"deregistration_delay,omitempty" cty:"deregistration_delay"`
 }
 
 type terraformTargetGroupHealthCheck struct {
-	HealthyThreshold   int64  `json:"healthy_threshold" cty:"healthy_threshold"`
-	UnhealthyThreshold int64  `json:"unhealthy_threshold" cty:"unhealthy_threshold"`
-	Protocol           string `json:"protocol" cty:"protocol"`
+	HealthyThreshold   int64   `json:"healthy_threshold" cty:"healthy_threshold"`
+	UnhealthyThreshold int64   `json:"unhealthy_threshold" cty:"unhealthy_threshold"`
+	Protocol           string  `json:"protocol" cty:"protocol"`
+	Interval           *int64  `json:"interval,omitempty" cty:"interval"`
+	Port               *string `json:"port,omitempty" cty:"port"`
+	Path               *string `json:"path,omitempty" cty:"path"`
 }
 
 func (_ *TargetGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *TargetGroup) error {
@@ -242,7 +335,14 @@ func (_ *TargetGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, change
 			HealthyThreshold:   *e.HealthyThreshold,
 			UnhealthyThreshold: *e.UnhealthyThreshold,
 			Protocol:           elbv2.ProtocolEnumTcp,
+			Interval:           e.HealthCheckInterval,
+			Port:               e.HealthCheckPort,
+			Path:               e.HealthCheckPath,
 		},
+		DeregistrationDelay: e.DeregistrationDelay,
+	}
+	if e.HealthCheckProtocol != nil {
+		tf.HealthCheck.Protocol = *e.HealthCheckProtocol
 	}
 
 	return t.RenderResource("aws_lb_target_group", *e.Name, tf)
@@ -267,9 +367,19 @@ type cloudformationTargetGroup struct {
 	VPCID    *cloudformation.Literal `json:"VpcId"`
 	Tags     []cloudformationTag     `json:"Tags"`
 
-	HealthCheckProtocol string `json:"HealthCheckProtocol"`
-	HealthyThreshold    int64  `json:"HealthyThresholdCount"`
-	UnhealthyThreshold  int64  `json:"UnhealthyThresholdCount"`
+	HealthCheckProtocol string  `json:"HealthCheckProtocol"`
+	HealthyThreshold    int64   `json:"HealthyThresholdCount"`
+	UnhealthyThreshold  int64   `json:"UnhealthyThresholdCount"`
+	HealthCheckInterval *int64  `json:"HealthCheckIntervalSeconds,omitempty"`
+	HealthCheckPort     *string `json:"HealthCheckPort,omitempty"`
+	HealthCheckPath     *string `json:"HealthCheckPath,omitempty"`
+
+	TargetGroupAttributes []cloudformationTargetGroupAttribute `json:"TargetGroupAttributes,omitempty"`
+}
+
+type cloudformationTargetGroupAttribute struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
 }
 
 func (_ *TargetGroup) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *TargetGroup) error {
@@ -287,6 +397,18 @@ func (_ *TargetGroup) RenderCloudformation(t *cloudformation.CloudformationTarge
 		HealthCheckProtocol: *e.Protocol,
 		HealthyThreshold:    *e.HealthyThreshold,
 		UnhealthyThreshold:  *e.UnhealthyThreshold,
+		HealthCheckInterval: e.HealthCheckInterval,
+		HealthCheckPort:     e.HealthCheckPort,
+		HealthCheckPath:     e.HealthCheckPath,
+	}
+	if e.HealthCheckProtocol != nil {
+		cf.HealthCheckProtocol = *e.HealthCheckProtocol
+	}
+	if e.DeregistrationDelay != nil {
+		cf.TargetGroupAttributes = append(cf.TargetGroupAttributes, cloudformationTargetGroupAttribute{
+			Key:   targetGroupAttributeDeregistrationDelay,
+			Value: strconv.FormatInt(*e.DeregistrationDelay, 10),
+		})
 	}
 	return t.RenderResource("AWS::ElasticLoadBalancingV2::TargetGroup", *e.Name, cf)
 }
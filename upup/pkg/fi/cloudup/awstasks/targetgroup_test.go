@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestTargetGroupTerraformRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &TargetGroup{
+				Name:                fi.String("tcp-test"),
+				VPC:                 &VPC{Name: fi.String("test")},
+				Port:                fi.Int64(443),
+				Protocol:            fi.String("TCP"),
+				HealthyThreshold:    fi.Int64(2),
+				UnhealthyThreshold:  fi.Int64(3),
+				HealthCheckInterval: fi.Int64(10),
+				HealthCheckPath:     fi.String("/readyz"),
+				HealthCheckPort:     fi.String("443"),
+				HealthCheckProtocol: fi.String("HTTPS"),
+				DeregistrationDelay: fi.Int64(30),
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_lb_target_group" "tcp-test" {
+  deregistration_delay = 30
+  health_check {
+    healthy_threshold   = 2
+    interval            = 10
+    path                = "/readyz"
+    port                = "443"
+    protocol            = "HTTPS"
+    unhealthy_threshold = 3
+  }
+  name     = "tcp-test"
+  port     = 443
+  protocol = "TCP"
+  vpc_id   = aws_vpc.test.id
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.34.0"
+    }
+  }
+}
+`,
+		},
+	}
+
+	doRenderTests(t, "RenderTerraform", cases)
+}
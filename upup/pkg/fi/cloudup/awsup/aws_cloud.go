@@ -25,8 +25,12 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/eventbridge"
 	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
@@ -46,6 +50,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"k8s.io/klog/v2"
 
@@ -93,8 +99,20 @@ const TagNameKopsRole = "kubernetes.io/kops/role"
 // TagNameClusterOwnershipPrefix is the AWS tag used for ownership
 const TagNameClusterOwnershipPrefix = "kubernetes.io/cluster/"
 
+// TagNameEgressGateway is the tag on the Elastic IP of an egress gateway that names the egress gateway
+const TagNameEgressGateway = "kops.k8s.io/egress-gateway"
+
+// TagNameLoadBalancer is the tag on an Elastic IP allocated for a load balancer that names the load balancer
+const TagNameLoadBalancer = "kops.k8s.io/load-balancer"
+
 const tagNameDetachedInstance = "kops.k8s.io/detached-from-asg"
 
+// tagNameSurgeGroup is the tag on a surge autoscaling group that names the autoscaling group it is a copy of
+const tagNameSurgeGroup = "kops.k8s.io/surge-for-asg"
+
+// surgeGroupSuffix is appended to the name of an autoscaling group to name its surge group
+const surgeGroupSuffix = "-surge"
+
 const (
 	WellKnownAccountAmazonLinux2 = "137112412989"
 	WellKnownAccountCentOS       = "125523088429"
@@ -121,6 +139,9 @@ type AWSCloud interface {
 
 	SQS() sqsiface.SQSAPI
 	EventBridge() eventbridgeiface.EventBridgeAPI
+	SSM() ssmiface.SSMAPI
+	SNS() snsiface.SNSAPI
+	ServiceQuotas() servicequotasiface.ServiceQuotasAPI
 
 	// TODO: Document and rationalize these tags/filters methods
 	AddTags(name *string, tags map[string]string)
@@ -177,22 +198,35 @@ type AWSCloud interface {
 	// DescribeInstanceType calls ec2.DescribeInstanceType to get information for a particular instance type
 	DescribeInstanceType(instanceType string) (*ec2.InstanceTypeInfo, error)
 
+	// ZonesWithInstanceType returns the availability zones of the region in which the instance type is offered
+	ZonesWithInstanceType(instanceType string) (sets.String, error)
+
+	// GetInstanceMaintenancePolicy returns the instance maintenance policy of the autoscaling group, or nil if it has none,
+	// as of the last time the group was described
+	GetInstanceMaintenancePolicy(groupName string) *InstanceMaintenancePolicy
+
+	// SetInstanceMaintenancePolicy sets the instance maintenance policy of the autoscaling group, or removes it if policy is nil
+	SetInstanceMaintenancePolicy(groupName string, policy *InstanceMaintenancePolicy) error
+
 	// AccountInfo returns the AWS account ID and AWS partition that we are deploying into
 	AccountInfo() (string, string, error)
 }
 
 type awsCloudImplementation struct {
-	cf          *cloudformation.CloudFormation
-	ec2         *ec2.EC2
-	iam         *iam.IAM
-	elb         *elb.ELB
-	elbv2       *elbv2.ELBV2
-	autoscaling *autoscaling.AutoScaling
-	route53     *route53.Route53
-	spotinst    spotinst.Cloud
-	sts         *sts.STS
-	sqs         *sqs.SQS
-	eventbridge *eventbridge.EventBridge
+	cf            *cloudformation.CloudFormation
+	ec2           *ec2.EC2
+	iam           *iam.IAM
+	elb           *elb.ELB
+	elbv2         *elbv2.ELBV2
+	autoscaling   *autoscaling.AutoScaling
+	route53       *route53.Route53
+	spotinst      spotinst.Cloud
+	sts           *sts.STS
+	sqs           *sqs.SQS
+	eventbridge   *eventbridge.EventBridge
+	ssm           *ssm.SSM
+	sns           *sns.SNS
+	servicequotas *servicequotas.ServiceQuotas
 
 	region string
 
@@ -201,6 +235,8 @@ type awsCloudImplementation struct {
 	regionDelayers *RegionDelayers
 
 	instanceTypes *instanceTypes
+
+	instanceMaintenancePolicies *instanceMaintenancePolicies
 }
 
 type RegionDelayers struct {
@@ -211,9 +247,11 @@ type RegionDelayers struct {
 type instanceTypes struct {
 	mutex   sync.Mutex
 	typeMap map[string]*ec2.InstanceTypeInfo
+	zoneMap map[string]sets.String
 }
 
 var _ fi.Cloud = &awsCloudImplementation{}
+var _ fi.SurgeGroupCloud = &awsCloudImplementation{}
 
 func (c *awsCloudImplementation) ProviderID() kops.CloudProviderID {
 	return kops.CloudProviderAWS
@@ -235,6 +273,10 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 			},
 			instanceTypes: &instanceTypes{
 				typeMap: make(map[string]*ec2.InstanceTypeInfo),
+				zoneMap: make(map[string]sets.String),
+			},
+			instanceMaintenancePolicies: &instanceMaintenancePolicies{
+				policies: make(map[string]*InstanceMaintenancePolicy),
 			},
 		}
 
@@ -330,6 +372,7 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 		}
 		c.autoscaling = autoscaling.New(sess, config)
 		c.autoscaling.Handlers.Send.PushFront(requestLogger)
+		c.autoscaling.Handlers.Unmarshal.PushFront(c.instanceMaintenancePolicies.recordDescribeResponse)
 		c.addHandlers(region, &c.autoscaling.Handlers)
 
 		sess, err = session.NewSessionWithOptions(session.Options{
@@ -372,6 +415,39 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 		c.eventbridge.Handlers.Send.PushFront(requestLogger)
 		c.addHandlers(region, &c.eventbridge.Handlers)
 
+		sess, err = session.NewSessionWithOptions(session.Options{
+			Config:            *config,
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return c, err
+		}
+		c.ssm = ssm.New(sess, config)
+		c.ssm.Handlers.Send.PushFront(requestLogger)
+		c.addHandlers(region, &c.ssm.Handlers)
+
+		sess, err = session.NewSessionWithOptions(session.Options{
+			Config:            *config,
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return c, err
+		}
+		c.sns = sns.New(sess, config)
+		c.sns.Handlers.Send.PushFront(requestLogger)
+		c.addHandlers(region, &c.sns.Handlers)
+
+		sess, err = session.NewSessionWithOptions(session.Options{
+			Config:            *config,
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return c, err
+		}
+		c.servicequotas = servicequotas.New(sess, config)
+		c.servicequotas.Handlers.Send.PushFront(requestLogger)
+		c.addHandlers(region, &c.servicequotas.Handlers)
+
 		awsCloudInstances[region] = c
 		raw = c
 	}
@@ -382,6 +458,10 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 }
 
 func (c *awsCloudImplementation) addHandlers(regionName string, h *request.Handlers) {
+	h.Complete.PushBackNamed(request.NamedHandler{
+		Name: "kops/trace",
+		Fn:   traceRequest(regionName),
+	})
 
 	delayer := c.getCrossRequestRetryDelay(regionName)
 	if delayer != nil {
@@ -595,6 +675,145 @@ func detachInstance(c AWSCloud, i *cloudinstances.CloudInstance) error {
 	return nil
 }
 
+// CreateSurgeGroup implements fi.SurgeGroupCloud
+func (c *awsCloudImplementation) CreateSurgeGroup(group *cloudinstances.CloudInstanceGroup, size int) (string, error) {
+	if c.spotinst != nil {
+		return "", fmt.Errorf("surge groups are not supported with spotinst")
+	}
+	return createSurgeGroup(c, group, size)
+}
+
+// GetSurgeGroupInstances implements fi.SurgeGroupCloud
+func (c *awsCloudImplementation) GetSurgeGroupInstances(name string) ([]string, error) {
+	return getSurgeGroupInstances(c, name)
+}
+
+// DeleteSurgeGroup implements fi.SurgeGroupCloud
+func (c *awsCloudImplementation) DeleteSurgeGroup(name string) error {
+	return deleteSurgeGroup(c, name)
+}
+
+func describeAutoscalingGroup(c AWSCloud, name string) (*autoscaling.Group, error) {
+	request := &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(name)},
+	}
+	response, err := c.Autoscaling().DescribeAutoScalingGroups(request)
+	if err != nil {
+		return nil, fmt.Errorf("error describing autoscaling group %q: %v", name, err)
+	}
+	for _, g := range response.AutoScalingGroups {
+		if aws.StringValue(g.AutoScalingGroupName) == name {
+			return g, nil
+		}
+	}
+	return nil, nil
+}
+
+func createSurgeGroup(c AWSCloud, group *cloudinstances.CloudInstanceGroup, size int) (string, error) {
+	asg, err := describeAutoscalingGroup(c, group.HumanName)
+	if err != nil {
+		return "", err
+	}
+	if asg == nil {
+		return "", fmt.Errorf("autoscaling group %q not found", group.HumanName)
+	}
+
+	name := group.HumanName + surgeGroupSuffix
+
+	existing, err := describeAutoscalingGroup(c, name)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		if aws.Int64Value(existing.DesiredCapacity) != int64(size) {
+			klog.V(2).Infof("Resizing surge autoscaling group %q to %d", name, size)
+			request := &autoscaling.UpdateAutoScalingGroupInput{
+				AutoScalingGroupName: aws.String(name),
+				DesiredCapacity:      aws.Int64(int64(size)),
+				MinSize:              aws.Int64(int64(size)),
+				MaxSize:              aws.Int64(int64(size)),
+			}
+			if _, err := c.Autoscaling().UpdateAutoScalingGroup(request); err != nil {
+				return "", fmt.Errorf("error resizing surge autoscaling group %q: %v", name, err)
+			}
+		}
+		return name, nil
+	}
+
+	request := &autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName:    aws.String(name),
+		DesiredCapacity:         aws.Int64(int64(size)),
+		MinSize:                 aws.Int64(int64(size)),
+		MaxSize:                 aws.Int64(int64(size)),
+		LaunchConfigurationName: asg.LaunchConfigurationName,
+		LaunchTemplate:          asg.LaunchTemplate,
+		MixedInstancesPolicy:    asg.MixedInstancesPolicy,
+		VPCZoneIdentifier:       asg.VPCZoneIdentifier,
+		LoadBalancerNames:       asg.LoadBalancerNames,
+		TargetGroupARNs:         asg.TargetGroupARNs,
+		HealthCheckType:         asg.HealthCheckType,
+		HealthCheckGracePeriod:  asg.HealthCheckGracePeriod,
+		CapacityRebalance:       asg.CapacityRebalance,
+	}
+	if aws.StringValue(asg.VPCZoneIdentifier) == "" {
+		request.AvailabilityZones = asg.AvailabilityZones
+	}
+	for _, tag := range asg.Tags {
+		request.Tags = append(request.Tags, &autoscaling.Tag{
+			Key:               tag.Key,
+			Value:             tag.Value,
+			PropagateAtLaunch: tag.PropagateAtLaunch,
+			ResourceId:        aws.String(name),
+			ResourceType:      aws.String("auto-scaling-group"),
+		})
+	}
+	request.Tags = append(request.Tags, &autoscaling.Tag{
+		Key:               aws.String(tagNameSurgeGroup),
+		Value:             aws.String(group.HumanName),
+		PropagateAtLaunch: aws.Bool(false),
+		ResourceId:        aws.String(name),
+		ResourceType:      aws.String("auto-scaling-group"),
+	})
+
+	klog.V(2).Infof("Creating surge autoscaling group %q with %d instances", name, size)
+	if _, err := c.Autoscaling().CreateAutoScalingGroup(request); err != nil {
+		return "", fmt.Errorf("error creating surge autoscaling group %q: %v", name, err)
+	}
+
+	return name, nil
+}
+
+func getSurgeGroupInstances(c AWSCloud, name string) ([]string, error) {
+	asg, err := describeAutoscalingGroup(c, name)
+	if err != nil {
+		return nil, err
+	}
+	if asg == nil {
+		return nil, fmt.Errorf("surge autoscaling group %q not found", name)
+	}
+
+	var ids []string
+	for _, i := range asg.Instances {
+		if strings.HasPrefix(aws.StringValue(i.LifecycleState), "Terminating") {
+			continue
+		}
+		ids = append(ids, aws.StringValue(i.InstanceId))
+	}
+	return ids, nil
+}
+
+func deleteSurgeGroup(c AWSCloud, name string) error {
+	klog.V(2).Infof("Deleting surge autoscaling group %q", name)
+	request := &autoscaling.DeleteAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(name),
+		ForceDelete:          aws.Bool(true),
+	}
+	if _, err := c.Autoscaling().DeleteAutoScalingGroup(request); err != nil {
+		return fmt.Errorf("error deleting surge autoscaling group %q: %v", name, err)
+	}
+	return nil
+}
+
 // GetCloudGroups returns a groups of instances that back a kops instance groups
 func (c *awsCloudImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
 	if c.spotinst != nil {
@@ -837,8 +1056,9 @@ func awsBuildCloudInstanceGroup(c AWSCloud, cluster *kops.Cluster, ig *kops.Inst
 		Raw:           g,
 	}
 
+	reasons := newUpdateReasonFinder(c)
 	for _, i := range g.Instances {
-		err := buildCloudInstance(i, instances, instanceSeen, nodeMap, cg, newConfigName)
+		err := buildCloudInstance(i, instances, instanceSeen, nodeMap, cg, newConfigName, reasons)
 		if err != nil {
 			return nil, err
 		}
@@ -851,7 +1071,7 @@ func awsBuildCloudInstanceGroup(c AWSCloud, cluster *kops.Cluster, ig *kops.Inst
 		return nil, err
 	}
 	for _, i := range result.Instances {
-		err := buildCloudInstance(i, instances, instanceSeen, nodeMap, cg, newConfigName)
+		err := buildCloudInstance(i, instances, instanceSeen, nodeMap, cg, newConfigName, reasons)
 		if err != nil {
 			return nil, err
 		}
@@ -873,6 +1093,7 @@ func awsBuildCloudInstanceGroup(c AWSCloud, cluster *kops.Cluster, ig *kops.Inst
 			if err != nil {
 				return nil, fmt.Errorf("error creating cloud instance group member: %v", err)
 			}
+			cm.UpdateReason = "detached by a previous rolling update"
 			instanceSeen[*id] = true
 			addCloudInstanceData(cm, instances[aws.StringValue(id)])
 		}
@@ -881,7 +1102,7 @@ func awsBuildCloudInstanceGroup(c AWSCloud, cluster *kops.Cluster, ig *kops.Inst
 	return cg, nil
 }
 
-func buildCloudInstance(i *autoscaling.Instance, instances map[string]*ec2.Instance, instanceSeen map[string]bool, nodeMap map[string]*v1.Node, cg *cloudinstances.CloudInstanceGroup, newConfigName string) error {
+func buildCloudInstance(i *autoscaling.Instance, instances map[string]*ec2.Instance, instanceSeen map[string]bool, nodeMap map[string]*v1.Node, cg *cloudinstances.CloudInstanceGroup, newConfigName string, reasons *updateReasonFinder) error {
 	id := aws.StringValue(i.InstanceId)
 	if id == "" {
 		klog.Warningf("ignoring instance with no instance id: %s in autoscaling group: %s", id, cg.HumanName)
@@ -908,6 +1129,9 @@ func buildCloudInstance(i *autoscaling.Instance, instances map[string]*ec2.Insta
 	if strings.HasPrefix(*i.LifecycleState, "Warmed") {
 		cm.State = cloudinstances.WarmPool
 	}
+	if status == cloudinstances.CloudInstanceStatusNeedsUpdate {
+		cm.UpdateReason = reasons.reason(currentConfigName, newConfigName)
+	}
 
 	addCloudInstanceData(cm, instances[id])
 	return nil
@@ -1851,6 +2075,18 @@ func (c *awsCloudImplementation) EventBridge() eventbridgeiface.EventBridgeAPI {
 	return c.eventbridge
 }
 
+func (c *awsCloudImplementation) SSM() ssmiface.SSMAPI {
+	return c.ssm
+}
+
+func (c *awsCloudImplementation) SNS() snsiface.SNSAPI {
+	return c.sns
+}
+
+func (c *awsCloudImplementation) ServiceQuotas() servicequotasiface.ServiceQuotasAPI {
+	return c.servicequotas
+}
+
 func (c *awsCloudImplementation) FindVPCInfo(vpcID string) (*fi.VPCInfo, error) {
 	return findVPCInfo(c, vpcID)
 }
@@ -1957,7 +2193,7 @@ func (c *awsCloudImplementation) DefaultInstanceType(cluster *kops.Cluster, ig *
 
 	// TODO: Validate that instance type exists in all AZs, but skip AZs that don't support any VPC stuff
 	for _, instanceType := range candidates {
-		zones, err := c.zonesWithInstanceType(instanceType)
+		zones, err := c.ZonesWithInstanceType(instanceType)
 		if err != nil {
 			return "", err
 		}
@@ -1971,30 +2207,55 @@ func (c *awsCloudImplementation) DefaultInstanceType(cluster *kops.Cluster, ig *
 	return "", fmt.Errorf("could not find a suitable supported instance type for the instance group %q (type %q) in region %q", ig.Name, ig.Spec.Role, c.region)
 }
 
-// supportsInstanceType uses the DescribeReservedInstancesOfferings API call to determine if an instance type is supported in a region
-func (c *awsCloudImplementation) zonesWithInstanceType(instanceType string) (sets.String, error) {
-	klog.V(4).Infof("checking if instance type %q is supported in region %q", instanceType, c.region)
-	request := &ec2.DescribeReservedInstancesOfferingsInput{}
-	request.InstanceTenancy = aws.String("default")
-	request.IncludeMarketplace = aws.Bool(false)
-	request.OfferingClass = aws.String(ec2.OfferingClassTypeStandard)
-	request.OfferingType = aws.String(ec2.OfferingTypeValuesNoUpfront)
-	request.ProductDescription = aws.String(ec2.RIProductDescriptionLinuxUnixamazonVpc)
-	request.InstanceType = aws.String(instanceType)
+func (c *awsCloudImplementation) GetInstanceMaintenancePolicy(groupName string) *InstanceMaintenancePolicy {
+	return c.instanceMaintenancePolicies.get(groupName)
+}
 
-	zones := sets.NewString()
+func (c *awsCloudImplementation) SetInstanceMaintenancePolicy(groupName string, policy *InstanceMaintenancePolicy) error {
+	if err := setInstanceMaintenancePolicy(c.autoscaling, groupName, policy); err != nil {
+		return err
+	}
+	c.instanceMaintenancePolicies.set(groupName, policy)
+	return nil
+}
 
-	response, err := c.ec2.DescribeReservedInstancesOfferings(request)
+// ZonesWithInstanceType uses the DescribeInstanceTypeOfferings API call to determine the availability zones in which an instance type is offered
+func (c *awsCloudImplementation) ZonesWithInstanceType(instanceType string) (sets.String, error) {
+	c.instanceTypes.mutex.Lock()
+	defer c.instanceTypes.mutex.Unlock()
+
+	if zones, ok := c.instanceTypes.zoneMap[instanceType]; ok {
+		return zones, nil
+	}
+
+	zones, err := zonesWithInstanceType(c, instanceType)
 	if err != nil {
-		return zones, fmt.Errorf("error checking if instance type %q is supported in region %q: %v", instanceType, c.region, err)
+		return nil, err
 	}
+	c.instanceTypes.zoneMap[instanceType] = zones
+	return zones, nil
+}
 
-	for _, item := range response.ReservedInstancesOfferings {
-		if aws.StringValue(item.InstanceType) == instanceType {
-			zones.Insert(aws.StringValue(item.AvailabilityZone))
-		} else {
-			klog.Warningf("skipping non-matching instance type offering: %v", item)
+func zonesWithInstanceType(c AWSCloud, instanceType string) (sets.String, error) {
+	klog.V(4).Infof("checking the zones in which instance type %q is offered in region %q", instanceType, c.Region())
+	request := &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+		Filters: []*ec2.Filter{
+			NewEC2Filter("instance-type", instanceType),
+		},
+	}
+
+	zones := sets.NewString()
+	err := c.EC2().DescribeInstanceTypeOfferingsPages(request, func(page *ec2.DescribeInstanceTypeOfferingsOutput, lastPage bool) bool {
+		for _, offering := range page.InstanceTypeOfferings {
+			if aws.StringValue(offering.InstanceType) == instanceType {
+				zones.Insert(aws.StringValue(offering.Location))
+			}
 		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing the zones in which instance type %q is offered in region %q: %v", instanceType, c.Region(), err)
 	}
 
 	return zones, nil
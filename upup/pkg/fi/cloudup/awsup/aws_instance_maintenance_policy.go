@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsup
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"k8s.io/klog/v2"
+)
+
+// The vendored version of the AWS SDK predates instance maintenance policies. They are written with the
+// UpdateAutoScalingGroup operation of the Auto Scaling query API, using a request shape that only holds the
+// fields of the policies, and read from the DescribeAutoScalingGroups responses that kOps already requests.
+
+// InstanceMaintenancePolicy is the range of healthy capacity that an autoscaling group keeps while it replaces instances
+type InstanceMaintenancePolicy struct {
+	// MinHealthyPercentage is the percentage of the desired capacity that stays healthy while instances are replaced
+	MinHealthyPercentage int64
+	// MaxHealthyPercentage is the percentage of the desired capacity that can be in service while instances are replaced
+	MaxHealthyPercentage int64
+}
+
+// NoInstanceMaintenancePolicy is the healthy percentage of autoscaling groups without an instance maintenance policy;
+// setting both percentages to it removes the policy
+const NoInstanceMaintenancePolicy = -1
+
+type instanceMaintenancePolicyShape struct {
+	_ struct{} `type:"structure"`
+
+	MinHealthyPercentage *int64 `type:"integer"`
+	MaxHealthyPercentage *int64 `type:"integer"`
+}
+
+type updateInstanceMaintenancePolicyInput struct {
+	_ struct{} `type:"structure"`
+
+	AutoScalingGroupName      *string                         `type:"string" required:"true"`
+	InstanceMaintenancePolicy *instanceMaintenancePolicyShape `type:"structure"`
+}
+
+// describeInstanceMaintenancePolicyResponse holds the instance maintenance policies of a DescribeAutoScalingGroups response
+type describeInstanceMaintenancePolicyResponse struct {
+	AutoScalingGroups []struct {
+		AutoScalingGroupName      string
+		InstanceMaintenancePolicy *struct {
+			MinHealthyPercentage *int64
+			MaxHealthyPercentage *int64
+		}
+	} `xml:"DescribeAutoScalingGroupsResult>AutoScalingGroups>member"`
+}
+
+// instanceMaintenancePolicies are the instance maintenance policies of the autoscaling groups, by name,
+// as of the last DescribeAutoScalingGroups response that included each group
+type instanceMaintenancePolicies struct {
+	mutex    sync.Mutex
+	policies map[string]*InstanceMaintenancePolicy
+}
+
+// get returns the instance maintenance policy of the autoscaling group, or nil if it has none
+func (p *instanceMaintenancePolicies) get(groupName string) *InstanceMaintenancePolicy {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.policies[groupName]
+}
+
+// set records the instance maintenance policy of the autoscaling group, or that it has none if policy is nil
+func (p *instanceMaintenancePolicies) set(groupName string, policy *InstanceMaintenancePolicy) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if policy == nil {
+		delete(p.policies, groupName)
+	} else {
+		p.policies[groupName] = policy
+	}
+}
+
+// recordDescribeResponse is an unmarshal handler of the autoscaling client that records the instance maintenance
+// policies of the DescribeAutoScalingGroups responses, leaving the body for the handlers of the sdk
+func (p *instanceMaintenancePolicies) recordDescribeResponse(r *request.Request) {
+	if r.Operation.Name != "DescribeAutoScalingGroups" {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.HTTPResponse.Body)
+	r.HTTPResponse.Body.Close()
+	r.HTTPResponse.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		// The sdk reports the truncated response
+		return
+	}
+
+	response := &describeInstanceMaintenancePolicyResponse{}
+	if err := xml.Unmarshal(body, response); err != nil {
+		klog.Warningf("error reading instance maintenance policies of autoscaling groups: %v", err)
+		return
+	}
+	for _, g := range response.AutoScalingGroups {
+		var policy *InstanceMaintenancePolicy
+		if g.InstanceMaintenancePolicy != nil {
+			min := aws.Int64Value(g.InstanceMaintenancePolicy.MinHealthyPercentage)
+			max := aws.Int64Value(g.InstanceMaintenancePolicy.MaxHealthyPercentage)
+			if g.InstanceMaintenancePolicy.MinHealthyPercentage != nil && min != NoInstanceMaintenancePolicy {
+				policy = &InstanceMaintenancePolicy{
+					MinHealthyPercentage: min,
+					MaxHealthyPercentage: max,
+				}
+			}
+		}
+		p.set(g.AutoScalingGroupName, policy)
+	}
+}
+
+// setInstanceMaintenancePolicy sets the instance maintenance policy of the autoscaling group, or removes it if policy is nil
+func setInstanceMaintenancePolicy(client *autoscaling.AutoScaling, groupName string, policy *InstanceMaintenancePolicy) error {
+	op := &request.Operation{
+		Name:       "UpdateAutoScalingGroup",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	shape := &instanceMaintenancePolicyShape{
+		MinHealthyPercentage: aws.Int64(NoInstanceMaintenancePolicy),
+		MaxHealthyPercentage: aws.Int64(NoInstanceMaintenancePolicy),
+	}
+	if policy != nil {
+		shape.MinHealthyPercentage = aws.Int64(policy.MinHealthyPercentage)
+		shape.MaxHealthyPercentage = aws.Int64(policy.MaxHealthyPercentage)
+	}
+	input := &updateInstanceMaintenancePolicyInput{
+		AutoScalingGroupName:      aws.String(groupName),
+		InstanceMaintenancePolicy: shape,
+	}
+	if err := client.NewRequest(op, input, &autoscaling.UpdateAutoScalingGroupOutput{}).Send(); err != nil {
+		return fmt.Errorf("error setting instance maintenance policy of autoscaling group %q: %v", groupName, err)
+	}
+	return nil
+}
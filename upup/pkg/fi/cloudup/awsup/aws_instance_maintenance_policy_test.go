@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsup
+
+import (
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+)
+
+// buildStubAutoscaling returns an autoscaling client that records the bodies of its requests and answers them with the response
+func buildStubAutoscaling(t *testing.T, response string, bodies *[]string) *autoscaling.AutoScaling {
+	config := aws.NewConfig().WithRegion("us-test-1").WithCredentials(credentials.NewStaticCredentials("id", "secret", ""))
+	sess, err := session.NewSession(config)
+	if err != nil {
+		t.Fatalf("error building session: %v", err)
+	}
+	client := autoscaling.New(sess)
+	client.Handlers.Send.Clear()
+	client.Handlers.Send.PushBack(func(r *request.Request) {
+		body, err := ioutil.ReadAll(r.GetBody())
+		if err != nil {
+			t.Fatalf("error reading request body: %v", err)
+		}
+		*bodies = append(*bodies, string(body))
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(response)),
+		}
+	})
+	return client
+}
+
+func TestRecordInstanceMaintenancePolicies(t *testing.T) {
+	response := `<DescribeAutoScalingGroupsResponse xmlns="http://autoscaling.amazonaws.com/doc/2011-01-01/">
+  <DescribeAutoScalingGroupsResult>
+    <AutoScalingGroups>
+      <member>
+        <AutoScalingGroupName>nodes</AutoScalingGroupName>
+        <MinSize>1</MinSize>
+        <InstanceMaintenancePolicy>
+          <MinHealthyPercentage>100</MinHealthyPercentage>
+          <MaxHealthyPercentage>120</MaxHealthyPercentage>
+        </InstanceMaintenancePolicy>
+      </member>
+      <member>
+        <AutoScalingGroupName>masters</AutoScalingGroupName>
+        <MinSize>1</MinSize>
+      </member>
+      <member>
+        <AutoScalingGroupName>bastions</AutoScalingGroupName>
+        <MinSize>1</MinSize>
+        <InstanceMaintenancePolicy>
+          <MinHealthyPercentage>-1</MinHealthyPercentage>
+          <MaxHealthyPercentage>-1</MaxHealthyPercentage>
+        </InstanceMaintenancePolicy>
+      </member>
+    </AutoScalingGroups>
+  </DescribeAutoScalingGroupsResult>
+  <ResponseMetadata>
+    <RequestId>5a6e1b4e-0000-0000-0000-000000000000</RequestId>
+  </ResponseMetadata>
+</DescribeAutoScalingGroupsResponse>`
+
+	var bodies []string
+	client := buildStubAutoscaling(t, response, &bodies)
+	policies := &instanceMaintenancePolicies{
+		policies: map[string]*InstanceMaintenancePolicy{
+			"bastions": {MinHealthyPercentage: 90, MaxHealthyPercentage: 110},
+		},
+	}
+	client.Handlers.Unmarshal.PushFront(policies.recordDescribeResponse)
+
+	output, err := client.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: aws.StringSlice([]string{"nodes", "masters", "bastions"}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Errorf("expected a single request, got %q", bodies)
+	}
+
+	// The sdk still reads the groups from the response
+	if len(output.AutoScalingGroups) != 3 || aws.Int64Value(output.AutoScalingGroups[0].MinSize) != 1 {
+		t.Errorf("unexpected groups %v", output.AutoScalingGroups)
+	}
+
+	grid := map[string]*InstanceMaintenancePolicy{
+		"nodes":    {MinHealthyPercentage: 100, MaxHealthyPercentage: 120},
+		"masters":  nil,
+		"bastions": nil,
+	}
+	for group, expected := range grid {
+		if actual := policies.get(group); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected policy %+v for %q, got %+v", expected, group, actual)
+		}
+	}
+}
+
+func TestSetInstanceMaintenancePolicy(t *testing.T) {
+	grid := []struct {
+		Name     string
+		Policy   *InstanceMaintenancePolicy
+		Expected string
+	}{
+		{
+			Name:     "set",
+			Policy:   &InstanceMaintenancePolicy{MinHealthyPercentage: 100, MaxHealthyPercentage: 150},
+			Expected: "Action=UpdateAutoScalingGroup&AutoScalingGroupName=nodes&InstanceMaintenancePolicy.MaxHealthyPercentage=150&InstanceMaintenancePolicy.MinHealthyPercentage=100&Version=2011-01-01",
+		},
+		{
+			Name:     "remove",
+			Expected: "Action=UpdateAutoScalingGroup&AutoScalingGroupName=nodes&InstanceMaintenancePolicy.MaxHealthyPercentage=-1&InstanceMaintenancePolicy.MinHealthyPercentage=-1&Version=2011-01-01",
+		},
+	}
+
+	response := `<UpdateAutoScalingGroupResponse xmlns="http://autoscaling.amazonaws.com/doc/2011-01-01/">
+  <ResponseMetadata>
+    <RequestId>5a6e1b4e-0000-0000-0000-000000000000</RequestId>
+  </ResponseMetadata>
+</UpdateAutoScalingGroupResponse>`
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			var bodies []string
+			client := buildStubAutoscaling(t, response, &bodies)
+
+			if err := setInstanceMaintenancePolicy(client, "nodes", g.Policy); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(bodies) != 1 || bodies[0] != g.Expected {
+				t.Errorf("expected request %q, got %q", g.Expected, bodies)
+			}
+		})
+	}
+}
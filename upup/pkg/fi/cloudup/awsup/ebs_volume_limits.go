@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsup
+
+import (
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// EBSVolumeLimits are the limits of the size, IOPS and throughput of an EBS volume type
+type EBSVolumeLimits struct {
+	// MinSize and MaxSize are the bounds of the size of the volumes, in GB
+	MinSize int32
+	MaxSize int32
+	// MaxIops is the greatest IOPS of the volumes, or 0 if the IOPS can't be provisioned
+	MaxIops int32
+	// MaxIopsPerGB is the greatest ratio of the IOPS to the size of the volumes
+	MaxIopsPerGB int32
+	// BaselineIops are the IOPS of the volumes with no provisioned IOPS, which the ratio to the size doesn't apply to
+	BaselineIops int32
+	// MaxThroughput is the greatest throughput of the volumes, in MiB/s, or 0 if the throughput can't be provisioned
+	MaxThroughput int32
+	// MaxThroughputPerIops is the greatest ratio of the throughput to the IOPS of the volumes
+	MaxThroughputPerIops float64
+}
+
+// EBSVolumeTypeLimits are the limits of the EBS volume types, from
+// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/EBSVolumeTypes.html
+// The limits of io2 are those of io2 Block Express, which all io2 volumes now are.
+var EBSVolumeTypeLimits = map[string]EBSVolumeLimits{
+	ec2.VolumeTypeStandard: {MinSize: 1, MaxSize: 1024},
+	ec2.VolumeTypeGp2:      {MinSize: 1, MaxSize: 16384},
+	ec2.VolumeTypeGp3: {
+		MinSize:              1,
+		MaxSize:              16384,
+		MaxIops:              16000,
+		MaxIopsPerGB:         500,
+		BaselineIops:         3000,
+		MaxThroughput:        1000,
+		MaxThroughputPerIops: 0.25,
+	},
+	ec2.VolumeTypeIo1: {MinSize: 4, MaxSize: 16384, MaxIops: 64000, MaxIopsPerGB: 50},
+	ec2.VolumeTypeIo2: {MinSize: 4, MaxSize: 65536, MaxIops: 256000, MaxIopsPerGB: 1000},
+}
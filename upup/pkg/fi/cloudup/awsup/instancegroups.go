@@ -37,6 +37,9 @@ func matchInstanceGroup(name string, clusterName string, instancegroups []*kops.
 			groupName = g.ObjectMeta.Name + "." + clusterName
 		case kops.InstanceGroupRoleBastion:
 			groupName = g.ObjectMeta.Name + "." + clusterName
+		case kops.InstanceGroupRoleHybrid:
+			// Hybrid machines are not in autoscaling groups
+			continue
 		default:
 			klog.Warningf("Ignoring InstanceGroup of unknown role %q", g.Spec.Role)
 			continue
@@ -31,8 +31,12 @@ import (
 	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
 	dnsproviderroute53 "k8s.io/kops/dnsprovider/pkg/dnsprovider/providers/aws/route53"
@@ -48,9 +52,17 @@ type MockAWSCloud struct {
 	tags   map[string]string
 
 	zones []*ec2.AvailabilityZone
+
+	// InstanceTypeOfferings restricts the zones in which instance types are offered;
+	// instance types that are not in the map are offered in every zone.
+	InstanceTypeOfferings map[string][]string
+
+	// InstanceMaintenancePolicies are the instance maintenance policies of the autoscaling groups, by name.
+	InstanceMaintenancePolicies map[string]*InstanceMaintenancePolicy
 }
 
 var _ fi.Cloud = (*MockAWSCloud)(nil)
+var _ fi.SurgeGroupCloud = (*MockAWSCloud)(nil)
 
 func InstallMockAWSCloud(region string, zoneLetters string) *MockAWSCloud {
 	i := BuildMockAWSCloud(region, zoneLetters)
@@ -86,6 +98,9 @@ type MockCloud struct {
 	MockSpotinst       spotinst.Cloud
 	MockSQS            sqsiface.SQSAPI
 	MockEventBridge    eventbridgeiface.EventBridgeAPI
+	MockSSM            ssmiface.SSMAPI
+	MockSNS            snsiface.SNSAPI
+	MockServiceQuotas  servicequotasiface.ServiceQuotasAPI
 }
 
 func (c *MockAWSCloud) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
@@ -100,6 +115,18 @@ func (c *MockAWSCloud) DetachInstance(i *cloudinstances.CloudInstance) error {
 	return detachInstance(c, i)
 }
 
+func (c *MockAWSCloud) CreateSurgeGroup(group *cloudinstances.CloudInstanceGroup, size int) (string, error) {
+	return createSurgeGroup(c, group, size)
+}
+
+func (c *MockAWSCloud) GetSurgeGroupInstances(name string) ([]string, error) {
+	return getSurgeGroupInstances(c, name)
+}
+
+func (c *MockAWSCloud) DeleteSurgeGroup(name string) error {
+	return deleteSurgeGroup(c, name)
+}
+
 func (c *MockAWSCloud) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
 	return getCloudGroups(c, cluster, instancegroups, warnUnmatched, nodes)
 }
@@ -297,6 +324,27 @@ func (c *MockAWSCloud) EventBridge() eventbridgeiface.EventBridgeAPI {
 	return c.MockEventBridge
 }
 
+func (c *MockAWSCloud) SSM() ssmiface.SSMAPI {
+	if c.MockSSM == nil {
+		klog.Fatalf("MockSSM not set")
+	}
+	return c.MockSSM
+}
+
+func (c *MockAWSCloud) SNS() snsiface.SNSAPI {
+	if c.MockSNS == nil {
+		klog.Fatalf("MockSNS not set")
+	}
+	return c.MockSNS
+}
+
+func (c *MockAWSCloud) ServiceQuotas() servicequotasiface.ServiceQuotasAPI {
+	if c.MockServiceQuotas == nil {
+		klog.Fatalf("MockServiceQuotas not set")
+	}
+	return c.MockServiceQuotas
+}
+
 func (c *MockAWSCloud) FindVPCInfo(id string) (*fi.VPCInfo, error) {
 	return findVPCInfo(c, id)
 }
@@ -347,6 +395,18 @@ func (c *MockAWSCloud) DescribeInstanceType(instanceType string) (*ec2.InstanceT
 		}
 	}
 
+	switch instanceType {
+	case "c5.large", "m5.large", "m5.xlarge", "t3.micro", "t3.medium", "t3.large", "a1.large":
+		info.Hypervisor = aws.String(ec2.InstanceTypeHypervisorNitro)
+		info.SupportedVirtualizationTypes = aws.StringSlice([]string{ec2.VirtualizationTypeHvm})
+	case "t2.micro", "t2.medium":
+		info.Hypervisor = aws.String(ec2.InstanceTypeHypervisorXen)
+		info.SupportedVirtualizationTypes = aws.StringSlice([]string{ec2.VirtualizationTypeHvm})
+	case "m3.medium":
+		info.Hypervisor = aws.String(ec2.InstanceTypeHypervisorXen)
+		info.SupportedVirtualizationTypes = aws.StringSlice([]string{ec2.VirtualizationTypeHvm, ec2.VirtualizationTypeParavirtual})
+	}
+
 	switch instanceType {
 	case "c5.large", "m3.medium", "m4.large", "m5.large", "m5.xlarge", "t3.micro", "t3.medium", "t3.large", "c4.large":
 		info.ProcessorInfo = &ec2.ProcessorInfo{
@@ -372,6 +432,36 @@ func (c *MockAWSCloud) DescribeInstanceType(instanceType string) (*ec2.InstanceT
 	return info, nil
 }
 
+// ZonesWithInstanceType returns the availability zones of the region in which the instance type is offered
+func (c *MockAWSCloud) ZonesWithInstanceType(instanceType string) (sets.String, error) {
+	if zones, ok := c.InstanceTypeOfferings[instanceType]; ok {
+		return sets.NewString(zones...), nil
+	}
+	zones := sets.NewString()
+	for _, zone := range c.zones {
+		zones.Insert(aws.StringValue(zone.ZoneName))
+	}
+	return zones, nil
+}
+
+// GetInstanceMaintenancePolicy returns the instance maintenance policy of the autoscaling group, or nil if it has none
+func (c *MockAWSCloud) GetInstanceMaintenancePolicy(groupName string) *InstanceMaintenancePolicy {
+	return c.InstanceMaintenancePolicies[groupName]
+}
+
+// SetInstanceMaintenancePolicy sets the instance maintenance policy of the autoscaling group, or removes it if policy is nil
+func (c *MockAWSCloud) SetInstanceMaintenancePolicy(groupName string, policy *InstanceMaintenancePolicy) error {
+	if policy == nil {
+		delete(c.InstanceMaintenancePolicies, groupName)
+		return nil
+	}
+	if c.InstanceMaintenancePolicies == nil {
+		c.InstanceMaintenancePolicies = make(map[string]*InstanceMaintenancePolicy)
+	}
+	c.InstanceMaintenancePolicies[groupName] = policy
+	return nil
+}
+
 // AccountInfo returns the AWS account ID and AWS partition that we are deploying into
 func (c *MockAWSCloud) AccountInfo() (string, string, error) {
 	return "123456789012", "aws-test", nil
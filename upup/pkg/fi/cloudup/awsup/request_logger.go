@@ -17,8 +17,11 @@ limitations under the License.
 package awsup
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws/request"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/tracing"
 )
 
 // RequestLogger logs every AWS request
@@ -44,3 +47,35 @@ func (l *RequestLogger) log(r *request.Request) {
 
 	klog.V(l.logLevel).Infof("AWS request: %s", methodDescription)
 }
+
+// traceRequest is a Complete handler for aws-sdk-go that records a span and a structured log entry for every request,
+// including its duration, retries and the AWS request ID
+func traceRequest(regionName string) func(r *request.Request) {
+	return func(r *request.Request) {
+		service := r.ClientInfo.ServiceName
+		name := "?"
+		if r.Operation != nil {
+			name = r.Operation.Name
+		}
+		statusCode := 0
+		if r.HTTPResponse != nil {
+			statusCode = r.HTTPResponse.StatusCode
+		}
+		end := time.Now()
+
+		span := tracing.StartAt(r.Context(), service+"/"+name, tracing.SpanKindClient, r.Time)
+		span.SetAttributes(
+			"rpc.system", "aws-api",
+			"rpc.service", service,
+			"rpc.method", name,
+			"aws.region", regionName,
+			"aws.request_id", r.RequestID,
+			"http.status_code", statusCode,
+			"kops.retries", r.RetryCount,
+		)
+		span.FinishAt(end, r.Error)
+
+		klog.V(4).InfoS("AWS request completed", "service", service, "operation", name, "region", regionName,
+			"requestID", r.RequestID, "statusCode", statusCode, "retries", r.RetryCount, "duration", end.Sub(r.Time), "err", r.Error)
+	}
+}
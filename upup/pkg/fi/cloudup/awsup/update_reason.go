@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+)
+
+// updateReasonFinder describes why an instance differs from the launch configuration or
+// launch template version of its autoscaling group.
+type updateReasonFinder struct {
+	cloud AWSCloud
+	// launchTemplateData caches launch template data, keyed by "id:version"
+	launchTemplateData map[string]*ec2.ResponseLaunchTemplateData
+}
+
+func newUpdateReasonFinder(c AWSCloud) *updateReasonFinder {
+	return &updateReasonFinder{
+		cloud:              c,
+		launchTemplateData: make(map[string]*ec2.ResponseLaunchTemplateData),
+	}
+}
+
+// reason describes the change from the current to the updated launch configuration or template,
+// as returned by findInstanceLaunchConfiguration and findAutoscalingGroupLaunchConfiguration.
+func (f *updateReasonFinder) reason(current, updated string) string {
+	currentID, currentVersion, currentIsTemplate := splitLaunchTemplate(current)
+	updatedID, updatedVersion, updatedIsTemplate := splitLaunchTemplate(updated)
+	if !currentIsTemplate || !updatedIsTemplate {
+		return fmt.Sprintf("launch configuration changed from %q to %q", current, updated)
+	}
+	if currentID != updatedID {
+		return fmt.Sprintf("launch template changed from %q to %q", currentID, updatedID)
+	}
+
+	currentData, err := f.getLaunchTemplateData(currentID, currentVersion)
+	if err == nil {
+		var updatedData *ec2.ResponseLaunchTemplateData
+		updatedData, err = f.getLaunchTemplateData(updatedID, updatedVersion)
+		if err == nil {
+			if changes := launchTemplateDataChanges(currentData, updatedData); len(changes) != 0 {
+				return strings.Join(changes, ", ")
+			}
+		}
+	}
+	if err != nil {
+		klog.V(2).Infof("unable to compare launch template versions of %q: %v", currentID, err)
+	}
+	return fmt.Sprintf("launch template version changed from %s to %s", currentVersion, updatedVersion)
+}
+
+func (f *updateReasonFinder) getLaunchTemplateData(id, version string) (*ec2.ResponseLaunchTemplateData, error) {
+	key := id + ":" + version
+	if data, found := f.launchTemplateData[key]; found {
+		return data, nil
+	}
+
+	response, err := f.cloud.EC2().DescribeLaunchTemplateVersions(&ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: aws.String(id),
+		Versions:         []*string{aws.String(version)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing launch template versions: %v", err)
+	}
+	if len(response.LaunchTemplateVersions) != 1 || response.LaunchTemplateVersions[0].LaunchTemplateData == nil {
+		return nil, fmt.Errorf("found %d versions of launch template %q matching %q", len(response.LaunchTemplateVersions), id, version)
+	}
+
+	data := response.LaunchTemplateVersions[0].LaunchTemplateData
+	f.launchTemplateData[key] = data
+	return data, nil
+}
+
+// splitLaunchTemplate splits an "id:version" launch template reference.
+func splitLaunchTemplate(name string) (string, string, bool) {
+	if !strings.HasPrefix(name, "lt-") {
+		return "", "", false
+	}
+	tokens := strings.SplitN(name, ":", 2)
+	if len(tokens) != 2 {
+		return "", "", false
+	}
+	return tokens[0], tokens[1], true
+}
+
+// launchTemplateDataChanges describes the differences between two launch template versions
+// that are most likely to have caused an update.
+func launchTemplateDataChanges(current, updated *ec2.ResponseLaunchTemplateData) []string {
+	var changes []string
+	if a, b := aws.StringValue(current.ImageId), aws.StringValue(updated.ImageId); a != b {
+		changes = append(changes, fmt.Sprintf("image changed from %q to %q", a, b))
+	}
+	if a, b := aws.StringValue(current.InstanceType), aws.StringValue(updated.InstanceType); a != b {
+		changes = append(changes, fmt.Sprintf("instance type changed from %q to %q", a, b))
+	}
+	if aws.StringValue(current.UserData) != aws.StringValue(updated.UserData) {
+		changes = append(changes, "user data changed")
+	}
+	return changes
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsup
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+type launchTemplateVersionsEC2 struct {
+	ec2iface.EC2API
+	versions map[string]*ec2.ResponseLaunchTemplateData
+	calls    int
+}
+
+func (m *launchTemplateVersionsEC2) DescribeLaunchTemplateVersions(input *ec2.DescribeLaunchTemplateVersionsInput) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	m.calls++
+	key := aws.StringValue(input.LaunchTemplateId) + ":" + aws.StringValue(input.Versions[0])
+	data, found := m.versions[key]
+	if !found {
+		return nil, fmt.Errorf("launch template version %q not found", key)
+	}
+	return &ec2.DescribeLaunchTemplateVersionsOutput{
+		LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{{LaunchTemplateData: data}},
+	}, nil
+}
+
+func TestUpdateReason(t *testing.T) {
+	cloud := BuildMockAWSCloud("us-east-1", "abc")
+	mockEC2 := &launchTemplateVersionsEC2{
+		versions: map[string]*ec2.ResponseLaunchTemplateData{
+			"lt-1:1": {ImageId: aws.String("ami-1"), InstanceType: aws.String("t3.medium"), UserData: aws.String("a")},
+			"lt-1:2": {ImageId: aws.String("ami-2"), InstanceType: aws.String("t3.medium"), UserData: aws.String("b")},
+			"lt-1:3": {ImageId: aws.String("ami-2"), InstanceType: aws.String("t3.medium"), UserData: aws.String("b"), EbsOptimized: aws.Bool(true)},
+			"lt-1:4": {ImageId: aws.String("ami-2"), InstanceType: aws.String("t3.large"), UserData: aws.String("b")},
+		},
+	}
+	cloud.MockEC2 = mockEC2
+
+	grid := []struct {
+		current  string
+		updated  string
+		expected string
+	}{
+		{
+			current:  "lt-1:1",
+			updated:  "lt-1:2",
+			expected: `image changed from "ami-1" to "ami-2", user data changed`,
+		},
+		{
+			current:  "lt-1:2",
+			updated:  "lt-1:3",
+			expected: "launch template version changed from 2 to 3",
+		},
+		{
+			current:  "lt-1:2",
+			updated:  "lt-1:4",
+			expected: `instance type changed from "t3.medium" to "t3.large"`,
+		},
+		{
+			current:  "lt-1:5",
+			updated:  "lt-1:4",
+			expected: "launch template version changed from 5 to 4",
+		},
+		{
+			current:  "lt-1:1",
+			updated:  "lt-2:1",
+			expected: `launch template changed from "lt-1" to "lt-2"`,
+		},
+		{
+			current:  "nodes.example.com-20210101",
+			updated:  "lt-1:1",
+			expected: `launch configuration changed from "nodes.example.com-20210101" to "lt-1:1"`,
+		},
+	}
+	finder := newUpdateReasonFinder(cloud)
+	for _, g := range grid {
+		actual := finder.reason(g.current, g.updated)
+		if actual != g.expected {
+			t.Errorf("unexpected reason for %s to %s: expected %q, got %q", g.current, g.updated, g.expected, actual)
+		}
+	}
+
+	calls := mockEC2.calls
+	finder.reason("lt-1:1", "lt-1:2")
+	if mockEC2.calls != calls {
+		t.Errorf("expected launch template versions to be cached")
+	}
+}
@@ -107,6 +107,16 @@ type VMScaleSet struct {
 	SKUName *string
 	// Capacity specifies the number of virtual machines the VM Scale Set.
 	Capacity *int64
+	// Zones are the availability zones the virtual machines are spread across.
+	Zones []string
+	// Priority is the priority of the virtual machines, either Regular or Spot.
+	Priority *string
+	// EvictionPolicy specifies whether Spot virtual machines are deleted or deallocated when they are evicted.
+	EvictionPolicy *string
+	// MaxPrice is the maximum hourly price of Spot virtual machines; -1 caps the price at the on-demand price.
+	MaxPrice *float64
+	// AcceleratedNetworking enables accelerated networking on the network interfaces.
+	AcceleratedNetworking *bool
 	// ComputerNamePrefix is the prefix of each VM name of the form <prefix><base-36-instance-id>.
 	// See https://docs.microsoft.com/en-us/azure/virtual-machine-scale-sets/virtual-machine-scale-sets-instance-ids.
 	ComputerNamePrefix *string
@@ -231,6 +241,21 @@ func (s *VMScaleSet) Find(c *fi.Context) (*VMScaleSet, error) {
 			Name: to.StringPtr(loadBalancerID.LoadBalancerName),
 		}
 	}
+	if found.Zones != nil && len(*found.Zones) > 0 {
+		vmss.Zones = *found.Zones
+	}
+	if profile.Priority != "" {
+		vmss.Priority = to.StringPtr(string(profile.Priority))
+	}
+	if profile.EvictionPolicy != "" {
+		vmss.EvictionPolicy = to.StringPtr(string(profile.EvictionPolicy))
+	}
+	if profile.BillingProfile != nil {
+		vmss.MaxPrice = profile.BillingProfile.MaxPrice
+	}
+	if to.Bool(nwConfig.EnableAcceleratedNetworking) {
+		vmss.AcceleratedNetworking = to.BoolPtr(true)
+	}
 	return vmss, nil
 }
 
@@ -254,6 +279,12 @@ func (s *VMScaleSet) CheckChanges(a, e, changes *VMScaleSet) error {
 	if changes.Name != nil {
 		return fi.CannotChangeField("Name")
 	}
+	if changes.Zones != nil {
+		return fi.CannotChangeField("Zones")
+	}
+	if changes.Priority != nil {
+		return fi.CannotChangeField("Priority")
+	}
 	return nil
 }
 
@@ -330,8 +361,9 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 	networkConfig := compute.VirtualMachineScaleSetNetworkConfiguration{
 		Name: to.StringPtr(name + "-netconfig"),
 		VirtualMachineScaleSetNetworkConfigurationProperties: &compute.VirtualMachineScaleSetNetworkConfigurationProperties{
-			Primary:            to.BoolPtr(true),
-			EnableIPForwarding: to.BoolPtr(true),
+			Primary:                     to.BoolPtr(true),
+			EnableIPForwarding:          to.BoolPtr(true),
+			EnableAcceleratedNetworking: e.AcceleratedNetworking,
 			IPConfigurations: &[]compute.VirtualMachineScaleSetIPConfiguration{
 				{
 					Name: to.StringPtr(name + "-ipconfig"),
@@ -341,6 +373,27 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 		},
 	}
 
+	vmProfile := &compute.VirtualMachineScaleSetVMProfile{
+		OsProfile:      osProfile,
+		StorageProfile: e.StorageProfile.VirtualMachineScaleSetStorageProfile,
+		NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
+			NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
+				networkConfig,
+			},
+		},
+	}
+	if e.Priority != nil {
+		vmProfile.Priority = compute.VirtualMachinePriorityTypes(*e.Priority)
+	}
+	if e.EvictionPolicy != nil {
+		vmProfile.EvictionPolicy = compute.VirtualMachineEvictionPolicyTypes(*e.EvictionPolicy)
+	}
+	if e.MaxPrice != nil {
+		vmProfile.BillingProfile = &compute.BillingProfile{
+			MaxPrice: e.MaxPrice,
+		}
+	}
+
 	vmss := compute.VirtualMachineScaleSet{
 		Location: to.StringPtr(t.Cloud.Region()),
 		Sku: &compute.Sku{
@@ -351,15 +404,7 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 			UpgradePolicy: &compute.UpgradePolicy{
 				Mode: compute.UpgradeModeManual,
 			},
-			VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
-				OsProfile:      osProfile,
-				StorageProfile: e.StorageProfile.VirtualMachineScaleSetStorageProfile,
-				NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
-					NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
-						networkConfig,
-					},
-				},
-			},
+			VirtualMachineProfile: vmProfile,
 		},
 		// Assign a system-assigned managed identity so that
 		// Azure creates an identity for VMs and provision
@@ -369,6 +414,9 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 		},
 		Tags: e.Tags,
 	}
+	if len(e.Zones) > 0 {
+		vmss.Zones = &e.Zones
+	}
 
 	result, err := t.Cloud.VMScaleSet().CreateOrUpdate(
 		context.TODO(),
@@ -130,6 +130,40 @@ func TestVMScaleSetRenderAzure(t *testing.T) {
 	}
 }
 
+func TestVMScaleSetRenderAzureSpot(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	apiTarget := azure.NewAzureAPITarget(cloud)
+	vmss := &VMScaleSet{}
+	expected := newTestVMScaleSet()
+	expected.Zones = []string{"1", "2"}
+	expected.Priority = to.StringPtr(string(compute.Spot))
+	expected.EvictionPolicy = to.StringPtr(string(compute.Delete))
+	expected.MaxPrice = to.Float64Ptr(-1)
+	expected.AcceleratedNetworking = to.BoolPtr(true)
+	if err := vmss.RenderAzure(apiTarget, nil, expected, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	actual := cloud.VMScaleSetsClient.VMSSes[*expected.Name]
+	if a, e := *actual.Zones, expected.Zones; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected zones: expected %v, but got %v", e, a)
+	}
+	profile := actual.VirtualMachineProfile
+	if a, e := profile.Priority, compute.Spot; a != e {
+		t.Errorf("unexpected priority: expected %s, but got %s", e, a)
+	}
+	if a, e := profile.EvictionPolicy, compute.Delete; a != e {
+		t.Errorf("unexpected eviction policy: expected %s, but got %s", e, a)
+	}
+	if a, e := *profile.BillingProfile.MaxPrice, float64(-1); a != e {
+		t.Errorf("unexpected max price: expected %f, but got %f", e, a)
+	}
+	nwConfig := (*profile.NetworkProfile.NetworkInterfaceConfigurations)[0]
+	if !*nwConfig.EnableAcceleratedNetworking {
+		t.Errorf("expected accelerated networking to be enabled")
+	}
+}
+
 func TestVMScaleSetFind(t *testing.T) {
 	cloud := NewMockAzureCloud("eastus")
 	ctx := &fi.Context{
@@ -337,6 +371,16 @@ func TestVMScaleSetCheckChanges(t *testing.T) {
 			changes: &VMScaleSet{Name: to.StringPtr("newName")},
 			success: false,
 		},
+		{
+			a:       &VMScaleSet{Name: to.StringPtr("name")},
+			changes: &VMScaleSet{Zones: []string{"1"}},
+			success: false,
+		},
+		{
+			a:       &VMScaleSet{Name: to.StringPtr("name")},
+			changes: &VMScaleSet{Priority: to.StringPtr(string(compute.Spot))},
+			success: false,
+		},
 	}
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
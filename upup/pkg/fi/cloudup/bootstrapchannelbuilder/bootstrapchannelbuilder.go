@@ -408,6 +408,28 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 		}
 	}
 
+	if b.Cluster.Spec.Authorization != nil && b.Cluster.Spec.Authorization.RBAC != nil && len(b.Cluster.Spec.Authorization.RBAC.Bindings) != 0 {
+		key := "rbac-bindings.addons.k8s.io"
+
+		{
+			location := key + "/k8s-1.16.yaml"
+			id := "k8s-1.16"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:     fi.String(key),
+				Selector: map[string]string{"k8s-addon": key},
+				Manifest: fi.String(location),
+				Id:       id,
+				Prune: &channelsapi.PruneSpec{
+					Kinds: []channelsapi.PruneKindSpec{
+						{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+						{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"},
+					},
+				},
+			})
+		}
+	}
+
 	{
 		key := "limit-range.addons.k8s.io"
 		version := "1.5.0"
@@ -506,6 +528,42 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 
 	}
 
+	if b.Cluster.Spec.Descheduler != nil && fi.BoolValue(b.Cluster.Spec.Descheduler.Enabled) {
+		{
+			key := "descheduler.addons.k8s.io"
+
+			{
+				location := key + "/k8s-1.18.yaml"
+				id := "k8s-1.18"
+
+				addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+					Name:     fi.String(key),
+					Selector: map[string]string{"k8s-addon": key},
+					Manifest: fi.String(location),
+					Id:       id,
+				})
+			}
+		}
+	}
+
+	if b.Cluster.Spec.VerticalPodAutoscaler != nil && fi.BoolValue(b.Cluster.Spec.VerticalPodAutoscaler.Enabled) {
+		{
+			key := "vertical-pod-autoscaler.addons.k8s.io"
+
+			{
+				location := key + "/k8s-1.16.yaml"
+				id := "k8s-1.16"
+
+				addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+					Name:     fi.String(key),
+					Selector: map[string]string{"k8s-addon": key},
+					Manifest: fi.String(location),
+					Id:       id,
+				})
+			}
+		}
+	}
+
 	if b.Cluster.Spec.MetricsServer != nil && fi.BoolValue(b.Cluster.Spec.MetricsServer.Enabled) {
 		{
 			key := "metrics-server.addons.k8s.io"
@@ -828,6 +886,21 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 				location := key + "/k8s-1.12.yaml"
 				id := "k8s-1.12"
 
+				addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+					Name:     fi.String(key),
+					Selector: authenticationSelector,
+					Manifest: fi.String(location),
+					Id:       id,
+				})
+			}
+		}
+		if b.Cluster.Spec.Authentication.OIDC != nil && b.Cluster.Spec.Authentication.OIDC.Dex != nil {
+			key := "authentication.dex"
+
+			{
+				location := key + "/k8s-1.16.yaml"
+				id := "k8s-1.16"
+
 				addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
 					Name:     fi.String(key),
 					Selector: authenticationSelector,
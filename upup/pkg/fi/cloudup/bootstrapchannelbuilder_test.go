@@ -51,6 +51,9 @@ func TestBootstrapChannelBuilder_BuildTasks(t *testing.T) {
 	runChannelBuilderTest(t, "amazonvpc", []string{"networking.amazon-vpc-routed-eni-k8s-1.16"})
 	runChannelBuilderTest(t, "amazonvpc-containerd", []string{"networking.amazon-vpc-routed-eni-k8s-1.16"})
 	runChannelBuilderTest(t, "awsiamauthenticator", []string{"authentication.aws-k8s-1.12"})
+	runChannelBuilderTest(t, "descheduler", []string{"descheduler.addons.k8s.io-k8s-1.18"})
+	runChannelBuilderTest(t, "rbacbindings", []string{"rbac-bindings.addons.k8s.io-k8s-1.16"})
+	runChannelBuilderTest(t, "verticalpodautoscaler", []string{"kops-controller.addons.k8s.io-k8s-1.16", "vertical-pod-autoscaler.addons.k8s.io-k8s-1.16"})
 }
 
 func TestBootstrapChannelBuilder_ServiceAccountIAM(t *testing.T) {
@@ -20,10 +20,13 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/architectures"
 )
 
@@ -341,10 +344,94 @@ func TestContainerdConfig(t *testing.T) {
 	}
 	config := &nodeup.Config{}
 
-	config.ContainerdConfig = buildContainerdConfig(cluster)
+	config.ContainerdConfig = buildContainerdConfig(cluster, nil)
 
 	if config.ContainerdConfig == "" {
 		t.Errorf("got unexpected empty containerd config")
 	}
 
 }
+
+func TestContainerdConfigOptions(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			ContainerRuntime: "containerd",
+			Containerd: &kops.ContainerdConfig{
+				CgroupDriver: fi.String("cgroupfs"),
+				NRI: &kops.ContainerdNRIConfig{
+					Enabled:    true,
+					PluginPath: fi.String("/opt/nri/plugins"),
+				},
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {
+						Mirrors: []string{"https://mirror.example.com"},
+					},
+				},
+				SandboxImage: fi.String("registry.example.com/pause:3.5"),
+			},
+			KubernetesVersion: "1.21.0",
+			Networking: &kops.NetworkingSpec{
+				Cilium: &kops.CiliumNetworkingSpec{},
+			},
+		},
+	}
+
+	expected := `version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+    sandbox_image = "registry.example.com/pause:3.5"
+
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+            SystemdCgroup = false
+
+    [plugins."io.containerd.grpc.v1.cri".registry]
+
+      [plugins."io.containerd.grpc.v1.cri".registry.mirrors]
+
+        [plugins."io.containerd.grpc.v1.cri".registry.mirrors."docker.io"]
+          endpoint = ["https://mirror.example.com"]
+
+  [plugins."io.containerd.nri.v1.nri"]
+    disable = false
+    plugin_path = "/opt/nri/plugins"
+`
+	if actual := buildContainerdConfig(cluster, nil); actual != expected {
+		t.Errorf("unexpected containerd config\n%s", diff.FormatDiff(expected, actual))
+	}
+}
+
+func TestContainerdConfigSELinux(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			ContainerRuntime:  "containerd",
+			Containerd:        &kops.ContainerdConfig{},
+			KubernetesVersion: "1.21.0",
+			Networking: &kops.NetworkingSpec{
+				Cilium: &kops.CiliumNetworkingSpec{},
+			},
+		},
+	}
+	ig := &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			SELinux: &kops.SELinuxSpec{
+				Mode: kops.SELinuxModeEnforcing,
+			},
+		},
+	}
+
+	if actual := buildContainerdConfig(cluster, ig); !strings.Contains(actual, "enable_selinux = true") {
+		t.Errorf("expected SELinux to be enabled in containerd config\n%s", actual)
+	}
+	if actual := buildContainerdConfig(cluster, nil); strings.Contains(actual, "enable_selinux") {
+		t.Errorf("expected SELinux not to be configured in containerd config\n%s", actual)
+	}
+}
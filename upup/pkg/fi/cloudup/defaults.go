@@ -99,9 +99,9 @@ func PerformAssignments(c *kops.Cluster, cloud fi.Cloud) error {
 		c.Spec.MasterPublicName = "api." + c.ObjectMeta.Name
 	}
 
-	// We only assign subnet CIDRs on AWS, OpenStack, Ali, and Azure.
+	// We only assign subnet CIDRs on AWS, OpenStack, Ali, Azure, and OCI.
 	pd := cloud.ProviderID()
-	if pd == kops.CloudProviderAWS || pd == kops.CloudProviderOpenstack || pd == kops.CloudProviderALI || pd == kops.CloudProviderAzure {
+	if pd == kops.CloudProviderAWS || pd == kops.CloudProviderOpenstack || pd == kops.CloudProviderALI || pd == kops.CloudProviderAzure || pd == kops.CloudProviderOCI {
 		// TODO: Use vpcInfo
 		err := assignCIDRsToSubnets(c, cloud)
 		if err != nil {
@@ -190,10 +190,14 @@ func assignProxy(cluster *kops.Cluster) (*kops.EgressProxySpec, error) {
 			"127.0.0.1",
 			"localhost",
 			cluster.Spec.ClusterDNSDomain, // TODO we may want this for public loadbalancers
+			".svc",
 			cluster.Spec.MasterPublicName,
+			cluster.Spec.MasterInternalName,
 			cluster.ObjectMeta.Name,
 			firstIP,
 			cluster.Spec.NonMasqueradeCIDR,
+			cluster.Spec.ServiceClusterIPRange,
+			cluster.Spec.PodCIDR,
 		} {
 			if exclude == "" {
 				continue
@@ -218,6 +222,11 @@ func assignProxy(cluster *kops.Cluster) (*kops.EgressProxySpec, error) {
 		} else {
 			klog.Warningf("No NetworkCIDR defined (yet), not adding to egressProxy.excludes")
 		}
+		for _, cidr := range cluster.Spec.AdditionalNetworkCIDRs {
+			if !strings.Contains(cluster.Spec.EgressProxy.ProxyExcludes, cidr) {
+				egressSlice = append(egressSlice, cidr)
+			}
+		}
 
 		egressProxy.ProxyExcludes = strings.Join(egressSlice, ",")
 		klog.V(8).Infof("Completed setting up Proxy excludes as follows: %q", egressProxy.ProxyExcludes)
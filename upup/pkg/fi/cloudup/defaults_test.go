@@ -41,7 +41,7 @@ func TestPopulateClusterSpec_Proxy(t *testing.T) {
 		t.Fatalf("unable to assign proxy, %v", err)
 	}
 
-	expectedExcludes := "google.com,127.0.0.1,localhost,api.testcluster.test.com,testcluster.test.com,100.64.0.2,100.64.0.1/10,169.254.169.254,192.168.0.0/20"
+	expectedExcludes := "google.com,127.0.0.1,localhost,.svc,api.testcluster.test.com,internal.api.testcluster.test.com,testcluster.test.com,100.64.0.2,100.64.0.1/10,169.254.169.254,192.168.0.0/20"
 	if c.Spec.EgressProxy.ProxyExcludes != expectedExcludes {
 		t.Fatalf("Incorrect proxy excludes set: %v, expected %v", c.Spec.EgressProxy.ProxyExcludes, expectedExcludes)
 	}
@@ -62,7 +62,7 @@ func TestPopulateClusterSpec_Proxy(t *testing.T) {
 		t.Fatalf("unable to assign proxy, %v", err)
 	}
 
-	expectedExcludes = "127.0.0.1,localhost,api.testcluster.test.com,testcluster.test.com,100.64.0.1,100.64.0.0/10,169.254.169.254,192.168.0.0/20"
+	expectedExcludes = "127.0.0.1,localhost,.svc,api.testcluster.test.com,internal.api.testcluster.test.com,testcluster.test.com,100.64.0.1,100.64.0.0/10,169.254.169.254,192.168.0.0/20"
 	if c.Spec.EgressProxy.ProxyExcludes != expectedExcludes {
 		t.Fatalf("Incorrect proxy excludes set: %v, expected %v", c.Spec.EgressProxy.ProxyExcludes, expectedExcludes)
 	}
@@ -76,7 +76,7 @@ func TestPopulateClusterSpec_Proxy(t *testing.T) {
 		t.Fatalf("unable to assign proxy, %v", err)
 	}
 
-	expectedExcludes = "127.0.0.1,localhost,api.testcluster.test.com,testcluster.test.com,172.16.0.6,172.16.0.5/12,192.168.0.0/20"
+	expectedExcludes = "127.0.0.1,localhost,.svc,api.testcluster.test.com,internal.api.testcluster.test.com,testcluster.test.com,172.16.0.6,172.16.0.5/12,192.168.0.0/20"
 	if c.Spec.EgressProxy.ProxyExcludes != expectedExcludes {
 		t.Fatalf("Incorrect proxy excludes set: %v", c.Spec.EgressProxy.ProxyExcludes)
 	}
@@ -87,9 +87,24 @@ func TestPopulateClusterSpec_Proxy(t *testing.T) {
 		t.Fatalf("unable to assign proxy, %v", err)
 	}
 
-	expectedExcludes = "127.0.0.1,localhost,api.testcluster.test.com,testcluster.test.com,172.16.0.6,172.16.0.5/12,192.168.0.0/20"
+	expectedExcludes = "127.0.0.1,localhost,.svc,api.testcluster.test.com,internal.api.testcluster.test.com,testcluster.test.com,172.16.0.6,172.16.0.5/12,192.168.0.0/20"
 	if c.Spec.EgressProxy.ProxyExcludes != expectedExcludes {
 		t.Fatalf("Incorrect proxy excludes set during idempotency check: %v    should have been %v", c.Spec.EgressProxy.ProxyExcludes, expectedExcludes)
 	}
 
+	// cluster networks outside of the non masquerade range
+	c.Spec.NonMasqueradeCIDR = "100.64.0.0/10"
+	c.Spec.ServiceClusterIPRange = "10.96.0.0/16"
+	c.Spec.PodCIDR = "10.100.0.0/16"
+	c.Spec.AdditionalNetworkCIDRs = []string{"192.168.16.0/20"}
+	c.Spec.EgressProxy.ProxyExcludes = ""
+	c.Spec.EgressProxy, err = assignProxy(c)
+	if err != nil {
+		t.Fatalf("unable to assign proxy, %v", err)
+	}
+
+	expectedExcludes = "127.0.0.1,localhost,.svc,api.testcluster.test.com,internal.api.testcluster.test.com,testcluster.test.com,100.64.0.1,100.64.0.0/10,10.96.0.0/16,10.100.0.0/16,192.168.0.0/20,192.168.16.0/20"
+	if c.Spec.EgressProxy.ProxyExcludes != expectedExcludes {
+		t.Fatalf("Incorrect proxy excludes set: %v, expected %v", c.Spec.EgressProxy.ProxyExcludes, expectedExcludes)
+	}
 }
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package do
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// capabilities describes the optional features that kOps supports on DigitalOcean.
+type capabilities struct{}
+
+var _ fi.CloudCapabilities = capabilities{}
+
+// Capabilities returns the optional features that kOps supports on DigitalOcean.
+func Capabilities() fi.CloudCapabilities {
+	return capabilities{}
+}
+
+func (capabilities) SupportsAPILoadBalancer() bool {
+	return true
+}
+
+func (capabilities) LoadBalancerClasses() []kops.LoadBalancerClass {
+	return nil
+}
+
+func (capabilities) SupportsIPv6() bool {
+	return false
+}
+
+func (capabilities) SupportsSpotInstances() bool {
+	return false
+}
+
+func (capabilities) SupportsMixedInstancesPolicy() bool {
+	return false
+}
+
+func (capabilities) SupportsWarmPool() bool {
+	return false
+}
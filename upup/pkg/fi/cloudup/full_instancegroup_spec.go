@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/model/awsmodel"
+	"k8s.io/kops/pkg/model/defaults"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// FullInstanceGroupSpec returns the instance group with the defaults kOps applies to it when building the cluster:
+// the populated spec, the kubelet configuration merged from the cluster, and the root volume and instance metadata defaults.
+// The cluster must be fully populated.
+func FullInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup, cloud fi.Cloud, channel *kops.Channel) (*kops.InstanceGroup, error) {
+	ig, err := PopulateInstanceGroupSpec(cluster, input, cloud, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _ := nodeup.NewConfig(cluster, ig)
+	kubelet := config.KubeletConfig
+
+	// Nodes using the AWS VPC CNI are limited by the number of IPs of their instance type
+	if cluster.Spec.Networking != nil && cluster.Spec.Networking.AmazonVPC != nil {
+		awsCloud, ok := cloud.(awsup.AWSCloud)
+		if !ok {
+			return nil, fmt.Errorf("amazon VPC networking is only supported on AWS")
+		}
+		instanceType, err := awsup.GetMachineTypeInfo(awsCloud, strings.Split(ig.Spec.MachineType, ",")[0])
+		if err != nil {
+			return nil, err
+		}
+		maxPods := defaults.AmazonVPCMaxPods(kubelet.MaxPods, instanceType.InstanceENIs, instanceType.InstanceIPsPerENI)
+		kubelet.MaxPods = &maxPods
+	}
+	ig.Spec.Kubelet = &kubelet
+
+	rootVolumeSize, err := defaults.InstanceGroupVolumeSize(ig)
+	if err != nil {
+		return nil, err
+	}
+	ig.Spec.RootVolumeSize = fi.Int32(rootVolumeSize)
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderAWS {
+		if fi.StringValue(ig.Spec.RootVolumeType) == "" {
+			ig.Spec.RootVolumeType = fi.String(awsmodel.DefaultVolumeType)
+		}
+		if ig.Spec.RootVolumeEncryption == nil {
+			ig.Spec.RootVolumeEncryption = fi.Bool(awsmodel.DefaultVolumeEncryption)
+		}
+
+		if ig.Spec.InstanceMetadata == nil {
+			ig.Spec.InstanceMetadata = &kops.InstanceMetadataOptions{}
+		}
+		if ig.Spec.InstanceMetadata.HTTPPutResponseHopLimit == nil {
+			ig.Spec.InstanceMetadata.HTTPPutResponseHopLimit = fi.Int64(1)
+		}
+		if ig.Spec.InstanceMetadata.HTTPTokens == nil {
+			ig.Spec.InstanceMetadata.HTTPTokens = fi.String(ec2.LaunchTemplateHttpTokensStateOptional)
+		}
+	}
+
+	return ig, nil
+}
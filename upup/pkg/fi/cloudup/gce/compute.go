@@ -39,6 +39,7 @@ type ComputeClient interface {
 	Instances() InstanceClient
 	InstanceTemplates() InstanceTemplateClient
 	InstanceGroupManagers() InstanceGroupManagerClient
+	RegionInstanceGroupManagers() RegionInstanceGroupManagerClient
 	TargetPools() TargetPoolClient
 
 	Disks() DiskClient
@@ -138,6 +139,12 @@ func (c *computeClientImpl) InstanceGroupManagers() InstanceGroupManagerClient {
 	}
 }
 
+func (c *computeClientImpl) RegionInstanceGroupManagers() RegionInstanceGroupManagerClient {
+	return &regionInstanceGroupManagerClientImpl{
+		srv: c.srv.RegionInstanceGroupManagers,
+	}
+}
+
 func (c *computeClientImpl) TargetPools() TargetPoolClient {
 	return &targetPoolClientImpl{
 		srv: c.srv.TargetPools,
@@ -535,6 +542,7 @@ type InstanceGroupManagerClient interface {
 	SetTargetPools(project, zone, name string, targetPools []string) (*compute.Operation, error)
 	SetInstanceTemplate(project, zone, name, instanceTemplateURL string) (*compute.Operation, error)
 	Resize(project, zone, name string, newSize int64) (*compute.Operation, error)
+	Patch(project, zone, name string, i *compute.InstanceGroupManager) (*compute.Operation, error)
 }
 
 type instanceGroupManagerClientImpl struct {
@@ -604,6 +612,95 @@ func (c *instanceGroupManagerClientImpl) Resize(project, zone, name string, newS
 	return c.srv.Resize(project, zone, name, newSize).Do()
 }
 
+func (c *instanceGroupManagerClientImpl) Patch(project, zone, name string, i *compute.InstanceGroupManager) (*compute.Operation, error) {
+	return c.srv.Patch(project, zone, name, i).Do()
+}
+
+type RegionInstanceGroupManagerClient interface {
+	Insert(project, region string, i *compute.InstanceGroupManager) (*compute.Operation, error)
+	Delete(project, region, name string) (*compute.Operation, error)
+	Get(project, region, name string) (*compute.InstanceGroupManager, error)
+	List(ctx context.Context, project, region string) ([]*compute.InstanceGroupManager, error)
+	ListManagedInstances(ctx context.Context, project, region, name string) ([]*compute.ManagedInstance, error)
+
+	RecreateInstances(project, region, name, id string) (*compute.Operation, error)
+	SetTargetPools(project, region, name string, targetPools []string) (*compute.Operation, error)
+	SetInstanceTemplate(project, region, name, instanceTemplateURL string) (*compute.Operation, error)
+	Resize(project, region, name string, newSize int64) (*compute.Operation, error)
+	Patch(project, region, name string, i *compute.InstanceGroupManager) (*compute.Operation, error)
+}
+
+type regionInstanceGroupManagerClientImpl struct {
+	srv *compute.RegionInstanceGroupManagersService
+}
+
+var _ RegionInstanceGroupManagerClient = &regionInstanceGroupManagerClientImpl{}
+
+func (c *regionInstanceGroupManagerClientImpl) Insert(project, region string, i *compute.InstanceGroupManager) (*compute.Operation, error) {
+	return c.srv.Insert(project, region, i).Do()
+}
+
+func (c *regionInstanceGroupManagerClientImpl) Delete(project, region, name string) (*compute.Operation, error) {
+	return c.srv.Delete(project, region, name).Do()
+}
+
+func (c *regionInstanceGroupManagerClientImpl) Get(project, region, name string) (*compute.InstanceGroupManager, error) {
+	return c.srv.Get(project, region, name).Do()
+}
+
+func (c *regionInstanceGroupManagerClientImpl) List(ctx context.Context, project, region string) ([]*compute.InstanceGroupManager, error) {
+	var ms []*compute.InstanceGroupManager
+	if err := c.srv.List(project, region).Pages(ctx, func(page *compute.RegionInstanceGroupManagerList) error {
+		ms = append(ms, page.Items...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+func (c *regionInstanceGroupManagerClientImpl) ListManagedInstances(ctx context.Context, project, region, name string) ([]*compute.ManagedInstance, error) {
+	var instances []*compute.ManagedInstance
+	if err := c.srv.ListManagedInstances(project, region, name).Pages(ctx, func(page *compute.RegionInstanceGroupManagersListInstancesResponse) error {
+		instances = append(instances, page.ManagedInstances...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
+func (c *regionInstanceGroupManagerClientImpl) RecreateInstances(project, region, name, id string) (*compute.Operation, error) {
+	req := &compute.RegionInstanceGroupManagersRecreateRequest{
+		Instances: []string{
+			id,
+		},
+	}
+	return c.srv.RecreateInstances(project, region, name, req).Do()
+}
+
+func (c *regionInstanceGroupManagerClientImpl) SetTargetPools(project, region, name string, targetPools []string) (*compute.Operation, error) {
+	req := &compute.RegionInstanceGroupManagersSetTargetPoolsRequest{
+		TargetPools: targetPools,
+	}
+	return c.srv.SetTargetPools(project, region, name, req).Do()
+}
+
+func (c *regionInstanceGroupManagerClientImpl) SetInstanceTemplate(project, region, name, instanceTemplateURL string) (*compute.Operation, error) {
+	req := &compute.RegionInstanceGroupManagersSetTemplateRequest{
+		InstanceTemplate: instanceTemplateURL,
+	}
+	return c.srv.SetInstanceTemplate(project, region, name, req).Do()
+}
+
+func (c *regionInstanceGroupManagerClientImpl) Resize(project, region, name string, newSize int64) (*compute.Operation, error) {
+	return c.srv.Resize(project, region, name, newSize).Do()
+}
+
+func (c *regionInstanceGroupManagerClientImpl) Patch(project, region, name string, i *compute.InstanceGroupManager) (*compute.Operation, error) {
+	return c.srv.Patch(project, region, name, i).Do()
+}
+
 type TargetPoolClient interface {
 	Insert(project, region string, tp *compute.TargetPool) (*compute.Operation, error)
 	Delete(project, region, name string) (*compute.Operation, error)
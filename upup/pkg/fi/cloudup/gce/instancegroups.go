@@ -43,6 +43,15 @@ func deleteCloudInstanceGroup(c GCECloud, g *cloudinstances.CloudInstanceGroup)
 		return err
 	}
 
+	for _, version := range mig.Versions {
+		if version.InstanceTemplate == mig.InstanceTemplate {
+			continue
+		}
+		if err := DeleteInstanceTemplate(c, version.InstanceTemplate); err != nil {
+			return err
+		}
+	}
+
 	return DeleteInstanceTemplate(c, mig.InstanceTemplate)
 }
 
@@ -68,7 +77,12 @@ func recreateCloudInstance(c GCECloud, i *cloudinstances.CloudInstance) error {
 		return err
 	}
 
-	op, err := c.Compute().InstanceGroupManagers().RecreateInstances(migURL.Project, migURL.Zone, migURL.Name, i.ID)
+	var op *compute.Operation
+	if migURL.Region != "" {
+		op, err = c.Compute().RegionInstanceGroupManagers().RecreateInstances(migURL.Project, migURL.Region, migURL.Name, i.ID)
+	} else {
+		op, err = c.Compute().InstanceGroupManagers().RecreateInstances(migURL.Project, migURL.Zone, migURL.Name, i.ID)
+	}
 	if err != nil {
 		if IsNotFound(err) {
 			klog.Infof("Instance not found, assuming deleted: %q", i.ID)
@@ -122,73 +136,89 @@ func getCloudGroups(c GCECloud, cluster *kops.Cluster, instancegroups []*kops.In
 		return nil, err
 	}
 
+	var migs []*compute.InstanceGroupManager
 	for _, zoneName := range zones {
-		migs, err := c.Compute().InstanceGroupManagers().List(ctx, project, zoneName)
+		zoneMIGs, err := c.Compute().InstanceGroupManagers().List(ctx, project, zoneName)
 		if err != nil {
 			return nil, fmt.Errorf("error listing InstanceGroupManagers: %v", err)
 		}
-		for _, mig := range migs {
-			name := mig.Name
+		migs = append(migs, zoneMIGs...)
+	}
+	{
+		regionMIGs, err := c.Compute().RegionInstanceGroupManagers().List(ctx, project, c.Region())
+		if err != nil {
+			return nil, fmt.Errorf("error listing regional InstanceGroupManagers: %v", err)
+		}
+		migs = append(migs, regionMIGs...)
+	}
 
-			instanceTemplate := instanceTemplates[mig.InstanceTemplate]
-			if instanceTemplate == nil {
-				klog.V(2).Infof("ignoring MIG %s with unmanaged InstanceTemplate: %s", name, mig.InstanceTemplate)
-				continue
-			}
+	for _, mig := range migs {
+		name := mig.Name
 
-			ig, err := matchInstanceGroup(mig, cluster, instancegroups)
-			if err != nil {
-				return nil, fmt.Errorf("error getting instance group for MIG %q", name)
-			}
-			if ig == nil {
-				if warnUnmatched {
-					klog.Warningf("Found MIG with no corresponding instance group %q", name)
-				}
-				continue
-			}
+		instanceTemplate := instanceTemplates[mig.InstanceTemplate]
+		if instanceTemplate == nil {
+			klog.V(2).Infof("ignoring MIG %s with unmanaged InstanceTemplate: %s", name, mig.InstanceTemplate)
+			continue
+		}
 
-			g := &cloudinstances.CloudInstanceGroup{
-				HumanName:     mig.Name,
-				InstanceGroup: ig,
-				MinSize:       int(mig.TargetSize),
-				TargetSize:    int(mig.TargetSize),
-				MaxSize:       int(mig.TargetSize),
-				Raw:           mig,
+		ig, err := matchInstanceGroup(mig, cluster, instancegroups)
+		if err != nil {
+			return nil, fmt.Errorf("error getting instance group for MIG %q", name)
+		}
+		if ig == nil {
+			if warnUnmatched {
+				klog.Warningf("Found MIG with no corresponding instance group %q", name)
 			}
-			groups[mig.Name] = g
+			continue
+		}
+
+		g := &cloudinstances.CloudInstanceGroup{
+			HumanName:     mig.Name,
+			InstanceGroup: ig,
+			MinSize:       int(mig.TargetSize),
+			TargetSize:    int(mig.TargetSize),
+			MaxSize:       int(mig.TargetSize),
+			Raw:           mig,
+		}
+		groups[mig.Name] = g
+
+		// Instances are up to date if they use the instance template of any version of the MIG
+		latestInstanceTemplates := map[string]bool{mig.InstanceTemplate: true}
+		for _, version := range mig.Versions {
+			latestInstanceTemplates[version.InstanceTemplate] = true
+		}
+
+		instances, err := ListManagedInstances(c, mig)
+		if err != nil {
+			return nil, err
+		}
 
-			latestInstanceTemplate := mig.InstanceTemplate
+		for _, i := range instances {
+			id := i.Instance
+			cm := &cloudinstances.CloudInstance{
+				ID:                 id,
+				CloudInstanceGroup: g,
+			}
 
-			instances, err := ListManagedInstances(c, mig)
-			if err != nil {
-				return nil, err
+			// Try first by provider ID
+			// The zone is taken from the instance, as instances of a regional MIG are spread over several zones
+			var node *v1.Node
+			if u, err := ParseGoogleCloudURL(id); err == nil {
+				providerID := "gce://" + project + "/" + u.Zone + "/" + u.Name
+				node = nodesByProviderID[providerID]
 			}
 
-			for _, i := range instances {
-				id := i.Instance
-				cm := &cloudinstances.CloudInstance{
-					ID:                 id,
-					CloudInstanceGroup: g,
-				}
-
-				// Try first by provider ID
-				name := LastComponent(id)
-				providerID := "gce://" + project + "/" + zoneName + "/" + name
-				node := nodesByProviderID[providerID]
-
-				if node != nil {
-					cm.Node = node
-				} else {
-					klog.V(8).Infof("unable to find node for instance: %s", id)
-				}
-
-				if i.Version != nil && latestInstanceTemplate == i.Version.InstanceTemplate {
-					g.Ready = append(g.Ready, cm)
-				} else {
-					g.NeedUpdate = append(g.NeedUpdate, cm)
-				}
+			if node != nil {
+				cm.Node = node
+			} else {
+				klog.V(8).Infof("unable to find node for instance: %s", id)
 			}
 
+			if i.Version != nil && latestInstanceTemplates[i.Version.InstanceTemplate] {
+				g.Ready = append(g.Ready, cm)
+			} else {
+				g.NeedUpdate = append(g.NeedUpdate, cm)
+			}
 		}
 	}
 
@@ -207,6 +237,13 @@ func NameForInstanceGroupManager(c *kops.Cluster, ig *kops.InstanceGroup, zone s
 	return name
 }
 
+// NameForRegionalInstanceGroupManager builds a name for a regional InstanceGroupManager
+func NameForRegionalInstanceGroupManager(c *kops.Cluster, ig *kops.InstanceGroup) string {
+	name := SafeObjectName(ig.ObjectMeta.Name, c.ObjectMeta.Name)
+	name = LimitedLengthName(name, 63)
+	return name
+}
+
 // LimitedLengthName returns a string subject to a maximum length
 func LimitedLengthName(s string, n int) string {
 	// We only use the hash if we need to
@@ -238,7 +275,12 @@ func matchInstanceGroup(mig *compute.InstanceGroupManager, c *kops.Cluster, inst
 	migName := LastComponent(mig.Name)
 	var matches []*kops.InstanceGroup
 	for _, ig := range instancegroups {
-		name := NameForInstanceGroupManager(c, ig, LastComponent(mig.Zone))
+		var name string
+		if mig.Region != "" {
+			name = NameForRegionalInstanceGroupManager(c, ig)
+		} else {
+			name = NameForInstanceGroupManager(c, ig, LastComponent(mig.Zone))
+		}
 		if name == migName {
 			matches = append(matches, ig)
 		}
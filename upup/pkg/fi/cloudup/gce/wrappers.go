@@ -32,7 +32,12 @@ func DeleteInstanceGroupManager(c GCECloud, t *compute.InstanceGroupManager) err
 		return err
 	}
 
-	op, err := c.Compute().InstanceGroupManagers().Delete(u.Project, u.Zone, u.Name)
+	var op *compute.Operation
+	if u.Region != "" {
+		op, err = c.Compute().RegionInstanceGroupManagers().Delete(u.Project, u.Region, u.Name)
+	} else {
+		op, err = c.Compute().InstanceGroupManagers().Delete(u.Project, u.Zone, u.Name)
+	}
 	if err != nil {
 		if IsNotFound(err) {
 			klog.Infof("InstanceGroupManager not found, assuming deleted: %q", t.SelfLink)
@@ -89,8 +94,6 @@ func ListManagedInstances(c GCECloud, igm *compute.InstanceGroupManager) ([]*com
 	ctx := context.Background()
 	project := c.Project()
 
-	zoneName := LastComponent(igm.Zone)
-
 	// TODO: Only select a subset of fields
 	//	req.Fields(
 	//		googleapi.Field("items/selfLink"),
@@ -98,7 +101,13 @@ func ListManagedInstances(c GCECloud, igm *compute.InstanceGroupManager) ([]*com
 	//		googleapi.Field("items/metadata/items[key='instance-template']"),
 	//	)
 
-	instances, err := c.Compute().InstanceGroupManagers().ListManagedInstances(ctx, project, zoneName, igm.Name)
+	var instances []*compute.ManagedInstance
+	var err error
+	if igm.Region != "" {
+		instances, err = c.Compute().RegionInstanceGroupManagers().ListManagedInstances(ctx, project, LastComponent(igm.Region), igm.Name)
+	} else {
+		instances, err = c.Compute().InstanceGroupManagers().ListManagedInstances(ctx, project, LastComponent(igm.Zone), igm.Name)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error listing ManagedInstances in %s: %v", igm.Name, err)
 	}
@@ -19,6 +19,7 @@ package gcetasks
 import (
 	"fmt"
 	"reflect"
+	"sort"
 
 	compute "google.golang.org/api/compute/v1"
 	"k8s.io/kops/upup/pkg/fi"
@@ -32,11 +33,23 @@ type InstanceGroupManager struct {
 	Name      *string
 	Lifecycle fi.Lifecycle
 
-	Zone             *string
+	Zone *string
+	// Region is set instead of Zone for a regional InstanceGroupManager
+	Region *string
+	// Zones are the zones over which a regional InstanceGroupManager distributes instances
+	Zones []string
+
 	BaseInstanceName *string
 	InstanceTemplate *InstanceTemplate
 	TargetSize       *int64
 
+	// AdditionalInstanceTemplates are used for a share of the instances, to mix machine types or provisioning models.
+	// InstanceTemplate is used for the instances that are not assigned to an additional instance template.
+	// An empty (non-nil) list removes any additional instance templates.
+	AdditionalInstanceTemplates []*InstanceTemplate
+	// AdditionalTargetSizePercents are the percentages of the instances using each of the AdditionalInstanceTemplates
+	AdditionalTargetSizePercents []int64
+
 	TargetPools []*TargetPool
 }
 
@@ -49,7 +62,13 @@ func (e *InstanceGroupManager) CompareWithID() *string {
 func (e *InstanceGroupManager) Find(c *fi.Context) (*InstanceGroupManager, error) {
 	cloud := c.Cloud.(gce.GCECloud)
 
-	r, err := cloud.Compute().InstanceGroupManagers().Get(cloud.Project(), *e.Zone, *e.Name)
+	var r *compute.InstanceGroupManager
+	var err error
+	if e.Region != nil {
+		r, err = cloud.Compute().RegionInstanceGroupManagers().Get(cloud.Project(), *e.Region, *e.Name)
+	} else {
+		r, err = cloud.Compute().InstanceGroupManagers().Get(cloud.Project(), *e.Zone, *e.Name)
+	}
 	if err != nil {
 		if gce.IsNotFound(err) {
 			return nil, nil
@@ -59,10 +78,32 @@ func (e *InstanceGroupManager) Find(c *fi.Context) (*InstanceGroupManager, error
 
 	actual := &InstanceGroupManager{}
 	actual.Name = &r.Name
-	actual.Zone = fi.String(lastComponent(r.Zone))
+	if r.Region != "" {
+		actual.Region = fi.String(lastComponent(r.Region))
+		if r.DistributionPolicy != nil {
+			for _, zone := range r.DistributionPolicy.Zones {
+				actual.Zones = append(actual.Zones, lastComponent(zone.Zone))
+			}
+		}
+		sort.Strings(actual.Zones)
+	} else {
+		actual.Zone = fi.String(lastComponent(r.Zone))
+	}
 	actual.BaseInstanceName = &r.BaseInstanceName
 	actual.TargetSize = &r.TargetSize
 	actual.InstanceTemplate = &InstanceTemplate{ID: fi.String(lastComponent(r.InstanceTemplate))}
+	actual.AdditionalInstanceTemplates = []*InstanceTemplate{}
+	actual.AdditionalTargetSizePercents = []int64{}
+	if len(r.Versions) > 1 {
+		for _, version := range r.Versions {
+			if version.TargetSize == nil {
+				actual.InstanceTemplate = &InstanceTemplate{ID: fi.String(lastComponent(version.InstanceTemplate))}
+				continue
+			}
+			actual.AdditionalInstanceTemplates = append(actual.AdditionalInstanceTemplates, &InstanceTemplate{ID: fi.String(lastComponent(version.InstanceTemplate))})
+			actual.AdditionalTargetSizePercents = append(actual.AdditionalTargetSizePercents, version.TargetSize.Percent)
+		}
+	}
 
 	for _, targetPool := range r.TargetPools {
 		actual.TargetPools = append(actual.TargetPools, &TargetPool{
@@ -82,6 +123,24 @@ func (e *InstanceGroupManager) Run(c *fi.Context) error {
 }
 
 func (_ *InstanceGroupManager) CheckChanges(a, e, changes *InstanceGroupManager) error {
+	if len(e.AdditionalInstanceTemplates) != len(e.AdditionalTargetSizePercents) {
+		return fmt.Errorf("InstanceGroupManager %q must have a target size for each additional instance template", fi.StringValue(e.Name))
+	}
+	if a == nil {
+		if (e.Zone == nil) == (e.Region == nil) {
+			return fmt.Errorf("exactly one of Zone or Region must be set on InstanceGroupManager %q", fi.StringValue(e.Name))
+		}
+	} else {
+		if changes.Zone != nil {
+			return fi.CannotChangeField("Zone")
+		}
+		if changes.Region != nil {
+			return fi.CannotChangeField("Region")
+		}
+		if changes.Zones != nil {
+			return fi.CannotChangeField("Zones")
+		}
+	}
 	return nil
 }
 
@@ -95,22 +154,60 @@ func (_ *InstanceGroupManager) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Ins
 
 	i := &compute.InstanceGroupManager{
 		Name:             *e.Name,
-		Zone:             *e.Zone,
 		BaseInstanceName: *e.BaseInstanceName,
 		TargetSize:       *e.TargetSize,
 		InstanceTemplate: instanceTemplateURL,
 	}
+	if e.Region != nil {
+		i.Region = *e.Region
+		if len(e.Zones) != 0 {
+			i.DistributionPolicy = &compute.DistributionPolicy{}
+			for _, zone := range e.Zones {
+				i.DistributionPolicy.Zones = append(i.DistributionPolicy.Zones, &compute.DistributionPolicyZoneConfiguration{
+					Zone: fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s", project, zone),
+				})
+			}
+		}
+	} else {
+		i.Zone = *e.Zone
+	}
 
 	for _, targetPool := range e.TargetPools {
 		i.TargetPools = append(i.TargetPools, targetPool.URL(t.Cloud))
 	}
 
+	if len(e.AdditionalInstanceTemplates) != 0 {
+		i.Versions = append(i.Versions, &compute.InstanceGroupManagerVersion{
+			Name:             fi.StringValue(e.InstanceTemplate.NamePrefix),
+			InstanceTemplate: instanceTemplateURL,
+		})
+		for n, additional := range e.AdditionalInstanceTemplates {
+			url, err := additional.URL(project)
+			if err != nil {
+				return err
+			}
+			i.Versions = append(i.Versions, &compute.InstanceGroupManagerVersion{
+				Name:             fi.StringValue(additional.NamePrefix),
+				InstanceTemplate: url,
+				TargetSize: &compute.FixedOrPercent{
+					Percent:         e.AdditionalTargetSizePercents[n],
+					ForceSendFields: []string{"Percent"},
+				},
+			})
+		}
+	}
+
 	if a == nil {
 		if i.TargetSize == 0 {
 			// TargetSize 0 will normally be omitted by the marshaling code; we need to force it
 			i.ForceSendFields = append(i.ForceSendFields, "TargetSize")
 		}
-		op, err := t.Cloud.Compute().InstanceGroupManagers().Insert(t.Cloud.Project(), *e.Zone, i)
+		var op *compute.Operation
+		if e.Region != nil {
+			op, err = t.Cloud.Compute().RegionInstanceGroupManagers().Insert(t.Cloud.Project(), *e.Region, i)
+		} else {
+			op, err = t.Cloud.Compute().InstanceGroupManagers().Insert(t.Cloud.Project(), *e.Zone, i)
+		}
 		if err != nil {
 			return fmt.Errorf("error creating InstanceGroupManager: %v", err)
 		}
@@ -120,7 +217,12 @@ func (_ *InstanceGroupManager) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Ins
 		}
 	} else {
 		if changes.TargetPools != nil {
-			op, err := t.Cloud.Compute().InstanceGroupManagers().SetTargetPools(t.Cloud.Project(), *e.Zone, i.Name, i.TargetPools)
+			var op *compute.Operation
+			if e.Region != nil {
+				op, err = t.Cloud.Compute().RegionInstanceGroupManagers().SetTargetPools(t.Cloud.Project(), *e.Region, i.Name, i.TargetPools)
+			} else {
+				op, err = t.Cloud.Compute().InstanceGroupManagers().SetTargetPools(t.Cloud.Project(), *e.Zone, i.Name, i.TargetPools)
+			}
 			if err != nil {
 				return fmt.Errorf("error updating TargetPools for InstanceGroupManager: %v", err)
 			}
@@ -132,8 +234,43 @@ func (_ *InstanceGroupManager) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Ins
 			changes.TargetPools = nil
 		}
 
+		if changes.AdditionalInstanceTemplates != nil || changes.AdditionalTargetSizePercents != nil || (changes.InstanceTemplate != nil && len(e.AdditionalInstanceTemplates) != 0) {
+			// The versions replace the instance template of the InstanceGroupManager
+			if len(i.Versions) == 0 {
+				i.Versions = []*compute.InstanceGroupManagerVersion{
+					{InstanceTemplate: instanceTemplateURL},
+				}
+			}
+			patch := &compute.InstanceGroupManager{
+				InstanceTemplate: instanceTemplateURL,
+				Versions:         i.Versions,
+			}
+			var op *compute.Operation
+			if e.Region != nil {
+				op, err = t.Cloud.Compute().RegionInstanceGroupManagers().Patch(t.Cloud.Project(), *e.Region, i.Name, patch)
+			} else {
+				op, err = t.Cloud.Compute().InstanceGroupManagers().Patch(t.Cloud.Project(), *e.Zone, i.Name, patch)
+			}
+			if err != nil {
+				return fmt.Errorf("error updating versions for InstanceGroupManager: %v", err)
+			}
+
+			if err := t.Cloud.WaitForOp(op); err != nil {
+				return fmt.Errorf("error updating versions for InstanceGroupManager: %v", err)
+			}
+
+			changes.InstanceTemplate = nil
+			changes.AdditionalInstanceTemplates = nil
+			changes.AdditionalTargetSizePercents = nil
+		}
+
 		if changes.InstanceTemplate != nil {
-			op, err := t.Cloud.Compute().InstanceGroupManagers().SetInstanceTemplate(t.Cloud.Project(), *e.Zone, i.Name, instanceTemplateURL)
+			var op *compute.Operation
+			if e.Region != nil {
+				op, err = t.Cloud.Compute().RegionInstanceGroupManagers().SetInstanceTemplate(t.Cloud.Project(), *e.Region, i.Name, instanceTemplateURL)
+			} else {
+				op, err = t.Cloud.Compute().InstanceGroupManagers().SetInstanceTemplate(t.Cloud.Project(), *e.Zone, i.Name, instanceTemplateURL)
+			}
 			if err != nil {
 				return fmt.Errorf("error updating InstanceTemplate for InstanceGroupManager: %v", err)
 			}
@@ -150,7 +287,12 @@ func (_ *InstanceGroupManager) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Ins
 			if i.TargetSize != 0 {
 				newSize = int64(i.TargetSize)
 			}
-			op, err := t.Cloud.Compute().InstanceGroupManagers().Resize(t.Cloud.Project(), *e.Zone, i.Name, newSize)
+			var op *compute.Operation
+			if e.Region != nil {
+				op, err = t.Cloud.Compute().RegionInstanceGroupManagers().Resize(t.Cloud.Project(), *e.Region, i.Name, newSize)
+			} else {
+				op, err = t.Cloud.Compute().InstanceGroupManagers().Resize(t.Cloud.Project(), *e.Zone, i.Name, newSize)
+			}
 			if err != nil {
 				return fmt.Errorf("error resizing InstanceGroupManager: %v", err)
 			}
@@ -173,31 +315,54 @@ func (_ *InstanceGroupManager) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Ins
 
 type terraformInstanceGroupManager struct {
 	Name             *string                    `json:"name" cty:"name"`
-	Zone             *string                    `json:"zone" cty:"zone"`
+	Zone             *string                    `json:"zone,omitempty" cty:"zone"`
+	Region           *string                    `json:"region,omitempty" cty:"region"`
+	Zones            []string                   `json:"distribution_policy_zones,omitempty" cty:"distribution_policy_zones"`
 	BaseInstanceName *string                    `json:"base_instance_name" cty:"base_instance_name"`
-	Version          *terraformVersion          `json:"version" cty:"version"`
+	Versions         []*terraformVersion        `json:"version" cty:"version"`
 	TargetSize       *int64                     `json:"target_size" cty:"target_size"`
 	TargetPools      []*terraformWriter.Literal `json:"target_pools,omitempty" cty:"target_pools"`
 }
 
 type terraformVersion struct {
+	Name             *string                  `json:"name,omitempty" cty:"name"`
 	InstanceTemplate *terraformWriter.Literal `json:"instance_template" cty:"instance_template"`
+	TargetSize       *terraformTargetSize     `json:"target_size,omitempty" cty:"target_size"`
+}
+
+type terraformTargetSize struct {
+	Percent *int64 `json:"percent,omitempty" cty:"percent"`
 }
 
 func (_ *InstanceGroupManager) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *InstanceGroupManager) error {
 	tf := &terraformInstanceGroupManager{
 		Name:             e.Name,
 		Zone:             e.Zone,
+		Region:           e.Region,
+		Zones:            e.Zones,
 		BaseInstanceName: e.BaseInstanceName,
 		TargetSize:       e.TargetSize,
 	}
-	tf.Version = &terraformVersion{
+	tf.Versions = append(tf.Versions, &terraformVersion{
 		InstanceTemplate: e.InstanceTemplate.TerraformLink(),
+	})
+	if len(e.AdditionalInstanceTemplates) != 0 {
+		tf.Versions[0].Name = e.InstanceTemplate.NamePrefix
+		for n, additional := range e.AdditionalInstanceTemplates {
+			tf.Versions = append(tf.Versions, &terraformVersion{
+				Name:             additional.NamePrefix,
+				InstanceTemplate: additional.TerraformLink(),
+				TargetSize:       &terraformTargetSize{Percent: fi.Int64(e.AdditionalTargetSizePercents[n])},
+			})
+		}
 	}
 
 	for _, targetPool := range e.TargetPools {
 		tf.TargetPools = append(tf.TargetPools, targetPool.TerraformLink())
 	}
 
+	if e.Region != nil {
+		return t.RenderResource("google_compute_region_instance_group_manager", *e.Name, tf)
+	}
 	return t.RenderResource("google_compute_instance_group_manager", *e.Name, tf)
 }
@@ -55,6 +55,10 @@ type InstanceTemplate struct {
 	Tags    []string
 	//Labels      map[string]string
 	Preemptible *bool
+	// AutomaticRestart overrides the restart of standard instances stopped by GCE
+	AutomaticRestart *bool
+	// OnHostMaintenance overrides the host maintenance action of standard instances
+	OnHostMaintenance *string
 
 	BootDiskImage  *string
 	BootDiskSizeGB *int64
@@ -73,6 +77,17 @@ type InstanceTemplate struct {
 	// HasExternalIP is set to true when an external IP is allocated to an instance.
 	HasExternalIP *bool
 
+	// ShieldedVM enables the Shielded VM options below when set to true.
+	ShieldedVM                  *bool
+	ShieldedSecureBoot          *bool
+	ShieldedVTPM                *bool
+	ShieldedIntegrityMonitoring *bool
+
+	// ConfidentialCompute enables memory encryption with Confidential VMs.
+	ConfidentialCompute *bool
+	// NestedVirtualization allows instances to run virtual machines.
+	NestedVirtualization *bool
+
 	// ID is the actual name
 	ID *string
 }
@@ -127,6 +142,24 @@ func (e *InstanceTemplate) Find(c *fi.Context) (*InstanceTemplate, error) {
 
 		if p.Scheduling != nil {
 			actual.Preemptible = &p.Scheduling.Preemptible
+			if e.AutomaticRestart != nil {
+				actual.AutomaticRestart = p.Scheduling.AutomaticRestart
+			}
+			if e.OnHostMaintenance != nil {
+				actual.OnHostMaintenance = fi.String(p.Scheduling.OnHostMaintenance)
+			}
+		}
+		if p.ShieldedInstanceConfig != nil {
+			actual.ShieldedVM = fi.Bool(true)
+			actual.ShieldedSecureBoot = fi.Bool(p.ShieldedInstanceConfig.EnableSecureBoot)
+			actual.ShieldedVTPM = fi.Bool(p.ShieldedInstanceConfig.EnableVtpm)
+			actual.ShieldedIntegrityMonitoring = fi.Bool(p.ShieldedInstanceConfig.EnableIntegrityMonitoring)
+		}
+		if p.ConfidentialInstanceConfig != nil {
+			actual.ConfidentialCompute = fi.Bool(p.ConfidentialInstanceConfig.EnableConfidentialCompute)
+		}
+		if p.AdvancedMachineFeatures != nil {
+			actual.NestedVirtualization = fi.Bool(p.AdvancedMachineFeatures.EnableNestedVirtualization)
 		}
 		if len(p.NetworkInterfaces) != 0 {
 			ni := p.NetworkInterfaces[0]
@@ -243,6 +276,16 @@ func (e *InstanceTemplate) mapToGCE(project string, region string) (*compute.Ins
 			OnHostMaintenance: "MIGRATE",
 			Preemptible:       false,
 		}
+		if e.AutomaticRestart != nil {
+			scheduling.AutomaticRestart = fi.Bool(*e.AutomaticRestart)
+		}
+		if e.OnHostMaintenance != nil {
+			scheduling.OnHostMaintenance = *e.OnHostMaintenance
+		}
+		if fi.BoolValue(e.ConfidentialCompute) {
+			// Confidential VMs do not support live migration
+			scheduling.OnHostMaintenance = "TERMINATE"
+		}
 	}
 
 	var disks []*compute.AttachedDisk
@@ -359,6 +402,25 @@ func (e *InstanceTemplate) mapToGCE(project string, region string) (*compute.Ins
 		},
 	}
 
+	if fi.BoolValue(e.ShieldedVM) {
+		i.Properties.ShieldedInstanceConfig = &compute.ShieldedInstanceConfig{
+			EnableSecureBoot:          fi.BoolValue(e.ShieldedSecureBoot),
+			EnableVtpm:                fi.BoolValue(e.ShieldedVTPM),
+			EnableIntegrityMonitoring: fi.BoolValue(e.ShieldedIntegrityMonitoring),
+			ForceSendFields:           []string{"EnableSecureBoot", "EnableVtpm", "EnableIntegrityMonitoring"},
+		}
+	}
+	if fi.BoolValue(e.ConfidentialCompute) {
+		i.Properties.ConfidentialInstanceConfig = &compute.ConfidentialInstanceConfig{
+			EnableConfidentialCompute: true,
+		}
+	}
+	if fi.BoolValue(e.NestedVirtualization) {
+		i.Properties.AdvancedMachineFeatures = &compute.AdvancedMachineFeatures{
+			EnableNestedVirtualization: true,
+		}
+	}
+
 	return i, nil
 }
 
@@ -457,6 +519,10 @@ type terraformInstanceTemplate struct {
 	Metadata              map[string]*terraformWriter.Literal      `json:"metadata,omitempty" cty:"metadata"`
 	MetadataStartupScript *terraformWriter.Literal                 `json:"metadata_startup_script,omitempty" cty:"metadata_startup_script"`
 	Tags                  []string                                 `json:"tags,omitempty" cty:"tags"`
+
+	ShieldedInstanceConfig     *terraformShieldedInstanceConfig     `json:"shielded_instance_config,omitempty" cty:"shielded_instance_config"`
+	ConfidentialInstanceConfig *terraformConfidentialInstanceConfig `json:"confidential_instance_config,omitempty" cty:"confidential_instance_config"`
+	AdvancedMachineFeatures    *terraformAdvancedMachineFeatures    `json:"advanced_machine_features,omitempty" cty:"advanced_machine_features"`
 }
 
 type terraformServiceAccount struct {
@@ -470,6 +536,20 @@ type terraformScheduling struct {
 	Preemptible       bool   `json:"preemptible" cty:"preemptible"`
 }
 
+type terraformShieldedInstanceConfig struct {
+	EnableSecureBoot          bool `json:"enable_secure_boot" cty:"enable_secure_boot"`
+	EnableVTPM                bool `json:"enable_vtpm" cty:"enable_vtpm"`
+	EnableIntegrityMonitoring bool `json:"enable_integrity_monitoring" cty:"enable_integrity_monitoring"`
+}
+
+type terraformConfidentialInstanceConfig struct {
+	EnableConfidentialCompute bool `json:"enable_confidential_compute" cty:"enable_confidential_compute"`
+}
+
+type terraformAdvancedMachineFeatures struct {
+	EnableNestedVirtualization bool `json:"enable_nested_virtualization" cty:"enable_nested_virtualization"`
+}
+
 type terraformInstanceTemplateAttachedDisk struct {
 	AutoDelete bool   `json:"auto_delete,omitempty" cty:"auto_delete"`
 	DeviceName string `json:"device_name,omitempty" cty:"device_name"`
@@ -612,6 +692,24 @@ func (_ *InstanceTemplate) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 		}
 	}
 
+	if c := i.Properties.ShieldedInstanceConfig; c != nil {
+		tf.ShieldedInstanceConfig = &terraformShieldedInstanceConfig{
+			EnableSecureBoot:          c.EnableSecureBoot,
+			EnableVTPM:                c.EnableVtpm,
+			EnableIntegrityMonitoring: c.EnableIntegrityMonitoring,
+		}
+	}
+	if c := i.Properties.ConfidentialInstanceConfig; c != nil {
+		tf.ConfidentialInstanceConfig = &terraformConfidentialInstanceConfig{
+			EnableConfidentialCompute: c.EnableConfidentialCompute,
+		}
+	}
+	if c := i.Properties.AdvancedMachineFeatures; c != nil {
+		tf.AdvancedMachineFeatures = &terraformAdvancedMachineFeatures{
+			EnableNestedVirtualization: c.EnableNestedVirtualization,
+		}
+	}
+
 	return t.RenderResource("google_compute_instance_template", name, tf)
 }
 
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hybrid
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// TokenAuthenticationTokenPrefix prefixes the authorization of the hybrid machines enrolled with a bootstrap token.
+	TokenAuthenticationTokenPrefix = "x-kops-hybrid-token "
+	// KeyAuthenticationTokenPrefix prefixes the authorization of the hybrid machines enrolled with a signing key.
+	KeyAuthenticationTokenPrefix = "x-kops-hybrid-key "
+)
+
+// keyToken is the authorization of a machine enrolled with a signing key.
+type keyToken struct {
+	NodeName string `json:"nodeName"`
+	// Timestamp is the time of the request, in seconds since the epoch.
+	Timestamp int64 `json:"timestamp"`
+	// Signature is the signature of the digest of the request, by the signing key of the machine.
+	Signature []byte `json:"signature"`
+}
+
+// keyDigest returns the digest that the signing key signs, which binds the node name and time to the request body.
+func keyDigest(nodeName string, timestamp int64, body []byte) []byte {
+	bodyHash := sha256.Sum256(body)
+	digest := sha256.Sum256([]byte(nodeName + "\n" + strconv.FormatInt(timestamp, 10) + "\n" + base64.StdEncoding.EncodeToString(bodyHash[:])))
+	return digest[:]
+}
+
+type tokenAuthenticator struct {
+	nodeName string
+	token    string
+}
+
+var _ fi.Authenticator = &tokenAuthenticator{}
+
+func (a *tokenAuthenticator) CreateToken(body []byte) (string, error) {
+	return TokenAuthenticationTokenPrefix + a.nodeName + ":" + a.token, nil
+}
+
+type keyAuthenticator struct {
+	nodeName string
+	key      crypto.Signer
+}
+
+var _ fi.Authenticator = &keyAuthenticator{}
+
+// CreateToken signs the request with the key of the machine.
+// RSA keys produce PKCS #1 v1.5 signatures and ECDSA keys ASN.1 encoded signatures.
+func (a *keyAuthenticator) CreateToken(body []byte) (string, error) {
+	token := keyToken{
+		NodeName:  a.nodeName,
+		Timestamp: time.Now().Unix(),
+	}
+
+	signature, err := a.key.Sign(rand.Reader, keyDigest(token.NodeName, token.Timestamp, body), crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("error signing request: %v", err)
+	}
+	token.Signature = signature
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("error encoding token: %v", err)
+	}
+	return KeyAuthenticationTokenPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// NewAuthenticator returns the authenticator of a hybrid machine, from its credential.
+func NewAuthenticator(credential *NodeCredential) (fi.Authenticator, error) {
+	if credential.PrivateKeyPath != "" {
+		b, err := ioutil.ReadFile(credential.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading private key %q: %v", credential.PrivateKeyPath, err)
+		}
+		key, err := pki.ParsePEMPrivateKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing private key %q: %v", credential.PrivateKeyPath, err)
+		}
+		return &keyAuthenticator{
+			nodeName: credential.NodeName,
+			key:      key.Key,
+		}, nil
+	}
+	if credential.Token != "" {
+		return &tokenAuthenticator{
+			nodeName: credential.NodeName,
+			token:    credential.Token,
+		}, nil
+	}
+	return nil, fmt.Errorf("hybrid credential of node %q has neither a token nor a private key", credential.NodeName)
+}
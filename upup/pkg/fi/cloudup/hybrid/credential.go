@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hybrid
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// NodeCredentialPath is where kops toolbox enroll stores the credential of a hybrid machine.
+const NodeCredentialPath = "/var/lib/kops/hybrid-credential.json"
+
+// DefaultNodePrivateKeyPath is the default path of the signing key of a hybrid machine enrolled with a key.
+const DefaultNodePrivateKeyPath = "/var/lib/kops/hybrid-key.pem"
+
+// Credential is the record of an enrolled hybrid node, against which kops-controller verifies its requests.
+type Credential struct {
+	// NodeName is the name of the Node of the machine.
+	NodeName string `json:"nodeName"`
+	// InstanceGroup is the name of the instance group the machine was enrolled into.
+	InstanceGroup string `json:"instanceGroup"`
+	// TokenHash is the hex encoded SHA-256 hash of the bootstrap token of the machine.
+	TokenHash string `json:"tokenHash,omitempty"`
+	// PublicKey is the PEM encoded public key of the signing key of the machine.
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// NodeCredential is the credential with which a hybrid machine authenticates to kops-controller.
+type NodeCredential struct {
+	// NodeName is the name of the Node of the machine.
+	NodeName string `json:"nodeName"`
+	// Token is the bootstrap token of the machine.
+	Token string `json:"token,omitempty"`
+	// PrivateKeyPath is the path of the PEM encoded signing key on the machine.
+	PrivateKeyPath string `json:"privateKeyPath,omitempty"`
+}
+
+// CredentialLocation returns the location of the credential of a hybrid node, relative to the config base.
+func CredentialLocation(nodeName string) string {
+	return "hybrid/" + nodeName + ".json"
+}
+
+// ReadCredential reads the credential of a hybrid node from the config base.
+func ReadCredential(configBase vfs.Path, nodeName string) (*Credential, error) {
+	if nodeName == "" || strings.Contains(nodeName, "/") {
+		return nil, fmt.Errorf("invalid node name %q", nodeName)
+	}
+
+	p := configBase.Join(CredentialLocation(nodeName))
+	b, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("node %q is not enrolled", nodeName)
+		}
+		return nil, fmt.Errorf("error reading credential %q: %v", p, err)
+	}
+
+	credential := &Credential{}
+	if err := json.Unmarshal(b, credential); err != nil {
+		return nil, fmt.Errorf("error parsing credential %q: %v", p, err)
+	}
+	if credential.NodeName != nodeName {
+		return nil, fmt.Errorf("credential %q is for node %q", p, credential.NodeName)
+	}
+	return credential, nil
+}
+
+// ReadNodeCredential reads the credential of the local hybrid machine.
+func ReadNodeCredential(path string) (*NodeCredential, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading hybrid credential %q: %v", path, err)
+	}
+
+	credential := &NodeCredential{}
+	if err := json.Unmarshal(b, credential); err != nil {
+		return nil, fmt.Errorf("error parsing hybrid credential %q: %v", path, err)
+	}
+	if credential.NodeName == "" {
+		return nil, fmt.Errorf("hybrid credential %q has no node name", path)
+	}
+	return credential, nil
+}
+
+// NewToken generates a random bootstrap token.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating bootstrap token: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hash of a bootstrap token, as stored in the Credential.
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hybrid
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// maxClockSkew is how far the time of a request signed by a key may be from the time of kops-controller.
+const maxClockSkew = 5 * time.Minute
+
+type hybridVerifier struct {
+	configBase     vfs.Path
+	instanceGroups map[string]bool
+	next           fi.Verifier
+	now            func() time.Time
+
+	// mutex protects seenSignatures
+	mutex sync.Mutex
+	// seenSignatures are the signatures of the requests accepted within maxClockSkew, which are not accepted again
+	seenSignatures map[string]time.Time
+}
+
+var _ fi.Verifier = &hybridVerifier{}
+
+// NewVerifier returns a verifier of the requests of the hybrid machines enrolled into the instanceGroups.
+// It passes the other requests to next, the verifier of the cloud provider.
+func NewVerifier(configBase vfs.Path, instanceGroups []string, next fi.Verifier) fi.Verifier {
+	v := &hybridVerifier{
+		configBase:     configBase,
+		instanceGroups: make(map[string]bool),
+		next:           next,
+		now:            time.Now,
+		seenSignatures: make(map[string]time.Time),
+	}
+	for _, name := range instanceGroups {
+		v.instanceGroups[name] = true
+	}
+	return v
+}
+
+func (v *hybridVerifier) VerifyToken(token string, body []byte) (*fi.VerifyResult, error) {
+	switch {
+	case strings.HasPrefix(token, TokenAuthenticationTokenPrefix):
+		return v.verifyBootstrapToken(strings.TrimPrefix(token, TokenAuthenticationTokenPrefix))
+	case strings.HasPrefix(token, KeyAuthenticationTokenPrefix):
+		return v.verifyKeyToken(strings.TrimPrefix(token, KeyAuthenticationTokenPrefix), body)
+	case v.next != nil:
+		return v.next.VerifyToken(token, body)
+	default:
+		return nil, fmt.Errorf("incorrect authorization type")
+	}
+}
+
+func (v *hybridVerifier) verifyBootstrapToken(token string) (*fi.VerifyResult, error) {
+	tokens := strings.SplitN(token, ":", 2)
+	if len(tokens) != 2 {
+		return nil, fmt.Errorf("malformed bootstrap token")
+	}
+
+	credential, err := v.readCredential(tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	if credential.TokenHash == "" {
+		return nil, fmt.Errorf("node %q is not enrolled with a bootstrap token", credential.NodeName)
+	}
+	if subtle.ConstantTimeCompare([]byte(HashToken(tokens[1])), []byte(credential.TokenHash)) != 1 {
+		return nil, fmt.Errorf("invalid bootstrap token for node %q", credential.NodeName)
+	}
+
+	return &fi.VerifyResult{
+		NodeName:          credential.NodeName,
+		InstanceGroupName: credential.InstanceGroup,
+	}, nil
+}
+
+func (v *hybridVerifier) verifyKeyToken(token string, body []byte) (*fi.VerifyResult, error) {
+	b, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("decoding authorization token: %v", err)
+	}
+	decoded := &keyToken{}
+	if err := json.Unmarshal(b, decoded); err != nil {
+		return nil, fmt.Errorf("decoding authorization token: %v", err)
+	}
+
+	credential, err := v.readCredential(decoded.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	if credential.PublicKey == "" {
+		return nil, fmt.Errorf("node %q is not enrolled with a signing key", credential.NodeName)
+	}
+
+	skew := v.now().Sub(time.Unix(decoded.Timestamp, 0))
+	if skew > maxClockSkew || skew < -maxClockSkew {
+		return nil, fmt.Errorf("request of node %q is not current", credential.NodeName)
+	}
+
+	publicKey, err := ParsePublicKey(credential.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("signing key of node %q: %v", credential.NodeName, err)
+	}
+
+	digest := keyDigest(decoded.NodeName, decoded.Timestamp, body)
+	verified := false
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		verified = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest, decoded.Signature) == nil
+	case *ecdsa.PublicKey:
+		verified = ecdsa.VerifyASN1(publicKey, digest, decoded.Signature)
+	}
+	if !verified {
+		return nil, fmt.Errorf("invalid signature for node %q", credential.NodeName)
+	}
+
+	// The token carries no nonce of kops-controller, so it is only accepted once
+	if !v.markSignatureSeen(decoded.Signature) {
+		return nil, fmt.Errorf("request of node %q was already accepted", credential.NodeName)
+	}
+
+	return &fi.VerifyResult{
+		NodeName:          credential.NodeName,
+		InstanceGroupName: credential.InstanceGroup,
+	}, nil
+}
+
+// readCredential reads the credential of a node, which must be enrolled into a hybrid instance group.
+func (v *hybridVerifier) readCredential(nodeName string) (*Credential, error) {
+	credential, err := ReadCredential(v.configBase, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	if !v.instanceGroups[credential.InstanceGroup] {
+		return nil, fmt.Errorf("node %q is enrolled into instance group %q, which is not a hybrid instance group", nodeName, credential.InstanceGroup)
+	}
+	return credential, nil
+}
+
+// markSignatureSeen records the signature of an accepted request, and returns false if it was already accepted.
+// The signatures are forgotten once their requests can no longer be current, which is at most twice maxClockSkew
+// after they are accepted.
+func (v *hybridVerifier) markSignatureSeen(signature []byte) bool {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	now := v.now()
+	for k, seen := range v.seenSignatures {
+		if now.Sub(seen) > 2*maxClockSkew {
+			delete(v.seenSignatures, k)
+		}
+	}
+
+	key := string(signature)
+	if _, found := v.seenSignatures[key]; found {
+		return false
+	}
+	v.seenSignatures[key] = now
+	return true
+}
+
+// ParsePublicKey parses the PEM encoded public key of the signing key of a machine, which must be an RSA or ECDSA key.
+func ParsePublicKey(s string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("expected a PEM encoded PUBLIC KEY")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %v", err)
+	}
+	switch publicKey.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return publicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hybrid
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+type fakeVerifier struct{}
+
+func (fakeVerifier) VerifyToken(token string, body []byte) (*fi.VerifyResult, error) {
+	return &fi.VerifyResult{NodeName: "cloud-node", InstanceGroupName: "nodes"}, nil
+}
+
+func writeCredential(t *testing.T, configBase vfs.Path, credential *Credential) {
+	b, err := json.Marshal(credential)
+	if err != nil {
+		t.Fatalf("error encoding credential: %v", err)
+	}
+	if err := configBase.Join(CredentialLocation(credential.NodeName)).WriteFile(bytes.NewReader(b), nil); err != nil {
+		t.Fatalf("error writing credential: %v", err)
+	}
+}
+
+func encodePublicKey(t *testing.T, publicKey crypto.PublicKey) string {
+	b, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("error encoding public key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+func TestVerifyBootstrapToken(t *testing.T) {
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests/cluster.example.com")
+	writeCredential(t, configBase, &Credential{NodeName: "edge-1", InstanceGroup: "edge", TokenHash: HashToken("secret")})
+	writeCredential(t, configBase, &Credential{NodeName: "edge-2", InstanceGroup: "nodes", TokenHash: HashToken("secret")})
+
+	verifier := NewVerifier(configBase, []string{"edge"}, fakeVerifier{})
+
+	authenticator, err := NewAuthenticator(&NodeCredential{NodeName: "edge-1", Token: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token, err := authenticator.CreateToken([]byte("body"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := verifier.VerifyToken(token, []byte("body"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.NodeName != "edge-1" || result.InstanceGroupName != "edge" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	grid := []struct {
+		token    string
+		expected string
+	}{
+		{
+			token:    TokenAuthenticationTokenPrefix + "edge-1:wrong",
+			expected: "invalid bootstrap token",
+		},
+		{
+			token:    TokenAuthenticationTokenPrefix + "edge-1",
+			expected: "malformed bootstrap token",
+		},
+		{
+			token:    TokenAuthenticationTokenPrefix + "edge-3:secret",
+			expected: "is not enrolled",
+		},
+		{
+			token:    TokenAuthenticationTokenPrefix + "../edge-1:secret",
+			expected: "invalid node name",
+		},
+		{
+			token:    TokenAuthenticationTokenPrefix + "edge-2:secret",
+			expected: "not a hybrid instance group",
+		},
+	}
+	for _, g := range grid {
+		_, err := verifier.VerifyToken(g.token, []byte("body"))
+		if err == nil || !strings.Contains(err.Error(), g.expected) {
+			t.Errorf("token %q: expected error containing %q, got %v", g.token, g.expected, err)
+		}
+	}
+
+	result, err = verifier.VerifyToken("x-aws-sts token", []byte("body"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.NodeName != "cloud-node" {
+		t.Errorf("other tokens were not passed to the cloud verifier: %+v", result)
+	}
+}
+
+func TestVerifyKeyToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests/cluster.example.com")
+	writeCredential(t, configBase, &Credential{NodeName: "edge-rsa", InstanceGroup: "edge", PublicKey: encodePublicKey(t, &rsaKey.PublicKey)})
+	writeCredential(t, configBase, &Credential{NodeName: "edge-ecdsa", InstanceGroup: "edge", PublicKey: encodePublicKey(t, &ecdsaKey.PublicKey)})
+
+	now := time.Now()
+	verifier := NewVerifier(configBase, []string{"edge"}, nil).(*hybridVerifier)
+	verifier.now = func() time.Time { return now }
+
+	signers := map[string]crypto.Signer{
+		"edge-rsa":   rsaKey,
+		"edge-ecdsa": ecdsaKey,
+	}
+	for nodeName, signer := range signers {
+		authenticator := &keyAuthenticator{
+			nodeName: nodeName,
+			key:      signer,
+		}
+
+		token, err := authenticator.CreateToken([]byte("body"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := verifier.VerifyToken(token, []byte("other body")); err == nil || !strings.Contains(err.Error(), "invalid signature") {
+			t.Errorf("node %q: expected invalid signature for another body, got %v", nodeName, err)
+		}
+
+		result, err := verifier.VerifyToken(token, []byte("body"))
+		if err != nil {
+			t.Fatalf("node %q: unexpected error: %v", nodeName, err)
+		}
+		if result.NodeName != nodeName || result.InstanceGroupName != "edge" {
+			t.Errorf("node %q: unexpected result %+v", nodeName, result)
+		}
+
+		if _, err := verifier.VerifyToken(token, []byte("body")); err == nil || !strings.Contains(err.Error(), "already accepted") {
+			t.Errorf("node %q: expected a replayed token to be rejected, got %v", nodeName, err)
+		}
+	}
+
+	// A signature by another key, and a signature that is too old, are rejected
+	stale := time.Now().Add(-10 * time.Minute).Unix()
+	grid := []struct {
+		nodeName  string
+		timestamp int64
+		signer    crypto.Signer
+		expected  string
+	}{
+		{
+			nodeName:  "edge-rsa",
+			timestamp: now.Unix(),
+			signer:    ecdsaKey,
+			expected:  "invalid signature",
+		},
+		{
+			nodeName:  "edge-ecdsa",
+			timestamp: stale,
+			signer:    ecdsaKey,
+			expected:  "is not current",
+		},
+	}
+	for _, g := range grid {
+		digest := keyDigest(g.nodeName, g.timestamp, []byte("body"))
+		signature, err := g.signer.Sign(rand.Reader, digest, crypto.SHA256)
+		if err != nil {
+			t.Fatalf("error signing: %v", err)
+		}
+		b, err := json.Marshal(keyToken{NodeName: g.nodeName, Timestamp: g.timestamp, Signature: signature})
+		if err != nil {
+			t.Fatalf("error encoding token: %v", err)
+		}
+		token := KeyAuthenticationTokenPrefix + base64.StdEncoding.EncodeToString(b)
+		if _, err := verifier.VerifyToken(token, []byte("body")); err == nil || !strings.Contains(err.Error(), g.expected) {
+			t.Errorf("node %q: expected error containing %q, got %v", g.nodeName, g.expected, err)
+		}
+	}
+
+	// The signatures are forgotten once their requests can no longer be current
+	now = now.Add(2*maxClockSkew + time.Second)
+	verifier.markSignatureSeen([]byte("other"))
+	if len(verifier.seenSignatures) != 1 {
+		t.Errorf("expected the old signatures to be forgotten, got %d", len(verifier.seenSignatures))
+	}
+
+	if _, err := verifier.VerifyToken("x-aws-sts token", []byte("body")); err == nil {
+		t.Errorf("expected other tokens to be rejected without a cloud verifier")
+	}
+}
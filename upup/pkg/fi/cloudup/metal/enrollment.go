@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// Enrollment is the record, written by kops toolbox enroll, of the machine that runs a node of a metal cluster.
+// kops-controller identifies the instance group of a node from its enrollment, not from the labels the node sets itself.
+type Enrollment struct {
+	// NodeName is the name of the Node of the machine.
+	NodeName string `json:"nodeName"`
+	// InstanceGroup is the name of the instance group the machine was enrolled into.
+	InstanceGroup string `json:"instanceGroup"`
+	// Host is the address the machine was enrolled at.
+	Host string `json:"host"`
+}
+
+// EnrollmentLocation returns the location of the enrollment of a node, relative to the config base.
+func EnrollmentLocation(nodeName string) string {
+	return "metal/" + nodeName + ".json"
+}
+
+// WriteEnrollment writes the enrollment of a node to the config base.
+func WriteEnrollment(configBase vfs.Path, enrollment *Enrollment) error {
+	if enrollment.NodeName == "" || strings.Contains(enrollment.NodeName, "/") {
+		return fmt.Errorf("invalid node name %q", enrollment.NodeName)
+	}
+
+	b, err := json.Marshal(enrollment)
+	if err != nil {
+		return fmt.Errorf("error building enrollment: %v", err)
+	}
+	p := configBase.Join(EnrollmentLocation(enrollment.NodeName))
+	if err := p.WriteFile(bytes.NewReader(b), nil); err != nil {
+		return fmt.Errorf("error writing enrollment %q: %v", p, err)
+	}
+	return nil
+}
+
+// ReadEnrollment reads the enrollment of a node from the config base.
+func ReadEnrollment(configBase vfs.Path, nodeName string) (*Enrollment, error) {
+	if nodeName == "" || strings.Contains(nodeName, "/") {
+		return nil, fmt.Errorf("invalid node name %q", nodeName)
+	}
+
+	p := configBase.Join(EnrollmentLocation(nodeName))
+	b, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("node %q is not enrolled", nodeName)
+		}
+		return nil, fmt.Errorf("error reading enrollment %q: %v", p, err)
+	}
+
+	enrollment := &Enrollment{}
+	if err := json.Unmarshal(b, enrollment); err != nil {
+		return nil, fmt.Errorf("error parsing enrollment %q: %v", p, err)
+	}
+	if enrollment.NodeName != nodeName {
+		return nil, fmt.Errorf("enrollment %q is for node %q", p, enrollment.NodeName)
+	}
+	return enrollment, nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestEnrollment(t *testing.T) {
+	vfs.Context.ResetMemfsContext(true)
+	configBase, err := vfs.Context.BuildVfsPath("memfs://state/cluster.k8s.local")
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+
+	if _, err := ReadEnrollment(configBase, "node-1"); err == nil {
+		t.Errorf("expected error reading enrollment of a node that was not enrolled")
+	}
+
+	if err := WriteEnrollment(configBase, &Enrollment{NodeName: "../node-1", InstanceGroup: "nodes"}); err == nil {
+		t.Errorf("expected error writing enrollment with an invalid node name")
+	}
+
+	if err := WriteEnrollment(configBase, &Enrollment{NodeName: "node-1", InstanceGroup: "nodes", Host: "192.168.1.20"}); err != nil {
+		t.Fatalf("error writing enrollment: %v", err)
+	}
+	enrollment, err := ReadEnrollment(configBase, "node-1")
+	if err != nil {
+		t.Fatalf("error reading enrollment: %v", err)
+	}
+	if enrollment.InstanceGroup != "nodes" || enrollment.Host != "192.168.1.20" {
+		t.Errorf("unexpected enrollment %+v", enrollment)
+	}
+}
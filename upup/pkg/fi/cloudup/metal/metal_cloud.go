@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// MetalCloud is the cloud of clusters that run on machines that kOps does not provision.
+// There is no cloud API; the machines of each instance group are listed in its spec.
+type MetalCloud interface {
+	fi.Cloud
+}
+
+type metalCloudImplementation struct{}
+
+var _ fi.Cloud = &metalCloudImplementation{}
+
+// NewMetalCloud returns a new MetalCloud.
+func NewMetalCloud() MetalCloud {
+	return &metalCloudImplementation{}
+}
+
+func (c *metalCloudImplementation) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderMetal
+}
+
+func (c *metalCloudImplementation) DNS() (dnsprovider.Interface, error) {
+	return nil, errors.New("DNS not implemented on metalCloud")
+}
+
+func (c *metalCloudImplementation) FindVPCInfo(id string) (*fi.VPCInfo, error) {
+	return nil, errors.New("FindVPCInfo not implemented on metalCloud")
+}
+
+func (c *metalCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstance) error {
+	return fmt.Errorf("machine %s cannot be deleted by kOps, it must be removed from the instance group and reset manually", i.ID)
+}
+
+func (c *metalCloudImplementation) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
+	return fmt.Errorf("the machines of instance group %s cannot be deleted by kOps, they must be reset manually", g.HumanName)
+}
+
+func (c *metalCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance) error {
+	return fmt.Errorf("machine %s cannot be detached by kOps", i.ID)
+}
+
+func (c *metalCloudImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	return getCloudGroups(instancegroups, nodes)
+}
+
+// getCloudGroups builds the cloud instance groups from the hosts listed in the instance groups.
+// The hosts are matched with the nodes by address or name.
+func getCloudGroups(instancegroups []*kops.InstanceGroup, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	nodesByHost := make(map[string]*v1.Node)
+	for i := range nodes {
+		node := &nodes[i]
+		nodesByHost[node.Name] = node
+		for _, address := range node.Status.Addresses {
+			nodesByHost[address.Address] = node
+		}
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, ig := range instancegroups {
+		var hosts []string
+		if ig.Spec.Metal != nil {
+			hosts = ig.Spec.Metal.Hosts
+		}
+		group := &cloudinstances.CloudInstanceGroup{
+			HumanName:     ig.Name,
+			InstanceGroup: ig,
+			MinSize:       len(hosts),
+			TargetSize:    len(hosts),
+			MaxSize:       len(hosts),
+		}
+		for _, host := range hosts {
+			node := nodesByHost[host]
+			if node == nil {
+				klog.V(2).Infof("machine %s of instance group %s has not joined the cluster", host, ig.Name)
+			}
+			// kOps cannot replace the machines, so they are always considered up to date.
+			if _, err := group.NewCloudInstance(host, cloudinstances.CloudInstanceStatusUpToDate, node); err != nil {
+				return nil, fmt.Errorf("error creating cloud instance group member: %v", err)
+			}
+		}
+		groups[ig.Name] = group
+	}
+	return groups, nil
+}
+
+// Region returns "", as there are no regions.
+func (c *metalCloudImplementation) Region() string {
+	return ""
+}
+
+func (c *metalCloudImplementation) FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error) {
+	// The etcd volumes are directories on the control plane machines, which kOps cannot inspect.
+	return &kops.ClusterStatus{}, nil
+}
+
+func (c *metalCloudImplementation) GetApiIngressStatus(cluster *kops.Cluster) ([]fi.ApiIngressStatus, error) {
+	return nil, nil
+}
+
+// NodeupScriptLocation returns the location of the nodeup script of an instance group, relative to the config base.
+func NodeupScriptLocation(ig *kops.InstanceGroup) string {
+	return "igconfig/" + strings.ToLower(string(ig.Spec.Role)) + "/" + ig.Name + "/nodeup.sh"
+}
+
+// EtcdVolumesDir is the directory of the control plane machines under which etcd-manager finds its volumes.
+const EtcdVolumesDir = "/mnt/disks"
+
+// EtcdVolumePrefix returns the prefix of the names of the volume directories of an etcd cluster.
+func EtcdVolumePrefix(clusterName, etcdClusterName string) string {
+	return clusterName + "--" + etcdClusterName + "--"
+}
+
+// EtcdVolumeDirs returns the directories that hold the etcd volumes of a control plane machine.
+func EtcdVolumeDirs(cluster *kops.Cluster, host string) []string {
+	var dirs []string
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		dirs = append(dirs, EtcdVolumesDir+"/"+EtcdVolumePrefix(cluster.Name, etcdCluster.Name)+host)
+	}
+	return dirs
+}
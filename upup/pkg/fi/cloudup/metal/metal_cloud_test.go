@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestGetCloudGroups(t *testing.T) {
+	igs := []*kops.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "control-plane"},
+			Spec: kops.InstanceGroupSpec{
+				Role:  kops.InstanceGroupRoleMaster,
+				Metal: &kops.MetalInstanceGroupSpec{Hosts: []string{"10.0.0.1"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+			Spec: kops.InstanceGroupSpec{
+				Role:  kops.InstanceGroupRoleNode,
+				Metal: &kops.MetalInstanceGroupSpec{Hosts: []string{"10.0.0.2", "node-3"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "empty"},
+			Spec: kops.InstanceGroupSpec{
+				Role: kops.InstanceGroupRoleNode,
+			},
+		},
+	}
+	nodes := []v1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "control-plane-1"},
+			Status: v1.NodeStatus{
+				Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-3"},
+		},
+	}
+
+	groups, err := getCloudGroups(igs, nodes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+
+	cp := groups["control-plane"]
+	if cp.TargetSize != 1 || len(cp.Ready) != 1 || len(cp.NeedUpdate) != 0 {
+		t.Errorf("unexpected control plane group: %+v", cp)
+	}
+	if cp.Ready[0].ID != "10.0.0.1" || cp.Ready[0].Node == nil || cp.Ready[0].Node.Name != "control-plane-1" {
+		t.Errorf("expected 10.0.0.1 to be matched with control-plane-1, got %+v", cp.Ready[0])
+	}
+
+	ng := groups["nodes"]
+	if ng.TargetSize != 2 || len(ng.Ready) != 2 {
+		t.Fatalf("unexpected nodes group: %+v", ng)
+	}
+	if ng.Ready[0].Node != nil {
+		t.Errorf("expected 10.0.0.2 not to be matched with a node, got %q", ng.Ready[0].Node.Name)
+	}
+	if ng.Ready[1].Node == nil || ng.Ready[1].Node.Name != "node-3" {
+		t.Errorf("expected node-3 to be matched by name, got %+v", ng.Ready[1])
+	}
+
+	if empty := groups["empty"]; empty.TargetSize != 0 || len(empty.Ready) != 0 {
+		t.Errorf("unexpected empty group: %+v", empty)
+	}
+}
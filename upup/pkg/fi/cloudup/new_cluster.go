@@ -39,6 +39,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
@@ -109,6 +110,11 @@ type NewClusterOptions struct {
 	AzureRouteTableName    string
 	AzureAdminUser         string
 
+	// OCICompartmentID is the OCID of the OCI compartment the cluster is created in.
+	OCICompartmentID string
+	// OCITagNamespace is the name of the OCI defined tag namespace that identifies the instances of the cluster.
+	OCITagNamespace string
+
 	// MasterCount is the number of masters to create. Defaults to the length of MasterZones
 	// if MasterZones is explicitly nonempty, otherwise defaults to 1.
 	MasterCount int32
@@ -124,6 +130,12 @@ type NewClusterOptions struct {
 	NodeCount int32
 	// Bastion enables the creation of a Bastion instance.
 	Bastion bool
+	// BastionMinSize and BastionMaxSize are the sizes of the bastion instance group.
+	// Defaults to leaving them unspecified, which results in a single bastion.
+	BastionMinSize int32
+	BastionMaxSize int32
+	// SessionManager enables access to the instances with AWS Systems Manager Session Manager, instead of a bastion.
+	SessionManager bool
 
 	// Networking is the networking provider/node to use.
 	Networking string
@@ -286,6 +298,18 @@ func NewCluster(opt *NewClusterOptions, clientset simple.Clientset) (*NewCluster
 		return nil, err
 	}
 
+	if opt.SessionManager {
+		if opt.Bastion {
+			return nil, fmt.Errorf("session manager replaces the bastion; --bastion cannot be used with --session-manager")
+		}
+		if api.CloudProviderID(cluster.Spec.CloudProvider) != api.CloudProviderAWS {
+			return nil, fmt.Errorf("session manager is only supported on AWS")
+		}
+		cluster.Spec.SessionManager = &api.SessionManagerSpec{
+			Enabled: fi.Bool(true),
+		}
+	}
+
 	err = setupAPI(opt, &cluster)
 	if err != nil {
 		return nil, err
@@ -408,6 +432,15 @@ func setupVPC(opt *NewClusterOptions, cluster *api.Cluster) error {
 			RouteTableName:    opt.AzureRouteTableName,
 			AdminUser:         opt.AzureAdminUser,
 		}
+	case api.CloudProviderOCI:
+		if cluster.Spec.CloudConfig == nil {
+			cluster.Spec.CloudConfig = &api.CloudConfiguration{}
+		}
+
+		cluster.Spec.CloudConfig.OCI = &api.OCIConfiguration{
+			CompartmentID: opt.OCICompartmentID,
+			TagNamespace:  opt.OCITagNamespace,
+		}
 	}
 
 	if featureflag.Spotinst.Enabled() {
@@ -513,6 +546,29 @@ func setupZones(opt *NewClusterOptions, cluster *api.Cluster, allZones sets.Stri
 			zoneToSubnetMap[zoneName] = subnet
 		}
 
+	case api.CloudProviderOCI:
+		// On OCI, subnets are regional - we create one per region, not per availability domain
+		for _, zoneName := range allZones.List() {
+			region, err := oci.ZoneToRegion(zoneName)
+			if err != nil {
+				return nil, err
+			}
+
+			// We create default subnets named the same as the regions
+			subnetName := region
+
+			subnet := model.FindSubnet(cluster, subnetName)
+			if subnet == nil {
+				subnet = &api.ClusterSubnetSpec{
+					Name:   subnetName,
+					Region: region,
+				}
+				cluster.Spec.Subnets = append(cluster.Spec.Subnets, *subnet)
+			}
+			zoneToSubnetMap[zoneName] = subnet
+		}
+		return zoneToSubnetMap, nil
+
 	case api.CloudProviderAWS:
 		if len(opt.Zones) > 0 && len(opt.SubnetIDs) > 0 {
 			zoneToSubnetProviderID, err = getAWSZoneToSubnetProviderID(cluster.Spec.NetworkID, opt.Zones[0][:len(opt.Zones[0])-1], opt.SubnetIDs)
@@ -679,7 +735,7 @@ func setupMasters(opt *NewClusterOptions, cluster *api.Cluster, zoneToSubnetMap
 			}
 
 			g.Spec.Subnets = []string{subnet.Name}
-			if cp := api.CloudProviderID(cluster.Spec.CloudProvider); cp == api.CloudProviderGCE || cp == api.CloudProviderAzure {
+			if cp := api.CloudProviderID(cluster.Spec.CloudProvider); cp == api.CloudProviderGCE || cp == api.CloudProviderAzure || cp == api.CloudProviderOCI {
 				g.Spec.Zones = []string{zone}
 			}
 
@@ -800,7 +856,7 @@ func setupNodes(opt *NewClusterOptions, cluster *api.Cluster, zoneToSubnetMap ma
 		}
 
 		g.Spec.Subnets = []string{subnet.Name}
-		if cp := api.CloudProviderID(cluster.Spec.CloudProvider); cp == api.CloudProviderGCE || cp == api.CloudProviderAzure {
+		if cp := api.CloudProviderID(cluster.Spec.CloudProvider); cp == api.CloudProviderGCE || cp == api.CloudProviderAzure || cp == api.CloudProviderOCI {
 			g.Spec.Zones = []string{zone}
 		}
 
@@ -848,7 +904,7 @@ func setupAPIServers(opt *NewClusterOptions, cluster *api.Cluster, zoneToSubnetM
 		}
 
 		g.Spec.Subnets = []string{subnet.Name}
-		if cp := api.CloudProviderID(cluster.Spec.CloudProvider); cp == api.CloudProviderGCE || cp == api.CloudProviderAzure {
+		if cp := api.CloudProviderID(cluster.Spec.CloudProvider); cp == api.CloudProviderGCE || cp == api.CloudProviderAzure || cp == api.CloudProviderOCI {
 			g.Spec.Zones = []string{zone}
 		}
 
@@ -928,6 +984,13 @@ func setupNetworking(opt *NewClusterOptions, cluster *api.Cluster) error {
 func setupTopology(opt *NewClusterOptions, cluster *api.Cluster, allZones sets.String) ([]*api.InstanceGroup, error) {
 	var bastions []*api.InstanceGroup
 
+	if !opt.Bastion && (opt.BastionMinSize != 0 || opt.BastionMaxSize != 0) {
+		return nil, fmt.Errorf("bastion sizes can only be specified with --bastion")
+	}
+	if opt.BastionMinSize < 0 || opt.BastionMaxSize < 0 || (opt.BastionMaxSize != 0 && opt.BastionMinSize > opt.BastionMaxSize) {
+		return nil, fmt.Errorf("invalid bastion sizes: minimum %d, maximum %d", opt.BastionMinSize, opt.BastionMaxSize)
+	}
+
 	switch opt.Topology {
 	case api.TopologyPublic, "":
 		cluster.Spec.Topology = &api.TopologySpec{
@@ -1014,6 +1077,15 @@ func setupTopology(opt *NewClusterOptions, cluster *api.Cluster, allZones sets.S
 			bastionGroup.ObjectMeta.Name = "bastions"
 			bastions = append(bastions, bastionGroup)
 
+			if opt.BastionMinSize != 0 {
+				bastionGroup.Spec.MinSize = fi.Int32(opt.BastionMinSize)
+			}
+			if opt.BastionMaxSize != 0 {
+				bastionGroup.Spec.MaxSize = fi.Int32(opt.BastionMaxSize)
+			} else if opt.BastionMinSize != 0 {
+				bastionGroup.Spec.MaxSize = fi.Int32(opt.BastionMinSize)
+			}
+
 			if !dns.IsGossipHostname(cluster.Name) {
 				cluster.Spec.Topology.Bastion = &api.BastionSpec{
 					BastionPublicName: "bastion." + cluster.Name,
@@ -1061,6 +1133,9 @@ func setupAPI(opt *NewClusterOptions, cluster *api.Cluster) error {
 		// in pkg/model/azuremodel/api_loadbalancer.go.
 		cluster.Spec.API = nil
 		return nil
+	} else if api.CloudProviderID(cluster.Spec.CloudProvider) == api.CloudProviderMetal {
+		// There are no load balancers on metal; clients reach the API through the control plane machines.
+		cluster.Spec.API.DNS = &api.DNSAccessSpec{}
 	} else if opt.APILoadBalancerType != "" || opt.APISSLCertificate != "" {
 		cluster.Spec.API.LoadBalancer = &api.LoadBalancerAccessSpec{}
 	} else {
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// VolumesClient is a client for managing block volumes.
+type VolumesClient interface {
+	Create(ctx context.Context, details core.CreateVolumeDetails) (*core.Volume, error)
+	Update(ctx context.Context, id string, details core.UpdateVolumeDetails) error
+	List(ctx context.Context, compartmentID string) ([]core.Volume, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type volumesClientImpl struct {
+	c *core.BlockstorageClient
+}
+
+var _ VolumesClient = &volumesClientImpl{}
+
+func (c *volumesClientImpl) Create(ctx context.Context, details core.CreateVolumeDetails) (*core.Volume, error) {
+	resp, err := c.c.CreateVolume(ctx, core.CreateVolumeRequest{CreateVolumeDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	err = waitForLifecycleState(ctx, "volume "+*resp.Id, string(core.VolumeLifecycleStateAvailable), func() (string, error) {
+		r, err := c.c.GetVolume(ctx, core.GetVolumeRequest{VolumeId: resp.Id})
+		return string(r.LifecycleState), err
+	})
+	return &resp.Volume, err
+}
+
+func (c *volumesClientImpl) Update(ctx context.Context, id string, details core.UpdateVolumeDetails) error {
+	_, err := c.c.UpdateVolume(ctx, core.UpdateVolumeRequest{VolumeId: &id, UpdateVolumeDetails: details})
+	return err
+}
+
+func (c *volumesClientImpl) List(ctx context.Context, compartmentID string) ([]core.Volume, error) {
+	var l []core.Volume
+	req := core.ListVolumesRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListVolumes(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != core.VolumeLifecycleStateTerminated {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *volumesClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeleteVolume(ctx, core.DeleteVolumeRequest{VolumeId: &id})
+	return err
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// InstancePoolsClient is a client for managing instance pools.
+type InstancePoolsClient interface {
+	Create(ctx context.Context, details core.CreateInstancePoolDetails) (*core.InstancePool, error)
+	Get(ctx context.Context, id string) (*core.InstancePool, error)
+	Update(ctx context.Context, id string, details core.UpdateInstancePoolDetails) error
+	List(ctx context.Context, compartmentID string) ([]core.InstancePoolSummary, error)
+	Terminate(ctx context.Context, id string) error
+	AttachLoadBalancer(ctx context.Context, id string, details core.AttachLoadBalancerDetails) error
+	ListInstances(ctx context.Context, compartmentID, id string) ([]core.InstanceSummary, error)
+	// DetachInstance removes an instance from the pool without terminating it.
+	// The pool launches a replacement instance.
+	DetachInstance(ctx context.Context, id, instanceID string) error
+}
+
+type instancePoolsClientImpl struct {
+	c *core.ComputeManagementClient
+}
+
+var _ InstancePoolsClient = &instancePoolsClientImpl{}
+
+func (c *instancePoolsClientImpl) Create(ctx context.Context, details core.CreateInstancePoolDetails) (*core.InstancePool, error) {
+	resp, err := c.c.CreateInstancePool(ctx, core.CreateInstancePoolRequest{CreateInstancePoolDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.InstancePool, nil
+}
+
+func (c *instancePoolsClientImpl) Get(ctx context.Context, id string) (*core.InstancePool, error) {
+	resp, err := c.c.GetInstancePool(ctx, core.GetInstancePoolRequest{InstancePoolId: &id})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.InstancePool, nil
+}
+
+func (c *instancePoolsClientImpl) Update(ctx context.Context, id string, details core.UpdateInstancePoolDetails) error {
+	_, err := c.c.UpdateInstancePool(ctx, core.UpdateInstancePoolRequest{InstancePoolId: &id, UpdateInstancePoolDetails: details})
+	return err
+}
+
+func (c *instancePoolsClientImpl) List(ctx context.Context, compartmentID string) ([]core.InstancePoolSummary, error) {
+	var l []core.InstancePoolSummary
+	req := core.ListInstancePoolsRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListInstancePools(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != core.InstancePoolSummaryLifecycleStateTerminated {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *instancePoolsClientImpl) Terminate(ctx context.Context, id string) error {
+	_, err := c.c.TerminateInstancePool(ctx, core.TerminateInstancePoolRequest{InstancePoolId: &id})
+	return err
+}
+
+func (c *instancePoolsClientImpl) AttachLoadBalancer(ctx context.Context, id string, details core.AttachLoadBalancerDetails) error {
+	_, err := c.c.AttachLoadBalancer(ctx, core.AttachLoadBalancerRequest{InstancePoolId: &id, AttachLoadBalancerDetails: details})
+	return err
+}
+
+func (c *instancePoolsClientImpl) ListInstances(ctx context.Context, compartmentID, id string) ([]core.InstanceSummary, error) {
+	var l []core.InstanceSummary
+	req := core.ListInstancePoolInstancesRequest{CompartmentId: &compartmentID, InstancePoolId: &id}
+	for {
+		resp, err := c.c.ListInstancePoolInstances(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *instancePoolsClientImpl) DetachInstance(ctx context.Context, id, instanceID string) error {
+	_, err := c.c.DetachInstancePoolInstance(ctx, core.DetachInstancePoolInstanceRequest{
+		InstancePoolId: &id,
+		DetachInstancePoolInstanceDetails: core.DetachInstancePoolInstanceDetails{
+			InstanceId:      &instanceID,
+			IsDecrementSize: fi.Bool(false),
+			IsAutoTerminate: fi.Bool(false),
+		},
+	})
+	return err
+}
+
+// InstanceConfigurationsClient is a client for managing instance configurations.
+type InstanceConfigurationsClient interface {
+	Create(ctx context.Context, details core.CreateInstanceConfigurationDetails) (*core.InstanceConfiguration, error)
+	Get(ctx context.Context, id string) (*core.InstanceConfiguration, error)
+	List(ctx context.Context, compartmentID string) ([]core.InstanceConfigurationSummary, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type instanceConfigurationsClientImpl struct {
+	c *core.ComputeManagementClient
+}
+
+var _ InstanceConfigurationsClient = &instanceConfigurationsClientImpl{}
+
+func (c *instanceConfigurationsClientImpl) Create(ctx context.Context, details core.CreateInstanceConfigurationDetails) (*core.InstanceConfiguration, error) {
+	resp, err := c.c.CreateInstanceConfiguration(ctx, core.CreateInstanceConfigurationRequest{CreateInstanceConfiguration: details})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.InstanceConfiguration, nil
+}
+
+func (c *instanceConfigurationsClientImpl) Get(ctx context.Context, id string) (*core.InstanceConfiguration, error) {
+	resp, err := c.c.GetInstanceConfiguration(ctx, core.GetInstanceConfigurationRequest{InstanceConfigurationId: &id})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.InstanceConfiguration, nil
+}
+
+func (c *instanceConfigurationsClientImpl) List(ctx context.Context, compartmentID string) ([]core.InstanceConfigurationSummary, error) {
+	var l []core.InstanceConfigurationSummary
+	req := core.ListInstanceConfigurationsRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListInstanceConfigurations(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *instanceConfigurationsClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeleteInstanceConfiguration(ctx, core.DeleteInstanceConfigurationRequest{InstanceConfigurationId: &id})
+	return err
+}
+
+// InstancesClient is a client for managing instances.
+type InstancesClient interface {
+	Terminate(ctx context.Context, id string) error
+}
+
+type instancesClientImpl struct {
+	c *core.ComputeClient
+}
+
+var _ InstancesClient = &instancesClientImpl{}
+
+func (c *instancesClientImpl) Terminate(ctx context.Context, id string) error {
+	_, err := c.c.TerminateInstance(ctx, core.TerminateInstanceRequest{InstanceId: &id})
+	return err
+}
+
+// ImagesClient is a client for looking up images.
+type ImagesClient interface {
+	// List returns the available images with the given display name.
+	List(ctx context.Context, compartmentID, displayName string) ([]core.Image, error)
+}
+
+type imagesClientImpl struct {
+	c *core.ComputeClient
+}
+
+var _ ImagesClient = &imagesClientImpl{}
+
+func (c *imagesClientImpl) List(ctx context.Context, compartmentID, displayName string) ([]core.Image, error) {
+	var l []core.Image
+	req := core.ListImagesRequest{
+		CompartmentId:  &compartmentID,
+		DisplayName:    &displayName,
+		LifecycleState: core.ImageLifecycleStateAvailable,
+	}
+	for {
+		resp, err := c.c.ListImages(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/identity"
+)
+
+// DynamicGroupsClient is a client for managing dynamic groups.
+// Dynamic groups are created in the root compartment of the tenancy.
+type DynamicGroupsClient interface {
+	Create(ctx context.Context, details identity.CreateDynamicGroupDetails) (*identity.DynamicGroup, error)
+	Update(ctx context.Context, id string, details identity.UpdateDynamicGroupDetails) error
+	List(ctx context.Context, tenancyID string) ([]identity.DynamicGroup, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type dynamicGroupsClientImpl struct {
+	c *identity.IdentityClient
+}
+
+var _ DynamicGroupsClient = &dynamicGroupsClientImpl{}
+
+func (c *dynamicGroupsClientImpl) Create(ctx context.Context, details identity.CreateDynamicGroupDetails) (*identity.DynamicGroup, error) {
+	resp, err := c.c.CreateDynamicGroup(ctx, identity.CreateDynamicGroupRequest{CreateDynamicGroupDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.DynamicGroup, nil
+}
+
+func (c *dynamicGroupsClientImpl) Update(ctx context.Context, id string, details identity.UpdateDynamicGroupDetails) error {
+	_, err := c.c.UpdateDynamicGroup(ctx, identity.UpdateDynamicGroupRequest{DynamicGroupId: &id, UpdateDynamicGroupDetails: details})
+	return err
+}
+
+func (c *dynamicGroupsClientImpl) List(ctx context.Context, tenancyID string) ([]identity.DynamicGroup, error) {
+	var l []identity.DynamicGroup
+	req := identity.ListDynamicGroupsRequest{CompartmentId: &tenancyID}
+	for {
+		resp, err := c.c.ListDynamicGroups(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != identity.DynamicGroupLifecycleStateDeleted {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *dynamicGroupsClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeleteDynamicGroup(ctx, identity.DeleteDynamicGroupRequest{DynamicGroupId: &id})
+	return err
+}
+
+// PoliciesClient is a client for managing IAM policies.
+type PoliciesClient interface {
+	Create(ctx context.Context, details identity.CreatePolicyDetails) (*identity.Policy, error)
+	Update(ctx context.Context, id string, details identity.UpdatePolicyDetails) error
+	List(ctx context.Context, compartmentID string) ([]identity.Policy, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type policiesClientImpl struct {
+	c *identity.IdentityClient
+}
+
+var _ PoliciesClient = &policiesClientImpl{}
+
+func (c *policiesClientImpl) Create(ctx context.Context, details identity.CreatePolicyDetails) (*identity.Policy, error) {
+	resp, err := c.c.CreatePolicy(ctx, identity.CreatePolicyRequest{CreatePolicyDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Policy, nil
+}
+
+func (c *policiesClientImpl) Update(ctx context.Context, id string, details identity.UpdatePolicyDetails) error {
+	_, err := c.c.UpdatePolicy(ctx, identity.UpdatePolicyRequest{PolicyId: &id, UpdatePolicyDetails: details})
+	return err
+}
+
+func (c *policiesClientImpl) List(ctx context.Context, compartmentID string) ([]identity.Policy, error) {
+	var l []identity.Policy
+	req := identity.ListPoliciesRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListPolicies(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != identity.PolicyLifecycleStateDeleted {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *policiesClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeletePolicy(ctx, identity.DeletePolicyRequest{PolicyId: &id})
+	return err
+}
+
+// AvailabilityDomainsClient is a client for listing availability domains.
+type AvailabilityDomainsClient interface {
+	List(ctx context.Context, compartmentID string) ([]identity.AvailabilityDomain, error)
+}
+
+type availabilityDomainsClientImpl struct {
+	c *identity.IdentityClient
+}
+
+var _ AvailabilityDomainsClient = &availabilityDomainsClientImpl{}
+
+func (c *availabilityDomainsClientImpl) List(ctx context.Context, compartmentID string) ([]identity.AvailabilityDomain, error) {
+	resp, err := c.c.ListAvailabilityDomains(ctx, identity.ListAvailabilityDomainsRequest{CompartmentId: &compartmentID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
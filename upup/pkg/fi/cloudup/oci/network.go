@@ -0,0 +1,303 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// VCNsClient is a client for managing Virtual Cloud Networks.
+type VCNsClient interface {
+	Create(ctx context.Context, details core.CreateVcnDetails) (*core.Vcn, error)
+	Update(ctx context.Context, id string, details core.UpdateVcnDetails) error
+	List(ctx context.Context, compartmentID string) ([]core.Vcn, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type vcnsClientImpl struct {
+	c *core.VirtualNetworkClient
+}
+
+var _ VCNsClient = &vcnsClientImpl{}
+
+func (c *vcnsClientImpl) Create(ctx context.Context, details core.CreateVcnDetails) (*core.Vcn, error) {
+	resp, err := c.c.CreateVcn(ctx, core.CreateVcnRequest{CreateVcnDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	err = waitForLifecycleState(ctx, "VCN "+*resp.Id, string(core.VcnLifecycleStateAvailable), func() (string, error) {
+		r, err := c.c.GetVcn(ctx, core.GetVcnRequest{VcnId: resp.Id})
+		return string(r.LifecycleState), err
+	})
+	return &resp.Vcn, err
+}
+
+func (c *vcnsClientImpl) Update(ctx context.Context, id string, details core.UpdateVcnDetails) error {
+	_, err := c.c.UpdateVcn(ctx, core.UpdateVcnRequest{VcnId: &id, UpdateVcnDetails: details})
+	return err
+}
+
+func (c *vcnsClientImpl) List(ctx context.Context, compartmentID string) ([]core.Vcn, error) {
+	var l []core.Vcn
+	req := core.ListVcnsRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListVcns(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != core.VcnLifecycleStateTerminated {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *vcnsClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeleteVcn(ctx, core.DeleteVcnRequest{VcnId: &id})
+	return err
+}
+
+// SubnetsClient is a client for managing subnets.
+type SubnetsClient interface {
+	Create(ctx context.Context, details core.CreateSubnetDetails) (*core.Subnet, error)
+	Update(ctx context.Context, id string, details core.UpdateSubnetDetails) error
+	List(ctx context.Context, compartmentID string) ([]core.Subnet, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type subnetsClientImpl struct {
+	c *core.VirtualNetworkClient
+}
+
+var _ SubnetsClient = &subnetsClientImpl{}
+
+func (c *subnetsClientImpl) Create(ctx context.Context, details core.CreateSubnetDetails) (*core.Subnet, error) {
+	resp, err := c.c.CreateSubnet(ctx, core.CreateSubnetRequest{CreateSubnetDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	err = waitForLifecycleState(ctx, "subnet "+*resp.Id, string(core.SubnetLifecycleStateAvailable), func() (string, error) {
+		r, err := c.c.GetSubnet(ctx, core.GetSubnetRequest{SubnetId: resp.Id})
+		return string(r.LifecycleState), err
+	})
+	return &resp.Subnet, err
+}
+
+func (c *subnetsClientImpl) Update(ctx context.Context, id string, details core.UpdateSubnetDetails) error {
+	_, err := c.c.UpdateSubnet(ctx, core.UpdateSubnetRequest{SubnetId: &id, UpdateSubnetDetails: details})
+	return err
+}
+
+func (c *subnetsClientImpl) List(ctx context.Context, compartmentID string) ([]core.Subnet, error) {
+	var l []core.Subnet
+	req := core.ListSubnetsRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListSubnets(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != core.SubnetLifecycleStateTerminated {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *subnetsClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeleteSubnet(ctx, core.DeleteSubnetRequest{SubnetId: &id})
+	return err
+}
+
+// InternetGatewaysClient is a client for managing internet gateways.
+type InternetGatewaysClient interface {
+	Create(ctx context.Context, details core.CreateInternetGatewayDetails) (*core.InternetGateway, error)
+	Update(ctx context.Context, id string, details core.UpdateInternetGatewayDetails) error
+	List(ctx context.Context, compartmentID string) ([]core.InternetGateway, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type internetGatewaysClientImpl struct {
+	c *core.VirtualNetworkClient
+}
+
+var _ InternetGatewaysClient = &internetGatewaysClientImpl{}
+
+func (c *internetGatewaysClientImpl) Create(ctx context.Context, details core.CreateInternetGatewayDetails) (*core.InternetGateway, error) {
+	resp, err := c.c.CreateInternetGateway(ctx, core.CreateInternetGatewayRequest{CreateInternetGatewayDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	err = waitForLifecycleState(ctx, "internet gateway "+*resp.Id, string(core.InternetGatewayLifecycleStateAvailable), func() (string, error) {
+		r, err := c.c.GetInternetGateway(ctx, core.GetInternetGatewayRequest{IgId: resp.Id})
+		return string(r.LifecycleState), err
+	})
+	return &resp.InternetGateway, err
+}
+
+func (c *internetGatewaysClientImpl) Update(ctx context.Context, id string, details core.UpdateInternetGatewayDetails) error {
+	_, err := c.c.UpdateInternetGateway(ctx, core.UpdateInternetGatewayRequest{IgId: &id, UpdateInternetGatewayDetails: details})
+	return err
+}
+
+func (c *internetGatewaysClientImpl) List(ctx context.Context, compartmentID string) ([]core.InternetGateway, error) {
+	var l []core.InternetGateway
+	req := core.ListInternetGatewaysRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListInternetGateways(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != core.InternetGatewayLifecycleStateTerminated {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *internetGatewaysClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeleteInternetGateway(ctx, core.DeleteInternetGatewayRequest{IgId: &id})
+	return err
+}
+
+// RouteTablesClient is a client for managing route tables.
+type RouteTablesClient interface {
+	Create(ctx context.Context, details core.CreateRouteTableDetails) (*core.RouteTable, error)
+	Update(ctx context.Context, id string, details core.UpdateRouteTableDetails) error
+	List(ctx context.Context, compartmentID string) ([]core.RouteTable, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type routeTablesClientImpl struct {
+	c *core.VirtualNetworkClient
+}
+
+var _ RouteTablesClient = &routeTablesClientImpl{}
+
+func (c *routeTablesClientImpl) Create(ctx context.Context, details core.CreateRouteTableDetails) (*core.RouteTable, error) {
+	resp, err := c.c.CreateRouteTable(ctx, core.CreateRouteTableRequest{CreateRouteTableDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	err = waitForLifecycleState(ctx, "route table "+*resp.Id, string(core.RouteTableLifecycleStateAvailable), func() (string, error) {
+		r, err := c.c.GetRouteTable(ctx, core.GetRouteTableRequest{RtId: resp.Id})
+		return string(r.LifecycleState), err
+	})
+	return &resp.RouteTable, err
+}
+
+func (c *routeTablesClientImpl) Update(ctx context.Context, id string, details core.UpdateRouteTableDetails) error {
+	_, err := c.c.UpdateRouteTable(ctx, core.UpdateRouteTableRequest{RtId: &id, UpdateRouteTableDetails: details})
+	return err
+}
+
+func (c *routeTablesClientImpl) List(ctx context.Context, compartmentID string) ([]core.RouteTable, error) {
+	var l []core.RouteTable
+	req := core.ListRouteTablesRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListRouteTables(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != core.RouteTableLifecycleStateTerminated {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *routeTablesClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeleteRouteTable(ctx, core.DeleteRouteTableRequest{RtId: &id})
+	return err
+}
+
+// SecurityListsClient is a client for managing security lists.
+type SecurityListsClient interface {
+	Create(ctx context.Context, details core.CreateSecurityListDetails) (*core.SecurityList, error)
+	Update(ctx context.Context, id string, details core.UpdateSecurityListDetails) error
+	List(ctx context.Context, compartmentID string) ([]core.SecurityList, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type securityListsClientImpl struct {
+	c *core.VirtualNetworkClient
+}
+
+var _ SecurityListsClient = &securityListsClientImpl{}
+
+func (c *securityListsClientImpl) Create(ctx context.Context, details core.CreateSecurityListDetails) (*core.SecurityList, error) {
+	resp, err := c.c.CreateSecurityList(ctx, core.CreateSecurityListRequest{CreateSecurityListDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	err = waitForLifecycleState(ctx, "security list "+*resp.Id, string(core.SecurityListLifecycleStateAvailable), func() (string, error) {
+		r, err := c.c.GetSecurityList(ctx, core.GetSecurityListRequest{SecurityListId: resp.Id})
+		return string(r.LifecycleState), err
+	})
+	return &resp.SecurityList, err
+}
+
+func (c *securityListsClientImpl) Update(ctx context.Context, id string, details core.UpdateSecurityListDetails) error {
+	_, err := c.c.UpdateSecurityList(ctx, core.UpdateSecurityListRequest{SecurityListId: &id, UpdateSecurityListDetails: details})
+	return err
+}
+
+func (c *securityListsClientImpl) List(ctx context.Context, compartmentID string) ([]core.SecurityList, error) {
+	var l []core.SecurityList
+	req := core.ListSecurityListsRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListSecurityLists(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != core.SecurityListLifecycleStateTerminated {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *securityListsClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeleteSecurityList(ctx, core.DeleteSecurityListRequest{SecurityListId: &id})
+	return err
+}
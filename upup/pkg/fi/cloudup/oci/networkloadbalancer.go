@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/networkloadbalancer"
+)
+
+// NetworkLoadBalancersClient is a client for managing network load balancers.
+type NetworkLoadBalancersClient interface {
+	Create(ctx context.Context, details networkloadbalancer.CreateNetworkLoadBalancerDetails) (*networkloadbalancer.NetworkLoadBalancer, error)
+	Get(ctx context.Context, id string) (*networkloadbalancer.NetworkLoadBalancer, error)
+	List(ctx context.Context, compartmentID string) ([]networkloadbalancer.NetworkLoadBalancerSummary, error)
+	Update(ctx context.Context, id string, details networkloadbalancer.UpdateNetworkLoadBalancerDetails) error
+	Delete(ctx context.Context, id string) error
+}
+
+type networkLoadBalancersClientImpl struct {
+	c *networkloadbalancer.NetworkLoadBalancerClient
+}
+
+var _ NetworkLoadBalancersClient = &networkLoadBalancersClientImpl{}
+
+func (c *networkLoadBalancersClientImpl) Create(ctx context.Context, details networkloadbalancer.CreateNetworkLoadBalancerDetails) (*networkloadbalancer.NetworkLoadBalancer, error) {
+	resp, err := c.c.CreateNetworkLoadBalancer(ctx, networkloadbalancer.CreateNetworkLoadBalancerRequest{CreateNetworkLoadBalancerDetails: details})
+	if err != nil {
+		return nil, err
+	}
+	// The load balancer must be active before instance pools can be attached to it.
+	var nlb *networkloadbalancer.NetworkLoadBalancer
+	err = waitForLifecycleState(ctx, "network load balancer "+*resp.Id, string(networkloadbalancer.LifecycleStateActive), func() (string, error) {
+		var err error
+		nlb, err = c.Get(ctx, *resp.Id)
+		if err != nil {
+			return "", err
+		}
+		return string(nlb.LifecycleState), nil
+	})
+	return nlb, err
+}
+
+func (c *networkLoadBalancersClientImpl) Get(ctx context.Context, id string) (*networkloadbalancer.NetworkLoadBalancer, error) {
+	resp, err := c.c.GetNetworkLoadBalancer(ctx, networkloadbalancer.GetNetworkLoadBalancerRequest{NetworkLoadBalancerId: &id})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.NetworkLoadBalancer, nil
+}
+
+func (c *networkLoadBalancersClientImpl) List(ctx context.Context, compartmentID string) ([]networkloadbalancer.NetworkLoadBalancerSummary, error) {
+	var l []networkloadbalancer.NetworkLoadBalancerSummary
+	req := networkloadbalancer.ListNetworkLoadBalancersRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.c.ListNetworkLoadBalancers(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Items {
+			if v.LifecycleState != networkloadbalancer.LifecycleStateDeleted {
+				l = append(l, v)
+			}
+		}
+		if resp.OpcNextPage == nil {
+			return l, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *networkLoadBalancersClientImpl) Update(ctx context.Context, id string, details networkloadbalancer.UpdateNetworkLoadBalancerDetails) error {
+	_, err := c.c.UpdateNetworkLoadBalancer(ctx, networkloadbalancer.UpdateNetworkLoadBalancerRequest{
+		NetworkLoadBalancerId:            &id,
+		UpdateNetworkLoadBalancerDetails: details,
+	})
+	return err
+}
+
+func (c *networkLoadBalancersClientImpl) Delete(ctx context.Context, id string) error {
+	_, err := c.c.DeleteNetworkLoadBalancer(ctx, networkloadbalancer.DeleteNetworkLoadBalancerRequest{NetworkLoadBalancerId: &id})
+	return err
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// OCIAPITarget is a target whose purpose is to provide access OCICloud.
+type OCIAPITarget struct {
+	Cloud OCICloud
+}
+
+var _ fi.Target = &OCIAPITarget{}
+
+// NewOCIAPITarget returns a new OCIAPITarget.
+func NewOCIAPITarget(cloud OCICloud) *OCIAPITarget {
+	return &OCIAPITarget{
+		Cloud: cloud,
+	}
+}
+
+// Finish is called by a lifecycle drive to finish the lifecycle of
+// the target.
+func (t *OCIAPITarget) Finish(taskMap map[string]fi.Task) error {
+	return nil
+}
+
+// ProcessDeletions returns true if we should delete resources.
+func (t *OCIAPITarget) ProcessDeletions() bool {
+	return true
+}
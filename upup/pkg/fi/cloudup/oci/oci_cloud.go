@@ -0,0 +1,295 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/networkloadbalancer"
+	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	TagClusterName = "KubernetesCluster"
+	// OCI freeform tag keys cannot contain periods, so we follow the OpenStack naming.
+	TagKopsInstanceGroup     = "KopsInstanceGroup"
+	TagKopsRole              = "KopsRole"
+	TagRoleMaster            = "Master"
+	TagRoleAPILoadBalancer   = "APILoadBalancer"
+	TagNameEtcdClusterPrefix = "KopsEtcd_"
+
+	// DefinedTagKeyCluster is the key, in the defined tag namespace of the cluster, of the tag that identifies its instances.
+	// Dynamic groups can only match instances by defined tags.
+	DefinedTagKeyCluster = "kops-cluster"
+
+	// envAuth selects the authentication method, following the convention of the OCI CLI.
+	envAuth                  = "OCI_CLI_AUTH"
+	authInstancePrincipal    = "instance_principal"
+	lifecycleStateTerminated = "TERMINATED"
+)
+
+// OCICloud provides clients to make API calls to Oracle Cloud Infrastructure.
+type OCICloud interface {
+	fi.Cloud
+
+	// CompartmentID returns the OCID of the compartment where the cluster is built.
+	CompartmentID() string
+	// TenancyID returns the OCID of the tenancy, which is the root compartment.
+	TenancyID() string
+	// Tags returns the freeform tags that identify the resources of the cluster.
+	Tags() map[string]string
+
+	VCN() VCNsClient
+	Subnet() SubnetsClient
+	InternetGateway() InternetGatewaysClient
+	RouteTable() RouteTablesClient
+	SecurityList() SecurityListsClient
+	InstancePool() InstancePoolsClient
+	InstanceConfiguration() InstanceConfigurationsClient
+	Instance() InstancesClient
+	Image() ImagesClient
+	Volume() VolumesClient
+	NetworkLoadBalancer() NetworkLoadBalancersClient
+	DynamicGroup() DynamicGroupsClient
+	Policy() PoliciesClient
+	AvailabilityDomain() AvailabilityDomainsClient
+}
+
+type ociCloudImplementation struct {
+	compartmentID string
+	tenancyID     string
+	region        string
+	tags          map[string]string
+
+	vcnsClient                   VCNsClient
+	subnetsClient                SubnetsClient
+	internetGatewaysClient       InternetGatewaysClient
+	routeTablesClient            RouteTablesClient
+	securityListsClient          SecurityListsClient
+	instancePoolsClient          InstancePoolsClient
+	instanceConfigurationsClient InstanceConfigurationsClient
+	instancesClient              InstancesClient
+	imagesClient                 ImagesClient
+	volumesClient                VolumesClient
+	networkLoadBalancersClient   NetworkLoadBalancersClient
+	dynamicGroupsClient          DynamicGroupsClient
+	policiesClient               PoliciesClient
+	availabilityDomainsClient    AvailabilityDomainsClient
+}
+
+var _ fi.Cloud = &ociCloudImplementation{}
+
+// NewOCICloud creates a new OCICloud.
+// Credentials are read from the OCI configuration file and environment,
+// or from the instance principal if OCI_CLI_AUTH is set to instance_principal.
+func NewOCICloud(compartmentID, region string, tags map[string]string) (OCICloud, error) {
+	if compartmentID == "" {
+		return nil, errors.New("the compartment of the cluster must be specified")
+	}
+
+	var configProvider common.ConfigurationProvider
+	if os.Getenv(envAuth) == authInstancePrincipal {
+		p, err := auth.InstancePrincipalConfigurationProvider()
+		if err != nil {
+			return nil, fmt.Errorf("error building instance principal configuration: %v", err)
+		}
+		configProvider = p
+	} else {
+		configProvider = common.DefaultConfigProvider()
+	}
+
+	tenancyID, err := configProvider.TenancyOCID()
+	if err != nil {
+		return nil, fmt.Errorf("error reading OCI tenancy: %v", err)
+	}
+
+	virtualNetworkClient, err := core.NewVirtualNetworkClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error building virtual network client: %v", err)
+	}
+	virtualNetworkClient.SetRegion(region)
+	computeClient, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error building compute client: %v", err)
+	}
+	computeClient.SetRegion(region)
+	computeManagementClient, err := core.NewComputeManagementClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error building compute management client: %v", err)
+	}
+	computeManagementClient.SetRegion(region)
+	blockstorageClient, err := core.NewBlockstorageClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error building block storage client: %v", err)
+	}
+	blockstorageClient.SetRegion(region)
+	nlbClient, err := networkloadbalancer.NewNetworkLoadBalancerClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error building network load balancer client: %v", err)
+	}
+	nlbClient.SetRegion(region)
+	identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error building identity client: %v", err)
+	}
+	identityClient.SetRegion(region)
+
+	return &ociCloudImplementation{
+		compartmentID:                compartmentID,
+		tenancyID:                    tenancyID,
+		region:                       region,
+		tags:                         tags,
+		vcnsClient:                   &vcnsClientImpl{c: &virtualNetworkClient},
+		subnetsClient:                &subnetsClientImpl{c: &virtualNetworkClient},
+		internetGatewaysClient:       &internetGatewaysClientImpl{c: &virtualNetworkClient},
+		routeTablesClient:            &routeTablesClientImpl{c: &virtualNetworkClient},
+		securityListsClient:          &securityListsClientImpl{c: &virtualNetworkClient},
+		instancePoolsClient:          &instancePoolsClientImpl{c: &computeManagementClient},
+		instanceConfigurationsClient: &instanceConfigurationsClientImpl{c: &computeManagementClient},
+		instancesClient:              &instancesClientImpl{c: &computeClient},
+		imagesClient:                 &imagesClientImpl{c: &computeClient},
+		volumesClient:                &volumesClientImpl{c: &blockstorageClient},
+		networkLoadBalancersClient:   &networkLoadBalancersClientImpl{c: &nlbClient},
+		dynamicGroupsClient:          &dynamicGroupsClientImpl{c: &identityClient},
+		policiesClient:               &policiesClientImpl{c: &identityClient},
+		availabilityDomainsClient:    &availabilityDomainsClientImpl{c: &identityClient},
+	}, nil
+}
+
+func (c *ociCloudImplementation) Region() string {
+	return c.region
+}
+
+func (c *ociCloudImplementation) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderOCI
+}
+
+func (c *ociCloudImplementation) DNS() (dnsprovider.Interface, error) {
+	return nil, errors.New("DNS not implemented on ociCloud")
+}
+
+func (c *ociCloudImplementation) FindVPCInfo(id string) (*fi.VPCInfo, error) {
+	return nil, errors.New("FindVPCInfo not implemented on ociCloud")
+}
+
+func (c *ociCloudImplementation) GetApiIngressStatus(cluster *kops.Cluster) ([]fi.ApiIngressStatus, error) {
+	return FindAPIIngressStatus(c, cluster)
+}
+
+// FindAPIIngressStatus returns the addresses of the API load balancer of the cluster.
+func FindAPIIngressStatus(c OCICloud, cluster *kops.Cluster) ([]fi.ApiIngressStatus, error) {
+	if cluster.Spec.API == nil || cluster.Spec.API.LoadBalancer == nil {
+		return nil, nil
+	}
+
+	nlbs, err := c.NetworkLoadBalancer().List(context.TODO(), c.CompartmentID())
+	if err != nil {
+		return nil, fmt.Errorf("error listing network load balancers: %v", err)
+	}
+
+	var ingresses []fi.ApiIngressStatus
+	for _, nlb := range nlbs {
+		if nlb.FreeformTags[TagClusterName] != cluster.Name || nlb.FreeformTags[TagKopsRole] != TagRoleAPILoadBalancer {
+			continue
+		}
+		for _, ip := range nlb.IpAddresses {
+			// Only report the public addresses of public load balancers, and the private addresses of private ones.
+			if ip.IpAddress == nil || fi.BoolValue(ip.IsPublic) == fi.BoolValue(nlb.IsPrivate) {
+				continue
+			}
+			ingresses = append(ingresses, fi.ApiIngressStatus{IP: *ip.IpAddress})
+		}
+	}
+	return ingresses, nil
+}
+
+func (c *ociCloudImplementation) CompartmentID() string {
+	return c.compartmentID
+}
+
+func (c *ociCloudImplementation) TenancyID() string {
+	return c.tenancyID
+}
+
+func (c *ociCloudImplementation) Tags() map[string]string {
+	return c.tags
+}
+
+func (c *ociCloudImplementation) VCN() VCNsClient {
+	return c.vcnsClient
+}
+
+func (c *ociCloudImplementation) Subnet() SubnetsClient {
+	return c.subnetsClient
+}
+
+func (c *ociCloudImplementation) InternetGateway() InternetGatewaysClient {
+	return c.internetGatewaysClient
+}
+
+func (c *ociCloudImplementation) RouteTable() RouteTablesClient {
+	return c.routeTablesClient
+}
+
+func (c *ociCloudImplementation) SecurityList() SecurityListsClient {
+	return c.securityListsClient
+}
+
+func (c *ociCloudImplementation) InstancePool() InstancePoolsClient {
+	return c.instancePoolsClient
+}
+
+func (c *ociCloudImplementation) InstanceConfiguration() InstanceConfigurationsClient {
+	return c.instanceConfigurationsClient
+}
+
+func (c *ociCloudImplementation) Instance() InstancesClient {
+	return c.instancesClient
+}
+
+func (c *ociCloudImplementation) Image() ImagesClient {
+	return c.imagesClient
+}
+
+func (c *ociCloudImplementation) Volume() VolumesClient {
+	return c.volumesClient
+}
+
+func (c *ociCloudImplementation) NetworkLoadBalancer() NetworkLoadBalancersClient {
+	return c.networkLoadBalancersClient
+}
+
+func (c *ociCloudImplementation) DynamicGroup() DynamicGroupsClient {
+	return c.dynamicGroupsClient
+}
+
+func (c *ociCloudImplementation) Policy() PoliciesClient {
+	return c.policiesClient
+}
+
+func (c *ociCloudImplementation) AvailabilityDomain() AvailabilityDomainsClient {
+	return c.availabilityDomainsClient
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/protokube/pkg/etcd"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// FindClusterStatus discovers the status of the cluster by looking for the tagged etcd volumes.
+func (c *ociCloudImplementation) FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error) {
+	return findClusterStatus(c, cluster)
+}
+
+func findClusterStatus(c OCICloud, cluster *kops.Cluster) (*kops.ClusterStatus, error) {
+	klog.V(2).Infof("Listing OCI block volumes.")
+	volumes, err := c.Volume().List(context.TODO(), c.CompartmentID())
+	if err != nil {
+		return nil, fmt.Errorf("error listing volumes: %v", err)
+	}
+
+	etcdStatus, err := findEtcdStatus(c, volumes)
+	if err != nil {
+		return nil, err
+	}
+	status := &kops.ClusterStatus{
+		EtcdClusters: etcdStatus,
+	}
+	klog.V(2).Infof("Cluster status (from cloud): %v", fi.DebugAsJsonString(status))
+	return status, nil
+}
+
+func findEtcdStatus(c OCICloud, volumes []core.Volume) ([]kops.EtcdClusterStatus, error) {
+	statusMap := make(map[string]*kops.EtcdClusterStatus)
+	for _, volume := range volumes {
+		if !hasTags(volume.FreeformTags, c.Tags()) || volume.FreeformTags[TagKopsRole] != TagRoleMaster {
+			continue
+		}
+
+		var (
+			etcdClusterName string
+			etcdClusterSpec *etcd.EtcdClusterSpec
+		)
+		for k, v := range volume.FreeformTags {
+			if strings.HasPrefix(k, TagNameEtcdClusterPrefix) {
+				etcdClusterName = strings.TrimPrefix(k, TagNameEtcdClusterPrefix)
+				var err error
+				etcdClusterSpec, err = etcd.ParseEtcdClusterSpec(etcdClusterName, v)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing etcd cluster tag %q on volume %q: %v", v, fi.StringValue(volume.DisplayName), err)
+				}
+			}
+		}
+		if etcdClusterName == "" || etcdClusterSpec == nil {
+			continue
+		}
+
+		status := statusMap[etcdClusterName]
+		if status == nil {
+			status = &kops.EtcdClusterStatus{
+				Name: etcdClusterName,
+			}
+			statusMap[etcdClusterName] = status
+		}
+		status.Members = append(status.Members, &kops.EtcdMemberStatus{
+			Name:     etcdClusterSpec.NodeName,
+			VolumeId: fi.StringValue(volume.Id),
+		})
+	}
+
+	var status []kops.EtcdClusterStatus
+	for _, v := range statusMap {
+		status = append(status, *v)
+	}
+	return status, nil
+}
+
+// hasTags returns true if actual contains all the expected tags.
+func hasTags(actual, expected map[string]string) bool {
+	for k, v := range expected {
+		if actual[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// GetCloudGroups returns Cloud Instance Groups for the cluster
+// by querying OCI.
+func (c *ociCloudImplementation) GetCloudGroups(
+	cluster *kops.Cluster,
+	instancegroups []*kops.InstanceGroup,
+	warnUnmatched bool,
+	nodes []v1.Node,
+) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	return getCloudGroups(c, cluster, instancegroups, warnUnmatched, nodes)
+}
+
+func getCloudGroups(c OCICloud, cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	igsByName := make(map[string]*kops.InstanceGroup)
+	for _, ig := range instancegroups {
+		igsByName[ig.Name] = ig
+	}
+
+	ctx := context.TODO()
+	pools, err := c.InstancePool().List(ctx, c.CompartmentID())
+	if err != nil {
+		return nil, fmt.Errorf("unable to find instance pools: %v", err)
+	}
+
+	nodeMap := cloudinstances.GetNodeMap(nodes, cluster)
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for i := range pools {
+		pool := &pools[i]
+		if pool.FreeformTags[TagClusterName] != cluster.Name {
+			continue
+		}
+
+		igName := pool.FreeformTags[TagKopsInstanceGroup]
+		ig, ok := igsByName[igName]
+		if !ok {
+			if warnUnmatched {
+				klog.Warningf("Found instance pool %q with no corresponding instance group", fi.StringValue(pool.DisplayName))
+			}
+			continue
+		}
+
+		cig, err := buildCloudInstanceGroup(ctx, c, ig, pool, nodeMap)
+		if err != nil {
+			return nil, fmt.Errorf("error getting cloud instance group %q: %v", ig.Name, err)
+		}
+		groups[ig.Name] = cig
+	}
+	return groups, nil
+}
+
+func buildCloudInstanceGroup(ctx context.Context, c OCICloud, ig *kops.InstanceGroup, pool *core.InstancePoolSummary, nodeMap map[string]*v1.Node) (*cloudinstances.CloudInstanceGroup, error) {
+	size := fi.IntValue(pool.Size)
+	cg := &cloudinstances.CloudInstanceGroup{
+		HumanName:     fi.StringValue(pool.DisplayName),
+		InstanceGroup: ig,
+		MinSize:       size,
+		TargetSize:    size,
+		MaxSize:       size,
+		Raw:           pool,
+	}
+
+	instances, err := c.InstancePool().ListInstances(ctx, c.CompartmentID(), fi.StringValue(pool.Id))
+	if err != nil {
+		return nil, fmt.Errorf("error listing instances of instance pool: %v", err)
+	}
+	for _, instance := range instances {
+		state := strings.ToLower(fi.StringValue(instance.State))
+		if state == "terminating" || state == "terminated" {
+			klog.V(4).Infof("ignoring instance %q in state %q", fi.StringValue(instance.Id), state)
+			continue
+		}
+
+		id := fi.StringValue(instance.Id)
+		status := cloudinstances.CloudInstanceStatusUpToDate
+		if fi.StringValue(instance.InstanceConfigurationId) != fi.StringValue(pool.InstanceConfigurationId) {
+			status = cloudinstances.CloudInstanceStatusNeedsUpdate
+		}
+		cm, err := cg.NewCloudInstance(id, status, nodeMap[id])
+		if err != nil {
+			return nil, fmt.Errorf("error creating cloud instance group member: %v", err)
+		}
+		cm.MachineType = fi.StringValue(instance.Shape)
+	}
+
+	return cg, nil
+}
+
+// DeleteGroup terminates the instance pool and deletes its instance configuration.
+func (c *ociCloudImplementation) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
+	return deleteGroup(c, g)
+}
+
+func deleteGroup(c OCICloud, g *cloudinstances.CloudInstanceGroup) error {
+	pool := g.Raw.(*core.InstancePoolSummary)
+	ctx := context.TODO()
+
+	klog.V(2).Infof("Terminating instance pool %q", fi.StringValue(pool.DisplayName))
+	if err := c.InstancePool().Terminate(ctx, fi.StringValue(pool.Id)); err != nil {
+		return fmt.Errorf("error terminating instance pool %q: %v", fi.StringValue(pool.DisplayName), err)
+	}
+
+	// The instance configuration cannot be deleted while the pool still refers to it.
+	err := waitForLifecycleState(ctx, "instance pool "+fi.StringValue(pool.DisplayName), lifecycleStateTerminated, func() (string, error) {
+		p, err := c.InstancePool().Get(ctx, fi.StringValue(pool.Id))
+		if err != nil {
+			return "", err
+		}
+		return string(p.LifecycleState), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.InstanceConfiguration().Delete(ctx, fi.StringValue(pool.InstanceConfigurationId)); err != nil {
+		return fmt.Errorf("error deleting instance configuration of pool %q: %v", fi.StringValue(pool.DisplayName), err)
+	}
+	return nil
+}
+
+// DeleteInstance terminates an instance. The instance pool replaces it with
+// a new instance built from its current instance configuration.
+func (c *ociCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstance) error {
+	klog.V(2).Infof("Terminating OCI instance %q", i.ID)
+	return c.Instance().Terminate(context.TODO(), i.ID)
+}
+
+// DetachInstance detaches an instance from its instance pool without
+// terminating it, so that the pool launches a replacement.
+func (c *ociCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance) error {
+	pool := i.CloudInstanceGroup.Raw.(*core.InstancePoolSummary)
+	klog.V(2).Infof("Detaching OCI instance %q from instance pool %q", i.ID, fi.StringValue(pool.DisplayName))
+	return c.InstancePool().DetachInstance(context.TODO(), fi.StringValue(pool.Id), i.ID)
+}
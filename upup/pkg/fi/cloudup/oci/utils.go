@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+const (
+	lifecyclePollInterval = 5 * time.Second
+	lifecyclePollTimeout  = 10 * time.Minute
+)
+
+// waitForLifecycleState polls a resource until it reaches the desired lifecycle state.
+func waitForLifecycleState(ctx context.Context, description, desiredState string, get func() (string, error)) error {
+	return wait.PollImmediate(lifecyclePollInterval, lifecyclePollTimeout, func() (bool, error) {
+		state, err := get()
+		if err != nil {
+			return false, fmt.Errorf("error getting %s: %v", description, err)
+		}
+		if state == desiredState {
+			return true, nil
+		}
+		if state == lifecycleStateTerminated || state == "FAILED" {
+			return false, fmt.Errorf("%s reached lifecycle state %s while waiting for %s", description, state, desiredState)
+		}
+		klog.V(4).Infof("waiting for %s to become %s, currently %s", description, desiredState, state)
+		return false, nil
+	})
+}
+
+// ZoneToRegion extracts the region from a zone of the
+// form <region>-ad-<availability-domain-number>.
+func ZoneToRegion(zone string) (string, error) {
+	i := strings.LastIndex(zone, "-ad-")
+	if i <= 0 {
+		return "", fmt.Errorf("invalid OCI zone: %q", zone)
+	}
+	return zone[:i], nil
+}
+
+// FindAvailabilityDomain returns the name of the availability domain for a zone.
+// Availability domain names carry a tenancy-specific prefix (e.g. "Uocm:PHX-AD-1"),
+// so they are matched by their "-AD-<n>" suffix.
+func FindAvailabilityDomain(ads []identity.AvailabilityDomain, zone string) (string, error) {
+	i := strings.LastIndex(zone, "-ad-")
+	if i <= 0 {
+		return "", fmt.Errorf("invalid OCI zone: %q", zone)
+	}
+	suffix := strings.ToUpper(zone[i:])
+	for _, ad := range ads {
+		if ad.Name != nil && strings.HasSuffix(strings.ToUpper(*ad.Name), suffix) {
+			return *ad.Name, nil
+		}
+	}
+	return "", fmt.Errorf("unable to find availability domain for zone %q", zone)
+}
+
+// SafeTagKey returns a freeform tag key that OCI accepts; "." and "/" are not
+// allowed in tag keys, so they are replaced with "_".
+func SafeTagKey(key string) string {
+	return strings.NewReplacer(".", "_", "/", "_").Replace(key)
+}
+
+// SafeIdentityName returns a name for an IAM resource; IAM names are unique
+// within the tenancy and may not contain ".".
+func SafeIdentityName(name string) string {
+	return strings.ReplaceAll(name, ".", "-")
+}
+
+// ShapeArchitecture returns the CPU architecture of a compute shape.
+// Ampere shapes (e.g. VM.Standard.A1.Flex) are arm64, all others are amd64.
+func ShapeArchitecture(shape string) architectures.Architecture {
+	if strings.Contains(shape, ".A1.") {
+		return architectures.ArchitectureArm64
+	}
+	return architectures.ArchitectureAmd64
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+func TestZoneToRegion(t *testing.T) {
+	testCases := []struct {
+		zone     string
+		expected string
+		success  bool
+	}{
+		{
+			zone:     "us-ashburn-1-ad-1",
+			expected: "us-ashburn-1",
+			success:  true,
+		},
+		{
+			zone:     "eu-frankfurt-1-ad-3",
+			expected: "eu-frankfurt-1",
+			success:  true,
+		},
+		{
+			zone:    "us-ashburn-1",
+			success: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.zone, func(t *testing.T) {
+			region, err := ZoneToRegion(tc.zone)
+			if !tc.success {
+				if err == nil {
+					t.Fatalf("unexpected success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if region != tc.expected {
+				t.Errorf("expected %s, but got %s", tc.expected, region)
+			}
+		})
+	}
+}
+
+func TestFindAvailabilityDomain(t *testing.T) {
+	ads := []identity.AvailabilityDomain{
+		{Name: fi.String("Uocm:US-ASHBURN-AD-1")},
+		{Name: fi.String("Uocm:US-ASHBURN-AD-2")},
+	}
+
+	ad, err := FindAvailabilityDomain(ads, "us-ashburn-1-ad-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ad != "Uocm:US-ASHBURN-AD-2" {
+		t.Errorf("expected Uocm:US-ASHBURN-AD-2, but got %s", ad)
+	}
+
+	if _, err := FindAvailabilityDomain(ads, "us-ashburn-1-ad-3"); err == nil {
+		t.Errorf("expected an error for a missing availability domain")
+	}
+}
+
+func TestShapeArchitecture(t *testing.T) {
+	testCases := map[string]architectures.Architecture{
+		"VM.Standard.A1.Flex":    architectures.ArchitectureArm64,
+		"BM.Standard.A1.160":     architectures.ArchitectureArm64,
+		"VM.Standard.E4.Flex":    architectures.ArchitectureAmd64,
+		"VM.Standard2.1":         architectures.ArchitectureAmd64,
+		"VM.Standard.E2.1.Micro": architectures.ArchitectureAmd64,
+	}
+	for shape, expected := range testCases {
+		t.Run(shape, func(t *testing.T) {
+			if arch := ShapeArchitecture(shape); arch != expected {
+				t.Errorf("expected %s, but got %s", expected, arch)
+			}
+		})
+	}
+}
+
+func TestSafeTagKey(t *testing.T) {
+	if key := SafeTagKey("k8s.io/role/master"); key != "k8s_io_role_master" {
+		t.Errorf("unexpected tag key %q", key)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+// DynamicGroup is an OCI IAM dynamic group, which lets instances act as principals.
+// Dynamic groups always live in the root compartment of the tenancy.
+// +kops:fitask
+type DynamicGroup struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID           *string
+	Description  *string
+	MatchingRule *string
+	Tags         map[string]string
+}
+
+var _ fi.Task = &DynamicGroup{}
+var _ fi.CompareWithID = &DynamicGroup{}
+
+// CompareWithID returns the OCID of the dynamic group.
+func (g *DynamicGroup) CompareWithID() *string {
+	return g.ID
+}
+
+// Find discovers the dynamic group in the cloud provider.
+func (g *DynamicGroup) Find(c *fi.Context) (*DynamicGroup, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	l, err := cloud.DynamicGroup().List(context.TODO(), cloud.TenancyID())
+	if err != nil {
+		return nil, err
+	}
+	var found *identity.DynamicGroup
+	for i := range l {
+		if fi.StringValue(l[i].Name) == fi.StringValue(g.Name) {
+			found = &l[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	g.ID = found.Id
+
+	return &DynamicGroup{
+		Name:         g.Name,
+		Lifecycle:    g.Lifecycle,
+		ID:           found.Id,
+		Description:  found.Description,
+		MatchingRule: found.MatchingRule,
+		Tags:         found.FreeformTags,
+	}, nil
+}
+
+// Run implements fi.Task.Run.
+func (g *DynamicGroup) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(g, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*DynamicGroup) CheckChanges(a, e, changes *DynamicGroup) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Description == nil {
+			return fi.RequiredField("Description")
+		}
+		if e.MatchingRule == nil {
+			return fi.RequiredField("MatchingRule")
+		}
+	}
+	return nil
+}
+
+// RenderOCI creates or updates a dynamic group.
+func (*DynamicGroup) RenderOCI(t *oci.OCIAPITarget, a, e, changes *DynamicGroup) error {
+	ctx := context.TODO()
+	if a == nil {
+		klog.Infof("Creating a new Dynamic Group with name: %s", fi.StringValue(e.Name))
+		dg, err := t.Cloud.DynamicGroup().Create(ctx, identity.CreateDynamicGroupDetails{
+			CompartmentId: fi.String(t.Cloud.TenancyID()),
+			Name:          e.Name,
+			Description:   e.Description,
+			MatchingRule:  e.MatchingRule,
+			FreeformTags:  e.Tags,
+		})
+		if err != nil {
+			return err
+		}
+		e.ID = dg.Id
+		return nil
+	}
+
+	klog.Infof("Updating a Dynamic Group with name: %s", fi.StringValue(e.Name))
+	return t.Cloud.DynamicGroup().Update(ctx, fi.StringValue(a.ID), identity.UpdateDynamicGroupDetails{
+		Description:  e.Description,
+		MatchingRule: e.MatchingRule,
+		FreeformTags: e.Tags,
+	})
+}
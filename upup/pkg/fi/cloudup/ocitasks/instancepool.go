@@ -0,0 +1,416 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+const (
+	metadataSSHAuthorizedKeys = "ssh_authorized_keys"
+	metadataUserData          = "user_data"
+)
+
+// InstancePool is an OCI Instance Pool together with its Instance Configuration.
+// Instance configurations are immutable, so a new one is created whenever the
+// launch details change, and the previous one is deleted.
+// +kops:fitask
+type InstancePool struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID *string
+	// InstanceConfigurationID is the OCID of the instance configuration currently used by the pool.
+	InstanceConfigurationID *string
+
+	Subnet *Subnet
+	// Zones are the kops zones the instances are spread across, of the form <region>-ad-<n>.
+	Zones []string
+	Size  *int
+	Shape *string
+	// OCPUs and MemoryInGBs configure flexible shapes.
+	OCPUs       *float32
+	MemoryInGBs *float32
+	// Image is the OCID or the display name of the image.
+	Image            *string
+	RootVolumeSizeGB *int64
+	AssignPublicIP   *bool
+	SSHPublicKey     *string
+	UserData         fi.Resource
+	// LoadBalancer is the API load balancer the instances are registered with.
+	LoadBalancer *NetworkLoadBalancer
+	Tags         map[string]string
+	// DefinedTags are the defined tags of the instances, by namespace and key.
+	DefinedTags map[string]map[string]string
+}
+
+var _ fi.Task = &InstancePool{}
+var _ fi.CompareWithID = &InstancePool{}
+
+// CompareWithID returns the OCID of the instance pool.
+func (p *InstancePool) CompareWithID() *string {
+	return p.ID
+}
+
+// Find discovers the instance pool in the cloud provider.
+func (p *InstancePool) Find(c *fi.Context) (*InstancePool, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	ctx := context.TODO()
+
+	// Resolve the image so that it can be compared with the image of the instance configuration.
+	if p.Image != nil {
+		image, err := resolveImage(ctx, cloud, fi.StringValue(p.Image))
+		if err != nil {
+			return nil, err
+		}
+		p.Image = fi.String(image)
+	}
+
+	l, err := cloud.InstancePool().List(ctx, cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+	var summary *core.InstancePoolSummary
+	for i := range l {
+		if fi.StringValue(l[i].DisplayName) == fi.StringValue(p.Name) {
+			summary = &l[i]
+			break
+		}
+	}
+	if summary == nil {
+		return nil, nil
+	}
+
+	pool, err := cloud.InstancePool().Get(ctx, fi.StringValue(summary.Id))
+	if err != nil {
+		return nil, fmt.Errorf("error getting instance pool %q: %v", fi.StringValue(p.Name), err)
+	}
+	config, err := cloud.InstanceConfiguration().Get(ctx, fi.StringValue(pool.InstanceConfigurationId))
+	if err != nil {
+		return nil, fmt.Errorf("error getting instance configuration of pool %q: %v", fi.StringValue(p.Name), err)
+	}
+
+	p.ID = pool.Id
+
+	actual := &InstancePool{
+		Name:                    p.Name,
+		Lifecycle:               p.Lifecycle,
+		ID:                      pool.Id,
+		InstanceConfigurationID: pool.InstanceConfigurationId,
+		Size:                    pool.Size,
+		Tags:                    pool.FreeformTags,
+	}
+
+	var ads []string
+	for _, placement := range pool.PlacementConfigurations {
+		ads = append(ads, fi.StringValue(placement.AvailabilityDomain))
+		actual.Subnet = &Subnet{ID: placement.PrimarySubnetId}
+	}
+	actual.Zones = ads
+	if len(p.Zones) > 0 {
+		expected, err := p.availabilityDomains(ctx, cloud)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(ads)
+		sort.Strings(expected)
+		if strings.Join(ads, ",") == strings.Join(expected, ",") {
+			actual.Zones = p.Zones
+		}
+	}
+
+	for _, lb := range pool.LoadBalancers {
+		if fi.StringValue(lb.BackendSetName) == APIBackendSetName {
+			actual.LoadBalancer = &NetworkLoadBalancer{ID: lb.LoadBalancerId}
+		}
+	}
+
+	details, ok := config.InstanceDetails.(core.ComputeInstanceDetails)
+	if !ok || details.LaunchDetails == nil {
+		return nil, fmt.Errorf("unexpected instance details in instance configuration of pool %q", fi.StringValue(p.Name))
+	}
+	launch := details.LaunchDetails
+	actual.Shape = launch.Shape
+	if launch.ShapeConfig != nil {
+		actual.OCPUs = launch.ShapeConfig.Ocpus
+		actual.MemoryInGBs = launch.ShapeConfig.MemoryInGBs
+	}
+	if source, ok := launch.SourceDetails.(core.InstanceConfigurationInstanceSourceViaImageDetails); ok {
+		actual.Image = source.ImageId
+		actual.RootVolumeSizeGB = source.BootVolumeSizeInGBs
+	}
+	if launch.CreateVnicDetails != nil {
+		actual.AssignPublicIP = fi.Bool(fi.BoolValue(launch.CreateVnicDetails.AssignPublicIp))
+	}
+	if key, ok := launch.Metadata[metadataSSHAuthorizedKeys]; ok {
+		actual.SSHPublicKey = fi.String(key)
+	}
+	actual.DefinedTags = matchingDefinedTags(launch.DefinedTags, p.DefinedTags)
+	if userData, ok := launch.Metadata[metadataUserData]; ok {
+		b, err := base64.StdEncoding.DecodeString(userData)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding user data of pool %q: %v", fi.StringValue(p.Name), err)
+		}
+		actual.UserData = fi.NewBytesResource(b)
+	}
+
+	return actual, nil
+}
+
+// Run implements fi.Task.Run.
+func (p *InstancePool) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(p, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*InstancePool) CheckChanges(a, e, changes *InstancePool) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Subnet == nil {
+			return fi.RequiredField("Subnet")
+		}
+		if len(e.Zones) == 0 {
+			return fi.RequiredField("Zones")
+		}
+		if e.Shape == nil {
+			return fi.RequiredField("Shape")
+		}
+		if e.Image == nil {
+			return fi.RequiredField("Image")
+		}
+		return nil
+	}
+
+	if changes.Zones != nil {
+		return fi.CannotChangeField("Zones")
+	}
+	if changes.Subnet != nil {
+		return fi.CannotChangeField("Subnet")
+	}
+	if changes.LoadBalancer != nil && a.LoadBalancer != nil {
+		return fi.CannotChangeField("LoadBalancer")
+	}
+	return nil
+}
+
+// RenderOCI creates or updates an instance pool.
+func (*InstancePool) RenderOCI(t *oci.OCIAPITarget, a, e, changes *InstancePool) error {
+	ctx := context.TODO()
+	cloud := t.Cloud
+
+	if a == nil {
+		klog.Infof("Creating a new Instance Pool with name: %s", fi.StringValue(e.Name))
+		configID, err := e.createInstanceConfiguration(ctx, cloud)
+		if err != nil {
+			return err
+		}
+
+		ads, err := e.availabilityDomains(ctx, cloud)
+		if err != nil {
+			return err
+		}
+		var placements []core.CreateInstancePoolPlacementConfigurationDetails
+		for _, ad := range ads {
+			placements = append(placements, core.CreateInstancePoolPlacementConfigurationDetails{
+				AvailabilityDomain: fi.String(ad),
+				PrimarySubnetId:    e.Subnet.ID,
+			})
+		}
+
+		details := core.CreateInstancePoolDetails{
+			CompartmentId:           fi.String(cloud.CompartmentID()),
+			DisplayName:             e.Name,
+			InstanceConfigurationId: fi.String(configID),
+			PlacementConfigurations: placements,
+			Size:                    e.Size,
+			FreeformTags:            e.Tags,
+		}
+		if e.LoadBalancer != nil {
+			details.LoadBalancers = []core.AttachLoadBalancerDetails{e.loadBalancerAttachment()}
+		}
+		pool, err := cloud.InstancePool().Create(ctx, details)
+		if err != nil {
+			return err
+		}
+		e.ID = pool.Id
+		e.InstanceConfigurationID = fi.String(configID)
+		return nil
+	}
+
+	klog.Infof("Updating an Instance Pool with name: %s", fi.StringValue(e.Name))
+	update := core.UpdateInstancePoolDetails{
+		Size:         e.Size,
+		FreeformTags: e.Tags,
+	}
+
+	launchDetailsChanged := changes.Shape != nil || changes.OCPUs != nil || changes.MemoryInGBs != nil ||
+		changes.Image != nil || changes.RootVolumeSizeGB != nil || changes.AssignPublicIP != nil ||
+		changes.SSHPublicKey != nil || changes.UserData != nil || changes.DefinedTags != nil
+	if launchDetailsChanged {
+		configID, err := e.createInstanceConfiguration(ctx, cloud)
+		if err != nil {
+			return err
+		}
+		update.InstanceConfigurationId = fi.String(configID)
+	}
+	if err := cloud.InstancePool().Update(ctx, fi.StringValue(a.ID), update); err != nil {
+		return err
+	}
+
+	if launchDetailsChanged && a.InstanceConfigurationID != nil {
+		// Existing instances keep running; only new instances use the new configuration.
+		if err := cloud.InstanceConfiguration().Delete(ctx, fi.StringValue(a.InstanceConfigurationID)); err != nil {
+			klog.Warningf("error deleting previous instance configuration %q: %v", fi.StringValue(a.InstanceConfigurationID), err)
+		}
+	}
+
+	if changes.LoadBalancer != nil {
+		if err := cloud.InstancePool().AttachLoadBalancer(ctx, fi.StringValue(a.ID), e.loadBalancerAttachment()); err != nil {
+			return fmt.Errorf("error attaching load balancer to instance pool %q: %v", fi.StringValue(e.Name), err)
+		}
+	}
+	return nil
+}
+
+func (p *InstancePool) loadBalancerAttachment() core.AttachLoadBalancerDetails {
+	return core.AttachLoadBalancerDetails{
+		LoadBalancerId: p.LoadBalancer.ID,
+		BackendSetName: fi.String(APIBackendSetName),
+		Port:           fi.Int(APIPort),
+		VnicSelection:  fi.String("PrimaryVnic"),
+	}
+}
+
+func (p *InstancePool) createInstanceConfiguration(ctx context.Context, cloud oci.OCICloud) (string, error) {
+	image, err := resolveImage(ctx, cloud, fi.StringValue(p.Image))
+	if err != nil {
+		return "", err
+	}
+
+	metadata := map[string]string{}
+	if p.SSHPublicKey != nil {
+		metadata[metadataSSHAuthorizedKeys] = fi.StringValue(p.SSHPublicKey)
+	}
+	if p.UserData != nil {
+		d, err := fi.ResourceAsBytes(p.UserData)
+		if err != nil {
+			return "", fmt.Errorf("error rendering UserData: %v", err)
+		}
+		metadata[metadataUserData] = base64.StdEncoding.EncodeToString(d)
+	}
+
+	launch := &core.InstanceConfigurationLaunchInstanceDetails{
+		CompartmentId: fi.String(cloud.CompartmentID()),
+		CreateVnicDetails: &core.InstanceConfigurationCreateVnicDetails{
+			AssignPublicIp: p.AssignPublicIP,
+			SubnetId:       p.Subnet.ID,
+		},
+		Metadata: metadata,
+		Shape:    p.Shape,
+		SourceDetails: core.InstanceConfigurationInstanceSourceViaImageDetails{
+			ImageId:             fi.String(image),
+			BootVolumeSizeInGBs: p.RootVolumeSizeGB,
+		},
+		FreeformTags: p.Tags,
+	}
+	if len(p.DefinedTags) != 0 {
+		launch.DefinedTags = make(map[string]map[string]interface{})
+		for namespace, tags := range p.DefinedTags {
+			launch.DefinedTags[namespace] = make(map[string]interface{})
+			for k, v := range tags {
+				launch.DefinedTags[namespace][k] = v
+			}
+		}
+	}
+	if p.OCPUs != nil || p.MemoryInGBs != nil {
+		launch.ShapeConfig = &core.InstanceConfigurationLaunchInstanceShapeConfigDetails{
+			Ocpus:       p.OCPUs,
+			MemoryInGBs: p.MemoryInGBs,
+		}
+	}
+
+	config, err := cloud.InstanceConfiguration().Create(ctx, core.CreateInstanceConfigurationDetails{
+		CompartmentId:   fi.String(cloud.CompartmentID()),
+		DisplayName:     p.Name,
+		InstanceDetails: core.ComputeInstanceDetails{LaunchDetails: launch},
+		FreeformTags:    p.Tags,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating instance configuration for %q: %v", fi.StringValue(p.Name), err)
+	}
+	return fi.StringValue(config.Id), nil
+}
+
+// matchingDefinedTags returns the defined tags of the expected namespaces and keys.
+// Other defined tags, such as the default tags of the tenancy, are ignored.
+func matchingDefinedTags(actual map[string]map[string]interface{}, expected map[string]map[string]string) map[string]map[string]string {
+	if len(expected) == 0 {
+		return nil
+	}
+	result := make(map[string]map[string]string)
+	for namespace, tags := range expected {
+		for k := range tags {
+			v, found := actual[namespace][k]
+			if !found {
+				continue
+			}
+			if result[namespace] == nil {
+				result[namespace] = make(map[string]string)
+			}
+			result[namespace][k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return result
+}
+
+func (p *InstancePool) availabilityDomains(ctx context.Context, cloud oci.OCICloud) ([]string, error) {
+	var ads []string
+	for _, zone := range p.Zones {
+		ad, err := findAvailabilityDomain(ctx, cloud, zone)
+		if err != nil {
+			return nil, err
+		}
+		ads = append(ads, ad)
+	}
+	return ads, nil
+}
+
+// resolveImage returns the OCID of an image given either its OCID or its display name.
+func resolveImage(ctx context.Context, cloud oci.OCICloud, image string) (string, error) {
+	if strings.HasPrefix(image, "ocid1.image.") {
+		return image, nil
+	}
+	images, err := cloud.Image().List(ctx, cloud.CompartmentID(), image)
+	if err != nil {
+		return "", fmt.Errorf("error listing images: %v", err)
+	}
+	if len(images) == 0 {
+		return "", fmt.Errorf("unable to find image %q", image)
+	}
+	return fi.StringValue(images[0].Id), nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+func newTestInstancePool(image, userData string) *InstancePool {
+	return &InstancePool{
+		Name:             fi.String("nodes.test-cluster"),
+		Lifecycle:        fi.LifecycleSync,
+		Subnet:           &Subnet{Name: fi.String("subnet"), ID: fi.String("ocid1.subnet.oc1..test")},
+		Zones:            []string{"us-ashburn-1-ad-1", "us-ashburn-1-ad-2"},
+		Size:             fi.Int(2),
+		Shape:            fi.String("VM.Standard.E4.Flex"),
+		OCPUs:            fi.Float32(2),
+		MemoryInGBs:      fi.Float32(16),
+		Image:            fi.String(image),
+		RootVolumeSizeGB: fi.Int64(128),
+		AssignPublicIP:   fi.Bool(true),
+		SSHPublicKey:     fi.String("ssh-rsa AAAA"),
+		UserData:         fi.NewStringResource(userData),
+		Tags:             map[string]string{oci.TagClusterName: "test-cluster"},
+		DefinedTags:      map[string]map[string]string{"kops": {oci.DefinedTagKeyCluster: "test-cluster"}},
+	}
+}
+
+func TestInstancePoolRun(t *testing.T) {
+	cloud := NewMockOCICloud("us-ashburn-1")
+	cloud.ImagesClient.Images = []core.Image{
+		{Id: fi.String("ocid1.image.oc1..ubuntu"), DisplayName: fi.String("Canonical-Ubuntu-20.04")},
+	}
+	ctx := &fi.Context{
+		Cloud:         cloud,
+		Target:        oci.NewOCIAPITarget(cloud),
+		CheckExisting: true,
+	}
+
+	pool := newTestInstancePool("Canonical-Ubuntu-20.04", "#!/bin/bash")
+	if err := pool.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	created := cloud.InstancePoolsClient.Pools[*pool.ID]
+	if a, e := len(created.PlacementConfigurations), 2; a != e {
+		t.Fatalf("unexpected number of placement configurations: expected %d, but got %d", e, a)
+	}
+	if a, e := fi.StringValue(created.PlacementConfigurations[1].AvailabilityDomain), "Test:us-ashburn-1-AD-2"; a != e {
+		t.Errorf("unexpected availability domain: expected %s, but got %s", e, a)
+	}
+	configID := fi.StringValue(created.InstanceConfigurationId)
+	config := cloud.InstanceConfigurationsClient.Configs[configID]
+	launch := config.InstanceDetails.(core.ComputeInstanceDetails).LaunchDetails
+	source := launch.SourceDetails.(core.InstanceConfigurationInstanceSourceViaImageDetails)
+	if a, e := fi.StringValue(source.ImageId), "ocid1.image.oc1..ubuntu"; a != e {
+		t.Errorf("unexpected image: expected %s, but got %s", e, a)
+	}
+	if a, e := launch.DefinedTags["kops"][oci.DefinedTagKeyCluster], "test-cluster"; a != e {
+		t.Errorf("unexpected defined tag: expected %s, but got %v", e, a)
+	}
+
+	// Running the same task again changes nothing.
+	same := newTestInstancePool("Canonical-Ubuntu-20.04", "#!/bin/bash")
+	if err := same.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := fi.StringValue(cloud.InstancePoolsClient.Pools[*pool.ID].InstanceConfigurationId), configID; a != e {
+		t.Errorf("unexpected new instance configuration %s", a)
+	}
+
+	// Changing the user data replaces the instance configuration.
+	changed := newTestInstancePool("ocid1.image.oc1..ubuntu", "#!/bin/bash\necho changed")
+	if err := changed.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	newConfigID := fi.StringValue(cloud.InstancePoolsClient.Pools[*pool.ID].InstanceConfigurationId)
+	if newConfigID == configID {
+		t.Errorf("expected a new instance configuration")
+	}
+	if _, ok := cloud.InstanceConfigurationsClient.Configs[configID]; ok {
+		t.Errorf("expected the previous instance configuration to be deleted")
+	}
+	if a, e := len(cloud.InstanceConfigurationsClient.Configs), 1; a != e {
+		t.Errorf("unexpected number of instance configurations: expected %d, but got %d", e, a)
+	}
+}
+
+func TestInstancePoolAttachLoadBalancer(t *testing.T) {
+	cloud := NewMockOCICloud("us-ashburn-1")
+	ctx := &fi.Context{
+		Cloud:         cloud,
+		Target:        oci.NewOCIAPITarget(cloud),
+		CheckExisting: true,
+	}
+
+	pool := newTestInstancePool("ocid1.image.oc1..ubuntu", "")
+	if err := pool.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	withLB := newTestInstancePool("ocid1.image.oc1..ubuntu", "")
+	withLB.LoadBalancer = &NetworkLoadBalancer{Name: fi.String("api"), ID: fi.String("ocid1.networkloadbalancer.oc1..test")}
+	if err := withLB.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	lbs := cloud.InstancePoolsClient.Pools[*pool.ID].LoadBalancers
+	if a, e := len(lbs), 1; a != e {
+		t.Fatalf("unexpected number of load balancers: expected %d, but got %d", e, a)
+	}
+	if a, e := fi.IntValue(lbs[0].Port), APIPort; a != e {
+		t.Errorf("unexpected port: expected %d, but got %d", e, a)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+// InternetGateway is an OCI Internet Gateway.
+// +kops:fitask
+type InternetGateway struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID   *string
+	VCN  *VCN
+	Tags map[string]string
+}
+
+var _ fi.Task = &InternetGateway{}
+var _ fi.CompareWithID = &InternetGateway{}
+
+// CompareWithID returns the OCID of the Internet Gateway.
+func (g *InternetGateway) CompareWithID() *string {
+	return g.ID
+}
+
+// Find discovers the Internet Gateway in the cloud provider.
+func (g *InternetGateway) Find(c *fi.Context) (*InternetGateway, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	l, err := cloud.InternetGateway().List(context.TODO(), cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+	var found *core.InternetGateway
+	for i := range l {
+		if fi.StringValue(l[i].DisplayName) == fi.StringValue(g.Name) {
+			found = &l[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	g.ID = found.Id
+
+	return &InternetGateway{
+		Name:      g.Name,
+		Lifecycle: g.Lifecycle,
+		ID:        found.Id,
+		VCN:       &VCN{ID: found.VcnId},
+		Tags:      found.FreeformTags,
+	}, nil
+}
+
+// Run implements fi.Task.Run.
+func (g *InternetGateway) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(g, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*InternetGateway) CheckChanges(a, e, changes *InternetGateway) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.VCN == nil {
+			return fi.RequiredField("VCN")
+		}
+		return nil
+	}
+
+	if changes.VCN != nil {
+		return fi.CannotChangeField("VCN")
+	}
+	return nil
+}
+
+// RenderOCI creates or updates an Internet Gateway.
+func (*InternetGateway) RenderOCI(t *oci.OCIAPITarget, a, e, changes *InternetGateway) error {
+	ctx := context.TODO()
+	if a == nil {
+		klog.Infof("Creating a new Internet Gateway with name: %s", fi.StringValue(e.Name))
+		ig, err := t.Cloud.InternetGateway().Create(ctx, core.CreateInternetGatewayDetails{
+			CompartmentId: fi.String(t.Cloud.CompartmentID()),
+			DisplayName:   e.Name,
+			VcnId:         e.VCN.ID,
+			IsEnabled:     fi.Bool(true),
+			FreeformTags:  e.Tags,
+		})
+		if err != nil {
+			return err
+		}
+		e.ID = ig.Id
+		return nil
+	}
+
+	// Only allow tags to be updated.
+	if changes.Tags == nil {
+		return nil
+	}
+	klog.Infof("Updating an Internet Gateway with name: %s", fi.StringValue(e.Name))
+	return t.Cloud.InternetGateway().Update(ctx, fi.StringValue(a.ID), core.UpdateInternetGatewayDetails{
+		FreeformTags: e.Tags,
+	})
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/networkloadbalancer"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+const (
+	// APIBackendSetName is the name of the backend set and listener for the Kubernetes API.
+	APIBackendSetName = "api"
+	// APIPort is the port the Kubernetes API is served on.
+	APIPort = 443
+)
+
+// NetworkLoadBalancer is an OCI Network Load Balancer for the Kubernetes API.
+// +kops:fitask
+type NetworkLoadBalancer struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID     *string
+	Subnet *Subnet
+	// Private is set when the load balancer only has a private IP.
+	Private *bool
+	Tags    map[string]string
+}
+
+var _ fi.Task = &NetworkLoadBalancer{}
+var _ fi.CompareWithID = &NetworkLoadBalancer{}
+
+// CompareWithID returns the OCID of the Network Load Balancer.
+func (lb *NetworkLoadBalancer) CompareWithID() *string {
+	return lb.ID
+}
+
+// Find discovers the Network Load Balancer in the cloud provider.
+func (lb *NetworkLoadBalancer) Find(c *fi.Context) (*NetworkLoadBalancer, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	l, err := cloud.NetworkLoadBalancer().List(context.TODO(), cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+	var found *networkloadbalancer.NetworkLoadBalancerSummary
+	for i := range l {
+		if fi.StringValue(l[i].DisplayName) == fi.StringValue(lb.Name) {
+			found = &l[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	lb.ID = found.Id
+
+	return &NetworkLoadBalancer{
+		Name:      lb.Name,
+		Lifecycle: lb.Lifecycle,
+		ID:        found.Id,
+		Subnet:    &Subnet{ID: found.SubnetId},
+		Private:   fi.Bool(fi.BoolValue(found.IsPrivate)),
+		Tags:      found.FreeformTags,
+	}, nil
+}
+
+// Run implements fi.Task.Run.
+func (lb *NetworkLoadBalancer) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(lb, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*NetworkLoadBalancer) CheckChanges(a, e, changes *NetworkLoadBalancer) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Subnet == nil {
+			return fi.RequiredField("Subnet")
+		}
+		return nil
+	}
+
+	if changes.Subnet != nil {
+		return fi.CannotChangeField("Subnet")
+	}
+	if changes.Private != nil {
+		return fi.CannotChangeField("Private")
+	}
+	return nil
+}
+
+// RenderOCI creates or updates a Network Load Balancer.
+func (*NetworkLoadBalancer) RenderOCI(t *oci.OCIAPITarget, a, e, changes *NetworkLoadBalancer) error {
+	ctx := context.TODO()
+	if a == nil {
+		klog.Infof("Creating a new Network Load Balancer with name: %s", fi.StringValue(e.Name))
+		nlb, err := t.Cloud.NetworkLoadBalancer().Create(ctx, networkloadbalancer.CreateNetworkLoadBalancerDetails{
+			CompartmentId: fi.String(t.Cloud.CompartmentID()),
+			DisplayName:   e.Name,
+			SubnetId:      e.Subnet.ID,
+			IsPrivate:     e.Private,
+			Listeners: map[string]networkloadbalancer.ListenerDetails{
+				APIBackendSetName: {
+					Name:                  fi.String(APIBackendSetName),
+					DefaultBackendSetName: fi.String(APIBackendSetName),
+					Port:                  fi.Int(APIPort),
+					Protocol:              networkloadbalancer.ListenerProtocolsTcp,
+				},
+			},
+			BackendSets: map[string]networkloadbalancer.BackendSetDetails{
+				APIBackendSetName: {
+					Policy: networkloadbalancer.NetworkLoadBalancingPolicyFiveTuple,
+					HealthChecker: &networkloadbalancer.HealthChecker{
+						Protocol: networkloadbalancer.HealthCheckProtocolsTcp,
+						Port:     fi.Int(APIPort),
+					},
+					Backends: []networkloadbalancer.Backend{},
+				},
+			},
+			FreeformTags: e.Tags,
+		})
+		if err != nil {
+			return err
+		}
+		e.ID = nlb.Id
+		return nil
+	}
+
+	// Only allow tags to be updated.
+	if changes.Tags == nil {
+		return nil
+	}
+	klog.Infof("Updating a Network Load Balancer with name: %s", fi.StringValue(e.Name))
+	return t.Cloud.NetworkLoadBalancer().Update(ctx, fi.StringValue(a.ID), networkloadbalancer.UpdateNetworkLoadBalancerDetails{
+		FreeformTags: e.Tags,
+	})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+// Policy is an OCI IAM policy in the compartment of the cluster.
+// +kops:fitask
+type Policy struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID          *string
+	Description *string
+	Statements  []string
+	// DynamicGroup is the dynamic group the statements refer to.
+	DynamicGroup *DynamicGroup
+	Tags         map[string]string
+}
+
+var _ fi.Task = &Policy{}
+var _ fi.CompareWithID = &Policy{}
+
+// CompareWithID returns the OCID of the policy.
+func (p *Policy) CompareWithID() *string {
+	return p.ID
+}
+
+// Find discovers the policy in the cloud provider.
+func (p *Policy) Find(c *fi.Context) (*Policy, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	l, err := cloud.Policy().List(context.TODO(), cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+	var found *identity.Policy
+	for i := range l {
+		if fi.StringValue(l[i].Name) == fi.StringValue(p.Name) {
+			found = &l[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	p.ID = found.Id
+
+	return &Policy{
+		Name:        p.Name,
+		Lifecycle:   p.Lifecycle,
+		ID:          found.Id,
+		Description: found.Description,
+		Statements:  found.Statements,
+		// The statements refer to the dynamic group by name, which is not reported back.
+		DynamicGroup: p.DynamicGroup,
+		Tags:         found.FreeformTags,
+	}, nil
+}
+
+// Run implements fi.Task.Run.
+func (p *Policy) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(p, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*Policy) CheckChanges(a, e, changes *Policy) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Description == nil {
+			return fi.RequiredField("Description")
+		}
+		if len(e.Statements) == 0 {
+			return fi.RequiredField("Statements")
+		}
+	}
+	return nil
+}
+
+// RenderOCI creates or updates a policy.
+func (*Policy) RenderOCI(t *oci.OCIAPITarget, a, e, changes *Policy) error {
+	ctx := context.TODO()
+	if a == nil {
+		klog.Infof("Creating a new Policy with name: %s", fi.StringValue(e.Name))
+		policy, err := t.Cloud.Policy().Create(ctx, identity.CreatePolicyDetails{
+			CompartmentId: fi.String(t.Cloud.CompartmentID()),
+			Name:          e.Name,
+			Description:   e.Description,
+			Statements:    e.Statements,
+			FreeformTags:  e.Tags,
+		})
+		if err != nil {
+			return err
+		}
+		e.ID = policy.Id
+		return nil
+	}
+
+	klog.Infof("Updating a Policy with name: %s", fi.StringValue(e.Name))
+	return t.Cloud.Policy().Update(ctx, fi.StringValue(a.ID), identity.UpdatePolicyDetails{
+		Description:  e.Description,
+		Statements:   e.Statements,
+		FreeformTags: e.Tags,
+	})
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+// RouteTable is an OCI Route Table.
+// The route table sends all traffic that leaves the VCN through the Internet Gateway.
+// +kops:fitask
+type RouteTable struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID              *string
+	VCN             *VCN
+	InternetGateway *InternetGateway
+	Tags            map[string]string
+}
+
+var _ fi.Task = &RouteTable{}
+var _ fi.CompareWithID = &RouteTable{}
+
+// CompareWithID returns the OCID of the Route Table.
+func (r *RouteTable) CompareWithID() *string {
+	return r.ID
+}
+
+// Find discovers the Route Table in the cloud provider.
+func (r *RouteTable) Find(c *fi.Context) (*RouteTable, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	l, err := cloud.RouteTable().List(context.TODO(), cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+	var found *core.RouteTable
+	for i := range l {
+		if fi.StringValue(l[i].DisplayName) == fi.StringValue(r.Name) {
+			found = &l[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	r.ID = found.Id
+
+	actual := &RouteTable{
+		Name:      r.Name,
+		Lifecycle: r.Lifecycle,
+		ID:        found.Id,
+		VCN:       &VCN{ID: found.VcnId},
+		Tags:      found.FreeformTags,
+	}
+	for _, rule := range found.RouteRules {
+		if fi.StringValue(rule.Destination) == "0.0.0.0/0" {
+			actual.InternetGateway = &InternetGateway{ID: rule.NetworkEntityId}
+		}
+	}
+	return actual, nil
+}
+
+// Run implements fi.Task.Run.
+func (r *RouteTable) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(r, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*RouteTable) CheckChanges(a, e, changes *RouteTable) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.VCN == nil {
+			return fi.RequiredField("VCN")
+		}
+		return nil
+	}
+
+	if changes.VCN != nil {
+		return fi.CannotChangeField("VCN")
+	}
+	return nil
+}
+
+func (r *RouteTable) routeRules() []core.RouteRule {
+	rules := []core.RouteRule{}
+	if r.InternetGateway != nil {
+		rules = append(rules, core.RouteRule{
+			Destination:     fi.String("0.0.0.0/0"),
+			DestinationType: core.RouteRuleDestinationTypeCidrBlock,
+			NetworkEntityId: r.InternetGateway.ID,
+		})
+	}
+	return rules
+}
+
+// RenderOCI creates or updates a Route Table.
+func (*RouteTable) RenderOCI(t *oci.OCIAPITarget, a, e, changes *RouteTable) error {
+	ctx := context.TODO()
+	if a == nil {
+		klog.Infof("Creating a new Route Table with name: %s", fi.StringValue(e.Name))
+		rt, err := t.Cloud.RouteTable().Create(ctx, core.CreateRouteTableDetails{
+			CompartmentId: fi.String(t.Cloud.CompartmentID()),
+			DisplayName:   e.Name,
+			VcnId:         e.VCN.ID,
+			RouteRules:    e.routeRules(),
+			FreeformTags:  e.Tags,
+		})
+		if err != nil {
+			return err
+		}
+		e.ID = rt.Id
+		return nil
+	}
+
+	klog.Infof("Updating a Route Table with name: %s", fi.StringValue(e.Name))
+	return t.Cloud.RouteTable().Update(ctx, fi.StringValue(a.ID), core.UpdateRouteTableDetails{
+		RouteRules:   e.routeRules(),
+		FreeformTags: e.Tags,
+	})
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+const (
+	// ProtocolAll matches all IP protocols in a security rule.
+	ProtocolAll = "all"
+	// ProtocolTCP is the IANA protocol number of TCP.
+	ProtocolTCP = "6"
+	// ProtocolUDP is the IANA protocol number of UDP.
+	ProtocolUDP = "17"
+)
+
+// SecurityList is an OCI Security List.
+// All egress traffic is allowed; ingress traffic is restricted to the given rules.
+// +kops:fitask
+type SecurityList struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID           *string
+	VCN          *VCN
+	IngressRules []*SecurityListRule
+	Tags         map[string]string
+}
+
+// SecurityListRule is a stateful ingress rule of a Security List.
+type SecurityListRule struct {
+	// Source is the CIDR block the traffic originates from.
+	Source *string
+	// Protocol is "all" or the IANA protocol number.
+	Protocol *string
+	// FromPort and ToPort are the destination port range for TCP and UDP rules.
+	FromPort *int
+	ToPort   *int
+}
+
+var _ fi.HasDependencies = &SecurityListRule{}
+
+// GetDependencies returns a slice of tasks on which the tasks depends on.
+func (r *SecurityListRule) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	return nil
+}
+
+var _ fi.Task = &SecurityList{}
+var _ fi.CompareWithID = &SecurityList{}
+
+// CompareWithID returns the OCID of the Security List.
+func (s *SecurityList) CompareWithID() *string {
+	return s.ID
+}
+
+// Find discovers the Security List in the cloud provider.
+func (s *SecurityList) Find(c *fi.Context) (*SecurityList, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	l, err := cloud.SecurityList().List(context.TODO(), cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+	var found *core.SecurityList
+	for i := range l {
+		if fi.StringValue(l[i].DisplayName) == fi.StringValue(s.Name) {
+			found = &l[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	s.ID = found.Id
+
+	actual := &SecurityList{
+		Name:      s.Name,
+		Lifecycle: s.Lifecycle,
+		ID:        found.Id,
+		VCN:       &VCN{ID: found.VcnId},
+		Tags:      found.FreeformTags,
+	}
+	for _, r := range found.IngressSecurityRules {
+		rule := &SecurityListRule{
+			Source:   r.Source,
+			Protocol: r.Protocol,
+		}
+		var portRange *core.PortRange
+		if r.TcpOptions != nil {
+			portRange = r.TcpOptions.DestinationPortRange
+		} else if r.UdpOptions != nil {
+			portRange = r.UdpOptions.DestinationPortRange
+		}
+		if portRange != nil {
+			rule.FromPort = portRange.Min
+			rule.ToPort = portRange.Max
+		}
+		actual.IngressRules = append(actual.IngressRules, rule)
+	}
+	return actual, nil
+}
+
+// Run implements fi.Task.Run.
+func (s *SecurityList) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(s, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*SecurityList) CheckChanges(a, e, changes *SecurityList) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.VCN == nil {
+			return fi.RequiredField("VCN")
+		}
+		return nil
+	}
+
+	if changes.VCN != nil {
+		return fi.CannotChangeField("VCN")
+	}
+	return nil
+}
+
+func (s *SecurityList) ingressSecurityRules() []core.IngressSecurityRule {
+	rules := []core.IngressSecurityRule{}
+	for _, r := range s.IngressRules {
+		rule := core.IngressSecurityRule{
+			Source:     r.Source,
+			SourceType: core.IngressSecurityRuleSourceTypeCidrBlock,
+			Protocol:   r.Protocol,
+		}
+		if r.FromPort != nil {
+			portRange := &core.PortRange{
+				Min: r.FromPort,
+				Max: r.ToPort,
+			}
+			switch fi.StringValue(r.Protocol) {
+			case ProtocolTCP:
+				rule.TcpOptions = &core.TcpOptions{DestinationPortRange: portRange}
+			case ProtocolUDP:
+				rule.UdpOptions = &core.UdpOptions{DestinationPortRange: portRange}
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func egressSecurityRules() []core.EgressSecurityRule {
+	return []core.EgressSecurityRule{
+		{
+			Destination:     fi.String("0.0.0.0/0"),
+			DestinationType: core.EgressSecurityRuleDestinationTypeCidrBlock,
+			Protocol:        fi.String(ProtocolAll),
+		},
+	}
+}
+
+// RenderOCI creates or updates a Security List.
+func (*SecurityList) RenderOCI(t *oci.OCIAPITarget, a, e, changes *SecurityList) error {
+	ctx := context.TODO()
+	if a == nil {
+		klog.Infof("Creating a new Security List with name: %s", fi.StringValue(e.Name))
+		sl, err := t.Cloud.SecurityList().Create(ctx, core.CreateSecurityListDetails{
+			CompartmentId:        fi.String(t.Cloud.CompartmentID()),
+			DisplayName:          e.Name,
+			VcnId:                e.VCN.ID,
+			IngressSecurityRules: e.ingressSecurityRules(),
+			EgressSecurityRules:  egressSecurityRules(),
+			FreeformTags:         e.Tags,
+		})
+		if err != nil {
+			return err
+		}
+		e.ID = sl.Id
+		return nil
+	}
+
+	klog.Infof("Updating a Security List with name: %s", fi.StringValue(e.Name))
+	return t.Cloud.SecurityList().Update(ctx, fi.StringValue(a.ID), core.UpdateSecurityListDetails{
+		IngressSecurityRules: e.ingressSecurityRules(),
+		EgressSecurityRules:  egressSecurityRules(),
+		FreeformTags:         e.Tags,
+	})
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+func TestSecurityListRenderAndFind(t *testing.T) {
+	cloud := NewMockOCICloud("us-ashburn-1")
+	apiTarget := oci.NewOCIAPITarget(cloud)
+	vcn := &VCN{Name: fi.String("vcn"), ID: fi.String("ocid1.vcn.oc1..test")}
+
+	expected := &SecurityList{
+		Name: fi.String("sl"),
+		VCN:  vcn,
+		IngressRules: []*SecurityListRule{
+			{
+				Source:   fi.String("10.0.0.0/16"),
+				Protocol: fi.String(ProtocolAll),
+			},
+			{
+				Source:   fi.String("0.0.0.0/0"),
+				Protocol: fi.String(ProtocolTCP),
+				FromPort: fi.Int(443),
+				ToPort:   fi.Int(443),
+			},
+			{
+				Source:   fi.String("0.0.0.0/0"),
+				Protocol: fi.String(ProtocolUDP),
+				FromPort: fi.Int(30000),
+				ToPort:   fi.Int(32767),
+			},
+		},
+	}
+	sl := &SecurityList{}
+	if err := sl.RenderOCI(apiTarget, nil, expected, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	created := cloud.SecurityListsClient.SLs[*expected.ID]
+	if a, e := len(created.EgressSecurityRules), 1; a != e {
+		t.Fatalf("unexpected number of egress rules: expected %d, but got %d", e, a)
+	}
+	if created.IngressSecurityRules[1].TcpOptions == nil || created.IngressSecurityRules[2].UdpOptions == nil {
+		t.Errorf("expected port ranges to be set on TCP and UDP rules, but got %+v", created.IngressSecurityRules)
+	}
+
+	ctx := &fi.Context{
+		Cloud: cloud,
+	}
+	actual, err := (&SecurityList{Name: fi.String("sl")}).Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := actual.IngressRules, expected.IngressRules; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected ingress rules: expected %+v, but got %+v", e, a)
+	}
+	if a, e := fi.StringValue(actual.VCN.ID), fi.StringValue(vcn.ID); a != e {
+		t.Errorf("unexpected VCN: expected %s, but got %s", e, a)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+// Subnet is a regional OCI subnet.
+// +kops:fitask
+type Subnet struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID           *string
+	VCN          *VCN
+	CIDR         *string
+	RouteTable   *RouteTable
+	SecurityList *SecurityList
+	// Public is set when instances in the subnet may have public IPs.
+	Public *bool
+	Tags   map[string]string
+}
+
+var _ fi.Task = &Subnet{}
+var _ fi.CompareWithID = &Subnet{}
+
+// CompareWithID returns the OCID of the subnet.
+func (s *Subnet) CompareWithID() *string {
+	return s.ID
+}
+
+// Find discovers the subnet in the cloud provider.
+func (s *Subnet) Find(c *fi.Context) (*Subnet, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	l, err := cloud.Subnet().List(context.TODO(), cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+	var found *core.Subnet
+	for i := range l {
+		if fi.StringValue(l[i].DisplayName) == fi.StringValue(s.Name) {
+			found = &l[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	s.ID = found.Id
+
+	actual := &Subnet{
+		Name:       s.Name,
+		Lifecycle:  s.Lifecycle,
+		ID:         found.Id,
+		VCN:        &VCN{ID: found.VcnId},
+		CIDR:       found.CidrBlock,
+		RouteTable: &RouteTable{ID: found.RouteTableId},
+		Public:     fi.Bool(!fi.BoolValue(found.ProhibitPublicIpOnVnic)),
+		Tags:       found.FreeformTags,
+	}
+	if len(found.SecurityListIds) > 0 {
+		actual.SecurityList = &SecurityList{ID: fi.String(found.SecurityListIds[0])}
+	}
+	return actual, nil
+}
+
+// Run implements fi.Task.Run.
+func (s *Subnet) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(s, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*Subnet) CheckChanges(a, e, changes *Subnet) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.VCN == nil {
+			return fi.RequiredField("VCN")
+		}
+		if e.CIDR == nil {
+			return fi.RequiredField("CIDR")
+		}
+		return nil
+	}
+
+	if changes.VCN != nil {
+		return fi.CannotChangeField("VCN")
+	}
+	if changes.CIDR != nil {
+		return fi.CannotChangeField("CIDR")
+	}
+	if changes.Public != nil {
+		return fi.CannotChangeField("Public")
+	}
+	return nil
+}
+
+func (s *Subnet) securityListIDs() []string {
+	if s.SecurityList == nil {
+		return nil
+	}
+	return []string{fi.StringValue(s.SecurityList.ID)}
+}
+
+// RenderOCI creates or updates a subnet.
+func (*Subnet) RenderOCI(t *oci.OCIAPITarget, a, e, changes *Subnet) error {
+	ctx := context.TODO()
+	if a == nil {
+		klog.Infof("Creating a new Subnet with name: %s", fi.StringValue(e.Name))
+		details := core.CreateSubnetDetails{
+			CompartmentId:          fi.String(t.Cloud.CompartmentID()),
+			DisplayName:            e.Name,
+			VcnId:                  e.VCN.ID,
+			CidrBlock:              e.CIDR,
+			SecurityListIds:        e.securityListIDs(),
+			ProhibitPublicIpOnVnic: fi.Bool(!fi.BoolValue(e.Public)),
+			FreeformTags:           e.Tags,
+		}
+		if e.RouteTable != nil {
+			details.RouteTableId = e.RouteTable.ID
+		}
+		subnet, err := t.Cloud.Subnet().Create(ctx, details)
+		if err != nil {
+			return err
+		}
+		e.ID = subnet.Id
+		return nil
+	}
+
+	klog.Infof("Updating a Subnet with name: %s", fi.StringValue(e.Name))
+	details := core.UpdateSubnetDetails{
+		SecurityListIds: e.securityListIDs(),
+		FreeformTags:    e.Tags,
+	}
+	if e.RouteTable != nil {
+		details.RouteTableId = e.RouteTable.ID
+	}
+	return t.Cloud.Subnet().Update(ctx, fi.StringValue(a.ID), details)
+}
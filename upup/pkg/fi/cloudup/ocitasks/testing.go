@@ -0,0 +1,939 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/networkloadbalancer"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+const (
+	testCompartmentID = "ocid1.compartment.oc1..test"
+	testTenancyID     = "ocid1.tenancy.oc1..test"
+)
+
+func newOCID(kind string) *string {
+	return fi.String(fmt.Sprintf("ocid1.%s.oc1..%s", kind, uuid.New().String()))
+}
+
+// MockOCICloud is a mock implementation of OCICloud.
+type MockOCICloud struct {
+	RegionName                   string
+	VCNsClient                   *MockVCNsClient
+	SubnetsClient                *MockSubnetsClient
+	InternetGatewaysClient       *MockInternetGatewaysClient
+	RouteTablesClient            *MockRouteTablesClient
+	SecurityListsClient          *MockSecurityListsClient
+	InstancePoolsClient          *MockInstancePoolsClient
+	InstanceConfigurationsClient *MockInstanceConfigurationsClient
+	InstancesClient              *MockInstancesClient
+	ImagesClient                 *MockImagesClient
+	VolumesClient                *MockVolumesClient
+	NetworkLoadBalancersClient   *MockNetworkLoadBalancersClient
+	DynamicGroupsClient          *MockDynamicGroupsClient
+	PoliciesClient               *MockPoliciesClient
+	AvailabilityDomainsClient    *MockAvailabilityDomainsClient
+}
+
+var _ oci.OCICloud = &MockOCICloud{}
+
+// NewMockOCICloud returns a new MockOCICloud.
+func NewMockOCICloud(region string) *MockOCICloud {
+	return &MockOCICloud{
+		RegionName:                   region,
+		VCNsClient:                   &MockVCNsClient{VCNs: map[string]core.Vcn{}},
+		SubnetsClient:                &MockSubnetsClient{Subnets: map[string]core.Subnet{}},
+		InternetGatewaysClient:       &MockInternetGatewaysClient{IGs: map[string]core.InternetGateway{}},
+		RouteTablesClient:            &MockRouteTablesClient{RTs: map[string]core.RouteTable{}},
+		SecurityListsClient:          &MockSecurityListsClient{SLs: map[string]core.SecurityList{}},
+		InstancePoolsClient:          &MockInstancePoolsClient{Pools: map[string]core.InstancePool{}},
+		InstanceConfigurationsClient: &MockInstanceConfigurationsClient{Configs: map[string]core.InstanceConfiguration{}},
+		InstancesClient:              &MockInstancesClient{},
+		ImagesClient:                 &MockImagesClient{},
+		VolumesClient:                &MockVolumesClient{Volumes: map[string]core.Volume{}},
+		NetworkLoadBalancersClient:   &MockNetworkLoadBalancersClient{NLBs: map[string]networkloadbalancer.NetworkLoadBalancer{}},
+		DynamicGroupsClient:          &MockDynamicGroupsClient{DGs: map[string]identity.DynamicGroup{}},
+		PoliciesClient:               &MockPoliciesClient{Policies: map[string]identity.Policy{}},
+		AvailabilityDomainsClient: &MockAvailabilityDomainsClient{
+			ADs: []identity.AvailabilityDomain{
+				{Name: fi.String("Test:" + region + "-AD-1")},
+				{Name: fi.String("Test:" + region + "-AD-2")},
+				{Name: fi.String("Test:" + region + "-AD-3")},
+			},
+		},
+	}
+}
+
+// Region returns the region.
+func (c *MockOCICloud) Region() string {
+	return c.RegionName
+}
+
+// ProviderID returns the provider ID.
+func (c *MockOCICloud) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderOCI
+}
+
+// DNS returns the DNS provider.
+func (c *MockOCICloud) DNS() (dnsprovider.Interface, error) {
+	return nil, errors.New("DNS not implemented on ociCloud")
+}
+
+// FindVPCInfo returns the VPCInfo.
+func (c *MockOCICloud) FindVPCInfo(id string) (*fi.VPCInfo, error) {
+	return nil, errors.New("FindVPCInfo not implemented on ociCloud")
+}
+
+// DeleteInstance deletes the instance.
+func (c *MockOCICloud) DeleteInstance(i *cloudinstances.CloudInstance) error {
+	return errors.New("DeleteInstance not implemented on ociCloud")
+}
+
+// DeleteGroup deletes the group.
+func (c *MockOCICloud) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
+	return errors.New("DeleteGroup not implemented on ociCloud")
+}
+
+// DetachInstance detaches the instance.
+func (c *MockOCICloud) DetachInstance(i *cloudinstances.CloudInstance) error {
+	return errors.New("DetachInstance not implemented on ociCloud")
+}
+
+// GetCloudGroups returns cloud instance groups.
+func (c *MockOCICloud) GetCloudGroups(
+	cluster *kops.Cluster,
+	instancegroups []*kops.InstanceGroup,
+	warnUnmatched bool,
+	nodes []v1.Node,
+) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	return nil, errors.New("GetCloudGroups not implemented on ociCloud")
+}
+
+// FindClusterStatus discovers the status of the cluster, by looking for the tagged etcd volumes
+func (c *MockOCICloud) FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error) {
+	return &kops.ClusterStatus{}, nil
+}
+
+// GetApiIngressStatus returns the status of API ingress.
+func (c *MockOCICloud) GetApiIngressStatus(cluster *kops.Cluster) ([]fi.ApiIngressStatus, error) {
+	return oci.FindAPIIngressStatus(c, cluster)
+}
+
+// CompartmentID returns the compartment ID.
+func (c *MockOCICloud) CompartmentID() string {
+	return testCompartmentID
+}
+
+// TenancyID returns the tenancy ID.
+func (c *MockOCICloud) TenancyID() string {
+	return testTenancyID
+}
+
+// Tags returns the cluster tags.
+func (c *MockOCICloud) Tags() map[string]string {
+	return map[string]string{oci.TagClusterName: "test-cluster"}
+}
+
+// VCN returns the VCN client.
+func (c *MockOCICloud) VCN() oci.VCNsClient {
+	return c.VCNsClient
+}
+
+// Subnet returns the subnet client.
+func (c *MockOCICloud) Subnet() oci.SubnetsClient {
+	return c.SubnetsClient
+}
+
+// InternetGateway returns the internet gateway client.
+func (c *MockOCICloud) InternetGateway() oci.InternetGatewaysClient {
+	return c.InternetGatewaysClient
+}
+
+// RouteTable returns the route table client.
+func (c *MockOCICloud) RouteTable() oci.RouteTablesClient {
+	return c.RouteTablesClient
+}
+
+// SecurityList returns the security list client.
+func (c *MockOCICloud) SecurityList() oci.SecurityListsClient {
+	return c.SecurityListsClient
+}
+
+// InstancePool returns the instance pool client.
+func (c *MockOCICloud) InstancePool() oci.InstancePoolsClient {
+	return c.InstancePoolsClient
+}
+
+// InstanceConfiguration returns the instance configuration client.
+func (c *MockOCICloud) InstanceConfiguration() oci.InstanceConfigurationsClient {
+	return c.InstanceConfigurationsClient
+}
+
+// Instance returns the instance client.
+func (c *MockOCICloud) Instance() oci.InstancesClient {
+	return c.InstancesClient
+}
+
+// Image returns the image client.
+func (c *MockOCICloud) Image() oci.ImagesClient {
+	return c.ImagesClient
+}
+
+// Volume returns the volume client.
+func (c *MockOCICloud) Volume() oci.VolumesClient {
+	return c.VolumesClient
+}
+
+// NetworkLoadBalancer returns the network load balancer client.
+func (c *MockOCICloud) NetworkLoadBalancer() oci.NetworkLoadBalancersClient {
+	return c.NetworkLoadBalancersClient
+}
+
+// DynamicGroup returns the dynamic group client.
+func (c *MockOCICloud) DynamicGroup() oci.DynamicGroupsClient {
+	return c.DynamicGroupsClient
+}
+
+// Policy returns the policy client.
+func (c *MockOCICloud) Policy() oci.PoliciesClient {
+	return c.PoliciesClient
+}
+
+// AvailabilityDomain returns the availability domain client.
+func (c *MockOCICloud) AvailabilityDomain() oci.AvailabilityDomainsClient {
+	return c.AvailabilityDomainsClient
+}
+
+// MockVCNsClient is a mock implementation of VCNsClient.
+type MockVCNsClient struct {
+	VCNs map[string]core.Vcn
+}
+
+var _ oci.VCNsClient = &MockVCNsClient{}
+
+// Create creates a new VCN.
+func (c *MockVCNsClient) Create(ctx context.Context, details core.CreateVcnDetails) (*core.Vcn, error) {
+	vcn := core.Vcn{
+		Id:             newOCID("vcn"),
+		CompartmentId:  details.CompartmentId,
+		DisplayName:    details.DisplayName,
+		CidrBlock:      details.CidrBlock,
+		DnsLabel:       details.DnsLabel,
+		FreeformTags:   details.FreeformTags,
+		LifecycleState: core.VcnLifecycleStateAvailable,
+	}
+	c.VCNs[*vcn.Id] = vcn
+	return &vcn, nil
+}
+
+// Update updates a VCN.
+func (c *MockVCNsClient) Update(ctx context.Context, id string, details core.UpdateVcnDetails) error {
+	vcn, ok := c.VCNs[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	vcn.FreeformTags = details.FreeformTags
+	c.VCNs[id] = vcn
+	return nil
+}
+
+// List returns a slice of VCNs.
+func (c *MockVCNsClient) List(ctx context.Context, compartmentID string) ([]core.Vcn, error) {
+	var l []core.Vcn
+	for _, v := range c.VCNs {
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified VCN.
+func (c *MockVCNsClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.VCNs[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.VCNs, id)
+	return nil
+}
+
+// MockSubnetsClient is a mock implementation of SubnetsClient.
+type MockSubnetsClient struct {
+	Subnets map[string]core.Subnet
+}
+
+var _ oci.SubnetsClient = &MockSubnetsClient{}
+
+// Create creates a new subnet.
+func (c *MockSubnetsClient) Create(ctx context.Context, details core.CreateSubnetDetails) (*core.Subnet, error) {
+	subnet := core.Subnet{
+		Id:                     newOCID("subnet"),
+		CompartmentId:          details.CompartmentId,
+		DisplayName:            details.DisplayName,
+		VcnId:                  details.VcnId,
+		CidrBlock:              details.CidrBlock,
+		RouteTableId:           details.RouteTableId,
+		SecurityListIds:        details.SecurityListIds,
+		ProhibitPublicIpOnVnic: details.ProhibitPublicIpOnVnic,
+		FreeformTags:           details.FreeformTags,
+		LifecycleState:         core.SubnetLifecycleStateAvailable,
+	}
+	c.Subnets[*subnet.Id] = subnet
+	return &subnet, nil
+}
+
+// Update updates a subnet.
+func (c *MockSubnetsClient) Update(ctx context.Context, id string, details core.UpdateSubnetDetails) error {
+	subnet, ok := c.Subnets[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	if details.RouteTableId != nil {
+		subnet.RouteTableId = details.RouteTableId
+	}
+	subnet.SecurityListIds = details.SecurityListIds
+	subnet.FreeformTags = details.FreeformTags
+	c.Subnets[id] = subnet
+	return nil
+}
+
+// List returns a slice of subnets.
+func (c *MockSubnetsClient) List(ctx context.Context, compartmentID string) ([]core.Subnet, error) {
+	var l []core.Subnet
+	for _, v := range c.Subnets {
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified subnet.
+func (c *MockSubnetsClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.Subnets[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.Subnets, id)
+	return nil
+}
+
+// MockInternetGatewaysClient is a mock implementation of InternetGatewaysClient.
+type MockInternetGatewaysClient struct {
+	IGs map[string]core.InternetGateway
+}
+
+var _ oci.InternetGatewaysClient = &MockInternetGatewaysClient{}
+
+// Create creates a new internet gateway.
+func (c *MockInternetGatewaysClient) Create(ctx context.Context, details core.CreateInternetGatewayDetails) (*core.InternetGateway, error) {
+	ig := core.InternetGateway{
+		Id:             newOCID("internetgateway"),
+		CompartmentId:  details.CompartmentId,
+		DisplayName:    details.DisplayName,
+		VcnId:          details.VcnId,
+		IsEnabled:      details.IsEnabled,
+		FreeformTags:   details.FreeformTags,
+		LifecycleState: core.InternetGatewayLifecycleStateAvailable,
+	}
+	c.IGs[*ig.Id] = ig
+	return &ig, nil
+}
+
+// Update updates an internet gateway.
+func (c *MockInternetGatewaysClient) Update(ctx context.Context, id string, details core.UpdateInternetGatewayDetails) error {
+	ig, ok := c.IGs[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	ig.FreeformTags = details.FreeformTags
+	c.IGs[id] = ig
+	return nil
+}
+
+// List returns a slice of internet gateways.
+func (c *MockInternetGatewaysClient) List(ctx context.Context, compartmentID string) ([]core.InternetGateway, error) {
+	var l []core.InternetGateway
+	for _, v := range c.IGs {
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified internet gateway.
+func (c *MockInternetGatewaysClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.IGs[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.IGs, id)
+	return nil
+}
+
+// MockRouteTablesClient is a mock implementation of RouteTablesClient.
+type MockRouteTablesClient struct {
+	RTs map[string]core.RouteTable
+}
+
+var _ oci.RouteTablesClient = &MockRouteTablesClient{}
+
+// Create creates a new route table.
+func (c *MockRouteTablesClient) Create(ctx context.Context, details core.CreateRouteTableDetails) (*core.RouteTable, error) {
+	rt := core.RouteTable{
+		Id:             newOCID("routetable"),
+		CompartmentId:  details.CompartmentId,
+		DisplayName:    details.DisplayName,
+		VcnId:          details.VcnId,
+		RouteRules:     details.RouteRules,
+		FreeformTags:   details.FreeformTags,
+		LifecycleState: core.RouteTableLifecycleStateAvailable,
+	}
+	c.RTs[*rt.Id] = rt
+	return &rt, nil
+}
+
+// Update updates a route table.
+func (c *MockRouteTablesClient) Update(ctx context.Context, id string, details core.UpdateRouteTableDetails) error {
+	rt, ok := c.RTs[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	rt.RouteRules = details.RouteRules
+	rt.FreeformTags = details.FreeformTags
+	c.RTs[id] = rt
+	return nil
+}
+
+// List returns a slice of route tables.
+func (c *MockRouteTablesClient) List(ctx context.Context, compartmentID string) ([]core.RouteTable, error) {
+	var l []core.RouteTable
+	for _, v := range c.RTs {
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified route table.
+func (c *MockRouteTablesClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.RTs[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.RTs, id)
+	return nil
+}
+
+// MockSecurityListsClient is a mock implementation of SecurityListsClient.
+type MockSecurityListsClient struct {
+	SLs map[string]core.SecurityList
+}
+
+var _ oci.SecurityListsClient = &MockSecurityListsClient{}
+
+// Create creates a new security list.
+func (c *MockSecurityListsClient) Create(ctx context.Context, details core.CreateSecurityListDetails) (*core.SecurityList, error) {
+	sl := core.SecurityList{
+		Id:                   newOCID("securitylist"),
+		CompartmentId:        details.CompartmentId,
+		DisplayName:          details.DisplayName,
+		VcnId:                details.VcnId,
+		IngressSecurityRules: details.IngressSecurityRules,
+		EgressSecurityRules:  details.EgressSecurityRules,
+		FreeformTags:         details.FreeformTags,
+		LifecycleState:       core.SecurityListLifecycleStateAvailable,
+	}
+	c.SLs[*sl.Id] = sl
+	return &sl, nil
+}
+
+// Update updates a security list.
+func (c *MockSecurityListsClient) Update(ctx context.Context, id string, details core.UpdateSecurityListDetails) error {
+	sl, ok := c.SLs[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	sl.IngressSecurityRules = details.IngressSecurityRules
+	sl.EgressSecurityRules = details.EgressSecurityRules
+	sl.FreeformTags = details.FreeformTags
+	c.SLs[id] = sl
+	return nil
+}
+
+// List returns a slice of security lists.
+func (c *MockSecurityListsClient) List(ctx context.Context, compartmentID string) ([]core.SecurityList, error) {
+	var l []core.SecurityList
+	for _, v := range c.SLs {
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified security list.
+func (c *MockSecurityListsClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.SLs[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.SLs, id)
+	return nil
+}
+
+// MockInstancePoolsClient is a mock implementation of InstancePoolsClient.
+type MockInstancePoolsClient struct {
+	Pools map[string]core.InstancePool
+}
+
+var _ oci.InstancePoolsClient = &MockInstancePoolsClient{}
+
+// Create creates a new instance pool.
+func (c *MockInstancePoolsClient) Create(ctx context.Context, details core.CreateInstancePoolDetails) (*core.InstancePool, error) {
+	pool := core.InstancePool{
+		Id:                      newOCID("instancepool"),
+		CompartmentId:           details.CompartmentId,
+		DisplayName:             details.DisplayName,
+		InstanceConfigurationId: details.InstanceConfigurationId,
+		Size:                    details.Size,
+		FreeformTags:            details.FreeformTags,
+		LifecycleState:          core.InstancePoolLifecycleStateRunning,
+	}
+	for _, p := range details.PlacementConfigurations {
+		pool.PlacementConfigurations = append(pool.PlacementConfigurations, core.InstancePoolPlacementConfiguration{
+			AvailabilityDomain: p.AvailabilityDomain,
+			PrimarySubnetId:    p.PrimarySubnetId,
+		})
+	}
+	for _, lb := range details.LoadBalancers {
+		pool.LoadBalancers = append(pool.LoadBalancers, loadBalancerAttachment(pool.Id, lb))
+	}
+	c.Pools[*pool.Id] = pool
+	return &pool, nil
+}
+
+func loadBalancerAttachment(poolID *string, details core.AttachLoadBalancerDetails) core.InstancePoolLoadBalancerAttachment {
+	return core.InstancePoolLoadBalancerAttachment{
+		Id:             newOCID("instancepoolloadbalancerattachment"),
+		InstancePoolId: poolID,
+		LoadBalancerId: details.LoadBalancerId,
+		BackendSetName: details.BackendSetName,
+		Port:           details.Port,
+		VnicSelection:  details.VnicSelection,
+	}
+}
+
+// Get returns an instance pool.
+func (c *MockInstancePoolsClient) Get(ctx context.Context, id string) (*core.InstancePool, error) {
+	pool, ok := c.Pools[id]
+	if !ok {
+		return nil, fmt.Errorf("%s does not exist", id)
+	}
+	return &pool, nil
+}
+
+// Update updates an instance pool.
+func (c *MockInstancePoolsClient) Update(ctx context.Context, id string, details core.UpdateInstancePoolDetails) error {
+	pool, ok := c.Pools[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	if details.InstanceConfigurationId != nil {
+		pool.InstanceConfigurationId = details.InstanceConfigurationId
+	}
+	if details.Size != nil {
+		pool.Size = details.Size
+	}
+	pool.FreeformTags = details.FreeformTags
+	c.Pools[id] = pool
+	return nil
+}
+
+// List returns a slice of instance pool summaries.
+func (c *MockInstancePoolsClient) List(ctx context.Context, compartmentID string) ([]core.InstancePoolSummary, error) {
+	var l []core.InstancePoolSummary
+	for _, v := range c.Pools {
+		l = append(l, core.InstancePoolSummary{
+			Id:                      v.Id,
+			CompartmentId:           v.CompartmentId,
+			DisplayName:             v.DisplayName,
+			InstanceConfigurationId: v.InstanceConfigurationId,
+			Size:                    v.Size,
+			FreeformTags:            v.FreeformTags,
+			LifecycleState:          core.InstancePoolSummaryLifecycleStateRunning,
+		})
+	}
+	return l, nil
+}
+
+// Terminate terminates a specified instance pool.
+func (c *MockInstancePoolsClient) Terminate(ctx context.Context, id string) error {
+	if _, ok := c.Pools[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.Pools, id)
+	return nil
+}
+
+// AttachLoadBalancer attaches a load balancer to an instance pool.
+func (c *MockInstancePoolsClient) AttachLoadBalancer(ctx context.Context, id string, details core.AttachLoadBalancerDetails) error {
+	pool, ok := c.Pools[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	pool.LoadBalancers = append(pool.LoadBalancers, loadBalancerAttachment(pool.Id, details))
+	c.Pools[id] = pool
+	return nil
+}
+
+// ListInstances returns a slice of instances in an instance pool.
+func (c *MockInstancePoolsClient) ListInstances(ctx context.Context, compartmentID, id string) ([]core.InstanceSummary, error) {
+	return nil, nil
+}
+
+// DetachInstance detaches an instance from an instance pool.
+func (c *MockInstancePoolsClient) DetachInstance(ctx context.Context, id, instanceID string) error {
+	return errors.New("DetachInstance not implemented on MockInstancePoolsClient")
+}
+
+// MockInstanceConfigurationsClient is a mock implementation of InstanceConfigurationsClient.
+type MockInstanceConfigurationsClient struct {
+	Configs map[string]core.InstanceConfiguration
+}
+
+var _ oci.InstanceConfigurationsClient = &MockInstanceConfigurationsClient{}
+
+// Create creates a new instance configuration.
+func (c *MockInstanceConfigurationsClient) Create(ctx context.Context, details core.CreateInstanceConfigurationDetails) (*core.InstanceConfiguration, error) {
+	config := core.InstanceConfiguration{
+		Id:              newOCID("instanceconfiguration"),
+		CompartmentId:   details.CompartmentId,
+		DisplayName:     details.DisplayName,
+		InstanceDetails: details.InstanceDetails,
+		FreeformTags:    details.FreeformTags,
+	}
+	c.Configs[*config.Id] = config
+	return &config, nil
+}
+
+// Get returns an instance configuration.
+func (c *MockInstanceConfigurationsClient) Get(ctx context.Context, id string) (*core.InstanceConfiguration, error) {
+	config, ok := c.Configs[id]
+	if !ok {
+		return nil, fmt.Errorf("%s does not exist", id)
+	}
+	return &config, nil
+}
+
+// List returns a slice of instance configuration summaries.
+func (c *MockInstanceConfigurationsClient) List(ctx context.Context, compartmentID string) ([]core.InstanceConfigurationSummary, error) {
+	var l []core.InstanceConfigurationSummary
+	for _, v := range c.Configs {
+		l = append(l, core.InstanceConfigurationSummary{
+			Id:            v.Id,
+			CompartmentId: v.CompartmentId,
+			DisplayName:   v.DisplayName,
+			FreeformTags:  v.FreeformTags,
+		})
+	}
+	return l, nil
+}
+
+// Delete deletes a specified instance configuration.
+func (c *MockInstanceConfigurationsClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.Configs[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.Configs, id)
+	return nil
+}
+
+// MockInstancesClient is a mock implementation of InstancesClient.
+type MockInstancesClient struct{}
+
+var _ oci.InstancesClient = &MockInstancesClient{}
+
+// Terminate terminates a specified instance.
+func (c *MockInstancesClient) Terminate(ctx context.Context, id string) error {
+	return errors.New("Terminate not implemented on MockInstancesClient")
+}
+
+// MockImagesClient is a mock implementation of ImagesClient.
+type MockImagesClient struct {
+	Images []core.Image
+}
+
+var _ oci.ImagesClient = &MockImagesClient{}
+
+// List returns a slice of images with the given display name.
+func (c *MockImagesClient) List(ctx context.Context, compartmentID, displayName string) ([]core.Image, error) {
+	var l []core.Image
+	for _, v := range c.Images {
+		if fi.StringValue(v.DisplayName) == displayName {
+			l = append(l, v)
+		}
+	}
+	return l, nil
+}
+
+// MockVolumesClient is a mock implementation of VolumesClient.
+type MockVolumesClient struct {
+	Volumes map[string]core.Volume
+}
+
+var _ oci.VolumesClient = &MockVolumesClient{}
+
+// Create creates a new volume.
+func (c *MockVolumesClient) Create(ctx context.Context, details core.CreateVolumeDetails) (*core.Volume, error) {
+	volume := core.Volume{
+		Id:                 newOCID("volume"),
+		CompartmentId:      details.CompartmentId,
+		AvailabilityDomain: details.AvailabilityDomain,
+		DisplayName:        details.DisplayName,
+		SizeInGBs:          details.SizeInGBs,
+		FreeformTags:       details.FreeformTags,
+		LifecycleState:     core.VolumeLifecycleStateAvailable,
+	}
+	c.Volumes[*volume.Id] = volume
+	return &volume, nil
+}
+
+// Update updates a volume.
+func (c *MockVolumesClient) Update(ctx context.Context, id string, details core.UpdateVolumeDetails) error {
+	volume, ok := c.Volumes[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	if details.SizeInGBs != nil {
+		volume.SizeInGBs = details.SizeInGBs
+	}
+	volume.FreeformTags = details.FreeformTags
+	c.Volumes[id] = volume
+	return nil
+}
+
+// List returns a slice of volumes.
+func (c *MockVolumesClient) List(ctx context.Context, compartmentID string) ([]core.Volume, error) {
+	var l []core.Volume
+	for _, v := range c.Volumes {
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified volume.
+func (c *MockVolumesClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.Volumes[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.Volumes, id)
+	return nil
+}
+
+// MockNetworkLoadBalancersClient is a mock implementation of NetworkLoadBalancersClient.
+type MockNetworkLoadBalancersClient struct {
+	NLBs map[string]networkloadbalancer.NetworkLoadBalancer
+}
+
+var _ oci.NetworkLoadBalancersClient = &MockNetworkLoadBalancersClient{}
+
+// Create creates a new network load balancer.
+func (c *MockNetworkLoadBalancersClient) Create(ctx context.Context, details networkloadbalancer.CreateNetworkLoadBalancerDetails) (*networkloadbalancer.NetworkLoadBalancer, error) {
+	nlb := networkloadbalancer.NetworkLoadBalancer{
+		Id:             newOCID("networkloadbalancer"),
+		CompartmentId:  details.CompartmentId,
+		DisplayName:    details.DisplayName,
+		SubnetId:       details.SubnetId,
+		IsPrivate:      details.IsPrivate,
+		FreeformTags:   details.FreeformTags,
+		LifecycleState: networkloadbalancer.LifecycleStateActive,
+		IpAddresses: []networkloadbalancer.IpAddress{
+			{IpAddress: fi.String("10.0.0.10"), IsPublic: fi.Bool(false)},
+		},
+	}
+	if !fi.BoolValue(details.IsPrivate) {
+		nlb.IpAddresses = append(nlb.IpAddresses, networkloadbalancer.IpAddress{IpAddress: fi.String("192.0.2.10"), IsPublic: fi.Bool(true)})
+	}
+	c.NLBs[*nlb.Id] = nlb
+	return &nlb, nil
+}
+
+// Get returns a network load balancer.
+func (c *MockNetworkLoadBalancersClient) Get(ctx context.Context, id string) (*networkloadbalancer.NetworkLoadBalancer, error) {
+	nlb, ok := c.NLBs[id]
+	if !ok {
+		return nil, fmt.Errorf("%s does not exist", id)
+	}
+	return &nlb, nil
+}
+
+// List returns a slice of network load balancer summaries.
+func (c *MockNetworkLoadBalancersClient) List(ctx context.Context, compartmentID string) ([]networkloadbalancer.NetworkLoadBalancerSummary, error) {
+	var l []networkloadbalancer.NetworkLoadBalancerSummary
+	for _, v := range c.NLBs {
+		l = append(l, networkloadbalancer.NetworkLoadBalancerSummary{
+			Id:             v.Id,
+			CompartmentId:  v.CompartmentId,
+			DisplayName:    v.DisplayName,
+			SubnetId:       v.SubnetId,
+			IsPrivate:      v.IsPrivate,
+			IpAddresses:    v.IpAddresses,
+			FreeformTags:   v.FreeformTags,
+			LifecycleState: v.LifecycleState,
+		})
+	}
+	return l, nil
+}
+
+// Update updates a network load balancer.
+func (c *MockNetworkLoadBalancersClient) Update(ctx context.Context, id string, details networkloadbalancer.UpdateNetworkLoadBalancerDetails) error {
+	nlb, ok := c.NLBs[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	nlb.FreeformTags = details.FreeformTags
+	c.NLBs[id] = nlb
+	return nil
+}
+
+// Delete deletes a specified network load balancer.
+func (c *MockNetworkLoadBalancersClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.NLBs[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.NLBs, id)
+	return nil
+}
+
+// MockDynamicGroupsClient is a mock implementation of DynamicGroupsClient.
+type MockDynamicGroupsClient struct {
+	DGs map[string]identity.DynamicGroup
+}
+
+var _ oci.DynamicGroupsClient = &MockDynamicGroupsClient{}
+
+// Create creates a new dynamic group.
+func (c *MockDynamicGroupsClient) Create(ctx context.Context, details identity.CreateDynamicGroupDetails) (*identity.DynamicGroup, error) {
+	dg := identity.DynamicGroup{
+		Id:             newOCID("dynamicgroup"),
+		CompartmentId:  details.CompartmentId,
+		Name:           details.Name,
+		Description:    details.Description,
+		MatchingRule:   details.MatchingRule,
+		FreeformTags:   details.FreeformTags,
+		LifecycleState: identity.DynamicGroupLifecycleStateActive,
+	}
+	c.DGs[*dg.Id] = dg
+	return &dg, nil
+}
+
+// Update updates a dynamic group.
+func (c *MockDynamicGroupsClient) Update(ctx context.Context, id string, details identity.UpdateDynamicGroupDetails) error {
+	dg, ok := c.DGs[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	dg.Description = details.Description
+	dg.MatchingRule = details.MatchingRule
+	dg.FreeformTags = details.FreeformTags
+	c.DGs[id] = dg
+	return nil
+}
+
+// List returns a slice of dynamic groups.
+func (c *MockDynamicGroupsClient) List(ctx context.Context, tenancyID string) ([]identity.DynamicGroup, error) {
+	var l []identity.DynamicGroup
+	for _, v := range c.DGs {
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified dynamic group.
+func (c *MockDynamicGroupsClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.DGs[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.DGs, id)
+	return nil
+}
+
+// MockPoliciesClient is a mock implementation of PoliciesClient.
+type MockPoliciesClient struct {
+	Policies map[string]identity.Policy
+}
+
+var _ oci.PoliciesClient = &MockPoliciesClient{}
+
+// Create creates a new policy.
+func (c *MockPoliciesClient) Create(ctx context.Context, details identity.CreatePolicyDetails) (*identity.Policy, error) {
+	policy := identity.Policy{
+		Id:             newOCID("policy"),
+		CompartmentId:  details.CompartmentId,
+		Name:           details.Name,
+		Description:    details.Description,
+		Statements:     details.Statements,
+		FreeformTags:   details.FreeformTags,
+		LifecycleState: identity.PolicyLifecycleStateActive,
+	}
+	c.Policies[*policy.Id] = policy
+	return &policy, nil
+}
+
+// Update updates a policy.
+func (c *MockPoliciesClient) Update(ctx context.Context, id string, details identity.UpdatePolicyDetails) error {
+	policy, ok := c.Policies[id]
+	if !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	policy.Description = details.Description
+	policy.Statements = details.Statements
+	policy.FreeformTags = details.FreeformTags
+	c.Policies[id] = policy
+	return nil
+}
+
+// List returns a slice of policies.
+func (c *MockPoliciesClient) List(ctx context.Context, compartmentID string) ([]identity.Policy, error) {
+	var l []identity.Policy
+	for _, v := range c.Policies {
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+// Delete deletes a specified policy.
+func (c *MockPoliciesClient) Delete(ctx context.Context, id string) error {
+	if _, ok := c.Policies[id]; !ok {
+		return fmt.Errorf("%s does not exist", id)
+	}
+	delete(c.Policies, id)
+	return nil
+}
+
+// MockAvailabilityDomainsClient is a mock implementation of AvailabilityDomainsClient.
+type MockAvailabilityDomainsClient struct {
+	ADs []identity.AvailabilityDomain
+}
+
+var _ oci.AvailabilityDomainsClient = &MockAvailabilityDomainsClient{}
+
+// List returns a slice of availability domains.
+func (c *MockAvailabilityDomainsClient) List(ctx context.Context, compartmentID string) ([]identity.AvailabilityDomain, error) {
+	return c.ADs, nil
+}
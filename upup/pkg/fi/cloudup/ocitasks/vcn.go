@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+// VCN is an OCI Virtual Cloud Network.
+// +kops:fitask
+type VCN struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID       *string
+	CIDR     *string
+	DNSLabel *string
+	Tags     map[string]string
+}
+
+var _ fi.Task = &VCN{}
+var _ fi.CompareWithID = &VCN{}
+
+// CompareWithID returns the OCID of the VCN.
+func (v *VCN) CompareWithID() *string {
+	return v.ID
+}
+
+// Find discovers the VCN in the cloud provider.
+func (v *VCN) Find(c *fi.Context) (*VCN, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	l, err := cloud.VCN().List(context.TODO(), cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+	var found *core.Vcn
+	for i := range l {
+		if fi.StringValue(l[i].DisplayName) == fi.StringValue(v.Name) {
+			found = &l[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	// Dependent tasks refer to the VCN by its OCID.
+	v.ID = found.Id
+
+	return &VCN{
+		Name:      v.Name,
+		Lifecycle: v.Lifecycle,
+		ID:        found.Id,
+		CIDR:      found.CidrBlock,
+		DNSLabel:  found.DnsLabel,
+		Tags:      found.FreeformTags,
+	}, nil
+}
+
+// Run implements fi.Task.Run.
+func (v *VCN) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*VCN) CheckChanges(a, e, changes *VCN) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.CIDR == nil {
+			return fi.RequiredField("CIDR")
+		}
+		return nil
+	}
+
+	if changes.CIDR != nil {
+		return fi.CannotChangeField("CIDR")
+	}
+	if changes.DNSLabel != nil {
+		return fi.CannotChangeField("DNSLabel")
+	}
+	return nil
+}
+
+// RenderOCI creates or updates a VCN.
+func (*VCN) RenderOCI(t *oci.OCIAPITarget, a, e, changes *VCN) error {
+	ctx := context.TODO()
+	if a == nil {
+		klog.Infof("Creating a new VCN with name: %s", fi.StringValue(e.Name))
+		vcn, err := t.Cloud.VCN().Create(ctx, core.CreateVcnDetails{
+			CompartmentId: fi.String(t.Cloud.CompartmentID()),
+			DisplayName:   e.Name,
+			CidrBlock:     e.CIDR,
+			DnsLabel:      e.DNSLabel,
+			FreeformTags:  e.Tags,
+		})
+		if err != nil {
+			return err
+		}
+		e.ID = vcn.Id
+		return nil
+	}
+
+	// Only allow tags to be updated.
+	if changes.Tags == nil {
+		return nil
+	}
+	klog.Infof("Updating a VCN with name: %s", fi.StringValue(e.Name))
+	return t.Cloud.VCN().Update(ctx, fi.StringValue(a.ID), core.UpdateVcnDetails{
+		FreeformTags: e.Tags,
+	})
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+func TestVCNRun(t *testing.T) {
+	cloud := NewMockOCICloud("us-ashburn-1")
+	ctx := &fi.Context{
+		Cloud:         cloud,
+		Target:        oci.NewOCIAPITarget(cloud),
+		CheckExisting: true,
+	}
+
+	vcn := &VCN{
+		Name:      fi.String("vcn"),
+		Lifecycle: fi.LifecycleSync,
+		CIDR:      fi.String("10.0.0.0/16"),
+		Tags:      map[string]string{"key": "val"},
+	}
+	if err := vcn.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if vcn.ID == nil {
+		t.Fatalf("expected the ID to be set after creation")
+	}
+	actual := cloud.VCNsClient.VCNs[*vcn.ID]
+	if a, e := fi.StringValue(actual.CidrBlock), "10.0.0.0/16"; a != e {
+		t.Errorf("unexpected CIDR: expected %s, but got %s", e, a)
+	}
+	if a, e := fi.StringValue(actual.CompartmentId), testCompartmentID; a != e {
+		t.Errorf("unexpected compartment: expected %s, but got %s", e, a)
+	}
+
+	// Update the tags of the existing VCN.
+	vcn2 := &VCN{
+		Name:      fi.String("vcn"),
+		Lifecycle: fi.LifecycleSync,
+		CIDR:      fi.String("10.0.0.0/16"),
+		Tags:      map[string]string{"key": "val2"},
+	}
+	if err := vcn2.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := len(cloud.VCNsClient.VCNs), 1; a != e {
+		t.Fatalf("unexpected number of VCNs: expected %d, but got %d", e, a)
+	}
+	if a, e := fi.StringValue(vcn2.ID), fi.StringValue(vcn.ID); a != e {
+		t.Errorf("unexpected ID: expected %s, but got %s", e, a)
+	}
+	if a, e := cloud.VCNsClient.VCNs[*vcn.ID].FreeformTags, vcn2.Tags; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected tags: expected %+v, but got %+v", e, a)
+	}
+}
+
+func TestVCNCheckChanges(t *testing.T) {
+	testCases := []struct {
+		a, e, changes *VCN
+		success       bool
+	}{
+		{
+			a:       nil,
+			e:       &VCN{Name: fi.String("name"), CIDR: fi.String("10.0.0.0/16")},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &VCN{Name: fi.String("name")},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       &VCN{Name: fi.String("name")},
+			changes: &VCN{Tags: map[string]string{"key": "val"}},
+			success: true,
+		},
+		{
+			a:       &VCN{Name: fi.String("name")},
+			changes: &VCN{CIDR: fi.String("10.1.0.0/16")},
+			success: false,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			vcn := VCN{}
+			err := vcn.CheckChanges(tc.a, tc.e, tc.changes)
+			if tc.success != (err == nil) {
+				t.Errorf("expected success=%t, but got err=%v", tc.success, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+// Volume is an OCI block volume used by etcd.
+// +kops:fitask
+type Volume struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID *string
+	// Zone is the kops zone of the volume, of the form <region>-ad-<n>.
+	Zone   *string
+	SizeGB *int64
+	Tags   map[string]string
+}
+
+var _ fi.Task = &Volume{}
+var _ fi.CompareWithID = &Volume{}
+
+// CompareWithID returns the OCID of the volume.
+func (v *Volume) CompareWithID() *string {
+	return v.ID
+}
+
+// Find discovers the volume in the cloud provider.
+func (v *Volume) Find(c *fi.Context) (*Volume, error) {
+	cloud := c.Cloud.(oci.OCICloud)
+	ctx := context.TODO()
+	l, err := cloud.Volume().List(ctx, cloud.CompartmentID())
+	if err != nil {
+		return nil, err
+	}
+	var found *core.Volume
+	for i := range l {
+		if fi.StringValue(l[i].DisplayName) == fi.StringValue(v.Name) {
+			found = &l[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+
+	v.ID = found.Id
+
+	actual := &Volume{
+		Name:      v.Name,
+		Lifecycle: v.Lifecycle,
+		ID:        found.Id,
+		Zone:      found.AvailabilityDomain,
+		SizeGB:    found.SizeInGBs,
+		Tags:      found.FreeformTags,
+	}
+	// Report the zone in kops form if the volume is in the expected availability domain.
+	if v.Zone != nil {
+		ad, err := findAvailabilityDomain(ctx, cloud, fi.StringValue(v.Zone))
+		if err != nil {
+			return nil, err
+		}
+		if ad == fi.StringValue(found.AvailabilityDomain) {
+			actual.Zone = v.Zone
+		}
+	}
+	return actual, nil
+}
+
+// Run implements fi.Task.Run.
+func (v *Volume) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+// CheckChanges returns an error if a change is not allowed.
+func (*Volume) CheckChanges(a, e, changes *Volume) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Zone == nil {
+			return fi.RequiredField("Zone")
+		}
+		return nil
+	}
+
+	if changes.Zone != nil {
+		return fi.CannotChangeField("Zone")
+	}
+	if changes.SizeGB != nil && fi.Int64Value(e.SizeGB) < fi.Int64Value(a.SizeGB) {
+		return fmt.Errorf("volume size cannot be decreased from %d to %d GB", fi.Int64Value(a.SizeGB), fi.Int64Value(e.SizeGB))
+	}
+	return nil
+}
+
+// RenderOCI creates or updates a volume.
+func (*Volume) RenderOCI(t *oci.OCIAPITarget, a, e, changes *Volume) error {
+	ctx := context.TODO()
+	if a == nil {
+		klog.Infof("Creating a new Volume with name: %s", fi.StringValue(e.Name))
+		ad, err := findAvailabilityDomain(ctx, t.Cloud, fi.StringValue(e.Zone))
+		if err != nil {
+			return err
+		}
+		volume, err := t.Cloud.Volume().Create(ctx, core.CreateVolumeDetails{
+			CompartmentId:      fi.String(t.Cloud.CompartmentID()),
+			AvailabilityDomain: fi.String(ad),
+			DisplayName:        e.Name,
+			SizeInGBs:          e.SizeGB,
+			FreeformTags:       e.Tags,
+		})
+		if err != nil {
+			return err
+		}
+		e.ID = volume.Id
+		return nil
+	}
+
+	klog.Infof("Updating a Volume with name: %s", fi.StringValue(e.Name))
+	return t.Cloud.Volume().Update(ctx, fi.StringValue(a.ID), core.UpdateVolumeDetails{
+		SizeInGBs:    changes.SizeGB,
+		FreeformTags: e.Tags,
+	})
+}
+
+// findAvailabilityDomain returns the name of the availability domain for a kops zone.
+func findAvailabilityDomain(ctx context.Context, cloud oci.OCICloud, zone string) (string, error) {
+	ads, err := cloud.AvailabilityDomain().List(ctx, cloud.TenancyID())
+	if err != nil {
+		return "", fmt.Errorf("error listing availability domains: %v", err)
+	}
+	return oci.FindAvailabilityDomain(ads, zone)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocitasks
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
+)
+
+func TestVolumeRun(t *testing.T) {
+	cloud := NewMockOCICloud("us-ashburn-1")
+	ctx := &fi.Context{
+		Cloud:         cloud,
+		Target:        oci.NewOCIAPITarget(cloud),
+		CheckExisting: true,
+	}
+
+	volume := &Volume{
+		Name:      fi.String("volume"),
+		Lifecycle: fi.LifecycleSync,
+		Zone:      fi.String("us-ashburn-1-ad-2"),
+		SizeGB:    fi.Int64(20),
+	}
+	if err := volume.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	actual := cloud.VolumesClient.Volumes[*volume.ID]
+	if a, e := fi.StringValue(actual.AvailabilityDomain), "Test:us-ashburn-1-AD-2"; a != e {
+		t.Errorf("unexpected availability domain: expected %s, but got %s", e, a)
+	}
+
+	// Finding the volume again reports the kops zone, so there are no changes.
+	found, err := (&Volume{Name: volume.Name, Zone: volume.Zone}).Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := fi.StringValue(found.Zone), fi.StringValue(volume.Zone); a != e {
+		t.Errorf("unexpected zone: expected %s, but got %s", e, a)
+	}
+
+	// Grow the volume.
+	volume2 := &Volume{
+		Name:      fi.String("volume"),
+		Lifecycle: fi.LifecycleSync,
+		Zone:      fi.String("us-ashburn-1-ad-2"),
+		SizeGB:    fi.Int64(30),
+	}
+	if err := volume2.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := fi.Int64Value(cloud.VolumesClient.Volumes[*volume.ID].SizeInGBs), int64(30); a != e {
+		t.Errorf("unexpected size: expected %d, but got %d", e, a)
+	}
+}
+
+func TestVolumeCheckChanges(t *testing.T) {
+	testCases := []struct {
+		a, e, changes *Volume
+		success       bool
+	}{
+		{
+			a:       nil,
+			e:       &Volume{Name: fi.String("name"), Zone: fi.String("us-ashburn-1-ad-1")},
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &Volume{Name: fi.String("name")},
+			success: false,
+		},
+		{
+			a:       &Volume{SizeGB: fi.Int64(20)},
+			e:       &Volume{SizeGB: fi.Int64(30)},
+			changes: &Volume{SizeGB: fi.Int64(30)},
+			success: true,
+		},
+		{
+			a:       &Volume{SizeGB: fi.Int64(30)},
+			e:       &Volume{SizeGB: fi.Int64(20)},
+			changes: &Volume{SizeGB: fi.Int64(20)},
+			success: false,
+		},
+		{
+			a:       &Volume{Zone: fi.String("us-ashburn-1-ad-1")},
+			e:       &Volume{Zone: fi.String("us-ashburn-1-ad-2")},
+			changes: &Volume{Zone: fi.String("us-ashburn-1-ad-2")},
+			success: false,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			volume := Volume{}
+			err := volume.CheckChanges(tc.a, tc.e, tc.changes)
+			if tc.success != (err == nil) {
+				t.Errorf("expected success=%t, but got err=%v", tc.success, err)
+			}
+		})
+	}
+}
@@ -39,9 +39,16 @@ const (
 	OS_ANNOTATION             = "openstack.kops.io/"
 	BOOT_FROM_VOLUME          = "osVolumeBoot"
 	BOOT_VOLUME_SIZE          = "osVolumeSize"
+	BOOT_VOLUME_TYPE          = "osVolumeType"
 	SERVER_GROUP_AFFINITY     = "serverGroupAffinity"
+	FLAVOR_MIN_VCPUS          = "flavorMinVCPUs"
+	FLAVOR_MIN_RAM            = "flavorMinRAM"
 )
 
+// ServerGroupAffinityPolicies are the supported policies of server groups.
+// The soft policies place instances on the same or different hosts when possible.
+var ServerGroupAffinityPolicies = []string{"affinity", "anti-affinity", "soft-affinity", "soft-anti-affinity"}
+
 // floatingBackoff is the backoff strategy for listing openstack floatingips
 var floatingBackoff = wait.Backoff{
 	Duration: time.Second,
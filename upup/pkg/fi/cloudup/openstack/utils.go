@@ -19,6 +19,7 @@ package openstack
 import (
 	"fmt"
 	"sort"
+	"strconv"
 
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
@@ -56,8 +57,13 @@ func (c *openstackCloud) DefaultInstanceType(cluster *kops.Cluster, ig *kops.Ins
 }
 
 func defaultInstanceType(c OpenstackCloud, cluster *kops.Cluster, ig *kops.InstanceGroup) (string, error) {
+	minVCPUs, minRAM, err := flavorRequirements(ig)
+	if err != nil {
+		return "", err
+	}
+
 	flavorPage, err := flavors.ListDetail(c.ComputeClient(), flavors.ListOpts{
-		MinRAM: 1024,
+		MinRAM: minRAM,
 	}).AllPages()
 	if err != nil {
 		return "", fmt.Errorf("Could not list flavors: %v", err)
@@ -70,36 +76,48 @@ func defaultInstanceType(c OpenstackCloud, cluster *kops.Cluster, ig *kops.Insta
 	sort.Sort(&fList)
 
 	var candidates flavorList
+	for _, flavor := range fList {
+		if flavor.RAM >= minRAM && flavor.VCPUs >= minVCPUs {
+			candidates = append(candidates, flavor)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("No suitable flavor with at least %d vCPUs and %d MB of RAM for instance group %q", minVCPUs, minRAM, ig.Name)
+	}
+	return candidates[0].Name, nil
+}
+
+// flavorRequirements returns the minimum vCPUs and RAM (in MB) of the flavor of an instance group.
+// The defaults depend on the role, and can be overridden with annotations.
+func flavorRequirements(ig *kops.InstanceGroup) (int, int, error) {
+	var minVCPUs, minRAM int
 	switch ig.Spec.Role {
 	case kops.InstanceGroupRoleMaster:
 		// Requirements based on awsCloudImplementation.DefaultInstanceType
-		for _, flavor := range fList {
-			if flavor.RAM >= 4096 && flavor.VCPUs >= 1 {
-				candidates = append(candidates, flavor)
-			}
-		}
-
+		minVCPUs, minRAM = 1, 4096
 	case kops.InstanceGroupRoleNode:
-		for _, flavor := range fList {
-			if flavor.RAM >= 4096 && flavor.VCPUs >= 2 {
-				candidates = append(candidates, flavor)
-			}
-		}
-
+		minVCPUs, minRAM = 2, 4096
 	case kops.InstanceGroupRoleBastion:
-		for _, flavor := range fList {
-			if flavor.RAM >= 1024 {
-				candidates = append(candidates, flavor)
-			}
-		}
-
+		minVCPUs, minRAM = 0, 1024
 	default:
-		return "", fmt.Errorf("unhandled role %q", ig.Spec.Role)
+		return 0, 0, fmt.Errorf("unhandled role %q", ig.Spec.Role)
 	}
-	if len(candidates) == 0 {
-		return "", fmt.Errorf("No suitable flavor for role %q", ig.Spec.Role)
+
+	if v, ok := ig.ObjectMeta.Annotations[OS_ANNOTATION+FLAVOR_MIN_VCPUS]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid value for %v: %v", OS_ANNOTATION+FLAVOR_MIN_VCPUS, err)
+		}
+		minVCPUs = n
 	}
-	return candidates[0].Name, nil
+	if v, ok := ig.ObjectMeta.Annotations[OS_ANNOTATION+FLAVOR_MIN_RAM]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid value for %v: %v", OS_ANNOTATION+FLAVOR_MIN_RAM, err)
+		}
+		minRAM = n
+	}
+	return minVCPUs, minRAM, nil
 }
 
 func GetServerFixedIP(server *servers.Server, interfaceName string) (poolAddress string, err error) {
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cloudmock/openstack/mockcompute"
+	"k8s.io/kops/cloudmock/openstack/mocknetworking"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func Test_DefaultInstanceType(t *testing.T) {
+	c := BuildMockOpenstackCloud("us-test1")
+	c.MockNovaClient = mockcompute.CreateClient(mocknetworking.CreateClient().ServiceClient())
+	for _, f := range []flavors.CreateOpts{
+		{Name: "small", RAM: 2048, VCPUs: 1},
+		{Name: "medium", RAM: 4096, VCPUs: 2},
+		{Name: "large", RAM: 8192, VCPUs: 4},
+		{Name: "xlarge", RAM: 16384, VCPUs: 8},
+	} {
+		f.Disk = fi.Int(16)
+		if _, err := flavors.Create(c.ComputeClient(), f).Extract(); err != nil {
+			t.Fatalf("error creating flavor: %v", err)
+		}
+	}
+
+	tests := []struct {
+		desc        string
+		role        kops.InstanceGroupRole
+		annotations map[string]string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			desc:     "master default",
+			role:     kops.InstanceGroupRoleMaster,
+			expected: "medium",
+		},
+		{
+			desc:     "bastion default",
+			role:     kops.InstanceGroupRoleBastion,
+			expected: "small",
+		},
+		{
+			desc: "node with resource constraints",
+			role: kops.InstanceGroupRoleNode,
+			annotations: map[string]string{
+				OS_ANNOTATION + FLAVOR_MIN_VCPUS: "3",
+				OS_ANNOTATION + FLAVOR_MIN_RAM:   "10000",
+			},
+			expected: "xlarge",
+		},
+		{
+			desc: "no matching flavor",
+			role: kops.InstanceGroupRoleNode,
+			annotations: map[string]string{
+				OS_ANNOTATION + FLAVOR_MIN_VCPUS: "16",
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "invalid constraint",
+			role: kops.InstanceGroupRoleNode,
+			annotations: map[string]string{
+				OS_ANNOTATION + FLAVOR_MIN_RAM: "8G",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ig := &kops.InstanceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ig",
+					Annotations: test.annotations,
+				},
+				Spec: kops.InstanceGroupSpec{
+					Role: test.role,
+				},
+			}
+			flavor, err := defaultInstanceType(c, &kops.Cluster{}, ig)
+			if test.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, got flavor %q", flavor)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if flavor != test.expected {
+				t.Errorf("expected flavor %q, got %q", test.expected, flavor)
+			}
+		})
+	}
+}
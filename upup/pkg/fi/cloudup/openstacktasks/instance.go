@@ -410,6 +410,10 @@ func includeBootVolumeOptions(t *openstack.OpenstackAPITarget, e *Instance, opts
 		bfv.BlockDevice[0].VolumeSize = int(i)
 	}
 
+	if s, ok := e.Metadata[openstack.BOOT_VOLUME_TYPE]; ok {
+		bfv.BlockDevice[0].VolumeType = s
+	}
+
 	return bfv, nil
 }
 
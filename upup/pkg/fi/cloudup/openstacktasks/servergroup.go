@@ -118,6 +118,9 @@ func (_ *ServerGroup) CheckChanges(a, e, changes *ServerGroup) error {
 		if changes.Name != nil {
 			return fi.CannotChangeField("Name")
 		}
+		if changes.Policies != nil {
+			return fi.CannotChangeField("Policies")
+		}
 	}
 	return nil
 }
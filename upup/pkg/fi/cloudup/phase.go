@@ -28,6 +28,8 @@ const (
 	PhaseSecurity Phase = "security"
 	// PhaseCluster creates the servers, and load-alancers
 	PhaseCluster Phase = "cluster"
+	// PhaseTags only adds the missing tags to the existing resources, without running the tasks
+	PhaseTags Phase = "tags"
 )
 
 // Phases are used for validation and cli help.
@@ -35,4 +37,5 @@ var Phases = sets.NewString(
 	string(PhaseSecurity),
 	string(PhaseNetwork),
 	string(PhaseCluster),
+	string(PhaseTags),
 )
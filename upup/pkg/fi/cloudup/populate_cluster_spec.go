@@ -261,6 +261,10 @@ func (c *populateClusterSpec) run(clientset simple.Clientset) error {
 		{
 			// Note: DefaultOptionsBuilder comes first
 			codeModels = append(codeModels, &components.DefaultsOptionsBuilder{Context: optionsContext})
+			// Note: FIPSOptionsBuilder comes before SecurityProfileOptionsBuilder, so the FIPS cipher suites take precedence
+			codeModels = append(codeModels, &components.FIPSOptionsBuilder{OptionsContext: optionsContext})
+			codeModels = append(codeModels, &components.SecurityProfileOptionsBuilder{OptionsContext: optionsContext})
+			codeModels = append(codeModels, &components.FeatureGatesOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.EtcdOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &etcdmanager.EtcdManagerOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.KubeAPIServerOptionsBuilder{OptionsContext: optionsContext})
@@ -278,9 +282,12 @@ func (c *populateClusterSpec) run(clientset simple.Clientset) error {
 			codeModels = append(codeModels, &components.OpenStackOptionsBulder{Context: optionsContext})
 			codeModels = append(codeModels, &components.DiscoveryOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.ClusterAutoscalerOptionsBuilder{OptionsContext: optionsContext})
+			codeModels = append(codeModels, &components.DeschedulerOptionsBuilder{OptionsContext: optionsContext})
+			codeModels = append(codeModels, &components.VerticalPodAutoscalerOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.NodeTerminationHandlerOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.NodeProblemDetectorOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.AWSEBSCSIDriverOptionsBuilder{OptionsContext: optionsContext})
+			codeModels = append(codeModels, &components.CloudControllerManagerOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.AWSCloudControllerManagerOptionsBuilder{OptionsContext: optionsContext})
 		}
 	}
@@ -28,9 +28,9 @@ import (
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/util/pkg/architectures"
-	"k8s.io/kops/util/pkg/reflectutils"
 )
 
 // Default Machine types for various types of instance group machine
@@ -39,15 +39,18 @@ const (
 	defaultNodeMachineTypeDO    = "s-2vcpu-4gb"
 	defaultNodeMachineTypeALI   = "ecs.n2.medium"
 	defaultNodeMachineTypeAzure = "Standard_B2ms"
+	defaultNodeMachineTypeOCI   = "VM.Standard.E4.Flex"
 
 	defaultBastionMachineTypeGCE   = "f1-micro"
 	defaultBastionMachineTypeALI   = "ecs.n2.small"
 	defaultBastionMachineTypeAzure = "Standard_B2ms"
+	defaultBastionMachineTypeOCI   = "VM.Standard.E4.Flex"
 
 	defaultMasterMachineTypeGCE   = "n1-standard-1"
 	defaultMasterMachineTypeDO    = "s-2vcpu-4gb"
 	defaultMasterMachineTypeALI   = "ecs.n2.medium"
 	defaultMasterMachineTypeAzure = "Standard_B2ms"
+	defaultMasterMachineTypeOCI   = "VM.Standard.E4.Flex"
 
 	defaultDONodeImage  = "ubuntu-20-04-x64"
 	defaultALINodeImage = "centos_7_04_64_20G_alibase_201701015.vhd"
@@ -66,14 +69,17 @@ var awsDedicatedInstanceExceptions = map[string]bool{
 // PopulateInstanceGroupSpec sets default values in the InstanceGroup
 // The InstanceGroup is simpler than the cluster spec, so we just populate in place (like the rest of k8s)
 func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup, cloud fi.Cloud, channel *kops.Channel) (*kops.InstanceGroup, error) {
+	ig := cluster.InstanceGroupWithDefaults(input)
+
 	var err error
-	err = validation.ValidateInstanceGroup(input, nil).ToAggregate()
+	err = validation.ValidateInstanceGroup(ig, nil).ToAggregate()
 	if err != nil {
 		return nil, err
 	}
 
-	ig := &kops.InstanceGroup{}
-	reflectutils.JSONMergeStruct(ig, input)
+	if ig.Spec.MachineType == "" && ig.Spec.ProvisioningPolicy != nil && len(ig.Spec.ProvisioningPolicy.MachineTypes) != 0 {
+		ig.Spec.MachineType = ig.Spec.ProvisioningPolicy.MachineTypes[0].Name
+	}
 
 	// TODO: Clean up
 	if ig.IsMaster() {
@@ -103,6 +109,11 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 		if ig.Spec.MaxSize == nil {
 			ig.Spec.MaxSize = fi.Int32(1)
 		}
+	} else if ig.IsHybrid() {
+		// Hybrid machines are provisioned outside the cloud provider and enrolled one by one.
+		if !featureflag.HybridNodes.Enabled() {
+			return nil, fmt.Errorf("hybrid nodes requires the HybridNodes feature flag to be enabled")
+		}
 	} else {
 		if ig.IsAPIServerOnly() && !featureflag.APIServerNodes.Enabled() {
 			return nil, fmt.Errorf("apiserver nodes requires the APIServerNodes feature flag to be enabled")
@@ -121,7 +132,8 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 		}
 	}
 
-	if ig.Spec.Image == "" {
+	// The operating system of metal machines is installed before kOps configures them.
+	if ig.Spec.Image == "" && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderMetal && !ig.IsHybrid() {
 		architecture, err := MachineArchitecture(cloud, ig.Spec.MachineType)
 		if err != nil {
 			return nil, fmt.Errorf("unable to determine machine architecture for InstanceGroup %q: %v", ig.ObjectMeta.Name, err)
@@ -143,6 +155,10 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 		}
 	}
 
+	if ig.IsHybrid() {
+		return ig, nil
+	}
+
 	if ig.IsMaster() {
 		if len(ig.Spec.Subnets) == 0 {
 			return nil, fmt.Errorf("master InstanceGroup %s did not specify any Subnets", ig.ObjectMeta.Name)
@@ -227,6 +243,18 @@ func defaultMachineType(cloud fi.Cloud, cluster *kops.Cluster, ig *kops.Instance
 		case kops.InstanceGroupRoleBastion:
 			return defaultBastionMachineTypeAzure, nil
 		}
+
+	case kops.CloudProviderOCI:
+		switch ig.Spec.Role {
+		case kops.InstanceGroupRoleMaster:
+			return defaultMasterMachineTypeOCI, nil
+
+		case kops.InstanceGroupRoleNode:
+			return defaultNodeMachineTypeOCI, nil
+
+		case kops.InstanceGroupRoleBastion:
+			return defaultBastionMachineTypeOCI, nil
+		}
 	}
 
 	klog.V(2).Infof("Cannot set default MachineType for CloudProvider=%q, Role=%q", cluster.Spec.CloudProvider, ig.Spec.Role)
@@ -285,6 +313,8 @@ func MachineArchitecture(cloud fi.Cloud, machineType string) (architectures.Arch
 			}
 		}
 		return "", fmt.Errorf("unsupported architecture for instance type %q: %v", machineType, unsupported)
+	case kops.CloudProviderOCI:
+		return oci.ShapeArchitecture(machineType), nil
 	default:
 		// No other clouds are known to support any other architectures at this time
 		return architectures.ArchitectureAmd64, nil
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/architectures"
 )
 
@@ -122,3 +123,50 @@ func TestMachineArchitecture(t *testing.T) {
 		})
 	}
 }
+
+func TestFullInstanceGroupSpec(t *testing.T) {
+	cloud, cluster := buildMinimalCluster()
+	cluster.Spec.Networking = &kopsapi.NetworkingSpec{AmazonVPC: &kopsapi.AmazonVPCNetworkingSpec{}}
+	cluster.Spec.Kubelet = &kopsapi.KubeletConfigSpec{MaxPods: fi.Int32(200), LogLevel: fi.Int32(2)}
+
+	g := buildMinimalNodeInstanceGroup("subnet-us-mock-1a")
+	g.Spec.MachineType = "t3.medium"
+	g.Spec.Image = "my-image"
+	g.Spec.Kubelet = &kopsapi.KubeletConfigSpec{LogLevel: fi.Int32(4)}
+	g.Spec.InstanceMetadata = &kopsapi.InstanceMetadataOptions{HTTPTokens: fi.String("required")}
+
+	full, err := FullInstanceGroupSpec(cluster, g, cloud, &kopsapi.Channel{})
+	if err != nil {
+		t.Fatalf("unexpected error from FullInstanceGroupSpec: %v", err)
+	}
+
+	// The mock cloud reports a single ENI with a single IP for every instance type
+	if v := fi.Int32Value(full.Spec.Kubelet.MaxPods); v != 2 {
+		t.Errorf("expected maxPods 2, got %d", v)
+	}
+	if v := fi.Int32Value(full.Spec.Kubelet.LogLevel); v != 4 {
+		t.Errorf("expected the kubelet logLevel of the instance group, got %d", v)
+	}
+	if v := fi.Int32Value(full.Spec.RootVolumeSize); v != 128 {
+		t.Errorf("expected root volume size 128, got %d", v)
+	}
+	if v := fi.StringValue(full.Spec.RootVolumeType); v != "gp3" {
+		t.Errorf("expected root volume type gp3, got %q", v)
+	}
+	if !fi.BoolValue(full.Spec.RootVolumeEncryption) {
+		t.Errorf("expected root volume encryption")
+	}
+	if v := fi.Int64Value(full.Spec.InstanceMetadata.HTTPPutResponseHopLimit); v != 1 {
+		t.Errorf("expected httpPutResponseHopLimit 1, got %d", v)
+	}
+	if v := fi.StringValue(full.Spec.InstanceMetadata.HTTPTokens); v != "required" {
+		t.Errorf("expected httpTokens of the instance group, got %q", v)
+	}
+	if v := fi.Int32Value(full.Spec.MinSize); v != 2 {
+		t.Errorf("expected minSize 2, got %d", v)
+	}
+
+	if g.Spec.RootVolumeSize != nil || g.Spec.InstanceMetadata.HTTPPutResponseHopLimit != nil {
+		t.Errorf("expected the input instance group to be unchanged, got %+v", g.Spec)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestBuildSSHConfigPublicKeys(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			SSH: &kops.SSHSpec{
+				PublicKeys: []kops.SSHPublicKeySpec{
+					{Name: "team-a", PublicKey: "ssh-ed25519 AAAA team-a\n"},
+					{Name: "team-b", PublicKey: "ssh-ed25519 BBBB team-b"},
+				},
+			},
+		},
+	}
+
+	grid := []struct {
+		Name          string
+		AdminKeys     []string
+		SSHPublicKeys []string
+		Expected      []string
+	}{
+		{
+			Name:      "all keys by default",
+			AdminKeys: []string{"ssh-rsa ADMIN admin"},
+			Expected:  []string{"ssh-rsa ADMIN admin", "ssh-ed25519 AAAA team-a", "ssh-ed25519 BBBB team-b"},
+		},
+		{
+			Name:          "selected keys",
+			AdminKeys:     []string{"ssh-rsa ADMIN admin"},
+			SSHPublicKeys: []string{"team-b"},
+			Expected:      []string{"ssh-rsa ADMIN admin", "ssh-ed25519 BBBB team-b"},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			builder := &nodeUpConfigBuilder{cluster: cluster}
+			for _, key := range g.AdminKeys {
+				builder.sshPublicKeys = append(builder.sshPublicKeys, []byte(key))
+			}
+			ig := &kops.InstanceGroup{Spec: kops.InstanceGroupSpec{SSHPublicKeys: g.SSHPublicKeys}}
+
+			config := builder.buildSSHConfig(ig)
+			if config == nil {
+				t.Fatalf("expected SSH configuration")
+			}
+			if !reflect.DeepEqual(config.AuthorizedKeys, g.Expected) {
+				t.Errorf("unexpected authorized keys\nexpected: %q\nactual:   %q", g.Expected, config.AuthorizedKeys)
+			}
+		})
+	}
+
+	builder := &nodeUpConfigBuilder{cluster: &kops.Cluster{}, sshPublicKeys: [][]byte{[]byte("ssh-rsa ADMIN admin")}}
+	if config := builder.buildSSHConfig(&kops.InstanceGroup{}); config != nil {
+		t.Errorf("expected no SSH configuration for a single admin key, got %v", config)
+	}
+}
@@ -34,12 +34,13 @@ import (
 	"os"
 	"path"
 	"sort"
-	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	kopscontrollerconfig "k8s.io/kops/cmd/kops-controller/pkg/config"
@@ -55,6 +56,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/util/pkg/env"
+	"k8s.io/kops/util/pkg/proxy"
+	"sigs.k8s.io/yaml"
 )
 
 // TemplateFunctions provides a collection of methods used throughout the templates
@@ -116,6 +119,7 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 	}
 
 	dest["KopsControllerArgv"] = tf.KopsControllerArgv
+	dest["DexConfig"] = tf.DexConfig
 	dest["KopsControllerConfig"] = tf.KopsControllerConfig
 	dest["DnsControllerArgv"] = tf.DNSControllerArgv
 	dest["ExternalDnsArgv"] = tf.ExternalDNSArgv
@@ -136,6 +140,19 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 		return tf.UseKopsControllerForNodeBootstrap()
 	}
 
+	dest["UseNodeRemediation"] = func() bool {
+		return apiModel.UseNodeRemediation(tf.Cluster)
+	}
+	dest["UseKubeletServerTLSBootstrap"] = func() bool {
+		return apiModel.UseKubeletServerTLSBootstrap(tf.Cluster, tf.InstanceGroups)
+	}
+	dest["UseEgressGateways"] = func() bool {
+		return apiModel.UseEgressGateways(tf.Cluster)
+	}
+	dest["UseVerticalPodAutoscaler"] = func() bool {
+		return apiModel.UseVerticalPodAutoscaler(tf.Cluster)
+	}
+
 	dest["DO_TOKEN"] = func() string {
 		return os.Getenv("DIGITALOCEAN_ACCESS_TOKEN")
 	}
@@ -233,6 +250,7 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 		return strings.Join(labels, ",")
 	}
 
+	dest["EBSCSIDriverExtraTags"] = tf.EBSCSIDriverExtraTags
 	dest["IsIPv6Only"] = tf.IsIPv6Only
 	dest["UseServiceAccountIAM"] = tf.UseServiceAccountIAM
 
@@ -353,6 +371,23 @@ func (tf *TemplateFunctions) CloudControllerConfigArgv() ([]string, error) {
 	} else {
 		argv = append(argv, fmt.Sprintf("--use-service-account-credentials=%t", true))
 	}
+	if leaderElection := cluster.Spec.ExternalCloudControllerManager.LeaderElection; leaderElection != nil && leaderElection.LeaderElect != nil {
+		argv = append(argv, fmt.Sprintf("--leader-elect=%t", *leaderElection.LeaderElect))
+	}
+	if cluster.Spec.ExternalCloudControllerManager.ConcurrentServiceSyncs != nil {
+		argv = append(argv, fmt.Sprintf("--concurrent-service-syncs=%d", *cluster.Spec.ExternalCloudControllerManager.ConcurrentServiceSyncs))
+	}
+	if featureGates := cluster.Spec.ExternalCloudControllerManager.FeatureGates; len(featureGates) != 0 {
+		var gates []string
+		for k, v := range featureGates {
+			gates = append(gates, k+"="+v)
+		}
+		sort.Strings(gates)
+		argv = append(argv, "--feature-gates="+strings.Join(gates, ","))
+	}
+	if cluster.Spec.ExternalCloudControllerManager.EnableLeaderMigration != nil {
+		argv = append(argv, fmt.Sprintf("--enable-leader-migration=%t", *cluster.Spec.ExternalCloudControllerManager.EnableLeaderMigration))
+	}
 
 	return argv, nil
 }
@@ -482,6 +517,138 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 		config.CacheNodeidentityInfo = true
 	}
 
+	if cluster.Spec.ContinuousValidation != nil {
+		config.Validation = &kopscontrollerconfig.ValidationOptions{
+			Interval:       metav1.Duration{Duration: 5 * time.Minute},
+			Checks:         cluster.Spec.ContinuousValidation.Checks,
+			MetricsAddress: fmt.Sprintf(":%d", wellknownports.KopsControllerMetricsPort),
+		}
+		if cluster.Spec.ContinuousValidation.Interval != nil {
+			config.Validation.Interval = *cluster.Spec.ContinuousValidation.Interval
+		}
+	}
+
+	if apiModel.UseNodeRemediation(cluster) {
+		npd := cluster.Spec.NodeProblemDetector
+		config.Remediation = &kopscontrollerconfig.RemediationOptions{
+			Conditions:    npd.Remediation.Conditions,
+			Threshold:     metav1.Duration{Duration: 10 * time.Minute},
+			MaxConcurrent: 1,
+			Recycle:       fi.BoolValue(npd.Remediation.Recycle),
+		}
+		if npd.Remediation.Threshold != nil {
+			config.Remediation.Threshold = *npd.Remediation.Threshold
+		}
+		if npd.Remediation.MaxConcurrent != nil {
+			config.Remediation.MaxConcurrent = int(*npd.Remediation.MaxConcurrent)
+		}
+	}
+
+	if cluster.Spec.OSPatching != nil {
+		config.OSPatching = &kopscontrollerconfig.OSPatchingOptions{
+			Interval:       metav1.Duration{Duration: 7 * 24 * time.Hour},
+			Method:         kops.OSPatchingMethodPod,
+			Reboot:         fi.BoolValue(cluster.Spec.OSPatching.Reboot),
+			InstanceGroups: cluster.Spec.OSPatching.InstanceGroups,
+		}
+		if cluster.Spec.OSPatching.Interval != nil {
+			config.OSPatching.Interval = *cluster.Spec.OSPatching.Interval
+		}
+		if cluster.Spec.OSPatching.Method != "" {
+			config.OSPatching.Method = cluster.Spec.OSPatching.Method
+		}
+		if cluster.Spec.OSPatching.Reboot == nil {
+			config.OSPatching.Reboot = true
+		}
+	}
+
+	if mw := cluster.Spec.MaintenanceWindow; mw != nil && mw.Duration != nil {
+		config.MaintenanceWindow = &kopscontrollerconfig.MaintenanceWindowOptions{
+			Schedule: mw.Schedule,
+			Duration: *mw.Duration,
+		}
+	}
+
+	if cluster.Spec.NodeLabelReconciliation != nil {
+		config.NodeLabelReconciliation = &kopscontrollerconfig.NodeLabelReconciliationOptions{
+			Interval: metav1.Duration{Duration: time.Minute},
+		}
+		if cluster.Spec.NodeLabelReconciliation.Interval != nil {
+			config.NodeLabelReconciliation.Interval = *cluster.Spec.NodeLabelReconciliation.Interval
+		}
+	}
+
+	if apiModel.UseVerticalPodAutoscaler(cluster) {
+		config.WebhookCertificates = &kopscontrollerconfig.WebhookCertificatesOptions{
+			CABasePath: "/etc/kubernetes/kops-controller/pki",
+			Signer:     fi.CertificateIDCA,
+			Certificates: []kopscontrollerconfig.WebhookCertificateOptions{
+				{
+					Namespace:      "kube-system",
+					Service:        "vpa-webhook",
+					Secret:         "vpa-tls-certs",
+					CertificateKey: "serverCert.pem",
+					PrivateKeyKey:  "serverKey.pem",
+					CAKey:          "caCert.pem",
+					Validity:       metav1.Duration{Duration: 30 * 24 * time.Hour},
+					Deployment:     "vpa-admission-controller",
+				},
+			},
+		}
+		if validity := cluster.Spec.VerticalPodAutoscaler.CertificateValidity; validity != nil {
+			config.WebhookCertificates.Certificates[0].Validity = *validity
+		}
+	}
+
+	if apiModel.UseKubeletServerTLSBootstrap(cluster, tf.InstanceGroups) {
+		config.ApproveKubeletServingCertificates = true
+	}
+
+	for _, gateway := range cluster.Spec.EgressGateways {
+		destinationCIDRs := gateway.DestinationCIDRs
+		if len(destinationCIDRs) == 0 {
+			destinationCIDRs = []string{"0.0.0.0/0"}
+		}
+		config.EgressGateways = append(config.EgressGateways, kopscontrollerconfig.EgressGatewayOptions{
+			Name:             gateway.Name,
+			InstanceGroup:    gateway.InstanceGroup,
+			ElasticIP:        tf.EgressGatewayElasticIPName(gateway.Name),
+			Namespaces:       gateway.Namespaces,
+			DestinationCIDRs: destinationCIDRs,
+		})
+	}
+
+	if names := apiModel.ScaleInProtectedInstanceGroups(tf.InstanceGroups); len(names) != 0 {
+		config.ScaleInProtection = &kopscontrollerconfig.ScaleInProtectionOptions{
+			InstanceGroups: names,
+		}
+	}
+
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		if etcdCluster.Backups == nil || etcdCluster.Backups.ReplicaStore == "" {
+			continue
+		}
+		if config.EtcdBackupReplication == nil {
+			// etcd-manager takes a backup every 15 minutes
+			config.EtcdBackupReplication = &kopscontrollerconfig.EtcdBackupReplicationOptions{
+				Interval: metav1.Duration{Duration: 15 * time.Minute},
+			}
+		}
+		config.EtcdBackupReplication.Stores = append(config.EtcdBackupReplication.Stores, kopscontrollerconfig.EtcdBackupReplicaOptions{
+			Source:      etcdCluster.Backups.BackupStore,
+			Destination: etcdCluster.Backups.ReplicaStore,
+		})
+	}
+
+	for _, ig := range tf.InstanceGroups {
+		if ig.IsHybrid() {
+			if config.HybridNodes == nil {
+				config.HybridNodes = &kopscontrollerconfig.HybridNodesOptions{}
+			}
+			config.HybridNodes.InstanceGroups = append(config.HybridNodes.InstanceGroups, ig.Name)
+		}
+	}
+
 	if tf.UseKopsControllerForNodeBootstrap() {
 		certNames := []string{"kubelet", "kubelet-server"}
 		signingCAs := []string{fi.CertificateIDCA}
@@ -565,6 +732,58 @@ func (tf *TemplateFunctions) KopsControllerArgv() ([]string, error) {
 	return argv, nil
 }
 
+// DexConfig returns the config for the managed Dex identity provider
+func (tf *TemplateFunctions) DexConfig() (string, error) {
+	oidc := tf.Cluster.Spec.Authentication.OIDC
+
+	var connectors []interface{}
+	if err := yaml.Unmarshal([]byte(oidc.Dex.Connectors), &connectors); err != nil {
+		return "", fmt.Errorf("failed to parse dex connectors: %v", err)
+	}
+
+	clientID := oidc.ClientID
+	if clientID == "" {
+		clientID = "kubernetes"
+	}
+
+	// The client is public so that kubectl plugins can use it without a shared secret;
+	// Dex allows public clients to redirect to localhost.
+	client := map[string]interface{}{
+		"id":     clientID,
+		"name":   "Kubernetes",
+		"public": true,
+	}
+	if len(oidc.Dex.RedirectURIs) != 0 {
+		client["redirectURIs"] = oidc.Dex.RedirectURIs
+	}
+
+	config := map[string]interface{}{
+		"issuer": oidc.IssuerURL,
+		"storage": map[string]interface{}{
+			"type": "kubernetes",
+			"config": map[string]interface{}{
+				"inCluster": true,
+			},
+		},
+		"web": map[string]interface{}{
+			"http": "0.0.0.0:5556",
+		},
+		"oauth2": map[string]interface{}{
+			"skipApprovalScreen": true,
+		},
+		"connectors":    connectors,
+		"staticClients": []interface{}{client},
+	}
+
+	// To avoid indentation problems, we marshal as json.  json is a subset of yaml
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize dex config: %v", err)
+	}
+
+	return string(b), nil
+}
+
 func (tf *TemplateFunctions) ExternalDNSArgv() ([]string, error) {
 	cluster := tf.Cluster
 
@@ -598,13 +817,7 @@ func (tf *TemplateFunctions) ProxyEnv() map[string]string {
 	}
 	httpProxy := proxies.HTTPProxy
 	if httpProxy.Host != "" {
-		var portSuffix string
-		if httpProxy.Port != 0 {
-			portSuffix = ":" + strconv.Itoa(httpProxy.Port)
-		} else {
-			portSuffix = ""
-		}
-		url := "http://" + httpProxy.Host + portSuffix
+		url := proxy.ProxyURL(proxies)
 		envs["http_proxy"] = url
 		envs["https_proxy"] = url
 	}
@@ -663,6 +876,33 @@ func (tf *TemplateFunctions) AWSCCMTag() (string, error) {
 	return tag, nil
 }
 
+// EBSCSIDriverExtraTags returns the tags the EBS CSI driver applies to the volumes it provisions: the cluster cloudLabels,
+// the labels of the cloud label policies for volumes that are not restricted to subnets and the driver extraTags.
+func (tf *TemplateFunctions) EBSCSIDriverExtraTags() string {
+	tags := map[string]string{
+		"KubernetesCluster": tf.Cluster.ObjectMeta.Name,
+	}
+	for k, v := range tf.Cluster.Spec.CloudLabels {
+		tags[k] = v
+	}
+	for k, v := range tf.CloudLabelPolicyTags(kops.CloudResourceTypeVolume) {
+		tags[k] = v
+	}
+	if tf.Cluster.Spec.CloudConfig != nil && tf.Cluster.Spec.CloudConfig.AWSEBSCSIDriver != nil {
+		for k, v := range tf.Cluster.Spec.CloudConfig.AWSEBSCSIDriver.ExtraTags {
+			tags[k] = v
+		}
+	}
+
+	var labels []string
+	for k, v := range tags {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+	}
+	// ensure stable sorting of tags
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
 // GetNodeInstanceGroups returns a map containing the defined instance groups of role "Node".
 func (tf *TemplateFunctions) GetNodeInstanceGroups() map[string]kops.InstanceGroupSpec {
 	nodegroups := make(map[string]kops.InstanceGroupSpec)
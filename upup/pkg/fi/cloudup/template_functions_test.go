@@ -191,6 +191,30 @@ func Test_TemplateFunctions_CloudControllerConfigArgv(t *testing.T) {
 				"--use-service-account-credentials=false",
 			},
 		},
+		{
+			desc: "Controller Configuration",
+			cluster: &kops.Cluster{Spec: kops.ClusterSpec{
+				CloudProvider: string(kops.CloudProviderAWS),
+				ExternalCloudControllerManager: &kops.CloudControllerManagerConfig{
+					LeaderElection:         &kops.LeaderElectionConfiguration{LeaderElect: fi.Bool(true)},
+					ConcurrentServiceSyncs: fi.Int32(5),
+					FeatureGates: map[string]string{
+						"RemoveSelfLink":                   "false",
+						"ControllerManagerLeaderMigration": "true",
+					},
+					EnableLeaderMigration: fi.Bool(true),
+				},
+			}},
+			expectedArgv: []string{
+				"--v=2",
+				"--cloud-provider=aws",
+				"--use-service-account-credentials=true",
+				"--leader-elect=true",
+				"--concurrent-service-syncs=5",
+				"--feature-gates=ControllerManagerLeaderMigration=true,RemoveSelfLink=false",
+				"--enable-leader-migration=true",
+			},
+		},
 	}
 	for _, testCase := range tests {
 		t.Run(testCase.desc, func(t *testing.T) {
@@ -207,3 +231,68 @@ func Test_TemplateFunctions_CloudControllerConfigArgv(t *testing.T) {
 		})
 	}
 }
+
+func Test_TemplateFunctions_EBSCSIDriverExtraTags(t *testing.T) {
+	tf := &TemplateFunctions{}
+	tf.Cluster = &kops.Cluster{Spec: kops.ClusterSpec{
+		CloudLabels: map[string]string{
+			"team":        "platform",
+			"cost-center": "1234",
+		},
+		CloudLabelPolicies: []kops.CloudLabelPolicy{
+			{
+				Labels:        map[string]string{"backup": "daily"},
+				ResourceTypes: []kops.CloudResourceType{kops.CloudResourceTypeVolume},
+			},
+			{
+				Labels:        map[string]string{"network": "private"},
+				ResourceTypes: []kops.CloudResourceType{kops.CloudResourceTypeVolume},
+				Subnets:       []string{"us-east-1a"},
+			},
+			{
+				Labels:        map[string]string{"lb": "true"},
+				ResourceTypes: []kops.CloudResourceType{kops.CloudResourceTypeLoadBalancer},
+			},
+		},
+		CloudConfig: &kops.CloudConfiguration{
+			AWSEBSCSIDriver: &kops.AWSEBSCSIDriver{
+				ExtraTags: map[string]string{
+					"cost-center": "5678",
+					"storage":     "csi",
+				},
+			},
+		},
+	}}
+	tf.Cluster.ObjectMeta.Name = "minimal.example.com"
+
+	expected := "KubernetesCluster=minimal.example.com,backup=daily,cost-center=5678,storage=csi,team=platform"
+	if actual := tf.EBSCSIDriverExtraTags(); actual != expected {
+		t.Errorf("EBSCSIDriverExtraTags differs: %q instead of %q", actual, expected)
+	}
+}
+
+func Test_TemplateFunctions_DexConfig(t *testing.T) {
+	tf := &TemplateFunctions{}
+	tf.Cluster = &kops.Cluster{Spec: kops.ClusterSpec{
+		Authentication: &kops.AuthenticationSpec{
+			OIDC: &kops.OIDCAuthenticationSpec{
+				IssuerURL: "https://dex.example.com",
+				Dex: &kops.DexSpec{
+					Connectors: "- type: github\n  id: github\n  name: GitHub\n  config:\n    clientSecret: $GITHUB_CLIENT_SECRET\n",
+				},
+			},
+		},
+	}}
+
+	actual, err := tf.DexConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"connectors":[{"config":{"clientSecret":"$GITHUB_CLIENT_SECRET"},"id":"github","name":"GitHub","type":"github"}],` +
+		`"issuer":"https://dex.example.com","oauth2":{"skipApprovalScreen":true},` +
+		`"staticClients":[{"id":"kubernetes","name":"Kubernetes","public":true}],` +
+		`"storage":{"config":{"inCluster":true},"type":"kubernetes"},"web":{"http":"0.0.0.0:5556"}}`
+	if actual != expected {
+		t.Errorf("Config differs: %s instead of %s", actual, expected)
+	}
+}
@@ -32,6 +32,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+	"k8s.io/kops/upup/pkg/fi/cloudup/oci"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
@@ -164,6 +166,34 @@ func BuildCloud(cluster *kops.Cluster) (fi.Cloud, error) {
 
 			cloud = azureCloud
 		}
+
+	case kops.CloudProviderOCI:
+		{
+			for _, subnet := range cluster.Spec.Subnets {
+				if subnet.Region != "" {
+					region = subnet.Region
+				}
+			}
+			if region == "" {
+				return nil, fmt.Errorf("on OCI, subnets must include Regions")
+			}
+			if cluster.Spec.CloudConfig == nil || cluster.Spec.CloudConfig.OCI == nil || cluster.Spec.CloudConfig.OCI.CompartmentID == "" {
+				return nil, fmt.Errorf("on OCI, cloudConfig.oci.compartmentId must be set")
+			}
+
+			cloudTags := map[string]string{oci.TagClusterName: cluster.ObjectMeta.Name}
+
+			ociCloud, err := oci.NewOCICloud(cluster.Spec.CloudConfig.OCI.CompartmentID, region, cloudTags)
+			if err != nil {
+				return nil, err
+			}
+
+			cloud = ociCloud
+		}
+	case kops.CloudProviderMetal:
+		{
+			cloud = metal.NewMetalCloud()
+		}
 	default:
 		return nil, fmt.Errorf("unknown CloudProvider %q", cluster.Spec.CloudProvider)
 	}
@@ -185,6 +215,12 @@ func SupportedClouds() []string {
 	if AlphaAllowGCE.Enabled() {
 		clouds = append(clouds, string(kops.CloudProviderGCE))
 	}
+	if featureflag.OCI.Enabled() {
+		clouds = append(clouds, string(kops.CloudProviderOCI))
+	}
+	if featureflag.Metal.Enabled() {
+		clouds = append(clouds, string(kops.CloudProviderMetal))
+	}
 
 	sort.Strings(clouds)
 	return clouds
@@ -18,6 +18,7 @@ package fi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -31,6 +32,7 @@ import (
 )
 
 type Context struct {
+	ctx    context.Context
 	Tmpdir string
 
 	Target            Target
@@ -54,8 +56,9 @@ type Warning struct {
 	Message string
 }
 
-func NewContext(target Target, cluster *kops.Cluster, cloud Cloud, keystore Keystore, secretStore SecretStore, clusterConfigBase vfs.Path, checkExisting bool, tasks map[string]Task) (*Context, error) {
+func NewContext(ctx context.Context, target Target, cluster *kops.Cluster, cloud Cloud, keystore Keystore, secretStore SecretStore, clusterConfigBase vfs.Path, checkExisting bool, tasks map[string]Task) (*Context, error) {
 	c := &Context{
+		ctx:               ctx,
 		Cloud:             cloud,
 		Cluster:           cluster,
 		Target:            target,
@@ -75,6 +78,11 @@ func NewContext(target Target, cluster *kops.Cluster, cloud Cloud, keystore Keys
 	return c, nil
 }
 
+// Context returns the context.Context the tasks are run with
+func (c *Context) Context() context.Context {
+	return c.ctx
+}
+
 func (c *Context) AllTasks() map[string]Task {
 	return c.tasks
 }
@@ -47,6 +47,32 @@ type DryRunTarget struct {
 	assetBuilder *assets.AssetBuilder
 }
 
+// InstanceChangeClassifier is implemented by the tasks that manage the instances of an instance group,
+// to report which changes are only applied to new instances, and so require a rolling update.
+type InstanceChangeClassifier interface {
+	// RequiresInstanceReplacement returns true if a change to the named field requires the instances to be replaced.
+	RequiresInstanceReplacement(fieldName string) bool
+}
+
+// InstanceChange is the change to a task managing the instances of an instance group, classified by field
+type InstanceChange struct {
+	// TaskName is the type of the task, e.g. LaunchTemplate
+	TaskName string
+	// ID identifies the task in the report
+	ID string
+	// Name is the name of the task, which identifies the instance group
+	Name string
+	// ReplacementFields are the changed fields requiring the instances to be replaced by a rolling update
+	ReplacementFields []string
+	// InPlaceFields are the changed fields applied to the existing instances
+	InPlaceFields []string
+}
+
+// RequiresReplacement returns true if the change requires the instances to be replaced by a rolling update
+func (c *InstanceChange) RequiresReplacement() bool {
+	return len(c.ReplacementFields) != 0
+}
+
 type render struct {
 	a       Task
 	aIsNil  bool
@@ -240,6 +266,28 @@ func (t *DryRunTarget) PrintReport(taskMap map[string]Task, out io.Writer) error
 				fmt.Fprintf(b, "\n")
 			}
 		}
+
+		instanceChanges, err := t.InstanceChanges(taskMap)
+		if err != nil {
+			return err
+		}
+		var replacements, inPlace []string
+		for _, c := range instanceChanges {
+			if len(c.ReplacementFields) != 0 {
+				replacements = append(replacements, fmt.Sprintf("  %s/%s\t%s\n", c.TaskName, c.ID, strings.Join(c.ReplacementFields, ", ")))
+			}
+			if len(c.InPlaceFields) != 0 {
+				inPlace = append(inPlace, fmt.Sprintf("  %s/%s\t%s\n", c.TaskName, c.ID, strings.Join(c.InPlaceFields, ", ")))
+			}
+		}
+		if len(replacements) != 0 {
+			fmt.Fprintf(b, "Will require a rolling update to replace the instances:\n")
+			fmt.Fprintf(b, "%s\n", strings.Join(replacements, ""))
+		}
+		if len(inPlace) != 0 {
+			fmt.Fprintf(b, "Will apply to the existing instances in place:\n")
+			fmt.Fprintf(b, "%s\n", strings.Join(inPlace, ""))
+		}
 	}
 
 	if len(t.deletions) != 0 {
@@ -272,6 +320,50 @@ func (t *DryRunTarget) PrintReport(taskMap map[string]Task, out io.Writer) error
 	return err
 }
 
+// InstanceChanges returns the changes to the existing tasks managing instances, classified by whether
+// they require the instances to be replaced by a rolling update. Created tasks have no instances to replace.
+func (t *DryRunTarget) InstanceChanges(taskMap map[string]Task) ([]*InstanceChange, error) {
+	var updates []*render
+	for _, r := range t.changes {
+		if r.aIsNil {
+			continue
+		}
+		if _, ok := r.e.(InstanceChangeClassifier); ok {
+			updates = append(updates, r)
+		}
+	}
+	sort.Sort(ByTaskKey(updates))
+
+	var instanceChanges []*InstanceChange
+	for _, r := range updates {
+		changeList, err := buildChangeList(r.a, r.e, r.changes)
+		if err != nil {
+			return nil, err
+		}
+		if len(changeList) == 0 {
+			continue
+		}
+
+		c := &InstanceChange{
+			TaskName: getTaskName(r.changes),
+			ID:       idForTask(taskMap, r.e),
+		}
+		if hasName, ok := r.e.(HasName); ok {
+			c.Name = StringValue(hasName.GetName())
+		}
+		classifier := r.e.(InstanceChangeClassifier)
+		for _, change := range changeList {
+			if classifier.RequiresInstanceReplacement(change.FieldName) {
+				c.ReplacementFields = append(c.ReplacementFields, change.FieldName)
+			} else {
+				c.InPlaceFields = append(c.InPlaceFields, change.FieldName)
+			}
+		}
+		instanceChanges = append(instanceChanges, c)
+	}
+	return instanceChanges, nil
+}
+
 type change struct {
 	FieldName   string
 	Description string
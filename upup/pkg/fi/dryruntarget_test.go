@@ -95,3 +95,80 @@ func Test_DryrunTarget_PrintReport(t *testing.T) {
 	err = target.PrintReport(tasks, &out)
 	assert.NoError(t, err, "target.PrintReport()")
 }
+
+type testInstanceTask struct {
+	Name     *string
+	MaxSize  *int64
+	UserData *string
+}
+
+var _ Task = &testInstanceTask{}
+var _ InstanceChangeClassifier = &testInstanceTask{}
+
+func (*testInstanceTask) Run(_ *Context) error {
+	panic("not implemented")
+}
+
+func (e *testInstanceTask) GetName() *string {
+	return e.Name
+}
+
+func (*testInstanceTask) RequiresInstanceReplacement(fieldName string) bool {
+	return fieldName == "UserData"
+}
+
+func Test_DryrunTarget_InstanceChanges(t *testing.T) {
+	builder := assets.NewAssetBuilder(&api.Cluster{
+		Spec: api.ClusterSpec{
+			KubernetesVersion: "1.17.3",
+		},
+	}, false)
+	var stdout bytes.Buffer
+	target := NewDryRunTarget(builder, &stdout)
+	tasks := map[string]Task{}
+
+	render := func(a, e *testInstanceTask) {
+		changes := &testInstanceTask{}
+		_ = BuildChanges(a, e, changes)
+		assert.NoError(t, target.Render(a, e, changes), "target.Render()")
+		tasks[*e.Name] = e
+	}
+	render(
+		&testInstanceTask{Name: String("nodes"), MaxSize: Int64(2), UserData: String("a")},
+		&testInstanceTask{Name: String("nodes"), MaxSize: Int64(3), UserData: String("b")},
+	)
+	render(
+		&testInstanceTask{Name: String("other"), MaxSize: Int64(2), UserData: String("a")},
+		&testInstanceTask{Name: String("other"), MaxSize: Int64(3), UserData: String("a")},
+	)
+	render(
+		nil,
+		&testInstanceTask{Name: String("new"), MaxSize: Int64(3), UserData: String("a")},
+	)
+
+	changes, err := target.InstanceChanges(tasks)
+	assert.NoError(t, err, "target.InstanceChanges()")
+	expected := []*InstanceChange{
+		{
+			TaskName:          "testInstanceTask",
+			ID:                "nodes",
+			Name:              "nodes",
+			ReplacementFields: []string{"UserData"},
+			InPlaceFields:     []string{"MaxSize"},
+		},
+		{
+			TaskName:      "testInstanceTask",
+			ID:            "other",
+			Name:          "other",
+			InPlaceFields: []string{"MaxSize"},
+		},
+	}
+	assert.Equal(t, expected, changes)
+	assert.True(t, changes[0].RequiresReplacement())
+	assert.False(t, changes[1].RequiresReplacement())
+
+	var out bytes.Buffer
+	assert.NoError(t, target.PrintReport(tasks, &out), "target.PrintReport()")
+	assert.Contains(t, out.String(), "Will require a rolling update to replace the instances:\n  testInstanceTask/nodes\tUserData\n")
+	assert.Contains(t, out.String(), "Will apply to the existing instances in place:\n  testInstanceTask/nodes\tMaxSize\n  testInstanceTask/other\tMaxSize\n")
+}
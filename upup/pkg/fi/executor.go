@@ -17,12 +17,14 @@ limitations under the License.
 package fi
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/tracing"
 )
 
 type executor struct {
@@ -53,6 +55,13 @@ func (o *RunTasksOptions) InitDefaults() {
 // RunTasks executes all the tasks, considering their dependencies
 // It will perform some re-execution on error, retrying as long as progress is still being made
 func (e *executor) RunTasks(taskMap map[string]Task) error {
+	ctx, span := tracing.Start(e.context.Context(), "RunTasks", "kops.tasks", len(taskMap))
+	err := e.runTasks(ctx, taskMap)
+	span.Finish(err)
+	return err
+}
+
+func (e *executor) runTasks(ctx context.Context, taskMap map[string]Task) error {
 	dependencies := FindTaskDependencies(taskMap)
 
 	for _, task := range taskMap {
@@ -118,7 +127,7 @@ func (e *executor) RunTasks(taskMap map[string]Task) error {
 		var tasks []*taskState
 		tasks = append(tasks, canRun...)
 
-		taskErrors := e.forkJoin(tasks)
+		taskErrors := e.forkJoin(ctx, tasks)
 		var errors []error
 		for i, err := range taskErrors {
 			ts := tasks[i]
@@ -171,7 +180,7 @@ func (e *executor) RunTasks(taskMap map[string]Task) error {
 	return nil
 }
 
-func (e *executor) forkJoin(tasks []*taskState) []error {
+func (e *executor) forkJoin(ctx context.Context, tasks []*taskState) []error {
 	if len(tasks) == 0 {
 		return nil
 	}
@@ -184,7 +193,7 @@ func (e *executor) forkJoin(tasks []*taskState) []error {
 			results[index] = fmt.Errorf("function panic")
 			defer wg.Done()
 			klog.V(2).Infof("Executing task %q: %v\n", ts.key, ts.task)
-			results[index] = ts.task.Run(e.context)
+			results[index] = e.runTask(ctx, ts)
 		}(tasks[i], i)
 	}
 
@@ -192,3 +201,23 @@ func (e *executor) forkJoin(tasks []*taskState) []error {
 
 	return results
 }
+
+// runTask runs a single task, recording a span and a structured log entry for it
+func (e *executor) runTask(ctx context.Context, ts *taskState) error {
+	start := time.Now()
+	_, span := tracing.Start(ctx, ts.key, "kops.task", ts.key)
+
+	err := ts.task.Run(e.context)
+
+	var id string
+	if hasID, ok := ts.task.(CompareWithID); ok {
+		id = StringValue(hasID.CompareWithID())
+	}
+	if id != "" {
+		span.SetAttributes("kops.resource_id", id)
+	}
+	span.Finish(err)
+
+	klog.V(2).InfoS("Executed task", "task", ts.key, "resourceID", id, "duration", time.Since(start), "err", err)
+	return err
+}
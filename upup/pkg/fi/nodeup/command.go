@@ -43,6 +43,7 @@ import (
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hybrid"
 	"k8s.io/kops/upup/pkg/fi/nodeup/cloudinit"
 	"k8s.io/kops/upup/pkg/fi/nodeup/local"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
@@ -93,12 +94,24 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("CacheDir is required")
 	}
 
-	region, err := getRegion(ctx, &bootConfig)
-	if err != nil {
-		return err
-	}
-	if err = seedRNG(ctx, &bootConfig, region); err != nil {
-		return err
+	// Hybrid machines are not instances of the cloud provider; they authenticate with the credential written by kops toolbox enroll.
+	var hybridCredential *hybrid.NodeCredential
+	var region string
+	if bootConfig.InstanceGroupRole == api.InstanceGroupRoleHybrid {
+		var err error
+		hybridCredential, err = hybrid.ReadNodeCredential(hybrid.NodeCredentialPath)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		region, err = getRegion(ctx, &bootConfig)
+		if err != nil {
+			return err
+		}
+		if err = seedRNG(ctx, &bootConfig, region); err != nil {
+			return err
+		}
 	}
 
 	var configBase vfs.Path
@@ -107,7 +120,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	var nodeConfig *nodeup.NodeConfig
 
 	if bootConfig.ConfigServer != nil {
-		response, err := getNodeConfigFromServer(ctx, &bootConfig, region)
+		response, err := getNodeConfigFromServer(ctx, &bootConfig, region, hybridCredential)
 		if err != nil {
 			return err
 		}
@@ -177,7 +190,11 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("nodeup config hash mismatch")
 	}
 
-	err = evaluateSpec(c, &nodeupConfig)
+	if hybridCredential != nil {
+		overrideHybridSpec(c, &nodeupConfig, hybridCredential)
+	}
+
+	err := evaluateSpec(c, &nodeupConfig)
 	if err != nil {
 		return err
 	}
@@ -203,7 +220,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 	var cloud fi.Cloud
 
-	if api.CloudProviderID(c.cluster.Spec.CloudProvider) == api.CloudProviderAWS {
+	if api.CloudProviderID(c.cluster.Spec.CloudProvider) == api.CloudProviderAWS && hybridCredential == nil {
 		awsCloud, err := awsup.NewAWSCloud(region, nil)
 		if err != nil {
 			return err
@@ -258,7 +275,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return err
 	}
 
-	if api.CloudProviderID(c.cluster.Spec.CloudProvider) == api.CloudProviderAWS {
+	if api.CloudProviderID(c.cluster.Spec.CloudProvider) == api.CloudProviderAWS && hybridCredential == nil {
 		instanceIDBytes, err := vfs.Context.ReadFile("metadata://aws/meta-data/instance-id")
 		if err != nil {
 			return fmt.Errorf("error reading instance-id from AWS metadata: %v", err)
@@ -281,12 +298,15 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.DirectoryBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.UpdateServiceBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.VolumesBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.InstanceStorageBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.EgressProxyBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.ContainerdBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.DockerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.ProtokubeBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.CloudConfigBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.FileAssetsBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.HookBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.NodePluginBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeletBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubectlBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.EtcdBuilder{NodeupModelContext: modelContext})
@@ -296,6 +316,11 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.SecretBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.FirewallBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.SysctlBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.SwapBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.SecurityModulesBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.FIPSBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.SSMAgentBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.SSHBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeAPIServerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeControllerManagerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeSchedulerBuilder{NodeupModelContext: modelContext})
@@ -303,6 +328,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.KubeProxyBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KopsControllerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.WarmPoolBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.ImageVerificationBuilder{NodeupModelContext: modelContext})
 
 	loader.Builders = append(loader.Builders, &networking.CommonBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &networking.CalicoBuilder{NodeupModelContext: modelContext})
@@ -343,7 +369,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("unsupported target type %q", c.Target)
 	}
 
-	context, err := fi.NewContext(target, c.cluster, cloud, keyStore, secretStore, configBase, checkExisting, taskMap)
+	context, err := fi.NewContext(ctx, target, c.cluster, cloud, keyStore, secretStore, configBase, checkExisting, taskMap)
 	if err != nil {
 		klog.Exitf("error building context: %v", err)
 	}
@@ -363,7 +389,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	}
 
 	if nodeupConfig.EnableLifecycleHook {
-		if api.CloudProviderID(c.cluster.Spec.CloudProvider) == api.CloudProviderAWS {
+		if api.CloudProviderID(c.cluster.Spec.CloudProvider) == api.CloudProviderAWS && hybridCredential == nil {
 			err := completeWarmingLifecycleAction(cloud.(awsup.AWSCloud), modelContext)
 			if err != nil {
 				return fmt.Errorf("failed to complete lifecylce action: %w", err)
@@ -403,6 +429,21 @@ func completeWarmingLifecycleAction(cloud awsup.AWSCloud, modelContext *model.No
 	return nil
 }
 
+// overrideHybridSpec registers a hybrid machine under the node name it was enrolled with,
+// as it has no cloud metadata from which to derive its name or address.
+func overrideHybridSpec(c *NodeUpCommand, nodeupConfig *nodeup.Config, credential *hybrid.NodeCredential) {
+	c.cluster.Spec.Kubelet.HostnameOverride = credential.NodeName
+	c.cluster.Spec.MasterKubelet.HostnameOverride = credential.NodeName
+	nodeupConfig.KubeletConfig.HostnameOverride = credential.NodeName
+
+	if c.cluster.Spec.KubeProxy != nil {
+		c.cluster.Spec.KubeProxy.HostnameOverride = credential.NodeName
+		if c.cluster.Spec.KubeProxy.BindAddress == "@aws" {
+			c.cluster.Spec.KubeProxy.BindAddress = ""
+		}
+	}
+}
+
 func evaluateSpec(c *NodeUpCommand, nodeupConfig *nodeup.Config) error {
 	var err error
 
@@ -714,11 +755,17 @@ func seedRNG(ctx context.Context, bootConfig *nodeup.BootConfig, region string)
 }
 
 // getNodeConfigFromServer queries kops-controller for our node's configuration.
-func getNodeConfigFromServer(ctx context.Context, bootConfig *nodeup.BootConfig, region string) (*nodeup.BootstrapResponse, error) {
+func getNodeConfigFromServer(ctx context.Context, bootConfig *nodeup.BootConfig, region string, hybridCredential *hybrid.NodeCredential) (*nodeup.BootstrapResponse, error) {
 	var authenticator fi.Authenticator
 
-	switch api.CloudProviderID(bootConfig.CloudProvider) {
-	case api.CloudProviderAWS:
+	switch {
+	case hybridCredential != nil:
+		a, err := hybrid.NewAuthenticator(hybridCredential)
+		if err != nil {
+			return nil, err
+		}
+		authenticator = a
+	case api.CloudProviderID(bootConfig.CloudProvider) == api.CloudProviderAWS:
 		a, err := awsup.NewAWSAuthenticator(region)
 		if err != nil {
 			return nil, err
@@ -68,6 +68,13 @@ func (e *Archive) GetDependencies(tasks map[string]fi.Task) []fi.Task {
 	// Requires parent directories to be created
 	deps = append(deps, findCreatesDirParents(e.TargetDir, tasks)...)
 
+	// Requires PreInstall plugins to have run
+	for _, v := range tasks {
+		if isPreInstallPlugin(v) {
+			deps = append(deps, v)
+		}
+	}
+
 	return deps
 }
 
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// bootMilestonesTimeout is how long nodeup waits for the kubelet to register the node before giving up reporting.
+	bootMilestonesTimeout = 3 * time.Minute
+	// bootMilestonesInterval is the interval between attempts to report the boot milestones.
+	bootMilestonesInterval = 10 * time.Second
+)
+
+// BootMilestonesTask reports the boot milestones that only nodeup observes to kops-controller.
+// Reporting is best effort: a failure is logged, but does not fail nodeup.
+type BootMilestonesTask struct {
+	// ContainerRuntimeService is the systemd unit of the container runtime.
+	ContainerRuntimeService string
+
+	// Client holds the client wrapper for the kops-bootstrap protocol
+	Client *KopsBootstrapClient
+}
+
+var _ fi.Task = &BootMilestonesTask{}
+var _ fi.HasName = &BootMilestonesTask{}
+var _ fi.HasDependencies = &BootMilestonesTask{}
+
+func (b *BootMilestonesTask) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	// The node is only registered after the kubelet started
+	var deps []fi.Task
+	for _, v := range tasks {
+		if svc, ok := v.(*Service); ok && (svc.Name == kubeletService || svc.Name == b.ContainerRuntimeService) {
+			deps = append(deps, v)
+		}
+	}
+	return deps
+}
+
+func (b *BootMilestonesTask) GetName() *string {
+	name := "BootMilestones"
+	return &name
+}
+
+func (b *BootMilestonesTask) String() string {
+	return "BootMilestonesTask"
+}
+
+func (b *BootMilestonesTask) Run(c *fi.Context) error {
+	ctx := context.TODO()
+
+	containerRuntimeReady, err := serviceActiveEnterTime(b.ContainerRuntimeService)
+	if err != nil {
+		klog.Warningf("not reporting boot milestones: %v", err)
+		return nil
+	}
+
+	req := &nodeup.BootMilestonesRequest{
+		APIVersion: nodeup.BootstrapAPIVersion,
+		Milestones: map[string]time.Time{
+			nodeup.BootMilestoneContainerRuntimeReady: containerRuntimeReady,
+		},
+	}
+
+	deadline := time.Now().Add(bootMilestonesTimeout)
+	for {
+		err := b.Client.ReportBootMilestones(ctx, req)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			klog.Warningf("giving up reporting boot milestones: %v", err)
+			return nil
+		}
+		klog.Infof("unable to report boot milestones, will retry: %v", err)
+		time.Sleep(bootMilestonesInterval)
+	}
+}
+
+// serviceActiveEnterTime returns the time the systemd unit last became active.
+func serviceActiveEnterTime(service string) (time.Time, error) {
+	cmd := exec.Command("systemctl", "show", "--property=ActiveEnterTimestamp", "--value", service)
+	cmd.Env = append(os.Environ(), "TZ=UTC")
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error querying the start time of %q: %v", service, err)
+	}
+	return parseSystemdTimestamp(string(output))
+}
+
+// parseSystemdTimestamp parses a timestamp printed by systemctl in the UTC timezone.
+func parseSystemdTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "n/a" {
+		return time.Time{}, fmt.Errorf("service has not been active")
+	}
+	t, err := time.Parse("Mon 2006-01-02 15:04:05 MST", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing systemd timestamp %q: %v", s, err)
+	}
+	return t, nil
+}
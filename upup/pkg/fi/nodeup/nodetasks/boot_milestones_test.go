@@ -0,0 +1,38 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSystemdTimestamp(t *testing.T) {
+	actual, err := parseSystemdTimestamp("Thu 2021-07-01 10:00:25 UTC\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := time.Date(2021, 7, 1, 10, 0, 25, 0, time.UTC); !actual.Equal(expected) {
+		t.Errorf("unexpected time %v, expected %v", actual, expected)
+	}
+
+	for _, s := range []string{"", "\n", "n/a", "2021-07-01T10:00:25Z"} {
+		if _, err := parseSystemdTimestamp(s); err == nil {
+			t.Errorf("expected an error parsing %q", s)
+		}
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"k8s.io/kops/pkg/apis/nodeup"
@@ -143,6 +144,28 @@ type KopsBootstrapClient struct {
 }
 
 func (b *KopsBootstrapClient) QueryBootstrap(ctx context.Context, req *nodeup.BootstrapRequest) (*nodeup.BootstrapResponse, error) {
+	body, err := b.post(ctx, "/bootstrap", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var bootstrapResp nodeup.BootstrapResponse
+	err = json.Unmarshal(body, &bootstrapResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bootstrapResp, nil
+}
+
+// ReportBootMilestones reports the boot milestones the node reached to kops-controller.
+func (b *KopsBootstrapClient) ReportBootMilestones(ctx context.Context, req *nodeup.BootMilestonesRequest) error {
+	_, err := b.post(ctx, "/boot-milestones", req)
+	return err
+}
+
+// post sends an authenticated request to kops-controller, returning the body of the response.
+func (b *KopsBootstrapClient) post(ctx context.Context, requestPath string, req interface{}) ([]byte, error) {
 	if b.httpClient == nil {
 		certPool := x509.NewCertPool()
 		certPool.AppendCertsFromPEM(b.CAs)
@@ -176,9 +199,9 @@ func (b *KopsBootstrapClient) QueryBootstrap(ctx context.Context, req *nodeup.Bo
 		return nil, err
 	}
 
-	bootstrapURL := b.BaseURL
-	bootstrapURL.Path = path.Join(bootstrapURL.Path, "/bootstrap")
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", bootstrapURL.String(), bytes.NewReader(reqBytes))
+	requestURL := b.BaseURL
+	requestURL.Path = path.Join(requestURL.Path, requestPath)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", requestURL.String(), bytes.NewReader(reqBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -206,19 +229,8 @@ func (b *KopsBootstrapClient) QueryBootstrap(ctx context.Context, req *nodeup.Bo
 				detail = scanner.Text()
 			}
 		}
-		return nil, fmt.Errorf("bootstrap returned status code %d: %s", resp.StatusCode, detail)
-	}
-
-	var bootstrapResp nodeup.BootstrapResponse
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s returned status code %d: %s", strings.TrimPrefix(requestPath, "/"), resp.StatusCode, detail)
 	}
 
-	err = json.Unmarshal(body, &bootstrapResp)
-	if err != nil {
-		return nil, err
-	}
-
-	return &bootstrapResp, nil
+	return ioutil.ReadAll(resp.Body)
 }
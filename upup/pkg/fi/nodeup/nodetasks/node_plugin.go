@@ -0,0 +1,223 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+var (
+	// nodePluginRetryInterval is the time between attempts to run a plugin
+	nodePluginRetryInterval = 10 * time.Second
+	// nodePluginJoinTimeout is the maximum time a PostJoin plugin waits for the node to register
+	nodePluginJoinTimeout = 15 * time.Minute
+)
+
+// NodePluginTask runs a nodeup plugin: it fetches the plugin's artifacts, then runs its command,
+// at the stage of the node lifecycle the plugin is configured for.
+type NodePluginTask struct {
+	// Name is the name of the plugin
+	Name string
+	// Stage is the stage of the node lifecycle at which the plugin runs
+	Stage kops.NodePluginStage
+	// Dir is the directory the artifacts are fetched into
+	Dir string
+	// Artifacts are the files fetched before the command runs
+	Artifacts []kops.NodePluginArtifact
+	// Command is the command to run
+	Command []string
+	// Environment is the additional environment of the command
+	Environment map[string]string
+	// Timeout is the maximum duration of each attempt
+	Timeout time.Duration
+	// Retries is the number of times a failed attempt is retried
+	Retries int
+	// NodeName is the name of the node, which PostJoin plugins wait to be registered
+	NodeName string
+	// Kubeconfig is the path of the kubeconfig used to check that the node is registered
+	Kubeconfig string
+}
+
+var _ fi.Task = &NodePluginTask{}
+var _ fi.HasDependencies = &NodePluginTask{}
+
+// GetDependencies implements HasDependencies::GetDependencies
+func (t *NodePluginTask) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	// PreInstall plugins run first; installation tasks depend on them instead.
+	if t.Stage == kops.NodePluginStagePreInstall {
+		return nil
+	}
+
+	var deps []fi.Task
+	for _, v := range tasks {
+		if svc, ok := v.(*Service); ok && svc.Name == kubeletService {
+			deps = append(deps, v)
+		}
+	}
+	return deps
+}
+
+// isPreInstallPlugin returns true if the task is a plugin that runs before anything is installed
+func isPreInstallPlugin(task fi.Task) bool {
+	plugin, ok := task.(*NodePluginTask)
+	return ok && plugin.Stage == kops.NodePluginStagePreInstall
+}
+
+func (t *NodePluginTask) GetName() *string {
+	if t.Name == "" {
+		return nil
+	}
+	return &t.Name
+}
+
+func (t *NodePluginTask) String() string {
+	return fmt.Sprintf("NodePluginTask: %s (%s)", t.Name, t.Stage)
+}
+
+func (t *NodePluginTask) Run(c *fi.Context) error {
+	if t.Stage == kops.NodePluginStagePostJoin {
+		if err := t.waitForNode(); err != nil {
+			return fmt.Errorf("nodeup plugin %q: %v", t.Name, err)
+		}
+	}
+
+	var err error
+	for attempt := 0; attempt <= t.Retries; attempt++ {
+		if attempt > 0 {
+			klog.Warningf("nodeup plugin %q failed, retrying in %v: %v", t.Name, nodePluginRetryInterval, err)
+			time.Sleep(nodePluginRetryInterval)
+		}
+		if err = t.run(); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("nodeup plugin %q failed after %d attempts: %v", t.Name, t.Retries+1, err)
+}
+
+// run makes a single attempt at fetching the artifacts and running the command
+func (t *NodePluginTask) run() error {
+	if err := t.fetchArtifacts(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	human := strings.Join(t.Command, " ")
+	klog.Infof("running nodeup plugin %q: %s", t.Name, human)
+	cmd := exec.CommandContext(ctx, t.Command[0], t.Command[1:]...)
+	cmd.Dir = t.Dir
+	cmd.Env = append(os.Environ(), t.environment()...)
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("'%s' timed out after %v: %s", human, t.Timeout, string(output))
+	}
+	if err != nil {
+		return fmt.Errorf("error running '%s': %v: %s", human, err, string(output))
+	}
+	return nil
+}
+
+// environment returns the environment variables the command runs with, in addition to nodeup's own
+func (t *NodePluginTask) environment() []string {
+	env := []string{
+		"KOPS_PLUGIN_NAME=" + t.Name,
+		"KOPS_PLUGIN_STAGE=" + string(t.Stage),
+		"KOPS_PLUGIN_DIR=" + t.Dir,
+	}
+
+	var names []string
+	for name := range t.Environment {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, name+"="+t.Environment[name])
+	}
+	return env
+}
+
+// fetchArtifacts downloads the artifacts into the plugin directory, skipping those already present
+func (t *NodePluginTask) fetchArtifacts() error {
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return fmt.Errorf("error creating directory %q: %v", t.Dir, err)
+	}
+
+	for _, artifact := range t.Artifacts {
+		u, err := url.Parse(artifact.Source)
+		if err != nil {
+			return fmt.Errorf("error parsing artifact %q: %v", artifact.Source, err)
+		}
+		hash, err := hashing.FromString(artifact.Hash)
+		if err != nil {
+			return fmt.Errorf("error parsing hash of artifact %q: %v", artifact.Source, err)
+		}
+		dest := filepath.Join(t.Dir, path.Base(u.Path))
+		if _, err := fi.DownloadURL(artifact.Source, dest, hash); err != nil {
+			return fmt.Errorf("error fetching artifact %q: %v", artifact.Source, err)
+		}
+	}
+	return nil
+}
+
+// waitForNode waits for the node to be registered with the cluster.
+// The kubeconfig is reloaded on each attempt, as the kubelet may write it when it bootstraps.
+func (t *NodePluginTask) waitForNode() error {
+	klog.Infof("waiting for node %q to join the cluster", t.NodeName)
+	err := wait.PollImmediate(nodePluginRetryInterval, nodePluginJoinTimeout, func() (bool, error) {
+		config, err := clientcmd.BuildConfigFromFlags("", t.Kubeconfig)
+		if err != nil {
+			klog.V(2).Infof("unable to load kubeconfig %q: %v", t.Kubeconfig, err)
+			return false, nil
+		}
+		client, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return false, fmt.Errorf("error building kubernetes client: %v", err)
+		}
+		if _, err := client.CoreV1().Nodes().Get(context.Background(), t.NodeName, metav1.GetOptions{}); err != nil {
+			klog.V(2).Infof("node %q has not joined the cluster: %v", t.NodeName, err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("node %q did not join the cluster: %v", t.NodeName, err)
+	}
+	return nil
+}
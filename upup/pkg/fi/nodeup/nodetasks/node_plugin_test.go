@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func hasDependency(deps []fi.Task, task fi.Task) bool {
+	for _, dep := range deps {
+		if dep == task {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNodePluginDependencies(t *testing.T) {
+	preInstall := &NodePluginTask{Name: "pre-install", Stage: kops.NodePluginStagePreInstall}
+	postKubelet := &NodePluginTask{Name: "post-kubelet", Stage: kops.NodePluginStagePostKubelet}
+	postJoin := &NodePluginTask{Name: "post-join", Stage: kops.NodePluginStagePostJoin}
+	kubelet := &Service{Name: kubeletService}
+	containerd := &Service{Name: containerdService}
+	pkg := &Package{Name: "socat"}
+	archive := &Archive{Name: "containerd", TargetDir: "/usr/local"}
+
+	tasks := map[string]fi.Task{
+		"pre-install":  preInstall,
+		"post-kubelet": postKubelet,
+		"post-join":    postJoin,
+		"kubelet":      kubelet,
+		"containerd":   containerd,
+		"package":      pkg,
+		"archive":      archive,
+	}
+
+	if deps := preInstall.GetDependencies(tasks); len(deps) != 0 {
+		t.Errorf("PreInstall plugin has unexpected dependencies: %v", deps)
+	}
+	for _, task := range []fi.HasDependencies{pkg, archive, containerd, kubelet} {
+		if !hasDependency(task.GetDependencies(tasks), preInstall) {
+			t.Errorf("%v does not depend on the PreInstall plugin", task)
+		}
+	}
+
+	for _, plugin := range []*NodePluginTask{postKubelet, postJoin} {
+		deps := plugin.GetDependencies(tasks)
+		if len(deps) != 1 || deps[0] != kubelet {
+			t.Errorf("%v should only depend on the kubelet, got %v", plugin, deps)
+		}
+		if hasDependency(kubelet.GetDependencies(tasks), plugin) {
+			t.Errorf("kubelet should not depend on %v", plugin)
+		}
+	}
+}
+
+func TestNodePluginRun(t *testing.T) {
+	defer func(interval time.Duration) { nodePluginRetryInterval = interval }(nodePluginRetryInterval)
+	nodePluginRetryInterval = 0
+
+	grid := []struct {
+		name     string
+		script   string
+		timeout  time.Duration
+		retries  int
+		attempts int
+		err      string
+	}{
+		{
+			name:     "success",
+			script:   `test "$KOPS_PLUGIN_NAME" = success && test "$KOPS_PLUGIN_STAGE" = PostKubelet && test "$EXTRA" = value`,
+			attempts: 1,
+		},
+		{
+			name:     "retried",
+			script:   `test "$(wc -l < attempts)" -ge 3`,
+			retries:  3,
+			attempts: 3,
+		},
+		{
+			name:     "failed",
+			script:   `exit 1`,
+			retries:  1,
+			attempts: 2,
+			err:      `nodeup plugin "failed" failed after 2 attempts`,
+		},
+		{
+			name:     "timeout",
+			script:   `exec sleep 10`,
+			timeout:  100 * time.Millisecond,
+			attempts: 1,
+			err:      "timed out after 100ms",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "nodeplugin")
+			if err != nil {
+				t.Fatalf("error creating temp dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			task := &NodePluginTask{
+				Name:        g.name,
+				Stage:       kops.NodePluginStagePostKubelet,
+				Dir:         dir,
+				Command:     []string{"/bin/sh", "-c", `echo >> "$KOPS_PLUGIN_DIR/attempts"; ` + g.script},
+				Environment: map[string]string{"EXTRA": "value"},
+				Timeout:     g.timeout,
+				Retries:     g.retries,
+			}
+			err = task.Run(nil)
+			if g.err == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if g.err != "" && (err == nil || !strings.Contains(err.Error(), g.err)) {
+				t.Errorf("expected error containing %q, got %v", g.err, err)
+			}
+
+			b, err := ioutil.ReadFile(filepath.Join(dir, "attempts"))
+			if err != nil {
+				t.Fatalf("error reading attempts: %v", err)
+			}
+			if attempts := strings.Count(string(b), "\n"); attempts != g.attempts {
+				t.Errorf("expected %d attempts, got %d", g.attempts, attempts)
+			}
+		})
+	}
+}
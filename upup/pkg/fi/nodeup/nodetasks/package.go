@@ -66,11 +66,14 @@ var _ fi.HasDependencies = &Package{}
 func (e *Package) GetDependencies(tasks map[string]fi.Task) []fi.Task {
 	var deps []fi.Task
 
-	// UpdatePackages before we install any packages
+	// UpdatePackages and run PreInstall plugins before we install any packages
 	for _, v := range tasks {
 		if _, ok := v.(*UpdatePackages); ok {
 			deps = append(deps, v)
 		}
+		if isPreInstallPlugin(v) {
+			deps = append(deps, v)
+		}
 	}
 
 	// If this package is a bare deb, install it after OS managed packages
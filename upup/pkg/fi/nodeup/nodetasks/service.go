@@ -77,6 +77,16 @@ func (p *Service) GetDependencies(tasks map[string]fi.Task) []fi.Task {
 			deps = append(deps, v)
 		case *Service, *LoadImageTask, *PullImageTask, *IssueCert, *BootstrapClientTask, *KubeConfig:
 			// ignore
+		case *NodePluginTask:
+			// Services start once PreInstall plugins have run; later plugins run once the kubelet has started.
+			if isPreInstallPlugin(v) {
+				deps = append(deps, v)
+			}
+		case *VerifyImageTask:
+			// The kubelet must only run images once they have been verified.
+			if p.Name == kubeletService {
+				deps = append(deps, v)
+			}
 		case *File:
 			if len(v.BeforeServices) > 0 {
 				for _, s := range v.BeforeServices {
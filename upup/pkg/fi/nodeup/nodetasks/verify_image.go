@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cosign"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// VerifyImageTask verifies a container image against the cluster's trust policy,
+// then pulls it by digest and tags it with its name, so that the verified image is the one that runs.
+type VerifyImageTask struct {
+	// Name is the image, as referenced by the manifests that run it.
+	Name string
+	// Digest is the digest the image is pinned to, if any.
+	Digest string
+	// CosignPublicKeys are the PEM encoded keys, one of which must have signed the image.
+	CosignPublicKeys []string
+	// Action is the action taken when the image fails verification.
+	Action kops.ImageVerificationAction
+	// Runtime is the container runtime the image is pulled into.
+	Runtime string
+}
+
+var _ fi.Task = &VerifyImageTask{}
+var _ fi.HasDependencies = &VerifyImageTask{}
+
+func (t *VerifyImageTask) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	// Images are pulled once the container runtime is running.
+	var deps []fi.Task
+	for _, v := range tasks {
+		if svc, ok := v.(*Service); ok && (svc.Name == containerdService || svc.Name == dockerService) {
+			deps = append(deps, v)
+		}
+	}
+	return deps
+}
+
+func (t *VerifyImageTask) GetName() *string {
+	if t.Name == "" {
+		return nil
+	}
+	return &t.Name
+}
+
+func (t *VerifyImageTask) String() string {
+	return fmt.Sprintf("VerifyImageTask: %s", t.Name)
+}
+
+func (t *VerifyImageTask) Run(c *fi.Context) error {
+	digest, err := t.verify()
+	if err != nil {
+		if t.Action == kops.ImageVerificationActionWarn {
+			klog.Warningf("image %q failed verification: %v", t.Name, err)
+			return nil
+		}
+		return fmt.Errorf("image %q failed verification: %v", t.Name, err)
+	}
+
+	return t.pull(digest)
+}
+
+// verify checks the image against the trust policy, returning the verified image reference including its digest.
+func (t *VerifyImageTask) verify() (name.Digest, error) {
+	ref, err := name.ParseReference(t.Name)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("parsing reference: %v", err)
+	}
+	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}
+
+	digest, err := resolveDigest(ref, t.Digest, options...)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	if len(t.CosignPublicKeys) != 0 {
+		var keys []*ecdsa.PublicKey
+		for _, data := range t.CosignPublicKeys {
+			key, err := cosign.ParsePublicKey([]byte(data))
+			if err != nil {
+				return name.Digest{}, fmt.Errorf("parsing cosign public key: %v", err)
+			}
+			keys = append(keys, key)
+		}
+		if err := cosign.Verify(ref.Context(), digest, keys, options...); err != nil {
+			return name.Digest{}, err
+		}
+	}
+
+	return ref.Context().Digest(digest.String()), nil
+}
+
+// resolveDigest returns the digest the image is pinned to, either by the trust policy or by its reference.
+// When neither pins it, the digest the registry currently serves for the reference is returned.
+func resolveDigest(ref name.Reference, pinned string, options ...remote.Option) (v1.Hash, error) {
+	var digests []string
+	if pinned != "" {
+		digests = append(digests, pinned)
+	}
+	if d, ok := ref.(name.Digest); ok {
+		digests = append(digests, d.DigestStr())
+	}
+
+	if len(digests) == 0 {
+		desc, err := remote.Head(ref, options...)
+		if err != nil {
+			return v1.Hash{}, fmt.Errorf("resolving digest: %v", err)
+		}
+		return desc.Digest, nil
+	}
+
+	for _, d := range digests[1:] {
+		if d != digests[0] {
+			return v1.Hash{}, fmt.Errorf("reference digest %s does not match pinned digest %s", d, digests[0])
+		}
+	}
+	return v1.NewHash(digests[0])
+}
+
+// pull pulls the image by digest, and tags it with the name it is referenced by.
+func (t *VerifyImageTask) pull(digest name.Digest) error {
+	ref, err := name.ParseReference(t.Name)
+	if err != nil {
+		return fmt.Errorf("parsing reference: %v", err)
+	}
+	tag, isTag := ref.(name.Tag)
+	byDigest := criRepository(digest.Context()) + "@" + digest.DigestStr()
+
+	var commands [][]string
+	switch t.Runtime {
+	case "docker":
+		commands = append(commands, []string{"docker", "pull", byDigest})
+		if isTag {
+			commands = append(commands, []string{"docker", "tag", byDigest, t.Name})
+		}
+	case "containerd":
+		commands = append(commands, []string{"ctr", "--namespace", "k8s.io", "images", "pull", byDigest})
+		if isTag {
+			commands = append(commands, []string{"ctr", "--namespace", "k8s.io", "images", "tag", "--force", byDigest, criRepository(tag.Context()) + ":" + tag.TagStr()})
+		}
+	default:
+		return fmt.Errorf("unknown container runtime: %s", t.Runtime)
+	}
+
+	for _, args := range commands {
+		human := strings.Join(args, " ")
+		klog.Infof("running command %s", human)
+		cmd := exec.Command(args[0], args[1:]...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error running '%s': %v: %s", human, err, string(output))
+		}
+	}
+	return nil
+}
+
+// criRepository returns the fully qualified repository name the CRI uses for images,
+// e.g. "docker.io/library/busybox" for "busybox".
+func criRepository(repository name.Repository) string {
+	registry := repository.RegistryStr()
+	if registry == name.DefaultRegistry {
+		registry = "docker.io"
+	}
+	return registry + "/" + repository.RepositoryStr()
+}
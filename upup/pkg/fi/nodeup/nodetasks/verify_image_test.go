@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+func TestVerifyImageResolveDigest(t *testing.T) {
+	digestA := "sha256:" + strings.Repeat("a", 64)
+	digestB := "sha256:" + strings.Repeat("b", 64)
+
+	grid := []struct {
+		image    string
+		pinned   string
+		expected string
+		err      string
+	}{
+		{
+			image:    "k8s.gcr.io/kube-proxy:v1.21.0",
+			pinned:   digestA,
+			expected: digestA,
+		},
+		{
+			image:    "k8s.gcr.io/kube-proxy@" + digestA,
+			expected: digestA,
+		},
+		{
+			image:    "k8s.gcr.io/kube-proxy@" + digestA,
+			pinned:   digestA,
+			expected: digestA,
+		},
+		{
+			image:  "k8s.gcr.io/kube-proxy@" + digestB,
+			pinned: digestA,
+			err:    "reference digest " + digestB + " does not match pinned digest " + digestA,
+		},
+	}
+	for _, g := range grid {
+		ref, err := name.ParseReference(g.image)
+		if err != nil {
+			t.Fatalf("error parsing %q: %v", g.image, err)
+		}
+		digest, err := resolveDigest(ref, g.pinned)
+		if g.err != "" {
+			if err == nil || err.Error() != g.err {
+				t.Errorf("%s: expected error %q, got %v", g.image, g.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", g.image, err)
+			continue
+		}
+		if digest.String() != g.expected {
+			t.Errorf("%s: expected digest %s, got %s", g.image, g.expected, digest)
+		}
+	}
+}
+
+func TestCRIRepository(t *testing.T) {
+	grid := map[string]string{
+		"busybox":                       "docker.io/library/busybox",
+		"calico/node:v3.19.1":           "docker.io/calico/node",
+		"k8s.gcr.io/kube-proxy:v1.21.0": "k8s.gcr.io/kube-proxy",
+	}
+	for image, expected := range grid {
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			t.Fatalf("error parsing %q: %v", image, err)
+		}
+		if actual := criRepository(ref.Context()); actual != expected {
+			t.Errorf("%s: expected %q, got %q", image, expected, actual)
+		}
+	}
+}
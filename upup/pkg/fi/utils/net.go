@@ -130,6 +130,9 @@ func CIDRSubnet(prefix string, newSize int, netNum int64) (string, error) {
 	if oldSize == 0 && totalSize == 0 {
 		return "", fmt.Errorf("unable to calculate CIDR mask size for %q: %q", prefix, baseCIDR.Mask)
 	}
+	if newSize < oldSize || newSize > totalSize {
+		return "", fmt.Errorf("unable to calculate subnet CIDR for %q -> /%d: size must be between %d and %d", prefix, newSize, oldSize, totalSize)
+	}
 
 	newNetwork, err := cidr.SubnetBig(baseCIDR, newSize-oldSize, big.NewInt(netNum))
 	if err != nil {
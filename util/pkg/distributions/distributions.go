@@ -73,6 +73,37 @@ func (d *Distribution) IsSystemd() bool {
 	return true
 }
 
+// SupportsSELinux returns true if this distribution can run with SELinux enabled
+func (d *Distribution) SupportsSELinux() bool {
+	switch d.project {
+	case "rhel", "centos", "amazonlinux2", "flatcar":
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsAppArmor returns true if this distribution uses AppArmor
+func (d *Distribution) SupportsAppArmor() bool {
+	return d.project == "debian" || d.project == "ubuntu"
+}
+
+// SupportsFIPS returns true if this distribution has FIPS 140-2 validated images.
+// On Ubuntu, only the FIPS images of Ubuntu Pro are validated.
+func (d *Distribution) SupportsFIPS() bool {
+	switch d.project {
+	case "rhel", "centos", "amazonlinux2", "ubuntu":
+		return true
+	default:
+		return false
+	}
+}
+
+// HasCryptoPolicies returns true if this distribution configures its crypto libraries with update-crypto-policies
+func (d *Distribution) HasCryptoPolicies() bool {
+	return (d.project == "rhel" || d.project == "centos") && d.version >= 8
+}
+
 // DefaultUsers returns the name of the system users for this distribution
 func (d *Distribution) DefaultUsers() ([]string, error) {
 	switch d.project {
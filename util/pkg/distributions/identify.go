@@ -88,3 +88,27 @@ func FindDistribution(rootfs string) (Distribution, error) {
 	klog.V(2).Infof("Contents of /etc/os-release:\n%s", osReleaseBytes)
 	return Distribution{}, fmt.Errorf("unsupported distro: %s", distro)
 }
+
+// FindDistributionFamilyForImage identifies the distribution of a well-known image from its name.
+// Only the family of the distribution is identified; its id and version are not set.
+// It returns false if the image name does not identify a distribution.
+func FindDistributionFamilyForImage(image string) (Distribution, bool) {
+	name := strings.ToLower(image)
+	switch {
+	case strings.Contains(name, "flatcar"):
+		return Distribution{project: "flatcar"}, true
+	case strings.HasPrefix(name, "cos-cloud/") || strings.Contains(name, "cos-stable") || strings.Contains(name, "cos-beta"):
+		return Distribution{project: "containeros"}, true
+	case strings.Contains(name, "ubuntu"):
+		return Distribution{packageFormat: "deb", project: "ubuntu"}, true
+	case strings.Contains(name, "debian"):
+		return Distribution{packageFormat: "deb", project: "debian"}, true
+	case strings.Contains(name, "amzn2") || strings.Contains(name, "amazon-linux-2") || strings.Contains(name, "amazonlinux2"):
+		return Distribution{packageFormat: "rpm", project: "amazonlinux2"}, true
+	case strings.Contains(name, "centos"):
+		return Distribution{packageFormat: "rpm", project: "centos"}, true
+	case strings.Contains(name, "rhel"):
+		return Distribution{packageFormat: "rpm", project: "rhel"}, true
+	}
+	return Distribution{}, false
+}
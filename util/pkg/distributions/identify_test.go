@@ -127,3 +127,66 @@ func TestFindDistribution(t *testing.T) {
 		}
 	}
 }
+
+func TestFindDistributionFamilyForImage(t *testing.T) {
+	tests := []struct {
+		image    string
+		found    bool
+		selinux  bool
+		apparmor bool
+		fips     bool
+	}{
+		{
+			image:    "099720109477/ubuntu/images/hvm-ssd/ubuntu-focal-20.04-amd64-server-20210415",
+			found:    true,
+			apparmor: true,
+			fips:     true,
+		},
+		{
+			image:    "kope.io/k8s-1.18-debian-stretch-amd64-hvm-ebs-2020-11-19",
+			found:    true,
+			apparmor: true,
+		},
+		{
+			image:   "137112412989/amzn2-ami-hvm-2.0.20210427.0-x86_64-gp2",
+			found:   true,
+			selinux: true,
+			fips:    true,
+		},
+		{
+			image:   "309956199498/RHEL-8.3.0_HVM-20201031-x86_64-0-Hourly2-GP2",
+			found:   true,
+			selinux: true,
+			fips:    true,
+		},
+		{
+			image:   "075585003325/Flatcar-stable-2765.2.3-hvm",
+			found:   true,
+			selinux: true,
+		},
+		{
+			image: "cos-cloud/cos-stable-89-16108-403-15",
+			found: true,
+		},
+		{
+			image: "ami-0123456789abcdef0",
+		},
+	}
+
+	for _, test := range tests {
+		actual, found := FindDistributionFamilyForImage(test.image)
+		if found != test.found {
+			t.Errorf("unexpected result for %q, actual=%v, expected=%v", test.image, found, test.found)
+			continue
+		}
+		if actual.SupportsSELinux() != test.selinux {
+			t.Errorf("unexpected SELinux support for %q, actual=%v, expected=%v", test.image, actual.SupportsSELinux(), test.selinux)
+		}
+		if actual.SupportsAppArmor() != test.apparmor {
+			t.Errorf("unexpected AppArmor support for %q, actual=%v, expected=%v", test.image, actual.SupportsAppArmor(), test.apparmor)
+		}
+		if actual.SupportsFIPS() != test.fips {
+			t.Errorf("unexpected FIPS support for %q, actual=%v, expected=%v", test.image, actual.SupportsFIPS(), test.fips)
+		}
+	}
+}
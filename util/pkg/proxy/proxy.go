@@ -18,6 +18,7 @@ package proxy
 
 import (
 	"strconv"
+	"strings"
 
 	"k8s.io/kops/pkg/apis/kops"
 
@@ -25,6 +26,23 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// ProxyURL returns the URL of the egress proxy, defaulting the scheme to http.
+func ProxyURL(proxies *kops.EgressProxySpec) string {
+	if proxies == nil {
+		return ""
+	}
+
+	url := proxies.HTTPProxy.Host
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = "http://" + url
+	}
+	if proxies.HTTPProxy.Port != 0 {
+		url += ":" + strconv.Itoa(proxies.HTTPProxy.Port)
+	}
+	return url
+}
+
+// GetProxyEnvVars returns the environment variables used to configure the egress proxy.
 func GetProxyEnvVars(proxies *kops.EgressProxySpec) []v1.EnvVar {
 	if proxies == nil {
 		return []v1.EnvVar{}
@@ -34,12 +52,7 @@ func GetProxyEnvVars(proxies *kops.EgressProxySpec) []v1.EnvVar {
 		klog.Warning("EgressProxy set but no proxy host provided")
 	}
 
-	var httpProxyURL string
-	if proxies.HTTPProxy.Port == 0 {
-		httpProxyURL = "http://" + proxies.HTTPProxy.Host
-	} else {
-		httpProxyURL = "http://" + proxies.HTTPProxy.Host + ":" + strconv.Itoa(proxies.HTTPProxy.Port)
-	}
+	httpProxyURL := ProxyURL(proxies)
 
 	noProxy := proxies.ProxyExcludes
 
@@ -63,6 +63,20 @@ func TestGetProxyEnvVars(t *testing.T) {
 				{Name: "no_proxy", Value: ""},
 			},
 		},
+		{
+			inProxies: &kops.EgressProxySpec{
+				HTTPProxy: kops.HTTPProxy{
+					Host: "https://a.b.c.d",
+					Port: 1234,
+				},
+			},
+			expected: []v1.EnvVar{
+				{Name: "http_proxy", Value: "https://a.b.c.d:1234"},
+				{Name: "https_proxy", Value: "https://a.b.c.d:1234"},
+				{Name: "NO_PROXY", Value: ""},
+				{Name: "no_proxy", Value: ""},
+			},
+		},
 		{
 			inProxies: &kops.EgressProxySpec{
 				HTTPProxy: kops.HTTPProxy{
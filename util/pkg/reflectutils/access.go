@@ -17,10 +17,13 @@ limitations under the License.
 package reflectutils
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
 )
 
 func SetString(target interface{}, targetPath string, newValue string) error {
@@ -43,7 +46,11 @@ func SetString(target interface{}, targetPath string, newValue string) error {
 				return fmt.Errorf("cannot set field %q (marked immutable)", path)
 			}
 
-			if err := setPrimitive(v, newValue); err != nil {
+			if isJSONValue(v.Type(), newValue) {
+				if err := setJSON(v, newValue); err != nil {
+					return fmt.Errorf("cannot set field %q: %v", path, err)
+				}
+			} else if err := setPrimitive(v, newValue); err != nil {
 				return fmt.Errorf("cannot set field %q: %v", path, err)
 			}
 
@@ -89,6 +96,51 @@ func SetString(target interface{}, targetPath string, newValue string) error {
 	return nil
 }
 
+// isJSONValue returns true if the value is a JSON array or object, and the field is a list, map or object that it can be set from.
+func isJSONValue(t reflect.Type, newValue string) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Struct:
+		trimmed := strings.TrimSpace(newValue)
+		return strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{")
+	default:
+		return false
+	}
+}
+
+// setJSON sets a list, map or object field from a JSON value.
+// A list is replaced by a JSON array; a map or object is patched with a JSON object, as a JSON merge patch (RFC 7386),
+// so that the keys that are not in the patch are kept, and keys that are null in the patch are removed.
+func setJSON(v reflect.Value, newValue string) error {
+	if !v.CanSet() {
+		return fmt.Errorf("cannot set value")
+	}
+
+	value := []byte(strings.TrimSpace(newValue))
+	if value[0] == '{' {
+		current, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Errorf("error marshaling current value: %v", err)
+		}
+		if string(current) == "null" {
+			current = []byte("{}")
+		}
+		value, err = jsonpatch.MergePatch(current, value)
+		if err != nil {
+			return fmt.Errorf("cannot apply %s as a JSON merge patch: %v", newValue, err)
+		}
+	}
+
+	newV := reflect.New(v.Type())
+	if err := json.Unmarshal(value, newV.Interface()); err != nil {
+		return fmt.Errorf("cannot interpret %s as %s: %v", newValue, BuildTypeName(v.Type()), err)
+	}
+	v.Set(newV.Elem())
+	return nil
+}
+
 func setPrimitive(v reflect.Value, newValue string) error {
 	if !v.CanSet() {
 		return fmt.Errorf("cannot set value")
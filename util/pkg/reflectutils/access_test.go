@@ -170,6 +170,41 @@ func TestSet(t *testing.T) {
 			Path:     "spec.containers[0].enumSlice",
 			Value:    "GHI,JKL",
 		},
+		{
+			Name:     "replace enum slice with JSON array",
+			Input:    "{ 'spec': { 'containers': [ { 'enumSlice': [ 'ABC', 'DEF' ] } ] } }",
+			Expected: "{ 'spec': { 'containers': [ { 'enumSlice': [ 'GHI' ] } ] } }",
+			Path:     "spec.containers[0].enumSlice",
+			Value:    `["GHI"]`,
+		},
+		{
+			Name:     "replace list of objects with JSON array",
+			Input:    "{ 'spec': { 'containers': [ { 'image': 'hello-world' } ] } }",
+			Expected: "{ 'spec': { 'containers': [ { 'image': 'nginx' }, { 'image': 'busybox', 'int': 1 } ] } }",
+			Path:     "spec.containers",
+			Value:    `[{"image": "nginx"}, {"image": "busybox", "int": 1}]`,
+		},
+		{
+			Name:     "create object from JSON object",
+			Input:    "{ 'spec': { 'containers': [ {} ] } }",
+			Expected: "{ 'spec': { 'containers': [ { 'policy': { 'name': 'allowed', 'allow': true } } ] } }",
+			Path:     "spec.containers[0].policy",
+			Value:    `{"name": "allowed", "allow": true}`,
+		},
+		{
+			Name:     "patch object with JSON object",
+			Input:    "{ 'spec': { 'containers': [ { 'policy': { 'name': 'allowed' } } ] } }",
+			Expected: "{ 'spec': { 'containers': [ { 'policy': { 'name': 'allowed', 'allow': true } } ] } }",
+			Path:     "spec.containers[0].policy",
+			Value:    `{"allow": true}`,
+		},
+		{
+			Name:     "patch map with JSON object",
+			Input:    "{ 'spec': { 'containers': [ { 'resources': { 'limits': { 'cpu': 1, 'memory': 2 } } } ] } }",
+			Expected: "{ 'spec': { 'containers': [ { 'resources': { 'limits': { 'cpu': 3, 'disk': 4 } } } ] } }",
+			Path:     "spec.containers[0].resources.limits",
+			Value:    `{"cpu": 3, "memory": null, "disk": 4}`,
+		},
 		// Not sure if we should do this...
 		// {
 		// 	Name:     "creating missing array elements",
@@ -253,6 +288,13 @@ func TestSetInvalidPath(t *testing.T) {
 			Value:         "123",
 			ExpectedError: "field wrong.path.check not found in *fakeObject",
 		},
+		{
+			Name:          "set list with JSON object",
+			Input:         "{ 'spec': { 'containers': [ {} ] } }",
+			Path:          "spec.containers",
+			Value:         `{"image": "nginx"}`,
+			ExpectedError: "cannot set field \"spec.containers\": cannot interpret {\"image\": \"nginx\"} as []fakeObjectContainers: json: cannot unmarshal object into Go value of type []reflectutils.fakeObjectContainers",
+		},
 	}
 
 	for _, g := range grid {